@@ -0,0 +1,134 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// The golden master checks every declaration extractor shares. Extracting Resources/GoldenMaster/Sources/&lt;fixture&gt;
+/// matches Controls/&lt;fixture&gt;_symbols.txt, which lists every symbol as "kind name start-end parent" (or
+/// "kind name start-end" for extractors whose fixture is checked without parents). Repairing, through
+/// <see cref="SymbolRepairs"/>, an extraction that found one declaration cut short adds the rest, keeps its ID,
+/// extends it and parents its members to it. What each language extracts beyond that is tested in its own
+/// &lt;Language&gt;GoldenMasterTests fixture.
+/// </summary>
+[TestFixture]
+public class DeclarationGoldenMasterTests
+{
+    internal static string TestResourcesPath => Path.Combine(TestContext.CurrentContext.TestDirectory, "..", "..", "..", "..", "COA.CodeSearch.McpServer.Tests", "Resources", "GoldenMaster");
+
+    [Test]
+    [TestCaseSource(nameof(GetExtractorTestCases))]
+    public void Extract_Should_Match_Golden_Master_Symbols(DeclarationGoldenMasterCase testCase)
+    {
+        // Act
+        var symbols = testCase.Extract(testCase.FilePath, testCase.Source);
+
+        // Assert
+        var names = symbols.ToDictionary(s => s.Id, s => s.Name);
+        var golden = File.ReadAllLines(Path.Combine(TestResourcesPath, "Controls", $"{Path.GetFileNameWithoutExtension(testCase.SourceFile)}_symbols.txt"))
+            .Where(l => l.Length > 0);
+        Assert.That(symbols.Select(s => $"{s.Kind} {s.Name} {s.StartLine}-{s.EndLine}"
+                + (testCase.WithParents ? $" {(s.ParentId == null ? "-" : names[s.ParentId])}" : string.Empty)),
+            Is.EqualTo(golden));
+        Assert.That(symbols.All(s => s.Language == testCase.Language), Is.True);
+    }
+
+    [Test]
+    [TestCaseSource(nameof(GetExtractorTestCases))]
+    public void Repair_Should_Add_Missing_Declarations_And_Keep_Extracted_Ids(DeclarationGoldenMasterCase testCase)
+    {
+        // Arrange - extraction that only found one declaration, cut short
+        var declared = testCase.Extract(testCase.FilePath, testCase.Source);
+        var truncated = declared.Single(s => s.Name == testCase.Truncated.Name && s.StartLine == testCase.Truncated.StartLine);
+        var extracted = new List<JulieSymbol>
+        {
+            new()
+            {
+                Id = "julie-" + truncated.Name, Name = truncated.Name, Kind = truncated.Kind, Language = testCase.Language,
+                FilePath = testCase.FilePath, StartLine = truncated.StartLine, EndLine = testCase.Truncated.EndLine
+            }
+        };
+
+        // Act
+        var repaired = SymbolRepairs.Repair(testCase.FilePath, testCase.Source, extracted);
+        var again = testCase.Repair(testCase.FilePath, testCase.Source, repaired);
+
+        // Assert
+        Assert.That(SymbolRepairs.Handles(testCase.FilePath), Is.True);
+        Assert.That(repaired, Has.Count.EqualTo(declared.Count));
+        var kept = repaired.Single(s => s.Id == "julie-" + truncated.Name);
+        Assert.That(kept.EndLine, Is.EqualTo(truncated.EndLine), "A truncated extent is extended");
+        Assert.That(repaired.Where(s => s.ParentId == kept.Id).Select(s => s.Name),
+            Is.EqualTo(declared.Where(s => s.ParentId == truncated.Id).Select(s => s.Name)), "Members point at the extracted symbol");
+        Assert.That(ReferenceEquals(again, repaired), Is.True, "Nothing left to repair");
+    }
+
+    private static IEnumerable<TestCaseData> GetExtractorTestCases()
+    {
+        var testCases = new[]
+        {
+            new DeclarationGoldenMasterCase("kotlin", "kotlin_services.kt", KotlinSymbols.Extract, KotlinSymbols.Repair, ("OrderService", 49, 52)),
+            new DeclarationGoldenMasterCase("swift", "swift_store.swift", SwiftSymbols.Extract, SwiftSymbols.Repair, ("OrderViewModel", 81, 90)),
+            new DeclarationGoldenMasterCase("zig", "zig_allocator.zig", ZigSymbols.Extract, ZigSymbols.Repair, ("Pool", 43, 46)),
+            new DeclarationGoldenMasterCase("scala", "scala_spark_job.scala", ScalaSymbols.Extract, ScalaSymbols.Repair, ("EventSummaryJob", 74, 75)),
+            new DeclarationGoldenMasterCase("sql", "sql_schema.sql", SqlSymbols.Extract, SqlSymbols.Repair, ("orders", 16, 16)),
+            new DeclarationGoldenMasterCase("protobuf", "proto_user_service.proto", ProtoSymbols.Extract, ProtoSymbols.Repair, ("Order", 43, 44)),
+            new DeclarationGoldenMasterCase("graphql", "graphql_store_schema.graphql", GraphQLSymbols.Extract, GraphQLSymbols.Repair, ("User", 17, 18)),
+            new DeclarationGoldenMasterCase("terraform", "terraform_eks_cluster.tf", TerraformSymbols.Extract, TerraformSymbols.Repair, ("aws_eks_cluster.main", 53, 54))
+                { WithParents = false },
+            new DeclarationGoldenMasterCase("dockerfile", "dockerfile_go_service.Dockerfile", DockerfileSymbols.Extract, DockerfileSymbols.Repair, ("runtime", 23, 23))
+                { WithParents = false },
+            new DeclarationGoldenMasterCase("markdown", "markdown_architecture.md", MarkdownSymbols.Extract, MarkdownSymbols.Repair, ("Order Service Architecture", 6, 6))
+                { FilePath = "docs/markdown_architecture.md", WithParents = false }
+        };
+
+        return testCases.Select(testCase => new TestCaseData(testCase).SetName($"{{m}}_{testCase.Language}"));
+    }
+}
+
+/// <summary>
+/// A declaration extractor and the golden master fixture it is checked against
+/// </summary>
+public class DeclarationGoldenMasterCase
+{
+    public DeclarationGoldenMasterCase(
+        string language,
+        string sourceFile,
+        Func<string, string, List<JulieSymbol>> extract,
+        Func<string, string, List<JulieSymbol>, List<JulieSymbol>> repair,
+        (string Name, int StartLine, int EndLine) truncated)
+    {
+        Language = language;
+        SourceFile = sourceFile;
+        FilePath = sourceFile;
+        Extract = extract;
+        Repair = repair;
+        Truncated = truncated;
+    }
+
+    public string Language { get; }
+    public string SourceFile { get; }
+
+    /// <summary>
+    /// The path the fixture is extracted as
+    /// </summary>
+    public string FilePath { get; init; }
+
+    /// <summary>
+    /// Whether the control file lists each symbol's parent
+    /// </summary>
+    public bool WithParents { get; init; } = true;
+
+    public Func<string, string, List<JulieSymbol>> Extract { get; }
+    public Func<string, string, List<JulieSymbol>, List<JulieSymbol>> Repair { get; }
+
+    /// <summary>
+    /// The declaration the extraction found, with the line it was cut short at
+    /// </summary>
+    public (string Name, int StartLine, int EndLine) Truncated { get; }
+
+    public string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", SourceFile));
+
+    public override string ToString() => Language;
+}
@@ -0,0 +1,45 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Dockerfile extraction against the golden master fixture dockerfile_go_service.Dockerfile: parser directives,
+/// global build arguments, a multi-stage build with a stage built from another, ENV in both forms with a comment
+/// inside a continuation, COPY with flags and a heredoc, and exec-form ENTRYPOINT and CMD.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against dockerfile_go_service_symbols.txt.
+/// </summary>
+[TestFixture]
+public class DockerfileGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "dockerfile_go_service.Dockerfile"));
+
+    [Test]
+    public void Extract_Should_Tie_Instructions_To_Stages_And_Images()
+    {
+        // Act
+        var symbols = DockerfileSymbols.Extract("dockerfile_go_service.Dockerfile", Source);
+
+        // Assert
+        Assert.That(symbols.Any(s => s.Name == "build" && s.Kind == "import"), Is.False, "FROM build starts from a stage, not an image");
+        Assert.That(symbols.Single(s => s.Name == "PORT").ParentId, Is.EqualTo(symbols.Single(s => s.Name == "runtime").Id));
+        Assert.That(symbols.Single(s => s.Name == "GOFLAGS").Signature, Is.EqualTo("ENV GOFLAGS=\"-trimpath -mod=readonly\""));
+        Assert.That(symbols.Single(s => s.Name == "golang").Signature, Is.EqualTo("FROM golang:${GO_VERSION}-alpine${ALPINE_VERSION} AS build"));
+        Assert.That(DockerfileSymbols.Repository("localhost:5000/acme/api:2.1"), Is.EqualTo("localhost:5000/acme/api"));
+    }
+
+    [Test]
+    public void References_Should_Find_Expansions_And_Stage_Uses_But_Not_Comments()
+    {
+        // Act
+        var port = DockerfileSymbols.References(Source, "PORT");
+        var build = DockerfileSymbols.References(Source, "build");
+        var arch = DockerfileSymbols.References(Source, "TARGETARCH");
+
+        // Assert - the commented ENV PORT=9090 is left out; the heredoc body is expanded by the shell
+        Assert.That(port, Is.EqualTo(new[] { (29, 6), (32, 7) }));
+        Assert.That(build, Is.EqualTo(new[] { (20, 5), (27, 12) }));
+        Assert.That(arch, Is.EqualTo(new[] { (17, 26) }));
+        Assert.That(DockerfileSymbols.References("RUN echo $PORTS $PORT_NUMBER", "PORT"), Is.Empty);
+    }
+}
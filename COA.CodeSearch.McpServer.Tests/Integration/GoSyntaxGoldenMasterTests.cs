@@ -0,0 +1,89 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Go 1.22+ syntax against the golden master fixture go_iterators.go: range-over-int, range-over-func,
+/// iterators, a generic alias and a closure capturing a loop variable. go_iterators_symbols.txt lists the
+/// symbols extraction must end up with.
+/// </summary>
+[TestFixture]
+public class GoSyntaxGoldenMasterTests
+{
+    private string TestResourcesPath => Path.Combine(TestContext.CurrentContext.TestDirectory, "..", "..", "..", "..", "COA.CodeSearch.McpServer.Tests", "Resources", "GoldenMaster");
+
+    private string Source => File.ReadAllText(Path.Combine(TestResourcesPath, "Sources", "go_iterators.go"));
+
+    private static JulieSymbol Symbol(string kind, string name, int startLine, int endLine) => new()
+    {
+        Id = $"julie-{name}",
+        Name = name,
+        Kind = kind,
+        Language = "go",
+        FilePath = "go_iterators.go",
+        StartLine = startLine,
+        EndLine = endLine
+    };
+
+    [Test]
+    public void FindFeatures_Should_Report_Each_New_Construct()
+    {
+        // Act
+        var features = GoSyntax.FindFeatures(Source);
+
+        // Assert
+        Assert.That(features.Select(f => $"{f.Line} {f.Kind} {f.Since}"), Is.EqualTo(new[]
+        {
+            "22 generic-alias 1.24",
+            "34 iterator 1.23",
+            "45 iterator 1.23",
+            "53 range-over-int 1.22",
+            "56 range-over-int 1.22",
+            "62 iterator 1.23",
+            "78 range-over-func 1.23",
+            "81 range-over-func 1.23",
+            "91 loop-capture 1.22"
+        }));
+    }
+
+    [Test]
+    public void Repair_Should_Recover_Declarations_Dropped_After_A_Parse_Error()
+    {
+        // Arrange - an older grammar gave up inside All and cut its extent short
+        var extracted = new List<JulieSymbol>
+        {
+            Symbol("struct", "Set", 11, 13),
+            Symbol("struct", "Pair", 16, 19),
+            Symbol("function", "NewSet", 25, 31),
+            Symbol("method", "All", 34, 36),
+            Symbol("function", "range", 36, 36)
+        };
+
+        // Act
+        var repaired = GoSyntax.Repair("go_iterators.go", Source, extracted);
+
+        // Assert
+        var golden = File.ReadAllLines(Path.Combine(TestResourcesPath, "Controls", "go_iterators_symbols.txt"))
+            .Where(l => l.Length > 0);
+        Assert.That(repaired.Select(s => $"{s.Kind} {s.Name} {s.StartLine}-{s.EndLine}"), Is.EqualTo(golden));
+        Assert.That(repaired.Single(s => s.Name == "All").Id, Is.EqualTo("julie-All"), "Extracted symbols keep their IDs");
+        Assert.That(repaired.Single(s => s.Name == "Len").ParentId, Is.EqualTo("julie-Set"));
+    }
+
+    [Test]
+    public void Repair_Should_Leave_Files_Without_New_Syntax_Alone()
+    {
+        // Arrange
+        var source = File.ReadAllText(Path.Combine(TestResourcesPath, "Sources", "go_main.go"));
+        var extracted = new List<JulieSymbol> { Symbol("function", "main", 1, 1) };
+
+        // Act
+        var repaired = GoSyntax.Repair("go_main.go", source, extracted);
+
+        // Assert
+        Assert.That(GoSyntax.FindFeatures(source), Is.Empty);
+        Assert.That(ReferenceEquals(repaired, extracted), Is.True);
+    }
+}
@@ -0,0 +1,46 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// GraphQL extraction against the golden master fixture graphql_store_schema.graphql: a schema block renaming the
+/// root types, a scalar, an interface, object types with directives, arguments spread over several lines and an
+/// implements clause on its own lines, an enum with a commented value, a multi-line union, input types, an
+/// <c>extend type</c> block and a commented-out type. <see cref="DeclarationGoldenMasterTests"/> checks it against
+/// graphql_store_schema_symbols.txt.
+/// </summary>
+[TestFixture]
+public class GraphQLGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "graphql_store_schema.graphql"));
+
+    [Test]
+    public void Extract_Should_Skip_Commented_Types_And_Read_Doc_Comments()
+    {
+        // Act
+        var symbols = GraphQLSymbols.Extract("graphql_store_schema.graphql", Source);
+
+        // Assert
+        Assert.That(symbols.Any(s => s.Name == "Archived"), Is.False, "Commented-out types are not declarations");
+        Assert.That(symbols.Single(s => s.Name == "SearchResult").Signature, Is.EqualTo("union SearchResult = User | Order"));
+        Assert.That(symbols.Single(s => s.Name == "Node").DocComment, Does.Contain("looked up by its global ID"));
+    }
+
+    [Test]
+    public void Extract_Should_Sign_Root_Fields_With_Their_Operation()
+    {
+        // Act
+        var symbols = GraphQLSymbols.Extract("graphql_store_schema.graphql", Source);
+
+        // Assert - the schema block makes StoreQuery and StoreMutation the roots
+        Assert.That(symbols.Where(s => s.Kind == "method").Select(s => s.Signature), Is.EqualTo(new[]
+        {
+            "query user(id: ID!): User",
+            "query search(term: String!, limit: Int = 20): [SearchResult!]!",
+            "mutation placeOrder(input: PlaceOrderInput!): Order!"
+        }));
+        Assert.That(symbols.Single(s => s.Name == "email").Signature, Is.EqualTo("email: String!"), "Directives are dropped");
+        Assert.That(symbols.Single(s => s.Name == "orders").Signature, Is.EqualTo("orders(first: Int = 10, after: String): [Order!]!"));
+    }
+}
@@ -0,0 +1,43 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Kotlin extraction against the golden master fixture kotlin_services.kt: data classes, enum entries with
+/// bodies, sealed interfaces, companion objects, secondary constructors, suspend and extension functions.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against kotlin_services_symbols.txt.
+/// </summary>
+[TestFixture]
+public class KotlinGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "kotlin_services.kt"));
+
+    [Test]
+    public void Extract_Should_Read_Visibility_And_Doc_Comments()
+    {
+        // Act
+        var symbols = KotlinSymbols.Extract("kotlin_services.kt", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "status").Visibility, Is.EqualTo("private"));
+        Assert.That(symbols.Single(s => s.Name == "OrderLine").DocComment, Does.Contain("An order line"));
+    }
+
+    [Test]
+    public void ReceiverOf_And_IsCompanion_Should_Expose_How_Members_Are_Called()
+    {
+        // Arrange
+        var symbols = KotlinSymbols.Extract("kotlin_services.kt", Source);
+
+        // Act
+        var receivers = symbols.Where(s => s.ParentId == null)
+            .Select(s => (s.Name, Receiver: KotlinSymbols.ReceiverOf(s.Signature)))
+            .Where(r => r.Receiver != null)
+            .Select(r => $"{r.Receiver}.{r.Name}");
+
+        // Assert
+        Assert.That(receivers, Is.EqualTo(new[] { "Order.summary", "List.largest", "String.isSku" }));
+        Assert.That(symbols.Where(KotlinSymbols.IsCompanion).Select(s => s.Name), Is.EqualTo(new[] { "Companion", "Factory" }));
+    }
+}
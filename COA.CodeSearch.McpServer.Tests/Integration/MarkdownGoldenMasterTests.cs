@@ -0,0 +1,45 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Markdown extraction against the golden master fixture markdown_architecture.md: YAML front matter, ATX and
+/// setext headings, a custom heading id, inline code in a heading, repeated headings, # lines inside fenced and
+/// indented code, a thematic break under a list, and fences with, without and after a language.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against markdown_architecture_symbols.txt.
+/// </summary>
+[TestFixture]
+public class MarkdownGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "markdown_architecture.md"));
+
+    [Test]
+    public void Extract_Should_Nest_Headings_By_Level()
+    {
+        // Act
+        var symbols = MarkdownSymbols.Extract("docs/markdown_architecture.md", Source);
+
+        // Assert
+        Assert.That(symbols.Where(s => s.Kind == "heading").Select(MarkdownSymbols.Level), Is.EqualTo(new[] { 1, 2, 3, 2, 3, 2, 3, 2, 1, 4 }));
+        Assert.That(symbols.Single(s => s.Name == "go").ParentId, Is.EqualTo(symbols.Single(s => s.Name == "Event Flow").Id));
+        Assert.That(symbols.Single(s => s.Name == "Event Flow").ParentId, Is.EqualTo(symbols.Single(s => s.Name == "Order Service Architecture").Id));
+    }
+
+    [Test]
+    public void Extract_Should_Put_GitHub_Anchors_In_Signatures()
+    {
+        // Act
+        var headings = MarkdownSymbols.Extract("docs/markdown_architecture.md", Source).Where(s => s.Kind == "heading").ToList();
+
+        // Assert - custom ids win, repeated headings are numbered as GitHub numbers them
+        Assert.That(headings.Single(s => s.Name == "Storage & Indexing (v2)").Signature, Is.EqualTo("## Storage & Indexing (v2) (#storage--indexing-v2)"));
+        Assert.That(headings.Single(s => s.Name == "Schema migrations").Signature, Is.EqualTo("### Schema migrations (#migrations)"));
+        Assert.That(headings.Where(s => s.Name == "Usage").Select(s => s.Signature), Is.EqualTo(new[]
+        {
+            "## Usage (#usage)", "### Usage (#usage-1)", "## Usage (#usage-2)"
+        }));
+        Assert.That(headings.Single(s => s.Name == "The order_created event").Signature, Does.EndWith("(#the-order_created-event)"));
+        Assert.That(MarkdownSymbols.Anchor("Café 2.0: What's New?"), Is.EqualTo("café-20-whats-new"));
+    }
+}
@@ -0,0 +1,50 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Protobuf extraction against the golden master fixture proto_user_service.proto: a package, messages with nested
+/// messages and enums, repeated, map, optional and oneof fields, reserved and option lines, an aliased enum, a
+/// one-line message, a block-commented message and a service whose RPCs stream and carry option bodies.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against proto_user_service_symbols.txt.
+/// </summary>
+[TestFixture]
+public class ProtoGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "proto_user_service.proto"));
+
+    [Test]
+    public void Extract_Should_Sign_Rpcs_And_Skip_Options_And_Reserved_Names()
+    {
+        // Act
+        var symbols = ProtoSymbols.Extract("proto_user_service.proto", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "ListOrders").Signature, Is.EqualTo("rpc ListOrders(GetUserRequest) returns (stream Order)"));
+        Assert.That(symbols.Single(s => s.Name == "UserService").DocComment, Does.Contain("watches their orders"));
+        Assert.That(symbols.Any(s => s.Name is "LegacyUser" or "allow_alias" or "legacy_name"), Is.False,
+            "Comments, options and reserved names are not declarations");
+    }
+
+    [Test]
+    public void GeneratedNames_Should_Follow_Go_And_CSharp_Generators()
+    {
+        // Act
+        string Names(string kind, string name, params string[] containers) =>
+            string.Join(" ", ProtoSymbols.GeneratedNames(kind, name, containers).Select(n => $"{n.Language}:{n.Name}"));
+
+        // Assert
+        Assert.That(Names("field", "display_name", "User"), Is.EqualTo("go:DisplayName go:GetDisplayName csharp:DisplayName"));
+        Assert.That(Names("class", "Line", "Order"), Is.EqualTo("go:Order_Line csharp:Line"));
+        Assert.That(Names("enum_member", "KIND_GIFT", "Order", "Line", "Kind"), Is.EqualTo("go:Order_Line_KIND_GIFT csharp:Gift"));
+        Assert.That(Names("enum_member", "STATUS_ACTIVE", "Status"), Is.EqualTo("go:Status_STATUS_ACTIVE csharp:Active"));
+        Assert.That(Names("interface", "UserService"), Is.EqualTo(
+            "go:UserServiceClient go:UserServiceServer go:NewUserServiceClient go:RegisterUserServiceServer " +
+            "go:UnimplementedUserServiceServer csharp:UserServiceClient csharp:UserServiceBase"));
+        Assert.That(Names("method", "GetUser", "UserService"), Is.EqualTo("go:GetUser csharp:GetUser csharp:GetUserAsync"));
+        Assert.That(ProtoSymbols.CandidateProtoNames("GetDisplayName"), Does.Contain("display_name"));
+        Assert.That(ProtoSymbols.CandidateProtoNames("RegisterUserServiceServer"), Does.Contain("UserService"));
+        Assert.That(ProtoSymbols.CandidateProtoNames("Order_Line_KIND_GIFT"), Does.Contain("KIND_GIFT"));
+    }
+}
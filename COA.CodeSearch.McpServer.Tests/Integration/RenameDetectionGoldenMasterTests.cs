@@ -0,0 +1,87 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Rename detection against the golden master Go fixtures: go_main_renamed.go renames
+/// Helper → HelperHandler and UsingHelper → UsingHelperHandler, the other controls don't rename anything
+/// </summary>
+[TestFixture]
+public class RenameDetectionGoldenMasterTests
+{
+    private string TestResourcesPath => Path.Combine(TestContext.CurrentContext.TestDirectory, "..", "..", "..", "..", "COA.CodeSearch.McpServer.Tests", "Resources", "GoldenMaster");
+
+    private List<SymbolChange> CompareWithSource(string controlFile)
+    {
+        var before = DeclarationScanner.Scan(File.ReadAllText(Path.Combine(TestResourcesPath, "Sources", "go_main.go")), "go_main.go");
+        var after = DeclarationScanner.Scan(File.ReadAllText(Path.Combine(TestResourcesPath, "Controls", controlFile)), "go_main.go");
+        return SymbolDiffer.Compare("go_main.go", before, "go_main.go", after);
+    }
+
+    [Test]
+    public void Renamed_Control_Should_Map_Old_Names_To_New_Names()
+    {
+        // Act
+        var changes = CompareWithSource("go_main_renamed.go");
+
+        // Assert
+        Assert.That(changes.Select(c => $"{c.Change}:{c.OldName}->{c.Name}"), Is.EquivalentTo(new[]
+        {
+            "renamed:Helper->HelperHandler",
+            "renamed:UsingHelper->UsingHelperHandler"
+        }));
+        Assert.That(changes.All(c => c.Confidence >= 0.85), Is.True,
+            $"Confidence too low: {string.Join(", ", changes.Select(c => $"{c.Name}={c.Confidence}"))}");
+        Assert.That(changes.All(c => c.IsBreaking), Is.True, "Exported Go functions are public API");
+    }
+
+    [Test]
+    public void Error_Handling_Control_Should_Report_Signature_Change_Not_Rename()
+    {
+        // Act
+        var changes = CompareWithSource("go_main_error_handling.go");
+
+        // Assert
+        Assert.That(changes.Select(c => $"{c.Change}:{c.Name}"), Is.EquivalentTo(new[]
+        {
+            "modified:Helper",
+            "added:validateState"
+        }));
+        Assert.That(changes.Single(c => c.Name == "Helper").Signature, Is.EqualTo("func Helper() error"));
+    }
+
+    [Test]
+    public void Struct_Field_Change_Should_Not_Change_Declarations()
+    {
+        // Act
+        var changes = CompareWithSource("go_main_struct_updated.go");
+
+        // Assert
+        Assert.That(changes, Is.Empty);
+    }
+
+    [Test]
+    public void Function_Moved_To_Another_File_Should_Be_Reported_As_Move()
+    {
+        // Arrange
+        var original = DeclarationScanner.Scan(File.ReadAllText(Path.Combine(TestResourcesPath, "Sources", "go_main.go")), "go_main.go");
+        var remaining = original.Where(d => d.Name != "Helper").ToList();
+        var helperFile = DeclarationScanner.Scan(
+            "package main\n\nfunc Helper() {\n    fmt.Println(\"Helper function called\")\n}\n", "helper.go");
+
+        // Act
+        var changes = SymbolDiffer.CompareFiles(new (string?, IReadOnlyList<Declaration>, string, IReadOnlyList<Declaration>)[]
+        {
+            ("go_main.go", original, "go_main.go", remaining),
+            (null, new List<Declaration>(), "helper.go", helperFile)
+        });
+
+        // Assert
+        var move = changes.Single();
+        Assert.That(move.Change, Is.EqualTo("moved"));
+        Assert.That(move.OldFilePath, Is.EqualTo("go_main.go"));
+        Assert.That(move.FilePath, Is.EqualTo("helper.go"));
+        Assert.That(move.IsBreaking, Is.False);
+    }
+}
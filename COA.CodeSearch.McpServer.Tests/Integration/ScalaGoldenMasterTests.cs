@@ -0,0 +1,52 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Scala extraction against the golden master fixture scala_spark_job.scala: a Scala 3 enum with cases, case
+/// classes and their constructor properties, a sealed trait with case objects, auxiliary constructors, implicit
+/// vals, classes and defs, given instances, extension methods, and the method bodies and multiline strings whose
+/// contents are not declarations. <see cref="DeclarationGoldenMasterTests"/> checks it against scala_spark_job_symbols.txt.
+/// </summary>
+[TestFixture]
+public class ScalaGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "scala_spark_job.scala"));
+
+    [Test]
+    public void Extract_Should_Read_Visibility_And_Doc_Comments()
+    {
+        // Act
+        var symbols = ScalaSymbols.Extract("scala_spark_job.scala", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "attempts").Visibility, Is.EqualTo("private"));
+        Assert.That(symbols.Single(s => s.Name == "processed").Visibility, Is.EqualTo("private"), "Qualified private is still private");
+        Assert.That(symbols.Single(s => s.Name == "Event" && s.Kind == "class").DocComment, Does.Contain("lands in the bucket"));
+    }
+
+    [Test]
+    public void Implicits_And_Givens_Should_Be_Found_With_Their_Context_Parameters()
+    {
+        // Arrange
+        var symbols = ScalaSymbols.Extract("scala_spark_job.scala", Source);
+        var names = symbols.ToDictionary(s => s.Id, s => s.Name);
+
+        // Act
+        var implicits = symbols.Where(ScalaSymbols.IsImplicit).Select(s => $"{s.Kind} {s.Name}");
+
+        // Assert
+        Assert.That(implicits, Is.EqualTo(new[]
+        {
+            "property eventOrdering", "class RichFrame", "method toSummary", "variable severityOrdering", "class given_Source_Event"
+        }));
+        Assert.That(ScalaSymbols.ContextParametersOf(symbols.Single(s => s.Name == "read" && names[s.ParentId!] == "Source").Signature),
+            Is.EqualTo(new[] { "SparkSession", "Encoder[T]" }));
+        Assert.That(ScalaSymbols.ContextParametersOf(symbols.Single(s => s.Name == "summarize").Signature), Is.EqualTo(new[] { "SparkSession" }));
+        Assert.That(symbols.Where(s => s.Kind == "method" && s.Name is "urgent" or "byUser").Select(s => names[s.ParentId!]), Is.EqualTo(new[] { "Dataset", "Dataset" }),
+            "Extension methods are members of the type they extend");
+        Assert.That(symbols.Any(s => s.Name is "raw" or "next" or "urgentCount" or "spark" or "notADeclaration" or "nor"), Is.False,
+            "Locals and multiline string text are not declarations");
+    }
+}
@@ -0,0 +1,59 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// SQL extraction against the golden master fixture sql_schema.sql: a PostgreSQL enum type, tables with quoted
+/// and schema-qualified names, columns, table constraints and string defaults holding ; and --, indexes, ALTER
+/// TABLE ADD COLUMN, views, a dollar-quoted function body, a trigger, a transaction and a MySQL procedure behind
+/// DELIMITER. <see cref="DeclarationGoldenMasterTests"/> checks it against sql_schema_symbols.txt.
+/// </summary>
+[TestFixture]
+public class SqlGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "sql_schema.sql"));
+
+    [Test]
+    public void Extract_Should_Keep_Quoted_Defaults_And_Skip_Non_Tables()
+    {
+        // Act
+        var symbols = SqlSymbols.Extract("sql_schema.sql", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "note").Signature, Is.EqualTo("note TEXT DEFAULT 'no; comment -- here'"));
+        Assert.That(symbols.Single(s => s.Name == "users").DocComment, Does.Contain("email is unique per tenant"));
+        Assert.That(symbols.Any(s => s.Name is "not_a_table" or "scratch" or "invoice_numbers"), Is.False,
+            "Comments, routine bodies and sequences are not tables");
+    }
+
+    [Test]
+    public void TSql_Batches_Should_End_Routines_At_Go()
+    {
+        // Arrange - a procedure body without BEGIN ... END runs to the GO ending its batch
+        const string source = """
+            CREATE TABLE [dbo].[Invoices] (
+                [InvoiceId] INT IDENTITY(1,1) NOT NULL,
+                [user_id] BIGINT NOT NULL
+            )
+            GO
+
+            CREATE OR ALTER PROCEDURE [dbo].[usp_InvoiceTotals]
+                @From DATE
+            AS
+                SELECT COUNT(*) FROM dbo.Invoices;
+                SELECT CASE WHEN @From IS NULL THEN 0 ELSE 1 END;
+            GO
+            """;
+
+        // Act
+        var symbols = SqlSymbols.Extract("reports.sql", source);
+
+        // Assert
+        Assert.That(symbols.Select(s => $"{s.Kind} {s.Name} {s.StartLine}-{s.EndLine}"), Is.EqualTo(new[]
+        {
+            "table Invoices 1-4", "column InvoiceId 2-2", "column user_id 3-3", "procedure usp_InvoiceTotals 7-11"
+        }));
+        Assert.That(SqlSymbols.Unquote("[dbo].[usp_InvoiceTotals]"), Is.EqualTo("usp_InvoiceTotals"));
+    }
+}
@@ -0,0 +1,47 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Swift extraction against the golden master fixture swift_store.swift: property wrappers, protocols with
+/// associated types, enums with associated values, extensions, actors, initializers, subscripts and operators.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against swift_store_symbols.txt.
+/// </summary>
+[TestFixture]
+public class SwiftGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "swift_store.swift"));
+
+    [Test]
+    public void Extract_Should_Read_Visibility_And_Doc_Comments()
+    {
+        // Act
+        var symbols = SwiftSymbols.Extract("swift_store.swift", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "note").Visibility, Is.EqualTo("private"));
+        Assert.That(symbols.Single(s => s.Name == "lines").Visibility, Is.EqualTo("public"), "private(set) only restricts the setter");
+        Assert.That(symbols.Single(s => s.Name == "Clamped" && s.Kind == "struct").DocComment, Does.Contain("Clamps a wrapped value"));
+    }
+
+    [Test]
+    public void Extensions_And_Property_Wrappers_Should_Be_Navigable()
+    {
+        // Arrange
+        var symbols = SwiftSymbols.Extract("swift_store.swift", Source);
+        var extension = symbols.Single(s => s.Kind == "extension");
+
+        // Act
+        var extensionMembers = symbols.Where(s => s.ParentId == extension.Id).Select(s => s.Name);
+        var wrappers = symbols.Where(s => s.Kind == "property")
+            .Select(s => (s.Name, Wrappers: SwiftSymbols.PropertyWrappersOf(s.Signature)))
+            .Where(p => p.Wrappers.Count > 0)
+            .Select(p => $"{p.Name}: {string.Join(", ", p.Wrappers)}");
+
+        // Assert
+        Assert.That(extension.Name, Is.EqualTo("Order"));
+        Assert.That(extensionMembers, Is.EqualTo(new[] { "price", "discounted", "==" }));
+        Assert.That(wrappers, Is.EqualTo(new[] { "orders: Published", "pageSize: Clamped", "lastOrder: AppStorage" }));
+    }
+}
@@ -0,0 +1,51 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Terraform extraction against the golden master fixture terraform_eks_cluster.tf: terraform and provider
+/// blocks, variables with descriptions, comments and nested validation blocks, locals holding maps and
+/// interpolations, a data source, a module, resources with nested blocks and a heredoc, a block-commented
+/// resource and an output. <see cref="DeclarationGoldenMasterTests"/> checks it against terraform_eks_cluster_symbols.txt.
+/// </summary>
+[TestFixture]
+public class TerraformGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "terraform_eks_cluster.tf"));
+
+    [Test]
+    public void Extract_Should_Read_Doc_Comments_And_Addresses()
+    {
+        // Act
+        var symbols = TerraformSymbols.Extract("terraform_eks_cluster.tf", Source);
+
+        // Assert
+        Assert.That(symbols.Any(s => s.Name == "aws_eks_node_group.legacy"), Is.False, "Commented-out resources are not declarations");
+        Assert.That(symbols.Single(s => s.Name == "cluster_name").DocComment, Does.Contain("Name shared by the cluster"));
+        Assert.That(symbols.Single(s => s.Name == "region").DocComment, Is.EqualTo("AWS region to deploy into"), "Falls back to the description");
+        Assert.That(symbols.Select(TerraformSymbols.Address).Where(a => a != null), Is.EqualTo(new[]
+        {
+            "var.cluster_name", "var.region", "var.node_count", "local.common_tags", "local.node_name", "data.aws_ami.eks_worker",
+            "module.network", "aws_eks_cluster.main", "aws_iam_role.cluster", "module.*.cluster_endpoint"
+        }));
+    }
+
+    [Test]
+    public void References_Should_Find_Uses_In_Expressions_And_Interpolations_But_Not_Comments()
+    {
+        // Act
+        var uses = TerraformSymbols.References("terraform_eks_cluster.tf", Source, "var.cluster_name");
+        var outputs = TerraformSymbols.References("main.tf", "endpoint = module.eks.cluster_endpoint\nother = module.eks.cluster_endpoint_v2", "module.*.cluster_endpoint");
+        var tfvars = TerraformSymbols.References("prod.tfvars", "cluster_name = \"orders-prod\"\n# cluster_name = \"old\"", "var.cluster_name");
+
+        // Assert - the commented tags line and the block-commented node group are left out
+        Assert.That(uses.Select(u => u.Line), Is.EqualTo(new[] { 36, 39, 49, 54, 66 }));
+        Assert.That(uses.First(), Is.EqualTo((36, 14)));
+        Assert.That(outputs, Is.EqualTo(new[] { (1, 11) }));
+        Assert.That(tfvars, Is.EqualTo(new[] { (1, 0) }));
+        Assert.That(TerraformSymbols.CandidateNames("var.cluster_name"), Does.Contain("cluster_name"));
+        Assert.That(TerraformSymbols.CandidateNames("aws_eks_cluster.main[0].endpoint"), Does.Contain("aws_eks_cluster.main"));
+        Assert.That(TerraformSymbols.CandidateNames("module.network.private_subnet_ids"), Does.Contain("private_subnet_ids"));
+    }
+}
@@ -0,0 +1,46 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Integration;
+
+/// <summary>
+/// Zig extraction against the golden master fixture zig_allocator.zig: error sets, enums with methods, structs
+/// and unions with fields, comptime and threadlocal variables, generic type functions, export and extern
+/// functions, and the multiline strings, tests and comptime blocks whose contents are not declarations.
+/// <see cref="DeclarationGoldenMasterTests"/> checks it against zig_allocator_symbols.txt.
+/// </summary>
+[TestFixture]
+public class ZigGoldenMasterTests
+{
+    private string Source => File.ReadAllText(Path.Combine(DeclarationGoldenMasterTests.TestResourcesPath, "Sources", "zig_allocator.zig"));
+
+    [Test]
+    public void Extract_Should_Read_Visibility_And_Doc_Comments()
+    {
+        // Act
+        var symbols = ZigSymbols.Extract("zig_allocator.zig", Source);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Name == "release").Visibility, Is.EqualTo("private"));
+        Assert.That(symbols.Single(s => s.Name == "pool_used").Visibility, Is.EqualTo("public"), "Exported functions are visible outside the file");
+        Assert.That(symbols.Single(s => s.Name == "PoolError").DocComment, Does.Contain("runs dry"));
+    }
+
+    [Test]
+    public void Generic_Types_Should_Own_The_Members_Of_The_Struct_They_Return()
+    {
+        // Arrange
+        var symbols = ZigSymbols.Extract("zig_allocator.zig", Source);
+        var list = symbols.Single(s => s.Name == "List");
+
+        // Act
+        var members = symbols.Where(s => s.ParentId == list.Id).Select(s => $"{s.Kind} {s.Name}");
+
+        // Assert
+        Assert.That(list.Kind, Is.EqualTo("struct"));
+        Assert.That(ZigSymbols.ComptimeParametersOf(list.Signature), Is.EqualTo(new[] { "T" }));
+        Assert.That(ZigSymbols.ComptimeParametersOf(symbols.Single(s => s.Name == "max").Signature), Is.EqualTo(new[] { "T" }));
+        Assert.That(members, Is.EqualTo(new[] { "field items", "field capacity", "field allocator", "constant Self", "method append", "method grow" }));
+        Assert.That(symbols.Any(s => s.Name is "local" or "larger" or "fake"), Is.False, "Locals and multiline string text are not declarations");
+    }
+}
@@ -0,0 +1,97 @@
+package collections
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Set is an unordered collection of unique values.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// Pair holds a key and its value.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Index is a generic alias (Go 1.24).
+type Index[K comparable] = map[K][]int
+
+// NewSet returns a set holding values.
+func NewSet[T comparable](values ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(values))}
+	for _, v := range values {
+		s.items[v] = struct{}{}
+	}
+	return s
+}
+
+// All yields every member of the set.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s.items {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted yields the members in order.
+func Sorted[S ~[]E, E cmp.Ordered](values S) iter.Seq2[int, E] {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return slices.All(sorted)
+}
+
+// Repeat calls fn n times.
+func Repeat(n int, fn func(int)) {
+	for i := range n {
+		fn(i)
+	}
+	for range 3 {
+		fn(-1)
+	}
+}
+
+// Backward yields the indexes of values from last to first.
+func Backward[E any](values []E) func(yield func(int, E) bool) {
+	return func(yield func(int, E) bool) {
+		for i := len(values) - 1; i >= 0; i-- {
+			if !yield(i, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Collect gathers the pairs of a map in key order.
+func Collect[K cmp.Ordered, V any](m map[K]V) []Pair[K, V] {
+	var pairs []Pair[K, V]
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		pairs = append(pairs, Pair[K, V]{Key: k, Value: m[k]})
+	}
+	for i, v := range Backward(pairs) {
+		_, _ = i, v
+	}
+	for v := range maps.Values(m) {
+		_ = v
+	}
+	return pairs
+}
+
+// Handlers captures each loop variable per iteration (Go 1.22 semantics).
+func Handlers(names []string) []func() string {
+	var handlers []func() string
+	for _, name := range names {
+		handlers = append(handlers, func() string { return name })
+	}
+	return handlers
+}
+
+// Len reports the set's size.
+func (s *Set[T]) Len() int { return len(s.items) }
@@ -0,0 +1,91 @@
+package com.acme.orders
+
+import kotlinx.coroutines.flow.Flow
+import kotlinx.coroutines.flow.flow
+
+typealias OrderId = Long
+
+const val MAX_RETRIES = 3
+
+/**
+ * An order line with its quantity.
+ */
+data class OrderLine(
+    val sku: String,
+    val quantity: Int,
+)
+
+data class Order(val id: OrderId, val lines: List<OrderLine>, private var status: Status = Status.NEW) {
+    val total: Int
+        get() = lines.sumOf { it.quantity }
+
+    fun isOpen(): Boolean = status != Status.SHIPPED
+
+    companion object {
+        fun empty(id: OrderId) = Order(id, emptyList())
+    }
+}
+
+enum class Status(val label: String) {
+    NEW("new"),
+    PAID("paid") {
+        override fun next() = SHIPPED
+    },
+    SHIPPED("shipped");
+
+    open fun next(): Status = this
+}
+
+sealed interface OrderEvent {
+    data class Placed(val order: Order) : OrderEvent
+    object Cancelled : OrderEvent
+}
+
+interface OrderRepository {
+    suspend fun find(id: OrderId): Order?
+    fun all(): Flow<Order>
+}
+
+class OrderService(private val repository: OrderRepository) {
+    private val cache = mutableMapOf<OrderId, Order>()
+
+    init {
+        val warm = "start { not a block }"
+        println(warm)
+    }
+
+    constructor() : this(InMemoryRepository())
+
+    suspend fun place(order: Order): Order {
+        val placed = order.copy()
+        fun audit(message: String) = println(message)
+        audit("placed ${placed.id}")
+        cache[placed.id] = placed
+        return placed
+    }
+
+    fun watch(): Flow<Order> = flow {
+        repository.all().collect { emit(it) }
+    }
+
+    companion object Factory {
+        @JvmStatic
+        fun create(): OrderService = OrderService()
+    }
+}
+
+object InMemoryRepository : OrderRepository {
+    override suspend fun find(id: OrderId): Order? = null
+    override fun all(): Flow<Order> = flow { }
+}
+
+fun Order.summary(): String = "${id}: ${total}"
+
+suspend fun <T : Comparable<T>> List<T>.largest(): T? = maxOrNull()
+
+val String.isSku: Boolean
+    get() = startsWith("SKU-")
+
+fun `places an order`() {
+    val service = OrderService.create()
+}
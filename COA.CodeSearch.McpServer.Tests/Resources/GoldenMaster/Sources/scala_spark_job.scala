@@ -0,0 +1,104 @@
+package com.example.jobs
+
+import org.apache.spark.sql.{DataFrame, Dataset, Encoder, SparkSession}
+import org.apache.spark.sql.functions._
+
+/** Severity of a pipeline event, ordered from least to most urgent */
+enum Severity(val weight: Int):
+  case Debug extends Severity(0)
+  case Info, Notice extends Severity(1)
+  case Error extends Severity(10)
+
+  def isUrgent: Boolean = weight >= 10
+end Severity
+
+/** A raw event as it lands in the bucket */
+case class Event(
+  id: String,
+  userId: Long,
+  @transient payload: String,
+  severity: Severity = Severity.Info
+)
+
+case class Summary(userId: Long, events: Long, urgent: Long)
+
+sealed trait Outcome {
+  def label: String
+}
+
+case object Succeeded extends Outcome { val label = "ok" }
+case object Failed extends Outcome {
+  val label = "failed"
+}
+
+class Checkpoint(val path: String, private var attempts: Int, retries: Int) {
+  def this(path: String) = this(path, 0, 3)
+
+  protected def bump(): Unit = {
+    val next = attempts + 1
+    attempts = next
+  }
+
+  type Marker = String
+}
+
+trait Source[T] {
+  def read(path: String)(using spark: SparkSession, enc: Encoder[T]): Dataset[T]
+}
+
+object Implicits {
+  implicit val eventOrdering: Ordering[Event] = Ordering.by(_.id)
+
+  implicit class RichFrame(val df: DataFrame) extends AnyVal {
+    def nonEmpty: Boolean = !df.isEmpty
+  }
+
+  implicit def toSummary(event: Event): Summary = Summary(event.userId, 1, 0)
+}
+
+given severityOrdering: Ordering[Severity] = Ordering.by(_.weight)
+
+given Source[Event] with
+  def read(path: String)(using spark: SparkSession, enc: Encoder[Event]): Dataset[Event] =
+    val raw = spark.read.json(path)
+    raw.as[Event]
+
+extension (ds: Dataset[Event])
+  def urgent: Dataset[Event] = ds.filter(_.severity.isUrgent)
+  def byUser(using spark: SparkSession): DataFrame =
+    import spark.implicits._
+    ds.groupBy($"userId").count()
+
+extension (s: String) def toSlug: String = s.toLowerCase.replace(' ', '-')
+
+object EventSummaryJob {
+  private val AppName = "event-summary"
+  lazy val defaultPath: String =
+    sys.env.getOrElse("EVENTS_PATH", "s3://events/")
+
+  private[jobs] var processed = 0L
+
+  val Query =
+    """
+      |val notADeclaration = 1
+      |def nor(this: Int) = 2
+      |""".stripMargin
+
+  def summarize(events: Dataset[Event])(implicit spark: SparkSession): Dataset[Summary] = {
+    import spark.implicits._
+    def urgentCount(e: Event): Long = if (e.severity.isUrgent) 1 else 0
+    events
+      .groupByKey(_.userId)
+      .mapGroups { (user, rows) =>
+        val all = rows.toSeq
+        Summary(user, all.size, all.map(urgentCount).sum)
+      }
+  }
+
+  def main(args: Array[String]): Unit = {
+    given spark: SparkSession = SparkSession.builder().appName(AppName).getOrCreate()
+    val events = summon[Source[Event]].read(args.headOption.getOrElse(defaultPath))
+    summarize(events).write.parquet("s3://summaries/")
+    processed += 1
+  }
+}
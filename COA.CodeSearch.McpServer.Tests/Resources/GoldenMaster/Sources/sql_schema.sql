@@ -0,0 +1,75 @@
+-- Orders schema: PostgreSQL tables, a view and a function, then the
+-- reporting procedures from the SQL Server and MySQL deployments.
+
+CREATE TYPE order_status AS ENUM ('pending', 'paid', 'shipped');
+
+-- Registered customers; email is unique per tenant.
+CREATE TABLE IF NOT EXISTS "public"."users" (
+    user_id    BIGSERIAL PRIMARY KEY,
+    tenant_id  INT NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    "display name" TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT users_email_key UNIQUE (tenant_id, email)
+);
+
+CREATE TABLE orders (
+    order_id   BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users (user_id),
+    status     order_status NOT NULL DEFAULT 'pending',
+    total      NUMERIC(12, 2) NOT NULL CHECK (total >= 0),
+    note       TEXT DEFAULT 'no; comment -- here',
+    PRIMARY KEY (order_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (tenant_id, lower(email));
+CREATE INDEX idx_orders_user
+    ON orders USING btree (user_id, status);
+CREATE INDEX ON orders (total);
+
+ALTER TABLE orders
+    ADD COLUMN shipped_at TIMESTAMPTZ,
+    ADD CONSTRAINT orders_total_positive CHECK (total > 0),
+    ADD COLUMN IF NOT EXISTS tracking_code TEXT;
+
+ALTER TABLE audit_log ADD COLUMN actor_id BIGINT;
+
+/* Open orders with their customer.
+   CREATE TABLE not_a_table (id INT); */
+CREATE OR REPLACE VIEW open_orders AS
+SELECT o.order_id, o.user_id, u.email
+FROM orders o
+JOIN users u ON u.user_id = o.user_id
+WHERE o.status <> 'shipped';
+
+CREATE MATERIALIZED VIEW daily_totals AS
+SELECT date_trunc('day', created_at) AS day, sum(total) AS total
+FROM orders GROUP BY 1;
+
+-- Marks an order paid and returns how many rows changed.
+CREATE OR REPLACE FUNCTION mark_paid(p_order_id BIGINT) RETURNS INT AS $$
+DECLARE
+    changed INT;
+BEGIN
+    CREATE TEMP TABLE scratch (id INT);
+    UPDATE orders SET status = 'paid' WHERE order_id = p_order_id;
+    GET DIAGNOSTICS changed = ROW_COUNT;
+    RETURN changed;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_touch
+    BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION mark_paid();
+
+BEGIN;
+CREATE SEQUENCE invoice_numbers START 1000;
+COMMIT;
+
+DELIMITER //
+CREATE DEFINER = 'admin'@'%' PROCEDURE refresh_totals(IN since DATE)
+BEGIN
+    DELETE FROM daily_totals WHERE day >= since;
+    INSERT INTO daily_totals SELECT since, 0;
+END //
+DELIMITER ;
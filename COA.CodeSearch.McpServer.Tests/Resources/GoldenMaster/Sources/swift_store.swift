@@ -0,0 +1,114 @@
+import Foundation
+import SwiftUI
+
+typealias OrderID = UUID
+
+let maxRetries = 3
+
+/// Clamps a wrapped value to a range.
+@propertyWrapper
+struct Clamped<Value: Comparable> {
+    private var value: Value
+    let range: ClosedRange<Value>
+
+    var wrappedValue: Value {
+        get { value }
+        set { value = min(max(newValue, range.lowerBound), range.upperBound) }
+    }
+
+    init(wrappedValue: Value, _ range: ClosedRange<Value>) {
+        self.range = range
+        self.value = min(max(wrappedValue, range.lowerBound), range.upperBound)
+    }
+}
+
+/// Something that can be priced.
+public protocol Priced {
+    associatedtype Amount: Numeric
+    var price: Amount { get }
+    func discounted(by percent: Double) -> Amount
+}
+
+public enum Status: String, Codable {
+    case new, paid
+    case shipped(carrier: String,
+                 tracking: String)
+    indirect case returned(Status)
+
+    var isOpen: Bool {
+        switch self {
+        case .new, .paid:
+            return true
+        default:
+            return false
+        }
+    }
+}
+
+public struct Order: Identifiable {
+    public let id: OrderID
+    public private(set) var lines: [Line]
+    fileprivate var note: String?
+
+    public struct Line {
+        let sku: String
+        var quantity: Int
+    }
+
+    public subscript(index: Int) -> Line {
+        lines[index]
+    }
+}
+
+extension Order: Priced {
+    public var price: Double {
+        lines.reduce(0) { total, line in
+            let each = 9.99
+            return total + each * Double(line.quantity)
+        }
+    }
+
+    public func discounted(by percent: Double) -> Double {
+        price * (1 - percent / 100)
+    }
+
+    static func == (lhs: Order, rhs: Order) -> Bool {
+        lhs.id == rhs.id
+    }
+}
+
+@MainActor
+final class OrderViewModel: ObservableObject {
+    @Published var orders: [Order] = []
+    @Clamped(0...10) var pageSize: Int = 5
+    @AppStorage("lastOrder")
+    private var lastOrder: String = ""
+
+    private let template = """
+        func notADeclaration() {}
+        """
+
+    convenience init(orders: [Order]) {
+        self.init()
+        self.orders = orders
+    }
+
+    init() {}
+
+    deinit {
+        print("gone")
+    }
+
+    func load() async throws {
+        func sort(_ a: Order, _ b: Order) -> Bool { a.lines.count < b.lines.count }
+        orders.sort(by: sort)
+    }
+}
+
+actor OrderCache {
+    private var cached: [OrderID: Order] = [:]
+
+    func order(for id: OrderID) -> Order? {
+        cached[id]
+    }
+}
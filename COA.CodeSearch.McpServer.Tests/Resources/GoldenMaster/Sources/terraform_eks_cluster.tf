@@ -0,0 +1,84 @@
+terraform {
+  required_version = ">= 1.5"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.region
+}
+
+# Name shared by the cluster, its node group and their tags
+variable "cluster_name" {
+  type    = string
+  default = "orders-prod"
+}
+
+variable "region" {
+  description = "AWS region to deploy into"
+  type        = string
+}
+
+variable "node_count" {
+  type = number
+  validation {
+    condition     = var.node_count > 0
+    error_message = "At least one node is required."
+  }
+}
+
+locals {
+  common_tags = {
+    Cluster = var.cluster_name
+    Owner   = "platform"
+  }
+  node_name = "${var.cluster_name}-nodes"
+}
+
+data "aws_ami" "eks_worker" {
+  most_recent = true
+  owners      = ["amazon"]
+}
+
+module "network" {
+  source = "./modules/network"
+  name   = var.cluster_name
+  tags   = local.common_tags
+}
+
+resource "aws_eks_cluster" "main" {
+  name     = var.cluster_name
+  role_arn = aws_iam_role.cluster.arn
+
+  vpc_config {
+    subnet_ids = module.network.private_subnet_ids
+  }
+
+  # tags = { Name = var.cluster_name }
+  tags = local.common_tags
+}
+
+resource "aws_iam_role" "cluster" {
+  name               = "${var.cluster_name}-role"
+  assume_role_policy = <<-EOT
+    {
+      "Version": "2012-10-17",
+      "Statement": [{ "Effect": "Allow", "Principal": { "Service": "eks.amazonaws.com" } }]
+    }
+  EOT
+}
+
+/*
+resource "aws_eks_node_group" "legacy" {
+  cluster_name = var.cluster_name
+}
+*/
+
+output "cluster_endpoint" {
+  description = "Endpoint of the EKS control plane"
+  value       = aws_eks_cluster.main.endpoint
+}
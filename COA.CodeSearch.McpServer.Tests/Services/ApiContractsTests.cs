@@ -0,0 +1,134 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ApiContractsTests
+{
+    private static string[] Lines(string text) => text.Replace("\r\n", "\n").Split('\n');
+
+    [Test]
+    public void ScanServer_Should_Combine_Controller_And_Group_Prefixes()
+    {
+        // Arrange
+        var controller = Lines(@"[ApiController]
+[Route(""api/[controller]"")]
+public class UsersController : ControllerBase
+{
+    [HttpGet(""{id:int}"")]
+    public async Task<ActionResult<User>> Get(int id) => Ok();
+
+    [HttpPost]
+    public IActionResult Create(User user) => Ok();
+}");
+        var gin = Lines(@"package main
+
+func routes(r *gin.Engine) {
+	v1 := r.Group(""/v1"")
+	items := v1.Group(""/items"")
+	items.GET(""/:id"", getItem)
+	// items.DELETE(""/:id"", deleteItem)
+	http.HandleFunc(""GET /health"", health)
+}");
+
+        // Act
+        var routes = ApiContracts.ScanServer("Api/UsersController.cs", controller)
+            .Concat(ApiContracts.ScanServer("cmd/main.go", gin))
+            .Select(r => $"{r.Method} {r.Path}")
+            .ToList();
+
+        // Assert
+        Assert.That(routes, Is.EqualTo(new[] { "GET /api/Users/{id}", "POST /api/Users", "GET /v1/items/{id}", "GET /health" }));
+    }
+
+    [Test]
+    public void ScanClient_Should_Read_The_Method_From_The_Call_Itself()
+    {
+        // Arrange
+        var lines = Lines(@"const user = await fetch(`/api/users/${id}`);
+await fetch(`/api/users/${id}`, { method: 'PUT', body });
+await axios.get(`${API_BASE}/orders/${id}?expand=items`);
+await fetch('https://api.github.com/repos/x/y');");
+
+        // Act
+        var calls = ApiContracts.ScanClient("web/api.ts", lines);
+
+        // Assert
+        Assert.That(calls.Select(c => $"{c.Method} {c.Path}"),
+            Is.EqualTo(new[] { "GET /api/users/{}", "PUT /api/users/{}", "GET /orders/{}", "GET /repos/x/y" }));
+        Assert.That(calls[2].Relative, Is.True, "a base-address variable leaves the route prefix out");
+        Assert.That(calls[2].QueryParameters, Is.EqualTo(new[] { "expand" }));
+        Assert.That(ApiContracts.IsExternal(calls[3]), Is.True);
+    }
+
+    [Test]
+    public void Compare_Should_Report_Missing_Routes_Method_Mismatches_And_Uncalled_Routes()
+    {
+        // Arrange
+        var routes = new List<ApiEndpoint>
+        {
+            new() { Method = "GET", Path = "/api/users/{id}", FilePath = "UsersController.cs", Line = 5 },
+            new() { Method = "DELETE", Path = "/api/users/{id}", FilePath = "UsersController.cs", Line = 9 },
+            new() { Method = "GET", Path = "/api/orders/{orderId}", FilePath = "Program.cs", Line = 2 }
+        };
+        var calls = new List<ApiCall>
+        {
+            new() { Method = "GET", Path = "/api/users/{}", FilePath = "api.ts", Line = 1 },
+            new() { Method = "PUT", Path = "/api/users/{}", FilePath = "api.ts", Line = 2 },
+            new() { Method = "GET", Path = "/api/profile", FilePath = "api.ts", Line = 3 },
+            new() { Method = "GET", Path = "/orders/{}", Relative = true, FilePath = "api.ts", Line = 4 }
+        };
+
+        // Act
+        var findings = ApiContracts.Compare(routes, new List<ApiEndpoint>(), calls);
+
+        // Assert
+        Assert.That(findings.Select(f => $"{f.Kind} {f.Method} {f.Path}"), Is.EqualTo(new[]
+        {
+            "missing GET /api/profile",
+            "method-mismatch PUT /api/users/{}",
+            "uncalled DELETE /api/users/{id}"
+        }));
+    }
+
+    [Test]
+    public void Compare_Should_Check_Routes_Against_An_OpenApi_Spec()
+    {
+        // Arrange
+        var spec = ApiContracts.ParseOpenApi("openapi.yaml", @"openapi: 3.0.0
+servers:
+  - url: https://example.com/api
+paths:
+  /users/{userId}:
+    parameters:
+      - in: path
+        name: userId
+    get:
+      parameters:
+        - name: fields
+          in: query
+  /reports:
+    get:
+      summary: Retired
+");
+        var routes = new List<ApiEndpoint>
+        {
+            new() { Method = "GET", Path = "/api/users/{id}", FilePath = "UsersController.cs", Line = 5 },
+            new() { Method = "POST", Path = "/api/login", FilePath = "Program.cs", Line = 3 }
+        };
+
+        // Act
+        var findings = ApiContracts.Compare(routes, spec, new List<ApiCall>());
+
+        // Assert
+        Assert.That(spec.Select(o => $"{o.Method} {o.Path}"), Is.EqualTo(new[] { "GET /api/users/{userId}", "GET /api/reports" }));
+        Assert.That(spec[0].QueryParameters, Is.EqualTo(new[] { "fields" }));
+        Assert.That(findings.Select(f => $"{f.Kind} {f.Path}"), Is.EqualTo(new[]
+        {
+            "parameter-drift /api/users/{id}",
+            "unimplemented /api/reports",
+            "undocumented /api/login"
+        }));
+    }
+}
@@ -0,0 +1,83 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ArchitectureLayersTests
+{
+    private static readonly (string Path, string? Content)[] OrderService =
+    {
+        ("src/Domain/Order.cs", "namespace Acme.Domain;\n\nusing Acme.Infrastructure;\n\npublic class Order\n{\n    public void Save() => new OrderStore().Write(this);\n}\n"),
+        ("src/Domain/Customer.cs", "namespace Acme.Domain;\n\npublic class Customer\n{\n}\n"),
+        ("src/Infrastructure/OrderStore.cs", "namespace Acme.Infrastructure;\n\nusing Acme.Domain;\nusing Acme.Persistence;\n\npublic class OrderStore\n{\n    public void Write(Order order) => Db.Run(order);\n}\n"),
+        ("src/Persistence/Db.cs", "namespace Acme.Persistence;\n\nusing System.Data.SqlClient;\n\npublic static class Db\n{\n    public static void Run(object o) => new SqlConnection(\"\").Open();\n}\n"),
+        ("src/Api/OrdersController.cs", "namespace Acme.Api;\n\nusing Acme.Domain;\n\npublic class OrdersController\n{\n    // SqlConnection is only mentioned here\n    const string Query = \"SELECT * FROM Orders\";\n}\n")
+    };
+
+    private static ArchitectureSettings Settings() => new()
+    {
+        Layers =
+        {
+            new ArchitectureLayer { Name = "domain", Paths = { "src/Domain/" } },
+            new ArchitectureLayer { Name = "infrastructure", Namespaces = { "Acme.Infrastructure" } },
+            new ArchitectureLayer { Name = "persistence", Paths = { "src/Persistence/**" } },
+            new ArchitectureLayer { Name = "api", Paths = { "*Controller.cs" } }
+        },
+        Rules =
+        {
+            new ArchitectureRule { Name = "pure domain", Layer = "domain", MustNotDependOn = { "infrastructure", "persistence" } },
+            new ArchitectureRule { Name = "sql in persistence", Layer = "*", Except = { "persistence" }, MustNotReference = { "System.Data.SqlClient", "SqlConnection", "/\\bSELECT\\b.+\\bFROM\\b/" } }
+        }
+    };
+
+    [Test]
+    public void AssignLayers_Should_Match_Globs_Then_Declared_Namespaces()
+    {
+        // Act
+        var layers = ArchitectureLayers.AssignLayers(Settings(), OrderService);
+
+        // Assert
+        Assert.That(layers["src/Domain/Order.cs"], Is.EqualTo("domain"));
+        Assert.That(layers["src/Infrastructure/OrderStore.cs"], Is.EqualTo("infrastructure"), "No glob covers it; its namespace does");
+        Assert.That(layers["src/Persistence/Db.cs"], Is.EqualTo("persistence"));
+        Assert.That(layers["src/Api/OrdersController.cs"], Is.EqualTo("api"));
+    }
+
+    [Test]
+    public void Check_Should_Report_Forbidden_Dependencies_And_References_With_Paths()
+    {
+        // Arrange
+        var settings = Settings();
+        var layers = ArchitectureLayers.AssignLayers(settings, OrderService);
+
+        // Act
+        var violations = ArchitectureLayers.Check(settings, OrderService, layers, ImportGraph.Build(OrderService));
+
+        // Assert
+        Assert.That(violations.Select(v => $"{v.Rule} {v.FilePath}:{v.Line} {v.Reference}"), Is.EqualTo(new[]
+        {
+            "sql in persistence src/Api/OrdersController.cs:8 SELECT * FROM",
+            "pure domain src/Domain/Order.cs:3 Acme.Infrastructure"
+        }));
+        var dependency = violations.Single(v => v.Rule == "pure domain");
+        Assert.That(dependency.ToLayer, Is.EqualTo("infrastructure"));
+        Assert.That(dependency.Path, Is.EqualTo(new[] { "src/Domain/Order.cs:3", "src/Infrastructure/OrderStore.cs" }));
+    }
+
+    [Test]
+    public void Validate_Should_Reject_Rules_Naming_Undeclared_Layers()
+    {
+        // Arrange
+        var settings = Settings();
+        settings.Rules.Add(new ArchitectureRule { Layer = "domian", MayOnlyDependOn = new List<string>() });
+
+        // Act
+        var problems = ArchitectureLayers.Validate(settings);
+
+        // Assert
+        Assert.That(problems, Has.Count.EqualTo(1));
+        Assert.That(problems[0], Does.Contain("undeclared layer 'domian'"));
+        Assert.That(ArchitectureLayers.Validate(Settings()), Is.Empty);
+    }
+}
@@ -0,0 +1,75 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Assets;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class AssetInventoryServiceTests
+{
+    private static List<AssetRecord> CreateAssets() => new()
+    {
+        new() { Path = "public/img/logo.png", Kind = "image", SizeBytes = 2048, Hash = "aa" },
+        new() { Path = "docs/images/logo.png", Kind = "image", SizeBytes = 2048, Hash = "aa" },
+        new() { Path = "src/assets/fonts/Inter.woff2", Kind = "font", SizeBytes = 9000, Hash = "bb" },
+        new() { Path = "src/assets/icons/close.svg", Kind = "image", SizeBytes = 300, Hash = "cc" },
+        new() { Path = "src/assets/icons/open.svg", Kind = "image", SizeBytes = 300, Hash = "cc" },
+        new() { Path = "legacy/banner.gif", Kind = "image", SizeBytes = 5000, Hash = "dd" }
+    };
+
+    [Test]
+    public void Classify_Should_Use_Extension_Then_Fixture_Directories()
+    {
+        // Act & Assert
+        Assert.That(AssetInventoryService.Classify("public/img/logo.PNG"), Is.EqualTo("image"));
+        Assert.That(AssetInventoryService.Classify("assets/Inter.woff2"), Is.EqualTo("font"));
+        Assert.That(AssetInventoryService.Classify("pkg/parser/testdata/input.json"), Is.EqualTo("fixture"));
+        Assert.That(AssetInventoryService.Classify("tests/fixtures/orders.csv"), Is.EqualTo("fixture"));
+        Assert.That(AssetInventoryService.Classify("src/fixtures.ts"), Is.Null, "A file named like the directory is code");
+        Assert.That(AssetInventoryService.Classify("src/app.ts"), Is.Null);
+    }
+
+    [Test]
+    public void ResolveReferences_Should_Resolve_Written_Paths_And_Fall_Back_To_File_Name()
+    {
+        // Arrange
+        var assets = CreateAssets();
+        var files = new List<(string Path, string Content)>
+        {
+            ("src/components/Header.tsx", "import logo from '/img/logo.png';\nconst icon = `/assets/icons/${name}.svg`;"),
+            ("src/styles/fonts.css", "@font-face { src: url('../assets/fonts/Inter.woff2'); }"),
+            ("README.md", "![Logo](logo.png)")
+        };
+
+        // Act
+        AssetInventoryService.ResolveReferences(assets, files);
+
+        // Assert - /img/logo.png resolves under public/ only; the bare name in the README matches both copies
+        var byPath = assets.ToDictionary(a => a.Path);
+        Assert.That(byPath["public/img/logo.png"].References.Select(r => $"{r.FilePath}:{r.Line} {r.Exact}"),
+            Is.EqualTo(new[] { "src/components/Header.tsx:1 True", "README.md:1 False" }));
+        Assert.That(byPath["docs/images/logo.png"].References.Select(r => r.FilePath), Is.EqualTo(new[] { "README.md" }));
+        Assert.That(byPath["src/assets/fonts/Inter.woff2"].References.Single().Exact, Is.True);
+        Assert.That(byPath["src/assets/icons/close.svg"].References, Is.Empty);
+        Assert.That(byPath["src/assets/icons/close.svg"].DirectoryReferenced, Is.True, "Loaded by a built path");
+        Assert.That(byPath["legacy/banner.gif"].References, Is.Empty);
+        Assert.That(byPath["legacy/banner.gif"].DirectoryReferenced, Is.False);
+    }
+
+    [Test]
+    public void FindDuplicates_Should_Group_By_Hash_And_Keep_The_Most_Referenced_Copy()
+    {
+        // Arrange
+        var assets = CreateAssets();
+        assets[1].References.Add(new AssetReference { FilePath = "README.md", Line = 1 });
+
+        // Act
+        var groups = AssetInventoryService.FindDuplicates(assets);
+
+        // Assert
+        Assert.That(groups.Select(g => $"{g.Hash} {g.WastedBytes} {string.Join(",", g.Copies.Select(c => c.Path))}"), Is.EqualTo(new[]
+        {
+            "aa 2048 docs/images/logo.png,public/img/logo.png",
+            "cc 300 src/assets/icons/close.svg,src/assets/icons/open.svg"
+        }));
+    }
+}
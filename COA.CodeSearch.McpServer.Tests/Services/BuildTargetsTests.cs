@@ -0,0 +1,140 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class BuildTargetsTests
+{
+    [Test]
+    public void Parse_Should_Read_Make_Rules_And_Taskfile_Tasks()
+    {
+        // Arrange
+        var makefile = "GO ?= go\n" +
+                       ".PHONY: all test lint\n" +
+                       "\n" +
+                       "all: bin/api ## Build everything\n" +
+                       "\n" +
+                       "# Run the unit tests\n" +
+                       "test: GOFLAGS = -race\n" +
+                       "test:\n" +
+                       "\t@$(GO) test \\\n" +
+                       "\t\t./...\n" +
+                       "\n" +
+                       "bin/api: cmd/api/main.go | lint\n" +
+                       "\t$(GO) build -o $@ ./cmd/api\n" +
+                       "\n" +
+                       "lint: ; golangci-lint run\n" +
+                       "%.pb.go: %.proto\n" +
+                       "\tprotoc $<\n";
+        var taskfile = @"version: '3'
+
+vars:
+  OUT: bin/app
+
+tasks:
+  default:
+    cmds:
+      - task: build
+  build:
+    desc: Build the app
+    deps: [generate]
+    sources:
+      - ./**/*.go
+    generates:
+      - bin/app
+    cmds:
+      - go build -o bin/app ./cmd/app
+      - cmd: echo done
+        silent: true
+  generate: go generate ./...
+  test:
+    cmds:
+      - |
+        go test ./...
+        echo ok
+";
+
+        // Act
+        var make = BuildTargets.Parse("services/api/Makefile", makefile);
+        var tasks = BuildTargets.Parse("Taskfile.yml", taskfile);
+
+        // Assert
+        Assert.That(make.Select(t => $"{t.Name}@{t.Line}"), Is.EqualTo(new[] { "all@4", "test@8", "bin/api@12", "lint@15" }));
+        var all = make[0];
+        Assert.That(all.Default, Is.True);
+        Assert.That(all.Description, Is.EqualTo("Build everything"));
+        Assert.That(all.DependsOn, Is.EqualTo(new[] { "bin/api" }));
+        Assert.That(all.Invocation, Is.EqualTo("make -C services/api all"));
+        Assert.That(make[1].Description, Is.EqualTo("Run the unit tests"));
+        Assert.That(make[1].Commands, Is.EqualTo(new[] { "$(GO) test  ./..." }));
+        Assert.That(make[2].Inputs, Is.EqualTo(new[] { "cmd/api/main.go" }));
+        Assert.That(make[2].Outputs, Is.EqualTo(new[] { "bin/api" }));
+        Assert.That(make[2].DependsOn, Is.EqualTo(new[] { "lint" }));
+        Assert.That(make[3].Commands, Is.EqualTo(new[] { "golangci-lint run" }));
+
+        Assert.That(tasks.Select(t => t.Name), Is.EqualTo(new[] { "default", "build", "generate", "test" }));
+        Assert.That(tasks[0].DependsOn, Is.EqualTo(new[] { "build" }));
+        Assert.That(tasks[0].Default, Is.True);
+        var build = tasks[1];
+        Assert.That(build.Description, Is.EqualTo("Build the app"));
+        Assert.That(build.DependsOn, Is.EqualTo(new[] { "generate" }));
+        Assert.That(build.Inputs, Is.EqualTo(new[] { "./**/*.go" }));
+        Assert.That(build.Outputs, Is.EqualTo(new[] { "bin/app" }));
+        Assert.That(build.Commands, Is.EqualTo(new[] { "go build -o bin/app ./cmd/app", "echo done" }));
+        Assert.That(build.Invocation, Is.EqualTo("task build"));
+        Assert.That(tasks[2].Commands, Is.EqualTo(new[] { "go generate ./..." }));
+        Assert.That(tasks[3].Commands, Is.EqualTo(new[] { "go test ./...\necho ok" }));
+    }
+
+    [Test]
+    public void Parse_Should_Read_MSBuild_Targets_And_Npm_Scripts()
+    {
+        // Arrange
+        var project = @"<Project Sdk=""Microsoft.NET.Sdk"">
+  <PropertyGroup>
+    <TargetFramework>net9.0</TargetFramework>
+  </PropertyGroup>
+
+  <Target Name=""BuildClient"" BeforeTargets=""Build"" DependsOnTargets=""RestoreClient;_Prepare"" Inputs=""@(ClientFiles)"" Outputs=""wwwroot/app.js"">
+    <Exec Command=""npm run build"" WorkingDirectory=""client"" />
+    <Copy SourceFiles=""client/dist/app.js"" DestinationFolder=""wwwroot"" Condition=""'$(Configuration)' == 'Release'"" />
+  </Target>
+</Project>";
+        var packageJson = @"{
+  ""name"": ""web"",
+  ""scripts"": {
+    ""prebuild"": ""npm run clean"",
+    ""build"": ""tsc -p tsconfig.build.json && node scripts/bundle.mjs"",
+    ""clean"": ""rimraf dist"",
+    ""test"": ""run-s lint test:*"",
+    ""lint"": ""eslint ."",
+    ""test:unit"": ""vitest run""
+  }
+}";
+
+        // Act
+        var targets = BuildTargets.Parse("src/Web/Web.csproj", project);
+        var scripts = BuildTargets.Parse("web/package.json", packageJson, "pnpm");
+
+        // Assert
+        var client = targets.Single();
+        Assert.That(client.Name, Is.EqualTo("BuildClient"));
+        Assert.That(client.Line, Is.EqualTo(6));
+        Assert.That(client.Invocation, Is.EqualTo("dotnet msbuild src/Web/Web.csproj -t:BuildClient"));
+        Assert.That(client.DependsOn, Is.EqualTo(new[] { "RestoreClient", "_Prepare" }));
+        Assert.That(client.Triggers, Is.EqualTo(new[] { "before Build" }));
+        Assert.That(client.Commands, Is.EqualTo(new[] { "npm run build", "Copy SourceFiles=\"client/dist/app.js\" DestinationFolder=\"wwwroot\"" }));
+        Assert.That(client.Inputs, Is.EqualTo(new[] { "@(ClientFiles)", "client/dist/app.js" }));
+        Assert.That(client.Outputs, Is.EqualTo(new[] { "wwwroot/app.js", "wwwroot" }));
+
+        Assert.That(scripts.Select(s => $"{s.Name}@{s.Line}"), Is.EqualTo(new[] { "prebuild@4", "build@5", "clean@6", "test@7", "lint@8", "test:unit@9" }));
+        Assert.That(scripts[0].Triggers, Is.EqualTo(new[] { "before build" }));
+        Assert.That(scripts[0].DependsOn, Is.EqualTo(new[] { "clean" }));
+        Assert.That(scripts[1].Invocation, Is.EqualTo("pnpm -C web run build"));
+        Assert.That(scripts[1].Inputs, Is.EqualTo(new[] { "tsconfig.build.json", "scripts/bundle.mjs" }));
+        Assert.That(scripts[3].DependsOn, Is.EqualTo(new[] { "lint", "test:unit" }));
+        Assert.That(BuildTargets.IsBuildFile("deploy/Taskfile.dist.yaml"), Is.True);
+        Assert.That(BuildTargets.IsBuildFile("docs/tasks.yml"), Is.False);
+    }
+}
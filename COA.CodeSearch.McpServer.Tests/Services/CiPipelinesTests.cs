@@ -0,0 +1,133 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class CiPipelinesTests
+{
+    [Test]
+    public void Parse_Should_Resolve_GitHub_Step_Paths_Against_Working_Directories()
+    {
+        // Arrange
+        var workflow = @"name: CI
+on: [push]
+env:
+  API_URL: https://example.com
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    defaults:
+      run:
+        working-directory: src
+    steps:
+      - uses: actions/checkout@v4
+      - uses: ./.github/actions/setup
+      - name: Build
+        run: |
+          ./build.sh --config $CONFIGURATION
+          dotnet test App.Tests/App.Tests.csproj -o out/results
+          cd tools && python gen.py ${{ secrets.TOKEN }}
+          echo ""VERSION=1.2"" >> $GITHUB_ENV
+      - name: Lint
+        run: bash ../scripts/lint.sh
+        working-directory: web
+  release:
+    uses: ./.github/workflows/release.yml
+";
+
+        // Act
+        var pipeline = CiPipelines.Parse(".github/workflows/ci.yml", workflow)!;
+
+        // Assert
+        Assert.That(pipeline.System, Is.EqualTo("github"));
+        Assert.That(pipeline.Name, Is.EqualTo("CI"));
+        Assert.That(pipeline.Steps.Select(s => s.Name), Is.EqualTo(new[] { "actions/checkout@v4", "./.github/actions/setup", "Build", "Lint" }));
+        Assert.That(pipeline.References.Select(r => $"{r.Kind}:{r.Path}@{r.Line}"), Is.EqualTo(new[]
+        {
+            "action:.github/actions/setup@13",
+            "script:src/build.sh@16",
+            "project:src/App.Tests/App.Tests.csproj@17",
+            "directory:src/tools@18",
+            "script:src/tools/gen.py@18",
+            "directory:web@22",
+            "script:scripts/lint.sh@21",
+            "workflow:.github/workflows/release.yml@24"
+        }));
+        Assert.That(pipeline.Variables.Select(v => $"{v.Name}:{v.Scope}"), Is.EqualTo(new[] { "API_URL:pipeline", "VERSION:step Build" }));
+        Assert.That(pipeline.EnvUses.Where(u => u.Kind == "secret").Select(u => u.Value), Is.EqualTo(new[] { "TOKEN" }));
+    }
+
+    [Test]
+    public void Parse_Should_Read_Azure_Stages_Templates_And_Task_Inputs()
+    {
+        // Arrange
+        var pipeline = @"variables:
+  - group: shared-secrets
+  - name: buildConfiguration
+    value: Release
+
+stages:
+  - stage: Build
+    jobs:
+      - job: Compile
+        steps:
+          - template: steps/restore.yml
+          - task: DotNetCoreCLI@2
+            displayName: Test
+            inputs:
+              command: test
+              projects: '**/*Tests.csproj'
+              arguments: '--configuration $(buildConfiguration)'
+          - task: PowerShell@2
+            inputs:
+              filePath: scripts/Publish.ps1
+          - pwsh: |
+              $version = ""1.0""
+              Write-Host ""##vso[task.setvariable variable=packageVersion]$version""
+              Write-Host $env:SYSTEM_ACCESSTOKEN
+  - template: /ci/deploy-stage.yml
+";
+
+        // Act
+        var azure = CiPipelines.Parse("build/azure-pipelines.yml", pipeline)!;
+
+        // Assert
+        Assert.That(azure.System, Is.EqualTo("azure"));
+        Assert.That(azure.VariableGroups, Is.EqualTo(new[] { "shared-secrets" }));
+        Assert.That(azure.Steps.Select(s => $"{s.Job}|{s.Name}"), Is.EqualTo(new[] { "Build.Compile|Test", "Build.Compile|PowerShell@2", "Build.Compile|$version = \"1.0\"" }));
+        Assert.That(azure.References.Select(r => $"{r.Kind}:{r.Path}"), Is.EquivalentTo(new[]
+        {
+            "template:ci/deploy-stage.yml", "template:build/steps/restore.yml", "project:**/*Tests.csproj", "script:scripts/Publish.ps1"
+        }));
+        Assert.That(azure.Variables.Select(v => v.Name), Is.EqualTo(new[] { "buildConfiguration", "packageVersion" }));
+        Assert.That(azure.EnvUses.Select(u => $"{u.Kind}:{u.Value}"), Is.EquivalentTo(new[] { "var:buildConfiguration", "env:SYSTEM_ACCESSTOKEN" }));
+    }
+
+    [Test]
+    public void LinkEnvironment_Should_Join_Pipeline_Variables_With_Code_Reads()
+    {
+        // Arrange
+        var workflow = @"jobs:
+  test:
+    runs-on: ubuntu-latest
+    env:
+      DATABASE_URL: postgres://localhost/test
+      LEGACY_FLAG: 'true'
+    steps:
+      - run: go test ./...
+";
+        var pipeline = CiPipelines.Parse(".github/workflows/test.yml", workflow)!;
+        var code = new[] { "func open() {", "\turl := os.Getenv(\"DATABASE_URL\")", "}" };
+        var reads = CiPipelines.ReadEnvironment("internal/db/db.go", code)
+            .GroupBy(r => r.Name)
+            .ToDictionary(g => g.Key, g => g.Select(r => $"internal/db/db.go:{r.Line}").ToList());
+
+        // Act
+        var variables = CiPipelines.LinkEnvironment(new[] { pipeline }, reads);
+
+        // Assert
+        Assert.That(variables.Select(v => $"{v.Name}:{v.Status}"), Is.EqualTo(new[] { "LEGACY_FLAG:unread", "DATABASE_URL:read" }));
+        Assert.That(variables[1].CodeReads, Is.EqualTo(new[] { "internal/db/db.go:2" }));
+    }
+}
@@ -0,0 +1,81 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Git;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class CodeOwnersTests
+{
+    private const string Content =
+        "# Default owners\n" +
+        "*                 @acme/core\n" +
+        "*.md              @acme/docs\n" +
+        "/src/Billing/     @alice @bob # payments team\n" +
+        "docs/             @acme/docs\n" +
+        "src/**/Tests/     @qa\n" +
+        "/src/Billing/Generated.cs\n";
+
+    [TestCase("README.md", "@acme/docs")]
+    [TestCase("src/Api/Program.cs", "@acme/core")]
+    [TestCase("src/Billing/Invoice.cs", "@alice")]
+    [TestCase("guides/docs/setup.txt", "@acme/docs")]
+    [TestCase("src/Api/Orders/Tests/OrderTests.cs", "@qa")]
+    public void GetOwners_Should_Use_Last_Matching_Rule(string path, string expectedFirstOwner)
+    {
+        // Arrange
+        var owners = new CodeOwners(Content);
+
+        // Act
+        var result = owners.GetOwners(path);
+
+        // Assert
+        Assert.That(result, Is.Not.Empty);
+        Assert.That(result[0], Is.EqualTo(expectedFirstOwner));
+    }
+
+    [Test]
+    public void GetOwners_Should_Return_Empty_For_Rule_Without_Owners()
+    {
+        // Arrange
+        var owners = new CodeOwners(Content);
+
+        // Act & Assert
+        Assert.That(owners.GetOwners("src/Billing/Generated.cs"), Is.Empty);
+        Assert.That(owners.RuleCount, Is.EqualTo(6));
+    }
+
+    [Test]
+    public void GetOwners_Should_Not_Match_Anchored_Pattern_Elsewhere()
+    {
+        // Arrange
+        var owners = new CodeOwners("/build/ @ops\n");
+
+        // Act & Assert
+        Assert.That(owners.GetOwners("build/ci.yml"), Is.EqualTo(new[] { "@ops" }));
+        Assert.That(owners.GetOwners("src/build/ci.yml"), Is.Empty);
+    }
+
+    [Test]
+    public void ParseLinePorcelain_Should_Read_Author_Per_Line()
+    {
+        // Arrange
+        var porcelain =
+            "0123456789012345678901234567890123456789 10 12 1\n" +
+            "author Jane Doe\n" +
+            "author-mail <jane@example.com>\n" +
+            "author-time 1700000000\n" +
+            "summary Fix totals\n" +
+            "\tvar total = 0;\n";
+
+        // Act
+        var lines = GitService.ParseLinePorcelain(porcelain);
+
+        // Assert
+        Assert.That(lines, Has.Count.EqualTo(1));
+        Assert.That(lines[0].Line, Is.EqualTo(12));
+        Assert.That(lines[0].AuthorEmail, Is.EqualTo("jane@example.com"));
+        Assert.That(lines[0].AuthorName, Is.EqualTo("Jane Doe"));
+        Assert.That(lines[0].Commit, Is.EqualTo("0123456789012345678901234567890123456789"));
+        Assert.That(lines[0].Summary, Is.EqualTo("Fix totals"));
+    }
+}
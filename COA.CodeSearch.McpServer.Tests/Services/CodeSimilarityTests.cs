@@ -0,0 +1,98 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class CodeSimilarityTests
+{
+    private const string CSharpLookup = """
+        public async Task<User> GetUserById(int userId)
+        {
+            // Look the user up in the cache first
+            var user = await _cache.GetAsync(userId);
+            return user ?? await _repository.LoadUser(userId, "users");
+        }
+        """;
+
+    private const string PythonLookup = """
+        def find_user(user_id):
+            user = self.cache.get_async(user_id)
+            return user or self.repository.load_user(user_id)
+        """;
+
+    private const string InvoiceTotal = """
+        decimal Total(Invoice invoice)
+        {
+            return invoice.Lines.Sum(line => line.Price * line.Quantity) - invoice.Discount;
+        }
+        """;
+
+    [Test]
+    public void Vectorize_Should_Split_Identifiers_And_Drop_Noise_Comments_And_Strings()
+    {
+        // Act
+        var vector = CodeSimilarity.Vectorize(CSharpLookup, new[] { "GetUserById" });
+
+        // Assert - the masked name does not count, so a renamed copy still matches
+        Assert.That(vector["user"], Is.EqualTo(7));
+        Assert.That(vector["id"], Is.EqualTo(3));
+        Assert.That(vector["cache"], Is.EqualTo(1));
+        Assert.That(vector.ContainsKey("by"), Is.False);
+        Assert.That(vector.ContainsKey("users"), Is.False, "String literals are dropped");
+        Assert.That(vector.ContainsKey("first"), Is.False, "Comments are dropped");
+        Assert.That(vector.ContainsKey("await"), Is.False);
+    }
+
+    [Test]
+    public void Cosine_Should_Score_The_Same_Logic_Across_Naming_Styles_Above_Unrelated_Code()
+    {
+        // Arrange
+        var csharp = CodeSimilarity.Vectorize(CSharpLookup, new[] { "GetUserById" });
+        var python = CodeSimilarity.Vectorize(PythonLookup, new[] { "find_user" });
+        var invoice = CodeSimilarity.Vectorize(InvoiceTotal, new[] { "Total" });
+        var idf = CodeSimilarity.InverseDocumentFrequency(new[] { csharp, python, invoice });
+
+        // Act
+        var similar = CodeSimilarity.Cosine(csharp, python, idf);
+        var unrelated = CodeSimilarity.Cosine(csharp, invoice, idf);
+
+        // Assert
+        Assert.That(similar, Is.GreaterThan(0.8));
+        Assert.That(unrelated, Is.EqualTo(0));
+        Assert.That(CodeSimilarity.Cosine(csharp, csharp, idf), Is.EqualTo(1.0).Within(1e-9));
+        Assert.That(CodeSimilarity.Cosine(csharp, new Dictionary<string, int>()), Is.EqualTo(0));
+    }
+
+    [Test]
+    public void InverseDocumentFrequency_Should_Weigh_Rare_Words_Above_Common_Ones()
+    {
+        // Arrange
+        var vectors = new[]
+        {
+            new Dictionary<string, int> { ["user"] = 1, ["retry"] = 1 },
+            new Dictionary<string, int> { ["user"] = 2 },
+            new Dictionary<string, int> { ["user"] = 1 }
+        };
+
+        // Act
+        var idf = CodeSimilarity.InverseDocumentFrequency(vectors);
+
+        // Assert
+        Assert.That(idf["user"], Is.EqualTo(1.0).Within(1e-9));
+        Assert.That(idf["retry"], Is.EqualTo(Math.Log(2) + 1).Within(1e-9));
+    }
+
+    [Test]
+    public void SharedTerms_And_DistinctiveTerms_Should_Rank_The_Words_That_Say_Most()
+    {
+        // Arrange
+        var a = new Dictionary<string, int> { ["user"] = 3, ["repository"] = 1, ["id"] = 2, ["cache"] = 1 };
+        var b = new Dictionary<string, int> { ["user"] = 1, ["repository"] = 2, ["id"] = 2 };
+        var idf = new Dictionary<string, double> { ["user"] = 1.0, ["repository"] = 3.0, ["id"] = 1.0 };
+
+        // Act & Assert
+        Assert.That(CodeSimilarity.SharedTerms(a, b, idf), Is.EqualTo(new[] { "repository", "id", "user" }));
+        Assert.That(CodeSimilarity.DistinctiveTerms(a, max: 2), Is.EqualTo(new[] { "repository", "cache" }));
+    }
+}
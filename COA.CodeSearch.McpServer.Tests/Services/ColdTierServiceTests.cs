@@ -0,0 +1,87 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.ColdStorage;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ColdTierServiceTests
+{
+    private string _workspace = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "cold_tier_test_" + Guid.NewGuid().ToString("N"));
+        Directory.CreateDirectory(_workspace);
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(_workspace)).Returns(Path.Combine(_workspace, ".index"));
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        if (Directory.Exists(_workspace))
+            Directory.Delete(_workspace, true);
+    }
+
+    private ColdTierService CreateService(params string[] paths)
+    {
+        var settings = paths.Select((p, i) => new KeyValuePair<string, string?>($"CodeSearch:ColdStorage:Paths:{i}", p));
+        var configuration = new ConfigurationBuilder().AddInMemoryCollection(settings).Build();
+        return new ColdTierService(_pathResolution.Object, configuration, NullLogger<ColdTierService>.Instance);
+    }
+
+    private string InWorkspace(string relativePath) => Path.Combine(_workspace, relativePath.Replace('/', Path.DirectorySeparatorChar));
+
+    [Test]
+    public void IsCold_Should_Match_Directory_Prefixes_And_File_Name_Globs()
+    {
+        // Arrange
+        var coldTier = CreateService("archives/", "legacy/**", "*.generated.sql");
+
+        // Act & Assert
+        Assert.That(coldTier.IsEnabled, Is.True);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("archives/2019/Invoice.cs")), Is.True);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("legacy/Billing.cs")), Is.True);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("db/schema.generated.sql")), Is.True);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("src/archives.cs")), Is.False);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("src/Billing.cs")), Is.False);
+    }
+
+    [Test]
+    public void Materialize_Should_Promote_Cold_Files_And_Survive_Restart()
+    {
+        // Arrange
+        var coldTier = CreateService("legacy/");
+        var billing = InWorkspace("legacy/Billing.cs");
+
+        // Act
+        var added = coldTier.Materialize(_workspace, new[] { billing, InWorkspace("src/Cart.cs") });
+        var again = coldTier.Materialize(_workspace, new[] { billing });
+        var reloaded = CreateService("legacy/");
+
+        // Assert
+        Assert.That(added, Is.EqualTo(new[] { billing }));
+        Assert.That(again, Is.Empty);
+        Assert.That(reloaded.IsCold(_workspace, billing), Is.False);
+        Assert.That(reloaded.IsInColdTier(_workspace, billing), Is.True);
+        Assert.That(reloaded.IsCold(_workspace, InWorkspace("legacy/Ledger.cs")), Is.True);
+        Assert.That(reloaded.GetMaterialized(_workspace), Is.EqualTo(new[] { "legacy/Billing.cs" }));
+    }
+
+    [Test]
+    public void IsCold_Should_Be_False_Without_Configured_Paths()
+    {
+        // Arrange
+        var coldTier = CreateService();
+
+        // Act & Assert
+        Assert.That(coldTier.IsEnabled, Is.False);
+        Assert.That(coldTier.IsCold(_workspace, InWorkspace("archives/Old.cs")), Is.False);
+    }
+}
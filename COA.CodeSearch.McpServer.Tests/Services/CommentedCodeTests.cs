@@ -0,0 +1,115 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Git;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class CommentedCodeTests
+{
+    private const string Service = """
+        // Copyright (c) Contoso. Licensed under the MIT license.
+        // See LICENSE in the project root.
+        // Not code either: it has words, and
+        // they run on for a few lines.
+        using System;
+
+        namespace Orders;
+
+        public class OrderService
+        {
+            /// <summary>
+            /// Totals the order.
+            /// </summary>
+            public decimal Total(Order order) => order.Lines.Sum(l => l.Price);
+
+            // Old discount logic, replaced by DiscountPolicy:
+            // public decimal ApplyDiscount(Order order)
+            // {
+            //     var rate = LegacyRates.Lookup(order.Customer);
+            //     if (rate > 0.5m)
+            //         rate = 0.5m;
+            //     return Total(order) * (1 - rate);
+            // }
+
+            // TODO: cache totals once the order is frozen.
+            // We tried this before and it made refunds harder.
+
+            // if (order.IsFrozen) {
+            //     cache[order.Id] = total;
+        }
+        """;
+
+    private const string Script = """
+        import os
+
+        # Retries were disabled while the API was flaky.
+        # for attempt in range(3):
+        #     response = client.send(payload)
+        #     if response.ok:
+        #         break
+        #     time.sleep(2 ** attempt)
+
+        # This module reads the settings file and returns a dict,
+        # with defaults for anything missing from the file.
+        def settings():
+            return {}
+        """;
+
+    [Test]
+    public void Find_Should_Report_Code_Blocks_And_Skip_Prose_Docs_And_Licenses()
+    {
+        // Act
+        var blocks = CommentedCode.Find("src/OrderService.cs", Service, 2)
+            .Concat(CommentedCode.Find("tools/sync.py", Script, 2))
+            .ToList();
+
+        // Assert - the explanation above a block is not part of it
+        Assert.That(blocks.Select(b => $"{b.FilePath}:{b.StartLine}-{b.EndLine} {b.CodeLines}"), Is.EqualTo(new[]
+        {
+            "src/OrderService.cs:17-23 7",
+            "src/OrderService.cs:28-29 2",
+            "tools/sync.py:4-8 5"
+        }));
+        Assert.That(blocks[2].Code, Does.StartWith("for attempt in range(3):\n    response = client.send(payload)"));
+    }
+
+    [Test]
+    public void Find_Should_Parse_What_A_Block_Declares_And_Calls()
+    {
+        // Act
+        var blocks = CommentedCode.Find("src/OrderService.cs", Service, 2);
+
+        // Assert
+        Assert.That(blocks[0].Declares, Is.EqualTo(new[] { "ApplyDiscount" }));
+        Assert.That(blocks[0].Calls, Is.EqualTo(new[] { "Lookup", "Total" }));
+        Assert.That(blocks[0].Complete, Is.True);
+        Assert.That(blocks[1].Complete, Is.False, "An unclosed if is a fragment");
+    }
+
+    [Test]
+    public void Assess_Should_Name_The_Disabling_Commit_And_Recommend()
+    {
+        // Arrange - one line was touched up after the block was commented out
+        var blocks = CommentedCode.Find("src/OrderService.cs", Service, 2);
+        var disabled = new DateTime(2025, 3, 1, 0, 0, 0, DateTimeKind.Utc);
+        var blame = Enumerable.Range(17, 7)
+            .Select(line => line == 19
+                ? new BlameLine { Line = line, Commit = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", AuthorName = "Sam", AuthorTime = disabled.AddDays(30), Summary = "Rename rates" }
+                : new BlameLine { Line = line, Commit = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", AuthorName = "Ana", AuthorTime = disabled, Summary = "Switch to DiscountPolicy" })
+            .ToList();
+        var declared = new HashSet<string> { "Total", "OrderService" };
+
+        // Act
+        CommentedCode.Assess(blocks[0], declared, blame, 180, disabled.AddDays(40));
+        CommentedCode.Assess(blocks[1], declared, new List<BlameLine>(), 180, disabled.AddDays(40));
+
+        // Assert
+        Assert.That(blocks[0].DisabledCommit, Is.EqualTo("aaaaaaaaaaaa"));
+        Assert.That($"{blocks[0].DisabledBy} {blocks[0].AgeDays} {blocks[0].DisabledIn}", Is.EqualTo("Ana 40 Switch to DiscountPolicy"));
+        Assert.That(blocks[0].UnresolvedCalls, Is.EqualTo(new[] { "Lookup" }));
+        Assert.That(blocks[0].Recommendation, Is.EqualTo("review"), "Recent, complete and not replaced");
+        Assert.That(blocks[1].Recommendation, Is.EqualTo("delete"));
+        Assert.That(blocks[1].Reason, Does.Contain("fragment"));
+    }
+}
@@ -0,0 +1,97 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ConfigKeyPathsTests
+{
+    [Test]
+    public void Scan_Should_Read_Nested_Json_Keys_With_Lines_And_Flat_AspNet_Keys()
+    {
+        // Arrange
+        var json = string.Join("\n",
+            "{",
+            "  // comments are allowed in appsettings",
+            "  \"Server\": {",
+            "    \"Timeout\": 30,",
+            "    \"Urls\": [\"http://+:80\", \"https://+:443\"]",
+            "  },",
+            "  \"Logging:LogLevel:Default\": \"Warning\",",
+            "}");
+
+        // Act
+        var entries = ConfigKeyPaths.Scan("src/Api/appsettings.json", json);
+
+        // Assert
+        Assert.That(entries.Select(e => e.Path), Is.EqualTo(new[]
+        {
+            "Server", "Server.Timeout", "Server.Urls", "Server.Urls[0]", "Server.Urls[1]", "Logging.LogLevel.Default"
+        }));
+        var timeout = entries.Single(e => e.Path == "Server.Timeout");
+        Assert.That((timeout.Line, timeout.Column, timeout.Value), Is.EqualTo((4, 4, "30")));
+        Assert.That(entries.Single(e => e.Path == "Server.Urls[1]").Value, Is.EqualTo("https://+:443"));
+        Assert.That(entries.Single(e => e.Path == "Logging.LogLevel.Default").Line, Is.EqualTo(7));
+    }
+
+    [Test]
+    public void Scan_Should_Read_Yaml_Mappings_Sequences_And_Skip_Block_Scalars()
+    {
+        // Arrange
+        var yaml = string.Join("\n",
+            "services:",
+            "  web:",
+            "    image: nginx:1.25 # pinned",
+            "    ports:",
+            "      - \"80:80\"",
+            "    healthcheck:",
+            "      test: |",
+            "        curl -f http://localhost/",
+            "        timeout: not-a-key",
+            "      timeout: 5s",
+            "---",
+            "spec:",
+            "  containers:",
+            "  - name: api",
+            "    image: acme/api:2.0",
+            "  - name: sidecar",
+            "    image: envoy:1.29");
+
+        // Act
+        var entries = ConfigKeyPaths.Scan("deploy/docker-compose.yml", yaml);
+
+        // Assert
+        Assert.That(entries.Select(e => e.Path), Is.EqualTo(new[]
+        {
+            "services", "services.web", "services.web.image", "services.web.ports", "services.web.ports[0]",
+            "services.web.healthcheck", "services.web.healthcheck.test", "services.web.healthcheck.timeout",
+            "spec", "spec.containers", "spec.containers[0].name", "spec.containers[0].image",
+            "spec.containers[1].name", "spec.containers[1].image"
+        }));
+        Assert.That(entries.Single(e => e.Path == "services.web.image").Value, Is.EqualTo("nginx:1.25"));
+        Assert.That(entries.Single(e => e.Path == "services.web.healthcheck.timeout").Line, Is.EqualTo(10));
+        Assert.That(entries.Single(e => e.Path == "spec.containers[1].image").Column, Is.EqualTo(4));
+    }
+
+    [Test]
+    public void Match_Should_Match_Path_Suffixes_Wildcards_Anchors_And_Values()
+    {
+        // Arrange
+        var yaml = "server:\n  timeout: 30s\nclient:\n  server:\n    timeout: 5s\nservices:\n  - image: nginx:1.25\n  - image: redis:7\n";
+        var entries = ConfigKeyPaths.Scan("values.yaml", yaml);
+
+        // Act
+        List<string> Find(string query) => ConfigKeyPaths.TryParseQuery(query, out var parsed)
+            ? entries.Where(e => ConfigKeyPaths.Match(e, parsed, caseSensitive: false)).Select(e => e.Path).ToList()
+            : new List<string>();
+
+        // Assert
+        Assert.That(Find("path:server.timeout"), Is.EqualTo(new[] { "server.timeout", "client.server.timeout" }));
+        Assert.That(Find("path:$.server.timeout"), Is.EqualTo(new[] { "server.timeout" }));
+        Assert.That(Find("path:Server.Timeout=5*"), Is.EqualTo(new[] { "client.server.timeout" }));
+        Assert.That(Find("path:services[*].image=redis*"), Is.EqualTo(new[] { "services[1].image" }));
+        Assert.That(Find("path:services.0.image"), Is.EqualTo(new[] { "services[0].image" }));
+        Assert.That(Find("path:$.**.timeout"), Has.Count.EqualTo(2));
+        Assert.That(ConfigKeyPaths.TryParseQuery("path:", out _), Is.False);
+    }
+}
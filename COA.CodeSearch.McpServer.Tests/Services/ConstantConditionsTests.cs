@@ -0,0 +1,218 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ConstantConditionsTests
+{
+    private static readonly Dictionary<string, string> Flags = new()
+    {
+        ["On"] = "true",
+        ["Off"] = "false",
+        ["Features.NewCheckout"] = "off",
+        ["Retries"] = "3",
+        ["Region"] = "eu",
+        ["Missing"] = "null",
+        ["DEBUG"] = "1",
+        ["TRACE"] = "0"
+    };
+
+    private static bool? Evaluate(string condition, bool preprocessor = false) =>
+        ConstantConditions.Evaluate(condition, Flags, new List<string>(), preprocessor);
+
+    [TestCase("On && On", true)]
+    [TestCase("On && Off", false)]
+    [TestCase("Off && On", false)]
+    [TestCase("Off && Off", false)]
+    [TestCase("On || On", true)]
+    [TestCase("On || Off", true)]
+    [TestCase("Off || On", true)]
+    [TestCase("Off || Off", false)]
+    [TestCase("!On", false)]
+    [TestCase("!Off", true)]
+    [TestCase("!!On", true)]
+    [TestCase("On and not Off", true)]
+    [TestCase("Off or Off", false)]
+    public void Evaluate_Should_Follow_The_Truth_Tables(string condition, bool expected)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition), Is.EqualTo(expected));
+    }
+
+    [TestCase("Off && user.IsAdmin", false)]
+    [TestCase("user.IsAdmin && Off", false)]
+    [TestCase("On || user.IsAdmin", true)]
+    [TestCase("user.IsAdmin || On", true)]
+    [TestCase("On && user.IsAdmin", null)]
+    [TestCase("Off || user.IsAdmin", null)]
+    [TestCase("!user.IsAdmin && On", null)]
+    [TestCase("(Off || user.IsAdmin) && On", null)]
+    [TestCase("(Off && user.IsAdmin) || Off", false)]
+    public void Evaluate_Should_Decide_Only_What_Known_Operands_Decide(string condition, bool? expected)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition), Is.EqualTo(expected));
+    }
+
+    [TestCase("Retries == 3", true)]
+    [TestCase("Retries > 5", false)]
+    [TestCase("Retries >= 3 && Retries < 4", true)]
+    [TestCase("Region == \"eu\"", true)]
+    [TestCase("Region !== 'us'", true)]
+    [TestCase("Missing == null", true)]
+    [TestCase("Missing is not None", false)]
+    [TestCase("Retries", true)]
+    [TestCase("Missing", false)]
+    [TestCase("Retries == user.Limit", null)]
+    [TestCase("Region > 1", null)]
+    public void Evaluate_Should_Compare_Numbers_Strings_And_Nulls(string condition, bool? expected)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition), Is.EqualTo(expected));
+    }
+
+    [TestCase("this.Features.NewCheckout", false)]
+    [TestCase("settings?.Features.NewCheckout", false)]
+    [TestCase("NewCheckout", null)]
+    public void Evaluate_Should_Match_Constants_On_A_Member_Boundary(string condition, bool? expected)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition), Is.EqualTo(expected));
+    }
+
+    [TestCase("true")]
+    [TestCase("user.IsAdmin")]
+    [TestCase("Retries + 1 > 3")]
+    [TestCase("x = On")]
+    [TestCase("On && (Off")]
+    public void Evaluate_Should_Leave_Conditions_Without_Known_Constants_Or_Unmodelled_Syntax(string condition)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition), Is.Null);
+    }
+
+    [Test]
+    public void Evaluate_Should_Report_The_Constants_It_Used()
+    {
+        // Arrange
+        var used = new List<string> { "On" };
+
+        // Act
+        var value = ConstantConditions.Evaluate("On && this.Features.NewCheckout || Off", Flags, used);
+
+        // Assert
+        Assert.That(value, Is.False);
+        Assert.That(used, Is.EqualTo(new[] { "On", "Features.NewCheckout", "Off" }));
+    }
+
+    [TestCase("defined(DEBUG)", true, true)]
+    [TestCase("defined DEBUG && !defined(TRACE)", true, true)]
+    [TestCase("defined(RELEASE)", true, null)]
+    [TestCase("DEBUG && TRACE", true, false)]
+    [TestCase("defined(DEBUG)", false, null)]
+    public void Evaluate_Should_Treat_Configured_Symbols_As_Defined_Unless_False(string condition, bool preprocessor, bool? expected)
+    {
+        // Act & Assert
+        Assert.That(Evaluate(condition, preprocessor), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void TryParseConstants_Should_Reject_Entries_Without_A_Name()
+    {
+        // Act & Assert
+        Assert.That(ConstantConditions.TryParseConstants(new[] { "A=1", " B = on " }, out var constants, out _), Is.True);
+        Assert.That(constants, Is.EqualTo(new Dictionary<string, string> { ["A"] = "1", ["B"] = "on" }));
+        Assert.That(ConstantConditions.TryParseConstants(new[] { "A=1", "=2" }, out _, out var invalid), Is.False);
+        Assert.That(invalid, Is.EqualTo("=2"));
+    }
+
+    [Test]
+    public void Scan_Should_Mark_The_Dead_Branch_Of_A_Brace_Conditional()
+    {
+        // Arrange
+        var lines = new[]
+        {
+            "void Checkout(User user)",
+            "{",
+            "    if (Features.NewCheckout && user.IsAdmin)",
+            "    {",
+            "        NewFlow();",
+            "    }",
+            "    else",
+            "    {",
+            "        OldFlow();",
+            "    }",
+            "    if (user.IsAdmin || On) Audit();",
+            "    while (Off) { Spin(); }",
+            "    do { Once(); } while (Off);",
+            "}"
+        };
+
+        // Act
+        var branches = ConstantConditions.Scan("Checkout.cs", lines, Flags);
+
+        // Assert
+        Assert.That(branches.Select(b => $"{b.Line} {b.Kind} {b.Value} {b.DeadBranch} {b.DeadStartLine}-{b.DeadEndLine}"), Is.EqualTo(new[]
+        {
+            "3 if False then 4-6",
+            "11 if True  -",
+            "12 while False body 12-12"
+        }));
+        Assert.That(branches[0].Suggestion, Is.EqualTo("Always false: keep only the else branch"));
+        Assert.That(branches[0].Constants, Is.EqualTo(new[] { "Features.NewCheckout" }));
+    }
+
+    [Test]
+    public void Scan_Should_Mark_The_Else_Chain_Of_An_Always_True_Python_Conditional()
+    {
+        // Arrange
+        var lines = new[]
+        {
+            "def checkout(user):",
+            "    if On or user.is_admin:",
+            "        new_flow()",
+            "    elif user.is_beta:",
+            "        beta_flow()",
+            "    else:",
+            "        old_flow()",
+            "    done()"
+        };
+
+        // Act
+        var branch = ConstantConditions.Scan("checkout.py", lines, Flags).Single();
+
+        // Assert
+        Assert.That(branch.Value, Is.True);
+        Assert.That(branch.DeadBranch, Is.EqualTo("else"));
+        Assert.That(branch.DeadStartLine, Is.EqualTo(4));
+        Assert.That(branch.DeadEndLine, Is.EqualTo(7));
+    }
+
+    [Test]
+    public void Scan_Should_Evaluate_Preprocessor_Blocks_At_Their_Own_Nesting_Level()
+    {
+        // Arrange
+        var lines = new[]
+        {
+            "#if TRACE",
+            "#if DEBUG",
+            "Log();",
+            "#endif",
+            "Trace();",
+            "#else",
+            "Quiet();",
+            "#endif"
+        };
+
+        // Act
+        var branches = ConstantConditions.Scan("Log.cs", lines, Flags);
+
+        // Assert
+        Assert.That(branches.Select(b => $"{b.Line} {b.Kind} {b.Value} {b.DeadBranch} {b.DeadStartLine}-{b.DeadEndLine}"), Is.EqualTo(new[]
+        {
+            "1 #if False then 2-5",
+            "2 #if True  -"
+        }));
+    }
+}
@@ -0,0 +1,81 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Constants;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ConstantValuesTests
+{
+    [TestCase("0x10", "16")]
+    [TestCase("0b1010", "10")]
+    [TestCase("1_000_000L", "1000000")]
+    [TestCase("1 << 4", "16")]
+    [TestCase("-(2 + 3) * 4", "-20")]
+    [TestCase("0x0F & 6 | 1", "7")]
+    [TestCase("'archived'", "\"archived\"")]
+    [TestCase("`raw`", "\"raw\"")]
+    [TestCase("True", "true")]
+    [TestCase("FALSE", "false")]
+    [TestCase("1 / 0", null)]
+    [TestCase("Limit + 1", null)]
+    [TestCase("1 || 2", null)]
+    public void Normalize_Should_Give_Different_Spellings_The_Same_Value(string literal, string? expected)
+    {
+        // Act & Assert
+        Assert.That(ConstantValues.Normalize(literal), Is.EqualTo(expected));
+    }
+
+    [TestCase("public const int MaxRetries = 3;", "MaxRetries", ".cs", "3")]
+    [TestCase("    Archived = 1 << 4,", "Archived", ".cs", "1 << 4")]
+    [TestCase("const Status: string = \"archived\";", "Status", ".ts", "\"archived\"")]
+    [TestCase("    ACTIVE(3, \"active\"),", "ACTIVE", ".java", "3")]
+    [TestCase("    ACTIVE(Status.Default),", "ACTIVE", ".java", null)]
+    [TestCase("    Pending,", "Pending", ".cs", null)]
+    [TestCase("if (MaxRetries == 3) Retry();", "MaxRetries", ".cs", null)]
+    public void InitializerOf_Should_Read_The_Value_Of_The_Declared_Name(string line, string name, string extension, string? expected)
+    {
+        // Act & Assert
+        Assert.That(ConstantValues.InitializerOf(line, name, extension), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void EnumSequence_Should_Number_Members_From_The_Previous_Value()
+    {
+        // Act
+        var values = ConstantValues.EnumSequence(new[] { null, null, "10", null, "Other", null });
+
+        // Assert - a member after one with no known value has no known value either
+        Assert.That(values, Is.EqualTo(new[] { "0", "1", "10", "11", null, null }));
+    }
+
+    [Test]
+    public void GoConstBlockValues_Should_Count_Iota_And_Repeat_The_Previous_Expression()
+    {
+        // Arrange
+        var lines = new[]
+        {
+            "package flags",
+            "",
+            "const (",
+            "    Read Mode = 1 << iota",
+            "    Write",
+            "    // Exec is reserved",
+            "    Exec",
+            "    All = Read | Write | Exec",
+            ")",
+            "",
+            "const Other = 5"
+        };
+
+        // Act
+        var values = ConstantValues.GoConstBlockValues(lines, 6);
+
+        // Assert
+        Assert.That(values.Keys, Is.EqualTo(new[] { 3, 4, 6, 7 }));
+        Assert.That(values[3], Is.EqualTo("1"));
+        Assert.That(values[4], Is.EqualTo("2"));
+        Assert.That(values[6], Is.EqualTo("4"));
+        Assert.That(values[7], Is.Null, "Other constants are not resolved");
+        Assert.That(ConstantValues.GoConstBlockValues(lines, 10), Is.Empty);
+    }
+}
@@ -0,0 +1,198 @@
+using NUnit.Framework;
+using System.Net;
+using System.Net.Sockets;
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Dashboard;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Lucene.Net.Util;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class DashboardServiceTests
+{
+    private const string Token = "dashboard-test-token";
+
+    private string _workspace = null!;
+    private string _otherWorkspace = null!;
+    private int _port;
+    private DashboardService? _dashboard;
+    private HttpClient _client = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-dashboard-test", Guid.NewGuid().ToString());
+        _otherWorkspace = Path.Combine(Path.GetTempPath(), "codesearch-dashboard-test", Guid.NewGuid().ToString());
+
+        // Reserve a free port for this test
+        var listener = new TcpListener(IPAddress.Loopback, 0);
+        listener.Start();
+        _port = ((IPEndPoint)listener.LocalEndpoint).Port;
+        listener.Stop();
+
+        _client = new HttpClient { BaseAddress = new Uri($"http://127.0.0.1:{_port}/") };
+    }
+
+    [TearDown]
+    public async Task TearDown()
+    {
+        _client.Dispose();
+        if (_dashboard != null)
+        {
+            await _dashboard.StopAsync(CancellationToken.None);
+            _dashboard.Dispose();
+        }
+    }
+
+    private async Task<DashboardService> StartAsync(string bindAddress = "127.0.0.1", string? token = null)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Dashboard:Enabled"] = "true",
+                ["CodeSearch:Dashboard:BindAddress"] = bindAddress,
+                ["CodeSearch:Dashboard:Port"] = _port.ToString(),
+                ["CodeSearch:Dashboard:AccessToken"] = token
+            })
+            .Build();
+        var pathResolution = new Mock<IPathResolutionService>();
+        pathResolution.Setup(p => p.GetPrimaryWorkspacePath()).Returns(_workspace);
+        var retention = new Mock<IIndexRetentionService>();
+        retention.Setup(r => r.GetWorkspaceIndexes()).Returns(new List<RetainedIndex>
+        {
+            new() { WorkspaceHash = "other", WorkspacePath = _otherWorkspace }
+        });
+
+        _dashboard = new DashboardService(
+            new ServiceCollection().BuildServiceProvider(),
+            pathResolution.Object,
+            new Mock<ILuceneIndexService>().Object,
+            new QueryPreprocessor(NullLogger<QueryPreprocessor>.Instance),
+            new CodeAnalyzer(LuceneVersion.LUCENE_48),
+            new Mock<ISQLiteSymbolService>().Object,
+            retention.Object,
+            configuration,
+            NullLogger<DashboardService>.Instance);
+        await _dashboard.StartAsync(CancellationToken.None);
+
+        // The listener starts in the background
+        for (var i = 0; i < 100 && _dashboard.Url == null && _dashboard.ExecuteTask is { IsCompleted: false }; i++)
+        {
+            await Task.Delay(100);
+        }
+        return _dashboard;
+    }
+
+    /// <summary>
+    /// The session token handed out in the dashboard URL
+    /// </summary>
+    private static string SessionQuery(DashboardService dashboard) => new Uri(dashboard.Url!).Query;
+
+    [Test]
+    public async Task Dashboard_Should_List_The_Primary_And_Indexed_Workspaces()
+    {
+        // Arrange
+        var dashboard = await StartAsync();
+
+        // Act
+        var json = await _client.GetStringAsync("api/workspaces" + SessionQuery(dashboard));
+
+        // Assert
+        Assert.That(dashboard.Url, Does.StartWith($"http://127.0.0.1:{_port}/?token="));
+        using var document = JsonDocument.Parse(json);
+        Assert.That(document.RootElement.GetProperty("primary").GetString(), Is.EqualTo(_workspace));
+        var workspaces = document.RootElement.GetProperty("workspaces").EnumerateArray()
+            .Select(w => $"{w.GetProperty("path").GetString()}:{w.GetProperty("isPrimary").GetBoolean()}")
+            .ToList();
+        Assert.That(workspaces, Is.EqualTo(new[] { $"{_workspace}:True", $"{_otherWorkspace}:False" }));
+    }
+
+    [Test]
+    public async Task Dashboard_Should_Require_The_Token_When_One_Is_Configured()
+    {
+        // Arrange
+        await StartAsync(token: Token);
+
+        // Act
+        var anonymous = await _client.GetAsync("api/workspaces");
+        var wrongToken = await _client.GetAsync("api/workspaces?token=guess");
+        using var request = new HttpRequestMessage(HttpMethod.Get, "api/workspaces");
+        request.Headers.Add("X-CodeSearch-Token", Token);
+        var authorized = await _client.SendAsync(request);
+
+        // Assert
+        Assert.That(anonymous.StatusCode, Is.EqualTo(HttpStatusCode.Unauthorized));
+        Assert.That(wrongToken.StatusCode, Is.EqualTo(HttpStatusCode.Unauthorized));
+        Assert.That(authorized.StatusCode, Is.EqualTo(HttpStatusCode.OK));
+        Assert.That(_dashboard!.Url, Is.EqualTo($"http://127.0.0.1:{_port}/"), "A configured token is not logged");
+    }
+
+    [Test]
+    public async Task Dashboard_Should_Require_The_Session_Token_On_Loopback()
+    {
+        // Arrange
+        var dashboard = await StartAsync();
+
+        // Act
+        var anonymous = await _client.GetAsync("api/workspaces");
+        var page = await _client.GetAsync(SessionQuery(dashboard));
+
+        // Assert
+        Assert.That(anonymous.StatusCode, Is.EqualTo(HttpStatusCode.Unauthorized));
+        Assert.That(page.StatusCode, Is.EqualTo(HttpStatusCode.OK));
+    }
+
+    [Test]
+    public async Task Dashboard_Should_Refuse_Host_Names_Other_Than_Loopback()
+    {
+        // Arrange - a DNS-rebinding page resolves its own name to 127.0.0.1
+        var dashboard = await StartAsync();
+        var url = "api/workspaces" + SessionQuery(dashboard);
+
+        // Act
+        using var rebound = new HttpRequestMessage(HttpMethod.Get, url);
+        rebound.Headers.Host = $"attacker.example:{_port}";
+        var reboundResponse = await _client.SendAsync(rebound);
+        using var localhost = new HttpRequestMessage(HttpMethod.Get, url);
+        localhost.Headers.Host = $"localhost:{_port}";
+        var localhostResponse = await _client.SendAsync(localhost);
+
+        // Assert
+        Assert.That(reboundResponse.StatusCode, Is.EqualTo(HttpStatusCode.BadRequest));
+        Assert.That(localhostResponse.StatusCode, Is.EqualTo(HttpStatusCode.OK));
+    }
+
+    [Test]
+    public async Task Dashboard_Should_Not_Serve_Files_Outside_Known_Workspaces()
+    {
+        // Arrange
+        var token = SessionQuery(await StartAsync()) + "&";
+
+        // Act
+        var escaped = await _client.GetAsync("api/file" + token + "path=" + Uri.EscapeDataString("../../etc/passwd"));
+        var unknownWorkspace = await _client.GetAsync("api/health" + token + "workspace=" + Uri.EscapeDataString(Path.GetTempPath()));
+
+        // Assert
+        Assert.That(escaped.StatusCode, Is.EqualTo(HttpStatusCode.NotFound));
+        Assert.That(unknownWorkspace.StatusCode, Is.EqualTo(HttpStatusCode.NotFound));
+    }
+
+    [Test]
+    public async Task Dashboard_Should_Not_Listen_Beyond_Loopback_Without_A_Token()
+    {
+        // Act
+        var dashboard = await StartAsync(bindAddress: "0.0.0.0");
+
+        // Assert
+        Assert.That(dashboard.Url, Is.Null);
+        Assert.That(dashboard.ExecuteTask!.IsCompleted, Is.True);
+    }
+}
@@ -0,0 +1,102 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class DataFlowScannerTests
+{
+    [Test]
+    public void Mask_Should_Blank_Literals_But_Keep_Interpolation_Holes()
+    {
+        // Arrange
+        var line = "var sql = $\"SELECT * FROM users WHERE id = {id}\"; // uses id";
+
+        // Act
+        var masked = DataFlowScanner.Mask(line, ".cs");
+
+        // Assert
+        Assert.That(masked, Has.Length.EqualTo(line.Length));
+        Assert.That(masked, Does.Not.Contain("SELECT"));
+        Assert.That(masked, Does.Contain("{id}"));
+        Assert.That(masked, Does.Not.Contain("uses"));
+    }
+
+    [Test]
+    public void FindAssignment_Should_Return_All_Targets_Of_Go_Short_Declaration()
+    {
+        // Act
+        var assignment = DataFlowScanner.FindAssignment("\tuser, err := repo.Find(name)");
+
+        // Assert
+        Assert.That(assignment, Is.Not.Null);
+        Assert.That(assignment!.Targets.Select(t => t.Name), Is.EqualTo(new[] { "user", "err" }));
+        Assert.That(assignment.Operator, Is.EqualTo(":="));
+    }
+
+    [TestCase("if (a == b) {")]
+    [TestCase("if x <= limit {")]
+    [TestCase("Find(x => x.Id == id);")]
+    [TestCase("Query(sql, timeout: 30);")]
+    public void FindAssignment_Should_Ignore_Comparisons_Lambdas_And_Named_Arguments(string line)
+    {
+        // Act & Assert
+        Assert.That(DataFlowScanner.FindAssignment(line), Is.Null);
+    }
+
+    [Test]
+    public void FindAssignment_Should_Mark_Member_Targets()
+    {
+        // Act
+        var assignment = DataFlowScanner.FindAssignment("this.name = name;");
+
+        // Assert
+        Assert.That(assignment!.Targets.Single().IsMember, Is.True);
+    }
+
+    [Test]
+    public void EnclosingCall_Should_Find_Innermost_Call_And_Argument_Index()
+    {
+        // Arrange
+        var line = "db.Exec(ctx, fmt.Sprintf(\"x %s\", q), other)";
+
+        // Act
+        var inner = DataFlowScanner.EnclosingCall(line, line.IndexOf("q)", StringComparison.Ordinal));
+        var outer = DataFlowScanner.EnclosingCall(line, line.IndexOf("other", StringComparison.Ordinal));
+
+        // Assert
+        Assert.That(inner, Is.EqualTo(new DataFlowCall("fmt.Sprintf", 1, 24)));
+        Assert.That(outer!.Callee, Is.EqualTo("db.Exec"));
+        Assert.That(outer.ArgumentIndex, Is.EqualTo(2));
+    }
+
+    [Test]
+    public void EnclosingCall_Should_Not_Treat_Control_Flow_As_Call()
+    {
+        // Act & Assert
+        Assert.That(DataFlowScanner.EnclosingCall("if (query != null) {", 4), Is.Null);
+    }
+
+    [TestCase("public async Task<User> Find(string name, [FromQuery] int id = 3, params object[] rest)", "Find", ".cs", "name,id,rest")]
+    [TestCase("func (r *Repo) Find(ctx context.Context, a, b string, opts ...Option) error {", "Find", ".go", "ctx,a,b,opts")]
+    [TestCase("def find(self, name: str, limit=10, *args, **kw):", "find", ".py", "name,limit,args,kw")]
+    [TestCase("async find(id: string, opts?: Map<string, number>): Promise<void> {", "find", ".ts", "id,opts")]
+    public void ParameterNames_Should_Handle_Each_Declaration_Style(string declaration, string name, string extension, string expected)
+    {
+        // Act
+        var names = DataFlowScanner.ParameterNames(declaration, name, extension);
+
+        // Assert
+        Assert.That(string.Join(",", names), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void VariablesIn_Should_Skip_Members_And_Called_Names()
+    {
+        // Arrange
+        var line = "x = a + b.c + d(e)";
+
+        // Act & Assert
+        Assert.That(DataFlowScanner.VariablesIn(line, 4, line.Length), Is.EqualTo(new[] { "a", "b", "e" }));
+    }
+}
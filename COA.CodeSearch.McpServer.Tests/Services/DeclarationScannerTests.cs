@@ -0,0 +1,172 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Tools;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class DeclarationScannerTests
+{
+    private const string CSharpSource =
+        "namespace Billing;\n" +
+        "\n" +
+        "public class InvoiceService\n" +
+        "{\n" +
+        "    public InvoiceService(IClock clock) { }\n" +
+        "    public string Name { get; set; } = \"\";\n" +
+        "    public decimal Total(Invoice invoice)\n" +
+        "    {\n" +
+        "        if (invoice == null) return 0;\n" +
+        "        return invoice.Lines.Sum(l => l.Amount);\n" +
+        "    }\n" +
+        "    private void Log(string message) => Console.WriteLine(message);\n" +
+        "}\n";
+
+    [Test]
+    public void Scan_Should_Find_CSharp_Types_And_Members_With_Containers()
+    {
+        // Act
+        var declarations = DeclarationScanner.Scan(CSharpSource, "InvoiceService.cs");
+
+        // Assert
+        Assert.That(declarations.Select(d => $"{d.Kind}:{d.QualifiedName}"), Is.EqualTo(new[]
+        {
+            "class:InvoiceService",
+            "method:InvoiceService.InvoiceService",
+            "property:InvoiceService.Name",
+            "method:InvoiceService.Total",
+            "method:InvoiceService.Log"
+        }));
+        Assert.That(declarations.Single(d => d.Name == "Total").IsPublic, Is.True);
+        Assert.That(declarations.Single(d => d.Name == "Log").IsPublic, Is.False);
+    }
+
+    [Test]
+    public void Scan_Should_Find_Go_Functions_Methods_And_Types()
+    {
+        // Arrange
+        var source =
+            "package main\n" +
+            "\n" +
+            "type (\n" +
+            "    Server struct {\n" +
+            "        port int\n" +
+            "    }\n" +
+            "    handler func()\n" +
+            ")\n" +
+            "\n" +
+            "func (s *Server) Start() error {\n" +
+            "    return nil\n" +
+            "}\n" +
+            "\n" +
+            "func helper() {}\n";
+
+        // Act
+        var declarations = DeclarationScanner.Scan(source, "server.go");
+
+        // Assert
+        Assert.That(declarations.Select(d => $"{d.Kind}:{d.QualifiedName}:{d.IsPublic}"), Is.EqualTo(new[]
+        {
+            "struct:Server:True",
+            "type:handler:False",
+            "method:Server.Start:True",
+            "function:helper:False"
+        }));
+    }
+
+    [Test]
+    public void Scan_Should_Treat_Underscore_Python_Names_As_Private()
+    {
+        // Arrange
+        var source =
+            "class Cart:\n" +
+            "    def __init__(self):\n" +
+            "        self.items = []\n" +
+            "\n" +
+            "    def _recalculate(self):\n" +
+            "        pass\n" +
+            "\n" +
+            "def checkout(cart):\n" +
+            "    return cart\n";
+
+        // Act
+        var declarations = DeclarationScanner.Scan(source, "cart.py");
+
+        // Assert
+        Assert.That(declarations.Select(d => $"{d.QualifiedName}:{d.IsPublic}"), Is.EqualTo(new[]
+        {
+            "Cart:True", "Cart.__init__:True", "Cart._recalculate:False", "checkout:True"
+        }));
+    }
+
+    [Test]
+    public void Compare_Should_Report_Renamed_Method_When_Body_Is_Unchanged()
+    {
+        // Arrange
+        var renamed = CSharpSource.Replace("Total(Invoice invoice)", "CalculateTotal(Invoice invoice)");
+        var before = DeclarationScanner.Scan(CSharpSource, "InvoiceService.cs");
+        var after = DeclarationScanner.Scan(renamed, "InvoiceService.cs");
+
+        // Act
+        var changes = SymbolDiffer.Compare("InvoiceService.cs", before, "InvoiceService.cs", after);
+
+        // Assert
+        Assert.That(changes, Has.Count.EqualTo(1));
+        Assert.That(changes[0].Change, Is.EqualTo("renamed"));
+        Assert.That(changes[0].OldName, Is.EqualTo("Total"));
+        Assert.That(changes[0].Name, Is.EqualTo("CalculateTotal"));
+        Assert.That(changes[0].Confidence, Is.GreaterThan(0.8));
+        Assert.That(changes[0].IsBreaking, Is.True);
+    }
+
+    [Test]
+    public void Compare_Should_Report_Signature_And_Visibility_Changes()
+    {
+        // Arrange
+        var changed = CSharpSource
+            .Replace("Total(Invoice invoice)", "Total(Invoice invoice, bool includeTax)")
+            .Replace("private void Log", "public void Log");
+        var before = DeclarationScanner.Scan(CSharpSource, "InvoiceService.cs");
+        var after = DeclarationScanner.Scan(changed, "InvoiceService.cs");
+
+        // Act
+        var changes = SymbolDiffer.Compare("InvoiceService.cs", before, "InvoiceService.cs", after);
+
+        // Assert
+        var total = changes.Single(c => c.Name == "Total");
+        Assert.That(total.Change, Is.EqualTo("modified"));
+        Assert.That(total.IsBreaking, Is.True);
+
+        var log = changes.Single(c => c.Name == "Log");
+        Assert.That(log.Change, Is.EqualTo("modified"));
+        Assert.That(log.IsPublic, Is.True);
+        Assert.That(log.IsBreaking, Is.False);
+    }
+
+    [Test]
+    public void Compare_Should_Not_Pair_Unrelated_Symbols_As_Renames()
+    {
+        // Arrange
+        var before = DeclarationScanner.Scan(
+            "public class A\n{\n    public void Save(Order order)\n    {\n        _repository.Add(order);\n        _repository.Commit();\n    }\n}\n", "A.cs");
+        var after = DeclarationScanner.Scan(
+            "public class A\n{\n    public int Count(string text)\n    {\n        return text.Split(' ').Length * 2;\n    }\n}\n", "A.cs");
+
+        // Act
+        var changes = SymbolDiffer.Compare("A.cs", before, "A.cs", after);
+
+        // Assert
+        Assert.That(changes.Select(c => $"{c.Change}:{c.Name}"), Is.EquivalentTo(new[] { "removed:Save", "added:Count" }));
+    }
+
+    [TestCase("src/Billing/Invoice.cs", "source")]
+    [TestCase("tests/InvoiceTests.cs", "test")]
+    [TestCase("docs/setup.md", "docs")]
+    [TestCase("src/App/App.csproj", "build")]
+    [TestCase("src/App/appsettings.json", "config")]
+    public void Categorize_Should_Classify_Changed_Files(string path, string expected)
+    {
+        // Act & Assert
+        Assert.That(DiffSummaryTool.Categorize(path), Is.EqualTo(expected));
+    }
+}
@@ -0,0 +1,112 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class DependencyCodeServiceTests
+{
+    private string _workspace = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-dependency-code-test", Guid.NewGuid().ToString());
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(_workspace)).Returns(Path.Combine(_workspace, ".coa", "index"));
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_workspace))
+                Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private DependencyCodeService CreateService(string? mode = null, params string[] directories)
+    {
+        var values = new Dictionary<string, string?> { ["CodeSearch:DependencyCode:Mode"] = mode };
+        for (var i = 0; i < directories.Length; i++)
+        {
+            values[$"CodeSearch:DependencyCode:Directories:{i}"] = directories[i];
+        }
+        var configuration = new ConfigurationBuilder().AddInMemoryCollection(values).Build();
+        return new DependencyCodeService(_pathResolution.Object, configuration, NullLogger<DependencyCodeService>.Instance);
+    }
+
+    [TestCase("src/vendor/github.com/lib/pq/conn.go", "src/vendor")]
+    [TestCase("ios/Pods/Alamofire/Source/Request.swift", "ios/Pods")]
+    [TestCase("src/orders/vendor.go", null)]
+    [TestCase("src/orders/OrderService.cs", null)]
+    public void GetDependencyDirectory_Should_Match_Default_Directory_Names_Anywhere(string relativePath, string? expected)
+    {
+        // Arrange
+        var service = CreateService();
+
+        // Act & Assert
+        Assert.That(service.GetDependencyDirectory(_workspace, Path.Combine(_workspace, relativePath)), Is.EqualTo(expected));
+        Assert.That(service.GetSettings(_workspace).Mode, Is.EqualTo(DependencyCodeSettings.Include));
+        Assert.That(service.IsExcluded(_workspace, Path.Combine(_workspace, relativePath)), Is.False, "Include indexes dependency code");
+    }
+
+    [Test]
+    public void Exclude_Should_Turn_Directories_Into_Ignore_Patterns()
+    {
+        // Arrange
+        var service = CreateService("Exclude", "src\\legacy\\libs\\", "vendor", "VENDOR", ".");
+
+        // Act
+        var patterns = service.GetIgnorePatterns(_workspace);
+
+        // Assert
+        Assert.That(patterns, Is.EqualTo(new[] { "src/legacy/libs/**", "**/vendor/**" }));
+        Assert.That(service.IsExcluded(_workspace, Path.Combine(_workspace, "src", "legacy", "libs", "zlib.c")), Is.True);
+        Assert.That(service.IsExcluded(_workspace, Path.Combine(_workspace, "src", "legacy", "Program.cs")), Is.False);
+    }
+
+    [Test]
+    public void Constructor_Should_Fall_Back_To_Include_For_An_Unknown_Mode()
+    {
+        // Act
+        var service = CreateService("hide");
+
+        // Assert
+        Assert.That(service.GetSettings(_workspace).Mode, Is.EqualTo(DependencyCodeSettings.Include));
+        Assert.That(service.GetIgnorePatterns(_workspace), Is.Empty);
+    }
+
+    [Test]
+    public void SetSettings_Should_Persist_Per_Workspace_Until_Cleared()
+    {
+        // Arrange
+        var service = CreateService();
+
+        // Act
+        service.SetSettings(_workspace, new DependencyCodeSettings { Mode = " Downrank ", Directories = new List<string> { "third_party/" } });
+        var reloaded = CreateService();
+
+        // Assert - a new instance reads what was saved beside the index
+        Assert.That(File.Exists(Path.Combine(_workspace, ".coa", "index", "dependency-code.json")), Is.True);
+        Assert.That(reloaded.HasWorkspaceSettings(_workspace), Is.True);
+        Assert.That(reloaded.GetSettings(_workspace).ToString(), Is.EqualTo("downrank: third_party"));
+
+        // Act - back to the default
+        reloaded.SetSettings(_workspace, null);
+
+        // Assert
+        Assert.That(reloaded.HasWorkspaceSettings(_workspace), Is.False);
+        Assert.That(CreateService().HasWorkspaceSettings(_workspace), Is.False);
+    }
+}
@@ -0,0 +1,136 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class DocDriftTests
+{
+    private const string WorkspacePath = "/repo";
+
+    private const string Handler = """
+        namespace Orders;
+
+        /// <summary>
+        /// Helper routes order events to the <see cref="OrderClient"/>.
+        /// </summary>
+        public class HelperHandler
+        {
+            /// <summary>
+            /// Sends the order, retrying on failure.
+            /// </summary>
+            /// <param name="request">The order to send</param>
+            /// <param name="maxRetries">How often to retry, defaults to 3</param>
+            public void Send(Order order, int maxRetries = 5)
+            {
+                _client.Post(order, maxRetries);
+            }
+
+            /// <summary>
+            /// Status sent while the order waits, <c>"pending"</c>, see <see cref="Send"/>.
+            /// </summary>
+            public string Status() => "queued";
+
+            private readonly OrderApi _client = new();
+        }
+        """;
+
+    private const string Fetch = """"
+        def fetch(url, timeout_ms=500):
+            """Fetches a page.
+
+            Args:
+                url (str): The page to fetch.
+                timeout: Seconds to wait. Defaults to 500.
+            """
+            return http.get(url, timeout_ms)
+        """";
+
+    private const string Format = """
+        /**
+         * Formats a price with `formatCurrency()`.
+         * @param {number} amount - the amount
+         * @param {string} [currency="USD"] - the currency, defaults to "USD"
+         */
+        export function formatPrice(amount, currency = "USD") {
+          return format(amount, currency);
+        }
+        """;
+
+    private static readonly Dictionary<string, string> Contents = new()
+    {
+        ["src/Orders/HelperHandler.cs"] = Handler,
+        ["tools/fetch.py"] = Fetch,
+        ["web/price.js"] = Format
+    };
+
+    private static readonly List<JulieSymbol> Symbols = new()
+    {
+        Symbol("HelperHandler", "class", "/repo/src/Orders/HelperHandler.cs", 6, 24, Handler, 3, 5),
+        Symbol("Send", "method", "/repo/src/Orders/HelperHandler.cs", 13, 16, Handler, 8, 12),
+        Symbol("Status", "method", "/repo/src/Orders/HelperHandler.cs", 21, 21, Handler, 18, 20),
+        Symbol("fetch", "function", "/repo/tools/fetch.py", 1, 8, Fetch, 2, 7),
+        Symbol("formatPrice", "function", "/repo/web/price.js", 6, 8, Format, 1, 5),
+        Symbol("Order", "class", "/repo/src/Orders/Order.cs", 3, 10, null, 0, 0),
+        Symbol("format", "function", "/repo/web/format.js", 1, 5, null, 0, 0)
+    };
+
+    private static readonly HashSet<string> Declared = Symbols.Select(s => s.Name).ToHashSet();
+
+    [Test]
+    public void Documented_Parameters_Missing_From_The_Signature_Should_Be_Reported_With_The_Undocumented_One()
+    {
+        // Act
+        var findings = DocDrift.Check(WorkspacePath, Symbols, Declared, Contents).Where(f => f.Kind == "parameter");
+
+        // Assert - formatPrice documents its parameters accurately, JSDoc optional syntax included
+        Assert.That(findings.Select(f => $"{f.FilePath}:{f.Line} {f.Symbol} {f.Mention} -> {f.Suggestion}"), Is.EqualTo(new[]
+        {
+            "src/Orders/HelperHandler.cs:11 Send request -> order",
+            "tools/fetch.py:6 fetch timeout -> timeout_ms"
+        }));
+    }
+
+    [Test]
+    public void Old_Names_And_Vanished_References_Should_Be_Reported()
+    {
+        // Act
+        var findings = DocDrift.Check(WorkspacePath, Symbols, Declared, Contents).Where(f => f.Kind is "name" or "reference").ToList();
+
+        // Assert - Send is still declared; formatCurrency() was replaced by format
+        Assert.That(findings.Select(f => $"{f.FilePath}:{f.Line} {f.Kind} {f.Symbol} {f.Mention} -> {f.Suggestion ?? "(none)"}"), Is.EqualTo(new[]
+        {
+            "src/Orders/HelperHandler.cs:4 reference HelperHandler OrderClient -> (none)",
+            "src/Orders/HelperHandler.cs:4 name HelperHandler Helper -> HelperHandler",
+            "web/price.js:2 reference formatPrice formatCurrency -> (none)"
+        }));
+        Assert.That(findings[1].Message, Is.EqualTo("Comment opens with 'Helper' but documents class HelperHandler"));
+    }
+
+    [Test]
+    public void Stated_Defaults_And_Literals_The_Code_No_Longer_Has_Should_Be_Reported()
+    {
+        // Act
+        var findings = DocDrift.Check(WorkspacePath, Symbols, Declared, Contents).Where(f => f.Kind == "value");
+
+        // Assert - fetch's stated default belongs to a parameter that no longer exists, formatPrice's is right
+        Assert.That(findings.Select(f => $"{f.Symbol} {f.Mention} -> {f.Suggestion ?? "(none)"}: {f.Message}"), Is.EqualTo(new[]
+        {
+            "Send 3 -> 5: Says 'maxRetries' defaults to 3 but the signature defaults it to 5",
+            "Status \"pending\" -> (none): Mentions \"pending\" but Status no longer contains it"
+        }));
+    }
+
+    private static JulieSymbol Symbol(string name, string kind, string filePath, int start, int end, string? content, int docStart, int docEnd) => new()
+    {
+        Id = $"{filePath}:{name}",
+        Name = name,
+        Kind = kind,
+        Language = Path.GetExtension(filePath) switch { ".cs" => "csharp", ".py" => "python", _ => "javascript" },
+        FilePath = filePath,
+        StartLine = start,
+        EndLine = end,
+        DocComment = content == null ? null : string.Join("\n", content.Split('\n')[(docStart - 1)..docEnd])
+    };
+}
@@ -0,0 +1,85 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Editor;
+using COA.CodeSearch.McpServer.Tools;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class EditorContextServiceTests
+{
+    private EditorContextService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _service = new EditorContextService();
+    }
+
+    [Test]
+    public void Apply_Should_Fill_Empty_Symbol_With_Cursor_Position_In_Editor_Encoding()
+    {
+        // Arrange
+        _service.Set(new EditorContext { FilePath = "src/Services/UserService.cs", Line = 42, Column = 17, ColumnEncoding = "utf-8" });
+        var parameters = new FindReferencesParameters();
+
+        // Act
+        var uses = _service.Apply(ToolNames.FindReferences, parameters);
+
+        // Assert
+        Assert.That(parameters.Symbol, Is.EqualTo("src/Services/UserService.cs:42:17"));
+        Assert.That(parameters.ColumnEncoding, Is.EqualTo("utf-8"));
+        Assert.That(uses.Select(u => u.ToString()), Is.EqualTo(new[] { "Symbol = src/Services/UserService.cs:42:17 (from the editor cursor)" }));
+    }
+
+    [Test]
+    public void Apply_Should_Prefer_A_Selected_Name_And_Never_Override_Explicit_Arguments()
+    {
+        // Arrange
+        _service.Set(new EditorContext
+        {
+            FilePath = "src/Services/UserService.cs",
+            Line = 42,
+            Column = 17,
+            Selection = new EditorSelection { StartLine = 42, StartColumn = 12, EndLine = 42, EndColumn = 36, Text = " UserService.GetUserAsync " }
+        });
+        var references = new FindReferencesParameters();
+        var explicitDefinition = new GoToDefinitionParameters { Symbol = "OrderService" };
+        var hover = new HoverParameters();
+        var overview = new GetSymbolsOverviewParameters();
+
+        // Act
+        _service.Apply(ToolNames.FindReferences, references);
+        var explicitUses = _service.Apply(ToolNames.GoToDefinition, explicitDefinition);
+        _service.Apply(ToolNames.Hover, hover);
+        _service.Apply(ToolNames.GetSymbolsOverview, overview);
+
+        // Assert
+        Assert.That(references.Symbol, Is.EqualTo("GetUserAsync"));
+        Assert.That(explicitDefinition.Symbol, Is.EqualTo("OrderService"));
+        Assert.That(explicitUses, Is.Empty);
+        Assert.That(hover.Position, Is.EqualTo("src/Services/UserService.cs:42:17"));
+        Assert.That(overview.FilePath, Is.EqualTo("src/Services/UserService.cs"));
+    }
+
+    [Test]
+    public void ArgumentFor_Should_Give_Name_Only_Tools_The_Symbol_At_Cursor_And_Skip_Edit_Tools()
+    {
+        // Arrange
+        var cursorOnly = new EditorContext { FilePath = "internal/users/service.go", Line = 10, Column = 5 };
+        var withSymbol = new EditorContext { FilePath = "internal/users/service.go", Line = 10, Column = 5, SymbolAtCursor = "GetUser" };
+        var multiLine = new EditorContext
+        {
+            FilePath = "internal/users/service.go",
+            Selection = new EditorSelection { StartLine = 3, StartColumn = 0, EndLine = 9, EndColumn = 1, Text = "func (s *Service) GetUser(id int) {\n}" }
+        };
+
+        // Act & Assert
+        Assert.That(EditorContextService.ArgumentFor(cursorOnly, ToolNames.FindTests), Is.Null);
+        Assert.That(EditorContextService.ArgumentFor(withSymbol, ToolNames.FindTests)?.ToString(),
+            Is.EqualTo("Symbol = GetUser (from the editor symbol at cursor)"));
+        Assert.That(EditorContextService.ArgumentFor(multiLine, ToolNames.GoToDefinition)?.ToString(),
+            Is.EqualTo("Symbol = internal/users/service.go:3:0 (from the editor selection start)"));
+        Assert.That(EditorContextService.ArgumentFor(withSymbol, ToolNames.EditLines), Is.Null);
+        Assert.That(EditorContextService.ArgumentFor(withSymbol, ToolNames.InsertAtLine), Is.Null);
+    }
+}
@@ -0,0 +1,155 @@
+using NUnit.Framework;
+using System.Security.Cryptography;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Lucene.Net.Documents;
+using Lucene.Net.Search;
+using Lucene.Net.Util;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+/// <summary>
+/// With encryption at rest the index lives in memory and each commit seals only the index files it added
+/// </summary>
+[TestFixture]
+public class EncryptedIndexPersistenceTests
+{
+    private const string WorkspaceHash = "test-hash";
+
+    private string _keyVariable = null!;
+    private string _workspace = null!;
+    private string _indexPath = null!;
+    private LuceneIndexService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _keyVariable = $"CODESEARCH_TEST_KEY_{Guid.NewGuid():N}";
+        Environment.SetEnvironmentVariable(_keyVariable, Convert.ToBase64String(RandomNumberGenerator.GetBytes(32)));
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-encrypted-index-test", Guid.NewGuid().ToString());
+        _indexPath = Path.Combine(_workspace, ".coa", "lucene");
+        System.IO.Directory.CreateDirectory(_workspace);
+
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Encryption:Enabled"] = "true",
+                ["CodeSearch:Encryption:KeySource"] = "Environment",
+                ["CodeSearch:Encryption:KeyEnvironmentVariable"] = _keyVariable
+            })
+            .Build();
+        var pathResolution = new Mock<IPathResolutionService>();
+        pathResolution.Setup(p => p.ComputeWorkspaceHash(It.IsAny<string>())).Returns(WorkspaceHash);
+        pathResolution.Setup(p => p.GetLuceneIndexPath(It.IsAny<string>())).Returns(_indexPath);
+        var codeAnalyzer = new CodeAnalyzer(LuceneVersion.LUCENE_48);
+
+        _service = new LuceneIndexService(
+            NullLogger<LuceneIndexService>.Instance,
+            configuration,
+            pathResolution.Object,
+            new CircuitBreakerService(NullLogger<CircuitBreakerService>.Instance, configuration),
+            new Mock<IMemoryPressureService>().Object,
+            new LineAwareSearchService(NullLogger<LineAwareSearchService>.Instance),
+            new SmartSnippetService(NullLogger<SmartSnippetService>.Instance, codeAnalyzer),
+            new Mock<IWriteLockManager>().Object,
+            codeAnalyzer,
+            new IndexEncryptionService(configuration, NullLogger<IndexEncryptionService>.Instance));
+    }
+
+    [TearDown]
+    public async Task TearDown()
+    {
+        await _service.DisposeAsync();
+        Environment.SetEnvironmentVariable(_keyVariable, null);
+        try
+        {
+            if (System.IO.Directory.Exists(_workspace))
+                System.IO.Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private async Task IndexAsync(params string[] paths)
+    {
+        await _service.IndexDocumentsAsync(_workspace, paths.Select(path => new Document
+        {
+            new StringField("path", path, Field.Store.YES),
+            new TextField("content", $"class {Path.GetFileNameWithoutExtension(path)} {{ }}", Field.Store.YES)
+        }));
+        await _service.CommitAsync(_workspace);
+    }
+
+    private async Task<List<string>> SearchAllAsync()
+    {
+        var result = await _service.SearchAsync(_workspace, new MatchAllDocsQuery(), 100);
+        return result.Hits.Select(h => h.FilePath).OrderBy(p => p).ToList();
+    }
+
+    private HashSet<string> SealedFiles() =>
+        System.IO.Directory.GetFiles(Path.Combine(_indexPath, "sealed")).Select(f => Path.GetFileName(f)).ToHashSet();
+
+    [Test]
+    public async Task CommitAsync_Should_Seal_Only_The_Files_The_Commit_Added()
+    {
+        // Arrange
+        await IndexAsync("Alpha.cs");
+        var first = SealedFiles();
+
+        // Act
+        await IndexAsync("Beta.cs");
+        var second = SealedFiles();
+
+        // Assert - the first segment is left as sealed; only the new segment and commit point are written
+        Assert.That(first, Is.Not.Empty);
+        Assert.That(second.Except(first), Is.Not.Empty);
+        Assert.That(first.Except(second).Count(), Is.LessThanOrEqualTo(1), "Only the superseded commit point is dropped");
+        Assert.That(second.Intersect(first).Count(), Is.GreaterThanOrEqualTo(first.Count - 1));
+        Assert.That(System.IO.Directory.GetFiles(_indexPath).Select(Path.GetFileName), Is.EqualTo(new[] { "manifest.cse" }),
+            "No plaintext index files are written next to the manifest");
+    }
+
+    [Test]
+    public async Task InitializeIndexAsync_Should_Reopen_The_Sealed_Index()
+    {
+        // Arrange
+        await IndexAsync("Alpha.cs");
+        await IndexAsync("Beta.cs");
+
+        // Act
+        await _service.CloseIndexAsync(WorkspaceHash);
+        var reopened = await SearchAllAsync();
+
+        // Assert
+        Assert.That(reopened, Is.EqualTo(new[] { "Alpha.cs", "Beta.cs" }));
+        foreach (var file in SealedFiles())
+        {
+            var bytes = await File.ReadAllBytesAsync(Path.Combine(_indexPath, "sealed", file));
+            Assert.That(System.Text.Encoding.UTF8.GetString(bytes), Does.Not.Contain("class Alpha"));
+        }
+    }
+
+    [Test]
+    public async Task ForceRebuildIndexAsync_Should_Not_Reuse_The_Sealed_Files_Of_The_Previous_Index()
+    {
+        // Arrange - the rebuilt index starts its segment names over
+        await IndexAsync("Alpha.cs", "Beta.cs");
+        var previous = SealedFiles();
+
+        // Act
+        await _service.ForceRebuildIndexAsync(_workspace);
+        await IndexAsync("Gamma.cs");
+        await _service.CloseIndexAsync(WorkspaceHash);
+        var reopened = await SearchAllAsync();
+
+        // Assert
+        Assert.That(reopened, Is.EqualTo(new[] { "Gamma.cs" }));
+        Assert.That(SealedFiles().Intersect(previous), Is.Empty, "The previous index's sealed files are deleted");
+    }
+}
@@ -0,0 +1,139 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using Microsoft.Data.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class EncryptedStorageServiceTests
+{
+    private string _keyVariable = null!;
+    private string _indexRoot = null!;
+    private string _dbPath = null!;
+    private IndexEncryptionService _encryption = null!;
+    private EncryptedStorageService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _keyVariable = $"CODESEARCH_TEST_KEY_{Guid.NewGuid():N}";
+        Environment.SetEnvironmentVariable(_keyVariable, "passphrase");
+        _indexRoot = Path.Combine(Path.GetTempPath(), "codesearch-encrypted-storage-test", Guid.NewGuid().ToString());
+        _dbPath = Path.Combine(_indexRoot, "workspace-hash", "db", "workspace.db");
+        Directory.CreateDirectory(Path.GetDirectoryName(_dbPath)!);
+
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Encryption:Enabled"] = "true",
+                ["CodeSearch:Encryption:KeySource"] = "Environment",
+                ["CodeSearch:Encryption:KeyEnvironmentVariable"] = _keyVariable,
+                ["CodeSearch:Encryption:ResealIntervalSeconds"] = "1"
+            })
+            .Build();
+        var pathResolution = new Mock<IPathResolutionService>();
+        pathResolution.Setup(p => p.GetIndexRootPath()).Returns(_indexRoot);
+
+        _encryption = new IndexEncryptionService(configuration, new Mock<ILogger<IndexEncryptionService>>().Object);
+        _service = new EncryptedStorageService(_encryption, pathResolution.Object, configuration,
+            NullLogger<EncryptedStorageService>.Instance);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        _service.Dispose();
+        Environment.SetEnvironmentVariable(_keyVariable, null);
+        SqliteConnection.ClearAllPools();
+        try
+        {
+            if (Directory.Exists(_indexRoot))
+                Directory.Delete(_indexRoot, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private void AddSymbol(string path, string name)
+    {
+        using var connection = new SqliteConnection($"Data Source={path};Pooling=false");
+        connection.Open();
+        using var cmd = connection.CreateCommand();
+        cmd.CommandText = "PRAGMA journal_mode=WAL; CREATE TABLE IF NOT EXISTS symbols (name TEXT); INSERT INTO symbols VALUES ($name);";
+        cmd.Parameters.AddWithValue("$name", name);
+        cmd.ExecuteNonQuery();
+    }
+
+    /// <summary>
+    /// Unseals a copy of the sealed database and reads its symbols, leaving the live database alone
+    /// </summary>
+    private List<string> ReadSealedSymbols()
+    {
+        var copyPath = Path.Combine(_indexRoot, $"sealed-copy-{Guid.NewGuid():N}.db");
+        File.Copy(_dbPath + _encryption.SealedExtension, copyPath + _encryption.SealedExtension);
+        Assert.That(_encryption.UnsealFile(copyPath), Is.True);
+
+        var names = new List<string>();
+        using (var connection = new SqliteConnection($"Data Source={copyPath};Pooling=false"))
+        {
+            connection.Open();
+            using var cmd = connection.CreateCommand();
+            cmd.CommandText = "SELECT name FROM symbols ORDER BY name";
+            using var reader = cmd.ExecuteReader();
+            while (reader.Read())
+            {
+                names.Add(reader.GetString(0));
+            }
+        }
+        return names;
+    }
+
+    [Test]
+    public async Task StartAsync_Should_Seal_A_Plaintext_Database_Left_By_An_Unclean_Exit()
+    {
+        // Arrange - the sealed copy is stale and the killed server left its latest writes in plaintext
+        AddSymbol(_dbPath, "Stale");
+        _encryption.SealFile(_dbPath);
+        AddSymbol(_dbPath, "Latest");
+
+        // Act
+        await _service.StartAsync(CancellationToken.None);
+        var unsealedOnStart = File.Exists(_dbPath);
+        await _service.StopAsync(CancellationToken.None);
+
+        // Assert - the leftover replaced the sealed copy and nothing stays in plaintext
+        Assert.That(unsealedOnStart, Is.True);
+        Assert.That(File.Exists(_dbPath), Is.False);
+        Assert.That(File.Exists(_dbPath + "-wal"), Is.False);
+        Assert.That(ReadSealedSymbols(), Is.EqualTo(new[] { "Latest" }));
+    }
+
+    [Test]
+    public async Task Service_Should_Reseal_Written_Databases_While_Running()
+    {
+        // Arrange
+        AddSymbol(_dbPath, "Alpha");
+        _encryption.SealFile(_dbPath);
+        await _service.StartAsync(CancellationToken.None);
+
+        // Act - a write while the server runs
+        AddSymbol(_dbPath, "Beta");
+        var resealed = new List<string>();
+        for (var attempt = 0; attempt < 50 && resealed.Count < 2; attempt++)
+        {
+            await Task.Delay(200);
+            resealed = ReadSealedSymbols();
+        }
+
+        // Assert - the sealed copy caught up without the database being closed
+        Assert.That(resealed, Is.EqualTo(new[] { "Alpha", "Beta" }));
+        Assert.That(File.Exists(_dbPath), Is.True);
+        await _service.StopAsync(CancellationToken.None);
+    }
+}
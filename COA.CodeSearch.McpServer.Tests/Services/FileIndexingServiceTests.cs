@@ -0,0 +1,125 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Lucene.Net.Documents;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Microsoft.Extensions.Options;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class FileIndexingServiceTests
+{
+    private string _workspace = null!;
+    private Mock<ILuceneIndexService> _lucene = null!;
+    private Mock<IIndexBudgetService> _indexBudget = null!;
+    private Mock<IDependencyCodeService> _dependencyCode = null!;
+    private FileIndexingService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-file-indexing-test", Guid.NewGuid().ToString());
+        Directory.CreateDirectory(_workspace);
+
+        _lucene = new Mock<ILuceneIndexService>();
+        _indexBudget = new Mock<IIndexBudgetService>();
+        _dependencyCode = new Mock<IDependencyCodeService>();
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:TypeExtraction:Enabled"] = "false" })
+            .Build();
+
+        _service = new FileIndexingService(
+            NullLogger<FileIndexingService>.Instance,
+            configuration,
+            _lucene.Object,
+            new Mock<IPathResolutionService>().Object,
+            new Mock<IIndexingMetricsService>().Object,
+            new Mock<ICircuitBreakerService>().Object,
+            new Mock<IMemoryPressureService>().Object,
+            Options.Create(new MemoryLimitsConfiguration()),
+            indexBudget: _indexBudget.Object,
+            dependencyCode: _dependencyCode.Object);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_workspace))
+                Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private string CreateFile(string relativePath)
+    {
+        var path = Path.Combine(_workspace, relativePath);
+        Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+        File.WriteAllText(path, "class Service { }");
+        return path;
+    }
+
+    private void VerifyNothingIndexed() =>
+        _lucene.Verify(l => l.IndexDocumentAsync(It.IsAny<string>(), It.IsAny<Document>(), It.IsAny<CancellationToken>()), Times.Never());
+
+    [Test]
+    public async Task IndexFileAsync_Should_Index_And_Commit_A_First_Party_File()
+    {
+        // Arrange
+        var path = CreateFile("src/Service.cs");
+
+        // Act
+        var outcome = await _service.IndexFileAsync(_workspace, path);
+
+        // Assert
+        Assert.That(outcome, Is.EqualTo(FileIndexOutcome.Indexed));
+        _lucene.Verify(l => l.IndexDocumentAsync(_workspace, It.Is<Document>(d => d.Get("path") == path), It.IsAny<CancellationToken>()), Times.Once());
+        _lucene.Verify(l => l.CommitAsync(_workspace, It.IsAny<CancellationToken>()), Times.Once());
+    }
+
+    [Test]
+    public async Task IndexFileAsync_Should_Skip_Excluded_Pruned_And_Missing_Files()
+    {
+        // Arrange
+        var vendored = CreateFile("vendor/lib/Client.cs");
+        var pruned = CreateFile("src/Generated.cs");
+        _dependencyCode.Setup(d => d.IsExcluded(_workspace, vendored)).Returns(true);
+        _indexBudget.Setup(b => b.IsPruned(_workspace, pruned)).Returns(true);
+
+        // Act
+        var outcomes = new[]
+        {
+            await _service.IndexFileAsync(_workspace, vendored),
+            await _service.IndexFileAsync(_workspace, pruned),
+            await _service.IndexFileAsync(_workspace, Path.Combine(_workspace, "src", "Deleted.cs"))
+        };
+
+        // Assert
+        Assert.That(outcomes, Is.All.EqualTo(FileIndexOutcome.Skipped));
+        VerifyNothingIndexed();
+    }
+
+    [Test]
+    public async Task IndexFileAsync_Should_Report_A_Failed_Write_As_Failed()
+    {
+        // Arrange
+        var path = CreateFile("src/Service.cs");
+        _lucene.Setup(l => l.IndexDocumentAsync(_workspace, It.IsAny<Document>(), It.IsAny<CancellationToken>()))
+            .ThrowsAsync(new IOException("Disk full"));
+
+        // Act
+        var outcome = await _service.IndexFileAsync(_workspace, path);
+
+        // Assert
+        Assert.That(outcome, Is.EqualTo(FileIndexOutcome.Failed));
+    }
+}
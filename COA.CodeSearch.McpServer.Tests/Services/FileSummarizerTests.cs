@@ -0,0 +1,99 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class FileSummarizerTests
+{
+    [Test]
+    public void Summarize_Should_Use_Doc_Comment_Of_Main_Type_And_Skip_License_Header()
+    {
+        // Arrange
+        var content = string.Join("\n",
+            "// Copyright (c) Acme. All rights reserved.",
+            "using System;",
+            "using Acme.Data;",
+            "",
+            "namespace Acme.Users;",
+            "",
+            "/// <summary>",
+            "/// Deactivates and caches users. Also talks to <see cref=\"IUserRepository\"/>.",
+            "/// </summary>",
+            "[Service]",
+            "public class UserService : BaseService",
+            "{",
+            "    public void Deactivate(int id) { }",
+            "}");
+
+        // Act
+        var summary = FileSummarizer.Summarize(content, "/src/UserService.cs");
+
+        // Assert
+        Assert.That(summary, Is.Not.Null);
+        Assert.That(summary!.Purpose, Is.EqualTo("Deactivates and caches users."));
+        Assert.That(summary.KeySymbols, Does.Contain("class UserService"));
+        Assert.That(summary.Dependencies, Is.EqualTo(new[] { "System", "Acme.Data" }));
+        Assert.That(summary.TypeCount, Is.EqualTo(1));
+    }
+
+    [Test]
+    public void Summarize_Should_Prefer_Extracted_Types_And_Infer_Purpose_Without_Comments()
+    {
+        // Arrange
+        var typeData = new TypeExtractionResult
+        {
+            Success = true,
+            Types = { new TypeInfo { Name = "OrderQueue", Kind = "class", Signature = "class OrderQueue", Line = 1, BaseType = "Queue" } },
+            Methods =
+            {
+                new MethodInfo { Name = "Push", Signature = "Push()", Line = 2 },
+                new MethodInfo { Name = "Pop", Signature = "Pop()", Line = 3 }
+            }
+        };
+
+        // Act
+        var summary = FileSummarizer.Summarize("class OrderQueue\n  Push\n  Pop\n", "/src/queue.custom", typeData);
+
+        // Assert
+        Assert.That(summary!.Purpose, Is.EqualTo("Defines class OrderQueue (Queue) with 2 member(s)"));
+        Assert.That(summary.KeySymbols, Is.EqualTo(new[] { "class OrderQueue : Queue" }));
+        Assert.That(summary.MemberCount, Is.EqualTo(2));
+    }
+
+    [Test]
+    public void ExtractDependencies_Should_Read_Imports_Across_Languages()
+    {
+        // Arrange
+        var lines = new[]
+        {
+            "import (",
+            "    \"fmt\"",
+            "    log \"github.com/sirupsen/logrus\"",
+            ")",
+            "import React from \"react\";",
+            "const fs = require('fs');",
+            "from pathlib import Path",
+            "#include <vector>",
+            "use std::collections::HashMap;",
+            "import fmt"
+        };
+
+        // Act
+        var dependencies = FileSummarizer.ExtractDependencies(lines);
+
+        // Assert
+        Assert.That(dependencies, Is.EqualTo(new[]
+        {
+            "fmt", "github.com/sirupsen/logrus", "react", "fs", "pathlib", "vector", "std::collections::HashMap"
+        }));
+    }
+
+    [Test]
+    public void Summarize_Should_Return_Null_For_Plain_Text()
+    {
+        // Act & Assert
+        Assert.That(FileSummarizer.Summarize("just some notes", "/docs/notes.txt"), Is.Null);
+    }
+}
@@ -0,0 +1,238 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class FileWatcherServiceTests
+{
+    private string _workspace = null!;
+    private Mock<IFileIndexingService> _fileIndexing = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+    private FileWatcherService _watcher = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "watcher_test_" + Guid.NewGuid().ToString("N"));
+        Directory.CreateDirectory(_workspace);
+        _fileIndexing = new Mock<IFileIndexingService>();
+        _fileIndexing.Setup(f => f.IndexFileAsync(It.IsAny<string>(), It.IsAny<string>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync(FileIndexOutcome.Indexed);
+        _pathResolution = new Mock<IPathResolutionService>();
+        _watcher = CreateService(new Dictionary<string, string?>());
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        _watcher.Dispose();
+        if (Directory.Exists(_workspace))
+            Directory.Delete(_workspace, true);
+    }
+
+    private FileWatcherService CreateService(Dictionary<string, string?> settings)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:FileWatcher:DebounceMilliseconds"] = "50",
+                ["CodeSearch:FileWatcher:CoalesceQuietMilliseconds"] = "300",
+                ["CodeSearch:FileWatcher:CoalesceMaxDelayMilliseconds"] = "10000"
+            }.Concat(settings).GroupBy(s => s.Key).Select(g => g.Last()))
+            .Build();
+        var watcher = new FileWatcherService(NullLogger<FileWatcherService>.Instance, configuration, _fileIndexing.Object,
+            _pathResolution.Object, new ServiceCollection().BuildServiceProvider());
+        watcher.StartWatching(_workspace);
+        return watcher;
+    }
+
+    private string Edit(string name, string content)
+    {
+        var path = Path.Combine(_workspace, name);
+        File.WriteAllText(path, content);
+        File.SetLastWriteTimeUtc(path, DateTime.UtcNow.AddSeconds(content.Length));
+        _watcher.RecordWrite(path);
+        return path;
+    }
+
+    private void VerifyIndexed(string path, Times times) =>
+        _fileIndexing.Verify(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>()), times);
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Index_Pending_Changes_Ahead_Of_A_Query()
+    {
+        // Arrange
+        var path = Edit("Service.cs", "class Service { }");
+
+        // Act
+        var indexed = await _watcher.IndexPendingChangesAsync(_workspace);
+        var again = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(indexed, Is.EqualTo(1));
+        Assert.That(again, Is.EqualTo(0), "Nothing is left pending once indexed");
+        VerifyIndexed(path, Times.Once());
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Keep_A_Change_That_Failed_To_Index()
+    {
+        // Arrange
+        var path = Edit("Service.cs", "class Service { }");
+        _fileIndexing.Setup(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>())).ReturnsAsync(FileIndexOutcome.Failed);
+
+        // Act
+        var failed = await _watcher.IndexPendingChangesAsync(_workspace);
+        _fileIndexing.Setup(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>())).ReturnsAsync(FileIndexOutcome.Indexed);
+        var retried = await _watcher.IndexPendingChangesAsync(_workspace);
+        var again = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(failed, Is.EqualTo(1));
+        Assert.That(retried, Is.EqualTo(1), "The failed change is still pending");
+        Assert.That(again, Is.EqualTo(0));
+        VerifyIndexed(path, Times.Exactly(2));
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Not_Keep_A_Change_To_A_Skipped_File()
+    {
+        // Arrange - excluded dependency code or a file pruned by the index budget
+        var path = Edit("Vendored.cs", "class Vendored { }");
+        _fileIndexing.Setup(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>())).ReturnsAsync(FileIndexOutcome.Skipped);
+
+        // Act
+        var skipped = await _watcher.IndexPendingChangesAsync(_workspace);
+        var again = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(skipped, Is.EqualTo(1));
+        Assert.That(again, Is.EqualTo(0), "A skipped file is not indexed again by every query");
+        VerifyIndexed(path, Times.Once());
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Drop_A_Change_That_Keeps_Failing()
+    {
+        // Arrange
+        var path = Edit("Service.cs", "class Service { }");
+        _fileIndexing.Setup(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>())).ReturnsAsync(FileIndexOutcome.Failed);
+
+        // Act
+        var attempts = new List<int>();
+        for (var i = 0; i < 4; i++)
+        {
+            attempts.Add(await _watcher.IndexPendingChangesAsync(_workspace));
+        }
+        Edit("Service.cs", "class Service { void Run() { } }");
+        var afterEdit = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(attempts, Is.EqualTo(new[] { 1, 1, 1, 0 }), "Given up after three failed attempts");
+        Assert.That(afterEdit, Is.EqualTo(1), "The next edit is tried again");
+        VerifyIndexed(path, Times.Exactly(4));
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Keep_An_Edit_Made_While_Indexing()
+    {
+        // Arrange - the file is edited again while its first change is being indexed
+        var path = Edit("Service.cs", "class Service { }");
+        var edited = false;
+        _fileIndexing.Setup(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>()))
+            .Callback(() =>
+            {
+                if (edited)
+                    return;
+                edited = true;
+                Edit("Service.cs", "class Service { void Run() { } }");
+            })
+            .ReturnsAsync(FileIndexOutcome.Indexed);
+
+        // Act
+        var first = await _watcher.IndexPendingChangesAsync(_workspace);
+        var second = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(first, Is.EqualTo(1));
+        Assert.That(second, Is.EqualTo(1), "The edit made during indexing is indexed by the next query");
+        VerifyIndexed(path, Times.Exactly(2));
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Make_A_Write_Tool_Edit_Visible_To_The_Next_Query()
+    {
+        // Arrange - a quiet period far longer than the test, so only the query can index the edit
+        _watcher.Dispose();
+        _watcher = CreateService(new Dictionary<string, string?> { ["CodeSearch:FileWatcher:CoalesceQuietMilliseconds"] = "60000" });
+        var path = Path.Combine(_workspace, "Service.cs");
+        File.WriteAllText(path, "class Service\n{\n}\n");
+        var editService = new UnifiedFileEditService(NullLogger<UnifiedFileEditService>.Instance, _watcher);
+
+        // Act
+        var edit = await editService.ReplaceLinesAsync(path, 2, 3, "{ void Run() { } }");
+        var indexed = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(edit.Success, Is.True);
+        Assert.That(indexed, Is.EqualTo(1));
+        VerifyIndexed(path, Times.Once());
+    }
+
+    [Test]
+    public async Task IndexPendingChangesAsync_Should_Return_At_Once_When_Nothing_Is_Pending()
+    {
+        // Act
+        var indexed = await _watcher.IndexPendingChangesAsync(_workspace);
+
+        // Assert
+        Assert.That(indexed, Is.EqualTo(0));
+        _fileIndexing.Verify(f => f.IndexFileAsync(It.IsAny<string>(), It.IsAny<string>(), It.IsAny<CancellationToken>()), Times.Never());
+    }
+
+    [Test]
+    public async Task Watcher_Should_Index_A_Burst_Of_Edits_Once_When_It_Ends()
+    {
+        // Arrange - five edits 50ms apart, well inside a 1s quiet period
+        _watcher.Dispose();
+        _watcher = CreateService(new Dictionary<string, string?> { ["CodeSearch:FileWatcher:CoalesceQuietMilliseconds"] = "1000" });
+        string path = string.Empty;
+        for (var i = 0; i < 5; i++)
+        {
+            path = Edit("Service.cs", "class Service { }" + new string(' ', i));
+            await Task.Delay(50);
+        }
+
+        // Act - the burst is held back until it has been quiet for the quiet period
+        VerifyIndexed(path, Times.Never());
+        await Task.Delay(2500);
+
+        // Assert
+        VerifyIndexed(path, Times.Once());
+        Assert.That(await _watcher.IndexPendingChangesAsync(_workspace), Is.EqualTo(0));
+    }
+
+    [Test]
+    public async Task Watcher_Should_Index_A_File_Edited_Continuously_After_The_Max_Delay()
+    {
+        // Arrange
+        _watcher.Dispose();
+        _watcher = CreateService(new Dictionary<string, string?> { ["CodeSearch:FileWatcher:CoalesceMaxDelayMilliseconds"] = "400" });
+
+        // Act - edits every 50ms for 1.5s never leave a quiet period
+        string path = string.Empty;
+        for (var i = 0; i < 30; i++)
+        {
+            path = Edit("Service.cs", "class Service { }" + new string(' ', i));
+            await Task.Delay(50);
+        }
+
+        // Assert
+        _fileIndexing.Verify(f => f.IndexFileAsync(_workspace, path, It.IsAny<CancellationToken>()), Times.AtLeast(2));
+    }
+}
@@ -0,0 +1,90 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class FindingsBaselineServiceTests
+{
+    private string _workspace = null!;
+    private string _baselinePath = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-findings-baseline-test", Guid.NewGuid().ToString());
+        var indexPath = Path.Combine(_workspace, ".coa", "index");
+        _baselinePath = Path.Combine(indexPath, "findings-baseline.json");
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(_workspace)).Returns(indexPath);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_workspace))
+                Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private FindingsBaselineService CreateService() =>
+        new(_pathResolution.Object, NullLogger<FindingsBaselineService>.Instance);
+
+    [Test]
+    public void UpdateBaseline_Should_Save_New_Findings_At_Once()
+    {
+        // Arrange
+        using var service = CreateService();
+
+        // Act
+        var added = service.UpdateBaseline(_workspace, "secrets", "a.cs", new[] { "key-1" }, reportFirstSight: true);
+
+        // Assert - a restart doesn't report it again
+        Assert.That(added, Is.EqualTo(new[] { "key-1" }));
+        Assert.That(File.Exists(_baselinePath), Is.True);
+        Assert.That(CreateService().UpdateBaseline(_workspace, "secrets", "a.cs", new[] { "key-1" }, reportFirstSight: true), Is.Empty);
+    }
+
+    [Test]
+    public void UpdateBaseline_Should_Hold_Files_Without_New_Findings_Until_Flushed()
+    {
+        // Arrange
+        using var service = CreateService();
+
+        // Act - first sight of files establishes their baseline without reporting anything
+        service.UpdateBaseline(_workspace, "patterns:Todo", "a.cs", new[] { "todo-1" }, reportFirstSight: false);
+        service.UpdateBaseline(_workspace, "patterns:Todo", "b.cs", new[] { "todo-2" }, reportFirstSight: false);
+        var writtenBeforeFlush = File.Exists(_baselinePath);
+        service.Flush(_workspace);
+
+        // Assert
+        Assert.That(writtenBeforeFlush, Is.False);
+        Assert.That(File.Exists(_baselinePath), Is.True);
+        Assert.That(CreateService().UpdateBaseline(_workspace, "patterns:Todo", "b.cs", new[] { "todo-2", "todo-3" }, reportFirstSight: false),
+            Is.EqualTo(new[] { "todo-3" }));
+    }
+
+    [Test]
+    public void Dispose_Should_Save_Unflushed_Baselines()
+    {
+        // Arrange
+        var service = CreateService();
+        service.UpdateBaseline(_workspace, "patterns:Todo", "a.cs", new[] { "todo-1" }, reportFirstSight: false);
+
+        // Act
+        service.Dispose();
+
+        // Assert
+        Assert.That(CreateService().UpdateBaseline(_workspace, "patterns:Todo", "a.cs", new[] { "todo-1", "todo-2" }, reportFirstSight: false),
+            Is.EqualTo(new[] { "todo-2" }));
+    }
+}
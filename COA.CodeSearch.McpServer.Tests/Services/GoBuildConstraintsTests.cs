@@ -0,0 +1,52 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoBuildConstraintsTests
+{
+    private static readonly GoBuildProfile LinuxAmd64 = new() { Goos = "linux", Goarch = "amd64" };
+    private static readonly GoBuildProfile WindowsArm64 = new() { Goos = "windows", Goarch = "arm64", Tags = { "integration" } };
+
+    [Test]
+    public void Read_Should_Combine_Header_Constraint_With_File_Name_Suffix()
+    {
+        // Arrange
+        var lines = @"// Copyright 2024 The Authors.
+
+//go:build (linux || darwin) && !cgo
+// +build linux darwin
+// +build !cgo
+
+/* Package poll wraps the platform poller. */
+package poll
+
+//go:build ignored
+".Split('\n');
+
+        // Act
+        var constraint = GoBuildConstraints.Read("/ws/poll/fd_amd64_test.go", lines);
+
+        // Assert
+        Assert.That(constraint, Is.EqualTo("(linux || darwin) && !cgo && amd64"));
+        Assert.That(GoBuildConstraints.Read("/ws/poll/fd.go", new[] { "// +build linux,386 darwin", "// +build !cgo", "", "package poll" }),
+            Is.EqualTo("((linux && 386) || darwin) && !cgo"));
+        Assert.That(GoBuildConstraints.Read("/ws/poll/linux.go", new[] { "package poll" }), Is.Null);
+        Assert.That(GoBuildConstraints.FromFileName("/ws/sys/zsyscall_windows_arm64.go"), Is.EqualTo("windows && arm64"));
+    }
+
+    [Test]
+    public void Matches_Should_Evaluate_Constraints_Against_The_Profile()
+    {
+        // Assert
+        Assert.That(GoBuildConstraints.Matches("(linux || darwin) && !cgo && amd64", LinuxAmd64), Is.True);
+        Assert.That(GoBuildConstraints.Matches("(linux || darwin) && !cgo && amd64", WindowsArm64), Is.False);
+        Assert.That(GoBuildConstraints.Matches("unix && go1.21", LinuxAmd64), Is.True);
+        Assert.That(GoBuildConstraints.Matches("unix", WindowsArm64), Is.False);
+        Assert.That(GoBuildConstraints.Matches("windows && integration", WindowsArm64), Is.True);
+        Assert.That(GoBuildConstraints.Matches("ignore", LinuxAmd64), Is.False);
+        Assert.That(GoBuildConstraints.Matches(null, LinuxAmd64), Is.True);
+        Assert.That(GoBuildConstraints.Matches("linux &&", WindowsArm64), Is.True);
+    }
+}
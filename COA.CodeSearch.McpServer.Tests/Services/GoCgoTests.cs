@@ -0,0 +1,133 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoCgoTests
+{
+    private const string Source = @"package native
+
+/*
+#cgo LDFLAGS: -lm
+#include <stdlib.h>
+#include ""bridge.h""
+
+#define BUFFER_SIZE 256
+
+typedef struct {
+    int x;
+    int y;
+} point;
+
+struct config {
+    char *name;
+};
+
+typedef int (*callback)(int);
+
+static inline int add(int a, int b) {
+    if (a > b) {
+        return a + b;
+    }
+    return b + a;
+}
+*/
+import ""C""
+
+import ""unsafe""
+
+// Point is a Go view of the C point
+type Point struct {
+	X, Y int
+}
+
+func Add(a, b int) int {
+	return int(C.add(C.int(a), C.int(b)))
+}
+
+func (p *Point) Free() {
+	C.free(unsafe.Pointer(p))
+}
+";
+
+    [Test]
+    public void FindPreamble_Should_Read_Includes_Directives_And_C_Declarations()
+    {
+        // Act
+        var preamble = GoCgo.FindPreamble(Source)!;
+
+        // Assert
+        Assert.That(preamble.StartLine, Is.EqualTo(3));
+        Assert.That(preamble.EndLine, Is.EqualTo(27));
+        Assert.That(preamble.ImportLine, Is.EqualTo(28));
+        Assert.That(preamble.Directives, Is.EqualTo(new[] { "#cgo LDFLAGS: -lm" }));
+        Assert.That(preamble.Includes, Is.EqualTo(new[] { "<stdlib.h>", "\"bridge.h\"" }));
+        Assert.That(preamble.Declarations.Select(d => $"{d.Kind} {d.Name}@{d.Line}"), Is.EqualTo(new[]
+        {
+            "macro BUFFER_SIZE@8", "typedef point@13", "struct config@15", "typedef callback@19", "function add@21"
+        }));
+    }
+
+    [Test]
+    public void FindPreamble_Should_Need_A_Comment_Directly_Above_The_Import()
+    {
+        // Arrange
+        var separated = "package p\n\n// #include <stdio.h>\n\nimport \"C\"\n";
+        var lineComments = "package p\n\n// #include <stdio.h>\n// int twice(int v) { return v * 2; }\nimport \"C\"\n";
+
+        // Act & Assert
+        Assert.That(GoCgo.FindPreamble(separated), Is.Null);
+        Assert.That(GoCgo.FindPreamble("package p\n\nimport \"fmt\"\n"), Is.Null);
+        var preamble = GoCgo.FindPreamble(lineComments)!;
+        Assert.That(preamble.StartLine, Is.EqualTo(3));
+        Assert.That(preamble.Declarations.Select(d => d.Name), Is.EqualTo(new[] { "twice" }));
+    }
+
+    [Test]
+    public void Repair_Should_Replace_Preamble_Symbols_With_One_Block()
+    {
+        // Arrange
+        var symbols = new List<JulieSymbol>
+        {
+            new() { Id = "1", Name = "native", Kind = "package", FilePath = "native.go", StartLine = 1, EndLine = 1 },
+            new() { Id = "2", Name = "point", Kind = "struct", FilePath = "native.go", StartLine = 10, EndLine = 13 },
+            new() { Id = "3", Name = "add", Kind = "function", FilePath = "native.go", StartLine = 21, EndLine = 26 },
+            new() { Id = "4", Name = "Point", Kind = "struct", FilePath = "native.go", StartLine = 33, EndLine = 35 },
+            new() { Id = "5", Name = "Add", Kind = "function", FilePath = "native.go", StartLine = 37, EndLine = 39 }
+        };
+
+        // Act
+        var repaired = GoCgo.Repair("native.go", Source, symbols);
+
+        // Assert
+        Assert.That(repaired.Select(s => $"{s.Kind} {s.Name}"), Is.EqualTo(new[] { "package native", "module C", "struct Point", "function Add" }));
+        var block = repaired[1];
+        Assert.That(block.StartLine, Is.EqualTo(3));
+        Assert.That(block.EndLine, Is.EqualTo(28));
+        Assert.That(block.DocComment, Does.Contain("function: add"));
+        Assert.That(GoCgo.Repair("native.go", Source, repaired).Select(s => s.Id), Is.EqualTo(repaired.Select(s => s.Id)));
+    }
+
+    [Test]
+    public void Repair_Should_Recover_Go_Declarations_When_Extraction_Stopped_At_The_Preamble()
+    {
+        // Arrange
+        var symbols = new List<JulieSymbol>
+        {
+            new() { Id = "1", Name = "native", Kind = "package", FilePath = "native.go", StartLine = 1, EndLine = 1 },
+            new() { Id = "2", Name = "BUFFER_SIZE", Kind = "constant", FilePath = "native.go", StartLine = 8, EndLine = 8 }
+        };
+
+        // Act
+        var repaired = GoCgo.Repair("native.go", Source, symbols);
+
+        // Assert
+        Assert.That(repaired.Select(s => $"{s.Kind} {s.Name}@{s.StartLine}"), Is.EqualTo(new[]
+        {
+            "package native@1", "module C@3", "struct Point@33", "function Add@37", "method Free@41"
+        }));
+        Assert.That(repaired.Last().Visibility, Is.EqualTo("public"));
+    }
+}
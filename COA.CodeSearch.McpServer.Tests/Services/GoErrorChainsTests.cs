@@ -0,0 +1,171 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoErrorChainsTests
+{
+    private const string Store = @"package users
+
+import (
+	""errors""
+	""fmt""
+)
+
+var (
+	ErrNotFound = errors.New(""user not found"")
+	ErrDeleted  = fmt.Errorf(""%w: deleted"", ErrNotFound)
+)
+
+type ValidationError struct {
+	Field string
+}
+
+func (e *ValidationError) Error() string { return ""invalid "" + e.Field }
+
+type Store struct{}
+
+func (s *Store) GetUser(id int) (*User, error) {
+	if id < 0 {
+		return nil, &ValidationError{Field: ""id""}
+	}
+	if id == 0 {
+		return nil, ErrNotFound
+	}
+	return &User{ID: id}, nil
+}
+";
+
+    private const string Service = @"package users
+
+import ""fmt""
+
+type Service struct {
+	store *Store
+}
+
+func (s *Service) Load(id int) (*User, error) {
+	u, err := s.store.GetUser(id)
+	if err != nil {
+		return nil, fmt.Errorf(""loading user %d: %w"",
+			id, err)
+	}
+	return u, nil
+}
+
+func (s *Service) Exists(id int) bool {
+	_, err := s.store.GetUser(id)
+	return err == nil
+}
+";
+
+    private const string Handler = @"package api
+
+import (
+	""errors""
+	""net/http""
+
+	""example.com/app/internal/users""
+)
+
+type Handler struct {
+	svc *users.Service
+}
+
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.Load(1)
+	if errors.Is(err, users.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	var invalid *users.ValidationError
+	if errors.As(err, &invalid) {
+		http.Error(w, invalid.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = user
+}
+";
+
+    private const string ServiceTest = @"package users
+
+func TestLoad(t *testing.T) {
+	_, err := svc.Load(0)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+}
+";
+
+    private static List<GoErrorFile> Files() => new()
+    {
+        GoErrorChains.Scan("internal/users/store.go", Store),
+        GoErrorChains.Scan("internal/users/service.go", Service),
+        GoErrorChains.Scan("internal/api/handler.go", Handler),
+        GoErrorChains.Scan("internal/users/service_test.go", ServiceTest)
+    };
+
+    [Test]
+    public void Scan_Should_Read_Sentinels_Error_Types_And_Multi_Line_Wraps()
+    {
+        // Act
+        var store = GoErrorChains.Scan("internal/users/store.go", Store);
+        var service = GoErrorChains.Scan("internal/users/service.go", Service);
+
+        // Assert
+        Assert.That(store.Sentinels.Select(s => $"{s.Name}@{s.Line}:{s.Message}:{string.Join(",", s.Wraps)}"), Is.EqualTo(new[]
+        {
+            "ErrNotFound@9:user not found:", "ErrDeleted@10:%w: deleted:ErrNotFound"
+        }));
+        Assert.That(store.Types.Select(t => $"{t.Name}@{t.Line}"), Is.EqualTo(new[] { "ValidationError@17" }));
+        Assert.That(store.Functions.Select(f => $"{f.DisplayName} {f.Line}-{f.EndLine}"), Is.EqualTo(new[]
+        {
+            "ValidationError.Error 17-17", "Store.GetUser 21-29"
+        }));
+        var wrap = service.Functions[0].Events.Single(e => e.Kind == "wrap");
+        Assert.That($"{wrap.Line} {wrap.Format} {string.Join(",", wrap.Wrapped)}", Is.EqualTo("12 loading user %d: %w err"));
+        Assert.That(GoErrorChains.Compose(wrap.Format, "user not found"), Is.EqualTo("loading user %d: user not found"));
+    }
+
+    [Test]
+    public void Trace_Should_Follow_A_Sentinel_Through_Wraps_To_Its_Checks()
+    {
+        // Act
+        var trace = GoErrorChains.Trace(Files(), "users.ErrNotFound")!;
+
+        // Assert
+        Assert.That(trace.Origin.Matches, Is.EqualTo(new[] { "ErrNotFound", "ErrDeleted" }));
+        Assert.That(trace.Hops.Select(h => $"{h.Depth} {h.Function} {h.How}@{h.Line}: {h.Message}"), Is.EqualTo(new[]
+        {
+            "0 Store.GetUser produces@26: user not found",
+            "1 Service.Load wraps@12: loading user %d: user not found",
+            "1 Service.Exists handles@19: user not found",
+            "2 Handler.GetUser checks@16: loading user %d: user not found"
+        }));
+        Assert.That(trace.Paths, Is.EqualTo(new[]
+        {
+            "Store.GetUser produces (internal/users/store.go:26) → Service.Load wraps \"loading user %d: %w\" (internal/users/service.go:12) → Handler.GetUser checks (internal/api/handler.go:16)",
+            "Store.GetUser produces (internal/users/store.go:26) → Service.Exists handles (internal/users/service.go:19)"
+        }));
+        Assert.That(trace.Checks.Select(c => $"{c.FilePath}:{c.Line} {c.Kind} {c.Target} {c.OnPath}"), Is.EqualTo(new[]
+        {
+            "internal/api/handler.go:16 is ErrNotFound True", "internal/users/service_test.go:5 is ErrNotFound False"
+        }));
+    }
+
+    [Test]
+    public void Trace_Should_Start_From_Error_Types_And_Construction_Sites()
+    {
+        // Act
+        var byType = GoErrorChains.Trace(Files(), "ValidationError")!;
+        var bySite = GoErrorChains.Trace(Files(), "internal/users/service.go:12")!;
+
+        // Assert
+        Assert.That(byType.Hops.Where(h => h.How == "produces").Select(h => $"{h.Function}@{h.Line}"), Is.EqualTo(new[] { "Store.GetUser@23" }));
+        Assert.That(byType.Checks.Select(c => $"{c.Function} {c.Kind} {c.Target} {c.OnPath}"), Is.EqualTo(new[] { "Handler.GetUser as ValidationError True" }));
+        Assert.That(bySite.Origin.Kind, Is.EqualTo("site"));
+        Assert.That(bySite.Hops.Select(h => $"{h.Function} {h.How}"), Is.EqualTo(new[] { "Service.Load produces", "Handler.GetUser handles" }));
+        Assert.That(GoErrorChains.Trace(Files(), "ErrMissing"), Is.Null);
+    }
+}
@@ -0,0 +1,99 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoGenericsTests
+{
+    private const string Numbers = @"package num
+
+type Number interface {
+	~int | ~int64 |
+		float32 | float64
+}
+
+type Stack[T any] struct{ items []T }
+
+type Buffer [16]byte
+
+func (s *Stack[T]) Push(v T) { s.items = append(s.items, v) }
+
+func Sum[T Number](values []T) T {
+	var total T
+	return total
+}
+
+func use(xs []int, i int) {
+	s := Stack[int]{}
+	_ = Sum[float64](nil)
+	_ = xs[i]
+	_ = xs[MaxSize]
+	var c cache.LRU[string, []byte]
+}
+";
+
+    private static TypeExtractionResult Extracted() => new()
+    {
+        Success = true,
+        Types =
+        {
+            new TypeInfo { Name = "Number", Kind = "interface", Signature = "type Number interface", Line = 3 },
+            new TypeInfo { Name = "Stack", Kind = "struct", Signature = "type Stack struct", Line = 8 },
+            new TypeInfo { Name = "Buffer", Kind = "type", Signature = "type Buffer", Line = 10 }
+        },
+        Methods =
+        {
+            new MethodInfo { Name = "Push", Signature = "func (s *Stack[T]) Push(v T)", Line = 12 },
+            new MethodInfo { Name = "Sum", Signature = "func Sum(values []T) T", Line = 14 }
+        }
+    };
+
+    [Test]
+    public void Enrich_Should_Capture_Type_Parameters_And_Constraint_Unions()
+    {
+        // Arrange
+        var result = Extracted();
+
+        // Act
+        GoGenerics.Enrich(result, Numbers);
+
+        // Assert
+        var sum = result.Methods.Single(m => m.Name == "Sum");
+        Assert.That(sum.Signature, Is.EqualTo("func Sum[T Number](values []T) T"));
+        Assert.That(sum.TypeParameters!.Single().Constraint, Is.EqualTo("Number"));
+        Assert.That(sum.TypeParameters!.Single().Union, Is.EqualTo(new[] { "~int", "~int64", "float32", "float64" }));
+
+        Assert.That(result.Types.Single(t => t.Name == "Number").TypeSet, Has.Count.EqualTo(4));
+        Assert.That(result.Types.Single(t => t.Name == "Stack").Signature, Is.EqualTo("type Stack[T any] struct"));
+        Assert.That(result.Types.Single(t => t.Name == "Buffer").TypeParameters, Is.Null, "an array length is not a type parameter");
+
+        var push = result.Methods.Single(m => m.Name == "Push");
+        Assert.That(push.ContainingType, Is.EqualTo("Stack"));
+        Assert.That(push.TypeParameters!.Single().Constraint, Is.EqualTo("any"));
+    }
+
+    [Test]
+    public void Enrich_Should_Find_Instantiations_But_Not_Index_Expressions()
+    {
+        // Arrange
+        var result = Extracted();
+
+        // Act
+        GoGenerics.Enrich(result, Numbers);
+
+        // Assert
+        var instantiations = result.Instantiations!.Select(i => $"{i.Name}[{string.Join(",", i.TypeArguments)}]").ToList();
+        Assert.That(instantiations, Is.EqualTo(new[] { "Stack[int]", "Sum[float64]", "cache.LRU[string,[]byte]" }));
+    }
+
+    [TestCase("Sum[T Number]", "Sum")]
+    [TestCase("Stack[int]", "Stack")]
+    [TestCase("cache.LRU[string, []byte]", "cache.LRU")]
+    [TestCase("UserService", "UserService")]
+    public void StripTypeArguments_Should_Leave_The_Generic_Name(string query, string expected)
+    {
+        Assert.That(GoGenerics.StripTypeArguments(query), Is.EqualTo(expected));
+    }
+}
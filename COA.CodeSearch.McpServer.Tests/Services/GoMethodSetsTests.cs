@@ -0,0 +1,258 @@
+using System.Text.Json;
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoMethodSetsTests
+{
+    private const string Store = @"package store
+
+type UserRepository interface {
+	Find(ctx context.Context, id string) (*User, error)
+	Save(ctx context.Context,
+		u *User) error
+	io.Closer
+}
+
+type Base struct{}
+
+func (Base) Close() error { return nil }
+
+type pgRepo struct {
+	Base
+	db *sql.DB
+}
+
+func (r *pgRepo) Find(c context.Context, id string) (*User, error) {
+	return nil, nil
+}
+
+func (r *pgRepo) Save(ctx context.Context, u *User) error { return nil }
+
+type memRepo struct{ *Base }
+
+func (m memRepo) Find(_ context.Context, key string) (u *User, err error) { return }
+";
+
+    private const string Shop = @"package shop
+
+type Store interface {
+	Get(id string) (*Item, error)
+	io.Closer
+}
+
+type ReadWriteStore interface {
+	Store
+	Put(item *Item) error
+}
+
+type Base struct{}
+
+func (b *Base) Close() error { return nil }
+
+type memStore struct {
+	Base
+}
+
+func (m *memStore) Get(id string) (*Item, error) { return nil, nil }
+
+func (m memStore) Put(item *Item) error { return nil }
+
+type diskStore struct {
+	*Base
+}
+
+func (d diskStore) Get(key string) (*Item, error) { return nil, nil }
+
+func (d diskStore) Put(*Item) error { return nil }
+
+type proxy struct {
+	ReadWriteStore
+}
+";
+
+    /// <summary>
+    /// The shop file as indexed: types and receiver methods as tree-sitter extracts them, annotated with method
+    /// sets and stored as JSON, then read back as find_implementations reads it
+    /// </summary>
+    private static (TypeExtractionResult Extracted, GoFileDeclarations Indexed) IndexShop()
+    {
+        var extracted = new TypeExtractionResult
+        {
+            Success = true,
+            Language = "go",
+            Types =
+            {
+                new TypeInfo { Name = "Store", Kind = "interface", Signature = "type Store interface", Line = 3 },
+                new TypeInfo { Name = "ReadWriteStore", Kind = "interface", Signature = "type ReadWriteStore interface", Line = 8 },
+                new TypeInfo { Name = "Base", Kind = "struct", Signature = "type Base struct", Line = 13 },
+                new TypeInfo { Name = "memStore", Kind = "struct", Signature = "type memStore struct", Line = 17 },
+                new TypeInfo { Name = "diskStore", Kind = "struct", Signature = "type diskStore struct", Line = 25 },
+                new TypeInfo { Name = "proxy", Kind = "struct", Signature = "type proxy struct", Line = 33 }
+            },
+            Methods =
+            {
+                new MethodInfo { Name = "Close", Signature = "func (b *Base) Close() error", Line = 15 },
+                new MethodInfo { Name = "Get", Signature = "func (m *memStore) Get(id string) (*Item, error)", Line = 21 },
+                new MethodInfo { Name = "Put", Signature = "func (m memStore) Put(item *Item) error", Line = 23 },
+                new MethodInfo { Name = "Get", Signature = "func (d diskStore) Get(key string) (*Item, error)", Line = 29 },
+                new MethodInfo { Name = "Put", Signature = "func (d diskStore) Put(*Item) error", Line = 31 }
+            }
+        };
+        GoMethodSets.AddMethodSets(extracted, Shop);
+
+        var json = JsonSerializer.Serialize(new { types = extracted.Types, methods = extracted.Methods, language = extracted.Language },
+            new JsonSerializerOptions { PropertyNamingPolicy = JsonNamingPolicy.CamelCase });
+        var stored = JsonSerializer.Deserialize<TypeExtractionResult>(json, TypeExtractionResult.DeserializationOptions)!;
+        return (extracted, GoMethodSets.FromTypeInfo("/ws/shop/store.go", stored));
+    }
+
+    [Test]
+    public void AddMethodSets_Should_Record_Receivers_And_Interface_Method_Specs()
+    {
+        // Act
+        var (extracted, _) = IndexShop();
+
+        // Assert
+        var memGet = extracted.Methods.Single(m => m.Line == 21);
+        Assert.That(memGet.ContainingType, Is.EqualTo("memStore"));
+        Assert.That(memGet.PointerReceiver, Is.True);
+        Assert.That(memGet.MethodSetSignature, Is.EqualTo("(string)(*Item,error)"));
+        Assert.That(extracted.Methods.Single(m => m.Line == 31).PointerReceiver, Is.False);
+
+        var put = extracted.Methods.Single(m => m.Line == 10);
+        Assert.That(put.ContainingType, Is.EqualTo("ReadWriteStore"));
+        Assert.That(put.PointerReceiver, Is.Null, "An interface method spec has no receiver");
+        Assert.That(put.MethodSetSignature, Is.EqualTo("(*Item)(error)"));
+        Assert.That(extracted.Types.Single(t => t.Name == "ReadWriteStore").Embedded, Is.EqualTo(new[] { "Store" }));
+        Assert.That(extracted.Types.Single(t => t.Name == "diskStore").Embedded, Is.EqualTo(new[] { "*Base" }));
+    }
+
+    [Test]
+    public void FromTypeInfo_Should_Require_The_Methods_Of_Embedded_Interfaces()
+    {
+        // Arrange
+        var (_, indexed) = IndexShop();
+        var target = indexed.Types.Single(t => t.Name == "ReadWriteStore");
+
+        // Act
+        var methods = GoMethodSets.InterfaceMethodSet(target, new[] { indexed });
+
+        // Assert - Get from Store, Close from the well-known io.Closer embedded in Store
+        Assert.That(methods.Select(m => m.Name + m.Signature),
+            Is.EqualTo(new[] { "Put(*Item)(error)", "Get(string)(*Item,error)", "Close()(error)" }));
+    }
+
+    [Test]
+    public void FromTypeInfo_Should_Need_A_Pointer_Only_For_Pointer_Receivers_Not_Reached_Through_A_Pointer()
+    {
+        // Arrange
+        var (_, indexed) = IndexShop();
+        var target = indexed.Types.Single(t => t.Name == "ReadWriteStore");
+
+        // Act
+        var implementations = GoMethodSets.FindImplementations(target, new[] { indexed }, out var unresolved)
+            .ToDictionary(i => i.Type.Name);
+
+        // Assert
+        Assert.That(implementations.Keys, Is.EquivalentTo(new[] { "memStore", "diskStore", "proxy" }));
+        Assert.That(implementations.Values.SelectMany(i => i.Missing), Is.Empty);
+        Assert.That(implementations["memStore"].PointerOnly, Is.True,
+            "Get has a pointer receiver, and Close is promoted from a Base embedded by value");
+        Assert.That(implementations["diskStore"].PointerOnly, Is.False, "Close is promoted through *Base");
+        Assert.That(implementations["proxy"].PointerOnly, Is.False, "An embedded interface promotes its methods to the value");
+        Assert.That(implementations["memStore"].Methods.Select(m => $"{m.Name}:{m.Line}"), Is.EqualTo(new[] { "Put:23", "Get:21", "Close:15" }));
+        Assert.That(unresolved, Is.Empty);
+    }
+
+    [Test]
+    public void Parse_Should_Normalise_Signatures_Without_Names_Or_Packages()
+    {
+        // Act
+        var declarations = GoMethodSets.Parse("/ws/store/repo.go", Store.Split('\n'));
+
+        // Assert
+        var repository = declarations.Types.Single(t => t.Name == "UserRepository");
+        Assert.That(repository.Methods.Select(m => m.Name + m.Signature),
+            Is.EqualTo(new[] { "Find(Context,string)(*User,error)", "Save(Context,*User)(error)" }));
+        Assert.That(repository.Embedded, Is.EqualTo(new[] { "io.Closer" }));
+        Assert.That(declarations.Types.Single(t => t.Name == "pgRepo").Embedded, Is.EqualTo(new[] { "Base" }));
+    }
+
+    [Test]
+    public void FindImplementations_Should_Include_Promoted_Methods_And_Pointer_Receivers()
+    {
+        // Arrange
+        var store = GoMethodSets.Parse("/ws/store/repo.go", Store.Split('\n'));
+        var cache = GoMethodSets.Parse("/ws/cache/cache.go", @"package cache
+
+type cached struct {
+	store.Base
+}
+
+func (c *cached) Find(ctx context.Context, id string) (*store.User, error) { return nil, nil }
+func (c *cached) Save(ctx context.Context, u *store.User) error { return nil }
+".Split('\n'));
+        var target = store.Types.Single(t => t.Name == "UserRepository");
+
+        // Act
+        var implementations = GoMethodSets.FindImplementations(target, new[] { store, cache }, out var unresolved);
+
+        // Assert
+        var pg = implementations.Single(i => i.Type.Name == "pgRepo");
+        Assert.That(pg.Missing, Is.Empty);
+        Assert.That(pg.PointerOnly, Is.True);
+        Assert.That(pg.Methods.Select(m => m.Name), Does.Contain("Close"));
+        Assert.That(implementations.Single(i => i.Type.Name == "cached").Missing, Is.Empty);
+        Assert.That(implementations.Single(i => i.Type.Name == "memRepo").Missing, Is.EqualTo(new[] { "Save" }));
+        Assert.That(unresolved, Is.Empty);
+    }
+
+    [Test]
+    public void FindMember_Should_Follow_Embedded_Fields_To_The_Declaring_Type()
+    {
+        // Arrange
+        var files = new[]
+        {
+            GoMethodSets.Parse("/ws/auth/user.go", @"package auth
+
+type Audit struct {
+	CreatedBy, UpdatedBy string `json:""by""`
+}
+
+type User struct {
+	Audit
+	Name string
+}
+
+func (u *User) DisplayName() string { return u.Name }
+
+type Admin struct {
+	*User
+	Roles []string
+}
+".Split('\n'))
+        };
+        var admin = files[0].Types.Single(t => t.Name == "Admin");
+
+        // Act
+        var name = GoMethodSets.FindMember(admin, "Name", files);
+        var display = GoMethodSets.FindMember(admin, "DisplayName", files);
+        var updatedBy = GoMethodSets.FindMember(admin, "UpdatedBy", files);
+
+        // Assert
+        Assert.That(name!.DeclaringType.Name, Is.EqualTo("User"));
+        Assert.That(name.Kind, Is.EqualTo("field"));
+        Assert.That(name.Line, Is.EqualTo(9));
+        Assert.That(name.PromotedVia, Is.EqualTo(new[] { "User" }));
+        Assert.That(display!.Kind, Is.EqualTo("method"));
+        Assert.That(display.Line, Is.EqualTo(12));
+        Assert.That(updatedBy!.DeclaringType.Name, Is.EqualTo("Audit"));
+        Assert.That(updatedBy.PromotedVia, Is.EqualTo(new[] { "User", "Audit" }));
+        Assert.That(GoMethodSets.FindMember(admin, "Roles", files)!.PromotedVia, Is.Empty);
+        Assert.That(GoMethodSets.FindMember(admin, "Missing", files), Is.Null);
+    }
+}
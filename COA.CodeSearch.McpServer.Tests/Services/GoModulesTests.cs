@@ -0,0 +1,97 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoModulesTests
+{
+    [Test]
+    public void ParseGoMod_Should_Read_Requirements_And_Replacements()
+    {
+        // Arrange
+        var content = @"module github.com/acme/shop // the shop
+
+go 1.22
+
+toolchain go1.22.3
+
+require github.com/google/uuid v1.6.0
+
+require (
+	github.com/jackc/pgx/v5 v5.5.5
+	golang.org/x/crypto v0.21.0 // indirect
+	""github.com/acme/billing"" v0.0.0
+)
+
+replace github.com/acme/billing => ../billing
+replace golang.org/x/crypto v0.21.0 => golang.org/x/crypto v0.22.0
+";
+
+        // Act
+        var module = GoModules.ParseGoMod(content);
+        module.Sums = GoModules.ParseGoSum("github.com/google/uuid v1.6.0 h1:abc=\ngithub.com/google/uuid v1.6.0/go.mod h1:def=\ngithub.com/jackc/pgx/v5 v5.5.5/go.mod h1:ghi=\n");
+
+        // Assert
+        Assert.That(module.Path, Is.EqualTo("github.com/acme/shop"));
+        Assert.That(module.GoVersion, Is.EqualTo("1.22"));
+        Assert.That(module.Requires.Select(r => $"{r.Path}@{r.Version}"),
+            Is.EqualTo(new[] { "github.com/google/uuid@v1.6.0", "github.com/jackc/pgx/v5@v5.5.5", "golang.org/x/crypto@v0.21.0", "github.com/acme/billing@v0.0.0" }));
+        Assert.That(module.Requires.Single(r => r.Indirect).Path, Is.EqualTo("golang.org/x/crypto"));
+        Assert.That(module.Requires[3].Replacement, Is.EqualTo("../billing"));
+        Assert.That(module.Requires[2].Replacement, Is.EqualTo("golang.org/x/crypto v0.22.0"));
+        Assert.That(GoModules.MissingSums(module), Is.Empty);
+    }
+
+    [Test]
+    public void Build_Should_Resolve_Imports_To_Workspace_Packages_Modules_And_Std()
+    {
+        // Arrange
+        var module = GoModules.ParseGoMod("module github.com/acme/shop\n\ngo 1.22\n\nrequire (\n\tgithub.com/jackc/pgx/v5 v5.5.5\n\tgithub.com/spf13/cobra v1.8.0\n)\n");
+        var server = GoModules.ReadFile("cmd/server/main.go", @"// Command server runs the shop.
+package main
+
+import (
+	""context""
+	db ""github.com/jackc/pgx/v5/pgxpool""
+	_ ""github.com/acme/shop/internal/auth"" // registers providers
+
+	""github.com/acme/shop/internal/orders""
+)
+
+import ""github.com/acme/unknown""
+
+func main() {}".Split('\n'));
+        var orders = GoModules.ReadFile("internal/orders/orders.go", "package orders\n\nimport \"github.com/acme/shop/internal/auth\"\n\nvar x = 1\nimport \"fmt\"".Split('\n'));
+        var auth = GoModules.ReadFile("internal/auth/auth.go", "package auth\n\nimport (\"errors\"; \"strings\")\n".Split('\n'));
+        var authTest = GoModules.ReadFile("internal/auth/auth_test.go", "package auth_test\n\nimport \"github.com/acme/shop/internal/auth\"\n".Split('\n'));
+
+        // Act
+        var graph = GoModules.Build(new[] { module }, new[] { authTest, server, orders, auth });
+
+        // Assert
+        Assert.That(server.Imports.Select(i => $"{i.Path}@{i.Line}"), Is.EqualTo(new[]
+        {
+            "context@5", "github.com/jackc/pgx/v5/pgxpool@6", "github.com/acme/shop/internal/auth@7",
+            "github.com/acme/shop/internal/orders@9", "github.com/acme/unknown@12"
+        }));
+        Assert.That(orders.Imports.Select(i => i.Path), Is.EqualTo(new[] { "github.com/acme/shop/internal/auth" }));
+        Assert.That(auth.Imports.Select(i => i.Path), Is.EqualTo(new[] { "errors", "strings" }));
+
+        Assert.That(graph.Packages.Keys.OrderBy(k => k), Is.EqualTo(new[] { "github.com/acme/shop/cmd/server", "github.com/acme/shop/internal/auth", "github.com/acme/shop/internal/orders" }));
+        Assert.That(graph.Packages["github.com/acme/shop/internal/auth"].Name, Is.EqualTo("auth"));
+        Assert.That(graph.Packages["github.com/acme/shop/internal/auth"].Files, Is.EqualTo(2));
+
+        var fromServer = graph.Imports.Where(i => i.From == "github.com/acme/shop/cmd/server").ToList();
+        Assert.That(fromServer.Select(i => i.Kind), Is.EqualTo(new[] { "std", "module", "internal", "internal", "unknown" }));
+        Assert.That(fromServer[1].Module, Is.EqualTo("github.com/jackc/pgx/v5"));
+        Assert.That(fromServer[1].Version, Is.EqualTo("v5.5.5"));
+
+        var authImporters = graph.Imports.Where(i => i.To == "github.com/acme/shop/internal/auth").ToList();
+        Assert.That(authImporters.Select(i => $"{i.From} {i.Test}"), Is.EquivalentTo(new[]
+        {
+            "github.com/acme/shop/internal/auth True", "github.com/acme/shop/cmd/server False", "github.com/acme/shop/internal/orders False"
+        }));
+        Assert.That(GoModules.UnusedRequirements(graph, module).Select(r => r.Path), Is.EqualTo(new[] { "github.com/spf13/cobra" }));
+    }
+}
@@ -0,0 +1,88 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GoStructTagsTests
+{
+    private const string Models = @"package models
+
+type (
+	User struct {
+		ID         int64  `json:""id"" db:""user_id""`
+		First, Last string `json:""name,omitempty""`
+		*audit.Stamp `json:""stamp""`
+		Address    struct {
+			City string `json:""city""`
+		} `json:""address""`
+		password string
+	}
+)
+
+type Order struct{ UserID int64 `gorm:""column:owner_user_id;not null""`; Total int }
+
+// type Fake struct { X int `json:""x""` }
+";
+
+    [Test]
+    public void Scan_Should_Read_Tagged_Fields_Of_Named_Structs()
+    {
+        // Act
+        var fields = GoStructTags.Scan(Models.Split('\n'));
+
+        // Assert
+        Assert.That(fields.Select(f => $"{f.Struct}.{f.Field}:{f.Line}"), Is.EqualTo(new[]
+        {
+            "User.ID:5", "User.First:6", "User.Last:6", "User.Stamp:7", "User.City:9", "Order.UserID:15"
+        }));
+        var id = fields[0];
+        Assert.That(id.Type, Is.EqualTo("int64"));
+        Assert.That(id.Tags, Is.EqualTo(new Dictionary<string, string> { ["json"] = "id", ["db"] = "user_id" }));
+        Assert.That(id.Column, Is.EqualTo(2));
+        Assert.That(fields.Single(f => f.Field == "Last").Column, Is.EqualTo(9));
+        Assert.That(fields.Single(f => f.Field == "UserID").Tag, Is.EqualTo(@"gorm:""column:owner_user_id;not null"""));
+    }
+
+    [TestCase("tag:json=name", "First,Last")]
+    [TestCase("tag:json=*,omitempty", "First,Last")]
+    [TestCase("tag:=u*id", "ID")]
+    [TestCase("tag:column=*user_id", "ID,UserID")]
+    [TestCase("tag:db", "ID")]
+    [TestCase("tag:JSON=CITY", "City")]
+    public void Match_Should_Compare_Tag_Names_With_Wildcards(string query, string expected)
+    {
+        // Arrange
+        var fields = GoStructTags.Scan(Models.Split('\n'));
+        Assert.That(GoStructTags.TryParseQuery(query, out var parsed), Is.True);
+
+        // Act
+        var matched = fields.Where(f => GoStructTags.Match(f, parsed, caseSensitive: false).Count > 0).Select(f => f.Field);
+
+        // Assert
+        Assert.That(string.Join(",", matched), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void Match_Should_Respect_Case_Sensitivity()
+    {
+        // Arrange
+        var city = GoStructTags.Scan(Models.Split('\n')).Single(f => f.Field == "City");
+        GoStructTags.TryParseQuery("tag:json=CITY", out var parsed);
+
+        // Act & Assert
+        Assert.That(GoStructTags.Match(city, parsed, caseSensitive: true), Is.Empty);
+        Assert.That(GoStructTags.Match(city, parsed, caseSensitive: false), Is.EqualTo(new[] { ("json", "city") }));
+    }
+
+    [TestCase(",omitempty", "json", null)]
+    [TestCase("column:user_name;not null", "gorm", "user_name")]
+    [TestCase("primaryKey", "gorm", null)]
+    [TestCase("-", "json", null)]
+    [TestCase("user_name,omitempty", "json", "user_name")]
+    public void NameOf_Should_Read_The_Name_Part_Of_A_Tag_Value(string value, string key, string? expected)
+    {
+        // Act & Assert
+        Assert.That(GoStructTags.NameOf(key, value), Is.EqualTo(expected));
+    }
+}
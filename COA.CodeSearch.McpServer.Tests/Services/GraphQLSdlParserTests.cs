@@ -0,0 +1,88 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.GraphQL;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GraphQLSdlParserTests
+{
+    [Test]
+    public void Parse_Should_Read_Types_Fields_And_Skip_Operations()
+    {
+        // Arrange
+        var sdl = @"""""""
+Root query
+""""""
+type Query {
+  ""one user""
+  user(id: ID!): User
+  users(first: Int = 10): [User!]! @deprecated(reason: ""use search"")
+}
+
+type User implements Node & Entity @key(fields: ""id"") {
+  id: ID!
+  posts: [Post!]!
+}
+
+union SearchResult = User | Post
+enum Role { ADMIN USER }
+directive @auth(role: String) on FIELD_DEFINITION | OBJECT
+
+query GetUser($id: ID!) { user(id: $id) { id } }
+{ users { id } }
+
+extend type Query {
+  me: User
+}
+
+schema { query: Query }
+";
+
+        // Act
+        var document = GraphQLSdlParser.Parse("schema/schema.graphqls", sdl);
+
+        // Assert
+        Assert.That(document, Is.Not.Null);
+        Assert.That(document!.Types.Select(t => $"{t.Kind} {t.Name}"), Is.EqualTo(new[] { "type Query", "type User", "union SearchResult", "enum Role" }));
+        var query = document.Types[0];
+        Assert.That(query.Line, Is.EqualTo(4));
+        Assert.That(query.Fields.Select(f => $"{f.Name}: {f.Type}"), Is.EqualTo(new[] { "user: User", "users: [User!]!" }));
+        Assert.That(query.Fields[0].Arguments, Is.EqualTo(new[] { "id: ID!" }));
+        Assert.That(query.Fields[0].Line, Is.EqualTo(6));
+        Assert.That(query.Fields[1].Arguments, Is.EqualTo(new[] { "first: Int = 10" }));
+        Assert.That(document.Types[1].Implements, Is.EqualTo(new[] { "Node", "Entity" }));
+        Assert.That(document.Types[2].Members, Is.EqualTo(new[] { "User", "Post" }));
+        Assert.That(document.Types[3].Members, Is.EqualTo(new[] { "ADMIN", "USER" }));
+        Assert.That(document.Extensions.Single().Fields.Single().Name, Is.EqualTo("me"));
+        Assert.That(document.RootTypes["query"], Is.EqualTo("Query"));
+    }
+
+    [Test]
+    public void Parse_Should_Read_Tagged_Templates_With_File_Lines_And_Ignore_Client_Queries()
+    {
+        // Arrange
+        var script = @"import { gql } from 'graphql-tag';
+
+export const GET_USER = gql`
+  query GetUser { user { id } }
+`;
+
+export const typeDefs = gql`
+  type Post {
+    title: String
+  }
+`;
+";
+
+        // Act
+        var document = GraphQLSdlParser.Parse("web/schema.ts", script);
+
+        // Assert
+        Assert.That(GraphQLSdlParser.IsCandidate("web/schema.ts", script), Is.True);
+        Assert.That(document, Is.Not.Null);
+        var post = document!.Types.Single();
+        Assert.That(post.Name, Is.EqualTo("Post"));
+        Assert.That(post.Line, Is.EqualTo(8));
+        Assert.That(post.Fields.Single().Line, Is.EqualTo(9));
+    }
+}
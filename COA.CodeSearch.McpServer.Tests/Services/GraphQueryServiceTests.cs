@@ -0,0 +1,171 @@
+using COA.CodeSearch.McpServer.Services.Graph;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+using NUnit.Framework;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class GraphQueryServiceTests
+{
+    private const string Workspace = "/test/workspace";
+
+    private readonly List<JulieSymbol> _symbols = new();
+    private readonly List<JulieIdentifier> _identifiers = new();
+    private readonly List<JulieRelationship> _relationships = new();
+    private GraphQueryService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _symbols.Clear();
+        _identifiers.Clear();
+        _relationships.Clear();
+
+        var sqlite = new Mock<ISQLiteSymbolService>();
+        sqlite.Setup(s => s.GetAllSymbolsAsync(Workspace, It.IsAny<CancellationToken>()))
+            .ReturnsAsync(() => _symbols.ToList());
+        sqlite.Setup(s => s.GetIdentifiersByNameAsync(Workspace, It.IsAny<string>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, string name, bool _, CancellationToken _) => _identifiers.Where(i => i.Name == name).ToList());
+        sqlite.Setup(s => s.GetIdentifiersByContainingSymbolAsync(Workspace, It.IsAny<string>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, string id, CancellationToken _) => _identifiers.Where(i => i.ContainingSymbolId == id).ToList());
+        sqlite.Setup(s => s.GetRelationshipsForSymbolsAsync(Workspace, It.IsAny<List<string>>(), It.IsAny<List<string>?>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, List<string> ids, List<string>? _, CancellationToken _) => _relationships
+                .Where(r => ids.Contains(r.FromSymbolId)).GroupBy(r => r.FromSymbolId).ToDictionary(g => g.Key, g => g.ToList()));
+        sqlite.Setup(s => s.GetRelationshipsToSymbolsAsync(Workspace, It.IsAny<List<string>>(), It.IsAny<List<string>?>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, List<string> ids, List<string>? _, CancellationToken _) => _relationships
+                .Where(r => ids.Contains(r.ToSymbolId)).GroupBy(r => r.ToSymbolId).ToDictionary(g => g.Key, g => g.ToList()));
+
+        var configuration = new ConfigurationBuilder().Build();
+        _service = new GraphQueryService(sqlite.Object, configuration, NullLogger<GraphQueryService>.Instance);
+
+        // IPaymentHandler <- CardHandler.Process calls Gateway.Charge; Audit.Log and Scheduler.Run call it too
+        AddSymbol("handler", "IPaymentHandler", "interface", "src/Billing/IPaymentHandler.cs");
+        AddSymbol("card", "CardHandler", "class", "src/Billing/CardHandler.cs");
+        AddSymbol("process", "Process", "method", "src/Billing/CardHandler.cs", parentId: "card");
+        AddSymbol("audit", "Audit", "class", "src/Billing/Audit.cs");
+        AddSymbol("log", "Log", "method", "src/Billing/Audit.cs", parentId: "audit");
+        AddSymbol("gateway", "Gateway", "class", "src/Payments/Gateway.cs");
+        AddSymbol("charge", "Charge", "method", "src/Payments/Gateway.cs", parentId: "gateway");
+        AddSymbol("scheduler", "Scheduler", "class", "src/Jobs/Scheduler.cs");
+        AddSymbol("run", "Run", "method", "src/Jobs/Scheduler.cs", parentId: "scheduler");
+        AddCall("Charge", "process", "charge");
+        AddCall("Charge", "log", null);
+        AddCall("Charge", "run", "charge");
+        _relationships.Add(new JulieRelationship { FromSymbolId = "card", ToSymbolId = "handler", Kind = "implements" });
+    }
+
+    private void AddSymbol(string id, string name, string kind, string filePath, string? parentId = null, string? signature = null)
+    {
+        _symbols.Add(new JulieSymbol
+        {
+            Id = id, Name = name, Kind = kind, FilePath = filePath, ParentId = parentId, Signature = signature,
+            StartLine = _symbols.Count + 1
+        });
+    }
+
+    private void AddCall(string name, string containingId, string? targetId, string? codeContext = null)
+    {
+        _identifiers.Add(new JulieIdentifier
+        {
+            Name = name, Kind = "call", ContainingSymbolId = containingId, TargetSymbolId = targetId, CodeContext = codeContext,
+            StartLine = _identifiers.Count + 10
+        });
+    }
+
+    [Test]
+    public async Task ExecuteAsync_Should_Chain_Callers_Path_And_Implements()
+    {
+        // Arrange
+        var steps = _service.Parse("symbol:Charge | callers | in:src/Billing | implements:IPaymentHandler");
+
+        // Act
+        var outcome = await _service.ExecuteAsync(Workspace, steps, caseSensitive: false);
+
+        // Assert
+        Assert.That(outcome.Symbols.Select(s => s.Id), Is.EqualTo(new[] { "process" }));
+        Assert.That(outcome.Steps.Select(s => s.Count), Is.EqualTo(new[] { 1, 3, 2, 1 }));
+        Assert.That(outcome.Truncated, Is.False);
+    }
+
+    [Test]
+    public async Task ExecuteAsync_Should_Traverse_Both_Directions_Of_Inheritance_And_Calls()
+    {
+        // Act
+        var implementations = await _service.ExecuteAsync(Workspace, _service.Parse("symbol:IPaymentHandler | implementations"), false);
+        var bases = await _service.ExecuteAsync(Workspace, _service.Parse("symbol:CardHandler | bases"), false);
+        var callees = await _service.ExecuteAsync(Workspace, _service.Parse("within:Card* | callees"), false);
+        var notBilling = await _service.ExecuteAsync(Workspace, _service.Parse("kind:method | !in:src/Billing"), false);
+
+        // Assert
+        Assert.That(implementations.Symbols.Select(s => s.Name), Is.EqualTo(new[] { "CardHandler" }));
+        Assert.That(bases.Symbols.Select(s => s.Name), Is.EqualTo(new[] { "IPaymentHandler" }));
+        Assert.That(callees.Symbols.Select(s => s.Name), Is.EqualTo(new[] { "Charge" }));
+        Assert.That(notBilling.Symbols.Select(s => s.Name), Is.EquivalentTo(new[] { "Charge", "Run" }));
+    }
+
+    [Test]
+    public async Task ExecuteAsync_Should_Recognise_Framework_Bases_From_The_Signature()
+    {
+        // Arrange
+        AddSymbol("pool", "ConnectionPool", "class", "src/Data/ConnectionPool.cs", signature: "public sealed class ConnectionPool : IDisposable");
+        AddSymbol("dispose", "Dispose", "method", "src/Data/ConnectionPool.cs", parentId: "pool", signature: "public void Dispose()");
+
+        // Act
+        var outcome = await _service.ExecuteAsync(Workspace, _service.Parse("kind:method | implements:IDisposable"), false);
+
+        // Assert
+        Assert.That(outcome.Symbols.Select(s => s.Name), Is.EqualTo(new[] { "Dispose" }));
+    }
+
+    [TestCase("", "EMPTY_QUERY")]
+    [TestCase("callers | symbol:Charge", "MISSING_SOURCE")]
+    [TestCase("symbol:Charge | callers:Foo", "INVALID_STEP")]
+    [TestCase("symbol: | callers", "INVALID_STEP")]
+    [TestCase("symbol:Charge | limit:0", "INVALID_STEP")]
+    [TestCase("symbol:Charge | followers", "UNKNOWN_STEP")]
+    public void Parse_Should_Reject_Malformed_Queries(string query, string code)
+    {
+        // Act & Assert
+        var ex = Assert.Throws<GraphQueryException>(() => _service.Parse(query));
+        Assert.That(ex!.Code, Is.EqualTo(code));
+    }
+
+    [Test]
+    public async Task FindPathsAsync_Should_Return_Shortest_Path_To_Each_Sink_Symbol()
+    {
+        // Arrange
+        var sources = _service.Parse("in:src/Billing | kind:class");
+        var sinks = _service.Parse("symbol:Charge");
+
+        // Act
+        var outcome = await _service.FindPathsAsync(Workspace, sources, sinks, new List<string>(), maxDepth: 4, maxPaths: 10, caseSensitive: false);
+
+        // Assert
+        Assert.That(outcome.SourceCount, Is.EqualTo(2), "classes stand for their methods");
+        Assert.That(outcome.Paths, Has.Count.EqualTo(1), "one path per sink");
+        Assert.That(outcome.Paths[0].Hops.Select(h => h.Symbol.Name), Is.EqualTo(new[] { "Process", "Charge" }).Or.EqualTo(new[] { "Log", "Charge" }));
+        Assert.That(outcome.Paths[0].Length, Is.EqualTo(1));
+    }
+
+    [Test]
+    public async Task FindPathsAsync_Should_Match_Sink_Calls_By_Qualifier()
+    {
+        // Arrange
+        AddCall("Command", "charge", null, "return exec.Command(\"sh\", \"-c\", script)");
+        AddCall("Command", "run", null, "cli.Command(name)");
+
+        // Act
+        var outcome = await _service.FindPathsAsync(Workspace, _service.Parse("symbol:Run"), null, new List<string> { "exec.Command" },
+            maxDepth: 4, maxPaths: 10, caseSensitive: false);
+
+        // Assert
+        Assert.That(outcome.Paths, Has.Count.EqualTo(1));
+        Assert.That(outcome.Paths[0].Hops.Select(h => h.Symbol.Name), Is.EqualTo(new[] { "Run", "Charge" }));
+        Assert.That(outcome.Paths[0].SinkCall, Is.EqualTo("exec.Command"));
+        Assert.That(outcome.Paths[0].Length, Is.EqualTo(2));
+    }
+}
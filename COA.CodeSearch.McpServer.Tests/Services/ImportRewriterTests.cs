@@ -0,0 +1,97 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ImportRewriterTests
+{
+    [Test]
+    public void Rewrite_Should_Rewrite_Go_Imports_Keeping_Aliases_And_Package_Names()
+    {
+        // Arrange
+        var source = string.Join("\n",
+            "package api",
+            "",
+            "import \"github.com/org/old/log\"",
+            "",
+            "import (",
+            "\t\"fmt\"",
+            "\tstore \"github.com/org/old/storage\"",
+            "\t_ \"github.com/org/old/drivers/pg\"",
+            "\t\"github.com/org/old\"",
+            "\t\"github.com/org/older/util\"",
+            ")");
+        var goMod = "module github.com/acme/app\n\nrequire (\n\tgithub.com/org/old v1.4.0\n)\n\nreplace github.com/org/old => ../old\n";
+
+        // Act
+        var (content, rewrites) = ImportRewriter.Rewrite("api/server.go", source, "github.com/org/old", "github.com/org/platform");
+        var (mod, modRewrites) = ImportRewriter.Rewrite("go.mod", goMod, "github.com/org/old", "github.com/org/platform");
+
+        // Assert - the package imported as old keeps that name; github.com/org/older is another module
+        Assert.That(rewrites.Select(r => r.Line), Is.EqualTo(new[] { 3, 7, 8, 9 }));
+        Assert.That(content, Does.Contain("import \"github.com/org/platform/log\""));
+        Assert.That(content, Does.Contain("\tstore \"github.com/org/platform/storage\""));
+        Assert.That(content, Does.Contain("\t_ \"github.com/org/platform/drivers/pg\""));
+        Assert.That(content, Does.Contain("\told \"github.com/org/platform\""));
+        Assert.That(rewrites.Single(r => r.Line == 9).Note, Does.Contain("aliased as old"));
+        Assert.That(content, Does.Contain("\"github.com/org/older/util\""));
+        Assert.That(modRewrites.Select(r => r.Kind), Is.EqualTo(new[] { "go-mod", "go-mod" }));
+        Assert.That(mod, Does.Contain("module github.com/acme/app"), "The module's own path is not an import");
+        Assert.That(mod, Does.Contain("\tgithub.com/org/platform v1.4.0"));
+        Assert.That(mod, Does.Contain("replace github.com/org/platform => ../old"));
+    }
+
+    [Test]
+    public void Rewrite_Should_Rewrite_Using_Directives_And_Project_Items()
+    {
+        // Arrange
+        var source = "using System;\r\nusing Acme.Old;\r\nusing Acme.Old.Data;\r\nglobal using static Acme.Old.Guard;\r\nusing Repo = Acme.Old.Data.Repository;\r\nusing Acme.Older;\r\n";
+        var project = string.Join("\n",
+            "<Project Sdk=\"Microsoft.NET.Sdk\">",
+            "  <ItemGroup>",
+            "    <Using Include=\"Acme.Old.Data\" />",
+            "    <ProjectReference Include=\"..\\Acme.Old\\Acme.Old.csproj\" />",
+            "    <ProjectReference Include=\"..\\Acme.Old.Tests\\Acme.Old.Tests.csproj\" />",
+            "    <PackageReference Include=\"Acme.Old.Client\" Version=\"2.1.0\" />",
+            "    <PackageReference Include=\"Acme.Olden\" Version=\"1.0.0\" />",
+            "  </ItemGroup>",
+            "</Project>");
+
+        // Act
+        var (content, rewrites) = ImportRewriter.Rewrite("src/Api/Startup.cs", source, "Acme.Old", "Acme.Core");
+        var (csproj, projectRewrites) = ImportRewriter.Rewrite("src/Api/Api.csproj", project, "Acme.Old", "Acme.Core");
+
+        // Assert
+        Assert.That(rewrites.Select(r => r.Line), Is.EqualTo(new[] { 2, 3, 4, 5 }));
+        Assert.That(content, Is.EqualTo("using System;\r\nusing Acme.Core;\r\nusing Acme.Core.Data;\r\nglobal using static Acme.Core.Guard;\r\nusing Repo = Acme.Core.Data.Repository;\r\nusing Acme.Older;\r\n"),
+            "Line endings are kept");
+        Assert.That(projectRewrites.Select(r => r.Line), Is.EqualTo(new[] { 3, 4, 5, 6 }));
+        Assert.That(csproj, Does.Contain("<ProjectReference Include=\"..\\Acme.Core\\Acme.Core.csproj\" />"));
+        Assert.That(csproj, Does.Contain("<ProjectReference Include=\"..\\Acme.Core.Tests\\Acme.Core.Tests.csproj\" />"));
+        Assert.That(csproj, Does.Contain("<PackageReference Include=\"Acme.Olden\""));
+    }
+
+    [Test]
+    public void Rewrite_Should_Rewrite_Script_Python_And_Jvm_Imports()
+    {
+        // Arrange
+        var script = "import { a,\n  b } from '@org/old/utils';\nexport * from \"@org/old\";\nconst c = require('@org/old/c');\nconst d = await import(`@org/old-ui`);\n";
+        var python = "import old.db as db, os\nfrom old.api import (\n    handler,\n)\nimport oldest\n";
+        var java = "package com.acme.app;\n\nimport com.acme.old.Client;\nimport static com.acme.old.Guard.check;\n";
+
+        // Act
+        var (ts, tsRewrites) = ImportRewriter.Rewrite("web/app.ts", script, "@org/old", "@org/core");
+        var (py, _) = ImportRewriter.Rewrite("svc/app.py", python, "old", "core");
+        var (jvm, jvmRewrites) = ImportRewriter.Rewrite("src/main/java/App.java", java, "com.acme.old", "com.acme.core");
+
+        // Assert
+        Assert.That(tsRewrites.Select(r => r.Line), Is.EqualTo(new[] { 2, 3, 4 }));
+        Assert.That(ts, Does.Contain("@org/old-ui"), "A longer package name is a different package");
+        Assert.That(py, Is.EqualTo("import core.db as db, os\nfrom core.api import (\n    handler,\n)\nimport oldest\n"));
+        Assert.That(jvmRewrites, Has.Count.EqualTo(2));
+        Assert.That(jvm, Does.Contain("package com.acme.app;"));
+        Assert.That(jvm, Does.Contain("import static com.acme.core.Guard.check;"));
+        Assert.That(ImportRewriter.Handles("README.md"), Is.False);
+    }
+}
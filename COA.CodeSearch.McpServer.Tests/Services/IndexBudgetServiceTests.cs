@@ -0,0 +1,186 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class IndexBudgetServiceTests
+{
+    private const long MB = 1024 * 1024;
+
+    private string _workspace = null!;
+    private string _indexPath = null!;
+    private Mock<ILuceneIndexService> _lucene = null!;
+    private Mock<IIndexRetentionService> _retention = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-budget-test", Guid.NewGuid().ToString());
+        _indexPath = Path.Combine(_workspace, ".coa", "index");
+        _lucene = new Mock<ILuceneIndexService>();
+        _retention = new Mock<IIndexRetentionService>();
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(It.IsAny<string>())).Returns(_indexPath);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_workspace))
+                Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private IndexBudgetService CreateService(bool enabled = true)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:IndexStorage:MaxWorkspaceSizeMB"] = "10",
+                ["CodeSearch:IndexStorage:Pruning:Enabled"] = enabled.ToString()
+            })
+            .Build();
+        return new IndexBudgetService(_lucene.Object, _retention.Object, _pathResolution.Object, configuration,
+            NullLogger<IndexBudgetService>.Instance);
+    }
+
+    private static PruneCandidate Candidate(string path, long sizeBytes, bool cold = false, bool generated = false) => new()
+    {
+        FilePath = "/ws/" + path,
+        RelativePath = path,
+        SizeBytes = sizeBytes,
+        EstimatedIndexBytes = sizeBytes,
+        IsCold = cold,
+        IsGenerated = generated
+    };
+
+    [Test]
+    public void SelectForPruning_Should_Run_Strategies_In_Order_And_Largest_First()
+    {
+        // Arrange
+        var candidates = new List<PruneCandidate>
+        {
+            Candidate("src/Big.cs", 500),
+            Candidate("src/Old.cs", 100, cold: true),
+            Candidate("src/Older.cs", 200, cold: true),
+            Candidate("obj/Api.g.cs", 300, cold: true, generated: true),
+            Candidate("src/Model.designer.cs", 50, generated: true)
+        };
+
+        // Act
+        var selected = IndexBudgetService.SelectForPruning(candidates, 650, new[] { "cold", "generated", "largest" });
+
+        // Assert - a cold generated file is taken once, by the cold strategy
+        Assert.That(selected.Select(s => $"{s.Candidate.RelativePath}:{s.Strategy}"), Is.EqualTo(new[]
+        {
+            "obj/Api.g.cs:cold",
+            "src/Older.cs:cold",
+            "src/Old.cs:cold",
+            "src/Model.designer.cs:generated"
+        }));
+    }
+
+    [Test]
+    public void SelectForPruning_Should_Skip_Strategies_That_Are_Not_Configured()
+    {
+        // Arrange
+        var candidates = new List<PruneCandidate> { Candidate("src/Big.cs", 500), Candidate("src/Old.cs", 100, cold: true) };
+
+        // Act
+        var selected = IndexBudgetService.SelectForPruning(candidates, 1000, new[] { "cold" });
+
+        // Assert
+        Assert.That(selected.Select(s => s.Candidate.RelativePath), Is.EqualTo(new[] { "src/Old.cs" }));
+    }
+
+    [Test]
+    public async Task EnforceBudgetAsync_Should_Delete_Pruned_Files_And_Remember_Them()
+    {
+        // Arrange - the index is 12 MB of a 10 MB budget, about 2.2 bytes per byte of source
+        var quotas = new Queue<IndexQuotaStatus>(new[]
+        {
+            new IndexQuotaStatus { SizeBytes = 12 * MB, QuotaBytes = 10 * MB },
+            new IndexQuotaStatus { SizeBytes = 9 * MB, QuotaBytes = 10 * MB }
+        });
+        _retention.Setup(r => r.CheckQuota(_workspace)).Returns(() => quotas.Dequeue());
+        _lucene.Setup(l => l.GetIndexedFilesAsync(_workspace, It.IsAny<CancellationToken>()))
+            .ReturnsAsync(new List<IndexedFile>
+            {
+                new() { FilePath = Path.Combine(_workspace, "src", "Service.cs"), SizeBytes = 4 * MB },
+                new() { FilePath = Path.Combine(_workspace, "src", "Client.g.cs"), SizeBytes = 1 * MB },
+                new() { FilePath = Path.Combine(_workspace, "src", "Legacy.cs"), SizeBytes = 1 * MB / 2, IsCold = true }
+            });
+        var service = CreateService();
+
+        // Act
+        var report = await service.EnforceBudgetAsync(_workspace);
+
+        // Assert - the cold file frees ~1 MB and the generated one ~2 MB, covering the 2 MB overage
+        Assert.That(report.Pruned.Select(p => $"{p.Path}:{p.Strategy}"), Is.EqualTo(new[] { "src/Legacy.cs:cold", "src/Client.g.cs:generated" }));
+        Assert.That(report.SizeAfterBytes, Is.EqualTo(9 * MB));
+        Assert.That(report.WithinBudget, Is.True);
+        _lucene.Verify(l => l.DeleteDocumentAsync(_workspace, Path.Combine(_workspace, "src", "Client.g.cs"), It.IsAny<CancellationToken>()), Times.Once());
+        _lucene.Verify(l => l.DeleteDocumentAsync(_workspace, Path.Combine(_workspace, "src", "Service.cs"), It.IsAny<CancellationToken>()), Times.Never());
+        _lucene.Verify(l => l.CommitAsync(_workspace, It.IsAny<CancellationToken>()), Times.Once());
+
+        var reloaded = CreateService();
+        Assert.That(reloaded.IsPruned(_workspace, Path.Combine(_workspace, "src", "Legacy.cs")), Is.True);
+        Assert.That(reloaded.IsPruned(_workspace, Path.Combine(_workspace, "src", "Service.cs")), Is.False);
+
+        reloaded.ResetPruned(_workspace);
+        Assert.That(CreateService().GetPrunedFiles(_workspace), Is.Empty);
+    }
+
+    [Test]
+    public async Task EnforceBudgetAsync_Should_Only_Report_In_A_Dry_Run()
+    {
+        // Arrange
+        _retention.Setup(r => r.CheckQuota(_workspace))
+            .Returns(new IndexQuotaStatus { SizeBytes = 12 * MB, QuotaBytes = 10 * MB });
+        _lucene.Setup(l => l.GetIndexedFilesAsync(_workspace, It.IsAny<CancellationToken>()))
+            .ReturnsAsync(new List<IndexedFile>
+            {
+                new() { FilePath = Path.Combine(_workspace, "src", "Service.cs"), SizeBytes = 4 * MB },
+                new() { FilePath = Path.Combine(_workspace, "src", "Small.cs"), SizeBytes = 2 * MB }
+            });
+        var service = CreateService();
+
+        // Act
+        var report = await service.EnforceBudgetAsync(_workspace, dryRun: true);
+
+        // Assert
+        Assert.That(report.Pruned.Select(p => p.Path), Is.EqualTo(new[] { "src/Service.cs" }));
+        Assert.That(report.SizeAfterBytes, Is.EqualTo(4 * MB));
+        Assert.That(service.IsPruned(_workspace, Path.Combine(_workspace, "src", "Service.cs")), Is.False);
+        _lucene.Verify(l => l.DeleteDocumentAsync(It.IsAny<string>(), It.IsAny<string>(), It.IsAny<CancellationToken>()), Times.Never());
+    }
+
+    [Test]
+    public async Task EnforceBudgetAsync_Should_Do_Nothing_When_Pruning_Is_Disabled()
+    {
+        // Arrange
+        _retention.Setup(r => r.CheckQuota(_workspace))
+            .Returns(new IndexQuotaStatus { SizeBytes = 12 * MB, QuotaBytes = 10 * MB });
+        var service = CreateService(enabled: false);
+
+        // Act
+        var report = await service.EnforceBudgetAsync(_workspace);
+
+        // Assert
+        Assert.That(report.Pruned, Is.Empty);
+        Assert.That(report.WithinBudget, Is.False);
+        _lucene.Verify(l => l.GetIndexedFilesAsync(It.IsAny<string>(), It.IsAny<CancellationToken>()), Times.Never());
+    }
+}
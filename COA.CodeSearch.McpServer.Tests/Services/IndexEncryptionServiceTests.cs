@@ -0,0 +1,134 @@
+using NUnit.Framework;
+using System;
+using System.Collections.Generic;
+using System.IO;
+using System.Security.Cryptography;
+using System.Text;
+using COA.CodeSearch.McpServer.Services;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class IndexEncryptionServiceTests
+{
+    private string _keyVariable = null!;
+    private string _testDirectory = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        // Unique variable per test so parallel runs don't share keys
+        _keyVariable = $"CODESEARCH_TEST_KEY_{Guid.NewGuid():N}";
+        _testDirectory = Path.Combine(Path.GetTempPath(), "codesearch-encryption-test", Guid.NewGuid().ToString());
+        Directory.CreateDirectory(_testDirectory);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        Environment.SetEnvironmentVariable(_keyVariable, null);
+        try
+        {
+            if (Directory.Exists(_testDirectory))
+                Directory.Delete(_testDirectory, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private IndexEncryptionService CreateService(bool enabled = true)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Encryption:Enabled"] = enabled.ToString(),
+                ["CodeSearch:Encryption:KeySource"] = "Environment",
+                ["CodeSearch:Encryption:KeyEnvironmentVariable"] = _keyVariable
+            })
+            .Build();
+
+        return new IndexEncryptionService(configuration, new Mock<ILogger<IndexEncryptionService>>().Object);
+    }
+
+    [Test]
+    public void Encrypt_Then_Decrypt_Should_RoundTrip()
+    {
+        // Arrange
+        Environment.SetEnvironmentVariable(_keyVariable, Convert.ToBase64String(RandomNumberGenerator.GetBytes(32)));
+        var service = CreateService();
+        var plaintext = Encoding.UTF8.GetBytes("public class Secret { }");
+
+        // Act
+        var payload = service.Encrypt(plaintext);
+        var decrypted = service.Decrypt(payload);
+
+        // Assert
+        Assert.That(service.IsEncryptedPayload(payload), Is.True);
+        Assert.That(payload, Is.Not.EqualTo(plaintext));
+        Assert.That(decrypted, Is.EqualTo(plaintext));
+    }
+
+    [Test]
+    public void Decrypt_WithTamperedPayload_Throws()
+    {
+        // Arrange
+        Environment.SetEnvironmentVariable(_keyVariable, "correct horse battery staple");
+        var service = CreateService();
+        var payload = service.Encrypt(Encoding.UTF8.GetBytes("sensitive source"));
+        payload[^1] ^= 0xFF;
+
+        // Act & Assert
+        Assert.Throws(Is.InstanceOf<CryptographicException>(), () => service.Decrypt(payload));
+    }
+
+    [Test]
+    public void Encrypt_WithoutKey_FailsClosed()
+    {
+        // Arrange
+        var service = CreateService();
+
+        // Act & Assert
+        Assert.That(service.HasKey(), Is.False);
+        Assert.Throws<InvalidOperationException>(() => service.Encrypt(new byte[] { 1, 2, 3 }));
+    }
+
+    [Test]
+    public void SealFile_Then_UnsealFile_Should_Restore_Contents()
+    {
+        // Arrange
+        Environment.SetEnvironmentVariable(_keyVariable, "passphrase");
+        var service = CreateService();
+        var dbPath = Path.Combine(_testDirectory, "workspace.db");
+        File.WriteAllText(dbPath, "symbol data");
+
+        // Act
+        service.SealFile(dbPath);
+        var plaintextRemovedAfterSeal = !File.Exists(dbPath);
+        var unsealed = service.UnsealFile(dbPath);
+
+        // Assert
+        Assert.That(plaintextRemovedAfterSeal, Is.True);
+        Assert.That(File.Exists(dbPath + service.SealedExtension), Is.True);
+        Assert.That(unsealed, Is.True);
+        Assert.That(File.ReadAllText(dbPath), Is.EqualTo("symbol data"));
+    }
+
+    [Test]
+    public void UnsealFile_WithNoSealedFile_ReturnsFalse()
+    {
+        // Arrange
+        Environment.SetEnvironmentVariable(_keyVariable, "passphrase");
+        var service = CreateService();
+
+        // Act
+        var result = service.UnsealFile(Path.Combine(_testDirectory, "missing.db"));
+
+        // Assert
+        Assert.That(result, Is.False);
+    }
+}
@@ -0,0 +1,173 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Lucene.Net.Documents;
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+using Lucene.Net.Store;
+using Lucene.Net.Util;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+/// <summary>
+/// An index stamped with another format is moved aside when opened; the previous format keeps serving
+/// searches until the rebuild retires it
+/// </summary>
+[TestFixture]
+public class IndexFormatTests
+{
+    private const string WorkspaceHash = "test-hash";
+
+    private string _workspace = null!;
+    private string _indexPath = null!;
+    private LuceneIndexService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-index-format-test", Guid.NewGuid().ToString());
+        _indexPath = Path.Combine(_workspace, ".coa", "lucene");
+        System.IO.Directory.CreateDirectory(_workspace);
+
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>())
+            .Build();
+        var pathResolution = new Mock<IPathResolutionService>();
+        pathResolution.Setup(p => p.ComputeWorkspaceHash(It.IsAny<string>())).Returns(WorkspaceHash);
+        pathResolution.Setup(p => p.GetLuceneIndexPath(It.IsAny<string>())).Returns(_indexPath);
+        var codeAnalyzer = new CodeAnalyzer(LuceneVersion.LUCENE_48);
+
+        _service = new LuceneIndexService(
+            NullLogger<LuceneIndexService>.Instance,
+            configuration,
+            pathResolution.Object,
+            new CircuitBreakerService(NullLogger<CircuitBreakerService>.Instance, configuration),
+            new Mock<IMemoryPressureService>().Object,
+            new LineAwareSearchService(NullLogger<LineAwareSearchService>.Instance),
+            new SmartSnippetService(NullLogger<SmartSnippetService>.Instance, codeAnalyzer),
+            new Mock<IWriteLockManager>().Object,
+            codeAnalyzer);
+    }
+
+    [TearDown]
+    public async Task TearDown()
+    {
+        await _service.DisposeAsync();
+        try
+        {
+            if (System.IO.Directory.Exists(_workspace))
+                System.IO.Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private static Document CreateDocument(string path) => new()
+    {
+        new StringField("path", path, Field.Store.YES),
+        new TextField("content", $"class {Path.GetFileNameWithoutExtension(path)} {{ }}", Field.Store.YES)
+    };
+
+    /// <summary>
+    /// Writes an index as a server of the given format would have left it
+    /// </summary>
+    private void WriteIndex(int? version, params string[] paths)
+    {
+        System.IO.Directory.CreateDirectory(_indexPath);
+        using var directory = FSDirectory.Open(_indexPath);
+        using var writer = new IndexWriter(directory,
+            new IndexWriterConfig(LuceneVersion.LUCENE_48, new CodeAnalyzer(LuceneVersion.LUCENE_48)));
+        writer.AddDocuments(paths.Select(CreateDocument));
+        if (version != null)
+        {
+            writer.SetCommitData(new Dictionary<string, string> { [IndexFormat.CommitDataKey] = version.Value.ToString() });
+        }
+        writer.Commit();
+    }
+
+    private async Task<List<string>> SearchAllAsync()
+    {
+        var result = await _service.SearchAsync(_workspace, new MatchAllDocsQuery(), 100);
+        return result.Hits.Select(h => h.FilePath).OrderBy(p => p).ToList();
+    }
+
+    [Test]
+    public void ReadVersion_Should_Count_Unstamped_Indexes_As_Version_One()
+    {
+        // Arrange
+        Assert.That(IndexFormat.ReadVersion(_indexPath), Is.Null, "No index yet");
+        WriteIndex(null, "Alpha.cs");
+
+        // Act & Assert
+        Assert.That(IndexFormat.ReadVersion(_indexPath), Is.EqualTo(1));
+        Assert.That(IndexFormat.GetSetAsidePath(_indexPath + Path.DirectorySeparatorChar, 3), Is.EqualTo(_indexPath + ".v3"));
+    }
+
+    [Test]
+    public async Task CommitAsync_Should_Stamp_The_Current_Format()
+    {
+        // Act
+        await _service.IndexDocumentsAsync(_workspace, new[] { CreateDocument("Alpha.cs") });
+        await _service.CommitAsync(_workspace);
+        await _service.CloseIndexAsync(WorkspaceHash);
+
+        // Assert
+        Assert.That(IndexFormat.ReadVersion(_indexPath), Is.EqualTo(IndexFormat.Current));
+        Assert.That(_service.GetRecoveryReport(_workspace), Is.Null);
+    }
+
+    [Test]
+    public async Task InitializeIndexAsync_Should_Serve_The_Previous_Format_Until_It_Is_Retired()
+    {
+        // Arrange
+        WriteIndex(IndexFormat.Previous, "Legacy.cs");
+        var setAsidePath = IndexFormat.GetSetAsidePath(_indexPath, IndexFormat.Previous);
+
+        // Act - the rebuild fills the new index while searches still see the old one
+        await _service.InitializeIndexAsync(_workspace);
+        await _service.IndexDocumentsAsync(_workspace, new[] { CreateDocument("Rebuilt.cs") });
+        await _service.CommitAsync(_workspace);
+        var duringRebuild = await SearchAllAsync();
+        var report = _service.GetRecoveryReport(_workspace);
+
+        var retired = await _service.RetirePreviousFormatAsync(_workspace);
+        var afterRebuild = await SearchAllAsync();
+
+        // Assert
+        Assert.That(report, Is.Not.Null);
+        Assert.That(report!.Action, Is.EqualTo(IndexRecoveryReport.RecoveryAction.FormatUpgrade));
+        Assert.That(report.FormatVersion, Is.EqualTo(IndexFormat.Previous));
+        Assert.That(report.RequiresFullReindex, Is.True);
+        Assert.That(duringRebuild, Is.EqualTo(new[] { "Legacy.cs" }));
+        Assert.That(retired, Is.True);
+        Assert.That(afterRebuild, Is.EqualTo(new[] { "Rebuilt.cs" }));
+        Assert.That(System.IO.Directory.Exists(setAsidePath), Is.False);
+        Assert.That(await _service.RetirePreviousFormatAsync(_workspace), Is.False, "Nothing left to retire");
+    }
+
+    [Test]
+    public async Task InitializeIndexAsync_Should_Set_Aside_Other_Formats_Without_Serving_Them()
+    {
+        // Arrange - written by a newer server
+        var newer = IndexFormat.Current + 1;
+        WriteIndex(newer, "Future.cs");
+
+        // Act
+        await _service.InitializeIndexAsync(_workspace);
+        var results = await SearchAllAsync();
+
+        // Assert
+        var report = _service.GetRecoveryReport(_workspace);
+        Assert.That(report, Is.Not.Null);
+        Assert.That(report!.Action, Is.EqualTo(IndexRecoveryReport.RecoveryAction.FormatReset));
+        Assert.That(report.SetAsidePath, Is.EqualTo(IndexFormat.GetSetAsidePath(_indexPath, newer)));
+        Assert.That(System.IO.Directory.Exists(report.SetAsidePath), Is.True, "Kept for a downgrade");
+        Assert.That(results, Is.Empty);
+    }
+}
@@ -0,0 +1,91 @@
+using NUnit.Framework;
+using System;
+using System.Collections.Generic;
+using System.Linq;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class IndexRetentionServiceTests
+{
+    private static readonly DateTime Now = new(2025, 6, 1, 12, 0, 0, DateTimeKind.Utc);
+
+    private static RetainedIndex CreateIndex(string name, int daysAgo, long sizeMb = 10, bool isPrimary = false, bool isOpen = false)
+    {
+        return new RetainedIndex
+        {
+            IndexPath = $"/indexes/{name}",
+            WorkspaceHash = name,
+            SizeBytes = sizeMb * 1024 * 1024,
+            LastAccessed = Now.AddDays(-daysAgo),
+            IsPrimary = isPrimary,
+            IsOpen = isOpen
+        };
+    }
+
+    [Test]
+    public void SelectForEviction_Should_Evict_Stale_Indexes()
+    {
+        // Arrange
+        var indexes = new List<RetainedIndex> { CreateIndex("fresh", 1), CreateIndex("stale", 45) };
+        var policy = new IndexRetentionPolicy { StaleAfterDays = 30 };
+
+        // Act
+        var selected = IndexRetentionService.SelectForEviction(indexes, policy, Now);
+
+        // Assert
+        Assert.That(selected.Select(s => s.Index.WorkspaceHash), Is.EqualTo(new[] { "stale" }));
+    }
+
+    [Test]
+    public void SelectForEviction_Should_Never_Evict_Primary_Or_Open_Indexes()
+    {
+        // Arrange
+        var indexes = new List<RetainedIndex>
+        {
+            CreateIndex("primary", 100, isPrimary: true),
+            CreateIndex("open", 100, isOpen: true)
+        };
+        var policy = new IndexRetentionPolicy { StaleAfterDays = 30, MaxWorkspaceIndexes = 1 };
+
+        // Act
+        var selected = IndexRetentionService.SelectForEviction(indexes, policy, Now);
+
+        // Assert
+        Assert.That(selected, Is.Empty);
+    }
+
+    [Test]
+    public void SelectForEviction_Should_Evict_Least_Recently_Used_Over_Count_Limit()
+    {
+        // Arrange
+        var indexes = new List<RetainedIndex> { CreateIndex("a", 1), CreateIndex("b", 5), CreateIndex("c", 3) };
+        var policy = new IndexRetentionPolicy { StaleAfterDays = 0, MaxWorkspaceIndexes = 2 };
+
+        // Act
+        var selected = IndexRetentionService.SelectForEviction(indexes, policy, Now);
+
+        // Assert
+        Assert.That(selected.Select(s => s.Index.WorkspaceHash), Is.EqualTo(new[] { "b" }));
+    }
+
+    [Test]
+    public void SelectForEviction_Should_Evict_Until_Under_Total_Size()
+    {
+        // Arrange
+        var indexes = new List<RetainedIndex>
+        {
+            CreateIndex("newest", 1, sizeMb: 40),
+            CreateIndex("middle", 2, sizeMb: 40),
+            CreateIndex("oldest", 3, sizeMb: 40)
+        };
+        var policy = new IndexRetentionPolicy { StaleAfterDays = 0, MaxTotalSizeBytes = 90L * 1024 * 1024 };
+
+        // Act
+        var selected = IndexRetentionService.SelectForEviction(indexes, policy, Now);
+
+        // Assert
+        Assert.That(selected.Select(s => s.Index.WorkspaceHash), Is.EqualTo(new[] { "oldest" }));
+    }
+}
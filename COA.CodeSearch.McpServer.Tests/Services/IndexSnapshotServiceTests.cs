@@ -0,0 +1,157 @@
+using NUnit.Framework;
+using System.Security.Cryptography;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class IndexSnapshotServiceTests
+{
+    private const string ServiceSource = """
+        namespace Shop;
+
+        public class OrderService
+        {
+            public void Submit(Order order)
+            {
+                Validate(order);
+            }
+        }
+        """;
+
+    private string _keyVariable = null!;
+    private string _workspace = null!;
+    private Mock<ISQLiteSymbolService> _sqlite = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _keyVariable = $"CODESEARCH_TEST_KEY_{Guid.NewGuid():N}";
+        Environment.SetEnvironmentVariable(_keyVariable, Convert.ToBase64String(RandomNumberGenerator.GetBytes(32)));
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-snapshot-test", Guid.NewGuid().ToString());
+
+        _sqlite = new Mock<ISQLiteSymbolService>();
+        _sqlite.Setup(s => s.DatabaseExists(_workspace)).Returns(true);
+        _sqlite.Setup(s => s.GetAllFilesAsync(_workspace, It.IsAny<CancellationToken>()))
+            .ReturnsAsync(new List<FileRecord>
+            {
+                new(Path.Combine(_workspace, "src", "OrderService.cs"), ServiceSource, "csharp", ServiceSource.Length, 0),
+                new("docs/README.md", "# Shop", "markdown", 6, 0)
+            });
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(_workspace)).Returns(Path.Combine(_workspace, ".coa", "index"));
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        Environment.SetEnvironmentVariable(_keyVariable, null);
+        try
+        {
+            if (Directory.Exists(_workspace))
+                Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private IndexSnapshotService CreateService(bool encrypted = false, int maxSnapshots = 20)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Encryption:Enabled"] = encrypted.ToString(),
+                ["CodeSearch:Encryption:KeySource"] = "Environment",
+                ["CodeSearch:Encryption:KeyEnvironmentVariable"] = _keyVariable,
+                ["CodeSearch:Snapshots:MaxSnapshots"] = maxSnapshots.ToString()
+            })
+            .Build();
+        return new IndexSnapshotService(_pathResolution.Object, _sqlite.Object,
+            new IndexEncryptionService(configuration, NullLogger<IndexEncryptionService>.Instance),
+            configuration, NullLogger<IndexSnapshotService>.Instance);
+    }
+
+    [Test]
+    public async Task CaptureAsync_Should_Key_Files_By_Relative_Path_With_Their_Declarations()
+    {
+        // Act
+        var snapshot = await CreateService().CaptureAsync(_workspace);
+
+        // Assert
+        Assert.That(snapshot.Files.Keys, Is.EquivalentTo(new[] { "src/OrderService.cs", "docs/README.md" }));
+        var service = snapshot.Files["SRC/orderservice.cs"];
+        Assert.That(service.Lines, Is.EqualTo(9));
+        Assert.That(service.ContentHash.Length, Is.EqualTo(16));
+        Assert.That(service.Declarations.Select(d => d.Name), Does.Contain("OrderService"));
+        Assert.That(service.Declarations.Select(d => d.Name), Does.Contain("Submit"));
+        Assert.That(snapshot.Files["docs/README.md"].Declarations, Is.Empty);
+    }
+
+    [TestCase(false)]
+    [TestCase(true)]
+    public async Task SaveAsync_Should_Round_Trip_Through_LoadAsync(bool encrypted)
+    {
+        // Arrange
+        var service = CreateService(encrypted);
+
+        // Act
+        var info = await service.SaveAsync(_workspace, "before-refactor");
+        var loaded = await service.LoadAsync(_workspace, "before-refactor");
+
+        // Assert
+        Assert.That(info.Name, Is.EqualTo("before-refactor"));
+        Assert.That(loaded, Is.Not.Null);
+        Assert.That(loaded!.Name, Is.EqualTo("before-refactor"));
+        Assert.That(loaded.Files["src/orderservice.cs"].Declarations.Select(d => d.Name), Does.Contain("Submit"));
+        Assert.That(await service.LoadAsync(_workspace, "missing"), Is.Null);
+
+        var stored = await File.ReadAllBytesAsync(Path.Combine(_workspace, ".coa", "index", "snapshots", "before-refactor.json.gz"));
+        Assert.That(stored.Take(2), encrypted ? Is.Not.EqualTo(new byte[] { 0x1f, 0x8b }) : Is.EqualTo(new byte[] { 0x1f, 0x8b }),
+            "Gzip header is only visible when not encrypted");
+    }
+
+    [Test]
+    public async Task SaveAsync_Should_Prune_The_Oldest_Snapshots_Beyond_The_Limit()
+    {
+        // Arrange
+        var service = CreateService(maxSnapshots: 2);
+
+        // Act
+        await service.SaveAsync(_workspace, "one");
+        await Task.Delay(50);
+        await service.SaveAsync(_workspace, "two");
+        await Task.Delay(50);
+        await service.SaveAsync(_workspace, "three");
+
+        // Assert
+        Assert.That(service.List(_workspace).Select(s => s.Name), Is.EqualTo(new[] { "three", "two" }));
+    }
+
+    [TestCase("release-1.2_rc", true)]
+    [TestCase("..", false)]
+    [TestCase("../escape", false)]
+    [TestCase("with space", false)]
+    [TestCase("", false)]
+    public void IsValidName_Should_Only_Allow_Names_That_Are_Safe_File_Names(string name, bool expected)
+    {
+        // Act & Assert
+        Assert.That(IndexSnapshotService.IsValidName(name), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void CaptureAsync_Should_Require_A_Symbol_Database()
+    {
+        // Arrange
+        _sqlite.Setup(s => s.DatabaseExists(_workspace)).Returns(false);
+
+        // Act & Assert
+        Assert.ThrowsAsync<InvalidOperationException>(() => CreateService().CaptureAsync(_workspace));
+    }
+}
@@ -0,0 +1,123 @@
+using NUnit.Framework;
+using System;
+using System.IO;
+using COA.CodeSearch.McpServer.Services.Lucene;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class IndexWriteJournalTests
+{
+    private string _testDirectory = null!;
+    private string _journalPath = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _testDirectory = Path.Combine(Path.GetTempPath(), "codesearch-journal-test", Guid.NewGuid().ToString());
+        Directory.CreateDirectory(_testDirectory);
+        _journalPath = IndexWriteJournal.GetJournalPath(Path.Combine(_testDirectory, "lucene"));
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_testDirectory))
+                Directory.Delete(_testDirectory, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    [Test]
+    public void GetJournalPath_Should_Place_Journal_Beside_Lucene_Directory()
+    {
+        // Assert
+        Assert.That(Path.GetDirectoryName(_journalPath), Is.EqualTo(_testDirectory));
+        Assert.That(Path.GetFileName(_journalPath), Is.EqualTo(IndexWriteJournal.JournalFileName));
+    }
+
+    [Test]
+    public void ReadPending_Should_Return_Appended_Paths_Without_Duplicates()
+    {
+        // Arrange
+        using (var journal = new IndexWriteJournal(_journalPath))
+        {
+            journal.Append(new[] { "/src/a.cs", "/src/b.cs" });
+            journal.Append(new[] { "/src/a.cs" });
+        }
+
+        // Act
+        var pending = IndexWriteJournal.ReadPending(_journalPath);
+
+        // Assert
+        Assert.That(pending, Is.EqualTo(new[] { "/src/a.cs", "/src/b.cs" }));
+    }
+
+    [Test]
+    public void Clear_Should_Leave_Nothing_Pending()
+    {
+        // Arrange
+        using var journal = new IndexWriteJournal(_journalPath);
+        journal.Append(new[] { "/src/a.cs" });
+
+        // Act
+        journal.Clear();
+
+        // Assert
+        Assert.That(IndexWriteJournal.ReadPending(_journalPath), Is.Empty);
+    }
+
+    [Test]
+    public void ReadPending_Should_Ignore_Torn_Final_Entry()
+    {
+        // Arrange - simulate a crash part-way through an append
+        File.WriteAllText(_journalPath, "/src/a.cs\n/src/b.c");
+
+        // Act
+        var pending = IndexWriteJournal.ReadPending(_journalPath);
+
+        // Assert
+        Assert.That(pending, Is.EqualTo(new[] { "/src/a.cs" }));
+    }
+
+    [Test]
+    public void Reopening_Should_Keep_Entries_From_Previous_Run()
+    {
+        // Arrange
+        using (var crashed = new IndexWriteJournal(_journalPath))
+        {
+            crashed.Append(new[] { "/src/a.cs" });
+        }
+
+        // Act
+        using (var reopened = new IndexWriteJournal(_journalPath))
+        {
+            reopened.Append(new[] { "/src/c.cs" });
+        }
+
+        // Assert
+        Assert.That(IndexWriteJournal.ReadPending(_journalPath), Is.EqualTo(new[] { "/src/a.cs", "/src/c.cs" }));
+    }
+
+    [Test]
+    public void MarkFullIndexStarted_Should_Flag_Index_Incomplete_Until_Completed()
+    {
+        // Arrange
+        var luceneIndexPath = Path.Combine(_testDirectory, "lucene");
+
+        // Act
+        IndexWriteJournal.MarkFullIndexStarted(luceneIndexPath);
+        var interrupted = IndexWriteJournal.IsFullIndexIncomplete(luceneIndexPath);
+        IndexWriteJournal.MarkFullIndexCompleted(luceneIndexPath);
+
+        // Assert
+        Assert.That(interrupted, Is.True);
+        Assert.That(IndexWriteJournal.IsFullIndexIncomplete(luceneIndexPath), Is.False);
+        Assert.That(File.Exists(Path.Combine(_testDirectory, IndexWriteJournal.IncompleteMarkerFileName)), Is.False);
+    }
+}
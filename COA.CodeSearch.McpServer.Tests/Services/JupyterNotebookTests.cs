@@ -0,0 +1,105 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class JupyterNotebookTests
+{
+    private const string Notebook = """
+        {
+         "cells": [
+          {
+           "cell_type": "markdown",
+           "metadata": {},
+           "source": ["# Churn model\n", "Loads the events and trains a classifier."]
+          },
+          {
+           "cell_type": "code",
+           "execution_count": 1,
+           "metadata": {},
+           "outputs": [{"name": "stdout", "output_type": "stream", "text": ["import pandas as pd\n"]}],
+           "source": ["import pandas as pd\n", "\n", "events = pd.read_csv(\"events.csv\")\n"]
+          },
+          {
+           "cell_type": "code",
+           "execution_count": null,
+           "metadata": {},
+           "outputs": [],
+           "source": []
+          },
+          {
+           "cell_type": "code",
+           "execution_count": 2,
+           "metadata": {},
+           "outputs": [],
+           "source": "def train(frame):\r\n    return fit(frame)"
+          }
+         ],
+         "metadata": {
+          "kernelspec": {"display_name": "Python 3", "language": "python", "name": "python3"},
+          "language_info": {"name": "python"}
+         },
+         "nbformat": 4,
+         "nbformat_minor": 5
+        }
+        """;
+
+    [Test]
+    public void Parse_Should_Join_Code_Cells_And_Record_Where_Each_Starts()
+    {
+        // Act
+        var notebook = JupyterNotebook.Parse(Notebook);
+
+        // Assert
+        Assert.That(notebook, Is.Not.Null);
+        Assert.That(notebook!.Text, Is.EqualTo(
+            "import pandas as pd\n\nevents = pd.read_csv(\"events.csv\")\n\ndef train(frame):\n    return fit(frame)"));
+        Assert.That(notebook.Cells, Is.EqualTo(new[]
+        {
+            new NotebookCellRange(Cell: 2, StartLine: 1, LineCount: 3),
+            new NotebookCellRange(Cell: 4, StartLine: 5, LineCount: 2)
+        }));
+        Assert.That(notebook.Language, Is.EqualTo("python"));
+    }
+
+    [Test]
+    public void Locate_Should_Map_Lines_To_Cell_And_Line_Through_The_Stored_Ranges()
+    {
+        // Arrange
+        var stored = JupyterNotebook.FormatCells(JupyterNotebook.Parse(Notebook)!.Cells);
+
+        // Act
+        var cells = JupyterNotebook.ParseCells(stored);
+
+        // Assert
+        Assert.That(stored, Is.EqualTo("2:1:3;4:5:2"));
+        Assert.That(JupyterNotebook.Locate(cells, 3), Is.EqualTo((2, 3)));
+        Assert.That(JupyterNotebook.Locate(cells, 4), Is.Null, "blank line between cells");
+        Assert.That(JupyterNotebook.Locate(cells, 6), Is.EqualTo((4, 2)));
+        Assert.That(JupyterNotebook.Locate(cells, 7), Is.Null);
+        Assert.That(JupyterNotebook.ParseCells("2:1:3;bad;4:x:2"), Has.Count.EqualTo(1));
+    }
+
+    [Test]
+    public void Parse_Should_Read_Nbformat3_Worksheets_And_Reject_Other_Json()
+    {
+        // Arrange
+        const string nbformat3 = """
+            {"worksheets": [{"cells": [
+              {"cell_type": "heading", "level": 1, "source": ["Setup"]},
+              {"cell_type": "code", "language": "python", "input": ["x = 1\n", "y = 2"]}
+            ]}], "metadata": {}, "nbformat": 3}
+            """;
+
+        // Act
+        var notebook = JupyterNotebook.Parse(nbformat3);
+
+        // Assert
+        Assert.That(notebook!.Text, Is.EqualTo("x = 1\ny = 2"));
+        Assert.That(notebook.Cells, Is.EqualTo(new[] { new NotebookCellRange(2, 1, 2) }));
+        Assert.That(notebook.Language, Is.Null);
+        Assert.That(JupyterNotebook.Parse("{\"name\": \"package\"}"), Is.Null);
+        Assert.That(JupyterNotebook.Parse("not json {"), Is.Null);
+    }
+}
@@ -0,0 +1,200 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class KubernetesManifestsTests
+{
+    [Test]
+    public void Parse_Should_Read_Workloads_And_Config_From_Every_Document()
+    {
+        // Arrange
+        var manifest = @"apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+  namespace: shop
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          image: ghcr.io/acme/orders-migrations:1.4
+      containers:
+        - name: api
+          image: ghcr.io/acme/orders-api:1.4
+          env:
+            - name: LOG_LEVEL
+              value: debug
+            - name: DB_HOST
+              valueFrom:
+                configMapKeyRef:
+                  name: orders-config
+                  key: db-host
+            - name: POD_NAME
+              valueFrom:
+                fieldRef:
+                  fieldPath: metadata.name
+          envFrom:
+            - secretRef:
+                name: orders-secrets
+      volumes:
+        - name: settings
+          configMap:
+            name: orders-config
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: orders-config
+data:
+  db-host: postgres
+  appsettings.json: |
+    { ""Logging"": {} }
+";
+
+        // Act
+        var resources = KubernetesManifests.Parse("deploy/orders.yaml", manifest);
+
+        // Assert
+        Assert.That(resources.Select(r => $"{r.Kind}/{r.Name}@{r.Line}"), Is.EqualTo(new[] { "Deployment/orders@2", "ConfigMap/orders-config@36" }));
+        var deployment = resources[0];
+        Assert.That(deployment.Namespace, Is.EqualTo("shop"));
+        Assert.That(deployment.Containers.Select(c => $"{c.Name}:{c.Image}:{c.Init}"), Is.EqualTo(new[]
+        {
+            "migrate:ghcr.io/acme/orders-migrations:1.4:True", "api:ghcr.io/acme/orders-api:1.4:False"
+        }));
+        Assert.That(deployment.Containers[1].Env.Select(e => $"{e.Name}:{e.Source}:{e.SourceName ?? e.Value}"), Is.EqualTo(new[]
+        {
+            "LOG_LEVEL:value:debug", "DB_HOST:configMap:orders-config", "POD_NAME:field:metadata.name"
+        }));
+        Assert.That(deployment.ConfigReferences.Select(r => $"{r.Usage}:{r.Kind}:{r.Name}:{r.Key}"), Is.EqualTo(new[]
+        {
+            "env:configMap:orders-config:db-host", "envFrom:secret:orders-secrets:", "volume:configMap:orders-config:"
+        }));
+        Assert.That(resources[1].Keys, Is.EqualTo(new[] { "db-host", "appsettings.json" }));
+        Assert.That(KubernetesManifests.IsManifest("deploy/orders.yaml", manifest), Is.True);
+        Assert.That(KubernetesManifests.IsManifest("deploy/values.yaml", "image:\n  tag: 1\n"), Is.False);
+    }
+
+    [Test]
+    public void Parse_Should_Render_Helm_Templates_From_Values()
+    {
+        // Arrange
+        var chart = KubernetesManifests.ReadChart("charts/orders", "apiVersion: v2\nname: orders\nversion: 0.3.0\nappVersion: \"2.1.0\"\n", @"image:
+  repository: acme/orders-api
+  tag: """"
+replicaCount: 2
+env:
+  - name: FEATURE_FLAGS
+    value: ""beta""
+unusedSetting: true
+");
+        var template = @"apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include ""orders.fullname"" . }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  template:
+    spec:
+      containers:
+        - name: {{ .Chart.Name }}
+          image: ""{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}""
+          {{- with .Values.env }}
+          env:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+          {{- if .Values.metrics.enabled }}
+          ports:
+            - containerPort: 9090
+          {{- end }}
+";
+
+        // Act
+        var resources = KubernetesManifests.Parse("charts/orders/templates/deployment.yaml", template, chart);
+
+        // Assert
+        var deployment = resources.Single();
+        Assert.That(deployment.Chart, Is.EqualTo("charts/orders"));
+        Assert.That(deployment.Name, Is.EqualTo("{{ include \"orders.fullname\" . }}"));
+        var container = deployment.Containers.Single();
+        Assert.That($"{container.Name} {container.Image}", Is.EqualTo("orders acme/orders-api:2.1.0"));
+        Assert.That(container.Env.Select(e => $"{e.Name}={e.Value}@{e.Line}"), Is.EqualTo(new[] { "FEATURE_FLAGS=beta@14" }));
+        Assert.That(chart.References.Select(r => $"{r.Path}:{r.Defined}@{r.Line}"), Is.EqualTo(new[]
+        {
+            "replicaCount:True@6", "image.repository:True@11", "image.tag:True@11", "env:True@12", "metrics.enabled:False@16"
+        }));
+        Assert.That(KubernetesManifests.ChartOf("charts/orders/templates/deployment.yaml", new[] { chart }), Is.EqualTo(chart));
+        Assert.That(KubernetesManifests.ChartOf("charts/orders/values.yaml", new[] { chart }), Is.Null);
+    }
+
+    [Test]
+    public void Link_Should_Resolve_Config_And_Join_Variables_With_The_Image_Source()
+    {
+        // Arrange
+        var manifest = @"apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: registry.local/cleanup-worker@sha256:abc
+              env:
+                - name: RETENTION_DAYS
+                  valueFrom:
+                    configMapKeyRef:
+                      name: cleanup
+                      key: retention
+                - name: API_TOKEN
+                  valueFrom:
+                    secretKeyRef:
+                      name: vault-token
+                      key: token
+              envFrom:
+                - configMapRef:
+                    name: cleanup
+                  prefix: APP_
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cleanup
+data:
+  days: ""7""
+";
+        var resources = KubernetesManifests.Parse("k8s/cleanup.yaml", manifest);
+        var container = resources[0].Containers.Single();
+        container.Source = KubernetesManifests.FindImageSource(container.Image, new[]
+        {
+            "src/Cleanup.Worker/Cleanup.Worker.csproj", "services/cleanup-worker/Dockerfile", "README.md"
+        });
+        var reads = new Dictionary<string, List<string>>
+        {
+            ["APP_days"] = new() { "services/cleanup-worker/main.go:12" },
+            ["API_TOKEN"] = new() { "tools/rotate.py:3" }
+        };
+
+        // Act
+        KubernetesManifests.Link(resources, reads);
+
+        // Assert
+        Assert.That(container.Source, Is.EqualTo("services/cleanup-worker"));
+        Assert.That(resources[0].ConfigReferences.Select(r => $"{r.Name}:{r.Status}"), Is.EqualTo(new[]
+        {
+            "cleanup:missing-key", "vault-token:external", "cleanup:defined"
+        }));
+        Assert.That(container.Env.Select(e => $"{e.Name}:{e.Status}"), Is.EqualTo(new[]
+        {
+            "RETENTION_DAYS:unread", "API_TOKEN:read-elsewhere", "APP_days:read"
+        }));
+        Assert.That(KubernetesManifests.FindImageSource("acme/billing:1.0", new[] { "src/Billing/Billing.csproj" }), Is.EqualTo("src/Billing"));
+        Assert.That(KubernetesManifests.FindImageSource("nginx:1.25", new[] { "src/Billing/Billing.csproj" }), Is.Null);
+    }
+}
@@ -0,0 +1,49 @@
+using NUnit.Framework;
+using System.Linq;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class LanguageCapabilitiesTests
+{
+    [Test]
+    public void Find_Should_Match_Name_Case_Insensitively()
+    {
+        // Act
+        var language = LanguageCapabilities.Find("TypeScript");
+
+        // Assert
+        Assert.That(language, Is.Not.Null);
+        Assert.That(language!.Name, Is.EqualTo("typescript"));
+    }
+
+    [Test]
+    public void Find_Should_Match_File_Extension()
+    {
+        // Act
+        var language = LanguageCapabilities.Find(".CSHTML");
+
+        // Assert
+        Assert.That(language, Is.Not.Null);
+        Assert.That(language!.Name, Is.EqualTo("razor"));
+        Assert.That(language.References, Is.EqualTo(FeatureLevel.Partial));
+    }
+
+    [Test]
+    public void Find_Should_Return_Null_For_Unknown_Language()
+    {
+        // Assert
+        Assert.That(LanguageCapabilities.Find("cobol"), Is.Null);
+    }
+
+    [Test]
+    public void All_Should_Not_Claim_Rename_Without_References()
+    {
+        // Rename depends on reference resolution, so it can never be better than references
+        var inconsistent = LanguageCapabilities.All.Where(l => l.Rename > l.References).Select(l => l.Name);
+
+        // Assert
+        Assert.That(inconsistent, Is.Empty);
+    }
+}
@@ -0,0 +1,111 @@
+using NUnit.Framework;
+using System;
+using System.Linq;
+using COA.CodeSearch.McpServer.Services.Logging;
+using Microsoft.Extensions.Logging;
+using Moq;
+using Serilog.Events;
+using Serilog.Parsing;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class LogQueryServiceTests
+{
+    private InMemoryLogSink _sink = null!;
+    private LogQueryService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _sink = new InMemoryLogSink(100);
+        _service = new LogQueryService(_sink, new Mock<ILogger<LogQueryService>>().Object);
+    }
+
+    private void Emit(LogEventLevel level, string message, string? correlationId = null, string? toolName = null, DateTimeOffset? timestamp = null)
+    {
+        var properties = new System.Collections.Generic.List<LogEventProperty>();
+        if (correlationId != null)
+            properties.Add(new LogEventProperty(CorrelationIdEnricher.CorrelationIdProperty, new ScalarValue(correlationId)));
+        if (toolName != null)
+            properties.Add(new LogEventProperty(CorrelationIdEnricher.ToolNameProperty, new ScalarValue(toolName)));
+
+        _sink.Emit(new LogEvent(timestamp ?? DateTimeOffset.UtcNow, level, null,
+            new MessageTemplateParser().Parse(message), properties));
+    }
+
+    [Test]
+    public void Query_Should_Filter_By_Minimum_Level_And_Return_Newest_First()
+    {
+        // Arrange
+        Emit(LogEventLevel.Debug, "debug detail");
+        Emit(LogEventLevel.Warning, "first warning");
+        Emit(LogEventLevel.Error, "then error");
+
+        // Act
+        var entries = _service.Query(new LogQuery { MinimumLevel = LogEventLevel.Warning });
+
+        // Assert
+        Assert.That(entries.Select(e => e.Message), Is.EqualTo(new[] { "then error", "first warning" }));
+    }
+
+    [Test]
+    public void Query_Should_Filter_By_CorrelationId_And_Tool()
+    {
+        // Arrange
+        Emit(LogEventLevel.Information, "search started", "abc123", "text_search");
+        Emit(LogEventLevel.Information, "other request", "def456", "text_search");
+        Emit(LogEventLevel.Information, "symbol lookup", "abc999", "symbol_search");
+
+        // Act
+        var byCorrelation = _service.Query(new LogQuery { CorrelationId = "abc123" });
+        var byTool = _service.Query(new LogQuery { ToolName = "TEXT_SEARCH" });
+
+        // Assert
+        Assert.That(byCorrelation.Single().Message, Is.EqualTo("search started"));
+        Assert.That(byTool, Has.Count.EqualTo(2));
+    }
+
+    [Test]
+    public void Query_Should_Respect_Since_And_MaxEntries()
+    {
+        // Arrange
+        Emit(LogEventLevel.Information, "old", timestamp: DateTimeOffset.UtcNow.AddHours(-2));
+        for (int i = 0; i < 5; i++)
+            Emit(LogEventLevel.Information, $"recent {i}");
+
+        // Act
+        var entries = _service.Query(new LogQuery { Since = DateTimeOffset.UtcNow.AddHours(-1), MaxEntries = 3 });
+
+        // Assert
+        Assert.That(entries, Has.Count.EqualTo(3));
+        Assert.That(entries.Any(e => e.Message == "old"), Is.False);
+    }
+
+    [Test]
+    public void Sink_Should_Drop_Oldest_Entries_Beyond_Capacity()
+    {
+        // Arrange
+        var sink = new InMemoryLogSink(2);
+        var service = new LogQueryService(sink, new Mock<ILogger<LogQueryService>>().Object);
+
+        // Act
+        foreach (var message in new[] { "one", "two", "three" })
+        {
+            sink.Emit(new LogEvent(DateTimeOffset.UtcNow, LogEventLevel.Information, null,
+                new MessageTemplateParser().Parse(message), Array.Empty<LogEventProperty>()));
+        }
+
+        // Assert
+        Assert.That(service.Query(new LogQuery()).Select(e => e.Message), Is.EqualTo(new[] { "three", "two" }));
+    }
+
+    [Test]
+    public void TryParse_Should_Accept_Microsoft_Level_Names()
+    {
+        // Act & Assert
+        Assert.That(RuntimeLogLevel.TryParse("Trace", out var trace) && trace == LogEventLevel.Verbose, Is.True);
+        Assert.That(RuntimeLogLevel.TryParse("critical", out var critical) && critical == LogEventLevel.Fatal, Is.True);
+        Assert.That(RuntimeLogLevel.TryParse("nonsense", out _), Is.False);
+    }
+}
@@ -0,0 +1,102 @@
+using System.Text.Json;
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class LspProtocolTests
+{
+    private static readonly string FileUri = new Uri(Path.GetFullPath("user_service.go")).AbsoluteUri;
+
+    private static JsonElement Parse(string json) => JsonDocument.Parse(json).RootElement;
+
+    private static string Range(int line, int start, int end) =>
+        $"{{\"start\":{{\"line\":{line},\"character\":{start}}},\"end\":{{\"line\":{line},\"character\":{end}}}}}";
+
+    [Test]
+    public void ParseLocations_Should_Read_Single_Location_Arrays_And_Links()
+    {
+        // Arrange
+        var single = Parse($"{{\"uri\":\"{FileUri}\",\"range\":{Range(4, 5, 16)}}}");
+        var links = Parse($"[{{\"targetUri\":\"{FileUri}\",\"targetRange\":{Range(3, 0, 20)},\"targetSelectionRange\":{Range(4, 5, 16)}}}]");
+
+        // Act
+        var fromSingle = LspProtocol.ParseLocations(single);
+        var fromLinks = LspProtocol.ParseLocations(links);
+
+        // Assert
+        Assert.That(fromSingle, Has.Count.EqualTo(1));
+        Assert.That(fromSingle[0].FilePath, Is.EqualTo(Path.GetFullPath("user_service.go")));
+        Assert.That(fromSingle[0].Range.Start, Is.EqualTo(new LspPosition(4, 5)));
+        Assert.That(fromLinks[0].Range.Start, Is.EqualTo(new LspPosition(4, 5)), "selection range is the name itself");
+    }
+
+    [Test]
+    public void ParseLocations_Should_Return_Empty_For_Null_Result()
+    {
+        // Act & Assert
+        Assert.That(LspProtocol.ParseLocations(Parse("null")), Is.Empty);
+    }
+
+    [Test]
+    public void ParseWorkspaceEdit_Should_Read_Changes_And_DocumentChanges()
+    {
+        // Arrange
+        var changes = Parse($"{{\"changes\":{{\"{FileUri}\":[{{\"range\":{Range(0, 5, 9)},\"newText\":\"Account\"}}]}}}}");
+        var documentChanges = Parse($"{{\"documentChanges\":[{{\"textDocument\":{{\"uri\":\"{FileUri}\",\"version\":2}},\"edits\":[{{\"range\":{Range(0, 5, 9)},\"newText\":\"Account\"}}]}},{{\"kind\":\"rename\",\"oldUri\":\"a\",\"newUri\":\"b\"}}]}}");
+
+        // Act
+        var fromChanges = LspProtocol.ParseWorkspaceEdit(changes);
+        var fromDocumentChanges = LspProtocol.ParseWorkspaceEdit(documentChanges);
+
+        // Assert
+        Assert.That(fromChanges.Single().Value.Single().NewText, Is.EqualTo("Account"));
+        Assert.That(fromDocumentChanges, Has.Count.EqualTo(1), "file operations are ignored");
+        Assert.That(fromDocumentChanges.Single().Value.Single().Range.End, Is.EqualTo(new LspPosition(0, 9)));
+    }
+
+    [Test]
+    public void ApplyEdits_Should_Apply_From_Last_To_First_With_Crlf_And_Utf16_Columns()
+    {
+        // Arrange
+        var text = "type User struct{}\r\nfunc (u User) Name() {}\r\n// 🙂 User\r\n";
+        var edits = new[]
+        {
+            new LspTextEdit(new LspRange(new LspPosition(0, 5), new LspPosition(0, 9)), "Account"),
+            new LspTextEdit(new LspRange(new LspPosition(1, 8), new LspPosition(1, 12)), "Account"),
+            new LspTextEdit(new LspRange(new LspPosition(2, 6), new LspPosition(2, 10)), "Account")
+        };
+
+        // Act
+        var result = LspProtocol.ApplyEdits(text, edits);
+
+        // Assert
+        Assert.That(result, Is.EqualTo("type Account struct{}\r\nfunc (u Account) Name() {}\r\n// 🙂 Account\r\n"));
+    }
+
+    [Test]
+    public void ParseHover_Should_Read_MarkupContent_And_MarkedStrings()
+    {
+        // Arrange
+        var markup = Parse("{\"contents\":{\"kind\":\"markdown\",\"value\":\"```go\\nfunc Find(name string) *User\\n```\"}}");
+        var marked = Parse("{\"contents\":[{\"language\":\"typescript\",\"value\":\"const limit: number\"},\"Maximum users per page\",\"\"]}");
+
+        // Act
+        var fromMarkup = LspProtocol.ParseHover(markup);
+        var fromMarked = LspProtocol.ParseHover(marked);
+
+        // Assert
+        Assert.That(fromMarkup, Is.EqualTo("```go\nfunc Find(name string) *User\n```"));
+        Assert.That(fromMarked, Is.EqualTo("```typescript\nconst limit: number\n```\n\nMaximum users per page"), "empty strings are skipped");
+    }
+
+    [Test]
+    public void ParseHover_Should_Return_Null_When_There_Is_Nothing_To_Show()
+    {
+        // Act & Assert
+        Assert.That(LspProtocol.ParseHover(Parse("null")), Is.Null);
+        Assert.That(LspProtocol.ParseHover(Parse("{\"contents\":\"\"}")), Is.Null);
+        Assert.That(LspProtocol.ParseHover(Parse("{\"contents\":[]}")), Is.Null);
+    }
+}
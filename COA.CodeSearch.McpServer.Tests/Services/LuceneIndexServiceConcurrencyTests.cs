@@ -0,0 +1,206 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Lucene.Net.Documents;
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+using Lucene.Net.Util;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+/// <summary>
+/// Searches lease their reader and the index context without taking the context lock; closing or rebuilding
+/// the index must wait for them, and a force rebuild keeps serving the index as it was until it is refilled.
+/// </summary>
+[TestFixture]
+public class LuceneIndexServiceConcurrencyTests
+{
+    private const string WorkspaceHash = "test-hash";
+
+    private string _workspace = null!;
+    private LuceneIndexService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "codesearch-concurrency-test", Guid.NewGuid().ToString());
+        System.IO.Directory.CreateDirectory(_workspace);
+
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:Lucene:UseRamDirectory"] = "true" })
+            .Build();
+        var pathResolution = new Mock<IPathResolutionService>();
+        pathResolution.Setup(p => p.ComputeWorkspaceHash(It.IsAny<string>())).Returns(WorkspaceHash);
+        pathResolution.Setup(p => p.GetLuceneIndexPath(It.IsAny<string>())).Returns(Path.Combine(_workspace, ".coa", "lucene"));
+        var codeAnalyzer = new CodeAnalyzer(LuceneVersion.LUCENE_48);
+
+        _service = new LuceneIndexService(
+            NullLogger<LuceneIndexService>.Instance,
+            configuration,
+            pathResolution.Object,
+            new CircuitBreakerService(NullLogger<CircuitBreakerService>.Instance, configuration),
+            new Mock<IMemoryPressureService>().Object,
+            new LineAwareSearchService(NullLogger<LineAwareSearchService>.Instance),
+            new SmartSnippetService(NullLogger<SmartSnippetService>.Instance, codeAnalyzer),
+            new Mock<IWriteLockManager>().Object,
+            codeAnalyzer);
+    }
+
+    [TearDown]
+    public async Task TearDown()
+    {
+        await _service.DisposeAsync();
+        try
+        {
+            if (System.IO.Directory.Exists(_workspace))
+                System.IO.Directory.Delete(_workspace, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private async Task IndexAsync(params string[] paths)
+    {
+        await _service.IndexDocumentsAsync(_workspace, paths.Select(path => new Document
+        {
+            new StringField("path", path, Field.Store.YES),
+            new TextField("content", $"class {Path.GetFileNameWithoutExtension(path)} {{ }}", Field.Store.YES)
+        }));
+        await _service.CommitAsync(_workspace);
+    }
+
+    private async Task<List<string>> SearchAllAsync()
+    {
+        var result = await _service.SearchAsync(_workspace, new MatchAllDocsQuery(), 100);
+        return result.Hits.Select(h => h.FilePath).OrderBy(p => p).ToList();
+    }
+
+    [Test]
+    public async Task CloseIndexAsync_Should_Wait_For_A_Running_Search()
+    {
+        // Arrange - a search that has leased its reader and is held inside the query
+        await IndexAsync("a.cs");
+        var query = new BlockingQuery(new TermQuery(new Term("path", "a.cs")));
+        var search = Task.Run(() => _service.SearchAsync(_workspace, query, 10));
+        Assert.That(query.Entered.Wait(TimeSpan.FromSeconds(10)), Is.True);
+
+        // Act
+        var close = Task.Run(() => _service.CloseIndexAsync(WorkspaceHash));
+        await Task.Delay(300);
+        var closedUnderSearch = close.IsCompleted;
+        query.Release.Set();
+
+        // Assert
+        Assert.That(closedUnderSearch, Is.False, "The index is not disposed under a running search");
+        var result = await search;
+        Assert.That(result.Hits.Select(h => h.FilePath), Is.EqualTo(new[] { "a.cs" }));
+        Assert.That(await close, Is.True);
+    }
+
+    [Test]
+    public async Task ForceRebuildIndexAsync_Should_Serve_The_Previous_Index_Until_Retired()
+    {
+        // Arrange
+        await IndexAsync("a.cs", "b.cs");
+
+        // Act - the rebuilt index is refilled with one file only
+        await _service.ForceRebuildIndexAsync(_workspace);
+        var duringRebuild = await SearchAllAsync();
+        await IndexAsync("c.cs");
+        var beforeRetire = await SearchAllAsync();
+        var retired = await _service.RetirePreviousFormatAsync(_workspace);
+        var afterRetire = await SearchAllAsync();
+
+        // Assert - stored fields are still read from the old directory the snapshot owns
+        Assert.That(duringRebuild, Is.EqualTo(new[] { "a.cs", "b.cs" }));
+        Assert.That(beforeRetire, Is.EqualTo(new[] { "a.cs", "b.cs" }));
+        Assert.That(retired, Is.True);
+        Assert.That(afterRetire, Is.EqualTo(new[] { "c.cs" }));
+    }
+
+    [Test]
+    public async Task ForceRebuildIndexAsync_Should_Keep_A_Search_Held_Across_It_Working()
+    {
+        // Arrange - a search leases the old index's reader, then the index is rebuilt under it
+        await IndexAsync("a.cs", "b.cs");
+        var query = new BlockingQuery(new MatchAllDocsQuery());
+        var search = Task.Run(() => _service.SearchAsync(_workspace, query, 10));
+        Assert.That(query.Entered.Wait(TimeSpan.FromSeconds(10)), Is.True);
+
+        // Act
+        var rebuild = Task.Run(() => _service.ForceRebuildIndexAsync(_workspace));
+        await Task.Delay(300);
+        query.Release.Set();
+        var result = await search;
+        await rebuild;
+
+        // Assert
+        Assert.That(result.Hits.Select(h => h.FilePath).OrderBy(p => p), Is.EqualTo(new[] { "a.cs", "b.cs" }));
+        Assert.That(await SearchAllAsync(), Is.EqualTo(new[] { "a.cs", "b.cs" }), "The snapshot is served after the rebuild");
+    }
+
+    [Test]
+    public async Task SearchAsync_Should_Not_Fail_While_The_Index_Is_Rebuilt_And_Closed_Repeatedly()
+    {
+        // Arrange
+        await IndexAsync("a.cs", "b.cs");
+        using var stop = new CancellationTokenSource();
+        var searches = Enumerable.Range(0, 4).Select(_ => Task.Run(async () =>
+        {
+            var results = new List<int>();
+            while (!stop.IsCancellationRequested)
+            {
+                results.Add((await _service.SearchAsync(_workspace, new MatchAllDocsQuery(), 10)).TotalHits);
+            }
+            return results;
+        })).ToList();
+
+        // Act
+        for (var i = 0; i < 5; i++)
+        {
+            await _service.ForceRebuildIndexAsync(_workspace);
+            await Task.Delay(20);
+        }
+        await _service.CloseIndexAsync(WorkspaceHash);
+        await IndexAsync("a.cs", "b.cs");
+        await Task.Delay(20);
+        stop.Cancel();
+        var counts = (await Task.WhenAll(searches)).SelectMany(c => c).ToList();
+
+        // Assert - searches saw the two files throughout the rebuilds (from the snapshot), and after the close either
+        // the reopened RAM index before it was refilled or the two files again
+        Assert.That(counts, Is.Not.Empty);
+        Assert.That(counts.Where(c => c != 2 && c != 0), Is.Empty);
+    }
+
+    /// <summary>
+    /// Blocks the search that runs it until released, after its reader has been leased
+    /// </summary>
+    private sealed class BlockingQuery : Query
+    {
+        private readonly Query _inner;
+
+        public BlockingQuery(Query inner)
+        {
+            _inner = inner;
+        }
+
+        public ManualResetEventSlim Entered { get; } = new();
+        public ManualResetEventSlim Release { get; } = new();
+
+        public override Weight CreateWeight(IndexSearcher searcher)
+        {
+            Entered.Set();
+            Release.Wait(TimeSpan.FromSeconds(30));
+            return _inner.CreateWeight(searcher);
+        }
+
+        public override string ToString(string field) => _inner.ToString(field);
+    }
+}
@@ -0,0 +1,129 @@
+using NUnit.Framework;
+using System.IO.Pipes;
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Elicitation;
+using COA.CodeSearch.McpServer.Services.Sampling;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class McpClientRequestChannelTests
+{
+    private const string Initialize =
+        "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"initialize\",\"params\":{\"capabilities\":{\"sampling\":{},\"elicitation\":{}}}}";
+    private const string ToolsList = "{\"jsonrpc\":\"2.0\",\"id\":2,\"method\":\"tools/list\"}";
+    private const string ToolsCall = "{\"jsonrpc\":\"2.0\",\"id\":3,\"method\":\"tools/call\",\"params\":{\"name\":\"text_search\"}}";
+
+    private static IConfiguration CreateConfiguration() => new ConfigurationBuilder()
+        .AddInMemoryCollection(new Dictionary<string, string?>
+        {
+            ["CodeSearch:Sampling:Enabled"] = "true",
+            ["CodeSearch:Elicitation:Enabled"] = "true"
+        })
+        .Build();
+
+    private static List<(string Id, string Method)> ReadRequests(string output) => output
+        .Split('\n', StringSplitOptions.RemoveEmptyEntries)
+        .Select(line => JsonDocument.Parse(line).RootElement)
+        .Select(root => (root.GetProperty("id").GetString()!, root.GetProperty("method").GetString()!))
+        .ToList();
+
+    private static async Task WaitForRequestsAsync(StringWriter output, int count)
+    {
+        for (var attempt = 0; attempt < 100 && ReadRequests(output.ToString()).Count < count; attempt++)
+        {
+            await Task.Delay(20);
+        }
+    }
+
+    [Test]
+    public async Task Channel_Should_Route_Responses_Over_A_Stdio_Pipe_While_The_Transport_Keeps_Reading()
+    {
+        // Arrange - the client writes into a real pipe and the transport reads the wrapped end line by line
+        var output = new StringWriter();
+        var channel = new McpClientRequestChannel(NullLogger<McpClientRequestChannel>.Instance, output);
+        var sampling = new McpSamplingClient(CreateConfiguration(), channel);
+        var elicitation = new McpElicitationClient(CreateConfiguration(), channel, NullLogger<McpElicitationClient>.Instance);
+
+        using var clientOut = new AnonymousPipeServerStream(PipeDirection.Out);
+        using var serverIn = new AnonymousPipeClientStream(PipeDirection.In, clientOut.ClientSafePipeHandle);
+        using var client = new StreamWriter(clientOut) { AutoFlush = true };
+        using var transportInput = channel.Attach(new StreamReader(serverIn));
+
+        var received = new List<string>();
+        var transport = Task.Run(async () =>
+        {
+            string? line;
+            while ((line = await transportInput.ReadLineAsync(CancellationToken.None)) != null)
+            {
+                received.Add(line);
+            }
+        });
+
+        // Act
+        await client.WriteLineAsync(Initialize);
+        for (var attempt = 0; attempt < 100 && !sampling.IsAvailable; attempt++)
+        {
+            await Task.Delay(20);
+        }
+
+        var samplingTask = sampling.CreateMessageAsync(new SamplingRequest { UserMessage = "rank these" });
+        var elicitationTask = elicitation.ChooseAsync(new ElicitationRequest
+        {
+            Message = "Which Helper?",
+            Options = new List<ElicitationOption> { new() { Value = "1", Label = "A" }, new() { Value = "2", Label = "B" } }
+        });
+        await WaitForRequestsAsync(output, 2);
+        var requests = ReadRequests(output.ToString()).ToDictionary(r => r.Method, r => r.Id);
+
+        // The client answers out of order, with its own requests in between
+        await client.WriteLineAsync(ToolsList);
+        await client.WriteLineAsync(
+            $"{{\"jsonrpc\":\"2.0\",\"id\":\"{requests["elicitation/create"]}\",\"result\":{{\"action\":\"accept\",\"content\":{{\"choice\":\"2\"}}}}}}");
+        await client.WriteLineAsync(
+            $"{{\"jsonrpc\":\"2.0\",\"id\":\"{requests["sampling/createMessage"]}\",\"result\":{{\"content\":{{\"type\":\"text\",\"text\":\"[2,1]\"}}}}}}");
+        await client.WriteLineAsync(ToolsCall);
+        var samplingResponse = await samplingTask;
+        var elicitationResult = await elicitationTask;
+        client.Dispose(); // end of stdin
+        await transport;
+
+        // Assert
+        Assert.That(requests.Values.Distinct().Count(), Is.EqualTo(2), "one id sequence for both clients");
+        Assert.That(samplingResponse.Text, Is.EqualTo("[2,1]"));
+        Assert.That(elicitationResult.Value, Is.EqualTo("2"));
+        Assert.That(received, Is.EqualTo(new[] { Initialize, ToolsList, ToolsCall }), "the transport sees only client messages");
+    }
+
+    [Test]
+    public void SendRequestAsync_Should_Time_Out_And_Swallow_A_Late_Answer()
+    {
+        // Arrange
+        var output = new StringWriter();
+        var channel = new McpClientRequestChannel(NullLogger<McpClientRequestChannel>.Instance, output);
+
+        // Act & Assert
+        Assert.ThrowsAsync<TimeoutException>(() =>
+            channel.SendRequestAsync("sampling/createMessage", new { }, TimeSpan.FromMilliseconds(50)));
+        var id = ReadRequests(output.ToString()).Single().Id;
+        Assert.That(channel.TryHandleInbound($"{{\"jsonrpc\":\"2.0\",\"id\":\"{id}\",\"result\":{{}}}}"), Is.True);
+    }
+
+    [Test]
+    public void ClientSupports_Should_Be_False_Until_Initialize_Declares_The_Capability()
+    {
+        // Arrange
+        var channel = new McpClientRequestChannel(NullLogger<McpClientRequestChannel>.Instance, new StringWriter());
+        Assert.That(channel.ClientSupports("sampling"), Is.False);
+
+        // Act
+        channel.TryHandleInbound("{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"initialize\",\"params\":{\"capabilities\":{\"elicitation\":{}}}}");
+
+        // Assert
+        Assert.That(channel.ClientSupports("elicitation"), Is.True);
+        Assert.That(channel.ClientSupports("sampling"), Is.False);
+    }
+}
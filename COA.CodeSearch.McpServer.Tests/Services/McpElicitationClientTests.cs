@@ -0,0 +1,100 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Elicitation;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class McpElicitationClientTests
+{
+    private const string Initialize = "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"initialize\",\"params\":{\"capabilities\":{\"elicitation\":{}}}}";
+
+    private StringWriter _output = null!;
+    private McpClientRequestChannel _channel = null!;
+    private McpElicitationClient _client = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:Elicitation:Enabled"] = "true" })
+            .Build();
+        _output = new StringWriter();
+        _channel = new McpClientRequestChannel(NullLogger<McpClientRequestChannel>.Instance, _output);
+        _client = new McpElicitationClient(configuration, _channel, NullLogger<McpElicitationClient>.Instance);
+    }
+
+    private static ElicitationRequest CreateRequest() => new()
+    {
+        Message = "Which Helper?",
+        Options = new List<ElicitationOption>
+        {
+            new() { Value = "1", Label = "class Helper - src/A.cs:3" },
+            new() { Value = "2", Label = "class Helper - src/B.cs:9" }
+        }
+    };
+
+    [Test]
+    public async Task ChooseAsync_Should_Round_Trip_Choice_Through_Intercepted_Stdin()
+    {
+        // Arrange
+        Assert.That(_channel.TryHandleInbound(Initialize), Is.False, "initialize must still reach the transport");
+        Assert.That(_client.IsAvailable, Is.True);
+
+        // Act
+        var pending = _client.ChooseAsync(CreateRequest());
+        Assert.That(_output.ToString(), Does.Contain("\"method\":\"elicitation/create\""));
+        Assert.That(_output.ToString(), Does.Contain("\"enumNames\":[\"class Helper - src/A.cs:3\""));
+        var consumed = _channel.TryHandleInbound(
+            "{\"jsonrpc\":\"2.0\",\"id\":\"codesearch-request-1\",\"result\":{\"action\":\"accept\",\"content\":{\"choice\":\"2\"}}}");
+        var result = await pending;
+
+        // Assert
+        Assert.That(consumed, Is.True);
+        Assert.That(result.Accepted, Is.True);
+        Assert.That(result.Value, Is.EqualTo("2"));
+    }
+
+    [TestCase("{\"action\":\"decline\"}")]
+    [TestCase("{\"action\":\"accept\",\"content\":{\"choice\":\"7\"}}")]
+    public async Task ChooseAsync_Should_Not_Accept_Declined_Or_Unknown_Answers(string resultJson)
+    {
+        // Arrange
+        _channel.TryHandleInbound(Initialize);
+
+        // Act
+        var pending = _client.ChooseAsync(CreateRequest());
+        _channel.TryHandleInbound($"{{\"jsonrpc\":\"2.0\",\"id\":\"codesearch-request-1\",\"result\":{resultJson}}}");
+        var result = await pending;
+
+        // Assert
+        Assert.That(result.Accepted, Is.False);
+    }
+
+    [Test]
+    public void ChooseAsync_Should_Throw_When_Client_Lacks_Capability()
+    {
+        // Arrange
+        _channel.TryHandleInbound("{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"initialize\",\"params\":{\"capabilities\":{}}}");
+
+        // Act & Assert
+        Assert.That(_client.IsAvailable, Is.False);
+        Assert.ThrowsAsync<ElicitationException>(() => _client.ChooseAsync(CreateRequest()));
+    }
+
+    [Test]
+    public void ClientResponseReader_Should_Pass_Other_Lines_Through()
+    {
+        // Arrange
+        _channel.TryHandleInbound(Initialize);
+        var reader = new ClientResponseReader(
+            new StringReader("{\"id\":\"codesearch-request-99\",\"result\":{}}\n{\"jsonrpc\":\"2.0\",\"id\":2,\"method\":\"tools/list\"}\n"),
+            _channel.TryHandleInbound);
+
+        // Act & Assert
+        Assert.That(reader.ReadLine(), Is.EqualTo("{\"jsonrpc\":\"2.0\",\"id\":2,\"method\":\"tools/list\"}"));
+        Assert.That(reader.ReadLine(), Is.Null);
+    }
+}
@@ -0,0 +1,108 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class MessageContractsTests
+{
+    [Test]
+    public void Link_Should_Pair_Producers_And_Consumers_Across_Languages()
+    {
+        // Arrange
+        var constants = new Dictionary<string, string?>();
+        var topics = @"namespace Shop;
+public static class Topics
+{
+    public const string OrderPlaced = ""orders.placed"";
+}".Split('\n');
+        MessageContracts.ReadConstants(topics, constants);
+
+        var publisher = @"using Confluent.Kafka;
+public class OrderService
+{
+    public async Task PlaceAsync(Order order)
+    {
+        await _producer.ProduceAsync(Topics.OrderPlaced, new Message<string, string> { Value = order.Id });
+        // _producer.Produce(""orders.legacy"", message);
+        await _producer.ProduceAsync(""orders.cancelled"", message);
+        await _bus.Subscribe(x => Handle(x));
+    }
+}".Split('\n');
+        var consumer = @"package billing
+
+import ""github.com/segmentio/kafka-go""
+
+func newReader() *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers: brokers,
+		Topic:   ""orders.placed"",
+	})
+}".Split('\n');
+        var audit = @"import { connect } from 'nats';
+nc.subscribe('orders.*', { callback: audit });
+emitter.on('error', onError);".Split('\n');
+
+        var sites = MessageContracts.Scan("src/OrderService.cs", publisher)
+            .Concat(MessageContracts.Scan("billing/reader.go", consumer))
+            .Concat(MessageContracts.Scan("audit/index.ts", audit))
+            .ToList();
+
+        // Act
+        var flows = MessageContracts.Link(sites, constants);
+
+        // Assert
+        Assert.That(flows.Select(f => f.Channel), Is.EqualTo(new[] { "orders.cancelled", "orders.placed" }));
+        var placed = flows[1];
+        Assert.That(placed.Status, Is.EqualTo("linked"));
+        Assert.That(placed.Producers.Single().Line, Is.EqualTo(6));
+        Assert.That(placed.Consumers.Select(c => $"{c.Broker}:{c.FilePath}:{c.Line}"),
+            Is.EquivalentTo(new[] { "kafka:billing/reader.go:8", "nats:audit/index.ts:2" }));
+        Assert.That(placed.Brokers, Is.EquivalentTo(new[] { "kafka", "nats" }));
+        Assert.That(flows[0].Status, Is.EqualTo("linked"));
+    }
+
+    [Test]
+    public void Link_Should_Follow_Queue_Bindings_And_Message_Types()
+    {
+        // Arrange
+        var rabbit = @"import pika
+channel.queue_bind(queue='invoices', exchange='billing', routing_key='invoice.created')
+channel.basic_publish(exchange='billing', routing_key='invoice.created', body=payload)
+channel.basic_consume(queue='invoices', on_message_callback=handle)
+channel.basic_publish(exchange='', routing_key=REFUNDS, body=payload)".Split('\n');
+        var handlers = @"using MassTransit;
+public class ShipOrderConsumer : IConsumer<OrderPaid>
+{
+}
+public class Checkout
+{
+    public Task Pay() => _publishEndpoint.Publish(new OrderPaid(orderId));
+    public Task Notify() => _publishEndpoint.Publish<OrderRefunded>(new { OrderId = orderId });
+}".Split('\n');
+        var sites = MessageContracts.Scan("billing/worker.py", rabbit)
+            .Concat(MessageContracts.Scan("Shipping/Checkout.cs", handlers))
+            .ToList();
+        var unresolved = new List<MessageSite>();
+
+        // Act
+        var flows = MessageContracts.Link(sites, new Dictionary<string, string?>(), unresolved);
+
+        // Assert
+        var invoice = flows.Single(f => f.Channel == "invoice.created");
+        Assert.That(invoice.Status, Is.EqualTo("linked"));
+        Assert.That(invoice.Consumers.Single().Via, Is.EqualTo("invoices"));
+        Assert.That(flows.Any(f => f.Channel == "invoices"), Is.False);
+
+        var paid = flows.Single(f => f.Channel == "OrderPaid");
+        Assert.That(paid.Kind, Is.EqualTo("type"));
+        Assert.That(paid.Status, Is.EqualTo("linked"));
+        Assert.That(paid.Brokers, Is.EqualTo(new[] { "masstransit" }));
+        Assert.That(flows.Single(f => f.Channel == "OrderRefunded").Status, Is.EqualTo("no-consumer"));
+
+        Assert.That(unresolved.Select(s => s.Channel), Is.EqualTo(new[] { "REFUNDS" }));
+        Assert.That(MessageContracts.MatchesPattern("orders.>", "orders.eu.placed"), Is.True);
+        Assert.That(MessageContracts.MatchesPattern("orders.#", "orders"), Is.True);
+        Assert.That(MessageContracts.MatchesPattern("orders.*", "orders.eu.placed"), Is.False);
+    }
+}
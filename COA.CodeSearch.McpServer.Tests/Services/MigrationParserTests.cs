@@ -0,0 +1,127 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Migrations;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class MigrationParserTests
+{
+    [Test]
+    public void BuildSchema_Should_Replay_Sql_Migrations_In_Version_Order()
+    {
+        // Arrange
+        var files = new Dictionary<string, string>
+        {
+            ["db/migrations/20240110090000_add_email.sql"] = @"-- +goose Up
+ALTER TABLE users ADD COLUMN email varchar(255) NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX users_email_idx ON users (email);
+ALTER TABLE users RENAME COLUMN name TO display_name, DROP COLUMN legacy;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN email;",
+            ["db/migrations/20240101120000_init.sql"] = @"-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ""public"".""users"" (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL, -- shown in the UI
+    legacy int,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+CREATE TABLE audit_log (id int, body text);
+
+-- +goose Down
+DROP TABLE users;",
+            ["db/migrations/20240201000000_drop_audit.sql"] = "-- +goose Up\nDROP TABLE IF EXISTS audit_log;\n",
+            ["db/migrations/20240201000000_drop_audit.down.sql"] = "CREATE TABLE audit_log (id int);"
+        };
+
+        // Act
+        var migrations = new List<MigrationFile>();
+        foreach (var (path, content) in files)
+        {
+            if (MigrationParser.Identify(path, content) is { } migration)
+            {
+                MigrationParser.ReadChanges(migration, content.Split('\n'));
+                migrations.Add(migration);
+            }
+        }
+        var ordered = migrations.OrderBy(m => m.Version, Comparer<string>.Create(MigrationParser.CompareVersions)).ToList();
+        var schema = MigrationParser.BuildSchema(ordered);
+
+        // Assert
+        Assert.That(ordered.Select(m => m.Name), Is.EqualTo(new[] { "init", "add_email", "drop_audit" }));
+        Assert.That(ordered[0].Changes.Select(c => $"{c.Action} {c.Table}@{c.Line}"), Is.EqualTo(new[] { "create-table users@3", "create-table audit_log@10" }));
+
+        var users = schema.Single();
+        Assert.That(users.Name, Is.EqualTo("users"));
+        Assert.That(users.Columns.Select(c => c.Name), Is.EqualTo(new[] { "id", "display_name", "created_at", "email" }));
+        Assert.That(users.Columns[0].PrimaryKey, Is.True);
+        Assert.That(users.Columns[2].Type, Is.EqualTo("timestamptz"));
+        Assert.That(users.Columns[2].Default, Is.EqualTo("now()"));
+        Assert.That(users.Columns[3].Nullable, Is.False);
+        Assert.That(users.Columns[3].AddedIn, Is.EqualTo("20240110090000"));
+        Assert.That(users.Indexes.Single().Unique, Is.True);
+        Assert.That(users.CreatedIn, Is.EqualTo("20240101120000"));
+        Assert.That(users.LastChangedIn, Is.EqualTo("20240110090000"));
+    }
+
+    [Test]
+    public void ReadChanges_Should_Read_EF_Core_Migration_Operations()
+    {
+        // Arrange
+        var path = "src/Data/Migrations/20240301101500_AddOrders.cs";
+        var content = @"using Microsoft.EntityFrameworkCore.Migrations;
+
+public partial class AddOrders : Migration
+{
+    protected override void Up(MigrationBuilder migrationBuilder)
+    {
+        migrationBuilder.CreateTable(
+            name: ""Orders"",
+            columns: table => new
+            {
+                Id = table.Column<int>(type: ""int"", nullable: false)
+                    .Annotation(""SqlServer:Identity"", ""1, 1""),
+                Total = table.Column<decimal>(type: ""decimal(18,2)"", nullable: false),
+                Note = table.Column<string>(name: ""note_text"", type: ""nvarchar(max)"", nullable: true)
+            },
+            constraints: table =>
+            {
+                table.PrimaryKey(""PK_Orders"", x => x.Id);
+            });
+
+        migrationBuilder.AddColumn<string>(
+            name: ""Status"",
+            table: ""Orders"",
+            type: ""nvarchar(20)"",
+            nullable: false,
+            defaultValue: """");
+
+        migrationBuilder.Sql(@""CREATE INDEX IX_Orders_Status ON Orders (Status)"");
+    }
+
+    protected override void Down(MigrationBuilder migrationBuilder)
+    {
+        migrationBuilder.DropTable(name: ""Orders"");
+    }
+}";
+
+        // Act
+        var migration = MigrationParser.Identify(path, content);
+        MigrationParser.ReadChanges(migration!, content.Split('\n'));
+        var orders = MigrationParser.BuildSchema(new[] { migration! }).Single();
+
+        // Assert
+        Assert.That(migration!.Tool, Is.EqualTo("ef-core"));
+        Assert.That(migration.Version, Is.EqualTo("20240301101500"));
+        Assert.That(migration.Name, Is.EqualTo("AddOrders"));
+        Assert.That(migration.Changes.Select(c => $"{c.Action}@{c.Line}"), Is.EqualTo(new[] { "create-table@7", "add-column@21", "create-index@28" }));
+        Assert.That(orders.Columns.Select(c => $"{c.Name} {c.Type}"),
+            Is.EqualTo(new[] { "Id int", "Total decimal(18,2)", "note_text nvarchar(max)", "Status nvarchar(20)" }));
+        Assert.That(orders.Columns[0].PrimaryKey, Is.True);
+        Assert.That(orders.Indexes.Single().Columns, Is.EqualTo(new[] { "Status" }));
+        Assert.That(MigrationParser.CompareVersions("1.10", "1.9"), Is.GreaterThan(0));
+        Assert.That(MigrationParser.Identify("db/V1_2__orders.sql", "create table orders (id int);")!.Version, Is.EqualTo("1.2"));
+    }
+}
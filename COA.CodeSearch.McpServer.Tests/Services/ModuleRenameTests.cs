@@ -0,0 +1,95 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ModuleRenameTests
+{
+    [Test]
+    public void Rename_Should_Rename_Go_Module_Directive_Imports_And_Config()
+    {
+        // Arrange
+        var goMod = "module github.com/acme/old\n\ngo 1.22\n\nrequire github.com/acme/old-tools v0.3.0\n";
+        var source = "package main\n\nimport (\n\t\"fmt\"\n\t\"github.com/acme/old/internal/api\"\n)\n";
+        var config = "builds:\n  - main: ./cmd/server\n    ldflags: -X github.com/acme/old/internal/version.Version={{.Version}}\n" +
+                     "homepage: https://github.com/acme/old\n";
+
+        // Act
+        var (mod, modRewrites) = ModuleRename.Rename("go.mod", goMod, "github.com/acme/old", "github.com/acme/platform");
+        var (code, codeRewrites) = ModuleRename.Rename("cmd/server/main.go", source, "github.com/acme/old", "github.com/acme/platform");
+        var (yaml, yamlRewrites) = ModuleRename.Rename(".goreleaser.yaml", config, "github.com/acme/old", "github.com/acme/platform");
+
+        // Assert - old-tools is another module
+        Assert.That(modRewrites.Select(r => r.Kind), Is.EqualTo(new[] { "go-module" }));
+        Assert.That(mod, Does.StartWith("module github.com/acme/platform\n"));
+        Assert.That(mod, Does.Contain("require github.com/acme/old-tools v0.3.0"));
+        Assert.That(codeRewrites.Select(r => r.Kind), Is.EqualTo(new[] { "go-import" }));
+        Assert.That(code, Does.Contain("\"github.com/acme/platform/internal/api\""));
+        Assert.That(yamlRewrites.Select(r => r.Line), Is.EqualTo(new[] { 3, 4 }));
+        Assert.That(yaml, Does.Contain("-X github.com/acme/platform/internal/version.Version"));
+        Assert.That(yaml, Does.Contain("https://github.com/acme/platform"));
+    }
+
+    [Test]
+    public void Rename_Should_Rename_CSharp_Namespaces_And_Qualified_Names_Outside_Strings()
+    {
+        // Arrange
+        var source = string.Join("\n",
+            "using Acme.Old.Orders;",
+            "using Acme.Older;",
+            "",
+            "namespace Acme.Old.Api;",
+            "",
+            "// Replaces Acme.Old.Legacy",
+            "public class OrdersController",
+            "{",
+            "    private readonly global::Acme.Old.Billing.IInvoicer _invoicer;",
+            "    private const string Source = \"Acme.Old.Api\";",
+            "}");
+
+        // Act
+        var (content, rewrites) = ModuleRename.Rename("src/Api/OrdersController.cs", source, "Acme.Old", "Acme.Core");
+
+        // Assert
+        Assert.That(rewrites.Select(r => (r.Line, r.Kind)), Is.EqualTo(new[] { (1, "using"), (4, "namespace"), (9, "qualified-name") }));
+        Assert.That(content, Does.Contain("using Acme.Core.Orders;"));
+        Assert.That(content, Does.Contain("using Acme.Older;"));
+        Assert.That(content, Does.Contain("namespace Acme.Core.Api;"));
+        Assert.That(content, Does.Contain("global::Acme.Core.Billing.IInvoicer"));
+        Assert.That(content, Does.Contain("// Replaces Acme.Old.Legacy"));
+        Assert.That(content, Does.Contain("\"Acme.Old.Api\""));
+    }
+
+    [Test]
+    public void Rename_Should_Rename_Project_Properties_References_And_AppSettings()
+    {
+        // Arrange
+        var project = string.Join("\r\n",
+            "<Project Sdk=\"Microsoft.NET.Sdk\">",
+            "  <PropertyGroup>",
+            "    <RootNamespace>Acme.Old</RootNamespace>",
+            "    <AssemblyName>Acme.Old.Api</AssemblyName>",
+            "  </PropertyGroup>",
+            "  <ItemGroup>",
+            "    <ProjectReference Include=\"..\\Acme.Old.Data\\Acme.Old.Data.csproj\" />",
+            "    <InternalsVisibleTo Include=\"Acme.Old.Api.Tests\" />",
+            "  </ItemGroup>",
+            "</Project>");
+        var settings = "{\n  \"Logging\": {\n    \"LogLevel\": {\n      \"Acme.Old\": \"Debug\",\n      \"Acme.OldStuff\": \"Warning\"\n    }\n  }\n}\n";
+
+        // Act
+        var (csproj, projectRewrites) = ModuleRename.Rename("src/Acme.Old.Api/Acme.Old.Api.csproj", project, "Acme.Old", "Acme.Core");
+        var (json, jsonRewrites) = ModuleRename.Rename("src/Acme.Old.Api/appsettings.json", settings, "Acme.Old", "Acme.Core");
+
+        // Assert
+        Assert.That(projectRewrites.Select(r => r.Kind), Is.EqualTo(new[] { "root-namespace", "project", "project", "project" }));
+        Assert.That(csproj, Does.Contain("<RootNamespace>Acme.Core</RootNamespace>\r\n"));
+        Assert.That(csproj, Does.Contain("<AssemblyName>Acme.Core.Api</AssemblyName>"));
+        Assert.That(csproj, Does.Contain("..\\Acme.Core.Data\\Acme.Core.Data.csproj"));
+        Assert.That(csproj, Does.Contain("<InternalsVisibleTo Include=\"Acme.Core.Api.Tests\" />"));
+        Assert.That(jsonRewrites.Select(r => r.Line), Is.EqualTo(new[] { 4 }));
+        Assert.That(json, Does.Contain("\"Acme.Core\": \"Debug\""));
+        Assert.That(json, Does.Contain("\"Acme.OldStuff\": \"Warning\""));
+    }
+}
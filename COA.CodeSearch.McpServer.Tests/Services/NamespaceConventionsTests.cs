@@ -0,0 +1,87 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class NamespaceConventionsTests
+{
+    private static readonly (string Path, string? Content)[] Workspace =
+    {
+        ("src/Acme.Api/Acme.Api.csproj", "<Project Sdk=\"Microsoft.NET.Sdk.Web\">\n</Project>\n"),
+        ("src/Acme.Api/Orders/OrderService.cs", "namespace Acme.Api.Orders;\n\npublic class OrderService\n{\n}\n"),
+        ("src/Acme.Api/Orders/OrderValidator.cs", "namespace Acme.Api.Services;\n\npublic class OrderValidator\n{\n    public bool Check(Order o) => true;\n}\n"),
+        ("src/Acme.Api/Services/Order.cs", "namespace Acme.Api.Services;\n\npublic record Order(int Id);\n"),
+        ("src/Acme.Api/Controllers/OrdersController.cs",
+            "using Acme.Api.Services;\n\nnamespace Acme.Api.Controllers;\n\npublic class OrdersController\n{\n    private readonly OrderValidator _validator = new();\n    private Acme.Api.Services.OrderValidator? _other;\n}\n"),
+        ("internal/billing/invoice.go", "package invoices\n\nfunc Total() int { return 0 }\n"),
+        ("internal/billing/invoice_test.go", "package invoices_test\n\nimport \"testing\"\n\nfunc TestTotal(t *testing.T) {}\n"),
+        ("internal/go-store/store.go", "package store\n"),
+        ("cmd/shop/main.go", "package main\n\nimport (\n\t\"github.com/acme/shop/internal/billing\"\n)\n\nfunc main() {\n\t_ = invoices.Total()\n}\n"),
+        ("app/src/main/java/com/acme/shop/Cart.java", "package com.acme.cart;\n\npublic class Cart {\n}\n"),
+        ("app/src/main/kotlin/shop/Basket.kt", "package com.acme.shop\n\nclass Basket\n")
+    };
+
+    [Test]
+    public void Check_Should_Compare_Declarations_With_Directories()
+    {
+        // Act
+        var violations = NamespaceConventions.Check(Workspace, new NamespaceConventionSettings());
+
+        // Assert
+        Assert.That(violations.Select(v => $"{v.FilePath}:{v.Line} {v.Declared} -> {v.Expected}"), Is.EqualTo(new[]
+        {
+            "app/src/main/java/com/acme/shop/Cart.java:1 com.acme.cart -> com.acme.shop",
+            "internal/billing/invoice.go:1 invoices -> billing",
+            "internal/billing/invoice_test.go:1 invoices_test -> billing_test",
+            "src/Acme.Api/Orders/OrderValidator.cs:1 Acme.Api.Services -> Acme.Api.Orders"
+        }));
+        Assert.That(violations.Last().Convention, Does.Contain("root namespace Acme.Api (Acme.Api.csproj)"));
+    }
+
+    [Test]
+    public void Fix_Should_Move_Declarations_And_The_References_To_Them()
+    {
+        // Arrange
+        var violations = NamespaceConventions.Check(Workspace, new NamespaceConventionSettings());
+
+        // Act
+        var edits = NamespaceConventions.Fix(Workspace, violations);
+
+        // Assert
+        Assert.That(edits.Select(e => $"{e.FilePath}:{e.Line} {e.NewText.Trim()}"), Is.EqualTo(new[]
+        {
+            "app/src/main/java/com/acme/shop/Cart.java:1 package com.acme.shop;",
+            "cmd/shop/main.go:8 _ = billing.Total()",
+            "internal/billing/invoice.go:1 package billing",
+            "internal/billing/invoice_test.go:1 package billing_test",
+            "src/Acme.Api/Controllers/OrdersController.cs:1 using Acme.Api.Services;\nusing Acme.Api.Orders;",
+            "src/Acme.Api/Controllers/OrdersController.cs:8 private Acme.Api.Orders.OrderValidator? _other;",
+            "src/Acme.Api/Orders/OrderValidator.cs:1 using Acme.Api.Services;\nnamespace Acme.Api.Orders;"
+        }));
+    }
+
+    [Test]
+    public void Check_Should_Honor_Configured_Roots_And_Ignores()
+    {
+        // Arrange
+        var files = new (string Path, string? Content)[]
+        {
+            ("src/Legacy/Old.cs", "namespace Something.Else { class Old { } }\n"),
+            ("src/Web/Pages/Index.cs", "namespace Shop.Web.Pages { class Index { } }\n"),
+            ("lib/java/net/shop/Util.java", "package net.tools;\n")
+        };
+        var settings = new NamespaceConventionSettings
+        {
+            RootNamespaces = { ["src/Web"] = "Shop.Web", ["src/Legacy"] = "Shop.Legacy" },
+            SourceRoots = { "lib/java" },
+            Ignore = { "src/Legacy/" }
+        };
+
+        // Act
+        var violations = NamespaceConventions.Check(files, settings);
+
+        // Assert
+        Assert.That(violations.Select(v => $"{v.FilePath} {v.Expected}"), Is.EqualTo(new[] { "lib/java/net/shop/Util.java net.shop" }));
+    }
+}
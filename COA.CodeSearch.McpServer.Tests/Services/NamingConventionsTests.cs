@@ -0,0 +1,106 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class NamingConventionsTests
+{
+    private const string WorkspacePath = "/repo";
+
+    private static readonly List<JulieSymbol> Symbols = new()
+    {
+        Symbol("Max_Retries", "constant", "go", "/repo/pkg/client/client.go", 3),
+        Symbol("HTTP_server", "struct", "go", "/repo/pkg/client/client.go", 5),
+        Symbol("parse_url", "function", "go", "/repo/pkg/client/client.go", 9),
+        Symbol("Client", "struct", "go", "/repo/pkg/client/client.go", 12),
+        Symbol("TestClient_Retries", "function", "go", "/repo/pkg/client/client_test.go", 5),
+        Symbol("Status_OK", "constant", "go", "/repo/pkg/api/api.pb.go", 20),
+        Symbol("OrderRepository", "interface", "csharp", "/repo/src/Orders/OrderRepository.cs", 3),
+        Symbol("Item", "interface", "csharp", "/repo/src/Orders/Item.cs", 3),
+        Symbol("IClock", "interface", "csharp", "/repo/src/Orders/IClock.cs", 3),
+        Symbol("Cancel_Should_Refund", "method", "csharp", "/repo/tests/OrderTests.cs", 10)
+    };
+
+    private static readonly HashSet<(string FilePath, string Name)> Tests = new()
+    {
+        ("pkg/client/client_test.go", "TestClient_Retries"),
+        ("tests/OrderTests.cs", "Cancel_Should_Refund")
+    };
+
+    [Test]
+    public void Built_In_Rules_Should_Report_Names_With_Suggested_Renames()
+    {
+        // Act
+        var violations = NamingConventions.Check(WorkspacePath, Symbols, NamingConventions.RulesFor(new NamingConventionSettings()), Tests);
+
+        // Assert - unexported Go names, Go tests and generated code are left alone
+        Assert.That(violations.Select(v => $"{v.FilePath}:{v.Line} {v.Rule} {v.Name} -> {v.Suggested}"), Is.EqualTo(new[]
+        {
+            "pkg/client/client.go:3 go-exported-names Max_Retries -> MaxRetries",
+            "pkg/client/client.go:5 go-exported-names HTTP_server -> HTTPServer",
+            "src/Orders/Item.cs:3 csharp-interface-prefix Item -> IItem",
+            "src/Orders/OrderRepository.cs:3 csharp-interface-prefix OrderRepository -> IOrderRepository"
+        }));
+        Assert.That(violations[0].Message, Is.EqualTo("Exported Go identifiers are PascalCase without underscores"));
+    }
+
+    [Test]
+    public void Configured_Rules_Should_Replace_Disable_And_Enable_Built_Ins()
+    {
+        // Arrange
+        var settings = new NamingConventionSettings
+        {
+            Enable = { "test-names" },
+            Disable = { "go-exported-names" },
+            Rules =
+            {
+                new NamingRule { Name = "csharp-interface-prefix", Languages = { "csharp" }, Kinds = { "interface" }, Suffix = "Repository", Paths = { "src/Orders/OrderRepository.cs" } },
+                new NamingRule { Name = "go-private-camel", Languages = { "go" }, When = @"^\p{Ll}", Style = "camelCase" }
+            }
+        };
+
+        // Act
+        var rules = NamingConventions.RulesFor(settings);
+        var violations = NamingConventions.Check(WorkspacePath, Symbols, rules, Tests);
+
+        // Assert
+        Assert.That(rules.Select(r => r.Name), Is.EqualTo(new[] { "test-names", "csharp-interface-prefix", "go-private-camel" }));
+        Assert.That(violations.Select(v => $"{v.Rule} {v.Name} -> {v.Suggested ?? "(none)"}"), Is.EqualTo(new[]
+        {
+            "go-private-camel parse_url -> parseUrl",
+            "test-names TestClient_Retries -> (none)",
+            "test-names Cancel_Should_Refund -> (none)"
+        }));
+        Assert.That(NamingConventions.Validate(new NamingConventionSettings
+        {
+            Rules = { new NamingRule { Name = "odd", Style = "Title Case" }, new NamingRule { Name = "empty" }, new NamingRule { Name = "bad", Pattern = "([" } }
+        }), Has.Count.EqualTo(3));
+    }
+
+    [TestCase("HTTP_server", "PascalCase", "HTTPServer")]
+    [TestCase("parse_url", "camelCase", "parseUrl")]
+    [TestCase("XMLHttpRequest", "snake_case", "xml_http_request")]
+    [TestCase("maxRetries2", "UPPER_SNAKE_CASE", "MAX_RETRIES_2")]
+    [TestCase("_cache_size", "camelCase", "_cacheSize")]
+    public void ToStyle_Should_Split_Words_On_Underscores_And_Case(string name, string style, string expected)
+    {
+        // Act
+        var styled = NamingConventions.ToStyle(name, style);
+
+        // Assert
+        Assert.That(styled, Is.EqualTo(expected));
+    }
+
+    private static JulieSymbol Symbol(string name, string kind, string language, string filePath, int line) => new()
+    {
+        Id = $"{filePath}:{name}",
+        Name = name,
+        Kind = kind,
+        Language = language,
+        FilePath = filePath,
+        StartLine = line,
+        EndLine = line
+    };
+}
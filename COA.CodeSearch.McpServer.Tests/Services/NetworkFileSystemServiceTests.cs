@@ -0,0 +1,106 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services.NetworkFileSystem;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class NetworkFileSystemServiceTests
+{
+    private static NetworkFileSystemService CreateService(params (string Key, string Value)[] settings)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(settings.ToDictionary(s => "CodeSearch:NetworkFileSystem:" + s.Key, s => (string?)s.Value))
+            .Build();
+        return new NetworkFileSystemService(configuration, NullLogger<NetworkFileSystemService>.Instance);
+    }
+
+    [Test]
+    public void AppliesTo_Should_Cover_Configured_Paths_And_Remote_Volumes_In_Auto_Mode()
+    {
+        // Arrange
+        var share = Path.Combine(Path.GetTempPath(), "mnt", "share");
+        var auto = CreateService(("Paths:0", share));
+        var off = CreateService(("Mode", "Off"), ("Paths:0", share));
+
+        // Act & Assert
+        Assert.That(auto.AppliesTo(Path.Combine(share, "repo", "src")), Is.True);
+        Assert.That(auto.AppliesTo(share + "2"), Is.False);
+        Assert.That(off.AppliesTo(Path.Combine(share, "repo")), Is.False);
+        Assert.That(CreateService(("Mode", "on")).AppliesTo(Path.GetTempPath()), Is.True);
+
+        Assert.That(NetworkFileSystemService.IsRemoteVolume(DriveType.Fixed, "nfs4"), Is.True);
+        Assert.That(NetworkFileSystemService.IsRemoteVolume(DriveType.Fixed, "ReFS"), Is.True);
+        Assert.That(NetworkFileSystemService.IsRemoteVolume(DriveType.Network, null), Is.True);
+        Assert.That(NetworkFileSystemService.IsRemoteVolume(DriveType.Fixed, "ext4"), Is.False);
+
+        var root = Path.GetPathRoot(share)!;
+        var mount = Path.Combine(Path.GetTempPath(), "mnt");
+        Assert.That(NetworkFileSystemService.VolumeOf(Path.Combine(share, "a.cs"), new[] { root, mount }), Is.EqualTo(mount));
+    }
+
+    [Test]
+    public async Task RetryAsync_Should_Retry_Transient_Errors_But_Not_Missing_Files()
+    {
+        // Arrange
+        var service = CreateService(("Mode", "On"), ("RetryCount", "2"), ("RetryDelayMilliseconds", "0"));
+        var attempts = 0;
+
+        // Act
+        var content = await service.RetryAsync("share/a.cs", _ =>
+        {
+            if (++attempts < 3)
+                throw new IOException("The specified network name is no longer available");
+            return Task.FromResult("class A {}");
+        });
+        var missingAttempts = 0;
+        var missing = Assert.ThrowsAsync<FileNotFoundException>(() => service.RetryAsync<string>("share/b.cs", _ =>
+        {
+            missingAttempts++;
+            throw new FileNotFoundException("gone");
+        }));
+
+        // Assert
+        Assert.That((content, attempts), Is.EqualTo(("class A {}", 3)));
+        Assert.That(missing, Is.Not.Null);
+        Assert.That(missingAttempts, Is.EqualTo(1));
+        Assert.That(Assert.ThrowsAsync<IOException>(() => service.RetryAsync<string>("share/c.cs", _ => throw new IOException("still down"))), Is.Not.Null);
+    }
+
+    [Test]
+    public void PollingWatcher_Should_Report_Changes_Between_Listings_And_Keep_Files_Of_Unreadable_Directories()
+    {
+        // Arrange
+        var root = Path.Combine(Path.GetTempPath(), "repo");
+        string File(string name) => Path.Combine(root, name);
+        var stamp = new DateTime(2024, 5, 1, 12, 0, 0, DateTimeKind.Utc);
+        var before = new Dictionary<string, FileStamp>
+        {
+            [File("kept.cs")] = new(10, stamp),
+            [File("edited.cs")] = new(10, stamp),
+            [File("removed.cs")] = new(10, stamp),
+            [Path.Combine(root, "share", "offline.cs")] = new(10, stamp)
+        };
+        var listed = new Dictionary<string, FileStamp>
+        {
+            [File("kept.cs")] = new(10, stamp),
+            [File("edited.cs")] = new(12, stamp.AddSeconds(5)),
+            [File("added.cs")] = new(3, stamp)
+        };
+
+        // Act
+        var after = PollingWatcher.Carry(before, listed, new[] { Path.Combine(root, "share") });
+        var changes = PollingWatcher.Compare(before, after).OrderBy(c => c.Path, StringComparer.Ordinal).ToList();
+
+        // Assert
+        Assert.That(changes, Is.EqualTo(new[]
+        {
+            (File("added.cs"), FileChangeType.Created),
+            (File("edited.cs"), FileChangeType.Modified),
+            (File("removed.cs"), FileChangeType.Deleted)
+        }));
+        Assert.That(after.ContainsKey(Path.Combine(root, "share", "offline.cs")), Is.True);
+    }
+}
@@ -0,0 +1,130 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.OpenApi;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class OpenApiDocumentParserTests
+{
+    [Test]
+    public void Parse_Should_Read_Yaml_Operations_With_Server_Base_Path_And_Body_Schemas()
+    {
+        // Arrange
+        var yaml = @"openapi: 3.0.3
+info:
+  title: Users API
+servers:
+  - url: https://example.com/api
+paths:
+  /users/{userId}:
+    get:
+      operationId: getUser
+      tags: [users, admin]
+      responses:
+        '200':
+          description: ok # trailing comment
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: ""#/components/schemas/User""
+      responses:
+        '201':
+          description: |
+            created
+            - not a list item
+";
+
+        // Act
+        var spec = OpenApiDocumentParser.Parse("api/openapi.yaml", yaml);
+
+        // Assert
+        Assert.That(spec, Is.Not.Null);
+        Assert.That(spec!.Title, Is.EqualTo("Users API"));
+        Assert.That(spec.Operations.Select(o => $"{o.Method} {o.Path}"), Is.EqualTo(new[] { "GET /api/users/{userId}", "POST /api/users" }));
+        Assert.That(spec.Operations[0].OperationId, Is.EqualTo("getUser"));
+        Assert.That(spec.Operations[0].Tags, Is.EqualTo(new[] { "users", "admin" }));
+        Assert.That(spec.Operations[0].ResponseSchemas["200"], Is.EqualTo("User"));
+        Assert.That(spec.Operations[0].Line, Is.EqualTo(8));
+        Assert.That(spec.Operations[1].RequestSchema, Is.EqualTo("User"));
+    }
+
+    [Test]
+    public void Parse_Should_Read_Swagger_Json_Definitions_With_Lines()
+    {
+        // Arrange
+        var json = @"{
+  ""swagger"": ""2.0"",
+  ""basePath"": ""/v1"",
+  ""paths"": {
+    ""/pets"": {
+      ""post"": {
+        ""parameters"": [ { ""in"": ""body"", ""name"": ""body"", ""schema"": { ""$ref"": ""#/definitions/Pet"" } } ],
+        ""responses"": { ""200"": { ""schema"": { ""type"": ""array"", ""items"": { ""$ref"": ""#/definitions/Pet"" } } } }
+      }
+    }
+  },
+  ""definitions"": {
+    ""Pet"": {
+      ""required"": [""name""],
+      ""properties"": {
+        ""name"": { ""type"": ""string"" },
+        ""tags"": { ""type"": ""array"", ""items"": { ""type"": ""string"" } }
+      }
+    }
+  }
+}";
+
+        // Act
+        var spec = OpenApiDocumentParser.Parse("api/swagger.json", json);
+
+        // Assert
+        Assert.That(spec, Is.Not.Null);
+        var operation = spec!.Operations.Single();
+        Assert.That($"{operation.Method} {operation.Path}", Is.EqualTo("POST /v1/pets"));
+        Assert.That(operation.Line, Is.EqualTo(6));
+        Assert.That(operation.RequestSchema, Is.EqualTo("Pet"));
+        Assert.That(operation.ResponseSchemas["200"], Is.EqualTo("Pet"), "an array of a schema is linked to the schema");
+
+        var pet = spec.Schemas.Single();
+        Assert.That(pet.Properties.Select(p => $"{p.Name}:{p.Type}:{p.Required}"), Is.EqualTo(new[] { "name:string:True", "tags:array of string:False" }));
+        Assert.That(pet.Line, Is.EqualTo(13));
+    }
+
+    [Test]
+    public void Parse_Should_Merge_AllOf_Properties_And_Ignore_Other_Documents()
+    {
+        // Arrange
+        var yaml = @"openapi: 3.1.0
+paths: {}
+components:
+  schemas:
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          properties:
+            total:
+              type: number
+    Base:
+      properties:
+        created_at: {type: string}
+";
+
+        // Act
+        var spec = OpenApiDocumentParser.Parse("openapi.yml", yaml);
+        var notSpec = OpenApiDocumentParser.Parse("config.yaml", "name: app\npaths:\n  - /tmp\n");
+
+        // Assert
+        Assert.That(notSpec, Is.Null);
+        var order = spec!.Schemas.Single(s => s.Name == "Order");
+        Assert.That(order.Extends, Is.EqualTo(new[] { "Base" }));
+        Assert.That(order.Properties.Select(p => p.Name), Is.EquivalentTo(new[] { "total", "created_at" }));
+    }
+}
@@ -0,0 +1,116 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Orm;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class OrmSchemaParserTests
+{
+    [Test]
+    public void ParseGo_Should_Map_Tagged_And_Conventional_Fields()
+    {
+        // Arrange
+        var lines = @"package models
+
+type User struct {
+	ID       uint   `gorm:""primaryKey""`
+	IsActive bool   `gorm:""column:is_active""`
+	LastSeen string `db:""last_seen_at""`
+	Name     string
+	Secret   string `gorm:""-""`
+}
+
+func (User) TableName() string { return ""app_users"" }
+".Split('\n');
+
+        // Act
+        var (entities, tableNames) = OrmSchemaParser.ParseGo("models/user.go", lines);
+
+        // Assert
+        var user = entities.Single();
+        Assert.That(user.Name, Is.EqualTo("User"));
+        Assert.That(tableNames["User"], Is.EqualTo("app_users"));
+        Assert.That(user.Columns.Select(c => $"{c.Member}={c.Column}"),
+            Is.EqualTo(new[] { "ID=id", "IsActive=is_active", "LastSeen=last_seen_at", "Name=name" }));
+        Assert.That(user.Columns.Single(c => c.Member == "ID").PrimaryKey, Is.True);
+        Assert.That(user.Columns.Single(c => c.Member == "Name").Explicit, Is.False);
+    }
+
+    [Test]
+    public void ResolveCSharp_Should_Prefer_Fluent_Configuration_Over_Attributes()
+    {
+        // Arrange
+        var entity = @"[Table(""accounts"")]
+public class Account
+{
+    [Key]
+    public int Id { get; set; }
+
+    [Column(""is_active"")]
+    public bool IsActive { get; set; }
+
+    public string Owner { get; set; }
+
+    public virtual List<Order> Orders { get; set; }
+}".Split('\n');
+        var context = @"public class AppDb : DbContext
+{
+    public DbSet<Account> Accounts { get; set; }
+
+    protected override void OnModelCreating(ModelBuilder modelBuilder)
+    {
+        modelBuilder.Entity<Account>().ToTable(""customer_accounts"");
+        modelBuilder.Entity<Account>().Property(a => a.Owner).HasColumnName(""owner_name"");
+    }
+}".Split('\n');
+
+        // Act
+        var entities = OrmSchemaParser.ResolveCSharp(new[]
+        {
+            OrmSchemaParser.ParseCSharp("Data/Account.cs", entity),
+            OrmSchemaParser.ParseCSharp("Data/AppDb.cs", context)
+        });
+
+        // Assert
+        var account = entities.Single();
+        Assert.That(account.Table, Is.EqualTo("customer_accounts"));
+        Assert.That(account.TableSource, Is.EqualTo("ToTable"));
+        Assert.That(account.Columns.Select(c => $"{c.Member}={c.Column}"),
+            Is.EqualTo(new[] { "Id=Id", "IsActive=is_active", "Owner=owner_name" }), "navigation collections are not columns");
+    }
+
+    [Test]
+    public void ParsePython_Should_Read_SqlAlchemy_And_Django_Models()
+    {
+        // Arrange
+        var lines = @"class Session(Base):
+    __tablename__ = ""sessions""
+    id = Column(Integer, primary_key=True)
+    is_active = Column(""active"", Boolean)
+
+class Invoice(models.Model):
+    total = models.DecimalField(max_digits=8, decimal_places=2)
+    paid = models.BooleanField(db_column=""is_paid"")
+".Split('\n');
+
+        // Act
+        var entities = OrmSchemaParser.ParsePython("app/models.py", lines);
+
+        // Assert
+        var session = entities.Single(e => e.Name == "Session");
+        Assert.That(session.Table, Is.EqualTo("sessions"));
+        Assert.That(session.Columns.Single(c => c.Member == "is_active").Column, Is.EqualTo("active"));
+
+        var invoice = entities.Single(e => e.Name == "Invoice");
+        Assert.That(invoice.Framework, Is.EqualTo("django"));
+        Assert.That(invoice.Columns.Select(c => c.Column), Is.EqualTo(new[] { "id", "total", "is_paid" }), "Django adds the implicit id key");
+    }
+
+    [TestCase("Category", "Categories")]
+    [TestCase("Address", "Addresses")]
+    [TestCase("user", "users")]
+    public void Pluralize_Should_Follow_English_Endings(string name, string expected)
+    {
+        Assert.That(OrmSchemaParser.Pluralize(name), Is.EqualTo(expected));
+    }
+}
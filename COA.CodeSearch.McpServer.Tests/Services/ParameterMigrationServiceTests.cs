@@ -0,0 +1,93 @@
+using NUnit.Framework;
+using System.Collections.Generic;
+using System.ComponentModel.DataAnnotations;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Tools;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ParameterMigrationServiceTests
+{
+    private const string ToolName = "list_things";
+
+    private class RenamedParameters
+    {
+        public string Pattern { get; set; } = "*";
+
+        public string? Extensions { get; set; }
+
+        [DeprecatedParameter(nameof(Extensions), Since = "2", RemovedIn = "3")]
+        public string? ExtensionFilter { get; set; }
+    }
+
+    private static ParameterMigrationService CreateService(bool acceptDeprecated = true)
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:Schema:AcceptDeprecatedParameters"] = acceptDeprecated.ToString()
+            })
+            .Build();
+
+        return new ParameterMigrationService(configuration, new Mock<ILogger<ParameterMigrationService>>().Object);
+    }
+
+    [Test]
+    public void Migrate_Should_Copy_Deprecated_Value_To_Replacement()
+    {
+        // Arrange
+        var parameters = new RenamedParameters { Pattern = "*.cs", ExtensionFilter = ".cs,.ts" };
+
+        // Act
+        var notices = CreateService().Migrate(ToolName, parameters);
+
+        // Assert
+        Assert.That(parameters.Extensions, Is.EqualTo(".cs,.ts"));
+        Assert.That(parameters.ExtensionFilter, Is.Null);
+        Assert.That(notices, Has.Count.EqualTo(1));
+        Assert.That(notices[0].Parameter, Is.EqualTo("extensionFilter"));
+        Assert.That(notices[0].ReplacedBy, Is.EqualTo("extensions"));
+        Assert.That(notices[0].RemovedIn, Is.EqualTo("3"));
+    }
+
+    [Test]
+    public void Migrate_Should_Prefer_New_Name_When_Both_Are_Sent()
+    {
+        // Arrange
+        var parameters = new RenamedParameters { Extensions = ".js", ExtensionFilter = ".cs" };
+
+        // Act
+        CreateService().Migrate(ToolName, parameters);
+
+        // Assert
+        Assert.That(parameters.Extensions, Is.EqualTo(".js"));
+    }
+
+    [Test]
+    public void Migrate_Should_Return_No_Notices_For_Current_Shape()
+    {
+        // Arrange
+        var parameters = new RenamedParameters { Extensions = ".js" };
+
+        // Act
+        var notices = CreateService().Migrate(ToolName, parameters);
+
+        // Assert
+        Assert.That(notices, Is.Empty);
+    }
+
+    [Test]
+    public void Migrate_Should_Reject_Deprecated_Names_When_Disabled()
+    {
+        // Arrange
+        var parameters = new RenamedParameters { ExtensionFilter = ".cs" };
+
+        // Act & Assert
+        var ex = Assert.Throws<ValidationException>(() => CreateService(acceptDeprecated: false).Migrate(ToolName, parameters));
+        Assert.That(ex!.Message, Does.Contain("extensions"));
+    }
+}
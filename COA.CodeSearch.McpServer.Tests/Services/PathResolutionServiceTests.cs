@@ -180,6 +180,64 @@ public class PathResolutionServiceTests
 
     #endregion
 
+    #region Index Storage Location Tests
+
+    [Test]
+    public void StorageLocation_Should_Default_To_Workspace()
+    {
+        // Assert
+        Assert.That(_service.StorageLocation, Is.EqualTo(IndexStorageLocation.Workspace));
+    }
+
+    [Test]
+    public void GetIndexPath_With_Repository_Storage_Should_Use_Repo_Directory()
+    {
+        // Arrange
+        _mockConfiguration.Setup(c => c[PathConstants.IndexStorageLocationConfigKey]).Returns("Repository");
+        var service = new PathResolutionService(_mockConfiguration.Object, _mockLogger.Object);
+        var repoPath = Path.Combine(_testBasePath, "other-repo");
+
+        // Act
+        var indexPath = service.GetIndexPath(repoPath);
+
+        // Assert
+        var expectedRoot = Path.Combine(repoPath, PathConstants.RepositoryIndexDirectoryName, PathConstants.IndexDirectoryName);
+        Assert.That(service.StorageLocation, Is.EqualTo(IndexStorageLocation.Repository));
+        Assert.That(indexPath, Does.StartWith(expectedRoot));
+    }
+
+    [Test]
+    public void GetIndexRootPath_With_Custom_Storage_Should_Use_Custom_Path()
+    {
+        // Arrange
+        var customRoot = Path.Combine(_testBasePath, "custom-indexes");
+        _mockConfiguration.Setup(c => c[PathConstants.IndexStorageLocationConfigKey]).Returns("custom");
+        _mockConfiguration.Setup(c => c[PathConstants.IndexStorageCustomPathConfigKey]).Returns(customRoot);
+        var service = new PathResolutionService(_mockConfiguration.Object, _mockLogger.Object);
+
+        // Act
+        var indexRoot = service.GetIndexRootPath();
+        var indexPath = service.GetIndexPath(Path.Combine(_testBasePath, "project"));
+
+        // Assert
+        Assert.That(indexRoot, Is.EqualTo(Path.GetFullPath(customRoot)));
+        Assert.That(indexPath, Does.StartWith(indexRoot));
+    }
+
+    [Test]
+    public void StorageLocation_Custom_Without_Path_Should_Fall_Back_To_Workspace()
+    {
+        // Arrange
+        _mockConfiguration.Setup(c => c[PathConstants.IndexStorageLocationConfigKey]).Returns("Custom");
+        var service = new PathResolutionService(_mockConfiguration.Object, _mockLogger.Object);
+
+        // Act & Assert
+        Assert.That(service.StorageLocation, Is.EqualTo(IndexStorageLocation.Workspace));
+        Assert.That(service.GetIndexRootPath(), Is.EqualTo(_service.GetIndexRootPath()));
+    }
+
+    #endregion
+
     #region Directory Management Tests
 
     [Test]
@@ -0,0 +1,153 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class PlatformPathsTests
+{
+    private string _root = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _root = Path.Combine(Path.GetTempPath(), "PlatformPathsTests_" + Guid.NewGuid().ToString("N"));
+        Directory.CreateDirectory(_root);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        if (Directory.Exists(_root))
+            Directory.Delete(_root, recursive: true);
+    }
+
+    [Test]
+    public void WithoutExtendedPrefix_Should_Fold_Long_Path_And_Nt_Forms_To_Plain_Paths()
+    {
+        // Act & Assert
+        Assert.That(PlatformPaths.WithoutExtendedPrefix(@"\\?\C:\src\very\deep\File.cs"), Is.EqualTo(@"C:\src\very\deep\File.cs"));
+        Assert.That(PlatformPaths.WithoutExtendedPrefix(@"\\?\UNC\server\share\repo"), Is.EqualTo(@"\\server\share\repo"));
+        Assert.That(PlatformPaths.WithoutExtendedPrefix(@"\??\D:\shared"), Is.EqualTo(@"D:\shared"));
+        Assert.That(PlatformPaths.WithoutExtendedPrefix(@"\\.\pipe\codesearch"), Is.EqualTo(@"\\.\pipe\codesearch"));
+        Assert.That(PlatformPaths.WithoutExtendedPrefix(@"C:\src"), Is.EqualTo(@"C:\src"));
+    }
+
+    [Test]
+    public void IsUnder_Should_Match_Whole_Path_Segments_Only()
+    {
+        // Arrange
+        var repo = Path.Combine(_root, "repo");
+
+        // Act & Assert
+        Assert.That(PlatformPaths.IsUnder(Path.Combine(repo, "src", "a.cs"), repo), Is.True);
+        Assert.That(PlatformPaths.IsUnder(repo, repo + Path.DirectorySeparatorChar), Is.True);
+        Assert.That(PlatformPaths.IsUnder(Path.Combine(_root, "repo2", "a.cs"), repo), Is.False);
+        Assert.That(PlatformPaths.PathEquals(Path.Combine(repo, "src") + Path.DirectorySeparatorChar, Path.Combine(repo, "src")), Is.True);
+        Assert.That(PlatformPaths.PathEquals(Path.Combine(repo, "Src"), Path.Combine(repo, "src")), Is.EqualTo(PlatformPaths.IgnoreCase));
+    }
+
+    [Test]
+    public void EnumerateFiles_Should_Walk_Each_Real_Directory_Once_Through_Links_And_Cycles()
+    {
+        // Arrange
+        var workspace = LinkedWorkspace();
+        var errors = new List<string>();
+
+        // Act
+        var files = PlatformPaths.EnumerateFiles(workspace, onError: (path, _) => errors.Add(Path.GetFileName(path))).ToList();
+
+        // Assert
+        Assert.That(Relative(workspace, files), Is.EqualTo(new[]
+        {
+            "packages-a/util.ts",
+            "src/app.ts",
+            "src/lib.ts"
+        }));
+        Assert.That(errors, Is.EqualTo(new[] { "missing.ts" }));
+    }
+
+    [Test]
+    public void EnumerateFiles_Should_Skip_Links_Or_Index_Every_Link_Path_As_The_Policy_Says()
+    {
+        // Arrange
+        var workspace = LinkedWorkspace();
+        var errors = new List<string>();
+
+        // Act
+        var skipped = PlatformPaths.EnumerateFiles(workspace, policy: SymlinkPolicy.Skip).ToList();
+        var asLinks = PlatformPaths.EnumerateFiles(workspace, onError: (path, _) => errors.Add(Path.GetFileName(path)),
+            policy: PlatformPaths.ParseSymlinkPolicy("index-as-link")).ToList();
+
+        // Assert
+        Assert.That(Relative(workspace, skipped), Is.EqualTo(new[] { "src/app.ts", "src/lib.ts" }));
+        Assert.That(Relative(workspace, asLinks), Is.EqualTo(new[]
+        {
+            "app-link.ts",
+            "packages-a/util.ts",
+            "packages-b/util.ts",
+            "src-link/app.ts",
+            "src-link/lib.ts",
+            "src/app.ts",
+            "src/lib.ts"
+        }));
+        // shared/loop leads back to the folder it is in, under both packages
+        Assert.That(errors, Is.EqualTo(new[] { "missing.ts", "loop", "loop" }));
+    }
+
+    [Test]
+    public void SymlinkFilter_Should_Keep_Of_Another_Walkers_Listing_What_The_Policy_Would_Walk()
+    {
+        // Arrange
+        var workspace = LinkedWorkspace();
+        var listing = new[]
+        {
+            "app-link.ts", "missing.ts", "packages-a/util.ts", "packages-a/loop/util.ts", "packages-b/util.ts",
+            "src-link/app.ts", "src/app.ts", "src/lib.ts"
+        }.Select(f => Path.Combine(workspace, f)).ToList();
+
+        foreach (var policy in new[] { SymlinkPolicy.Follow, SymlinkPolicy.Skip, SymlinkPolicy.IndexAsLink })
+        {
+            // Act
+            var admits = PlatformPaths.SymlinkFilter(workspace, policy);
+            var kept = listing.Where(admits).ToList();
+
+            // Assert
+            var walked = PlatformPaths.EnumerateFiles(workspace, policy: policy).ToHashSet();
+            Assert.That(Relative(workspace, kept), Is.EqualTo(Relative(workspace, listing.Where(walked.Contains))), policy.ToString());
+        }
+    }
+
+    /// <summary>
+    /// A workspace with a link back into it, two links to one shared folder outside it, a link cycle in the shared
+    /// folder, a file link and a broken link
+    /// </summary>
+    private string LinkedWorkspace()
+    {
+        var workspace = Path.Combine(_root, "workspace");
+        var shared = Path.Combine(_root, "shared");
+        Directory.CreateDirectory(Path.Combine(workspace, "src"));
+        Directory.CreateDirectory(shared);
+        File.WriteAllText(Path.Combine(workspace, "src", "app.ts"), "");
+        File.WriteAllText(Path.Combine(shared, "util.ts"), "");
+        File.WriteAllText(Path.Combine(workspace, "src", "lib.ts"), "");
+
+        try
+        {
+            Directory.CreateSymbolicLink(Path.Combine(workspace, "src-link"), Path.Combine(workspace, "src"));
+            Directory.CreateSymbolicLink(Path.Combine(workspace, "packages-a"), shared);
+            Directory.CreateSymbolicLink(Path.Combine(workspace, "packages-b"), shared);
+            Directory.CreateSymbolicLink(Path.Combine(shared, "loop"), shared);
+            File.CreateSymbolicLink(Path.Combine(workspace, "app-link.ts"), Path.Combine(workspace, "src", "app.ts"));
+            File.CreateSymbolicLink(Path.Combine(workspace, "missing.ts"), Path.Combine(_root, "nowhere.ts"));
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            Assert.Ignore($"Symbolic links are not available here: {ex.Message}");
+        }
+        return workspace;
+    }
+
+    private static List<string> Relative(string workspace, IEnumerable<string> files) =>
+        files.Select(f => Path.GetRelativePath(workspace, f).Replace('\\', '/')).OrderBy(f => f, StringComparer.Ordinal).ToList();
+}
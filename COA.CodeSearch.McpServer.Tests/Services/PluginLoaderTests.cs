@@ -0,0 +1,154 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Plugins;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class PluginLoaderTests
+{
+    private string _pluginsDirectory = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _pluginsDirectory = Path.Combine(Path.GetTempPath(), "codesearch-plugins-test", Guid.NewGuid().ToString());
+        Directory.CreateDirectory(_pluginsDirectory);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        try
+        {
+            if (Directory.Exists(_pluginsDirectory))
+                Directory.Delete(_pluginsDirectory, true);
+        }
+        catch
+        {
+            // Ignore cleanup errors
+        }
+    }
+
+    private IConfiguration CreateConfiguration(bool enabled, params string[] paths)
+    {
+        var settings = new Dictionary<string, string?>
+        {
+            ["CodeSearch:Plugins:Enabled"] = enabled.ToString(),
+            ["CodeSearch:Plugins:Directory"] = _pluginsDirectory,
+            ["CodeSearch:Plugins:Settings:Recording:Name"] = "configured-analyzer"
+        };
+        for (var i = 0; i < paths.Length; i++)
+        {
+            settings[$"CodeSearch:Plugins:Paths:{i}"] = paths[i];
+        }
+        return new ConfigurationBuilder().AddInMemoryCollection(settings).Build();
+    }
+
+    private string CreatePluginFolder(string name, string contents = "not an assembly")
+    {
+        var folder = Path.Combine(_pluginsDirectory, name);
+        Directory.CreateDirectory(folder);
+        var path = Path.Combine(folder, name + ".dll");
+        File.WriteAllText(path, contents);
+        return path;
+    }
+
+    [Test]
+    public void LoadPlugins_Should_Load_Nothing_Unless_Enabled()
+    {
+        // Arrange
+        CreatePluginFolder("Broken");
+        var services = new ServiceCollection();
+
+        // Act
+        var catalog = PluginLoader.LoadPlugins(services, CreateConfiguration(enabled: false), NullLogger.Instance);
+
+        // Assert
+        Assert.That(catalog.Plugins, Is.Empty);
+        Assert.That(services.BuildServiceProvider().GetService<PluginCatalog>(), Is.SameAs(catalog));
+    }
+
+    [Test]
+    public void FindPluginAssemblies_Should_List_Configured_Paths_Then_Folders_Named_After_Their_Assembly()
+    {
+        // Arrange
+        var zeta = CreatePluginFolder("Zeta");
+        var alpha = CreatePluginFolder("Alpha");
+        Directory.CreateDirectory(Path.Combine(_pluginsDirectory, "Empty"));
+        File.WriteAllText(Path.Combine(_pluginsDirectory, "Empty", "Other.dll"), "");
+        var missing = Path.Combine(_pluginsDirectory, "Missing.dll");
+
+        // Act
+        var paths = PluginLoader.FindPluginAssemblies(CreateConfiguration(true, zeta, missing), NullLogger.Instance);
+
+        // Assert - an explicit path is not listed twice, and a missing one is skipped
+        Assert.That(paths, Is.EqualTo(new[] { zeta, alpha }));
+    }
+
+    [Test]
+    public void LoadPlugins_Should_Report_An_Assembly_That_Fails_To_Load_And_Keep_Going()
+    {
+        // Arrange
+        var broken = CreatePluginFolder("Broken");
+        var services = new ServiceCollection();
+
+        // Act
+        var catalog = PluginLoader.LoadPlugins(services, CreateConfiguration(enabled: true), NullLogger.Instance);
+
+        // Assert
+        var plugin = catalog.Plugins.Single();
+        Assert.That(plugin.Loaded, Is.False);
+        Assert.That(plugin.Name, Is.EqualTo("Broken"));
+        Assert.That(plugin.AssemblyPath, Is.EqualTo(broken));
+        Assert.That(plugin.Error, Is.Not.Empty);
+    }
+
+    [Test]
+    public void LoadPlugins_Should_Configure_Every_Plugin_In_The_Assembly()
+    {
+        // Arrange - this test assembly carries RecordingPlugin
+        var assemblyPath = typeof(PluginLoaderTests).Assembly.Location;
+        var services = new ServiceCollection();
+
+        // Act
+        var catalog = PluginLoader.LoadPlugins(services, CreateConfiguration(true, assemblyPath), NullLogger.Instance);
+
+        // Assert - the plugin's analyzer implements the server's interface, shared across load contexts
+        var plugin = catalog.Plugins.Single();
+        Assert.That(plugin.Loaded, Is.True);
+        Assert.That($"{plugin.Name} {plugin.Version}", Is.EqualTo("Recording 1.2.0"));
+        var analyzer = services.BuildServiceProvider().GetServices<IPatternAnalyzerPlugin>().Single();
+        Assert.That(analyzer.Name, Is.EqualTo("configured-analyzer"));
+        Assert.That(analyzer.GetType().Assembly, Is.Not.SameAs(typeof(PluginLoaderTests).Assembly), "Loaded in its own context");
+    }
+
+    public class RecordingPlugin : ICodeSearchPlugin
+    {
+        public string Name => "Recording";
+        public string Version => "1.2.0";
+
+        public void ConfigureServices(IServiceCollection services, IConfiguration configuration)
+        {
+            services.AddSingleton<IPatternAnalyzerPlugin>(new RecordingAnalyzer(configuration["CodeSearch:Plugins:Settings:Recording:Name"]!));
+        }
+    }
+
+    public class RecordingAnalyzer : IPatternAnalyzerPlugin
+    {
+        public RecordingAnalyzer(string name)
+        {
+            Name = name;
+        }
+
+        public string Name { get; }
+
+        public bool Supports(string? language, string extension) => true;
+
+        public Task<IReadOnlyList<CodePattern>> AnalyzeAsync(PatternAnalysisContext context, CancellationToken cancellationToken) =>
+            Task.FromResult<IReadOnlyList<CodePattern>>(Array.Empty<CodePattern>());
+    }
+}
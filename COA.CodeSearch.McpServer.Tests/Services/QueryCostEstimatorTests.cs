@@ -0,0 +1,105 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class QueryCostEstimatorTests
+{
+    private static QueryCostEstimator CreateEstimator(Dictionary<string, string?>? settings = null) =>
+        new(new ConfigurationBuilder().AddInMemoryCollection(settings ?? new()).Build(), NullLogger<QueryCostEstimator>.Instance);
+
+    [Test]
+    public void Estimate_Should_Flag_Unanchored_Regex_Over_Large_Index()
+    {
+        // Arrange
+        var estimator = CreateEstimator();
+
+        // Act
+        var estimate = estimator.Estimate(".*", "regex", 100_000);
+
+        // Assert
+        Assert.That(estimate.IsExpensive, Is.True);
+        Assert.That(estimate.Complexity, Is.EqualTo(9));
+        Assert.That(estimate.Reasons, Has.Count.EqualTo(2));
+    }
+
+    [Test]
+    public void Estimate_Should_Not_Flag_Literal_Anchored_Queries()
+    {
+        // Arrange
+        var estimator = CreateEstimator();
+
+        // Act
+        var regex = estimator.Estimate("UserService.*Async", "regex", 1_000_000);
+        var text = estimator.Estimate("class UserService", "auto", 1_000_000);
+
+        // Assert
+        Assert.That(regex.IsExpensive, Is.False);
+        Assert.That(regex.Complexity, Is.EqualTo(1));
+        Assert.That(text.IsExpensive, Is.False);
+    }
+
+    [Test]
+    public void Estimate_Should_Not_Flag_Small_Index()
+    {
+        // Act
+        var estimate = CreateEstimator().Estimate(".*", "regex", 500);
+
+        // Assert
+        Assert.That(estimate.Complexity, Is.GreaterThan(1));
+        Assert.That(estimate.IsExpensive, Is.False);
+    }
+
+    [Test]
+    public void Estimate_Should_Flag_Leading_Wildcard_In_Auto_Mode()
+    {
+        // Act
+        var estimate = CreateEstimator().Estimate("*Async", "auto", 100_000);
+
+        // Assert
+        Assert.That(estimate.IsExpensive, Is.True);
+        Assert.That(estimate.Reasons[0], Does.Contain("leading wildcard"));
+    }
+
+    [Test]
+    public void Estimate_Should_Flag_Nested_Quantifiers()
+    {
+        // Act
+        var estimate = CreateEstimator().Estimate("(a+)+b", "regex", 10);
+
+        // Assert
+        Assert.That(estimate.Reasons, Has.Some.Contains("nested quantifiers"));
+    }
+
+    [Test]
+    public void Constructor_Should_Read_Action_And_Clamp_Sample_Rate()
+    {
+        // Act
+        var estimator = CreateEstimator(new()
+        {
+            ["CodeSearch:QueryGuardrails:Action"] = "Sample",
+            ["CodeSearch:QueryGuardrails:SampleRate"] = "5"
+        });
+
+        // Assert
+        Assert.That(estimator.Action, Is.EqualTo(QueryGuardrailAction.Sample));
+        Assert.That(estimator.SampleRate, Is.EqualTo(1.0));
+    }
+
+    [Test]
+    public void SampledDocFilter_Should_Accept_Roughly_The_Configured_Rate()
+    {
+        // Arrange
+        var filter = new SampledDocFilter(0.1, seed: 42);
+
+        // Act
+        var accepted = Enumerable.Range(0, 100_000).Count(filter.Accepts);
+
+        // Assert
+        Assert.That(accepted, Is.InRange(9_000, 11_000));
+    }
+}
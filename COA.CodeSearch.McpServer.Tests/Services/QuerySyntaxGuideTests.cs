@@ -0,0 +1,69 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class QuerySyntaxGuideTests
+{
+    [Test]
+    public void Build_Should_Template_Examples_On_Workspace_Names()
+    {
+        // Act
+        var topics = QuerySyntaxGuide.Build("QueryPreprocessor", "BuildQuery");
+
+        // Assert
+        Assert.That(topics.Select(t => t.Name), Is.EqualTo(QuerySyntaxGuide.TopicNames));
+        var auto = topics.Single(t => t.Name == "auto");
+        Assert.That(auto.Examples.Select(e => e.Query), Does.Contain("QueryPreprocessor"));
+        Assert.That(auto.Examples.Select(e => e.Query), Does.Contain("QueryPreprocessor.BuildQuery"));
+        Assert.That(auto.Examples.Select(e => e.Query), Does.Contain("query preprocessor"));
+        Assert.That(topics.SelectMany(t => t.Examples).Any(e => e.Query.Contains(QuerySyntaxGuide.GenericTypeName)), Is.False);
+    }
+
+    [Test]
+    public void Build_Should_Filter_By_Topic_Case_Insensitively()
+    {
+        // Act
+        var topics = QuerySyntaxGuide.Build("QueryPreprocessor", "BuildQuery", "REGEX");
+
+        // Assert
+        Assert.That(topics, Has.Count.EqualTo(1));
+        Assert.That(topics[0].SearchMode, Is.EqualTo("regex"));
+        Assert.That(topics[0].Examples.Select(e => e.Query), Does.Contain("build[a-z0-9_]*"));
+    }
+
+    [Test]
+    public void Fuzzy_Example_Should_Be_A_Misspelling_Within_Two_Edits()
+    {
+        // Act
+        var fuzzy = QuerySyntaxGuide.Build("QueryPreprocessor", "BuildQuery", "fuzzy").Single();
+
+        // Assert
+        var typo = fuzzy.Examples[0].Query;
+        Assert.That(typo, Is.Not.EqualTo("querypreprocessor"));
+        Assert.That(typo.Length, Is.EqualTo("querypreprocessor".Length));
+        Assert.That(typo.Where((c, i) => c != "querypreprocessor"[i]).Count(), Is.EqualTo(2));
+    }
+
+    [TestCase("getUserById", new[] { "get", "User", "By", "Id" })]
+    [TestCase("HTTPClient", new[] { "HTTP", "Client" })]
+    [TestCase("max_retry_count", new[] { "max", "retry", "count" })]
+    [TestCase("Parse", new[] { "Parse" })]
+    public void SplitWords_Should_Split_Identifier_Words(string identifier, string[] expected)
+    {
+        // Act & Assert
+        Assert.That(QuerySyntaxGuide.SplitWords(identifier), Is.EqualTo(expected));
+    }
+
+    [TestCase("UserService", true, true)]
+    [TestCase("userService", true, false)]
+    [TestCase("userService", false, true)]
+    [TestCase("Parse", true, false)]
+    [TestCase("Outer.Inner", true, false)]
+    public void IsGoodSample_Should_Prefer_Compound_Identifiers(string name, bool requireUpperFirst, bool expected)
+    {
+        // Act & Assert
+        Assert.That(QuerySyntaxGuide.IsGoodSample(name, requireUpperFirst), Is.EqualTo(expected));
+    }
+}
@@ -0,0 +1,72 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Logging;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class QueryUsageTests
+{
+    [Test]
+    public async Task Counters_Should_Add_Up_Work_Recorded_Across_Parallel_Tasks_Of_One_Invocation()
+    {
+        // Arrange
+        var counters = QueryUsage.Begin();
+
+        // Act
+        await Task.WhenAll(Enumerable.Range(0, 8).Select(_ => Task.Run(async () =>
+        {
+            await Task.Yield();
+            QueryUsage.RecordDocuments(10, bytes: 100);
+            QueryUsage.RecordBytes(5);
+            QueryUsage.RecordCacheLookup(hit: true);
+            QueryUsage.RecordCacheLookup(hit: false);
+        })));
+        var report = counters.ToReport(elapsedMs: 42);
+
+        // Assert
+        Assert.That(report.ElapsedMs, Is.EqualTo(42));
+        Assert.That(report.DocumentsScanned, Is.EqualTo(80));
+        Assert.That(report.BytesRead, Is.EqualTo(840));
+        Assert.That((report.CacheHits, report.CacheMisses), Is.EqualTo((8L, 8L)));
+    }
+
+    [Test]
+    public async Task Concurrent_Invocations_Should_Count_Separately()
+    {
+        // Arrange
+        async Task<QueryUsageReport> Invoke(int documents)
+        {
+            var counters = QueryUsage.Begin();
+            await Task.Delay(10);
+            QueryUsage.RecordDocuments(documents);
+            await Task.Delay(10);
+            return counters.ToReport(0);
+        }
+
+        // Act
+        var reports = await Task.WhenAll(Task.Run(() => Invoke(3)), Task.Run(() => Invoke(7)));
+
+        // Assert
+        Assert.That(reports.Select(r => r.DocumentsScanned), Is.EqualTo(new[] { 3L, 7L }));
+    }
+
+    [Test]
+    public async Task Work_Outside_An_Invocation_Should_Not_Be_Counted()
+    {
+        // Arrange
+        var background = Task.Run(() =>
+        {
+            QueryUsage.RecordDocuments(1000, bytes: 1000);
+            return QueryUsage.Current;
+        });
+
+        // Act
+        var backgroundCounters = await background;
+        var counters = QueryUsage.Begin();
+        QueryUsage.RecordDocuments(2);
+
+        // Assert
+        Assert.That(backgroundCounters, Is.Null);
+        Assert.That(counters.ToReport(0).DocumentsScanned, Is.EqualTo(2));
+    }
+}
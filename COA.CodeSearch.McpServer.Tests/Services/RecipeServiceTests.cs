@@ -0,0 +1,106 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Recipes;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class RecipeServiceTests
+{
+    private string _workspace = null!;
+    private RecipeService _recipes = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "recipe_test_" + Guid.NewGuid().ToString("N"));
+        Directory.CreateDirectory(_workspace);
+        _recipes = new RecipeService(new ConfigurationBuilder().Build(), NullLogger<RecipeService>.Instance);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        if (Directory.Exists(_workspace))
+            Directory.Delete(_workspace, true);
+    }
+
+    [Test]
+    public async Task PlanAsync_Should_Chain_Steps_In_Memory_Without_Writing()
+    {
+        // Arrange
+        File.WriteAllText(Path.Combine(_workspace, "UserService.cs"), "using System.Linq;\nusing System;\n\nclass UserService { }\n");
+        File.WriteAllText(Path.Combine(_workspace, "Consumer.cs"), "class Consumer { UserService s; UserServiceFactory f; }\n");
+        File.WriteAllText(Path.Combine(_workspace, "notes.md"), "UserService docs\n");
+        var recipe = _recipes.Parse("""
+            {
+              "name": "rename service",
+              "steps": [
+                { "op": "search", "pattern": "UserService", "filePattern": "*.cs" },
+                { "op": "rename", "oldName": "UserService", "newName": "AccountService" },
+                { "op": "organize_imports" }
+              ]
+            }
+            """);
+
+        // Act
+        var plan = await _recipes.PlanAsync(_workspace, recipe);
+
+        // Assert
+        Assert.That(plan.FailedStep, Is.Null);
+        Assert.That(plan.Files.Select(f => f.FilePath), Is.EqualTo(new[] { "Consumer.cs", "UserService.cs" }));
+        Assert.That(plan.Files[0].Current, Is.EqualTo("class Consumer { AccountService s; UserServiceFactory f; }\n"));
+        Assert.That(plan.Files[1].Current, Is.EqualTo("using System;\nusing System.Linq;\n\nclass AccountService { }\n"));
+        Assert.That(plan.Steps.Select(s => s.Status), Is.EqualTo(new[] { "ok", "ok", "ok" }));
+        Assert.That(File.ReadAllText(Path.Combine(_workspace, "UserService.cs")), Does.Contain("class UserService"));
+    }
+
+    [Test]
+    public async Task PlanAsync_Should_Stop_At_Failing_Step_And_Skip_The_Rest()
+    {
+        // Arrange
+        File.WriteAllText(Path.Combine(_workspace, "a.txt"), "one\ntwo\n");
+        var recipe = _recipes.Parse("""
+            {
+              "steps": [
+                { "op": "edit", "filePath": "a.txt", "operation": "replace", "startLine": 5, "content": "x" },
+                { "op": "replace", "pattern": "one", "replacement": "1" }
+              ]
+            }
+            """);
+
+        // Act
+        var plan = await _recipes.PlanAsync(_workspace, recipe);
+
+        // Assert
+        Assert.That(plan.FailedStep?.Index, Is.EqualTo(1));
+        Assert.That(plan.Steps[1].Status, Is.EqualTo("skipped"));
+        Assert.That(plan.Files, Is.Empty);
+    }
+
+    [TestCase("""{ "steps": [] }""")]
+    [TestCase("""{ "steps": [{ "op": "format" }] }""")]
+    [TestCase("""{ "steps": [{ "op": "rename", "oldName": "A", "newName": "not valid" }] }""")]
+    [TestCase("""{ "steps": [{ "op": "verify_build" }, { "op": "organize_imports" }] }""")]
+    [TestCase("""{ "steps": [{ "op": "verify_build", "command": "rm -rf /" }] }""")]
+    public void Parse_Should_Reject_Invalid_Recipes(string json)
+    {
+        // Act & Assert
+        var ex = Assert.Throws<RecipeException>(() => _recipes.Parse(json));
+        Assert.That(ex!.Code, Is.EqualTo("INVALID_RECIPE"));
+    }
+
+    [Test]
+    public void OrganizeCSharpUsings_Should_Sort_Group_And_Deduplicate()
+    {
+        // Arrange
+        var content = "// header\nusing Zeta;\nusing static System.Math;\nusing System.IO;\nusing Alias = Foo.Bar;\nusing Zeta;\nusing Alpha;\n\nnamespace N;\n";
+
+        // Act
+        var result = RecipeService.OrganizeCSharpUsings(content);
+
+        // Assert
+        Assert.That(result, Is.EqualTo("// header\nusing System.IO;\nusing Alpha;\nusing Zeta;\nusing static System.Math;\nusing Alias = Foo.Bar;\n\nnamespace N;\n"));
+    }
+}
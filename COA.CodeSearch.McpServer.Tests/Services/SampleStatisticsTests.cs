@@ -0,0 +1,56 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Lucene;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SampleStatisticsTests
+{
+    [Test]
+    public void Extrapolate_Should_Scale_Sample_Proportion_To_Index()
+    {
+        // Act
+        var estimate = SampleStatistics.Extrapolate(matchesInSample: 50, sampledDocuments: 1_000, totalDocuments: 10_000, sampleRate: 0.1);
+
+        // Assert
+        Assert.That(estimate.EstimatedMatches, Is.EqualTo(500));
+        Assert.That(estimate.LowerBound, Is.InRange(350, 500));
+        Assert.That(estimate.UpperBound, Is.InRange(500, 700));
+        Assert.That(estimate.ConfidenceLevel, Is.EqualTo(0.95));
+    }
+
+    [Test]
+    public void Extrapolate_Should_Never_Go_Below_Matches_Already_Seen()
+    {
+        // Act
+        var estimate = SampleStatistics.Extrapolate(matchesInSample: 1, sampledDocuments: 10, totalDocuments: 100, sampleRate: 0.1);
+
+        // Assert
+        Assert.That(estimate.LowerBound, Is.GreaterThanOrEqualTo(1));
+        Assert.That(estimate.UpperBound, Is.LessThanOrEqualTo(91));
+    }
+
+    [Test]
+    public void Extrapolate_Should_Give_Nonzero_Upper_Bound_When_Sample_Has_No_Matches()
+    {
+        // Act
+        var estimate = SampleStatistics.Extrapolate(matchesInSample: 0, sampledDocuments: 100, totalDocuments: 1_000, sampleRate: 0.1);
+
+        // Assert
+        Assert.That(estimate.EstimatedMatches, Is.EqualTo(0));
+        Assert.That(estimate.LowerBound, Is.EqualTo(0));
+        Assert.That(estimate.UpperBound, Is.GreaterThan(0));
+    }
+
+    [Test]
+    public void Extrapolate_Should_Be_Exact_When_Whole_Index_Sampled()
+    {
+        // Act
+        var estimate = SampleStatistics.Extrapolate(matchesInSample: 42, sampledDocuments: 300, totalDocuments: 300, sampleRate: 1.0);
+
+        // Assert
+        Assert.That(estimate.EstimatedMatches, Is.EqualTo(42));
+        Assert.That(estimate.LowerBound, Is.EqualTo(42));
+        Assert.That(estimate.UpperBound, Is.EqualTo(42));
+    }
+}
@@ -0,0 +1,79 @@
+using System.Text.Json;
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Ci;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SarifReportWriterTests
+{
+    [Test]
+    public void ToSarif_Should_Write_One_Result_Per_Finding_With_Relative_Locations()
+    {
+        // Arrange
+        var report = new CiReport
+        {
+            Findings =
+            {
+                new CiFinding { RuleId = "query/no-thread-sleep", Level = "error", Message = "Use Task.Delay", FilePath = "src/Worker.cs", Line = 42 },
+                new CiFinding { RuleId = "query/no-thread-sleep", Level = "error", Message = "Use Task.Delay", FilePath = "src/Other.cs", Line = 7 }
+            }
+        };
+
+        // Act
+        using var document = JsonDocument.Parse(SarifReportWriter.ToSarif(report, "2.1.8"));
+
+        // Assert
+        var run = document.RootElement.GetProperty("runs")[0];
+        Assert.That(document.RootElement.GetProperty("version").GetString(), Is.EqualTo("2.1.0"));
+        Assert.That(run.GetProperty("tool").GetProperty("driver").GetProperty("rules").GetArrayLength(), Is.EqualTo(1));
+        Assert.That(run.GetProperty("results").GetArrayLength(), Is.EqualTo(2));
+
+        var location = run.GetProperty("results")[0].GetProperty("locations")[0].GetProperty("physicalLocation");
+        Assert.That(location.GetProperty("artifactLocation").GetProperty("uri").GetString(), Is.EqualTo("src/Worker.cs"));
+        Assert.That(location.GetProperty("region").GetProperty("startLine").GetInt32(), Is.EqualTo(42));
+    }
+
+    [Test]
+    public void Report_Should_Fail_When_Any_Check_Exceeds_Its_Threshold()
+    {
+        // Arrange
+        var report = new CiReport
+        {
+            Checks =
+            {
+                new CiCheckResult { Name = "secrets", Kind = "secrets", Count = 0, Threshold = 0, Passed = true },
+                new CiCheckResult { Name = "todo-budget", Kind = "query", Count = 30, Threshold = 25, Passed = false }
+            }
+        };
+
+        // Assert
+        Assert.That(report.Passed, Is.False);
+    }
+
+    [Test]
+    public void Parse_Should_Reject_Unknown_Arguments()
+    {
+        // Act
+        var options = CiOptions.Parse(new[] { "--fail-fast" }, out var error);
+
+        // Assert
+        Assert.That(options, Is.Null);
+        Assert.That(error, Does.Contain("--fail-fast"));
+    }
+
+    [Test]
+    public void Parse_Should_Default_Config_To_Workspace_File()
+    {
+        // Arrange
+        var workspace = Path.GetTempPath();
+
+        // Act
+        var options = CiOptions.Parse(new[] { "--workspace", workspace, "--no-index" }, out _);
+
+        // Assert
+        Assert.That(options, Is.Not.Null);
+        Assert.That(options!.SkipIndexing, Is.True);
+        Assert.That(options.ConfigPath, Is.EqualTo(Path.Combine(Path.GetFullPath(workspace), CiConfiguration.DefaultFileName)));
+    }
+}
@@ -0,0 +1,59 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Tools;
+using COA.Mcp.Framework.TokenOptimization.Models;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SchemaInfoMiddlewareTests
+{
+    [TearDown]
+    public void TearDown()
+    {
+        ToolCallNotes.Deprecations = Array.Empty<DeprecationNotice>();
+    }
+
+    [Test]
+    public async Task OnAfterExecutionAsync_Should_Stamp_The_Schema_Version_On_Any_Tool_Response()
+    {
+        // Arrange
+        var search = new AIOptimizedResponse<SearchFilesResult> { Success = true };
+        var failure = new AIOptimizedResponse<string> { Success = false, Meta = new AIResponseMeta() };
+
+        // Act
+        await new SchemaInfoMiddleware().OnAfterExecutionAsync(ToolNames.SearchFiles, null, search, 5);
+        await new SchemaInfoMiddleware().OnAfterExecutionAsync(ToolNames.TextSearch, null, failure, 5);
+
+        // Assert
+        Assert.That(search.Meta!.ExtensionData![SchemaInfoMiddleware.SchemaVersionKey], Is.EqualTo(ToolSchemaVersions.Default));
+        Assert.That(failure.Meta!.ExtensionData![SchemaInfoMiddleware.SchemaVersionKey], Is.EqualTo(ToolSchemaVersions.Default));
+        Assert.That(search.Insights, Is.Null.Or.Empty, "nothing to warn about");
+    }
+
+    [Test]
+    public async Task OnAfterExecutionAsync_Should_Warn_Once_About_Deprecated_Parameters()
+    {
+        // Arrange
+        var notice = new DeprecationNotice { ToolName = "list_things", Parameter = "extensionFilter", ReplacedBy = "extensions", Since = "2" };
+        ToolCallNotes.Deprecations = new[] { notice };
+        var response = new AIOptimizedResponse<string> { Success = true };
+        var middleware = new SchemaInfoMiddleware();
+
+        // Act - a cached response goes through again
+        await middleware.OnAfterExecutionAsync("list_things", null, response, 5);
+        await middleware.OnAfterExecutionAsync("list_things", null, response, 5);
+
+        // Assert
+        Assert.That(response.Insights, Is.EqualTo(new[] { $"⚠️ {notice}" }));
+        Assert.That(response.Meta!.ExtensionData![SchemaInfoMiddleware.DeprecationsKey], Is.EqualTo(new[] { notice }));
+    }
+
+    [Test]
+    public void OnAfterExecutionAsync_Should_Ignore_Results_Without_Meta()
+    {
+        // Act & Assert
+        Assert.DoesNotThrowAsync(() => new SchemaInfoMiddleware().OnAfterExecutionAsync(ToolNames.TextSearch, null, "plain", 5));
+        Assert.DoesNotThrowAsync(() => new SchemaInfoMiddleware().OnAfterExecutionAsync(ToolNames.TextSearch, null, null, 5));
+    }
+}
@@ -0,0 +1,108 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ScratchWorkspaceServiceTests
+{
+    private string _workspace = null!;
+    private ScratchWorkspaceService _scratch = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "scratch_test_" + Guid.NewGuid().ToString("N"));
+        Directory.CreateDirectory(_workspace);
+        _scratch = new ScratchWorkspaceService(new ConfigurationBuilder().Build(), NullLogger<ScratchWorkspaceService>.Instance);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        if (Directory.Exists(_workspace))
+            Directory.Delete(_workspace, true);
+    }
+
+    [Test]
+    public void Diff_Should_Report_Added_And_Modified_Files_Without_Touching_Disk()
+    {
+        // Arrange
+        File.WriteAllText(Path.Combine(_workspace, "a.txt"), "one\ntwo\nthree\n");
+        _scratch.Stage(_workspace, "a.txt", "one\n2\nthree\n");
+        _scratch.Stage(_workspace, Path.Combine(_workspace, "src", "new.txt"), "hello\n", "generated helper");
+
+        // Act
+        var diffs = _scratch.Diff(_workspace);
+
+        // Assert
+        Assert.That(diffs.Select(d => (d.FilePath, d.Status)), Is.EqualTo(new[] { ("a.txt", "modified"), ("src/new.txt", "added") }));
+        Assert.That(diffs[0].Diff, Does.Contain("-two\n+2\n"));
+        Assert.That(diffs[1].Note, Is.EqualTo("generated helper"));
+        Assert.That(diffs.Any(d => d.Conflict), Is.False);
+        Assert.That(File.ReadAllText(Path.Combine(_workspace, "a.txt")), Is.EqualTo("one\ntwo\nthree\n"));
+        Assert.That(File.Exists(Path.Combine(_workspace, "src", "new.txt")), Is.False);
+    }
+
+    [Test]
+    public void Diff_Should_Flag_Conflict_When_File_Changes_After_Staging()
+    {
+        // Arrange
+        var path = Path.Combine(_workspace, "a.txt");
+        File.WriteAllText(path, "original\n");
+        _scratch.Stage(_workspace, "a.txt", "staged\n");
+
+        // Act
+        File.WriteAllText(path, "edited by someone else\n");
+        _scratch.Stage(_workspace, "a.txt", "staged again\n");
+
+        // Assert
+        Assert.That(_scratch.Diff(_workspace).Single().Conflict, Is.True, "Re-staging keeps the original base");
+    }
+
+    [Test]
+    public void Stage_Should_Reject_Paths_Outside_Workspace()
+    {
+        // Act & Assert
+        var ex = Assert.Throws<ScratchException>(() => _scratch.Stage(_workspace, "../outside.txt", "x"));
+        Assert.That(ex!.Code, Is.EqualTo("PATH_OUTSIDE_WORKSPACE"));
+    }
+
+    [Test]
+    public void Search_Should_Find_Lines_In_Staged_Content()
+    {
+        // Arrange
+        _scratch.Stage(_workspace, "b.cs", "class B\n{\n    // TODO: cache\n}\n");
+        _scratch.Stage(_workspace, "gone.cs", null);
+
+        // Act
+        var matches = _scratch.Search(_workspace, "todo", isRegex: false, caseSensitive: false, maxResults: 10);
+
+        // Assert
+        Assert.That(matches.Single().FilePath, Is.EqualTo("b.cs"));
+        Assert.That(matches.Single().LineNumber, Is.EqualTo(3));
+    }
+
+    [TestCase("insert", 2, null, "a\nX\nb\nc\n")]
+    [TestCase("insert", 4, null, "a\nb\nc\nX\n")]
+    [TestCase("replace", 2, 3, "a\nX\n")]
+    [TestCase("delete", 1, null, "b\nc\n")]
+    public void ApplyLineEdit_Should_Edit_Lines_And_Keep_Trailing_Newline(string operation, int start, int? end, string expected)
+    {
+        // Act
+        var result = ScratchWorkspaceService.ApplyLineEdit("a\nb\nc\n", operation, start, end, operation == "delete" ? null : "X");
+
+        // Assert
+        Assert.That(result, Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void ApplyLineEdit_Should_Reject_Out_Of_Range_Lines()
+    {
+        // Act & Assert
+        Assert.Throws<ScratchException>(() => ScratchWorkspaceService.ApplyLineEdit("a\nb\n", "replace", 2, 5, "X"));
+        Assert.Throws<ScratchException>(() => ScratchWorkspaceService.ApplyLineEdit("a\nb\n", "insert", 4, null, "X"));
+    }
+}
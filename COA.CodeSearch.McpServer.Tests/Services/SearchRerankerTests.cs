@@ -0,0 +1,132 @@
+using NUnit.Framework;
+using Moq;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sampling;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SearchRerankerTests
+{
+    private static SearchResult CreateResult(int count) => new()
+    {
+        TotalHits = count,
+        Hits = Enumerable.Range(1, count)
+            .Select(i => new SearchHit { FilePath = $"/src/File{i}.cs", LineNumber = i * 10, Score = 1f / i, Snippet = $"snippet {i}" })
+            .ToList()
+    };
+
+    private static SearchReranker CreateReranker(ISamplingClient client) =>
+        new(client, new ConfigurationBuilder().Build(), NullLogger<SearchReranker>.Instance);
+
+    [Test]
+    public void ParseAnswer_Should_Read_Fenced_Json_And_Drop_Invalid_Ids()
+    {
+        // Arrange
+        var text = "```json\n{\"ranking\":[{\"id\":3,\"reason\":\"deactivates users\"},{\"id\":1},{\"id\":3},{\"id\":9}],\"filtered\":[2]}\n```";
+
+        // Act
+        var answer = SearchReranker.ParseAnswer(text, 4);
+
+        // Assert
+        Assert.That(answer, Is.Not.Null);
+        Assert.That(answer!.Ranked.Select(r => r.Id), Is.EqualTo(new[] { 3, 1 }));
+        Assert.That(answer.Ranked[0].Reason, Is.EqualTo("deactivates users"));
+        Assert.That(answer.Filtered, Is.EqualTo(new[] { 2 }));
+    }
+
+    [Test]
+    public void ParseAnswer_Should_Fall_Back_To_Number_List()
+    {
+        // Act
+        var answer = SearchReranker.ParseAnswer("Best matches: 2, 4, 1", 4);
+
+        // Assert
+        Assert.That(answer!.Ranked.Select(r => r.Id), Is.EqualTo(new[] { 2, 4, 1 }));
+        Assert.That(answer.Filtered, Is.Empty);
+    }
+
+    [Test]
+    public void ParseAnswer_Should_Return_Null_When_Nothing_Usable()
+    {
+        // Act & Assert
+        Assert.That(SearchReranker.ParseAnswer("I cannot help with that.", 4), Is.Null);
+        Assert.That(SearchReranker.ParseAnswer("{\"ranking\":[]}", 4), Is.Null);
+    }
+
+    [Test]
+    public async Task RerankAsync_Should_Reorder_Filter_And_Keep_Raw_Order()
+    {
+        // Arrange
+        var client = new Mock<ISamplingClient>();
+        client.Setup(c => c.IsAvailable).Returns(true);
+        client.Setup(c => c.CreateMessageAsync(It.IsAny<SamplingRequest>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync(new SamplingResponse { Text = "{\"ranking\":[{\"id\":3,\"reason\":\"exact match\"}],\"filtered\":[1]}", Model = "test-model" });
+        var result = CreateResult(4);
+
+        // Act
+        var reranking = await CreateReranker(client.Object).RerankAsync(result, "where are users deactivated", CancellationToken.None);
+
+        // Assert
+        Assert.That(reranking.Applied, Is.True);
+        Assert.That(reranking.Model, Is.EqualTo("test-model"));
+        Assert.That(reranking.RawOrder.Select(h => h.RawRank), Is.EqualTo(new[] { 1, 2, 3, 4 }));
+        Assert.That(reranking.RerankedOrder.Select(h => h.RawRank), Is.EqualTo(new[] { 3, 2, 4 }), "Unmentioned candidates follow in raw order");
+        Assert.That(reranking.RerankedOrder[0].Reason, Is.EqualTo("exact match"));
+        Assert.That(reranking.Filtered.Single().FilePath, Is.EqualTo("/src/File1.cs"));
+        Assert.That(result.Hits.Select(h => h.FilePath), Is.EqualTo(new[] { "/src/File3.cs", "/src/File2.cs", "/src/File4.cs" }));
+        Assert.That(result.TotalHits, Is.EqualTo(3));
+        Assert.That(result.Reranking, Is.SameAs(reranking));
+    }
+
+    [Test]
+    public async Task RerankAsync_Should_Keep_Raw_Order_When_Sampling_Fails()
+    {
+        // Arrange
+        var client = new Mock<ISamplingClient>();
+        client.Setup(c => c.IsAvailable).Returns(true);
+        client.Setup(c => c.CreateMessageAsync(It.IsAny<SamplingRequest>(), It.IsAny<CancellationToken>()))
+            .ThrowsAsync(new SamplingException("Client rejected sampling request: user declined"));
+        var result = CreateResult(3);
+
+        // Act
+        var reranking = await CreateReranker(client.Object).RerankAsync(result, "user cache", CancellationToken.None);
+
+        // Assert
+        Assert.That(reranking.Applied, Is.False);
+        Assert.That(reranking.FallbackReason, Does.Contain("user declined"));
+        Assert.That(result.Hits.Select(h => h.FilePath), Is.EqualTo(new[] { "/src/File1.cs", "/src/File2.cs", "/src/File3.cs" }));
+    }
+
+    [Test]
+    public async Task SamplingClient_Should_Round_Trip_Request_Through_Intercepted_Stdin()
+    {
+        // Arrange
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:Sampling:Enabled"] = "true" })
+            .Build();
+        var output = new StringWriter();
+        var channel = new McpClientRequestChannel(NullLogger<McpClientRequestChannel>.Instance, output);
+        var client = new McpSamplingClient(configuration, channel);
+
+        var initialize = "{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"initialize\",\"params\":{\"capabilities\":{\"sampling\":{}}}}";
+        Assert.That(channel.TryHandleInbound(initialize), Is.False, "initialize must still reach the transport");
+        Assert.That(client.IsAvailable, Is.True);
+
+        // Act
+        var pending = client.CreateMessageAsync(new SamplingRequest { UserMessage = "rank these" });
+        Assert.That(output.ToString(), Does.Contain("\"method\":\"sampling/createMessage\""));
+        var consumed = channel.TryHandleInbound(
+            "{\"jsonrpc\":\"2.0\",\"id\":\"codesearch-request-1\",\"result\":{\"role\":\"assistant\",\"content\":{\"type\":\"text\",\"text\":\"[2,1]\"},\"model\":\"m\"}}");
+        var response = await pending;
+
+        // Assert
+        Assert.That(consumed, Is.True);
+        Assert.That(response.Text, Is.EqualTo("[2,1]"));
+        Assert.That(response.Model, Is.EqualTo("m"));
+        Assert.That(channel.TryHandleInbound("{\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{}}"), Is.False);
+    }
+}
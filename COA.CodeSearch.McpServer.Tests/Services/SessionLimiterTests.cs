@@ -0,0 +1,66 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.RateLimiting;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SessionLimiterTests
+{
+    [Test]
+    public void TokenBucket_Should_Allow_Burst_Then_Refuse_With_Time_Until_Next_Request()
+    {
+        // Arrange
+        var start = new DateTime(2024, 1, 1, 0, 0, 0, DateTimeKind.Utc);
+        var bucket = new TokenBucket(requestsPerMinute: 60, burst: 3, start);
+
+        // Act
+        var admitted = Enumerable.Range(0, 3).Count(i => bucket.TryTake(start, out _));
+        var refused = !bucket.TryTake(start, out var retryAfter);
+        var refilled = bucket.TryTake(start.AddSeconds(1), out _);
+
+        // Assert
+        Assert.That(admitted, Is.EqualTo(3));
+        Assert.That(refused, Is.True);
+        Assert.That(retryAfter, Is.EqualTo(TimeSpan.FromSeconds(1)));
+        Assert.That(refilled, Is.True);
+    }
+
+    [Test]
+    public async Task FairScheduler_Should_Queue_Beyond_Share_And_Refuse_When_Queue_Is_Full()
+    {
+        // Arrange
+        var scheduler = new FairScheduler(share: 1, maxQueued: 1, queueTimeout: TimeSpan.FromSeconds(10));
+        var first = await scheduler.EnterAsync();
+
+        // Act
+        var queued = scheduler.EnterAsync();
+        var refused = Assert.ThrowsAsync<RateLimitExceededException>(() => scheduler.EnterAsync());
+        var waitedBeforeRelease = queued.IsCompleted;
+        first.Dispose();
+        using var second = await queued;
+
+        // Assert
+        Assert.That(waitedBeforeRelease, Is.False);
+        Assert.That(refused!.RetryAfterSeconds, Is.GreaterThanOrEqualTo(1));
+        Assert.That(refused.Message, Does.Contain("Retry after"));
+        Assert.That(scheduler.Demand, Is.EqualTo(1));
+    }
+
+    [Test]
+    public async Task FairScheduler_Should_Refuse_Call_That_Waits_Past_Timeout_And_Divide_Slots_Between_Busy_Sessions()
+    {
+        // Arrange
+        var scheduler = new FairScheduler(share: 1, maxQueued: 4, queueTimeout: TimeSpan.FromMilliseconds(50));
+        using var running = await scheduler.EnterAsync();
+
+        // Act
+        var timedOut = Assert.ThrowsAsync<RateLimitExceededException>(() => scheduler.EnterAsync());
+
+        // Assert
+        Assert.That(timedOut!.Reason, Does.Contain("No query slot became free"));
+        Assert.That(scheduler.Demand, Is.EqualTo(1));
+        Assert.That(FairScheduler.FairShare(8, 1), Is.EqualTo(8));
+        Assert.That(FairScheduler.FairShare(8, 3), Is.EqualTo(2));
+        Assert.That(FairScheduler.FairShare(8, 20), Is.EqualTo(1));
+    }
+}
@@ -0,0 +1,79 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class ShutdownCoordinatorTests
+{
+    private ShutdownCoordinator _coordinator = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        var configuration = new ConfigurationBuilder()
+            .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:Shutdown:TimeoutSeconds"] = "5" })
+            .Build();
+        _coordinator = new ShutdownCoordinator(configuration, NullLogger<ShutdownCoordinator>.Instance);
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        _coordinator.Dispose();
+    }
+
+    [Test]
+    public void BeginShutdown_Should_Cancel_The_Shutdown_Token_Once()
+    {
+        // Act
+        var first = _coordinator.BeginShutdown();
+        var second = _coordinator.BeginShutdown();
+
+        // Assert
+        Assert.That(first, Is.True);
+        Assert.That(second, Is.False);
+        Assert.That(_coordinator.IsShuttingDown, Is.True);
+        Assert.That(_coordinator.ShutdownToken.IsCancellationRequested, Is.True);
+        Assert.That(_coordinator.Timeout, Is.EqualTo(TimeSpan.FromSeconds(5)));
+    }
+
+    [Test]
+    public async Task WaitForOperationsAsync_Should_Return_When_Tracked_Operations_Finish()
+    {
+        // Arrange
+        var indexing = _coordinator.TrackOperation("index:/ws");
+        using (_coordinator.TrackOperation("scan:/ws"))
+        {
+            Assert.That(_coordinator.GetActiveOperations(), Is.EquivalentTo(new[] { "index:/ws", "scan:/ws" }));
+        }
+
+        // Act
+        var wait = _coordinator.WaitForOperationsAsync(CancellationToken.None);
+        await Task.Delay(100);
+        var waitingBeforeDispose = !wait.IsCompleted;
+        indexing.Dispose();
+
+        // Assert
+        Assert.That(waitingBeforeDispose, Is.True);
+        Assert.That(await wait, Is.True);
+        Assert.That(_coordinator.GetActiveOperations(), Is.Empty);
+    }
+
+    [Test]
+    public async Task WaitForOperationsAsync_Should_Give_Up_When_The_Token_Fires()
+    {
+        // Arrange
+        using var stuck = _coordinator.TrackOperation("index:/ws");
+        using var cts = new CancellationTokenSource(TimeSpan.FromMilliseconds(100));
+
+        // Act
+        var finished = await _coordinator.WaitForOperationsAsync(cts.Token);
+
+        // Assert
+        Assert.That(finished, Is.False);
+        Assert.That(_coordinator.GetActiveOperations(), Is.EqualTo(new[] { "index:/ws" }));
+    }
+}
@@ -0,0 +1,96 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SmartSearchPlannerTests
+{
+    [TestCase("deactivated", "deactivat")]
+    [TestCase("deactivate", "deactivat")]
+    [TestCase("deactivation", "deactiv")]
+    [TestCase("users", "user")]
+    [TestCase("categories", "category")]
+    [TestCase("settings", "set")]
+    [TestCase("running", "run")]
+    [TestCase("caching", "cach")]
+    [TestCase("address", "address")]
+    [TestCase("Cached", "cach")]
+    public void ToPrefix_Should_Reduce_Word_Forms_To_A_Shared_Prefix(string word, string expected)
+    {
+        // Act & Assert
+        Assert.That(SmartSearchPlanner.ToPrefix(word), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void Plan_Should_Split_Clauses_And_Carry_The_Subject_To_A_Lone_Verb()
+    {
+        // Act
+        var plan = SmartSearchPlanner.Plan("where are users deactivated and cached");
+
+        // Assert
+        Assert.That(plan.Clauses.Select(c => string.Join(" ", c.Concepts.Select(k => k.Prefix))), Is.EqualTo(new[]
+        {
+            "user deactivat",
+            "user cach"
+        }));
+        Assert.That(plan.Clauses[0].Inherited, Is.Null);
+        Assert.That(plan.Clauses[1].Inherited, Is.EqualTo("users"));
+        Assert.That(plan.Steps.Select(s => $"{s.Id} {s.Clause} {s.Field}"), Is.EqualTo(new[]
+        {
+            "1 0 content_symbols",
+            "2 0 content",
+            "3 1 content_symbols",
+            "4 1 content"
+        }));
+        Assert.That(plan.Steps[0].Query,
+            Is.EqualTo("content_symbols:user* AND (content_symbols:deactivat* OR content_symbols:disabl* OR content_symbols:suspend*)"));
+    }
+
+    [Test]
+    public void Plan_Should_Drop_Stop_Words_And_Synonyms_Of_Words_Already_In_The_Clause()
+    {
+        // Act
+        var plan = SmartSearchPlanner.Plan("find the code that loads configuration settings");
+
+        // Assert
+        Assert.That(plan.Clauses.Single().Concepts.Select(c => c.Word), Is.EqualTo(new[] { "loads", "configuration" }));
+    }
+
+    [Test]
+    public void Plan_Should_Split_Identifiers_Into_Words_And_Skip_Synonyms_When_Asked()
+    {
+        // Act
+        var plan = SmartSearchPlanner.Plan("UserCache invalidation", expandSynonyms: false);
+
+        // Assert
+        var concepts = plan.Clauses.Single().Concepts;
+        Assert.That(concepts.Select(c => c.Prefix), Is.EqualTo(new[] { "user", "cach", "invalid" }));
+        Assert.That(concepts.All(c => c.Alternatives.Count == 0), Is.True);
+    }
+
+    [Test]
+    public void Plan_Should_Stop_At_The_Maximum_Number_Of_Clauses()
+    {
+        // Act
+        var plan = SmartSearchPlanner.Plan("orders, invoices, payments, refunds, shipments");
+
+        // Assert
+        Assert.That(plan.Clauses.Count, Is.EqualTo(SmartSearchPlanner.MaxClauses));
+        Assert.That(SmartSearchPlanner.Plan("how is it done").Steps, Is.Empty);
+    }
+
+    [Test]
+    public void ToLuceneQuery_Should_Require_Every_Concept_By_Any_Of_Its_Prefixes()
+    {
+        // Arrange
+        var plan = SmartSearchPlanner.Plan("users deactivated");
+
+        // Act
+        var query = SmartSearchPlanner.ToLuceneQuery(plan.Steps[0], plan.Clauses[0]);
+
+        // Assert
+        Assert.That(query.ToString(),
+            Is.EqualTo("+(content_symbols:user*) +(content_symbols:deactivat* content_symbols:disabl* content_symbols:suspend*)"));
+    }
+}
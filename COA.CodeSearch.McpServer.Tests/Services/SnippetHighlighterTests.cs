@@ -0,0 +1,91 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SnippetHighlighterTests
+{
+    private static string Render(List<SnippetSegment> segments) =>
+        string.Join(" ", segments.Where(s => s.Kind != SnippetTokenKinds.Text).Select(s => $"{s.Kind}:{s.Text}"));
+
+    [Test]
+    public void Classify_Should_Give_Back_Each_Line_When_Segments_Are_Joined()
+    {
+        // Arrange
+        var lines = new[] { "    var total = items.Sum(i => i.Price); // \"quoted\"", "", "\tif (x != 0x1F) return 'c';" };
+
+        // Act
+        var classified = SnippetHighlighter.Classify(lines, "Order.cs");
+
+        // Assert
+        Assert.That(classified.Select(l => string.Concat(l.Select(s => s.Text))), Is.EqualTo(lines));
+    }
+
+    [Test]
+    public void Classify_Should_Tell_Keywords_Types_Functions_And_Literals_Apart()
+    {
+        // Act
+        var line = SnippetHighlighter.Classify(new[] { "public static User Find(string name) => Load(\"users\", 42);" }, "Repo.cs").Single();
+
+        // Assert
+        Assert.That(Render(line), Is.EqualTo(
+            "keyword:public keyword:static type:User function:Find punctuation:( keyword:string identifier:name punctuation:) " +
+            "operator:=> function:Load punctuation:( string:\"users\" punctuation:, number:42 punctuation:);"));
+    }
+
+    [Test]
+    public void Classify_Should_Carry_Block_Comments_Across_Lines()
+    {
+        // Act
+        var classified = SnippetHighlighter.Classify(new[] { "int a; /* starts", "still inside */ int b;" }, "a.c");
+
+        // Assert
+        Assert.That(Render(classified[0]), Is.EqualTo("keyword:int identifier:a punctuation:; comment:/* starts"));
+        Assert.That(Render(classified[1]), Is.EqualTo("comment:still inside */ keyword:int identifier:b punctuation:;"));
+    }
+
+    [Test]
+    public void Classify_Should_Not_Read_A_Rust_Lifetime_As_A_String()
+    {
+        // Act
+        var line = SnippetHighlighter.Classify(new[] { "fn first<'a>(s: &'a str) -> char { 'x' }" }, "lib.rs").Single();
+
+        // Assert
+        Assert.That(line.Where(s => s.Kind == SnippetTokenKinds.String).Select(s => s.Text), Is.EqualTo(new[] { "'x'" }));
+    }
+
+    [TestCase("query.sql", "select id from users -- all", "keyword:select identifier:id keyword:from identifier:users comment:-- all")]
+    [TestCase("deploy.py", "if Ready: run() # now", "keyword:if type:Ready operator:: function:run punctuation:() comment:# now")]
+    [TestCase("data.json", "{\"on\": true}", "punctuation:{ string:\"on\" operator:: keyword:true punctuation:}")]
+    public void Classify_Should_Use_The_Language_Family_Of_The_Extension(string filePath, string text, string expected)
+    {
+        // Act & Assert
+        Assert.That(Render(SnippetHighlighter.Classify(new[] { text }, filePath).Single()), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void Apply_Should_Colour_Context_Lines_For_Ansi_And_Attach_Segments_For_Classified()
+    {
+        // Arrange
+        SearchHit CreateHit() => new() { FilePath = "/src/Order.cs", ContextLines = new List<string> { "return null;" } };
+        var plain = CreateHit();
+        var ansi = CreateHit();
+        var classified = CreateHit();
+
+        // Act
+        SnippetHighlighter.Apply(plain, "plain");
+        SnippetHighlighter.Apply(ansi, "ANSI");
+        SnippetHighlighter.Apply(classified, "classified");
+
+        // Assert
+        Assert.That(plain.ContextLines, Is.EqualTo(new[] { "return null;" }));
+        Assert.That(plain.ClassifiedLines, Is.Null);
+        Assert.That(ansi.ContextLines, Is.EqualTo(new[] { "\u001b[35mreturn\u001b[0m \u001b[35mnull\u001b[0m;" }));
+        Assert.That(classified.ContextLines, Is.EqualTo(new[] { "return null;" }));
+        Assert.That(Render(classified.ClassifiedLines!.Single()), Is.EqualTo("keyword:return keyword:null punctuation:;"));
+        Assert.That(SnippetHighlighter.IsValidFormat("Classified"), Is.True);
+        Assert.That(SnippetHighlighter.IsValidFormat("html"), Is.False);
+    }
+}
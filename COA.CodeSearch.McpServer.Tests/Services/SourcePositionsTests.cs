@@ -0,0 +1,97 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SourcePositionsTests
+{
+    // "é" is 2 bytes / 1 code unit, "😀" is 4 bytes / 2 code units
+    private const string Line = "var caf\u00e9 = \"\ud83d\ude00\"; Greet(caf\u00e9);";
+
+    [TestCase(0, 0)]
+    [TestCase(4, 4)]    // start of café
+    [TestCase(9, 8)]    // after café: é took two bytes
+    [TestCase(13, 12)]  // start of the emoji
+    [TestCase(17, 14)]  // after the emoji: four bytes, two code units
+    [TestCase(20, 17)]  // Greet
+    public void ByteToUtf16Column_Should_Count_Multibyte_Characters(int byteColumn, int expected)
+    {
+        // Act & Assert
+        Assert.That(SourcePositions.ByteToUtf16Column(Line, byteColumn), Is.EqualTo(expected));
+    }
+
+    [TestCase(0, 0)]
+    [TestCase(8, 9)]
+    [TestCase(14, 17)]
+    [TestCase(13, 13)]  // inside the surrogate pair snaps to its start
+    public void Utf16ToByteColumn_Should_Count_Multibyte_Characters(int utf16Column, int expected)
+    {
+        // Act & Assert
+        Assert.That(SourcePositions.Utf16ToByteColumn(Line, utf16Column), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void Conversions_Should_Round_Trip_At_Character_Boundaries()
+    {
+        // Act & Assert
+        for (var column = 0; column <= Line.Length; column++)
+        {
+            if (column < Line.Length && char.IsLowSurrogate(Line[column]))
+                continue;
+
+            var bytes = SourcePositions.Utf16ToByteColumn(Line, column);
+            Assert.That(SourcePositions.ByteToUtf16Column(Line, bytes), Is.EqualTo(column), $"column {column}");
+        }
+    }
+
+    [Test]
+    public void TryParseLocation_Should_Accept_Windows_Paths_And_Reject_Names()
+    {
+        // Act & Assert
+        Assert.That(SourcePositions.TryParseLocation(@"C:\src\App.cs:12:5", out var path, out var line, out var column), Is.True);
+        Assert.That(path, Is.EqualTo(@"C:\src\App.cs"));
+        Assert.That(line, Is.EqualTo(12));
+        Assert.That(column, Is.EqualTo(5));
+
+        Assert.That(SourcePositions.TryParseLocation("UserService", out _, out _, out _), Is.False);
+        Assert.That(SourcePositions.TryParseLocation("src/App.cs:0:5", out _, out _, out _), Is.False);
+    }
+
+    [TestCase(27, "utf-16", "caf\u00e9")]  // cursor just past "café" in "Greet(café)"
+    [TestCase(17, "utf-16", "Greet")]
+    [TestCase(20, "utf-8", "Greet")]
+    [TestCase(10, "utf-16", null)]      // just after the '='
+    public async Task GetIdentifierAtAsync_Should_Resolve_Position_In_Either_Encoding(int column, string encoding, string? expected)
+    {
+        // Arrange
+        var positions = new SourcePositions((_, _) => Task.FromResult<string?>("// header\r\n" + Line));
+
+        // Act
+        var identifier = await positions.GetIdentifierAtAsync("src/App.cs", 2, column, encoding);
+
+        // Assert
+        Assert.That(identifier, Is.EqualTo(expected));
+    }
+
+    [Test]
+    public async Task ToUtf16ColumnAsync_Should_Fall_Back_To_Byte_Column_When_File_Is_Missing()
+    {
+        // Arrange
+        var loads = 0;
+        var positions = new SourcePositions((_, _) =>
+        {
+            loads++;
+            return Task.FromResult<string?>(null);
+        });
+
+        // Act
+        var first = await positions.ToUtf16ColumnAsync("gone.cs", 3, 7);
+        var second = await positions.ToUtf16ColumnAsync("gone.cs", 4, 9);
+
+        // Assert
+        Assert.That(first, Is.EqualTo(7));
+        Assert.That(second, Is.EqualTo(9));
+        Assert.That(loads, Is.EqualTo(1), "lines are cached per file");
+    }
+}
@@ -0,0 +1,140 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Lucene.Net.Analysis.Standard;
+using Lucene.Net.Documents;
+using Lucene.Net.Index;
+using Lucene.Net.Store;
+using Lucene.Net.Util;
+using System.Diagnostics;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class StoredFieldCompressionTests
+{
+    private static long WriteAndMeasure(RAMDirectory directory, string? mode, IReadOnlyList<string> contents)
+    {
+        var config = new IndexWriterConfig(LuceneVersion.LUCENE_48, new StandardAnalyzer(LuceneVersion.LUCENE_48));
+        var codec = StoredFieldCompression.CreateCodec(mode);
+        if (codec != null)
+            config.Codec = codec;
+
+        using (var writer = new IndexWriter(directory, config))
+        {
+            for (var i = 0; i < contents.Count; i++)
+            {
+                writer.AddDocument(new Document
+                {
+                    new StringField("path", $"/src/File{i}.cs", Field.Store.YES),
+                    new TextField("content", contents[i], Field.Store.YES)
+                });
+            }
+            writer.Commit();
+        }
+
+        return directory.ListAll().Where(f => f.EndsWith(".fdt")).Sum(directory.FileLength);
+    }
+
+    private static List<string> SourceFiles() => Enumerable.Range(0, 200)
+        .Select(i => $"// Copyright (c) Contoso. Licensed under the MIT license.\nusing System;\nusing System.Linq;\n\n" +
+                     $"namespace Contoso.Billing;\n\npublic class Invoice{i}\n{{\n    public decimal Total{i}(decimal amount) => amount * {i};\n}}\n")
+        .ToList();
+
+    [TestCase(StoredFieldCompression.Lz4HighCompression)]
+    [TestCase(StoredFieldCompression.Brotli)]
+    [TestCase(StoredFieldCompression.Deflate)]
+    public void Mode_Should_Store_Source_Smaller_Than_Lz4_And_Read_Back(string mode)
+    {
+        // Arrange
+        var contents = SourceFiles();
+        using var lz4 = new RAMDirectory();
+        using var compressed = new RAMDirectory();
+
+        // Act
+        var lz4Bytes = WriteAndMeasure(lz4, StoredFieldCompression.Lz4, contents);
+        var compressedBytes = WriteAndMeasure(compressed, mode, contents);
+
+        // Assert
+        Assert.That(compressedBytes, Is.LessThan(lz4Bytes));
+        using var reader = DirectoryReader.Open(compressed);
+        Assert.That(reader.Document(7).Get("content"), Is.EqualTo(contents[7]));
+        Assert.That(reader.Document(199).Get("content"), Is.EqualTo(contents[199]));
+    }
+
+    [Test]
+    public void Index_Should_Stay_Readable_When_Compression_Changes()
+    {
+        // Arrange
+        var contents = SourceFiles();
+        using var directory = new RAMDirectory();
+        WriteAndMeasure(directory, StoredFieldCompression.Deflate, contents.Take(100).ToList());
+
+        // Act - new segments use the default codec beside the high-compression ones
+        WriteAndMeasure(directory, StoredFieldCompression.Lz4, contents.Skip(100).ToList());
+
+        // Assert
+        using var reader = DirectoryReader.Open(directory);
+        Assert.That(reader.NumDocs, Is.EqualTo(200));
+        Assert.That(reader.Document(3).Get("content"), Is.EqualTo(contents[3]));
+        Assert.That(reader.Document(150).Get("content"), Is.EqualTo(contents[150]));
+    }
+
+    [Test]
+    public void CreateCodec_Should_Reject_Unknown_Modes()
+    {
+        // Act & Assert
+        Assert.Throws<ArgumentException>(() => StoredFieldCompression.CreateCodec("zstd"));
+        Assert.That(StoredFieldCompression.CreateCodec(null), Is.Null);
+    }
+
+    [Test]
+    [Category("Performance")]
+    public void Benchmark_Should_Report_Size_Indexing_Time_And_Retrieval_Latency_For_Each_Mode()
+    {
+        // Arrange - the server's own source is a realistic corpus; RAMDirectory keeps disk speed out of the
+        // comparison, leaving the compression work that differs between modes
+        var sourceRoot = Path.Combine(TestContext.CurrentContext.TestDirectory, "..", "..", "..", "..", "COA.CodeSearch.McpServer");
+        if (!System.IO.Directory.Exists(sourceRoot))
+            Assert.Ignore($"Source tree not found at {sourceRoot}");
+
+        var contents = System.IO.Directory.EnumerateFiles(sourceRoot, "*.cs", SearchOption.AllDirectories)
+            .Where(f => !f.Contains($"{Path.DirectorySeparatorChar}obj{Path.DirectorySeparatorChar}") &&
+                        !f.Contains($"{Path.DirectorySeparatorChar}bin{Path.DirectorySeparatorChar}"))
+            .OrderBy(f => f, StringComparer.Ordinal)
+            .Select(File.ReadAllText)
+            .ToList();
+        var sourceBytes = contents.Sum(c => (long)System.Text.Encoding.UTF8.GetByteCount(c));
+        var random = new Random(42);
+        var hits = Enumerable.Range(0, 2000).Select(_ => random.Next(contents.Count)).ToList();
+
+        TestContext.WriteLine($"Stored Field Compression Benchmark: {contents.Count} files, {sourceBytes / 1024} KB of source");
+        TestContext.WriteLine($"  {"Mode",-8} {"Index KB",10} {"Stored KB",10} {"% source",9} {"Index ms",9} {"Load us/hit",12}");
+
+        foreach (var mode in StoredFieldCompression.Modes)
+        {
+            using var directory = new RAMDirectory();
+
+            // Act - indexing time
+            var indexing = Stopwatch.StartNew();
+            var storedBytes = WriteAndMeasure(directory, mode, contents);
+            indexing.Stop();
+            var indexBytes = directory.ListAll().Sum(directory.FileLength);
+
+            // Act - retrieval latency: loading each hit's content, as search results do for line numbers and snippets
+            using var reader = DirectoryReader.Open(directory);
+            foreach (var doc in hits.Take(200))
+            {
+                reader.Document(doc); // warm up
+            }
+            var retrieval = Stopwatch.StartNew();
+            var loaded = hits.Select(doc => reader.Document(doc).Get("content")).ToList();
+            retrieval.Stop();
+
+            // Assert
+            Assert.That(loaded, Is.EqualTo(hits.Select(doc => contents[doc])), mode);
+
+            TestContext.WriteLine($"  {mode,-8} {indexBytes / 1024,10} {storedBytes / 1024,10} {100.0 * indexBytes / sourceBytes,8:F1}% " +
+                                  $"{indexing.ElapsedMilliseconds,9} {retrieval.Elapsed.TotalMilliseconds * 1000 / hits.Count,12:F1}");
+        }
+    }
+}
@@ -0,0 +1,134 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SvelteSymbolsTests
+{
+    private const string LegacyComponent = """
+        <script context="module">
+          export const prerender = true;
+        </script>
+
+        <script lang="ts">
+          import { createEventDispatcher } from 'svelte';
+
+          export let title: string;
+          export let count = 0; // starts at zero
+          let klass = '';
+          export { klass as class };
+
+          $: doubled = count * 2;
+          $: ({ first, last } = split(title));
+          $: if (count > 10) {
+            console.warn('too many');
+          }
+
+          function increment() {
+            count += 1;
+          }
+        </script>
+
+        <button class={klass} on:click={increment}>{title}: {doubled}</button>
+        """;
+
+    [Test]
+    public void Extract_Should_Read_Exported_Props_Reactive_Statements_And_Declarations_At_Their_File_Lines()
+    {
+        // Act
+        var symbols = SvelteSymbols.Extract("src/lib/click-counter.svelte", LegacyComponent);
+
+        // Assert
+        var component = symbols.Single(s => s.Kind == "class");
+        Assert.That(component.Name, Is.EqualTo("ClickCounter"));
+        Assert.That(component.Signature, Is.EqualTo("<ClickCounter> props: title, count, class"));
+
+        Assert.That(symbols.Where(s => s.Kind == "property").Select(s => (s.Signature, s.StartLine)), Is.EqualTo(new[]
+        {
+            ("prop title: string", 8),
+            ("prop count? = 0", 9),
+            ("prop class", 11)
+        }));
+
+        var doubled = symbols.Single(s => s.Name == "doubled");
+        Assert.That((doubled.Kind, doubled.StartLine, doubled.StartColumn, doubled.Signature), Is.EqualTo(("reactive", 13, 5, "$: doubled = count * 2;")));
+        Assert.That(symbols.Where(s => s.StartLine == 14).Select(s => s.Name), Is.EqualTo(new[] { "first", "last" }));
+        var warning = symbols.Single(s => s.Name == "$:");
+        Assert.That((warning.StartLine, warning.EndLine), Is.EqualTo((15, 17)));
+
+        var increment = symbols.Single(s => s.Name == "increment");
+        Assert.That((increment.Kind, increment.StartLine, increment.EndLine), Is.EqualTo(("function", 19, 21)));
+        Assert.That(symbols.Single(s => s.Name == "prerender").Kind, Is.EqualTo("variable"));
+        Assert.That(symbols.Where(s => s.Name != component.Name).All(s => s.ParentId == component.Id), Is.True);
+    }
+
+    [Test]
+    public void Extract_Should_Type_Rune_Props_From_Their_Interface_And_Mark_Runes_As_Reactive()
+    {
+        // Arrange
+        var content = """
+            <script lang="ts">
+              interface Props {
+                label: string;
+                // selected option
+                value?: string;
+                items?: Array<{ id: number }>;
+              }
+
+              let {
+                label,
+                value = $bindable(''),
+                items = [],
+                class: className = '',
+                ...rest
+              }: Props = $props();
+
+              let open = $state(false);
+              const visible = $derived(items.filter(i => i.id > 0));
+
+              $effect(() => {
+                if (open) console.log(value);
+              });
+            </script>
+            """;
+
+        // Act
+        var symbols = SvelteSymbols.Extract("Select.svelte", content);
+
+        // Assert
+        Assert.That(symbols.Where(s => s.Kind == "property").Select(s => (s.Signature, s.StartLine)), Is.EqualTo(new[]
+        {
+            ("prop label: string", 10),
+            ("prop value?: string = '' (bindable)", 11),
+            ("prop items?: Array<{ id: number }> = []", 12),
+            ("prop class? = ''", 13)
+        }));
+        Assert.That(symbols.Single(s => s.Name == "open").Kind, Is.EqualTo("variable"));
+        Assert.That(symbols.Single(s => s.Name == "visible").Kind, Is.EqualTo("reactive"));
+        var effect = symbols.Single(s => s.Name == "$effect");
+        Assert.That((effect.Kind, effect.StartLine, effect.EndLine), Is.EqualTo(("reactive", 20, 22)));
+        Assert.That(symbols.Any(s => s.Name == "rest"), Is.False);
+    }
+
+    [Test]
+    public void Repair_Should_Add_Missing_Symbols_And_Keep_The_List_When_Nothing_Is_Missing()
+    {
+        // Arrange
+        var indexed = new List<JulieSymbol>
+        {
+            new() { Id = "existing", Name = "increment", Kind = "function", FilePath = "counter.svelte", StartLine = 19, EndLine = 19 }
+        };
+
+        // Act
+        var repaired = SvelteSymbols.Repair("counter.svelte", LegacyComponent, indexed);
+        var unchanged = SvelteSymbols.Repair("counter.svelte", LegacyComponent, repaired);
+
+        // Assert
+        var increment = repaired.Single(s => s.Name == "increment");
+        Assert.That((increment.Id, increment.EndLine), Is.EqualTo(("existing", 21)));
+        Assert.That(repaired.Count(s => s.Kind == "property"), Is.EqualTo(3));
+        Assert.That(unchanged, Is.SameAs(repaired));
+    }
+}
@@ -0,0 +1,164 @@
+using System.Text;
+using COA.CodeSearch.McpServer.Services.Anchors;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+using NUnit.Framework;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class SymbolAnchorServiceTests
+{
+    private const string Workspace = "/test/workspace";
+    private const string Method = "public int Total(int a) { return a * 2; }";
+
+    private readonly Dictionary<string, (string Content, List<JulieSymbol> Symbols)> _files = new();
+    private Mock<ISQLiteSymbolService> _sqlite = null!;
+    private SymbolAnchorService _service = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _files.Clear();
+        _sqlite = new Mock<ISQLiteSymbolService>();
+        _sqlite.Setup(s => s.GetFileByPathAsync(Workspace, It.IsAny<string>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, string path, CancellationToken _) =>
+                _files.TryGetValue(path, out var file) ? new FileRecord(path, file.Content, "csharp", file.Content.Length, 0) : null);
+        _sqlite.Setup(s => s.GetSymbolsForFileAsync(Workspace, It.IsAny<string>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, string path, CancellationToken _) =>
+                _files.TryGetValue(path, out var file) ? file.Symbols : new List<JulieSymbol>());
+        _sqlite.Setup(s => s.GetSymbolsByNameAsync(Workspace, It.IsAny<string>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()))
+            .ReturnsAsync((string _, string name, bool _, CancellationToken _) =>
+                _files.Values.SelectMany(f => f.Symbols).Where(s => s.Name == name).ToList());
+        _service = new SymbolAnchorService(_sqlite.Object, NullLogger<SymbolAnchorService>.Instance);
+    }
+
+    private JulieSymbol AddFile(string path, string methodText, string methodName = "Total", string prefix = "")
+    {
+        var content = $"{prefix}class Cart\n{{\n    {methodText}\n}}\n";
+        var type = Symbol("type-" + path, "Cart", null, path, content, content.TrimEnd('\n'));
+        var method = Symbol("method-" + path, methodName, type.Id, path, content, methodText);
+        _files[path] = (content, new List<JulieSymbol> { type, method });
+        return method;
+    }
+
+    private static JulieSymbol Symbol(string id, string name, string? parentId, string path, string content, string text)
+    {
+        var start = Encoding.UTF8.GetByteCount(content[..content.IndexOf(text, StringComparison.Ordinal)]);
+        return new JulieSymbol
+        {
+            Id = id,
+            Name = name,
+            Kind = parentId == null ? "class" : "method",
+            FilePath = path,
+            ParentId = parentId,
+            StartByte = start,
+            EndByte = start + Encoding.UTF8.GetByteCount(text)
+        };
+    }
+
+    [Test]
+    public void TryParse_Should_Round_Trip_And_Reject_Plain_Names()
+    {
+        // Arrange
+        var anchor = new SymbolAnchor("src/My#Dir/Cart.cs", "Cart.Total", "0123456789abcdef");
+
+        // Act & Assert
+        Assert.That(SymbolAnchor.TryParse(anchor.ToString(), out var parsed), Is.True);
+        Assert.That(parsed, Is.EqualTo(anchor));
+        Assert.That(SymbolAnchor.TryParse("Cart", out _), Is.False);
+        Assert.That(SymbolAnchor.TryParse("anchor:src/Cart.cs#Cart", out _), Is.False);
+    }
+
+    [Test]
+    public async Task ResolveAsync_Should_Find_Symbol_After_Lines_Shift_And_Reformatting()
+    {
+        // Arrange
+        var anchor = (await _service.CreateAnchorAsync(Workspace, AddFile("src/Cart.cs", Method)))!;
+        AddFile("src/Cart.cs", "public int Total(int a)\n    {\n        return a * 2;\n    }", prefix: "using System;\n\n");
+
+        // Act
+        var resolution = await _service.ResolveAsync(Workspace, anchor);
+
+        // Assert
+        Assert.That(anchor.SymbolPath, Is.EqualTo("Cart.Total"));
+        Assert.That(resolution.Status, Is.EqualTo(AnchorStatus.Exact));
+        Assert.That(resolution.Symbol!.Name, Is.EqualTo("Total"));
+        Assert.That(resolution.CurrentAnchor, Is.EqualTo(anchor.ToString()));
+    }
+
+    [Test]
+    public async Task ResolveAsync_Should_Report_Edited_Body_As_Changed()
+    {
+        // Arrange
+        var anchor = (await _service.CreateAnchorAsync(Workspace, AddFile("src/Cart.cs", Method)))!;
+        AddFile("src/Cart.cs", "public int Total(int a) { return a * 3; }");
+
+        // Act
+        var resolution = await _service.ResolveAsync(Workspace, anchor);
+
+        // Assert
+        Assert.That(resolution.Status, Is.EqualTo(AnchorStatus.Changed));
+        Assert.That(resolution.CurrentAnchor, Is.Not.EqualTo(anchor.ToString()));
+    }
+
+    [Test]
+    public async Task ResolveAsync_Should_Follow_Renames_And_Moves()
+    {
+        // Arrange
+        var anchor = (await _service.CreateAnchorAsync(Workspace, AddFile("src/Cart.cs", Method)))!;
+
+        // Act: renamed in place
+        AddFile("src/Cart.cs", "public int Sum(int a) { return a * 2; }", methodName: "Sum");
+        var renamed = await _service.ResolveAsync(Workspace, anchor);
+
+        // Act: moved to another file
+        _files.Remove("src/Cart.cs");
+        AddFile("src/Orders/Cart.cs", Method);
+        var moved = await _service.ResolveAsync(Workspace, anchor);
+
+        // Assert
+        Assert.That(renamed.Status, Is.EqualTo(AnchorStatus.Renamed));
+        Assert.That(renamed.Symbol!.Name, Is.EqualTo("Sum"));
+        Assert.That(moved.Status, Is.EqualTo(AnchorStatus.Moved));
+        Assert.That(moved.Symbol!.FilePath, Is.EqualTo("src/Orders/Cart.cs"));
+    }
+
+    [Test]
+    public async Task ResolveAsync_Should_Report_Missing_When_Symbol_Is_Gone()
+    {
+        // Arrange
+        var anchor = (await _service.CreateAnchorAsync(Workspace, AddFile("src/Cart.cs", Method)))!;
+        _files.Remove("src/Cart.cs");
+
+        // Act
+        var resolution = await _service.ResolveAsync(Workspace, anchor);
+
+        // Assert
+        Assert.That(resolution.Status, Is.EqualTo(AnchorStatus.Missing));
+        Assert.That(resolution.Symbol, Is.Null);
+    }
+
+    [Test]
+    public async Task CreateAnchorAsync_Should_Qualify_Go_Methods_By_Receiver()
+    {
+        // Arrange
+        const string userGet = "func (s *UserService) Get(id int) *User { return nil }";
+        const string orderGet = "func (o OrderService) Get(id int) *Order { return nil }";
+        var content = $"package svc\n\n{userGet}\n\n{orderGet}\n";
+        var user = Symbol("user-get", "Get", null, "svc/svc.go", content, userGet);
+        var order = Symbol("order-get", "Get", null, "svc/svc.go", content, orderGet);
+        _files["svc/svc.go"] = (content, new List<JulieSymbol> { user, order });
+
+        // Act
+        var anchor = (await _service.CreateAnchorAsync(Workspace, order))!;
+        var resolution = await _service.ResolveAsync(Workspace, anchor);
+
+        // Assert
+        Assert.That(anchor.SymbolPath, Is.EqualTo("OrderService.Get"));
+        Assert.That(resolution.Status, Is.EqualTo(AnchorStatus.Exact));
+        Assert.That(resolution.Symbol!.Id, Is.EqualTo("order-get"));
+    }
+}
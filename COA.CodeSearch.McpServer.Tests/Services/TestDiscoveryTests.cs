@@ -0,0 +1,140 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class TestDiscoveryTests
+{
+    private const string GoTests = @"package users
+
+import ""testing""
+
+func newService() *UserService {
+	return NewUserService(newFakeStore())
+}
+
+func TestUserService_GetUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		wantErr bool
+	}{
+		{name: ""existing user"", id: 1},
+		{name: ""missing user"", id: 99, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newService().GetUser(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf(""GetUser(%d) error = %v"", tt.id, err)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Run(""soft delete"", func(t *testing.T) {})
+}
+
+func BenchmarkGetUser(b *testing.B) {
+	svc := newService()
+	for i := 0; i < b.N; i++ {
+		svc.GetUser(1)
+	}
+}
+
+func ExampleUserService_GetUser() {
+	// Output: alice
+}
+
+func TestMain(m *testing.M) {}
+";
+
+    [Test]
+    public void FindTests_Should_Read_Go_Tests_Benchmarks_Examples_And_Table_Cases()
+    {
+        // Act
+        var tests = TestDiscovery.FindTests("internal/users/service_test.go", GoTests);
+
+        // Assert
+        Assert.That(tests.Select(t => $"{t.Kind} {t.Name} {t.Line}-{t.EndLine}"), Is.EqualTo(new[]
+        {
+            "test TestUserService_GetUser 9-26", "test TestDeleteUser 28-30", "benchmark BenchmarkGetUser 32-37", "example ExampleUserService_GetUser 39-41"
+        }));
+        Assert.That(tests[0].Cases, Is.EqualTo(new[] { "existing user", "missing user" }));
+        Assert.That(tests[1].Cases, Is.EqualTo(new[] { "soft delete" }));
+    }
+
+    [Test]
+    public void FindUses_Should_Separate_Calls_From_Names()
+    {
+        // Arrange
+        var tests = TestDiscovery.FindTests("internal/users/service_test.go", GoTests);
+        var lines = GoTests.Split('\n');
+
+        // Act
+        var uses = TestDiscovery.FindUses(tests, lines, "GetUser", "UserService");
+
+        // Assert
+        Assert.That(uses.Select(u => $"{u.Test.Name}:{u.How}:{string.Join(",", u.Lines)}"), Is.EqualTo(new[]
+        {
+            "TestUserService_GetUser:calls:20,22", "BenchmarkGetUser:calls:35", "ExampleUserService_GetUser:named:"
+        }));
+        Assert.That(TestDiscovery.RunCommand("internal/users/service_test.go", uses.Select(u => u.Test).ToList()),
+            Is.EqualTo("go test ./internal/users -run '^(TestUserService_GetUser|ExampleUserService_GetUser)$' -bench '^(BenchmarkGetUser)$'"));
+    }
+
+    [Test]
+    public void FindTests_Should_Read_DotNet_Jest_And_Pytest_Tests()
+    {
+        // Arrange
+        var xunit = @"public class UserServiceTests
+{
+    [Theory]
+    [InlineData(1)]
+    [InlineData(2)]
+    [Trait(""Category"", ""Unit"")]
+    public async Task GetUser_Returns_User(int id)
+    {
+        var user = await _service.GetUser(id);
+    }
+
+    private void Helper() { }
+}";
+        var jest = @"describe('UserService', () => {
+  it('returns the user', async () => {
+    expect(await service.getUser(1)).toBeDefined();
+  });
+  test.each([[1], [2]])('handles id %i', (id) => {
+  });
+});";
+        var pytest = @"class TestUsers:
+    @pytest.mark.parametrize(""uid"", [1, 2])
+    def test_get_user(self, uid):
+        assert service.get_user(uid)
+
+def helper():
+    pass
+";
+
+        // Act
+        var dotnet = TestDiscovery.FindTests("tests/App.Tests/UserServiceTests.cs", xunit);
+        var js = TestDiscovery.FindTests("src/users.test.ts", jest);
+        var py = TestDiscovery.FindTests("tests/test_users.py", pytest);
+
+        // Assert
+        Assert.That(dotnet.Select(t => $"{t.Framework} {t.Container}.{t.Name} {t.Line}-{t.EndLine} [{string.Join(";", t.Cases)}]"),
+            Is.EqualTo(new[] { "xunit UserServiceTests.GetUser_Returns_User 7-10 [1;2]" }));
+        Assert.That(js.Select(t => $"{t.Container} > {t.Name} {t.Line}-{t.EndLine}"), Is.EqualTo(new[]
+        {
+            "UserService > returns the user 2-4", "UserService > handles id %i 5-6"
+        }));
+        Assert.That(py.Select(t => $"{t.Container}::{t.Name} {t.Line}-{t.EndLine} [{string.Join(";", t.Cases)}]"),
+            Is.EqualTo(new[] { "TestUsers::test_get_user 3-4 [parametrize x1]" }));
+        Assert.That(TestDiscovery.RunCommand("tests/App.Tests/UserServiceTests.cs", dotnet, "tests/App.Tests/App.Tests.csproj"),
+            Is.EqualTo("dotnet test tests/App.Tests/App.Tests.csproj --filter \"FullyQualifiedName~UserServiceTests.GetUser_Returns_User\""));
+        Assert.That(TestDiscovery.RunCommand("tests/test_users.py", py), Is.EqualTo("pytest tests/test_users.py::TestUsers::test_get_user"));
+        Assert.That(TestDiscovery.RunCommand("src/users.test.ts", js.Take(1).ToList(), runner: "vitest"), Is.EqualTo("npx vitest run src/users.test.ts -t \"returns the user\""));
+    }
+}
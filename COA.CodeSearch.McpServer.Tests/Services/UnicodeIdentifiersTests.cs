@@ -0,0 +1,129 @@
+using System.Globalization;
+using System.Text;
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using Lucene.Net.Analysis.TokenAttributes;
+using Lucene.Net.Util;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class UnicodeIdentifiersTests
+{
+    [TestCase("ID", "id")]
+    [TestCase("İD", "id")]
+    [TestCase("ıd", "id")]
+    [TestCase("I\u0307D", "id")]
+    [TestCase("CAFE\u0301", "caf\u00e9")]
+    [TestCase("Straße", "straße")]
+    public void Fold_Should_Fold_Case_And_Normalize(string input, string expected)
+    {
+        // Act & Assert
+        Assert.That(UnicodeIdentifiers.Fold(input), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void Fold_Should_Not_Depend_On_Current_Culture()
+    {
+        // Arrange
+        var original = CultureInfo.CurrentCulture;
+        CultureInfo.CurrentCulture = new CultureInfo("tr-TR");
+
+        try
+        {
+            // Act & Assert
+            Assert.That(UnicodeIdentifiers.Fold("USER_ID"), Is.EqualTo("user_id"));
+            Assert.That(UnicodeIdentifiers.EqualsIgnoreCase("Initialize", "INITIALIZE"), Is.True);
+        }
+        finally
+        {
+            CultureInfo.CurrentCulture = original;
+        }
+    }
+
+    [Test]
+    public void EqualsNormalized_Should_Match_Composed_And_Decomposed_But_Keep_Case()
+    {
+        // Act & Assert
+        Assert.That(UnicodeIdentifiers.EqualsNormalized("caf\u00e9", "cafe\u0301"), Is.True);
+        Assert.That(UnicodeIdentifiers.EqualsNormalized("Caf\u00e9", "cafe\u0301"), Is.False);
+    }
+
+    [TestCase('\u0301', true)]  // combining acute accent
+    [TestCase('\u200D', true)]  // zero-width joiner
+    [TestCase('क', true)]  // Devanagari KA
+    [TestCase('_', true)]
+    [TestCase('.', false)]
+    [TestCase(' ', false)]
+    public void IsIdentifierPart_Should_Accept_Marks_And_Joiners(char c, bool expected)
+    {
+        // Act & Assert
+        Assert.That(UnicodeIdentifiers.IsIdentifierPart(c), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void CharIndexOfUtf8Offset_Should_Convert_Byte_Offsets_Past_Non_Ascii_Text()
+    {
+        // Arrange
+        var content = "var größe = ıd;";
+        var utf8 = Encoding.UTF8.GetBytes(content);
+        var byteOffset = Encoding.UTF8.GetByteCount("var größe = ");
+
+        // Act
+        var index = UnicodeIdentifiers.CharIndexOfUtf8Offset(utf8, byteOffset);
+
+        // Assert
+        Assert.That(content.Substring(index, 2), Is.EqualTo("ıd"));
+    }
+
+    [TestCase("NFC", NormalizationForm.FormC)]
+    [TestCase("nfd", NormalizationForm.FormD)]
+    [TestCase(null, NormalizationForm.FormC)]
+    public void ParseNormalization_Should_Read_Setting(string? value, NormalizationForm expected)
+    {
+        // Act & Assert
+        Assert.That(UnicodeIdentifiers.ParseNormalization(value), Is.EqualTo(expected));
+    }
+
+    [Test]
+    public void ParseNormalization_Should_Allow_None_And_Reject_Unknown_Forms()
+    {
+        // Act & Assert
+        Assert.That(UnicodeIdentifiers.ParseNormalization("None"), Is.Null);
+        Assert.Throws<ArgumentException>(() => UnicodeIdentifiers.ParseNormalization("NFKC"));
+    }
+
+    [Test]
+    public void SplitWords_Should_Keep_Non_Ascii_Letters_Together()
+    {
+        // Act & Assert
+        Assert.That(QuerySyntaxGuide.SplitWords("größeBerechnen"), Is.EqualTo(new[] { "größe", "Berechnen" }));
+        Assert.That(QuerySyntaxGuide.SplitWords("HTTPServer"), Is.EqualTo(new[] { "HTTP", "Server" }));
+    }
+
+    [Test]
+    public void CodeAnalyzer_Should_Index_Decomposed_And_Turkish_Identifiers_As_Folded_Terms()
+    {
+        // Arrange
+        using var analyzer = new CodeAnalyzer(LuceneVersion.LUCENE_48);
+        var tokens = new List<string>();
+
+        // Act
+        using (var stream = analyzer.GetTokenStream("content", "var ıd = CAFE\u0301 + KULLANICI"))
+        {
+            var term = stream.AddAttribute<ICharTermAttribute>();
+            stream.Reset();
+            while (stream.IncrementToken())
+            {
+                tokens.Add(term.ToString());
+            }
+            stream.End();
+        }
+
+        // Assert
+        Assert.That(tokens, Does.Contain("id"));
+        Assert.That(tokens, Does.Contain("caf\u00e9"));
+        Assert.That(tokens, Does.Contain("kullanici"));
+    }
+}
@@ -0,0 +1,98 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Git;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class UnifiedDiffParserTests
+{
+    private const string SampleDiff =
+        "diff --git a/src/OrderService.cs b/src/OrderService.cs\n" +
+        "index 1111111..2222222 100644\n" +
+        "--- a/src/OrderService.cs\n" +
+        "+++ b/src/OrderService.cs\n" +
+        "@@ -10,2 +10,3 @@ public class OrderService\n" +
+        "-        var total = 0;\n" +
+        "-        return total;\n" +
+        "+        var total = items.Sum(i => i.Price);\n" +
+        "+        // TODO: apply discounts\n" +
+        "+        return total;\n" +
+        "@@ -40,0 +42 @@ public class OrderService\n" +
+        "+    public void Cancel() { }\n" +
+        "diff --git a/src/Old.cs b/src/New.cs\n" +
+        "similarity index 95%\n" +
+        "rename from src/Old.cs\n" +
+        "rename to src/New.cs\n" +
+        "diff --git a/tests/OrderServiceTests.cs b/tests/OrderServiceTests.cs\n" +
+        "new file mode 100644\n" +
+        "--- /dev/null\n" +
+        "+++ b/tests/OrderServiceTests.cs\n" +
+        "@@ -0,0 +1,2 @@\n" +
+        "+[TestFixture]\n" +
+        "+public class OrderServiceTests { }\n";
+
+    [Test]
+    public void Parse_Should_Read_Files_Statuses_And_Hunks()
+    {
+        // Act
+        var files = UnifiedDiffParser.Parse(SampleDiff);
+
+        // Assert
+        Assert.That(files, Has.Count.EqualTo(3));
+        Assert.That(files[0].Status, Is.EqualTo("modified"));
+        Assert.That(files[0].Hunks, Has.Count.EqualTo(2));
+        Assert.That(files[0].Additions, Is.EqualTo(4));
+        Assert.That(files[0].Deletions, Is.EqualTo(2));
+        Assert.That(files[0].Hunks[0].Context, Is.EqualTo("public class OrderService"));
+        Assert.That(files[1].Status, Is.EqualTo("renamed"));
+        Assert.That(files[1].OldPath, Is.EqualTo("src/Old.cs"));
+        Assert.That(files[1].Path, Is.EqualTo("src/New.cs"));
+        Assert.That(files[2].Status, Is.EqualTo("added"));
+        Assert.That(files[2].OldPath, Is.Null);
+    }
+
+    [Test]
+    public void Parse_Should_Default_Omitted_Hunk_Counts_To_One()
+    {
+        // Act
+        var hunk = UnifiedDiffParser.Parse(SampleDiff)[0].Hunks[1];
+
+        // Assert
+        Assert.That(hunk.OldCount, Is.EqualTo(0));
+        Assert.That(hunk.NewStart, Is.EqualTo(42));
+        Assert.That(hunk.NewCount, Is.EqualTo(1));
+        Assert.That(hunk.ContainsNewLine(42), Is.True);
+        Assert.That(hunk.ContainsNewLine(43), Is.False);
+    }
+
+    [TestCase("main")]
+    [TestCase("origin/feature/login")]
+    [TestCase("HEAD~3")]
+    [TestCase("v2.1.0")]
+    public void ValidateRef_Should_Accept_Ref_Expressions(string gitRef)
+    {
+        Assert.DoesNotThrow(() => GitService.ValidateRef(gitRef));
+    }
+
+    [TestCase("--output=/tmp/x")]
+    [TestCase("main..evil")]
+    [TestCase("main; rm -rf /")]
+    [TestCase("")]
+    public void ValidateRef_Should_Reject_Options_And_Ranges(string gitRef)
+    {
+        Assert.Throws<GitException>(() => GitService.ValidateRef(gitRef));
+    }
+
+    [TestCase("tests/OrderServiceTests.cs", true)]
+    [TestCase("src/App.Tests/Helpers.cs", true)]
+    [TestCase("web/src/cart.test.ts", true)]
+    [TestCase("pkg/order/order_test.go", true)]
+    [TestCase("test_orders.py", true)]
+    [TestCase("src/Latest.cs", false)]
+    [TestCase("src/OrderService.cs", false)]
+    public void IsTestFile_Should_Recognize_Test_Conventions(string path, bool expected)
+    {
+        Assert.That(TestFileDetector.IsTestFile(path), Is.EqualTo(expected));
+    }
+}
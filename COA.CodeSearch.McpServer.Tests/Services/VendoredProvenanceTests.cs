@@ -0,0 +1,93 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class VendoredProvenanceTests
+{
+    [Test]
+    public void ParseModulesTxt_Should_Read_Modules_Replacements_And_Find_File_Owners()
+    {
+        // Arrange
+        var modulesTxt = string.Join("\n",
+            "# github.com/Azure/go-autorest v14.2.0+incompatible",
+            "## explicit",
+            "github.com/Azure/go-autorest",
+            "# github.com/Azure/go-autorest/autorest v0.11.29",
+            "## explicit; go 1.15",
+            "github.com/Azure/go-autorest/autorest",
+            "# golang.org/x/sys v0.15.0 => ../sys",
+            "golang.org/x/sys/unix",
+            "# github.com/pkg/errors v0.9.1 => github.com/acme/errors v0.9.2-patched",
+            "github.com/pkg/errors",
+            "# example.com/unused => ./unused");
+
+        // Act
+        var modules = VendoredProvenance.ParseModulesTxt(modulesTxt);
+        var nested = VendoredProvenance.FindModule(modules, "github.com/Azure/go-autorest/autorest/client.go");
+        var parent = VendoredProvenance.FindModule(modules, "github.com/Azure/go-autorest/LICENSE");
+        var stray = VendoredProvenance.FindModule(modules, "github.com/other/lib/lib.go");
+
+        // Assert
+        Assert.That(modules.Select(m => m.Path), Is.EqualTo(new[]
+        {
+            "github.com/Azure/go-autorest", "github.com/Azure/go-autorest/autorest", "golang.org/x/sys", "github.com/pkg/errors", "example.com/unused"
+        }));
+        Assert.That(modules[2].IsLocalReplacement, Is.True);
+        Assert.That((modules[3].Replacement, modules[3].ReplacementVersion), Is.EqualTo(("github.com/acme/errors", "v0.9.2-patched")));
+        Assert.That(nested!.Value.Module.Path, Is.EqualTo("github.com/Azure/go-autorest/autorest"));
+        Assert.That(nested.Value.PathInModule, Is.EqualTo("client.go"));
+        Assert.That(parent!.Value.Module.Version, Is.EqualTo("v14.2.0+incompatible"));
+        Assert.That(stray, Is.Null);
+        Assert.That(VendoredProvenance.ModuleCacheDirectory("/cache", "github.com/Azure/go-autorest", "v14.2.0+incompatible").Replace('\\', '/'),
+            Is.EqualTo("/cache/github.com/!azure/go-autorest@v14.2.0+incompatible"));
+    }
+
+    [Test]
+    public void DetectOrigin_Should_Read_Header_Urls_And_Revisions()
+    {
+        // Arrange
+        var github = "// Copyright 2009 The Go Authors.\n//\n// Copied from https://github.com/golang/go/blob/go1.21.5/src/sort/slice.go\n\npackage sortx\n";
+        var versioned = "# Vendored from: https://gitlab.com/acme/tools/-/blob/main/lib/retry.py\n# Version: 2.3.1\nimport time\n";
+        var repoOnly = "/*\n * Adapted from https://github.com/acme/widgets at commit 3f2a9c1b7d4e\n */\n";
+        var plain = "// Copyright Acme\npackage main\n// copied from https://example.com/snippet";
+
+        // Act
+        var fromGitHub = VendoredProvenance.DetectOrigin(github);
+        var fromGitLab = VendoredProvenance.DetectOrigin(versioned);
+        var fromRepo = VendoredProvenance.DetectOrigin(repoOnly);
+
+        // Assert
+        Assert.That(fromGitHub!.Line, Is.EqualTo(3));
+        Assert.That((fromGitHub.Repository, fromGitHub.Revision, fromGitHub.UpstreamPath), Is.EqualTo(("github.com/golang/go", "go1.21.5", "src/sort/slice.go")));
+        Assert.That(VendoredProvenance.RawUrl(fromGitHub), Is.EqualTo("https://raw.githubusercontent.com/golang/go/go1.21.5/src/sort/slice.go"));
+        Assert.That(VendoredProvenance.RawUrl(fromGitLab!), Is.EqualTo("https://gitlab.com/acme/tools/-/raw/main/lib/retry.py"));
+        Assert.That((fromRepo!.Repository, fromRepo.Revision, fromRepo.UpstreamPath), Is.EqualTo(("github.com/acme/widgets", "3f2a9c1b7d4e", (string?)null)));
+        Assert.That(VendoredProvenance.RawUrl(fromRepo), Is.Null);
+        Assert.That(VendoredProvenance.DetectOrigin(plain)?.Url, Is.EqualTo("https://example.com/snippet"));
+        Assert.That(VendoredProvenance.DetectOrigin("package main\n\nfunc main() {}\n"), Is.Null);
+    }
+
+    [Test]
+    public void Compare_Should_Ignore_Line_Endings_And_Origin_Header_But_Flag_Code_Changes()
+    {
+        // Arrange
+        var upstream = "// Copyright 2009 The Go Authors.\n\npackage sort\n\nfunc Slice(x any, less func(i, j int) bool) {\n\trank(x, less)\n}\n";
+        var headerOnly = "// Copyright 2009 The Go Authors.\r\n// Copied from https://github.com/golang/go/blob/go1.21.5/src/sort/slice.go\r\n\r\npackage sort\r\n\r\n" +
+                         "func Slice(x any, less func(i, j int) bool) {\r\n\trank(x, less)\r\n}\r\n\r\n";
+        var patched = headerOnly.Replace("\trank(x, less)", "\tif x == nil {\r\n\t\treturn\r\n\t}\r\n\trank(x, less)");
+
+        // Act
+        var clean = VendoredProvenance.Compare(upstream, headerOnly, "sortx/slice.go");
+        var modified = VendoredProvenance.Compare(upstream, patched, "sortx/slice.go", contextLines: 1);
+        var identical = VendoredProvenance.Compare(upstream, "\uFEFF" + upstream, "sortx/slice.go");
+
+        // Assert
+        Assert.That((clean.Identical, clean.HeaderOnly, clean.Diff), Is.EqualTo((true, true, (string?)null)));
+        Assert.That(modified.Identical, Is.False);
+        Assert.That((modified.Added, modified.Removed), Is.EqualTo((4, 0)));
+        Assert.That(modified.Diff, Does.Contain("+\tif x == nil {"));
+        Assert.That(identical.Identical && !identical.HeaderOnly, Is.True);
+    }
+}
@@ -0,0 +1,139 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class VueSfcSymbolsTests
+{
+    private const string SetupComponent = """
+        <template>
+          <form @submit.prevent="save">
+            <input ref="nameInput" v-model="name" />
+            <template v-if="advanced">
+              <textarea :ref="setNotes" />
+            </template>
+          </form>
+        </template>
+
+        <script setup lang="ts">
+        import { ref } from 'vue'
+
+        interface Props {
+          title: string
+          // shown under the title
+          subtitle?: string
+          tags?: Record<string, number>
+        }
+
+        const props = withDefaults(defineProps<Props>(), {
+          subtitle: 'None'
+        })
+
+        const emit = defineEmits<{
+          (e: 'save' | 'submit', id: number): void
+          cancel: []
+        }>()
+
+        const model = defineModel<string>('query')
+
+        function save() {
+          emit('save', 1)
+        }
+        </script>
+
+        <style scoped>
+        form { display: grid; }
+        </style>
+        """;
+
+    [Test]
+    public void Extract_Should_Place_Script_Props_Emits_And_Template_Refs_On_Their_Lines_In_The_File()
+    {
+        // Act
+        var symbols = VueSfcSymbols.Extract("src/components/user-form.vue", SetupComponent);
+
+        // Assert
+        var component = symbols.Single(s => s.Kind == "class");
+        Assert.That(component.Name, Is.EqualTo("UserForm"));
+        Assert.That(component.Signature, Is.EqualTo("<UserForm> props: title, subtitle, tags, query; emits: save, submit, cancel, update:query"));
+
+        var save = symbols.Single(s => s.Name == "save" && s.Kind == "function");
+        Assert.That((save.StartLine, save.EndLine), Is.EqualTo((31, 33)));
+
+        var title = symbols.Single(s => s.Name == "title");
+        Assert.That((title.Kind, title.StartLine, title.StartColumn), Is.EqualTo(("property", 14, 2)));
+        Assert.That(symbols.Single(s => s.Name == "subtitle").Signature, Is.EqualTo("prop subtitle?: string = 'None'"));
+        Assert.That(symbols.Single(s => s.Name == "tags").Signature, Is.EqualTo("prop tags?: Record<string, number>"));
+
+        var submit = symbols.Single(s => s.Name == "submit");
+        Assert.That((submit.Kind, submit.StartLine, submit.Signature), Is.EqualTo(("event", 25, "emit submit(id: number)")));
+        Assert.That(symbols.Single(s => s.Name == "cancel").Signature, Is.EqualTo("emit cancel()"));
+        Assert.That(symbols.Single(s => s.Name == "query").Signature, Is.EqualTo("prop query?: string (v-model)"));
+        Assert.That(symbols.Single(s => s.Name == "update:query").Signature, Is.EqualTo("emit update:query(value: string)"));
+
+        var nameInput = symbols.Single(s => s.Kind == "field");
+        Assert.That((nameInput.Name, nameInput.StartLine, nameInput.Signature), Is.EqualTo(("nameInput", 3, "ref=\"nameInput\" <input>")));
+        Assert.That(symbols.Where(s => s.Name != component.Name).All(s => s.ParentId == component.Id), Is.True);
+    }
+
+    [Test]
+    public void Extract_Should_Read_Runtime_Props_And_Emits_Of_The_Options_Api()
+    {
+        // Arrange
+        var content = """
+            <template><div>{{ label }}</div></template>
+            <script>
+            export default {
+              name: 'CounterBadge',
+              props: {
+                label: String,
+                count: { type: [Number, String], required: true },
+                user: { type: Object as PropType<User>, default: null }
+              },
+              emits: ['increment', 'reset'],
+              methods: {
+                increment() { this.$emit('increment') }
+              }
+            }
+            </script>
+            """;
+
+        // Act
+        var symbols = VueSfcSymbols.Extract("Badge.vue", content);
+
+        // Assert
+        Assert.That(symbols.Single(s => s.Kind == "class").Name, Is.EqualTo("CounterBadge"));
+        Assert.That(symbols.Where(s => s.Kind == "property").Select(s => s.Signature), Is.EqualTo(new[]
+        {
+            "prop label?: String",
+            "prop count: Number | String",
+            "prop user?: User = null"
+        }));
+        Assert.That(symbols.Where(s => s.Kind == "event").Select(s => (s.Name, s.StartLine)), Is.EqualTo(new[] { ("increment", 10), ("reset", 10) }));
+    }
+
+    [Test]
+    public void Repair_Should_Move_Symbols_Numbered_From_Their_Script_Block_And_Add_Missing_Declarations()
+    {
+        // Arrange
+        var julie = new List<JulieSymbol>
+        {
+            // save() is on line 31 of the file, line 22 of the <script setup> block starting on line 10
+            new() { Id = "julie-save", Name = "save", Kind = "function", FilePath = "user-form.vue", StartLine = 22, EndLine = 24 },
+            new() { Id = "julie-props", Name = "props", Kind = "variable", FilePath = "user-form.vue", StartLine = 20, EndLine = 22 }
+        };
+
+        // Act
+        var repaired = VueSfcSymbols.Repair("user-form.vue", SetupComponent, julie);
+        var unchanged = VueSfcSymbols.Repair("user-form.vue", SetupComponent, repaired);
+
+        // Assert
+        var save = repaired.Single(s => s.Name == "save" && s.Kind == "function");
+        Assert.That((save.Id, save.StartLine, save.EndLine), Is.EqualTo(("julie-save", 31, 33)));
+        Assert.That(repaired.Single(s => s.Name == "props").Id, Is.EqualTo("julie-props"));
+        Assert.That(repaired.Count(s => s.Name == "title" && s.Kind == "property"), Is.EqualTo(1));
+        Assert.That(unchanged, Is.SameAs(repaired));
+    }
+}
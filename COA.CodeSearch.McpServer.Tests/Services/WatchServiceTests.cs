@@ -0,0 +1,112 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Watches;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+using Moq;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class WatchServiceTests
+{
+    private string _workspace = null!;
+    private string _indexPath = null!;
+    private Mock<IPathResolutionService> _pathResolution = null!;
+    private StringWriter _output = null!;
+    private WatchService _watches = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "watch_test_" + Guid.NewGuid().ToString("N"));
+        _indexPath = Path.Combine(_workspace, ".index");
+        Directory.CreateDirectory(Path.Combine(_workspace, "payments"));
+        _pathResolution = new Mock<IPathResolutionService>();
+        _pathResolution.Setup(p => p.GetIndexPath(_workspace)).Returns(_indexPath);
+        _output = new StringWriter();
+        _watches = CreateService();
+    }
+
+    [TearDown]
+    public void TearDown()
+    {
+        if (Directory.Exists(_workspace))
+            Directory.Delete(_workspace, true);
+    }
+
+    private WatchService CreateService() =>
+        new(_pathResolution.Object, new ConfigurationBuilder().Build(), NullLogger<WatchService>.Instance, output: _output);
+
+    private string WriteFile(string relativePath, string content)
+    {
+        var path = Path.Combine(_workspace, relativePath);
+        File.WriteAllText(path, content);
+        return path;
+    }
+
+    [Test]
+    public async Task EvaluateAsync_Should_Alert_Only_On_Lines_Added_By_The_Update()
+    {
+        // Arrange
+        var watch = _watches.Add(_workspace, new WatchDefinition { Pattern = "Thread.Sleep" });
+        const string before = "void A()\n{\n    Thread.Sleep(10);\n}\n";
+        var path = WriteFile("Worker.cs", "// moved down\nvoid A()\n{\n        Thread.Sleep(10);\n    Thread.Sleep(500);\n}\n");
+
+        // Act
+        var alerts = await _watches.EvaluateAsync(_workspace, path, before);
+
+        // Assert
+        Assert.That(alerts, Has.Count.EqualTo(1));
+        Assert.That(alerts[0].WatchId, Is.EqualTo(watch.Id));
+        Assert.That(alerts[0].FilePath, Is.EqualTo("Worker.cs"));
+        Assert.That(alerts[0].Line, Is.EqualTo(5));
+        Assert.That(alerts[0].LineText, Is.EqualTo("Thread.Sleep(500);"));
+        Assert.That(_output.ToString(), Does.Contain("\"method\":\"notifications/message\""));
+    }
+
+    [Test]
+    public async Task EvaluateAsync_Should_Respect_File_Pattern_And_Report_New_Files_In_Full()
+    {
+        // Arrange
+        _watches.Add(_workspace, new WatchDefinition { Name = "payments TODOs", Pattern = "TODO", FilePattern = "payments/**" });
+        var outside = WriteFile("Cart.cs", "// TODO: outside\n");
+        var inside = WriteFile(Path.Combine("payments", "Charge.cs"), "// TODO: retry\n// todo: refund\n");
+
+        // Act
+        var ignored = await _watches.EvaluateAsync(_workspace, outside, null);
+        var raised = await _watches.EvaluateAsync(_workspace, inside, null);
+
+        // Assert
+        Assert.That(ignored, Is.Empty);
+        Assert.That(raised.Select(a => a.Line), Is.EqualTo(new[] { 1, 2 }));
+        Assert.That(raised.All(a => a.WatchName == "payments TODOs"), Is.True);
+    }
+
+    [Test]
+    public async Task GetAlerts_Should_Return_Alerts_After_Sequence_And_Watches_Should_Survive_Restart()
+    {
+        // Arrange
+        var watch = _watches.Add(_workspace, new WatchDefinition { Pattern = @"catch\s*\(Exception\)", IsRegex = true });
+        var first = await _watches.EvaluateAsync(_workspace, WriteFile("A.cs", "catch (Exception) { }\n"), null);
+        await _watches.EvaluateAsync(_workspace, WriteFile("B.cs", "catch(Exception) { }\n"), null);
+
+        // Act
+        var newer = _watches.GetAlerts(_workspace, afterSequence: first[0].Sequence);
+        var reloaded = CreateService().List(_workspace);
+
+        // Assert
+        Assert.That(newer.Select(a => a.FilePath), Is.EqualTo(new[] { "B.cs" }));
+        Assert.That(reloaded.Select(w => w.Id), Is.EqualTo(new[] { watch.Id }));
+        Assert.That(_watches.Remove(_workspace, watch.Id), Is.True);
+        Assert.That(_watches.HasWatches(_workspace), Is.False);
+    }
+
+    [Test]
+    public void Add_Should_Reject_Invalid_Regex()
+    {
+        // Act & Assert
+        var ex = Assert.Throws<WatchException>(() => _watches.Add(_workspace, new WatchDefinition { Pattern = "(unclosed", IsRegex = true }));
+        Assert.That(ex!.Code, Is.EqualTo("INVALID_PATTERN"));
+    }
+}
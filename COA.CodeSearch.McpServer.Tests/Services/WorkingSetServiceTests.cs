@@ -0,0 +1,87 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging.Abstractions;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class WorkingSetServiceTests
+{
+    private string _workspace = null!;
+
+    [SetUp]
+    public void SetUp()
+    {
+        _workspace = Path.Combine(Path.GetTempPath(), "ws-" + Guid.NewGuid().ToString("N"));
+    }
+
+    private static WorkingSetService CreateService(bool enabled = true) => new(
+        new ConfigurationBuilder().AddInMemoryCollection(new Dictionary<string, string?>
+        {
+            ["CodeSearch:WorkingSet:Enabled"] = enabled.ToString(),
+            ["CodeSearch:WorkingSet:NeighborBoost"] = "0.5"
+        }).Build(),
+        NullLogger<WorkingSetService>.Instance);
+
+    private string Full(string relativePath) => Path.GetFullPath(Path.Combine(_workspace, relativePath));
+
+    [Test]
+    public void GetFileWeights_Should_Weigh_Edits_Above_Views_And_Boost_Neighbors()
+    {
+        // Arrange
+        var service = CreateService();
+        service.RecordFile("src/Orders.cs", WorkingSetActivity.Edited, _workspace);
+        service.RecordFile("src/Users.cs", WorkingSetActivity.Viewed, _workspace);
+        service.SetNeighbors(Full("src/Orders.cs"), new[] { Full("src/OrderRepository.cs"), Full("src/Users.cs") });
+
+        // Act
+        var weights = service.GetFileWeights(_workspace);
+
+        // Assert
+        Assert.That(weights[Full("src/Orders.cs")], Is.EqualTo(1.0f).Within(0.01f));
+        Assert.That(weights[Full("src/Users.cs")], Is.EqualTo(0.5f).Within(0.01f));
+        Assert.That(weights[Full("src/OrderRepository.cs")], Is.EqualTo(0.5f).Within(0.01f));
+        Assert.That(service.GetEntries(_workspace).Select(e => $"{e.FilePath} {e.Edits}/{e.Views}"),
+            Is.EqualTo(new[] { "src/Orders.cs 1/0", "src/Users.cs 0/1" }));
+    }
+
+    [Test]
+    public void RecordParameters_Should_Record_Files_Positions_And_Symbol_Names()
+    {
+        // Arrange
+        var service = CreateService();
+        var before = service.Version;
+
+        // Act
+        service.RecordParameters(new { WorkspacePath = _workspace, FilePath = "src/Orders.cs" });
+        service.RecordParameters(new { WorkspacePath = _workspace, Symbol = "src/Users.cs:12:5" });
+        service.RecordParameters(new { WorkspacePath = _workspace, Symbol = "OrderService" });
+        service.RecordFile(Full("src/Orders.cs"), WorkingSetActivity.Edited);
+
+        // Assert
+        Assert.That(service.Version, Is.GreaterThan(before));
+        Assert.That(service.GetEntries(_workspace).Select(e => $"{e.FilePath} {e.Edits}/{e.Views}"),
+            Is.EquivalentTo(new[] { "src/Orders.cs 1/1", "src/Users.cs 0/1" }));
+        Assert.That(service.GetSymbols(_workspace).Select(s => s.Name), Is.EqualTo(new[] { "OrderService" }));
+        Assert.That(service.GetSymbolWeight(_workspace, "OrderService"), Is.GreaterThan(0f));
+    }
+
+    [Test]
+    public void Clear_And_Disabled_Should_Leave_No_Weights()
+    {
+        // Arrange
+        var service = CreateService();
+        service.RecordFile("src/Orders.cs", WorkingSetActivity.Opened, _workspace);
+        var disabled = CreateService(enabled: false);
+        disabled.RecordFile("src/Orders.cs", WorkingSetActivity.Edited, _workspace);
+
+        // Act
+        var cleared = service.Clear(_workspace);
+
+        // Assert
+        Assert.That(cleared, Is.EqualTo(1));
+        Assert.That(service.GetFileWeights(_workspace), Is.Empty);
+        Assert.That(disabled.GetEntries(_workspace), Is.Empty);
+    }
+}
@@ -0,0 +1,87 @@
+using NUnit.Framework;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tests.Services;
+
+[TestFixture]
+public class WorkspaceOverviewTests
+{
+    private static readonly (string Path, string? Content)[] GoService =
+    {
+        ("go.mod", "module github.com/acme/shop\n\ngo 1.23\n"),
+        ("cmd/shop/main.go", "package main\n\nimport (\n\t\"github.com/acme/shop/internal/orders\"\n)\n\nfunc main() {\n\torders.Run()\n}\n"),
+        ("internal/orders/orders.go", "package orders\n\nimport \"github.com/acme/shop/internal/store\"\n\nfunc Run() { store.Open() }\n"),
+        ("internal/orders/orders_test.go", "package orders\n\nimport \"testing\"\n\nfunc TestRun(t *testing.T) {}\n\nfunc TestRunTwice(t *testing.T) {}\n"),
+        ("internal/store/store.go", "package store\n\nimport \"database/sql\"\n\nfunc Open() *sql.DB { return nil }\n"),
+        ("Dockerfile", "FROM golang:1.23\nCOPY . .\nENTRYPOINT [\"/shop\"]\n"),
+        (".github/workflows/ci.yml", "on: push\n"),
+        ("README.md", "# Shop\n")
+    };
+
+    [Test]
+    public void Build_Should_Link_Clusters_By_Imports_And_Assign_Roles()
+    {
+        // Act
+        var report = WorkspaceOverview.Build(GoService);
+
+        // Assert
+        Assert.That(report.Clusters.Select(c => $"{c.Name} {c.Role}"), Is.EquivalentTo(new[]
+        {
+            "cmd/shop application", "internal/orders middle", "internal/store core"
+        }));
+        var orders = report.Clusters.Single(c => c.Name == "internal/orders");
+        Assert.That(orders.DependsOn, Is.EqualTo(new[] { "internal/store" }));
+        Assert.That(orders.UsedBy, Is.EqualTo(new[] { "cmd/shop" }));
+        Assert.That(orders.Files, Is.EqualTo(1), "Test files stay out of clusters");
+    }
+
+    [Test]
+    public void Build_Should_Report_Languages_Entry_Points_Configuration_And_Tests()
+    {
+        // Act
+        var report = WorkspaceOverview.Build(GoService);
+
+        // Assert
+        Assert.That(report.Languages.Select(l => l.Language), Is.EqualTo(new[] { "go" }));
+        Assert.That(report.Languages[0].Percent, Is.EqualTo(100.0));
+        Assert.That(report.EntryPoints.Select(e => $"{e.FilePath}:{e.Line} {e.Kind}"), Is.EquivalentTo(new[]
+        {
+            "cmd/shop/main.go:7 main", "Dockerfile:3 container"
+        }));
+        Assert.That(report.Configuration.Select(c => $"{c.Kind} {c.FilePath}"), Is.EqualTo(new[]
+        {
+            "build go.mod", "container Dockerfile", "ci .github/workflows/ci.yml"
+        }));
+        Assert.That(report.Tests.TestFiles, Is.EqualTo(1));
+        Assert.That(report.Tests.Tests, Is.EqualTo(2));
+        Assert.That(report.Tests.Frameworks, Is.EqualTo(new[] { "go" }));
+        Assert.That(report.Tests.Layout, Is.EqualTo("co-located"));
+    }
+
+    [Test]
+    public void Build_Should_Resolve_Relative_Script_Imports_And_Namespaces()
+    {
+        // Arrange
+        var files = new (string Path, string? Content)[]
+        {
+            ("web/src/app.ts", "import { api } from '../lib/api';\nconst app = express();\napp.listen(port);\n"),
+            ("web/lib/api.ts", "export const api = 1;\n"),
+            ("src/Api/Program.cs", "using Shop.Domain;\n\nnamespace Shop.Api;\n\nvar builder = WebApplication.CreateBuilder(args);\n"),
+            ("src/Domain/Order.cs", "namespace Shop.Domain;\n\npublic class Order { }\n"),
+            ("tests/Shop.Tests/OrderTests.cs", "using NUnit.Framework;\n\n[TestFixture]\npublic class OrderTests\n{\n    [Test]\n    public void Works() { }\n}\n")
+        };
+
+        // Act
+        var report = WorkspaceOverview.Build(files);
+
+        // Assert
+        Assert.That(report.Clusters.Single(c => c.Name == "web/src").DependsOn, Is.EqualTo(new[] { "web/lib" }));
+        Assert.That(report.Clusters.Single(c => c.Name == "src/Api").DependsOn, Is.EqualTo(new[] { "src/Domain" }));
+        Assert.That(report.EntryPoints.Select(e => $"{e.FilePath} {e.Kind}"), Is.EquivalentTo(new[]
+        {
+            "web/src/app.ts host", "src/Api/Program.cs host"
+        }));
+        Assert.That(report.Tests.Layout, Is.EqualTo("separate"));
+        Assert.That(report.Tests.Directories, Is.EqualTo(new[] { "tests/Shop.Tests" }));
+    }
+}
@@ -6,6 +6,7 @@ using COA.CodeSearch.McpServer.Tests.Base;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.Services.Lucene;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Roslyn;
 using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
@@ -260,6 +261,114 @@ namespace COA.CodeSearch.McpServer.Tests.Tools
                 .WithMessage("*Symbol field is required*");
         }
 
+        [Test]
+        public async Task ExecuteAsync_RoslynResolvesSymbol_ReturnsCompilerBoundReferences()
+        {
+            // Arrange
+            var roslynMock = new Mock<IRoslynAnalysisService>();
+            roslynMock.SetupGet(x => x.IsEnabled).Returns(true);
+            roslynMock
+                .Setup(x => x.FindReferencesByNameAsync(It.IsAny<string>(), "TestMethod", false, It.IsAny<CancellationToken>()))
+                .ReturnsAsync(new RoslynReferenceResult
+                {
+                    SymbolDisplay = "TestNamespace.TestClass.TestMethod()",
+                    SymbolKind = "Method",
+                    Locations = new List<RoslynLocation>
+                    {
+                        new() { FilePath = "/test/TestClass.cs", Line = 5, Column = 16, IsDefinition = true, ReferenceType = "definition" },
+                        new() { FilePath = "/test/Caller.cs", Line = 12, Column = 8, ReferenceType = "reference", ContainedIn = "Caller.Run" }
+                    }
+                });
+            var tool = CreateToolWithRoslyn(roslynMock.Object);
+
+            LuceneIndexServiceMock
+                .Setup(x => x.IndexExistsAsync(It.IsAny<string>(), It.IsAny<CancellationToken>()))
+                .ReturnsAsync(true);
+
+            var parameters = new FindReferencesParameters
+            {
+                Symbol = "TestMethod",
+                WorkspacePath = TestWorkspacePath,
+                MaxResults = 10,
+                MaxTokens = 8000,
+                NoCache = true
+            };
+
+            // Act
+            var result = await tool.ExecuteAsync(parameters, CancellationToken.None);
+
+            // Assert - the index is not searched for a name match
+            result.Success.Should().BeTrue();
+            result.Meta!.ExtensionData!["source"].Should().Be("roslyn");
+            dynamic results = result.Data.GetType().GetProperty("Results")!.GetValue(result.Data)!;
+            ((int)results.TotalHits).Should().Be(2);
+            var containers = new List<string>();
+            foreach (dynamic hit in (System.Collections.IList)results.Hits)
+            {
+                var fields = (IDictionary<string, string>)hit.Fields;
+                fields["source"].Should().Be("roslyn");
+                containers.Add(fields["containedIn"]);
+            }
+            containers.Should().Contain("Caller.Run");
+            LuceneIndexServiceMock.Verify(
+                x => x.SearchAsync(It.IsAny<string>(), It.IsAny<Query>(), It.IsAny<int>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()),
+                Times.Never);
+        }
+
+        [Test]
+        public async Task ExecuteAsync_RoslynHasNoAnswer_FallsBackToIndex()
+        {
+            // Arrange - the solution did not load, so the tier answers null
+            var roslynMock = new Mock<IRoslynAnalysisService>();
+            roslynMock.SetupGet(x => x.IsEnabled).Returns(true);
+            roslynMock
+                .Setup(x => x.FindReferencesByNameAsync(It.IsAny<string>(), It.IsAny<string>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()))
+                .ReturnsAsync((RoslynReferenceResult?)null);
+            var tool = CreateToolWithRoslyn(roslynMock.Object);
+
+            LuceneIndexServiceMock
+                .Setup(x => x.IndexExistsAsync(It.IsAny<string>(), It.IsAny<CancellationToken>()))
+                .ReturnsAsync(true);
+            LuceneIndexServiceMock
+                .Setup(x => x.SearchAsync(It.IsAny<string>(), It.IsAny<Query>(), It.IsAny<int>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()))
+                .ReturnsAsync(CreateMockSearchResultWithReferences());
+
+            var parameters = new FindReferencesParameters
+            {
+                Symbol = "TestMethod",
+                WorkspacePath = TestWorkspacePath,
+                MaxResults = 10,
+                MaxTokens = 8000,
+                NoCache = true
+            };
+
+            // Act
+            var result = await tool.ExecuteAsync(parameters, CancellationToken.None);
+
+            // Assert
+            result.Success.Should().BeTrue();
+            (result.Meta?.ExtensionData?.GetValueOrDefault("source")).Should().NotBe("roslyn");
+            LuceneIndexServiceMock.Verify(
+                x => x.SearchAsync(It.IsAny<string>(), It.IsAny<Query>(), It.IsAny<int>(), It.IsAny<bool>(), It.IsAny<CancellationToken>()),
+                Times.AtLeastOnce);
+        }
+
+        private FindReferencesTool CreateToolWithRoslyn(IRoslynAnalysisService roslyn)
+        {
+            return new FindReferencesTool(
+                ServiceProvider,
+                LuceneIndexServiceMock.Object,
+                PathResolutionServiceMock.Object,
+                ResponseCacheServiceMock.Object,
+                ResourceStorageServiceMock.Object,
+                CacheKeyGeneratorMock.Object,
+                new SmartQueryPreprocessor(new Mock<ILogger<SmartQueryPreprocessor>>().Object),
+                CodeAnalyzer,
+                new Mock<IReferenceResolverService>().Object,
+                ToolLoggerMock.Object,
+                roslyn);
+        }
+
         private SearchResult CreateMockSearchResultWithReferences()
         {
             var searchHit = new SearchHit
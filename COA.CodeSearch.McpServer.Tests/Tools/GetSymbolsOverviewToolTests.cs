@@ -243,6 +243,50 @@ public enum TestEnum { }";
             overview.TotalSymbols.Should().Be(4);
         }
 
+        [Test]
+        public async Task ExecuteAsync_Should_List_Markdown_Headings_As_Sections()
+        {
+            // Arrange
+            var markdownPath = Path.Combine(TestWorkspacePath, "ARCHITECTURE.md");
+            await File.WriteAllTextAsync(markdownPath, "# Architecture\n\n## Storage\n\n```sql\nSELECT 1;\n```\n\n## Events\n");
+
+            var mockSymbols = new List<JulieSymbol>
+            {
+                new JulieSymbol { Id = "h1", Name = "Architecture", Kind = "heading", Signature = "# Architecture (#architecture)", StartLine = 1, EndLine = 9, Language = "markdown", FilePath = markdownPath },
+                new JulieSymbol { Id = "h2", Name = "Storage", Kind = "heading", Signature = "## Storage (#storage)", StartLine = 3, EndLine = 7, Language = "markdown", FilePath = markdownPath, ParentId = "h1" },
+                new JulieSymbol { Id = "b1", Name = "sql", Kind = "block", Signature = "```sql", StartLine = 5, EndLine = 7, Language = "markdown", FilePath = markdownPath, ParentId = "h2" },
+                new JulieSymbol { Id = "h3", Name = "Events", Kind = "heading", Signature = "## Events (#events)", StartLine = 9, EndLine = 9, Language = "markdown", FilePath = markdownPath, ParentId = "h1" }
+            };
+
+            var sqliteMock = new Mock<ISQLiteSymbolService>();
+            sqliteMock.Setup(x => x.DatabaseExists(It.IsAny<string>())).Returns(true);
+            sqliteMock.Setup(x => x.GetSymbolsForFileAsync(It.IsAny<string>(), markdownPath, It.IsAny<CancellationToken>()))
+                .ReturnsAsync(mockSymbols);
+
+            _tool = CreateToolWithSQLite(sqliteMock);
+
+            var parameters = new GetSymbolsOverviewParameters
+            {
+                FilePath = markdownPath,
+                WorkspacePath = TestWorkspacePath,
+                IncludeLineNumbers = true
+            };
+
+            // Act
+            var result = await ExecuteToolAsync<AIOptimizedResponse<SymbolsOverviewResult>>(
+                async () => await _tool.ExecuteAsync(parameters, CancellationToken.None));
+
+            // Assert
+            result.Success.Should().BeTrue();
+            var overview = result.Result!.Data!.Results;
+
+            overview.Sections.Select(s => (s.Name, s.Level, s.Line, s.EndLine)).Should().Equal(
+                ("Architecture", 1, 1, 9), ("Storage", 2, 3, 7), ("Events", 2, 9, 9));
+            overview.Sections[1].CodeBlocks.Should().Equal("sql");
+            overview.Sections[0].CodeBlocks.Should().BeEmpty();
+            overview.TotalSymbols.Should().Be(3);
+        }
+
         [Test]
         public async Task ExecuteAsync_Should_Include_Methods_When_Requested()
         {
@@ -20,6 +20,7 @@ using System.Linq;
 using COA.Mcp.Framework.Models;
 using Microsoft.Extensions.Logging;
 using COA.Mcp.Framework.Exceptions;
+using Microsoft.Extensions.DependencyInjection;
 
 namespace COA.CodeSearch.McpServer.Tests.Tools
 {
@@ -28,6 +29,15 @@ namespace COA.CodeSearch.McpServer.Tests.Tools
     {
         private GoToDefinitionTool _tool = null!;
         private Mock<ISQLiteSymbolService>? _sqliteServiceMock;
+        private Mock<IPrefetchService> _prefetchMock = null!;
+
+        protected override void ConfigureServices(IServiceCollection services)
+        {
+            base.ConfigureServices(services);
+
+            _prefetchMock = new Mock<IPrefetchService>();
+            services.AddSingleton(_prefetchMock.Object);
+        }
 
         protected override GoToDefinitionTool CreateTool()
         {
@@ -277,6 +287,63 @@ namespace COA.CodeSearch.McpServer.Tests.Tools
             snippet.Should().Contain("interface TestInterface");
         }
 
+        [Test]
+        public async Task ExecuteAsync_DefinitionFound_PrefetchesReferencesAndOutline()
+        {
+            // Arrange
+            var parameters = new GoToDefinitionParameters
+            {
+                Symbol = "TestInterface",
+                WorkspacePath = TestWorkspacePath,
+                ContextLines = 5
+            };
+            _tool = CreateToolWithSQLite(CreateSQLiteMock("TestInterface"));
+
+            // Act
+            var result = await _tool.ExecuteAsync(parameters, CancellationToken.None);
+
+            // Assert - the workspace is passed on because the caller gave one
+            result.Success.Should().BeTrue();
+            _prefetchMock.Verify(p => p.PrefetchSymbol(
+                It.Is<string?>(w => w != null),
+                "TestInterface",
+                It.Is<string?>(f => f != null && f.Contains("TestInterface.cs"))), Times.Once);
+        }
+
+        [Test]
+        public async Task ExecuteAsync_SymbolNotFound_DoesNotPrefetch()
+        {
+            // Arrange
+            var parameters = new GoToDefinitionParameters
+            {
+                Symbol = "NonExistentClass",
+                WorkspacePath = TestWorkspacePath
+            };
+            _tool = CreateToolWithSQLite(CreateSQLiteMock("NonExistentClass"));
+
+            // Act
+            var result = await _tool.ExecuteAsync(parameters, CancellationToken.None);
+
+            // Assert
+            result.Success.Should().BeFalse();
+            _prefetchMock.Verify(p => p.PrefetchSymbol(It.IsAny<string?>(), It.IsAny<string>(), It.IsAny<string?>()), Times.Never);
+        }
+
+        private Mock<ISQLiteSymbolService> CreateSQLiteMock(string symbolName)
+        {
+            var sqliteMock = new Mock<ISQLiteSymbolService>();
+            sqliteMock
+                .Setup(x => x.DatabaseExists(It.IsAny<string>()))
+                .Returns(true);
+            sqliteMock
+                .Setup(x => x.GetSymbolsByNameAsync(It.IsAny<string>(), symbolName, false, It.IsAny<CancellationToken>()))
+                .ReturnsAsync(CreateMockSQLiteSymbols().Where(s => s.Name == symbolName).ToList());
+            sqliteMock
+                .Setup(x => x.GetFileByPathAsync(It.IsAny<string>(), It.IsAny<string>(), It.IsAny<CancellationToken>()))
+                .ReturnsAsync(CreateMockSQLiteFile());
+            return sqliteMock;
+        }
+
         private List<JulieSymbol> CreateMockSQLiteSymbols()
         {
             return new List<JulieSymbol>
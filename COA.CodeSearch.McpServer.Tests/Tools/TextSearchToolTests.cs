@@ -15,7 +15,10 @@ using System.Threading;
 using System;
 using System.Collections.Generic;
 using COA.Mcp.Framework.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
 using Microsoft.Extensions.Logging;
+using Microsoft.Extensions.Logging.Abstractions;
 
 namespace COA.CodeSearch.McpServer.Tests.Tools
 {
@@ -23,6 +26,18 @@ namespace COA.CodeSearch.McpServer.Tests.Tools
     public class TextSearchToolTests : CodeSearchToolTestBase<TextSearchTool>
     {
         private TextSearchTool _tool = null!;
+
+        protected override void ConfigureServices(IServiceCollection services)
+        {
+            base.ConfigureServices(services);
+
+            // A quiet period far longer than any test, so only a query indexes recorded writes
+            var configuration = new ConfigurationBuilder()
+                .AddInMemoryCollection(new Dictionary<string, string?> { ["CodeSearch:FileWatcher:CoalesceQuietMilliseconds"] = "60000" })
+                .Build();
+            services.AddSingleton(sp => new FileWatcherService(NullLogger<FileWatcherService>.Instance, configuration,
+                FileIndexingServiceMock.Object, PathResolutionServiceMock.Object, sp));
+        }
         
         protected override TextSearchTool CreateTool()
         {
@@ -621,5 +636,56 @@ namespace COA.CodeSearch.McpServer.Tests.Tools
         }
 
         #endregion Type-First Workflow Tests
+
+        [Test]
+        public async Task ExecuteAsync_Should_Index_A_Write_Made_Through_The_Server_Before_Searching()
+        {
+            // Arrange
+            SetupNoIndex();
+            var path = RecordWrite("Service.cs", "class Service { }");
+            var parameters = new TextSearchParameters
+            {
+                Query = "Service",
+                WorkspacePath = TestWorkspacePath
+            };
+
+            // Act
+            await ExecuteToolAsync<AIOptimizedResponse<SearchResult>>(
+                async () => await _tool.ExecuteAsync(parameters, CancellationToken.None));
+
+            // Assert
+            FileIndexingServiceMock.Verify(x => x.IndexFileAsync(It.IsAny<string>(), path, It.IsAny<CancellationToken>()), Times.Once);
+        }
+
+        [Test]
+        public async Task ExecuteAsync_Should_Not_Wait_For_Pending_Edits_When_Skipped()
+        {
+            // Arrange
+            SetupNoIndex();
+            var path = RecordWrite("Service.cs", "class Service { }");
+            var parameters = new TextSearchParameters
+            {
+                Query = "Service",
+                WorkspacePath = TestWorkspacePath,
+                SkipPendingEdits = true
+            };
+
+            // Act
+            await ExecuteToolAsync<AIOptimizedResponse<SearchResult>>(
+                async () => await _tool.ExecuteAsync(parameters, CancellationToken.None));
+
+            // Assert
+            FileIndexingServiceMock.Verify(x => x.IndexFileAsync(It.IsAny<string>(), path, It.IsAny<CancellationToken>()), Times.Never);
+        }
+
+        private string RecordWrite(string name, string content)
+        {
+            var fileWatcher = ServiceProvider.GetRequiredService<FileWatcherService>();
+            fileWatcher.StartWatching(TestWorkspacePath);
+            var path = Path.Combine(TestWorkspacePath, name);
+            File.WriteAllText(path, content);
+            fileWatcher.RecordWrite(path);
+            return path;
+        }
     }
 }
\ No newline at end of file
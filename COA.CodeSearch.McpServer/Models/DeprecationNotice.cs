@@ -0,0 +1,19 @@
+namespace COA.CodeSearch.McpServer.Models;
+
+/// <summary>
+/// Describes a deprecated parameter a client sent, so integrations can migrate before it is removed
+/// </summary>
+public class DeprecationNotice
+{
+    public string ToolName { get; set; } = string.Empty;
+    public string Parameter { get; set; } = string.Empty;
+    public string ReplacedBy { get; set; } = string.Empty;
+    public string Since { get; set; } = string.Empty;
+    public string? RemovedIn { get; set; }
+
+    public override string ToString()
+    {
+        var removal = RemovedIn != null ? $" and will be removed in schema v{RemovedIn}" : string.Empty;
+        return $"Parameter '{Parameter}' of {ToolName} is deprecated since schema v{Since}{removal} - use '{ReplacedBy}' instead";
+    }
+}
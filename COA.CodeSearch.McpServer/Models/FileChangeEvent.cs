@@ -9,6 +9,16 @@ public class FileChangeEvent
     public string WorkspacePath { get; set; } = string.Empty;
     public FileChangeType ChangeType { get; set; }
     public DateTime Timestamp { get; set; } = DateTime.UtcNow;
+
+    /// <summary>
+    /// When the first change of the current burst was seen; <see cref="Timestamp"/> moves with every later one
+    /// </summary>
+    public DateTime FirstSeen { get; set; } = DateTime.UtcNow;
+
+    /// <summary>
+    /// Attempts to index this change that failed; reset when the file changes again
+    /// </summary>
+    public int FailedAttempts { get; set; }
 }
 
 /// <summary>
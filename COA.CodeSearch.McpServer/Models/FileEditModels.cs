@@ -229,4 +229,45 @@ public class EditPermissionResult
     /// Suggestions if the edit was denied
     /// </summary>
     public List<string> Suggestions { get; set; } = new();
-}
\ No newline at end of file
+}
+/// <summary>
+/// One file of a set applied together by <c>UnifiedFileEditService.ApplyFileSetAsync</c>
+/// </summary>
+public class FileSetChange
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// New content; null deletes the file
+    /// </summary>
+    public string? NewContent { get; set; }
+
+    /// <summary>
+    /// SHA-256 of the content the change was based on (null when the file did not exist).
+    /// The set is rejected if the file no longer matches. Leave unset to skip the check.
+    /// </summary>
+    public string? ExpectedHash { get; set; }
+    public bool CheckExpectedHash { get; set; }
+}
+
+/// <summary>
+/// Outcome of applying a file set: either every file was written or none was
+/// </summary>
+public class FileSetResult
+{
+    public bool Success { get; set; }
+    public List<string> Written { get; set; } = new();
+    public List<string> Deleted { get; set; } = new();
+
+    /// <summary>
+    /// Files whose content changed since the set was prepared
+    /// </summary>
+    public List<string> Conflicts { get; set; } = new();
+
+    public string? ErrorMessage { get; set; }
+
+    /// <summary>
+    /// True when a write failed part-way and the files already written were restored
+    /// </summary>
+    public bool RolledBack { get; set; }
+}
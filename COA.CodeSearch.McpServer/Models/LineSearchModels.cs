@@ -88,6 +88,13 @@ public class LineSearchParams
     [Description("Maximum tokens for response (default: 8000)")]
     [JsonPropertyName("maxTokens")]
     public int MaxTokens { get; set; } = 8000;
+
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    [JsonPropertyName("skipPendingEdits")]
+    public bool SkipPendingEdits { get; set; } = false;
 }
 
 /// <summary>
@@ -136,6 +143,18 @@ public class LineMatch
     /// </summary>
     [JsonPropertyName("highlightedFragments")]
     public List<string>? HighlightedFragments { get; set; }
+
+    /// <summary>
+    /// Notebook cell holding the match (1-based position in the notebook), for Jupyter notebooks
+    /// </summary>
+    [JsonPropertyName("cell")]
+    public int? Cell { get; set; }
+
+    /// <summary>
+    /// Line of the match within the notebook cell
+    /// </summary>
+    [JsonPropertyName("cellLine")]
+    public int? CellLine { get; set; }
 }
 
 /// <summary>
@@ -35,6 +35,24 @@ public enum SearchMode
     /// </summary>
     Regex,
 
+    /// <summary>
+    /// Statistical estimate: evaluates the query over a random subset of files and extrapolates
+    /// the total number of matching files with a confidence interval.
+    /// </summary>
+    Sample,
+
+    /// <summary>
+    /// Go struct tag search: matches tag:KEY=VALUE queries (tag:json=user_name, tag:column=is_active)
+    /// against struct field tags and returns the field declarations.
+    /// </summary>
+    Tag,
+
+    /// <summary>
+    /// Config key path search: matches path:KEY.PATH queries (path:server.timeout, path:services.*.image)
+    /// against the nested keys of JSON and YAML files and returns the key lines.
+    /// </summary>
+    Config,
+
     // Internal routing modes - used by Auto mode's smart detection
     /// <summary>
     /// Pattern-preserving search mode (internal use).
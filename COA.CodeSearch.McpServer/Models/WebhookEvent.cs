@@ -0,0 +1,41 @@
+namespace COA.CodeSearch.McpServer.Models;
+
+/// <summary>
+/// Event names webhook endpoints can subscribe to
+/// </summary>
+public static class WebhookEvents
+{
+    /// <summary>A full index_workspace pass finished</summary>
+    public const string IndexCompleted = "index.completed";
+
+    /// <summary>find_patterns reported findings that were not in the file's baseline</summary>
+    public const string FindingsNew = "findings.new";
+
+    /// <summary>Indexing found something that looks like a credential</summary>
+    public const string SecretsDetected = "secrets.detected";
+
+    /// <summary>An incremental index update added lines matching a standing watch</summary>
+    public const string WatchMatched = "watch.matched";
+
+    public static readonly string[] All = { IndexCompleted, FindingsNew, SecretsDetected, WatchMatched };
+}
+
+/// <summary>
+/// Payload posted to webhook endpoints
+/// </summary>
+public class WebhookEvent
+{
+    public string Event { get; set; } = string.Empty;
+    public DateTime Timestamp { get; set; } = DateTime.UtcNow;
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// One-line description, used as the message text for chat endpoints
+    /// </summary>
+    public string Summary { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Event-specific details
+    /// </summary>
+    public object? Data { get; set; }
+}
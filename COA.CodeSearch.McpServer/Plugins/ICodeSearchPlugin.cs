@@ -0,0 +1,35 @@
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+
+namespace COA.CodeSearch.McpServer.Plugins;
+
+/// <summary>
+/// Entry point of a plugin assembly. The loader creates one instance of every public, non-abstract
+/// implementation it finds and calls <see cref="ConfigureServices"/> before the server starts.
+/// </summary>
+/// <remarks>
+/// Plugins register their extensions as services:
+/// <list type="bullet">
+/// <item><see cref="IPatternAnalyzerPlugin"/> - extra checks run by find_patterns</item>
+/// <item><see cref="ISymbolExtractorPlugin"/> - symbol extraction for files julie-codesearch does not cover</item>
+/// <item>MCP tools - any <c>McpToolBase</c> subclass in the plugin assembly is discovered like a built-in tool</item>
+/// </list>
+/// Plugin services can depend on server services such as <c>ILuceneIndexService</c> and <c>ISQLiteSymbolService</c>
+/// to run against the server's indexes.
+/// </remarks>
+public interface ICodeSearchPlugin
+{
+    /// <summary>
+    /// Unique plugin name, used in logs and in the capabilities tool
+    /// </summary>
+    string Name { get; }
+
+    string Version { get; }
+
+    /// <summary>
+    /// Registers the plugin's analyzers, extractors, tools and their dependencies
+    /// </summary>
+    /// <param name="services">The server's service collection</param>
+    /// <param name="configuration">Server configuration; plugin settings live under CodeSearch:Plugins:Settings:{Name}</param>
+    void ConfigureServices(IServiceCollection services, IConfiguration configuration);
+}
@@ -0,0 +1,44 @@
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Tools.Models;
+
+namespace COA.CodeSearch.McpServer.Plugins;
+
+/// <summary>
+/// Custom code analyzer run by find_patterns alongside the built-in detectors
+/// </summary>
+public interface IPatternAnalyzerPlugin
+{
+    /// <summary>
+    /// Analyzer name, reported as the source of its findings
+    /// </summary>
+    string Name { get; }
+
+    /// <summary>
+    /// Whether this analyzer applies to a file
+    /// </summary>
+    /// <param name="language">Language reported by julie-codesearch, or null when no symbols are indexed</param>
+    /// <param name="extension">Lower-case file extension including the dot</param>
+    bool Supports(string? language, string extension);
+
+    /// <summary>
+    /// Analyzes one file
+    /// </summary>
+    Task<IReadOnlyList<CodePattern>> AnalyzeAsync(PatternAnalysisContext context, CancellationToken cancellationToken);
+}
+
+/// <summary>
+/// File being analyzed by an <see cref="IPatternAnalyzerPlugin"/>
+/// </summary>
+public class PatternAnalysisContext
+{
+    public required string FilePath { get; init; }
+    public required string WorkspacePath { get; init; }
+    public required string Content { get; init; }
+    public required string[] Lines { get; init; }
+    public string? Language { get; init; }
+
+    /// <summary>
+    /// Symbols for the file from the SQLite symbol database, when available
+    /// </summary>
+    public IReadOnlyList<JulieSymbol>? Symbols { get; init; }
+}
@@ -0,0 +1,22 @@
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Plugins;
+
+/// <summary>
+/// Symbol extractor for file types julie-codesearch does not cover (DSLs, config formats, in-house languages).
+/// Used during indexing when no symbols were found for a file, so its types and methods become searchable.
+/// </summary>
+public interface ISymbolExtractorPlugin
+{
+    string Name { get; }
+
+    /// <summary>
+    /// File extensions handled, including the dot (e.g. ".proto")
+    /// </summary>
+    IReadOnlyCollection<string> Extensions { get; }
+
+    /// <summary>
+    /// Extracts types and methods from a file, or returns null when nothing was found
+    /// </summary>
+    Task<TypeExtractionResult?> ExtractAsync(string filePath, string content, CancellationToken cancellationToken);
+}
@@ -0,0 +1,37 @@
+using System.Reflection;
+using System.Runtime.Loader;
+
+namespace COA.CodeSearch.McpServer.Plugins;
+
+/// <summary>
+/// Isolated load context for one plugin. The plugin's private dependencies resolve from its own folder,
+/// while assemblies the server already has loaded (this server, the MCP framework, Lucene, Microsoft.Extensions)
+/// are shared so plugin types implement the same interfaces the server sees.
+/// </summary>
+internal sealed class PluginLoadContext : AssemblyLoadContext
+{
+    private readonly AssemblyDependencyResolver _resolver;
+
+    public PluginLoadContext(string pluginPath) : base(Path.GetFileNameWithoutExtension(pluginPath), isCollectible: false)
+    {
+        _resolver = new AssemblyDependencyResolver(pluginPath);
+    }
+
+    protected override Assembly? Load(AssemblyName assemblyName)
+    {
+        // Prefer the host's copy of shared contracts
+        if (Default.Assemblies.Any(a => AssemblyName.ReferenceMatchesDefinition(assemblyName, a.GetName())))
+        {
+            return null;
+        }
+
+        var path = _resolver.ResolveAssemblyToPath(assemblyName);
+        return path != null ? LoadFromAssemblyPath(path) : null;
+    }
+
+    protected override IntPtr LoadUnmanagedDll(string unmanagedDllName)
+    {
+        var path = _resolver.ResolveUnmanagedDllToPath(unmanagedDllName);
+        return path != null ? LoadUnmanagedDllFromPath(path) : IntPtr.Zero;
+    }
+}
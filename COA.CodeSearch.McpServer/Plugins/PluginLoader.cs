@@ -0,0 +1,172 @@
+using System.Reflection;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Plugins;
+
+/// <summary>
+/// A plugin assembly found at startup
+/// </summary>
+public class LoadedPlugin
+{
+    public string Name { get; init; } = string.Empty;
+    public string Version { get; init; } = string.Empty;
+    public string AssemblyPath { get; init; } = string.Empty;
+
+    /// <summary>
+    /// The loaded assembly, or null when loading failed
+    /// </summary>
+    public Assembly? Assembly { get; init; }
+
+    public string? Error { get; init; }
+    public bool Loaded => Error == null;
+}
+
+/// <summary>
+/// Plugins loaded at startup, registered as a singleton for diagnostics
+/// </summary>
+public class PluginCatalog
+{
+    public PluginCatalog(IReadOnlyList<LoadedPlugin> plugins)
+    {
+        Plugins = plugins;
+    }
+
+    public IReadOnlyList<LoadedPlugin> Plugins { get; }
+}
+
+/// <summary>
+/// Loads plugin assemblies listed in configuration or found in the plugins directory.
+/// Plugins run in-process with the server's permissions, so loading is off unless
+/// CodeSearch:Plugins:Enabled is set, and plugins are never loaded from a workspace.
+/// </summary>
+public static class PluginLoader
+{
+    /// <summary>
+    /// Default plugins directory: ~/.coa/codesearch/plugins, one sub-folder per plugin
+    /// </summary>
+    public static string DefaultPluginsDirectory => Path.Combine(
+        Environment.GetFolderPath(Environment.SpecialFolder.UserProfile), ".coa", "codesearch", "plugins");
+
+    /// <summary>
+    /// Loads every configured plugin and lets it register its services.
+    /// A plugin that fails to load or configure is reported and skipped; it never stops the server.
+    /// </summary>
+    public static PluginCatalog LoadPlugins(IServiceCollection services, IConfiguration configuration, ILogger logger)
+    {
+        var loaded = new List<LoadedPlugin>();
+        if (!configuration.GetValue("CodeSearch:Plugins:Enabled", false))
+        {
+            return Register(services, loaded);
+        }
+
+        foreach (var assemblyPath in FindPluginAssemblies(configuration, logger))
+        {
+            loaded.AddRange(LoadPlugin(assemblyPath, services, configuration, logger));
+        }
+
+        logger.LogInformation("Loaded {Count} plugin(s): {Plugins}",
+            loaded.Count(p => p.Loaded), string.Join(", ", loaded.Where(p => p.Loaded).Select(p => $"{p.Name} {p.Version}")));
+        return Register(services, loaded);
+    }
+
+    /// <summary>
+    /// Gets the assembly paths to load: explicit CodeSearch:Plugins:Paths entries, then
+    /// {dir}/{name}/{name}.dll for each sub-folder of the plugins directory
+    /// </summary>
+    public static IReadOnlyList<string> FindPluginAssemblies(IConfiguration configuration, ILogger logger)
+    {
+        var paths = new List<string>();
+
+        foreach (var path in configuration.GetSection("CodeSearch:Plugins:Paths").Get<string[]>() ?? Array.Empty<string>())
+        {
+            var fullPath = Path.GetFullPath(Environment.ExpandEnvironmentVariables(path));
+            if (File.Exists(fullPath))
+                paths.Add(fullPath);
+            else
+                logger.LogWarning("Plugin assembly not found: {PluginPath}", fullPath);
+        }
+
+        var directory = configuration.GetValue<string>("CodeSearch:Plugins:Directory");
+        directory = string.IsNullOrWhiteSpace(directory)
+            ? DefaultPluginsDirectory
+            : Path.GetFullPath(Environment.ExpandEnvironmentVariables(directory));
+
+        if (Directory.Exists(directory))
+        {
+            foreach (var pluginFolder in Directory.GetDirectories(directory).OrderBy(d => d, StringComparer.Ordinal))
+            {
+                var candidate = Path.Combine(pluginFolder, Path.GetFileName(pluginFolder) + ".dll");
+                if (File.Exists(candidate) && !paths.Contains(candidate, StringComparer.OrdinalIgnoreCase))
+                    paths.Add(candidate);
+            }
+        }
+
+        return paths;
+    }
+
+    private static IEnumerable<LoadedPlugin> LoadPlugin(string assemblyPath, IServiceCollection services, IConfiguration configuration, ILogger logger)
+    {
+        Assembly assembly;
+        List<Type> pluginTypes;
+        try
+        {
+            assembly = new PluginLoadContext(assemblyPath).LoadFromAssemblyPath(assemblyPath);
+            pluginTypes = assembly.GetExportedTypes()
+                .Where(t => t.IsClass && !t.IsAbstract && typeof(ICodeSearchPlugin).IsAssignableFrom(t))
+                .ToList();
+        }
+        catch (Exception ex)
+        {
+            logger.LogError(ex, "Failed to load plugin assembly {PluginPath}", assemblyPath);
+            return new[] { Failed(assemblyPath, ex.Message) };
+        }
+
+        if (pluginTypes.Count == 0)
+        {
+            logger.LogWarning("Plugin assembly {PluginPath} has no public ICodeSearchPlugin implementation", assemblyPath);
+            return new[] { Failed(assemblyPath, "No ICodeSearchPlugin implementation found") };
+        }
+
+        var results = new List<LoadedPlugin>();
+        foreach (var type in pluginTypes)
+        {
+            try
+            {
+                var plugin = (ICodeSearchPlugin)Activator.CreateInstance(type)!;
+                plugin.ConfigureServices(services, configuration);
+                results.Add(new LoadedPlugin
+                {
+                    Name = plugin.Name,
+                    Version = plugin.Version,
+                    AssemblyPath = assemblyPath,
+                    Assembly = assembly
+                });
+                logger.LogInformation("Plugin {PluginName} {PluginVersion} configured from {PluginPath}",
+                    plugin.Name, plugin.Version, assemblyPath);
+            }
+            catch (Exception ex)
+            {
+                logger.LogError(ex, "Plugin {PluginType} failed to configure", type.FullName);
+                results.Add(Failed(assemblyPath, $"{type.FullName}: {ex.Message}"));
+            }
+        }
+
+        return results;
+    }
+
+    private static LoadedPlugin Failed(string assemblyPath, string error) => new()
+    {
+        Name = Path.GetFileNameWithoutExtension(assemblyPath),
+        AssemblyPath = assemblyPath,
+        Error = error
+    };
+
+    private static PluginCatalog Register(IServiceCollection services, List<LoadedPlugin> loaded)
+    {
+        var catalog = new PluginCatalog(loaded);
+        services.AddSingleton(catalog);
+        return catalog;
+    }
+}
@@ -10,10 +10,37 @@ using Microsoft.Extensions.Hosting;
 using Microsoft.Extensions.Logging;
 using Microsoft.Extensions.Options;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Ci;
+using COA.CodeSearch.McpServer.Services.ColdStorage;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Assets;
+using COA.CodeSearch.McpServer.Services.Anchors;
+using COA.CodeSearch.McpServer.Services.GoBuild;
+using COA.CodeSearch.McpServer.Services.GoTypes;
+using COA.CodeSearch.McpServer.Services.Constants;
+using COA.CodeSearch.McpServer.Services.OpenApi;
+using COA.CodeSearch.McpServer.Services.GraphQL;
+using COA.CodeSearch.McpServer.Services.Orm;
+using COA.CodeSearch.McpServer.Services.Migrations;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Graph;
 using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using COA.CodeSearch.McpServer.Services.Roslyn;
+using COA.CodeSearch.McpServer.Services.Logging;
+using COA.CodeSearch.McpServer.Services.RateLimiting;
+using COA.CodeSearch.McpServer.Services.NetworkFileSystem;
 using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sampling;
+using COA.CodeSearch.McpServer.Services.Elicitation;
+using COA.CodeSearch.McpServer.Services.Recipes;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Services.Editor;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
 using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Services.Watches;
 using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Plugins;
 using COA.CodeSearch.McpServer.Tools;
 using Lucene.Net.Util;
 using Serilog;
@@ -44,11 +71,75 @@ public class Program
         // Register core services
         services.AddSingleton<IPathResolutionService, PathResolutionService>();
         services.AddSingleton<IParameterDefaultsService, ParameterDefaultsService>();
+        services.AddSingleton<IParameterMigrationService, ParameterMigrationService>();
         // WorkspaceRegistry removed - using hybrid local indexing model
         services.AddSingleton<ICircuitBreakerService, CircuitBreakerService>();
         services.AddSingleton<IMemoryPressureService, MemoryPressureService>();
         services.AddSingleton<IQueryCacheService, QueryCacheService>();
         
+        // Graceful shutdown coordination (checkpoint in-flight indexing, bounded by CodeSearch:Shutdown:TimeoutSeconds)
+        services.AddSingleton<IShutdownCoordinator, ShutdownCoordinator>();
+        services.Configure<HostOptions>(options =>
+            options.ShutdownTimeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Shutdown:TimeoutSeconds", 10) + 5));
+        
+        // Encryption at rest for Lucene snapshots and SQLite symbol databases (opt-in via CodeSearch:Encryption)
+        services.AddSingleton<IIndexEncryptionService, IndexEncryptionService>();
+        // Registered before FileWatcher so databases are unsealed first and sealed last
+        services.AddHostedService<EncryptedStorageService>();
+        
+        // Log buffer and runtime log level control (get_logs, set_log_level)
+        services.AddSingleton(InMemoryLogSink.Instance);
+        services.AddSingleton<ILogQueryService, LogQueryService>();
+        services.AddSingleton<COA.Mcp.Framework.Pipeline.ISimpleMiddleware, QueryUsageMiddleware>(); // meta.resourceUsage on responses - opt-in via CodeSearch:QueryUsage
+        services.AddSingleton<COA.Mcp.Framework.Pipeline.ISimpleMiddleware, SchemaInfoMiddleware>(); // meta.schemaVersion and deprecation warnings on every response
+        
+        // Per-session rate limits and fair scheduling of queries between sessions - opt-in via CodeSearch:RateLimiting
+        services.AddSingleton<SessionLimiter>();
+        services.AddSingleton<ISessionLimiter>(provider => provider.GetRequiredService<SessionLimiter>());
+        services.AddHostedService(provider => provider.GetRequiredService<SessionLimiter>());
+        services.AddSingleton<COA.Mcp.Framework.Pipeline.ISimpleMiddleware, SessionLimitMiddleware>(); // Admits tool calls, refuses with retry-after hints
+        
+        // Index retention (storage quotas, LRU eviction of stale workspace indexes, purge_index)
+        services.AddSingleton<IndexRetentionService>();
+        services.AddSingleton<IIndexRetentionService>(provider => provider.GetRequiredService<IndexRetentionService>());
+        services.AddHostedService(provider => provider.GetRequiredService<IndexRetentionService>());
+        services.AddSingleton<IIndexBudgetService, IndexBudgetService>(); // Prune files when an index exceeds MaxWorkspaceSizeMB
+        
+        // Webhook notifications (index completion, new findings vs baseline, detected secrets) - opt-in via CodeSearch:Webhooks
+        services.AddSingleton<IFindingsBaselineService, FindingsBaselineService>();
+        services.AddSingleton<WebhookService>();
+        services.AddSingleton<IWebhookService>(provider => provider.GetRequiredService<WebhookService>());
+        services.AddHostedService(provider => provider.GetRequiredService<WebhookService>());
+
+        // Watch expressions (standing queries evaluated by the file watcher, alerts as notifications and webhooks)
+        services.AddSingleton<IWatchService, WatchService>();
+
+        // Background prefetch of find_references / get_symbols_overview after goto_definition and symbol_search
+        services.AddSingleton<PrefetchService>();
+        services.AddSingleton<IPrefetchService>(provider => provider.GetRequiredService<PrefetchService>());
+        services.AddHostedService(provider => provider.GetRequiredService<PrefetchService>());
+        services.AddSingleton<ILanguageServerService, LanguageServerService>(); // Optional gopls/OmniSharp/tsserver for precise definitions and renames
+        services.AddSingleton<IRoslynAnalysisService, RoslynAnalysisService>(); // Optional Roslyn solution for C# references, renames and nullable flow
+        services.AddSingleton<IGoTypesService, GoTypesService>(); // Optional go/types helper for Go references, renames and type_of
+        
+        // Network file system resilience (retries, no mmap, polling watcher) - CodeSearch:NetworkFileSystem
+        services.AddSingleton<INetworkFileSystemService, NetworkFileSystemService>();
+        
+        // Cold storage tier (reduced-fidelity indexing for CodeSearch:ColdStorage:Paths, materialized on demand)
+        services.AddSingleton<IColdTierService, ColdTierService>();
+        
+        // Vendored and third-party directories: excluded, indexed as dependency code, or downranked (per workspace)
+        services.AddSingleton<IDependencyCodeService, DependencyCodeService>();
+        
+        // Non-code assets (size, hash, references from code), inventoried beside the index after each full pass
+        services.AddSingleton<IAssetInventoryService, AssetInventoryService>();
+        
+        // Git access for code review tools (read-only git CLI calls)
+        services.AddSingleton<COA.CodeSearch.McpServer.Services.Git.IGitService, COA.CodeSearch.McpServer.Services.Git.GitService>();
+        
+        // Point-in-time index snapshots for snapshot_diff (stored beside the index, encrypted when enabled)
+        services.AddSingleton<IIndexSnapshotService, IndexSnapshotService>();
+        
         // Register Lucene services
         services.AddSingleton<COA.CodeSearch.McpServer.Services.Lucene.ILuceneIndexService, 
                               COA.CodeSearch.McpServer.Services.Lucene.LuceneIndexService>();
@@ -67,7 +158,16 @@ public class Program
             sp.GetRequiredService<IOptions<MemoryLimitsConfiguration>>(),
             sp.GetRequiredService<IJulieCodeSearchService>(),     // Pass julie-codesearch service
             sp.GetRequiredService<ISQLiteSymbolService>(),         // Pass SQLite service
-            sp.GetRequiredService<ISemanticIntelligenceService>() // Pass semantic service
+            sp.GetRequiredService<ISemanticIntelligenceService>(), // Pass semantic service
+            sp.GetRequiredService<IShutdownCoordinator>(),         // Stop full passes at a checkpoint on shutdown
+            sp.GetServices<ISymbolExtractorPlugin>(),              // Symbol extraction for file types from plugins
+            sp.GetRequiredService<IWebhookService>(),              // Index completion and secret detection events
+            sp.GetRequiredService<IFindingsBaselineService>(),     // Only notify about secrets not reported before
+            sp.GetRequiredService<IColdTierService>(),             // Reduced-fidelity documents for cold paths
+            sp.GetRequiredService<IIndexBudgetService>(),          // Leave out files pruned to fit the size budget
+            sp.GetRequiredService<IDependencyCodeService>(),       // Exclude or flag vendored and third-party code
+            sp.GetRequiredService<IAssetInventoryService>(),       // Inventory images, fonts and fixtures after each full pass
+            sp.GetRequiredService<INetworkFileSystemService>()     // Retry transient read errors on network file systems
         ));
         
         // Register support services
@@ -80,6 +180,10 @@ public class Program
         // Register modern file editing services
         services.AddScoped<UnifiedFileEditService>();
         services.AddSingleton<IWorkspacePermissionService, WorkspacePermissionService>();
+        services.AddSingleton<IScratchWorkspaceService, ScratchWorkspaceService>(); // Staged generated code, in memory until committed
+        services.AddSingleton<IEditorContextService, EditorContextService>(); // Open file, cursor and selection the client reported last
+        services.AddSingleton<IWorkingSetService, WorkingSetService>(); // Files and symbols the session touched, boosted in ranking
+        services.AddSingleton<IRecipeService, RecipeService>(); // Recipe planning, build checks and rollback history
         
         // API services for HTTP mode
         services.AddSingleton<ConfidenceCalculatorService>();
@@ -89,11 +193,14 @@ public class Program
         
         // Query preprocessing for code-aware search
         services.AddSingleton<QueryPreprocessor>();
+        services.AddSingleton<QueryCostEstimator>(); // Warn, confirm or sample expensive text searches
         
         // Code analysis services  
         services.AddSingleton<COA.CodeSearch.McpServer.Services.Analysis.CodeAnalyzer>(provider =>
             new COA.CodeSearch.McpServer.Services.Analysis.CodeAnalyzer(LuceneVersion.LUCENE_48, 
-                preserveCase: false, splitCamelCase: true));
+                preserveCase: false, splitCamelCase: true,
+                normalization: COA.CodeSearch.McpServer.Services.Analysis.UnicodeIdentifiers.ParseNormalization(
+                    configuration.GetValue<string>("CodeSearch:Unicode:Normalization"))));
         
         // Julie integration services for tree-sitter extraction and semantic search
         // Julie CodeSearch CLI service for SQLite-based indexing (scan + update commands)
@@ -111,6 +218,33 @@ public class Program
         services.AddSingleton<COA.CodeSearch.McpServer.Services.Sqlite.ISQLiteSymbolService,
                               COA.CodeSearch.McpServer.Services.Sqlite.SQLiteSymbolService>();
 
+        // Symbol anchors (symbol path + body fingerprint, re-resolved after edits)
+        services.AddSingleton<ISymbolAnchorService, SymbolAnchorService>();
+
+        // Graph queries (chained filters and traversals over symbols, calls and inheritance)
+        services.AddSingleton<IGraphQueryService, GraphQueryService>();
+
+        // Data flow (value sources and sinks of a variable, across calls)
+        services.AddSingleton<IDataFlowService, DataFlowService>();
+
+        // Constant and enum catalog (values from declarations, usages by name and by raw literal)
+        services.AddSingleton<IConstantUsageService, ConstantUsageService>();
+
+        // ORM schema (entities mapped to tables and columns, code reading and writing a column)
+        services.AddSingleton<IOrmSchemaService, OrmSchemaService>();
+
+        // Migration files (goose, golang-migrate, dbmate, Flyway, EF Core) replayed into the schema they build
+        services.AddSingleton<IMigrationIndexService, MigrationIndexService>();
+
+        // OpenAPI spec index (operations and schemas linked to handlers and DTO types)
+        services.AddSingleton<IOpenApiIndexService, OpenApiIndexService>();
+
+        // GraphQL schema index (types and fields linked to resolvers and models)
+        services.AddSingleton<IGraphQLIndexService, GraphQLIndexService>();
+
+        // Go build profiles (per-workspace GOOS/GOARCH/tags) and file build constraints
+        services.AddSingleton<IGoBuildProfileService, GoBuildProfileService>();
+
         // SQLite vec extension for semantic vector search
         services.AddSingleton<COA.CodeSearch.McpServer.Services.Sqlite.ISqliteVecExtensionService,
                               COA.CodeSearch.McpServer.Services.Sqlite.SqliteVecExtensionService>();
@@ -227,10 +361,22 @@ public class Program
         {
             processMode = "SERVICE";
         }
+        else if (args?.FirstOrDefault() == "ci")
+        {
+            processMode = "CI";
+        }
+
+        // Runtime-adjustable levels (set_log_level tool) take precedence over the static configuration
+        RuntimeLogLevel.Initialize(configuration);
 
         Log.Logger = new LoggerConfiguration()
             .ReadFrom.Configuration(configuration)
+            .MinimumLevel.ControlledBy(RuntimeLogLevel.Default)
+            .MinimumLevel.Override(RuntimeLogLevel.CodeSearchSource, RuntimeLogLevel.CodeSearch)
             .Enrich.WithProperty("ProcessMode", processMode) // Add process mode to all log entries
+            .Enrich.FromLogContext()
+            .Enrich.With(new CorrelationIdEnricher()) // Per-request correlation ID and tool name
+            .WriteTo.Sink(InMemoryLogSink.Instance) // Recent entries for the get_logs tool
             .WriteTo.File(
                 logFile,
                 rollingInterval: RollingInterval.Day,
@@ -238,12 +384,12 @@ public class Program
                 fileSizeLimitBytes: 10 * 1024 * 1024, // 10MB
                 retainedFileCountLimit: 7, // Keep 7 days of logs
                 shared: true, // CRITICAL: Allow multiple processes to write to same file
-                outputTemplate: "{Timestamp:yyyy-MM-dd HH:mm:ss.fff zzz} [{Level:u3}] [{ProcessMode}] {SourceContext} {Message:lj}{NewLine}{Exception}"
+                outputTemplate: "{Timestamp:yyyy-MM-dd HH:mm:ss.fff zzz} [{Level:u3}] [{ProcessMode}] [{CorrelationId}] {SourceContext} {Message:lj}{NewLine}{Exception}"
             )
             .CreateLogger();
     }
 
-    public static async Task Main(string[] args)
+    public static async Task<int> Main(string[] args)
     {
         // CodeSearch now runs in STDIO mode only (HTTP API removed)
 
@@ -253,6 +399,12 @@ public class Program
             .AddEnvironmentVariables()
             .Build();
 
+        // Non-interactive PR gate: codesearch ci [options]
+        if (args.Length > 0 && args[0] == "ci")
+        {
+            return await RunCiAsync(args.Skip(1).ToArray(), configuration);
+        }
+
         // Configure Serilog early - FILE ONLY (no console to avoid breaking STDIO)
         ConfigureSerilog(configuration, args);
 
@@ -267,6 +419,7 @@ public class Program
                 .ConfigureLogging(logging =>
                 {
                     logging.ClearProviders();
+                    logging.SetMinimumLevel(Microsoft.Extensions.Logging.LogLevel.Trace); // Serilog level switches do the filtering
                     logging.AddSerilog(); // Use Serilog for all logging
                 })
                 // Framework 2.1.12+ features: Opt-in production features that were previously default-enabled
@@ -275,6 +428,10 @@ public class Program
 
             // Configure shared services
             ConfigureSharedServices(builder.Services, configuration);
+
+            // Load plugins after the built-in services so they can depend on them (and override them if needed)
+            using var pluginLoggerFactory = LoggerFactory.Create(logging => logging.AddSerilog());
+            var pluginCatalog = PluginLoader.LoadPlugins(builder.Services, configuration, pluginLoggerFactory.CreateLogger("CodeSearch.Plugins"));
             
             // Token optimization is active via response builders and DI services above
 
@@ -293,11 +450,14 @@ public class Program
             // Register tools in DI first (required for constructor dependencies)
             // Search tools
             builder.Services.AddScoped<IndexWorkspaceTool>();
+            builder.Services.AddScoped<DependencyCodeTool>(); // Exclude, include or downrank vendored and third-party directories
+            builder.Services.AddScoped<VendoredProvenanceTool>(); // Upstream origins of vendored and copied code, diffed for local changes
             builder.Services.AddScoped<TextSearchTool>(); // Uses BaseResponseBuilder pattern
             builder.Services.AddScoped<SearchFilesTool>(); // Unified file/directory search
             builder.Services.AddScoped<RecentFilesTool>(); // New! Framework 1.5.2 implementation
             builder.Services.AddScoped<LineSearchTool>(); // New! Grep-like line-level search
             builder.Services.AddScoped<SearchAndReplaceTool>(); // Enhanced! Uses DiffMatchPatch and workspace permissions
+            builder.Services.AddScoped<SmartSearchTool>(); // Natural-language request → planned index queries, merged per file
 
             // Navigation tools (from CodeNav consolidation)
             // TODO: All tools should follow the response builder pattern for consistency
@@ -306,28 +466,93 @@ public class Program
             builder.Services.AddScoped<FindReferencesTool>(); // Find all usages of a symbol
             builder.Services.AddScoped<TraceCallPathTool>(); // Hierarchical call chain analysis
             builder.Services.AddScoped<GoToDefinitionTool>(); // Jump to symbol definition
+            builder.Services.AddScoped<GraphQueryTool>(); // Chained filters and traversals over the code graph
+            builder.Services.AddScoped<TaintPathsTool>(); // Shortest call paths from source symbols to sinks
+            builder.Services.AddScoped<NullableFlowTool>(); // C# nullable annotation and flow state at a position (Roslyn tier)
+            builder.Services.AddScoped<TypeOfTool>(); // Go expression types at a position (go/types tier)
+            builder.Services.AddScoped<HoverTool>(); // Type, signature and doc at a position (precise tiers, else the index)
+            builder.Services.AddScoped<DataFlowTool>(); // Where a variable's value comes from and where it goes
+            builder.Services.AddScoped<FindImplementationsTool>(); // Go types whose method sets satisfy an interface
+            builder.Services.AddScoped<FindTestsTool>(); // Tests, table cases, benchmarks and examples exercising a symbol, with run commands
+            builder.Services.AddScoped<FindConstantUsagesTool>(); // Usages of a constant or enum member, by name and by raw value
+            builder.Services.AddScoped<OrmEntitiesTool>(); // ORM entities with their tables and columns
+            builder.Services.AddScoped<ColumnUsagesTool>(); // Code reading or writing a database column
+            builder.Services.AddScoped<MigrationSchemaTool>(); // Schema derived from migration files, with table history and references
+            builder.Services.AddScoped<OpenApiLinksTool>(); // OpenAPI operations and schemas linked to handlers and DTO types
+            builder.Services.AddScoped<GraphQLLinksTool>(); // GraphQL fields linked to resolvers, types to models
+            builder.Services.AddScoped<GoBuildProfileTool>(); // Per-workspace GOOS/GOARCH profile and the files it excludes
+            builder.Services.AddScoped<GoPackageGraphTool>(); // Go package import graph from go.mod, go.sum and import blocks
+            builder.Services.AddScoped<TraceErrorChainTool>(); // Go error wrap chains from construction to errors.Is/As checks
+            builder.Services.AddScoped<ListBuildTargetsTool>(); // Makefile, MSBuild, npm script and Taskfile targets with their commands
+            builder.Services.AddScoped<CiPipelinesTool>(); // GitHub Actions and Azure Pipelines steps checked against workspace paths and env reads
+            builder.Services.AddScoped<KubernetesManifestsTool>(); // Kubernetes manifests and Helm charts linked to image sources, env reads and ConfigMaps
 
             // Editing tools (NEW - Enable dogfooding!)
             builder.Services.AddScoped<EditLinesTool>(); // Unified line editing (insert/replace/delete)
 
+            // Scratch workspace tools (stage generated code, review, then apply as one unit)
+            builder.Services.AddScoped<StageScratchTool>(); // Stage files/snippets without touching the workspace
+            builder.Services.AddScoped<ScratchDiffTool>(); // Unified diff of staged files vs the workspace
+            builder.Services.AddScoped<SearchScratchTool>(); // Line search over staged content
+            builder.Services.AddScoped<CommitScratchTool>(); // All-or-nothing write of staged files
+
+            // Editor integration
+            builder.Services.AddScoped<SetEditorContextTool>(); // Open file, cursor and selection as implicit symbol/position/file and search boost
+            builder.Services.AddScoped<WorkingSetTool>(); // Show or clear the session's working set
+
             // Refactoring tools (AST-aware semantic refactoring)
             builder.Services.AddScoped<SmartRefactorTool>(); // Smart refactoring with symbol-aware transformations
+            builder.Services.AddScoped<RunRecipeTool>(); // Multi-step refactoring recipes with preview and rollback
+            builder.Services.AddScoped<RewriteImportsTool>(); // Import paths and namespaces rewritten across the workspace
 
             // Advanced semantic tools (Tree-sitter + Lucene powered)
             builder.Services.AddScoped<GetSymbolsOverviewTool>(); // Extract all symbols from files
             builder.Services.AddScoped<ReadSymbolsTool>(); // Read specific symbol implementations (token-efficient)
             builder.Services.AddScoped<FindPatternsTool>(); // Semantic pattern detection for code quality
+            builder.Services.AddScoped<FindSimilarCodeTool>(); // Functions resembling a snippet or symbol (token vectors + embeddings)
+            builder.Services.AddScoped<DeadBranchesTool>(); // Conditionals decided by known constants and their unreachable branches
+            builder.Services.AddScoped<ApiContractsTool>(); // Server routes, client calls and OpenAPI specs checked against each other
+            builder.Services.AddScoped<MessageFlowsTool>(); // Message producers and consumers paired by topic, queue or event type
+            builder.Services.AddScoped<WorkspaceOverviewTool>(); // Onboarding report: languages, entry points, clusters, configuration, test layout
+            builder.Services.AddScoped<CheckArchitectureTool>(); // Layering rules checked against imports and references
+            builder.Services.AddScoped<CheckNamespacesTool>(); // Namespace/package declarations checked against directories
+            builder.Services.AddScoped<CheckNamingTool>(); // Symbol names checked against naming rules
+            builder.Services.AddScoped<CheckDocDriftTool>(); // Doc comments checked against the code they document
+            builder.Services.AddScoped<FindCommentedCodeTool>(); // Commented-out code blocks with the commit that disabled them
+            builder.Services.AddScoped<FindUnreferencedAssetsTool>(); // Images, fonts and fixtures no indexed file names
+            builder.Services.AddScoped<FindDuplicateAssetsTool>(); // Assets with identical content by hash
+
+            // Code review tools
+            builder.Services.AddScoped<ReviewContextTool>(); // Review packet for a diff between two refs
+            builder.Services.AddScoped<SuggestReviewersTool>(); // Reviewers from CODEOWNERS, blame and history
+            builder.Services.AddScoped<DiffSummaryTool>(); // Structural diff summary for commit messages and changelogs
+            builder.Services.AddScoped<SnapshotDiffTool>(); // Symbol-level diff of index snapshots or git refs
+            builder.Services.AddScoped<DetectRenamesTool>(); // Old → new symbol mappings over a commit range
+
+            // Index maintenance tools
+            builder.Services.AddScoped<PurgeIndexTool>(); // Remove workspace indexes and apply retention policy
+            builder.Services.AddScoped<WatchTool>(); // Standing queries with alerts on incremental index updates
+
+            // Diagnostics tools
+            builder.Services.AddScoped<GetLogsTool>(); // Recent log entries filtered by level/tool/correlation ID
+            builder.Services.AddScoped<SetLogLevelTool>(); // Change log level without restarting
+            builder.Services.AddScoped<CapabilitiesTool>(); // Supported languages, analyzers, transport features and limits
+            builder.Services.AddScoped<QueryHelpTool>(); // Query syntax with examples validated against the index
             
             // Register resource providers
             // builder.Services.AddSingleton<IResourceProvider, SearchResultResourceProvider>();
 
             // Discover and register all tools from assembly
             builder.DiscoverTools(typeof(Program).Assembly);
+            foreach (var plugin in pluginCatalog.Plugins.Where(p => p.Assembly != null).DistinctBy(p => p.AssemblyPath))
+            {
+                builder.DiscoverTools(plugin.Assembly!);
+            }
 
             // Configure behavioral adoption using Framework 2.1.1 features
             var templateVariables = new COA.Mcp.Framework.Services.TemplateVariables
             {
-                AvailableTools = new[] { "text_search", "symbol_search", "goto_definition", "find_references", "trace_call_path", "search_files", "line_search", "search_and_replace", "recent_files", "index_workspace", "edit_lines", "smart_refactor", "get_symbols_overview", "read_symbols", "find_patterns" },
+                AvailableTools = new[] { "text_search", "symbol_search", "goto_definition", "find_references", "trace_call_path", "search_files", "line_search", "search_and_replace", "smart_search", "recent_files", "index_workspace", "edit_lines", "stage_scratch", "scratch_diff", "search_scratch", "commit_scratch", "smart_refactor", "run_recipe", "get_symbols_overview", "read_symbols", "find_patterns", "find_similar_code", "review_context", "suggest_reviewers", "diff_summary", "snapshot_diff", "detect_renames", "purge_index", "get_logs", "set_log_level", "capabilities", "query_help" },
                 ToolPriorities = new Dictionary<string, int>
                 {
                     {"goto_definition", 100},
@@ -569,7 +794,7 @@ This approach is deeply satisfying - you've not just fixed a bug, you've built p
                 {
                     ["available_tools"] = templateVariables.AvailableTools,
                     ["tool_priorities"] = templateVariables.ToolPriorities,
-                    ["enforcement_level"] = templateVariables.EnforcementLevel.ToString().ToLower(),
+                    ["enforcement_level"] = templateVariables.EnforcementLevel.ToString().ToLowerInvariant(),
                     ["tool_comparisons"] = templateVariables.ToolComparisons.Values.ToList(),
                     ["has_tool"] = true
                 };
@@ -621,6 +846,12 @@ This approach is deeply satisfying - you've not just fixed a bug, you've built p
                 // Register startup indexing service
                 builder.Services.AddHostedService<StartupIndexingService>();
                 
+                // Optional read-only web dashboard (opt-in via CodeSearch:Dashboard, loopback by default)
+                builder.Services.AddHostedService<COA.CodeSearch.McpServer.Services.Dashboard.DashboardService>();
+                
+                // Registered last so it stops first: flushes indexes and the watcher queue before other services stop
+                builder.Services.AddHostedService<GracefulShutdownService>();
+                
                 // Configure resources using the new Framework 2.1.1 API
                 builder.ConfigureResources((registry, serviceProvider) =>
                 {
@@ -628,6 +859,23 @@ This approach is deeply satisfying - you've not just fixed a bug, you've built p
                     registry.RegisterProvider(resourceProvider);
                 });
                 
+                // Server-to-client requests share one channel - stdin is wrapped to catch their responses, so install before the transport reads
+                var clientRequests = new McpClientRequestChannel(pluginLoggerFactory.CreateLogger<McpClientRequestChannel>());
+                
+                // MCP sampling for text_search rerank
+                var samplingClient = new McpSamplingClient(configuration, clientRequests);
+                builder.Services.AddSingleton<ISamplingClient>(samplingClient);
+                builder.Services.AddSingleton<SearchReranker>();
+                
+                // MCP elicitation lets tools ask which symbol/workspace was meant when a name is ambiguous
+                var elicitationClient = new McpElicitationClient(configuration, clientRequests, pluginLoggerFactory.CreateLogger<McpElicitationClient>());
+                builder.Services.AddSingleton<IElicitationClient>(elicitationClient);
+                
+                if (samplingClient.Enabled || elicitationClient.Enabled)
+                {
+                    clientRequests.Install();
+                }
+                
                 // Use STDIO transport
                 builder.UseStdioTransport();
                 
@@ -640,6 +888,8 @@ This approach is deeply satisfying - you've not just fixed a bug, you've built p
                 // This ensures the same instance receives and processes events
                 await builder.RunAsync();
             }
+
+            return 0;
         }
         catch (Exception ex)
         {
@@ -652,4 +902,86 @@ This approach is deeply satisfying - you've not just fixed a bug, you've built p
         }
     }
 
+    /// <summary>
+    /// Runs <c>codesearch ci</c>: indexes the checkout, runs the checks from codesearch-ci.json,
+    /// writes SARIF/JSON reports and returns a <see cref="CiExitCodes"/> value.
+    /// Output goes to the console since there is no MCP client on STDIO in this mode.
+    /// </summary>
+    private static async Task<int> RunCiAsync(string[] args, IConfiguration baseConfiguration)
+    {
+        var options = CiOptions.Parse(args, out var error);
+        if (options == null)
+        {
+            Console.Error.WriteLine(error);
+            Console.Error.WriteLine(CiOptions.Usage);
+            return CiExitCodes.ConfigurationError;
+        }
+
+        CiConfiguration ciConfig;
+        try
+        {
+            ciConfig = CiRunner.LoadConfiguration(options.ConfigPath!);
+        }
+        catch (Exception ex) when (ex is System.Text.Json.JsonException or IOException)
+        {
+            Console.Error.WriteLine($"Invalid CI configuration {options.ConfigPath}: {ex.Message}");
+            return CiExitCodes.ConfigurationError;
+        }
+
+        // Index data lives under the checkout, like a normal session started in that directory
+        var configuration = new ConfigurationBuilder()
+            .AddConfiguration(baseConfiguration)
+            .AddInMemoryCollection(new Dictionary<string, string?>
+            {
+                ["CodeSearch:PrimaryWorkspace"] = options.WorkspacePath
+            })
+            .Build();
+
+        ConfigureSerilog(configuration, new[] { "ci" });
+
+        var services = new ServiceCollection();
+        services.AddLogging(logging => logging.AddSerilog());
+        ConfigureSharedServices(services, configuration);
+        services.AddScoped<FindPatternsTool>();
+        services.AddSingleton<CiRunner>();
+
+        try
+        {
+            await using var serviceProvider = services.BuildServiceProvider();
+            using var cancellation = new CancellationTokenSource();
+            Console.CancelKeyPress += (_, e) =>
+            {
+                e.Cancel = true;
+                cancellation.Cancel();
+            };
+
+            var runner = serviceProvider.GetRequiredService<CiRunner>();
+            var report = await runner.RunAsync(options, ciConfig, "2.1.8", cancellation.Token);
+
+            Console.WriteLine($"CodeSearch CI - {report.WorkspacePath} ({report.IndexedFiles} files, {report.Duration.TotalSeconds:F1}s)");
+            foreach (var check in report.Checks)
+            {
+                var threshold = check.Kind == "index" ? string.Empty : $" (max {check.Threshold})";
+                Console.WriteLine($"  [{(check.Passed ? "PASS" : "FAIL")}] {check.Kind} {check.Name}: {check.Count}{threshold}{(check.Detail != null ? " - " + check.Detail : string.Empty)}");
+            }
+            foreach (var finding in report.Findings.Where(f => f.Level == "error").Take(50))
+            {
+                Console.WriteLine($"  {finding.FilePath}:{finding.Line}: {finding.RuleId}: {finding.Message}");
+            }
+            Console.WriteLine(report.Passed ? "Passed" : $"Failed (exit code {report.ExitCode})");
+
+            return report.ExitCode;
+        }
+        catch (Exception ex)
+        {
+            Log.Fatal(ex, "CI run failed");
+            Console.Error.WriteLine($"CI run failed: {ex.Message}");
+            return CiExitCodes.IndexingFailed;
+        }
+        finally
+        {
+            Log.CloseAndFlush();
+        }
+    }
+
 }
\ No newline at end of file
@@ -375,7 +375,11 @@ public class FindReferencesResponseBuilder : BaseResponseBuilder<SearchResult, A
                 ContextLines = hit.ContextLines, // PRESERVE: Context for AI analysis
                 StartLine = hit.StartLine, // PRESERVE: Context bounds
                 EndLine = hit.EndLine, // PRESERVE: Context bounds
-                Snippet = hit.Snippet // PRESERVE: Original snippet for context
+                Column = hit.Column, // PRESERVE: Exact position (UTF-8 bytes)
+                Utf16Column = hit.Utf16Column, // PRESERVE: Exact position for LSP clients
+                ByteOffset = hit.ByteOffset,
+                Snippet = hit.Snippet, // PRESERVE: Original snippet for context
+                DependencyCode = hit.DependencyCode // PRESERVE: Vendored and third-party code is told apart
             };
         }).ToList();
     }
@@ -105,6 +105,12 @@ public class GoToDefinitionResponseBuilder : BaseResponseBuilder<SymbolDefinitio
             if (!string.IsNullOrEmpty(data.BaseType))
                 insights.Add($"Inherits from: {data.BaseType}");
             
+            if (data.PromotedVia?.Any() == true)
+                insights.Add($"Promoted from {data.ContainingType} through embedded {string.Join(" -> ", data.PromotedVia)}");
+
+            if (data.BuildConstraint != null)
+                insights.Add($"Build constraint: {data.BuildConstraint}" + (data.InBuild == false ? " - excluded by the workspace's Go build profile" : string.Empty));
+
             if (data.Interfaces?.Any() == true)
                 insights.Add($"Implements: {string.Join(", ", data.Interfaces)}");
             
@@ -250,6 +256,10 @@ public class GoToDefinitionResponseBuilder : BaseResponseBuilder<SymbolDefinitio
             FilePath = definition.FilePath,
             Line = definition.Line,
             Column = definition.Column,
+            Utf16Column = definition.Utf16Column,
+            ByteOffset = definition.ByteOffset,
+            Anchor = definition.Anchor,
+            AnchorStatus = definition.AnchorStatus,
             Language = definition.Language,
             Modifiers = definition.Modifiers,
             BaseType = definition.BaseType,
@@ -355,5 +355,7 @@ public class IndexStatistics
     public int DeletedDocumentCount { get; set; }
     public int SegmentCount { get; set; }
     public long IndexSizeBytes { get; set; }
+    public long StoredFieldsBytes { get; set; }
+    public string? StoredFieldCompression { get; set; }
     public Dictionary<string, int> FileTypeDistribution { get; set; } = new();
 }
\ No newline at end of file
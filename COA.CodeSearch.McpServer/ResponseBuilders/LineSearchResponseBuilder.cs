@@ -277,7 +277,9 @@ public class LineSearchResponseBuilder : BaseResponseBuilder<LineSearchResult, A
                 ContextLines = match.ContextLines?.Length > 0 ? match.ContextLines : null,
                 StartLine = match.StartLine,
                 EndLine = match.EndLine,
-                HighlightedFragments = ShouldIncludeHighlights(match) ? match.HighlightedFragments : null
+                HighlightedFragments = ShouldIncludeHighlights(match) ? match.HighlightedFragments : null,
+                Cell = match.Cell,
+                CellLine = match.CellLine
             }).ToList(),
             TotalMatches = file.TotalMatches,
             LastModified = file.LastModified,
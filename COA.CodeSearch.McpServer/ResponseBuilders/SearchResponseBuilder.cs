@@ -86,7 +86,9 @@ public class SearchResponseBuilder : BaseResponseBuilder<SearchResult, AIOptimiz
             TotalHits = data.TotalHits,
             Hits = reducedHits,
             SearchTime = data.SearchTime,
-            Query = data.Query
+            Query = data.Query,
+            Reranking = data.Reranking,
+            Sample = data.Sample
             // ProcessingTimeMs is a computed property, no need to set it
         };
         
@@ -195,6 +197,29 @@ public class SearchResponseBuilder : BaseResponseBuilder<SearchResult, AIOptimiz
             }
         }
         
+        // Re-ranking outcome - where the model's top pick sat in the raw order
+        if (data.Reranking != null)
+        {
+            if (data.Reranking.Applied && data.Reranking.RerankedOrder.Count > 0)
+            {
+                var top = data.Reranking.RerankedOrder[0];
+                insights.Add($"Re-ranked {data.Reranking.RawOrder.Count} candidates via client LLM{(data.Reranking.Model != null ? $" ({data.Reranking.Model})" : "")}: " +
+                             $"top result was #{top.RawRank} in raw order, {data.Reranking.Filtered.Count} filtered as irrelevant");
+            }
+            else if (data.Reranking.FallbackReason != null)
+            {
+                insights.Add($"Not re-ranked: {data.Reranking.FallbackReason}");
+            }
+        }
+
+        // Sample mode - the counts above cover only the sampled files
+        if (data.Sample != null)
+        {
+            var sample = data.Sample;
+            insights.Insert(0, $"📊 About {sample.EstimatedMatches} of {sample.TotalDocuments} files match ({sample.ConfidenceLevel:P0} interval {sample.LowerBound}-{sample.UpperBound}), " +
+                               $"extrapolated from {sample.MatchesInSample} matches in {sample.SampledDocuments} sampled files ({sample.SampleRate:P1})");
+        }
+
         // Search effectiveness
         if (data.TotalHits > data.Hits.Count * 10)
         {
@@ -339,7 +364,15 @@ public class SearchResponseBuilder : BaseResponseBuilder<SearchResult, AIOptimiz
                 ContextLines = hit.ContextLines, // PRESERVE: Context for AI analysis
                 StartLine = hit.StartLine, // PRESERVE: Context bounds
                 EndLine = hit.EndLine, // PRESERVE: Context bounds
-                Snippet = hit.Snippet // PRESERVE: Original snippet for context
+                Column = hit.Column, // PRESERVE: Exact position (UTF-8 bytes)
+                Utf16Column = hit.Utf16Column, // PRESERVE: Exact position for LSP clients
+                ByteOffset = hit.ByteOffset,
+                Snippet = hit.Snippet, // PRESERVE: Original snippet for context
+                Summary = hit.Summary, // PRESERVE: Lets the caller triage without opening the file
+                ClassifiedLines = hit.ClassifiedLines, // PRESERVE: Requested via snippetFormat=classified
+                DependencyCode = hit.DependencyCode, // PRESERVE: Vendored and third-party code is told apart
+                Cell = hit.Cell, // PRESERVE: Notebook cell of the match
+                CellLine = hit.CellLine
             };
         }).ToList();
     }
@@ -361,6 +394,20 @@ public class SearchResponseBuilder : BaseResponseBuilder<SearchResult, AIOptimiz
             tokens += TokenEstimator.EstimateString(hit.Snippet ?? "");
             tokens += 10; // Reduced metadata
         }
+
+        if (hit.ClassifiedLines != null)
+        {
+            // Each segment costs its text plus the kind tag and JSON punctuation
+            tokens += hit.ClassifiedLines.Sum(line => line.Sum(segment => TokenEstimator.EstimateString(segment.Text) + 4));
+        }
+
+        if (hit.Summary != null)
+        {
+            tokens += TokenEstimator.EstimateString(hit.Summary.Purpose ?? "");
+            tokens += hit.Summary.KeySymbols.Sum(TokenEstimator.EstimateString);
+            tokens += hit.Summary.Dependencies.Sum(TokenEstimator.EstimateString);
+            tokens += 10;
+        }
         
         return tokens;
     }
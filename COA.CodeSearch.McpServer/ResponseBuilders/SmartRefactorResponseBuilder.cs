@@ -71,6 +71,14 @@ public class SmartRefactorResponseBuilder : BaseResponseBuilder<SmartRefactorRes
             }
         }
 
+        // The consolidated diff goes in whole or not at all; the per-file changes still show what moved
+        var remainingBudget = dataBudget - reducedChanges.Sum(c => EstimateChangeTokens(c));
+        var diff = data.Diff != null && TokenEstimator.EstimateString(data.Diff) <= remainingBudget ? data.Diff : null;
+        if (data.Diff != null && diff == null)
+        {
+            data.Notes.Add("The diff was too large for this response - preview a narrower change or review git diff after applying");
+        }
+
         // Generate insights and actions
         var insights = GenerateInsights(data, "adaptive");
         var actions = GenerateActions(data, actionsBudget);
@@ -85,6 +93,9 @@ public class SmartRefactorResponseBuilder : BaseResponseBuilder<SmartRefactorRes
             ChangesCount = data.ChangesCount,
             Changes = reducedChanges,
             Errors = data.Errors,
+            Source = data.Source,
+            Notes = data.Notes,
+            Diff = diff,
             NextActions = data.NextActions,
             Duration = data.Duration
         };
@@ -104,6 +115,7 @@ public class SmartRefactorResponseBuilder : BaseResponseBuilder<SmartRefactorRes
                     ["filesModified"] = data.FilesModified.Count,
                     ["changesCount"] = data.ChangesCount,
                     ["errors"] = data.Errors.Count,
+                    ["source"] = data.Source,
                     ["durationMs"] = (int)data.Duration.TotalMilliseconds
                 }
             },
@@ -157,6 +169,8 @@ public class SmartRefactorResponseBuilder : BaseResponseBuilder<SmartRefactorRes
             insights.Add($"📝 Renamed symbol across {data.FilesModified.Count} files");
         }
 
+        insights.AddRange(data.Notes);
+
         if (data.Errors.Any())
         {
             insights.Add($"⚠️ {data.Errors.Count} errors occurred during operation");
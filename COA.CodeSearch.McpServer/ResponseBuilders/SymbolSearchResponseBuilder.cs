@@ -160,6 +160,10 @@ public class SymbolSearchResponseBuilder : BaseResponseBuilder<SymbolSearchResul
             var hasInterfaces = data.Symbols.Any(s => s.Interfaces?.Any() == true);
             if (hasInterfaces)
                 insights.Add("Some types implement interfaces");
+
+            var excluded = data.Symbols.Count(s => s.InBuild == false);
+            if (excluded > 0)
+                insights.Add($"{excluded} symbol(s) are in Go files the workspace's build profile excludes (inBuild: false) - buildProfileOnly drops them");
         }
 
         return insights;
@@ -0,0 +1,26 @@
+using Lucene.Net.Index;
+
+namespace COA.CodeSearch.McpServer.Scoring;
+
+/// <summary>
+/// Ranks vendored and third-party code below first-party code, for workspaces that index dependency code with
+/// the downrank mode. Documents are flagged at index time with origin=dependency.
+/// </summary>
+public class DependencyCodeFactor : IScoringFactor
+{
+    public string Name => "DependencyCode";
+    public float Weight { get; set; } = 1.0f;
+
+    public float CalculateScore(IndexReader reader, int docId, ScoringContext searchContext)
+    {
+        try
+        {
+            var doc = reader.Document(docId);
+            return doc.Get("origin") == "dependency" ? 0.05f : 1.0f;
+        }
+        catch (Exception)
+        {
+            return 1.0f; // First-party on error
+        }
+    }
+}
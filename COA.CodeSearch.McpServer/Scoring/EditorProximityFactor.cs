@@ -0,0 +1,59 @@
+using Lucene.Net.Index;
+
+namespace COA.CodeSearch.McpServer.Scoring;
+
+/// <summary>
+/// Ranks files close to the one open in the editor higher: the file itself, then its directory, then the
+/// directories around it. Closeness is the number of leading path segments shared with the open file.
+/// </summary>
+public class EditorProximityFactor : IScoringFactor
+{
+    private readonly string[] _openFile;
+
+    public string Name => "EditorProximity";
+    public float Weight { get; set; } = 0.3f;
+
+    /// <param name="openFile">Workspace-relative path of the open file</param>
+    public EditorProximityFactor(string openFile)
+    {
+        _openFile = Split(openFile);
+    }
+
+    public float CalculateScore(IndexReader reader, int docId, ScoringContext searchContext)
+    {
+        try
+        {
+            var doc = reader.Document(docId);
+            return Score(doc.Get("relativePath") ?? "");
+        }
+        catch (Exception)
+        {
+            return 0.5f; // Neutral on error
+        }
+    }
+
+    /// <summary>
+    /// 1.0 for the open file, 0.8 for its directory, falling by 0.2 per directory level up to 0.2
+    /// </summary>
+    public float Score(string relativePath)
+    {
+        var path = Split(relativePath);
+        if (path.Length == 0 || _openFile.Length == 0)
+            return 0.5f;
+
+        if (path.SequenceEqual(_openFile, StringComparer.OrdinalIgnoreCase))
+            return 1.0f;
+
+        var shared = 0;
+        while (shared < Math.Min(path.Length, _openFile.Length) - 1 &&
+               string.Equals(path[shared], _openFile[shared], StringComparison.OrdinalIgnoreCase))
+        {
+            shared++;
+        }
+
+        var levelsUp = _openFile.Length - 1 - shared;
+        return Math.Max(0.2f, 0.8f - 0.2f * levelsUp);
+    }
+
+    private static string[] Split(string path) => path.Split(new[] { '/', '\\' }, StringSplitOptions.RemoveEmptyEntries);
+}
@@ -32,6 +32,7 @@ public class FileTypeRelevanceFactor : IScoringFactor
             { ".go", 1.0f },
             { ".rs", 1.0f },
             { ".kt", 1.0f },
+            { ".scala", 1.0f },
             { ".swift", 1.0f },
             
             // Web files
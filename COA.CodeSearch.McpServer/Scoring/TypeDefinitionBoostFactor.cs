@@ -1,3 +1,4 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
 using Lucene.Net.Documents;
 using Lucene.Net.Index;
 using Microsoft.Extensions.Logging;
@@ -31,8 +32,8 @@ public class TypeDefinitionBoostFactor : IScoringFactor
             return 1.0f;
         }
         
-        var queryTerms = queryText.ToLower().Split(' ', StringSplitOptions.RemoveEmptyEntries);
-        var typeTerms = typeNames.ToLower().Split(' ', StringSplitOptions.RemoveEmptyEntries);
+        var queryTerms = UnicodeIdentifiers.Fold(queryText).Split(' ', StringSplitOptions.RemoveEmptyEntries);
+        var typeTerms = UnicodeIdentifiers.Fold(typeNames).Split(' ', StringSplitOptions.RemoveEmptyEntries);
         
         var matchCount = 0;
         foreach (var queryTerm in queryTerms)
@@ -57,7 +58,7 @@ public class TypeDefinitionBoostFactor : IScoringFactor
         {
             foreach (var typeDef in typeDefs)
             {
-                var defValue = typeDef.GetStringValue()?.ToLower();
+                var defValue = typeDef.GetStringValue() is { } value ? UnicodeIdentifiers.Fold(value) : null;
                 if (!string.IsNullOrEmpty(defValue))
                 {
                     foreach (var queryTerm in queryTerms)
@@ -0,0 +1,45 @@
+using Lucene.Net.Index;
+
+namespace COA.CodeSearch.McpServer.Scoring;
+
+/// <summary>
+/// Ranks files in the session's working set higher: files recently viewed or edited and their neighbors in
+/// the reference graph. Files outside the working set keep a low baseline rather than zero.
+/// </summary>
+public class WorkingSetFactor : IScoringFactor
+{
+    private const float Baseline = 0.3f;
+    private readonly IReadOnlyDictionary<string, float> _weights;
+
+    public string Name => "WorkingSet";
+    public float Weight { get; set; } = 0.4f;
+
+    /// <param name="weights">Working-set weight per absolute file path, 0 to 1</param>
+    public WorkingSetFactor(IReadOnlyDictionary<string, float> weights)
+    {
+        _weights = weights;
+    }
+
+    public float CalculateScore(IndexReader reader, int docId, ScoringContext searchContext)
+    {
+        try
+        {
+            var doc = reader.Document(docId);
+            return Score(doc.Get("path") ?? "");
+        }
+        catch (Exception)
+        {
+            return 0.5f; // Neutral on error
+        }
+    }
+
+    /// <summary>
+    /// The baseline for files outside the working set, rising to 1.0 with the file's weight
+    /// </summary>
+    public float Score(string path)
+    {
+        return _weights.TryGetValue(path, out var weight)
+            ? Baseline + (1.0f - Baseline) * Math.Clamp(weight, 0f, 1f)
+            : Baseline;
+    }
+}
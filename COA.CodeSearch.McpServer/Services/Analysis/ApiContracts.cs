@@ -0,0 +1,989 @@
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// An HTTP route a server handles, or an operation an OpenAPI spec declares
+/// </summary>
+public class ApiEndpoint
+{
+    /// <summary>
+    /// GET, POST, ... or * when the route accepts any method
+    /// </summary>
+    public string Method { get; set; } = "*";
+
+    /// <summary>
+    /// Route template with named parameters where the source names them: /users/{id}
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// aspnet, minimal-api, nestjs, express, gin/echo, chi/fiber, net/http, gorilla, flask, fastapi, django or openapi
+    /// </summary>
+    public string Framework { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Query parameters an OpenAPI operation declares; empty for code routes, whose query reads are not followed
+    /// </summary>
+    public List<string> QueryParameters { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A client request to a URL written in the code: fetch, axios, HttpClient, http.NewRequest, requests, or a
+/// generated client's path
+/// </summary>
+public class ApiCall
+{
+    /// <summary>
+    /// GET, POST, ... or * when the method could not be read
+    /// </summary>
+    public string Method { get; set; } = "*";
+
+    /// <summary>
+    /// Path with interpolated and concatenated values as {} segments: /users/{}/orders
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The URL starts with a base address held in a variable, so the path may lack the route's prefix
+    /// </summary>
+    public bool Relative { get; set; }
+
+    /// <summary>
+    /// Host of an absolute URL; calls to hosts other than localhost are left out of the comparison
+    /// </summary>
+    public string? Host { get; set; }
+
+    public List<string> QueryParameters { get; set; } = new();
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Code { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// A route, call or spec operation and where it is written
+/// </summary>
+public class ApiContractLocation
+{
+    public string Method { get; set; } = string.Empty;
+    public string Path { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A disagreement between server routes, client calls and OpenAPI specs
+/// </summary>
+public class ApiContractFinding
+{
+    /// <summary>
+    /// missing (call to no route), method-mismatch, parameter-drift, unimplemented (spec operation with no
+    /// route), undocumented (route missing from the spec) or uncalled (route no client calls)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Method { get; set; } = string.Empty;
+    public string Path { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Detail { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The routes, calls or operations the finding is measured against
+    /// </summary>
+    public List<ApiContractLocation> Related { get; set; } = new();
+}
+
+/// <summary>
+/// Reads server routes, client calls and OpenAPI operations from source text and cross-checks them. Routes come
+/// from ASP.NET attributes and minimal APIs, NestJS decorators, Express, Go net/http, gorilla, gin, echo, chi and
+/// fiber, Flask, FastAPI and Django; group prefixes are followed within a file. Paths are compared segment by
+/// segment with parameters matching anything, and a call whose URL starts from a base-address variable may match
+/// the tail of a longer route.
+/// </summary>
+public static class ApiContracts
+{
+    /// <summary>
+    /// Finding kinds in report order
+    /// </summary>
+    public static readonly string[] Kinds = { "missing", "method-mismatch", "parameter-drift", "unimplemented", "undocumented", "uncalled" };
+
+    private static readonly HashSet<string> CodeExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".go", ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".py"
+    };
+
+    private static readonly HashSet<string> SpecExtensions = new(StringComparer.OrdinalIgnoreCase) { ".json", ".yaml", ".yml" };
+
+    private static readonly string[] HttpMethods = { "get", "post", "put", "delete", "patch", "head", "options" };
+
+    // C# attribute routing and NestJS decorators share the class-prefix/method-template shape
+    private static readonly Regex CsRouteAttribute = new(
+        @"\b(?:Http(?<verb>Get|Post|Put|Delete|Patch|Head|Options)|(?<route>Route))\b(?:\s*\(\s*(?:Template\s*=\s*)?@?""(?<path>[^""]*)"")?", RegexOptions.Compiled);
+    private static readonly Regex NestDecorator = new(
+        @"@(?:(?<verb>Get|Post|Put|Delete|Patch|Head|Options|All)|(?<route>Controller))\s*\(\s*(?:['""`](?<path>[^'""`]*)['""`])?", RegexOptions.Compiled);
+    private static readonly Regex ClassDeclaration = new(@"\bclass\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex MethodDeclaration = new(@"(?<name>\w+)\s*(?:<[^<>()]*>)?\s*\(", RegexOptions.Compiled);
+
+    private static readonly Regex MinimalApi = new(
+        @"\b(?<recv>\w+)\.Map(?<verb>Get|Post|Put|Delete|Patch)\s*\(\s*@?""(?<path>[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex GoHandle = new(
+        @"\b(?<recv>\w+)\.(?:HandleFunc|Handle)\s*\(\s*""(?<path>[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex GorillaMethods = new(@"\.Methods\s*\((?<methods>[^)]*)\)", RegexOptions.Compiled);
+    private static readonly Regex GoUpperVerb = new(
+        @"\b(?<recv>\w+)\.(?<verb>GET|POST|PUT|DELETE|PATCH|HEAD|OPTIONS|Any)\s*\(\s*""(?<path>[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex GoTitleVerb = new(
+        @"\b(?<recv>\w+)\.(?<verb>Get|Post|Put|Delete|Patch|Head|Options|All)\s*\(\s*""(?<path>/[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex ExpressRoute = new(
+        @"\b(?<recv>app|router|server|\w*Router|\w*router|\w*App)\.(?<verb>get|post|put|delete|patch|head|options|all)\s*\(\s*['""`](?<path>/[^'""`]*)['""`]",
+        RegexOptions.Compiled);
+    private static readonly Regex FlaskRoute = new(
+        @"^\s*@(?<recv>\w+)\.route\s*\(\s*r?['""](?<path>[^'""]*)['""](?<rest>.*)", RegexOptions.Compiled);
+    private static readonly Regex PythonVerbRoute = new(
+        @"^\s*@(?<recv>\w+)\.(?<verb>get|post|put|delete|patch|head|options)\s*\(\s*r?['""](?<path>[^'""]*)['""]", RegexOptions.Compiled);
+    private static readonly Regex DjangoPath = new(@"\bpath\s*\(\s*r?['""](?<path>[^'""]*)['""]", RegexOptions.Compiled);
+    private static readonly Regex PythonMethods = new(@"methods\s*=\s*[\[(](?<methods>[^\])]*)", RegexOptions.Compiled);
+
+    // var := parent.Group("/api") and its equivalents; resolved within the file
+    private static readonly Regex GroupAssignment = new(
+        @"\b(?<var>\w+)\s*(?::=|=)\s*(?<parent>\w+)\.(?:Group|Route|PathPrefix|MapGroup)\s*\(\s*""(?<path>[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex PythonPrefix = new(
+        @"\b(?<var>\w+)\s*=\s*(?:APIRouter|Blueprint)\s*\((?<args>.*)", RegexOptions.Compiled);
+    private static readonly Regex PythonPrefixArgument = new(@"\b(?:url_)?prefix\s*=\s*['""](?<path>[^'""]*)['""]", RegexOptions.Compiled);
+    private static readonly Regex ExpressMount = new(
+        @"\b(?<parent>\w+)\.use\s*\(\s*['""`](?<path>/[^'""`]*)['""`]\s*,\s*(?<var>\w+)\s*\)", RegexOptions.Compiled);
+
+    // Client calls: the match ends where the URL argument starts
+    private static readonly Regex FetchCall = new(@"\bfetch\s*\(\s*", RegexOptions.Compiled);
+    private static readonly Regex JsClientCall = new(
+        @"\b(?:axios|http|api|\$http|\w*[Cc]lient|\w*Api|this\.http|this\.\w*[Cc]lient)\.(?<verb>get|post|put|delete|patch|head)\s*(?:<[^<>()]*>)?\s*\(\s*",
+        RegexOptions.Compiled);
+    private static readonly Regex GoClientCall = new(@"\b(?:http|\w*[Cc]lient)\.(?<verb>Get|Post|Head)\s*\(\s*", RegexOptions.Compiled);
+    private static readonly Regex GoNewRequest = new(
+        @"\b(?:http|httptest)\.NewRequest(?:WithContext)?\s*\(\s*(?:\w+\s*,\s*)?(?:""(?<verb>\w+)""|http\.Method(?<verb>\w+))\s*,\s*", RegexOptions.Compiled);
+    private static readonly Regex CsClientCall = new(
+        @"\.(?<verb>Get|Post|Put|Delete|Patch)(?:FromJson|AsJson|String|Stream|ByteArray)?Async\s*(?:<[^<>()]*>)?\s*\(\s*", RegexOptions.Compiled);
+    private static readonly Regex CsRequestMessage = new(
+        @"\bnew\s*(?:HttpRequestMessage)?\s*\(\s*HttpMethod\.(?<verb>\w+)\s*,\s*", RegexOptions.Compiled);
+    private static readonly Regex PythonClientCall = new(
+        @"\b(?:requests|httpx|session|client|\w+_client|\w*[Ss]ession)\.(?<verb>get|post|put|delete|patch|head)\s*\(\s*", RegexOptions.Compiled);
+    private static readonly Regex GeneratedClientPath = new(@"\blocalVarPath\s*:?=\s*", RegexOptions.Compiled);
+    private static readonly Regex MethodOption = new(
+        @"(?:\bmethod\s*:\s*['""`]|HTTPMethod\s*:?=\s*(?:http\.Method|['""])|\bHttpMethod\s*\(\s*"")(?<verb>[A-Za-z]+)", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    private static readonly Regex Scheme = new(@"^[a-zA-Z][\w+.-]*://(?<host>[^/?#]*)", RegexOptions.Compiled);
+    private static readonly Regex FormatVerb = new(@"%[-+# 0-9.]*[a-zA-Z]|\{\d+(?::[^}]*)?\}", RegexOptions.Compiled);
+    private static readonly Regex QueryKey = new(@"(?:^|&)(?<key>[\w.\-\[\]]+)=", RegexOptions.Compiled);
+    private static readonly Regex YamlKey = new(@"^(?<indent>\s*)(?<dash>-\s+)?['""]?(?<key>[^'"":#]+?)['""]?\s*:(?:\s+(?<value>.*?))?\s*$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Source files routes and calls are read from
+    /// </summary>
+    public static bool IsSourceFile(string filePath) =>
+        CodeExtensions.Contains(Path.GetExtension(filePath)) && !filePath.EndsWith(".d.ts", StringComparison.OrdinalIgnoreCase)
+        && !filePath.EndsWith(".min.js", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Files that may hold an OpenAPI or Swagger document
+    /// </summary>
+    public static bool IsSpecCandidate(string filePath, string content) =>
+        SpecExtensions.Contains(Path.GetExtension(filePath))
+        && Regex.IsMatch(content, @"^\s*[""']?(?:openapi|swagger)[""']?\s*:", RegexOptions.Multiline)
+        && content.Contains("paths", StringComparison.Ordinal);
+
+    /// <summary>
+    /// Routes the file's server code declares
+    /// </summary>
+    public static List<ApiEndpoint> ScanServer(string filePath, IReadOnlyList<string> lines)
+    {
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+        var prefixes = Prefixes(lines, masked);
+        var endpoints = new List<ApiEndpoint>();
+
+        void Add(string method, string path, string framework, int line) => endpoints.Add(new ApiEndpoint
+        {
+            Method = method.ToUpperInvariant(),
+            Path = NormalizePath(path),
+            Framework = framework,
+            FilePath = filePath,
+            Line = line + 1
+        });
+
+        switch (extension)
+        {
+            case ".cs":
+                ScanAttributeRoutes(lines, masked, CsRouteAttribute, "aspnet", Add);
+                for (var i = 0; i < lines.Count; i++)
+                {
+                    foreach (var m in InCode(MinimalApi, lines[i], masked[i]))
+                        Add(m.Groups["verb"].Value, Join(Prefix(prefixes, m.Groups["recv"].Value), m.Groups["path"].Value), "minimal-api", i);
+                }
+                break;
+
+            case ".go":
+                for (var i = 0; i < lines.Count; i++)
+                {
+                    foreach (var m in InCode(GoHandle, lines[i], masked[i]))
+                    {
+                        var pattern = m.Groups["path"].Value.Trim();
+                        var space = pattern.IndexOf(' ');
+                        var gorilla = GorillaMethods.Match(lines[i]);
+                        var prefix = Prefix(prefixes, m.Groups["recv"].Value);
+                        if (space > 0)
+                            Add(pattern[..space], Join(prefix, pattern[(space + 1)..].Trim()), "net/http", i);
+                        else if (gorilla.Success)
+                        {
+                            foreach (var method in Regex.Matches(gorilla.Groups["methods"].Value, @"""(\w+)""|http\.Method(\w+)").Select(v => v.Groups[1].Success ? v.Groups[1].Value : v.Groups[2].Value))
+                                Add(method, Join(prefix, pattern), "gorilla", i);
+                        }
+                        else
+                            Add("*", Join(prefix, pattern), "net/http", i);
+                    }
+                    foreach (var m in InCode(GoUpperVerb, lines[i], masked[i]))
+                        Add(m.Groups["verb"].Value == "Any" ? "*" : m.Groups["verb"].Value, Join(Prefix(prefixes, m.Groups["recv"].Value), m.Groups["path"].Value), "gin/echo", i);
+                    foreach (var m in InCode(GoTitleVerb, lines[i], masked[i]).Where(m => m.Groups["recv"].Value != "http"))
+                        Add(m.Groups["verb"].Value == "All" ? "*" : m.Groups["verb"].Value, Join(Prefix(prefixes, m.Groups["recv"].Value), m.Groups["path"].Value), "chi/fiber", i);
+                }
+                break;
+
+            case ".js" or ".jsx" or ".ts" or ".tsx" or ".mjs" or ".cjs":
+                ScanAttributeRoutes(lines, masked, NestDecorator, "nestjs", Add);
+                for (var i = 0; i < lines.Count; i++)
+                {
+                    foreach (var m in InCode(ExpressRoute, lines[i], masked[i]))
+                        Add(m.Groups["verb"].Value == "all" ? "*" : m.Groups["verb"].Value, Join(Prefix(prefixes, m.Groups["recv"].Value), m.Groups["path"].Value), "express", i);
+                }
+                break;
+
+            case ".py":
+                var django = Path.GetFileName(filePath).Equals("urls.py", StringComparison.OrdinalIgnoreCase);
+                for (var i = 0; i < lines.Count; i++)
+                {
+                    if (FlaskRoute.Match(lines[i]) is { Success: true } flask)
+                    {
+                        var path = Join(Prefix(prefixes, flask.Groups["recv"].Value), flask.Groups["path"].Value);
+                        var methods = PythonMethods.Match(flask.Groups["rest"].Value);
+                        var verbs = methods.Success
+                            ? Regex.Matches(methods.Groups["methods"].Value, @"['""](\w+)['""]").Select(v => v.Groups[1].Value).ToList()
+                            : new List<string> { "GET" };
+                        foreach (var verb in verbs)
+                            Add(verb, path, "flask", i);
+                    }
+                    else if (PythonVerbRoute.Match(lines[i]) is { Success: true } route)
+                    {
+                        Add(route.Groups["verb"].Value, Join(Prefix(prefixes, route.Groups["recv"].Value), route.Groups["path"].Value), "fastapi", i);
+                    }
+                    else if (django)
+                    {
+                        foreach (var m in InCode(DjangoPath, lines[i], masked[i]))
+                            Add("*", "/" + m.Groups["path"].Value, "django", i);
+                    }
+                }
+                break;
+        }
+
+        return endpoints;
+    }
+
+    /// <summary>
+    /// Requests the file's client code makes to URLs written in it
+    /// </summary>
+    public static List<ApiCall> ScanClient(string filePath, IReadOnlyList<string> lines)
+    {
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+        var calls = new List<ApiCall>();
+        var patterns = extension switch
+        {
+            ".cs" => new[] { CsClientCall, CsRequestMessage },
+            ".go" => new[] { GoClientCall, GoNewRequest, GeneratedClientPath },
+            ".py" => new[] { PythonClientCall },
+            _ => new[] { FetchCall, JsClientCall, GeneratedClientPath }
+        };
+
+        for (var i = 0; i < lines.Count; i++)
+        {
+            foreach (var pattern in patterns)
+            {
+                foreach (var m in InCode(pattern, lines[i], masked[i]))
+                {
+                    var url = ReadUrl(lines[i][(m.Index + m.Length)..]);
+                    if (url == null)
+                        continue;
+
+                    var method = m.Groups["verb"].Success ? m.Groups["verb"].Value
+                        : pattern == FetchCall ? MethodInCall(lines, i, m.Index + m.Length)
+                        : GeneratedClientMethod(lines, i, extension == ".go" ? -1 : 1);
+                    url.Method = method?.ToUpperInvariant() ?? (pattern == FetchCall ? "GET" : "*");
+                    url.FilePath = filePath;
+                    url.Line = i + 1;
+                    url.Code = lines[i].Trim();
+                    calls.Add(url);
+                }
+            }
+        }
+
+        return calls;
+    }
+
+    /// <summary>
+    /// Operations an OpenAPI 3 or Swagger 2 document declares, in JSON or YAML, with the base path of its first
+    /// server (or basePath) applied
+    /// </summary>
+    public static List<ApiEndpoint> ParseOpenApi(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        try
+        {
+            return Path.GetExtension(filePath).Equals(".json", StringComparison.OrdinalIgnoreCase)
+                ? ParseOpenApiJson(filePath, content, lines)
+                : ParseOpenApiYaml(filePath, lines);
+        }
+        catch (JsonException)
+        {
+            return new List<ApiEndpoint>();
+        }
+    }
+
+    /// <summary>
+    /// Cross-checks routes, spec operations and calls. Calls are checked against the routes when the workspace
+    /// has server code, else against the spec; uncalled routes are only reported when there are calls at all,
+    /// and spec findings only when there is both a spec and server code.
+    /// </summary>
+    public static List<ApiContractFinding> Compare(IReadOnlyList<ApiEndpoint> routes, IReadOnlyList<ApiEndpoint> spec, IReadOnlyList<ApiCall> calls)
+    {
+        var findings = new List<ApiContractFinding>();
+        var called = new HashSet<ApiEndpoint>();
+        var targets = routes.Count > 0 ? routes : spec;
+        var internalCalls = calls.Where(c => !IsExternal(c)).ToList();
+
+        foreach (var call in internalCalls)
+        {
+            if (targets.Count == 0)
+                break;
+
+            var byPath = Matching(targets, call.Path, call.Relative);
+            if (byPath.Count == 0)
+            {
+                findings.Add(Finding("missing", call.Method, call.Path, call.FilePath, call.Line,
+                    routes.Count > 0 ? "No route matches this URL" : "No operation in the spec matches this URL",
+                    Nearest(targets, call.Path)));
+                continue;
+            }
+
+            var byMethod = byPath.Where(e => MethodsAgree(e.Method, call.Method)).ToList();
+            if (byMethod.Count == 0)
+            {
+                findings.Add(Finding("method-mismatch", call.Method, call.Path, call.FilePath, call.Line,
+                    $"The route accepts {string.Join(", ", byPath.Select(e => e.Method).Distinct())}, not {call.Method}", byPath));
+                continue;
+            }
+            called.UnionWith(byMethod);
+
+            var operations = routes.Count > 0 ? Matching(spec, call.Path, call.Relative).Where(e => MethodsAgree(e.Method, call.Method)).ToList() : byMethod;
+            var declared = operations.SelectMany(o => o.QueryParameters).ToHashSet(StringComparer.Ordinal);
+            var undeclared = call.QueryParameters.Where(q => !declared.Contains(q)).ToList();
+            if (operations.Count > 0 && undeclared.Count > 0)
+            {
+                findings.Add(Finding("parameter-drift", call.Method, call.Path, call.FilePath, call.Line,
+                    $"Sends query parameter(s) {string.Join(", ", undeclared)} the spec does not declare" +
+                    (declared.Count > 0 ? $" (declared: {string.Join(", ", declared)})" : string.Empty), operations));
+            }
+        }
+
+        if (spec.Count > 0 && routes.Count > 0)
+        {
+            foreach (var operation in spec)
+            {
+                var implementing = Implementing(routes, operation);
+                if (implementing.Count == 0)
+                {
+                    findings.Add(Finding("unimplemented", operation.Method, operation.Path, operation.FilePath, operation.Line,
+                        "The spec declares this operation but no route implements it", Nearest(routes, operation.Path)));
+                    continue;
+                }
+
+                foreach (var route in implementing)
+                {
+                    var drift = ParameterNames(operation.Path).Zip(ParameterNames(route.Path))
+                        .Where(p => p.First.Length > 0 && p.Second.Length > 0 && !p.First.Equals(p.Second, StringComparison.OrdinalIgnoreCase))
+                        .Select(p => $"{{{p.First}}} in the spec is {{{p.Second}}} in the route")
+                        .ToList();
+                    if (drift.Count > 0)
+                    {
+                        findings.Add(Finding("parameter-drift", route.Method, route.Path, route.FilePath, route.Line,
+                            string.Join("; ", drift), new[] { operation }));
+                    }
+                }
+            }
+
+            foreach (var route in routes)
+            {
+                if (!Matching(spec, route.Path, relative: false).Any(o => MethodsAgree(o.Method, route.Method)))
+                {
+                    findings.Add(Finding("undocumented", route.Method, route.Path, route.FilePath, route.Line,
+                        "The route is not in the OpenAPI spec", Nearest(spec, route.Path)));
+                }
+            }
+        }
+
+        if (internalCalls.Count > 0)
+        {
+            foreach (var route in routes.Where(r => !called.Contains(r)))
+            {
+                findings.Add(Finding("uncalled", route.Method, route.Path, route.FilePath, route.Line,
+                    "No client call in the workspace reaches this route", Array.Empty<ApiEndpoint>()));
+            }
+        }
+
+        return findings
+            .OrderBy(f => Array.IndexOf(Kinds, f.Kind))
+            .ThenBy(f => f.FilePath, StringComparer.Ordinal)
+            .ThenBy(f => f.Line)
+            .ToList();
+    }
+
+    /// <summary>
+    /// The routes serving a spec operation: same method (or any), and the same path or one a prefix declared
+    /// elsewhere leaves out
+    /// </summary>
+    public static List<ApiEndpoint> Implementing(IReadOnlyList<ApiEndpoint> routes, ApiEndpoint operation) =>
+        Matching(routes, operation.Path, relative: false).Where(r => MethodsAgree(r.Method, operation.Method)).ToList();
+
+    /// <summary>
+    /// A route or URL path in one form: leading slash, no trailing slash, parameters as {name} or {} when unnamed
+    /// </summary>
+    public static string NormalizePath(string path)
+    {
+        var segments = path.Split('?', '#')[0].Split('/', StringSplitOptions.RemoveEmptyEntries)
+            .Select(s => Parameter(s) is { } name ? $"{{{name}}}" : s);
+        return "/" + string.Join("/", segments);
+    }
+
+    /// <summary>
+    /// Client calls to a host other than the local machine reach someone else's API
+    /// </summary>
+    public static bool IsExternal(ApiCall call) =>
+        call.Host != null && !Regex.IsMatch(call.Host, @"^(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1\])(?::\d+)?$", RegexOptions.IgnoreCase);
+
+    private static void ScanAttributeRoutes(IReadOnlyList<string> lines, string[] masked, Regex attribute, string framework,
+        Action<string, string, string, int> add)
+    {
+        string? classRoute = null;
+        string? className = null;
+        string? pendingRoute = null;
+        var pendingVerbs = new List<(string Verb, string? Path)>();
+
+        for (var i = 0; i < lines.Count; i++)
+        {
+            var trimmed = masked[i].TrimStart();
+            var attributes = trimmed.StartsWith('[') || trimmed.StartsWith('@') ? InCode(attribute, lines[i], masked[i]).ToList() : new List<Match>();
+            foreach (var m in attributes)
+            {
+                var path = m.Groups["path"].Success ? m.Groups["path"].Value : null;
+                if (m.Groups["route"].Success)
+                    pendingRoute = path ?? string.Empty;
+                else
+                    pendingVerbs.Add((m.Groups["verb"].Value == "All" ? "*" : m.Groups["verb"].Value, path));
+            }
+
+            // The declaration may follow the attribute on the same line
+            var code = attributes.Count > 0 ? masked[i][(masked[i].LastIndexOfAny(new[] { ']', ')' }) + 1)..] : masked[i];
+            if (ClassDeclaration.Match(code) is { Success: true } classMatch)
+            {
+                className = classMatch.Groups["name"].Value;
+                classRoute = pendingRoute;
+                pendingRoute = null;
+                pendingVerbs.Clear();
+                continue;
+            }
+
+            if (pendingVerbs.Count == 0 && (pendingRoute == null || className == null))
+                continue;
+            var declaration = MethodDeclaration.Matches(code).FirstOrDefault(d => !IsKeyword(d.Groups["name"].Value));
+            if (declaration == null)
+                continue;
+
+            var verbs = pendingVerbs.Count > 0 ? pendingVerbs : new List<(string Verb, string? Path)> { ("*", null) };
+            foreach (var (verb, template) in verbs)
+            {
+                var methodPath = template ?? pendingRoute;
+                if (methodPath == null && classRoute == null)
+                    continue;
+
+                var path = methodPath != null && (methodPath.StartsWith('/') || methodPath.StartsWith("~/"))
+                    ? methodPath.TrimStart('~')
+                    : Join(classRoute ?? string.Empty, methodPath ?? string.Empty);
+                var controller = className != null && className.EndsWith("Controller", StringComparison.Ordinal) && className.Length > 10
+                    ? className[..^10]
+                    : className ?? string.Empty;
+                path = path.Replace("[controller]", controller, StringComparison.OrdinalIgnoreCase)
+                    .Replace("[action]", declaration.Groups["name"].Value, StringComparison.OrdinalIgnoreCase);
+                add(verb, path, framework, i);
+            }
+            pendingVerbs.Clear();
+            pendingRoute = null;
+        }
+    }
+
+    private static bool IsKeyword(string word) => word is "if" or "for" or "foreach" or "while" or "switch" or "catch" or "using"
+        or "lock" or "return" or "new" or "typeof" or "nameof" or "await" or "function" or "constructor";
+
+    /// <summary>
+    /// Route prefixes of group variables, resolved through their parents
+    /// </summary>
+    private static Dictionary<string, string> Prefixes(IReadOnlyList<string> lines, string[] masked)
+    {
+        var declared = new Dictionary<string, (string Parent, string Path)>(StringComparer.Ordinal);
+        for (var i = 0; i < lines.Count; i++)
+        {
+            foreach (var m in InCode(GroupAssignment, lines[i], masked[i]))
+                declared[m.Groups["var"].Value] = (m.Groups["parent"].Value, m.Groups["path"].Value);
+            foreach (var m in InCode(ExpressMount, lines[i], masked[i]))
+                declared[m.Groups["var"].Value] = (m.Groups["parent"].Value, m.Groups["path"].Value);
+            if (PythonPrefix.Match(lines[i]) is { Success: true } python && PythonPrefixArgument.Match(python.Groups["args"].Value) is { Success: true } argument)
+                declared[python.Groups["var"].Value] = (string.Empty, argument.Groups["path"].Value);
+        }
+
+        string Resolve(string name, int depth) =>
+            depth < 10 && declared.TryGetValue(name, out var group) ? Join(Resolve(group.Parent, depth + 1), group.Path) : string.Empty;
+
+        return declared.Keys.ToDictionary(k => k, k => Resolve(k, 0), StringComparer.Ordinal);
+    }
+
+    private static string Prefix(Dictionary<string, string> prefixes, string receiver) =>
+        prefixes.TryGetValue(receiver, out var prefix) ? prefix : string.Empty;
+
+    private static string Join(string prefix, string path) =>
+        "/" + string.Join("/", new[] { prefix, path }.Select(p => p.Trim('/')).Where(p => p.Length > 0));
+
+    // Matches starting in code, not inside a string or comment
+    private static IEnumerable<Match> InCode(Regex pattern, string line, string masked) =>
+        pattern.Matches(line).Where(m => m.Index < masked.Length && masked[m.Index] != ' ');
+
+    /// <summary>
+    /// The URL expression at the start of <paramref name="text"/>: string pieces joined by +, interpolation and
+    /// format verbs as {} segments, a base-address variable in front making it relative
+    /// </summary>
+    private static ApiCall? ReadUrl(string text)
+    {
+        var url = new StringBuilder();
+        var leadingExpression = false;
+        var sawLiteral = false;
+        var i = 0;
+
+        // fmt.Sprintf("/users/%d", id) and string.Format("...{0}", x) format their first argument
+        var format = Regex.Match(text, @"^(?:fmt\.Sprintf|[Ss]tring\.[Ff]ormat)\s*\(\s*");
+        if (format.Success)
+            i = format.Length;
+
+        while (i < text.Length)
+        {
+            var c = text[i];
+            if (char.IsWhiteSpace(c) || c == '+')
+            {
+                i++;
+                continue;
+            }
+
+            var prefixLength = 0;
+            while (i + prefixLength < text.Length && text[i + prefixLength] is '$' or '@' or 'f' or 'F' or 'r' or 'R' or 'b' or 'u' && prefixLength < 3)
+                prefixLength++;
+            var quoteAt = i + prefixLength;
+            if (quoteAt < text.Length && text[quoteAt] is '"' or '\'' or '`')
+            {
+                var quote = text[quoteAt];
+                var interpolated = quote == '`' || text[i..quoteAt].IndexOfAny(new[] { '$', 'f', 'F' }) >= 0;
+                var end = quoteAt + 1;
+                var literal = new StringBuilder();
+                while (end < text.Length && text[end] != quote)
+                {
+                    if (text[end] == '\\' && end + 1 < text.Length)
+                    {
+                        literal.Append(text[end + 1]);
+                        end += 2;
+                        continue;
+                    }
+                    if (interpolated && (text[end] == '{' || text[end] == '$' && end + 1 < text.Length && text[end + 1] == '{'))
+                    {
+                        var close = text.IndexOf('}', end);
+                        if (close < 0)
+                            break;
+                        literal.Append("{}");
+                        end = close + 1;
+                        continue;
+                    }
+                    literal.Append(text[end]);
+                    end++;
+                }
+                url.Append(FormatVerb.Replace(literal.ToString(), "{}"));
+                sawLiteral = true;
+                i = end + 1;
+                continue;
+            }
+
+            // A literal followed by .replace(...) or a method call ends the URL
+            if (c is ',' or ')' or ';' or '.' or '}' or ']' || sawLiteral && !char.IsLetterOrDigit(c) && c != '_')
+                break;
+
+            var start = i;
+            var depth = 0;
+            while (i < text.Length && (depth > 0 || text[i] is not (',' or ')' or ';' or '+' or ' ' or '}')))
+            {
+                if (text[i] is '(' or '[') depth++;
+                else if (text[i] is ')' or ']') depth--;
+                i++;
+            }
+            if (i == start)
+                break;
+            if (url.Length == 0)
+                leadingExpression = true;
+            else
+                url.Append("{}");
+        }
+
+        if (!sawLiteral)
+            return null;
+
+        var raw = url.ToString().Trim();
+        string? host = null;
+        if (Scheme.Match(raw) is { Success: true } scheme)
+        {
+            // A host from a variable is the service's own configured address, not a known outside API
+            host = scheme.Groups["host"].Value.Contains('{') ? null : scheme.Groups["host"].Value;
+            raw = raw[scheme.Length..];
+            leadingExpression = host == null;
+        }
+        while (raw.StartsWith("{}", StringComparison.Ordinal))
+        {
+            raw = raw[2..];
+            leadingExpression = true;
+        }
+        if (raw.Length == 0 || raw.Any(char.IsWhiteSpace) || !raw.Contains('/') && host == null)
+            return null;
+        if (!raw.StartsWith('/'))
+        {
+            if (host == null && !leadingExpression && !Regex.IsMatch(raw, @"^[\w.-]+/"))
+                return null;
+            raw = "/" + raw;
+            leadingExpression = true;
+        }
+
+        var queryAt = raw.IndexOf('?');
+        var query = queryAt >= 0 ? raw[(queryAt + 1)..].Split('#')[0] : string.Empty;
+        var path = NormalizePath(raw);
+        if (!path.Split('/').Any(s => s.Length > 0 && Parameter(s) == null))
+            return null;
+
+        return new ApiCall
+        {
+            Path = path,
+            Relative = leadingExpression,
+            Host = host,
+            QueryParameters = QueryKey.Matches(query).Select(m => m.Groups["key"].Value).Distinct().ToList()
+        };
+    }
+
+    // fetch(url, { method: 'POST' }) names the method in its options, before the call closes
+    private static string? MethodInCall(IReadOnlyList<string> lines, int index, int column)
+    {
+        var depth = 1;
+        for (var i = index; i < Math.Min(lines.Count, index + 10) && depth > 0; i++)
+        {
+            var text = i == index ? lines[i][column..] : lines[i];
+            var end = text.Length;
+            for (var c = 0; c < text.Length; c++)
+            {
+                if (text[c] == '(') depth++;
+                else if (text[c] == ')' && --depth == 0)
+                {
+                    end = c;
+                    break;
+                }
+            }
+            if (MethodOption.Match(text[..end]) is { Success: true } m && HttpMethods.Contains(m.Groups["verb"].Value.ToLowerInvariant()))
+                return m.Groups["verb"].Value;
+        }
+        return null;
+    }
+
+    // Generated clients set the method in a statement of its own: before the path in Go, after it in TypeScript
+    private static string? GeneratedClientMethod(IReadOnlyList<string> lines, int index, int step)
+    {
+        for (var i = index + step; i >= 0 && i < lines.Count && Math.Abs(i - index) <= 15; i += step)
+        {
+            if (GeneratedClientPath.IsMatch(lines[i]))
+                break;
+            if (MethodOption.Match(lines[i]) is { Success: true } m && HttpMethods.Contains(m.Groups["verb"].Value.ToLowerInvariant()))
+                return m.Groups["verb"].Value;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// The parameter name of a route segment, "" for an unnamed one, or null for a literal segment
+    /// </summary>
+    private static string? Parameter(string segment)
+    {
+        if (segment.Contains("{}", StringComparison.Ordinal) || segment.Contains("${", StringComparison.Ordinal))
+            return string.Empty;
+        var match = Regex.Match(segment, @"^(?:\{\*{0,2}(?<name>\w+)(?:[:?][^}]*)?\??\}|:(?<name>\w+)\??|<(?:\w+:)?(?<name>\w+)>|\*(?<name>\w*)|\[(?:\.\.\.)?(?<name>\w+)\])$");
+        return match.Success ? match.Groups["name"].Value : null;
+    }
+
+    private static IEnumerable<string> ParameterNames(string path) =>
+        path.Split('/', StringSplitOptions.RemoveEmptyEntries).Select(s => Parameter(s)).OfType<string>();
+
+    private static List<ApiEndpoint> Matching(IReadOnlyList<ApiEndpoint> endpoints, string path, bool relative)
+    {
+        var segments = path.Split('/', StringSplitOptions.RemoveEmptyEntries);
+        var exact = endpoints.Where(e => SegmentsMatch(e.Path.Split('/', StringSplitOptions.RemoveEmptyEntries), segments, loose: false)).ToList();
+        if (exact.Count > 0)
+            return exact;
+
+        // A base-address variable or a router mounted in another file leaves a prefix out of one side
+        return endpoints.Where(e => SegmentsMatch(e.Path.Split('/', StringSplitOptions.RemoveEmptyEntries), segments, loose: true)
+            && (relative || e.Path.Split('/', StringSplitOptions.RemoveEmptyEntries).Length < segments.Length)).ToList();
+    }
+
+    private static bool SegmentsMatch(string[] route, string[] call, bool loose)
+    {
+        if (route.Length != call.Length && (!loose || route.Length == 0 || call.Length == 0))
+            return false;
+
+        var (shorter, longer) = route.Length <= call.Length ? (route, call) : (call, route);
+        var offset = longer.Length - shorter.Length;
+        if (offset > 0 && (longer.Take(offset).Any(s => Parameter(s) != null) || shorter.All(s => Parameter(s) != null)))
+            return false;
+
+        for (var i = 0; i < shorter.Length; i++)
+        {
+            var a = shorter[i];
+            var b = longer[i + offset];
+            if (Parameter(a) == null && Parameter(b) == null && !a.Equals(b, StringComparison.OrdinalIgnoreCase))
+                return false;
+        }
+        return true;
+    }
+
+    private static bool MethodsAgree(string a, string b) =>
+        a == "*" || b == "*" || a.Equals(b, StringComparison.OrdinalIgnoreCase) || a == "GET" && b == "HEAD" || a == "HEAD" && b == "GET";
+
+    // Endpoints sharing the most leading segments, so a typo'd or renamed URL shows what it was meant to reach
+    private static List<ApiEndpoint> Nearest(IReadOnlyList<ApiEndpoint> endpoints, string path)
+    {
+        var segments = path.Split('/', StringSplitOptions.RemoveEmptyEntries);
+        int Shared(ApiEndpoint e) => e.Path.Split('/', StringSplitOptions.RemoveEmptyEntries).Zip(segments)
+            .TakeWhile(p => p.First.Equals(p.Second, StringComparison.OrdinalIgnoreCase) || Parameter(p.First) != null || Parameter(p.Second) != null)
+            .Count();
+
+        return endpoints.Select(e => (Endpoint: e, Shared: Shared(e))).Where(e => e.Shared > 0)
+            .OrderByDescending(e => e.Shared).ThenBy(e => e.Endpoint.Path, StringComparer.Ordinal)
+            .Take(3).Select(e => e.Endpoint).ToList();
+    }
+
+    private static ApiContractFinding Finding(string kind, string method, string path, string filePath, int line, string detail,
+        IEnumerable<ApiEndpoint> related) => new()
+    {
+        Kind = kind,
+        Method = method,
+        Path = path,
+        FilePath = filePath,
+        Line = line,
+        Detail = detail,
+        Related = related.Select(e => new ApiContractLocation { Method = e.Method, Path = e.Path, FilePath = e.FilePath, Line = e.Line }).ToList()
+    };
+
+    private static List<ApiEndpoint> ParseOpenApiJson(string filePath, string content, string[] lines)
+    {
+        var operations = new List<ApiEndpoint>();
+        using var document = JsonDocument.Parse(content, new JsonDocumentOptions { CommentHandling = JsonCommentHandling.Skip, AllowTrailingCommas = true });
+        var root = document.RootElement;
+        if (root.ValueKind != JsonValueKind.Object || !root.TryGetProperty("paths", out var paths) || paths.ValueKind != JsonValueKind.Object)
+            return operations;
+
+        var basePath = root.TryGetProperty("basePath", out var swaggerBase) && swaggerBase.ValueKind == JsonValueKind.String
+            ? swaggerBase.GetString() ?? string.Empty
+            : root.TryGetProperty("servers", out var servers) && servers.ValueKind == JsonValueKind.Array && servers.GetArrayLength() > 0
+                && servers[0].TryGetProperty("url", out var serverUrl) && serverUrl.ValueKind == JsonValueKind.String
+                ? ServerPath(serverUrl.GetString() ?? string.Empty)
+                : string.Empty;
+
+        foreach (var pathItem in paths.EnumerateObject().Where(p => p.Value.ValueKind == JsonValueKind.Object))
+        {
+            var line = Array.FindIndex(lines, l => l.Contains($"\"{pathItem.Name}\"", StringComparison.Ordinal)) + 1;
+            var shared = QueryParametersOf(pathItem.Value);
+            foreach (var operation in pathItem.Value.EnumerateObject().Where(o => HttpMethods.Contains(o.Name) && o.Value.ValueKind == JsonValueKind.Object))
+            {
+                operations.Add(new ApiEndpoint
+                {
+                    Method = operation.Name.ToUpperInvariant(),
+                    Path = NormalizePath(Join(basePath, pathItem.Name)),
+                    Framework = "openapi",
+                    QueryParameters = shared.Concat(QueryParametersOf(operation.Value)).Distinct().ToList(),
+                    FilePath = filePath,
+                    Line = line
+                });
+            }
+        }
+        return operations;
+    }
+
+    private static IEnumerable<string> QueryParametersOf(JsonElement element) =>
+        element.TryGetProperty("parameters", out var parameters) && parameters.ValueKind == JsonValueKind.Array
+            ? parameters.EnumerateArray()
+                .Where(p => p.ValueKind == JsonValueKind.Object && p.TryGetProperty("in", out var location) && location.GetString() == "query"
+                    && p.TryGetProperty("name", out _))
+                .Select(p => p.GetProperty("name").GetString() ?? string.Empty)
+                .Where(n => n.Length > 0)
+                .ToList()
+            : Enumerable.Empty<string>();
+
+    /// <summary>
+    /// The paths section of a YAML spec, read by indentation: path keys, method keys under them, and the name and
+    /// location of parameter list items at either level. $ref parameters are not followed.
+    /// </summary>
+    private static List<ApiEndpoint> ParseOpenApiYaml(string filePath, string[] lines)
+    {
+        var operations = new List<ApiEndpoint>();
+        var basePath = string.Empty;
+        var pathsAt = -1;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (Regex.Match(lines[i], @"^basePath\s*:\s*['""]?(?<path>[^'""\s]+)") is { Success: true } swaggerBase)
+                basePath = swaggerBase.Groups["path"].Value;
+            else if (lines[i].StartsWith("servers:", StringComparison.Ordinal) && basePath.Length == 0)
+            {
+                var url = lines.Skip(i + 1).TakeWhile(l => l.Length == 0 || char.IsWhiteSpace(l[0]) || l[0] == '-')
+                    .Select(l => Regex.Match(l, @"\burl\s*:\s*['""]?(?<url>[^'""\s]+)")).FirstOrDefault(m => m.Success);
+                if (url != null)
+                    basePath = ServerPath(url.Groups["url"].Value);
+            }
+            else if (Regex.IsMatch(lines[i], @"^paths\s*:\s*$"))
+                pathsAt = i;
+        }
+        if (pathsAt < 0)
+            return operations;
+
+        string? path = null;
+        int pathLine = 0, pathIndent = -1, methodIndent = -1;
+        ApiEndpoint? current = null;
+        var pathQuery = new List<string>();
+        var pathOperations = new List<ApiEndpoint>();
+        string? itemName = null, itemIn = null;
+        var itemIndent = -1;
+        var itemOwner = (ApiEndpoint?)null;
+
+        void FlushItem()
+        {
+            if (itemName != null && itemIn == "query")
+            {
+                if (itemOwner != null)
+                    itemOwner.QueryParameters.Add(itemName);
+                else
+                    pathQuery.Add(itemName);
+            }
+            itemName = itemIn = null;
+            itemIndent = -1;
+        }
+
+        void FlushPath()
+        {
+            FlushItem();
+            foreach (var operation in pathOperations)
+                operation.QueryParameters = pathQuery.Concat(operation.QueryParameters).Distinct().ToList();
+            operations.AddRange(pathOperations);
+            pathOperations.Clear();
+            pathQuery.Clear();
+            current = null;
+        }
+
+        for (var i = pathsAt + 1; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            if (line.Trim().Length == 0 || line.TrimStart().StartsWith('#'))
+                continue;
+            if (!char.IsWhiteSpace(line[0]))
+                break;
+
+            var key = YamlKey.Match(line);
+            var indent = line.Length - line.TrimStart().Length;
+            if (itemIndent >= 0 && (indent <= itemIndent && !key.Groups["dash"].Success || indent < itemIndent))
+                FlushItem();
+
+            if (!key.Success)
+                continue;
+            var name = key.Groups["key"].Value.Trim();
+            if (key.Groups["dash"].Success)
+            {
+                FlushItem();
+                itemIndent = indent;
+                itemOwner = current;
+            }
+
+            if (pathIndent < 0 || indent <= pathIndent)
+            {
+                if (!name.StartsWith('/'))
+                    continue;
+                FlushPath();
+                path = name;
+                pathLine = i + 1;
+                pathIndent = indent;
+                methodIndent = -1;
+                continue;
+            }
+            if (path == null)
+                continue;
+
+            if (methodIndent < 0 && !key.Groups["dash"].Success)
+                methodIndent = indent;
+            if (indent == methodIndent && !key.Groups["dash"].Success)
+            {
+                FlushItem();
+                current = null;
+                if (HttpMethods.Contains(name))
+                {
+                    current = new ApiEndpoint
+                    {
+                        Method = name.ToUpperInvariant(),
+                        Path = NormalizePath(Join(basePath, path)),
+                        Framework = "openapi",
+                        FilePath = filePath,
+                        Line = i + 1
+                    };
+                    pathOperations.Add(current);
+                }
+                continue;
+            }
+
+            if (itemIndent >= 0)
+            {
+                var value = key.Groups["value"].Value.Trim().Trim('\'', '"');
+                if (name == "name")
+                    itemName = value;
+                else if (name == "in")
+                    itemIn = value;
+            }
+        }
+        FlushPath();
+
+        return operations;
+    }
+
+    /// <summary>
+    /// The path part of an OpenAPI server URL, which prefixes every operation's path
+    /// </summary>
+    public static string ServerPath(string url)
+    {
+        var scheme = Scheme.Match(url);
+        var path = scheme.Success ? url[scheme.Length..] : url;
+        return path.StartsWith('/') ? path : string.Empty;
+    }
+}
@@ -0,0 +1,320 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Declared layers and the rules between them, from .codesearch-architecture.json in the workspace root or
+/// CodeSearch:Architecture in configuration
+/// </summary>
+public class ArchitectureSettings
+{
+    public List<ArchitectureLayer> Layers { get; set; } = new();
+    public List<ArchitectureRule> Rules { get; set; } = new();
+}
+
+/// <summary>
+/// A named group of files, by path glob or by namespace/package prefix
+/// </summary>
+public class ArchitectureLayer
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Workspace-relative globs: src/Domain/**, **/*Repository.cs; a trailing slash covers a directory
+    /// </summary>
+    public List<string> Paths { get; set; } = new();
+
+    /// <summary>
+    /// Namespace or package prefixes (Acme.Domain, com.acme.domain) for files the globs miss and for imports
+    /// of code outside the workspace
+    /// </summary>
+    public List<string> Namespaces { get; set; } = new();
+}
+
+/// <summary>
+/// What files of a layer may not depend on or reference
+/// </summary>
+public class ArchitectureRule
+{
+    /// <summary>
+    /// Name shown with violations; defaults to a description of the rule
+    /// </summary>
+    public string? Name { get; set; }
+
+    /// <summary>
+    /// Layer the rule constrains, or "*" for every file including files in no layer
+    /// </summary>
+    public string Layer { get; set; } = "*";
+
+    /// <summary>
+    /// Layers exempt from a "*" rule
+    /// </summary>
+    public List<string> Except { get; set; } = new();
+
+    /// <summary>
+    /// Layers the constrained files must not import
+    /// </summary>
+    public List<string> MustNotDependOn { get; set; } = new();
+
+    /// <summary>
+    /// When set, the only layers the constrained files may import besides their own
+    /// </summary>
+    public List<string>? MayOnlyDependOn { get; set; }
+
+    /// <summary>
+    /// Imports (System.Data.SqlClient, database/sql, java.sql), identifiers used in code (SqlConnection) or
+    /// /regular expressions/ matched against raw lines, string literals included
+    /// </summary>
+    public List<string> MustNotReference { get; set; } = new();
+
+    public string DisplayName => Name
+        ?? (Layer == "*" ? "everything" : Layer)
+           + (Except.Count > 0 ? $" except {string.Join(", ", Except)}" : string.Empty)
+           + (MustNotDependOn.Count > 0 ? $" must not depend on {string.Join(", ", MustNotDependOn)}" : string.Empty)
+           + (MayOnlyDependOn != null ? $" may only depend on {(MayOnlyDependOn.Count > 0 ? string.Join(", ", MayOnlyDependOn) : "itself")}" : string.Empty)
+           + (MustNotReference.Count > 0 ? $" must not reference {string.Join(", ", MustNotReference)}" : string.Empty);
+}
+
+/// <summary>
+/// A reference breaking a rule: the importing or referencing line, and the file or name it reaches
+/// </summary>
+public class LayerViolation
+{
+    public string Rule { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Layer of the offending file; null when it is in no layer
+    /// </summary>
+    public string? FromLayer { get; set; }
+
+    /// <summary>
+    /// Layer reached; null for a forbidden reference
+    /// </summary>
+    public string? ToLayer { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The import or reference as written
+    /// </summary>
+    public string Reference { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The offending reference path: the referencing file:line, then the workspace file it reaches when the
+    /// reference resolves to one
+    /// </summary>
+    public List<string> Path { get; set; } = new();
+}
+
+/// <summary>
+/// Assigns files to declared layers and checks layering rules against their imports (resolved by
+/// <see cref="ImportGraph"/>) and the names their code references
+/// </summary>
+public static class ArchitectureLayers
+{
+    private static readonly Regex NamespaceDeclaration = new(@"^\s*(?:namespace|package)\s+(?<name>[\w.]+)", RegexOptions.Compiled | RegexOptions.Multiline);
+
+    /// <summary>
+    /// Problems that would make rules silently pass: unnamed layers, and rules naming layers that are not declared
+    /// </summary>
+    public static List<string> Validate(ArchitectureSettings settings)
+    {
+        var problems = new List<string>();
+        var names = settings.Layers.Select(l => l.Name).ToHashSet(StringComparer.OrdinalIgnoreCase);
+        foreach (var layer in settings.Layers.Where(l => string.IsNullOrWhiteSpace(l.Name)))
+            problems.Add("A layer has no name");
+        foreach (var layer in settings.Layers.Where(l => l.Paths.Count == 0 && l.Namespaces.Count == 0))
+            problems.Add($"Layer '{layer.Name}' has neither paths nor namespaces, so no file belongs to it");
+        foreach (var duplicate in settings.Layers.GroupBy(l => l.Name, StringComparer.OrdinalIgnoreCase).Where(g => g.Count() > 1))
+            problems.Add($"Layer '{duplicate.Key}' is declared {duplicate.Count()} times");
+        foreach (var duplicate in settings.Rules.GroupBy(r => r.DisplayName).Where(g => g.Count() > 1))
+            problems.Add($"Rule '{duplicate.Key}' is declared {duplicate.Count()} times - give the rules distinct names");
+        foreach (var rule in settings.Rules)
+        {
+            var referenced = new[] { rule.Layer }.Concat(rule.Except).Concat(rule.MustNotDependOn).Concat(rule.MayOnlyDependOn ?? new List<string>());
+            foreach (var unknown in referenced.Where(n => n != "*" && !names.Contains(n)).Distinct(StringComparer.OrdinalIgnoreCase))
+                problems.Add($"Rule '{rule.DisplayName}' names undeclared layer '{unknown}'");
+            if (rule.MustNotDependOn.Count == 0 && rule.MayOnlyDependOn == null && rule.MustNotReference.Count == 0)
+                problems.Add($"Rule '{rule.DisplayName}' forbids nothing - give mustNotDependOn, mayOnlyDependOn or mustNotReference");
+            foreach (var pattern in rule.MustNotReference.Where(IsRegex))
+            {
+                try
+                {
+                    _ = new Regex(pattern[1..^1]);
+                }
+                catch (ArgumentException ex)
+                {
+                    problems.Add($"Rule '{rule.DisplayName}' has an invalid pattern {pattern}: {ex.Message}");
+                }
+            }
+        }
+        return problems;
+    }
+
+    /// <summary>
+    /// The layer of each file: the first layer whose globs match its path, else whose namespace prefixes match
+    /// the namespace or package it declares. Files in no layer are left out.
+    /// </summary>
+    public static Dictionary<string, string> AssignLayers(ArchitectureSettings settings, IReadOnlyList<(string Path, string? Content)> files)
+    {
+        var globs = settings.Layers.Select(l => (l.Name, Patterns: l.Paths.Select(GlobToRegex).ToList())).ToList();
+        var layers = new Dictionary<string, string>(StringComparer.Ordinal);
+        foreach (var (path, content) in files)
+        {
+            var layer = globs.FirstOrDefault(g => g.Patterns.Any(p => p.IsMatch(path))).Name;
+            if (layer == null && content != null && NamespaceDeclaration.Match(content) is { Success: true } declaration)
+                layer = LayerOfNamespace(settings, declaration.Groups["name"].Value);
+            if (layer != null)
+                layers[path] = layer;
+        }
+        return layers;
+    }
+
+    /// <summary>
+    /// Every reference breaking a rule, ordered by file and line
+    /// </summary>
+    public static List<LayerViolation> Check(ArchitectureSettings settings, IReadOnlyList<(string Path, string? Content)> files,
+        IReadOnlyDictionary<string, string> layers, IReadOnlyList<ImportLink> imports)
+    {
+        var violations = new List<LayerViolation>();
+        var importsByFile = imports.GroupBy(i => i.FromPath).ToDictionary(g => g.Key, g => g.ToList(), StringComparer.Ordinal);
+
+        foreach (var rule in settings.Rules)
+        {
+            var name = rule.DisplayName;
+            var references = rule.MustNotReference.Select(p => (Text: p, Pattern: ReferencePattern(p))).ToList();
+            foreach (var (path, content) in files)
+            {
+                var fromLayer = layers.GetValueOrDefault(path);
+                if (!Applies(rule, fromLayer))
+                    continue;
+
+                var fileImports = importsByFile.GetValueOrDefault(path) ?? new List<ImportLink>();
+                foreach (var import in fileImports)
+                {
+                    // Layers the import reaches: its workspace files' layers, or the layer owning its namespace
+                    var reached = import.Targets
+                        .Select(t => (Layer: layers.GetValueOrDefault(t), Target: (string?)t))
+                        .Where(t => t.Layer != null)
+                        .Append((Layer: LayerOfNamespace(settings, import.Import), Target: (string?)null))
+                        .Where(t => t.Layer != null && !t.Layer.Equals(fromLayer, StringComparison.OrdinalIgnoreCase))
+                        .GroupBy(t => t.Layer!, StringComparer.OrdinalIgnoreCase)
+                        .Select(g => (Layer: g.Key, Target: g.Select(t => t.Target).FirstOrDefault(t => t != null)));
+
+                    foreach (var (toLayer, target) in reached)
+                    {
+                        if (!Forbids(rule, toLayer))
+                            continue;
+                        violations.Add(new LayerViolation
+                        {
+                            Rule = name,
+                            FromLayer = fromLayer,
+                            ToLayer = toLayer,
+                            FilePath = path,
+                            Line = import.Line,
+                            Reference = import.Import,
+                            Path = target == null ? new List<string> { $"{path}:{import.Line}" } : new List<string> { $"{path}:{import.Line}", target }
+                        });
+                    }
+
+                    foreach (var reference in references.Where(r => !IsRegex(r.Text) && ImportMatches(import.Import, r.Text)))
+                    {
+                        violations.Add(Reference(name, fromLayer, path, import.Line, import.Import));
+                    }
+                }
+
+                if (content == null || references.Count == 0)
+                    continue;
+
+                // Names used in code and raw patterns; import lines were judged above
+                var importLines = fileImports.Select(i => i.Line).ToHashSet();
+                var lines = content.Replace("\r\n", "\n").Split('\n');
+                var masked = DataFlowScanner.MaskLines(lines, Path.GetExtension(path));
+                for (var i = 0; i < lines.Length; i++)
+                {
+                    if (importLines.Contains(i + 1))
+                        continue;
+                    var hit = references.FirstOrDefault(r => r.Pattern.IsMatch(IsRegex(r.Text) ? lines[i] : masked[i]));
+                    if (hit.Text != null)
+                        violations.Add(Reference(name, fromLayer, path, i + 1, hit.Pattern.Match(IsRegex(hit.Text) ? lines[i] : masked[i]).Value.Trim()));
+                }
+            }
+        }
+
+        return violations
+            .GroupBy(v => (v.Rule, v.FilePath, v.Line, v.ToLayer, v.Reference))
+            .Select(g => g.First())
+            .OrderBy(v => v.FilePath, StringComparer.Ordinal)
+            .ThenBy(v => v.Line)
+            .ToList();
+    }
+
+    private static bool Applies(ArchitectureRule rule, string? layer) =>
+        rule.Layer == "*"
+            ? layer == null || !rule.Except.Contains(layer, StringComparer.OrdinalIgnoreCase)
+            : rule.Layer.Equals(layer, StringComparison.OrdinalIgnoreCase);
+
+    private static bool Forbids(ArchitectureRule rule, string toLayer) =>
+        rule.MustNotDependOn.Contains(toLayer, StringComparer.OrdinalIgnoreCase)
+        || rule.MayOnlyDependOn != null && !rule.MayOnlyDependOn.Contains(toLayer, StringComparer.OrdinalIgnoreCase);
+
+    private static string? LayerOfNamespace(ArchitectureSettings settings, string name) =>
+        settings.Layers
+            .SelectMany(l => l.Namespaces.Select(n => (l.Name, Namespace: n)))
+            .Where(l => name == l.Namespace || name.StartsWith(l.Namespace + ".", StringComparison.Ordinal))
+            .OrderByDescending(l => l.Namespace.Length)
+            .Select(l => l.Name)
+            .FirstOrDefault();
+
+    // An import matches a reference naming it or a module above it: database/sql, java.sql.*, serde::de
+    private static bool ImportMatches(string import, string reference) =>
+        import == reference
+        || import.Length > reference.Length && import.StartsWith(reference, StringComparison.Ordinal) && import[reference.Length] is '.' or '/' or ':';
+
+    private static bool IsRegex(string pattern) => pattern.Length > 2 && pattern.StartsWith('/') && pattern.EndsWith('/');
+
+    private static Regex ReferencePattern(string reference)
+    {
+        if (IsRegex(reference))
+        {
+            try
+            {
+                return new Regex(reference[1..^1], RegexOptions.IgnoreCase);
+            }
+            catch (ArgumentException)
+            {
+                return new Regex("(?!)");
+            }
+        }
+        // A dotted or slashed reference is also matched where code spells it out in full
+        return new Regex($@"(?<![\w.]){Regex.Escape(reference)}\b");
+    }
+
+    private static LayerViolation Reference(string rule, string? layer, string path, int line, string reference) => new()
+    {
+        Rule = rule,
+        FromLayer = layer,
+        FilePath = path,
+        Line = line,
+        Reference = reference.Length > 120 ? reference[..117] + "..." : reference,
+        Path = new List<string> { $"{path}:{line}" }
+    };
+
+    // "*.sql" matches file names anywhere; patterns with a slash match the relative path, and a trailing slash
+    // ("src/Domain/") covers everything below the directory
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        if (normalized.EndsWith('/'))
+            normalized += "**";
+
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+}
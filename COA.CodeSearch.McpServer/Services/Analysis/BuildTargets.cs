@@ -0,0 +1,555 @@
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using System.Xml;
+using System.Xml.Linq;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A runnable target of a build system: a make rule, an MSBuild target, an npm script or a Taskfile task
+/// </summary>
+public class BuildTarget
+{
+    /// <summary>
+    /// make, msbuild, npm or task
+    /// </summary>
+    public string Tool { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Command line running the target from the workspace root
+    /// </summary>
+    public string Invocation { get; set; } = string.Empty;
+
+    /// <summary>
+    /// From a ## or # comment above a make rule, a target's Label, or a task's desc
+    /// </summary>
+    public string? Description { get; set; }
+
+    /// <summary>
+    /// Commands as written: recipe lines, Exec commands and other MSBuild tasks, script text, task cmds
+    /// </summary>
+    public List<string> Commands { get; set; } = new();
+
+    /// <summary>
+    /// Targets run first: prerequisites, DependsOnTargets, scripts it runs, task deps
+    /// </summary>
+    public List<string> DependsOn { get; set; } = new();
+
+    /// <summary>
+    /// Targets it hooks onto: "before Build" (BeforeTargets, pre scripts) or "after Build"
+    /// </summary>
+    public List<string> Triggers { get; set; } = new();
+
+    /// <summary>
+    /// Files or globs it reads and writes: file prerequisites and file targets, Inputs/Outputs, sources/generates
+    /// </summary>
+    public List<string> Inputs { get; set; } = new();
+    public List<string> Outputs { get; set; } = new();
+
+    /// <summary>
+    /// The goal make runs with no arguments
+    /// </summary>
+    public bool Default { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// Reads build targets from Makefiles, MSBuild project and targets files, package.json scripts and Taskfiles.
+/// Each reader works on the file's text alone: included makefiles, imported MSBuild files and Taskfile includes
+/// are read as files of their own rather than followed.
+/// </summary>
+public static class BuildTargets
+{
+    private static readonly HashSet<string> MakeFileNames = new(StringComparer.Ordinal) { "Makefile", "makefile", "GNUmakefile" };
+    private static readonly HashSet<string> MsBuildExtensions = new(StringComparer.OrdinalIgnoreCase) { ".csproj", ".vbproj", ".fsproj", ".proj", ".props", ".targets" };
+    private static readonly Regex TaskfileName = new(@"^[Tt]askfile(\.dist)?\.ya?ml$", RegexOptions.Compiled);
+
+    private static readonly Regex MakeAssignment = new(@"^(?:(?:export|override|private)\s+)*[\w.$()-]+\s*(?:::?=|:::=|\?=|\+=|!=|=)", RegexOptions.Compiled);
+    private static readonly Regex MakeRule = new(@"^(?<targets>[^:#=\s][^:#=]*?)\s*::?(?!=)(?<rest>.*)$", RegexOptions.Compiled);
+    private static readonly Regex MakeDirective = new(@"^(?:-?include|sinclude|ifn?eq|ifn?def|else|endif|export|unexport|vpath|undefine)\b", RegexOptions.Compiled);
+    private static readonly Regex DefaultGoal = new(@"^\.DEFAULT_GOAL\s*:?=\s*(?<goal>\S+)", RegexOptions.Compiled);
+    private static readonly Regex ScriptCall = new(@"\b(?:npm\s+run(?:-script)?|pnpm(?:\s+run)?|yarn(?:\s+run)?|bun\s+run)\s+(?<name>[\w:.-]+)", RegexOptions.Compiled);
+    private static readonly Regex RunAll = new(@"\b(?:npm-run-all|run-s|run-p)\b(?<args>[^&|;]*)", RegexOptions.Compiled);
+    private static readonly Regex PathToken = new(@"^(?:\.{0,2}/)?[\w@.-]+(?:/[\w@.*-]+)+$|^[\w.-]+\.(?:js|mjs|cjs|ts|mts|json|sh|py|yml|yaml)$", RegexOptions.Compiled);
+    private static readonly Regex YamlKey = new(@"^(?<indent>\s*)(?:""(?<key>[^""]+)""|'(?<key>[^']+)'|(?<key>[\w:.-]+))\s*:(?:\s+(?<value>.*))?$", RegexOptions.Compiled);
+
+    public static bool IsBuildFile(string filePath)
+    {
+        var name = Path.GetFileName(filePath);
+        return MakeFileNames.Contains(name)
+               || name.EndsWith(".mk", StringComparison.OrdinalIgnoreCase)
+               || MsBuildExtensions.Contains(Path.GetExtension(name))
+               || name.Equals("package.json", StringComparison.Ordinal)
+               || TaskfileName.IsMatch(name);
+    }
+
+    /// <summary>
+    /// The targets a build file declares; <paramref name="filePath"/> is workspace-relative and shapes the
+    /// invocation. <paramref name="runner"/> is the package manager for package.json: npm, yarn or pnpm.
+    /// </summary>
+    public static List<BuildTarget> Parse(string filePath, string content, string runner = "npm")
+    {
+        var name = Path.GetFileName(filePath);
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        if (name.Equals("package.json", StringComparison.Ordinal))
+            return ParsePackageJson(filePath, content, lines, runner);
+        if (TaskfileName.IsMatch(name))
+            return ParseTaskfile(filePath, lines);
+        if (MsBuildExtensions.Contains(Path.GetExtension(name)))
+            return ParseMsBuild(filePath, content);
+        return ParseMakefile(filePath, lines);
+    }
+
+    private static List<BuildTarget> ParseMakefile(string filePath, string[] lines)
+    {
+        var targets = new Dictionary<string, BuildTarget>(StringComparer.Ordinal);
+        var phony = new HashSet<string>(StringComparer.Ordinal);
+        var current = new List<BuildTarget>();
+        string? defaultGoal = null;
+        string? firstGoal = null;
+        var comments = new List<string>();
+        var inDefine = false;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var start = i;
+            var line = lines[i];
+            while (line.EndsWith('\\') && i + 1 < lines.Length)
+                line = line[..^1] + " " + lines[++i].Trim();
+
+            if (inDefine)
+            {
+                inDefine = !line.TrimStart().StartsWith("endef", StringComparison.Ordinal);
+                continue;
+            }
+            if (line.StartsWith('\t'))
+            {
+                var command = line.Trim().TrimStart('@', '-', '+').Trim();
+                if (command.Length > 0 && !command.StartsWith('#'))
+                {
+                    foreach (var target in current)
+                        target.Commands.Add(command);
+                }
+                continue;
+            }
+
+            var text = line.Trim();
+            if (text.StartsWith('#'))
+            {
+                comments.Add(text.TrimStart('#').Trim());
+                continue;
+            }
+            if (text.Length == 0)
+            {
+                comments.Clear();
+                continue;
+            }
+
+            var description = comments.Count > 0 ? comments[^1] : null;
+            comments.Clear();
+
+            if (text.StartsWith("define ", StringComparison.Ordinal) || text == "define")
+            {
+                inDefine = true;
+                current = new List<BuildTarget>();
+                continue;
+            }
+            if (DefaultGoal.Match(text) is { Success: true } goal)
+            {
+                defaultGoal = goal.Groups["goal"].Value;
+                continue;
+            }
+            if (MakeDirective.IsMatch(text) || MakeAssignment.IsMatch(text))
+            {
+                if (!MakeDirective.IsMatch(text))
+                    current = new List<BuildTarget>();
+                continue;
+            }
+
+            var rule = MakeRule.Match(text);
+            if (!rule.Success)
+                continue;
+
+            var rest = rule.Groups["rest"].Value;
+            var help = rest.IndexOf("##", StringComparison.Ordinal);
+            if (help >= 0)
+            {
+                description = rest[(help + 2)..].Trim();
+                rest = rest[..help];
+            }
+            else if (rest.IndexOf('#') is var hash and >= 0)
+            {
+                rest = rest[..hash];
+            }
+
+            string? inline = null;
+            if (rest.IndexOf(';') is var semicolon and >= 0)
+            {
+                inline = rest[(semicolon + 1)..].Trim();
+                rest = rest[..semicolon];
+            }
+
+            var names = rule.Groups["targets"].Value.Split((char[]?)null, StringSplitOptions.RemoveEmptyEntries);
+            var prerequisites = rest.Split((char[]?)null, StringSplitOptions.RemoveEmptyEntries).Where(p => p != "|").ToList();
+            if (names.Any(n => n == ".PHONY"))
+            {
+                phony.UnionWith(prerequisites);
+                current = new List<BuildTarget>();
+                continue;
+            }
+
+            // Target-specific variables (test: GOFLAGS = -race) add no rule
+            if (MakeAssignment.IsMatch(rest.Trim()))
+            {
+                if (description != null)
+                    comments.Add(description);
+                current = new List<BuildTarget>();
+                continue;
+            }
+
+            current = new List<BuildTarget>();
+            foreach (var name in names)
+            {
+                // Special targets and pattern rules can't be asked for by name
+                if (name.StartsWith('.') && name.Length > 1 && char.IsUpper(name[1]) || name.Contains('%'))
+                    continue;
+                firstGoal ??= name;
+                if (!targets.TryGetValue(name, out var target))
+                {
+                    targets[name] = target = new BuildTarget { Tool = "make", Name = name, FilePath = filePath, Line = start + 1 };
+                }
+                target.Description ??= string.IsNullOrEmpty(description) ? null : description;
+                AddRange(target.DependsOn, prerequisites);
+                if (!string.IsNullOrEmpty(inline))
+                    target.Commands.Add(inline);
+                current.Add(target);
+            }
+        }
+
+        var file = Path.GetFileName(filePath);
+        var directory = DirectoryOf(filePath);
+        foreach (var target in targets.Values)
+        {
+            // Prerequisites that aren't targets are files; so is a target nothing declares phony that has a path shape
+            var files = target.DependsOn.Where(p => !targets.ContainsKey(p) && !phony.Contains(p)).ToList();
+            target.Inputs.AddRange(files);
+            target.DependsOn.RemoveAll(files.Contains);
+            if (!phony.Contains(target.Name) && (target.Name.Contains('.') || target.Name.Contains('/')))
+                target.Outputs.Add(target.Name);
+
+            target.Default = target.Name == (defaultGoal ?? firstGoal);
+            target.Invocation = MakeFileNames.Contains(file)
+                ? (directory.Length == 0 ? "make " : $"make -C {directory} ") + target.Name
+                : $"make -f {filePath} {target.Name}";
+        }
+        return targets.Values.ToList();
+    }
+
+    private static List<BuildTarget> ParseMsBuild(string filePath, string content)
+    {
+        XDocument document;
+        try
+        {
+            document = XDocument.Parse(content, LoadOptions.SetLineInfo);
+        }
+        catch (XmlException)
+        {
+            return new List<BuildTarget>();
+        }
+
+        var targets = new List<BuildTarget>();
+        foreach (var element in document.Descendants().Where(e => e.Name.LocalName == "Target"))
+        {
+            var name = (string?)element.Attribute("Name");
+            if (string.IsNullOrWhiteSpace(name))
+                continue;
+
+            var target = new BuildTarget
+            {
+                Tool = "msbuild",
+                Name = name,
+                Invocation = $"dotnet msbuild {filePath} -t:{name}",
+                Description = (string?)element.Attribute("Label"),
+                FilePath = filePath,
+                Line = ((IXmlLineInfo)element).LineNumber
+            };
+            AddRange(target.DependsOn, SplitList((string?)element.Attribute("DependsOnTargets")));
+            target.Triggers.AddRange(SplitList((string?)element.Attribute("BeforeTargets")).Select(t => "before " + t));
+            target.Triggers.AddRange(SplitList((string?)element.Attribute("AfterTargets")).Select(t => "after " + t));
+            AddRange(target.Inputs, SplitList((string?)element.Attribute("Inputs")));
+            AddRange(target.Outputs, SplitList((string?)element.Attribute("Outputs")));
+
+            foreach (var task in element.Elements())
+            {
+                var taskName = task.Name.LocalName;
+                if (taskName is "PropertyGroup" or "ItemGroup" or "OnError")
+                    continue;
+                if (taskName == "CallTarget")
+                {
+                    AddRange(target.DependsOn, SplitList((string?)task.Attribute("Targets")));
+                    continue;
+                }
+                target.Commands.Add(taskName == "Exec"
+                    ? (string?)task.Attribute("Command") ?? "Exec"
+                    : string.Join(" ", new[] { taskName }.Concat(task.Attributes().Where(a => a.Name.LocalName != "Condition").Select(a => $"{a.Name.LocalName}=\"{a.Value}\""))));
+
+                // Copy, WriteLinesToFile, Delete and the like name the files they read and write
+                foreach (var attribute in task.Attributes())
+                {
+                    var values = SplitList(attribute.Value);
+                    switch (attribute.Name.LocalName)
+                    {
+                        case "SourceFiles" or "Projects" or "InputFiles":
+                            AddRange(target.Inputs, values);
+                            break;
+                        case "DestinationFiles" or "DestinationFolder" or "OutputFile" or "File" when taskName != "ReadLinesFromFile":
+                            AddRange(target.Outputs, values);
+                            break;
+                        case "File":
+                            AddRange(target.Inputs, values);
+                            break;
+                    }
+                }
+            }
+            targets.Add(target);
+        }
+        return targets;
+    }
+
+    private static List<BuildTarget> ParsePackageJson(string filePath, string content, string[] lines, string runner)
+    {
+        var scripts = new List<(string Name, string Command)>();
+        try
+        {
+            using var document = JsonDocument.Parse(content, new JsonDocumentOptions { CommentHandling = JsonCommentHandling.Skip, AllowTrailingCommas = true });
+            if (document.RootElement.ValueKind != JsonValueKind.Object
+                || !document.RootElement.TryGetProperty("scripts", out var element) || element.ValueKind != JsonValueKind.Object)
+                return new List<BuildTarget>();
+            foreach (var script in element.EnumerateObject())
+            {
+                if (script.Value.ValueKind == JsonValueKind.String)
+                    scripts.Add((script.Name, script.Value.GetString()!));
+            }
+        }
+        catch (JsonException)
+        {
+            return new List<BuildTarget>();
+        }
+
+        var names = scripts.Select(s => s.Name).ToHashSet(StringComparer.Ordinal);
+        var scriptsLine = Array.FindIndex(lines, l => l.Contains("\"scripts\"", StringComparison.Ordinal));
+        var directory = DirectoryOf(filePath);
+        var targets = new List<BuildTarget>();
+
+        foreach (var (name, command) in scripts)
+        {
+            var line = scriptsLine < 0 ? -1 : Array.FindIndex(lines, scriptsLine, l => Regex.IsMatch(l, $"\"{Regex.Escape(name)}\"\\s*:"));
+            var target = new BuildTarget
+            {
+                Tool = "npm",
+                Name = name,
+                Invocation = Runner(runner, directory, name),
+                Commands = { command },
+                FilePath = filePath,
+                Line = line < 0 ? 1 : line + 1
+            };
+
+            AddRange(target.DependsOn, ScriptCall.Matches(command).Select(m => m.Groups["name"].Value).Where(names.Contains));
+            foreach (Match runAll in RunAll.Matches(command))
+            {
+                foreach (var pattern in runAll.Groups["args"].Value.Split(' ', StringSplitOptions.RemoveEmptyEntries).Where(a => !a.StartsWith('-')))
+                {
+                    var regex = new Regex("^" + Regex.Escape(pattern.Trim('"', '\'')).Replace(@"\*\*", ".*").Replace(@"\*", "[^:]*") + "$");
+                    AddRange(target.DependsOn, names.Where(n => regex.IsMatch(n)));
+                }
+            }
+
+            // npm runs prebuild before build and postbuild after it
+            foreach (var (prefix, when) in new[] { ("pre", "before "), ("post", "after ") })
+            {
+                if (name.StartsWith(prefix, StringComparison.Ordinal) && names.Contains(name[prefix.Length..]))
+                    target.Triggers.Add(when + name[prefix.Length..]);
+            }
+
+            AddRange(target.Inputs, command.Split(new[] { ' ', '&', '|', ';', '=' }, StringSplitOptions.RemoveEmptyEntries)
+                .Select(t => t.Trim('"', '\''))
+                .Where(t => PathToken.IsMatch(t) && !t.StartsWith("node_modules/", StringComparison.Ordinal) && !t.StartsWith('@')));
+            targets.Add(target);
+        }
+        return targets;
+    }
+
+    /// <summary>
+    /// The tasks: map of a Taskfile, read by indentation - desc, cmds, deps, sources and generates, with
+    /// commands as plain strings, block scalars or cmd:/task: entries
+    /// </summary>
+    private static List<BuildTarget> ParseTaskfile(string filePath, string[] lines)
+    {
+        var targets = new List<BuildTarget>();
+        var directory = DirectoryOf(filePath);
+        var tasksLine = Array.FindIndex(lines, l => l.TrimEnd() == "tasks:");
+        if (tasksLine < 0)
+            return targets;
+
+        BuildTarget? task = null;
+        string? section = null;
+        var taskIndent = -1;
+        var sectionIndent = -1;
+
+        for (var i = tasksLine + 1; i < lines.Length; i++)
+        {
+            var line = lines[i].TrimEnd();
+            var text = line.TrimStart();
+            if (text.Length == 0 || text.StartsWith('#'))
+                continue;
+            var indent = line.Length - text.Length;
+            if (indent == 0)
+                break;
+
+            if (taskIndent < 0)
+                taskIndent = indent;
+            if (indent == taskIndent)
+            {
+                var key = YamlKey.Match(line);
+                if (!key.Success)
+                    continue;
+                var name = key.Groups["key"].Value;
+                task = new BuildTarget
+                {
+                    Tool = "task",
+                    Name = name,
+                    Invocation = (directory.Length == 0 ? "task " : $"task -d {directory} ") + name,
+                    FilePath = filePath,
+                    Line = i + 1
+                };
+                targets.Add(task);
+                section = null;
+                // build: go build ./... and build: [lint, test] are shorthand for cmds
+                var value = key.Groups["value"].Value.Trim();
+                if (value.Length > 0)
+                    AddValues(task, "cmds", value);
+                continue;
+            }
+            if (task == null)
+                continue;
+
+            if (section == null || indent <= sectionIndent)
+            {
+                var key = YamlKey.Match(line);
+                section = key.Success ? key.Groups["key"].Value : null;
+                sectionIndent = indent;
+                if (section != null && key.Groups["value"].Value.Trim() is { Length: > 0 } value && !value.StartsWith('|') && !value.StartsWith('>'))
+                {
+                    AddValues(task, section, value);
+                    section = null;
+                }
+                else if (section != null && key.Groups["value"].Value.TrimStart() is var block && (block.StartsWith('|') || block.StartsWith('>')))
+                {
+                    // desc: | with the text on the lines below
+                    var body = new List<string>();
+                    while (i + 1 < lines.Length && (lines[i + 1].Trim().Length == 0 || lines[i + 1].Length - lines[i + 1].TrimStart().Length > indent))
+                        body.Add(lines[++i].Trim());
+                    AddValues(task, section, string.Join("\n", body).Trim());
+                    section = null;
+                }
+                continue;
+            }
+
+            // An entry under the current section: - item, - task: name, - cmd: text, or a block scalar
+            if (!text.StartsWith("- ", StringComparison.Ordinal) && text != "-")
+            {
+                if (YamlKey.Match(text) is { Success: true } nested && nested.Groups["key"].Value is "cmd" or "task")
+                    AddEntry(task, section, nested.Groups["key"].Value, nested.Groups["value"].Value.Trim(), lines, ref i, indent);
+                continue;
+            }
+
+            var item = text[1..].Trim();
+            var entry = YamlKey.Match(item);
+            if (entry.Success && entry.Groups["key"].Value is "cmd" or "task")
+            {
+                AddEntry(task, section, entry.Groups["key"].Value, entry.Groups["value"].Value.Trim(), lines, ref i, indent);
+            }
+            else if (item.StartsWith('|') || item.StartsWith('>'))
+            {
+                var block = new List<string>();
+                while (i + 1 < lines.Length && (lines[i + 1].Trim().Length == 0 || lines[i + 1].Length - lines[i + 1].TrimStart().Length > indent))
+                    block.Add(lines[++i].Trim());
+                AddValues(task, section, string.Join("\n", block).Trim());
+            }
+            else if (!entry.Success)
+            {
+                AddValues(task, section, item);
+            }
+        }
+
+        foreach (var target in targets)
+            target.Default = target.Name == "default";
+        return targets;
+    }
+
+    private static void AddEntry(BuildTarget task, string? section, string key, string value, string[] lines, ref int i, int indent)
+    {
+        if (value.StartsWith('|') || value.StartsWith('>'))
+        {
+            var block = new List<string>();
+            while (i + 1 < lines.Length && (lines[i + 1].Trim().Length == 0 || lines[i + 1].Length - lines[i + 1].TrimStart().Length > indent + 2))
+                block.Add(lines[++i].Trim());
+            value = string.Join("\n", block).Trim();
+        }
+        // A cmds entry running another task is a dependency run in sequence
+        AddValues(task, key == "task" ? "deps" : section, value);
+    }
+
+    private static void AddValues(BuildTarget task, string? section, string value)
+    {
+        var values = value.StartsWith('[') && value.EndsWith(']')
+            ? value[1..^1].Split(',').Select(v => Unquote(v.Trim())).Where(v => v.Length > 0).ToList()
+            : new List<string> { Unquote(value) };
+        switch (section)
+        {
+            case "cmds" or "cmd":
+                task.Commands.AddRange(values);
+                break;
+            case "deps":
+                AddRange(task.DependsOn, values);
+                break;
+            case "desc":
+                task.Description ??= values[0];
+                break;
+            case "sources":
+                AddRange(task.Inputs, values);
+                break;
+            case "generates":
+                AddRange(task.Outputs, values);
+                break;
+        }
+    }
+
+    private static string Runner(string runner, string directory, string script) => runner switch
+    {
+        "yarn" => (directory.Length == 0 ? "yarn " : $"yarn --cwd {directory} ") + script,
+        "pnpm" => (directory.Length == 0 ? "pnpm run " : $"pnpm -C {directory} run ") + script,
+        _ => (directory.Length == 0 ? "npm run " : $"npm --prefix {directory} run ") + script
+    };
+
+    private static IEnumerable<string> SplitList(string? value) =>
+        (value ?? string.Empty).Split(';').Select(v => v.Trim()).Where(v => v.Length > 0);
+
+    private static void AddRange(List<string> list, IEnumerable<string> values)
+    {
+        foreach (var value in values)
+        {
+            if (!list.Contains(value))
+                list.Add(value);
+        }
+    }
+
+    private static string DirectoryOf(string filePath) => Path.GetDirectoryName(filePath)?.Replace('\\', '/') ?? string.Empty;
+
+    private static string Unquote(string text) =>
+        text.Length >= 2 && (text[0] == '"' && text[^1] == '"' || text[0] == '\'' && text[^1] == '\'') ? text[1..^1] : text;
+}
@@ -0,0 +1,858 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A CI pipeline definition: a GitHub Actions workflow or composite action, or an Azure Pipelines file or template
+/// </summary>
+public class CiPipeline
+{
+    /// <summary>
+    /// github or azure
+    /// </summary>
+    public string System { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The workflow or pipeline name, or the file name when it has none
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+
+    public List<CiStep> Steps { get; set; } = new();
+
+    /// <summary>
+    /// Variables the pipeline sets: env maps, Azure variables, GITHUB_ENV writes and setvariable logging commands
+    /// </summary>
+    public List<CiVariable> Variables { get; set; } = new();
+
+    /// <summary>
+    /// Scripts, directories, projects, templates, local actions and other files the pipeline names
+    /// </summary>
+    public List<CiReference> References { get; set; } = new();
+
+    /// <summary>
+    /// Environment variables, secrets and pipeline variables the steps read
+    /// </summary>
+    public List<CiReference> EnvUses { get; set; } = new();
+
+    /// <summary>
+    /// Azure variable groups the pipeline links; their variables are defined outside the repository
+    /// </summary>
+    public List<string> VariableGroups { get; set; } = new();
+}
+
+/// <summary>
+/// One step of a job: a script, an action or an Azure task
+/// </summary>
+public class CiStep
+{
+    public string Job { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The action (actions/checkout@v4) or Azure task (DotNetCoreCLI@2) the step runs
+    /// </summary>
+    public string? Uses { get; set; }
+
+    /// <summary>
+    /// Inline script text
+    /// </summary>
+    public string? Run { get; set; }
+
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A variable the pipeline defines
+/// </summary>
+public class CiVariable
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// pipeline, job &lt;id&gt; or step &lt;name&gt;
+    /// </summary>
+    public string Scope { get; set; } = string.Empty;
+
+    public string? Value { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// Something a step names: a path in the workspace, or a variable it reads
+/// </summary>
+public class CiReference
+{
+    /// <summary>
+    /// Paths: script, directory, project, template, action, workflow or file. Variables: env, var (an Azure
+    /// $(macro)), secret, or setting (a GitHub vars. configuration variable).
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// As written in the pipeline
+    /// </summary>
+    public string Value { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Workspace-relative path the value resolves to, after working directories and workspace variables;
+    /// null for variables and for paths built from other variables
+    /// </summary>
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Whether the path exists in the workspace; set by the caller that knows the workspace
+    /// </summary>
+    public bool? Exists { get; set; }
+
+    public string Job { get; set; } = string.Empty;
+    public string Step { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A variable pipelines define or read, with the workspace code that reads it from the environment
+/// </summary>
+public class CiEnvironmentVariable
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// read (a step or the code reads it), unread (defined but nothing in the workspace reads it) or
+    /// undefined (a step reads it as a pipeline variable no pipeline defines)
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public List<CiVariable> Definitions { get; set; } = new();
+
+    /// <summary>
+    /// Steps reading it, as file:line
+    /// </summary>
+    public List<string> StepUses { get; set; } = new();
+
+    /// <summary>
+    /// Workspace code and scripts reading it from the environment, as file:line
+    /// </summary>
+    public List<string> CodeReads { get; set; } = new();
+}
+
+/// <summary>
+/// Reads GitHub Actions workflows and composite actions and Azure Pipelines files into jobs, steps, the
+/// variables they define and the paths and variables they use. YAML is read by indentation - block maps and
+/// lists, block scalars, flow lists and simple flow maps; anchors and expressions are kept as text. Paths are
+/// resolved against working directories but existence is left to the caller.
+/// </summary>
+public static class CiPipelines
+{
+    private static readonly Regex YamlKey = new(
+        @"^(?:""(?<key>[^""]*)""|'(?<key>[^']*)'|(?<key>[^\s#'""\-][^#]*?|-[^\s#][^#]*?))\s*:(?:\s+(?<value>.*))?$", RegexOptions.Compiled);
+    private static readonly Regex AzurePipelineName = new(@"(^|[./-])azure-pipelines?([.-][\w.-]*)?\.ya?ml$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex ScriptPath = new(@"^(?:\.{0,2}/)?(?:[\w@.-]+/)*[\w@.-]+\.(?:sh|bash|ps1|psm1|py|rb|pl|js|mjs|cjs|ts|cmd|bat)$", RegexOptions.Compiled);
+    private static readonly Regex FilePathToken = new(@"^(?:\.{0,2}/)?(?:[\w@.*-]+/)+[\w@.*-]+$|^[\w.-]+\.(?:sh|ps1|py|js|json|ya?ml|csproj|fsproj|vbproj|sln|slnx|props|toml|lock|txt|cfg|ini|xml|config)$", RegexOptions.Compiled);
+    private static readonly Regex ProjectPath = new(@"\.(?:csproj|fsproj|vbproj|sln|slnx|proj)$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex GitHubEnvWrite = new(@"echo\s+[""']?(?<name>[A-Za-z_]\w*)=(?<value>[^""'>]*)[""']?\s*>>\s*[""']?\$\{?GITHUB_ENV", RegexOptions.Compiled);
+    private static readonly Regex AzureSetVariable = new(@"##vso\[task\.setvariable\s+variable=(?<name>[\w.]+)[^\]]*\](?<value>[^""'\r\n]*)", RegexOptions.Compiled);
+    private static readonly Regex Expression = new(@"\$\{\{\s*(?<scope>env|secrets|vars)\.(?<name>[A-Za-z_][\w-]*)\s*\}\}", RegexOptions.Compiled);
+    private static readonly Regex ShellVariable = new(@"(?<![\w$\\])\$(?:\{(?<name>[A-Za-z_]\w*)(?:[:#%/][^}]*)?\}|(?<name>[A-Za-z_]\w*))", RegexOptions.Compiled);
+    private static readonly Regex PowerShellVariable = new(@"\$env:(?<name>[A-Za-z_]\w*)", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex CmdVariable = new(@"%(?<name>[A-Za-z_]\w+)%", RegexOptions.Compiled);
+    private static readonly Regex ScriptTask = new(@"^(?:PowerShell|Bash|BatchScript|ShellScript|AzurePowerShell|AzureCLI)@", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex AzureMacro = new(@"\$\((?<name>[A-Za-z_][\w.]*)\)", RegexOptions.Compiled);
+    private static readonly Regex EnvironmentRead = new(
+        @"(?:GetEnvironmentVariable|Getenv|LookupEnv|getenv|environ\.get|env::var(?:_os)?|env!|ENV\.fetch|Deno\.env\.get)\(\s*[""'](?<name>[A-Za-z_]\w*)[""']" +
+        @"|(?:process\.env|import\.meta\.env)\.(?<name>[A-Za-z_]\w*)" +
+        @"|(?:process\.env|import\.meta\.env|environ|ENV|\$_ENV|\$_SERVER)\[\s*[""'](?<name>[A-Za-z_]\w*)[""']\s*\]",
+        RegexOptions.Compiled);
+
+    /// <summary>
+    /// Variables every runner provides; reading them without a definition is expected
+    /// </summary>
+    private static readonly Regex RunnerVariable = new(
+        @"^(?:GITHUB_\w+|RUNNER_\w+|ACTIONS_\w+|CI|HOME|PATH|PWD|USER|SHELL|TEMP|TMP|(?:Agent|Build|System|Pipeline|Environment|Release|Resources|Strategy|Checks)\..+|(?:AGENT|BUILD|SYSTEM|PIPELINE|RELEASE|TF)_\w+)$",
+        RegexOptions.Compiled);
+
+    private static readonly HashSet<string> Interpreters = new(StringComparer.Ordinal)
+    {
+        "bash", "sh", "zsh", "pwsh", "powershell", "python", "python3", "node", "ruby", "perl", "source", ".", "tsx", "ts-node", "deno", "bun", "cmd"
+    };
+    private static readonly HashSet<string> OutputFlags = new(StringComparer.Ordinal)
+    {
+        "-o", "--output", "-out", "--out", "--out-dir", "--outdir", "-d", "--destination", ">", ">>", "-O", "--results-directory", "-p:PublishDir", "tee"
+    };
+    private static readonly HashSet<string> ShellKeywords = new(StringComparer.Ordinal)
+    {
+        "if", "then", "else", "fi", "for", "do", "done", "while", "case", "esac", "echo", "export", "set", "test", "[", "[["
+    };
+
+    private sealed class Node
+    {
+        public Dictionary<string, Node>? Map;
+        public List<Node>? Items;
+        public string? Value;
+        public int Line;
+
+        // A | or > scalar, whose text starts on the line below its key
+        public bool Block;
+
+        public Node? this[string key] => Map != null && Map.TryGetValue(key, out var child) ? child : null;
+        public string? Text(string key) => this[key]?.Value;
+    }
+
+    /// <summary>
+    /// Workflows under .github/workflows, composite actions (action.yml), azure-pipelines*.yml anywhere and YAML
+    /// under .azure-pipelines, .azuredevops or .pipelines
+    /// </summary>
+    public static bool IsPipelineFile(string filePath)
+    {
+        var path = filePath.Replace('\\', '/');
+        var name = Path.GetFileName(path);
+        if (!name.EndsWith(".yml", StringComparison.OrdinalIgnoreCase) && !name.EndsWith(".yaml", StringComparison.OrdinalIgnoreCase))
+            return false;
+        return path.StartsWith(".github/workflows/", StringComparison.Ordinal)
+               || path.Contains("/.github/workflows/", StringComparison.Ordinal)
+               || path.StartsWith(".github/", StringComparison.Ordinal) && name is "action.yml" or "action.yaml"
+               || AzurePipelineName.IsMatch(name)
+               || Regex.IsMatch(path, @"(^|/)\.(?:azure-pipelines|azuredevops|pipelines)/");
+    }
+
+    /// <summary>
+    /// The pipeline in <paramref name="content"/>, or null when it has no jobs, stages or steps.
+    /// <paramref name="filePath"/> is workspace-relative; files under .github are read as GitHub Actions and the
+    /// rest as Azure Pipelines.
+    /// </summary>
+    public static CiPipeline? Parse(string filePath, string content)
+    {
+        filePath = filePath.Replace('\\', '/');
+        var root = ReadYaml(content.Replace("\r\n", "\n").Split('\n'));
+        if (root.Map == null)
+            return null;
+
+        var github = filePath.StartsWith(".github/", StringComparison.Ordinal) || filePath.Contains("/.github/", StringComparison.Ordinal);
+        var pipeline = new CiPipeline
+        {
+            System = github ? "github" : "azure",
+            Name = root.Text("name") is { Length: > 0 } name ? name : Path.GetFileNameWithoutExtension(filePath),
+            FilePath = filePath
+        };
+        var reader = new Reader(pipeline);
+
+        if (github)
+        {
+            if (root["jobs"] == null && root["runs"]?["steps"] == null)
+                return null;
+            reader.ReadGitHub(root);
+        }
+        else
+        {
+            // A variables template has only variables
+            if (root["jobs"] == null && root["stages"] == null && root["steps"] == null && root["extends"] == null && root["variables"] == null)
+                return null;
+            reader.ReadAzure(root);
+        }
+        return pipeline;
+    }
+
+    /// <summary>
+    /// Environment variable reads in source code - GetEnvironmentVariable, os.Getenv, process.env, os.environ,
+    /// env::var, System.getenv, ENV[] - and in shell and PowerShell scripts, as (name, line)
+    /// </summary>
+    public static IEnumerable<(string Name, int Line)> ReadEnvironment(string filePath, string[] lines)
+    {
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            var names = extension switch
+            {
+                ".sh" or ".bash" or ".zsh" => ShellVariable.Matches(line).Select(m => m.Groups["name"].Value),
+                ".ps1" or ".psm1" => PowerShellVariable.Matches(line).Select(m => m.Groups["name"].Value),
+                ".cmd" or ".bat" => CmdVariable.Matches(line).Select(m => m.Groups["name"].Value),
+                _ => EnvironmentRead.Matches(line).Select(m => m.Groups["name"].Value)
+            };
+            foreach (var name in names.Distinct(StringComparer.Ordinal))
+                yield return (name, i + 1);
+        }
+    }
+
+    /// <summary>
+    /// Joins the variables pipelines define with the steps and code that read them. <paramref name="codeReads"/>
+    /// maps a variable name to the file:line places the workspace reads it. A shell $name in a step only counts
+    /// when the name is defined somewhere or read by the code, since scripts also have locals; an Azure $(name)
+    /// nothing defines is undefined unless a variable group or template could supply it.
+    /// </summary>
+    public static List<CiEnvironmentVariable> LinkEnvironment(IReadOnlyCollection<CiPipeline> pipelines, IReadOnlyDictionary<string, List<string>> codeReads)
+    {
+        var variables = new Dictionary<string, CiEnvironmentVariable>(StringComparer.Ordinal);
+        CiEnvironmentVariable Get(string name) =>
+            variables.TryGetValue(name, out var variable) ? variable : variables[name] = new CiEnvironmentVariable { Name = name };
+
+        foreach (var definition in pipelines.SelectMany(p => p.Variables))
+            Get(definition.Name).Definitions.Add(definition);
+
+        var groups = pipelines.Any(p => p.VariableGroups.Count > 0 || p.References.Any(r => r.Kind == "template" && r.Step == "variable template"));
+        foreach (var use in pipelines.SelectMany(p => p.EnvUses).Where(u => u.Kind is "env" or "var"))
+        {
+            if (!variables.ContainsKey(use.Value) && (RunnerVariable.IsMatch(use.Value) || use.Kind == "env" && !codeReads.ContainsKey(use.Value)))
+                continue;
+            var variable = Get(use.Value);
+            var at = $"{use.FilePath}:{use.Line}";
+            if (!variable.StepUses.Contains(at))
+                variable.StepUses.Add(at);
+        }
+
+        foreach (var variable in variables.Values)
+        {
+            if (codeReads.TryGetValue(variable.Name, out var reads))
+                variable.CodeReads.AddRange(reads);
+            // A definition that only feeds another definition ($(a).$(b)) still counts as read
+            var feeds = pipelines.SelectMany(p => p.Variables).Any(v => v.Value?.Contains($"$({variable.Name})", StringComparison.Ordinal) == true
+                                                                         || v.Value?.Contains($"env.{variable.Name}", StringComparison.Ordinal) == true);
+            variable.Status = variable.Definitions.Count == 0 ? (groups ? "read" : "undefined")
+                : variable.StepUses.Count > 0 || variable.CodeReads.Count > 0 || feeds ? "read"
+                : "unread";
+        }
+        return variables.Values.OrderBy(v => v.Status == "read" ? 1 : 0).ThenBy(v => v.Name, StringComparer.Ordinal).ToList();
+    }
+
+    private sealed class Reader
+    {
+        private readonly CiPipeline _pipeline;
+        private readonly string _directory;
+
+        public Reader(CiPipeline pipeline)
+        {
+            _pipeline = pipeline;
+            _directory = Path.GetDirectoryName(pipeline.FilePath)?.Replace('\\', '/') ?? string.Empty;
+        }
+
+        public void ReadGitHub(Node root)
+        {
+            AddVariables(root["env"], "pipeline");
+
+            // A composite action runs its steps from the caller's workspace; github.action_path is its own directory
+            if (root["runs"]?["steps"] is { } actionSteps)
+            {
+                ReadGitHubSteps(actionSteps, "action", null);
+                return;
+            }
+
+            var defaults = root["defaults"]?["run"]?.Text("working-directory");
+            foreach (var (id, job) in root["jobs"]?.Map ?? new Dictionary<string, Node>())
+            {
+                AddVariables(job["env"], $"job {id}");
+                if (job.Text("uses") is { } workflow)
+                    AddUses(workflow, id, job.Text("name") ?? id, job["uses"]!.Line, "workflow");
+                AddSecrets(job["with"], id, id);
+                AddSecrets(job["secrets"], id, id);
+                ReadGitHubSteps(job["steps"], id, job["defaults"]?["run"]?.Text("working-directory") ?? defaults);
+            }
+        }
+
+        private void ReadGitHubSteps(Node? steps, string job, string? workingDirectory)
+        {
+            foreach (var step in steps?.Items ?? new List<Node>())
+            {
+                var name = step.Text("name") ?? step.Text("id") ?? step.Text("uses") ?? FirstLine(step.Text("run")) ?? $"step {step.Line}";
+                var entry = new CiStep { Job = job, Name = name, Uses = step.Text("uses"), Run = step.Text("run"), Line = step.Line };
+                _pipeline.Steps.Add(entry);
+                AddVariables(step["env"], $"step {name}");
+
+                var directory = step.Text("working-directory") ?? workingDirectory;
+                if (step.Text("working-directory") is { } own)
+                    AddPath("directory", own, null, job, name, step["working-directory"]!.Line);
+                if (entry.Uses != null)
+                    AddUses(entry.Uses, job, name, step.Line, "action");
+                if (entry.Run != null)
+                    ReadScript(step["run"]!, directory, job, name, github: true, powershell: step.Text("shell") is "pwsh" or "powershell");
+                AddSecrets(step["with"], job, name);
+                AddSecrets(step["env"], job, name);
+            }
+        }
+
+        private void AddUses(string uses, string job, string step, int line, string kind)
+        {
+            // Only ./ references live in the workspace; owner/repo@ref and docker:// are fetched by the runner
+            if (!uses.StartsWith("./", StringComparison.Ordinal))
+                return;
+            var path = uses.Split('@')[0].TrimEnd('/');
+            AddPath(kind, path, null, job, step, line, fromRoot: true);
+        }
+
+        public void ReadAzure(Node root)
+        {
+            AddAzureVariables(root["variables"], "pipeline");
+            if (root["extends"]?.Text("template") is { } extends)
+                AddTemplate(extends, "pipeline", "extends", root["extends"]!.Line);
+            ReadAzureStages(root["stages"]);
+            ReadAzureJobs(root["jobs"], null);
+            ReadAzureSteps(root["steps"], "pipeline", null);
+        }
+
+        private void ReadAzureStages(Node? stages)
+        {
+            foreach (var stage in stages?.Items ?? new List<Node>())
+            {
+                if (stage.Text("template") is { } template)
+                {
+                    AddTemplate(template, "pipeline", "stage template", stage.Line);
+                    continue;
+                }
+                AddAzureVariables(stage["variables"], $"stage {stage.Text("stage")}");
+                ReadAzureJobs(stage["jobs"], stage.Text("stage"));
+            }
+        }
+
+        private void ReadAzureJobs(Node? jobs, string? stage)
+        {
+            foreach (var job in jobs?.Items ?? new List<Node>())
+            {
+                var id = job.Text("job") ?? job.Text("deployment") ?? "job";
+                if (stage != null)
+                    id = $"{stage}.{id}";
+                if (job.Text("template") is { } template)
+                {
+                    AddTemplate(template, id, "job template", job.Line);
+                    continue;
+                }
+                AddAzureVariables(job["variables"], $"job {id}");
+                var workingDirectory = job["workspace"]?.Text("workingDirectory");
+                ReadAzureSteps(job["steps"], id, workingDirectory);
+
+                // Deployment jobs keep their steps under strategy: runOnce/rolling/canary: deploy/preDeploy/...
+                foreach (var strategy in job["strategy"]?.Map?.Values ?? Enumerable.Empty<Node>())
+                {
+                    foreach (var hook in strategy.Map?.Values ?? Enumerable.Empty<Node>())
+                        ReadAzureSteps(hook["steps"], id, workingDirectory);
+                }
+            }
+        }
+
+        private void ReadAzureSteps(Node? steps, string job, string? workingDirectory)
+        {
+            foreach (var step in steps?.Items ?? new List<Node>())
+            {
+                if (step.Text("template") is { } template)
+                {
+                    AddTemplate(template, job, "step template", step.Line);
+                    continue;
+                }
+
+                var scriptKey = new[] { "script", "bash", "pwsh", "powershell" }.FirstOrDefault(k => step[k] != null);
+                var task = step.Text("task");
+                var inputs = step["inputs"];
+                var run = scriptKey != null ? step.Text(scriptKey) : inputs?.Text("script") ?? inputs?.Text("inlineScript");
+                var name = step.Text("displayName") ?? step.Text("name") ?? task ?? FirstLine(run) ?? (step.Text("checkout") is { } c ? $"checkout {c}" : $"step {step.Line}");
+                _pipeline.Steps.Add(new CiStep { Job = job, Name = name, Uses = task, Run = run, Line = step.Line });
+                AddAzureVariables(step["env"], $"step {name}");
+
+                var directory = step.Text("workingDirectory") ?? inputs?.Text("workingDirectory") ?? workingDirectory;
+                foreach (var key in new[] { "workingDirectory" })
+                {
+                    if (step[key] is { Value: { } value } node)
+                        AddPath("directory", value, null, job, name, node.Line);
+                    if (inputs?[key] is { Value: { } input } inputNode)
+                        AddPath("directory", input, null, job, name, inputNode.Line);
+                }
+                if ((scriptKey != null ? step[scriptKey] : inputs?["script"] ?? inputs?["inlineScript"]) is { Value: not null } script)
+                    ReadScript(script, directory, job, name, github: false,
+                        powershell: scriptKey is "pwsh" or "powershell" || scriptKey == null && task?.StartsWith("PowerShell", StringComparison.OrdinalIgnoreCase) == true);
+
+                if (inputs?.Map == null)
+                    continue;
+                foreach (var (key, input) in inputs.Map)
+                {
+                    if (input.Value is not { Length: > 0 } value)
+                        continue;
+                    switch (key)
+                    {
+                        case "scriptPath":
+                        case "filePath" or "filename" when ScriptTask.IsMatch(task ?? string.Empty):
+                            AddPath("script", value, directory, job, name, input.Line);
+                            break;
+                        case "projects" or "solution" or "restoreSolution" or "project" or "projectFile":
+                            foreach (var project in value.Split('\n', StringSplitOptions.RemoveEmptyEntries | StringSplitOptions.TrimEntries))
+                            {
+                                if (!project.StartsWith('!'))
+                                    AddPath("project", project, directory, job, name, input.Line);
+                            }
+                            break;
+                        case "arguments":
+                            AddEnvUses(value, job, name, input.Line, github: false);
+                            break;
+                    }
+                }
+            }
+        }
+
+        /// <summary>
+        /// Azure variables as a map, or a list of name/value pairs, groups and templates
+        /// </summary>
+        private void AddAzureVariables(Node? variables, string scope)
+        {
+            if (variables?.Items == null)
+            {
+                AddVariables(variables, scope);
+                return;
+            }
+            foreach (var item in variables.Items)
+            {
+                if (item.Text("name") is { } name)
+                    _pipeline.Variables.Add(new CiVariable { Name = name, Scope = scope, Value = item.Text("value"), FilePath = _pipeline.FilePath, Line = item.Line });
+                else if (item.Text("group") is { } group)
+                    _pipeline.VariableGroups.Add(group);
+                else if (item.Text("template") is { } template)
+                    AddTemplate(template, scope, "variable template", item.Line);
+            }
+        }
+
+        private void AddVariables(Node? variables, string scope)
+        {
+            foreach (var (name, value) in variables?.Map ?? new Dictionary<string, Node>())
+            {
+                if (name.StartsWith("${{", StringComparison.Ordinal))
+                    continue;
+                _pipeline.Variables.Add(new CiVariable { Name = name, Scope = scope, Value = value.Value, FilePath = _pipeline.FilePath, Line = value.Line });
+            }
+        }
+
+        private void AddSecrets(Node? values, string job, string step)
+        {
+            foreach (var value in values?.Map?.Values ?? Enumerable.Empty<Node>())
+            {
+                if (value.Value != null)
+                    AddEnvUses(value.Value, job, step, value.Line, github: true, expressionsOnly: true);
+            }
+        }
+
+        /// <summary>
+        /// Azure template paths are relative to the file that names them unless they start with /; @alias means
+        /// another repository
+        /// </summary>
+        private void AddTemplate(string template, string job, string step, int line)
+        {
+            if (template.Contains('@') || template.Contains("${{", StringComparison.Ordinal))
+                return;
+            var path = template.StartsWith('/')
+                ? template.TrimStart('/')
+                : Combine(_directory, template);
+            _pipeline.References.Add(new CiReference { Kind = "template", Value = template, Path = Normalize(path), Job = job, Step = step, FilePath = _pipeline.FilePath, Line = line });
+        }
+
+        private void ReadScript(Node script, string? workingDirectory, string job, string step, bool github, bool powershell)
+        {
+            var lines = script.Value!.Split('\n');
+            for (var offset = 0; offset < lines.Length; offset++)
+            {
+                var text = lines[offset].Trim();
+                var at = script.Line + offset + (script.Block ? 1 : 0);
+                if (text.Length == 0 || text.StartsWith('#') || text.StartsWith("REM ", StringComparison.OrdinalIgnoreCase))
+                    continue;
+
+                foreach (Match write in GitHubEnvWrite.Matches(text))
+                    _pipeline.Variables.Add(new CiVariable { Name = write.Groups["name"].Value, Scope = $"step {step}", Value = write.Groups["value"].Value.Trim(), FilePath = _pipeline.FilePath, Line = at });
+                foreach (Match set in AzureSetVariable.Matches(text))
+                    _pipeline.Variables.Add(new CiVariable { Name = set.Groups["name"].Value, Scope = $"step {step}", Value = set.Groups["value"].Value.Trim(), FilePath = _pipeline.FilePath, Line = at });
+                AddEnvUses(text, job, step, at, github, powershell);
+
+                foreach (var command in Regex.Split(text, @"\s*(?:&&|\|\||;|\|)\s*"))
+                    workingDirectory = ReadCommand(command, workingDirectory, job, step, at);
+            }
+        }
+
+        /// <summary>
+        /// Records the paths one command names and returns the working directory after it, so a cd carries on
+        /// to the commands that follow
+        /// </summary>
+        private string? ReadCommand(string command, string? workingDirectory, string job, string step, int line)
+        {
+            var tokens = Regex.Matches(command, @"""[^""]*""|'[^']*'|\S+").Select(m => m.Value.Trim('"', '\'')).ToList();
+            var first = 0;
+            while (first < tokens.Count && (tokens[first] is "sudo" or "exec" or "time" or "&" || Regex.IsMatch(tokens[first], @"^[A-Za-z_]\w*=")))
+                first++;
+            if (first >= tokens.Count || ShellKeywords.Contains(tokens[first]))
+                return workingDirectory;
+
+            var program = tokens[first];
+            if (program == "cd" && first + 1 < tokens.Count && tokens[first + 1] != "-")
+            {
+                AddPath("directory", tokens[first + 1], workingDirectory, job, step, line);
+                return Combine(workingDirectory ?? string.Empty, tokens[first + 1]);
+            }
+            if (program.Contains('/'))
+            {
+                AddPath("script", program, workingDirectory, job, step, line);
+            }
+
+            var interpreter = Interpreters.Contains(Path.GetFileNameWithoutExtension(program));
+            var scriptSeen = false;
+            for (var i = first + 1; i < tokens.Count; i++)
+            {
+                var token = tokens[i];
+                var previous = tokens[i - 1];
+                if (OutputFlags.Contains(previous) || token.StartsWith('>') || token.StartsWith("--output=", StringComparison.Ordinal) || token.StartsWith("-o=", StringComparison.Ordinal))
+                    continue;
+                if (token.StartsWith('-') || token.Contains("://", StringComparison.Ordinal) || token.StartsWith('@'))
+                    continue;
+
+                if (interpreter && !scriptSeen && (ScriptPath.IsMatch(token) || previous is "-File" or "-f" && program is "pwsh" or "powershell"))
+                {
+                    scriptSeen = true;
+                    AddPath("script", token, workingDirectory, job, step, line);
+                }
+                else if (previous is "--project" or "-p" && program is "dotnet" || ProjectPath.IsMatch(token))
+                {
+                    AddPath("project", token, workingDirectory, job, step, line);
+                }
+                else if (previous is "-C" or "--directory" or "--prefix" or "--cwd" or "-chdir")
+                {
+                    AddPath("directory", token, workingDirectory, job, step, line);
+                }
+                else if (FilePathToken.IsMatch(token) && !token.StartsWith("./...", StringComparison.Ordinal) && !token.StartsWith("refs/", StringComparison.Ordinal) && !token.EndsWith("/...", StringComparison.Ordinal))
+                {
+                    AddPath("file", token, workingDirectory, job, step, line);
+                }
+            }
+            return workingDirectory;
+        }
+
+        private void AddEnvUses(string text, string job, string step, int line, bool github, bool powershell = false, bool expressionsOnly = false)
+        {
+            void Add(string kind, string name)
+            {
+                if (!_pipeline.EnvUses.Any(u => u.Kind == kind && u.Value == name && u.Line == line && u.Step == step))
+                    _pipeline.EnvUses.Add(new CiReference { Kind = kind, Value = name, Job = job, Step = step, FilePath = _pipeline.FilePath, Line = line });
+            }
+
+            foreach (Match expression in Expression.Matches(text))
+                Add(expression.Groups["scope"].Value switch { "secrets" => "secret", "vars" => "setting", _ => "env" }, expression.Groups["name"].Value);
+            if (expressionsOnly)
+                return;
+
+            var script = Expression.Replace(text, string.Empty);
+            if (!github)
+            {
+                foreach (Match macro in AzureMacro.Matches(script))
+                    Add("var", macro.Groups["name"].Value);
+                script = AzureMacro.Replace(script, string.Empty);
+            }
+            // PowerShell's own $name variables are script locals; only $env:NAME reads the environment
+            if (!powershell)
+            {
+                foreach (Match variable in ShellVariable.Matches(PowerShellVariable.Replace(script, string.Empty)))
+                    Add("env", variable.Groups["name"].Value);
+            }
+            foreach (Match variable in PowerShellVariable.Matches(script))
+                Add("env", variable.Groups["name"].Value);
+            foreach (Match variable in CmdVariable.Matches(script))
+                Add("env", variable.Groups["name"].Value);
+        }
+
+        private void AddPath(string kind, string value, string? workingDirectory, string job, string step, int line, bool fromRoot = false)
+        {
+            // Runner tools and installed packages are not part of the repository
+            if (value.StartsWith('/') || value.StartsWith('~') || Regex.IsMatch(value, @"^[A-Za-z]:[\\/]")
+                || value.StartsWith("node_modules/", StringComparison.Ordinal) || value.Contains("/node_modules/", StringComparison.Ordinal))
+                return;
+            var path = Resolve(value, fromRoot ? null : workingDirectory);
+            if (_pipeline.References.Any(r => r.Kind == kind && r.Value == value && r.Step == step && r.Job == job))
+                return;
+            _pipeline.References.Add(new CiReference { Kind = kind, Value = value, Path = path, Job = job, Step = step, FilePath = _pipeline.FilePath, Line = line });
+        }
+
+        /// <summary>
+        /// The workspace-relative path a value names, or null when it depends on variables other than the workspace root
+        /// </summary>
+        private string? Resolve(string value, string? workingDirectory)
+        {
+            var path = value.Replace('\\', '/');
+            foreach (var root in new[] { "${{ github.workspace }}", "${{github.workspace}}", "$GITHUB_WORKSPACE", "${GITHUB_WORKSPACE}",
+                         "$(Build.SourcesDirectory)", "$(System.DefaultWorkingDirectory)", "$(Build.Repository.LocalPath)", "$(Pipeline.Workspace)/s" })
+            {
+                if (path.StartsWith(root, StringComparison.OrdinalIgnoreCase))
+                    return Normalize(path[root.Length..].TrimStart('/'));
+            }
+            foreach (var actionPath in new[] { "${{ github.action_path }}", "${{github.action_path}}", "$GITHUB_ACTION_PATH", "${GITHUB_ACTION_PATH}" })
+            {
+                if (path.StartsWith(actionPath, StringComparison.Ordinal))
+                    return Normalize(Combine(_directory, path[actionPath.Length..].TrimStart('/')));
+            }
+            if (path.Contains('$') || path.Contains('%') || path.StartsWith('/') || path.StartsWith('~') || Regex.IsMatch(path, @"^[A-Za-z]:"))
+                return null;
+            if (workingDirectory == null)
+                return Normalize(path);
+            var directory = Resolve(workingDirectory, null);
+            return directory == null ? null : Normalize(Combine(directory, path));
+        }
+    }
+
+    private static string Combine(string directory, string path) =>
+        directory.Length == 0 ? path : path.Length == 0 ? directory : directory.TrimEnd('/') + "/" + path;
+
+    /// <summary>
+    /// Collapses . and .. segments; a path that climbs out of the workspace keeps its leading ..
+    /// </summary>
+    private static string Normalize(string path)
+    {
+        var parts = new List<string>();
+        foreach (var part in path.Replace('\\', '/').Split('/', StringSplitOptions.RemoveEmptyEntries))
+        {
+            if (part == ".")
+                continue;
+            if (part == ".." && parts.Count > 0 && parts[^1] != "..")
+                parts.RemoveAt(parts.Count - 1);
+            else
+                parts.Add(part);
+        }
+        return string.Join("/", parts);
+    }
+
+    private static string? FirstLine(string? text) =>
+        text?.Split('\n').Select(l => l.Trim()).FirstOrDefault(l => l.Length > 0) is { } line
+            ? line.Length > 60 ? line[..57] + "..." : line
+            : null;
+
+    /// <summary>
+    /// Block YAML by indentation, as the OpenAPI reader does, except that block scalars are captured line by
+    /// line before list items are split so scripts keep their dashes and indentation
+    /// </summary>
+    private static Node ReadYaml(string[] lines)
+    {
+        var entries = new List<(int Indent, string Text, int Line, string? Block)>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i].TrimEnd();
+            var text = line.TrimStart();
+            if (text.Length == 0 || text.StartsWith('#') || text == "---" || text == "...")
+                continue;
+
+            var indent = line.Length - text.Length;
+            while (text == "-" || text.StartsWith("- ", StringComparison.Ordinal))
+            {
+                entries.Add((indent, "-", i + 1, null));
+                var rest = text[1..].TrimStart();
+                indent += text.Length - rest.Length;
+                text = rest;
+            }
+            if (text.Length == 0)
+                continue;
+
+            string? block = null;
+            var start = i + 1;
+            var key = YamlKey.Match(text);
+            if (key.Success && key.Groups["value"].Value.Trim() is { Length: > 0 } value && value[0] is '|' or '>')
+            {
+                var body = new List<string>();
+                var bodyIndent = -1;
+                while (i + 1 < lines.Length)
+                {
+                    var next = lines[i + 1].TrimEnd();
+                    var nextIndent = next.Length - next.TrimStart().Length;
+                    if (next.Trim().Length > 0 && nextIndent <= indent)
+                        break;
+                    i++;
+                    if (next.Trim().Length > 0 && bodyIndent < 0)
+                        bodyIndent = nextIndent;
+                    body.Add(next.Length > bodyIndent && bodyIndent >= 0 ? next[bodyIndent..] : next.Trim());
+                }
+                while (body.Count > 0 && body[^1].Length == 0)
+                    body.RemoveAt(body.Count - 1);
+                block = string.Join(value[0] == '|' ? "\n" : " ", body);
+            }
+            entries.Add((indent, text, start, block));
+        }
+
+        var position = 0;
+        return ReadBlock(entries, ref position, -1) ?? new Node();
+    }
+
+    private static Node? ReadBlock(List<(int Indent, string Text, int Line, string? Block)> entries, ref int i, int parentIndent)
+    {
+        if (i >= entries.Count || entries[i].Indent <= parentIndent)
+            return null;
+
+        var indent = entries[i].Indent;
+        if (entries[i].Text == "-")
+        {
+            var list = new Node { Items = new List<Node>(), Line = entries[i].Line };
+            while (i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-")
+            {
+                var line = entries[i++].Line;
+                var item = ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+                item.Line = line;
+                list.Items.Add(item);
+            }
+            return list;
+        }
+
+        var key = YamlKey.Match(entries[i].Text);
+        if (!key.Success)
+            return Scalar(entries[i].Text, entries[i++].Line);
+
+        var map = new Node { Map = new Dictionary<string, Node>(StringComparer.Ordinal), Line = entries[i].Line };
+        while (i < entries.Count && entries[i].Indent == indent)
+        {
+            key = YamlKey.Match(entries[i].Text);
+            var line = entries[i].Line;
+            var block = entries[i++].Block;
+            if (!key.Success)
+                continue;
+
+            var value = key.Groups["value"].Value.Trim();
+            Node child;
+            if (block != null)
+            {
+                child = new Node { Value = block, Block = true };
+            }
+            else if (value.Length == 0 || value.StartsWith('&'))
+            {
+                // A list may sit at its key's own indentation
+                child = i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-"
+                    ? ReadBlock(entries, ref i, indent - 1)!
+                    : ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+            }
+            else
+            {
+                child = Scalar(value, line);
+            }
+            child.Line = line;
+            map.Map[key.Groups["key"].Value.Trim()] = child;
+        }
+        return map;
+    }
+
+    private static Node Scalar(string text, int line)
+    {
+        if (text.StartsWith('[') && text.EndsWith(']'))
+        {
+            return new Node
+            {
+                Items = text[1..^1].Split(',').Select(v => v.Trim()).Where(v => v.Length > 0).Select(v => Scalar(v, line)).ToList(),
+                Line = line
+            };
+        }
+        if (text.StartsWith('{') && text.EndsWith('}') && !text.StartsWith("{{", StringComparison.Ordinal))
+        {
+            var map = new Dictionary<string, Node>(StringComparer.Ordinal);
+            foreach (var pair in text[1..^1].Split(','))
+            {
+                var colon = pair.IndexOf(':');
+                if (colon > 0)
+                    map[Unquote(pair[..colon].Trim())] = Scalar(pair[(colon + 1)..].Trim(), line);
+            }
+            return new Node { Map = map, Line = line };
+        }
+        if (text[0] is not ('"' or '\''))
+        {
+            var comment = text.IndexOf(" #", StringComparison.Ordinal);
+            if (comment >= 0)
+                text = text[..comment].TrimEnd();
+        }
+        return new Node { Value = Unquote(text), Line = line };
+    }
+
+    private static string Unquote(string text) =>
+        text.Length >= 2 && (text[0] == '"' && text[^1] == '"' || text[0] == '\'' && text[^1] == '\'') ? text[1..^1] : text;
+}
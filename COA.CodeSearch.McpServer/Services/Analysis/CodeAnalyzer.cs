@@ -19,12 +19,19 @@ public sealed class CodeAnalyzer : Analyzer
     private readonly bool _preserveCase;
     private readonly bool _splitCamelCase;
     private readonly LuceneVersion _version;
+    private readonly NormalizationForm? _normalization;
 
-    public CodeAnalyzer(LuceneVersion version, bool preserveCase = false, bool splitCamelCase = true)
+    /// <param name="version">Lucene version</param>
+    /// <param name="preserveCase">Keep the original case instead of folding it</param>
+    /// <param name="splitCamelCase">Also emit the camelCase/snake_case parts of identifiers</param>
+    /// <param name="normalization">Unicode normalization applied to every term (CodeSearch:Unicode:Normalization), null for none</param>
+    public CodeAnalyzer(LuceneVersion version, bool preserveCase = false, bool splitCamelCase = true,
+        NormalizationForm? normalization = NormalizationForm.FormC)
     {
         _version = version;
         _preserveCase = preserveCase;
         _splitCamelCase = splitCamelCase;
+        _normalization = normalization;
     }
 
     protected override TokenStreamComponents CreateComponents(string fieldName, TextReader reader)
@@ -51,11 +58,8 @@ public sealed class CodeAnalyzer : Analyzer
             stream = new CamelCaseFilter(stream);
         }
         
-        // Preserve or lowercase based on configuration
-        if (!_preserveCase)
-        {
-            stream = new LowerCaseFilter(_version, stream);
-        }
+        // Normalize, and fold case unless configured to preserve it
+        stream = new UnicodeFoldingFilter(stream, _normalization, foldCase: !_preserveCase);
         
         // Remove very short tokens (single characters except operators)
         stream = new CodeLengthFilter(stream, minLength: 1);
@@ -75,10 +79,7 @@ public sealed class CodeAnalyzer : Analyzer
         TokenStream stream = tokenizer;
         
         // Apply minimal processing to preserve patterns like "IRepository<T>", ": ITool"
-        if (!_preserveCase)
-        {
-            stream = new LowerCaseFilter(_version, stream);
-        }
+        stream = new UnicodeFoldingFilter(stream, _normalization, foldCase: !_preserveCase);
         
         // No length filtering - keep all tokens including short ones with special chars
         return new TokenStreamComponents(tokenizer, stream);
@@ -95,8 +96,8 @@ public sealed class CodeAnalyzer : Analyzer
             // Always split camelCase for symbol search
             stream = new CamelCaseFilter(stream);
             
-            // Always lowercase for symbol search consistency
-            stream = new LowerCaseFilter(_version, stream);
+            // Always fold case for symbol search consistency
+            stream = new UnicodeFoldingFilter(stream, _normalization, foldCase: true);
             
             // Filter out very short tokens and non-alphanumeric
             stream = new CodeLengthFilter(stream, minLength: 2);
@@ -381,7 +382,7 @@ public sealed class CodeTokenizer : Tokenizer
     
     private bool IsTokenChar(char c)
     {
-        return char.IsLetterOrDigit(c) || c == '_';
+        return UnicodeIdentifiers.IsIdentifierPart(c);
     }
     
     private bool IsOperatorChar(char c)
@@ -706,6 +707,45 @@ public sealed class CamelCaseFilter : TokenFilter
     }
 }
 
+/// <summary>
+/// Filter that normalizes terms (NFC/NFD) and folds case with <see cref="UnicodeIdentifiers.Fold"/>.
+/// Replaces Lucene's LowerCaseFilter so Turkish I variants and decomposed characters index the same
+/// way regardless of the machine's culture.
+/// </summary>
+public sealed class UnicodeFoldingFilter : TokenFilter
+{
+    private readonly NormalizationForm? _normalization;
+    private readonly bool _foldCase;
+    private readonly ICharTermAttribute _termAttr;
+
+    public UnicodeFoldingFilter(TokenStream input, NormalizationForm? normalization, bool foldCase) : base(input)
+    {
+        _normalization = normalization;
+        _foldCase = foldCase;
+        _termAttr = AddAttribute<ICharTermAttribute>();
+    }
+
+    public override bool IncrementToken()
+    {
+        if (!m_input.IncrementToken())
+        {
+            return false;
+        }
+
+        var term = _termAttr.ToString();
+        var processed = _foldCase
+            ? UnicodeIdentifiers.Fold(term, _normalization)
+            : UnicodeIdentifiers.Normalize(term, _normalization);
+
+        if (!string.Equals(term, processed, StringComparison.Ordinal))
+        {
+            _termAttr.SetEmpty().Append(processed);
+        }
+
+        return true;
+    }
+}
+
 /// <summary>
 /// Filter that removes very short tokens except for important operators.
 /// </summary>
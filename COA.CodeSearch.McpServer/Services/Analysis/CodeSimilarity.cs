@@ -0,0 +1,129 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Token-vector similarity for find_similar_code. Code is reduced to a bag of lowercased identifier
+/// words (camelCase and snake_case split), so <c>GetUserById</c> and <c>get_user_by_id</c> agree;
+/// vectors are compared by TF-IDF cosine with IDF taken from the candidate set.
+/// </summary>
+public static class CodeSimilarity
+{
+    /// <summary>
+    /// Bodies with fewer words than this are one-liners; everything looks like them
+    /// </summary>
+    public const int MinTokens = 6;
+
+    private static readonly Regex Identifier = new(UnicodeIdentifiers.IdentifierPattern, RegexOptions.Compiled);
+    private static readonly Regex StringLiteral = new(@"""(?:[^""\\\n]|\\.)*""|'(?:[^'\\\n]|\\.)*'|`[^`]*`", RegexOptions.Compiled);
+    private static readonly Regex LineComment = new(@"(?://|#).*$", RegexOptions.Compiled | RegexOptions.Multiline);
+
+    // Present in nearly every function of every language; they only add noise to the vector
+    private static readonly HashSet<string> Noise = new(StringComparer.Ordinal)
+    {
+        "public", "private", "protected", "internal", "static", "readonly", "const", "final", "async", "await",
+        "return", "var", "let", "val", "def", "func", "fn", "function", "void", "this", "self", "new", "true",
+        "false", "null", "nil", "none", "the", "to", "of", "in", "is", "and", "or", "not", "override", "virtual", "pub", "mut"
+    };
+
+    /// <summary>
+    /// Word counts for a piece of code. String literals and line comments are dropped; the
+    /// masked names (the symbol's own name) are left out so a renamed copy still matches.
+    /// </summary>
+    public static Dictionary<string, int> Vectorize(string code, IEnumerable<string?>? maskedNames = null)
+    {
+        var masked = new HashSet<string>(StringComparer.Ordinal);
+        foreach (var name in maskedNames ?? Enumerable.Empty<string?>())
+        {
+            if (!string.IsNullOrEmpty(name))
+                masked.Add(name);
+        }
+        var stripped = LineComment.Replace(StringLiteral.Replace(code, " "), " ");
+
+        var counts = new Dictionary<string, int>(StringComparer.Ordinal);
+        foreach (Match match in Identifier.Matches(stripped))
+        {
+            if (masked.Contains(match.Value))
+                continue;
+
+            foreach (var word in QuerySyntaxGuide.SplitWords(match.Value))
+            {
+                var lower = UnicodeIdentifiers.Fold(word);
+                if (lower.Length < 2 || Noise.Contains(lower))
+                    continue;
+                counts[lower] = counts.GetValueOrDefault(lower) + 1;
+            }
+        }
+
+        return counts;
+    }
+
+    /// <summary>
+    /// Smoothed inverse document frequency of each word over the given vectors
+    /// </summary>
+    public static Dictionary<string, double> InverseDocumentFrequency(IReadOnlyCollection<Dictionary<string, int>> vectors)
+    {
+        var documentFrequency = new Dictionary<string, int>(StringComparer.Ordinal);
+        foreach (var vector in vectors)
+        {
+            foreach (var word in vector.Keys)
+                documentFrequency[word] = documentFrequency.GetValueOrDefault(word) + 1;
+        }
+
+        return documentFrequency.ToDictionary(
+            kv => kv.Key,
+            kv => Math.Log((1.0 + vectors.Count) / (1.0 + kv.Value)) + 1.0,
+            StringComparer.Ordinal);
+    }
+
+    /// <summary>
+    /// TF-IDF cosine similarity (0-1). Words missing from <paramref name="idf"/> weigh as if seen once.
+    /// </summary>
+    public static double Cosine(Dictionary<string, int> a, Dictionary<string, int> b, IReadOnlyDictionary<string, double>? idf = null)
+    {
+        if (a.Count == 0 || b.Count == 0)
+            return 0;
+
+        var maxIdf = idf is { Count: > 0 } ? idf.Values.Max() : 1.0;
+        double Weight(string word, int count) => Math.Sqrt(count) * (idf?.GetValueOrDefault(word, maxIdf) ?? 1.0);
+
+        double dot = 0, normA = 0, normB = 0;
+        foreach (var (word, count) in a)
+        {
+            var weight = Weight(word, count);
+            normA += weight * weight;
+            if (b.TryGetValue(word, out var other))
+                dot += weight * Weight(word, other);
+        }
+        foreach (var (word, count) in b)
+        {
+            var weight = Weight(word, count);
+            normB += weight * weight;
+        }
+
+        return dot == 0 ? 0 : Math.Min(1.0, dot / Math.Sqrt(normA * normB));
+    }
+
+    /// <summary>
+    /// Shared words ordered by their contribution, for explaining a match
+    /// </summary>
+    public static List<string> SharedTerms(Dictionary<string, int> a, Dictionary<string, int> b, IReadOnlyDictionary<string, double>? idf, int max = 5) =>
+        a.Keys.Where(b.ContainsKey)
+            .OrderByDescending(w => (idf?.GetValueOrDefault(w, 1.0) ?? 1.0) * Math.Min(a[w], b[w]))
+            .ThenBy(w => w, StringComparer.Ordinal)
+            .Take(max)
+            .ToList();
+
+    /// <summary>
+    /// Words of the vector worth sending to the index to find candidate files. Length stands in for
+    /// rarity: long words ("invoice", "retry") say more than short ones ("id", "get")
+    /// </summary>
+    public static List<string> DistinctiveTerms(Dictionary<string, int> vector, int max = 12) =>
+        vector.Keys
+            .Where(w => w.Length >= 3)
+            .OrderByDescending(w => w.Length)
+            .ThenByDescending(w => vector[w])
+            .ThenBy(w => w, StringComparer.Ordinal)
+            .Take(max)
+            .ToList();
+}
@@ -0,0 +1,308 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Git;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A run of commented-out code, with what it declares and calls and, when git history is available, the
+/// commit that disabled it
+/// </summary>
+public class CommentedCodeBlock
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int StartLine { get; set; }
+    public int EndLine { get; set; }
+
+    /// <summary>
+    /// Lines that read as code rather than prose
+    /// </summary>
+    public int CodeLines { get; set; }
+
+    /// <summary>
+    /// The block with its comment markers and common indentation removed
+    /// </summary>
+    public string Code { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Brackets balance, so the block is a whole statement or declaration rather than a fragment
+    /// </summary>
+    public bool Complete { get; set; }
+
+    /// <summary>
+    /// Types, functions and methods the block declares
+    /// </summary>
+    public List<string> Declares { get; set; } = new();
+
+    /// <summary>
+    /// Functions the block calls
+    /// </summary>
+    public List<string> Calls { get; set; } = new();
+
+    /// <summary>
+    /// Declared names that live code now declares - the block was likely replaced
+    /// </summary>
+    public List<string> DeclaredElsewhere { get; set; } = new();
+
+    /// <summary>
+    /// Called names nothing in the index declares any more (library calls included)
+    /// </summary>
+    public List<string> UnresolvedCalls { get; set; } = new();
+
+    public string? DisabledCommit { get; set; }
+    public string? DisabledBy { get; set; }
+    public DateTime? DisabledAt { get; set; }
+
+    /// <summary>
+    /// First line of the disabling commit's message
+    /// </summary>
+    public string? DisabledIn { get; set; }
+
+    public int? AgeDays { get; set; }
+
+    /// <summary>
+    /// delete or review
+    /// </summary>
+    public string Recommendation { get; set; } = "review";
+
+    public string Reason { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Finds commented-out code: runs of line comments (and C-style block comments) whose lines read as code -
+/// statements, calls, assignments, braces - rather than prose. Doc comments, compiler directives and license
+/// headers are prose or skipped outright. <see cref="Assess"/> weighs a block against the index and its
+/// blame to recommend deleting or reviewing it.
+/// </summary>
+public static class CommentedCode
+{
+    private static readonly HashSet<string> SlashComments = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".java", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".go", ".rs", ".kt", ".kts", ".swift", ".scala", ".sc",
+        ".c", ".h", ".cpp", ".cc", ".cxx", ".hpp", ".hh", ".php", ".dart", ".zig", ".groovy", ".gradle"
+    };
+    private static readonly HashSet<string> HashComments = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".py", ".rb", ".sh", ".bash", ".pl", ".r", ".ps1", ".ex", ".exs", ".tf", ".gd"
+    };
+    private static readonly HashSet<string> DashComments = new(StringComparer.OrdinalIgnoreCase) { ".sql", ".lua", ".hs" };
+
+    // Doc comments and directives look like comments but are neither prose nor disabled code
+    private static readonly Regex Directive = new(
+        @"^(?://[/!]|//\s*(?:go:|\+build|#(?:region|endregion)|eslint|prettier|@ts-|nolint|NOSONAR|swiftlint|ReSharper)|#!|#\s*(?:type:|noqa|pylint:|-\*-|frozen_string_literal|rubocop:)|---)",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex CodeStart = new(
+        @"^(?:if|else|elif|for|foreach|while|switch|case|return|var|let|const|val|def|class|struct|interface|enum|func|fn|function|public|private|protected|internal|static|try|catch|except|finally|throw|raise|await|yield|#include)\b",
+        RegexOptions.Compiled);
+    private static readonly Regex Import = new(@"^(?:(?:import|using|package|require)\s+[\w.""'/@-]+;?|from\s+[\w.]+\s+import\b.*)$", RegexOptions.Compiled);
+    private static readonly Regex CodePunctuation = new(@"[(){};=:\[\]<>""']", RegexOptions.Compiled);
+    private static readonly Regex Sentence = new(@"^\w+(?:\s+[a-z]+){3,}.*[.!?]$", RegexOptions.Compiled);
+    private static readonly Regex CodeEnd = new(@"(?:[;{}()\[\],]|=>|->|\)\s*:|\belse\s*:|\btry\s*:)\s*$", RegexOptions.Compiled);
+    private static readonly Regex Assignment = new(@"^[\w.\[\]""'$@]+\s*(?:[-+*/%|&]?=|:=)\s*[^=\s]", RegexOptions.Compiled);
+    private static readonly Regex Call = new(@"^[\w.$@:<>]+\s*\(.*\)\s*;?$", RegexOptions.Compiled);
+    private static readonly Regex Prose = new(
+        @"^(?:(?:TODO|FIXME|NOTE|HACK|XXX|BUG|SAFETY|WARNING)\b|[A-Za-z][\w'’-]*(?:,?\s+[\w'’(),/-]+){3,}[.?!:]?$)", RegexOptions.Compiled);
+    private static readonly Regex License = new(@"\b(?:copyright|license|licensed|spdx|all rights reserved)\b", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    /// <summary>
+    /// Whether a file's comments can be read: its extension has line comments this finder knows
+    /// </summary>
+    public static bool Supports(string filePath)
+    {
+        var extension = Path.GetExtension(filePath);
+        return SlashComments.Contains(extension) || HashComments.Contains(extension) || DashComments.Contains(extension);
+    }
+
+    /// <summary>
+    /// The commented-out code blocks of a file with at least <paramref name="minLines"/> code lines, in file order
+    /// </summary>
+    public static List<CommentedCodeBlock> Find(string filePath, string content, int minLines)
+    {
+        var extension = Path.GetExtension(filePath);
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var blocks = new List<CommentedCodeBlock>();
+
+        var i = 0;
+        while (i < lines.Length)
+        {
+            var (start, end, body) = Run(lines, i, extension);
+            if (body == null)
+            {
+                i++;
+                continue;
+            }
+            if (Read(filePath, extension, start, body) is { } block && block.CodeLines >= minLines)
+                blocks.Add(block);
+            i = end + 1;
+        }
+        return blocks;
+    }
+
+    /// <summary>
+    /// Weighs a block against live code and its blame: replaced blocks, fragments and blocks disabled longer than
+    /// <paramref name="staleDays"/> ago are worth deleting; recent, complete ones are worth a look before they go
+    /// </summary>
+    public static void Assess(CommentedCodeBlock block, IReadOnlySet<string> declared, IReadOnlyList<BlameLine> blame, int staleDays, DateTime now)
+    {
+        block.DeclaredElsewhere = block.Declares.Where(declared.Contains).ToList();
+        block.UnresolvedCalls = block.Calls.Where(c => !declared.Contains(c)).ToList();
+
+        // The commit that commented the block out touched most of its lines; later edits touch a few
+        var disabling = blame
+            .GroupBy(b => b.Commit)
+            .OrderByDescending(g => g.Count())
+            .ThenByDescending(g => g.First().AuthorTime)
+            .FirstOrDefault()?.First();
+        if (disabling != null)
+        {
+            block.DisabledCommit = disabling.Commit.Length > 12 ? disabling.Commit[..12] : disabling.Commit;
+            block.DisabledBy = disabling.AuthorName;
+            block.DisabledAt = disabling.AuthorTime;
+            block.DisabledIn = disabling.Summary;
+            block.AgeDays = Math.Max(0, (int)(now - disabling.AuthorTime).TotalDays);
+        }
+
+        var reasons = new List<string>();
+        if (block.DeclaredElsewhere.Count > 0)
+            reasons.Add($"{string.Join(", ", block.DeclaredElsewhere)} is declared in live code now - the block was replaced");
+        if (!block.Complete)
+            reasons.Add("brackets don't balance - it is a fragment that won't compile on its own");
+        if (block.AgeDays > staleDays)
+            reasons.Add($"disabled {block.AgeDays} days ago and never restored");
+
+        if (reasons.Count > 0)
+        {
+            block.Recommendation = "delete";
+            block.Reason = string.Join("; ", reasons);
+        }
+        else
+        {
+            block.Recommendation = "review";
+            block.Reason = block.AgeDays == null
+                ? "no history for the block - check whether it is still wanted"
+                : $"disabled {block.AgeDays} days ago; the history may show whether it was meant to come back";
+        }
+    }
+
+    /// <summary>
+    /// The comment run starting at a line: consecutive line comments, or a /* */ block that isn't a doc comment.
+    /// Body is null when the line doesn't start one.
+    /// </summary>
+    private static (int Start, int End, List<string>? Body) Run(string[] lines, int index, string extension)
+    {
+        var trimmed = lines[index].TrimStart();
+        if (SlashComments.Contains(extension) && trimmed.StartsWith("/*", StringComparison.Ordinal) && !trimmed.StartsWith("/**", StringComparison.Ordinal))
+        {
+            var body = new List<string>();
+            for (var i = index; i < lines.Length; i++)
+            {
+                var text = i == index ? trimmed[2..] : lines[i];
+                var close = text.IndexOf("*/", StringComparison.Ordinal);
+                var line = close < 0 ? text : text[..close];
+                body.Add(Regex.Replace(line, @"^\s*\*(?!/)\s?", string.Empty));
+                if (close >= 0)
+                    return close + 2 < text.Length && text[(close + 2)..].Trim().Length > 0 ? (index, i, null) : (index, i, body);
+            }
+            return (index, lines.Length - 1, null);
+        }
+
+        var marker = Marker(extension);
+        if (marker == null || !trimmed.StartsWith(marker, StringComparison.Ordinal) || Directive.IsMatch(trimmed))
+            return (index, index, null);
+
+        var run = new List<string>();
+        var end = index;
+        for (var i = index; i < lines.Length; i++)
+        {
+            var text = lines[i].TrimStart();
+            if (!text.StartsWith(marker, StringComparison.Ordinal) || Directive.IsMatch(text))
+                break;
+            run.Add(lines[i][(lines[i].IndexOf(marker, StringComparison.Ordinal) + marker.Length)..]);
+            end = i;
+        }
+        return (index, end, run);
+    }
+
+    private static CommentedCodeBlock? Read(string filePath, string extension, int start, List<string> body)
+    {
+        if (body.Any(l => License.IsMatch(l)))
+            return null;
+
+        // Code lines form the block; prose lines at either end are the comment explaining it
+        var kinds = body.Select(l => Classify(l.Trim())).ToList();
+        var first = kinds.FindIndex(k => k == true);
+        var last = kinds.FindLastIndex(k => k == true);
+        if (first < 0)
+            return null;
+
+        var code = kinds.Skip(first).Take(last - first + 1).Count(k => k == true);
+        var prose = kinds.Skip(first).Take(last - first + 1).Count(k => k == false);
+        if (code < 2 * prose)
+            return null;
+
+        var kept = body.Skip(first).Take(last - first + 1).ToList();
+        var indent = kept.Where(l => l.Trim().Length > 0).Select(l => l.Length - l.TrimStart().Length).DefaultIfEmpty(0).Min();
+        kept = kept.Select(l => l.Length >= indent ? l[indent..].TrimEnd() : l.Trim()).ToList();
+
+        var maskExtension = DashComments.Contains(extension) ? ".sql" : extension;
+        var masked = DataFlowScanner.MaskLines(kept, maskExtension);
+        var calls = new List<string>();
+        foreach (var line in masked)
+        {
+            foreach (var callee in DataFlowScanner.CallsIn(line, 0, line.Length).Select(DataFlowScanner.LastSegment))
+            {
+                if (!calls.Contains(callee) && !CodeStart.IsMatch(callee))
+                    calls.Add(callee);
+            }
+        }
+
+        var source = string.Join("\n", kept);
+        var declares = DeclarationScanner.Supports(filePath)
+            ? DeclarationScanner.Scan(source, filePath).Select(d => d.Name).Distinct().ToList()
+            : new List<string>();
+
+        return new CommentedCodeBlock
+        {
+            FilePath = filePath,
+            StartLine = start + first + 1,
+            EndLine = start + last + 1,
+            CodeLines = code,
+            Code = source,
+            Complete = Balanced(masked),
+            Declares = declares,
+            Calls = calls.Where(c => !declares.Contains(c)).ToList()
+        };
+    }
+
+    /// <summary>
+    /// true for a line that reads as code, false for prose, null for blank or undecided lines
+    /// </summary>
+    private static bool? Classify(string line)
+    {
+        if (line.Length == 0)
+            return null;
+        if (line is "{" or "}" or "};" or ")" or "]" or "end" or "else" or "pass" or "break" or "continue" || Import.IsMatch(line))
+            return true;
+        if (CodeStart.IsMatch(line) && (CodePunctuation.IsMatch(line) || !line.Contains(' ')))
+            return !Sentence.IsMatch(line);
+        if (CodeEnd.IsMatch(line) || Assignment.IsMatch(line) || Call.IsMatch(line))
+            return !Prose.IsMatch(line) || line.EndsWith(';') || line.EndsWith('{');
+        return Prose.IsMatch(line) || Regex.IsMatch(line, @"^\p{L}") ? false : null;
+    }
+
+    private static bool Balanced(IEnumerable<string> masked)
+    {
+        var depth = 0;
+        foreach (var c in masked.SelectMany(l => l))
+        {
+            if (c is '(' or '[' or '{')
+                depth++;
+            else if (c is ')' or ']' or '}' && --depth < 0)
+                return false;
+        }
+        return depth == 0;
+    }
+
+    private static string? Marker(string extension) =>
+        SlashComments.Contains(extension) ? "//" : HashComments.Contains(extension) ? "#" : DashComments.Contains(extension) ? "--" : null;
+}
@@ -0,0 +1,392 @@
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A key in a JSON or YAML file with the path of keys leading to it
+/// </summary>
+public class ConfigKeyEntry
+{
+    /// <summary>
+    /// The key path as written in queries: server.timeout, spec.containers[0].image
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The path split into keys and [n] sequence indexes
+    /// </summary>
+    public List<string> Segments { get; set; } = new();
+
+    /// <summary>
+    /// The scalar value as written, or null for a mapping or sequence
+    /// </summary>
+    public string? Value { get; set; }
+
+    public int Line { get; set; }
+
+    /// <summary>
+    /// 0-based column of the key
+    /// </summary>
+    public int Column { get; set; }
+}
+
+/// <summary>
+/// A parsed path:KEY.PATH=VALUE query. Segments may be * (any one key or index) or ** (any number of them);
+/// VALUE may be empty (any value) and may use * wildcards
+/// </summary>
+public class ConfigKeyQuery
+{
+    public List<string> Segments { get; set; } = new();
+    public string Value { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Set by a leading $. - the path starts at the document root instead of at any depth
+    /// </summary>
+    public bool Anchored { get; set; }
+}
+
+/// <summary>
+/// Reads the nested keys of JSON and YAML files (appsettings.json, Helm values, docker-compose files) and matches
+/// them against path:KEY.PATH queries, so path:server.timeout finds the timeout under server wherever the nesting
+/// puts it rather than every line containing "timeout". ASP.NET-style keys written flat (Logging:LogLevel) count
+/// as nested. YAML is read line by line: block scalars are skipped and flow collections are kept as values.
+/// </summary>
+public static class ConfigKeyPaths
+{
+    public const string QueryPrefix = "path:";
+
+    private const long MaxFileBytes = 2 * 1024 * 1024;
+
+    private static readonly HashSet<string> LockFiles = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "package-lock.json", "npm-shrinkwrap.json", "packages.lock.json", "composer.lock", "pnpm-lock.yaml", "yarn.lock"
+    };
+
+    private static readonly Regex YamlKey = new(
+        @"^(?<key>""(?:[^""\\]|\\.)*""|'[^']*'|[^\s#'""{\[\-?][^#]*?|-[^\s#][^#]*?)\s*:(?:\s+(?<value>.*?)|)\s*$", RegexOptions.Compiled);
+    private static readonly Regex QuerySyntax = new(@"^path:(?<path>[^=]*)(?:=(?<value>.*))?$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex PathSegment = new(@"\[(?<index>[^\]]*)\]|(?<key>[^.\[\]]+)", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True when the query uses the path: prefix
+    /// </summary>
+    public static bool IsKeyPathQuery(string query) => query.TrimStart().StartsWith(QueryPrefix, StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// True for the JSON and YAML files <see cref="Scan"/> reads; lock files are left out
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        Path.GetExtension(filePath).ToLowerInvariant() is ".json" or ".jsonc" or ".yaml" or ".yml"
+        && !LockFiles.Contains(Path.GetFileName(filePath));
+
+    /// <summary>
+    /// True when a file is too large to be configuration worth scanning
+    /// </summary>
+    public static bool IsTooLarge(long bytes) => bytes > MaxFileBytes;
+
+    /// <summary>
+    /// Parses path:server.timeout, path:services.*.image, path:**.timeout=30s or path:$.spec.replicas; the path:
+    /// prefix is optional
+    /// </summary>
+    public static bool TryParseQuery(string query, out ConfigKeyQuery parsed)
+    {
+        var text = query.Trim();
+        if (!IsKeyPathQuery(text))
+            text = QueryPrefix + text;
+
+        parsed = new ConfigKeyQuery();
+        var match = QuerySyntax.Match(text);
+        if (!match.Success)
+            return false;
+
+        var path = match.Groups["path"].Value.Trim();
+        if (path.StartsWith("$.", StringComparison.Ordinal) || path == "$")
+        {
+            parsed.Anchored = true;
+            path = path.TrimStart('$').TrimStart('.');
+        }
+        parsed.Segments = SplitPath(path);
+        parsed.Value = match.Groups["value"].Value.Trim().Trim('"', '\'');
+        return parsed.Segments.Count > 0;
+    }
+
+    /// <summary>
+    /// Every key in a JSON or YAML file, in file order
+    /// </summary>
+    public static List<ConfigKeyEntry> Scan(string filePath, string content) =>
+        Path.GetExtension(filePath).ToLowerInvariant() is ".json" or ".jsonc" ? ScanJson(content) : ScanYaml(content);
+
+    /// <summary>
+    /// True when the entry's path ends with the query's segments (starts with them too when anchored) and its
+    /// value matches
+    /// </summary>
+    public static bool Match(ConfigKeyEntry entry, ConfigKeyQuery query, bool caseSensitive)
+    {
+        var pattern = query.Anchored ? query.Segments : new[] { "**" }.Concat(query.Segments).ToList();
+        if (!MatchSegments(pattern, 0, entry.Segments, 0, caseSensitive))
+            return false;
+
+        if (query.Value.Length == 0)
+            return true;
+        return entry.Value != null
+            && Regex.IsMatch(entry.Value.Trim('"', '\''), "^" + Regex.Escape(query.Value).Replace(@"\*", ".*") + "$",
+                caseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase);
+    }
+
+    private static bool MatchSegments(IReadOnlyList<string> pattern, int p, IReadOnlyList<string> segments, int s, bool caseSensitive)
+    {
+        if (p == pattern.Count)
+            return s == segments.Count;
+
+        if (pattern[p] == "**")
+        {
+            for (var skip = s; skip <= segments.Count; skip++)
+            {
+                if (MatchSegments(pattern, p + 1, segments, skip, caseSensitive))
+                    return true;
+            }
+            return false;
+        }
+
+        if (s == segments.Count)
+            return false;
+
+        var matches = pattern[p] is "*" or "[*]"
+            || pattern[p].Equals(segments[s], caseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase);
+        return matches && MatchSegments(pattern, p + 1, segments, s + 1, caseSensitive);
+    }
+
+    /// <summary>
+    /// server.timeout → [server, timeout]; containers[0].image and containers.0.image → [containers, [0], image]
+    /// </summary>
+    private static List<string> SplitPath(string path) =>
+        PathSegment.Matches(path)
+            .Select(m => m.Groups["index"].Success ? $"[{m.Groups["index"].Value}]"
+                : m.Value.All(char.IsDigit) ? $"[{m.Value}]"
+                : m.Value.Trim())
+            .Where(s => s.Length > 0)
+            .ToList();
+
+    private static string Display(IEnumerable<string> segments)
+    {
+        var builder = new StringBuilder();
+        foreach (var segment in segments)
+        {
+            if (builder.Length > 0 && !segment.StartsWith('['))
+                builder.Append('.');
+            builder.Append(segment);
+        }
+        return builder.ToString();
+    }
+
+    private static List<ConfigKeyEntry> ScanJson(string content)
+    {
+        var entries = new List<ConfigKeyEntry>();
+        var bytes = Encoding.UTF8.GetBytes(content);
+        var lineStarts = new List<int> { 0 };
+        for (var i = 0; i < bytes.Length; i++)
+        {
+            if (bytes[i] == '\n')
+                lineStarts.Add(i + 1);
+        }
+
+        var reader = new Utf8JsonReader(bytes, new JsonReaderOptions { CommentHandling = JsonCommentHandling.Skip, AllowTrailingCommas = true });
+        var path = new List<List<string>>();
+        var arrayIndexes = new Stack<int>();
+        var containers = new Stack<bool>();
+        List<string>? pendingKey = null;
+        var pendingStart = 0L;
+
+        try
+        {
+            while (reader.Read())
+            {
+                switch (reader.TokenType)
+                {
+                    case JsonTokenType.PropertyName:
+                        // appsettings keys written flat ("Logging:LogLevel") nest like their nested form
+                        pendingKey = reader.GetString()!.Split(':').ToList();
+                        pendingStart = reader.TokenStartIndex;
+                        continue;
+
+                    case JsonTokenType.EndObject:
+                    case JsonTokenType.EndArray:
+                        if (containers.Count > 0 && containers.Pop())
+                            arrayIndexes.Pop();
+                        if (path.Count > 0)
+                            path.RemoveAt(path.Count - 1);
+                        continue;
+                }
+
+                // A value: named by the pending key, or by its index inside an array
+                var inArray = containers.Count > 0 && containers.Peek();
+                List<string>? segment = null;
+                var start = reader.TokenStartIndex;
+                if (inArray)
+                {
+                    var index = arrayIndexes.Pop();
+                    arrayIndexes.Push(index + 1);
+                    segment = new List<string> { $"[{index}]" };
+                }
+                else if (pendingKey != null)
+                {
+                    segment = pendingKey;
+                    start = pendingStart;
+                }
+                pendingKey = null;
+
+                var container = reader.TokenType is JsonTokenType.StartObject or JsonTokenType.StartArray;
+                if (segment != null && (!inArray || !container))
+                {
+                    var segments = path.SelectMany(s => s).Concat(segment).ToList();
+                    var line = lineStarts.BinarySearch((int)start);
+                    line = line >= 0 ? line : ~line - 1;
+                    entries.Add(new ConfigKeyEntry
+                    {
+                        Path = Display(segments),
+                        Segments = segments,
+                        Value = container ? null : Encoding.UTF8.GetString(reader.ValueSpan),
+                        Line = line + 1,
+                        Column = Encoding.UTF8.GetCharCount(bytes, lineStarts[line], (int)start - lineStarts[line])
+                    });
+                }
+
+                if (container)
+                {
+                    path.Add(segment ?? new List<string>());
+                    containers.Push(reader.TokenType == JsonTokenType.StartArray);
+                    if (reader.TokenType == JsonTokenType.StartArray)
+                        arrayIndexes.Push(0);
+                }
+            }
+        }
+        catch (JsonException)
+        {
+            // Keep the keys read before the syntax error
+        }
+
+        return entries;
+    }
+
+    private static List<ConfigKeyEntry> ScanYaml(string content)
+    {
+        var entries = new List<ConfigKeyEntry>();
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var frames = new List<(int Indent, string Segment, bool Item)>();
+        var nextIndex = new Dictionary<string, int>(StringComparer.Ordinal);
+        var blockIndent = -1;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            var trimmed = line.Trim();
+            var indent = line.Length - line.TrimStart().Length;
+
+            // Lines of a | or > block scalar belong to the key above them
+            if (blockIndent >= 0)
+            {
+                if (trimmed.Length == 0 || indent > blockIndent)
+                    continue;
+                blockIndent = -1;
+            }
+
+            if (trimmed.Length == 0 || trimmed.StartsWith('#') || trimmed.StartsWith("{{", StringComparison.Ordinal) || trimmed.StartsWith('%'))
+                continue;
+            if (trimmed == "---" || trimmed.StartsWith("--- ", StringComparison.Ordinal) || trimmed == "...")
+            {
+                frames.Clear();
+                nextIndex.Clear();
+                continue;
+            }
+
+            var column = indent;
+            var text = line[indent..];
+            while (true)
+            {
+                if (text == "-" || text.StartsWith("- ", StringComparison.Ordinal))
+                {
+                    // A sequence item: a sibling of the item before it, or the first under the key above
+                    frames.RemoveAll(f => f.Indent > column || f.Indent == column && f.Item);
+                    var parent = Display(frames.Select(f => f.Segment));
+                    var index = nextIndex.GetValueOrDefault(parent);
+                    nextIndex[parent] = index + 1;
+                    frames.Add((column, $"[{index}]", true));
+
+                    var rest = text.Length > 1 ? text[1..] : string.Empty;
+                    var offset = rest.Length - rest.TrimStart().Length;
+                    text = rest.TrimStart();
+                    column += 1 + offset;
+                    if (text.Length == 0 || text.StartsWith('#'))
+                        break;
+                    if (text.StartsWith("- ", StringComparison.Ordinal) || YamlKey.IsMatch(text))
+                        continue;
+
+                    var itemSegments = frames.Select(f => f.Segment).ToList();
+                    entries.Add(new ConfigKeyEntry
+                    {
+                        Path = Display(itemSegments),
+                        Segments = itemSegments,
+                        Value = StripComment(text),
+                        Line = i + 1,
+                        Column = column
+                    });
+                    break;
+                }
+
+                var key = YamlKey.Match(text);
+                if (!key.Success)
+                    break;
+
+                frames.RemoveAll(f => f.Indent >= column);
+                var name = key.Groups["key"].Value.Trim();
+                if (name.Length > 1 && (name[0] == '"' || name[0] == '\'') && name[^1] == name[0])
+                    name = name[1..^1];
+                var value = key.Groups["value"].Success ? StripComment(key.Groups["value"].Value) : null;
+                if (value is { Length: > 0 } && (value[0] == '|' || value[0] == '>'))
+                {
+                    blockIndent = column;
+                }
+
+                frames.Add((column, name, false));
+                var segments = frames.Select(f => f.Segment).ToList();
+                nextIndex.Remove(Display(segments));
+                entries.Add(new ConfigKeyEntry
+                {
+                    Path = Display(segments),
+                    Segments = segments,
+                    Value = string.IsNullOrEmpty(value) ? null : value,
+                    Line = i + 1,
+                    Column = column
+                });
+                break;
+            }
+        }
+
+        return entries;
+    }
+
+    /// <summary>
+    /// A YAML value without its trailing comment; # inside quotes stays
+    /// </summary>
+    private static string StripComment(string value)
+    {
+        var quote = '\0';
+        for (var i = 0; i < value.Length; i++)
+        {
+            var c = value[i];
+            if (quote != '\0')
+            {
+                if (c == quote)
+                    quote = '\0';
+                continue;
+            }
+            if (c is '"' or '\'' && (i == 0 || char.IsWhiteSpace(value[i - 1])))
+                quote = c;
+            else if (c == '#' && (i == 0 || char.IsWhiteSpace(value[i - 1])))
+                return value[..i].TrimEnd();
+        }
+        return value.Trim();
+    }
+}
@@ -0,0 +1,810 @@
+using System.Globalization;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A conditional whose value is fixed by the configured constants, and the branch that can never run
+/// </summary>
+public class ConstantBranch
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Line of the condition
+    /// </summary>
+    public int Line { get; set; }
+
+    /// <summary>
+    /// if, else if, elif, while, #if, #elif, #ifdef or #ifndef
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Condition { get; set; } = string.Empty;
+
+    /// <summary>
+    /// What the condition always evaluates to
+    /// </summary>
+    public bool Value { get; set; }
+
+    /// <summary>
+    /// Configured constants the condition depends on
+    /// </summary>
+    public List<string> Constants { get; set; } = new();
+
+    /// <summary>
+    /// "then", "else" or "body" (loops); null when nothing is dead and the condition is only redundant
+    /// </summary>
+    public string? DeadBranch { get; set; }
+
+    public int? DeadStartLine { get; set; }
+    public int? DeadEndLine { get; set; }
+
+    public string Suggestion { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Finds conditionals that known constants (feature flags, build symbols) make always true or false. Conditions
+/// are evaluated with three-valued logic, so <c>Flags.NewCheckout &amp;&amp; user.IsAdmin</c> is always false when
+/// the flag is off even though the user is unknown. Brace languages, Python indentation and C-family
+/// preprocessor directives are understood; anything else is left alone.
+/// </summary>
+public static class ConstantConditions
+{
+    private static readonly HashSet<string> BraceExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".java", ".js", ".jsx", ".ts", ".tsx", ".mjs", ".go", ".rs", ".c", ".h", ".cpp", ".cc", ".hpp", ".m", ".mm",
+        ".kt", ".kts", ".swift", ".php", ".dart", ".scala", ".groovy"
+    };
+
+    // Languages where the condition has no parentheses and runs up to the block's '{'
+    private static readonly HashSet<string> ParenlessExtensions = new(StringComparer.OrdinalIgnoreCase) { ".go", ".rs", ".swift" };
+
+    private static readonly HashSet<string> PreprocessorExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".c", ".h", ".cpp", ".cc", ".hpp", ".m", ".mm"
+    };
+
+    private static readonly Regex Keyword = new(@"(?<![\w$.])(?<kw>else\s+if|elif|if|while)\b", RegexOptions.Compiled);
+    private static readonly Regex Directive = new(@"^\s*#\s*(?<kw>if|elif|ifdef|ifndef|else|endif)\b(?<expr>.*)$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Parses "NAME=value" settings. Values true/on/yes/enabled and false/off/no/disabled are booleans, numbers
+    /// are numbers, anything else a string.
+    /// </summary>
+    /// <returns>False with the offending entry when one has no '=' or an empty name</returns>
+    public static bool TryParseConstants(IEnumerable<string> entries, out Dictionary<string, string> constants, out string? invalid)
+    {
+        constants = new Dictionary<string, string>(StringComparer.Ordinal);
+        invalid = null;
+        foreach (var entry in entries)
+        {
+            var equals = entry.IndexOf('=');
+            var name = equals > 0 ? entry[..equals].Trim() : string.Empty;
+            if (name.Length == 0)
+            {
+                invalid = entry;
+                return false;
+            }
+            constants[name] = entry[(equals + 1)..].Trim();
+        }
+        return true;
+    }
+
+    /// <summary>
+    /// Conditionals in a file whose value the constants decide
+    /// </summary>
+    public static List<ConstantBranch> Scan(string filePath, IReadOnlyList<string> lines, IReadOnlyDictionary<string, string> constants)
+    {
+        var extension = Path.GetExtension(filePath);
+        var result = new List<ConstantBranch>();
+        if (!BraceExtensions.Contains(extension) && !extension.Equals(".py", StringComparison.OrdinalIgnoreCase))
+            return result;
+
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+        for (var line = 0; line < lines.Count; line++)
+        {
+            if (PreprocessorExtensions.Contains(extension) && Directive.Match(masked[line]) is { Success: true } directive)
+            {
+                if (ScanDirective(filePath, masked, line, directive, constants) is { } branch)
+                    result.Add(branch);
+                continue;
+            }
+
+            foreach (Match keyword in Keyword.Matches(masked[line]))
+            {
+                if (ScanConditional(filePath, lines, masked, line, keyword, extension, constants) is { } branch)
+                    result.Add(branch);
+            }
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// Evaluates a condition against the constants: true or false when they decide it, null otherwise.
+    /// Names of the constants it used are added to <paramref name="used"/>.
+    /// </summary>
+    public static bool? Evaluate(string condition, IReadOnlyDictionary<string, string> constants, ICollection<string> used, bool preprocessor = false)
+    {
+        var tokens = Tokenize(condition);
+        if (tokens == null)
+            return null;
+
+        var parser = new Parser(tokens, constants, preprocessor);
+        var value = parser.ParseOr();
+        if (!parser.AtEnd)
+            return null;
+
+        foreach (var name in parser.Used)
+        {
+            if (!used.Contains(name))
+                used.Add(name);
+        }
+        return parser.Used.Count > 0 ? Truth(value) : null;
+    }
+
+    private static ConstantBranch? ScanConditional(string filePath, IReadOnlyList<string> lines, string[] masked, int line, Match keyword,
+        string extension, IReadOnlyDictionary<string, string> constants)
+    {
+        var text = masked[line];
+        var kind = Regex.Replace(keyword.Groups["kw"].Value, @"\s+", " ");
+        var start = keyword.Index + keyword.Length;
+        var python = extension.Equals(".py", StringComparison.OrdinalIgnoreCase);
+
+        int conditionStart, conditionEnd;
+        if (python)
+        {
+            conditionEnd = LastTopLevel(text, ':', start);
+            conditionStart = start;
+        }
+        else if (ParenlessExtensions.Contains(extension))
+        {
+            conditionEnd = LastTopLevel(text, '{', start);
+            conditionStart = Math.Max(start, LastTopLevel(text[..Math.Max(start, conditionEnd)], ';', start) + 1);
+        }
+        else
+        {
+            var open = text.IndexOf('(', start);
+            if (open < 0 || text[start..open].Trim().Length > 0)
+                return null;
+            conditionStart = open + 1;
+            conditionEnd = MatchingParen(text, open);
+        }
+        if (conditionEnd <= conditionStart)
+            return null;
+
+        // do { ... } while (x); runs its body regardless
+        if (kind == "while" && (text[..keyword.Index].TrimEnd().EndsWith('}') || text[Math.Min(conditionEnd + 1, text.Length)..].TrimStart().StartsWith(';')))
+            return null;
+
+        var condition = lines[line][conditionStart..conditionEnd].Trim();
+        var used = new List<string>();
+        if (Evaluate(condition, constants, used) is not { } value)
+            return null;
+        if (kind == "while" && value)
+            return null;
+
+        var branch = new ConstantBranch
+        {
+            FilePath = filePath,
+            Line = line + 1,
+            Kind = kind,
+            Condition = condition,
+            Value = value,
+            Constants = used
+        };
+
+        var (thenStart, thenEnd, elseStart, elseEnd) = python
+            ? IndentBranches(masked, line)
+            : BraceBranches(masked, line, ParenlessExtensions.Contains(extension) ? conditionEnd : conditionEnd + 1, allowElse: kind != "while");
+
+        if (!value)
+        {
+            branch.DeadBranch = kind == "while" ? "body" : "then";
+            (branch.DeadStartLine, branch.DeadEndLine) = (thenStart + 1, thenEnd + 1);
+            branch.Suggestion = kind == "while" ? "Loop never runs: remove it"
+                : elseStart >= 0 ? "Always false: keep only the else branch"
+                : "Always false: remove the conditional and its body";
+        }
+        else if (elseStart >= 0)
+        {
+            branch.DeadBranch = "else";
+            (branch.DeadStartLine, branch.DeadEndLine) = (elseStart + 1, elseEnd + 1);
+            branch.Suggestion = "Always true: keep the then branch and remove the else";
+        }
+        else
+        {
+            branch.Suggestion = "Always true: the condition is redundant, unwrap the body";
+        }
+        return branch;
+    }
+
+    /// <summary>
+    /// Lines of the then-block starting after <paramref name="from"/> on <paramref name="line"/>, and of the
+    /// else chain following it (-1 when there is none)
+    /// </summary>
+    private static (int ThenStart, int ThenEnd, int ElseStart, int ElseEnd) BraceBranches(string[] masked, int line, int from, bool allowElse)
+    {
+        var (open, openLine) = NextNonSpace(masked, line, from);
+        if (openLine < 0)
+            return (line, line, -1, -1);
+
+        int endLine, endIndex;
+        if (masked[openLine][open] == '{')
+        {
+            (endLine, endIndex) = MatchingBrace(masked, openLine, open);
+            if (endLine < 0)
+                return (line, masked.Length - 1, -1, -1);
+        }
+        else
+        {
+            // Single statement: up to its ';' or the end of its line
+            endLine = openLine;
+            endIndex = masked[openLine].IndexOf(';', open) is var semicolon and >= 0 ? semicolon : masked[openLine].Length - 1;
+        }
+
+        var thenStart = openLine == line && masked[line][(open + 1)..].Trim().Length == 0 ? line + 1 : openLine;
+        var thenEnd = endLine;
+        if (!allowElse)
+            return (thenStart, thenEnd, -1, -1);
+
+        var elseStart = -1;
+        var elseEnd = -1;
+        for (var (index, current) = NextNonSpace(masked, endLine, endIndex + 1);
+             current >= 0 && Regex.IsMatch(masked[current][index..], @"^else\b");
+             (index, current) = NextNonSpace(masked, endLine, endIndex + 1))
+        {
+            if (elseStart < 0)
+                elseStart = current;
+
+            var afterElse = index + 4;
+            var nested = Regex.Match(masked[current][afterElse..], @"^\s*if\b");
+            var searchFrom = afterElse + (nested.Success ? nested.Length : 0);
+            var (brace, braceLine) = (masked[current].IndexOf('{', searchFrom), current);
+            if (brace < 0)
+                (brace, braceLine) = NextNonSpace(masked, current, masked[current].Length);
+            if (braceLine < 0 || masked[braceLine][brace] != '{')
+            {
+                elseEnd = braceLine < 0 ? current : braceLine;
+                break;
+            }
+            (endLine, endIndex) = MatchingBrace(masked, braceLine, brace);
+            if (endLine < 0)
+            {
+                elseEnd = masked.Length - 1;
+                break;
+            }
+            elseEnd = endLine;
+            if (!nested.Success)
+                break;
+        }
+
+        return (thenStart, thenEnd, elseStart, elseEnd);
+    }
+
+    private static (int ThenStart, int ThenEnd, int ElseStart, int ElseEnd) IndentBranches(string[] masked, int line)
+    {
+        var indent = Indent(masked[line]);
+        var thenEnd = BlockEnd(masked, line, indent);
+        var elseStart = -1;
+        var elseEnd = -1;
+
+        for (var next = NextNonBlank(masked, thenEnd + 1);
+             next >= 0 && Indent(masked[next]) == indent && Regex.IsMatch(masked[next].TrimStart(), @"^(elif|else)\b");
+             next = NextNonBlank(masked, elseEnd + 1))
+        {
+            if (elseStart < 0)
+                elseStart = next;
+            elseEnd = BlockEnd(masked, next, indent);
+            if (masked[next].TrimStart().StartsWith("else", StringComparison.Ordinal))
+                break;
+        }
+
+        return (Math.Min(line + 1, thenEnd), thenEnd, elseStart, elseEnd);
+    }
+
+    // Last line indented deeper than the header, or the header itself for a one-line "if x: y"
+    private static int BlockEnd(string[] masked, int header, int indent)
+    {
+        var end = header;
+        for (var i = header + 1; i < masked.Length; i++)
+        {
+            if (masked[i].Trim().Length == 0)
+                continue;
+            if (Indent(masked[i]) <= indent)
+                break;
+            end = i;
+        }
+        return end;
+    }
+
+    private static ConstantBranch? ScanDirective(string filePath, string[] masked, int line, Match directive,
+        IReadOnlyDictionary<string, string> constants)
+    {
+        var kind = directive.Groups["kw"].Value;
+        if (kind is "else" or "endif")
+            return null;
+
+        var expression = directive.Groups["expr"].Value.Trim();
+        var condition = kind switch
+        {
+            "ifdef" => $"defined({expression})",
+            "ifndef" => $"!defined({expression})",
+            _ => expression
+        };
+
+        var used = new List<string>();
+        if (Evaluate(condition, constants, used, preprocessor: true) is not { } value)
+            return null;
+
+        // Siblings at this nesting level: the next #elif/#else and the closing #endif
+        var depth = 0;
+        var nextSibling = -1;
+        var endif = -1;
+        for (var i = line + 1; i < masked.Length && endif < 0; i++)
+        {
+            var match = Directive.Match(masked[i]);
+            if (!match.Success)
+                continue;
+            switch (match.Groups["kw"].Value)
+            {
+                case "if" or "ifdef" or "ifndef":
+                    depth++;
+                    break;
+                case "endif" when depth > 0:
+                    depth--;
+                    break;
+                case "endif":
+                    endif = i;
+                    break;
+                case "elif" or "else" when depth == 0 && nextSibling < 0:
+                    nextSibling = i;
+                    break;
+            }
+        }
+        if (endif < 0)
+            return null;
+
+        var blockEnd = (nextSibling >= 0 ? nextSibling : endif) - 1;
+        var branch = new ConstantBranch
+        {
+            FilePath = filePath,
+            Line = line + 1,
+            Kind = "#" + kind,
+            Condition = condition,
+            Value = value,
+            Constants = used
+        };
+
+        if (!value)
+        {
+            branch.DeadBranch = "then";
+            (branch.DeadStartLine, branch.DeadEndLine) = (line + 2, blockEnd + 1);
+            branch.Suggestion = $"Always false: remove the #{kind} block";
+        }
+        else if (nextSibling >= 0)
+        {
+            branch.DeadBranch = "else";
+            (branch.DeadStartLine, branch.DeadEndLine) = (nextSibling + 1, endif);
+            branch.Suggestion = $"Always true: keep the #{kind} block and remove the alternatives";
+        }
+        else
+        {
+            branch.Suggestion = $"Always true: remove the #{kind} and #endif lines";
+        }
+        return branch;
+    }
+
+    private static int LastTopLevel(string text, char target, int from)
+    {
+        var depth = 0;
+        var last = -1;
+        for (var i = from; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '(' or '[') depth++;
+            else if (c is ')' or ']') depth--;
+            else if (c == '{' && target != '{') depth++;
+            else if (c == '}' && target != '{') depth--;
+            if (c == target && depth == 0)
+                last = i;
+        }
+        return last;
+    }
+
+    private static int MatchingParen(string text, int open)
+    {
+        var depth = 0;
+        for (var i = open; i < text.Length; i++)
+        {
+            if (text[i] == '(') depth++;
+            else if (text[i] == ')' && --depth == 0) return i;
+        }
+        return -1;
+    }
+
+    private static (int Line, int Index) MatchingBrace(string[] masked, int line, int open)
+    {
+        var depth = 0;
+        for (var l = line; l < masked.Length; l++)
+        {
+            for (var i = l == line ? open : 0; i < masked[l].Length; i++)
+            {
+                if (masked[l][i] == '{') depth++;
+                else if (masked[l][i] == '}' && --depth == 0) return (l, i);
+            }
+        }
+        return (-1, -1);
+    }
+
+    private static (int Index, int Line) NextNonSpace(string[] masked, int line, int from)
+    {
+        for (var l = line; l < masked.Length; l++)
+        {
+            for (var i = l == line ? from : 0; i < masked[l].Length; i++)
+            {
+                if (!char.IsWhiteSpace(masked[l][i]))
+                    return (i, l);
+            }
+        }
+        return (-1, -1);
+    }
+
+    private static int NextNonBlank(string[] masked, int from)
+    {
+        for (var i = from; i < masked.Length; i++)
+        {
+            if (masked[i].Trim().Length > 0)
+                return i;
+        }
+        return -1;
+    }
+
+    private static int Indent(string line) => line.Length - line.TrimStart().Length;
+
+    private enum TokenKind { Atom, String, Number, Operator, Open, Close }
+
+    private sealed record Token(TokenKind Kind, string Text);
+
+    /// <summary>
+    /// Null when the condition holds something the evaluator does not model (arithmetic, assignments, lambdas)
+    /// </summary>
+    private static List<Token>? Tokenize(string condition)
+    {
+        var tokens = new List<Token>();
+        var i = 0;
+        while (i < condition.Length)
+        {
+            var c = condition[i];
+            if (char.IsWhiteSpace(c))
+            {
+                i++;
+                continue;
+            }
+
+            if (c is '"' or '\'')
+            {
+                var end = condition.IndexOf(c, i + 1);
+                if (end < 0)
+                    return null;
+                tokens.Add(new Token(TokenKind.String, condition[(i + 1)..end]));
+                i = end + 1;
+                continue;
+            }
+
+            if (char.IsDigit(c))
+            {
+                var start = i;
+                while (i < condition.Length && (char.IsLetterOrDigit(condition[i]) || condition[i] == '.'))
+                    i++;
+                tokens.Add(new Token(TokenKind.Number, condition[start..i]));
+                continue;
+            }
+
+            if (UnicodeIdentifiers.IsIdentifierPart(c) || c is '$' or '@')
+            {
+                var atom = ReadAtom(condition, ref i);
+                if (atom == null)
+                    return null;
+                tokens.Add(atom is "and" or "or" or "not" or "is" ? new Token(TokenKind.Operator, atom) : new Token(TokenKind.Atom, atom));
+                continue;
+            }
+
+            var op = new[] { "!==", "===", "==", "!=", "<=", ">=", "&&", "||", "!", "<", ">" }
+                .FirstOrDefault(o => string.CompareOrdinal(condition, i, o, 0, o.Length) == 0);
+            if (op != null)
+            {
+                tokens.Add(new Token(TokenKind.Operator, op));
+                i += op.Length;
+            }
+            else if (c == '(')
+            {
+                tokens.Add(new Token(TokenKind.Open, "("));
+                i++;
+            }
+            else if (c == ')')
+            {
+                tokens.Add(new Token(TokenKind.Close, ")"));
+                i++;
+            }
+            else
+            {
+                return null;
+            }
+        }
+        return tokens;
+    }
+
+    /// <summary>
+    /// A dotted name, optionally called: <c>Features.NewCheckout</c>, <c>flags.IsEnabled("beta")</c>.
+    /// Member separators (?. :: ->) are normalised to '.' and whitespace dropped.
+    /// </summary>
+    private static string? ReadAtom(string text, ref int i)
+    {
+        var builder = new System.Text.StringBuilder();
+        while (true)
+        {
+            while (i < text.Length && (UnicodeIdentifiers.IsIdentifierPart(text[i]) || text[i] is '$' or '@'))
+                builder.Append(text[i++]);
+
+            var at = i;
+            var separator = new[] { "?.", "::", "->", "." }.FirstOrDefault(s => string.CompareOrdinal(text, at, s, 0, s.Length) == 0);
+            if (separator == null || i + separator.Length >= text.Length || !UnicodeIdentifiers.IsIdentifierPart(text[i + separator.Length]))
+                break;
+            builder.Append('.');
+            i += separator.Length;
+        }
+
+        var name = builder.ToString();
+        if (name is "and" or "or" or "not" or "is")
+            return name;
+
+        var after = i;
+        while (after < text.Length && text[after] == ' ') after++;
+        if (after < text.Length && text[after] == '(')
+        {
+            var close = MatchingParen(text, after);
+            if (close < 0)
+                return null;
+            builder.Append(Regex.Replace(text[after..(close + 1)], @"\s+", ""));
+            i = close + 1;
+        }
+        return builder.ToString();
+    }
+
+    private enum ValueKind { Unknown, Bool, Number, String, Null }
+
+    private readonly record struct Value(ValueKind Kind, bool Bool = false, double Number = 0, string? Text = null)
+    {
+        public static readonly Value Unknown = new(ValueKind.Unknown);
+        public static Value Of(bool value) => new(ValueKind.Bool, Bool: value);
+    }
+
+    private static bool? Truth(Value value) => value.Kind switch
+    {
+        ValueKind.Bool => value.Bool,
+        ValueKind.Number => value.Number != 0,
+        ValueKind.Null => false,
+        _ => null
+    };
+
+    private static Value Parse(string text)
+    {
+        var trimmed = text.Trim();
+        switch (trimmed.ToLowerInvariant())
+        {
+            case "true" or "on" or "yes" or "enabled":
+                return Value.Of(true);
+            case "false" or "off" or "no" or "disabled":
+                return Value.Of(false);
+            case "null" or "nil" or "none" or "undefined":
+                return new Value(ValueKind.Null);
+        }
+        if (double.TryParse(trimmed, NumberStyles.Float, CultureInfo.InvariantCulture, out var number))
+            return new Value(ValueKind.Number, Number: number);
+        if (trimmed.Length >= 2 && trimmed[0] is '"' or '\'' && trimmed[^1] == trimmed[0])
+            trimmed = trimmed[1..^1];
+        return new Value(ValueKind.String, Text: trimmed);
+    }
+
+    /// <summary>
+    /// Recursive descent over ||, &amp;&amp;, ! and comparisons, with short-circuiting on unknown operands
+    /// </summary>
+    private sealed class Parser
+    {
+        private readonly List<Token> _tokens;
+        private readonly IReadOnlyDictionary<string, string> _constants;
+        private readonly bool _preprocessor;
+        private int _position;
+
+        public Parser(List<Token> tokens, IReadOnlyDictionary<string, string> constants, bool preprocessor)
+        {
+            _tokens = tokens;
+            _constants = constants;
+            _preprocessor = preprocessor;
+        }
+
+        public List<string> Used { get; } = new();
+
+        public bool AtEnd => _position == _tokens.Count;
+
+        private Token? Peek => _position < _tokens.Count ? _tokens[_position] : null;
+
+        private bool Accept(params string[] operators)
+        {
+            if (Peek is { Kind: TokenKind.Operator } token && operators.Contains(token.Text))
+            {
+                _position++;
+                return true;
+            }
+            return false;
+        }
+
+        public Value ParseOr()
+        {
+            var left = ParseAnd();
+            while (Accept("||", "or"))
+            {
+                var right = ParseAnd();
+                left = (Truth(left), Truth(right)) switch
+                {
+                    (true, _) or (_, true) => Value.Of(true),
+                    (false, false) => Value.Of(false),
+                    _ => Value.Unknown
+                };
+            }
+            return left;
+        }
+
+        private Value ParseAnd()
+        {
+            var left = ParseNot();
+            while (Accept("&&", "and"))
+            {
+                var right = ParseNot();
+                left = (Truth(left), Truth(right)) switch
+                {
+                    (false, _) or (_, false) => Value.Of(false),
+                    (true, true) => Value.Of(true),
+                    _ => Value.Unknown
+                };
+            }
+            return left;
+        }
+
+        private Value ParseNot()
+        {
+            if (Accept("!", "not"))
+            {
+                return Truth(ParseNot()) is { } truth ? Value.Of(!truth) : Value.Unknown;
+            }
+            return ParseComparison();
+        }
+
+        private Value ParseComparison()
+        {
+            var left = ParsePrimary();
+            string op;
+            if (Accept("==", "===")) op = "==";
+            else if (Accept("!=", "!==")) op = "!=";
+            else if (Accept("is")) op = Accept("not") ? "!=" : "==";
+            else if (Peek is { Kind: TokenKind.Operator, Text: "<" or ">" or "<=" or ">=" } comparison)
+            {
+                _position++;
+                op = comparison.Text;
+            }
+            else return left;
+
+            return Compare(left, op, ParsePrimary());
+        }
+
+        private Value ParsePrimary()
+        {
+            var token = Peek;
+            if (token == null)
+                return Value.Unknown;
+            _position++;
+
+            switch (token.Kind)
+            {
+                case TokenKind.Open:
+                    var inner = ParseOr();
+                    if (Peek is { Kind: TokenKind.Close })
+                        _position++;
+                    else
+                        _position = _tokens.Count + 1;
+                    return inner;
+                case TokenKind.String:
+                    return new Value(ValueKind.String, Text: token.Text);
+                case TokenKind.Number:
+                    return double.TryParse(token.Text, NumberStyles.Float, CultureInfo.InvariantCulture, out var number)
+                        ? new Value(ValueKind.Number, Number: number)
+                        : Value.Unknown;
+                case TokenKind.Atom when token.Text.StartsWith("defined(", StringComparison.Ordinal):
+                    return Defined(token.Text["defined(".Length..^1]);
+                case TokenKind.Atom when token.Text == "defined" && Peek is { Kind: TokenKind.Atom } symbol:
+                    _position++;
+                    return Defined(symbol.Text);
+                case TokenKind.Atom:
+                    return Resolve(token.Text);
+                default:
+                    _position = _tokens.Count + 1;
+                    return Value.Unknown;
+            }
+        }
+
+        private Value Resolve(string atom)
+        {
+            switch (atom)
+            {
+                case "true" or "True":
+                    return Value.Of(true);
+                case "false" or "False":
+                    return Value.Of(false);
+                case "null" or "nil" or "None" or "undefined":
+                    return new Value(ValueKind.Null);
+            }
+
+            if (Lookup(atom) is { } name)
+            {
+                Used.Add(name);
+                return Parse(_constants[name]);
+            }
+            return Value.Unknown;
+        }
+
+        // A configured symbol counts as defined unless it is set to false/0
+        private Value Defined(string symbol)
+        {
+            if (!_preprocessor || Lookup(symbol.Trim()) is not { } name)
+                return Value.Unknown;
+            Used.Add(name);
+            return Value.Of(Truth(Parse(_constants[name])) != false);
+        }
+
+        /// <summary>
+        /// The constant an atom refers to: the same text, or a key the atom ends with on a member boundary
+        /// (<c>this.options.Beta</c> matches <c>options.Beta</c> and <c>Beta</c>)
+        /// </summary>
+        private string? Lookup(string atom)
+        {
+            if (_constants.ContainsKey(atom))
+                return atom;
+            return _constants.Keys
+                .Where(key => atom.EndsWith("." + key, StringComparison.Ordinal))
+                .OrderByDescending(key => key.Length)
+                .FirstOrDefault();
+        }
+
+        private static Value Compare(Value left, string op, Value right)
+        {
+            if (left.Kind == ValueKind.Unknown || right.Kind == ValueKind.Unknown)
+                return Value.Unknown;
+
+            if (op is "==" or "!=")
+            {
+                bool? equal = (left.Kind, right.Kind) switch
+                {
+                    (ValueKind.Bool, ValueKind.Bool) => left.Bool == right.Bool,
+                    (ValueKind.Number, ValueKind.Number) => left.Number.Equals(right.Number),
+                    (ValueKind.String, ValueKind.String) => left.Text == right.Text,
+                    (ValueKind.Null, ValueKind.Null) => true,
+                    (ValueKind.Null, _) or (_, ValueKind.Null) => false,
+                    _ => null
+                };
+                return equal is { } e ? Value.Of(op == "==" ? e : !e) : Value.Unknown;
+            }
+
+            if (left.Kind != ValueKind.Number || right.Kind != ValueKind.Number)
+                return Value.Unknown;
+            return Value.Of(op switch
+            {
+                "<" => left.Number < right.Number,
+                ">" => left.Number > right.Number,
+                "<=" => left.Number <= right.Number,
+                _ => left.Number >= right.Number
+            });
+        }
+    }
+}
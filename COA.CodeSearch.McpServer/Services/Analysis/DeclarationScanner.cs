@@ -0,0 +1,381 @@
+using System.Text.Json.Serialization;
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A declaration found by <see cref="DeclarationScanner"/>
+/// </summary>
+public class Declaration
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// class, interface, struct, enum, record, trait, type, method, function or property
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Enclosing type (or Go receiver / Rust impl type), if any
+    /// </summary>
+    public string? Container { get; set; }
+
+    public int Line { get; set; }
+    public int EndLine { get; set; }
+
+    /// <summary>
+    /// The declaration line, trimmed
+    /// </summary>
+    public string Signature { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Part of the public API: public/export/pub, an exported Go identifier, or a Python name without a leading underscore
+    /// </summary>
+    public bool IsPublic { get; set; }
+
+    /// <summary>
+    /// Source from the declaration to the next one, used for similarity
+    /// </summary>
+    public string Body { get; set; } = string.Empty;
+
+    [JsonIgnore]
+    public string QualifiedName => Container == null ? Name : $"{Container}.{Name}";
+
+    [JsonIgnore]
+    public bool IsType => Kind is "class" or "interface" or "struct" or "enum" or "record" or "trait" or "type";
+}
+
+/// <summary>
+/// Line-based declaration scanner for comparing two versions of a file. Unlike julie-codesearch it
+/// needs no index, so it works on any git revision; it trades precision for that (one declaration
+/// per line, containers inferred from indentation).
+/// </summary>
+public static class DeclarationScanner
+{
+    private const string CSharpModifiers = @"(?:(?:public|private|protected|internal|static|virtual|override|abstract|async|sealed|extern|new|partial|readonly|unsafe|final|synchronized|default|native)\s+)";
+
+    private static readonly Regex CSharpType = new(
+        @"^(?<indent>\s*)(?<mods>" + CSharpModifiers + @"*)(?:record\s+(?:class|struct)|class|interface|struct|enum|record|@interface)\s+(?<name>\w+)",
+        RegexOptions.Compiled);
+    private static readonly Regex CSharpMethod = new(
+        @"^(?<indent>\s*)(?<mods>" + CSharpModifiers + @"+)(?:[\w<>\[\],.?]+(?:\s*<[^()]*>)?\s+)?(?<name>\w+)\s*(?:<[^()]*>)?\s*\(",
+        RegexOptions.Compiled);
+    private static readonly Regex CSharpProperty = new(
+        @"^(?<indent>\s*)(?<mods>" + CSharpModifiers + @"+)[\w<>\[\],.?]+\s+(?<name>\w+)\s*(?:\{\s*(?:get|set|init)|=>)",
+        RegexOptions.Compiled);
+
+    private static readonly Regex ScriptType = new(
+        @"^(?<indent>\s*)(?<export>export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?<kind>class|interface|enum|type)\s+(?<name>\w+)",
+        RegexOptions.Compiled);
+    private static readonly Regex ScriptFunction = new(
+        @"^(?<indent>\s*)(?<export>export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(?<name>\w+)\s*(?:<[^>]*>)?\s*\(",
+        RegexOptions.Compiled);
+    private static readonly Regex ScriptArrow = new(
+        @"^(?<indent>\s*)(?<export>export\s+)?(?:const|let)\s+(?<name>\w+)\s*(?::[^=]+)?=\s*(?:async\s*)?(?:\([^)]*\)|\w+)\s*(?::[^=]+)?=>",
+        RegexOptions.Compiled);
+    private static readonly Regex ScriptMethod = new(
+        @"^(?<indent>\s+)(?<mods>(?:(?:public|private|protected|static|async|readonly|get|set)\s+)*)(?<name>#?\w+)\s*(?:<[^>]*>)?\s*\([^;]*\)\s*(?::\s*[^{;]+)?\{\s*$",
+        RegexOptions.Compiled);
+
+    private static readonly Regex PythonDeclaration = new(
+        @"^(?<indent>\s*)(?:async\s+)?(?<kind>def|class)\s+(?<name>\w+)",
+        RegexOptions.Compiled);
+
+    private static readonly Regex GoFunction = new(
+        @"^func\s+(?:\(\s*\w*\s*\*?(?<receiver>\w+)(?:\[[^\]]*\])?\s*\)\s*)?(?<name>\w+)\s*(?:\[(?:[^\[\]]|\[[^\]]*\])*\])?\s*\(",
+        RegexOptions.Compiled);
+    private static readonly Regex GoType = new(
+        @"^(?:type\s+|\s+)(?<name>\w+)\s*(?:\[(?:[^\[\]]|\[[^\]]*\])*\]\s*)?(?:=\s*)?(?:(?<kind>struct|interface)\b)?",
+        RegexOptions.Compiled);
+
+    private static readonly Regex RustItem = new(
+        @"^(?<indent>\s*)(?<pub>pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?(?:extern\s+""[^""]*""\s+)?(?<kind>fn|struct|enum|trait|type)\s+(?<name>\w+)",
+        RegexOptions.Compiled);
+    private static readonly Regex RustImpl = new(
+        @"^impl(?:<[^>]*>)?\s+(?:[\w:<>]+\s+for\s+)?(?<name>\w+)",
+        RegexOptions.Compiled);
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal)
+    {
+        "if", "else", "for", "foreach", "while", "do", "switch", "case", "catch", "try", "finally", "using", "lock",
+        "return", "new", "throw", "await", "yield", "sizeof", "typeof", "nameof", "when", "function", "constructor",
+        "super", "this", "base", "get", "set", "default", "fixed", "checked", "unchecked"
+    };
+
+    /// <summary>
+    /// Extensions the scanner understands
+    /// </summary>
+    public static readonly string[] SupportedExtensions =
+    {
+        ".cs", ".java", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".py", ".go", ".rs"
+    };
+
+    public static bool Supports(string filePath) =>
+        SupportedExtensions.Contains(Path.GetExtension(filePath), StringComparer.OrdinalIgnoreCase);
+
+    public static List<Declaration> Scan(string content, string filePath)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var declarations = Path.GetExtension(filePath).ToLowerInvariant() switch
+        {
+            ".cs" or ".java" => ScanCStyle(lines),
+            ".ts" or ".tsx" or ".js" or ".jsx" or ".mjs" or ".cjs" => ScanScript(lines),
+            ".py" => ScanPython(lines),
+            ".go" => ScanGo(lines),
+            ".rs" => ScanRust(lines),
+            _ => new List<Declaration>()
+        };
+
+        // Each declaration runs until the next one; good enough for similarity, not for exact extents
+        for (var i = 0; i < declarations.Count; i++)
+        {
+            var end = i + 1 < declarations.Count ? declarations[i + 1].Line - 1 : lines.Length;
+            declarations[i].EndLine = Math.Max(declarations[i].Line, end);
+            declarations[i].Body = string.Join("\n", lines[(declarations[i].Line - 1)..declarations[i].EndLine]);
+        }
+
+        return declarations;
+    }
+
+    private static List<Declaration> ScanCStyle(string[] lines)
+    {
+        var result = new List<Declaration>();
+        var containers = new List<(int Indent, string Name)>();
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            if (IsCommentOrBlank(line))
+                continue;
+
+            var type = CSharpType.Match(line);
+            if (type.Success)
+            {
+                var indent = Indent(type.Groups["indent"].Value);
+                var kind = Regex.Match(line, @"\b(record|class|interface|struct|enum)\b").Value;
+                result.Add(Create(type.Groups["name"].Value, kind == "" ? "class" : kind, ContainerAt(containers, indent), i, line,
+                    type.Groups["mods"].Value.Contains("public")));
+                PushContainer(containers, indent, type.Groups["name"].Value);
+                continue;
+            }
+
+            var property = CSharpProperty.Match(line);
+            if (property.Success && !Keywords.Contains(property.Groups["name"].Value))
+            {
+                var indent = Indent(property.Groups["indent"].Value);
+                result.Add(Create(property.Groups["name"].Value, "property", ContainerAt(containers, indent), i, line,
+                    property.Groups["mods"].Value.Contains("public")));
+                continue;
+            }
+
+            var method = CSharpMethod.Match(line);
+            // A trailing semicolon is a call or field unless the member is abstract or expression-bodied
+            if (method.Success && !Keywords.Contains(method.Groups["name"].Value) &&
+                (!line.TrimEnd().EndsWith(';') || line.Contains("=>") || IsAbstractMember(line)))
+            {
+                var indent = Indent(method.Groups["indent"].Value);
+                result.Add(Create(method.Groups["name"].Value, "method", ContainerAt(containers, indent), i, line,
+                    method.Groups["mods"].Value.Contains("public")));
+            }
+        }
+
+        return result;
+    }
+
+    private static List<Declaration> ScanScript(string[] lines)
+    {
+        var result = new List<Declaration>();
+        var containers = new List<(int Indent, string Name)>();
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            if (IsCommentOrBlank(line))
+                continue;
+
+            var type = ScriptType.Match(line);
+            if (type.Success)
+            {
+                var indent = Indent(type.Groups["indent"].Value);
+                result.Add(Create(type.Groups["name"].Value, type.Groups["kind"].Value, ContainerAt(containers, indent), i, line,
+                    type.Groups["export"].Success));
+                if (type.Groups["kind"].Value is "class" or "interface")
+                    PushContainer(containers, indent, type.Groups["name"].Value);
+                continue;
+            }
+
+            var function = ScriptFunction.Match(line);
+            var arrow = function.Success ? function : ScriptArrow.Match(line);
+            if (arrow.Success)
+            {
+                var indent = Indent(arrow.Groups["indent"].Value);
+                result.Add(Create(arrow.Groups["name"].Value, "function", ContainerAt(containers, indent), i, line,
+                    arrow.Groups["export"].Success));
+                continue;
+            }
+
+            var method = ScriptMethod.Match(line);
+            if (method.Success && !Keywords.Contains(method.Groups["name"].Value))
+            {
+                var indent = Indent(method.Groups["indent"].Value);
+                var container = ContainerAt(containers, indent);
+                if (container == null)
+                    continue;
+
+                var name = method.Groups["name"].Value;
+                var isPrivate = name.StartsWith('#') || method.Groups["mods"].Value.Contains("private") || method.Groups["mods"].Value.Contains("protected");
+                result.Add(Create(name.TrimStart('#'), "method", container, i, line,
+                    !isPrivate && result.Any(d => d.Name == container && d.IsType && d.IsPublic)));
+            }
+        }
+
+        return result;
+    }
+
+    private static List<Declaration> ScanPython(string[] lines)
+    {
+        var result = new List<Declaration>();
+        var containers = new List<(int Indent, string Name)>();
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var match = PythonDeclaration.Match(lines[i]);
+            if (!match.Success)
+                continue;
+
+            var indent = Indent(match.Groups["indent"].Value);
+            var name = match.Groups["name"].Value;
+            var container = ContainerAt(containers, indent);
+            var isClass = match.Groups["kind"].Value == "class";
+
+            // Dunder methods (__init__, __eq__) are public API; _private names are not
+            var isPublic = (!name.StartsWith('_') || (name.StartsWith("__") && name.EndsWith("__"))) &&
+                           (container == null || !container.StartsWith('_'));
+            result.Add(Create(name, isClass ? "class" : container == null ? "function" : "method", container, i, lines[i], isPublic));
+
+            if (isClass)
+                PushContainer(containers, indent, name);
+        }
+
+        return result;
+    }
+
+    private static List<Declaration> ScanGo(string[] lines)
+    {
+        var result = new List<Declaration>();
+        var inTypeBlock = false;
+        var blockDepth = 0;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            if (line.StartsWith("type ("))
+            {
+                inTypeBlock = true;
+                blockDepth = 0;
+                continue;
+            }
+            if (inTypeBlock && line.StartsWith(")"))
+            {
+                inTypeBlock = false;
+                continue;
+            }
+
+            var function = GoFunction.Match(line);
+            if (function.Success)
+            {
+                var receiver = function.Groups["receiver"].Success ? function.Groups["receiver"].Value : null;
+                var name = function.Groups["name"].Value;
+                result.Add(Create(name, receiver == null ? "function" : "method", receiver, i, line, char.IsUpper(name[0])));
+                continue;
+            }
+
+            // Inside "type ( ... )" only top-level specs are declarations, not struct fields
+            var depthBefore = blockDepth;
+            if (inTypeBlock)
+                blockDepth += line.Count(c => c == '{') - line.Count(c => c == '}');
+
+            if (line.StartsWith("type ") || inTypeBlock && depthBefore == 0 && !IsCommentOrBlank(line))
+            {
+                var type = GoType.Match(line);
+                if (type.Success)
+                {
+                    var kind = type.Groups["kind"].Success && type.Groups["kind"].Value.Length > 0 ? type.Groups["kind"].Value : "type";
+                    var name = type.Groups["name"].Value;
+                    result.Add(Create(name, kind, null, i, line, char.IsUpper(name[0])));
+                }
+            }
+        }
+
+        return result;
+    }
+
+    private static List<Declaration> ScanRust(string[] lines)
+    {
+        var result = new List<Declaration>();
+        string? implType = null;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            var impl = RustImpl.Match(line);
+            if (impl.Success)
+            {
+                implType = impl.Groups["name"].Value;
+                continue;
+            }
+            if (line.StartsWith('}'))
+            {
+                implType = null;
+                continue;
+            }
+
+            var item = RustItem.Match(line);
+            if (!item.Success)
+                continue;
+
+            var kind = item.Groups["kind"].Value;
+            var nested = Indent(item.Groups["indent"].Value) > 0;
+            var container = nested ? implType : null;
+            result.Add(Create(item.Groups["name"].Value,
+                kind == "fn" ? (container == null ? "function" : "method") : kind,
+                container, i, line, item.Groups["pub"].Success));
+        }
+
+        return result;
+    }
+
+    private static Declaration Create(string name, string kind, string? container, int index, string line, bool isPublic) => new()
+    {
+        Name = name,
+        Kind = kind,
+        Container = container,
+        Line = index + 1,
+        Signature = line.Trim().TrimEnd('{').Trim(),
+        IsPublic = isPublic
+    };
+
+    private static bool IsAbstractMember(string line) =>
+        Regex.IsMatch(line, @"\b(abstract|extern)\b") && line.TrimEnd().EndsWith(';');
+
+    private static bool IsCommentOrBlank(string line)
+    {
+        var trimmed = line.TrimStart();
+        return trimmed.Length == 0 || trimmed.StartsWith("//") || trimmed.StartsWith("/*") || trimmed.StartsWith('*') || trimmed.StartsWith('#');
+    }
+
+    private static int Indent(string whitespace) => whitespace.Replace("\t", "    ").Length;
+
+    private static string? ContainerAt(List<(int Indent, string Name)> containers, int indent)
+    {
+        // Containers opened at this indentation or deeper have been closed
+        containers.RemoveAll(c => c.Indent >= indent);
+        return containers.Count > 0 ? containers[^1].Name : null;
+    }
+
+    private static void PushContainer(List<(int Indent, string Name)> containers, int indent, string name)
+    {
+        containers.RemoveAll(c => c.Indent >= indent);
+        containers.Add((indent, name));
+    }
+}
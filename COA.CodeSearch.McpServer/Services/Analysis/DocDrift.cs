@@ -0,0 +1,411 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A doc comment that says something its declaration no longer does
+/// </summary>
+public class DocDriftFinding
+{
+    /// <summary>
+    /// parameter (documents a parameter the signature lacks), reference (names a symbol that no longer exists),
+    /// name (opens with a name other than the declaration's) or value (states a default or literal the code no longer has)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Symbol { get; set; } = string.Empty;
+    public string SymbolKind { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The comment line holding the stale mention, or the declaration's line when it cannot be placed
+    /// </summary>
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The stale text as the comment has it
+    /// </summary>
+    public string Mention { get; set; } = string.Empty;
+
+    public string Message { get; set; } = string.Empty;
+
+    /// <summary>
+    /// What the comment should probably say instead - the undocumented parameter, the renamed symbol or the
+    /// actual default; null when there is no single candidate
+    /// </summary>
+    public string? Suggestion { get; set; }
+}
+
+/// <summary>
+/// Finds doc comments that drifted from their code: documented parameters the signature no longer has, names
+/// in cref, link and code spans that nothing declares or uses any more, comments still opening with a
+/// declaration's old name, and defaults or literals the signature and body no longer contain
+/// </summary>
+public static class DocDrift
+{
+    private static readonly Regex CommentMarker = new(@"^\s*(?:///|//!|//|/\*\*|/\*|\*/|\*|#|--|""""""|''')\s?", RegexOptions.Compiled);
+    private static readonly Regex Identifier = new($"^{UnicodeIdentifiers.IdentifierPattern}$", RegexOptions.Compiled);
+
+    private static readonly Regex[] ParameterTags =
+    {
+        new(@"<param(?:ref)?\s+name\s*=\s*""(?<name>[^""]+)""", RegexOptions.Compiled),
+        new(@"@param\s+(?:\{[^}]*\}\s+)?\[?\$?(?<name>[\p{L}_][\w]*)", RegexOptions.Compiled),
+        new(@":param\s+(?:[\w\[\], .|]+\s+)?(?<name>[\p{L}_]\w*)\s*:", RegexOptions.Compiled),
+        new(@"-\s+[Pp]arameter\s+(?<name>[\p{L}_]\w*)\s*:", RegexOptions.Compiled)
+    };
+
+    private static readonly Regex ParameterSection = new(@"^(?<indent>\s*)(?:-\s+)?(?:Args|Arguments|Parameters|Keyword Args|Params)\s*:\s*$", RegexOptions.Compiled);
+    private static readonly Regex SectionEntry = new(@"^\s*(?:[-*]\s+)?\*{0,2}`?(?<name>[\p{L}_]\w*)`?\*{0,2}\s*(?:\([^)]*\))?\s*[:-]", RegexOptions.Compiled);
+
+    private static readonly Regex[] References =
+    {
+        new(@"<(?:see|seealso)\s+cref\s*=\s*""(?:\w:)?(?<target>[^""]+)""", RegexOptions.Compiled),
+        new(@"\{@(?:link|linkplain)\s+(?<target>[^\s}]+)", RegexOptions.Compiled),
+        new(@"(?<![\]\w])\[`?(?<target>[\p{L}_][\w.:#]+)`?\](?![(\[:])", RegexOptions.Compiled)
+    };
+
+    private static readonly Regex CodeSpan = new(@"`(?<code>[^`\n]+)`|<c>(?<code>[^<]+)</c>|\{@code\s+(?<code>[^}]+)\}", RegexOptions.Compiled);
+    private static readonly Regex Literal = new(@"^(?:""[^""]*""|'[^']*'|-?\d[\d_]*(?:\.\d+)?)$", RegexOptions.Compiled);
+    private static readonly Regex DefaultValue = new(
+        @"\bdefaults?(?:\s+(?:is|to|of|value(?:\s+is)?))?\s*[:=]?\s*`?(?<value>""[^""]*""|'[^']*'|-?\d[\d_]*(?:\.\d+)?|\b(?:true|false|null|nil|None)\b)",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal)
+    {
+        "true", "false", "null", "nil", "None", "True", "False", "self", "this", "super", "undefined", "void",
+        "async", "await", "return", "yield", "new", "default", "var", "let", "const", "static", "int", "string",
+        "bool", "object", "any", "unknown", "never", "error", "nullptr", "NaN", "Infinity"
+    };
+
+    private static readonly HashSet<string> GoArticles = new(StringComparer.Ordinal)
+    {
+        "A", "An", "The", "Deprecated"
+    };
+
+    private static readonly HashSet<string> Callables = new(StringComparer.Ordinal)
+    {
+        "function", "method", "constructor"
+    };
+
+    /// <summary>
+    /// The drift in the doc comments of <paramref name="symbols"/>. <paramref name="declared"/> holds every
+    /// symbol name in the workspace and <paramref name="contents"/> the files by workspace-relative path.
+    /// </summary>
+    public static List<DocDriftFinding> Check(
+        string workspacePath,
+        IEnumerable<JulieSymbol> symbols,
+        IReadOnlySet<string> declared,
+        IReadOnlyDictionary<string, string> contents)
+    {
+        var findings = new List<DocDriftFinding>();
+        var files = new Dictionary<string, SourceFile>(StringComparer.Ordinal);
+
+        foreach (var symbol in symbols.Where(s => !string.IsNullOrWhiteSpace(s.DocComment)))
+        {
+            var path = Relative(workspacePath, symbol.FilePath);
+            if (!files.TryGetValue(path, out var file))
+            {
+                if (!contents.TryGetValue(path, out var content))
+                    continue;
+                file = files[path] = new SourceFile(path, content);
+            }
+            if (symbol.StartLine < 1 || symbol.StartLine > file.Lines.Length)
+                continue;
+
+            var found = CheckSymbol(symbol, file, declared);
+            foreach (var finding in found.GroupBy(f => (f.Kind, f.Mention)).Select(g => g.First()))
+            {
+                finding.Symbol = symbol.Name;
+                finding.SymbolKind = symbol.Kind;
+                finding.FilePath = path;
+                finding.Line = LineOf(file, symbol, finding.Mention);
+                findings.Add(finding);
+            }
+        }
+
+        return findings.OrderBy(f => f.FilePath, StringComparer.Ordinal).ThenBy(f => f.Line).ToList();
+    }
+
+    private static IEnumerable<DocDriftFinding> CheckSymbol(JulieSymbol symbol, SourceFile file, IReadOnlySet<string> declared)
+    {
+        var doc = symbol.DocComment!.Replace("\r\n", "\n").Split('\n');
+        var text = doc.Select(l => CommentMarker.Replace(l, "")).ToList();
+        var joined = string.Join("\n", text);
+        var end = Math.Clamp(symbol.EndLine, symbol.StartLine, file.Lines.Length);
+        var body = string.Join("\n", file.Lines[(symbol.StartLine - 1)..end]);
+        var header = string.Join("\n", file.Masked[(symbol.StartLine - 1)..Math.Min(end, symbol.StartLine + 9)]);
+
+        var list = Callables.Contains(symbol.Kind) ? ParameterList(file, symbol, end) : null;
+        var parameters = list != null ? DataFlowScanner.ParameterNames(header, symbol.Name, file.Extension) : null;
+
+        // Parameters the comment documents that the signature no longer declares
+        var documented = DocumentedParameters(text);
+        if (parameters != null)
+        {
+            var undocumented = parameters.Where(p => !documented.Contains(p)).ToList();
+            foreach (var name in documented.Where(d => !parameters.Contains(d)))
+            {
+                yield return new DocDriftFinding
+                {
+                    Kind = "parameter",
+                    Mention = name,
+                    Message = $"Documents parameter '{name}' but {symbol.Name} has no such parameter",
+                    Suggestion = undocumented.Count == 1 ? undocumented[0] : null
+                };
+            }
+        }
+
+        bool Known(string name) =>
+            name == symbol.Name || declared.Contains(name) || file.Identifiers.Contains(name) || parameters?.Contains(name) == true || Keywords.Contains(name);
+
+        // Names in cref, link and code spans that nothing declares and the file no longer uses
+        var mentions = References.SelectMany(r => r.Matches(joined)).Select(m => TargetName(m.Groups["target"].Value))
+            .Concat(CodeSpan.Matches(joined).Select(m => CodeName(m.Groups["code"].Value.Trim())));
+        foreach (var name in mentions.OfType<string>().Distinct())
+        {
+            if (Known(name) || documented.Contains(name))
+                continue;
+            var renamed = file.Identifiers.Where(n => n != name && (n.StartsWith(name, StringComparison.Ordinal) || n.EndsWith(name, StringComparison.Ordinal))).ToList();
+            yield return new DocDriftFinding
+            {
+                Kind = "reference",
+                Mention = name,
+                Message = $"Refers to '{name}', which is no longer declared or used",
+                Suggestion = renamed.Count == 1 ? renamed[0] : null
+            };
+        }
+
+        // A comment opening with the declaration's old name: "Helper handles ..." above HelperHandler, or any
+        // other name above an exported Go declaration, whose comment by convention opens with its name
+        var first = Regex.Match(Regex.Replace(joined, @"<[^>]+>", " "), @"^\s*(?<word>[\p{L}_]\w*)").Groups["word"].Value;
+        if (first.Length > 0 && first != symbol.Name && !Known(first)
+            && (symbol.Name.StartsWith(first, StringComparison.Ordinal) && symbol.Name.Length > first.Length && (char.IsUpper(symbol.Name[first.Length]) || symbol.Name[first.Length] == '_')
+                || symbol.Language == "go" && char.IsUpper(first[0]) && char.IsUpper(symbol.Name[0]) && !GoArticles.Contains(first)))
+        {
+            yield return new DocDriftFinding
+            {
+                Kind = "name",
+                Mention = first,
+                Message = $"Comment opens with '{first}' but documents {symbol.Kind} {symbol.Name}",
+                Suggestion = symbol.Name
+            };
+        }
+
+        // Defaults the comment states for a parameter that the signature gives another value
+        if (parameters != null)
+        {
+            foreach (var (name, description) in ParameterDescriptions(text))
+            {
+                if (!parameters.Contains(name) || DefaultValue.Match(description) is not { Success: true } stated)
+                    continue;
+                var actual = Regex.Match(list!,
+                    $@"(?<![\w$]){Regex.Escape(name)}\s*(?::[^=,()]*(?:\([^()]*\))?[^=,()]*)?=\s*(?<value>""[^""]*""|'[^']*'|[^,()\s]+)");
+                var value = stated.Groups["value"].Value;
+                if (actual.Success && Normalize(actual.Groups["value"].Value) != Normalize(value))
+                {
+                    yield return new DocDriftFinding
+                    {
+                        Kind = "value",
+                        Mention = value,
+                        Message = $"Says '{name}' defaults to {value} but the signature defaults it to {actual.Groups["value"].Value}",
+                        Suggestion = actual.Groups["value"].Value
+                    };
+                }
+            }
+        }
+
+        // Literals in code spans that the declaration's body no longer contains; bodiless declarations have nothing to compare
+        if (!HasBody(file, symbol, end))
+            yield break;
+        var normalized = body.Replace("_", "");
+        foreach (var literal in CodeSpan.Matches(joined).Select(m => m.Groups["code"].Value.Trim()).Where(c => Literal.IsMatch(c)).Distinct())
+        {
+            if (literal is "0" or "1" or "-1" or "\"\"" or "''")
+                continue;
+            var number = char.IsDigit(literal[^1]);
+            var present = number
+                ? Regex.IsMatch(normalized, $@"(?<![\w.]){Regex.Escape(literal.Replace("_", ""))}(?![\w.]|\.\d)")
+                : body.Contains(literal, StringComparison.Ordinal) || body.Contains($"\"{literal[1..^1]}\"", StringComparison.Ordinal) || body.Contains($"'{literal[1..^1]}'", StringComparison.Ordinal);
+            if (!present)
+            {
+                yield return new DocDriftFinding
+                {
+                    Kind = "value",
+                    Mention = literal,
+                    Message = $"Mentions {literal} but {symbol.Name} no longer contains it"
+                };
+            }
+        }
+    }
+
+    /// <summary>
+    /// Parameter names the comment documents: XML param tags, @param, :param, Swift's - Parameter, and the
+    /// entries of Args:, Parameters: and - Parameters: sections
+    /// </summary>
+    private static HashSet<string> DocumentedParameters(List<string> text) =>
+        ParameterDescriptions(text).Select(d => d.Name).ToHashSet(StringComparer.Ordinal);
+
+    private static List<(string Name, string Description)> ParameterDescriptions(List<string> text)
+    {
+        var result = new List<(string, string)>();
+        var joined = string.Join("\n", text);
+        foreach (var tag in ParameterTags)
+        {
+            foreach (Match match in tag.Matches(joined))
+            {
+                var rest = joined[(match.Index + match.Length)..];
+                var close = Regex.Match(rest, @"</param>|\n\s*(?:@|:param|-\s+[Pp]arameter)|\n\s*\n");
+                result.Add((match.Groups["name"].Value, close.Success ? rest[..close.Index] : rest));
+            }
+        }
+
+        for (var i = 0; i < text.Count; i++)
+        {
+            var section = ParameterSection.Match(text[i]);
+            if (!section.Success)
+                continue;
+            var indent = section.Groups["indent"].Length;
+            var entryIndent = -1;
+            for (i++; i < text.Count; i++)
+            {
+                if (text[i].Trim().Length == 0)
+                    continue;
+                var depth = text[i].Length - text[i].TrimStart().Length;
+                if (depth <= indent)
+                {
+                    i--;
+                    break;
+                }
+
+                // Deeper lines continue the entry above
+                if (entryIndent < 0)
+                    entryIndent = depth;
+                if (depth == entryIndent && SectionEntry.Match(text[i]) is { Success: true } entry)
+                    result.Add((entry.Groups["name"].Value, text[i][(entry.Index + entry.Length)..]));
+            }
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The name a reference resolves to: the last segment of <c>Orders.Service.Place(int)</c>, <c>List#add</c> or
+    /// <c>crate::io::Read</c>; null for code spans that are expressions rather than names
+    /// </summary>
+    private static string? TargetName(string target)
+    {
+        var name = Regex.Replace(target.Trim(), @"\([^()]*\)$|<[^<>]*>$|\{[^{}]*\}$|\[\]$", "");
+        var segment = name.Split(new[] { ".", "#", "::" }, StringSplitOptions.None)[^1];
+        return Identifier.IsMatch(segment) && Regex.IsMatch(name, @"^[\w.#:]+$") ? segment : null;
+    }
+
+    /// <summary>
+    /// A code span that names something: PascalCase, camelCase or snake_case, or called with <c>()</c>;
+    /// null for plain words, paths and expressions
+    /// </summary>
+    private static string? CodeName(string code)
+    {
+        var match = Regex.Match(code, $@"^(?<name>{UnicodeIdentifiers.IdentifierPattern})(?<call>\(\))?$");
+        if (!match.Success)
+            return null;
+        var name = match.Groups["name"].Value;
+        return match.Groups["call"].Success || Regex.IsMatch(name, @"\p{Ll}\p{Lu}|\p{L}_\p{L}|^\p{Lu}.*\p{Ll}") ? name : null;
+    }
+
+    /// <summary>
+    /// The text of a callable's parameter list, between the parentheses after its name; null when the
+    /// declaration's first lines have none
+    /// </summary>
+    private static string? ParameterList(SourceFile file, JulieSymbol symbol, int end)
+    {
+        var last = Math.Min(end, symbol.StartLine + 9);
+        var masked = string.Join("\n", file.Masked[(symbol.StartLine - 1)..last]);
+        var match = Regex.Match(masked, $@"(?<![\w$]){Regex.Escape(symbol.Name)}\s*(?:<[^()]*?>|\[[^()]*?\])?\s*\(");
+        if (!match.Success)
+            return null;
+
+        var depth = 0;
+        for (var i = match.Index + match.Length - 1; i < masked.Length; i++)
+        {
+            if (masked[i] == '(')
+                depth++;
+            else if (masked[i] == ')' && --depth == 0)
+                return string.Join("\n", file.Lines[(symbol.StartLine - 1)..last])[(match.Index + match.Length)..i];
+        }
+        return null;
+    }
+
+    private static bool HasBody(SourceFile file, JulieSymbol symbol, int end)
+    {
+        var masked = string.Join("\n", file.Masked[(symbol.StartLine - 1)..end]).Trim();
+        return end > symbol.StartLine || masked.Contains('=') || masked.Contains('{') || !masked.EndsWith(';');
+    }
+
+    private static string Normalize(string value) =>
+        value.Trim().Trim('"', '\'', '`').Replace("_", "").TrimEnd('L', 'l', 'f', 'F', 'd', 'D', 'm', 'M', 'u', 'U').ToLowerInvariant();
+
+    /// <summary>
+    /// The line of the declaration's comment mentioning <paramref name="mention"/>: above the declaration, or
+    /// below it for a Python docstring
+    /// </summary>
+    private static int LineOf(SourceFile file, JulieSymbol symbol, string mention)
+    {
+        var size = symbol.DocComment!.Count(c => c == '\n') + 1;
+        var from = Math.Max(0, symbol.StartLine - 1 - size - 3);
+        var to = Math.Min(file.Lines.Length, symbol.StartLine + size + 1);
+        for (var i = from; i < to; i++)
+        {
+            if (i != symbol.StartLine - 1 && file.Lines[i].Contains(mention, StringComparison.Ordinal) && file.Masked[i].Trim().Length < file.Lines[i].Trim().Length)
+                return i + 1;
+        }
+        return symbol.StartLine;
+    }
+
+    /// <summary>
+    /// Lines inside <c>"""</c> and <c>'''</c> strings and docstrings blanked, so their words are not taken for code
+    /// </summary>
+    private static string[] BlankMultilineStrings(string[] lines)
+    {
+        var result = lines.ToArray();
+        string? open = null;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (open != null && !lines[i].Contains(open, StringComparison.Ordinal))
+            {
+                result[i] = new string(' ', lines[i].Length);
+                continue;
+            }
+            foreach (Match delimiter in Regex.Matches(lines[i], "\"\"\"|'''"))
+            {
+                if (open == null)
+                    open = delimiter.Value;
+                else if (open == delimiter.Value)
+                    open = null;
+            }
+        }
+        return result;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    /// <summary>
+    /// A file's lines, its lines with comments and strings blanked, and the identifiers its code uses
+    /// </summary>
+    private sealed class SourceFile
+    {
+        public SourceFile(string path, string content)
+        {
+            Extension = Path.GetExtension(path).ToLowerInvariant();
+            Lines = content.Replace("\r\n", "\n").Split('\n');
+            Masked = DataFlowScanner.MaskLines(BlankMultilineStrings(Lines), Extension);
+            Identifiers = Masked.SelectMany(l => Regex.Matches(l, UnicodeIdentifiers.IdentifierPattern).Select(m => m.Value)).ToHashSet(StringComparer.Ordinal);
+        }
+
+        public string Extension { get; }
+        public string[] Lines { get; }
+        public string[] Masked { get; }
+        public HashSet<string> Identifiers { get; }
+    }
+}
@@ -0,0 +1,316 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Dockerfile instructions read as declarations: base images (FROM, named by repository: golang,
+/// gcr.io/distroless/static), build stages (FROM ... AS build), build arguments (ARG), environment variables (ENV),
+/// copied paths (COPY and ADD, named by destination) and entry points (ENTRYPOINT and CMD, named by executable).
+/// Continuation lines, parser directives (# escape=`) and heredocs are followed. <see cref="References"/> finds
+/// where a file expands an argument or variable (<c>$PORT</c>, <c>${GO_VERSION:-1.22}</c>) or names a stage
+/// (<c>COPY --from=build</c>), since julie-codesearch extracts nothing from Dockerfiles.
+/// </summary>
+public static class DockerfileSymbols
+{
+    private static readonly Regex Directive = new(@"^#\s*(?<name>\w+)\s*=\s*(?<value>\S+)\s*$", RegexOptions.Compiled);
+    private static readonly Regex Instruction = new(@"^\s*(?<keyword>[A-Za-z]+)(?:\s+|$)", RegexOptions.Compiled);
+    private static readonly Regex HeredocMarker = new(@"<<(?<strip>-?)[""']?(?<marker>[A-Za-z_]\w*)[""']?", RegexOptions.Compiled);
+    private static readonly Regex Token = new(@"(?:""(?:[^""\\]|\\.)*""|'[^']*'|[^\s""'])+", RegexOptions.Compiled);
+    private static readonly Regex JsonString = new(@"""(?<value>(?:[^""\\]|\\.)*)""", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Dockerfiles and Containerfiles, including variants such as Dockerfile.dev and api.Dockerfile
+    /// </summary>
+    public static bool Handles(string filePath)
+    {
+        var name = Path.GetFileName(filePath);
+        return name.Equals("Dockerfile", StringComparison.OrdinalIgnoreCase)
+            || name.Equals("Containerfile", StringComparison.OrdinalIgnoreCase)
+            || name.StartsWith("Dockerfile.", StringComparison.OrdinalIgnoreCase)
+            || name.EndsWith(".Dockerfile", StringComparison.OrdinalIgnoreCase)
+            || name.EndsWith(".dockerfile", StringComparison.OrdinalIgnoreCase);
+    }
+
+    /// <summary>
+    /// Every declaration in a Dockerfile, in source order. Instructions of a named stage have the stage as parent.
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var symbols = new List<JulieSymbol>();
+        JulieSymbol? stage = null;
+        var stageNames = new HashSet<string>(StringComparer.OrdinalIgnoreCase);
+
+        foreach (var instruction in Instructions(lines))
+        {
+            var keyword = instruction.Keyword;
+            var args = instruction.Arguments;
+            var signature = Regex.Replace(instruction.Text.Trim(), @"\s+", " ");
+
+            if (keyword == "FROM")
+            {
+                if (stage != null)
+                    stage.EndLine = LastLineBefore(lines, instruction.Line);
+                stage = null;
+
+                var tokens = Tokens(args).Where(t => !t.Text.StartsWith("--", StringComparison.Ordinal)).ToList();
+                if (tokens.Count == 0)
+                    continue;
+
+                var image = tokens[0];
+                var named = tokens.Count >= 3 && tokens[1].Text.Equals("AS", StringComparison.OrdinalIgnoreCase);
+                if (named)
+                {
+                    stage = Declare(symbols, filePath, "module", tokens[2].Text, instruction.Position(tokens[2].Index), instruction.EndLine, signature, null);
+                }
+
+                // FROM build starts from an earlier stage rather than an image
+                if (!stageNames.Contains(image.Text))
+                {
+                    Declare(symbols, filePath, "import", Repository(image.Text), instruction.Position(image.Index), instruction.EndLine, signature, stage);
+                }
+                if (named)
+                    stageNames.Add(tokens[2].Text);
+                continue;
+            }
+
+            switch (keyword)
+            {
+                case "ARG":
+                    foreach (var token in Tokens(args))
+                    {
+                        var name = token.Text.Split('=')[0];
+                        if (IsVariableName(name))
+                            Declare(symbols, filePath, "variable", name, instruction.Position(token.Index), instruction.EndLine, $"ARG {token.Text}", stage);
+                    }
+                    break;
+
+                case "ENV":
+                    var pairs = Tokens(args);
+                    if (pairs.Count > 0 && !pairs[0].Text.Contains('='))
+                    {
+                        // Legacy form: ENV NAME the rest of the line
+                        if (IsVariableName(pairs[0].Text))
+                            Declare(symbols, filePath, "constant", pairs[0].Text, instruction.Position(pairs[0].Index), instruction.EndLine, signature, stage);
+                        break;
+                    }
+                    foreach (var pair in pairs)
+                    {
+                        var name = pair.Text.Split('=')[0];
+                        if (IsVariableName(name))
+                            Declare(symbols, filePath, "constant", name, instruction.Position(pair.Index), instruction.EndLine, $"ENV {pair.Text}", stage);
+                    }
+                    break;
+
+                case "COPY":
+                case "ADD":
+                    var paths = args.TrimStart().StartsWith('[')
+                        ? JsonString.Matches(args).Select(m => (Text: m.Groups["value"].Value, Index: m.Groups["value"].Index)).ToList()
+                        : Tokens(args).Where(t => !t.Text.StartsWith("--", StringComparison.Ordinal) && !t.Text.StartsWith("<<", StringComparison.Ordinal)).ToList();
+                    if (paths.Count >= 2 || paths.Count == 1 && HeredocMarker.IsMatch(args))
+                    {
+                        var destination = paths[^1];
+                        Declare(symbols, filePath, "field", destination.Text.Trim('"', '\''), instruction.Position(destination.Index), instruction.EndLine, signature, stage);
+                    }
+                    break;
+
+                case "ENTRYPOINT":
+                case "CMD":
+                    var executable = args.TrimStart().StartsWith('[')
+                        ? JsonString.Matches(args).Select(m => (Text: m.Groups["value"].Value, Index: m.Groups["value"].Index)).FirstOrDefault()
+                        : Tokens(args).FirstOrDefault();
+                    // CMD ["--verbose"] only passes arguments to the ENTRYPOINT
+                    if (!string.IsNullOrEmpty(executable.Text) && !executable.Text.StartsWith('-'))
+                        Declare(symbols, filePath, "function", executable.Text.Trim('"', '\''), instruction.Position(executable.Index), instruction.EndLine, signature, stage);
+                    break;
+            }
+        }
+
+        if (stage != null)
+            stage.EndLine = LastLineBefore(lines, lines.Length + 1);
+        foreach (var symbol in symbols)
+            symbol.EndColumn = lines[symbol.EndLine - 1].Length;
+        return symbols.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Dockerfile with the declarations julie-codesearch missed added. Extracted symbols keep
+    /// their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// Where a Dockerfile uses <paramref name="name"/>: $NAME and ${NAME...} expansions for arguments and
+    /// variables, FROM name and --from=name for stages. Comment lines are skipped; heredoc bodies are searched
+    /// since the shell expands them.
+    /// </summary>
+    public static List<(int Line, int Column)> References(string content, string name)
+    {
+        var uses = new List<(int Line, int Column)>();
+        var expansion = new Regex($@"\$(?:{Regex.Escape(name)}(?!\w)|\{{{Regex.Escape(name)}(?=[}}:\-+?]))");
+        var stageUse = new Regex($@"(?:^\s*FROM\s+(?:--\S+\s+)*|--from=)(?<name>{Regex.Escape(name)})(?![\w.:/@-])", RegexOptions.IgnoreCase);
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (lines[i].TrimStart().StartsWith('#'))
+                continue;
+
+            foreach (Match match in expansion.Matches(lines[i]))
+                uses.Add((i + 1, match.Index));
+            foreach (Match match in stageUse.Matches(lines[i]))
+                uses.Add((i + 1, match.Groups["name"].Index));
+        }
+        return uses.Distinct().OrderBy(u => u.Line).ThenBy(u => u.Column).ToList();
+    }
+
+    /// <summary>
+    /// The repository of an image reference without its tag or digest; a registry port is kept:
+    /// golang:1.22-alpine → golang, gcr.io/distroless/static@sha256:... → gcr.io/distroless/static
+    /// </summary>
+    public static string Repository(string image)
+    {
+        var withoutDigest = image.Split('@')[0];
+        var lastSlash = withoutDigest.LastIndexOf('/');
+        var colon = withoutDigest.IndexOf(':', lastSlash + 1);
+        return colon > 0 ? withoutDigest[..colon] : withoutDigest;
+    }
+
+    private static bool IsVariableName(string name) => Regex.IsMatch(name, @"^[A-Za-z_]\w*$");
+
+    private static List<(string Text, int Index)> Tokens(string text) =>
+        Token.Matches(text).Select(m => (m.Value, m.Index)).ToList();
+
+    private static int LastLineBefore(string[] lines, int line)
+    {
+        var last = line - 1;
+        while (last > 1 && (lines[last - 1].Trim().Length == 0 || lines[last - 1].TrimStart().StartsWith('#')))
+            last--;
+        return Math.Max(1, last);
+    }
+
+    private static JulieSymbol Declare(List<JulieSymbol> symbols, string filePath, string kind, string name,
+        (int Line, int Column) position, int endLine, string signature, JulieSymbol? parent)
+    {
+        var symbol = new JulieSymbol
+        {
+            Id = StableId(filePath, kind, name, position.Line),
+            Name = name,
+            Kind = kind,
+            Language = "dockerfile",
+            FilePath = filePath,
+            StartLine = position.Line,
+            StartColumn = position.Column,
+            EndLine = endLine,
+            Signature = signature,
+            Visibility = "public",
+            ParentId = parent?.Id
+        };
+        symbols.Add(symbol);
+        return symbol;
+    }
+
+    /// <summary>
+    /// Logical instructions with continuation lines joined; the <c>#</c> comment lines Docker allows inside a
+    /// continuation and the bodies of heredocs are left out
+    /// </summary>
+    private static IEnumerable<LogicalInstruction> Instructions(string[] lines)
+    {
+        var escape = '\\';
+        var i = 0;
+
+        // Parser directives come first, before any comment or instruction
+        while (i < lines.Length && Directive.Match(lines[i].Trim()) is { Success: true } directive)
+        {
+            if (directive.Groups["name"].Value.Equals("escape", StringComparison.OrdinalIgnoreCase) && directive.Groups["value"].Value.Length == 1)
+                escape = directive.Groups["value"].Value[0];
+            i++;
+        }
+
+        for (; i < lines.Length; i++)
+        {
+            var trimmed = lines[i].Trim();
+            if (trimmed.Length == 0 || trimmed.StartsWith('#'))
+                continue;
+
+            var text = new StringBuilder();
+            var positions = new List<(int Line, int Column)>();
+            var start = i;
+            while (true)
+            {
+                var line = lines[i];
+                var body = line.TrimEnd();
+                var continues = body.EndsWith(escape) && i + 1 < lines.Length;
+                if (continues)
+                    body = body[..^1];
+                for (var c = 0; c < body.Length; c++)
+                {
+                    text.Append(body[c]);
+                    positions.Add((i + 1, c));
+                }
+                if (!continues)
+                    break;
+
+                text.Append(' ');
+                positions.Add((i + 1, body.Length));
+                i++;
+                while (i + 1 < lines.Length && (lines[i].TrimStart().StartsWith('#') || lines[i].Trim().Length == 0))
+                    i++;
+            }
+
+            var logical = text.ToString();
+            var keyword = Instruction.Match(logical);
+            if (!keyword.Success)
+                continue;
+
+            var instruction = new LogicalInstruction
+            {
+                Keyword = keyword.Groups["keyword"].Value.ToUpperInvariant(),
+                Text = logical,
+                Arguments = logical[keyword.Length..],
+                ArgumentsOffset = keyword.Length,
+                Positions = positions,
+                Line = start + 1,
+                EndLine = i + 1
+            };
+
+            // Heredoc bodies follow the instruction up to their closing marker
+            foreach (Match heredoc in HeredocMarker.Matches(instruction.Arguments))
+            {
+                var marker = heredoc.Groups["marker"].Value;
+                var strip = heredoc.Groups["strip"].Value.Length > 0;
+                while (i + 1 < lines.Length)
+                {
+                    i++;
+                    if ((strip ? lines[i].TrimStart('\t') : lines[i]).TrimEnd() == marker)
+                        break;
+                }
+            }
+
+            yield return instruction;
+        }
+    }
+
+    private static string StableId(string filePath, string kind, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"dockerfile:{filePath}:{kind}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class LogicalInstruction
+    {
+        public string Keyword { get; init; } = string.Empty;
+        public string Text { get; init; } = string.Empty;
+        public string Arguments { get; init; } = string.Empty;
+        public int ArgumentsOffset { get; init; }
+        public List<(int Line, int Column)> Positions { get; init; } = new();
+        public int Line { get; init; }
+        public int EndLine { get; init; }
+
+        /// <summary>
+        /// Physical line and column of an index into <see cref="Arguments"/>
+        /// </summary>
+        public (int Line, int Column) Position(int argumentIndex) => Positions[Math.Min(ArgumentsOffset + argumentIndex, Positions.Count - 1)];
+    }
+}
@@ -0,0 +1,248 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Builds the compact structural summary stored with each indexed file: a one-sentence purpose
+/// (from the main type's doc comment or a file header, otherwise inferred from its symbols),
+/// the key types and the imported modules
+/// </summary>
+public static class FileSummarizer
+{
+    public const int MaxKeySymbols = 5;
+    public const int MaxDependencies = 8;
+    private const int MaxPurposeLength = 160;
+    private const int MaxImportScanLines = 300;
+
+    private static readonly Regex[] ImportPatterns =
+    {
+        // JS/TS "import x from 'y'", "export * from 'y'", side-effect "import 'y'" and Go single imports
+        new(@"^\s*(?:import|export)\b.*?\bfrom\s+['""]([^'""]+)['""]", RegexOptions.Compiled),
+        new(@"^\s*import\s+['""]([^'""]+)['""]", RegexOptions.Compiled),
+        new(@"\brequire(?:_relative)?\s*\(?\s*['""]([^'""]+)['""]", RegexOptions.Compiled),
+        new(@"^\s*(?:global\s+)?using\s+(?:static\s+)?([A-Za-z_][\w.]*)\s*;", RegexOptions.Compiled),
+        new(@"^\s*from\s+([\w.]+)\s+import\b", RegexOptions.Compiled),
+        new(@"^\s*import\s+(?:static\s+)?([A-Za-z_][\w.]*)(?:\.\*)?\s*;?\s*$", RegexOptions.Compiled),
+        new(@"^\s*(?:pub\s+)?use\s+([\w:]+)", RegexOptions.Compiled),
+        new(@"^\s*#\s*include\s+[<""]([^>""]+)[>""]", RegexOptions.Compiled)
+    };
+
+    private static readonly Regex GoImportBlockLine = new(@"^\s*(?:[\w.]+\s+)?""([^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex XmlTag = new(@"<see\s+(?:cref|langword)=""(?:\w:)?([^""]+)""\s*/>|<[^>]+>", RegexOptions.Compiled);
+    private static readonly Regex CommentMarker = new(@"^\s*(?:///|//!|//|/\*\*|/\*|\*/|\*|#!?|--|"""""")\s?", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Summarizes a file. Uses extracted types when available and falls back to the line-based
+    /// <see cref="DeclarationScanner"/>; returns null when there is nothing worth storing.
+    /// </summary>
+    public static FileSummary? Summarize(string content, string filePath, TypeExtractionResult? typeData = null)
+    {
+        var lines = content.Split('\n').Select(l => l.TrimEnd('\r')).ToArray();
+        var symbols = CollectSymbols(content, filePath, typeData);
+
+        var types = symbols.Where(s => s.IsType).ToList();
+        var keySymbols = (types.Count > 0 ? types : symbols)
+            .OrderByDescending(s => s.IsPublic)
+            .ThenBy(s => s.Line)
+            .Take(MaxKeySymbols)
+            .OrderBy(s => s.Line)
+            .ToList();
+
+        var dependencies = ExtractDependencies(lines);
+        var mainSymbol = keySymbols.FirstOrDefault();
+        var purpose = (mainSymbol != null ? DocCommentFor(lines, mainSymbol.Line) : null)
+            ?? FileHeaderComment(lines)
+            ?? InferPurpose(symbols, types);
+
+        if (purpose == null && keySymbols.Count == 0 && dependencies.Count == 0)
+            return null;
+
+        return new FileSummary
+        {
+            Purpose = purpose,
+            KeySymbols = keySymbols.Select(s => s.BaseType != null ? $"{s.Kind} {s.Name} : {s.BaseType}" : $"{s.Kind} {s.Name}").ToList(),
+            Dependencies = dependencies.Take(MaxDependencies).ToList(),
+            DependencyCount = dependencies.Count,
+            TypeCount = types.Count,
+            MemberCount = symbols.Count - types.Count
+        };
+    }
+
+    /// <summary>
+    /// Imported modules in first-seen order, without duplicates
+    /// </summary>
+    public static List<string> ExtractDependencies(IReadOnlyList<string> lines)
+    {
+        var dependencies = new List<string>();
+        var inGoImportBlock = false;
+
+        foreach (var line in lines.Take(MaxImportScanLines))
+        {
+            string? dependency = null;
+            if (inGoImportBlock)
+            {
+                if (line.TrimStart().StartsWith(')'))
+                {
+                    inGoImportBlock = false;
+                    continue;
+                }
+                var match = GoImportBlockLine.Match(line);
+                dependency = match.Success ? match.Groups[1].Value : null;
+            }
+            else if (Regex.IsMatch(line, @"^\s*import\s*\(\s*$"))
+            {
+                inGoImportBlock = true;
+                continue;
+            }
+            else
+            {
+                foreach (var pattern in ImportPatterns)
+                {
+                    var match = pattern.Match(line);
+                    if (match.Success)
+                    {
+                        dependency = match.Groups[1].Value;
+                        break;
+                    }
+                }
+            }
+
+            if (!string.IsNullOrEmpty(dependency) && !dependencies.Contains(dependency))
+                dependencies.Add(dependency);
+        }
+
+        return dependencies;
+    }
+
+    /// <summary>
+    /// First sentence of the comment directly above a declaration (1-based line), or of a Python
+    /// docstring directly below it
+    /// </summary>
+    public static string? DocCommentFor(IReadOnlyList<string> lines, int line)
+    {
+        var index = line - 2;
+
+        // Skip attributes and decorators between the comment and the declaration
+        while (index >= 0 && Regex.IsMatch(lines[index], @"^\s*(?:\[.*\]|@\w+.*)\s*$"))
+            index--;
+
+        var block = new List<string>();
+        while (index >= 0 && IsCommentLine(lines[index]))
+        {
+            block.Insert(0, lines[index]);
+            index--;
+        }
+
+        if (block.Count == 0 && line >= 1 && line < lines.Count && lines[line].TrimStart().StartsWith("\"\"\""))
+        {
+            for (var i = line; i < lines.Count && i < line + 10; i++)
+            {
+                block.Add(lines[i]);
+                if (i > line && lines[i].Contains("\"\"\"") || i == line && lines[i].Trim().Length > 6 && lines[i].Trim().EndsWith("\"\"\""))
+                    break;
+            }
+        }
+
+        return FirstSentence(block);
+    }
+
+    private static string? FileHeaderComment(IReadOnlyList<string> lines)
+    {
+        var block = new List<string>();
+        foreach (var line in lines.SkipWhile(l => string.IsNullOrWhiteSpace(l) || l.StartsWith("#!")))
+        {
+            if (!IsCommentLine(line) && !(block.Count == 0 && line.TrimStart().StartsWith("\"\"\"")) && !(block.Count > 0 && block[0].TrimStart().StartsWith("\"\"\"")))
+                break;
+            block.Add(line);
+            if (block.Count > 1 && line.Contains("\"\"\"") || block.Count > 15)
+                break;
+        }
+
+        var text = FirstSentence(block);
+
+        // License banners and generated-code notices say nothing about the file
+        return text != null && Regex.IsMatch(text, @"copyright|license|auto-?generated|do not edit|<auto-generated", RegexOptions.IgnoreCase)
+            ? null
+            : text;
+    }
+
+    private static string? InferPurpose(List<SummarySymbol> symbols, List<SummarySymbol> types)
+    {
+        if (symbols.Count == 0)
+            return null;
+
+        if (types.Count == 0)
+            return $"{symbols.Count} function(s): {string.Join(", ", symbols.Take(3).Select(s => s.Name))}{(symbols.Count > 3 ? ", ..." : "")}";
+
+        var main = types.OrderByDescending(t => t.IsPublic).ThenBy(t => t.Line).First();
+        var members = symbols.Count - types.Count;
+        var purpose = $"Defines {main.Kind} {main.Name}";
+        if (main.BaseType != null)
+            purpose += $" ({main.BaseType})";
+        if (types.Count > 1)
+            purpose += $" and {types.Count - 1} other type(s)";
+        return members > 0 ? $"{purpose} with {members} member(s)" : purpose;
+    }
+
+    private static bool IsCommentLine(string line)
+    {
+        var trimmed = line.TrimStart();
+        return trimmed.StartsWith("//") || trimmed.StartsWith("/*") || trimmed.StartsWith('*') ||
+               trimmed.StartsWith('#') && !trimmed.StartsWith("#include") && !trimmed.StartsWith("#region") && !trimmed.StartsWith("#pragma") ||
+               trimmed.StartsWith("--");
+    }
+
+    /// <summary>
+    /// Text of a comment block without comment markers, XML doc tags or @-tag lines, on one line
+    /// </summary>
+    public static string? CommentText(IEnumerable<string> block)
+    {
+        var text = string.Join(" ", block
+            .Select(l => CommentMarker.Replace(l.Trim(), "").Replace("\"\"\"", "").Replace("*/", ""))
+            .Select(l => XmlTag.Replace(l, m => m.Groups[1].Success ? m.Groups[1].Value : " ").Trim())
+            .Where(l => l.Length > 0 && !l.StartsWith('@')));
+        text = Regex.Replace(text, @"\s+", " ").Trim();
+        return text.Length == 0 ? null : text;
+    }
+
+    private static string? FirstSentence(List<string> block)
+    {
+        var text = CommentText(block);
+        if (text == null)
+            return null;
+
+        var end = Regex.Match(text, @"[.!?](?:\s|$)");
+        if (end.Success)
+            text = text[..(end.Index + 1)];
+
+        return text.Length > MaxPurposeLength ? text[..(MaxPurposeLength - 3)].TrimEnd() + "..." : text;
+    }
+
+    private static List<SummarySymbol> CollectSymbols(string content, string filePath, TypeExtractionResult? typeData)
+    {
+        if (typeData?.Success == true && (typeData.Types.Count > 0 || typeData.Methods.Count > 0))
+        {
+            return typeData.Types
+                .Select(t => new SummarySymbol(t.Name, t.Kind.ToLowerInvariant(), t.Line, IsPublicModifier(t.Modifiers), t.BaseType, true))
+                .Concat(typeData.Methods
+                    .Select(m => new SummarySymbol(m.Name, "function", m.Line, IsPublicModifier(m.Modifiers), null, false)))
+                .ToList();
+        }
+
+        if (!DeclarationScanner.Supports(filePath))
+            return new List<SummarySymbol>();
+
+        return DeclarationScanner.Scan(content, filePath)
+            .Where(d => d.Kind != "property")
+            .Select(d => new SummarySymbol(d.Name, d.Kind, d.Line, d.IsPublic, null, d.IsType))
+            .ToList();
+    }
+
+    // Julie reports visibility as a single modifier; no modifier means we can't tell, so don't penalize it
+    private static bool IsPublicModifier(List<string> modifiers) =>
+        modifiers.Count == 0 || modifiers.Any(m => m is "public" or "export" or "pub" or "exported");
+
+    private sealed record SummarySymbol(string Name, string Kind, int Line, bool IsPublic, string? BaseType, bool IsType);
+}
@@ -0,0 +1,222 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// GOOS, GOARCH and custom tags a build is configured with
+/// </summary>
+public class GoBuildProfile
+{
+    public string Goos { get; set; } = string.Empty;
+    public string Goarch { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Custom tags as passed to go build -tags (integration, cgo, netgo)
+    /// </summary>
+    public List<string> Tags { get; set; } = new();
+
+    public override string ToString() => $"{Goos}/{Goarch}" + (Tags.Count > 0 ? $" +{string.Join(",", Tags)}" : string.Empty);
+}
+
+/// <summary>
+/// Go build constraints from source text: the <c>//go:build</c> line, legacy <c>// +build</c> lines, and the
+/// GOOS/GOARCH suffixes of the file name (<c>_linux.go</c>, <c>_windows_amd64.go</c>), combined into one
+/// expression and evaluated against a build profile the way the go command does
+/// </summary>
+public static class GoBuildConstraints
+{
+    private static readonly Regex GoBuild = new(@"^//go:build\s+(?<expr>.+)$", RegexOptions.Compiled);
+    private static readonly Regex PlusBuild = new(@"^//\s*\+build\s+(?<expr>.+)$", RegexOptions.Compiled);
+    private static readonly Regex Token = new(@"\s*(?<token>&&|\|\||!|\(|\)|[\w.]+)", RegexOptions.Compiled);
+
+    public static readonly HashSet<string> KnownOs = new(StringComparer.Ordinal)
+    {
+        "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js", "linux", "nacl",
+        "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos"
+    };
+
+    public static readonly HashSet<string> KnownArch = new(StringComparer.Ordinal)
+    {
+        "386", "amd64", "arm", "arm64", "loong64", "mips", "mipsle", "mips64", "mips64le", "ppc64", "ppc64le",
+        "riscv64", "s390x", "sparc64", "wasm"
+    };
+
+    private static readonly HashSet<string> UnixOs = new(StringComparer.Ordinal)
+    {
+        "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "linux", "netbsd", "openbsd", "solaris"
+    };
+
+    /// <summary>
+    /// The file's build constraint as one expression (<c>linux &amp;&amp; (amd64 || arm64)</c>); null when
+    /// it builds everywhere
+    /// </summary>
+    public static string? Read(string filePath, IEnumerable<string> lines)
+    {
+        string? goBuild = null;
+        var plusBuild = new List<string>();
+
+        // Constraints sit in the header, before the package clause, among comments and blank lines
+        var inBlockComment = false;
+        foreach (var raw in lines)
+        {
+            var line = raw.Trim();
+            if (inBlockComment)
+            {
+                inBlockComment = !line.Contains("*/", StringComparison.Ordinal);
+                continue;
+            }
+            if (line.Length == 0)
+                continue;
+            if (line.StartsWith("/*", StringComparison.Ordinal))
+            {
+                inBlockComment = !line.Contains("*/", StringComparison.Ordinal);
+                continue;
+            }
+            if (!line.StartsWith("//", StringComparison.Ordinal))
+                break;
+
+            if (GoBuild.Match(line) is { Success: true } build)
+                goBuild ??= build.Groups["expr"].Value.Trim();
+            else if (PlusBuild.Match(line) is { Success: true } plus)
+                plusBuild.Add(FromPlusBuild(plus.Groups["expr"].Value));
+        }
+
+        var parts = new List<string>();
+        if (goBuild != null)
+            parts.Add(goBuild);
+        else
+            parts.AddRange(plusBuild);
+        if (FromFileName(filePath) is { } implied)
+            parts.Add(implied);
+
+        return parts.Count switch
+        {
+            0 => null,
+            1 => parts[0],
+            _ => string.Join(" && ", parts.Select(p => HasTopLevelOr(p) ? $"({p})" : p))
+        };
+    }
+
+    private static bool HasTopLevelOr(string expression)
+    {
+        var depth = 0;
+        for (var i = 0; i < expression.Length; i++)
+        {
+            if (expression[i] == '(') depth++;
+            else if (expression[i] == ')') depth--;
+            else if (depth == 0 && string.CompareOrdinal(expression, i, "||", 0, 2) == 0) return true;
+        }
+        return false;
+    }
+
+    /// <summary>
+    /// The GOOS and GOARCH a file name restricts it to: <c>poll_linux_arm64_test.go</c> gives
+    /// <c>linux &amp;&amp; arm64</c>. A name that is only the suffix (<c>linux.go</c>) is unconstrained.
+    /// </summary>
+    public static string? FromFileName(string filePath)
+    {
+        var name = Path.GetFileNameWithoutExtension(filePath);
+        if (name.EndsWith("_test", StringComparison.Ordinal))
+            name = name[..^5];
+        var parts = name.Split('_');
+        if (parts.Length < 2)
+            return null;
+
+        var last = parts[^1];
+        if (parts.Length >= 3 && KnownOs.Contains(parts[^2]) && KnownArch.Contains(last))
+            return $"{parts[^2]} && {last}";
+        return KnownOs.Contains(last) || KnownArch.Contains(last) ? last : null;
+    }
+
+    /// <summary>
+    /// Whether a constraint holds for the profile; true for a null constraint and for one that doesn't parse
+    /// </summary>
+    public static bool Matches(string? constraint, GoBuildProfile profile)
+    {
+        if (string.IsNullOrWhiteSpace(constraint))
+            return true;
+
+        var tokens = Token.Matches(constraint).Select(m => m.Groups["token"].Value).ToList();
+        var position = 0;
+        try
+        {
+            var result = Or(tokens, ref position, profile);
+
+            // Left-over tokens mean it didn't parse; the go command rejects such a file rather than skipping it
+            return position < tokens.Count || result;
+        }
+        catch (FormatException)
+        {
+            return true;
+        }
+    }
+
+    /// <summary>
+    /// Whether a single tag is satisfied: GOOS, GOARCH, unix for Unix systems, gc, any go1.N release and the
+    /// profile's custom tags. android also satisfies linux, illumos solaris and ios darwin, as in the go command.
+    /// </summary>
+    public static bool HasTag(string tag, GoBuildProfile profile) =>
+        tag == profile.Goos || tag == profile.Goarch
+        || tag == "unix" && UnixOs.Contains(profile.Goos)
+        || tag == "linux" && profile.Goos == "android"
+        || tag == "solaris" && profile.Goos == "illumos"
+        || tag == "darwin" && profile.Goos == "ios"
+        || tag == "gc"
+        || tag.StartsWith("go1.", StringComparison.Ordinal)
+        || profile.Tags.Contains(tag, StringComparer.Ordinal);
+
+    // "// +build linux,amd64 darwin" means (linux && amd64) || darwin; several lines are ANDed by the caller
+    private static string FromPlusBuild(string expression)
+    {
+        var options = expression.Split(' ', StringSplitOptions.RemoveEmptyEntries)
+            .Select(o => string.Join(" && ", o.Split(',', StringSplitOptions.RemoveEmptyEntries)))
+            .ToList();
+        return options.Count == 1
+            ? options[0]
+            : string.Join(" || ", options.Select(o => o.Contains("&&", StringComparison.Ordinal) ? $"({o})" : o));
+    }
+
+    private static bool Or(List<string> tokens, ref int position, GoBuildProfile profile)
+    {
+        var result = And(tokens, ref position, profile);
+        while (position < tokens.Count && tokens[position] == "||")
+        {
+            position++;
+            result |= And(tokens, ref position, profile);
+        }
+        return result;
+    }
+
+    private static bool And(List<string> tokens, ref int position, GoBuildProfile profile)
+    {
+        var result = Not(tokens, ref position, profile);
+        while (position < tokens.Count && tokens[position] == "&&")
+        {
+            position++;
+            result &= Not(tokens, ref position, profile);
+        }
+        return result;
+    }
+
+    private static bool Not(List<string> tokens, ref int position, GoBuildProfile profile)
+    {
+        if (position >= tokens.Count)
+            throw new FormatException("Unexpected end of build constraint");
+
+        var token = tokens[position++];
+        switch (token)
+        {
+            case "!":
+                return !Not(tokens, ref position, profile);
+            case "(":
+                var result = Or(tokens, ref position, profile);
+                if (position >= tokens.Count || tokens[position++] != ")")
+                    throw new FormatException("Missing ')' in build constraint");
+                return result;
+            case "&&" or "||" or ")":
+                throw new FormatException($"Unexpected '{token}' in build constraint");
+            default:
+                return HasTag(token, profile);
+        }
+    }
+}
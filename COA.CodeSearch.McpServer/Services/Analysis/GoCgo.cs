@@ -0,0 +1,264 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// The C preamble of a cgo file: the comment directly above <c>import "C"</c>
+/// </summary>
+public class CgoPreamble
+{
+    /// <summary>
+    /// First and last comment lines, 1-based
+    /// </summary>
+    public int StartLine { get; set; }
+    public int EndLine { get; set; }
+
+    /// <summary>
+    /// The line of <c>import "C"</c> (or of <c>"C"</c> in an import group)
+    /// </summary>
+    public int ImportLine { get; set; }
+
+    /// <summary>
+    /// #cgo directives as written: <c>#cgo LDFLAGS: -lm</c>
+    /// </summary>
+    public List<string> Directives { get; set; } = new();
+
+    /// <summary>
+    /// Included headers: <c>&lt;stdlib.h&gt;</c>, <c>"bridge.h"</c>
+    /// </summary>
+    public List<string> Includes { get; set; } = new();
+
+    /// <summary>
+    /// Functions, structs, typedefs, enums and macros the preamble declares - what Go reaches as C.name
+    /// </summary>
+    public List<CgoDeclaration> Declarations { get; set; } = new();
+}
+
+/// <summary>
+/// A C name declared in a cgo preamble
+/// </summary>
+public class CgoDeclaration
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// function, struct, union, enum, typedef or macro
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// Keeps cgo preambles out of Go symbol extraction. The preamble is C inside a Go comment; parsers read it as
+/// garbage declarations or stop at it. Symbols inside it are dropped, the whole preamble becomes one opaque
+/// <c>C</c> module symbol listing what it declares, and when nothing after <c>import "C"</c> was extracted the
+/// Go declarations are recovered with the line scanner.
+/// </summary>
+public static class GoCgo
+{
+    private static readonly Regex ImportC = new(@"^\s*import\s+(?:_\s+)?""C""\s*(?://.*)?$", RegexOptions.Compiled);
+    private static readonly Regex GroupedC = new(@"^\s*""C""\s*(?://.*)?$", RegexOptions.Compiled);
+    private static readonly Regex Directive = new(@"^\s*#\s*cgo\b.*$", RegexOptions.Compiled);
+    private static readonly Regex Include = new(@"^\s*#\s*include\s*(?<header><[^>]+>|""[^""]+"")", RegexOptions.Compiled);
+    private static readonly Regex Define = new(@"^\s*#\s*define\s+(?<name>[A-Za-z_]\w*)", RegexOptions.Compiled);
+    private static readonly Regex Tagged = new(@"^\s*(?:typedef\s+)?(?<kind>struct|union|enum)\s+(?<name>[A-Za-z_]\w*)\s*\{", RegexOptions.Compiled);
+    private static readonly Regex Typedef = new(@"^\s*typedef\b(?:.*\}\s*|[^;(]*?[\s*])(?<name>[A-Za-z_]\w*)\s*(?:\[[^\]]*\]\s*)?;", RegexOptions.Compiled);
+    private static readonly Regex TypedefClose = new(@"^\s*\}\s*(?<name>[A-Za-z_]\w*)\s*(?:\[[^\]]*\]\s*)?;", RegexOptions.Compiled);
+    private static readonly Regex FunctionPointerTypedef = new(@"^\s*typedef\b[^;]*\(\s*\*\s*(?<name>[A-Za-z_]\w*)\s*\)", RegexOptions.Compiled);
+    private static readonly Regex Function = new(
+        @"^\s*(?:(?:static|inline|extern|const|unsigned|signed|struct|enum|__attribute__\s*\(\([^)]*\)\))\s+)*[A-Za-z_]\w*[\s*]+(?<name>[A-Za-z_]\w*)\s*\([^;{]*\)?\s*(?:\{|;|$)",
+        RegexOptions.Compiled);
+    private static readonly HashSet<string> NotFunctions = new(StringComparer.Ordinal) { "if", "for", "while", "switch", "return", "sizeof", "else" };
+
+    /// <summary>
+    /// The preamble in a Go file, or null when the file does not import "C" or nothing comments the import
+    /// </summary>
+    public static CgoPreamble? FindPreamble(string content)
+    {
+        if (!content.Contains("\"C\"", StringComparison.Ordinal))
+            return null;
+
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (!ImportC.IsMatch(lines[i]) && !(GroupedC.IsMatch(lines[i]) && InImportGroup(lines, i)))
+                continue;
+
+            // cgo only reads a comment that ends on the line right above the import
+            var end = i - 1;
+            if (end < 0)
+                return null;
+            var start = end;
+            var last = lines[end].Trim();
+            if (last.EndsWith("*/", StringComparison.Ordinal))
+            {
+                while (start >= 0 && !lines[start].Contains("/*", StringComparison.Ordinal))
+                    start--;
+                if (start < 0)
+                    return null;
+            }
+            else if (last.StartsWith("//", StringComparison.Ordinal))
+            {
+                while (start > 0 && lines[start - 1].TrimStart().StartsWith("//", StringComparison.Ordinal))
+                    start--;
+            }
+            else
+            {
+                return null;
+            }
+
+            var preamble = new CgoPreamble { StartLine = start + 1, EndLine = end + 1, ImportLine = i + 1 };
+            ReadPreamble(preamble, lines, start, end);
+            return preamble;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// The Go symbols of a cgo file with the preamble's symbols replaced by one opaque block. Returns
+    /// <paramref name="symbols"/> itself when the file has no preamble.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols)
+    {
+        var preamble = FindPreamble(content);
+        if (preamble == null)
+            return symbols;
+
+        var kept = symbols
+            .Where(s => s.StartLine < preamble.StartLine || s.StartLine > preamble.EndLine)
+            .Where(s => !(s.StartLine == preamble.ImportLine && s.Name == "C" && s.Kind == "module"))
+            .ToList();
+
+        // A parser that gave up at the preamble leaves the rest of the file empty
+        if (!kept.Any(s => s.StartLine > preamble.ImportLine))
+        {
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            for (var i = preamble.StartLine - 1; i < preamble.EndLine && i < lines.Length; i++)
+                lines[i] = string.Empty;
+            kept.AddRange(DeclarationScanner.Scan(string.Join("\n", lines), filePath)
+                .Where(d => d.Line > preamble.ImportLine)
+                .Select(d => ToSymbol(filePath, d)));
+        }
+
+        kept.Add(new JulieSymbol
+        {
+            Id = StableId(filePath, "C", preamble.StartLine),
+            Name = "C",
+            Kind = "module",
+            Language = "go",
+            FilePath = filePath,
+            StartLine = preamble.StartLine,
+            StartColumn = 0,
+            EndLine = preamble.ImportLine,
+            EndColumn = 0,
+            Signature = "import \"C\"",
+            DocComment = Describe(preamble),
+            Visibility = "private"
+        });
+        return kept.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList();
+    }
+
+    /// <summary>
+    /// One line per part of the preamble: headers, cgo flags and the C names it declares
+    /// </summary>
+    public static string Describe(CgoPreamble preamble)
+    {
+        var text = new StringBuilder($"cgo preamble, lines {preamble.StartLine}-{preamble.EndLine}");
+        if (preamble.Includes.Count > 0)
+            text.Append("\nincludes: ").Append(string.Join(", ", preamble.Includes));
+        if (preamble.Directives.Count > 0)
+            text.Append("\n").Append(string.Join("\n", preamble.Directives));
+        foreach (var group in preamble.Declarations.GroupBy(d => d.Kind))
+            text.Append('\n').Append(group.Key).Append(": ").Append(string.Join(", ", group.Select(d => d.Name)));
+        return text.ToString();
+    }
+
+    private static void ReadPreamble(CgoPreamble preamble, string[] lines, int start, int end)
+    {
+        var depth = 0;
+        var inTypedef = false;
+        for (var i = start; i <= end; i++)
+        {
+            var line = lines[i];
+            var text = line.TrimStart();
+            if (text.StartsWith("//", StringComparison.Ordinal))
+                text = text[2..];
+            text = text.Replace("/*", string.Empty).Replace("*/", string.Empty);
+
+            if (Directive.Match(text) is { Success: true } directive)
+            {
+                preamble.Directives.Add(directive.Value.Trim());
+                continue;
+            }
+            if (Include.Match(text) is { Success: true } include)
+            {
+                preamble.Includes.Add(include.Groups["header"].Value);
+                continue;
+            }
+            if (Define.Match(text) is { Success: true } define)
+            {
+                Add(preamble, define.Groups["name"].Value, "macro", i);
+                continue;
+            }
+
+            // Only file-scope declarations; bodies of inline functions are skipped by brace depth
+            if (depth == 0)
+            {
+                inTypedef = text.TrimStart().StartsWith("typedef", StringComparison.Ordinal) && text.Contains('{');
+                if (Tagged.Match(text) is { Success: true } tagged)
+                    Add(preamble, tagged.Groups["name"].Value, tagged.Groups["kind"].Value, i);
+                else if (FunctionPointerTypedef.Match(text) is { Success: true } pointer)
+                    Add(preamble, pointer.Groups["name"].Value, "typedef", i);
+                else if (Typedef.Match(text) is { Success: true } typedef)
+                    Add(preamble, typedef.Groups["name"].Value, "typedef", i);
+                else if (Function.Match(text) is { Success: true } function && !NotFunctions.Contains(function.Groups["name"].Value))
+                    Add(preamble, function.Groups["name"].Value, "function", i);
+            }
+            else if (inTypedef && depth == 1 && TypedefClose.Match(text) is { Success: true } closing)
+            {
+                // typedef struct { ... } name;
+                Add(preamble, closing.Groups["name"].Value, "typedef", i);
+            }
+            depth = Math.Max(0, depth + text.Count(c => c == '{') - text.Count(c => c == '}'));
+        }
+    }
+
+    private static void Add(CgoPreamble preamble, string name, string kind, int index)
+    {
+        if (!preamble.Declarations.Any(d => d.Name == name && d.Kind == kind))
+            preamble.Declarations.Add(new CgoDeclaration { Name = name, Kind = kind, Line = index + 1 });
+    }
+
+    private static bool InImportGroup(string[] lines, int index)
+    {
+        for (var i = index - 1; i >= 0; i--)
+        {
+            var text = lines[i].Trim();
+            if (text.StartsWith("import (", StringComparison.Ordinal) || text == "import(")
+                return true;
+            if (text.StartsWith(')') || text.StartsWith("func ", StringComparison.Ordinal) || text.StartsWith("type ", StringComparison.Ordinal))
+                return false;
+        }
+        return false;
+    }
+
+    private static JulieSymbol ToSymbol(string filePath, Declaration declaration) => new()
+    {
+        Id = StableId(filePath, declaration.QualifiedName, declaration.Line),
+        Name = declaration.Name,
+        Kind = declaration.Kind,
+        Language = "go",
+        FilePath = filePath,
+        StartLine = declaration.Line,
+        EndLine = declaration.EndLine > 0 ? declaration.EndLine : declaration.Line,
+        Signature = declaration.Signature,
+        Visibility = declaration.IsPublic ? "public" : "private"
+    };
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"cgo:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+}
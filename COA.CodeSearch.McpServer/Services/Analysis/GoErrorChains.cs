@@ -0,0 +1,871 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Error handling facts of one Go file: package-level sentinel errors, types with an <c>Error() string</c>
+/// method, and per function the statements that create, wrap, pass on or inspect errors
+/// </summary>
+public class GoErrorFile
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string Package { get; set; } = string.Empty;
+    public bool Test { get; set; }
+    public List<GoErrorSentinel> Sentinels { get; set; } = new();
+    public List<GoErrorType> Types { get; set; } = new();
+    public List<GoErrorFunction> Functions { get; set; } = new();
+}
+
+/// <summary>
+/// A package-level error value (<c>var ErrNotFound = errors.New("not found")</c>)
+/// </summary>
+public class GoErrorSentinel
+{
+    public string Name { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string? Message { get; set; }
+
+    /// <summary>
+    /// Sentinels this one wraps with %w, so errors.Is matches them too
+    /// </summary>
+    public List<string> Wraps { get; set; } = new();
+}
+
+/// <summary>
+/// A type implementing error through an <c>Error() string</c> method
+/// </summary>
+public class GoErrorType
+{
+    public string Name { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A func or method with the error statements in its body, in source order
+/// </summary>
+public class GoErrorFunction
+{
+    public string Name { get; set; } = string.Empty;
+    public string? Receiver { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public string Package { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public int EndLine { get; set; }
+    public List<GoErrorEvent> Events { get; set; } = new();
+
+    public string DisplayName => Receiver == null ? Name : $"{Receiver}.{Name}";
+    public string Key => $"{FilePath}:{Line}";
+}
+
+/// <summary>
+/// One error statement inside a function
+/// </summary>
+public class GoErrorEvent
+{
+    /// <summary>
+    /// new (errors.New, fmt.Errorf without %w), wrap (fmt.Errorf with %w, errors.Join), literal (a composite
+    /// literal such as <c>&amp;NotFoundError{}</c>), call (a call whose error result is kept or returned),
+    /// return or check
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Format or message of new and wrap
+    /// </summary>
+    public string? Format { get; set; }
+
+    /// <summary>
+    /// Expressions wrapped by a wrap
+    /// </summary>
+    public List<string> Wrapped { get; set; } = new();
+
+    /// <summary>
+    /// Called function or method name of a call, without qualifier
+    /// </summary>
+    public string? Callee { get; set; }
+
+    /// <summary>
+    /// Package or receiver expression before the callee (<c>users</c>, <c>s.store</c>)
+    /// </summary>
+    public string? Qualifier { get; set; }
+
+    /// <summary>
+    /// Error variable a call assigns, or the variable a check inspects
+    /// </summary>
+    public string? Variable { get; set; }
+
+    /// <summary>
+    /// A call made directly in a return statement
+    /// </summary>
+    public bool Returned { get; set; }
+
+    /// <summary>
+    /// Type of a literal, or sentinel/type a check compares against (qualifier dropped)
+    /// </summary>
+    public string? Target { get; set; }
+
+    /// <summary>
+    /// is, as, equals or case for checks
+    /// </summary>
+    public string? CheckKind { get; set; }
+
+    /// <summary>
+    /// Returned expressions of a return, strings blanked
+    /// </summary>
+    public string? Text { get; set; }
+}
+
+/// <summary>
+/// What a trace started from
+/// </summary>
+public class GoErrorOrigin
+{
+    /// <summary>
+    /// sentinel, type or site
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string? Message { get; set; }
+
+    /// <summary>
+    /// Sentinels and types errors.Is/errors.As would match for this error: the origin itself, sentinels
+    /// wrapping it and any sentinel or type a construction site wraps
+    /// </summary>
+    public List<string> Matches { get; set; } = new();
+}
+
+/// <summary>
+/// A function the error passes through, and how
+/// </summary>
+public class GoErrorHop
+{
+    public string Function { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// produces, wraps, returns, checks or handles (neither wrapped nor returned - logged, swallowed or replaced)
+    /// </summary>
+    public string How { get; set; } = string.Empty;
+
+    public string? Format { get; set; }
+
+    /// <summary>
+    /// Message of the error after this hop, with the formats of every wrap applied
+    /// </summary>
+    public string? Message { get; set; }
+
+    public int Depth { get; set; }
+
+    /// <summary>
+    /// Key of the function this hop received the error from; null for producers
+    /// </summary>
+    public string? From { get; set; }
+
+    public string Key { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// An errors.Is/errors.As/==/case check for the error
+/// </summary>
+public class GoErrorCheck
+{
+    public string Function { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Kind { get; set; } = string.Empty;
+    public string Target { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The check is in a function the trace reached, so it sees errors from the origin
+    /// </summary>
+    public bool OnPath { get; set; }
+}
+
+public class GoErrorTrace
+{
+    public GoErrorOrigin Origin { get; set; } = new();
+    public List<GoErrorHop> Hops { get; set; } = new();
+    public List<GoErrorCheck> Checks { get; set; } = new();
+
+    /// <summary>
+    /// Each propagation path from a producer to where the error stops, one line per path
+    /// </summary>
+    public List<string> Paths { get; set; } = new();
+
+    /// <summary>
+    /// The depth limit stopped at least one path
+    /// </summary>
+    public bool DepthLimited { get; set; }
+}
+
+/// <summary>
+/// Traces Go error wrap chains: from a sentinel error, an error type or an error construction site, up
+/// through the callers that wrap it with <c>fmt.Errorf("...: %w", err)</c> or return it, to the
+/// <c>errors.Is</c>/<c>errors.As</c> checks that look for it. Callers are matched by name, so same-named
+/// functions in other packages may add extra paths.
+/// </summary>
+public static class GoErrorChains
+{
+    private static readonly Regex Package = new(@"^package\s+(?<name>\w+)", RegexOptions.Compiled);
+
+    private static readonly Regex Func = new(
+        @"^func\s*(?:\(\s*(?:\w+\s+)?\*?(?<receiver>\w+)(?:\[[^\]]*\])?\s*\)\s*)?(?<name>\w+)", RegexOptions.Compiled);
+
+    private static readonly Regex ErrorMethod = new(
+        @"^func\s*\(\s*(?:\w+\s+)?\*?(?<type>\w+)(?:\[[^\]]*\])?\s*\)\s*Error\s*\(\s*\)\s*string\b", RegexOptions.Compiled);
+
+    private static readonly Regex SentinelDeclaration = new(
+        @"^\s*(?:var\s+)?(?<name>[\p{L}_]\w*)\s*(?:error\s*)?=\s*(?<ctor>errors\.New|fmt\.Errorf)\s*\(", RegexOptions.Compiled);
+
+    private static readonly Regex Constructor = new(@"(?<![\w.])(?<ctor>errors\.New|errors\.Join|fmt\.Errorf)\s*\(", RegexOptions.Compiled);
+
+    private static readonly Regex Literal = new(@"(?<![\w.])&?(?:\w+\.)?(?<type>[A-Z]\w*)\s*\{", RegexOptions.Compiled);
+
+    private static readonly Regex AssignedCall = new(
+        @"^\s*(?:if\s+)?(?<vars>[\w\s,]+?)\s*:?=\s*(?<callee>[\p{L}_][\w.]*?)\s*(?:\[[^\]]*\])?\s*\(", RegexOptions.Compiled);
+
+    private static readonly Regex ReturnedCall = new(
+        @"^\s*return\s+(?:[^,]+,\s*)*?(?<callee>[\p{L}_][\w.]*?)\s*(?:\[[^\]]*\])?\s*\(", RegexOptions.Compiled);
+
+    private static readonly Regex Return = new(@"^\s*return\b(?<values>.*)$", RegexOptions.Compiled);
+
+    private static readonly Regex IsCheck = new(@"\berrors\.Is\s*\(\s*(?<var>[\w.]+)\s*,\s*(?<target>[\w.]+)\s*\)", RegexOptions.Compiled);
+
+    private static readonly Regex AsCheck = new(
+        @"\berrors\.As\s*\(\s*(?<var>[\w.]+)\s*,\s*(?:&(?<target>\w+)|new\s*\(\s*\*?(?:\w+\.)?(?<type>\w+)\s*\))\s*\)", RegexOptions.Compiled);
+
+    private static readonly Regex EqualsCheck = new(
+        @"\b(?<var>\w+)\s*[!=]=\s*(?<target>(?:\w+\.)?Err\w*)\b|\b(?<target>(?:\w+\.)?Err\w*)\s*[!=]=\s*(?<var>\w+)\b", RegexOptions.Compiled);
+
+    private static readonly Regex CaseCheck = new(@"^\s*case\s+(?<targets>(?:\w+\.)?Err\w*(?:\s*,\s*(?:\w+\.)?Err\w*)*)\s*:", RegexOptions.Compiled);
+
+    private static readonly Regex Verb = new(@"%(?:%|[-+# 0]*(?:\*|\d+)?(?:\.(?:\*|\d+))?[a-zA-Z])", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal)
+    {
+        "if", "for", "switch", "select", "go", "defer", "func", "return", "make", "new", "append", "len", "cap"
+    };
+
+    /// <summary>
+    /// Reads the error statements of a Go file
+    /// </summary>
+    public static GoErrorFile Scan(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+        var file = new GoErrorFile
+        {
+            FilePath = filePath,
+            Test = filePath.EndsWith("_test.go", StringComparison.OrdinalIgnoreCase)
+        };
+
+        GoErrorFunction? function = null;
+        var opened = false;
+        var depth = 0;
+        var inVarBlock = false;
+        var consumedUntil = -1;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var text = masked[i];
+            var lineNumber = i + 1;
+
+            if (depth == 0)
+            {
+                if (Package.Match(text) is { Success: true } package)
+                {
+                    file.Package = package.Groups["name"].Value;
+                }
+                else if (ErrorMethod.Match(text) is { Success: true } errorMethod)
+                {
+                    file.Types.Add(new GoErrorType { Name = errorMethod.Groups["type"].Value, FilePath = filePath, Line = lineNumber });
+                }
+
+                if (Func.Match(text) is { Success: true } func)
+                {
+                    function = new GoErrorFunction
+                    {
+                        Name = func.Groups["name"].Value,
+                        Receiver = func.Groups["receiver"].Success ? func.Groups["receiver"].Value : null,
+                        FilePath = filePath,
+                        Package = file.Package,
+                        Line = lineNumber
+                    };
+                    file.Functions.Add(function);
+                    opened = false;
+                }
+                else if (Regex.IsMatch(text, @"^var\s*\("))
+                {
+                    inVarBlock = true;
+                }
+                else if (inVarBlock && text.TrimStart().StartsWith(')'))
+                {
+                    inVarBlock = false;
+                }
+                else if ((inVarBlock || text.StartsWith("var", StringComparison.Ordinal)) &&
+                         SentinelDeclaration.Match(text) is { Success: true } sentinel)
+                {
+                    var (statement, original, _) = Statement(lines, masked, i);
+                    var call = Constructor.Match(statement);
+                    var args = call.Success ? Arguments(statement, original, call.Index + call.Length - 1) : new List<(string Masked, string Original)>();
+                    var format = args.Count > 0 ? Unquote(args[0].Original) : null;
+                    file.Sentinels.Add(new GoErrorSentinel
+                    {
+                        Name = sentinel.Groups["name"].Value,
+                        FilePath = filePath,
+                        Line = lineNumber,
+                        Message = format,
+                        Wraps = WrappedArguments(format, args).Select(Unqualified).ToList()
+                    });
+                }
+            }
+
+            if (function != null && i > consumedUntil)
+            {
+                var (statement, original, last) = Statement(lines, masked, i);
+                consumedUntil = last;
+
+                // The signature is no statement; a one-line body after it is
+                var body = opened || lineNumber > function.Line ? 0 : statement.IndexOf('{') + 1;
+                if (opened || body > 0)
+                    ReadStatement(function, statement[body..], original[Math.Min(body, original.Length)..], lineNumber, masked);
+            }
+
+            foreach (var c in text)
+            {
+                if (c == '{') depth++;
+                else if (c == '}') depth--;
+            }
+
+            if (function != null && text.Contains('{'))
+            {
+                opened = true;
+            }
+            if (function != null && opened && depth <= 0)
+            {
+                function.EndLine = lineNumber;
+                function = null;
+                depth = 0;
+            }
+        }
+
+        return file;
+    }
+
+    private static void ReadStatement(GoErrorFunction function, string statement, string original, int line, string[] masked)
+    {
+        var events = function.Events;
+
+        // Calls whose error result is kept or returned
+        if (AssignedCall.Match(statement) is { Success: true } assigned && !Keywords.Contains(Last(assigned.Groups["callee"].Value)))
+        {
+            var variables = assigned.Groups["vars"].Value.Split(',').Select(v => v.Trim()).ToList();
+            var errorVariable = variables.LastOrDefault(IsErrorVariable);
+            var callee = assigned.Groups["callee"].Value;
+            if (errorVariable != null && !IsConstructor(callee))
+            {
+                events.Add(Call(callee, line, errorVariable, returned: false));
+            }
+        }
+        else if (ReturnedCall.Match(statement) is { Success: true } returned && !Keywords.Contains(Last(returned.Groups["callee"].Value))
+                 && !IsConstructor(returned.Groups["callee"].Value))
+        {
+            events.Add(Call(returned.Groups["callee"].Value, line, null, returned: true));
+        }
+
+        foreach (Match constructor in Constructor.Matches(statement))
+        {
+            var args = Arguments(statement, original, constructor.Index + constructor.Length - 1);
+            var ctor = constructor.Groups["ctor"].Value;
+            if (ctor == "errors.Join")
+            {
+                events.Add(new GoErrorEvent { Kind = "wrap", Line = line, Format = "%w", Wrapped = args.Select(a => a.Masked.Trim()).ToList() });
+                continue;
+            }
+
+            var format = args.Count > 0 ? Unquote(args[0].Original) : null;
+            var wrapped = ctor == "fmt.Errorf" ? WrappedArguments(format, args) : new List<string>();
+            events.Add(new GoErrorEvent { Kind = wrapped.Count > 0 ? "wrap" : "new", Line = line, Format = format, Wrapped = wrapped });
+        }
+
+        foreach (Match literal in Literal.Matches(statement))
+        {
+            events.Add(new GoErrorEvent { Kind = "literal", Line = line, Target = literal.Groups["type"].Value });
+        }
+
+        foreach (Match check in IsCheck.Matches(statement))
+        {
+            events.Add(Check("is", line, check.Groups["var"].Value, check.Groups["target"].Value));
+        }
+        foreach (Match check in AsCheck.Matches(statement))
+        {
+            var target = check.Groups["type"].Success
+                ? check.Groups["type"].Value
+                : TypeOfVariable(check.Groups["target"].Value, function.Line, line, masked) ?? check.Groups["target"].Value;
+            events.Add(Check("as", line, check.Groups["var"].Value, target));
+        }
+        foreach (Match check in EqualsCheck.Matches(statement))
+        {
+            events.Add(Check("equals", line, check.Groups["var"].Value, check.Groups["target"].Value));
+        }
+        if (CaseCheck.Match(statement) is { Success: true } caseCheck)
+        {
+            var variable = SwitchVariable(function.Line, line, masked);
+            foreach (var target in caseCheck.Groups["targets"].Value.Split(','))
+            {
+                events.Add(Check("case", line, variable ?? "", target.Trim()));
+            }
+        }
+
+        if (Return.Match(statement) is { Success: true } ret)
+        {
+            events.Add(new GoErrorEvent { Kind = "return", Line = line, Text = ret.Groups["values"].Value.Trim() });
+        }
+    }
+
+    /// <summary>
+    /// Traces the error named by <paramref name="error"/> - a sentinel (ErrNotFound, users.ErrNotFound), an
+    /// error type (NotFoundError) or a construction site as "path:line" - through the callers of the functions
+    /// producing it. Null when nothing in the files matches.
+    /// </summary>
+    public static GoErrorTrace? Trace(IReadOnlyList<GoErrorFile> files, string error, int maxDepth = 5, bool includeTests = false)
+    {
+        var functions = files.SelectMany(f => f.Functions).ToList();
+        var origin = ResolveOrigin(files, functions, error.Trim(), out var producers);
+        if (origin == null)
+            return null;
+
+        var trace = new GoErrorTrace { Origin = origin };
+        var matches = origin.Matches.ToHashSet(StringComparer.Ordinal);
+        var testFiles = files.Where(f => f.Test).Select(f => f.FilePath).ToHashSet(StringComparer.Ordinal);
+        var reached = new Dictionary<string, GoErrorHop>(StringComparer.Ordinal);
+        var frontier = new List<(GoErrorFunction Function, GoErrorHop Hop)>();
+
+        foreach (var (function, producing) in producers)
+        {
+            var hop = new GoErrorHop
+            {
+                Function = function.DisplayName,
+                FilePath = function.FilePath,
+                Line = producing.Line,
+                How = "produces",
+                Format = producing.Kind == "wrap" ? producing.Format : null,
+                Message = producing.Kind == "wrap" && origin.Kind != "site" ? Compose(producing.Format, origin.Message) : producing.Format ?? origin.Message,
+                Key = function.Key
+            };
+            if (reached.TryAdd(function.Key, hop))
+            {
+                trace.Hops.Add(hop);
+                frontier.Add((function, hop));
+            }
+        }
+
+        for (var depth = 1; frontier.Count > 0; depth++)
+        {
+            var next = new List<(GoErrorFunction Function, GoErrorHop Hop)>();
+            foreach (var (callee, from) in frontier)
+            {
+                foreach (var caller in functions)
+                {
+                    if (caller.Key == callee.Key || !includeTests && testFiles.Contains(caller.FilePath))
+                        continue;
+
+                    foreach (var call in caller.Events.Where(e => e.Kind == "call" && Calls(e, callee, caller)))
+                    {
+                        var hop = Follow(caller, call, from, matches);
+                        hop.Depth = depth;
+                        if (depth > maxDepth)
+                        {
+                            trace.DepthLimited = true;
+                            continue;
+                        }
+                        if (reached.ContainsKey(caller.Key) && hop.How is "wraps" or "returns")
+                            continue;
+
+                        trace.Hops.Add(hop);
+                        if (hop.How is "wraps" or "returns" && reached.TryAdd(caller.Key, hop))
+                        {
+                            next.Add((caller, hop));
+                        }
+                    }
+                }
+            }
+            frontier = next;
+        }
+
+        var onPath = reached.Keys.ToHashSet(StringComparer.Ordinal);
+        foreach (var function in functions)
+        {
+            foreach (var check in function.Events.Where(e => e.Kind == "check" && matches.Contains(Unqualified(e.Target!))))
+            {
+                trace.Checks.Add(new GoErrorCheck
+                {
+                    Function = function.DisplayName,
+                    FilePath = function.FilePath,
+                    Line = check.Line,
+                    Kind = check.CheckKind!,
+                    Target = check.Target!,
+                    OnPath = onPath.Contains(function.Key) || trace.Hops.Any(h => h.How == "checks" && h.Key == function.Key)
+                });
+            }
+        }
+
+        trace.Paths = RenderPaths(trace.Hops);
+        return trace;
+    }
+
+    /// <summary>
+    /// How the caller treats the error a call hands it: the first wrap of the error variable, return of it or
+    /// check on it after the call, before the variable is assigned again
+    /// </summary>
+    private static GoErrorHop Follow(GoErrorFunction caller, GoErrorEvent call, GoErrorHop from, HashSet<string> matches)
+    {
+        var hop = new GoErrorHop
+        {
+            Function = caller.DisplayName,
+            FilePath = caller.FilePath,
+            Line = call.Line,
+            Message = from.Message,
+            From = from.Key,
+            Key = caller.Key
+        };
+
+        if (call.Returned)
+        {
+            hop.How = "returns";
+            return hop;
+        }
+
+        var variable = new Regex($@"(?<![\w.]){Regex.Escape(call.Variable!)}(?!\w)");
+        foreach (var e in caller.Events.Where(e => e.Line >= call.Line && !ReferenceEquals(e, call)))
+        {
+            if (e.Kind == "call" && e.Variable == call.Variable && e.Line > call.Line)
+                break;
+
+            if (e.Kind == "check" && e.Variable == call.Variable && matches.Contains(Unqualified(e.Target!)))
+            {
+                hop.How = "checks";
+                hop.Line = e.Line;
+                return hop;
+            }
+            if (e.Kind == "wrap" && e.Wrapped.Any(w => variable.IsMatch(w)))
+            {
+                hop.How = "wraps";
+                hop.Line = e.Line;
+                hop.Format = e.Format;
+                hop.Message = Compose(e.Format, from.Message);
+                return hop;
+            }
+            if (e.Kind == "return" && Values(e.Text).Any(v => v == call.Variable))
+            {
+                hop.How = "returns";
+                hop.Line = e.Line;
+                return hop;
+            }
+        }
+
+        hop.How = "handles";
+        return hop;
+    }
+
+    private static GoErrorOrigin? ResolveOrigin(IReadOnlyList<GoErrorFile> files, List<GoErrorFunction> functions, string error,
+        out List<(GoErrorFunction Function, GoErrorEvent Event)> producers)
+    {
+        producers = new List<(GoErrorFunction, GoErrorEvent)>();
+        var sentinels = files.SelectMany(f => f.Sentinels).ToList();
+        var types = files.SelectMany(f => f.Types).ToList();
+
+        var site = Regex.Match(error, @"^(?<path>.+?):(?<line>\d+)(?::\d+)?$");
+        if (site.Success)
+        {
+            var path = site.Groups["path"].Value.Replace('\\', '/');
+            var line = int.Parse(site.Groups["line"].Value);
+            var function = functions.FirstOrDefault(f => PathMatches(f.FilePath, path) && f.Line <= line && line <= f.EndLine);
+            var created = function?.Events.FirstOrDefault(e => e.Line == line && e.Kind is "new" or "wrap" or "literal");
+            if (function == null || created == null)
+                return null;
+
+            var origin = new GoErrorOrigin
+            {
+                Kind = "site",
+                Name = $"{function.DisplayName}:{line}",
+                FilePath = function.FilePath,
+                Line = line,
+                Message = created.Format
+            };
+            foreach (var wrapped in created.Wrapped.Select(Unqualified).Where(w => sentinels.Any(s => s.Name == w)))
+            {
+                origin.Matches.AddRange(Expand(wrapped, sentinels));
+            }
+            if (created.Kind == "literal")
+            {
+                origin.Matches.Add(created.Target!);
+                origin.Message ??= created.Target;
+            }
+            origin.Matches = origin.Matches.Distinct().ToList();
+            producers.Add((function, created));
+            return origin;
+        }
+
+        var name = Unqualified(error);
+        var sentinel = sentinels.FirstOrDefault(s => s.Name == name);
+        var type = sentinel == null ? types.FirstOrDefault(t => t.Name == name) : null;
+        if (sentinel == null && type == null)
+            return null;
+
+        var result = new GoErrorOrigin
+        {
+            Kind = sentinel != null ? "sentinel" : "type",
+            Name = name,
+            FilePath = sentinel?.FilePath ?? type!.FilePath,
+            Line = sentinel?.Line ?? type!.Line,
+            Message = sentinel?.Message ?? name,
+            Matches = sentinel != null ? Expand(name, sentinels) : new List<string> { name }
+        };
+
+        // Functions returning the value itself, wrapping it, or building the type
+        var named = new Regex($@"(?<![\w]){Regex.Escape(name)}(?!\w)");
+        foreach (var function in functions)
+        {
+            var producing = function.Events.FirstOrDefault(e =>
+                e.Kind == "return" && named.IsMatch(e.Text ?? "") ||
+                e.Kind == "wrap" && e.Wrapped.Any(w => Unqualified(w) == name) ||
+                e.Kind == "literal" && e.Target == name && type != null && function.Name != "Error");
+            if (producing != null && !(sentinel != null && function.Events.Any(e => e.Kind == "check" && e.Line == producing.Line)))
+            {
+                producers.Add((function, producing));
+            }
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The sentinel and, transitively, every sentinel wrapping it - errors.Is(err, base) holds for all of them
+    /// </summary>
+    private static List<string> Expand(string name, List<GoErrorSentinel> sentinels)
+    {
+        var names = new List<string> { name };
+        for (var i = 0; i < names.Count; i++)
+        {
+            names.AddRange(sentinels.Where(s => s.Wraps.Contains(names[i]) && !names.Contains(s.Name)).Select(s => s.Name));
+        }
+        return names;
+    }
+
+    private static List<string> RenderPaths(List<GoErrorHop> hops)
+    {
+        var byKey = hops.Where(h => h.How is "produces" or "wraps" or "returns")
+            .GroupBy(h => h.Key)
+            .ToDictionary(g => g.Key, g => g.First());
+        var parents = hops.Where(h => h.From != null).Select(h => h.From!).ToHashSet(StringComparer.Ordinal);
+        var paths = new List<string>();
+
+        // Leaves are hops nothing continued from: a check, a handler, or a wrap/return no caller picked up.
+        // Longest paths first
+        var leaves = hops.Where(h => h.How is "checks" or "handles" || !parents.Contains(h.Key) && byKey.GetValueOrDefault(h.Key) == h);
+        foreach (var leaf in leaves.OrderByDescending(h => h.Depth))
+        {
+            var chain = new List<string>();
+            for (GoErrorHop? hop = leaf; hop != null; hop = hop.From != null ? byKey.GetValueOrDefault(hop.From) : null)
+            {
+                var how = hop.How == "wraps" ? $"wraps \"{hop.Format}\"" : hop.How;
+                chain.Insert(0, $"{hop.Function} {how} ({hop.FilePath}:{hop.Line})");
+                if (chain.Count > 50)
+                    break;
+            }
+            paths.Add(string.Join(" → ", chain));
+        }
+        return paths;
+    }
+
+    /// <summary>
+    /// The error message after a wrap: the format with %w replaced by the wrapped message
+    /// </summary>
+    public static string? Compose(string? format, string? wrapped)
+    {
+        if (format == null)
+            return wrapped;
+        var index = format.IndexOf("%w", StringComparison.Ordinal);
+        return index < 0 || wrapped == null ? format : format[..index] + wrapped + format[(index + 2)..];
+    }
+
+    private static bool Calls(GoErrorEvent call, GoErrorFunction callee, GoErrorFunction caller)
+    {
+        if (call.Callee != callee.Name)
+            return false;
+
+        // Methods are called through a value; plain functions directly in their package or through its name
+        if (callee.Receiver != null)
+            return call.Qualifier != null && call.Qualifier != callee.Package;
+        return call.Qualifier == null
+            ? string.Equals(Directory(caller.FilePath), Directory(callee.FilePath), StringComparison.Ordinal)
+            : call.Qualifier == callee.Package;
+    }
+
+    private static GoErrorEvent Call(string callee, int line, string? variable, bool returned)
+    {
+        var dot = callee.LastIndexOf('.');
+        return new GoErrorEvent
+        {
+            Kind = "call",
+            Line = line,
+            Callee = dot < 0 ? callee : callee[(dot + 1)..],
+            Qualifier = dot < 0 ? null : callee[..dot],
+            Variable = variable,
+            Returned = returned
+        };
+    }
+
+    private static GoErrorEvent Check(string kind, int line, string variable, string target) =>
+        new() { Kind = "check", CheckKind = kind, Line = line, Variable = variable, Target = Unqualified(target) };
+
+    /// <summary>
+    /// Arguments of a %w verb in a format, by counting the verbs before it
+    /// </summary>
+    private static List<string> WrappedArguments(string? format, List<(string Masked, string Original)> args)
+    {
+        var wrapped = new List<string>();
+        if (format == null)
+            return wrapped;
+
+        var index = 0;
+        foreach (Match verb in Verb.Matches(format))
+        {
+            if (verb.Value == "%%")
+                continue;
+            index += verb.Value.Count(c => c == '*');
+            if (verb.Value.EndsWith('w') && index + 1 < args.Count)
+                wrapped.Add(args[index + 1].Masked.Trim());
+            index++;
+        }
+        return wrapped;
+    }
+
+    /// <summary>
+    /// A statement starting at a line, joined with the following lines while its parentheses are open
+    /// (a call spread over lines); a line opening a block ends it
+    /// </summary>
+    private static (string Masked, string Original, int LastLine) Statement(string[] lines, string[] masked, int start)
+    {
+        var text = masked[start];
+        var original = lines[start];
+        var last = start;
+        while (Balance(text) > 0 && !text.TrimEnd().EndsWith('{') && last + 1 < lines.Length && last - start < 8)
+        {
+            last++;
+            text += " " + masked[last].Trim();
+            original += " " + lines[last].Trim();
+        }
+        return (text, original, last);
+    }
+
+    /// <summary>
+    /// Top-level arguments of the call whose opening parenthesis is at <paramref name="open"/>, split on the
+    /// masked text and returned with the original text so string arguments keep their content
+    /// </summary>
+    private static List<(string Masked, string Original)> Arguments(string masked, string original, int open)
+    {
+        var args = new List<(string, string)>();
+        var depth = 0;
+        var start = open + 1;
+        for (var i = open; i < masked.Length; i++)
+        {
+            var c = masked[i];
+            if (c is '(' or '[' or '{') depth++;
+            else if (c is ')' or ']' or '}')
+            {
+                depth--;
+                if (depth == 0)
+                {
+                    if (i > start || args.Count > 0)
+                        args.Add((masked[start..i], original[start..Math.Min(i, original.Length)]));
+                    break;
+                }
+            }
+            else if (c == ',' && depth == 1)
+            {
+                args.Add((masked[start..i], original[start..Math.Min(i, original.Length)]));
+                start = i + 1;
+            }
+        }
+        return args;
+    }
+
+    private static string? TypeOfVariable(string variable, int functionLine, int line, string[] masked)
+    {
+        var declaration = new Regex($@"\bvar\s+{Regex.Escape(variable)}\s+\*?(?:\w+\.)?(?<type>\w+)|\b{Regex.Escape(variable)}\s*:=\s*&?(?:\w+\.)?(?<type>\w+)\s*\{{");
+        for (var i = line - 1; i >= functionLine - 1 && i >= 0; i--)
+        {
+            if (declaration.Match(masked[i]) is { Success: true } match)
+                return match.Groups["type"].Value;
+        }
+        return null;
+    }
+
+    private static string? SwitchVariable(int functionLine, int line, string[] masked)
+    {
+        for (var i = line - 2; i >= functionLine - 1 && i >= 0; i--)
+        {
+            var match = Regex.Match(masked[i], @"^\s*switch\s+(?:(?<var>\w+)\s*\{|.*;\s*(?<var>\w+)\s*\{)");
+            if (match.Success)
+                return match.Groups["var"].Value;
+            if (Regex.IsMatch(masked[i], @"^\s*switch\b"))
+                return null;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Top-level comma-separated values of a return statement
+    /// </summary>
+    private static IEnumerable<string> Values(string? text)
+    {
+        if (string.IsNullOrEmpty(text))
+            yield break;
+
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i <= text.Length; i++)
+        {
+            if (i == text.Length || text[i] == ',' && depth == 0)
+            {
+                yield return text[start..i].Trim();
+                start = i + 1;
+            }
+            else if (text[i] is '(' or '[' or '{') depth++;
+            else if (text[i] is ')' or ']' or '}') depth--;
+        }
+    }
+
+    private static int Balance(string text) => text.Count(c => c == '(') - text.Count(c => c == ')');
+
+    private static bool IsErrorVariable(string name) =>
+        name == "err" || name.EndsWith("Err", StringComparison.Ordinal) || name.EndsWith("err", StringComparison.Ordinal) && name.Length > 3;
+
+    private static bool IsConstructor(string callee) => callee is "errors.New" or "errors.Join" or "fmt.Errorf";
+
+    private static string Last(string callee) => callee[(callee.LastIndexOf('.') + 1)..];
+
+    private static string Unqualified(string name) => name.Trim().TrimStart('&', '*')[(name.Trim().TrimStart('&', '*').LastIndexOf('.') + 1)..];
+
+    private static string Unquote(string argument)
+    {
+        var text = argument.Trim();
+        return text.Length >= 2 && (text[0] == '"' && text[^1] == '"' || text[0] == '`' && text[^1] == '`') ? text[1..^1] : text;
+    }
+
+    private static string Directory(string filePath) => filePath.Contains('/') ? filePath[..filePath.LastIndexOf('/')] : string.Empty;
+
+    private static bool PathMatches(string filePath, string path) =>
+        filePath.Equals(path, StringComparison.OrdinalIgnoreCase) || filePath.EndsWith("/" + path, StringComparison.OrdinalIgnoreCase) ||
+        path.EndsWith("/" + filePath, StringComparison.OrdinalIgnoreCase);
+}
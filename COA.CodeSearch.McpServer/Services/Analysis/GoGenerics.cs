@@ -0,0 +1,398 @@
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Go generics from source text, which the tree-sitter symbols carry only partly: type parameter lists with
+/// their constraints, the type sets of constraint interfaces (<c>int | ~float64</c>) and instantiations with
+/// explicit type arguments (<c>Stack[int]</c>, <c>Map[string, User]</c>)
+/// </summary>
+public static class GoGenerics
+{
+    private static readonly Regex FuncHeader = new(
+        @"^\s*func\s*(?:\((?<receiver>[^()]*)\)\s*)?(?<name>\w+)\s*(?<open>\[)?", RegexOptions.Compiled);
+    private static readonly Regex Receiver = new(@"^\s*(?:\w+\s+)?\*?\s*(?<type>\w+)\s*\[(?<params>[^\]]*)\]", RegexOptions.Compiled);
+    private static readonly Regex TypeParameter = new(@"^(?<name>[A-Za-z_]\w*)(?:\s+(?<constraint>.+))?$", RegexOptions.Compiled | RegexOptions.Singleline);
+    private static readonly Regex InlineInterface = new(@"^interface\s*\{(?<body>.*)\}$", RegexOptions.Compiled | RegexOptions.Singleline);
+    private static readonly Regex Subscript = new(@"(?<![\w.])(?<name>[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?)\[", RegexOptions.Compiled);
+    private static readonly Regex TypeLike = new(
+        @"^(?:\*|\[\d*\])*(?:map\[.+\].+|chan\b.+|func\s*\(.*|interface\s*\{.*\}|struct\s*\{.*\}|[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)?(?:\[.+\])?)$",
+        RegexOptions.Compiled);
+    private static readonly Regex Whitespace = new(@"\s+", RegexOptions.Compiled);
+    private static readonly Regex GenericName = new(@"^(?<name>[A-Za-z_][\w.]*)\s*\[.*\]$", RegexOptions.Compiled | RegexOptions.Singleline);
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal) { "map", "chan", "func", "interface", "struct", "type", "return", "case" };
+
+    private static readonly HashSet<string> BuiltinTypes = new(StringComparer.Ordinal)
+    {
+        "any", "bool", "byte", "comparable", "complex64", "complex128", "error", "float32", "float64",
+        "int", "int8", "int16", "int32", "int64", "rune", "string",
+        "uint", "uint8", "uint16", "uint32", "uint64", "uintptr"
+    };
+
+    // Declarations rarely spread their type parameters over more lines than this
+    private const int MaxHeaderLines = 10;
+
+    /// <summary>
+    /// Adds type parameters, constraint type sets and instantiations to the symbols extracted from a Go file,
+    /// and restores the type parameter list to signatures that lost it
+    /// </summary>
+    public static void Enrich(TypeExtractionResult result, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+
+        foreach (var type in result.Types)
+        {
+            var line = type.Line - 1;
+            if (line < 0 || line >= lines.Length)
+                continue;
+
+            type.TypeParameters = TypeParametersOf(masked, line, type.Name);
+            if (type.Kind.Equals("interface", StringComparison.OrdinalIgnoreCase))
+                type.TypeSet = TypeSetOf(masked, line);
+            if (type.TypeParameters != null && !DeclaresTypeParameters(type.Signature, type.Name))
+                type.Signature = DeclarationHeader(lines, masked, line) ?? type.Signature;
+        }
+
+        var declared = result.Types
+            .GroupBy(t => t.Name, StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => g.First(), StringComparer.Ordinal);
+
+        foreach (var method in result.Methods)
+        {
+            var line = method.Line - 1;
+            if (line < 0 || line >= lines.Length)
+                continue;
+
+            var header = FuncHeader.Match(masked[line]);
+            if (!header.Success || header.Groups["name"].Value != method.Name)
+                continue;
+
+            if (header.Groups["open"].Success)
+            {
+                method.TypeParameters = TypeParametersOf(masked, line, method.Name);
+            }
+            else if (Receiver.Match(header.Groups["receiver"].Value) is { Success: true } receiver)
+            {
+                // Methods can't declare type parameters; they use the receiver type's, named afresh
+                var owner = receiver.Groups["type"].Value;
+                var ownerParameters = declared.TryGetValue(owner, out var ownerType) ? ownerType.TypeParameters : null;
+                method.ContainingType ??= owner;
+                method.TypeParameters = receiver.Groups["params"].Value.Split(',')
+                    .Select(p => p.Trim())
+                    .Where(p => p.Length > 0)
+                    .Select((p, i) => new TypeParameterInfo
+                    {
+                        Name = p,
+                        Constraint = ownerParameters?.ElementAtOrDefault(i)?.Constraint,
+                        Union = ownerParameters?.ElementAtOrDefault(i)?.Union
+                    })
+                    .ToList();
+            }
+
+            if (method.TypeParameters != null && !DeclaresTypeParameters(method.Signature, method.Name))
+                method.Signature = DeclarationHeader(lines, masked, line) ?? method.Signature;
+        }
+
+        // A constraint naming an interface declared in this file takes that interface's type set
+        var parameters = result.Types.SelectMany(t => t.TypeParameters ?? new())
+            .Concat(result.Methods.SelectMany(m => m.TypeParameters ?? new()));
+        foreach (var parameter in parameters)
+        {
+            if (parameter.Union == null && parameter.Constraint != null
+                && declared.TryGetValue(parameter.Constraint, out var constraint) && constraint.TypeSet != null)
+            {
+                parameter.Union = constraint.TypeSet;
+            }
+        }
+
+        var generic = result.Types.Where(t => t.TypeParameters != null).Select(t => t.Name)
+            .Concat(result.Methods.Where(m => m.TypeParameters != null && m.ContainingType == null).Select(m => m.Name))
+            .ToHashSet(StringComparer.Ordinal);
+        var instantiations = FindInstantiations(masked, generic);
+        result.Instantiations = instantiations.Count > 0 ? instantiations : null;
+    }
+
+    /// <summary>
+    /// Generics of the one declaration at the start of <paramref name="content"/>, for callers holding a symbol
+    /// rather than an extracted file: type parameters, a constraint interface's type set, and the signature with
+    /// its type parameter list
+    /// </summary>
+    public static (List<TypeParameterInfo>? TypeParameters, List<string>? TypeSet, string Signature) Describe(
+        string content, string name, string kind, string signature)
+    {
+        var result = new TypeExtractionResult { Success = true };
+        if (kind is "function" or "method")
+        {
+            var method = new MethodInfo { Name = name, Signature = signature, Line = 1 };
+            result.Methods.Add(method);
+            Enrich(result, content);
+            return (method.TypeParameters, null, method.Signature);
+        }
+
+        var type = new TypeInfo { Name = name, Kind = kind, Signature = signature, Line = 1 };
+        result.Types.Add(type);
+        Enrich(result, content);
+        return (type.TypeParameters, type.TypeSet, type.Signature);
+    }
+
+    /// <summary>
+    /// Type parameters of the type or function <paramref name="name"/> declared on a masked line; null when
+    /// it is not generic
+    /// </summary>
+    public static List<TypeParameterInfo>? TypeParametersOf(IReadOnlyList<string> masked, int line, string name)
+    {
+        var text = string.Join("\n", masked.Skip(line).Take(MaxHeaderLines));
+        var match = Regex.Match(text, $@"^\s*(?:func\s+|type\s+)?{Regex.Escape(name)}\s*\[");
+        if (!match.Success)
+            return null;
+
+        var inner = ReadBracket(text, match.Index + match.Length - 1);
+        return inner == null ? null : ParseTypeParameters(inner);
+    }
+
+    /// <summary>
+    /// Type parameters of one declaration line, for callers that have only the line
+    /// </summary>
+    public static List<TypeParameterInfo>? TypeParametersOf(string line, string name) =>
+        TypeParametersOf(new[] { DataFlowScanner.Mask(line, ".go") }, 0, name);
+
+    /// <summary>
+    /// Parses the text between the brackets of a type parameter list: <c>K comparable, V any</c>,
+    /// <c>T, U Number</c>. Null when it is not one, such as the length of an array type <c>[N]int</c>.
+    /// </summary>
+    public static List<TypeParameterInfo>? ParseTypeParameters(string list)
+    {
+        var result = new List<TypeParameterInfo>();
+        var pending = new List<string>();
+        foreach (var part in SplitTopLevel(list, ','))
+        {
+            var trimmed = part.Trim();
+            if (trimmed.Length == 0)
+                continue;
+
+            var match = TypeParameter.Match(trimmed);
+            if (!match.Success)
+                return null;
+
+            var name = match.Groups["name"].Value;
+            if (!match.Groups["constraint"].Success)
+            {
+                // Names grouped before a shared constraint: [T, U any]
+                pending.Add(name);
+                continue;
+            }
+
+            var constraint = Whitespace.Replace(match.Groups["constraint"].Value.Trim(), " ");
+            foreach (var parameter in pending.Append(name))
+            {
+                result.Add(new TypeParameterInfo { Name = parameter, Constraint = constraint, Union = UnionTerms(constraint) });
+            }
+            pending.Clear();
+        }
+
+        return pending.Count == 0 && result.Count > 0 ? result : null;
+    }
+
+    /// <summary>
+    /// Terms of a union constraint (<c>int | ~float64</c>, also inside <c>interface{ ... }</c>); null when the
+    /// constraint is a single named type
+    /// </summary>
+    public static List<string>? UnionTerms(string constraint)
+    {
+        var text = constraint.Trim();
+        if (InlineInterface.Match(text) is { Success: true } inline)
+            text = inline.Groups["body"].Value.Trim();
+
+        var terms = SplitTopLevel(text, '|').Select(t => t.Trim()).Where(t => t.Length > 0).ToList();
+        return terms.Count > 1 || terms.Count == 1 && terms[0].StartsWith('~') ? terms : null;
+    }
+
+    /// <summary>
+    /// Type set of the interface declared on a masked line: the terms of its union lines, intersected when
+    /// there are several. Null for an interface of methods only.
+    /// </summary>
+    public static List<string>? TypeSetOf(IReadOnlyList<string> masked, int line)
+    {
+        var text = string.Join("\n", masked.Skip(line));
+        var open = Regex.Match(text, @"\binterface\s*\{");
+        if (!open.Success || text[..open.Index].Contains('\n'))
+            return null;
+
+        var body = ReadBracket(text, open.Index + open.Length - 1);
+        if (body == null)
+            return null;
+
+        // A union may continue on the next line after a trailing |
+        body = Regex.Replace(body, @"\|\s*\n", "| ");
+
+        List<string>? typeSet = null;
+        foreach (var element in body.Split('\n', ';').Select(e => e.Trim()).Where(e => e.Length > 0))
+        {
+            // Method specs have parameter lists; a lone name is an embedded interface
+            if (element.Contains('(') || !element.Contains('|') && !element.StartsWith('~') && !BuiltinTypes.Contains(element))
+                continue;
+
+            var terms = SplitTopLevel(element, '|').Select(t => Whitespace.Replace(t.Trim(), " ")).Where(t => t.Length > 0).ToList();
+            typeSet = typeSet == null ? terms : typeSet.Intersect(terms, StringComparer.Ordinal).ToList();
+        }
+        return typeSet;
+    }
+
+    /// <summary>
+    /// Uses of generic types and functions with explicit type arguments on masked lines. A subscript counts
+    /// when it names one of <paramref name="genericNames"/> with type-like arguments, or when an argument can
+    /// only be a type (<c>int</c>, <c>[]byte</c>, <c>*pkg.User</c>) - index expressions never qualify.
+    /// </summary>
+    public static List<GenericInstantiation> FindInstantiations(IReadOnlyList<string> masked, IReadOnlySet<string> genericNames)
+    {
+        var result = new List<GenericInstantiation>();
+        var seen = new HashSet<string>(StringComparer.Ordinal);
+
+        for (var i = 0; i < masked.Count; i++)
+        {
+            var line = masked[i];
+            var receiverEnd = ReceiverEnd(line);
+
+            foreach (Match match in Subscript.Matches(line))
+            {
+                var name = match.Groups["name"].Value;
+                if (match.Index < receiverEnd || Keywords.Contains(name))
+                    continue;
+
+                var inner = ReadBracket(line, match.Index + match.Length - 1);
+                if (inner == null)
+                    continue;
+
+                var arguments = SplitTopLevel(inner, ',').Select(a => Whitespace.Replace(a.Trim(), " ")).ToList();
+                if (arguments.Any(a => a.Length == 0 || !TypeLike.IsMatch(a)))
+                    continue;
+
+                var bare = name[(name.LastIndexOf('.') + 1)..];
+                if (!genericNames.Contains(bare) && !arguments.Any(IsUnmistakablyType))
+                    continue;
+
+                if (seen.Add($"{i}:{name}[{string.Join(",", arguments)}]"))
+                    result.Add(new GenericInstantiation { Name = name, TypeArguments = arguments, Line = i + 1 });
+            }
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The name a generic symbol query refers to: <c>Sum[T Number]</c> and <c>Stack[int]</c> search as
+    /// <c>Sum</c> and <c>Stack</c>. Other queries come back unchanged.
+    /// </summary>
+    public static string StripTypeArguments(string symbol)
+    {
+        var match = GenericName.Match(symbol.Trim());
+        return match.Success ? match.Groups["name"].Value : symbol;
+    }
+
+    /// <summary>
+    /// The declaration as written up to its body, joined onto one line: <c>func Sum[T Number](values []T) T</c>
+    /// </summary>
+    private static string? DeclarationHeader(IReadOnlyList<string> lines, IReadOnlyList<string> masked, int line)
+    {
+        var text = new StringBuilder();
+        var depth = 0;
+        for (var i = line; i < lines.Count && i < line + MaxHeaderLines; i++)
+        {
+            var end = masked[i].Length;
+            for (var c = 0; c < masked[i].Length; c++)
+            {
+                var ch = masked[i][c];
+                if (ch is '(' or '[') depth++;
+                else if (ch is ')' or ']') depth--;
+                else if (ch == '{' && depth == 0)
+                {
+                    end = c;
+                    break;
+                }
+            }
+
+            text.Append(' ').Append(lines[i][..Math.Min(end, lines[i].Length)].Trim());
+            if (end < masked[i].Length || depth <= 0)
+                break;
+        }
+
+        var header = Whitespace.Replace(text.ToString().Trim(), " ").Replace("[ ", "[").Replace("( ", "(");
+        return header.Length > 0 ? header : null;
+    }
+
+    /// <summary>
+    /// Whether a signature still carries the type parameter list after the name, or the receiver's for a method
+    /// </summary>
+    private static bool DeclaresTypeParameters(string signature, string name) =>
+        Regex.IsMatch(signature, $@"\b{Regex.Escape(name)}\s*\[|^\s*func\s*\([^()]*\[[^()]*\)\s*{Regex.Escape(name)}\b");
+
+    /// <summary>
+    /// Index just past a method declaration's receiver, so <c>(s *Stack[T])</c> isn't read as an instantiation
+    /// </summary>
+    private static int ReceiverEnd(string line)
+    {
+        var header = Regex.Match(line, @"^\s*func\s*\(");
+        if (!header.Success)
+            return -1;
+        var close = line.IndexOf(')', header.Length);
+        return close < 0 ? line.Length : close;
+    }
+
+    private static bool IsUnmistakablyType(string argument)
+    {
+        var text = argument;
+        var prefixed = false;
+        while (true)
+        {
+            if (text.StartsWith('*')) text = text[1..];
+            else if (Regex.Match(text, @"^\[\d*\]") is { Success: true } array) text = text[array.Length..];
+            else break;
+            prefixed = true;
+        }
+
+        if (BuiltinTypes.Contains(text) || Regex.IsMatch(text, @"^(?:map\[|chan\b|func\s*\(|interface\s*\{|struct\s*\{)"))
+            return true;
+        // pkg.Type; a prefix such as *T or []T also makes a bare exported name a type
+        return Regex.IsMatch(text, @"^[a-z_]\w*\.[A-Z]\w*(?:\[.+\])?$") || prefixed && Regex.IsMatch(text, @"^[A-Z]\w*(?:\[.+\])?$");
+    }
+
+    /// <summary>
+    /// Text inside the bracket opened at <paramref name="open"/>, or null when it does not close
+    /// </summary>
+    private static string? ReadBracket(string text, int open)
+    {
+        var depth = 0;
+        for (var i = open; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '(' or '[' or '{') depth++;
+            else if (c is ')' or ']' or '}' && --depth == 0)
+                return text[(open + 1)..i];
+        }
+        return null;
+    }
+
+    private static List<string> SplitTopLevel(string text, char separator)
+    {
+        var parts = new List<string>();
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '(' or '[' or '{') depth++;
+            else if (c is ')' or ']' or '}') depth--;
+            else if (c == separator && depth == 0)
+            {
+                parts.Add(text[start..i]);
+                start = i + 1;
+            }
+        }
+        parts.Add(text[start..]);
+        return parts;
+    }
+}
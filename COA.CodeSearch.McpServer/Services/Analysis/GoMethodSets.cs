@@ -0,0 +1,716 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A method as it appears in an interface or on a receiver: name and signature with parameter names and
+/// package qualifiers dropped, e.g. <c>(Context,string)(*User,error)</c>
+/// </summary>
+public record GoMethod(string Name, string Signature, string FilePath, int Line, bool PointerReceiver = false);
+
+/// <summary>
+/// A struct field; an embedded field is named after its type (<c>Base</c> for <c>*pkg.Base</c>). Tag is the field's
+/// tag without the backquotes, Column the 0-based column of its name, and Inline marks a field of an inline struct
+/// type, which belongs to the named struct around it but is not promoted to it.
+/// </summary>
+public record GoField(string Name, string Type, string FilePath, int Line, bool Embedded = false,
+    string? Tag = null, int Column = 0, bool Inline = false);
+
+/// <summary>
+/// A named struct or interface type declared in a Go file
+/// </summary>
+public class GoTypeDeclaration
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// struct, interface or type (any other named type, which can still have methods)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Package directory; Go methods live in the package of their receiver type
+    /// </summary>
+    public string Package { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Interface method specs
+    /// </summary>
+    public List<GoMethod> Methods { get; set; } = new();
+
+    /// <summary>
+    /// Embedded types as written (<c>io.Reader</c>, <c>*Base</c>)
+    /// </summary>
+    public List<string> Embedded { get; set; } = new();
+
+    /// <summary>
+    /// Struct fields, embedded ones included
+    /// </summary>
+    public List<GoField> Fields { get; set; } = new();
+}
+
+/// <summary>
+/// The field or method a selector on a type denotes, declared on the type or promoted from an embedded field
+/// </summary>
+public class GoMemberResolution
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// field or method
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The type declaring the member
+    /// </summary>
+    public GoTypeDeclaration DeclaringType { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Embedded fields walked from the selected type to the declaring one, outermost first; empty when the
+    /// type declares the member itself
+    /// </summary>
+    public List<string> PromotedVia { get; set; } = new();
+}
+
+/// <summary>
+/// A type whose method set satisfies an interface
+/// </summary>
+public class GoImplementation
+{
+    public GoTypeDeclaration Type { get; set; } = new();
+
+    /// <summary>
+    /// Only the pointer type implements the interface, because a matched method has a pointer receiver
+    /// </summary>
+    public bool PointerOnly { get; set; }
+
+    /// <summary>
+    /// The methods that satisfy the interface, including ones promoted from embedded types
+    /// </summary>
+    public List<GoMethod> Methods { get; set; } = new();
+
+    /// <summary>
+    /// Interface methods the type lacks; empty for an implementation, one entry for a near miss
+    /// </summary>
+    public List<string> Missing { get; set; } = new();
+}
+
+/// <summary>
+/// Declarations parsed from one Go file
+/// </summary>
+public class GoFileDeclarations
+{
+    public List<GoTypeDeclaration> Types { get; set; } = new();
+
+    /// <summary>
+    /// Methods keyed by receiver type name
+    /// </summary>
+    public List<(string Receiver, GoMethod Method)> Methods { get; set; } = new();
+}
+
+/// <summary>
+/// Go method sets from source text, for implicit interface satisfaction: a type implements an interface when
+/// its methods - declared on it or promoted from embedded fields - cover the interface's, with identical
+/// signatures. Types compare by name with package qualifiers dropped, which is exact within a package and
+/// rarely wrong across them.
+/// </summary>
+public static class GoMethodSets
+{
+    private static readonly Regex TypeHeader = new(
+        @"^\s*(?:type\s+)?(?<name>\w+)\s*(?:\[[^\]]*\])?\s+(?<kind>interface|struct)\s*\{", RegexOptions.Compiled);
+    private static readonly Regex NamedType = new(@"^\s*type\s+(?<name>\w+)\s*(?:\[[^\]]*\])?\s*=?\s*[\w*\[]", RegexOptions.Compiled);
+    private static readonly Regex GroupedType = new(@"^\s*(?<name>\w+)\s*(?:\[[^\]]*\])?\s*=?\s*[\w*\[]", RegexOptions.Compiled);
+    private static readonly Regex MethodHeader = new(
+        @"^func\s*\(\s*(?:\w+\s+)?(?<pointer>\*)?\s*(?<receiver>\w+)(?:\[[^\]]*\])?\s*\)\s*(?<name>\w+)\s*(?:\[[^\]]*\])?\s*\(",
+        RegexOptions.Compiled);
+    private static readonly Regex MethodSpec = new(@"^(?<name>\w+)\s*\(", RegexOptions.Compiled);
+    private static readonly Regex EmbeddedField = new(@"^(?<type>\*?[\w.]+)(?:\[[^\]]*\])?\s*(?:[`""].*)?$", RegexOptions.Compiled);
+    private static readonly Regex NamedFields = new(
+        @"^(?<names>[A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+(?<type>[^\s`""][^`""]*)", RegexOptions.Compiled);
+    private static readonly Regex PackageQualifier = new(@"\b[a-z_]\w*\.(?=[A-Za-z_])", RegexOptions.Compiled);
+
+    // Common standard library interfaces, so embedding them resolves without indexing GOROOT
+    private static readonly Dictionary<string, GoMethod[]> KnownInterfaces = new(StringComparer.Ordinal)
+    {
+        ["error"] = new[] { new GoMethod("Error", "()(string)", "builtin", 0) },
+        ["Stringer"] = new[] { new GoMethod("String", "()(string)", "fmt", 0) },
+        ["Reader"] = new[] { new GoMethod("Read", "([]byte)(int,error)", "io", 0) },
+        ["Writer"] = new[] { new GoMethod("Write", "([]byte)(int,error)", "io", 0) },
+        ["Closer"] = new[] { new GoMethod("Close", "()(error)", "io", 0) },
+        ["ReadCloser"] = new[] { new GoMethod("Read", "([]byte)(int,error)", "io", 0), new GoMethod("Close", "()(error)", "io", 0) },
+        ["WriteCloser"] = new[] { new GoMethod("Write", "([]byte)(int,error)", "io", 0), new GoMethod("Close", "()(error)", "io", 0) },
+        ["ReadWriter"] = new[] { new GoMethod("Read", "([]byte)(int,error)", "io", 0), new GoMethod("Write", "([]byte)(int,error)", "io", 0) }
+    };
+
+    private static readonly HashSet<string> TypeKeywords = new(StringComparer.Ordinal) { "chan", "func", "map", "struct", "interface" };
+
+    /// <summary>
+    /// Types and methods declared in a Go file
+    /// </summary>
+    public static GoFileDeclarations Parse(string filePath, IReadOnlyList<string> lines)
+    {
+        var result = new GoFileDeclarations();
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+        var package = Path.GetDirectoryName(filePath)?.Replace('\\', '/') ?? string.Empty;
+        var inTypeGroup = false;
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var line = masked[i];
+            var trimmed = line.Trim();
+            if (Regex.IsMatch(trimmed, @"^type\s*\($"))
+            {
+                inTypeGroup = true;
+                continue;
+            }
+            if (inTypeGroup && trimmed == ")")
+            {
+                inTypeGroup = false;
+                continue;
+            }
+
+            if (MethodHeader.Match(line) is { Success: true } method)
+            {
+                var (signature, end) = ReadSignature(masked, i, method.Index + method.Length - 1);
+                result.Methods.Add((method.Groups["receiver"].Value,
+                    new GoMethod(method.Groups["name"].Value, signature, filePath, i + 1, method.Groups["pointer"].Success)));
+                i = end;
+                continue;
+            }
+
+            var header = TypeHeader.Match(line);
+            if (header.Success && (trimmed.StartsWith("type", StringComparison.Ordinal) || inTypeGroup))
+            {
+                var type = new GoTypeDeclaration
+                {
+                    Name = header.Groups["name"].Value,
+                    Kind = header.Groups["kind"].Value,
+                    FilePath = filePath,
+                    Line = i + 1,
+                    Package = package
+                };
+                i = ParseBody(lines, masked, i, header.Index + header.Length - 1, type);
+                result.Types.Add(type);
+                continue;
+            }
+
+            if ((inTypeGroup ? GroupedType.Match(line) : NamedType.Match(line)) is { Success: true } named)
+            {
+                result.Types.Add(new GoTypeDeclaration
+                {
+                    Name = named.Groups["name"].Value, Kind = "type", FilePath = filePath, Line = i + 1, Package = package
+                });
+            }
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The receiver type a method is declared on, from its declaration or signature: <c>UserService</c> for
+    /// <c>func (s *UserService[T]) GetUser(</c>; null for plain functions
+    /// </summary>
+    public static string? ReceiverOf(string? declaration)
+    {
+        if (string.IsNullOrEmpty(declaration))
+            return null;
+
+        var method = MethodHeader.Match(declaration.TrimStart());
+        return method.Success ? method.Groups["receiver"].Value : null;
+    }
+
+    /// <summary>
+    /// Types whose method sets cover the interface, and near misses lacking a single method
+    /// </summary>
+    public static List<GoImplementation> FindImplementations(GoTypeDeclaration target, IReadOnlyList<GoFileDeclarations> files,
+        out List<string> unresolvedEmbeds)
+    {
+        var types = files.SelectMany(f => f.Types).ToList();
+        var methods = files.SelectMany(f => f.Methods)
+            .ToLookup(m => (Path.GetDirectoryName(m.Method.FilePath)?.Replace('\\', '/') ?? string.Empty, m.Receiver), m => m.Method);
+        unresolvedEmbeds = new List<string>();
+        var required = InterfaceMethods(target, types, new HashSet<GoTypeDeclaration>(), unresolvedEmbeds);
+        var result = new List<GoImplementation>();
+        if (required.Count == 0)
+            return result;
+
+        foreach (var type in types.Where(t => t.Kind != "interface"))
+        {
+            var set = MethodSet(type, types, methods, new HashSet<GoTypeDeclaration>());
+            var implementation = new GoImplementation { Type = type };
+            foreach (var spec in required.Values)
+            {
+                if (set.TryGetValue(spec.Name, out var found) && found.Method.Signature == spec.Signature)
+                {
+                    implementation.Methods.Add(found.Method);
+                    implementation.PointerOnly |= found.NeedsPointer;
+                }
+                else
+                {
+                    implementation.Missing.Add(spec.Name);
+                }
+            }
+
+            // A near miss needs most of the interface: one missing method out of several
+            if (implementation.Missing.Count == 0 || implementation.Missing.Count == 1 && required.Count > 1)
+                result.Add(implementation);
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The interface's methods, embedded interfaces included
+    /// </summary>
+    public static List<GoMethod> InterfaceMethodSet(GoTypeDeclaration target, IReadOnlyList<GoFileDeclarations> files) =>
+        InterfaceMethods(target, files.SelectMany(f => f.Types).ToList(), new HashSet<GoTypeDeclaration>(), new List<string>()).Values.ToList();
+
+    /// <summary>
+    /// The field or method <paramref name="member"/> selects on a type, following embedded fields breadth-first
+    /// as Go promotes them: the shallowest depth wins, and two candidates at the same depth are ambiguous (null)
+    /// </summary>
+    public static GoMemberResolution? FindMember(GoTypeDeclaration type, string member, IReadOnlyList<GoFileDeclarations> files)
+    {
+        var types = files.SelectMany(f => f.Types).ToList();
+        var methods = files.SelectMany(f => f.Methods)
+            .ToLookup(m => (Path.GetDirectoryName(m.Method.FilePath)?.Replace('\\', '/') ?? string.Empty, m.Receiver), m => m.Method);
+        var visited = new HashSet<GoTypeDeclaration> { type };
+        var level = new List<(GoTypeDeclaration Type, List<string> Via)> { (type, new List<string>()) };
+
+        while (level.Count > 0)
+        {
+            var found = new List<GoMemberResolution>();
+            foreach (var (current, via) in level)
+            {
+                var field = current.Fields.FirstOrDefault(f => f.Name == member && !f.Inline);
+                var method = methods[(current.Package, current.Name)].FirstOrDefault(m => m.Name == member)
+                    ?? current.Methods.FirstOrDefault(m => m.Name == member);
+                if (field != null)
+                    found.Add(Resolution(member, "field", current, field.FilePath, field.Line, via));
+                else if (method != null)
+                    found.Add(Resolution(member, "method", current, method.FilePath, method.Line, via));
+            }
+            if (found.Count > 0)
+                return found.Count == 1 ? found[0] : null;
+
+            var next = new List<(GoTypeDeclaration, List<string>)>();
+            foreach (var (current, via) in level)
+            {
+                foreach (var embedded in current.Embedded)
+                {
+                    var declaration = Resolve(embedded.TrimStart('*'), current.Package, types, null);
+                    if (declaration != null && visited.Add(declaration))
+                        next.Add((declaration, via.Append(Unqualified(embedded)).ToList()));
+                }
+            }
+            level = next;
+        }
+        return null;
+    }
+
+    private static GoMemberResolution Resolution(string name, string kind, GoTypeDeclaration type, string filePath, int line, List<string> via) =>
+        new() { Name = name, Kind = kind, DeclaringType = type, FilePath = filePath, Line = line, PromotedVia = via };
+
+    /// <summary>
+    /// Records what method sets are built from on the types and methods extracted from a Go file: the types each
+    /// struct and interface embeds, interface method specs, and each method's receiver and normalised signature
+    /// </summary>
+    public static void AddMethodSets(TypeExtractionResult result, string content)
+    {
+        var declarations = Parse(string.Empty, content.Replace("\r\n", "\n").Split('\n'));
+        foreach (var type in result.Types)
+        {
+            var declaration = declarations.Types.FirstOrDefault(d => d.Name == type.Name && d.Line == type.Line)
+                ?? declarations.Types.FirstOrDefault(d => d.Name == type.Name);
+            if (declaration == null)
+                continue;
+
+            if (declaration.Embedded.Count > 0)
+                type.Embedded = declaration.Embedded.ToList();
+            foreach (var spec in declaration.Methods)
+                AddMethod(result, declaration.Name, spec, pointerReceiver: null);
+        }
+
+        foreach (var (receiver, method) in declarations.Methods)
+            AddMethod(result, receiver, method, method.PointerReceiver);
+    }
+
+    /// <summary>
+    /// Annotates the extracted method declared on that line, or adds it when the extractor skipped it
+    /// (tree-sitter leaves out interface method specs)
+    /// </summary>
+    private static void AddMethod(TypeExtractionResult result, string owner, GoMethod method, bool? pointerReceiver)
+    {
+        var info = result.Methods.FirstOrDefault(m => m.Name == method.Name && m.Line == method.Line)
+            ?? result.Methods.FirstOrDefault(m => m.Name == method.Name && m.MethodSetSignature == null
+                && pointerReceiver != null && ReceiverOf(m.Signature) == owner);
+        if (info == null)
+        {
+            info = new MethodInfo { Name = method.Name, Signature = method.Name + method.Signature, Line = method.Line };
+            result.Methods.Add(info);
+        }
+
+        info.ContainingType = owner;
+        info.MethodSetSignature = method.Signature;
+        info.PointerReceiver = pointerReceiver;
+    }
+
+    /// <summary>
+    /// Declarations of an indexed Go file, rebuilt from the type information stored with it by
+    /// <see cref="AddMethodSets"/>; struct fields are not stored, so <see cref="FindMember"/> needs <see cref="Parse"/>
+    /// </summary>
+    public static GoFileDeclarations FromTypeInfo(string filePath, TypeExtractionResult typeData)
+    {
+        var result = new GoFileDeclarations();
+        var package = Path.GetDirectoryName(filePath)?.Replace('\\', '/') ?? string.Empty;
+        foreach (var type in typeData.Types)
+        {
+            result.Types.Add(new GoTypeDeclaration
+            {
+                Name = type.Name,
+                Kind = type.Kind.ToLowerInvariant() is "struct" or "interface" ? type.Kind.ToLowerInvariant() : "type",
+                FilePath = filePath,
+                Line = type.Line,
+                Package = package,
+                Embedded = type.Embedded?.ToList() ?? new List<string>()
+            });
+        }
+
+        foreach (var method in typeData.Methods.Where(m => m.ContainingType != null && m.MethodSetSignature != null))
+        {
+            var goMethod = new GoMethod(method.Name, method.MethodSetSignature!, filePath, method.Line, method.PointerReceiver == true);
+            var declaringInterface = method.PointerReceiver == null
+                ? result.Types.FirstOrDefault(t => t.Kind == "interface" && t.Name == method.ContainingType)
+                : null;
+            if (declaringInterface != null)
+                declaringInterface.Methods.Add(goMethod);
+            else
+                result.Methods.Add((method.ContainingType!, goMethod));
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The type an identifier was declared with, read back from <paramref name="line"/> (1-based) to the
+    /// enclosing func: a receiver or parameter (<c>u *User</c>), <c>var u User</c> or a composite literal
+    /// (<c>u := &amp;User{</c>). Null when it is declared some other way, e.g. from a call's result.
+    /// </summary>
+    public static string? DeclaredTypeOf(IReadOnlyList<string> lines, int line, string identifier)
+    {
+        var name = Regex.Escape(identifier);
+        var literal = new Regex($@"\b{name}\s*:?=\s*&?(?<type>[A-Za-z_][\w.]*)\s*(?:\[[^\]]*\])?\s*\{{");
+        var declared = new Regex($@"(?:\bvar\s+{name}|[(,]\s*{name})\s+\*?(?<type>[A-Za-z_][\w.]*)");
+        for (var i = Math.Min(line, lines.Count) - 1; i >= 0; i--)
+        {
+            var text = lines[i];
+            var match = literal.Match(text) is { Success: true } composite ? composite : declared.Match(text);
+            if (match.Success && !TypeKeywords.Contains(match.Groups["type"].Value))
+                return match.Groups["type"].Value;
+            if (text.StartsWith("func", StringComparison.Ordinal))
+                break;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Method specs of an interface, including embedded interfaces
+    /// </summary>
+    private static Dictionary<string, GoMethod> InterfaceMethods(GoTypeDeclaration type, List<GoTypeDeclaration> types,
+        HashSet<GoTypeDeclaration> visiting, List<string> unresolved)
+    {
+        var methods = new Dictionary<string, GoMethod>(StringComparer.Ordinal);
+        if (!visiting.Add(type))
+            return methods;
+
+        foreach (var method in type.Methods)
+            methods[method.Name] = method;
+        foreach (var embedded in type.Embedded)
+        {
+            var name = Unqualified(embedded);
+            var declaration = Resolve(embedded, type.Package, types, "interface");
+            if (declaration != null)
+            {
+                foreach (var (key, value) in InterfaceMethods(declaration, types, visiting, unresolved))
+                    methods.TryAdd(key, value);
+            }
+            else if (KnownInterfaces.TryGetValue(name, out var known))
+            {
+                foreach (var method in known)
+                    methods.TryAdd(method.Name, method);
+            }
+            else
+            {
+                unresolved.Add(embedded);
+            }
+        }
+        return methods;
+    }
+
+    /// <summary>
+    /// Methods callable on a type, by name; NeedsPointer when only the pointer type has the method
+    /// </summary>
+    private static Dictionary<string, (GoMethod Method, bool NeedsPointer)> MethodSet(GoTypeDeclaration type,
+        List<GoTypeDeclaration> types, ILookup<(string Package, string Receiver), GoMethod> methods, HashSet<GoTypeDeclaration> visiting)
+    {
+        var set = new Dictionary<string, (GoMethod, bool)>(StringComparer.Ordinal);
+        if (!visiting.Add(type))
+            return set;
+
+        foreach (var method in methods[(type.Package, type.Name)])
+            set[method.Name] = (method, method.PointerReceiver);
+
+        // Promoted methods never override declared ones; through *Embedded every method reaches the value type
+        foreach (var embedded in type.Embedded)
+        {
+            var pointer = embedded.StartsWith('*');
+            var declaration = Resolve(embedded.TrimStart('*'), type.Package, types, null);
+            if (declaration == null)
+                continue;
+
+            var promoted = declaration.Kind == "interface"
+                ? InterfaceMethods(declaration, types, new HashSet<GoTypeDeclaration>(), new List<string>())
+                    .ToDictionary(m => m.Key, m => (m.Value, false))
+                : MethodSet(declaration, types, methods, visiting);
+            foreach (var (name, (method, needsPointer)) in promoted)
+                set.TryAdd(name, (method, needsPointer && !pointer));
+        }
+        return set;
+    }
+
+    /// <summary>
+    /// The declaration an embedded or target name refers to: same package first, then any package whose
+    /// directory ends with the qualifier
+    /// </summary>
+    public static GoTypeDeclaration? Resolve(string name, string package, IReadOnlyList<GoTypeDeclaration> types, string? kind)
+    {
+        var bare = Unqualified(name.TrimStart('*'));
+        var qualifier = name.TrimStart('*').Contains('.') ? name.TrimStart('*')[..name.TrimStart('*').LastIndexOf('.')] : null;
+        var candidates = types.Where(t => t.Name == bare && (kind == null || t.Kind == kind)).ToList();
+        return candidates.FirstOrDefault(t => qualifier == null && t.Package == package)
+            ?? candidates.FirstOrDefault(t => qualifier != null && (t.Package == qualifier || t.Package.EndsWith("/" + qualifier, StringComparison.Ordinal)))
+            ?? (qualifier == null ? candidates.FirstOrDefault() : null);
+    }
+
+    private static string Unqualified(string name) => name.TrimStart('*')[(name.TrimStart('*').LastIndexOf('.') + 1)..];
+
+    /// <summary>
+    /// Reads a struct or interface body, returning the line it ends on
+    /// </summary>
+    private static int ParseBody(IReadOnlyList<string> lines, string[] masked, int line, int open, GoTypeDeclaration type)
+    {
+        var depth = 0;
+        for (var l = line; l < masked.Length; l++)
+        {
+            var text = masked[l];
+            var lineStartDepth = depth;
+            for (var c = l == line ? open : 0; c < text.Length; c++)
+            {
+                if (text[c] == '{') depth++;
+                else if (text[c] == '}') depth--;
+                if (depth != 0)
+                    continue;
+
+                // Single-line body: type Empty interface{} or interface{ Close() error }; struct{ X, Y int; Base }
+                if (l == line)
+                {
+                    var start = open + 1;
+                    foreach (var member in text[(open + 1)..c].Split(';'))
+                    {
+                        ParseMember(member, start, lines[l], masked, l, type, inline: false);
+                        start += member.Length + 1;
+                    }
+                }
+                return l;
+            }
+
+            if (l > line && lineStartDepth == 1)
+            {
+                var member = text.Trim();
+                if (type.Kind == "interface" && MethodSpec.IsMatch(member))
+                {
+                    var (signature, end) = ReadSignature(masked, l, text.IndexOf('('));
+                    type.Methods.Add(new GoMethod(MethodSpec.Match(member).Groups["name"].Value, signature, type.FilePath, l + 1));
+                    if (end > l)
+                    {
+                        depth += CountBraces(masked, l + 1, end);
+                        l = end;
+                    }
+                }
+                else
+                {
+                    ParseMember(text, 0, lines[l], masked, l, type, inline: false);
+                }
+            }
+            else if (l > line && lineStartDepth > 1 && type.Kind == "struct")
+            {
+                // Fields of an inline struct type: Address struct { City string `json:"city"` } `json:"address"`
+                ParseMember(text, 0, lines[l], masked, l, type, inline: true);
+            }
+        }
+        return masked.Length - 1;
+    }
+
+    private static int CountBraces(string[] masked, int from, int to)
+    {
+        var depth = 0;
+        for (var l = from; l <= to; l++)
+            depth += masked[l].Count(c => c == '{') - masked[l].Count(c => c == '}');
+        return depth;
+    }
+
+    /// <summary>
+    /// Reads one member of a body; <paramref name="text"/> is the masked member, starting at <paramref name="offset"/>
+    /// on the line, and its tag is read from the unmasked <paramref name="source"/> line
+    /// </summary>
+    private static void ParseMember(string text, int offset, string source, string[] masked, int line, GoTypeDeclaration type, bool inline)
+    {
+        var member = text.Trim();
+        offset += text.Length - text.TrimStart().Length;
+        if (member.Length == 0 || member.Contains('|') || member.StartsWith('~'))
+            return;
+
+        if (type.Kind == "interface" && MethodSpec.Match(member) is { Success: true } spec)
+        {
+            var (signature, _) = ReadSignature(new[] { member }, 0, member.IndexOf('('));
+            type.Methods.Add(new GoMethod(spec.Groups["name"].Value, signature, type.FilePath, line + 1));
+        }
+        else if (EmbeddedField.Match(member) is { Success: true } embedded)
+        {
+            var embeddedType = embedded.Groups["type"].Value;
+            if (!inline)
+                type.Embedded.Add(embeddedType);
+            if (type.Kind == "struct")
+            {
+                type.Fields.Add(new GoField(Unqualified(embeddedType), embeddedType, type.FilePath, line + 1, Embedded: true,
+                    Tag: TagOf(member, offset, source), Column: offset + embedded.Groups["type"].Index, Inline: inline));
+            }
+        }
+        else if (type.Kind == "struct" && NamedFields.Match(member) is { Success: true } named)
+        {
+            var tag = TagOf(member, offset, source);
+            foreach (var name in named.Groups["names"].Value.Split(',').Select(n => n.Trim()))
+            {
+                var column = Regex.Match(member, $@"\b{Regex.Escape(name)}\b").Index;
+                type.Fields.Add(new GoField(name, named.Groups["type"].Value.Trim(), type.FilePath, line + 1,
+                    Tag: tag, Column: offset + column, Inline: inline));
+            }
+        }
+    }
+
+    /// <summary>
+    /// The tag of a masked member, read from the source line: masking keeps the backquotes where they are
+    /// </summary>
+    private static string? TagOf(string member, int offset, string source)
+    {
+        var open = member.IndexOf('`');
+        var close = open < 0 ? -1 : member.IndexOf('`', open + 1);
+        return close < 0 || offset + close > source.Length ? null : source[(offset + open + 1)..(offset + close)];
+    }
+
+    /// <summary>
+    /// Normalised signature from the parameter list's '(' up to the body's '{' or the end of the spec,
+    /// joining continuation lines; returns the line it ended on
+    /// </summary>
+    private static (string Signature, int EndLine) ReadSignature(IReadOnlyList<string> masked, int line, int open)
+    {
+        var text = masked[line][open..];
+        var end = line;
+        while (Depth(text) > 0 && end + 1 < masked.Count && end - line < 20)
+            text += " " + masked[++end].Trim();
+
+        var close = Close(text, 0);
+        if (close < 0)
+            return (string.Empty, end);
+
+        var parameters = text[1..close];
+        var rest = text[(close + 1)..];
+        var brace = TopLevelBrace(rest);
+        var results = (brace >= 0 ? rest[..brace] : rest).Trim();
+        if (results.StartsWith('(') && Close(results, 0) is var resultsClose and > 0)
+            results = results[1..resultsClose];
+
+        return ($"({string.Join(",", Types(parameters))})({string.Join(",", Types(results))})", end);
+    }
+
+    /// <summary>
+    /// Parameter types without names: <c>ctx context.Context, a, b int</c> gives Context, int, int
+    /// </summary>
+    private static List<string> Types(string list)
+    {
+        var parts = SplitTopLevel(list).Select(p => p.Trim()).Where(p => p.Length > 0).ToList();
+        var named = parts.Select(p => Regex.Match(p, @"^(?<name>\w+)\s+(?<type>\S.*)$")).ToList();
+        var anyNamed = named.Any(m => m.Success && !TypeKeywords.Contains(m.Groups["name"].Value));
+
+        var types = new string[parts.Count];
+        string? pending = null;
+        for (var i = parts.Count - 1; i >= 0; i--)
+        {
+            if (anyNamed && named[i].Success)
+                pending = named[i].Groups["type"].Value;
+            types[i] = anyNamed ? pending ?? parts[i] : parts[i];
+        }
+        return types.Select(t => PackageQualifier.Replace(Regex.Replace(t, @"\s+", ""), "")).ToList();
+    }
+
+    private static List<string> SplitTopLevel(string text)
+    {
+        var parts = new List<string>();
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            if (text[i] is '(' or '[' or '{') depth++;
+            else if (text[i] is ')' or ']' or '}') depth--;
+            else if (text[i] == ',' && depth == 0)
+            {
+                parts.Add(text[start..i]);
+                start = i + 1;
+            }
+        }
+        parts.Add(text[start..]);
+        return parts;
+    }
+
+    private static int Depth(string text) => text.Count(c => c == '(') - text.Count(c => c == ')');
+
+    private static int Close(string text, int open)
+    {
+        var depth = 0;
+        for (var i = open; i < text.Length; i++)
+        {
+            if (text[i] == '(') depth++;
+            else if (text[i] == ')' && --depth == 0) return i;
+        }
+        return -1;
+    }
+
+    // The body's opening brace, skipping braces of interface{} / struct{} result types
+    private static int TopLevelBrace(string text)
+    {
+        for (var i = 0; i < text.Length; i++)
+        {
+            if (text[i] != '{')
+                continue;
+            var before = text[..i].TrimEnd();
+            if (before.EndsWith("interface", StringComparison.Ordinal) || before.EndsWith("struct", StringComparison.Ordinal))
+            {
+                var depth = 0;
+                for (; i < text.Length; i++)
+                {
+                    if (text[i] == '{') depth++;
+                    else if (text[i] == '}' && --depth == 0) break;
+                }
+                continue;
+            }
+            return i;
+        }
+        return -1;
+    }
+}
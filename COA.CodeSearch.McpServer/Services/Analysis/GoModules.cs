@@ -0,0 +1,381 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A go.mod file: the module it declares and the modules it requires
+/// </summary>
+public class GoModule
+{
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Workspace-relative directory holding go.mod; empty for the workspace root
+    /// </summary>
+    public string Directory { get; set; } = string.Empty;
+
+    public string GoVersion { get; set; } = string.Empty;
+    public List<GoRequirement> Requires { get; set; } = new();
+
+    // path@version entries from go.sum; null when the module has no go.sum
+    internal HashSet<string>? Sums { get; set; }
+}
+
+public class GoRequirement
+{
+    public string Path { get; set; } = string.Empty;
+    public string Version { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Marked // indirect: needed by a dependency, not imported by the module itself
+    /// </summary>
+    public bool Indirect { get; set; }
+
+    /// <summary>
+    /// Target of a replace directive: another module path and version, or a local directory
+    /// </summary>
+    public string? Replacement { get; set; }
+}
+
+/// <summary>
+/// A Go package in the workspace: the directory's non-test files and the tests beside them
+/// </summary>
+public class GoPackage
+{
+    public string ImportPath { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string Directory { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Path of the module the package belongs to
+    /// </summary>
+    public string Module { get; set; } = string.Empty;
+
+    public int Files { get; set; }
+}
+
+/// <summary>
+/// One import statement, resolved to what it names
+/// </summary>
+public class GoImport
+{
+    /// <summary>
+    /// Import path of the importing package
+    /// </summary>
+    public string From { get; set; } = string.Empty;
+
+    public string To { get; set; } = string.Empty;
+
+    /// <summary>
+    /// internal (a workspace package), module (a required module), std, or unknown (no module provides it)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Required module providing an external import, with its version
+    /// </summary>
+    public string? Module { get; set; }
+    public string? Version { get; set; }
+
+    /// <summary>
+    /// Imported from a _test.go file
+    /// </summary>
+    public bool Test { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    // Build constraint of the importing file, evaluated against a profile by callers
+    internal string? Constraint { get; set; }
+}
+
+/// <summary>
+/// A Go source file as the graph needs it: its package clause and imports
+/// </summary>
+public class GoSourceFile
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string PackageName { get; set; } = string.Empty;
+    public List<(string Path, int Line)> Imports { get; set; } = new();
+    public string? Constraint { get; set; }
+}
+
+public class GoPackageGraph
+{
+    public List<GoModule> Modules { get; set; } = new();
+    public Dictionary<string, GoPackage> Packages { get; set; } = new(StringComparer.Ordinal);
+    public List<GoImport> Imports { get; set; } = new();
+}
+
+/// <summary>
+/// Reads go.mod, go.sum and import blocks, and links a workspace's Go files into a package graph the way the
+/// go command resolves import paths: workspace modules first, the standard library for paths without a dot in
+/// their first element, then the importing module's requirements by longest prefix
+/// </summary>
+public static class GoModules
+{
+    private static readonly Regex Directive = new(@"^(?<verb>module|go|require|replace|exclude|retract|toolchain|godebug|tool)\b\s*(?<rest>.*)$", RegexOptions.Compiled);
+    private static readonly Regex Package = new(@"^package\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex ImportSpec = new(@"^(?:(?<alias>[\w.]+)\s+)?(?:""(?<path>[^""]+)""|`(?<path>[^`]+)`)", RegexOptions.Compiled);
+
+    public static GoModule ParseGoMod(string content)
+    {
+        var module = new GoModule();
+        var replacements = new Dictionary<string, string>(StringComparer.Ordinal);
+        string? block = null;
+
+        foreach (var raw in content.Replace("\r\n", "\n").Split('\n'))
+        {
+            var comment = raw.IndexOf("//", StringComparison.Ordinal);
+            var line = (comment >= 0 ? raw[..comment] : raw).Trim();
+            var indirect = comment >= 0 && raw[(comment + 2)..].Trim().StartsWith("indirect", StringComparison.Ordinal);
+
+            if (block != null)
+            {
+                if (line == ")")
+                    block = null;
+                else if (line.Length > 0)
+                    Apply(module, replacements, block, line, indirect);
+                continue;
+            }
+
+            var match = Directive.Match(line);
+            if (!match.Success)
+                continue;
+            var rest = match.Groups["rest"].Value.Trim();
+            if (rest == "(")
+                block = match.Groups["verb"].Value;
+            else
+                Apply(module, replacements, match.Groups["verb"].Value, rest, indirect);
+        }
+
+        foreach (var requirement in module.Requires)
+        {
+            if (replacements.TryGetValue(requirement.Path + "@" + requirement.Version, out var target) || replacements.TryGetValue(requirement.Path, out target))
+                requirement.Replacement = target;
+        }
+        return module;
+    }
+
+    /// <summary>
+    /// The path@version entries of a go.sum file, /go.mod-only entries included
+    /// </summary>
+    public static HashSet<string> ParseGoSum(string content)
+    {
+        var sums = new HashSet<string>(StringComparer.Ordinal);
+        foreach (var line in content.Replace("\r\n", "\n").Split('\n'))
+        {
+            var fields = line.Split(' ', StringSplitOptions.RemoveEmptyEntries);
+            if (fields.Length >= 3)
+                sums.Add(fields[0] + "@" + (fields[1].EndsWith("/go.mod", StringComparison.Ordinal) ? fields[1][..^7] : fields[1]));
+        }
+        return sums;
+    }
+
+    /// <summary>
+    /// The package name and import paths of a Go file, read up to its first declaration
+    /// </summary>
+    public static GoSourceFile ReadFile(string filePath, IReadOnlyList<string> lines)
+    {
+        var file = new GoSourceFile { FilePath = filePath };
+        var inBlock = false;
+        var inComment = false;
+
+        for (var i = 0; i < lines.Count; i++)
+        {
+            var line = lines[i].Trim();
+            if (inComment)
+            {
+                var end = line.IndexOf("*/", StringComparison.Ordinal);
+                if (end < 0)
+                    continue;
+                inComment = false;
+                line = line[(end + 2)..].Trim();
+            }
+            if (line.StartsWith("/*", StringComparison.Ordinal) && !line.Contains("*/", StringComparison.Ordinal))
+            {
+                inComment = true;
+                continue;
+            }
+            if (line.Length == 0 || line.StartsWith("//", StringComparison.Ordinal))
+                continue;
+
+            if (file.PackageName.Length == 0)
+            {
+                var package = Package.Match(line);
+                if (package.Success)
+                    file.PackageName = package.Groups["name"].Value;
+                continue;
+            }
+
+            if (inBlock)
+            {
+                if (line.StartsWith(')'))
+                {
+                    inBlock = false;
+                    continue;
+                }
+                AddImport(file, line, i);
+                continue;
+            }
+
+            if (!line.StartsWith("import", StringComparison.Ordinal) || line.Length > 6 && !char.IsWhiteSpace(line[6]) && line[6] != '(' && line[6] != '"')
+                break;
+            var spec = line[6..].Trim();
+            if (spec.StartsWith('('))
+            {
+                inBlock = true;
+                spec = spec[1..].Trim();
+                if (spec.Length == 0)
+                    continue;
+                // import ( "fmt"; "os" ) on one line
+                foreach (var part in spec.TrimEnd(')').Split(';', StringSplitOptions.RemoveEmptyEntries))
+                    AddImport(file, part.Trim(), i);
+                inBlock = !spec.EndsWith(')');
+                continue;
+            }
+            AddImport(file, spec, i);
+        }
+        return file;
+    }
+
+    /// <summary>
+    /// Links the files into packages under their nearest module and resolves every import
+    /// </summary>
+    public static GoPackageGraph Build(IEnumerable<GoModule> modules, IEnumerable<GoSourceFile> files)
+    {
+        var graph = new GoPackageGraph { Modules = modules.OrderBy(m => m.Directory, StringComparer.Ordinal).ToList() };
+        var byDirectory = graph.Modules.ToDictionary(m => m.Directory, StringComparer.Ordinal);
+        var pending = new List<(GoSourceFile File, GoModule Module, string ImportPath)>();
+
+        foreach (var file in files)
+        {
+            var directory = System.IO.Path.GetDirectoryName(file.FilePath)?.Replace('\\', '/') ?? string.Empty;
+            if (file.PackageName.Length == 0 || Owner(byDirectory, directory) is not { } module)
+                continue;
+
+            var relative = module.Directory.Length == 0 ? directory : directory.Length > module.Directory.Length ? directory[(module.Directory.Length + 1)..] : string.Empty;
+            var importPath = relative.Length == 0 ? module.Path : module.Path + "/" + relative;
+            var test = file.FilePath.EndsWith("_test.go", StringComparison.OrdinalIgnoreCase);
+
+            if (!graph.Packages.TryGetValue(importPath, out var package))
+            {
+                graph.Packages[importPath] = package = new GoPackage { ImportPath = importPath, Directory = directory, Module = module.Path };
+            }
+            package.Files++;
+            // The package's name comes from its non-test files; foo_test tests take it only until one is seen
+            if (!test || package.Name.Length == 0)
+                package.Name = test && file.PackageName.EndsWith("_test", StringComparison.Ordinal) ? file.PackageName[..^5] : file.PackageName;
+
+            pending.Add((file, module, importPath));
+        }
+
+        foreach (var (file, module, from) in pending)
+        {
+            foreach (var (path, line) in file.Imports)
+            {
+                var import = new GoImport
+                {
+                    From = from,
+                    To = path,
+                    Test = file.FilePath.EndsWith("_test.go", StringComparison.OrdinalIgnoreCase),
+                    FilePath = file.FilePath,
+                    Line = line,
+                    Constraint = file.Constraint
+                };
+
+                if (graph.Packages.ContainsKey(path) || graph.Modules.Any(m => HasPrefix(path, m.Path)))
+                {
+                    import.Kind = "internal";
+                }
+                else if (!path.Split('/')[0].Contains('.'))
+                {
+                    import.Kind = "std";
+                }
+                else if (module.Requires.Where(r => HasPrefix(path, r.Path)).MaxBy(r => r.Path.Length) is { } requirement)
+                {
+                    import.Kind = "module";
+                    import.Module = requirement.Path;
+                    import.Version = requirement.Version;
+                }
+                else
+                {
+                    import.Kind = "unknown";
+                }
+                graph.Imports.Add(import);
+            }
+        }
+        return graph;
+    }
+
+    /// <summary>
+    /// Direct requirements no package of the module imports from
+    /// </summary>
+    public static List<GoRequirement> UnusedRequirements(GoPackageGraph graph, GoModule module)
+    {
+        var used = graph.Imports
+            .Where(i => i.Kind == "module" && graph.Packages.TryGetValue(i.From, out var from) && from.Module == module.Path)
+            .Select(i => i.Module!)
+            .ToHashSet(StringComparer.Ordinal);
+        return module.Requires.Where(r => !r.Indirect && !used.Contains(r.Path)).ToList();
+    }
+
+    /// <summary>
+    /// Requirements go.sum has no checksum for; empty when the module has no go.sum
+    /// </summary>
+    public static List<GoRequirement> MissingSums(GoModule module) =>
+        module.Sums == null
+            ? new List<GoRequirement>()
+            : module.Requires.Where(r => r.Replacement == null && !module.Sums.Contains(r.Path + "@" + r.Version)).ToList();
+
+    private static void Apply(GoModule module, Dictionary<string, string> replacements, string verb, string text, bool indirect)
+    {
+        var fields = text.Split((char[]?)null, StringSplitOptions.RemoveEmptyEntries).Select(Unquote).ToArray();
+        switch (verb)
+        {
+            case "module" when fields.Length > 0:
+                module.Path = fields[0];
+                break;
+            case "go" when fields.Length > 0:
+                module.GoVersion = fields[0];
+                break;
+            case "require" when fields.Length >= 2:
+                module.Requires.Add(new GoRequirement { Path = fields[0], Version = fields[1], Indirect = indirect });
+                break;
+            case "replace":
+                var arrow = Array.IndexOf(fields, "=>");
+                if (arrow is 1 or 2 && arrow + 1 < fields.Length)
+                {
+                    var key = arrow == 2 ? fields[0] + "@" + fields[1] : fields[0];
+                    replacements[key] = string.Join(" ", fields[(arrow + 1)..]);
+                }
+                break;
+        }
+    }
+
+    private static void AddImport(GoSourceFile file, string spec, int index)
+    {
+        var match = ImportSpec.Match(spec);
+        if (match.Success)
+            file.Imports.Add((match.Groups["path"].Value, index + 1));
+    }
+
+    // The module whose directory is the nearest ancestor of the file's
+    private static GoModule? Owner(Dictionary<string, GoModule> byDirectory, string directory)
+    {
+        for (var current = directory; ; current = current.Contains('/') ? current[..current.LastIndexOf('/')] : string.Empty)
+        {
+            if (byDirectory.TryGetValue(current, out var module))
+                return module;
+            if (current.Length == 0)
+                return null;
+        }
+    }
+
+    private static bool HasPrefix(string importPath, string modulePath) =>
+        modulePath.Length > 0
+        && (importPath == modulePath || importPath.StartsWith(modulePath + "/", StringComparison.Ordinal));
+
+    private static string Unquote(string field) =>
+        field.Length >= 2 && (field[0] == '"' && field[^1] == '"' || field[0] == '`' && field[^1] == '`') ? field[1..^1] : field;
+}
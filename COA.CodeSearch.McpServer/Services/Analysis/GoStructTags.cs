@@ -0,0 +1,161 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A Go struct field with its tag
+/// </summary>
+public class StructTagField
+{
+    /// <summary>
+    /// The named struct declaring the field; fields of inline struct types belong to the named struct around them
+    /// </summary>
+    public string Struct { get; set; } = string.Empty;
+
+    public string Field { get; set; } = string.Empty;
+    public string Type { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The tag as written, without the backquotes
+    /// </summary>
+    public string Tag { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Tag values by key: json → "user_name,omitempty"
+    /// </summary>
+    public Dictionary<string, string> Tags { get; set; } = new(StringComparer.Ordinal);
+
+    public int Line { get; set; }
+
+    /// <summary>
+    /// 0-based column of the field name
+    /// </summary>
+    public int Column { get; set; }
+}
+
+/// <summary>
+/// A parsed tag:KEY=VALUE query. KEY may be empty (any key) or "column" (the database column a db, gorm, bun,
+/// pg or sql tag names); VALUE may be empty (any value) and may use * wildcards
+/// </summary>
+public class StructTagQuery
+{
+    public string Key { get; set; } = string.Empty;
+    public string Value { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Reads Go struct field tags (<c>json:"user_name,omitempty" db:"user_name"</c>) and matches them against
+/// tag:KEY=VALUE queries, so the names a struct exposes over an API or maps to a table can be searched directly.
+/// Values are compared by the name part of the tag - what precedes the first comma - and gorm's column: setting.
+/// </summary>
+public static class GoStructTags
+{
+    public const string QueryPrefix = "tag:";
+
+    /// <summary>
+    /// Pseudo-key matching whichever tag names the database column
+    /// </summary>
+    public const string ColumnKey = "column";
+
+    private static readonly string[] ColumnTagKeys = { "db", "gorm", "bun", "pg", "sql", "sqlite", "ch" };
+
+    private static readonly Regex TagPair = new(@"(?<key>[^\s:""]+):""(?<value>(?:[^""\\]|\\.)*)""", RegexOptions.Compiled);
+    private static readonly Regex QuerySyntax = new(@"^tag:(?<key>[\w.-]*)(?:=(?<value>.*))?$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    /// <summary>
+    /// True when the query uses the tag: prefix
+    /// </summary>
+    public static bool IsTagQuery(string query) => query.TrimStart().StartsWith(QueryPrefix, StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Parses tag:json=user_name, tag:db, tag:=user_name or tag:column=is_active; the tag: prefix is optional
+    /// </summary>
+    public static bool TryParseQuery(string query, out StructTagQuery parsed)
+    {
+        var text = query.Trim();
+        if (!IsTagQuery(text))
+            text = QueryPrefix + text;
+
+        parsed = new StructTagQuery();
+        var match = QuerySyntax.Match(text);
+        if (!match.Success)
+            return false;
+
+        parsed.Key = match.Groups["key"].Value;
+        parsed.Value = match.Groups["value"].Value.Trim().Trim('"', '`');
+        return parsed.Key.Length > 0 || parsed.Value.Length > 0;
+    }
+
+    /// <summary>
+    /// Tagged fields of the named structs in a Go file, as <see cref="GoMethodSets.Parse"/> reads them
+    /// </summary>
+    public static List<StructTagField> Scan(IReadOnlyList<string> lines) =>
+        GoMethodSets.Parse(string.Empty, lines).Types
+            .Where(t => t.Kind == "struct")
+            .SelectMany(t => t.Fields.Where(f => f.Tag != null).Select(f => new StructTagField
+            {
+                Struct = t.Name,
+                Field = f.Name,
+                Type = f.Type,
+                Tag = f.Tag!,
+                Tags = Parse(f.Tag!),
+                Line = f.Line,
+                Column = f.Column
+            }))
+            .ToList();
+
+    /// <summary>
+    /// Tag key/value pairs in reflect.StructTag's conventional format
+    /// </summary>
+    public static Dictionary<string, string> Parse(string tag) =>
+        TagPair.Matches(tag)
+            .GroupBy(m => m.Groups["key"].Value, StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => g.First().Groups["value"].Value, StringComparer.Ordinal);
+
+    /// <summary>
+    /// The name a tag value gives the field: json's "user_name,omitempty" → user_name, gorm's
+    /// "column:user_name;not null" → user_name. Null when the tag names nothing (gorm without column:, "-").
+    /// </summary>
+    public static string? NameOf(string key, string value)
+    {
+        if (key == "gorm")
+        {
+            return value.Split(';').Select(s => s.Trim())
+                .FirstOrDefault(s => s.StartsWith("column:", StringComparison.OrdinalIgnoreCase))?[7..];
+        }
+        var name = value.Split(',')[0].Trim();
+        return name.Length == 0 || name == "-" ? null : name;
+    }
+
+    /// <summary>
+    /// The tags of <paramref name="field"/> the query matches, as key → matched name (or the whole value for
+    /// key-only queries)
+    /// </summary>
+    public static List<(string Key, string Value)> Match(StructTagField field, StructTagQuery query, bool caseSensitive)
+    {
+        var keys = query.Key.Length == 0 ? field.Tags.Keys.ToList()
+            : query.Key.Equals(ColumnKey, StringComparison.OrdinalIgnoreCase) ? ColumnTagKeys.Where(field.Tags.ContainsKey).ToList()
+            : field.Tags.Keys.Where(k => k.Equals(query.Key, caseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase)).ToList();
+
+        var pattern = query.Value.Length == 0 ? null : ValuePattern(query.Value, caseSensitive);
+        var matches = new List<(string Key, string Value)>();
+        foreach (var key in keys)
+        {
+            var value = field.Tags[key];
+            if (pattern == null)
+            {
+                matches.Add((key, value));
+                continue;
+            }
+
+            // Options (omitempty, primaryKey) are matched too when the query spells them with their separator
+            var name = NameOf(key, value);
+            if (name != null && pattern.IsMatch(name) || query.Value.IndexOfAny(new[] { ',', ';', ':' }) >= 0 && pattern.IsMatch(value))
+                matches.Add((key, name ?? value));
+        }
+        return matches;
+    }
+
+    private static Regex ValuePattern(string value, bool caseSensitive) =>
+        new("^" + Regex.Escape(value).Replace(@"\*", ".*") + "$", caseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase);
+}
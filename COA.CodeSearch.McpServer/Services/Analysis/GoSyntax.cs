@@ -0,0 +1,276 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A Go construct newer than the grammar bundled with older julie-codesearch builds
+/// </summary>
+public class GoSyntaxFeature
+{
+    /// <summary>
+    /// range-over-int, range-over-func, iterator, generic-alias or loop-capture
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Go release that introduced it: 1.22, 1.23 or 1.24
+    /// </summary>
+    public string Since { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The line, trimmed
+    /// </summary>
+    public string Text { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Keeps Go 1.22+ files whole in symbol extraction. Range-over-int and range-over-func loops, iterator
+/// signatures, generic aliases and per-iteration loop variables trip grammars older than the language:
+/// error recovery drops the declarations around them or cuts function extents short. For files using
+/// them the extracted symbols are reconciled with the line scanner - missing top-level declarations are
+/// recovered and extents are re-measured by brace matching.
+/// </summary>
+public static class GoSyntax
+{
+    private static readonly Regex RangeClause = new(@"\bfor\b(?:\s+(?<vars>[\w\s,]+?)\s*:?=)?\s*range\s+(?<expr>.+?)\s*\{\s*$", RegexOptions.Compiled);
+    private static readonly Regex ThreeClauseFor = new(@"\bfor\s+(?<var>\w+)\s*:=[^;{]*;", RegexOptions.Compiled);
+    private static readonly Regex IntExpression = new(@"^(?:\d[\d_]*|(?:len|cap|int|int8|int16|int32|int64|uint|uint8|uint16|uint32|uint64)\(.*\)|.*[-+*/%]\s*\d+)$", RegexOptions.Compiled);
+    private static readonly Regex CallName = new(@"^(?:(?<qualifier>\w+)\.)?(?<name>\w+)(?:\[[^\]]*\])?\(", RegexOptions.Compiled);
+    private static readonly Regex FuncDeclaration = new(
+        @"^func\s+(?:\([^)]*\)\s*)?(?<name>\w+)\s*(?:\[(?:[^\[\]]|\[[^\]]*\])*\])?\s*\((?<params>[^)]*)\)\s*(?<result>[^{]*?)\s*\{?\s*$",
+        RegexOptions.Compiled);
+    private static readonly Regex IteratorResult = new(@"^(?:\*?iter\.Seq2?\b|func\s*\(\s*yield\s+func\b)", RegexOptions.Compiled);
+    private static readonly Regex GenericAlias = new(@"^(?:type\s+|\s+)\w+\s*\[[^\]]*\]\s*=", RegexOptions.Compiled);
+    private static readonly Regex ImportSpec = new(@"^\s*(?:import\s+)?(?:(?<alias>\w+)\s+)?""(?<path>[\w./-]+)""\s*$", RegexOptions.Compiled);
+    private static readonly Regex Closure = new(@"\bfunc\s*\(|^\s*(?:go|defer)\s", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Standard library functions returning iterators (Go 1.23+)
+    /// </summary>
+    private static readonly HashSet<string> StandardIterators = new(StringComparer.Ordinal)
+    {
+        "maps.All", "maps.Keys", "maps.Values", "slices.All", "slices.Values", "slices.Backward", "slices.Chunk",
+        "strings.Lines", "strings.SplitSeq", "strings.SplitAfterSeq", "strings.FieldsSeq", "strings.FieldsFuncSeq",
+        "bytes.Lines", "bytes.SplitSeq", "bytes.SplitAfterSeq", "bytes.FieldsSeq", "bytes.FieldsFuncSeq"
+    };
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal)
+    {
+        "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go", "goto",
+        "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type", "var"
+    };
+
+    /// <summary>
+    /// Go 1.22+ constructs in a file, in line order
+    /// </summary>
+    public static List<GoSyntaxFeature> FindFeatures(string content)
+    {
+        var features = new List<GoSyntaxFeature>();
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+
+        // Iterators declared here; ranging over a call to one is range-over-func
+        var iterators = new HashSet<string>(StringComparer.Ordinal);
+        var packages = new HashSet<string>(StringComparer.Ordinal);
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (ImportSpec.Match(lines[i]) is { Success: true } import)
+                packages.Add(import.Groups["alias"].Success ? import.Groups["alias"].Value : import.Groups["path"].Value.Split('/')[^1]);
+            if (FuncDeclaration.Match(masked[i]) is { Success: true } function && IteratorResult.IsMatch(function.Groups["result"].Value))
+            {
+                iterators.Add(function.Groups["name"].Value);
+                Add(features, "iterator", "1.23", i, lines);
+            }
+            else if (GenericAlias.IsMatch(masked[i]) && (masked[i].StartsWith("type ") || InTypeBlock(masked, i)))
+            {
+                Add(features, "generic-alias", "1.24", i, lines);
+            }
+        }
+
+        string? signature = null;
+        var loops = new List<(int Depth, HashSet<string> Variables)>();
+        var depth = 0;
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var line = masked[i];
+            if (line.StartsWith("func ", StringComparison.Ordinal))
+                signature = line;
+
+            // A closure in a loop body sees its own copy of each loop variable since Go 1.22
+            if (loops.Count > 0 && Closure.IsMatch(line) &&
+                loops.SelectMany(l => l.Variables).Any(v => Regex.IsMatch(line, $@"\b{Regex.Escape(v)}\b")))
+            {
+                Add(features, "loop-capture", "1.22", i, lines);
+            }
+
+            var variables = new HashSet<string>(StringComparer.Ordinal);
+            if (RangeClause.Match(line) is { Success: true } range)
+            {
+                var expression = range.Groups["expr"].Value.Trim();
+                if (IsInteger(expression, signature, masked, i))
+                    Add(features, "range-over-int", "1.22", i, lines);
+                else if (IsIterator(expression, iterators, packages))
+                    Add(features, "range-over-func", "1.23", i, lines);
+
+                foreach (var name in range.Groups["vars"].Value.Split(',', StringSplitOptions.TrimEntries | StringSplitOptions.RemoveEmptyEntries))
+                {
+                    if (name != "_")
+                        variables.Add(name);
+                }
+            }
+            else if (ThreeClauseFor.Match(line) is { Success: true } loop)
+            {
+                variables.Add(loop.Groups["var"].Value);
+            }
+
+            var opened = line.Count(c => c == '{') - line.Count(c => c == '}');
+            if (variables.Count > 0)
+                loops.Add((depth + 1, variables));
+            depth = Math.Max(0, depth + opened);
+            loops.RemoveAll(l => l.Depth > depth);
+        }
+
+        return features.GroupBy(f => (f.Kind, f.Line)).Select(g => g.First()).OrderBy(f => f.Line).ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Go file using Go 1.22+ constructs, with dropped top-level declarations recovered and
+    /// truncated extents corrected. Returns <paramref name="symbols"/> itself when nothing needed repair.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols)
+    {
+        if (FindFeatures(content).Count == 0)
+            return symbols;
+
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+        var repaired = symbols.Where(s => !Keywords.Contains(s.Name)).ToList();
+        var changed = repaired.Count != symbols.Count;
+
+        foreach (var declaration in DeclarationScanner.Scan(content, filePath))
+        {
+            var endLine = EndOf(masked, declaration.Line);
+            var existing = repaired.FirstOrDefault(s => s.Name == declaration.Name && s.StartLine == declaration.Line);
+            if (existing == null)
+            {
+                var symbol = ToSymbol(filePath, declaration, endLine);
+                if (declaration.Container != null)
+                    symbol.ParentId = repaired.FirstOrDefault(s => s.Name == declaration.Container && s.ParentId == null && IsType(s.Kind))?.Id;
+                repaired.Add(symbol);
+                changed = true;
+            }
+            else if (existing.EndLine < endLine)
+            {
+                existing.EndLine = endLine;
+                existing.EndColumn = lines[endLine - 1].Length;
+                changed = true;
+            }
+        }
+
+        return changed ? repaired.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList() : symbols;
+    }
+
+    /// <summary>
+    /// The line closing the declaration starting on <paramref name="line"/> (1-based): its matching brace,
+    /// or the line itself when it opens no block
+    /// </summary>
+    private static int EndOf(string[] masked, int line)
+    {
+        var depth = 0;
+        var opened = false;
+        for (var i = line - 1; i < masked.Length; i++)
+        {
+            foreach (var c in masked[i])
+            {
+                if (c == '{')
+                {
+                    depth++;
+                    opened = true;
+                }
+                else if (c == '}')
+                {
+                    depth--;
+                }
+            }
+            if (!opened)
+                return line;
+            if (depth <= 0)
+                return i + 1;
+        }
+        return masked.Length;
+    }
+
+    private static bool IsInteger(string expression, string? signature, string[] masked, int index)
+    {
+        if (IntExpression.IsMatch(expression))
+            return true;
+        if (!Regex.IsMatch(expression, @"^\w+$"))
+            return false;
+
+        // A parameter or local of an integer type
+        var name = Regex.Escape(expression);
+        var integer = @"u?int(?:8|16|32|64)?\b";
+        if (signature != null && Regex.IsMatch(signature, $@"[(,]\s*(?:\w+\s*,\s*)*{name}\s*(?:,\s*\w+\s*)*{integer}"))
+            return true;
+        for (var i = index - 1; i >= 0 && !masked[i].StartsWith("func ", StringComparison.Ordinal); i--)
+        {
+            if (Regex.IsMatch(masked[i], $@"\b{name}\s*:=\s*(?:\d|len\(|cap\()") || Regex.IsMatch(masked[i], $@"\bvar\s+{name}\s+{integer}"))
+                return true;
+        }
+        return false;
+    }
+
+    private static bool IsIterator(string expression, HashSet<string> iterators, HashSet<string> packages)
+    {
+        if (expression.StartsWith("func(", StringComparison.Ordinal) || expression.StartsWith("func (", StringComparison.Ordinal))
+            return true;
+
+        // The outermost call decides: slices.Sorted(maps.Keys(m)) ranges over a slice
+        var call = CallName.Match(Regex.Replace(expression, @"^[\w.]*?(?=\w+\.\w+\()", string.Empty));
+        if (!call.Success)
+            return false;
+        var name = call.Groups["name"].Value;
+        if (call.Groups["qualifier"].Success && packages.Contains(call.Groups["qualifier"].Value))
+            return StandardIterators.Contains($"{call.Groups["qualifier"].Value}.{name}");
+        return iterators.Contains(name);
+    }
+
+    private static bool InTypeBlock(string[] masked, int index)
+    {
+        for (var i = index - 1; i >= 0; i--)
+        {
+            if (masked[i].StartsWith("type (", StringComparison.Ordinal))
+                return true;
+            if (masked[i].StartsWith(')') || masked[i].StartsWith("func ", StringComparison.Ordinal))
+                return false;
+        }
+        return false;
+    }
+
+    private static void Add(List<GoSyntaxFeature> features, string kind, string since, int index, string[] lines) =>
+        features.Add(new GoSyntaxFeature { Kind = kind, Since = since, Line = index + 1, Text = lines[index].Trim() });
+
+    private static bool IsType(string kind) => kind is "struct" or "interface" or "type" or "class";
+
+    private static JulieSymbol ToSymbol(string filePath, Declaration declaration, int endLine) => new()
+    {
+        Id = StableId(filePath, declaration.QualifiedName, declaration.Line),
+        Name = declaration.Name,
+        Kind = declaration.Kind,
+        Language = "go",
+        FilePath = filePath,
+        StartLine = declaration.Line,
+        EndLine = endLine,
+        Signature = declaration.Signature,
+        Visibility = declaration.IsPublic ? "public" : "private"
+    };
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"go:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+}
@@ -0,0 +1,340 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// GraphQL schema declarations read from .graphql, .graphqls and .gql files: object, interface and input types,
+/// enums and their values, unions, scalars, <c>extend type</c> blocks, and the fields of each. Fields of the root
+/// operation types are methods, signed with their operation (<c>query user(id: ID!): User</c>), so queries and
+/// mutations can be searched for like functions.
+/// </summary>
+public static class GraphQLSymbols
+{
+    private static readonly HashSet<string> Extensions = new(StringComparer.OrdinalIgnoreCase) { ".graphql", ".graphqls", ".gql" };
+    private static readonly Regex Definition = new(
+        @"^\s*(?<extend>extend\s+)?(?<keyword>type|interface|input|enum|union|scalar|schema)\b\s*(?<name>[_A-Za-z]\w*)?", RegexOptions.Compiled);
+    private static readonly Regex Field = new(@"^\s*(?<name>[_A-Za-z]\w*)\s*(?=[(:])", RegexOptions.Compiled);
+    private static readonly Regex EnumValue = new(@"^\s*(?<name>[_A-Za-z]\w*)\b", RegexOptions.Compiled);
+    private static readonly Regex RootOperation = new(@"\b(?<operation>query|mutation|subscription)\s*:\s*(?<type>[_A-Za-z]\w*)", RegexOptions.Compiled);
+    private static readonly Regex Directive = new(@"\s*@\w+(?:\s*\([^)]*\))?", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for the file extensions GraphQL schemas are written in
+    /// </summary>
+    public static bool Handles(string filePath) => Extensions.Contains(Path.GetExtension(filePath));
+
+    /// <summary>
+    /// Every declaration in a schema file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = Mask(lines);
+        var roots = RootTypes(masked);
+        var found = new List<Found>();
+
+        Found? block = null;
+        string? blockKeyword = null;
+        var pending = false;
+        var depth = 0;
+        var parens = 0;
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var line = masked[i];
+            Match match;
+
+            if (depth == 0 && parens == 0 && (match = Definition.Match(line)).Success)
+            {
+                var keyword = match.Groups["keyword"].Value;
+
+                // union SearchResult =
+                //   | User
+                //   | Post
+                var end = i;
+                while (keyword == "union" && end + 1 < lines.Length && Regex.IsMatch(masked[end + 1], @"^\s*[|=]"))
+                    end++;
+
+                block = keyword == "schema" || !match.Groups["name"].Success
+                    ? null
+                    : Declare(found, Kind(keyword), match, lines, i, null, Signature(string.Join(" ", lines[i..(end + 1)].Select(StripComment))));
+                blockKeyword = keyword;
+                pending = keyword is not ("union" or "scalar");
+                if (block != null)
+                    block.EndLine = end + 1;
+            }
+            else if (depth == 1 && parens == 0 && block != null && blockKeyword == "enum" && (match = EnumValue.Match(line)).Success)
+            {
+                Declare(found, "enum_member", match, lines, i, block, match.Groups["name"].Value);
+            }
+            else if (depth == 1 && parens == 0 && block != null && blockKeyword is "type" or "interface" or "input"
+                     && (match = Field.Match(line)).Success)
+            {
+                var signature = FieldSignature(lines, masked, i, match.Index);
+                var operation = roots.FirstOrDefault(r => r.Value == block.Name).Key;
+                if (operation != null)
+                    Declare(found, "method", match, lines, i, block, $"{operation} {signature}");
+                else
+                    Declare(found, "field", match, lines, i, block, signature);
+            }
+
+            foreach (var c in line)
+            {
+                switch (c)
+                {
+                    case '(':
+                        parens++;
+                        break;
+                    case ')' when parens > 0:
+                        parens--;
+                        break;
+                    case '{':
+                        if (depth == 0 && !pending)
+                            block = null;
+                        pending = false;
+                        depth++;
+                        break;
+                    case '}' when depth > 0:
+                        depth--;
+                        if (depth == 0 && block != null)
+                        {
+                            block.EndLine = i + 1;
+                            block = null;
+                        }
+                        break;
+                }
+            }
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in found)
+        {
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = declaration.Kind,
+                Language = "graphql",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = Math.Max(declaration.Line, declaration.EndLine),
+                EndColumn = lines[Math.Max(declaration.Line, declaration.EndLine) - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = "public"
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a schema file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    private static string Kind(string keyword) => keyword switch
+    {
+        "interface" => "interface",
+        "enum" => "enum",
+        "union" or "scalar" => "type",
+        _ => "class"
+    };
+
+    /// <summary>
+    /// Root operation types by operation, from a <c>schema { }</c> block in the file or the default names
+    /// </summary>
+    private static Dictionary<string, string> RootTypes(string[] masked)
+    {
+        var text = string.Join("\n", masked);
+        var roots = new Dictionary<string, string>(StringComparer.Ordinal);
+        foreach (Match schema in Regex.Matches(text, @"\bschema\b[^{]*\{(?<body>[^}]*)\}"))
+        {
+            foreach (Match operation in RootOperation.Matches(schema.Groups["body"].Value))
+                roots[operation.Groups["operation"].Value] = operation.Groups["type"].Value;
+        }
+        if (roots.Count == 0)
+        {
+            roots["query"] = "Query";
+            roots["mutation"] = "Mutation";
+            roots["subscription"] = "Subscription";
+        }
+        return roots;
+    }
+
+    /// <summary>
+    /// A field as written, from its name through its type, with arguments spread over several lines joined and
+    /// directives dropped: <c>user(id: ID!, includeDeleted: Boolean = false): User</c>
+    /// </summary>
+    private static string FieldSignature(string[] lines, string[] masked, int index, int column)
+    {
+        var text = new StringBuilder(StripComment(lines[index])[column..]);
+        var parens = masked[index][column..].Count(c => c == '(') - masked[index][column..].Count(c => c == ')');
+        for (var i = index + 1; parens > 0 && i < lines.Length; i++)
+        {
+            text.Append(' ').Append(StripComment(lines[i]).Trim());
+            parens += masked[i].Count(c => c == '(') - masked[i].Count(c => c == ')');
+        }
+        var signature = Regex.Replace(Directive.Replace(text.ToString(), string.Empty), @"\s+", " ").Trim();
+        signature = Regex.Replace(signature, @"\(\s+", "(");
+        signature = Regex.Replace(signature, @"\s+\)", ")");
+        signature = Regex.Replace(signature, @",?\s*\)", ")");
+        signature = Regex.Replace(signature, @"(?<=\w|\)|\]|!)\s*,?\s+(?=\w+\s*:)", ", ");
+        signature = signature.TrimEnd(',', ' ', '{');
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    private static Found Declare(List<Found> found, string kind, Match match, string[] lines, int index, Found? container, string signature)
+    {
+        var name = match.Groups["name"];
+        var declaration = new Found
+        {
+            Kind = kind,
+            Name = name.Value,
+            Line = index + 1,
+            Column = name.Index,
+            EndLine = index + 1,
+            Signature = signature,
+            Container = container
+        };
+        found.Add(declaration);
+        return declaration;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = Directive.Replace(StripComment(line).Trim(), string.Empty);
+        signature = Regex.Replace(Regex.Replace(signature, @"\s*\{.*$", string.Empty), @"\s+", " ");
+        signature = Regex.Replace(signature, @"=\s*\|", "=");
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    /// <summary>
+    /// The lines with comments removed and string contents - descriptions included - blanked out, so braces and
+    /// parentheses in them do not count
+    /// </summary>
+    private static string[] Mask(string[] lines)
+    {
+        var masked = new string[lines.Length];
+        var inBlockString = false;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i].ToCharArray();
+            var inString = false;
+            for (var j = 0; j < line.Length; j++)
+            {
+                if (inBlockString)
+                {
+                    if (string.CompareOrdinal(lines[i], j, "\"\"\"", 0, 3) == 0)
+                    {
+                        inBlockString = false;
+                        line[j] = line[j + 1] = line[j + 2] = ' ';
+                        j += 2;
+                    }
+                    else
+                    {
+                        line[j] = ' ';
+                    }
+                }
+                else if (inString)
+                {
+                    if (line[j] == '\\' && j + 1 < line.Length)
+                        line[j++] = ' ';
+                    else if (line[j] == '"')
+                        inString = false;
+                    line[j] = ' ';
+                }
+                else if (string.CompareOrdinal(lines[i], j, "\"\"\"", 0, 3) == 0)
+                {
+                    inBlockString = true;
+                    line[j] = line[j + 1] = line[j + 2] = ' ';
+                    j += 2;
+                }
+                else if (line[j] == '"')
+                {
+                    inString = true;
+                    line[j] = ' ';
+                }
+                else if (line[j] == '#')
+                {
+                    for (; j < line.Length; j++)
+                        line[j] = ' ';
+                }
+            }
+            masked[i] = new string(line);
+        }
+        return masked;
+    }
+
+    private static string StripComment(string line)
+    {
+        var inString = false;
+        for (var i = 0; i < line.Length; i++)
+        {
+            if (line[i] == '"' && (i == 0 || line[i - 1] != '\\'))
+                inString = !inString;
+            else if (line[i] == '#' && !inString)
+                return line[..i].TrimEnd();
+        }
+        return line;
+    }
+
+    /// <summary>
+    /// The description string or <c>#</c> comment lines right above a declaration
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var end = index - 1;
+        if (end < 0)
+            return null;
+
+        var last = lines[end].Trim();
+        var i = end;
+        if (last.EndsWith("\"\"\"", StringComparison.Ordinal))
+        {
+            while (i > 0 && !(lines[i].TrimStart().StartsWith("\"\"\"", StringComparison.Ordinal) && (i < end || last.Length > 3)))
+                i--;
+        }
+        else if (last.StartsWith('"') && last.EndsWith('"') && last.Length > 1)
+        {
+            // "A one-line description"
+        }
+        else if (last.StartsWith('#'))
+        {
+            while (i > 0 && lines[i - 1].TrimStart().StartsWith('#'))
+                i--;
+        }
+        else
+        {
+            return null;
+        }
+        return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"graphql:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+        public string Name { get; init; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; set; }
+        public string Signature { get; init; } = string.Empty;
+        public Found? Container { get; init; }
+    }
+}
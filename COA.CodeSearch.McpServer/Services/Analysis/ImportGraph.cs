@@ -0,0 +1,250 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// One import, using, use or require statement and the workspace files it reaches
+/// </summary>
+public class ImportLink
+{
+    /// <summary>
+    /// Workspace-relative path of the importing file
+    /// </summary>
+    public string FromPath { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The imported module, package, namespace or path as written
+    /// </summary>
+    public string Import { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Workspace files the import resolves to: the module file, the package directory's files or the files
+    /// declaring the namespace. Empty for third-party and standard library imports.
+    /// </summary>
+    public List<string> Targets { get; set; } = new();
+}
+
+/// <summary>
+/// Reads the imports of source files and resolves them to workspace files by path - relative JS/TS specifiers,
+/// Python modules, Go import path suffixes, C#/Java/Kotlin/Scala namespaces and Rust crate paths - without
+/// running a build or a package manager
+/// </summary>
+public static class ImportGraph
+{
+    private static readonly Regex ScriptImport = new(@"(?:\bfrom\s+|\bimport\s*\(?\s*|\brequire\s*\(\s*)['""](?<path>[^'""\s]+)['""]", RegexOptions.Compiled);
+    private static readonly Regex PythonImport = new(@"^\s*(?:from\s+(?<module>\.*[\w.]*)\s+import\b|import\s+(?<module>[\w.]+))", RegexOptions.Compiled);
+    private static readonly Regex GoImportLine = new(@"^\s*(?:import\s+)?(?:[\w.]+\s+)?""(?<path>[^""]+)""\s*(?://.*)?$", RegexOptions.Compiled);
+    private static readonly Regex DottedImport = new(
+        @"^\s*(?:(?:global\s+)?using\s+(?:static\s+)?(?:\w+\s*=\s*)?|import\s+(?:static\s+)?)(?<name>[\w.]+?)(?:\.\*|\._)?(?:\s+as\s+\w+)?\s*;?\s*$",
+        RegexOptions.Compiled);
+    private static readonly Regex NamespaceDeclaration = new(@"^\s*(?:namespace|package)\s+(?<name>[\w.]+)", RegexOptions.Compiled | RegexOptions.Multiline);
+    private static readonly Regex RustUse = new(@"^\s*(?:pub(?:\([^)]*\))?\s+)?use\s+(?<path>[\w:]+)", RegexOptions.Compiled);
+
+    private static readonly string[] ScriptExtensions = { ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".vue", ".svelte" };
+
+    /// <summary>
+    /// The imports of every file (workspace-relative paths with forward slashes; files without content are
+    /// skipped), each resolved against the same set of files
+    /// </summary>
+    public static List<ImportLink> Build(IReadOnlyList<(string Path, string? Content)> files)
+    {
+        var paths = files.Select(f => f.Path).ToHashSet(StringComparer.Ordinal);
+        var byDirectory = files.GroupBy(f => DirectoryOf(f.Path), StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => g.Select(f => f.Path).ToList(), StringComparer.Ordinal);
+        var namespaces = new Dictionary<string, List<string>>(StringComparer.Ordinal);
+        foreach (var (path, content) in files.Where(f => f.Content != null && IsNamespaced(f.Path)))
+        {
+            foreach (Match declaration in NamespaceDeclaration.Matches(content!))
+            {
+                var name = declaration.Groups["name"].Value;
+                if (!namespaces.TryGetValue(name, out var declaring))
+                    namespaces[name] = declaring = new List<string>();
+                declaring.Add(path);
+            }
+        }
+
+        var links = new List<ImportLink>();
+        foreach (var (path, content) in files.Where(f => f.Content != null))
+        {
+            foreach (var (line, import) in Imports(path, content!))
+            {
+                links.Add(new ImportLink
+                {
+                    FromPath = path,
+                    Line = line,
+                    Import = import,
+                    Targets = Resolve(path, import, paths, byDirectory, namespaces).Where(t => t != path).Distinct().ToList()
+                });
+            }
+        }
+        return links;
+    }
+
+    /// <summary>
+    /// The import statements of one file as (1-based line, imported name) pairs
+    /// </summary>
+    public static IEnumerable<(int Line, string Import)> Imports(string path, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var extension = Path.GetExtension(path).ToLowerInvariant();
+        var inGoBlock = false;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            switch (extension)
+            {
+                case ".ts" or ".tsx" or ".js" or ".jsx" or ".mjs" or ".cjs" or ".vue" or ".svelte":
+                    foreach (Match import in ScriptImport.Matches(line))
+                        yield return (i + 1, import.Groups["path"].Value);
+                    break;
+
+                case ".py":
+                    if (PythonImport.Match(line) is { Success: true } python)
+                        yield return (i + 1, python.Groups["module"].Value);
+                    break;
+
+                case ".go":
+                    var trimmed = line.Trim();
+                    if (trimmed.StartsWith("import (", StringComparison.Ordinal) || trimmed == "import(")
+                    {
+                        inGoBlock = true;
+                        continue;
+                    }
+                    if (inGoBlock && trimmed.StartsWith(')'))
+                    {
+                        inGoBlock = false;
+                        continue;
+                    }
+                    if ((inGoBlock || trimmed.StartsWith("import ", StringComparison.Ordinal)) && GoImportLine.Match(line) is { Success: true } go)
+                        yield return (i + 1, go.Groups["path"].Value);
+                    break;
+
+                case ".cs" or ".java" or ".kt" or ".kts" or ".scala":
+                    if (DottedImport.Match(line) is { Success: true } dotted)
+                        yield return (i + 1, dotted.Groups["name"].Value);
+                    break;
+
+                case ".rs":
+                    if (RustUse.Match(line) is { Success: true } use)
+                        yield return (i + 1, use.Groups["path"].Value);
+                    break;
+            }
+        }
+    }
+
+    private static IEnumerable<string> Resolve(string path, string import, HashSet<string> paths, Dictionary<string, List<string>> byDirectory,
+        Dictionary<string, List<string>> namespaces)
+    {
+        var directory = DirectoryOf(path);
+        switch (Path.GetExtension(path).ToLowerInvariant())
+        {
+            case ".ts" or ".tsx" or ".js" or ".jsx" or ".mjs" or ".cjs" or ".vue" or ".svelte":
+                if (!import.StartsWith("./", StringComparison.Ordinal) && !import.StartsWith("../", StringComparison.Ordinal))
+                    break;
+                if (Normalize(directory.Length == 0 ? import : $"{directory}/{import}") is not { } target)
+                    break;
+                var file = new[] { target }
+                    .Concat(ScriptExtensions.Select(e => target + e))
+                    .Concat(ScriptExtensions.Select(e => $"{target}/index{e}"))
+                    .FirstOrDefault(paths.Contains);
+                if (file != null)
+                    return new[] { file };
+                return byDirectory.GetValueOrDefault(target) ?? Enumerable.Empty<string>();
+
+            case ".py":
+            {
+                var dots = import.TakeWhile(c => c == '.').Count();
+                var relative = import[dots..].Replace('.', '/');
+                var baseDirectory = directory;
+                for (var up = 1; up < dots && baseDirectory.Length > 0; up++)
+                    baseDirectory = DirectoryOf(baseDirectory);
+                var module = dots > 0 ? string.Join('/', new[] { baseDirectory, relative }.Where(s => s.Length > 0)) : relative;
+                if (module.Length == 0 && dots == 0)
+                    break;
+                if (paths.Contains(module + ".py"))
+                    return new[] { module + ".py" };
+                if (paths.Contains(module + "/__init__.py"))
+                    return new[] { module + "/__init__.py" };
+                return byDirectory.GetValueOrDefault(module) ?? Enumerable.Empty<string>();
+            }
+
+            case ".go":
+            {
+                // A package is its directory; the longest workspace directory the import path ends with wins
+                var package = byDirectory.Keys
+                    .Where(d => d.Length > 0 && (import == d || import.EndsWith("/" + d, StringComparison.Ordinal)))
+                    .OrderByDescending(d => d.Length)
+                    .FirstOrDefault();
+                return package == null
+                    ? Enumerable.Empty<string>()
+                    : byDirectory[package].Where(p => p.EndsWith(".go", StringComparison.Ordinal) && !p.EndsWith("_test.go", StringComparison.Ordinal));
+            }
+
+            case ".cs" or ".java" or ".kt" or ".kts" or ".scala":
+                // using Acme.Orders; names a namespace, import com.acme.orders.Order a type within one
+                if (namespaces.TryGetValue(import, out var declaring))
+                    return declaring;
+                var dot = import.LastIndexOf('.');
+                if (dot > 0 && namespaces.TryGetValue(import[..dot], out declaring))
+                {
+                    var typeName = import[(dot + 1)..];
+                    var typeFiles = declaring.Where(p => Path.GetFileNameWithoutExtension(p) == typeName).ToList();
+                    return typeFiles.Count > 0 ? typeFiles : declaring;
+                }
+                break;
+
+            case ".rs":
+            {
+                var segments = import.Split("::", StringSplitOptions.RemoveEmptyEntries);
+                if (segments.Length < 2)
+                    break;
+                string? root = segments[0] switch
+                {
+                    "crate" => path.Contains("/src/") ? path[..(path.IndexOf("/src/", StringComparison.Ordinal) + 4)]
+                        : path.StartsWith("src/", StringComparison.Ordinal) ? "src" : null,
+                    "super" => directory,
+                    "self" => Path.GetFileNameWithoutExtension(path) is "mod" or "lib" or "main" ? directory : $"{directory}/{Path.GetFileNameWithoutExtension(path)}",
+                    _ => null
+                };
+                if (root == null)
+                    break;
+                var module = root.Length == 0 ? segments[1] : $"{root}/{segments[1]}";
+                if (paths.Contains(module + ".rs"))
+                    return new[] { module + ".rs" };
+                if (paths.Contains(module + "/mod.rs"))
+                    return new[] { module + "/mod.rs" };
+                return byDirectory.GetValueOrDefault(module) ?? Enumerable.Empty<string>();
+            }
+        }
+        return Enumerable.Empty<string>();
+    }
+
+    private static bool IsNamespaced(string path) =>
+        Path.GetExtension(path).ToLowerInvariant() is ".cs" or ".java" or ".kt" or ".kts" or ".scala";
+
+    private static string DirectoryOf(string path) => path.Contains('/') ? path[..path.LastIndexOf('/')] : string.Empty;
+
+    /// <summary>
+    /// Resolves . and .. segments; null when the path climbs out of the workspace
+    /// </summary>
+    private static string? Normalize(string path)
+    {
+        var parts = new List<string>();
+        foreach (var segment in path.Split('/'))
+        {
+            if (segment is "" or ".")
+                continue;
+            if (segment == "..")
+            {
+                if (parts.Count == 0)
+                    return null;
+                parts.RemoveAt(parts.Count - 1);
+                continue;
+            }
+            parts.Add(segment);
+        }
+        return string.Join('/', parts);
+    }
+}
@@ -0,0 +1,247 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// One rewritten line: the line as it was, what it becomes and the kind of reference rewritten on it
+/// </summary>
+public class ImportRewrite
+{
+    public int Line { get; set; }
+
+    /// <summary>
+    /// go-import, go-mod, using, project, script-import, python-import or jvm-import
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string OldText { get; set; } = string.Empty;
+    public string NewText { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Set when the rewrite changed more than the path, e.g. an alias added to keep a Go package name
+    /// </summary>
+    public string? Note { get; set; }
+}
+
+/// <summary>
+/// Rewrites the imports of one path or namespace prefix to another in a file: Go import specs (grouped and
+/// aliased ones included) and go.mod require and replace lines, C# using directives (static, global and alias
+/// forms), MSBuild <c>Using</c>, <c>ProjectReference</c> and <c>PackageReference</c> items, JavaScript and
+/// TypeScript import, export, require and dynamic import specifiers, Python import and from-import statements,
+/// and Java, Kotlin and Scala imports. A prefix matches whole path segments only: github.com/org/old rewrites
+/// github.com/org/old/pkg but not github.com/org/older.
+/// </summary>
+public static class ImportRewriter
+{
+    private static readonly Regex GoSpec = new(@"^(?<lead>\s*(?:import\s+)?)(?:(?<alias>[\w.]+)\s+)?""(?<path>[^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex GoModEntry = new(@"^(?<lead>\s*(?:require\s+|replace\s+)?)(?<path>[\w.~-]+(?:/[\w.~-]+)+)(?=\s)", RegexOptions.Compiled);
+    private static readonly Regex GoModReplaceTarget = new(@"(?<==>\s*)(?<path>[\w.~-]+(?:/[\w.~-]+)+)", RegexOptions.Compiled);
+    private static readonly Regex Using = new(@"^(?<lead>\s*(?:global\s+)?using\s+(?:static\s+)?(?:\w+\s*=\s*)?)(?<name>[\w.]+)(?=\s*;)", RegexOptions.Compiled);
+    private static readonly Regex ProjectItem = new(
+        @"<(?<item>Using|ProjectReference|PackageReference)\b[^>]*?\b(?:Include|Update)\s*=\s*""(?<value>[^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex ScriptSpecifier = new(
+        @"(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*|\bexport\s+\*\s+from\s*)(?<quote>['""`])(?<path>[^'""`]+)\k<quote>", RegexOptions.Compiled);
+    private static readonly Regex PythonImport = new(@"^(?<lead>\s*import\s+)(?<names>[\w.]+(?:\s+as\s+\w+)?(?:\s*,\s*[\w.]+(?:\s+as\s+\w+)?)*)", RegexOptions.Compiled);
+    private static readonly Regex PythonFrom = new(@"^(?<lead>\s*from\s+)(?<name>[\w.]+)(?=\s+import\b)", RegexOptions.Compiled);
+    private static readonly Regex JvmImport = new(@"^(?<lead>\s*import\s+(?:static\s+)?)(?<name>[\w.]+)", RegexOptions.Compiled);
+    private static readonly Regex GoIdentifier = new(@"^[A-Za-z_]\w*$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for the files <see cref="Rewrite"/> reads
+    /// </summary>
+    public static bool Handles(string filePath) => KindOf(filePath) != null;
+
+    /// <summary>
+    /// The file with every import of <paramref name="from"/>, or a path or namespace under it, rewritten to
+    /// <paramref name="to"/>, and the lines that changed. Unaliased Go imports whose package name would change
+    /// get the old name as an alias when <paramref name="keepGoPackageNames"/> is set, so code using it still builds.
+    /// </summary>
+    public static (string Content, List<ImportRewrite> Rewrites) Rewrite(string filePath, string content, string from, string to, bool keepGoPackageNames = true)
+    {
+        var rewrites = new List<ImportRewrite>();
+        var kind = KindOf(filePath);
+        if (kind == null || string.IsNullOrEmpty(from)
+            || !content.Contains(from, StringComparison.Ordinal) && !content.Contains(from.Replace('/', '\\'), StringComparison.Ordinal))
+            return (content, rewrites);
+
+        var newline = content.Contains("\r\n") ? "\r\n" : "\n";
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var inGoBlock = false;
+        var inGoModBlock = false;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            string? rewritten = null;
+            string? note = null;
+            var trimmed = line.Trim();
+
+            switch (kind)
+            {
+                case "go-import":
+                    if (trimmed.StartsWith("import (", StringComparison.Ordinal) || trimmed == "import(")
+                    {
+                        inGoBlock = true;
+                        continue;
+                    }
+                    if (inGoBlock && trimmed.StartsWith(')'))
+                    {
+                        inGoBlock = false;
+                        continue;
+                    }
+                    if ((inGoBlock || trimmed.StartsWith("import ", StringComparison.Ordinal)) && GoSpec.Match(line) is { Success: true } spec
+                        && Replace(spec.Groups["path"].Value, from, to, "/") is { } goPath)
+                    {
+                        var alias = spec.Groups["alias"].Success ? spec.Groups["alias"].Value : null;
+                        var oldName = GoPackageName(spec.Groups["path"].Value);
+                        var newName = GoPackageName(goPath);
+                        if (alias == null && keepGoPackageNames && oldName != newName && GoIdentifier.IsMatch(oldName))
+                        {
+                            alias = oldName;
+                            note = $"aliased as {oldName} so code using the package still builds";
+                        }
+                        rewritten = spec.Groups["lead"].Value + (alias != null ? alias + " " : string.Empty) + $"\"{goPath}\"" + line[(spec.Index + spec.Length)..];
+                    }
+                    break;
+
+                case "go-mod":
+                    if (trimmed.StartsWith("require (", StringComparison.Ordinal) || trimmed.StartsWith("replace (", StringComparison.Ordinal))
+                    {
+                        inGoModBlock = true;
+                        continue;
+                    }
+                    if (inGoModBlock && trimmed.StartsWith(')'))
+                    {
+                        inGoModBlock = false;
+                        continue;
+                    }
+                    if (inGoModBlock || trimmed.StartsWith("require ", StringComparison.Ordinal) || trimmed.StartsWith("replace ", StringComparison.Ordinal))
+                    {
+                        var updated = GoModEntry.Replace(line, m => Replace(m.Groups["path"].Value, from, to, "/") is { } p ? m.Groups["lead"].Value + p : m.Value, 1);
+                        updated = GoModReplaceTarget.Replace(updated, m => Replace(m.Groups["path"].Value, from, to, "/") ?? m.Value);
+                        if (updated != line)
+                            rewritten = updated;
+                    }
+                    break;
+
+                case "using":
+                    if (Using.Match(line) is { Success: true } directive && Replace(directive.Groups["name"].Value, from, to, ".") is { } ns)
+                        rewritten = directive.Groups["lead"].Value + ns + line[(directive.Index + directive.Length)..];
+                    break;
+
+                case "project":
+                    rewritten = ProjectItem.Replace(line, m =>
+                    {
+                        var value = m.Groups["value"];
+                        var replaced = m.Groups["item"].Value == "ProjectReference"
+                            ? ReplaceInPath(value.Value, from, to)
+                            : Replace(value.Value, from, to, ".");
+                        return replaced == null
+                            ? m.Value
+                            : m.Value[..(value.Index - m.Index)] + replaced + m.Value[(value.Index - m.Index + value.Length)..];
+                    });
+                    if (rewritten == line)
+                        rewritten = null;
+                    break;
+
+                case "script-import":
+                    rewritten = ScriptSpecifier.Replace(line, m =>
+                    {
+                        var path = m.Groups["path"];
+                        return Replace(path.Value, from.TrimEnd('/'), to.TrimEnd('/'), "/") is { } specifier
+                            ? m.Value[..(path.Index - m.Index)] + specifier + m.Value[(path.Index - m.Index + path.Length)..]
+                            : m.Value;
+                    });
+                    if (rewritten == line)
+                        rewritten = null;
+                    break;
+
+                case "python-import":
+                    if (PythonFrom.Match(line) is { Success: true } fromImport)
+                    {
+                        if (Replace(fromImport.Groups["name"].Value, from, to, ".") is { } module)
+                            rewritten = fromImport.Groups["lead"].Value + module + line[(fromImport.Index + fromImport.Length)..];
+                    }
+                    else if (PythonImport.Match(line) is { Success: true } import)
+                    {
+                        var names = Regex.Replace(import.Groups["names"].Value, @"[\w.]+(?=\s+as\b|\s*,|$)",
+                            m => Replace(m.Value, from, to, ".") ?? m.Value);
+                        if (names != import.Groups["names"].Value)
+                            rewritten = import.Groups["lead"].Value + names + line[(import.Index + import.Length)..];
+                    }
+                    break;
+
+                case "jvm-import":
+                    if (JvmImport.Match(line) is { Success: true } jvm && Replace(jvm.Groups["name"].Value, from, to, ".") is { } package)
+                        rewritten = jvm.Groups["lead"].Value + package + line[(jvm.Index + jvm.Length)..];
+                    break;
+            }
+
+            if (rewritten == null || rewritten == line)
+                continue;
+
+            rewrites.Add(new ImportRewrite { Line = i + 1, Kind = kind, OldText = line, NewText = rewritten, Note = note });
+            lines[i] = rewritten;
+        }
+
+        return rewrites.Count == 0 ? (content, rewrites) : (string.Join(newline, lines), rewrites);
+    }
+
+    /// <summary>
+    /// <paramref name="value"/> with the prefix <paramref name="from"/> replaced when it matches whole segments,
+    /// otherwise null
+    /// </summary>
+    public static string? Replace(string value, string from, string to, string separator)
+    {
+        if (value == from)
+            return to;
+        return value.StartsWith(from + separator, StringComparison.Ordinal) ? to + value[from.Length..] : null;
+    }
+
+    /// <summary>
+    /// A project path with the directory or file-name segments naming <paramref name="from"/> - alone or as the
+    /// prefix of a dotted name (Acme.Old.Tests) - renamed: ..\Acme.Old\Acme.Old.csproj → ..\Acme.New\Acme.New.csproj.
+    /// A <paramref name="from"/> written as a path (src/old) is matched across either separator.
+    /// </summary>
+    private static string? ReplaceInPath(string value, string from, string to)
+    {
+        var separator = value.Contains('\\') ? '\\' : '/';
+        var fromPath = from.Replace('/', separator).Replace('\\', separator);
+        var toPath = to.Replace('/', separator).Replace('\\', separator);
+        var pattern = new Regex($@"(?<=^|[\\/]){Regex.Escape(fromPath)}(?=$|[\\/.])");
+        var replaced = pattern.Replace(value, toPath);
+        return replaced == value ? null : replaced;
+    }
+
+    /// <summary>
+    /// The package name Go gives an unaliased import by convention: its last element, skipping a major version
+    /// suffix (gopkg.in/yaml.v3 → yaml, github.com/org/lib/v2 → lib) and anything after a dash
+    /// </summary>
+    private static string GoPackageName(string path)
+    {
+        var elements = path.Split('/');
+        var last = elements[^1];
+        if (Regex.IsMatch(last, @"^v\d+$") && elements.Length > 1)
+            last = elements[^2];
+        last = Regex.Replace(last, @"\.v\d+$", string.Empty);
+        var dash = last.LastIndexOf('-');
+        return dash >= 0 ? last[(dash + 1)..] : last;
+    }
+
+    private static string? KindOf(string filePath)
+    {
+        var name = Path.GetFileName(filePath);
+        if (name == "go.mod")
+            return "go-mod";
+        return Path.GetExtension(filePath).ToLowerInvariant() switch
+        {
+            ".go" => "go-import",
+            ".cs" => "using",
+            ".csproj" or ".fsproj" or ".vbproj" or ".props" or ".targets" => "project",
+            ".ts" or ".tsx" or ".js" or ".jsx" or ".mjs" or ".cjs" or ".mts" or ".cts" or ".vue" or ".svelte" => "script-import",
+            ".py" or ".pyi" => "python-import",
+            ".java" or ".kt" or ".kts" or ".scala" or ".groovy" => "jvm-import",
+            _ => null
+        };
+    }
+}
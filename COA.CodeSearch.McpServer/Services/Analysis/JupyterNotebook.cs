@@ -0,0 +1,198 @@
+using System.Globalization;
+using System.Text;
+using System.Text.Json;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A code cell's place in the text a notebook is indexed as: <see cref="Cell"/> is the 1-based position of the
+/// cell in the notebook (markdown cells count), <see cref="StartLine"/> the 1-based line its source starts on
+/// </summary>
+public record NotebookCellRange(int Cell, int StartLine, int LineCount);
+
+/// <summary>
+/// The code cells of a notebook as one text, a blank line between cells, with the range of each cell
+/// </summary>
+public class NotebookText
+{
+    public string Text { get; init; } = string.Empty;
+    public string? Language { get; init; }
+    public IReadOnlyList<NotebookCellRange> Cells { get; init; } = Array.Empty<NotebookCellRange>();
+}
+
+/// <summary>
+/// Jupyter notebooks (.ipynb) read as their code cells. Indexing the JSON would put search hits on lines of
+/// escaped strings with outputs and metadata around them; the cell text is indexed instead, and lines of it are
+/// mapped back to a cell number and a line within the cell.
+/// </summary>
+public static class JupyterNotebook
+{
+    /// <summary>
+    /// Stored index field holding the cell ranges of a notebook document
+    /// </summary>
+    public const string CellsField = "notebook_cells";
+
+    /// <summary>
+    /// True for Jupyter notebooks
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".ipynb", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// The code cells of a notebook (nbformat 4, or 3 with worksheets); null when the content is not notebook JSON
+    /// </summary>
+    public static NotebookText? Parse(string json)
+    {
+        JsonDocument document;
+        try
+        {
+            document = JsonDocument.Parse(json, new JsonDocumentOptions { AllowTrailingCommas = true });
+        }
+        catch (JsonException)
+        {
+            return null;
+        }
+
+        using (document)
+        {
+            var root = document.RootElement;
+            if (root.ValueKind != JsonValueKind.Object)
+                return null;
+
+            IEnumerable<JsonElement> cells;
+            if (root.TryGetProperty("cells", out var v4Cells) && v4Cells.ValueKind == JsonValueKind.Array)
+            {
+                cells = v4Cells.EnumerateArray();
+            }
+            else if (root.TryGetProperty("worksheets", out var worksheets) && worksheets.ValueKind == JsonValueKind.Array)
+            {
+                cells = worksheets.EnumerateArray()
+                    .Where(w => w.ValueKind == JsonValueKind.Object && w.TryGetProperty("cells", out var c) && c.ValueKind == JsonValueKind.Array)
+                    .SelectMany(w => w.GetProperty("cells").EnumerateArray());
+            }
+            else
+            {
+                return null;
+            }
+
+            var text = new StringBuilder();
+            var ranges = new List<NotebookCellRange>();
+            var line = 1;
+            var position = 0;
+
+            foreach (var cell in cells)
+            {
+                position++;
+                if (cell.ValueKind != JsonValueKind.Object
+                    || !cell.TryGetProperty("cell_type", out var type) || type.ValueKind != JsonValueKind.String
+                    || type.GetString() != "code")
+                {
+                    continue;
+                }
+
+                // nbformat 4 keeps the code in "source", nbformat 3 in "input"
+                var source = cell.TryGetProperty("source", out var s) ? s : cell.TryGetProperty("input", out var i) ? i : default;
+                var code = SourceText(source).Replace("\r\n", "\n").TrimEnd('\n');
+                if (code.Trim().Length == 0)
+                    continue;
+
+                if (ranges.Count > 0)
+                {
+                    text.Append("\n\n");
+                    line++;
+                }
+
+                var lineCount = code.Count(c => c == '\n') + 1;
+                ranges.Add(new NotebookCellRange(position, line, lineCount));
+                text.Append(code);
+                line += lineCount;
+            }
+
+            return new NotebookText
+            {
+                Text = text.ToString(),
+                Language = Language(root),
+                Cells = ranges
+            };
+        }
+    }
+
+    /// <summary>
+    /// The cell and 1-based line within it of a line of the notebook text; null for the blank lines between cells
+    /// </summary>
+    public static (int Cell, int Line)? Locate(IReadOnlyList<NotebookCellRange> cells, int line)
+    {
+        foreach (var range in cells)
+        {
+            if (line < range.StartLine)
+                break;
+            if (line < range.StartLine + range.LineCount)
+                return (range.Cell, line - range.StartLine + 1);
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Cell ranges as stored in the index: "cell:startLine:lineCount" separated by semicolons
+    /// </summary>
+    public static string FormatCells(IReadOnlyList<NotebookCellRange> cells) =>
+        string.Join(";", cells.Select(c => string.Create(CultureInfo.InvariantCulture, $"{c.Cell}:{c.StartLine}:{c.LineCount}")));
+
+    /// <summary>
+    /// Cell ranges read back from <see cref="FormatCells"/>; malformed entries are skipped
+    /// </summary>
+    public static List<NotebookCellRange> ParseCells(string? stored)
+    {
+        var cells = new List<NotebookCellRange>();
+        if (string.IsNullOrEmpty(stored))
+            return cells;
+
+        foreach (var entry in stored.Split(';', StringSplitOptions.RemoveEmptyEntries))
+        {
+            var parts = entry.Split(':');
+            if (parts.Length == 3
+                && int.TryParse(parts[0], NumberStyles.None, CultureInfo.InvariantCulture, out var cell)
+                && int.TryParse(parts[1], NumberStyles.None, CultureInfo.InvariantCulture, out var startLine)
+                && int.TryParse(parts[2], NumberStyles.None, CultureInfo.InvariantCulture, out var lineCount))
+            {
+                cells.Add(new NotebookCellRange(cell, startLine, lineCount));
+            }
+        }
+        return cells;
+    }
+
+    /// <summary>
+    /// Cell source, written either as one string or as a list of lines that keep their line endings
+    /// </summary>
+    private static string SourceText(JsonElement source) => source.ValueKind switch
+    {
+        JsonValueKind.String => source.GetString() ?? string.Empty,
+        JsonValueKind.Array => string.Concat(source.EnumerateArray()
+            .Where(l => l.ValueKind == JsonValueKind.String)
+            .Select(l => l.GetString())),
+        _ => string.Empty
+    };
+
+    /// <summary>
+    /// The kernel language from metadata.language_info or metadata.kernelspec
+    /// </summary>
+    private static string? Language(JsonElement root)
+    {
+        if (!root.TryGetProperty("metadata", out var metadata) || metadata.ValueKind != JsonValueKind.Object)
+            return null;
+
+        if (metadata.TryGetProperty("language_info", out var info) && info.ValueKind == JsonValueKind.Object
+            && info.TryGetProperty("name", out var name) && name.ValueKind == JsonValueKind.String)
+        {
+            return name.GetString();
+        }
+
+        if (metadata.TryGetProperty("kernelspec", out var kernel) && kernel.ValueKind == JsonValueKind.Object
+            && kernel.TryGetProperty("language", out var language) && language.ValueKind == JsonValueKind.String)
+        {
+            return language.GetString();
+        }
+
+        return null;
+    }
+}
@@ -0,0 +1,530 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Kotlin declarations read from source: classes (data, sealed, enum, value, annotation), interfaces, objects and
+/// companion objects, functions including extension and suspend functions, properties, primary-constructor
+/// properties, secondary constructors, enum entries and type aliases. Locals inside function bodies, lambdas and
+/// init blocks are left out. Fills in what julie-codesearch's Kotlin extraction misses.
+/// </summary>
+public static class KotlinSymbols
+{
+    private const string Prefix = @"^\s*(?:@[\w.:]+(?:\([^()]*\))?\s+)*";
+    private const string Modifiers = @"(?<modifiers>(?:(?:public|private|protected|internal|open|final|abstract|sealed|data|enum|annotation|inner|value|inline|suspend|override|operator|infix|tailrec|external|const|lateinit|expect|actual)\s+)*)";
+    private const string Name = @"(?<name>\w+|`[^`]+`)";
+    private const string Receiver = @"(?:(?<receiver>[\w.]+(?:<[^()=]*?>)?\??)\.)?";
+
+    private static readonly Regex Companion = new($@"{Prefix}{Modifiers}companion\s+object\b(?:\s+(?<name>\w+))?", RegexOptions.Compiled);
+    private static readonly Regex TypeDeclaration = new($@"{Prefix}{Modifiers}(?<keyword>class|interface|fun\s+interface|object)\s+{Name}", RegexOptions.Compiled);
+    private static readonly Regex Function = new($@"{Prefix}{Modifiers}fun\s+(?:<[^()]*?>\s*)?{Receiver}{Name}\s*\(", RegexOptions.Compiled);
+    private static readonly Regex Property = new($@"{Prefix}{Modifiers}(?<keyword>val|var)\s+(?:<[^()]*?>\s*)?{Receiver}(?<name>\w+|`[^`]+`)\b", RegexOptions.Compiled);
+    private static readonly Regex Constructor = new($@"{Prefix}{Modifiers}constructor\s*\(", RegexOptions.Compiled);
+    private static readonly Regex TypeAlias = new($@"{Prefix}{Modifiers}typealias\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex Init = new(@"^\s*init\s*\{", RegexOptions.Compiled);
+    private static readonly Regex ConstructorProperty = new(
+        $@"(?:^|[(,])\s*(?:@[\w.:]+(?:\([^()]*\))?\s+)*{Modifiers}(?<keyword>val|var)\s+(?<name>\w+|`[^`]+`)\s*:", RegexOptions.Compiled);
+    private static readonly Regex EnumEntry = new(@"^\s*(?:@[\w.:]+(?:\([^()]*\))?\s+)*(?<name>[\p{L}_]\w*)\s*(?:\(|\{|$)", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Kotlin sources and scripts
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".kt", StringComparison.OrdinalIgnoreCase)
+        || filePath.EndsWith(".kts", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a Kotlin file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".kt");
+        var found = new List<Found>();
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (string.IsNullOrWhiteSpace(masked[i]) || Read(lines, masked, i) is not { } declaration)
+                continue;
+            found.Add(declaration);
+
+            // A primary constructor's parameters declare properties, not members of their own
+            if (declaration.Keyword == "class")
+                i = Math.Max(i, HeaderEnd(masked, declaration));
+        }
+
+        // Declarations inside function bodies, property initializers and init blocks are locals
+        var kept = new List<Found>();
+        foreach (var declaration in found)
+        {
+            var container = Innermost(found, declaration);
+            if (container != null && container.Kind is not ("class" or "interface" or "enum" or "enum_member"))
+                continue;
+            declaration.Container = container;
+            kept.Add(declaration);
+        }
+
+        // Enum entries and primary-constructor properties come from the declarations holding them
+        foreach (var type in kept.ToList())
+        {
+            if (type.Kind == "enum")
+                kept.AddRange(EnumEntries(lines, masked, type, found));
+            if (type.Keyword == "class")
+                kept.AddRange(ConstructorProperties(lines, masked, type));
+        }
+        foreach (var constructor in kept.Where(d => d.Kind == "constructor" && d.Container != null))
+        {
+            constructor.Name = constructor.Container!.Name;
+        }
+
+        // Functions in an enum entry's body belong to the entry
+        foreach (var declaration in kept.Where(d => d.Container?.Kind == "enum"))
+        {
+            declaration.Container = kept.FirstOrDefault(e => e.Kind == "enum_member" && e.Container == declaration.Container
+                && e.Line < declaration.Line && e.EndLine >= declaration.Line) ?? declaration.Container;
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in kept.Where(d => d.Kind != "init").OrderBy(d => d.Line).ThenBy(d => d.Column))
+        {
+            var kind = declaration.Kind switch
+            {
+                "function" when declaration.Container != null => "method",
+                "variable" when declaration.Container != null => "property",
+                _ => declaration.Kind
+            };
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = kind,
+                Language = "kotlin",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = declaration.EndLine,
+                EndColumn = lines[declaration.EndLine - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = declaration.Visibility
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Kotlin file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The receiver type of an extension function or property signature (String for <c>fun String.toSlug()</c>),
+    /// without type arguments or nullability
+    /// </summary>
+    public static string? ReceiverOf(string? signature)
+    {
+        if (string.IsNullOrEmpty(signature))
+            return null;
+
+        var match = Function.Match(signature);
+        if (!match.Success)
+            match = Property.Match(signature);
+        if (!match.Success || !match.Groups["receiver"].Success)
+            return null;
+
+        var receiver = match.Groups["receiver"].Value.TrimEnd('?');
+        var arguments = receiver.IndexOf('<');
+        return arguments < 0 ? receiver : receiver[..arguments];
+    }
+
+    /// <summary>
+    /// Whether a symbol is a companion object, whose members are called through the enclosing class
+    /// </summary>
+    public static bool IsCompanion(JulieSymbol symbol) =>
+        symbol.Language == "kotlin" && symbol.Signature != null && Companion.IsMatch(symbol.Signature);
+
+    private static Found? Read(string[] lines, string[] masked, int index)
+    {
+        var line = masked[index];
+        Match match;
+        string kind;
+
+        if ((match = Companion.Match(line)).Success)
+        {
+            kind = "class";
+        }
+        else if ((match = TypeDeclaration.Match(line)).Success)
+        {
+            kind = match.Groups["keyword"].Value switch
+            {
+                "class" when Regex.IsMatch(match.Groups["modifiers"].Value, @"\benum\b") => "enum",
+                "class" or "object" => "class",
+                _ => "interface"
+            };
+        }
+        else if ((match = Function.Match(line)).Success)
+        {
+            kind = "function";
+        }
+        else if ((match = Constructor.Match(line)).Success)
+        {
+            kind = "constructor";
+        }
+        else if ((match = Property.Match(line)).Success)
+        {
+            kind = Regex.IsMatch(match.Groups["modifiers"].Value, @"\bconst\b") ? "constant" : "variable";
+        }
+        else if ((match = TypeAlias.Match(line)).Success)
+        {
+            kind = "type";
+        }
+        else if ((match = Init.Match(line)).Success)
+        {
+            kind = "init";
+        }
+        else
+        {
+            return null;
+        }
+
+        var name = match.Groups["name"];
+        var modifiers = match.Groups["modifiers"].Value;
+        var end = kind is "variable" or "constant" or "type" ? ExpressionEnd(masked, index) : EndOf(masked, index);
+        return new Found
+        {
+            Kind = kind,
+            Keyword = match.Groups["keyword"].Success ? match.Groups["keyword"].Value : string.Empty,
+            Name = name.Success ? lines[index].Substring(name.Index, name.Length).Trim('`') : kind == "class" ? "Companion" : kind,
+            Line = index + 1,
+            Column = name.Success ? name.Index : match.Index + line.Length - line.TrimStart().Length,
+            EndLine = end + 1,
+            Signature = Signature(lines[index]),
+            Visibility = Regex.Match(modifiers, @"\b(?<visibility>private|protected|internal)\b") is { Success: true } visibility
+                ? visibility.Groups["visibility"].Value
+                : "public"
+        };
+    }
+
+    /// <summary>
+    /// The entries of an enum class: the comma-separated names at the top of its body, up to the first semicolon
+    /// </summary>
+    private static IEnumerable<Found> EnumEntries(string[] lines, string[] masked, Found type, List<Found> found)
+    {
+        var line = type.Line - 1;
+        var open = masked[line].IndexOf('{', type.Column);
+        while (open < 0 && ++line < type.EndLine)
+            open = masked[line].IndexOf('{');
+        if (open < 0)
+            yield break;
+
+        var depth = 0;
+        var pending = true;
+        for (var i = line; i < type.EndLine; i++)
+        {
+            if (depth == 0 && i > line && found.Any(d => d.Line == i + 1))
+                yield break;
+
+            var segmentStart = i == line ? open + 1 : 0;
+            for (var c = segmentStart; c <= masked[i].Length; c++)
+            {
+                var ch = c < masked[i].Length ? masked[i][c] : '\n';
+                if (depth == 0 && pending && ch is '(' or '{' or ',' or ';' or '}' or '\n')
+                {
+                    var entry = EnumEntry.Match(masked[i][segmentStart..c]);
+                    if (entry.Success && masked[i][segmentStart..c].Trim().Length > 0)
+                    {
+                        var column = segmentStart + entry.Groups["name"].Index;
+                        pending = false;
+                        yield return new Found
+                        {
+                            Kind = "enum_member",
+                            Name = entry.Groups["name"].Value,
+                            Line = i + 1,
+                            Column = column,
+                            EndLine = EntryEnd(masked, i, column, type.EndLine) + 1,
+                            Signature = lines[i].Trim().TrimEnd(',', ';'),
+                            Container = type
+                        };
+                    }
+                }
+
+                switch (ch)
+                {
+                    case '(' or '{' or '[':
+                        depth++;
+                        break;
+                    case ')' or ']':
+                        depth--;
+                        break;
+                    case '}' when depth == 0:
+                        yield break;
+                    case '}':
+                        depth--;
+                        break;
+                    case ';' when depth == 0:
+                        yield break;
+                    case ',' when depth == 0:
+                        pending = true;
+                        segmentStart = c + 1;
+                        break;
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// The last line (0-based) of an enum entry: up to its argument list and body, before the separating comma
+    /// </summary>
+    private static int EntryEnd(string[] masked, int index, int column, int bodyEnd)
+    {
+        var depth = 0;
+        var last = index;
+        for (var i = index; i < bodyEnd; i++)
+        {
+            for (var c = i == index ? column : 0; c < masked[i].Length; c++)
+            {
+                var ch = masked[i][c];
+                if (depth == 0 && ch is ',' or ';' or '}')
+                    return last;
+                if (ch is '(' or '{' or '[')
+                    depth++;
+                else if (ch is ')' or '}' or ']')
+                    depth--;
+                if (!char.IsWhiteSpace(ch))
+                    last = i;
+            }
+        }
+        return last;
+    }
+
+    /// <summary>
+    /// The line (0-based) closing a class header's primary constructor parameter list, or the declaration's
+    /// own line when it has none
+    /// </summary>
+    private static int HeaderEnd(string[] masked, Found type)
+    {
+        var index = type.Line - 1;
+        var open = masked[index].IndexOf('(', type.Column);
+        var brace = masked[index].IndexOf('{', type.Column);
+        if (open < 0 || brace >= 0 && brace < open)
+            return index;
+
+        var depth = 0;
+        for (var i = index; i < masked.Length; i++)
+        {
+            for (var c = i == index ? open : 0; c < masked[i].Length; c++)
+            {
+                if (masked[i][c] == '(')
+                    depth++;
+                else if (masked[i][c] == ')' && --depth == 0)
+                    return i;
+            }
+        }
+        return index;
+    }
+
+    /// <summary>
+    /// val and var parameters of a class's primary constructor, which declare properties
+    /// </summary>
+    private static IEnumerable<Found> ConstructorProperties(string[] lines, string[] masked, Found type)
+    {
+        var header = masked[type.Line - 1];
+        var open = header.IndexOf('(', type.Column);
+        var brace = header.IndexOf('{', type.Column);
+        if (open < 0 || brace >= 0 && brace < open)
+            yield break;
+
+        var depth = 0;
+        for (var i = type.Line - 1; i < type.EndLine; i++)
+        {
+            var start = i == type.Line - 1 ? open : 0;
+            var text = masked[i];
+            var close = text.Length;
+            for (var c = start; c < text.Length; c++)
+            {
+                if (text[c] == '(')
+                    depth++;
+                else if (text[c] == ')' && --depth == 0)
+                {
+                    close = c;
+                    break;
+                }
+            }
+
+            var parameters = ConstructorProperty.Matches(text[..close], start);
+            for (var p = 0; p < parameters.Count; p++)
+            {
+                var parameter = parameters[p];
+                var name = parameter.Groups["name"];
+                var signatureEnd = p + 1 < parameters.Count ? parameters[p + 1].Index : close;
+                yield return new Found
+                {
+                    Kind = "property",
+                    Name = lines[i].Substring(name.Index, name.Length).Trim('`'),
+                    Line = i + 1,
+                    Column = name.Index,
+                    EndLine = i + 1,
+                    Signature = lines[i][parameter.Index..signatureEnd].Trim().TrimStart('(', ',').Trim().TrimEnd(','),
+                    Visibility = Regex.Match(parameter.Groups["modifiers"].Value, @"\b(?<visibility>private|protected|internal)\b") is { Success: true } visibility
+                        ? visibility.Groups["visibility"].Value
+                        : "public",
+                    Container = type
+                };
+            }
+            if (close < text.Length)
+                yield break;
+        }
+    }
+
+    /// <summary>
+    /// The innermost other declaration whose extent holds <paramref name="declaration"/>
+    /// </summary>
+    private static Found? Innermost(List<Found> found, Found declaration) =>
+        found.Where(d => d != declaration && d.Line <= declaration.Line && d.EndLine >= declaration.Line
+                && (d.Line < declaration.Line || d.Column < declaration.Column))
+            .OrderByDescending(d => d.Line)
+            .ThenByDescending(d => d.Column)
+            .FirstOrDefault();
+
+    /// <summary>
+    /// The line (0-based) closing a declaration: its matching brace, the end of a parenthesized header with no
+    /// body, or the end of an expression body
+    /// </summary>
+    private static int EndOf(string[] masked, int index, int column = 0)
+    {
+        var braces = 0;
+        var parens = 0;
+        var opened = false;
+        for (var i = index; i < masked.Length; i++)
+        {
+            var text = masked[i];
+            for (var c = i == index ? column : 0; c < text.Length; c++)
+            {
+                switch (text[c])
+                {
+                    case '{':
+                        braces++;
+                        opened = true;
+                        break;
+                    case '}':
+                        braces--;
+                        break;
+                    case '(':
+                        parens++;
+                        break;
+                    case ')':
+                        parens--;
+                        break;
+                }
+                if (opened && braces == 0)
+                    return i;
+            }
+            if (opened || parens > 0)
+                continue;
+
+            // A supertype list or expression body on the following line still belongs to the header
+            var trimmed = text.TrimEnd();
+            var next = NextCode(masked, i);
+            if (trimmed.EndsWith('=') || trimmed.EndsWith(':')
+                || next != null && (next.StartsWith(':') || next.StartsWith('{') || next.StartsWith("where ", StringComparison.Ordinal) || next.StartsWith('=')))
+                continue;
+            return i;
+        }
+        return masked.Length - 1;
+    }
+
+    /// <summary>
+    /// The last line (0-based) of a property or alias: its initializer's brackets closed and no continuation
+    /// operator left dangling, or the end of its getter and setter
+    /// </summary>
+    private static int ExpressionEnd(string[] masked, int index)
+    {
+        var end = EndOf(masked, index);
+        for (var next = end + 1; next < masked.Length; next++)
+        {
+            var code = masked[next].Trim();
+            if (code.Length == 0)
+                continue;
+            if (!Regex.IsMatch(code, @"^(?:(?:private|protected|internal)\s+)?(?:get|set)\b") && !code.StartsWith('.') && !code.StartsWith("?.", StringComparison.Ordinal))
+                break;
+            end = EndOf(masked, next);
+            next = end;
+        }
+        return end;
+    }
+
+    private static string? NextCode(string[] masked, int index)
+    {
+        for (var i = index + 1; i < masked.Length; i++)
+        {
+            var code = masked[i].Trim();
+            if (code.Length > 0)
+                return code;
+        }
+        return null;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = line.Trim();
+        if (signature.EndsWith('{'))
+            signature = signature[..^1].TrimEnd();
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    /// <summary>
+    /// The KDoc block right above a declaration, skipping annotation lines
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var i = index - 1;
+        while (i >= 0 && lines[i].TrimStart().StartsWith('@'))
+            i--;
+        if (i < 0 || !lines[i].TrimEnd().EndsWith("*/", StringComparison.Ordinal))
+            return null;
+
+        var end = i;
+        while (i >= 0 && !lines[i].TrimStart().StartsWith("/**", StringComparison.Ordinal))
+        {
+            if (lines[i].TrimStart().StartsWith("/*", StringComparison.Ordinal))
+                return null;
+            i--;
+        }
+        return i < 0 ? null : string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"kotlin:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+
+        /// <summary>
+        /// class, interface, object or val/var as written; empty for other declarations
+        /// </summary>
+        public string Keyword { get; init; } = string.Empty;
+
+        public string Name { get; set; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; init; }
+        public string Signature { get; init; } = string.Empty;
+        public string Visibility { get; init; } = "public";
+        public Found? Container { get; set; }
+    }
+}
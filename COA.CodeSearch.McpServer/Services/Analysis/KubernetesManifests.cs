@@ -0,0 +1,856 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// One Kubernetes object from a manifest or a rendered Helm template
+/// </summary>
+public class K8sResource
+{
+    public string Kind { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string? Namespace { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The chart directory when the object comes from a Helm template
+    /// </summary>
+    public string? Chart { get; set; }
+
+    /// <summary>
+    /// Containers and init containers of the pod template, for workloads
+    /// </summary>
+    public List<K8sContainer> Containers { get; set; } = new();
+
+    /// <summary>
+    /// data, stringData and binaryData keys, for ConfigMaps and Secrets
+    /// </summary>
+    public List<string> Keys { get; set; } = new();
+
+    /// <summary>
+    /// ConfigMaps and Secrets the pod template reads through env, envFrom and volumes
+    /// </summary>
+    public List<K8sConfigReference> ConfigReferences { get; set; } = new();
+}
+
+/// <summary>
+/// A container of a pod template
+/// </summary>
+public class K8sContainer
+{
+    public string Name { get; set; } = string.Empty;
+    public string Image { get; set; } = string.Empty;
+    public bool Init { get; set; }
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Workspace directory whose Dockerfile or project builds the image; set by the caller that knows the workspace
+    /// </summary>
+    public string? Source { get; set; }
+
+    public List<K8sEnvVar> Env { get; set; } = new();
+}
+
+/// <summary>
+/// An environment variable a container gets
+/// </summary>
+public class K8sEnvVar
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The literal value, when the manifest sets one
+    /// </summary>
+    public string? Value { get; set; }
+
+    /// <summary>
+    /// value, configMap, secret, field or resource
+    /// </summary>
+    public string Source { get; set; } = "value";
+
+    /// <summary>
+    /// ConfigMap or Secret name, field path or resource name the value comes from
+    /// </summary>
+    public string? SourceName { get; set; }
+
+    public string? Key { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// read (the container's source reads it, or any code when the source is unknown), read-elsewhere (only
+    /// code outside the container's source reads it) or unread; set by <see cref="KubernetesManifests.Link"/>
+    /// </summary>
+    public string? Status { get; set; }
+
+    /// <summary>
+    /// Code reading it from the environment, as file:line
+    /// </summary>
+    public List<string> CodeReads { get; set; } = new();
+}
+
+/// <summary>
+/// A ConfigMap or Secret a pod template uses
+/// </summary>
+public class K8sConfigReference
+{
+    /// <summary>
+    /// configMap or secret
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The key a configMapKeyRef or secretKeyRef reads; null when the whole object is used
+    /// </summary>
+    public string? Key { get; set; }
+
+    /// <summary>
+    /// env, envFrom or volume
+    /// </summary>
+    public string Usage { get; set; } = string.Empty;
+
+    /// <summary>
+    /// envFrom prefix added to every key
+    /// </summary>
+    public string? Prefix { get; set; }
+
+    /// <summary>
+    /// Kind/name of the workload
+    /// </summary>
+    public string Resource { get; set; } = string.Empty;
+
+    public string? Container { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// defined, missing-key (the object is in the workspace without the key), external (no object of that
+    /// name in the workspace) or unresolved (the name is a template expression); set by <see cref="KubernetesManifests.Link"/>
+    /// </summary>
+    public string? Status { get; set; }
+}
+
+/// <summary>
+/// A Helm chart: Chart.yaml metadata, values.yaml and the values its templates read
+/// </summary>
+public class HelmChart
+{
+    public string Directory { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string? Version { get; set; }
+    public string? AppVersion { get; set; }
+
+    /// <summary>
+    /// values.yaml by dotted path; maps and lists have a null value
+    /// </summary>
+    public Dictionary<string, string?> Values { get; set; } = new(StringComparer.Ordinal);
+
+    /// <summary>
+    /// .Values reads in the templates, filled in as they are parsed
+    /// </summary>
+    public List<HelmValueReference> References { get; set; } = new();
+
+    // values.yaml text under each map and list, dedented, for toYaml
+    internal Dictionary<string, List<string>> Blocks { get; } = new(StringComparer.Ordinal);
+}
+
+/// <summary>
+/// A .Values read in a Helm template
+/// </summary>
+public class HelmValueReference
+{
+    /// <summary>
+    /// Dotted path below .Values: image.tag
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Whether values.yaml sets the path
+    /// </summary>
+    public bool Defined { get; set; }
+
+    public string? Value { get; set; }
+}
+
+/// <summary>
+/// Reads Kubernetes manifests and Helm charts into objects, containers, environment variables and the ConfigMaps
+/// and Secrets they use. Helm templates are rendered just far enough to parse: .Values, .Chart and .Release reads
+/// with default, quote and case pipes are substituted from values.yaml, toYaml blocks are expanded, with blocks
+/// scope '.', and other actions are kept as text or dropped. YAML is read by indentation as the CI pipeline reader does.
+/// </summary>
+public static class KubernetesManifests
+{
+    private static readonly Regex YamlKey = new(
+        @"^(?:""(?<key>[^""]*)""|'(?<key>[^']*)'|(?<key>[^\s#'""\-{][^#]*?|-[^\s#][^#]*?))\s*:(?:\s+(?<value>.*))?$", RegexOptions.Compiled);
+    private static readonly Regex ApiVersion = new(@"^apiVersion:\s*\S", RegexOptions.Compiled | RegexOptions.Multiline);
+    private static readonly Regex Kind = new(@"^kind:\s*\S", RegexOptions.Compiled | RegexOptions.Multiline);
+    private static readonly Regex Action = new(@"\{\{-?\s*(?<body>.*?)\s*-?\}\}", RegexOptions.Compiled);
+    private static readonly Regex ValuesRead = new(@"\$?\.Values\.(?<path>[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*)", RegexOptions.Compiled);
+    private static readonly Regex ContextRead = new(@"(?<![\w$\)\].])\.(?<path>[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*)", RegexOptions.Compiled);
+    private static readonly Regex QuotedText = new(@"""(?:[^""\\]|\\.)*""|`[^`]*`", RegexOptions.Compiled);
+    private static readonly Regex ToYaml = new(@"^toYaml\s+(?<target>\S+)\s*\|\s*n?indent\s+(?<indent>\d+)$", RegexOptions.Compiled);
+    private static readonly HashSet<string> BuiltIns = new(StringComparer.Ordinal) { "Values", "Release", "Chart", "Capabilities", "Template", "Files" };
+    private static readonly HashSet<string> NoOpPipes = new(StringComparer.Ordinal) { "quote", "squote", "toString", "trim" };
+
+    private sealed class Node
+    {
+        public Dictionary<string, Node>? Map;
+        public List<Node>? Items;
+        public string? Value;
+        public int Line;
+
+        public Node? this[string key] => Map != null && Map.TryGetValue(key, out var child) ? child : null;
+        public string? Text(string key) => this[key]?.Value;
+    }
+
+    /// <summary>
+    /// A YAML file with top-level apiVersion and kind
+    /// </summary>
+    public static bool IsManifest(string filePath, string content)
+    {
+        var name = Path.GetFileName(filePath);
+        return (name.EndsWith(".yml", StringComparison.OrdinalIgnoreCase) || name.EndsWith(".yaml", StringComparison.OrdinalIgnoreCase))
+               && ApiVersion.IsMatch(content) && Kind.IsMatch(content);
+    }
+
+    /// <summary>
+    /// Whether a workspace-relative path is a YAML template of one of the charts
+    /// </summary>
+    public static HelmChart? ChartOf(string filePath, IEnumerable<HelmChart> charts)
+    {
+        var path = filePath.Replace('\\', '/');
+        if (!path.EndsWith(".yaml", StringComparison.OrdinalIgnoreCase) && !path.EndsWith(".yml", StringComparison.OrdinalIgnoreCase))
+            return null;
+        return charts
+            .Where(c => path.StartsWith(c.Directory.Length == 0 ? "templates/" : c.Directory + "/templates/", StringComparison.Ordinal))
+            .OrderByDescending(c => c.Directory.Length)
+            .FirstOrDefault();
+    }
+
+    /// <summary>
+    /// The chart in <paramref name="chartDirectory"/>, from its Chart.yaml and values.yaml
+    /// </summary>
+    public static HelmChart ReadChart(string chartDirectory, string chartYaml, string? valuesYaml)
+    {
+        var metadata = ReadYaml(Numbered(chartYaml));
+        var chart = new HelmChart
+        {
+            Directory = chartDirectory.Replace('\\', '/').TrimEnd('/'),
+            Name = metadata.Text("name") ?? Path.GetFileName(chartDirectory.TrimEnd('/', '\\')),
+            Version = metadata.Text("version"),
+            AppVersion = metadata.Text("appVersion")
+        };
+        if (valuesYaml == null)
+            return chart;
+
+        var lines = valuesYaml.Replace("\r\n", "\n").Split('\n');
+        void Flatten(Node node, string path)
+        {
+            if (node.Map == null)
+                return;
+            foreach (var (key, child) in node.Map)
+            {
+                var childPath = path.Length == 0 ? key : path + "." + key;
+                chart.Values[childPath] = child.Map == null && child.Items == null ? child.Value : null;
+                if (child.Map != null || child.Items != null)
+                    chart.Blocks[childPath] = BlockBelow(lines, child.Line);
+                Flatten(child, childPath);
+            }
+        }
+        Flatten(ReadYaml(Numbered(valuesYaml)), string.Empty);
+        return chart;
+    }
+
+    /// <summary>
+    /// The objects in a manifest, or in a Helm template when <paramref name="chart"/> is given - in which case the
+    /// template's .Values reads are added to the chart. <paramref name="filePath"/> is workspace-relative.
+    /// </summary>
+    public static List<K8sResource> Parse(string filePath, string content, HelmChart? chart = null)
+    {
+        filePath = filePath.Replace('\\', '/');
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var rendered = chart == null ? lines.Select((text, i) => (text, i + 1)).ToList() : Render(filePath, lines, chart);
+
+        var resources = new List<K8sResource>();
+        var document = new List<(string Text, int Line)>();
+        foreach (var line in rendered.Append(("---", 0)))
+        {
+            if (line.Item1.TrimEnd() != "---")
+            {
+                document.Add(line);
+                continue;
+            }
+            ReadObject(ReadYaml(document), filePath, chart, resources);
+            document.Clear();
+        }
+        return resources;
+    }
+
+    /// <summary>
+    /// Resolves ConfigMap and Secret references against the objects the workspace defines, expands envFrom into
+    /// the variables it supplies, and joins every container variable with the code reading it
+    /// (<paramref name="codeReads"/>: variable name to file:line).
+    /// </summary>
+    public static void Link(IReadOnlyCollection<K8sResource> resources, IReadOnlyDictionary<string, List<string>> codeReads)
+    {
+        var defined = resources
+            .Where(r => r.Kind is "ConfigMap" or "Secret")
+            .GroupBy(r => (Kind: r.Kind == "ConfigMap" ? "configMap" : "secret", r.Name))
+            .ToDictionary(g => g.Key, g => g.SelectMany(r => r.Keys).ToHashSet(StringComparer.Ordinal));
+
+        foreach (var resource in resources)
+        {
+            foreach (var reference in resource.ConfigReferences)
+            {
+                var keys = defined.GetValueOrDefault((reference.Kind, reference.Name));
+                reference.Status = reference.Name.Contains("{{", StringComparison.Ordinal) ? "unresolved"
+                    : keys == null ? "external"
+                    : reference.Key != null && !keys.Contains(reference.Key) ? "missing-key"
+                    : "defined";
+
+                if (reference.Usage != "envFrom" || reference.Status != "defined")
+                    continue;
+                var container = resource.Containers.FirstOrDefault(c => c.Name == reference.Container);
+                foreach (var key in keys!.OrderBy(k => k, StringComparer.Ordinal))
+                {
+                    var name = (reference.Prefix ?? string.Empty) + key;
+                    if (container != null && !container.Env.Any(e => e.Name == name))
+                    {
+                        container.Env.Add(new K8sEnvVar
+                        {
+                            Name = name, Source = reference.Kind, SourceName = reference.Name, Key = key,
+                            FilePath = reference.FilePath, Line = reference.Line
+                        });
+                    }
+                }
+            }
+
+            foreach (var container in resource.Containers)
+            {
+                foreach (var variable in container.Env)
+                {
+                    var reads = codeReads.TryGetValue(variable.Name, out var found) ? found : new List<string>();
+                    var own = container.Source == null
+                        ? reads
+                        : reads.Where(r => container.Source.Length == 0 || r.StartsWith(container.Source + "/", StringComparison.Ordinal)).ToList();
+                    variable.Status = own.Count > 0 ? "read" : reads.Count > 0 ? "read-elsewhere" : "unread";
+                    variable.CodeReads = own.Count > 0 ? own : reads;
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// The workspace directory that builds <paramref name="image"/>: a directory with a Dockerfile, or a project
+    /// (.csproj, package.json, go.mod, Cargo.toml, pyproject.toml, pom.xml), whose name matches the image's
+    /// repository ignoring case and punctuation. Dockerfiles win over projects; null when nothing matches.
+    /// </summary>
+    public static string? FindImageSource(string image, IEnumerable<string> files)
+    {
+        if (image.Contains("{{", StringComparison.Ordinal) || image.Length == 0)
+            return null;
+        var repository = image.Split('@')[0];
+        repository = repository[(repository.LastIndexOf('/') + 1)..];
+        if (repository.Contains(':'))
+            repository = repository[..repository.IndexOf(':')];
+        var wanted = Normalize(repository);
+        if (wanted.Length == 0)
+            return null;
+
+        string? project = null;
+        foreach (var file in files.Select(f => f.Replace('\\', '/')).OrderBy(f => f.Count(c => c == '/')).ThenBy(f => f, StringComparer.Ordinal))
+        {
+            var name = Path.GetFileName(file);
+            var directory = Path.GetDirectoryName(file)?.Replace('\\', '/') ?? string.Empty;
+            var directoryName = Path.GetFileName(directory);
+            if (name == "Dockerfile" || name.StartsWith("Dockerfile.", StringComparison.Ordinal) || name.EndsWith(".Dockerfile", StringComparison.OrdinalIgnoreCase))
+            {
+                var variant = name == "Dockerfile" ? string.Empty : name.Replace("Dockerfile", string.Empty).Trim('.');
+                if (Normalize(directoryName) == wanted || variant.Length > 0 && (Normalize(variant) == wanted || Normalize(directoryName + variant) == wanted))
+                    return directory;
+            }
+            else if (project == null)
+            {
+                var key = Path.GetExtension(name) is ".csproj" or ".fsproj" or ".vbproj"
+                    ? Path.GetFileNameWithoutExtension(name)
+                    : name is "package.json" or "go.mod" or "Cargo.toml" or "pyproject.toml" or "pom.xml" ? directoryName : null;
+                if (key != null && Normalize(key) == wanted)
+                    project = directory;
+            }
+        }
+        return project;
+    }
+
+    private static string Normalize(string name) => new(name.Where(char.IsLetterOrDigit).Select(char.ToLowerInvariant).ToArray());
+
+    private static void ReadObject(Node root, string filePath, HelmChart? chart, List<K8sResource> resources)
+    {
+        if (root.Text("kind") is not { Length: > 0 } kind)
+            return;
+        if (kind == "List" && root["items"]?.Items is { } items)
+        {
+            foreach (var item in items)
+                ReadObject(item, filePath, chart, resources);
+            return;
+        }
+
+        var resource = new K8sResource
+        {
+            Kind = kind,
+            Name = root["metadata"]?.Text("name") ?? string.Empty,
+            Namespace = root["metadata"]?.Text("namespace"),
+            FilePath = filePath,
+            Line = root["kind"]!.Line,
+            Chart = chart?.Directory
+        };
+        resources.Add(resource);
+
+        if (kind is "ConfigMap" or "Secret")
+        {
+            foreach (var section in new[] { "data", "stringData", "binaryData" })
+            {
+                if (root[section]?.Map is { } data)
+                    resource.Keys.AddRange(data.Keys.Where(k => !resource.Keys.Contains(k)));
+            }
+            return;
+        }
+
+        var spec = root["spec"];
+        var pod = spec?["template"]?["spec"] ?? spec?["jobTemplate"]?["spec"]?["template"]?["spec"] ?? (spec?["containers"] != null ? spec : null);
+        if (pod == null)
+            return;
+
+        var label = $"{kind}/{resource.Name}";
+        foreach (var (section, init) in new[] { ("initContainers", true), ("containers", false) })
+        {
+            foreach (var item in pod[section]?.Items ?? new List<Node>())
+            {
+                var container = new K8sContainer
+                {
+                    Name = item.Text("name") ?? string.Empty,
+                    Image = item.Text("image") ?? string.Empty,
+                    Init = init,
+                    Line = item.Line
+                };
+                resource.Containers.Add(container);
+                ReadEnv(item, container, resource, label, filePath);
+            }
+        }
+
+        foreach (var volume in pod["volumes"]?.Items ?? new List<Node>())
+        {
+            var sources = new List<Node> { volume };
+            sources.AddRange(volume["projected"]?["sources"]?.Items ?? new List<Node>());
+            foreach (var source in sources)
+            {
+                if (source["configMap"]?.Text("name") is { Length: > 0 } configMap)
+                    resource.ConfigReferences.Add(new K8sConfigReference { Kind = "configMap", Name = configMap, Usage = "volume", Resource = label, FilePath = filePath, Line = source["configMap"]!.Line });
+                if ((source["secret"]?.Text("secretName") ?? source["secret"]?.Text("name")) is { Length: > 0 } secret)
+                    resource.ConfigReferences.Add(new K8sConfigReference { Kind = "secret", Name = secret, Usage = "volume", Resource = label, FilePath = filePath, Line = source["secret"]!.Line });
+            }
+        }
+    }
+
+    private static void ReadEnv(Node item, K8sContainer container, K8sResource resource, string label, string filePath)
+    {
+        foreach (var entry in item["env"]?.Items ?? new List<Node>())
+        {
+            if (entry.Text("name") is not { Length: > 0 } name)
+                continue;
+            var variable = new K8sEnvVar { Name = name, Value = entry.Text("value"), FilePath = filePath, Line = entry.Line };
+            var from = entry["valueFrom"];
+            foreach (var (refKey, kind) in new[] { ("configMapKeyRef", "configMap"), ("secretKeyRef", "secret") })
+            {
+                if (from?[refKey] is not { } keyRef)
+                    continue;
+                variable.Source = kind;
+                variable.SourceName = keyRef.Text("name");
+                variable.Key = keyRef.Text("key");
+                if (variable.SourceName is { Length: > 0 })
+                {
+                    resource.ConfigReferences.Add(new K8sConfigReference
+                    {
+                        Kind = kind, Name = variable.SourceName, Key = variable.Key, Usage = "env", Resource = label,
+                        Container = container.Name, FilePath = filePath, Line = keyRef.Line
+                    });
+                }
+            }
+            if (from?["fieldRef"] is { } fieldRef)
+            {
+                variable.Source = "field";
+                variable.SourceName = fieldRef.Text("fieldPath");
+            }
+            else if (from?["resourceFieldRef"] is { } resourceRef)
+            {
+                variable.Source = "resource";
+                variable.SourceName = resourceRef.Text("resource");
+            }
+            container.Env.Add(variable);
+        }
+
+        foreach (var entry in item["envFrom"]?.Items ?? new List<Node>())
+        {
+            foreach (var (refKey, kind) in new[] { ("configMapRef", "configMap"), ("secretRef", "secret") })
+            {
+                if (entry[refKey]?.Text("name") is { Length: > 0 } name)
+                {
+                    resource.ConfigReferences.Add(new K8sConfigReference
+                    {
+                        Kind = kind, Name = name, Usage = "envFrom", Prefix = entry.Text("prefix"), Resource = label,
+                        Container = container.Name, FilePath = filePath, Line = entry[refKey]!.Line
+                    });
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// The template's lines with actions substituted or dropped, each keeping the line it came from; the
+    /// .Values reads are recorded on the chart as they are met
+    /// </summary>
+    private static List<(string Text, int Line)> Render(string filePath, string[] lines, HelmChart chart)
+    {
+        var output = new List<(string Text, int Line)>();
+
+        // if/with/range/define blocks with the values path '.' stands for: "" is the top level, null unknown
+        var blocks = new Stack<(string Keyword, string? Context)>();
+        string? Context() => blocks.Count == 0 ? string.Empty : blocks.Peek().Context;
+        bool InDefine() => blocks.Any(b => b.Keyword == "define");
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            var actions = Action.Matches(line);
+            if (actions.Count == 0)
+            {
+                if (!InDefine())
+                    output.Add((line, i + 1));
+                continue;
+            }
+
+            var bare = Action.Replace(line, string.Empty).Trim().Length == 0;
+            foreach (Match action in actions)
+            {
+                var body = action.Groups["body"].Value.Trim();
+                if (body.StartsWith("/*", StringComparison.Ordinal))
+                    continue;
+                var keyword = body.Split(' ', 2)[0];
+                var context = Context();
+                if (!InDefine() && keyword != "define")
+                    Record(chart, filePath, i + 1, body, context);
+
+                switch (keyword)
+                {
+                    case "define":
+                        blocks.Push(("define", null));
+                        break;
+                    case "if":
+                        blocks.Push(("if", context));
+                        break;
+                    case "with":
+                        blocks.Push(("with", ValuesPath(body[4..].Trim().Split(' ')[0], context)));
+                        break;
+                    case "range":
+                        blocks.Push(("range", null));
+                        break;
+                    case "end":
+                        if (blocks.Count > 0)
+                            blocks.Pop();
+                        break;
+                    default:
+                        if (bare && ToYaml.Match(body) is { Success: true } toYaml && ValuesPath(toYaml.Groups["target"].Value, context) is { } path
+                            && chart.Blocks.TryGetValue(path, out var text))
+                        {
+                            var indent = new string(' ', int.Parse(toYaml.Groups["indent"].Value));
+                            output.AddRange(text.Select(t => (indent + t, i + 1)));
+                        }
+                        break;
+                }
+            }
+
+            if (InDefine() || bare)
+                continue;
+            var rendered = Action.Replace(line, m => Evaluate(m.Groups["body"].Value.Trim(), Context(), chart) ?? m.Value);
+            output.Add((rendered, i + 1));
+        }
+        return output;
+    }
+
+    private static void Record(HelmChart chart, string filePath, int line, string body, string? context)
+    {
+        var code = QuotedText.Replace(body, string.Empty);
+        var paths = ValuesRead.Matches(code).Select(m => m.Groups["path"].Value).ToList();
+        if (context is { Length: > 0 })
+        {
+            paths.AddRange(ContextRead.Matches(ValuesRead.Replace(code, string.Empty))
+                .Select(m => m.Groups["path"].Value)
+                .Where(p => !BuiltIns.Contains(p.Split('.')[0]))
+                .Select(p => context.Length == 0 ? p : context + "." + p));
+        }
+        foreach (var path in paths.Distinct(StringComparer.Ordinal))
+        {
+            if (chart.References.Any(r => r.Path == path && r.FilePath == filePath && r.Line == line))
+                continue;
+            chart.References.Add(new HelmValueReference
+            {
+                Path = path,
+                FilePath = filePath,
+                Line = line,
+                Defined = chart.Values.ContainsKey(path),
+                Value = chart.Values.GetValueOrDefault(path)
+            });
+        }
+    }
+
+    /// <summary>
+    /// The values path an expression names (.Values.a.b, $.Values.a.b, '.' or .b inside a with), or null
+    /// </summary>
+    private static string? ValuesPath(string expression, string? context)
+    {
+        if (ValuesRead.Match(expression) is { Success: true, Index: 0 } values && values.Length == expression.Length)
+            return values.Groups["path"].Value;
+        if (context == null || context.Length == 0)
+            return null;
+        if (expression == ".")
+            return context;
+        return ContextRead.Match(expression) is { Success: true, Index: 0 } relative && relative.Length == expression.Length
+               && !BuiltIns.Contains(relative.Groups["path"].Value.Split('.')[0])
+            ? context + "." + relative.Groups["path"].Value
+            : null;
+    }
+
+    /// <summary>
+    /// The text an action renders to, when it only reads values, chart and release fields through simple pipes
+    /// </summary>
+    private static string? Evaluate(string body, string? context, HelmChart chart)
+    {
+        var stages = body.Split('|').Select(s => s.Trim()).ToList();
+        var value = Term(stages[0], context, chart);
+        foreach (var stage in stages.Skip(1))
+        {
+            var parts = stage.Split(' ', 2, StringSplitOptions.TrimEntries);
+            switch (parts[0])
+            {
+                case "default" when parts.Length == 2:
+                    if (string.IsNullOrEmpty(value))
+                        value = Term(parts[1], context, chart);
+                    break;
+                case "lower":
+                    value = value?.ToLowerInvariant();
+                    break;
+                case "upper":
+                    value = value?.ToUpperInvariant();
+                    break;
+                case "trunc" when parts.Length == 2 && int.TryParse(parts[1], out var length):
+                    value = value != null && value.Length > length ? value[..length] : value;
+                    break;
+                case "trimSuffix" when parts.Length == 2:
+                    var suffix = Unquote(parts[1]);
+                    value = value != null && value.EndsWith(suffix, StringComparison.Ordinal) ? value[..^suffix.Length] : value;
+                    break;
+                default:
+                    if (!NoOpPipes.Contains(parts[0]))
+                        return null;
+                    break;
+            }
+        }
+        return value;
+    }
+
+    private static string? Term(string term, string? context, HelmChart chart)
+    {
+        var parts = Regex.Matches(term, @"""(?:[^""\\]|\\.)*""|\S+").Select(m => m.Value).ToList();
+        if (parts.Count == 0)
+            return null;
+        switch (parts[0])
+        {
+            case "default" when parts.Count == 3:
+                return Term(parts[2], context, chart) is { Length: > 0 } set ? set : Term(parts[1], context, chart);
+            case "quote" or "squote" or "toString" when parts.Count == 2:
+                return Term(parts[1], context, chart);
+            case ".Chart.Name":
+                return chart.Name;
+            case ".Chart.Version":
+                return chart.Version;
+            case ".Chart.AppVersion":
+                return chart.AppVersion;
+            case ".Release.Name" or "$.Release.Name":
+                return "release-name";
+            case ".Release.Namespace" or "$.Release.Namespace":
+                return "default";
+        }
+        if (parts.Count > 1)
+            return null;
+        if (term.StartsWith('"') && term.EndsWith('"') && term.Length >= 2)
+            return term[1..^1];
+        if (Regex.IsMatch(term, @"^-?\d+(?:\.\d+)?$") || term is "true" or "false")
+            return term;
+        return ValuesPath(term, context) is { } path ? chart.Values.GetValueOrDefault(path) : null;
+    }
+
+    /// <summary>
+    /// The lines nested under the key on <paramref name="keyLine"/>, dedented to column 0
+    /// </summary>
+    private static List<string> BlockBelow(string[] lines, int keyLine)
+    {
+        var keyIndent = lines[keyLine - 1].Length - lines[keyLine - 1].TrimStart().Length;
+        var block = new List<string>();
+        for (var i = keyLine; i < lines.Length; i++)
+        {
+            var text = lines[i].TrimEnd();
+            var indent = text.Length - text.TrimStart().Length;
+            if (text.Trim().Length > 0 && (indent < keyIndent || indent == keyIndent && !text.TrimStart().StartsWith('-')))
+                break;
+            block.Add(text);
+        }
+        while (block.Count > 0 && block[^1].Trim().Length == 0)
+            block.RemoveAt(block.Count - 1);
+        var common = block.Where(l => l.Trim().Length > 0).Select(l => l.Length - l.TrimStart().Length).DefaultIfEmpty(0).Min();
+        return block.Select(l => l.Length >= common ? l[common..] : l.Trim()).ToList();
+    }
+
+    private static List<(string Text, int Line)> Numbered(string content) =>
+        content.Replace("\r\n", "\n").Split('\n').Select((text, i) => (text, i + 1)).ToList();
+
+    /// <summary>
+    /// Block YAML by indentation with block scalars, flow lists and simple flow maps, from lines that carry
+    /// their source line numbers
+    /// </summary>
+    private static Node ReadYaml(IReadOnlyList<(string Text, int Line)> lines)
+    {
+        var entries = new List<(int Indent, string Text, int Line, string? Block)>();
+        for (var i = 0; i < lines.Count; i++)
+        {
+            var line = lines[i].Text.TrimEnd();
+            var text = line.TrimStart();
+            if (text.Length == 0 || text.StartsWith('#') || text == "---" || text == "...")
+                continue;
+
+            var indent = line.Length - text.Length;
+            while (text == "-" || text.StartsWith("- ", StringComparison.Ordinal))
+            {
+                entries.Add((indent, "-", lines[i].Line, null));
+                var rest = text[1..].TrimStart();
+                indent += text.Length - rest.Length;
+                text = rest;
+            }
+            if (text.Length == 0)
+                continue;
+
+            string? block = null;
+            var start = lines[i].Line;
+            var key = YamlKey.Match(text);
+            if (key.Success && key.Groups["value"].Value.Trim() is { Length: > 0 } value && value[0] is '|' or '>')
+            {
+                var body = new List<string>();
+                while (i + 1 < lines.Count)
+                {
+                    var next = lines[i + 1].Text.TrimEnd();
+                    if (next.Trim().Length > 0 && next.Length - next.TrimStart().Length <= indent)
+                        break;
+                    i++;
+                    body.Add(next.Trim());
+                }
+                block = string.Join(value[0] == '|' ? "\n" : " ", body).Trim();
+            }
+            entries.Add((indent, text, start, block));
+        }
+
+        var position = 0;
+        return ReadBlock(entries, ref position, -1) ?? new Node();
+    }
+
+    private static Node? ReadBlock(List<(int Indent, string Text, int Line, string? Block)> entries, ref int i, int parentIndent)
+    {
+        if (i >= entries.Count || entries[i].Indent <= parentIndent)
+            return null;
+
+        var indent = entries[i].Indent;
+        if (entries[i].Text == "-")
+        {
+            var list = new Node { Items = new List<Node>(), Line = entries[i].Line };
+            while (i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-")
+            {
+                var line = entries[i++].Line;
+                var item = ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+                item.Line = line;
+                list.Items.Add(item);
+            }
+            return list;
+        }
+
+        var key = YamlKey.Match(entries[i].Text);
+        if (!key.Success)
+            return Scalar(entries[i].Text, entries[i++].Line);
+
+        var map = new Node { Map = new Dictionary<string, Node>(StringComparer.Ordinal), Line = entries[i].Line };
+        while (i < entries.Count && entries[i].Indent == indent)
+        {
+            key = YamlKey.Match(entries[i].Text);
+            var line = entries[i].Line;
+            var block = entries[i++].Block;
+            if (!key.Success)
+                continue;
+
+            var value = key.Groups["value"].Value.Trim();
+            Node child;
+            if (block != null)
+            {
+                child = new Node { Value = block };
+            }
+            else if (value.Length == 0 || value.StartsWith('&'))
+            {
+                // A list may sit at its key's own indentation
+                child = i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-"
+                    ? ReadBlock(entries, ref i, indent - 1)!
+                    : ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+            }
+            else
+            {
+                child = Scalar(value, line);
+            }
+            child.Line = line;
+            map.Map[key.Groups["key"].Value.Trim()] = child;
+        }
+        return map;
+    }
+
+    private static Node Scalar(string text, int line)
+    {
+        if (text.StartsWith('[') && text.EndsWith(']'))
+        {
+            return new Node
+            {
+                Items = text[1..^1].Split(',').Select(v => v.Trim()).Where(v => v.Length > 0).Select(v => Scalar(v, line)).ToList(),
+                Line = line
+            };
+        }
+        if (text.StartsWith('{') && text.EndsWith('}') && !text.StartsWith("{{", StringComparison.Ordinal))
+        {
+            var map = new Dictionary<string, Node>(StringComparer.Ordinal);
+            foreach (var pair in text[1..^1].Split(','))
+            {
+                var colon = pair.IndexOf(':');
+                if (colon > 0)
+                    map[Unquote(pair[..colon].Trim())] = Scalar(pair[(colon + 1)..].Trim(), line);
+            }
+            return new Node { Map = map, Line = line };
+        }
+        if (text[0] is not ('"' or '\''))
+        {
+            var comment = text.IndexOf(" #", StringComparison.Ordinal);
+            if (comment >= 0)
+                text = text[..comment].TrimEnd();
+        }
+        return new Node { Value = Unquote(text), Line = line };
+    }
+
+    private static string Unquote(string text) =>
+        text.Length >= 2 && (text[0] == '"' && text[^1] == '"' || text[0] == '\'' && text[^1] == '\'') ? text[1..^1] : text;
+}
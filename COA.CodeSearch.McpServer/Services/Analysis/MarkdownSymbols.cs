@@ -0,0 +1,252 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Markdown headings read as declarations so a design document can be navigated like code: ATX (## Storage)
+/// and setext (underlined) headings become "heading" symbols nested under their parent heading and spanning
+/// their section, and fenced code blocks with a language become "block" symbols named by the language.
+/// Front matter, fenced and indented code are not searched for headings. The GitHub anchor of each heading
+/// (#storage-layer) is part of its signature.
+/// </summary>
+public static class MarkdownSymbols
+{
+    private static readonly Regex AtxHeading = new(@"^ {0,3}(?<marks>#{1,6})(?:[ \t]+(?<text>.*?))?(?:[ \t]+#+)?[ \t]*$", RegexOptions.Compiled);
+    private static readonly Regex SetextUnderline = new(@"^ {0,3}(?<marks>=+|-+)[ \t]*$", RegexOptions.Compiled);
+    private static readonly Regex FenceOpen = new(@"^(?<indent> {0,3})(?<fence>`{3,}|~{3,})[ \t]*(?<info>[^`]*?)[ \t]*$", RegexOptions.Compiled);
+    private static readonly Regex CustomId = new(@"[ \t]*\{#(?<id>[\w-]+)\}[ \t]*$", RegexOptions.Compiled);
+    private static readonly Regex BlockStart = new(@"^ {0,3}(?:[-+*>]|\d{1,9}[.)]|\||<)", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Markdown and MDX files
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".md", StringComparison.OrdinalIgnoreCase)
+        || filePath.EndsWith(".mdx", StringComparison.OrdinalIgnoreCase)
+        || filePath.EndsWith(".markdown", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every heading and fenced code block with a language, in source order. A heading ends where the next
+    /// heading of the same or a higher level starts.
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var symbols = new List<JulieSymbol>();
+        var open = new List<(JulieSymbol Symbol, int Level)>();
+        var anchors = new Dictionary<string, int>(StringComparer.Ordinal);
+        var i = SkipFrontMatter(lines);
+        var paragraphStart = -1;
+
+        void CloseSections(int level, int beforeLine)
+        {
+            while (open.Count > 0 && open[^1].Level >= level)
+            {
+                open[^1].Symbol.EndLine = LastContentLine(lines, open[^1].Symbol.StartLine, beforeLine);
+                open.RemoveAt(open.Count - 1);
+            }
+        }
+
+        void Heading(int level, string rawText, int startLine, int endLine, int column)
+        {
+            var (text, id) = HeadingText(rawText);
+            if (text.Length == 0)
+                return;
+
+            CloseSections(level, startLine);
+            var anchor = id ?? Anchor(text);
+            if (id == null)
+            {
+                // GitHub numbers repeated anchors: usage, usage-1, usage-2
+                var seen = anchors.GetValueOrDefault(anchor);
+                anchors[anchor] = seen + 1;
+                if (seen > 0)
+                    anchor = $"{anchor}-{seen}";
+            }
+
+            var symbol = Declare(symbols, filePath, "heading", text, startLine, column, endLine, $"{new string('#', level)} {text} (#{anchor})",
+                open.Count > 0 ? open[^1].Symbol : null);
+            open.Add((symbol, level));
+        }
+
+        for (; i < lines.Length; i++)
+        {
+            var line = lines[i];
+
+            if (FenceOpen.Match(line) is { Success: true } fence && (fence.Groups["fence"].Value[0] == '~' || !fence.Groups["info"].Value.Contains('`')))
+            {
+                var marker = fence.Groups["fence"].Value;
+                var close = i + 1;
+                while (close < lines.Length && !IsFenceClose(lines[close], marker))
+                    close++;
+
+                var language = Language(fence.Groups["info"].Value);
+                if (language != null)
+                {
+                    Declare(symbols, filePath, "block", language, i + 1, fence.Groups["indent"].Length, Math.Min(close, lines.Length - 1) + 1,
+                        line.Trim(), open.Count > 0 ? open[^1].Symbol : null);
+                }
+                i = close;
+                paragraphStart = -1;
+                continue;
+            }
+
+            if (line.Trim().Length == 0)
+            {
+                paragraphStart = -1;
+                continue;
+            }
+
+            // Indented code: four spaces or a tab outside a paragraph
+            if (paragraphStart < 0 && (line.StartsWith("    ", StringComparison.Ordinal) || line.StartsWith('\t')))
+                continue;
+
+            if (AtxHeading.Match(line) is { Success: true } atx)
+            {
+                Heading(atx.Groups["marks"].Length, atx.Groups["text"].Value, i + 1, i + 1, line.Length - line.TrimStart().Length);
+                paragraphStart = -1;
+                continue;
+            }
+
+            if (paragraphStart >= 0 && SetextUnderline.Match(line) is { Success: true } underline)
+            {
+                var text = string.Join(" ", lines[paragraphStart..i].Select(l => l.Trim()));
+                Heading(underline.Groups["marks"].Value[0] == '=' ? 1 : 2, text, paragraphStart + 1, i + 1,
+                    lines[paragraphStart].Length - lines[paragraphStart].TrimStart().Length);
+                paragraphStart = -1;
+                continue;
+            }
+
+            // Lists, quotes, tables and HTML are not paragraphs a setext underline can turn into a heading
+            if (paragraphStart < 0 && !BlockStart.IsMatch(line))
+                paragraphStart = i;
+        }
+
+        CloseSections(1, lines.Length + 1);
+        foreach (var symbol in symbols)
+            symbol.EndColumn = lines[symbol.EndLine - 1].Length;
+        return symbols;
+    }
+
+    /// <summary>
+    /// The symbols of a Markdown file with the headings and code blocks julie-codesearch missed added.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The anchor GitHub generates for a heading: lowercased, punctuation dropped, spaces as hyphens
+    /// ("Storage & Indexing (v2)" → storage--indexing-v2)
+    /// </summary>
+    public static string Anchor(string headingText)
+    {
+        var anchor = new StringBuilder();
+        foreach (var c in headingText.Trim().ToLowerInvariant())
+        {
+            if (char.IsLetterOrDigit(c) || c is '-' or '_')
+                anchor.Append(c);
+            else if (c == ' ')
+                anchor.Append('-');
+        }
+        return anchor.ToString();
+    }
+
+    /// <summary>
+    /// The level of a heading symbol, from the # marks of its signature; 0 for other symbols
+    /// </summary>
+    public static int Level(JulieSymbol symbol) =>
+        symbol.Kind == "heading" && symbol.Signature != null ? symbol.Signature.TakeWhile(c => c == '#').Count() : 0;
+
+    /// <summary>
+    /// Heading text without inline markup - emphasis, code spans, links and images keep their text - and a
+    /// trailing {#custom-id}, which is returned separately
+    /// </summary>
+    private static (string Text, string? Id) HeadingText(string raw)
+    {
+        string? id = null;
+        if (CustomId.Match(raw) is { Success: true } custom)
+        {
+            id = custom.Groups["id"].Value;
+            raw = raw[..custom.Index];
+        }
+
+        var text = Regex.Replace(raw, @"!?\[(?<text>[^\]]*)\]\([^)]*\)", "${text}");
+        text = Regex.Replace(text, @"\[(?<text>[^\]]*)\]\[[^\]]*\]", "${text}");
+        text = Regex.Replace(text, @"<[^>]+>", string.Empty);
+        text = Regex.Replace(text, @"(\*\*|\*|~~|`)(?<inner>.+?)\1", "${inner}");
+        // snake_case names keep their underscores; only _emphasis_ at word boundaries is markup
+        text = Regex.Replace(text, @"(?<!\w)(__|_)(?<inner>.+?)\1(?!\w)", "${inner}");
+        return (Regex.Replace(text, @"\s+", " ").Trim(), id);
+    }
+
+    /// <summary>
+    /// The language of a fence info string: ```ts title="a.ts" → ts, ```{python} → python; null when none is given
+    /// </summary>
+    private static string? Language(string info)
+    {
+        var first = info.Trim().Split(' ', '\t', ',')[0].Trim('{', '}', '.');
+        return first.Length == 0 ? null : first.ToLowerInvariant();
+    }
+
+    private static bool IsFenceClose(string line, string marker)
+    {
+        var trimmed = line.TrimStart(' ');
+        if (line.Length - trimmed.Length > 3)
+            return false;
+        trimmed = trimmed.TrimEnd();
+        return trimmed.Length >= marker.Length && trimmed.All(c => c == marker[0]);
+    }
+
+    /// <summary>
+    /// The first line after YAML (---) or TOML (+++) front matter
+    /// </summary>
+    private static int SkipFrontMatter(string[] lines)
+    {
+        if (lines.Length == 0 || lines[0].TrimEnd() is not ("---" or "+++"))
+            return 0;
+
+        var delimiter = lines[0].TrimEnd();
+        for (var i = 1; i < lines.Length; i++)
+        {
+            if (lines[i].TrimEnd() == delimiter || delimiter == "---" && lines[i].TrimEnd() == "...")
+                return i + 1;
+        }
+        return 0;
+    }
+
+    private static int LastContentLine(string[] lines, int startLine, int beforeLine)
+    {
+        var last = Math.Min(beforeLine - 1, lines.Length);
+        while (last > startLine && lines[last - 1].Trim().Length == 0)
+            last--;
+        return last;
+    }
+
+    private static JulieSymbol Declare(List<JulieSymbol> symbols, string filePath, string kind, string name,
+        int line, int column, int endLine, string signature, JulieSymbol? parent)
+    {
+        var symbol = new JulieSymbol
+        {
+            Id = StableId(filePath, kind, name, line),
+            Name = name,
+            Kind = kind,
+            Language = "markdown",
+            FilePath = filePath,
+            StartLine = line,
+            StartColumn = column,
+            EndLine = endLine,
+            Signature = signature,
+            Visibility = "public",
+            ParentId = parent?.Id
+        };
+        symbols.Add(symbol);
+        return symbol;
+    }
+
+    private static string StableId(string filePath, string kind, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"markdown:{filePath}:{kind}:{name}:{line}")))[..32].ToLowerInvariant();
+}
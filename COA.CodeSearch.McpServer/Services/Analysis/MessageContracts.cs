@@ -0,0 +1,474 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A place that publishes or subscribes to a message channel - a Kafka topic, a queue, a NATS subject, an
+/// event name or an event class
+/// </summary>
+public class MessageSite
+{
+    /// <summary>
+    /// publish or subscribe
+    /// </summary>
+    public string Role { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Topic, queue, subject or event name, or the message type's name for type-based buses
+    /// </summary>
+    public string Channel { get; set; } = string.Empty;
+
+    /// <summary>
+    /// name for topics, queues, subjects and event names; type for event classes
+    /// </summary>
+    public string Kind { get; set; } = "name";
+
+    /// <summary>
+    /// kafka, rabbitmq, nats, redis, azure-service-bus, jms, masstransit, mediatr, nservicebus, event-bus,
+    /// nestjs, events (in-process emitters) or pubsub when the client library could not be told
+    /// </summary>
+    public string Broker { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The channel is a constant or variable whose value could not be found; Channel holds its name
+    /// </summary>
+    public bool Dynamic { get; set; }
+
+    /// <summary>
+    /// The queue a consumer reads the channel through, when an exchange binding routes it there
+    /// </summary>
+    public string? Via { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Code { get; set; } = string.Empty;
+
+    // Generic publish/subscribe/emit calls are only kept when their channel is a literal or a known constant
+    internal bool Weak { get; set; }
+    internal bool ViaDynamic { get; set; }
+}
+
+/// <summary>
+/// One channel with everything that publishes to and consumes from it
+/// </summary>
+public class MessageFlow
+{
+    public string Channel { get; set; } = string.Empty;
+
+    /// <summary>
+    /// name or type, as on <see cref="MessageSite.Kind"/>
+    /// </summary>
+    public string Kind { get; set; } = "name";
+
+    public List<string> Brokers { get; set; } = new();
+
+    /// <summary>
+    /// linked, no-consumer or no-producer
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public List<MessageSite> Producers { get; set; } = new();
+    public List<MessageSite> Consumers { get; set; } = new();
+}
+
+/// <summary>
+/// Reads message publish and subscribe sites from source text and pairs them by channel. Covers Kafka
+/// (Confluent, kafkajs, sarama, kafka-go, kafka-python, Spring), RabbitMQ (RabbitMQ.Client, amqplib, amqp091,
+/// pika, Spring AMQP) with queue bindings, NATS and Redis pub/sub, Azure Service Bus, JMS, MassTransit,
+/// MediatR, NServiceBus and event-bus handlers by message type, NestJS patterns and Node event emitters.
+/// Channels held in string constants are resolved across the workspace; wildcard subscriptions (orders.*,
+/// orders.>, orders.#) consume every channel they match.
+/// </summary>
+public static class MessageContracts
+{
+    /// <summary>
+    /// Flow statuses in report order
+    /// </summary>
+    public static readonly string[] Statuses = { "no-consumer", "no-producer", "linked" };
+
+    private static readonly HashSet<string> CodeExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".go", ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".py", ".java", ".kt"
+    };
+
+    private static readonly Dictionary<string, Regex> BrokerHints = new()
+    {
+        ["kafka"] = new(@"kafka|sarama", RegexOptions.Compiled | RegexOptions.IgnoreCase),
+        ["rabbitmq"] = new(@"rabbit|amqp|\bpika\b", RegexOptions.Compiled | RegexOptions.IgnoreCase),
+        ["nats"] = new(@"\bnats\b|nats-io|NATS\.", RegexOptions.Compiled | RegexOptions.IgnoreCase),
+        ["redis"] = new(@"redis", RegexOptions.Compiled | RegexOptions.IgnoreCase),
+        ["azure-service-bus"] = new(@"Azure\.Messaging\.ServiceBus|@azure/service-bus|azure\.servicebus", RegexOptions.Compiled),
+        ["jms"] = new(@"\b(?:javax|jakarta)\.jms\b|JmsTemplate", RegexOptions.Compiled),
+        ["masstransit"] = new(@"\bMassTransit\b", RegexOptions.Compiled),
+        ["mediatr"] = new(@"\bMediatR\b|\bIMediator\b", RegexOptions.Compiled),
+        ["nservicebus"] = new(@"\bNServiceBus\b", RegexOptions.Compiled),
+        ["event-bus"] = new(@"\bI?EventBus\b", RegexOptions.Compiled),
+        ["nestjs"] = new(@"@nestjs/microservices", RegexOptions.Compiled)
+    };
+
+    // Event names Node streams, sockets, processes and the DOM emit on their own
+    private static readonly HashSet<string> BuiltInEvents = new(StringComparer.Ordinal)
+    {
+        "error", "close", "end", "data", "connect", "connection", "disconnect", "message", "ready", "exit", "finish",
+        "drain", "open", "listening", "request", "response", "line", "change", "click", "timeout", "readable", "pipe",
+        "unpipe", "abort", "load", "resize", "input", "submit", "keydown", "keyup", "beforeExit", "uncaughtException",
+        "unhandledRejection", "warning", "SIGINT", "SIGTERM", "SIGHUP", "newListener", "removeListener", "upgrade"
+    };
+
+    private static readonly Regex Constant = new(
+        @"^\s*(?:(?:export|public|private|internal|protected|static|readonly|final|const|var|let|val|def)\s+)*(?:[\w.<>]+\s+)?" +
+        @"(?!(?:string|String|str)\b)(?<name>[A-Za-z_$][\w$]*)(?:\s*:\s*[\w.<>]+|\s+string)?\s*=\s*[$@]?(?<q>[""'`])(?<value>[^""'`]+)\k<q>\s*;?\s*(?:$|//|#)",
+        RegexOptions.Compiled);
+
+    private static readonly Regex WildcardSegment = new(@"^(?:\*|>|#)$|\*", RegexOptions.Compiled);
+
+    private sealed record Rule(Regex Pattern, string Role, string[] Brokers, bool Weak = false, bool NeedsHint = false,
+        Regex? PublishContext = null, Regex? SubscribeContext = null);
+
+    // A channel argument: a string literal (@"", $"", f'', r''), nameof(X), or a constant or variable
+    private static string Arg(string name) =>
+        $@"(?:nameof\((?<{name}Nameof>[\w.]+)\)|[$@fFrRbB]{{0,2}}(?<{name}Q>[""'`])(?<{name}>[^""'`]*)\k<{name}Q>|(?<{name}Id>[A-Za-z_$][\w.$]*))";
+
+    private static Regex R(string pattern) => new(pattern, RegexOptions.Compiled);
+
+    private static readonly string Ch = Arg("ch");
+    private static readonly string Ex = Arg("ex");
+    private static readonly string Key = Arg("key");
+    private static readonly string Queue = Arg("q");
+    private static readonly string[] PubSub = { "nats", "redis", "kafka", "pubsub" };
+    private static readonly string[] TypedBuses = { "masstransit", "nservicebus", "mediatr", "event-bus" };
+
+    private static readonly Rule[] CSharpRules =
+    {
+        new(R($@"\.Produce(?:Async)?\s*\(\s*{Ch}"), "publish", new[] { "kafka" }),
+        new(R($@"\.BasicPublish(?:Async)?\s*\(\s*(?:exchange:\s*)?{Ex}\s*,\s*(?:routingKey:\s*)?{Key}"), "publish", new[] { "rabbitmq" }),
+        new(R($@"\.BasicConsume(?:Async)?\s*\(\s*(?:queue:\s*)?{Ch}"), "subscribe", new[] { "rabbitmq" }),
+        new(R($@"\.QueueBind(?:Async)?\s*\(\s*(?:queue:\s*)?{Queue}\s*,\s*(?:exchange:\s*)?{Ex}\s*,\s*(?:routingKey:\s*)?{Key}"), "bind", new[] { "rabbitmq" }),
+        new(R($@"\.CreateSender\s*\(\s*{Ch}"), "publish", new[] { "azure-service-bus" }),
+        new(R($@"\.Create(?:Session)?(?:Processor|Receiver)\s*\(\s*{Ch}"), "subscribe", new[] { "azure-service-bus" }),
+        new(R($@"\.(?:Publish|PublishAsync)\s*\(\s*{Ch}\s*,"), "publish", PubSub, Weak: true),
+        new(R($@"\.Subscribe(?:Async)?\s*\(\s*{Ch}\s*[,)]"), "subscribe", PubSub, Weak: true),
+        new(R(@"\bIConsumer<\s*(?<type>[A-Z]\w*)\s*>"), "subscribe", new[] { "masstransit" }),
+        new(R(@"\bI(?:NotificationHandler|RequestHandler)<\s*(?<type>[A-Z]\w*)\s*[,>]"), "subscribe", new[] { "mediatr" }),
+        new(R(@"\bIHandleMessages<\s*(?<type>[A-Z]\w*)\s*>"), "subscribe", new[] { "nservicebus" }),
+        new(R(@"\bI(?:IntegrationEventHandler|DomainEventHandler|EventHandler)<\s*(?<type>[A-Z]\w*)\s*>"), "subscribe", new[] { "event-bus" }),
+        new(R(@"\.(?:Publish|Send)(?:Async)?\s*(?:<\s*(?<type>[A-Z]\w*)\s*>\s*)?\(\s*(?:new\s+(?<type>[A-Z]\w*))?"), "publish", TypedBuses, NeedsHint: true)
+    };
+
+    private static readonly Rule[] GoRules =
+    {
+        new(R($@"\bTopic:\s*{Ch}"), "context", new[] { "kafka" },
+            PublishContext: R(@"ProducerMessage|kafka\.Message|kafka\.Writer|WriterConfig"),
+            SubscribeContext: R(@"ReaderConfig|kafka\.NewReader|ConsumerConfig")),
+        new(R($@"\.ConsumePartition\s*\(\s*{Ch}"), "subscribe", new[] { "kafka" }),
+        new(R($@"\.Consume\s*\(\s*\w+\s*,\s*\[\]string\s*\{{\s*{Ch}"), "subscribe", new[] { "kafka" }),
+        new(R($@"\.Publish(?:WithContext)?\s*\(\s*(?:\w+\s*,\s*)?{Ex}\s*,\s*{Key}\s*,\s*(?:false|true)\b"), "publish", new[] { "rabbitmq" }),
+        new(R($@"\.Consume(?:WithContext)?\s*\(\s*(?:ctx\w*\s*,\s*)?{Ch}\s*,\s*[^,]+,\s*(?:false|true)\b"), "subscribe", new[] { "rabbitmq" }),
+        new(R($@"\.QueueBind\s*\(\s*{Queue}\s*,\s*{Key}\s*,\s*{Ex}"), "bind", new[] { "rabbitmq" }),
+        new(R($@"\.(?:Publish|PublishMsg|Request)\s*\(\s*(?:ctx\w*\s*,\s*)?{Ch}\s*,"), "publish", PubSub, Weak: true),
+        new(R($@"\.(?:Subscribe|QueueSubscribe|ChanSubscribe|SubscribeSync|PSubscribe)\s*\(\s*(?:ctx\w*\s*,\s*)?{Ch}\s*[,)]"), "subscribe", PubSub, Weak: true)
+    };
+
+    private static readonly Rule[] ScriptRules =
+    {
+        new(R($@"\btopics?\s*:\s*\[?\s*{Ch}"), "context", new[] { "kafka" },
+            PublishContext: R(@"\.send(?:Batch)?\s*\("),
+            SubscribeContext: R(@"\.subscribe\s*\(")),
+        new(R($@"\.publish\s*\(\s*{Ex}\s*,\s*{Key}\s*,"), "publish", new[] { "rabbitmq" }),
+        new(R($@"\.sendToQueue\s*\(\s*{Ch}"), "publish", new[] { "rabbitmq" }),
+        new(R($@"\.consume\s*\(\s*{Ch}\s*,"), "subscribe", new[] { "rabbitmq" }),
+        new(R($@"\.bindQueue\s*\(\s*{Queue}\s*,\s*{Ex}\s*,\s*{Key}"), "bind", new[] { "rabbitmq" }),
+        new(R($@"@(?:EventPattern|MessagePattern)\s*\(\s*{Ch}"), "subscribe", new[] { "nestjs" }),
+        new(R($@"@OnEvent\s*\(\s*{Ch}"), "subscribe", new[] { "events" }),
+        new(R($@"\.send\s*\(\s*{Ch}\s*,"), "publish", new[] { "nestjs" }, Weak: true, NeedsHint: true),
+        new(R($@"\.emit(?:Async)?\s*\(\s*{Ch}\s*[,)]"), "publish", new[] { "nestjs", "events" }, Weak: true),
+        new(R($@"\.(?:on|once|addListener)\s*\(\s*{Ch}\s*,"), "subscribe", new[] { "events" }, Weak: true),
+        new(R($@"\.publish\s*\(\s*{Ch}\s*,"), "publish", PubSub, Weak: true),
+        new(R($@"\.(?:subscribe|pSubscribe|psubscribe)\s*\(\s*{Ch}\s*[,)]"), "subscribe", PubSub, Weak: true)
+    };
+
+    private static readonly Rule[] PythonRules =
+    {
+        new(R($@"\bKafkaConsumer\s*\(\s*{Ch}"), "subscribe", new[] { "kafka" }),
+        new(R($@"\.produce\s*\(\s*(?:topic\s*=\s*)?{Ch}"), "publish", new[] { "kafka" }),
+        new(R($@"\.send\s*\(\s*(?:topic\s*=\s*)?{Ch}\s*,"), "publish", new[] { "kafka" }, Weak: true, NeedsHint: true),
+        new(R($@"\.subscribe\s*\(\s*(?:topics\s*=\s*)?\[\s*{Ch}"), "subscribe", new[] { "kafka" }, NeedsHint: true),
+        new(R($@"\.basic_publish\s*\(\s*(?:exchange\s*=\s*)?{Ex}\s*,\s*(?:routing_key\s*=\s*)?{Key}"), "publish", new[] { "rabbitmq" }),
+        new(R($@"\.basic_consume\s*\(\s*(?:queue\s*=\s*)?{Ch}"), "subscribe", new[] { "rabbitmq" }),
+        new(R($@"\.queue_bind\s*\(\s*(?:queue\s*=\s*)?{Queue}\s*,\s*(?:exchange\s*=\s*)?{Ex}(?:\s*,\s*(?:routing_key\s*=\s*)?{Key})?"), "bind", new[] { "rabbitmq" }),
+        new(R($@"\.publish\s*\(\s*{Ch}\s*,"), "publish", PubSub, Weak: true),
+        new(R($@"\.p?subscribe\s*\(\s*{Ch}\s*[,)]"), "subscribe", PubSub, Weak: true)
+    };
+
+    private static readonly Rule[] JvmRules =
+    {
+        new(R($@"@KafkaListener\s*\([^)]*?topics\s*=\s*[{{\[]?\s*{Ch}"), "subscribe", new[] { "kafka" }),
+        new(R($@"@RabbitListener\s*\([^)]*?queues\s*=\s*[{{\[]?\s*{Ch}"), "subscribe", new[] { "rabbitmq" }),
+        new(R($@"@JmsListener\s*\([^)]*?destination\s*=\s*{Ch}"), "subscribe", new[] { "jms" }),
+        new(R($@"\.send\s*\(\s*{Ch}\s*,"), "publish", new[] { "kafka" }, Weak: true, NeedsHint: true),
+        new(R($@"\.convertAndSend\s*\(\s*{Ex}(?:\s*,\s*{Key}(?=\s*,))?"), "publish", new[] { "rabbitmq", "jms" }, NeedsHint: true)
+    };
+
+    /// <summary>
+    /// Source files the scanner reads
+    /// </summary>
+    public static bool IsSourceFile(string filePath) =>
+        CodeExtensions.Contains(Path.GetExtension(filePath)) && !filePath.EndsWith(".d.ts", StringComparison.OrdinalIgnoreCase)
+        && !filePath.EndsWith(".min.js", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Adds the file's string constants (<c>const string OrdersTopic = "orders"</c>, <c>ORDERS = 'orders'</c>) to
+    /// <paramref name="constants"/>; a name given different values in different places maps to null
+    /// </summary>
+    public static void ReadConstants(IReadOnlyList<string> lines, IDictionary<string, string?> constants)
+    {
+        foreach (var line in lines)
+        {
+            var m = Constant.Match(line);
+            if (!m.Success)
+                continue;
+            var name = m.Groups["name"].Value;
+            var value = m.Groups["value"].Value;
+            constants[name] = constants.TryGetValue(name, out var existing) && existing != value ? null : value;
+        }
+    }
+
+    /// <summary>
+    /// Publish, subscribe and queue-binding sites in one file. Bindings come back with Role bind, the routing
+    /// key (or exchange) as Channel and the queue as Via.
+    /// </summary>
+    public static List<MessageSite> Scan(string filePath, IReadOnlyList<string> lines)
+    {
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var rules = extension switch
+        {
+            ".cs" => CSharpRules,
+            ".go" => GoRules,
+            ".py" => PythonRules,
+            ".java" or ".kt" => JvmRules,
+            _ => ScriptRules
+        };
+
+        var sites = new List<MessageSite>();
+        var content = string.Join('\n', lines);
+        var hinted = BrokerHints.Where(h => h.Value.IsMatch(content)).Select(h => h.Key).ToHashSet();
+
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+        for (var i = 0; i < lines.Count; i++)
+        {
+            // The first rule to claim a position wins, so a RabbitMQ publish isn't also read as a generic one
+            var claimed = new HashSet<int>();
+            foreach (var rule in rules)
+            {
+                var broker = rule.Brokers.FirstOrDefault(hinted.Contains);
+                if (broker == null && rule.NeedsHint)
+                    continue;
+                broker ??= rule.Brokers.Length > 1 && rule.Brokers[^1] == "pubsub" ? "pubsub" : rule.Brokers[0];
+
+                foreach (var m in InCode(rule.Pattern, lines[i], masked[i]))
+                {
+                    if (claimed.Contains(m.Index))
+                        continue;
+                    if (ToSite(rule, m, broker, lines, i) is { } site)
+                    {
+                        claimed.Add(m.Index);
+                        site.FilePath = filePath;
+                        site.Line = i + 1;
+                        site.Code = lines[i].Trim();
+                        sites.Add(site);
+                    }
+                }
+            }
+        }
+        return sites;
+    }
+
+    private static MessageSite? ToSite(Rule rule, Match m, string broker, IReadOnlyList<string> lines, int line)
+    {
+        var role = rule.Role;
+        if (role == "context")
+        {
+            role = ContextRole(rule, lines, line, m.Index);
+            if (role == null)
+                return null;
+        }
+
+        if (m.Groups["type"].Success)
+            return new MessageSite { Role = role, Channel = m.Groups["type"].Value, Kind = "type", Broker = broker };
+        if (rule.Pattern.GetGroupNames().Contains("type"))
+            return null;
+
+        var site = new MessageSite { Role = role, Broker = broker, Weak = rule.Weak };
+        if (role == "bind")
+        {
+            var (queue, queueDynamic) = Read(m, "q");
+            var (exchange, exchangeDynamic) = Read(m, "ex");
+            var (key, keyDynamic) = Read(m, "key");
+            if (queue == null)
+                return null;
+            (site.Channel, site.Dynamic) = string.IsNullOrEmpty(key) ? (exchange ?? string.Empty, exchangeDynamic) : (key, keyDynamic);
+            (site.Via, site.ViaDynamic) = (queue, queueDynamic);
+            return site.Channel.Length > 0 ? site : null;
+        }
+
+        if (rule.Pattern.GetGroupNames().Contains("ex"))
+        {
+            // RabbitMQ: the routing key names the channel, or the exchange itself when it fans out with an empty key
+            var (exchange, exchangeDynamic) = Read(m, "ex");
+            var (key, keyDynamic) = Read(m, "key");
+            (site.Channel, site.Dynamic) = string.IsNullOrEmpty(key) ? (exchange ?? string.Empty, exchangeDynamic) : (key, keyDynamic);
+        }
+        else
+        {
+            var (channel, dynamic) = Read(m, "ch");
+            (site.Channel, site.Dynamic) = (channel ?? string.Empty, dynamic);
+        }
+
+        if (site.Channel.Length == 0 || site.Broker == "events" && !site.Dynamic && BuiltInEvents.Contains(site.Channel))
+            return null;
+        return site;
+    }
+
+    private static (string? Value, bool Dynamic) Read(Match m, string name)
+    {
+        if (m.Groups[name].Success)
+            return (m.Groups[name].Value, false);
+        if (m.Groups[name + "Nameof"].Success)
+            return (m.Groups[name + "Nameof"].Value.Split('.')[^1], false);
+        if (m.Groups[name + "Id"].Success)
+            return (m.Groups[name + "Id"].Value, true);
+        return (null, false);
+    }
+
+    // A topic key in a struct or object literal: publish or subscribe by the nearest constructor or call above it
+    private static string? ContextRole(Rule rule, IReadOnlyList<string> lines, int line, int index)
+    {
+        var text = string.Join('\n', lines.Skip(Math.Max(0, line - 5)).Take(Math.Min(line, 5)).Append(lines[line][..index]));
+        var publish = rule.PublishContext!.Matches(text).LastOrDefault()?.Index ?? -1;
+        var subscribe = rule.SubscribeContext!.Matches(text).LastOrDefault()?.Index ?? -1;
+        return publish < 0 && subscribe < 0 ? null : publish > subscribe ? "publish" : "subscribe";
+    }
+
+    /// <summary>
+    /// Pairs producers with consumers by channel. Constant names are replaced by their values; sites whose channel
+    /// stays unknown go to <paramref name="unresolved"/>, or are dropped for generic calls. A consumer of a queue
+    /// bound to routing keys or exchanges consumes those channels, and a wildcard subscription consumes every
+    /// published channel it matches.
+    /// </summary>
+    public static List<MessageFlow> Link(IEnumerable<MessageSite> sites, IReadOnlyDictionary<string, string?> constants,
+        List<MessageSite>? unresolved = null)
+    {
+        var resolved = new List<MessageSite>();
+        foreach (var site in sites)
+        {
+            if (site.Dynamic && Resolve(site.Channel, constants) is { } channel)
+            {
+                site.Channel = channel;
+                site.Dynamic = false;
+            }
+            if (site.ViaDynamic && Resolve(site.Via!, constants) is { } via)
+            {
+                site.Via = via;
+                site.ViaDynamic = false;
+            }
+            if (site.Dynamic || site.ViaDynamic)
+            {
+                if (!site.Weak)
+                    unresolved?.Add(site);
+                continue;
+            }
+            resolved.Add(site);
+        }
+
+        var bindings = resolved.Where(s => s.Role == "bind").ToList();
+        var producers = resolved.Where(s => s.Role == "publish").ToList();
+        var published = producers.Select(p => p.Channel).ToHashSet(StringComparer.Ordinal);
+        var consumers = new List<MessageSite>();
+        foreach (var consumer in resolved.Where(s => s.Role == "subscribe"))
+        {
+            var bound = bindings.Where(b => b.Via == consumer.Channel).ToList();
+            foreach (var binding in bound)
+            {
+                consumers.Add(new MessageSite
+                {
+                    Role = consumer.Role, Channel = binding.Channel, Kind = consumer.Kind, Broker = consumer.Broker, Via = consumer.Channel, FilePath = consumer.FilePath, Line = consumer.Line, Code = consumer.Code
+                });
+            }
+
+            // Publishing straight to the queue goes through the default exchange
+            if (bound.Count == 0 || published.Contains(consumer.Channel))
+                consumers.Add(consumer);
+        }
+
+        var flows = new Dictionary<string, MessageFlow>(StringComparer.Ordinal);
+        MessageFlow FlowFor(MessageSite site)
+        {
+            if (!flows.TryGetValue(site.Channel, out var flow))
+                flows[site.Channel] = flow = new MessageFlow { Channel = site.Channel, Kind = site.Kind };
+            if (!flow.Brokers.Contains(site.Broker))
+                flow.Brokers.Add(site.Broker);
+            return flow;
+        }
+
+        foreach (var producer in producers)
+            FlowFor(producer).Producers.Add(producer);
+
+        foreach (var consumer in consumers)
+        {
+            var matched = IsPattern(consumer.Channel)
+                ? published.Where(p => !IsPattern(p) && MatchesPattern(consumer.Channel, p)).ToList()
+                : new List<string>();
+            if (matched.Count == 0)
+            {
+                FlowFor(consumer).Consumers.Add(consumer);
+                continue;
+            }
+            foreach (var channel in matched)
+            {
+                var flow = flows[channel];
+                if (!flow.Brokers.Contains(consumer.Broker))
+                    flow.Brokers.Add(consumer.Broker);
+                flow.Consumers.Add(consumer);
+            }
+        }
+
+        foreach (var flow in flows.Values)
+        {
+            flow.Status = flow.Producers.Count == 0 ? "no-producer" : flow.Consumers.Count == 0 ? "no-consumer" : "linked";
+        }
+
+        return flows.Values.OrderBy(f => f.Channel, StringComparer.Ordinal).ToList();
+    }
+
+    /// <summary>
+    /// Whether a subscription pattern matches a channel: * is one dot-separated word, &gt; (NATS) and # (AMQP) the
+    /// rest, and a * inside a word any text, as in Redis PSUBSCRIBE
+    /// </summary>
+    public static bool MatchesPattern(string pattern, string channel)
+    {
+        var segments = pattern.Split('.');
+        var regex = string.Empty;
+        for (var i = 0; i < segments.Length; i++)
+        {
+            var segment = segments[i];
+            var separator = i == 0 ? string.Empty : @"\.";
+            regex += segment switch
+            {
+                ">" => separator + ".+",
+                "#" => i == 0 ? ".*" : @"(?:\..*)?",
+                "*" => separator + @"[^.]+",
+                _ => separator + Regex.Escape(segment).Replace(@"\*", ".*")
+            };
+        }
+        return Regex.IsMatch(channel, $"^{regex}$");
+    }
+
+    private static bool IsPattern(string channel) => channel.Split('.').Any(s => WildcardSegment.IsMatch(s));
+
+    private static string? Resolve(string expression, IReadOnlyDictionary<string, string?> constants) =>
+        constants.TryGetValue(expression.Split('.')[^1], out var value) ? value : null;
+
+    private static IEnumerable<Match> InCode(Regex pattern, string line, string masked) =>
+        pattern.Matches(line).Where(m => m.Index < masked.Length && masked[m.Index] != ' ');
+}
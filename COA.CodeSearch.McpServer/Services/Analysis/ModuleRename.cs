@@ -0,0 +1,109 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Renames a Go module path or C# root namespace in a file: everything <see cref="ImportRewriter"/> rewrites, plus
+/// the go.mod module directive, C# namespace declarations and qualified names in code, RootNamespace, AssemblyName,
+/// PackageId and InternalsVisibleTo in project files, and references in configuration (JSON, YAML, TOML, XML, .config,
+/// .ini, .env and .properties). Like the import rewrite it matches whole segments only, so renaming Acme.Old leaves
+/// Acme.Older alone.
+/// </summary>
+public static class ModuleRename
+{
+    private static readonly Regex GoModule = new(@"^(?<lead>\s*module\s+)(?<path>[^\s/]+(?:/[^\s/]+)*)", RegexOptions.Compiled);
+    private static readonly Regex CSharpNamespace = new(@"^(?<lead>\s*namespace\s+)(?<name>[\w.]+)", RegexOptions.Compiled);
+    private static readonly Regex RootNamespace = new(@"(?<lead><RootNamespace>\s*)(?<name>[^<\s]+)(?=\s*</RootNamespace>)", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> ConfigExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".json", ".jsonc", ".yaml", ".yml", ".toml", ".xml", ".config", ".ini", ".env", ".properties", ".conf"
+    };
+
+    /// <summary>
+    /// True for the files <see cref="Rename"/> reads
+    /// </summary>
+    public static bool Handles(string filePath) => ImportRewriter.Handles(filePath) || IsConfig(filePath);
+
+    /// <summary>
+    /// The file with <paramref name="from"/> renamed to <paramref name="to"/> wherever it names the module or
+    /// namespace, and the lines that changed in line order. Strings and comments in C# code are left alone.
+    /// </summary>
+    public static (string Content, List<ImportRewrite> Rewrites) Rename(string filePath, string content, string from, string to)
+    {
+        var (rewritten, rewrites) = ImportRewriter.Rewrite(filePath, content, from, to);
+        if (string.IsNullOrEmpty(from) || !rewritten.Contains(from, StringComparison.Ordinal))
+            return (rewritten, rewrites);
+
+        var newline = rewritten.Contains("\r\n") ? "\r\n" : "\n";
+        var lines = rewritten.Replace("\r\n", "\n").Split('\n');
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var done = rewrites.Select(r => r.Line).ToHashSet();
+        var masked = extension == ".cs" ? DataFlowScanner.MaskLines(lines, extension) : null;
+        var reference = new Regex($@"(?<![\w.\-])(?<!\w/){Regex.Escape(from)}(?![\w\-])");
+        var project = extension is ".csproj" or ".fsproj" or ".vbproj" or ".props" or ".targets";
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (done.Contains(i + 1))
+                continue;
+
+            var line = lines[i];
+            string? updated = null;
+            string? kind = null;
+
+            if (Path.GetFileName(filePath) == "go.mod")
+            {
+                if (GoModule.Match(line) is { Success: true } module && ImportRewriter.Replace(module.Groups["path"].Value, from, to, "/") is { } path)
+                {
+                    updated = module.Groups["lead"].Value + path + line[(module.Index + module.Length)..];
+                    kind = "go-module";
+                }
+            }
+            else if (masked != null)
+            {
+                if (CSharpNamespace.Match(masked[i]) is { Success: true } declaration
+                    && ImportRewriter.Replace(declaration.Groups["name"].Value, from, to, ".") is { } name)
+                {
+                    updated = declaration.Groups["lead"].Value + name + line[(declaration.Index + declaration.Length)..];
+                    kind = "namespace";
+                }
+                else if (reference.IsMatch(masked[i]))
+                {
+                    // Match on the masked line so names in strings and comments stay; offsets line up because masking keeps length
+                    var matches = reference.Matches(masked[i]);
+                    updated = line;
+                    for (var m = matches.Count - 1; m >= 0; m--)
+                        updated = updated[..matches[m].Index] + to + updated[(matches[m].Index + matches[m].Length)..];
+                    kind = "qualified-name";
+                }
+            }
+            else if (project && RootNamespace.Match(line) is { Success: true } root
+                && ImportRewriter.Replace(root.Groups["name"].Value, from, to, ".") is { } rootName)
+            {
+                updated = line[..root.Index] + root.Groups["lead"].Value + rootName + line[(root.Index + root.Length)..];
+                kind = "root-namespace";
+            }
+            else if ((project || IsConfig(filePath)) && reference.IsMatch(line))
+            {
+                // AssemblyName, PackageId and InternalsVisibleTo in project files follow the root namespace
+                updated = reference.Replace(line, to);
+                kind = project ? "project" : "config";
+            }
+
+            if (updated == null || updated == line)
+                continue;
+
+            rewrites.Add(new ImportRewrite { Line = i + 1, Kind = kind!, OldText = line, NewText = updated });
+            lines[i] = updated;
+        }
+
+        rewrites.Sort((a, b) => a.Line.CompareTo(b.Line));
+        return (string.Join(newline, lines), rewrites);
+    }
+
+    private static bool IsConfig(string filePath) =>
+        ConfigExtensions.Contains(Path.GetExtension(filePath))
+        || Path.GetFileName(filePath).StartsWith(".env", StringComparison.Ordinal);
+}
@@ -0,0 +1,475 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Conventions tying namespaces and packages to directories, from CodeSearch:NamespaceConventions in configuration
+/// </summary>
+public class NamespaceConventionSettings
+{
+    /// <summary>
+    /// Root namespace of each C# project directory ("src/Acme.Api": "Acme.Api"), for projects whose .csproj does
+    /// not say or is not indexed. Overrides RootNamespace in the .csproj.
+    /// </summary>
+    public Dictionary<string, string> RootNamespaces { get; set; } = new();
+
+    /// <summary>
+    /// Java, Kotlin and Scala source roots besides the src/&lt;set&gt;/&lt;language&gt; Maven and Gradle layouts
+    /// </summary>
+    public List<string> SourceRoots { get; set; } = new();
+
+    /// <summary>
+    /// Workspace-relative globs of files left unchecked
+    /// </summary>
+    public List<string> Ignore { get; set; } = new();
+}
+
+/// <summary>
+/// A namespace or package declaration that does not match its file's directory
+/// </summary>
+public class NamespaceViolation
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Language { get; set; } = string.Empty;
+    public string Declared { get; set; } = string.Empty;
+    public string Expected { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The convention broken, e.g. "C# namespace matches the folder path under root namespace Acme.Api"
+    /// </summary>
+    public string Convention { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// One line of a fix: the line as it is and what it becomes, which may span several lines when imports are added
+/// </summary>
+public class NamespaceEdit
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string OldText { get; set; } = string.Empty;
+    public string NewText { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Checks namespace and package declarations against directory structure - C# namespaces follow the folder path
+/// under the project's root namespace, a Go package is named after its directory, Java, Kotlin and Scala packages
+/// follow the path under their source root - and computes the edits that fix them along with the imports,
+/// using directives and qualified names that refer to the moved declarations
+/// </summary>
+public static class NamespaceConventions
+{
+    private static readonly Regex CSharpNamespace = new(@"^(?<indent>\s*)namespace\s+(?<name>[\w.]+)", RegexOptions.Compiled);
+    private static readonly Regex GoPackage = new(@"^package\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex JvmPackage = new(@"^\s*package\s+(?<name>[\w.]+)", RegexOptions.Compiled);
+    private static readonly Regex RootNamespace = new(@"<RootNamespace>\s*(?<name>[^<\s]+)\s*</RootNamespace>", RegexOptions.Compiled);
+    private static readonly Regex AssemblyName = new(@"<AssemblyName>\s*(?<name>[^<\s$]+)\s*</AssemblyName>", RegexOptions.Compiled);
+    private static readonly Regex JvmSourceRoot = new(@"(?:^|/)src/\w+/(?:java|kotlin|scala)/", RegexOptions.Compiled);
+    private static readonly Regex TypeDeclaration = new(
+        @"\b(?:class|struct|interface|enum|record|object|trait)\s+(?<name>[\p{Lu}_]\w*)", RegexOptions.Compiled);
+    private static readonly Regex GoImport = new(@"^\s*(?:import\s+)?(?<alias>[\w.]+\s+)?""(?<path>[^""]+)""", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Every declaration breaking a convention, ordered by file. Files whose expected namespace cannot be told -
+    /// C# outside any known project, JVM sources outside any source root - are not reported.
+    /// </summary>
+    public static List<NamespaceViolation> Check(IReadOnlyList<(string Path, string? Content)> files, NamespaceConventionSettings settings)
+    {
+        var ignored = settings.Ignore.Select(GlobToRegex).ToList();
+        var projects = Projects(files, settings);
+        var violations = new List<NamespaceViolation>();
+
+        foreach (var (path, content) in files.OrderBy(f => f.Path, StringComparer.Ordinal))
+        {
+            if (content == null || ignored.Any(g => g.IsMatch(path)))
+                continue;
+
+            var violation = Path.GetExtension(path).ToLowerInvariant() switch
+            {
+                ".cs" => CheckCSharp(path, content, projects),
+                ".go" => CheckGo(path, content),
+                ".java" => CheckJvm(path, content, "java", settings),
+                ".kt" or ".kts" => CheckJvm(path, content, "kotlin", settings),
+                ".scala" => CheckJvm(path, content, "scala", settings),
+                _ => null
+            };
+            if (violation != null)
+                violations.Add(violation);
+        }
+        return violations;
+    }
+
+    /// <summary>
+    /// The edits fixing <paramref name="violations"/>: each declaration renamed, and in the other files, imports,
+    /// using directives and qualified names of the types the fixed files declare moved to the new namespace.
+    /// Go files importing a renamed package have their package qualifiers renamed.
+    /// </summary>
+    public static List<NamespaceEdit> Fix(IReadOnlyList<(string Path, string? Content)> files, IReadOnlyList<NamespaceViolation> violations)
+    {
+        var original = files.Where(f => f.Content != null)
+            .ToDictionary(f => f.Path, f => f.Content!.Replace("\r\n", "\n").Split('\n'), StringComparer.Ordinal);
+        var edited = original.ToDictionary(f => f.Key, f => f.Value.ToArray(), StringComparer.Ordinal);
+
+        foreach (var violation in violations)
+        {
+            if (!edited.TryGetValue(violation.FilePath, out var lines))
+                continue;
+            var index = violation.Line - 1;
+            lines[index] = ReplaceName(lines[index], violation.Declared, violation.Expected);
+        }
+
+        // A namespace keeps its using directives while files still declare it after the fix
+        var remaining = violations.Select(v => v.Declared).Distinct().ToDictionary(n => n, n =>
+            files.Any(f => f.Content != null && !violations.Any(v => v.FilePath == f.Path && v.Declared == n) && DeclaredNamespace(f.Path, f.Content) == n));
+
+        foreach (var group in violations.Where(v => v.Language != "go").GroupBy(v => (v.Declared, v.Expected, v.Language)))
+        {
+            var types = group.SelectMany(v => TypeDeclaration.Matches(string.Join("\n", original[v.FilePath]))
+                    .Select(m => m.Groups["name"].Value))
+                .ToHashSet(StringComparer.Ordinal);
+            var moved = group.Select(v => v.FilePath).ToHashSet(StringComparer.Ordinal);
+            foreach (var (path, lines) in edited)
+            {
+                if (moved.Contains(path) || !SameFamily(path, group.Key.Language))
+                    continue;
+                MoveReferences(path, original[path], lines, group.Key.Declared, group.Key.Expected, types, remaining[group.Key.Declared]);
+            }
+        }
+
+        // A moved file loses sight of what stays behind, except a C# namespace moving below the one it leaves
+        foreach (var violation in violations.Where(v => v.Language != "go" && !(v.Language == "csharp" && v.Expected.StartsWith(v.Declared + ".", StringComparison.Ordinal))))
+        {
+            var staying = files
+                .Where(f => f.Content != null && f.Path != violation.FilePath && SameFamily(f.Path, violation.Language)
+                    && !violations.Any(v => v.FilePath == f.Path) && DeclaredNamespace(f.Path, f.Content) == violation.Declared)
+                .SelectMany(f => TypeDeclaration.Matches(f.Content!).Select(m => m.Groups["name"].Value))
+                .ToHashSet(StringComparer.Ordinal);
+            var masked = DataFlowScanner.MaskLines(original[violation.FilePath], Path.GetExtension(violation.FilePath));
+            var uses = staying.Count > 0 && masked.Any(l => Regex.IsMatch(l, $@"(?<![\w.])(?:{string.Join('|', staying.Select(Regex.Escape))})\b"));
+            if (uses)
+                AddDirective(violation.FilePath, original[violation.FilePath], edited[violation.FilePath], violation.Declared);
+        }
+
+        foreach (var violation in violations.Where(v => v.Language == "go" && !v.Declared.EndsWith("_test", StringComparison.Ordinal)))
+        {
+            var directory = DirectoryOf(violation.FilePath);
+            foreach (var (path, lines) in edited.Where(f => f.Key.EndsWith(".go", StringComparison.Ordinal) && DirectoryOf(f.Key) != directory))
+                RenameGoQualifier(original[path], lines, directory, violation.Declared, violation.Expected);
+        }
+
+        var edits = new List<NamespaceEdit>();
+        foreach (var (path, lines) in edited.OrderBy(f => f.Key, StringComparer.Ordinal))
+        {
+            for (var i = 0; i < lines.Length; i++)
+            {
+                if (lines[i] != original[path][i])
+                    edits.Add(new NamespaceEdit { FilePath = path, Line = i + 1, OldText = original[path][i], NewText = lines[i] });
+            }
+        }
+        return edits;
+    }
+
+    private static NamespaceViolation? CheckCSharp(string path, string content, List<(string Directory, string Namespace, string Source)> projects)
+    {
+        var project = projects
+            .Where(p => p.Directory.Length == 0 || path.StartsWith(p.Directory + "/", StringComparison.Ordinal))
+            .OrderByDescending(p => p.Directory.Length)
+            .FirstOrDefault();
+        if (project.Namespace == null)
+            return null;
+
+        var (line, declared) = FirstDeclaration(content, CSharpNamespace);
+        if (declared == null)
+            return null;
+
+        var relative = DirectoryOf(project.Directory.Length == 0 ? path : path[(project.Directory.Length + 1)..]);
+        var expected = string.Join('.', new[] { project.Namespace }.Concat(relative.Split('/', StringSplitOptions.RemoveEmptyEntries).Select(Identifier)));
+        return declared == expected ? null : new NamespaceViolation
+        {
+            FilePath = path,
+            Line = line,
+            Language = "csharp",
+            Declared = declared,
+            Expected = expected,
+            Convention = $"C# namespace matches the folder path under root namespace {project.Namespace} ({project.Source})"
+        };
+    }
+
+    private static NamespaceViolation? CheckGo(string path, string content)
+    {
+        var directory = DirectoryOf(path);
+        var segments = directory.Split('/', StringSplitOptions.RemoveEmptyEntries);
+        if (segments.Length == 0 || segments.Contains("testdata"))
+            return null;
+
+        var (line, declared) = FirstDeclaration(content, GoPackage);
+        if (declared == null || declared == "main" || declared == "documentation")
+            return null;
+
+        // Major version directories (v2) name their parent's package
+        var name = Regex.IsMatch(segments[^1], @"^v\d+$") && segments.Length > 1 ? segments[^2] : segments[^1];
+        var suffix = declared.EndsWith("_test", StringComparison.Ordinal) && path.EndsWith("_test.go", StringComparison.Ordinal) ? "_test" : string.Empty;
+        var package = declared[..^suffix.Length];
+        var candidates = new[]
+        {
+            name, name.Replace("-", string.Empty).Replace(".", string.Empty), name.Replace('-', '_').Replace('.', '_'),
+            Regex.Replace(name, @"^go-|-go$", string.Empty).Replace("-", string.Empty)
+        }.Select(c => c.ToLowerInvariant()).ToList();
+        if (candidates.Contains(package.ToLowerInvariant()))
+            return null;
+
+        return new NamespaceViolation
+        {
+            FilePath = path,
+            Line = line,
+            Language = "go",
+            Declared = declared,
+            Expected = Identifier(candidates[1]) + suffix,
+            Convention = $"Go package is named after its directory {name}"
+        };
+    }
+
+    private static NamespaceViolation? CheckJvm(string path, string content, string language, NamespaceConventionSettings settings)
+    {
+        var root = settings.SourceRoots
+            .Select(r => r.Replace('\\', '/').TrimEnd('/'))
+            .Where(r => path.StartsWith(r + "/", StringComparison.Ordinal))
+            .OrderByDescending(r => r.Length)
+            .Select(r => r.Length + 1)
+            .FirstOrDefault();
+        if (root == 0 && JvmSourceRoot.Match(path) is { Success: true } layout)
+            root = layout.Index + layout.Length;
+        if (root == 0)
+            return null;
+
+        var (line, declared) = FirstDeclaration(content, JvmPackage);
+        declared ??= string.Empty;
+        var expected = DirectoryOf(path[root..]).Replace('/', '.');
+        if (declared == expected)
+            return null;
+
+        // Kotlin recommends omitting the common root package from the directory structure
+        if (language == "kotlin" && (expected.Length == 0 || declared.EndsWith("." + expected, StringComparison.Ordinal)))
+            return null;
+        if (line == 0)
+            return null;
+
+        return new NamespaceViolation
+        {
+            FilePath = path,
+            Line = line,
+            Language = language,
+            Declared = declared,
+            Expected = expected,
+            Convention = $"{char.ToUpperInvariant(language[0])}{language[1..]} package matches the directory under source root {path[..(root - 1)]}"
+        };
+    }
+
+    /// <summary>
+    /// C# project directories and their root namespaces: configured ones, then each .csproj's RootNamespace,
+    /// AssemblyName or file name
+    /// </summary>
+    private static List<(string Directory, string Namespace, string Source)> Projects(IReadOnlyList<(string Path, string? Content)> files,
+        NamespaceConventionSettings settings)
+    {
+        var projects = settings.RootNamespaces
+            .Select(r => (Directory: r.Key.Replace('\\', '/').Trim('/').TrimStart('.', '/'), Namespace: r.Value, Source: "configured"))
+            .ToList();
+        foreach (var (path, content) in files.Where(f => f.Path.EndsWith(".csproj", StringComparison.OrdinalIgnoreCase)))
+        {
+            var directory = DirectoryOf(path);
+            if (projects.Any(p => p.Directory == directory))
+                continue;
+            var name = content != null && RootNamespace.Match(content) is { Success: true } root ? root.Groups["name"].Value
+                : content != null && AssemblyName.Match(content) is { Success: true } assembly ? assembly.Groups["name"].Value
+                : Path.GetFileNameWithoutExtension(path);
+            projects.Add((directory, string.Join('.', name.Split('.').Select(Identifier)), Path.GetFileName(path)));
+        }
+        return projects;
+    }
+
+    /// <summary>
+    /// Adds the new namespace where a file used the declared one to reach the moved types, and rewrites
+    /// qualified references to them
+    /// </summary>
+    private static void MoveReferences(string path, string[] original, string[] lines, string declared, string expected,
+        HashSet<string> types, bool declaredRemains)
+    {
+        if (types.Count == 0)
+            return;
+
+        var csharp = path.EndsWith(".cs", StringComparison.OrdinalIgnoreCase);
+        var keyword = csharp ? "using" : "import";
+        var masked = DataFlowScanner.MaskLines(original, Path.GetExtension(path));
+        var qualified = new Regex($@"(?<![\w.]){Regex.Escape(declared)}\.(?<type>{string.Join('|', types.Select(Regex.Escape))})\b");
+        var typeUse = new Regex($@"(?<![\w.])(?:{string.Join('|', types.Select(Regex.Escape))})\b");
+        var wholeImport = new Regex($@"^(?<indent>\s*)(?:global\s+)?{keyword}\s+{Regex.Escape(declared)}(?:\.\*|\._)?\s*;?\s*$");
+        var typeImport = new Regex($@"^\s*import\s+{Regex.Escape(declared)}\.(?<type>\w+)\b");
+
+        var usesTypes = false;
+        var hasExpected = original.Any(l => Regex.IsMatch(l, $@"^\s*(?:global\s+)?{keyword}\s+{Regex.Escape(expected)}(?:\.\*|\._)?\s*;?\s*$"));
+        var importLine = -1;
+        for (var i = 0; i < original.Length; i++)
+        {
+            if (wholeImport.IsMatch(original[i]))
+            {
+                importLine = i;
+                continue;
+            }
+            if (typeImport.Match(original[i]) is { Success: true } single && types.Contains(single.Groups["type"].Value))
+            {
+                lines[i] = ReplaceName(lines[i], declared, expected);
+                continue;
+            }
+            if (qualified.IsMatch(masked[i]))
+                lines[i] = qualified.Replace(lines[i], m => $"{expected}.{m.Groups["type"].Value}");
+            else if (typeUse.IsMatch(masked[i]))
+                usesTypes = true;
+        }
+        if (!usesTypes || hasExpected)
+            return;
+
+        if (importLine >= 0)
+        {
+            // using Old; becomes using New; once nothing declares Old any more
+            var indent = wholeImport.Match(original[importLine]).Groups["indent"].Value;
+            var directive = wholeImport.Replace(original[importLine], m => m.Value.Replace(declared, expected)).Trim();
+            lines[importLine] = declaredRemains ? $"{lines[importLine]}\n{indent}{directive}" : indent + directive;
+            return;
+        }
+
+        // Files in the declared namespace (or below it) saw the types without a directive
+        var own = DeclaredNamespace(path, string.Join("\n", original));
+        if (own != null && (own == declared || own.StartsWith(declared + ".", StringComparison.Ordinal)))
+            AddDirective(path, original, lines, expected);
+    }
+
+    /// <summary>
+    /// Adds a using directive or wildcard import of <paramref name="name"/> after a file's last one, or next to
+    /// its namespace or package declaration
+    /// </summary>
+    private static void AddDirective(string path, string[] original, string[] lines, string name)
+    {
+        var csharp = path.EndsWith(".cs", StringComparison.OrdinalIgnoreCase);
+        var keyword = csharp ? "using" : "import";
+        var directive = csharp ? $"using {name};"
+            : path.EndsWith(".java", StringComparison.OrdinalIgnoreCase) ? $"import {name}.*;"
+            : path.EndsWith(".scala", StringComparison.OrdinalIgnoreCase) ? $"import {name}._"
+            : $"import {name}.*";
+        var declaration = Array.FindIndex(original, l => (csharp ? CSharpNamespace : JvmPackage).IsMatch(l));
+        var lastDirective = Array.FindLastIndex(original, l => Regex.IsMatch(l, $@"^\s*(?:global\s+)?{keyword}\s+[\w.]+"));
+        if (declaration < 0)
+            return;
+        if (lastDirective >= 0 && (lastDirective < declaration || !csharp))
+            lines[lastDirective] = $"{lines[lastDirective]}\n{directive}";
+        else if (csharp)
+            lines[declaration] = $"{directive}\n{lines[declaration]}";
+        else
+            lines[declaration] = $"{lines[declaration]}\n\n{directive}";
+    }
+
+    /// <summary>
+    /// Renames <c>old.</c> qualifiers in a Go file that imports the package directory without an alias
+    /// </summary>
+    private static void RenameGoQualifier(string[] original, string[] lines, string directory, string declared, string expected)
+    {
+        var imported = false;
+        var inBlock = false;
+        var masked = DataFlowScanner.MaskLines(original, ".go");
+        var qualifier = new Regex($@"(?<![\w.]){Regex.Escape(declared)}\.");
+        for (var i = 0; i < original.Length; i++)
+        {
+            var trimmed = original[i].Trim();
+            if (trimmed.StartsWith("import (", StringComparison.Ordinal))
+            {
+                inBlock = true;
+                continue;
+            }
+            if (inBlock && trimmed.StartsWith(')'))
+            {
+                inBlock = false;
+                continue;
+            }
+            if ((inBlock || trimmed.StartsWith("import ", StringComparison.Ordinal)) && GoImport.Match(original[i]) is { Success: true } import)
+            {
+                var target = import.Groups["path"].Value;
+                if (!import.Groups["alias"].Success && (target == directory || target.EndsWith("/" + directory, StringComparison.Ordinal)))
+                    imported = true;
+                continue;
+            }
+            if (imported && qualifier.IsMatch(masked[i]))
+            {
+                var positions = qualifier.Matches(masked[i]).Select(m => m.Index).ToList();
+                var text = lines[i];
+                foreach (var position in positions.OrderByDescending(p => p))
+                    text = text[..position] + expected + text[(position + declared.Length)..];
+                lines[i] = text;
+            }
+        }
+    }
+
+    private static string? DeclaredNamespace(string path, string content) =>
+        Path.GetExtension(path).ToLowerInvariant() switch
+        {
+            ".cs" => FirstDeclaration(content, CSharpNamespace).Name,
+            ".java" or ".kt" or ".kts" or ".scala" => FirstDeclaration(content, JvmPackage).Name,
+            ".go" => FirstDeclaration(content, GoPackage).Name,
+            _ => null
+        };
+
+    private static (int Line, string? Name) FirstDeclaration(string content, Regex declaration)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".cs");
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (declaration.Match(masked[i]) is { Success: true } match)
+                return (i + 1, match.Groups["name"].Value);
+        }
+        return (0, null);
+    }
+
+    private static bool SameFamily(string path, string language) =>
+        Path.GetExtension(path).ToLowerInvariant() switch
+        {
+            ".cs" => language == "csharp",
+            ".java" or ".kt" or ".kts" or ".scala" => language is "java" or "kotlin" or "scala",
+            _ => false
+        };
+
+    private static string ReplaceName(string line, string declared, string expected)
+    {
+        var match = Regex.Match(line, $@"(?<![\w.]){Regex.Escape(declared)}(?![\w])");
+        return match.Success ? line[..match.Index] + expected + line[(match.Index + match.Length)..] : line;
+    }
+
+    /// <summary>
+    /// A folder name as a namespace segment, the way project templates derive it: invalid characters become
+    /// underscores and a leading digit gets one in front
+    /// </summary>
+    private static string Identifier(string segment)
+    {
+        var identifier = Regex.Replace(segment, @"[^\w.]", "_");
+        return identifier.Length > 0 && char.IsDigit(identifier[0]) ? "_" + identifier : identifier;
+    }
+
+    private static string DirectoryOf(string path) => path.Contains('/') ? path[..path.LastIndexOf('/')] : string.Empty;
+
+    // "*.Designer.cs" matches file names anywhere; patterns with a slash match the relative path, and a trailing
+    // slash ("src/Legacy/") covers everything below the directory
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        if (normalized.EndsWith('/'))
+            normalized += "**";
+
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+}
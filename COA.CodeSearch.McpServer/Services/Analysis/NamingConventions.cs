@@ -0,0 +1,403 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Naming rules from CodeSearch:NamingConventions in configuration, on top of the built-in ones
+/// </summary>
+public class NamingConventionSettings
+{
+    /// <summary>
+    /// Rules checked besides the enabled built-in ones; a rule named like a built-in replaces it
+    /// </summary>
+    public List<NamingRule> Rules { get; set; } = new();
+
+    /// <summary>
+    /// Built-in rules switched on that are off by default, such as test-names
+    /// </summary>
+    public List<string> Enable { get; set; } = new();
+
+    /// <summary>
+    /// Built-in rules switched off
+    /// </summary>
+    public List<string> Disable { get; set; } = new();
+
+    /// <summary>
+    /// Workspace-relative globs of files left unchecked
+    /// </summary>
+    public List<string> Ignore { get; set; } = new();
+}
+
+/// <summary>
+/// A naming rule: which symbols it covers and what their names must look like. A name must satisfy every
+/// requirement given - style, prefix, suffix and pattern.
+/// </summary>
+public class NamingRule
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// What the rule asks for, shown with each violation
+    /// </summary>
+    public string? Description { get; set; }
+
+    /// <summary>
+    /// Languages covered (csharp, go, python, ...); empty for all
+    /// </summary>
+    public List<string> Languages { get; set; } = new();
+
+    /// <summary>
+    /// Symbol kinds covered (class, interface, method, function, field, ...); empty for all
+    /// </summary>
+    public List<string> Kinds { get; set; } = new();
+
+    /// <summary>
+    /// Only symbols with this visibility (public, private, protected, internal)
+    /// </summary>
+    public string? Visibility { get; set; }
+
+    /// <summary>
+    /// Only names matching this regex - ^\p{Lu} selects exported Go identifiers
+    /// </summary>
+    public string? When { get; set; }
+
+    /// <summary>
+    /// true for test functions and methods only, false to leave them out, unset for both
+    /// </summary>
+    public bool? Tests { get; set; }
+
+    /// <summary>
+    /// Workspace-relative globs of the files covered; empty for all
+    /// </summary>
+    public List<string> Paths { get; set; } = new();
+
+    /// <summary>
+    /// Workspace-relative globs of files the rule skips, such as generated code
+    /// </summary>
+    public List<string> Except { get; set; } = new();
+
+    /// <summary>
+    /// PascalCase, camelCase, snake_case or UPPER_SNAKE_CASE, for the name after any prefix and before any suffix
+    /// </summary>
+    public string? Style { get; set; }
+
+    public string? Prefix { get; set; }
+    public string? Suffix { get; set; }
+
+    /// <summary>
+    /// A regex the whole name must match
+    /// </summary>
+    public string? Pattern { get; set; }
+
+    /// <summary>
+    /// Whether the built-in rule is checked without being enabled; rules from configuration always are
+    /// </summary>
+    public bool EnabledByDefault { get; set; } = true;
+}
+
+/// <summary>
+/// A symbol whose name breaks a rule, with the name that would satisfy it when one can be derived
+/// </summary>
+public class NamingViolation
+{
+    public string Rule { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+    public string Language { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Message { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The name restyled and given the required prefix and suffix; null when no mechanical rename satisfies the rule
+    /// </summary>
+    public string? Suggested { get; set; }
+}
+
+/// <summary>
+/// Checks symbol names against naming rules - exported Go identifiers in PascalCase without underscores, C#
+/// interfaces starting with I, test names shaped Test_Subject_Scenario and whatever rules are configured - and
+/// derives the names that would satisfy them
+/// </summary>
+public static class NamingConventions
+{
+    private static readonly Regex Word = new(@"\p{Lu}+(?=\p{Lu}\p{Ll})|\p{Lu}?\p{Ll}+|\p{Lu}+|\p{N}+|\p{Lo}+", RegexOptions.Compiled);
+
+    private static readonly Dictionary<string, Regex> Styles = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ["PascalCase"] = new(@"^\p{Lu}[\p{L}\p{N}]*$", RegexOptions.Compiled),
+        ["camelCase"] = new(@"^\p{Ll}[\p{L}\p{N}]*$", RegexOptions.Compiled),
+        ["snake_case"] = new(@"^\p{Ll}[\p{Ll}\p{N}]*(?:_[\p{Ll}\p{N}]+)*$", RegexOptions.Compiled),
+        ["UPPER_SNAKE_CASE"] = new(@"^\p{Lu}[\p{Lu}\p{N}]*(?:_[\p{Lu}\p{N}]+)*$", RegexOptions.Compiled)
+    };
+
+    /// <summary>
+    /// The rules checked unless disabled, and test-names, checked once enabled
+    /// </summary>
+    public static IReadOnlyList<NamingRule> BuiltInRules { get; } = new List<NamingRule>
+    {
+        new()
+        {
+            Name = "go-exported-names",
+            Description = "Exported Go identifiers are PascalCase without underscores",
+            Languages = { "go" },
+            When = @"^\p{Lu}",
+            Tests = false,
+            Except = { "*.pb.go", "*_string.go", "zz_generated*.go" },
+            Style = "PascalCase"
+        },
+        new()
+        {
+            Name = "csharp-interface-prefix",
+            Description = "C# interfaces start with I followed by a PascalCase name",
+            Languages = { "csharp" },
+            Kinds = { "interface" },
+            Prefix = "I",
+            Style = "PascalCase"
+        },
+        new()
+        {
+            Name = "test-names",
+            Description = "Test functions are named Test_Subject_Scenario",
+            Tests = true,
+            Pattern = @"^Test_[\p{L}\p{N}]+_\w+$",
+            EnabledByDefault = false
+        }
+    };
+
+    /// <summary>
+    /// The built-in rules left on plus the configured ones, which replace built-ins of the same name
+    /// </summary>
+    public static List<NamingRule> RulesFor(NamingConventionSettings settings)
+    {
+        var configured = settings.Rules.Select(r => r.Name).ToHashSet(StringComparer.OrdinalIgnoreCase);
+        return BuiltInRules
+            .Where(r => !configured.Contains(r.Name))
+            .Where(r => r.EnabledByDefault
+                ? !settings.Disable.Contains(r.Name, StringComparer.OrdinalIgnoreCase)
+                : settings.Enable.Contains(r.Name, StringComparer.OrdinalIgnoreCase))
+            .Concat(settings.Rules)
+            .ToList();
+    }
+
+    /// <summary>
+    /// Problems that keep rules from being checked: missing names, duplicates, unknown styles and bad regexes
+    /// </summary>
+    public static List<string> Validate(NamingConventionSettings settings)
+    {
+        var problems = new List<string>();
+        foreach (var rule in settings.Rules)
+        {
+            var label = string.IsNullOrWhiteSpace(rule.Name) ? "A rule" : $"Rule '{rule.Name}'";
+            if (string.IsNullOrWhiteSpace(rule.Name))
+                problems.Add("A rule has no name");
+            if (rule.Style != null && !Styles.ContainsKey(rule.Style))
+                problems.Add($"{label} has unknown style '{rule.Style}' - use {string.Join(", ", Styles.Keys)}");
+            if (rule.Style == null && rule.Prefix == null && rule.Suffix == null && rule.Pattern == null)
+                problems.Add($"{label} requires nothing - give it a style, prefix, suffix or pattern");
+            foreach (var (field, regex) in new[] { ("when", rule.When), ("pattern", rule.Pattern) })
+            {
+                if (regex == null)
+                    continue;
+                try
+                {
+                    _ = new Regex(regex);
+                }
+                catch (ArgumentException ex)
+                {
+                    problems.Add($"{label} has an invalid {field} regex: {ex.Message}");
+                }
+            }
+        }
+        foreach (var duplicate in settings.Rules.GroupBy(r => r.Name, StringComparer.OrdinalIgnoreCase).Where(g => g.Key.Length > 0 && g.Count() > 1))
+        {
+            problems.Add($"Rule '{duplicate.Key}' is declared {duplicate.Count()} times");
+        }
+        return problems;
+    }
+
+    /// <summary>
+    /// Every symbol breaking a rule, by file and line, with workspace-relative paths. <paramref name="tests"/>
+    /// holds the test functions as (relative path, name) pairs, for rules that pick or skip tests.
+    /// </summary>
+    public static List<NamingViolation> Check(
+        string workspacePath,
+        IEnumerable<JulieSymbol> symbols,
+        IReadOnlyList<NamingRule> rules,
+        IReadOnlySet<(string FilePath, string Name)> tests,
+        IReadOnlyList<string>? ignore = null)
+    {
+        var ignored = (ignore ?? Array.Empty<string>()).Select(GlobToRegex).ToList();
+        var compiled = rules.Select(r => new CompiledRule(r)).ToList();
+        var violations = new List<NamingViolation>();
+
+        foreach (var symbol in symbols)
+        {
+            var path = (Path.IsPathRooted(symbol.FilePath) ? Path.GetRelativePath(workspacePath, symbol.FilePath) : symbol.FilePath).Replace('\\', '/');
+            if (ignored.Any(g => g.IsMatch(path)))
+                continue;
+
+            var isTest = tests.Contains((path, symbol.Name));
+            foreach (var rule in compiled.Where(r => r.Covers(symbol, path, isTest)))
+            {
+                if (rule.Accepts(symbol.Name))
+                    continue;
+
+                var suggested = rule.Suggest(symbol.Name);
+                violations.Add(new NamingViolation
+                {
+                    Rule = rule.Rule.Name,
+                    Name = symbol.Name,
+                    Kind = symbol.Kind,
+                    Language = symbol.Language,
+                    FilePath = path,
+                    Line = symbol.StartLine,
+                    Message = rule.Rule.Description ?? Describe(rule.Rule),
+                    Suggested = suggested != null && suggested != symbol.Name && rule.Accepts(suggested) ? suggested : null
+                });
+            }
+        }
+
+        return violations.OrderBy(v => v.FilePath, StringComparer.Ordinal).ThenBy(v => v.Line).ThenBy(v => v.Rule).ToList();
+    }
+
+    /// <summary>
+    /// The test functions and methods declared in the files, as (path, name) pairs for <see cref="Check"/>
+    /// </summary>
+    public static HashSet<(string FilePath, string Name)> TestsIn(IEnumerable<(string Path, string? Content)> files) =>
+        files.Where(f => !string.IsNullOrEmpty(f.Content))
+            .SelectMany(f => TestDiscovery.FindTests(f.Path, f.Content!).Select(t => (f.Path, t.Name)))
+            .ToHashSet();
+
+    /// <summary>
+    /// <paramref name="name"/> rewritten in a style, keeping acronyms upper case in PascalCase and camelCase
+    /// (HTTP_server becomes HTTPServer). Leading and trailing underscores are kept.
+    /// </summary>
+    public static string ToStyle(string name, string style)
+    {
+        var leading = name.Length - name.TrimStart('_').Length;
+        var trailing = name.Length - name.TrimEnd('_').Length;
+        var words = Word.Matches(name).Select(m => m.Value).ToList();
+        if (words.Count == 0)
+            return name;
+
+        var styled = style.ToLowerInvariant() switch
+        {
+            "pascalcase" => string.Concat(words.Select(Capitalize)),
+            "camelcase" => (IsAcronym(words[0]) && words.Count > 1 ? words[0].ToLowerInvariant() : LowerFirst(words[0])) + string.Concat(words.Skip(1).Select(Capitalize)),
+            "snake_case" => string.Join("_", words.Select(w => w.ToLowerInvariant())),
+            "upper_snake_case" => string.Join("_", words.Select(w => w.ToUpperInvariant())),
+            _ => name.Trim('_')
+        };
+        return new string('_', leading) + styled + new string('_', trailing);
+    }
+
+    private static string Capitalize(string word) =>
+        IsAcronym(word) ? word : char.ToUpperInvariant(word[0]) + word[1..].ToLowerInvariant();
+
+    private static string LowerFirst(string word) => char.ToLowerInvariant(word[0]) + word[1..];
+
+    private static bool IsAcronym(string word) => word.Length > 1 && word.All(c => !char.IsLetter(c) || char.IsUpper(c));
+
+    private static string Describe(NamingRule rule)
+    {
+        var parts = new List<string>();
+        if (rule.Prefix != null)
+            parts.Add($"start with '{rule.Prefix}'");
+        if (rule.Suffix != null)
+            parts.Add($"end with '{rule.Suffix}'");
+        if (rule.Style != null)
+            parts.Add($"be {rule.Style}");
+        if (rule.Pattern != null)
+            parts.Add($"match /{rule.Pattern}/");
+        return $"Names must {string.Join(" and ", parts)}";
+    }
+
+    // "*.pb.go" matches file names anywhere; patterns with a slash match the relative path, and a trailing
+    // slash ("gen/") covers everything below the directory
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        if (normalized.EndsWith('/'))
+            normalized += "**";
+
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    private sealed class CompiledRule
+    {
+        private readonly Regex? _when;
+        private readonly Regex? _pattern;
+        private readonly Regex? _style;
+        private readonly List<Regex> _paths;
+        private readonly List<Regex> _except;
+
+        public CompiledRule(NamingRule rule)
+        {
+            Rule = rule;
+            _when = rule.When == null ? null : new Regex(rule.When);
+            _pattern = rule.Pattern == null ? null : new Regex(rule.Pattern);
+            _style = rule.Style != null && Styles.TryGetValue(rule.Style, out var style) ? style : null;
+            _paths = rule.Paths.Select(GlobToRegex).ToList();
+            _except = rule.Except.Select(GlobToRegex).ToList();
+        }
+
+        public NamingRule Rule { get; }
+
+        public bool Covers(JulieSymbol symbol, string path, bool isTest) =>
+            (Rule.Languages.Count == 0 || Rule.Languages.Contains(symbol.Language, StringComparer.OrdinalIgnoreCase))
+            && (Rule.Kinds.Count == 0 || Rule.Kinds.Contains(symbol.Kind, StringComparer.OrdinalIgnoreCase))
+            && (Rule.Visibility == null || string.Equals(Rule.Visibility, symbol.Visibility, StringComparison.OrdinalIgnoreCase))
+            && (Rule.Tests == null || Rule.Tests == isTest)
+            && (_when == null || _when.IsMatch(symbol.Name))
+            && (_paths.Count == 0 || _paths.Any(p => p.IsMatch(path)))
+            && !_except.Any(p => p.IsMatch(path));
+
+        public bool Accepts(string name)
+        {
+            if (_pattern != null && !_pattern.IsMatch(name))
+                return false;
+
+            var stem = name;
+            if (Rule.Prefix != null)
+            {
+                if (!stem.StartsWith(Rule.Prefix, StringComparison.Ordinal))
+                    return false;
+                stem = stem[Rule.Prefix.Length..];
+            }
+            if (Rule.Suffix != null)
+            {
+                if (!stem.EndsWith(Rule.Suffix, StringComparison.Ordinal))
+                    return false;
+                stem = stem[..^Rule.Suffix.Length];
+            }
+            return _style == null || stem.Length > 0 && _style.IsMatch(stem);
+        }
+
+        /// <summary>
+        /// The name with its stem restyled and the prefix and suffix added where missing. An existing prefix only
+        /// counts when the rest is styled right, so the interface Item becomes IItem rather than Item.
+        /// </summary>
+        public string? Suggest(string name)
+        {
+            var stem = name;
+            if (Rule.Prefix != null && stem.StartsWith(Rule.Prefix, StringComparison.Ordinal) && stem.Length > Rule.Prefix.Length
+                && (_style == null || _style.IsMatch(stem[Rule.Prefix.Length..])))
+            {
+                stem = stem[Rule.Prefix.Length..];
+            }
+            if (Rule.Suffix != null && stem.EndsWith(Rule.Suffix, StringComparison.Ordinal) && stem.Length > Rule.Suffix.Length)
+                stem = stem[..^Rule.Suffix.Length];
+            if (Rule.Style != null)
+                stem = ToStyle(stem, Rule.Style);
+
+            return Rule.Prefix + stem + Rule.Suffix;
+        }
+    }
+}
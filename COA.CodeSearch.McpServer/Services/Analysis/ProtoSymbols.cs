@@ -0,0 +1,351 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Protocol Buffers declarations read from .proto files: the package, messages (nested ones parented to their
+/// message), fields (oneof and map fields included), enums and their values, services and their RPC methods.
+/// <see cref="GeneratedNames"/> gives the Go and C# identifiers protoc generates for each, so references in
+/// generated and calling code can be traced back to the definition.
+/// </summary>
+public static class ProtoSymbols
+{
+    private static readonly Regex Package = new(@"^\s*package\s+(?<name>[\w.]+)\s*;", RegexOptions.Compiled);
+    private static readonly Regex Block = new(@"^\s*(?<keyword>message|enum|service|oneof|extend)\s+(?<name>[\w.]+)\s*\{?", RegexOptions.Compiled);
+    private static readonly Regex Rpc = new(
+        @"^\s*rpc\s+(?<name>\w+)\s*\(\s*(?:stream\s+)?(?<request>[\w.]+)\s*\)\s*returns\s*\(\s*(?:stream\s+)?(?<response>[\w.]+)\s*\)", RegexOptions.Compiled);
+    private static readonly Regex Field = new(
+        @"^\s*(?:(?:optional|required|repeated)\s+)?(?<type>map\s*<\s*[\w.]+\s*,\s*[\w.]+\s*>|\.?[\w.]+)\s+(?<name>\w+)\s*=\s*\d+", RegexOptions.Compiled);
+    private static readonly Regex EnumValue = new(@"^\s*(?<name>\w+)\s*=\s*-?(?:0x[\da-fA-F]+|\d+)", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> NotFields = new(StringComparer.Ordinal)
+    {
+        "option", "reserved", "extensions", "syntax", "edition", "import", "package"
+    };
+
+    /// <summary>
+    /// True for Protobuf definitions
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".proto", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a .proto file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".proto");
+        var found = new List<Found>();
+
+        // Open blocks, innermost last; oneof and extend blocks hold fields of the message around them
+        var open = new List<(Found? Declaration, string Keyword, int Depth)>();
+        var depth = 0;
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var line = masked[i];
+            var container = open.LastOrDefault(b => b.Keyword is "message" or "enum" or "service").Declaration;
+            var keyword = open.Count == 0 ? null : open[^1].Keyword;
+            Found? declared = null;
+            Match match;
+
+            if ((match = Package.Match(line)).Success && open.Count == 0)
+            {
+                declared = Declare(found, "namespace", match, lines, i, null);
+            }
+            else if ((match = Block.Match(line)).Success && keyword is null or "message")
+            {
+                var blockKeyword = match.Groups["keyword"].Value;
+                declared = blockKeyword switch
+                {
+                    "message" => Declare(found, "class", match, lines, i, container),
+                    "enum" => Declare(found, "enum", match, lines, i, container),
+                    "service" => Declare(found, "interface", match, lines, i, null),
+                    _ => null
+                };
+                var column = line.IndexOf('{', match.Index);
+                if (column >= 0)
+                {
+                    open.Add((declared, blockKeyword, depth));
+                    depth++;
+                    line = new string(' ', column + 1) + line[(column + 1)..];
+
+                    // message GetUserRequest { string user_id = 1; }
+                    if (blockKeyword == "message" && (match = Field.Match(line)).Success)
+                        Declare(found, "field", match, lines, i, declared);
+                }
+                else
+                {
+                    // The brace is on a following line; the block opens there
+                    open.Add((declared, blockKeyword, -1));
+                }
+            }
+            else if ((match = Rpc.Match(line)).Success && keyword == "service")
+            {
+                declared = Declare(found, "method", match, lines, i, container);
+
+                // RPCs with an options body end at its closing brace, the others at their semicolon
+                if (line.IndexOf('{', match.Index + match.Length) >= 0)
+                    open.Add((declared, "rpc", -1));
+            }
+            else if (keyword == "enum" && (match = EnumValue.Match(line)).Success && !NotFields.Contains(match.Groups["name"].Value))
+            {
+                declared = Declare(found, "enum_member", match, lines, i, container);
+            }
+            else if (keyword is "message" or "oneof" or "extend" && (match = Field.Match(line)).Success
+                     && !NotFields.Contains(match.Groups["type"].Value))
+            {
+                declared = Declare(found, "field", match, lines, i, container);
+            }
+
+            foreach (var c in line)
+            {
+                if (c == '{')
+                {
+                    var pending = open.FindLastIndex(b => b.Depth == -1);
+                    if (pending >= 0)
+                        open[pending] = (open[pending].Declaration, open[pending].Keyword, depth);
+                    else
+                        open.Add((null, "body", depth));
+                    depth++;
+                }
+                else if (c == '}' && depth > 0)
+                {
+                    depth--;
+                    var closed = open.FindLastIndex(b => b.Depth == depth);
+                    if (closed >= 0)
+                    {
+                        if (open[closed].Declaration is { } block)
+                            block.EndLine = i + 1;
+                        open.RemoveAt(closed);
+                    }
+                }
+            }
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in found)
+        {
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = declaration.Kind,
+                Language = "protobuf",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = Math.Max(declaration.Line, declaration.EndLine),
+                EndColumn = lines[Math.Max(declaration.Line, declaration.EndLine) - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = "public"
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a .proto file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The identifiers protoc-gen-go, protoc-gen-go-grpc and the C# generators emit for a declaration, given the
+    /// names of the messages it is nested in, outermost first. A field user_id is UserId and GetUserId in Go and
+    /// UserId in C#; a message Order.Line is Order_Line in Go and Line in C#; a service Users generates
+    /// UsersClient, UsersServer, NewUsersClient, RegisterUsersServer and UnimplementedUsersServer in Go and
+    /// UsersClient and UsersBase in C#; an enum value of a nested enum is prefixed with the message's Go name.
+    /// </summary>
+    public static List<(string Language, string Name)> GeneratedNames(string kind, string name, IReadOnlyList<string> containers)
+    {
+        var names = new List<(string, string)>();
+        var goPath = string.Join("_", containers.Append(name).Select(GoCamelCase));
+        switch (kind)
+        {
+            case "class":
+                names.Add(("go", goPath));
+                names.Add(("csharp", PascalCase(name)));
+                break;
+            case "enum":
+                names.Add(("go", goPath));
+                names.Add(("csharp", PascalCase(name)));
+                break;
+            case "enum_member":
+                // Go scopes values like C++: by the message around the enum, or the enum itself when top-level
+                var scope = containers.Count > 1 ? containers.Take(containers.Count - 1) : containers;
+                names.Add(("go", $"{string.Join("_", scope.Select(GoCamelCase))}_{name}"));
+                var enumName = containers.Count > 0 ? containers[^1] : string.Empty;
+                var value = name.StartsWith(ScreamingSnake(enumName) + "_", StringComparison.Ordinal)
+                    ? name[(ScreamingSnake(enumName).Length + 1)..]
+                    : name;
+                names.Add(("csharp", PascalCase(value.ToLowerInvariant())));
+                break;
+            case "field":
+                names.Add(("go", GoCamelCase(name)));
+                names.Add(("go", "Get" + GoCamelCase(name)));
+                names.Add(("csharp", PascalCase(name)));
+                break;
+            case "interface":
+                names.Add(("go", name + "Client"));
+                names.Add(("go", name + "Server"));
+                names.Add(("go", $"New{name}Client"));
+                names.Add(("go", $"Register{name}Server"));
+                names.Add(("go", $"Unimplemented{name}Server"));
+                names.Add(("csharp", name + "Client"));
+                names.Add(("csharp", name + "Base"));
+                break;
+            case "method":
+                names.Add(("go", name));
+                names.Add(("csharp", name));
+                names.Add(("csharp", name + "Async"));
+                break;
+        }
+        return names.Distinct().ToList();
+    }
+
+    /// <summary>
+    /// Names a generated identifier may have had in the .proto file: UsersClient → Users, GetUserId → UserId
+    /// and user_id, Order_Line → Line, Status_STATUS_ACTIVE → STATUS_ACTIVE. Candidates still have to be confirmed with
+    /// <see cref="GeneratedNames"/>.
+    /// </summary>
+    public static List<string> CandidateProtoNames(string generated)
+    {
+        var candidates = new List<string> { generated };
+        void Add(string name)
+        {
+            if (name.Length > 0 && !candidates.Contains(name))
+                candidates.Add(name);
+        }
+
+        var stripped = Regex.Replace(generated, @"^(?:New|Register|Unimplemented)(?=[A-Z])", string.Empty);
+        Add(Regex.Replace(stripped, @"(?<=\w)(?:Client|Server|Base|Async)$", string.Empty));
+        var field = Regex.Replace(generated, @"^Get(?=[A-Z])", string.Empty);
+        Add(field);
+        if (!generated.Contains('_'))
+            Add(Regex.Replace(field, @"(?<=[a-z0-9])(?=[A-Z])", "_").ToLowerInvariant());
+        for (var underscore = generated.IndexOf('_'); underscore >= 0; underscore = generated.IndexOf('_', underscore + 1))
+            Add(generated[(underscore + 1)..]);
+        if (Regex.IsMatch(generated, "^[A-Z][a-z0-9]+(?:[A-Z][a-z0-9]*)*$"))
+            Add(ScreamingSnake(generated));
+        return candidates;
+    }
+
+    /// <summary>
+    /// Lines of a .proto file that use a message or enum as a field, map value or RPC type, by name or
+    /// qualified name; declarations themselves are not uses
+    /// </summary>
+    public static List<int> TypeUses(string content, string name)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(lines, ".proto");
+        var use = new Regex($@"(?:^\s*(?:(?:optional|required|repeated)\s+)?|[<,(]\s*|\bstream\s+)\.?(?:[\w]+\.)*{Regex.Escape(name)}\b(?!\s*=)");
+        var result = new List<int>();
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (Block.IsMatch(masked[i]) || !use.IsMatch(masked[i]))
+                continue;
+            result.Add(i + 1);
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// protoc-gen-go's name mangling: underscores dropped and the letter after each capitalized, first letter upper
+    /// </summary>
+    public static string GoCamelCase(string name)
+    {
+        var builder = new StringBuilder();
+        var upper = true;
+        foreach (var c in name)
+        {
+            if (c == '_')
+            {
+                if (builder.Length == 0)
+                    builder.Append('X');
+                upper = true;
+                continue;
+            }
+            builder.Append(upper && char.IsLower(c) ? char.ToUpperInvariant(c) : c);
+            upper = char.IsDigit(c);
+        }
+        return builder.ToString();
+    }
+
+    private static string PascalCase(string name) =>
+        string.Concat(name.Split('_', StringSplitOptions.RemoveEmptyEntries).Select(p => char.ToUpperInvariant(p[0]) + p[1..]));
+
+    private static string ScreamingSnake(string name) =>
+        Regex.Replace(name, @"(?<=[a-z0-9])(?=[A-Z])", "_").ToUpperInvariant();
+
+    private static Found Declare(List<Found> found, string kind, Match match, string[] lines, int index, Found? container)
+    {
+        var name = match.Groups["name"];
+        var declaration = new Found
+        {
+            Kind = kind,
+            Name = name.Value,
+            Line = index + 1,
+            Column = name.Index,
+            EndLine = index + 1,
+            Signature = Signature(lines[index]),
+            Container = container
+        };
+        found.Add(declaration);
+        return declaration;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = line.Trim();
+        var comment = signature.IndexOf("//", StringComparison.Ordinal);
+        if (comment > 0)
+            signature = signature[..comment].TrimEnd();
+        signature = Regex.Replace(signature, @"\s*\{\s*\}?$", string.Empty);
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    /// <summary>
+    /// The <c>//</c> comment lines right above a declaration
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var end = index - 1;
+        if (end < 0 || !lines[end].TrimStart().StartsWith("//", StringComparison.Ordinal))
+            return null;
+
+        var i = end;
+        while (i > 0 && lines[i - 1].TrimStart().StartsWith("//", StringComparison.Ordinal))
+            i--;
+        return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"protobuf:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+        public string Name { get; init; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; set; }
+        public string Signature { get; init; } = string.Empty;
+        public Found? Container { get; init; }
+    }
+}
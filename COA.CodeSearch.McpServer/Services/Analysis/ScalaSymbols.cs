@@ -0,0 +1,546 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Scala declarations read from source: classes and case classes with their constructor properties, traits,
+/// objects and case objects, Scala 3 enums and their cases, methods and auxiliary constructors, vals and vars,
+/// type members, implicit classes, defs and vals, given instances and extension methods. Bodies may use braces
+/// or Scala 3 indentation. Locals inside method bodies and initializers are left out. julie-codesearch does not
+/// extract Scala, so these are a Scala file's only symbols.
+/// </summary>
+public static class ScalaSymbols
+{
+    private const string Prefix = @"^\s*(?:@[\w.]+(?:\([^()]*\))?\s+)*";
+    private const string Modifiers = @"(?<modifiers>(?:(?:(?:private|protected)(?:\[\w+\])?|final|sealed|abstract|implicit|lazy|override|case|inline|opaque|transparent|open|infix)\s+)*)";
+    private const string Name = @"(?<name>[\p{L}_][\w]*(?:_[^\w\s\[(:]+)?|`[^`]+`|[-+*/%=<>!&|^~?:#\\]+)";
+
+    private static readonly Regex TypeDeclaration = new($@"{Prefix}{Modifiers}(?<keyword>class|trait|object|enum)\s+(?<name>\w+|`[^`]+`)", RegexOptions.Compiled);
+    private static readonly Regex Function = new($@"{Prefix}{Modifiers}def\s+{Name}", RegexOptions.Compiled);
+    private static readonly Regex Value = new($@"{Prefix}{Modifiers}(?<keyword>val|var)\s+(?<name>[\p{{L}}_]\w*|`[^`]+`)\s*(?=[:=,]|$)", RegexOptions.Compiled);
+    private static readonly Regex TypeMember = new($@"{Prefix}{Modifiers}type\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex Given = new(
+        $@"{Prefix}{Modifiers}given\s+(?:(?<name>[\p{{L}}_]\w*)\s*(?:\[[^\]]*\]\s*)?(?:\([^()]*\)\s*)*:\s*)?(?<type>[^=]+?)\s*(?<body>with\b|=|$)", RegexOptions.Compiled);
+    private static readonly Regex Extension = new(@"^\s*extension\s*(?:\[[^\]]*\]\s*)?\(\s*\w+\s*:\s*(?<type>[^)]+)\)", RegexOptions.Compiled);
+    private static readonly Regex Case = new(@"^\s*case\s+(?!class\b|object\b)", RegexOptions.Compiled);
+    private static readonly Regex CaseName = new(@"^(?<name>[\p{L}_]\w*|`[^`]+`)\s*(?:\(|\[|extends\b|,|$)", RegexOptions.Compiled);
+    private static readonly Regex ConstructorParameter = new(
+        $@"(?:^|[(,])\s*(?:@[\w.]+(?:\([^()]*\))?\s+)*{Modifiers}(?:(?<keyword>val|var)\s+)?(?<name>[\p{{L}}_]\w*|`[^`]+`)\s*:", RegexOptions.Compiled);
+    private static readonly Regex ContextClause = new(@"\(\s*(?:using|implicit)\s+(?<parameters>[^()]*(?:\([^()]*\)[^()]*)*)\)", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Scala sources and worksheets
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".scala", StringComparison.OrdinalIgnoreCase)
+        || filePath.EndsWith(".sc", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a Scala file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(BlankMultilineStrings(lines), ".scala");
+        var found = new List<Found>();
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (!string.IsNullOrWhiteSpace(masked[i]))
+                found.AddRange(Read(lines, masked, i));
+        }
+
+        // Declarations inside method bodies, initializers and given definitions are locals
+        var kept = new List<Found>();
+        foreach (var declaration in found)
+        {
+            var container = Innermost(found, declaration);
+            if (container != null && container.Kind is not ("class" or "interface" or "enum" or "extension"))
+                continue;
+            if (declaration.Kind == "enum_member" && container?.Kind != "enum")
+                continue;
+            declaration.Container = container;
+            kept.Add(declaration);
+        }
+
+        // Constructor parameters of case classes, and val/var parameters of other classes, are properties
+        foreach (var type in kept.Where(d => d.Keyword == "class").ToList())
+        {
+            kept.AddRange(ConstructorProperties(lines, masked, type));
+        }
+        foreach (var constructor in kept.Where(d => d.Kind == "constructor" && d.Container != null))
+        {
+            constructor.Name = constructor.Container!.Name;
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in kept.OrderBy(d => d.Line).ThenBy(d => d.Column))
+        {
+            var kind = declaration.Kind switch
+            {
+                "function" when declaration.Container != null => "method",
+                "variable" when declaration.Container != null => "property",
+                _ => declaration.Kind
+            };
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = kind,
+                Language = "scala",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = declaration.EndLine,
+                EndColumn = lines[declaration.EndLine - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = declaration.Visibility
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Scala file with the declarations not yet indexed added and parents filled in.
+    /// Indexed symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// Whether a symbol takes part in implicit resolution: an implicit class, def, val or object, or a given instance
+    /// </summary>
+    public static bool IsImplicit(JulieSymbol symbol) =>
+        symbol.Language == "scala" && symbol.Signature != null
+        && Regex.IsMatch(symbol.Signature, $@"{Prefix}(?:(?:(?:private|protected)(?:\[\w+\])?|final|override|lazy|inline|transparent)\s+)*(?:implicit|given)\b");
+
+    /// <summary>
+    /// The parameter types of a signature's <c>using</c> and <c>implicit</c> clauses (SparkSession and Encoder[T]
+    /// for <c>def load[T](path: String)(using spark: SparkSession, enc: Encoder[T])</c>) - what a caller must
+    /// have in implicit scope
+    /// </summary>
+    public static List<string> ContextParametersOf(string? signature)
+    {
+        var types = new List<string>();
+        if (string.IsNullOrEmpty(signature))
+            return types;
+
+        foreach (Match clause in ContextClause.Matches(signature))
+        {
+            foreach (var parameter in SplitTopLevel(clause.Groups["parameters"].Value))
+            {
+                var colon = parameter.IndexOf(':');
+                var type = (colon < 0 ? parameter : parameter[(colon + 1)..]).Trim();
+                if (type.Length > 0)
+                    types.Add(type);
+            }
+        }
+        return types;
+    }
+
+    private static IEnumerable<Found> Read(string[] lines, string[] masked, int index)
+    {
+        var line = masked[index];
+        Match match;
+        string kind;
+        var keyword = string.Empty;
+        string? name = null;
+
+        if ((match = TypeDeclaration.Match(line)).Success)
+        {
+            keyword = match.Groups["keyword"].Value;
+            kind = keyword switch
+            {
+                "trait" => "interface",
+                "object" or "class" => "class",
+                _ => "enum"
+            };
+        }
+        else if ((match = Function.Match(line)).Success)
+        {
+            kind = match.Groups["name"].Value == "this" ? "constructor" : "function";
+        }
+        else if ((match = Value.Match(line)).Success)
+        {
+            kind = "variable";
+        }
+        else if ((match = TypeMember.Match(line)).Success)
+        {
+            kind = "type";
+        }
+        else if ((match = Given.Match(line)).Success)
+        {
+            kind = match.Groups["body"].Value == "with" ? "class" : "variable";
+            if (!match.Groups["name"].Success)
+                name = "given_" + Regex.Replace(match.Groups["type"].Value, @"\W+", "_").Trim('_');
+        }
+        else if ((match = Extension.Match(line)).Success)
+        {
+            return ExtensionDeclarations(lines, masked, index, match);
+        }
+        else if ((match = Case.Match(line)).Success)
+        {
+            return Cases(lines, masked, index, match.Length);
+        }
+        else
+        {
+            return Enumerable.Empty<Found>();
+        }
+
+        var nameGroup = match.Groups["name"];
+        var column = nameGroup.Success ? nameGroup.Index : match.Index + line.Length - line.TrimStart().Length;
+        return new[]
+        {
+            new Found
+            {
+                Kind = kind,
+                Keyword = keyword,
+                Name = name ?? lines[index].Substring(nameGroup.Index, nameGroup.Length).Trim('`'),
+                Line = index + 1,
+                Column = column,
+                EndLine = EndOf(masked, index, column) + 1,
+                Signature = Signature(lines[index]),
+                Visibility = VisibilityOf(match.Groups["modifiers"].Value)
+            }
+        };
+    }
+
+    /// <summary>
+    /// An extension block, named after the type it extends so its methods are found as members of that type,
+    /// and the method of a single-line <c>extension (s: String) def toSlug</c>
+    /// </summary>
+    private static IEnumerable<Found> ExtensionDeclarations(string[] lines, string[] masked, int index, Match match)
+    {
+        var type = match.Groups["type"].Value.Trim();
+        var bracket = type.IndexOf('[');
+        var rest = masked[index][(match.Index + match.Length)..];
+
+        // A Scala 3 extension with nothing after its parameter is followed by indented methods
+        var extension = new Found
+        {
+            Kind = "extension",
+            Name = bracket < 0 ? type : type[..bracket],
+            Line = index + 1,
+            Column = Indent(masked[index]),
+            EndLine = (string.IsNullOrWhiteSpace(rest) ? IndentedEnd(masked, index, Indent(masked[index])) : EndOf(masked, index, match.Length)) + 1,
+            Signature = Signature(lines[index]),
+            Visibility = "public"
+        };
+        yield return extension;
+
+        if (Function.Match(rest) is { Success: true } function)
+        {
+            var column = match.Index + match.Length + function.Groups["name"].Index;
+            yield return new Found
+            {
+                Kind = "function",
+                Name = lines[index].Substring(column, function.Groups["name"].Length).Trim('`'),
+                Line = index + 1,
+                Column = column,
+                EndLine = extension.EndLine,
+                Signature = Signature(lines[index][(match.Index + match.Length)..]),
+                Visibility = VisibilityOf(function.Groups["modifiers"].Value)
+            };
+        }
+    }
+
+    /// <summary>
+    /// The cases one <c>case</c> line of a Scala 3 enum lists: <c>case Red, Green</c> or <c>case Circle(r: Double)</c>
+    /// </summary>
+    private static IEnumerable<Found> Cases(string[] lines, string[] masked, int index, int start)
+    {
+        var end = EndOf(masked, index, start);
+        var depth = 0;
+        var pending = true;
+        for (var i = index; i <= end; i++)
+        {
+            var text = masked[i];
+            for (var c = i == index ? start : 0; c < text.Length; c++)
+            {
+                var ch = text[c];
+                if (pending && depth == 0 && !char.IsWhiteSpace(ch))
+                {
+                    pending = false;
+                    if (CaseName.Match(text[c..]) is { Success: true } entry)
+                    {
+                        yield return new Found
+                        {
+                            Kind = "enum_member",
+                            Name = entry.Groups["name"].Value.Trim('`'),
+                            Line = i + 1,
+                            Column = c,
+                            EndLine = end + 1,
+                            Signature = lines[i].Trim(),
+                            Visibility = "public"
+                        };
+                    }
+                }
+                if (ch is '(' or '[' or '{')
+                    depth++;
+                else if (ch is ')' or ']' or '}')
+                    depth--;
+                else if (ch == ',' && depth == 0)
+                    pending = true;
+            }
+        }
+    }
+
+    /// <summary>
+    /// The constructor parameters of a class that declare properties: every parameter of a case class's first
+    /// parameter list, and val and var parameters of other classes
+    /// </summary>
+    private static IEnumerable<Found> ConstructorProperties(string[] lines, string[] masked, Found type)
+    {
+        var header = masked[type.Line - 1];
+        var after = type.Column + type.Name.Length;
+        if (Regex.Match(header[after..], @"^\s*(?:\[[^\]]*\]\s*)?(?:(?:private|protected)(?:\[\w+\])?\s*)?\(") is not { Success: true } opening)
+            yield break;
+
+        var isCase = Regex.IsMatch(header[..type.Column], @"\bcase\s+class\s+$");
+        var open = after + opening.Length - 1;
+        var depth = 0;
+        for (var i = type.Line - 1; i < type.EndLine; i++)
+        {
+            var start = i == type.Line - 1 ? open : 0;
+            var text = masked[i];
+            var close = text.Length;
+            for (var c = start; c < text.Length; c++)
+            {
+                if (text[c] is '(' or '[')
+                    depth++;
+                else if (text[c] is ')' or ']' && --depth == 0)
+                {
+                    close = c;
+                    break;
+                }
+            }
+
+            var parameters = ConstructorParameter.Matches(text[..close], start);
+            for (var p = 0; p < parameters.Count; p++)
+            {
+                var parameter = parameters[p];
+                if (!isCase && !parameter.Groups["keyword"].Success || parameter.Groups["name"].Value is "using" or "implicit")
+                    continue;
+
+                var name = parameter.Groups["name"];
+                var signatureEnd = p + 1 < parameters.Count ? parameters[p + 1].Index : close;
+                yield return new Found
+                {
+                    Kind = "property",
+                    Name = lines[i].Substring(name.Index, name.Length).Trim('`'),
+                    Line = i + 1,
+                    Column = name.Index,
+                    EndLine = i + 1,
+                    Signature = lines[i][parameter.Index..signatureEnd].Trim().TrimStart('(', ',').Trim().TrimEnd(','),
+                    Visibility = VisibilityOf(parameter.Groups["modifiers"].Value),
+                    Container = type
+                };
+            }
+            if (close < text.Length)
+                yield break;
+        }
+    }
+
+    /// <summary>
+    /// The innermost other declaration whose extent holds <paramref name="declaration"/>
+    /// </summary>
+    private static Found? Innermost(List<Found> found, Found declaration) =>
+        found.Where(d => d != declaration && d.Kind != "enum_member" && d.Line <= declaration.Line && d.EndLine >= declaration.Line
+                && (d.Line < declaration.Line || d.Column < declaration.Column))
+            .OrderByDescending(d => d.Line)
+            .ThenByDescending(d => d.Column)
+            .FirstOrDefault();
+
+    /// <summary>
+    /// The line (0-based) ending a declaration: its matching brace, the last line indented under a header
+    /// ending in =, :, with or =>, or the end of a header or expression with no body
+    /// </summary>
+    private static int EndOf(string[] masked, int index, int column)
+    {
+        var braces = 0;
+        var parens = 0;
+        var opened = false;
+        for (var i = index; i < masked.Length; i++)
+        {
+            var text = masked[i];
+            for (var c = i == index ? column : 0; c < text.Length; c++)
+            {
+                switch (text[c])
+                {
+                    case '{':
+                        braces++;
+                        opened = true;
+                        break;
+                    case '}':
+                        braces--;
+                        break;
+                    case '(' or '[':
+                        parens++;
+                        break;
+                    case ')' or ']':
+                        parens--;
+                        break;
+                }
+                if (opened && braces == 0)
+                    return i;
+            }
+            if (opened || parens > 0)
+                continue;
+
+            // Parents, derives clauses and chained calls on the next line still belong to the declaration
+            var next = NextCode(masked, i);
+            if (next != null && (next.StartsWith('{') || next.StartsWith('.') && !next.StartsWith("..", StringComparison.Ordinal)
+                || Regex.IsMatch(next, @"^(?:extends|with|derives)\b")))
+                continue;
+
+            var trimmed = text.TrimEnd();
+            if (trimmed.EndsWith('=') || trimmed.EndsWith(':') || trimmed.EndsWith("=>", StringComparison.Ordinal) || Regex.IsMatch(trimmed, @"\bwith$"))
+                return IndentedEnd(masked, i, Indent(masked[index]));
+            return i;
+        }
+        return masked.Length - 1;
+    }
+
+    /// <summary>
+    /// The last line (0-based) of an indented body: lines indented deeper than the header, and an
+    /// <c>end</c> marker level with it
+    /// </summary>
+    private static int IndentedEnd(string[] masked, int index, int indent)
+    {
+        var last = index;
+        for (var i = index + 1; i < masked.Length; i++)
+        {
+            var code = masked[i].Trim();
+            if (code.Length == 0)
+                continue;
+            var depth = Indent(masked[i]);
+            if (depth > indent)
+            {
+                last = i;
+                continue;
+            }
+            if (depth == indent && Regex.IsMatch(code, @"^end\b"))
+                return i;
+            break;
+        }
+        return last;
+    }
+
+    private static int Indent(string line) => line.Length - line.TrimStart().Length;
+
+    private static string? NextCode(string[] masked, int index)
+    {
+        for (var i = index + 1; i < masked.Length; i++)
+        {
+            var code = masked[i].Trim();
+            if (code.Length > 0)
+                return code;
+        }
+        return null;
+    }
+
+    private static IEnumerable<string> SplitTopLevel(string text)
+    {
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            if (text[i] is '(' or '[')
+                depth++;
+            else if (text[i] is ')' or ']')
+                depth--;
+            else if (text[i] == ',' && depth == 0)
+            {
+                yield return text[start..i];
+                start = i + 1;
+            }
+        }
+        yield return text[start..];
+    }
+
+    /// <summary>
+    /// Lines inside <c>"""</c> string literals blanked, so their text is not read as declarations
+    /// </summary>
+    private static string[] BlankMultilineStrings(string[] lines)
+    {
+        var result = lines.ToArray();
+        var inString = false;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var delimiters = Regex.Matches(lines[i], "\"\"\"").Count;
+            if (inString && delimiters == 0)
+                result[i] = new string(' ', lines[i].Length);
+            if (delimiters % 2 == 1)
+                inString = !inString;
+        }
+        return result;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = line.Trim();
+        if (signature.EndsWith('{'))
+            signature = signature[..^1].TrimEnd();
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    private static string VisibilityOf(string modifiers) =>
+        Regex.Match(modifiers, @"\b(?<visibility>private|protected)\b") is { Success: true } visibility
+            ? visibility.Groups["visibility"].Value
+            : "public";
+
+    /// <summary>
+    /// The Scaladoc block right above a declaration, skipping annotation lines
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var i = index - 1;
+        while (i >= 0 && lines[i].TrimStart().StartsWith('@'))
+            i--;
+        if (i < 0 || !lines[i].TrimEnd().EndsWith("*/", StringComparison.Ordinal))
+            return null;
+
+        var end = i;
+        while (i >= 0 && !lines[i].TrimStart().StartsWith("/**", StringComparison.Ordinal))
+        {
+            if (lines[i].TrimStart().StartsWith("/*", StringComparison.Ordinal))
+                return null;
+            i--;
+        }
+        return i < 0 ? null : string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"scala:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+
+        /// <summary>
+        /// class, trait, object or enum for type declarations; empty for other declarations
+        /// </summary>
+        public string Keyword { get; init; } = string.Empty;
+
+        public string Name { get; set; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; init; }
+        public string Signature { get; init; } = string.Empty;
+        public string Visibility { get; init; } = "public";
+        public Found? Container { get; set; }
+    }
+}
@@ -0,0 +1,291 @@
+using System.Text;
+using COA.CodeSearch.McpServer.Services.Lucene;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Lightweight lexical classifier for result snippets, so clients can syntax-highlight hits without
+/// re-parsing them. It works line by line on a few language families chosen by file extension; only block
+/// comments carry state across lines. Good enough for colouring, not a parser.
+/// </summary>
+public static class SnippetHighlighter
+{
+    public const string Plain = "plain";
+    public const string Ansi = "ansi";
+    public const string Classified = "classified";
+
+    public static readonly string[] Formats = { Plain, Ansi, Classified };
+
+    private const string AnsiReset = "\u001b[0m";
+    private const string OperatorChars = "+-*/%=<>!&|^~?:";
+
+    private static readonly Dictionary<string, string> AnsiColors = new()
+    {
+        [SnippetTokenKinds.Keyword] = "\u001b[35m",
+        [SnippetTokenKinds.Type] = "\u001b[36m",
+        [SnippetTokenKinds.Function] = "\u001b[33m",
+        [SnippetTokenKinds.String] = "\u001b[32m",
+        [SnippetTokenKinds.Number] = "\u001b[34m",
+        [SnippetTokenKinds.Comment] = "\u001b[90m"
+    };
+
+    private sealed record LanguageProfile(
+        string[] LineComments,
+        string? BlockOpen,
+        string? BlockClose,
+        HashSet<string> Keywords,
+        bool TypesByCase,
+        bool Lifetimes = false);
+
+    private static readonly HashSet<string> CFamilyKeywords = new(StringComparer.Ordinal)
+    {
+        "abstract", "as", "async", "await", "base", "bool", "break", "byte", "case", "catch", "char", "class",
+        "const", "continue", "default", "defer", "delegate", "do", "double", "else", "enum", "export", "extends",
+        "extern", "false", "final", "finally", "float", "fn", "for", "foreach", "func", "function", "go", "goto",
+        "if", "impl", "implements", "import", "in", "int", "interface", "internal", "is", "let", "long", "match",
+        "mod", "mut", "namespace", "new", "nil", "null", "object", "operator", "out", "override", "package",
+        "private", "protected", "pub", "public", "readonly", "record", "ref", "return", "sealed", "self", "short",
+        "static", "string", "struct", "super", "switch", "this", "throw", "throws", "trait", "true", "try", "type",
+        "typeof", "uint", "ulong", "undefined", "use", "using", "val", "var", "virtual", "void", "volatile",
+        "where", "while", "yield"
+    };
+
+    private static readonly HashSet<string> ScriptKeywords = new(StringComparer.Ordinal)
+    {
+        "and", "as", "assert", "begin", "break", "case", "class", "def", "del", "do", "done", "elif", "else",
+        "elsif", "end", "esac", "except", "export", "False", "fi", "finally", "for", "from", "function", "global",
+        "if", "import", "in", "is", "lambda", "local", "module", "None", "nil", "nonlocal", "not", "or", "pass",
+        "raise", "require", "rescue", "return", "self", "then", "True", "try", "unless", "until", "while", "with",
+        "yield"
+    };
+
+    private static readonly HashSet<string> SqlKeywords = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "alter", "and", "as", "asc", "begin", "by", "case", "create", "delete", "desc", "distinct", "drop", "else",
+        "end", "exists", "from", "group", "having", "in", "index", "inner", "insert", "into", "is", "join", "left",
+        "like", "limit", "not", "null", "on", "or", "order", "outer", "primary", "key", "right", "select", "set",
+        "table", "then", "union", "update", "values", "view", "when", "where", "with"
+    };
+
+    private static readonly HashSet<string> DataKeywords = new(StringComparer.Ordinal) { "true", "false", "null" };
+
+    private static readonly LanguageProfile CFamily = new(new[] { "//" }, "/*", "*/", CFamilyKeywords, TypesByCase: true);
+    private static readonly LanguageProfile Rust = CFamily with { Lifetimes = true };
+    private static readonly LanguageProfile Script = new(new[] { "#" }, null, null, ScriptKeywords, TypesByCase: true);
+    private static readonly LanguageProfile Sql = new(new[] { "--" }, "/*", "*/", SqlKeywords, TypesByCase: false);
+    private static readonly LanguageProfile Data = new(Array.Empty<string>(), null, null, DataKeywords, TypesByCase: false);
+
+    private static readonly Dictionary<string, LanguageProfile> ProfilesByExtension = new(StringComparer.OrdinalIgnoreCase)
+    {
+        [".cs"] = CFamily, [".java"] = CFamily, [".kt"] = CFamily, [".scala"] = CFamily, [".go"] = CFamily,
+        [".rs"] = Rust, [".c"] = CFamily, [".h"] = CFamily, [".cpp"] = CFamily, [".hpp"] = CFamily,
+        [".cc"] = CFamily, [".js"] = CFamily, [".jsx"] = CFamily, [".ts"] = CFamily, [".tsx"] = CFamily,
+        [".mjs"] = CFamily, [".swift"] = CFamily, [".dart"] = CFamily, [".php"] = CFamily, [".vue"] = CFamily,
+        [".razor"] = CFamily, [".cshtml"] = CFamily,
+        [".py"] = Script, [".rb"] = Script, [".sh"] = Script, [".bash"] = Script, [".ps1"] = Script,
+        [".yml"] = Script, [".yaml"] = Script, [".toml"] = Script, [".r"] = Script, [".pl"] = Script,
+        [".sql"] = Sql,
+        [".json"] = Data
+    };
+
+    /// <summary>
+    /// True for plain, ansi and classified
+    /// </summary>
+    public static bool IsValidFormat(string? format) =>
+        format != null && Formats.Contains(format, StringComparer.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Renders the hit's context lines in the requested format: ansi colours ContextLines in place,
+    /// classified fills <see cref="SearchHit.ClassifiedLines"/> and leaves the text plain.
+    /// </summary>
+    public static void Apply(SearchHit hit, string format)
+    {
+        if (hit.ContextLines == null || hit.ContextLines.Count == 0 || string.Equals(format, Plain, StringComparison.OrdinalIgnoreCase))
+            return;
+
+        var classified = Classify(hit.ContextLines, hit.FilePath);
+        if (string.Equals(format, Ansi, StringComparison.OrdinalIgnoreCase))
+        {
+            hit.ContextLines = classified.Select(ToAnsi).ToList();
+        }
+        else
+        {
+            hit.ClassifiedLines = classified;
+        }
+    }
+
+    /// <summary>
+    /// Splits each line into classified segments; concatenating a line's segment texts gives the line back
+    /// </summary>
+    public static List<List<SnippetSegment>> Classify(IReadOnlyList<string> lines, string? filePath)
+    {
+        var profile = ProfileFor(filePath);
+        var inBlockComment = false;
+        var result = new List<List<SnippetSegment>>(lines.Count);
+
+        foreach (var line in lines)
+        {
+            result.Add(ClassifyLine(line, profile, ref inBlockComment));
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Joins segments back into a line with ANSI colour escapes; unclassified text stays uncoloured
+    /// </summary>
+    public static string ToAnsi(IEnumerable<SnippetSegment> segments)
+    {
+        var builder = new StringBuilder();
+        foreach (var segment in segments)
+        {
+            if (AnsiColors.TryGetValue(segment.Kind, out var color))
+            {
+                builder.Append(color).Append(segment.Text).Append(AnsiReset);
+            }
+            else
+            {
+                builder.Append(segment.Text);
+            }
+        }
+        return builder.ToString();
+    }
+
+    private static LanguageProfile ProfileFor(string? filePath)
+    {
+        var extension = string.IsNullOrEmpty(filePath) ? string.Empty : Path.GetExtension(filePath);
+        return ProfilesByExtension.TryGetValue(extension, out var profile) ? profile : Data;
+    }
+
+    private static List<SnippetSegment> ClassifyLine(string line, LanguageProfile profile, ref bool inBlockComment)
+    {
+        var segments = new List<SnippetSegment>();
+        var i = 0;
+
+        while (i < line.Length)
+        {
+            var start = i;
+            string kind;
+
+            if (inBlockComment)
+            {
+                var close = line.IndexOf(profile.BlockClose!, i, StringComparison.Ordinal);
+                i = close < 0 ? line.Length : close + profile.BlockClose!.Length;
+                inBlockComment = close < 0;
+                kind = SnippetTokenKinds.Comment;
+            }
+            else if (char.IsWhiteSpace(line[i]))
+            {
+                while (i < line.Length && char.IsWhiteSpace(line[i])) i++;
+                kind = SnippetTokenKinds.Text;
+            }
+            else if (profile.LineComments.Any(marker => string.CompareOrdinal(line, i, marker, 0, marker.Length) == 0))
+            {
+                i = line.Length;
+                kind = SnippetTokenKinds.Comment;
+            }
+            else if (profile.BlockOpen != null && string.CompareOrdinal(line, i, profile.BlockOpen, 0, profile.BlockOpen.Length) == 0)
+            {
+                var close = line.IndexOf(profile.BlockClose!, i + profile.BlockOpen.Length, StringComparison.Ordinal);
+                i = close < 0 ? line.Length : close + profile.BlockClose!.Length;
+                inBlockComment = close < 0;
+                kind = SnippetTokenKinds.Comment;
+            }
+            else if (line[i] == '\'' && profile.Lifetimes && !IsCharLiteral(line, i))
+            {
+                // Rust lifetime ('a): the apostrophe alone, the name is classified as an identifier
+                i++;
+                kind = SnippetTokenKinds.Punctuation;
+            }
+            else if (line[i] is '"' or '\'' or '`')
+            {
+                var end = FindClosingQuote(line, i);
+                if (end < 0 && line[i] == '\'')
+                {
+                    // Lone apostrophe: Rust lifetimes, VB comments, prose - not worth colouring the rest of the line
+                    i++;
+                    kind = SnippetTokenKinds.Punctuation;
+                }
+                else
+                {
+                    i = end < 0 ? line.Length : end + 1;
+                    kind = SnippetTokenKinds.String;
+                }
+            }
+            else if (char.IsDigit(line[i]))
+            {
+                while (i < line.Length && (char.IsLetterOrDigit(line[i]) || line[i] is '_' or '.')) i++;
+                kind = SnippetTokenKinds.Number;
+            }
+            else if (UnicodeIdentifiers.IsIdentifierPart(line[i]) || line[i] == '$')
+            {
+                while (i < line.Length && (UnicodeIdentifiers.IsIdentifierPart(line[i]) || line[i] == '$')) i++;
+                kind = ClassifyWord(line, start, i, profile);
+            }
+            else if (OperatorChars.Contains(line[i]))
+            {
+                while (i < line.Length && OperatorChars.Contains(line[i])) i++;
+                kind = SnippetTokenKinds.Operator;
+            }
+            else
+            {
+                i++;
+                kind = SnippetTokenKinds.Punctuation;
+            }
+
+            Add(segments, line.Substring(start, i - start), kind);
+        }
+
+        return segments;
+    }
+
+    private static string ClassifyWord(string line, int start, int end, LanguageProfile profile)
+    {
+        var word = line.Substring(start, end - start);
+        if (profile.Keywords.Contains(word))
+            return SnippetTokenKinds.Keyword;
+
+        var next = end;
+        while (next < line.Length && line[next] == ' ') next++;
+        if (next < line.Length && line[next] == '(')
+            return SnippetTokenKinds.Function;
+
+        return profile.TypesByCase && char.IsUpper(word[0])
+            ? SnippetTokenKinds.Type
+            : SnippetTokenKinds.Identifier;
+    }
+
+    private static bool IsCharLiteral(string line, int quoteIndex) =>
+        quoteIndex + 1 < line.Length && line[quoteIndex + 1] == '\\' ||
+        quoteIndex + 2 < line.Length && line[quoteIndex + 2] == '\'';
+
+    private static int FindClosingQuote(string line, int openIndex)
+    {
+        var quote = line[openIndex];
+        for (var i = openIndex + 1; i < line.Length; i++)
+        {
+            if (line[i] == '\\')
+            {
+                i++;
+            }
+            else if (line[i] == quote)
+            {
+                return i;
+            }
+        }
+        return -1;
+    }
+
+    private static void Add(List<SnippetSegment> segments, string text, string kind)
+    {
+        // Neighbouring segments of the same kind (whitespace runs, ")]" and so on) are merged to keep output small
+        if (segments.Count > 0 && segments[^1].Kind == kind && kind != SnippetTokenKinds.Identifier)
+        {
+            segments[^1].Text += text;
+        }
+        else
+        {
+            segments.Add(new SnippetSegment { Text = text, Kind = kind });
+        }
+    }
+}
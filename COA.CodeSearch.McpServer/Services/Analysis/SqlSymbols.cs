@@ -0,0 +1,513 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// SQL DDL declarations read from source: tables and their columns (including columns added by ALTER TABLE to a
+/// table created in the same file), views, stored procedures, functions, triggers, named indexes and types.
+/// Names are unqualified and unquoted - "public"."users" is users. Statements end at semicolons, T-SQL GO
+/// lines and MySQL DELIMITER delimiters; routine bodies in BEGIN ... END blocks and dollar quotes are not
+/// read as statements of their own. Fills in what julie-codesearch's SQL extraction misses.
+/// </summary>
+public static class SqlSymbols
+{
+    private const string Part = @"(?:""[^""]+""|`[^`]+`|\[[^\]]+\]|[\w$]+)";
+    private const string Ident = $@"(?:{Part}\s*\.\s*){{0,2}}{Part}";
+    private const string OrReplace = @"(?:OR\s+(?:REPLACE|ALTER)\s+)?";
+    private const string Definer = @"(?:DEFINER\s*=\s*(?:'[^']*'|`[^`]*`|[\w.]+)(?:@(?:'[^']*'|`[^`]*`|[\w.%]+))?\s+)?";
+    private const string IfNotExists = @"(?:IF\s+NOT\s+EXISTS\s+)?";
+    private const RegexOptions Options = RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline;
+
+    private static readonly Regex CreateTable = new(
+        $@"^CREATE\s+{OrReplace}(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+{IfNotExists}(?<name>{Ident})\s*(?<columns>\()?", Options);
+    private static readonly Regex CreateView = new(
+        $@"^CREATE\s+{OrReplace}{Definer}(?:(?:TEMP|TEMPORARY)\s+)?(?:MATERIALIZED\s+)?VIEW\s+{IfNotExists}(?<name>{Ident})", Options);
+    private static readonly Regex CreateRoutine = new(
+        $@"^CREATE\s+{OrReplace}{Definer}(?<keyword>PROC(?:EDURE)?|FUNCTION)\s+{IfNotExists}(?<name>{Ident})", Options);
+    private static readonly Regex CreateTrigger = new(
+        $@"^CREATE\s+{OrReplace}{Definer}(?:CONSTRAINT\s+)?TRIGGER\s+{IfNotExists}(?<name>{Ident}).*?\bON\s+(?<table>{Ident})", Options);
+    private static readonly Regex CreateIndex = new(
+        $@"^CREATE\s+(?:UNIQUE\s+)?(?:(?:CLUSTERED|NONCLUSTERED|FULLTEXT|SPATIAL|BITMAP)\s+)?INDEX\s+(?:CONCURRENTLY\s+)?{IfNotExists}(?<name>{Ident})\s+ON\s+(?:ONLY\s+)?(?<table>{Ident})", Options);
+    private static readonly Regex CreateType = new($@"^CREATE\s+(?:OR\s+REPLACE\s+)?TYPE\s+(?<name>{Ident})", Options);
+    private static readonly Regex AlterTable = new($@"^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(?<name>{Ident})\s+", Options);
+    private static readonly Regex AddColumn = new($@"^ADD\s+(?:COLUMN\s+)?{IfNotExists}(?<name>{Ident})", Options);
+    private static readonly Regex ColumnName = new($@"^(?<name>{Ident})", Options);
+    private static readonly Regex ConstraintStart = new(
+        @"^(?:CONSTRAINT|PRIMARY|UNIQUE|FOREIGN|CHECK|INDEX|KEY|EXCLUDE|FULLTEXT|SPATIAL|PERIOD|LIKE)\b", Options);
+    private static readonly Regex Go = new(@"^\s*GO(?:\s+\d+)?\s*$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex Delimiter = new(@"^\s*DELIMITER\s+(?<delimiter>\S+)", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex Word = new(@"[A-Za-z_]\w*", RegexOptions.Compiled);
+    private static readonly Regex DollarTag = new(@"^\$(?:[A-Za-z_]\w*)?\$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for SQL scripts
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".sql", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a SQL file, in source order, with columns, indexes and triggers parented to their
+    /// table when it is created in the same file
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = Mask(lines);
+        var found = new List<Found>();
+        var tables = new Dictionary<string, Found>(StringComparer.OrdinalIgnoreCase);
+
+        foreach (var statement in Statements(lines, masked))
+        {
+            var text = statement.Text;
+            Match match;
+            if ((match = CreateTable.Match(text)).Success)
+            {
+                var table = Declare(found, statement, "table", match.Groups["name"], null);
+                tables.TryAdd(table.Name, table);
+                if (match.Groups["columns"].Success)
+                    found.AddRange(Columns(statement, match.Groups["columns"].Index, table));
+            }
+            else if ((match = CreateView.Match(text)).Success)
+            {
+                Declare(found, statement, "view", match.Groups["name"], null);
+            }
+            else if ((match = CreateRoutine.Match(text)).Success)
+            {
+                var kind = match.Groups["keyword"].Value.StartsWith("PROC", StringComparison.OrdinalIgnoreCase) ? "procedure" : "function";
+                Declare(found, statement, kind, match.Groups["name"], null);
+            }
+            else if ((match = CreateTrigger.Match(text)).Success)
+            {
+                Declare(found, statement, "trigger", match.Groups["name"], tables.GetValueOrDefault(Unquote(match.Groups["table"].Value)));
+            }
+            else if ((match = CreateIndex.Match(text)).Success)
+            {
+                Declare(found, statement, "index", match.Groups["name"], tables.GetValueOrDefault(Unquote(match.Groups["table"].Value)));
+            }
+            else if ((match = CreateType.Match(text)).Success)
+            {
+                Declare(found, statement, "type", match.Groups["name"], null);
+            }
+            else if ((match = AlterTable.Match(text)).Success)
+            {
+                var table = tables.GetValueOrDefault(Unquote(match.Groups["name"].Value));
+                found.AddRange(AddedColumns(statement, match.Index + match.Length, table));
+            }
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in found.OrderBy(d => d.Line).ThenBy(d => d.Column))
+        {
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = declaration.Kind,
+                Language = "sql",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = declaration.EndLine,
+                EndColumn = lines[declaration.EndLine - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = declaration.Kind == "column" ? null : DocComment(lines, declaration.Line - 1),
+                Visibility = "public"
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a SQL file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The unqualified, unquoted name a possibly schema-qualified identifier declares: "public"."users" → users
+    /// </summary>
+    public static string Unquote(string identifier)
+    {
+        var last = Regex.Match(identifier, $@"{Part}\s*$").Value.Trim();
+        return last.Length > 1 && last[0] is '"' or '`' or '[' ? last[1..^1] : last;
+    }
+
+    private static Found Declare(List<Found> found, Statement statement, string kind, Group name, Found? container)
+    {
+        var declaration = new Found
+        {
+            Kind = kind,
+            Name = Unquote(name.Value),
+            Line = statement.Line,
+            Column = statement.Column,
+            EndLine = statement.EndLine,
+            Signature = Signature(statement.Original.Split('\n')[0]),
+            Container = container
+        };
+        found.Add(declaration);
+        return declaration;
+    }
+
+    /// <summary>
+    /// The column definitions in a CREATE TABLE's parenthesized list; table constraints are skipped
+    /// </summary>
+    private static IEnumerable<Found> Columns(Statement statement, int open, Found table)
+    {
+        foreach (var (start, end) in Elements(statement.Text, open + 1, ')'))
+        {
+            var element = statement.Text[start..end];
+            if (ConstraintStart.IsMatch(element) || ColumnName.Match(element) is not { Success: true } name)
+                continue;
+            yield return Column(statement, start, end, Unquote(name.Value), table);
+        }
+    }
+
+    /// <summary>
+    /// The columns an ALTER TABLE adds, one per ADD [COLUMN] action
+    /// </summary>
+    private static IEnumerable<Found> AddedColumns(Statement statement, int actions, Found? table)
+    {
+        foreach (var (start, end) in Elements(statement.Text, actions, null))
+        {
+            var element = statement.Text[start..end];
+            if (AddColumn.Match(element) is not { Success: true } add)
+                continue;
+            var name = add.Groups["name"];
+            if (ConstraintStart.IsMatch(name.Value) && !name.Value.StartsWith('"'))
+                continue;
+            yield return Column(statement, start + name.Index, end, Unquote(name.Value), table);
+        }
+    }
+
+    private static Found Column(Statement statement, int start, int end, string name, Found? table)
+    {
+        var (line, column) = statement.PositionOf(start);
+        return new Found
+        {
+            Kind = "column",
+            Name = name,
+            Line = line,
+            Column = column,
+            EndLine = statement.PositionOf(end - 1).Line,
+            Signature = Signature(Regex.Replace(statement.Original[start..end], @"\s+", " ")),
+            Container = table
+        };
+    }
+
+    /// <summary>
+    /// The trimmed comma-separated elements from <paramref name="from"/> up to the unmatched
+    /// <paramref name="close"/> (or the end of the statement), as start and end offsets
+    /// </summary>
+    private static IEnumerable<(int Start, int End)> Elements(string text, int from, char? close)
+    {
+        var depth = 0;
+        var start = from;
+        for (var i = from; i <= text.Length; i++)
+        {
+            var c = i < text.Length ? text[i] : '\0';
+            if (c == '(')
+            {
+                depth++;
+                continue;
+            }
+            if (c == ')' && depth > 0)
+            {
+                depth--;
+                continue;
+            }
+            if (depth > 0 || c != ',' && c != close && i < text.Length)
+                continue;
+
+            var s = start;
+            var e = i;
+            while (s < e && char.IsWhiteSpace(text[s]))
+                s++;
+            while (e > s && char.IsWhiteSpace(text[e - 1]))
+                e--;
+            if (e > s)
+                yield return (s, e);
+            if (c == close)
+                yield break;
+            start = i + 1;
+        }
+    }
+
+    /// <summary>
+    /// The statements of a masked file: split at semicolons (or the DELIMITER in effect) outside parentheses
+    /// and BEGIN ... END blocks, and at GO lines. Batches separated by GO hold one routine, so a CREATE
+    /// PROCEDURE, FUNCTION or TRIGGER runs to the next GO when the file has any.
+    /// </summary>
+    private static IEnumerable<Statement> Statements(string[] lines, string[] masked)
+    {
+        var batched = masked.Any(l => Go.IsMatch(l));
+        var delimiter = ";";
+        var depth = 0;
+        var blocks = 0;
+        (int Line, int Column)? start = null;
+
+        Statement Close(int line, int column)
+        {
+            var statement = Statement.From(lines, masked, start!.Value.Line, start.Value.Column, line, column);
+            start = null;
+            depth = 0;
+            blocks = 0;
+            return statement;
+        }
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var text = masked[i];
+            if (Go.IsMatch(text) || start == null && Delimiter.Match(text) is { Success: true })
+            {
+                if (start != null)
+                    yield return Close(i - 1, masked[i - 1].Length);
+                if (Delimiter.Match(text) is { Success: true } changed)
+                    delimiter = changed.Groups["delimiter"].Value;
+                continue;
+            }
+
+            for (var c = 0; c < text.Length; c++)
+            {
+                if (char.IsWhiteSpace(text[c]))
+                    continue;
+                start ??= (i, c);
+
+                if (string.CompareOrdinal(text, c, delimiter, 0, delimiter.Length) == 0 && depth == 0 && blocks == 0
+                    && !(delimiter == ";" && batched && IsRoutine(masked, start.Value)))
+                {
+                    yield return Close(i, c);
+                    c += delimiter.Length - 1;
+                    continue;
+                }
+
+                switch (text[c])
+                {
+                    case '(':
+                        depth++;
+                        continue;
+                    case ')':
+                        depth = Math.Max(0, depth - 1);
+                        continue;
+                }
+
+                if (!char.IsLetter(text[c]) || c > 0 && (char.IsLetterOrDigit(text[c - 1]) || text[c - 1] is '_' or '.' or '"' or '`' or '['))
+                    continue;
+                var word = Word.Match(text, c);
+                if (!word.Success || word.Index != c)
+                    continue;
+                var next = Word.Match(text, c + word.Length);
+                var rest = text[(c + word.Length)..].TrimStart();
+                var following = next.Success && text[(c + word.Length)..next.Index].Trim().Length == 0 ? next.Value.ToUpperInvariant() : string.Empty;
+                switch (word.Value.ToUpperInvariant())
+                {
+                    case "BEGIN" when following is not ("TRAN" or "TRANSACTION" or "WORK" or "DISTRIBUTED" or "DEFERRED" or "IMMEDIATE" or "EXCLUSIVE")
+                                      && !rest.StartsWith(';') && start.Value != (i, c):
+                    case "CASE":
+                        blocks++;
+                        break;
+                    case "END" when blocks > 0 && following is not ("IF" or "LOOP" or "WHILE" or "REPEAT" or "FOR"):
+                        blocks--;
+                        break;
+                }
+                c += word.Length - 1;
+            }
+        }
+
+        if (start != null)
+        {
+            var last = masked.Length - 1;
+            while (last > start.Value.Line && string.IsNullOrWhiteSpace(masked[last]))
+                last--;
+            yield return Close(last, masked[last].Length);
+        }
+    }
+
+    private static bool IsRoutine(string[] masked, (int Line, int Column) start)
+    {
+        var head = masked[start.Line][start.Column..];
+        for (var i = start.Line + 1; i < masked.Length && head.Length < 200; i++)
+            head += "\n" + masked[i];
+        return CreateRoutine.IsMatch(head) || Regex.IsMatch(head, $@"^CREATE\s+{OrReplace}{Definer}(?:CONSTRAINT\s+)?TRIGGER\b", RegexOptions.IgnoreCase);
+    }
+
+    /// <summary>
+    /// Lines with comments blanked and the contents of string literals and dollar-quoted bodies blanked, quotes
+    /// kept; quoted identifiers ("users", `users`, [users]) are left as they are
+    /// </summary>
+    private static string[] Mask(string[] lines)
+    {
+        var masked = new string[lines.Length];
+        var inBlock = false;
+        var inString = false;
+        string? dollar = null;
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            var builder = new StringBuilder(line);
+            for (var c = 0; c < line.Length; c++)
+            {
+                if (inBlock)
+                {
+                    builder[c] = ' ';
+                    if (line[c] == '*' && c + 1 < line.Length && line[c + 1] == '/')
+                    {
+                        builder[c + 1] = ' ';
+                        c++;
+                        inBlock = false;
+                    }
+                }
+                else if (dollar != null)
+                {
+                    if (string.CompareOrdinal(line, c, dollar, 0, dollar.Length) == 0)
+                    {
+                        c += dollar.Length - 1;
+                        dollar = null;
+                    }
+                    else
+                    {
+                        builder[c] = ' ';
+                    }
+                }
+                else if (inString)
+                {
+                    if (line[c] == '\'' && c + 1 < line.Length && line[c + 1] == '\'')
+                    {
+                        builder[c] = builder[c + 1] = ' ';
+                        c++;
+                    }
+                    else if (line[c] == '\'')
+                    {
+                        inString = false;
+                    }
+                    else
+                    {
+                        builder[c] = ' ';
+                    }
+                }
+                else if (line[c] == '-' && c + 1 < line.Length && line[c + 1] == '-')
+                {
+                    for (var k = c; k < line.Length; k++)
+                        builder[k] = ' ';
+                    break;
+                }
+                else if (line[c] == '/' && c + 1 < line.Length && line[c + 1] == '*')
+                {
+                    builder[c] = builder[c + 1] = ' ';
+                    c++;
+                    inBlock = true;
+                }
+                else if (line[c] == '\'')
+                {
+                    inString = true;
+                }
+                else if (line[c] == '$' && (c == 0 || !char.IsLetterOrDigit(line[c - 1]) && line[c - 1] != '_')
+                         && DollarTag.Match(line[c..]) is { Success: true } tag)
+                {
+                    dollar = tag.Value;
+                    c += tag.Length - 1;
+                }
+            }
+            masked[i] = builder.ToString();
+        }
+        return masked;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = line.Trim().TrimEnd(',', ';').TrimEnd();
+        if (signature.EndsWith('('))
+            signature = signature[..^1].TrimEnd();
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    /// <summary>
+    /// The <c>--</c> comment lines right above a declaration
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var end = index - 1;
+        if (end < 0 || !lines[end].TrimStart().StartsWith("--", StringComparison.Ordinal))
+            return null;
+
+        var i = end;
+        while (i > 0 && lines[i - 1].TrimStart().StartsWith("--", StringComparison.Ordinal))
+            i--;
+        return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"sql:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    /// <summary>
+    /// One statement's text, masked and as written, and where it starts and ends
+    /// </summary>
+    private sealed class Statement
+    {
+        public string Text { get; private init; } = string.Empty;
+        public string Original { get; private init; } = string.Empty;
+        public int Line { get; private init; }
+        public int Column { get; private init; }
+        public int EndLine { get; private init; }
+
+        /// <summary>
+        /// From the 0-based start position up to (not including) the 0-based end position
+        /// </summary>
+        public static Statement From(string[] lines, string[] masked, int line, int column, int endLine, int endColumn)
+        {
+            while (endLine > line && string.IsNullOrWhiteSpace(masked[endLine]))
+            {
+                endLine--;
+                endColumn = masked[endLine].Length;
+            }
+            string Slice(string[] source) => string.Join("\n", Enumerable.Range(line, endLine - line + 1).Select(i =>
+            {
+                var from = i == line ? column : 0;
+                var to = i == endLine ? Math.Min(endColumn, source[i].Length) : source[i].Length;
+                return to > from ? source[i][from..to] : string.Empty;
+            }));
+            return new Statement
+            {
+                Text = Slice(masked),
+                Original = Slice(lines),
+                Line = line + 1,
+                Column = column,
+                EndLine = endLine + 1
+            };
+        }
+
+        /// <summary>
+        /// The 1-based line and 0-based column of an offset into <see cref="Text"/>
+        /// </summary>
+        public (int Line, int Column) PositionOf(int offset)
+        {
+            var before = Text[..offset];
+            var newline = before.LastIndexOf('\n');
+            var line = Line + before.Count(c => c == '\n');
+            return (line, newline < 0 ? Column + offset : offset - newline - 1);
+        }
+    }
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+        public string Name { get; init; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; init; }
+        public string Signature { get; init; } = string.Empty;
+        public Found? Container { get; init; }
+    }
+}
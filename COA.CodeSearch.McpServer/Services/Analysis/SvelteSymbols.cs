@@ -0,0 +1,525 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Svelte components read as declarations, since julie-codesearch extracts nothing from .svelte files: top-level
+/// declarations of the instance and module &lt;script&gt; blocks, props (export let, export { x as y } or
+/// let { ... } = $props()) as "property" symbols and reactive statements ($: ..., $derived and $effect runes) as
+/// "reactive" symbols, at their line and column in the file. The component, named after the file, is their parent
+/// and spans the file.
+/// </summary>
+public static class SvelteSymbols
+{
+    private static readonly Regex ScriptOpen = new(@"<script(?<attrs>(?:\s[^>]*)?)>", RegexOptions.Compiled);
+    private static readonly Regex ScriptClose = new(@"</script\s*>", RegexOptions.Compiled);
+    private static readonly Regex ModuleAttribute = new(@"\scontext\s*=\s*['""]module['""]|\smodule(?![\w-])", RegexOptions.Compiled);
+    private static readonly Regex Declaration = new(
+        @"^[ \t]*(?:export[ \t]+)?(?:declare[ \t]+)?(?:(?<kind>async[ \t]+function|function|abstract[ \t]+class|class|interface|const[ \t]+enum|enum|type)(?:[ \t]*\*[ \t]*|[ \t]+)(?<name>[A-Za-z_$][\w$]*)|(?<kind>const|let|var)[ \t]+(?<name>[A-Za-z_$][\w$]*)(?:[ \t]*:[^=\n]+)?[ \t]*=[ \t]*(?<init>[^\n]*))",
+        RegexOptions.Compiled);
+    private static readonly Regex FunctionValue = new(@"^(?:async\b|function\b|\([^)]*\)\s*(?::[^=]+)?=>|[A-Za-z_$][\w$]*\s*=>)", RegexOptions.Compiled);
+    private static readonly Regex ExportLet = new(
+        @"^[ \t]*export[ \t]+(?:let|var)[ \t]+(?<name>[A-Za-z_$][\w$]*)(?:[ \t]*:[ \t]*(?<type>[^=;]+?))?[ \t]*(?:=[ \t]*(?<value>.*?))?[ \t]*;?[ \t]*$",
+        RegexOptions.Compiled);
+    private static readonly Regex ExportList = new(@"^[ \t]*export[ \t]*\{(?<names>[^}]*)\}", RegexOptions.Compiled);
+    private static readonly Regex ExportName = new(@"(?:(?<local>[A-Za-z_$][\w$]*)\s+as\s+)?(?<name>[A-Za-z_$][\w$]*)\s*$", RegexOptions.Compiled);
+    private static readonly Regex Destructure = new(@"^[ \t]*(?:let|const|var)[ \t]*(?=\{)", RegexOptions.Compiled);
+    private static readonly Regex PropsCall = new(@"\G\s*(?::\s*(?<type>[^=]+?))?\s*=\s*\$props\s*\(\s*\)", RegexOptions.Compiled);
+    private static readonly Regex PropPart = new(
+        @"^(?<name>[A-Za-z_$][\w$]*|'[^']*'|""[^""]*"")\s*(?::\s*[A-Za-z_$][\w$]*)?\s*(?:=\s*(?<value>[\s\S]+))?$",
+        RegexOptions.Compiled);
+    private static readonly Regex Bindable = new(@"^\$bindable\s*\((?<value>[\s\S]*)\)$", RegexOptions.Compiled);
+    private static readonly Regex ReactiveLabel = new(@"^[ \t]*\$:[ \t]*(?<body>.*)", RegexOptions.Compiled);
+    private static readonly Regex ReactiveAssignment = new(
+        @"^(?:(?<name>[A-Za-z_$][\w$]*)|\(\s*[{\[](?<names>[^}\]]*)[}\]])\s*=(?![=>])",
+        RegexOptions.Compiled);
+    private static readonly Regex Effect = new(@"^[ \t]*(?<name>\$effect(?:\.pre)?)[ \t]*\(", RegexOptions.Compiled);
+    private static readonly Regex TypeMember = new(@"^(?:readonly\s+)?(?<name>[A-Za-z_$][\w$]*)(?<optional>\?)?\s*:\s*(?<type>[\s\S]+)$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Svelte components
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".svelte", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// The component, the declarations of its script blocks, its props and reactive statements, in source order
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var text = content.Replace("\r\n", "\n");
+        var lines = new LineMap(text);
+        var scripts = Scripts(text);
+
+        var fileName = Path.GetFileNameWithoutExtension(filePath);
+        var component = new JulieSymbol
+        {
+            Id = StableId(filePath, "class", "component", 1),
+            Name = string.Concat(fileName.Split('-', '_', '.', ' ').Where(p => p.Length > 0).Select(p => char.ToUpperInvariant(p[0]) + p[1..])),
+            Kind = "class",
+            Language = "svelte",
+            FilePath = filePath,
+            StartLine = 1,
+            StartColumn = 0,
+            EndLine = lines.Count,
+            EndColumn = lines.LineLength(lines.Count),
+            Visibility = "public"
+        };
+        var symbols = new List<JulieSymbol> { component };
+        var props = new List<JulieSymbol>();
+
+        foreach (var script in scripts)
+        {
+            var lineStart = script.Start;
+            while (lineStart < script.End)
+            {
+                var lineEnd = text.IndexOf('\n', lineStart);
+                if (lineEnd < 0 || lineEnd > script.End)
+                    lineEnd = script.End;
+
+                if (script.IsCode(lineStart) && script.DepthAt(lineStart) == 0)
+                    Statement(script, scripts, lineStart, lineEnd, lines, filePath, component, symbols, props);
+
+                lineStart = lineEnd + 1;
+            }
+        }
+
+        symbols.AddRange(props);
+        component.Signature = $"<{component.Name}>"
+            + (props.Count > 0 ? $" props: {string.Join(", ", props.Select(p => p.Name).Distinct())}" : string.Empty);
+
+        return symbols.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a .svelte file with the declarations julie-codesearch missed added and multi-line
+    /// statements extended to their last line. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The &lt;script&gt; blocks of a component. &lt;script context="module"&gt; (Svelte 4) and
+    /// &lt;script module&gt; (Svelte 5) run once per module and declare no props.
+    /// </summary>
+    private static List<ScriptCode> Scripts(string text)
+    {
+        var scripts = new List<ScriptCode>();
+        var position = 0;
+        while (ScriptOpen.Match(text, position) is { Success: true } open)
+        {
+            var start = open.Index + open.Length;
+            var close = ScriptClose.Match(text, start);
+            var end = close.Success ? close.Index : text.Length;
+            scripts.Add(new ScriptCode(text, start, end, ModuleAttribute.IsMatch(open.Groups["attrs"].Value)));
+            position = close.Success ? close.Index + close.Length : text.Length;
+        }
+        return scripts;
+    }
+
+    /// <summary>
+    /// The symbols of the top-level statement starting on the line between <paramref name="lineStart"/> and
+    /// <paramref name="lineEnd"/>
+    /// </summary>
+    private static void Statement(ScriptCode script, List<ScriptCode> scripts, int lineStart, int lineEnd, LineMap lines,
+        string filePath, JulieSymbol component, List<JulieSymbol> symbols, List<JulieSymbol> props)
+    {
+        var text = script.Text;
+        // Trailing comments are not part of a default or signature
+        var line = text[lineStart..script.CodeEnd(lineStart, lineEnd)].TrimEnd();
+        var end = script.StatementEnd(lineStart, lineEnd);
+        var endLine = lines.Position(end).Line;
+        var signature = line.Trim().TrimEnd('{').TrimEnd();
+
+        if (!script.IsModule && ExportLet.Match(line) is { Success: true } exportLet)
+        {
+            var value = exportLet.Groups["value"];
+            // A default spanning lines (export let items = [) is left out of the signature
+            var initial = value.Success && endLine == lines.Position(lineStart).Line ? value.Value : null;
+            var type = exportLet.Groups["type"].Success ? exportLet.Groups["type"].Value.Trim() : null;
+            props.Add(Prop(lines, filePath, exportLet.Groups["name"].Value, lineStart + exportLet.Groups["name"].Index, endLine,
+                value.Success, type, initial, bindable: false, component));
+            return;
+        }
+
+        if (!script.IsModule && ExportList.Match(line) is { Success: true } exportList)
+        {
+            // export { klass as class } declares the prop "class"
+            var names = exportList.Groups["names"];
+            var offset = 0;
+            foreach (var part in names.Value.Split(','))
+            {
+                if (ExportName.Match(part) is { Success: true } exported)
+                {
+                    props.Add(Prop(lines, filePath, exported.Groups["name"].Value, lineStart + names.Index + offset + exported.Groups["name"].Index,
+                        endLine, optional: false, type: null, initial: null, bindable: false, component));
+                }
+                offset += part.Length + 1;
+            }
+            return;
+        }
+
+        if (!script.IsModule && Destructure.Match(line) is { Success: true } destructure
+            && script.Close(lineStart + destructure.Length) is { } close
+            && PropsCall.Match(text, close + 1) is { Success: true } call)
+        {
+            props.AddRange(RuneProps(script, scripts, lineStart + destructure.Length, close, call.Groups["type"], endLine, lines, filePath, component));
+            return;
+        }
+
+        if (ReactiveLabel.Match(line) is { Success: true } reactive)
+        {
+            var body = reactive.Groups["body"];
+            if (ReactiveAssignment.Match(body.Value) is { Success: true } assignment)
+            {
+                // $: doubled = count * 2 declares doubled; $: ({ a, b } = pair) declares a and b
+                if (assignment.Groups["name"].Success)
+                {
+                    symbols.Add(Reactive(lines, filePath, assignment.Groups["name"].Value,
+                        lineStart + body.Index + assignment.Groups["name"].Index, endLine, signature, component));
+                }
+                else
+                {
+                    var names = assignment.Groups["names"];
+                    foreach (Match name in Regex.Matches(names.Value, @"(?:[\w$]+\s*:\s*)?(?<name>[A-Za-z_$][\w$]*)"))
+                    {
+                        symbols.Add(Reactive(lines, filePath, name.Groups["name"].Value,
+                            lineStart + body.Index + names.Index + name.Groups["name"].Index, endLine, signature, component));
+                    }
+                }
+            }
+            else
+            {
+                // $: { ... }, $: if (...) and $: call() run again when what they read changes
+                symbols.Add(Reactive(lines, filePath, "$:", lineStart + line.IndexOf('$'), endLine, signature, component));
+            }
+            return;
+        }
+
+        if (Effect.Match(line) is { Success: true } effect)
+        {
+            symbols.Add(Reactive(lines, filePath, effect.Groups["name"].Value, lineStart + effect.Groups["name"].Index, endLine, signature, component));
+            return;
+        }
+
+        if (Declaration.Match(line) is { Success: true } declaration)
+        {
+            var init = declaration.Groups["init"].Value;
+            var keyword = Regex.Replace(declaration.Groups["kind"].Value, @"\s+", " ");
+            var kind = keyword switch
+            {
+                "function" or "async function" => "function",
+                "class" or "abstract class" => "class",
+                "interface" => "interface",
+                "enum" or "const enum" => "enum",
+                "type" => "type",
+                _ when init.StartsWith("$derived", StringComparison.Ordinal) => "reactive",
+                _ => FunctionValue.IsMatch(init) ? "function" : "variable"
+            };
+
+            var name = declaration.Groups["name"];
+            var (startLine, column) = lines.Position(lineStart + name.Index);
+            var symbol = Declare(filePath, kind, name.Value, startLine, column, endLine, signature, component);
+            symbol.EndColumn = lines.LineLength(endLine);
+            symbols.Add(symbol);
+        }
+    }
+
+    /// <summary>
+    /// The props of let { a, b = 1, value = $bindable() }: Props = $props(), typed from the annotation: a type
+    /// literal or an interface or type alias of one of the scripts. Renamed props (class: klass) keep the outer
+    /// name; the rest (...rest) is not a prop.
+    /// </summary>
+    private static IEnumerable<JulieSymbol> RuneProps(ScriptCode script, List<ScriptCode> scripts, int open, int close, Group annotation,
+        int endLine, LineMap lines, string filePath, JulieSymbol component)
+    {
+        var types = new Dictionary<string, (bool Optional, string Type)>(StringComparer.Ordinal);
+        if (annotation.Success)
+        {
+            var typeName = annotation.Value.Trim();
+            var literal = typeName.StartsWith('{') ? (script, annotation.Index + annotation.Value.IndexOf('{')) : TypeDeclaration(scripts, typeName);
+            if (literal is ({ } owner, var typeOpen) && owner.Close(typeOpen) is { } typeClose)
+            {
+                foreach (var (_, member) in owner.Segments(typeOpen, typeClose, typeLiteral: true))
+                {
+                    if (TypeMember.Match(member) is { Success: true } typed)
+                        types[typed.Groups["name"].Value] = (typed.Groups["optional"].Success, Collapse(typed.Groups["type"].Value));
+                }
+            }
+        }
+
+        foreach (var (start, part) in script.Segments(open, close, typeLiteral: false))
+        {
+            if (part.StartsWith("...", StringComparison.Ordinal) || PropPart.Match(part) is not { Success: true } prop)
+                continue;
+
+            var name = prop.Groups["name"].Value.Trim('\'', '"');
+            var value = prop.Groups["value"].Success ? Collapse(prop.Groups["value"].Value) : null;
+            var bindable = false;
+            if (value != null && Bindable.Match(value) is { Success: true } bound)
+            {
+                bindable = true;
+                value = bound.Groups["value"].Value.Trim() is { Length: > 0 } initial ? initial : null;
+            }
+
+            types.TryGetValue(name, out var typed);
+            yield return Prop(lines, filePath, name, start + prop.Groups["name"].Index, endLine,
+                typed.Optional || value != null || bindable, typed.Type, value, bindable, component);
+        }
+    }
+
+    /// <summary>
+    /// The script and opening brace of interface <paramref name="name"/> { ... } or type <paramref name="name"/> = { ... }
+    /// </summary>
+    private static (ScriptCode? Script, int Open) TypeDeclaration(List<ScriptCode> scripts, string name)
+    {
+        var pattern = new Regex($@"\b(?:interface\s+{Regex.Escape(name)}\b[^{{=;]*|type\s+{Regex.Escape(name)}\s*=\s*)(?=\{{)");
+        foreach (var script in scripts)
+        {
+            var match = pattern.Match(script.Text, script.Start, script.End - script.Start);
+            if (match.Success && script.IsCode(match.Index))
+                return (script, match.Index + match.Length);
+        }
+        return (null, -1);
+    }
+
+    private static JulieSymbol Prop(LineMap lines, string filePath, string name, int nameIndex, int endLine, bool optional, string? type,
+        string? initial, bool bindable, JulieSymbol component)
+    {
+        var (line, column) = lines.Position(nameIndex);
+        var signature = $"prop {name}{(optional ? "?" : string.Empty)}"
+            + (type != null ? $": {type}" : string.Empty)
+            + (initial != null ? $" = {initial}" : string.Empty)
+            + (bindable ? " (bindable)" : string.Empty);
+        var symbol = Declare(filePath, "property", name, line, column, Math.Max(line, endLine), signature, component);
+        symbol.EndColumn = lines.LineLength(symbol.EndLine);
+        return symbol;
+    }
+
+    private static JulieSymbol Reactive(LineMap lines, string filePath, string name, int nameIndex, int endLine, string signature, JulieSymbol component)
+    {
+        var (line, column) = lines.Position(nameIndex);
+        var symbol = Declare(filePath, "reactive", name, line, column, endLine, signature, component);
+        symbol.EndColumn = lines.LineLength(endLine);
+        return symbol;
+    }
+
+    private static string Collapse(string value) =>
+        Regex.Replace(value.Trim().TrimEnd(';', ','), @"\s+", " ");
+
+    private static JulieSymbol Declare(string filePath, string kind, string name, int line, int column, int endLine, string signature, JulieSymbol parent) =>
+        new()
+        {
+            Id = StableId(filePath, kind, name, line),
+            Name = name,
+            Kind = kind,
+            Language = "svelte",
+            FilePath = filePath,
+            StartLine = line,
+            StartColumn = column,
+            EndLine = endLine,
+            Signature = signature,
+            Visibility = "public",
+            ParentId = parent.Id
+        };
+
+    private static string StableId(string filePath, string kind, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"svelte:{filePath}:{kind}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    /// <summary>
+    /// Line and column of offsets into the component
+    /// </summary>
+    private sealed class LineMap
+    {
+        private readonly List<int> _starts = new() { 0 };
+        private readonly int _length;
+
+        public LineMap(string text)
+        {
+            for (var i = 0; i < text.Length; i++)
+            {
+                if (text[i] == '\n')
+                    _starts.Add(i + 1);
+            }
+            _length = text.Length;
+        }
+
+        public int Count => _starts.Count;
+
+        public (int Line, int Column) Position(int index)
+        {
+            var line = _starts.BinarySearch(index);
+            if (line < 0)
+                line = ~line - 1;
+            return (line + 1, index - _starts[line]);
+        }
+
+        public int LineLength(int line) =>
+            (line < _starts.Count ? _starts[line] - 1 : _length) - _starts[line - 1];
+    }
+
+    /// <summary>
+    /// A script block with the bracket depth of every character and whether it is code, so statements are found
+    /// at the top level and outside strings and comments
+    /// </summary>
+    private sealed class ScriptCode
+    {
+        private readonly int[] _depth;
+        private readonly bool[] _comment;
+        private readonly bool[] _string;
+
+        public ScriptCode(string text, int start, int end, bool isModule)
+        {
+            Text = text;
+            Start = start;
+            End = end;
+            IsModule = isModule;
+            _depth = new int[end - start + 1];
+            _comment = new bool[end - start + 1];
+            _string = new bool[end - start + 1];
+
+            var depth = 0;
+            for (var at = start; at < end; at++)
+            {
+                var c = text[at];
+                _depth[at - start] = depth;
+
+                if (c is '\'' or '"' or '`')
+                {
+                    var close = at + 1;
+                    while (close < end && text[close] != c && !(c != '`' && text[close] == '\n'))
+                        close += text[close] == '\\' ? 2 : 1;
+                    at = Mark(_string, at, Math.Min(close, end - 1), depth);
+                }
+                else if (c == '/' && at + 1 < end && text[at + 1] is '/' or '*')
+                {
+                    var close = text[at + 1] == '/'
+                        ? text.IndexOf('\n', at) - 1
+                        : text.IndexOf("*/", at + 2, StringComparison.Ordinal) + 1;
+                    if (close < at || close >= end)
+                        close = end - 1;
+                    at = Mark(_comment, at, close, depth);
+                }
+                else if (c is '(' or '[' or '{')
+                {
+                    depth++;
+                }
+                else if (c is ')' or ']' or '}')
+                {
+                    depth = Math.Max(0, depth - 1);
+                }
+            }
+            _depth[end - start] = depth;
+        }
+
+        public string Text { get; }
+
+        public int Start { get; }
+
+        public int End { get; }
+
+        public bool IsModule { get; }
+
+        public bool IsCode(int index) => !_comment[index - Start] && !_string[index - Start];
+
+        public int DepthAt(int index) => _depth[index - Start];
+
+        /// <summary>
+        /// Where the code of a line ends: at its first comment, or at <paramref name="lineEnd"/>
+        /// </summary>
+        public int CodeEnd(int lineStart, int lineEnd)
+        {
+            for (var i = lineStart; i < lineEnd; i++)
+            {
+                if (_comment[i - Start])
+                    return i;
+            }
+            return lineEnd;
+        }
+
+        /// <summary>
+        /// The closing bracket of the bracket at <paramref name="open"/>
+        /// </summary>
+        public int? Close(int open)
+        {
+            if (open < Start || open >= End || Text[open] is not ('(' or '[' or '{') || !IsCode(open))
+                return null;
+
+            var depth = DepthAt(open);
+            for (var i = open + 1; i < End; i++)
+            {
+                if (IsCode(i) && Text[i] is ')' or ']' or '}' && DepthAt(i) == depth + 1)
+                    return i;
+            }
+            return null;
+        }
+
+        /// <summary>
+        /// The last character of the statement starting on the line at <paramref name="lineStart"/>: the end of the
+        /// line, or of the last line inside the brackets it opens
+        /// </summary>
+        public int StatementEnd(int lineStart, int lineEnd)
+        {
+            var depth = DepthAt(lineStart);
+            while (lineEnd < End && DepthAt(lineEnd) > depth)
+            {
+                var next = Text.IndexOf('\n', lineEnd + 1);
+                lineEnd = next < 0 || next > End ? End : next;
+            }
+            return Math.Max(lineStart, lineEnd - 1);
+        }
+
+        /// <summary>
+        /// The separated parts between two brackets, starting at their first character that is not blank or
+        /// commented, with comments blanked out. Parts are separated by commas, and in type literals also by
+        /// semicolons and line breaks.
+        /// </summary>
+        public IEnumerable<(int Start, string Text)> Segments(int open, int close, bool typeLiteral)
+        {
+            var depth = DepthAt(open) + 1;
+            var angles = 0;
+            var start = open + 1;
+            for (var i = open + 1; i <= close; i++)
+            {
+                if (i < close)
+                {
+                    if (!IsCode(i) || DepthAt(i) != depth)
+                        continue;
+                    if (typeLiteral && Text[i] == '<')
+                        angles++;
+                    else if (typeLiteral && Text[i] == '>' && Text[i - 1] != '=' && angles > 0)
+                        angles--;
+                    if (angles > 0 || (typeLiteral ? Text[i] is not (',' or ';' or '\n') : Text[i] != ','))
+                        continue;
+                }
+
+                while (start < i && (char.IsWhiteSpace(Text[start]) || _comment[start - Start]))
+                    start++;
+                if (start < i)
+                {
+                    var segment = new StringBuilder(Text, start, i - start, i - start);
+                    for (var j = 0; j < segment.Length; j++)
+                    {
+                        if (_comment[start + j - Start])
+                            segment[j] = ' ';
+                    }
+                    yield return (start, segment.ToString().TrimEnd());
+                }
+                start = i + 1;
+            }
+        }
+
+        private int Mark(bool[] kind, int from, int to, int depth)
+        {
+            for (var at = from; at <= to; at++)
+            {
+                kind[at - Start] = true;
+                _depth[at - Start] = depth;
+            }
+            return to;
+        }
+    }
+}
@@ -0,0 +1,466 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Swift declarations read from source: classes, structs, enums and their cases, protocols, actors, extensions,
+/// functions and operators, initializers, subscripts, properties (with their property wrapper attributes),
+/// type aliases and associated types. Members of an extension are parented to it, and the extension is named
+/// after the type it extends. Locals inside function and accessor bodies are left out. Fills in what
+/// julie-codesearch's Swift extraction misses.
+/// </summary>
+public static class SwiftSymbols
+{
+    private const string Attributes = @"(?:@\w+(?:\.\w+)*(?:\([^()]*\))?\s+)*";
+    private const string Prefix = @"^\s*" + Attributes;
+    private const string Modifiers = @"(?<modifiers>(?:(?:(?:public|private|fileprivate|internal|open|package)(?:\(set\))?|final|static|class(?=\s+(?:func|var|let|subscript|override|final)\b)|override|mutating|nonmutating|convenience|required|lazy|weak|unowned(?:\((?:safe|unsafe)\))?|dynamic|indirect|nonisolated|optional|distributed)\s+)*)";
+    private const string Name = @"(?<name>\w+|`[^`]+`)";
+    private const string Generics = @"(?:<[^()]*?>)?";
+
+    private static readonly Regex Function = new($@"{Prefix}{Modifiers}func\s+(?<name>\w+|`[^`]+`|[-+*/%=<>!&|^~?.]+)\s*{Generics}\s*\(", RegexOptions.Compiled);
+    private static readonly Regex Initializer = new($@"{Prefix}{Modifiers}init[?!]?\s*{Generics}\s*\(", RegexOptions.Compiled);
+    private static readonly Regex Deinitializer = new($@"{Prefix}deinit\s*\{{", RegexOptions.Compiled);
+    private static readonly Regex Subscript = new($@"{Prefix}{Modifiers}subscript\s*{Generics}\s*\(", RegexOptions.Compiled);
+    private static readonly Regex Property = new($@"{Prefix}{Modifiers}(?<keyword>var|let)\s+{Name}", RegexOptions.Compiled);
+    private static readonly Regex TypeDeclaration = new($@"{Prefix}{Modifiers}(?<keyword>class|struct|enum|protocol|actor|extension)\s+(?<name>[\w.]+|`[^`]+`)", RegexOptions.Compiled);
+    private static readonly Regex TypeAlias = new($@"{Prefix}{Modifiers}(?:typealias|associatedtype)\s+{Name}", RegexOptions.Compiled);
+    private static readonly Regex Case = new(@"^\s*(?:@\w+\s+)*(?:indirect\s+)?case\s+", RegexOptions.Compiled);
+    private static readonly Regex CaseName = new(@"^\s*(?<name>\w+|`[^`]+`)", RegexOptions.Compiled);
+    private static readonly Regex AttributeLine = new($@"^\s*@\w+(?:\.\w+)*(?:\([^()]*\))?(?:\s+@\w+(?:\.\w+)*(?:\([^()]*\))?)*\s*$", RegexOptions.Compiled);
+    private static readonly Regex Visibility = new(@"\b(?<visibility>open|public|private|fileprivate|internal|package)\b(?!\()", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Swift sources
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".swift", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a Swift file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(BlankMultilineStrings(lines), ".swift");
+        var found = new List<Found>();
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (!string.IsNullOrWhiteSpace(masked[i]))
+                found.AddRange(Read(lines, masked, i));
+        }
+
+        // Declarations inside function bodies, accessors and closures are locals
+        var kept = new List<Found>();
+        foreach (var declaration in found)
+        {
+            var container = Innermost(found, declaration);
+            if (container != null && container.Kind is not ("class" or "struct" or "enum" or "interface" or "extension"))
+                continue;
+            if (declaration.Kind == "enum_member" && container?.Kind != "enum")
+                continue;
+            declaration.Container = container;
+            kept.Add(declaration);
+        }
+        foreach (var initializer in kept.Where(d => d.Kind == "constructor" && d.Container != null))
+        {
+            initializer.Name = initializer.Container!.Name;
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in kept.OrderBy(d => d.Line).ThenBy(d => d.Column))
+        {
+            var kind = declaration.Kind switch
+            {
+                "function" when declaration.Container != null => "method",
+                "variable" or "constant" when declaration.Container != null => "property",
+                _ => declaration.Kind
+            };
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = kind,
+                Language = "swift",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = declaration.EndLine,
+                EndColumn = lines[declaration.EndLine - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = declaration.Visibility
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Swift file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The property wrapper attributes of a property signature (Published and AppStorage for
+    /// <c>@Published @AppStorage("name") var name</c>), without arguments
+    /// </summary>
+    public static List<string> PropertyWrappersOf(string? signature)
+    {
+        if (string.IsNullOrEmpty(signature) || Property.Match(signature) is not { Success: true } property)
+            return new List<string>();
+
+        return Regex.Matches(signature[..property.Groups["modifiers"].Index], @"@(?<name>\w+(?:\.\w+)*)")
+            .Select(m => m.Groups["name"].Value)
+            .Where(name => !BuiltInAttributes.Contains(name))
+            .ToList();
+    }
+
+    private static readonly HashSet<string> BuiltInAttributes = new(StringComparer.Ordinal)
+    {
+        "objc", "nonobjc", "available", "MainActor", "IBOutlet", "IBInspectable", "GKInspectable", "NSManaged", "NSCopying",
+        "inlinable", "usableFromInline", "preconcurrency", "discardableResult", "Sendable", "unchecked", "frozen", "dynamicCallable"
+    };
+
+    private static IEnumerable<Found> Read(string[] lines, string[] masked, int index)
+    {
+        var line = masked[index];
+        Match match;
+        string kind;
+        string? name = null;
+
+        if ((match = Function.Match(line)).Success)
+        {
+            kind = "function";
+        }
+        else if ((match = Initializer.Match(line)).Success)
+        {
+            kind = "constructor";
+            name = "init";
+        }
+        else if ((match = Deinitializer.Match(line)).Success)
+        {
+            kind = "function";
+            name = "deinit";
+        }
+        else if ((match = Subscript.Match(line)).Success)
+        {
+            kind = "function";
+            name = "subscript";
+        }
+        else if ((match = Property.Match(line)).Success)
+        {
+            kind = match.Groups["keyword"].Value == "let" ? "constant" : "variable";
+        }
+        else if ((match = TypeDeclaration.Match(line)).Success)
+        {
+            kind = match.Groups["keyword"].Value switch
+            {
+                "protocol" => "interface",
+                "actor" => "class",
+                var keyword => keyword
+            };
+        }
+        else if ((match = TypeAlias.Match(line)).Success)
+        {
+            kind = "type";
+        }
+        else if ((match = Case.Match(line)).Success)
+        {
+            return Cases(lines, masked, index, match.Length);
+        }
+        else
+        {
+            return Enumerable.Empty<Found>();
+        }
+
+        var nameGroup = match.Groups["name"];
+        if (name == null)
+        {
+            name = lines[index].Substring(nameGroup.Index, nameGroup.Length).Trim('`');
+            // extension Outer.Inner extends Inner
+            if (kind == "extension" && name.Contains('.'))
+                name = name[(name.LastIndexOf('.') + 1)..];
+        }
+
+        var end = kind is "variable" or "constant" or "type" ? ExpressionEnd(lines, masked, index) : EndOf(masked, index);
+        return new[]
+        {
+            new Found
+            {
+                Kind = kind,
+                Name = name,
+                Line = index + 1,
+                Column = nameGroup.Success ? nameGroup.Index : match.Index + line.Length - line.TrimStart().Length,
+                EndLine = end + 1,
+                Signature = Signature(lines, index),
+                Visibility = VisibilityOf(match.Groups["modifiers"].Value)
+            }
+        };
+    }
+
+    /// <summary>
+    /// The cases one <c>case</c> declaration lists: <c>case small, medium(Int), large = "L"</c>
+    /// </summary>
+    private static IEnumerable<Found> Cases(string[] lines, string[] masked, int index, int start)
+    {
+        var end = EndOf(masked, index);
+        var depth = 0;
+        var pending = true;
+        for (var i = index; i <= end; i++)
+        {
+            var text = masked[i];
+            for (var c = i == index ? start : 0; c < text.Length; c++)
+            {
+                var ch = text[c];
+                if (pending && depth == 0 && !char.IsWhiteSpace(ch))
+                {
+                    pending = false;
+                    if (CaseName.Match(text[c..]) is { Success: true } entry)
+                    {
+                        var (endLine, endColumn) = CaseEnd(masked, i, c, end);
+                        var signature = endLine == i
+                            ? lines[i][c..endColumn]
+                            : string.Join(" ", new[] { lines[i][c..] }.Concat(lines[(i + 1)..endLine]).Append(lines[endLine][..endColumn]).Select(l => l.Trim()));
+                        yield return new Found
+                        {
+                            Kind = "enum_member",
+                            Name = entry.Groups["name"].Value.Trim('`'),
+                            Line = i + 1,
+                            Column = c,
+                            EndLine = endLine + 1,
+                            Signature = signature.Trim(),
+                            Visibility = "public"
+                        };
+                    }
+                }
+                if (ch is '(' or '[')
+                    depth++;
+                else if (ch is ')' or ']')
+                    depth--;
+                else if (ch == ',' && depth == 0)
+                    pending = true;
+            }
+        }
+    }
+
+    /// <summary>
+    /// The line (0-based) and column where a case ends: the comma separating it from the next case, or the end
+    /// of the line its associated values close on
+    /// </summary>
+    private static (int Line, int Column) CaseEnd(string[] masked, int line, int column, int end)
+    {
+        var depth = 0;
+        for (var i = line; i <= end; i++)
+        {
+            var text = masked[i];
+            for (var c = i == line ? column : 0; c < text.Length; c++)
+            {
+                if (text[c] is '(' or '[')
+                    depth++;
+                else if (text[c] is ')' or ']')
+                    depth--;
+                else if (text[c] == ',' && depth == 0)
+                    return (i, c);
+            }
+            if (depth == 0)
+                return (i, text.TrimEnd().Length);
+        }
+        return (end, masked[end].TrimEnd().Length);
+    }
+
+    /// <summary>
+    /// The innermost other declaration whose extent holds <paramref name="declaration"/>
+    /// </summary>
+    private static Found? Innermost(List<Found> found, Found declaration) =>
+        found.Where(d => d != declaration && d.Kind != "enum_member" && d.Line <= declaration.Line && d.EndLine >= declaration.Line
+                && (d.Line < declaration.Line || d.Column < declaration.Column))
+            .OrderByDescending(d => d.Line)
+            .ThenByDescending(d => d.Column)
+            .FirstOrDefault();
+
+    /// <summary>
+    /// The line (0-based) closing a declaration: its matching brace, or the end of a header with no body
+    /// (protocol requirements, stored properties)
+    /// </summary>
+    private static int EndOf(string[] masked, int index)
+    {
+        var braces = 0;
+        var parens = 0;
+        var opened = false;
+        for (var i = index; i < masked.Length; i++)
+        {
+            var text = masked[i];
+            foreach (var ch in text)
+            {
+                switch (ch)
+                {
+                    case '{':
+                        braces++;
+                        opened = true;
+                        break;
+                    case '}':
+                        braces--;
+                        break;
+                    case '(' or '[':
+                        parens++;
+                        break;
+                    case ')' or ']':
+                        parens--;
+                        break;
+                }
+                if (opened && braces == 0)
+                    return i;
+            }
+            if (opened || parens > 0)
+                continue;
+
+            // Conformances, effects, return types and where clauses may continue the header on the next line
+            var trimmed = text.TrimEnd();
+            var next = NextCode(masked, i);
+            if (trimmed.EndsWith('=') || trimmed.EndsWith(':') || trimmed.EndsWith(',') || trimmed.EndsWith("->", StringComparison.Ordinal)
+                || next != null && (next.StartsWith('{') || next.StartsWith(':') || next.StartsWith("->", StringComparison.Ordinal)
+                    || next.StartsWith("where ", StringComparison.Ordinal) || next.StartsWith("throws", StringComparison.Ordinal)
+                    || next.StartsWith("async", StringComparison.Ordinal)))
+                continue;
+            return i;
+        }
+        return masked.Length - 1;
+    }
+
+    /// <summary>
+    /// The last line (0-based) of a property or alias, including an initializer chained onto following lines
+    /// </summary>
+    private static int ExpressionEnd(string[] lines, string[] masked, int index)
+    {
+        var end = EndOf(masked, index);
+        if (Regex.Matches(lines[index], "\"\"\"").Count % 2 == 1)
+        {
+            // A multi-line string literal runs to its closing delimiter
+            end = index + 1;
+            while (end < lines.Length - 1 && !lines[end].Contains("\"\"\"", StringComparison.Ordinal))
+                end++;
+        }
+        for (var next = end + 1; next < masked.Length; next++)
+        {
+            var code = masked[next].Trim();
+            if (code.Length == 0)
+                continue;
+            if (!code.StartsWith('.') || code.StartsWith("..", StringComparison.Ordinal))
+                break;
+            end = EndOf(masked, next);
+            next = end;
+        }
+        return end;
+    }
+
+    private static string? NextCode(string[] masked, int index)
+    {
+        for (var i = index + 1; i < masked.Length; i++)
+        {
+            var code = masked[i].Trim();
+            if (code.Length > 0)
+                return code;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Lines inside <c>"""</c> string literals blanked, so their text is not read as declarations
+    /// </summary>
+    private static string[] BlankMultilineStrings(string[] lines)
+    {
+        var result = lines.ToArray();
+        var inString = false;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var delimiters = Regex.Matches(lines[i], "\"\"\"").Count;
+            if (inString && delimiters == 0)
+                result[i] = new string(' ', lines[i].Length);
+            if (delimiters % 2 == 1)
+                inString = !inString;
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The declaration line, with attributes written on the lines above it (property wrappers, @MainActor) in front
+    /// </summary>
+    private static string Signature(string[] lines, int index)
+    {
+        var first = index;
+        while (first > 0 && AttributeLine.IsMatch(lines[first - 1]))
+            first--;
+        var signature = string.Join(" ", lines[first..(index + 1)].Select(l => l.Trim()));
+        if (signature.EndsWith('{'))
+            signature = signature[..^1].TrimEnd();
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    private static string VisibilityOf(string modifiers) =>
+        Visibility.Match(modifiers) is { Success: true } visibility
+            ? visibility.Groups["visibility"].Value switch
+            {
+                "open" or "public" => "public",
+                "private" or "fileprivate" => "private",
+                _ => "internal"
+            }
+            : "internal";
+
+    /// <summary>
+    /// The /// lines or /** */ block right above a declaration, skipping attribute lines
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var i = index - 1;
+        while (i >= 0 && AttributeLine.IsMatch(lines[i]))
+            i--;
+        if (i < 0)
+            return null;
+
+        var end = i;
+        if (lines[i].TrimStart().StartsWith("///", StringComparison.Ordinal))
+        {
+            while (i > 0 && lines[i - 1].TrimStart().StartsWith("///", StringComparison.Ordinal))
+                i--;
+            return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+        }
+        if (!lines[i].TrimEnd().EndsWith("*/", StringComparison.Ordinal))
+            return null;
+        while (i >= 0 && !lines[i].TrimStart().StartsWith("/**", StringComparison.Ordinal))
+        {
+            if (lines[i].TrimStart().StartsWith("/*", StringComparison.Ordinal))
+                return null;
+            i--;
+        }
+        return i < 0 ? null : string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"swift:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+        public string Name { get; set; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; init; }
+        public string Signature { get; init; } = string.Empty;
+        public string Visibility { get; init; } = "internal";
+        public Found? Container { get; set; }
+    }
+}
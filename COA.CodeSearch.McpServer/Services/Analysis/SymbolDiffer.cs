@@ -0,0 +1,297 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A symbol-level difference between two versions of the code
+/// </summary>
+public class SymbolChange
+{
+    /// <summary>
+    /// added, removed, renamed, moved (to another file or type) or modified (signature or visibility changed)
+    /// </summary>
+    public string Change { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+    public string? OldName { get; set; }
+    public string Kind { get; set; } = string.Empty;
+    public string? Container { get; set; }
+
+    /// <summary>
+    /// Container before the change, for modified, renamed and moved symbols
+    /// </summary>
+    public string? OldContainer { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public string? OldFilePath { get; set; }
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Line in the old version, for modified, renamed and moved symbols
+    /// </summary>
+    public int? OldLine { get; set; }
+
+    public string? Signature { get; set; }
+    public string? OldSignature { get; set; }
+    public bool IsPublic { get; set; }
+    public bool WasPublic { get; set; }
+
+    /// <summary>
+    /// Rename/move confidence between 0 and 1; null for other changes
+    /// </summary>
+    public double? Confidence { get; set; }
+
+    /// <summary>
+    /// Removes, renames or changes part of the public API
+    /// </summary>
+    public bool IsBreaking => WasPublic && Change switch
+    {
+        "added" => false,
+        "moved" => OldContainer != Container || !IsPublic,
+        "modified" => !IsPublic || OldSignature != Signature,
+        _ => true
+    };
+}
+
+/// <summary>
+/// Compares declarations from two versions and pairs removed/added symbols into renames
+/// when their bodies and signatures are similar enough. The heuristics follow the rename
+/// golden masters: <c>Helper</c> → <c>HelperHandler</c> keeps its body, so it is a rename,
+/// not a removal plus an unrelated addition.
+/// </summary>
+public static class SymbolDiffer
+{
+    public const double DefaultRenameThreshold = 0.6;
+
+    private static readonly Regex Token = new(@"\w+|[^\s\w]", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Compares one file's declarations. Pass an empty list for an added or deleted file.
+    /// </summary>
+    public static List<SymbolChange> Compare(
+        string? oldPath,
+        IReadOnlyList<Declaration> oldDeclarations,
+        string newPath,
+        IReadOnlyList<Declaration> newDeclarations,
+        double renameThreshold = DefaultRenameThreshold)
+    {
+        return CompareFiles(new[] { (oldPath, oldDeclarations, newPath, newDeclarations) }, renameThreshold);
+    }
+
+    /// <summary>
+    /// Compares several changed files at once so symbols moved between files are reported as
+    /// renames (or moves) rather than a removal and an addition
+    /// </summary>
+    public static List<SymbolChange> CompareFiles(
+        IEnumerable<(string? OldPath, IReadOnlyList<Declaration> Old, string NewPath, IReadOnlyList<Declaration> New)> files,
+        double renameThreshold = DefaultRenameThreshold)
+    {
+        var changes = new List<SymbolChange>();
+        var removed = new List<(string Path, Declaration Declaration)>();
+        var added = new List<(string Path, Declaration Declaration)>();
+
+        foreach (var (oldPath, oldDeclarations, newPath, newDeclarations) in files)
+        {
+            var unmatchedNew = newDeclarations.ToList();
+            foreach (var old in oldDeclarations)
+            {
+                // Overloads share a key; prefer the one with the same signature
+                var candidates = unmatchedNew.Where(n => SameIdentity(old, n)).ToList();
+                var match = candidates.FirstOrDefault(n => n.Signature == old.Signature) ?? candidates.FirstOrDefault();
+                if (match == null)
+                {
+                    removed.Add((oldPath ?? newPath, old));
+                    continue;
+                }
+
+                unmatchedNew.Remove(match);
+                if (match.Signature != old.Signature || match.IsPublic != old.IsPublic)
+                {
+                    changes.Add(CreateChange("modified", oldPath ?? newPath, old, newPath, match));
+                }
+            }
+
+            added.AddRange(unmatchedNew.Select(d => (newPath, d)));
+        }
+
+        changes.AddRange(PairRenames(removed, added, renameThreshold));
+        return changes;
+    }
+
+    /// <summary>
+    /// Pairs removed and added declarations, highest confidence first; unpaired declarations
+    /// are reported as removed and added
+    /// </summary>
+    public static List<SymbolChange> PairRenames(
+        IReadOnlyList<(string Path, Declaration Declaration)> removed,
+        IReadOnlyList<(string Path, Declaration Declaration)> added,
+        double renameThreshold = DefaultRenameThreshold)
+    {
+        var changes = new List<SymbolChange>();
+        var scored = new List<(int Old, int New, double Score)>();
+
+        for (var i = 0; i < removed.Count; i++)
+        {
+            for (var j = 0; j < added.Count; j++)
+            {
+                if (!CompatibleKinds(removed[i].Declaration.Kind, added[j].Declaration.Kind))
+                    continue;
+
+                var score = RenameConfidence(removed[i].Declaration, added[j].Declaration);
+                if (score >= renameThreshold)
+                    scored.Add((i, j, score));
+            }
+        }
+
+        var pairedOld = new HashSet<int>();
+        var pairedNew = new HashSet<int>();
+        foreach (var (oldIndex, newIndex, score) in scored.OrderByDescending(s => s.Score))
+        {
+            if (pairedOld.Contains(oldIndex) || pairedNew.Contains(newIndex))
+                continue;
+
+            pairedOld.Add(oldIndex);
+            pairedNew.Add(newIndex);
+
+            var (oldFile, old) = removed[oldIndex];
+            var (newFile, renamed) = added[newIndex];
+            // Same name in another file or type is a move; identical identities were matched earlier
+            var change = CreateChange(old.Name == renamed.Name ? "moved" : "renamed", oldFile, old, newFile, renamed);
+            change.Confidence = Math.Round(score, 2);
+            changes.Add(change);
+        }
+
+        for (var i = 0; i < removed.Count; i++)
+        {
+            if (!pairedOld.Contains(i))
+                changes.Add(CreateChange("removed", removed[i].Path, removed[i].Declaration, null, null));
+        }
+        for (var j = 0; j < added.Count; j++)
+        {
+            if (!pairedNew.Contains(j))
+                changes.Add(CreateChange("added", null, null, added[j].Path, added[j].Declaration));
+        }
+
+        return changes;
+    }
+
+    /// <summary>
+    /// How likely <paramref name="renamed"/> is <paramref name="old"/> under a new name (0-1).
+    /// Bodies and signatures are compared with each symbol's own name masked, so a rename
+    /// alone doesn't lower the score; similar names add a little on top.
+    /// </summary>
+    public static double RenameConfidence(Declaration old, Declaration renamed)
+    {
+        var oldNames = new[] { old.Name, old.Container };
+        var newNames = new[] { renamed.Name, renamed.Container };
+
+        var signature = Similarity(Tokenize(old.Signature, oldNames), Tokenize(renamed.Signature, newNames));
+        var name = NameSimilarity(old.Name, renamed.Name);
+
+        var oldBody = Tokenize(old.Body, oldNames);
+        var newBody = Tokenize(renamed.Body, newNames);
+
+        // One-line declarations (fields, abstract members) have nothing beyond the signature
+        if (oldBody.Count <= 3 || newBody.Count <= 3)
+            return 0.6 * signature + 0.4 * name;
+
+        var body = Similarity(oldBody, newBody);
+        var confidence = 0.55 * body + 0.3 * signature + 0.15 * name;
+
+        // Different containers make an identical body less conclusive (copy vs. rename)
+        if (old.Container != renamed.Container && old.Container != null && renamed.Container != null)
+            confidence *= 0.9;
+
+        return Math.Min(1.0, confidence);
+    }
+
+    /// <summary>
+    /// 1 for equal names, high when one name extends the other (Helper → HelperHandler), otherwise edit-distance based
+    /// </summary>
+    public static double NameSimilarity(string a, string b)
+    {
+        if (a.Equals(b, StringComparison.Ordinal))
+            return 1.0;
+
+        var distance = 1.0 - (double)Levenshtein(a.ToLowerInvariant(), b.ToLowerInvariant()) / Math.Max(a.Length, b.Length);
+        if (a.Contains(b, StringComparison.OrdinalIgnoreCase) || b.Contains(a, StringComparison.OrdinalIgnoreCase))
+            distance = Math.Max(distance, 0.8);
+
+        return distance;
+    }
+
+    private static bool SameIdentity(Declaration a, Declaration b) =>
+        a.Name == b.Name && a.Container == b.Container && CompatibleKinds(a.Kind, b.Kind);
+
+    private static bool CompatibleKinds(string a, string b) =>
+        a == b ||
+        (a is "method" or "function") && (b is "method" or "function") ||
+        (a is "class" or "struct" or "record") && (b is "class" or "struct" or "record");
+
+    private static SymbolChange CreateChange(string change, string? oldPath, Declaration? old, string? newPath, Declaration? current)
+    {
+        var primary = current ?? old!;
+        return new SymbolChange
+        {
+            Change = change,
+            Name = primary.Name,
+            OldName = old != null && current != null && old.Name != current.Name ? old.Name : null,
+            Kind = primary.Kind,
+            Container = primary.Container,
+            OldContainer = current != null ? old?.Container : null,
+            FilePath = newPath ?? oldPath ?? string.Empty,
+            OldFilePath = old != null && current != null && oldPath != newPath ? oldPath : null,
+            Line = primary.Line,
+            OldLine = current != null ? old?.Line : null,
+            Signature = current?.Signature,
+            OldSignature = old?.Signature,
+            IsPublic = current?.IsPublic ?? false,
+            WasPublic = old?.IsPublic ?? false
+        };
+    }
+
+    private static Dictionary<string, int> Tokenize(string text, string?[] maskedNames)
+    {
+        var counts = new Dictionary<string, int>(StringComparer.Ordinal);
+        foreach (Match match in Token.Matches(text))
+        {
+            var token = maskedNames.Contains(match.Value) ? "$" : match.Value;
+            counts[token] = counts.GetValueOrDefault(token) + 1;
+        }
+
+        return counts;
+    }
+
+    /// <summary>
+    /// Dice coefficient over token multisets
+    /// </summary>
+    private static double Similarity(Dictionary<string, int> a, Dictionary<string, int> b)
+    {
+        var totalA = a.Values.Sum();
+        var totalB = b.Values.Sum();
+        if (totalA == 0 && totalB == 0)
+            return 1.0;
+
+        var shared = a.Sum(kv => Math.Min(kv.Value, b.GetValueOrDefault(kv.Key)));
+        return 2.0 * shared / (totalA + totalB);
+    }
+
+    private static int Levenshtein(string a, string b)
+    {
+        var previous = Enumerable.Range(0, b.Length + 1).ToArray();
+        var current = new int[b.Length + 1];
+
+        for (var i = 1; i <= a.Length; i++)
+        {
+            current[0] = i;
+            for (var j = 1; j <= b.Length; j++)
+            {
+                var cost = a[i - 1] == b[j - 1] ? 0 : 1;
+                current[j] = Math.Min(Math.Min(current[j - 1] + 1, previous[j] + 1), previous[j - 1] + cost);
+            }
+            (previous, current) = (current, previous);
+        }
+
+        return previous[b.Length];
+    }
+}
@@ -0,0 +1,106 @@
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// The corrections made to julie-codesearch's symbols, picked by file: the Go repairs, and the declarations read by
+/// the Kotlin, Swift, Zig, Scala, SQL, Protobuf, GraphQL, Terraform, Dockerfile, Markdown, Vue and Svelte extractors.
+/// The indexer runs them over every file in one pass and the watcher over each changed file.
+/// </summary>
+public static class SymbolRepairs
+{
+    private static readonly (Func<string, bool> Handles, Func<string, string, List<JulieSymbol>, List<JulieSymbol>> Repair)[] Repairs =
+    {
+        (path => path.EndsWith(".go", StringComparison.OrdinalIgnoreCase), RepairGo),
+        (KotlinSymbols.Handles, KotlinSymbols.Repair),
+        (SwiftSymbols.Handles, SwiftSymbols.Repair),
+        (ZigSymbols.Handles, ZigSymbols.Repair),
+        (ScalaSymbols.Handles, ScalaSymbols.Repair),
+        (SqlSymbols.Handles, SqlSymbols.Repair),
+        (ProtoSymbols.Handles, ProtoSymbols.Repair),
+        (GraphQLSymbols.Handles, GraphQLSymbols.Repair),
+        (TerraformSymbols.Handles, TerraformSymbols.Repair),
+        (DockerfileSymbols.Handles, DockerfileSymbols.Repair),
+        (MarkdownSymbols.Handles, MarkdownSymbols.Repair),
+        (VueSfcSymbols.Handles, VueSfcSymbols.Repair),
+        (SvelteSymbols.Handles, SvelteSymbols.Repair)
+    };
+
+    /// <summary>
+    /// True for files whose symbols one of the repairs corrects
+    /// </summary>
+    public static bool Handles(string filePath) => Repairs.Any(r => r.Handles(filePath));
+
+    /// <summary>
+    /// The symbols of a file corrected by the repair for its language. Returns <paramref name="symbols"/> itself
+    /// when no repair handles the file or nothing needed correcting.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols)
+    {
+        foreach (var (handles, repair) in Repairs)
+        {
+            if (handles(filePath))
+                return repair(filePath, content, symbols);
+        }
+        return symbols;
+    }
+
+    /// <summary>
+    /// <paramref name="symbols"/> with the <paramref name="declared"/> symbols they lack added, symbols ending before
+    /// their declaration extended to its end and parents filled in. A symbol stands for a declaration with the same
+    /// name and start line and keeps its ID. Returns <paramref name="symbols"/> itself when nothing changed.
+    /// </summary>
+    public static List<JulieSymbol> Merge(List<JulieSymbol> symbols, List<JulieSymbol> declared)
+    {
+        var byDeclared = new Dictionary<string, JulieSymbol>(StringComparer.Ordinal);
+        var repaired = symbols.ToList();
+        var changed = false;
+
+        foreach (var declaration in declared)
+        {
+            var existing = repaired.FirstOrDefault(s => s.Name == declaration.Name && s.StartLine == declaration.StartLine);
+            if (existing == null)
+            {
+                existing = declaration;
+                repaired.Add(existing);
+                changed = true;
+            }
+            else if (existing.EndLine < declaration.EndLine)
+            {
+                existing.EndLine = declaration.EndLine;
+                existing.EndColumn = declaration.EndColumn;
+                changed = true;
+            }
+            byDeclared[declaration.Id] = existing;
+        }
+
+        // Parents point at whichever symbol now stands for the declaration
+        foreach (var declaration in declared.Where(d => d.ParentId != null))
+        {
+            if (!byDeclared.TryGetValue(declaration.ParentId!, out var parent))
+                continue;
+
+            var symbol = byDeclared[declaration.Id];
+            if (symbol.ParentId == null || ReferenceEquals(symbol, declaration) && symbol.ParentId != parent.Id)
+            {
+                symbol.ParentId = parent.Id;
+                changed = true;
+            }
+        }
+
+        return changed ? repaired.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList() : symbols;
+    }
+
+    /// <summary>
+    /// Symbols read out of a cgo preamble become one opaque block (<see cref="GoCgo"/>) and declarations lost
+    /// around Go 1.22+ constructs are recovered (<see cref="GoSyntax"/>)
+    /// </summary>
+    private static List<JulieSymbol> RepairGo(string filePath, string content, List<JulieSymbol> symbols)
+    {
+        var cgo = GoCgo.FindPreamble(content) != null;
+        if (!cgo && GoSyntax.FindFeatures(content).Count == 0)
+            return symbols;
+
+        return GoSyntax.Repair(filePath, content, cgo ? GoCgo.Repair(filePath, content, symbols) : symbols);
+    }
+}
@@ -0,0 +1,344 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Terraform declarations read from .tf files: resources and data sources (named by their address,
+/// <c>aws_instance.web</c> and <c>data.aws_ami.ubuntu</c>), modules, input variables, outputs, locals and providers.
+/// <see cref="Address"/> and <see cref="References"/> find where each is used - <c>var.cluster_name</c>,
+/// <c>module.network.vpc_id</c> - since julie-codesearch extracts no identifiers from HCL.
+/// </summary>
+public static class TerraformSymbols
+{
+    private static readonly Regex Block = new(
+        @"^\s*(?<keyword>resource|data|module|variable|output|provider|locals|terraform)\b(?<labels>(?:\s*""[^""]*"")*)\s*\{?", RegexOptions.Compiled);
+    private static readonly Regex Label = new(@"""(?<label>[^""]*)""", RegexOptions.Compiled);
+    private static readonly Regex Attribute = new(@"^\s*(?<name>[A-Za-z_][\w-]*)\s*=(?!=)", RegexOptions.Compiled);
+    private static readonly Regex Heredoc = new(@"<<-?\s*(?<marker>[A-Za-z_]\w*)\s*$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Terraform configuration files
+    /// </summary>
+    public static bool Handles(string filePath) => filePath.EndsWith(".tf", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// True for the files that can use Terraform declarations: configuration and variable definition files
+    /// </summary>
+    public static bool CanReference(string filePath) =>
+        Handles(filePath) || filePath.EndsWith(".tfvars", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a .tf file, in source order
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = Mask(lines, strings: true);
+        var found = new List<Found>();
+
+        Found? block = null;
+        string? blockKeyword = null;
+        Found? local = null;
+        var depth = 0;
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var line = masked[i];
+            Match match;
+
+            if (depth == 0 && (match = Block.Match(lines[i])).Success && masked[i].TrimStart().StartsWith(match.Groups["keyword"].Value, StringComparison.Ordinal))
+            {
+                var keyword = match.Groups["keyword"].Value;
+                var labels = Label.Matches(match.Groups["labels"].Value).Select(m => m.Groups["label"].Value).ToList();
+                var name = keyword switch
+                {
+                    "resource" when labels.Count >= 2 => $"{labels[0]}.{labels[1]}",
+                    "data" when labels.Count >= 2 => $"data.{labels[0]}.{labels[1]}",
+                    "module" or "variable" or "output" or "provider" when labels.Count >= 1 => labels[0],
+                    _ => null
+                };
+                block = name == null ? null : Declare(found, Kind(keyword), name, i, lines[i].IndexOf('"'), Signature(lines[i]));
+                blockKeyword = keyword;
+            }
+            else if (depth == 1 && blockKeyword == "locals" && (match = Attribute.Match(line)).Success)
+            {
+                var name = match.Groups["name"].Value;
+                local = Declare(found, "constant", name, i, match.Groups["name"].Index, $"local.{name}");
+            }
+            else if (depth == 1 && block != null && blockKeyword is "variable" or "output" && block.Description == null
+                     && (match = Regex.Match(lines[i], @"^\s*description\s*=\s*""(?<text>(?:[^""\\]|\\.)*)""")).Success)
+            {
+                block.Description = match.Groups["text"].Value;
+            }
+
+            foreach (var c in line)
+            {
+                if (c == '{' || c == '[' || c == '(')
+                {
+                    depth++;
+                }
+                else if ((c == '}' || c == ']' || c == ')') && depth > 0)
+                {
+                    depth--;
+                    if (depth == 0 && block != null)
+                    {
+                        block.EndLine = i + 1;
+                        block = null;
+                    }
+                }
+            }
+
+            // A local's value ends where its line does, unless it opens a map or list
+            if (local != null && depth <= 1)
+            {
+                local.EndLine = i + 1;
+                local = null;
+            }
+        }
+
+        return found.Select(declaration => new JulieSymbol
+        {
+            Id = StableId(filePath, declaration.Name, declaration.Line),
+            Name = declaration.Name,
+            Kind = declaration.Kind,
+            Language = "terraform",
+            FilePath = filePath,
+            StartLine = declaration.Line,
+            StartColumn = Math.Max(0, declaration.Column),
+            EndLine = Math.Max(declaration.Line, declaration.EndLine),
+            EndColumn = lines[Math.Max(declaration.Line, declaration.EndLine) - 1].Length,
+            Signature = declaration.Signature,
+            DocComment = DocComment(lines, declaration.Line - 1) ?? declaration.Description,
+            Visibility = "public"
+        }).ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a .tf file with the declarations julie-codesearch missed added. Extracted symbols keep
+    /// their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// How expressions in the declaring module refer to a symbol: <c>var.cluster_name</c>, <c>local.tags</c>,
+    /// <c>module.network</c>, <c>aws_instance.web</c>, <c>data.aws_ami.ubuntu</c>. Outputs are read by the
+    /// calling module through its module block, <c>module.*.vpc_id</c>. Null for providers.
+    /// </summary>
+    public static string? Address(JulieSymbol symbol)
+    {
+        var keyword = symbol.Signature?.Split(' ', '.')[0];
+        return keyword switch
+        {
+            "variable" => $"var.{symbol.Name}",
+            "local" => $"local.{symbol.Name}",
+            "module" => $"module.{symbol.Name}",
+            "output" => $"module.*.{symbol.Name}",
+            "resource" or "data" => symbol.Name,
+            _ => null
+        };
+    }
+
+    /// <summary>
+    /// Names a symbol may be stored under for an address as written in an expression, attributes included:
+    /// var.cluster_name.id → cluster_name, aws_instance.web[0].private_ip → aws_instance.web,
+    /// module.network.vpc_id → network and vpc_id
+    /// </summary>
+    public static List<string> CandidateNames(string written)
+    {
+        // aws_instance.web[0].id and module.nodes["blue"].id index into the resource or module
+        var parts = Regex.Replace(written, @"\[[^\]]*\]", string.Empty).Split('.');
+        var candidates = new List<string> { written };
+        void Add(string name)
+        {
+            if (name.Length > 0 && !candidates.Contains(name))
+                candidates.Add(name);
+        }
+
+        switch (parts[0])
+        {
+            case "var" or "local" when parts.Length >= 2:
+                Add(parts[1]);
+                break;
+            case "module" when parts.Length >= 2:
+                Add(parts[1]);
+                if (parts.Length >= 3)
+                    Add(parts[2]);
+                break;
+            case "data" when parts.Length >= 3:
+                Add(string.Join(".", parts.Take(3)));
+                break;
+            default:
+                if (parts.Length >= 2)
+                    Add(string.Join(".", parts.Take(2)));
+                break;
+        }
+        return candidates;
+    }
+
+    /// <summary>
+    /// Positions in a .tf or .tfvars file that use an <see cref="Address"/>, inside string interpolations and
+    /// heredocs too but not in comments. In .tfvars files a variable's address is its assignment:
+    /// <c>cluster_name = "prod"</c>.
+    /// </summary>
+    public static List<(int Line, int Column)> References(string filePath, string content, string address)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = Mask(lines, strings: false);
+        var pattern = filePath.EndsWith(".tfvars", StringComparison.OrdinalIgnoreCase)
+            ? address.StartsWith("var.", StringComparison.Ordinal)
+                ? new Regex($@"^\s*{Regex.Escape(address[4..])}(?=\s*=)")
+                : null
+            : new Regex($@"(?<![\w.-]){Regex.Escape(address).Replace(@"\*", @"[\w-]+")}(?![\w-])");
+        var result = new List<(int, int)>();
+        if (pattern == null)
+            return result;
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            foreach (Match match in pattern.Matches(masked[i]))
+                result.Add((i + 1, match.Index + match.Length - match.Value.TrimStart().Length));
+        }
+        return result;
+    }
+
+    private static string Kind(string keyword) => keyword switch
+    {
+        "resource" or "data" => "class",
+        "module" => "module",
+        "variable" => "variable",
+        "output" => "property",
+        _ => "namespace"
+    };
+
+    private static Found Declare(List<Found> found, string kind, string name, int index, int column, string signature)
+    {
+        var declaration = new Found
+        {
+            Kind = kind,
+            Name = name,
+            Line = index + 1,
+            Column = column,
+            EndLine = index + 1,
+            Signature = signature
+        };
+        found.Add(declaration);
+        return declaration;
+    }
+
+    private static string Signature(string line)
+    {
+        var signature = Regex.Replace(line.Trim(), @"\s*\{.*$", string.Empty);
+        return Regex.Replace(signature, @"\s+", " ");
+    }
+
+    /// <summary>
+    /// The lines with <c>#</c>, <c>//</c> and <c>/* */</c> comments blanked out, and string and heredoc contents
+    /// as well when <paramref name="strings"/> is set, so only structure is left
+    /// </summary>
+    private static string[] Mask(string[] lines, bool strings)
+    {
+        var masked = new string[lines.Length];
+        var inComment = false;
+        string? heredoc = null;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i].ToCharArray();
+            if (heredoc != null)
+            {
+                if (lines[i].Trim() == heredoc)
+                    heredoc = null;
+                else if (strings)
+                    Array.Fill(line, ' ');
+                masked[i] = new string(line);
+                continue;
+            }
+
+            var inString = false;
+            for (var j = 0; j < line.Length; j++)
+            {
+                var next = j + 1 < line.Length ? line[j + 1] : '\0';
+                if (inComment)
+                {
+                    if (line[j] == '*' && next == '/')
+                    {
+                        inComment = false;
+                        line[j + 1] = ' ';
+                    }
+                    line[j] = ' ';
+                }
+                else if (inString)
+                {
+                    if (line[j] == '\\' && j + 1 < line.Length)
+                    {
+                        if (strings)
+                            line[j] = line[j + 1] = ' ';
+                        j++;
+                        continue;
+                    }
+                    if (line[j] == '"')
+                        inString = false;
+                    if (strings)
+                        line[j] = ' ';
+                }
+                else if (line[j] == '"')
+                {
+                    inString = true;
+                    if (strings)
+                        line[j] = ' ';
+                }
+                else if (line[j] == '#' || line[j] == '/' && next == '/')
+                {
+                    Array.Fill(line, ' ', j, line.Length - j);
+                    break;
+                }
+                else if (line[j] == '/' && next == '*')
+                {
+                    inComment = true;
+                    line[j] = line[j + 1] = ' ';
+                    j++;
+                }
+            }
+
+            masked[i] = new string(line);
+            if (Heredoc.Match(masked[i]) is { Success: true } start)
+                heredoc = start.Groups["marker"].Value;
+        }
+        return masked;
+    }
+
+    /// <summary>
+    /// The <c>#</c> or <c>//</c> comment lines right above a declaration
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        static bool IsComment(string line) =>
+            line.TrimStart().StartsWith('#') || line.TrimStart().StartsWith("//", StringComparison.Ordinal);
+
+        var end = index - 1;
+        if (end < 0 || !IsComment(lines[end]))
+            return null;
+
+        var i = end;
+        while (i > 0 && IsComment(lines[i - 1]))
+            i--;
+        return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"terraform:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        public string Kind { get; init; } = string.Empty;
+        public string Name { get; init; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; set; }
+        public string Signature { get; init; } = string.Empty;
+        public string? Description { get; set; }
+    }
+}
@@ -0,0 +1,455 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A test, benchmark, fuzz target or example declared in a test file
+/// </summary>
+public class TestCase
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// test, benchmark, fuzz or example
+    /// </summary>
+    public string Kind { get; set; } = "test";
+
+    /// <summary>
+    /// go, xunit, nunit, mstest, jest, pytest, junit or rust
+    /// </summary>
+    public string Framework { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The enclosing test class or describe block, dotted when nested
+    /// </summary>
+    public string? Container { get; set; }
+
+    public int Line { get; set; }
+    public int EndLine { get; set; }
+
+    /// <summary>
+    /// Subtests (t.Run), table-driven case names and data rows ([InlineData], [TestCase], parametrize)
+    /// </summary>
+    public List<string> Cases { get; set; } = new();
+}
+
+/// <summary>
+/// How a test reaches a symbol
+/// </summary>
+public class TestUse
+{
+    public TestCase Test { get; set; } = new();
+
+    /// <summary>
+    /// calls (the name is called or referenced and its type or package is in the test), mentions (the name
+    /// alone) or named (the test is named after the symbol)
+    /// </summary>
+    public string How { get; set; } = string.Empty;
+
+    public List<int> Lines { get; set; } = new();
+}
+
+/// <summary>
+/// Finds the tests declared in test files - Go Test/Benchmark/Fuzz/Example functions with their subtests and
+/// table cases, xUnit/NUnit/MSTest methods, Jest/Vitest/Mocha it and test blocks, pytest functions, JUnit and
+/// Rust tests - and which of them use a symbol. Test bodies end at their closing brace, or by indentation for Python.
+/// </summary>
+public static class TestDiscovery
+{
+    private static readonly Regex GoTest = new(
+        @"^func\s+(?<name>(?<kind>Test|Benchmark|Fuzz|Example)(?:[A-Z_\d]\w*)?)\s*\((?:\s*\w+\s+\*testing\.(?<type>[TBF])\s*)?\)", RegexOptions.Compiled);
+    private static readonly Regex GoSubtest = new(@"\b\w+\.Run\(\s*""(?<name>[^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex GoTableCase = new(@"^\s*(?:\{\s*)?(?:name|desc|description|title|scenario)\s*:\s*""(?<name>[^""]+)""|^\s*""(?<name>[^""]+)""\s*:\s*\{", RegexOptions.Compiled);
+    private static readonly Regex DotNetAttribute = new(@"^\s*\[(?:[\w.]+\.)?(?<attribute>Fact|Theory|Test|TestCase|TestCaseSource|TestMethod|DataTestMethod|InlineData|DataRow)\b(?:Attribute)?(?<args>\(.*\))?", RegexOptions.Compiled);
+    private static readonly Regex DotNetMethod = new(@"^\s*(?:(?:public|private|protected|internal|static|async|override|virtual)\s+)*(?:Task|ValueTask|void|Task<[^>]+>)\s+(?<name>\w+)\s*\(", RegexOptions.Compiled);
+    private static readonly Regex ClassDeclaration = new(@"^\s*(?:(?:public|private|protected|internal|static|sealed|abstract|partial|open|final|data)\s+)*class\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex JsTest = new(@"^\s*(?<fn>it|test|bench)(?:\.(?:only|skip|concurrent|todo))?(?<each>\.each\b.*?\))?\(\s*(?<q>['""`])(?<name>.+?)\k<q>", RegexOptions.Compiled);
+    private static readonly Regex JsDescribe = new(@"^\s*(?:describe|context|suite)(?:\.(?:only|skip|each\b.*?\)))?\(\s*(?<q>['""`])(?<name>.+?)\k<q>", RegexOptions.Compiled);
+    private static readonly Regex PythonTest = new(@"^(?<indent>\s*)(?:async\s+)?def\s+(?<name>test_?\w*)\s*\(", RegexOptions.Compiled);
+    private static readonly Regex PythonClass = new(@"^(?<indent>\s*)class\s+(?<name>Test\w*)", RegexOptions.Compiled);
+    private static readonly Regex PythonParametrize = new(@"^\s*@pytest\.mark\.parametrize\(", RegexOptions.Compiled);
+    private static readonly Regex JvmAnnotation = new(@"^\s*@(?<annotation>Test|ParameterizedTest|RepeatedTest|TestFactory)\b", RegexOptions.Compiled);
+    private static readonly Regex JvmMethod = new(@"^\s*(?:(?:public|private|protected|internal|static|suspend|final)\s+)*(?:void\s+|fun\s+)(?<name>`[^`]+`|\w+)\s*\(", RegexOptions.Compiled);
+    private static readonly Regex RustAttribute = new(@"^\s*#\[(?:(?<bench>bench)|(?:[\w:]+::)?test)\b", RegexOptions.Compiled);
+    private static readonly Regex RustFunction = new(@"^\s*(?:pub\s+)?(?:async\s+)?fn\s+(?<name>\w+)\s*\(", RegexOptions.Compiled);
+
+    /// <summary>
+    /// The tests in a test file, by its extension
+    /// </summary>
+    public static List<TestCase> FindTests(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        return Path.GetExtension(filePath).ToLowerInvariant() switch
+        {
+            ".go" => FindGoTests(lines),
+            ".cs" or ".fs" or ".vb" => FindDotNetTests(lines),
+            ".js" or ".jsx" or ".ts" or ".tsx" or ".mjs" or ".cjs" or ".mts" or ".cts" => FindJsTests(lines),
+            ".py" => FindPythonTests(lines),
+            ".java" or ".kt" or ".kts" => FindJvmTests(lines),
+            ".rs" => FindRustTests(lines),
+            _ => new List<TestCase>()
+        };
+    }
+
+    /// <summary>
+    /// The tests that use <paramref name="name"/>: by calling or referencing it (with <paramref name="qualifier"/> -
+    /// its type, class or package - somewhere in the test or the file's setup for a confident match), or by being
+    /// named after it. <paramref name="indexedLines"/> are lines where the index saw the name as an identifier.
+    /// </summary>
+    public static List<TestUse> FindUses(IReadOnlyList<TestCase> tests, string[] lines, string name, string? qualifier, IReadOnlySet<int>? indexedLines = null)
+    {
+        var word = new Regex($@"(?<![\w$]){Regex.Escape(name)}(?![\w$])");
+        // Go constructs types through NewT, so NewUserService counts as UserService
+        var qualifierWord = string.IsNullOrEmpty(qualifier) ? null : new Regex($@"(?<![\w$])(?:New)?{Regex.Escape(qualifier)}(?![\w$])");
+
+        // Setup outside any test (fixtures, fields, helpers) counts towards the qualifier for every test in the file
+        var inTests = new HashSet<int>(tests.SelectMany(t => Enumerable.Range(t.Line, Math.Max(1, t.EndLine - t.Line + 1))));
+        var fileQualified = qualifierWord != null && lines.Where((_, i) => !inTests.Contains(i + 1)).Any(l => qualifierWord.IsMatch(l));
+
+        var uses = new List<TestUse>();
+        foreach (var test in tests)
+        {
+            var hits = new List<int>();
+            var qualified = qualifierWord == null || fileQualified;
+            for (var line = test.Line + 1; line <= test.EndLine && line <= lines.Length; line++)
+            {
+                var text = lines[line - 1];
+                if (word.IsMatch(text) || indexedLines?.Contains(line) == true)
+                    hits.Add(line);
+                if (!qualified && qualifierWord!.IsMatch(text))
+                    qualified = true;
+            }
+
+            if (hits.Count > 0)
+                uses.Add(new TestUse { Test = test, How = qualified ? "calls" : "mentions", Lines = hits });
+            else if (IsNamedAfter(test, name, qualifier))
+                uses.Add(new TestUse { Test = test, How = "named" });
+        }
+        return uses;
+    }
+
+    /// <summary>
+    /// The command that runs <paramref name="tests"/> from one file. <paramref name="project"/> is the .csproj,
+    /// pom.xml or build.gradle nearest the file and <paramref name="runner"/> the JavaScript runner (jest, vitest
+    /// or mocha), when the caller knows them.
+    /// </summary>
+    public static string RunCommand(string filePath, IReadOnlyList<TestCase> tests, string? project = null, string? runner = null)
+    {
+        var path = filePath.Replace('\\', '/');
+        var framework = tests[0].Framework;
+        string Alternation(IEnumerable<string> names) => string.Join("|", names.Distinct(StringComparer.Ordinal));
+        switch (framework)
+        {
+            case "go":
+            {
+                var directory = Path.GetDirectoryName(path)?.Replace('\\', '/');
+                var package = string.IsNullOrEmpty(directory) ? "." : "./" + directory;
+                var run = tests.Where(t => t.Kind != "benchmark").Select(t => t.Name).ToList();
+                var bench = tests.Where(t => t.Kind == "benchmark").Select(t => t.Name).ToList();
+                if (bench.Count == 0)
+                    return $"go test {package} -run '^({Alternation(run)})$'";
+                return run.Count == 0
+                    ? $"go test {package} -run '^$' -bench '^({Alternation(bench)})$'"
+                    : $"go test {package} -run '^({Alternation(run)})$' -bench '^({Alternation(bench)})$'";
+            }
+            case "xunit" or "nunit" or "mstest":
+                var filter = string.Join("|", tests.Select(t => $"FullyQualifiedName~{(t.Container != null ? t.Container + "." : string.Empty)}{t.Name}").Distinct());
+                return project != null ? $"dotnet test {project} --filter \"{filter}\"" : $"dotnet test --filter \"{filter}\"";
+            case "jest":
+                var pattern = Alternation(tests.Select(t => Regex.Replace(t.Name, @"[.*+?^${}()|\[\]\\]", @"\$0")));
+                return runner switch
+                {
+                    "vitest" => $"npx vitest run {path} -t \"{pattern}\"",
+                    "mocha" => $"npx mocha {path} --grep \"{pattern}\"",
+                    _ => $"npx jest {path} -t \"{pattern}\""
+                };
+            case "pytest":
+                return "pytest " + string.Join(" ", tests.Select(t => $"{path}::{(t.Container != null ? t.Container.Replace(".", "::") + "::" : string.Empty)}{t.Name}").Distinct());
+            case "junit":
+                var classes = tests.Select(t => $"{t.Container ?? Path.GetFileNameWithoutExtension(path)}#{t.Name.Trim('`')}").Distinct().ToList();
+                return project != null && Path.GetFileName(project).StartsWith("build.gradle", StringComparison.Ordinal)
+                    ? "gradle test " + string.Join(" ", classes.Select(c => $"--tests '{c.Replace('#', '.')}'"))
+                    : $"mvn test -Dtest='{string.Join("+", classes)}'";
+            case "rust":
+                return tests.Count == 1 ? $"cargo test {tests[0].Name}" : $"cargo test -- {string.Join(" ", tests.Select(t => t.Name).Distinct())}";
+            default:
+                return string.Empty;
+        }
+    }
+
+    private static bool IsNamedAfter(TestCase test, string name, string? qualifier)
+    {
+        var testName = Regex.Replace(test.Name, @"^(?:Test|Benchmark|Fuzz|Example|test_?)", string.Empty);
+        var compact = testName.Replace("_", string.Empty);
+        if (qualifier != null && compact.StartsWith(qualifier + name, StringComparison.OrdinalIgnoreCase))
+            return true;
+        return testName.Split('_', '.', ' ').Any(part => part.Equals(name, StringComparison.OrdinalIgnoreCase))
+               || testName.StartsWith(name, StringComparison.Ordinal) && (testName.Length == name.Length || char.IsUpper(testName[name.Length]) || testName[name.Length] == '_')
+               || test.Container != null && test.Container.Split('.').Any(c => c.Equals(name, StringComparison.OrdinalIgnoreCase)
+                                                                                  || c.Equals(qualifier + "." + name, StringComparison.OrdinalIgnoreCase));
+    }
+
+    private static List<TestCase> FindGoTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var match = GoTest.Match(lines[i]);
+            if (!match.Success || match.Groups["name"].Value == "TestMain")
+                continue;
+            var kind = match.Groups["kind"].Value.ToLowerInvariant();
+            if (kind != "example" && !match.Groups["type"].Success)
+                continue;
+
+            var test = new TestCase { Name = match.Groups["name"].Value, Kind = kind, Framework = "go", Line = i + 1, EndLine = BraceEnd(lines, i) };
+            for (var j = i + 1; j < test.EndLine && j < lines.Length; j++)
+            {
+                foreach (Match subtest in GoSubtest.Matches(lines[j]))
+                    AddCase(test, subtest.Groups["name"].Value);
+                if (GoTableCase.Match(lines[j]) is { Success: true } tableCase)
+                    AddCase(test, tableCase.Groups["name"].Value);
+            }
+            tests.Add(test);
+        }
+        return tests;
+    }
+
+    private static List<TestCase> FindDotNetTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        var classes = new List<(string Name, int Line, int End)>();
+        string? framework = null;
+        var cases = new List<string>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (ClassDeclaration.Match(lines[i]) is { Success: true } declaration)
+            {
+                classes.Add((declaration.Groups["name"].Value, i + 1, BraceEnd(lines, i)));
+                continue;
+            }
+            if (DotNetAttribute.Match(lines[i]) is { Success: true } attribute)
+            {
+                var name = attribute.Groups["attribute"].Value;
+                framework ??= name switch
+                {
+                    "Fact" or "Theory" or "InlineData" => "xunit",
+                    "TestMethod" or "DataTestMethod" or "DataRow" => "mstest",
+                    _ => "nunit"
+                };
+                if (name is "InlineData" or "TestCase" or "DataRow" && attribute.Groups["args"].Success)
+                    cases.Add(attribute.Groups["args"].Value.Trim('(', ')'));
+                continue;
+            }
+            if (framework != null && DotNetMethod.Match(lines[i]) is { Success: true } method)
+            {
+                var test = new TestCase
+                {
+                    Name = method.Groups["name"].Value,
+                    Framework = framework,
+                    Container = Container(classes, i + 1),
+                    Line = i + 1,
+                    EndLine = BraceEnd(lines, i),
+                    Cases = cases.ToList()
+                };
+                tests.Add(test);
+            }
+            // Other attributes ([Category], [Trait]) and comments may sit between [Test] and the method
+            var trimmed = lines[i].TrimStart();
+            if (trimmed.Length > 0 && !trimmed.StartsWith('[') && !trimmed.StartsWith("//", StringComparison.Ordinal))
+            {
+                framework = null;
+                cases.Clear();
+            }
+        }
+        return tests;
+    }
+
+    private static List<TestCase> FindJsTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        var describes = new List<(string Name, int Line, int End)>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (JsDescribe.Match(lines[i]) is { Success: true } describe)
+            {
+                describes.Add((describe.Groups["name"].Value, i + 1, BraceEnd(lines, i)));
+                continue;
+            }
+            if (JsTest.Match(lines[i]) is not { Success: true } match)
+                continue;
+            var test = new TestCase
+            {
+                Name = match.Groups["name"].Value,
+                Kind = match.Groups["fn"].Value == "bench" ? "benchmark" : "test",
+                Framework = "jest",
+                Container = Container(describes, i + 1),
+                Line = i + 1,
+                EndLine = BraceEnd(lines, i)
+            };
+            if (match.Groups["each"].Success)
+                test.Cases.Add(match.Groups["each"].Value.TrimStart('.'));
+            tests.Add(test);
+        }
+        return tests;
+    }
+
+    private static List<TestCase> FindPythonTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        var classes = new List<(string Name, int Indent, int Line, int End)>();
+        var parametrized = 0;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (PythonClass.Match(lines[i]) is { Success: true } declaration)
+            {
+                var indent = declaration.Groups["indent"].Value.Length;
+                classes.Add((declaration.Groups["name"].Value, indent, i + 1, IndentEnd(lines, i, indent)));
+                continue;
+            }
+            if (PythonParametrize.IsMatch(lines[i]))
+            {
+                parametrized++;
+                continue;
+            }
+            if (PythonTest.Match(lines[i]) is { Success: true } match)
+            {
+                var indent = match.Groups["indent"].Value.Length;
+                var container = classes.Where(c => c.Indent < indent && c.Line < i + 1 && c.End >= i + 1).Select(c => c.Name).ToList();
+                var test = new TestCase
+                {
+                    Name = match.Groups["name"].Value,
+                    Framework = "pytest",
+                    Container = container.Count > 0 ? string.Join(".", container) : null,
+                    Line = i + 1,
+                    EndLine = IndentEnd(lines, i, indent)
+                };
+                if (parametrized > 0)
+                    test.Cases.Add($"parametrize x{parametrized}");
+                tests.Add(test);
+            }
+            if (!lines[i].TrimStart().StartsWith('@') && lines[i].Trim().Length > 0)
+                parametrized = 0;
+        }
+        return tests;
+    }
+
+    private static List<TestCase> FindJvmTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        var classes = new List<(string Name, int Line, int End)>();
+        var annotated = false;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (ClassDeclaration.Match(lines[i]) is { Success: true } declaration)
+            {
+                classes.Add((declaration.Groups["name"].Value, i + 1, BraceEnd(lines, i)));
+                continue;
+            }
+            if (JvmAnnotation.IsMatch(lines[i]))
+            {
+                annotated = true;
+                continue;
+            }
+            if (annotated && JvmMethod.Match(lines[i]) is { Success: true } method)
+            {
+                tests.Add(new TestCase
+                {
+                    Name = method.Groups["name"].Value,
+                    Framework = "junit",
+                    Container = Container(classes, i + 1),
+                    Line = i + 1,
+                    EndLine = BraceEnd(lines, i)
+                });
+                annotated = false;
+            }
+            else if (!lines[i].TrimStart().StartsWith('@') && lines[i].Trim().Length > 0)
+            {
+                annotated = false;
+            }
+        }
+        return tests;
+    }
+
+    private static List<TestCase> FindRustTests(string[] lines)
+    {
+        var tests = new List<TestCase>();
+        string? pending = null;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (RustAttribute.Match(lines[i]) is { Success: true } attribute)
+            {
+                pending = attribute.Groups["bench"].Success ? "benchmark" : "test";
+                continue;
+            }
+            if (pending != null && RustFunction.Match(lines[i]) is { Success: true } function)
+            {
+                tests.Add(new TestCase { Name = function.Groups["name"].Value, Kind = pending, Framework = "rust", Line = i + 1, EndLine = BraceEnd(lines, i) });
+                pending = null;
+            }
+            else if (!lines[i].TrimStart().StartsWith("#[", StringComparison.Ordinal) && lines[i].Trim().Length > 0)
+            {
+                pending = null;
+            }
+        }
+        return tests;
+    }
+
+    private static void AddCase(TestCase test, string name)
+    {
+        if (!test.Cases.Contains(name))
+            test.Cases.Add(name);
+    }
+
+    private static string? Container(List<(string Name, int Line, int End)> blocks, int line)
+    {
+        var names = blocks.Where(b => b.Line < line && b.End >= line).Select(b => b.Name).ToList();
+        return names.Count > 0 ? string.Join(".", names) : null;
+    }
+
+    /// <summary>
+    /// The line holding the brace that closes the first block opened on or after <paramref name="start"/>;
+    /// string and comment contents are skipped so braces inside them do not count
+    /// </summary>
+    private static int BraceEnd(string[] lines, int start)
+    {
+        var depth = 0;
+        var opened = false;
+        for (var i = start; i < lines.Length; i++)
+        {
+            var text = Regex.Replace(lines[i], @"""(?:[^""\\]|\\.)*""|'(?:[^'\\]|\\.)*'|`[^`]*`|//.*$", string.Empty);
+            foreach (var c in text)
+            {
+                if (c == '{')
+                {
+                    depth++;
+                    opened = true;
+                }
+                else if (c == '}' && opened && --depth == 0)
+                {
+                    return i + 1;
+                }
+            }
+            // A block that never opens (an abstract declaration, a one-line arrow) ends where it started
+            if (!opened && i > start + 2)
+                return start + 1;
+        }
+        return lines.Length;
+    }
+
+    /// <summary>
+    /// The last line indented deeper than <paramref name="indent"/> after <paramref name="start"/>
+    /// </summary>
+    private static int IndentEnd(string[] lines, int start, int indent)
+    {
+        var end = start + 1;
+        for (var i = start + 1; i < lines.Length; i++)
+        {
+            if (lines[i].Trim().Length == 0)
+                continue;
+            if (lines[i].Length - lines[i].TrimStart().Length <= indent)
+                break;
+            end = i + 1;
+        }
+        return end;
+    }
+}
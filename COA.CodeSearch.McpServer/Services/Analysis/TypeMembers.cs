@@ -0,0 +1,98 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// The data members a class, struct, record or interface declares, read from source between the type's lines,
+/// for comparing DTOs and models with the schemas they serialize to
+/// </summary>
+public static class TypeMembers
+{
+    // Serialized names spelled out next to a member: json tags, [JsonPropertyName], @JsonProperty, Field(alias=)
+    private static readonly Regex NameAttribute = new(
+        @"(?:JsonPropertyName|JsonProperty|SerializedName)\s*\(\s*(?:value\s*=\s*)?""(?<name>[^""]+)""|\balias\s*=\s*['""](?<name>[^'""]+)['""]", RegexOptions.Compiled);
+    private static readonly Regex GoMember = new(@"^\s*(?<name>[A-Z]\w*)\s+[^\s`/][^`]*?(?:`(?<tag>[^`]*)`)?\s*(?://.*)?$", RegexOptions.Compiled);
+    private static readonly Regex CSharpProperty = new(@"^\s*(?:\[[^\]]*\]\s*)*public\s+(?:required\s+|virtual\s+|override\s+)*[\w<>\[\]?,. ]+?\s+(?<name>\w+)\s*\{\s*(?:get|init)", RegexOptions.Compiled);
+    private static readonly Regex CSharpPositional = new(@"\brecord\s+(?:class\s+|struct\s+)?\w+\s*\((?<parameters>[^)]*)\)", RegexOptions.Compiled);
+    private static readonly Regex JavaField = new(@"^\s*(?:@\w+(?:\([^)]*\))?\s*)*(?:private|public|protected)\s+(?:final\s+)?[\w<>\[\], ?]+\s+(?<name>\w+)\s*[;=]", RegexOptions.Compiled);
+    private static readonly Regex TsMember = new(@"^\s*(?:readonly\s+)?(?<name>[\w$]+|""[^""]+""|'[^']+')\??\s*:", RegexOptions.Compiled);
+    private static readonly Regex PythonMember = new(@"^(?<indent>\s+)(?<name>[A-Za-z_]\w*)\s*:\s*[^=]", RegexOptions.Compiled);
+
+    /// <summary>
+    /// The serialized members a type declares, by the name they serialize under where the source spells it out
+    /// </summary>
+    public static List<(string Name, int Line)> Of(string[] lines, JulieSymbol type)
+    {
+        var members = new List<(string Name, int Line)>();
+        var start = Math.Max(type.StartLine - 1, 0);
+        var end = Math.Min(type.EndLine > 0 ? type.EndLine : lines.Length, lines.Length);
+        var extension = Path.GetExtension(type.FilePath).ToLowerInvariant();
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+
+        string? Renamed(int i)
+        {
+            // An attribute line of its own names the member below it
+            var attribute = NameAttribute.Match(lines[i]);
+            if (!attribute.Success && i > start && lines[i - 1].TrimStart() is ['[' or '@', ..] previous && !previous.Contains(';'))
+                attribute = NameAttribute.Match(previous);
+            return attribute.Success ? attribute.Groups["name"].Value : null;
+        }
+
+        if (extension is ".cs" && CSharpPositional.Match(masked[start]) is { Success: true } record)
+        {
+            var parameters = lines[start].Substring(record.Groups["parameters"].Index, record.Groups["parameters"].Length);
+            foreach (var parameter in parameters.Split(',').Select(p => p.Trim()).Where(p => p.Length > 0))
+            {
+                var name = NameAttribute.Match(parameter) is { Success: true } attribute
+                    ? attribute.Groups["name"].Value
+                    : parameter.Split(' ', StringSplitOptions.RemoveEmptyEntries).Last();
+                members.Add((name, start + 1));
+            }
+        }
+
+        var depth = 0;
+        var bodyIndent = -1;
+        for (var i = start; i < end; i++)
+        {
+            var before = depth;
+            depth += masked[i].Count(c => c == '{') - masked[i].Count(c => c == '}');
+            switch (extension)
+            {
+                case ".go" when before == 1 && GoMember.Match(lines[i]) is { Success: true } field:
+                    var json = GoStructTags.Parse(field.Groups["tag"].Value).GetValueOrDefault("json");
+                    if (json?.Split(',')[0] == "-")
+                        break;
+                    members.Add((json != null ? GoStructTags.NameOf("json", json) ?? field.Groups["name"].Value : field.Groups["name"].Value, i + 1));
+                    break;
+                case ".cs" when before == 1 && CSharpProperty.Match(masked[i]) is { Success: true } property:
+                    if (lines[i].Contains("JsonIgnore") || i > start && lines[i - 1].Contains("JsonIgnore"))
+                        break;
+                    members.Add((Renamed(i) ?? property.Groups["name"].Value, i + 1));
+                    break;
+                case ".java" or ".kt" when before == 1 && JavaField.Match(masked[i]) is { Success: true } javaField:
+                    if (masked[i].Contains(" static "))
+                        break;
+                    members.Add((Renamed(i) ?? javaField.Groups["name"].Value, i + 1));
+                    break;
+                case ".ts" or ".tsx" when before == 1 && TsMember.Match(lines[i]) is { Success: true } tsMember && !masked[i].Contains('('):
+                    members.Add((tsMember.Groups["name"].Value.Trim('"', '\''), i + 1));
+                    break;
+                case ".py" when i > start && PythonMember.Match(masked[i]) is { Success: true } pythonMember:
+                    var indent = pythonMember.Groups["indent"].Length;
+                    if (bodyIndent < 0)
+                        bodyIndent = indent;
+                    if (indent == bodyIndent)
+                        members.Add((Renamed(i) ?? pythonMember.Groups["name"].Value, i + 1));
+                    break;
+            }
+        }
+        return members;
+    }
+
+    /// <summary>
+    /// userName, user_name and UserName are the same member under different naming policies
+    /// </summary>
+    public static string Comparable(string name) => name.Replace("_", string.Empty).Replace("-", string.Empty).ToLowerInvariant();
+}
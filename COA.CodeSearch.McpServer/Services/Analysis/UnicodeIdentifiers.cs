@@ -0,0 +1,166 @@
+using System.Globalization;
+using System.Text;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Culture-independent identifier handling shared by indexing, matching and rename. Identifiers may contain
+/// any letter, combining marks (é written as e + U+0301), connector punctuation and the zero-width joiners some
+/// scripts need; case folding never depends on the machine's culture and treats the Turkish dotted and dotless
+/// I as plain i, so <c>ID</c>, <c>id</c>, <c>İD</c> and <c>ıd</c> all match.
+/// </summary>
+public static class UnicodeIdentifiers
+{
+    /// <summary>
+    /// Regex character class for one identifier character, used as word boundary in whole-identifier patterns
+    /// </summary>
+    public const string IdentifierCharClass = @"[\p{L}\p{Mn}\p{Mc}\p{Nd}\p{Nl}\p{Pc}\p{Cf}]";
+
+    /// <summary>
+    /// Regex for a whole identifier: a letter or underscore followed by identifier characters
+    /// </summary>
+    public const string IdentifierPattern = @"[\p{L}\p{Nl}_][\p{L}\p{Mn}\p{Mc}\p{Nd}\p{Nl}\p{Pc}\p{Cf}]*";
+
+    private const char DotlessI = '\u0131';
+    private const char DottedCapitalI = '\u0130';
+    private const char CombiningDotAbove = '\u0307';
+
+    /// <summary>
+    /// Parses the CodeSearch:Unicode:Normalization setting: NFC (default), NFD or None
+    /// </summary>
+    public static NormalizationForm? ParseNormalization(string? value)
+    {
+        return value?.Trim().ToUpperInvariant() switch
+        {
+            null or "" or "NFC" => NormalizationForm.FormC,
+            "NFD" => NormalizationForm.FormD,
+            "NONE" => null,
+            _ => throw new ArgumentException($"Unknown Unicode normalization '{value}', expected NFC, NFD or None")
+        };
+    }
+
+    /// <summary>
+    /// True for characters that can continue an identifier. Surrogates are accepted so astral letters
+    /// (e.g. mathematical alphanumerics) are not split in half by char-at-a-time tokenizers.
+    /// </summary>
+    public static bool IsIdentifierPart(char c)
+    {
+        if (c < 128)
+            return char.IsAsciiLetterOrDigit(c) || c == '_';
+
+        return char.GetUnicodeCategory(c) switch
+        {
+            UnicodeCategory.UppercaseLetter or UnicodeCategory.LowercaseLetter or UnicodeCategory.TitlecaseLetter or
+            UnicodeCategory.ModifierLetter or UnicodeCategory.OtherLetter or UnicodeCategory.LetterNumber or
+            UnicodeCategory.DecimalDigitNumber or UnicodeCategory.NonSpacingMark or UnicodeCategory.SpacingCombiningMark or
+            UnicodeCategory.EnclosingMark or UnicodeCategory.ConnectorPunctuation or UnicodeCategory.Format or
+            UnicodeCategory.Surrogate => true,
+            _ => false
+        };
+    }
+
+    /// <summary>
+    /// Applies the normalization form; returns the input when it is already normalized or form is null
+    /// </summary>
+    public static string Normalize(string text, NormalizationForm? form)
+    {
+        if (form == null || string.IsNullOrEmpty(text) || IsAscii(text) || text.IsNormalized(form.Value))
+            return text;
+
+        return text.Normalize(form.Value);
+    }
+
+    /// <summary>
+    /// Case-folds an identifier for comparison: normalized to the given form, lowercased with the invariant
+    /// culture, with İ and ı folded to i.
+    /// </summary>
+    public static string Fold(string text, NormalizationForm? form = NormalizationForm.FormC)
+    {
+        if (string.IsNullOrEmpty(text))
+            return text;
+
+        if (IsAscii(text))
+            return text.ToLowerInvariant();
+
+        var normalized = Normalize(text, form);
+        var builder = new StringBuilder(normalized.Length);
+        for (var i = 0; i < normalized.Length; i++)
+        {
+            var c = normalized[i];
+            if (c == DottedCapitalI || c == DotlessI)
+            {
+                builder.Append('i');
+            }
+            else if (c == CombiningDotAbove && builder.Length > 0 && builder[^1] == 'i' &&
+                     i > 0 && normalized[i - 1] == 'I')
+            {
+                // İ in decomposed form is I + U+0307; drop the dot so it folds like the precomposed letter
+            }
+            else
+            {
+                builder.Append(char.ToLowerInvariant(c));
+            }
+        }
+
+        return builder.ToString();
+    }
+
+    /// <summary>
+    /// Case- and normalization-insensitive identifier equality
+    /// </summary>
+    public static bool EqualsIgnoreCase(string? a, string? b)
+    {
+        if (a == null || b == null)
+            return a == b;
+
+        return string.Equals(Fold(a), Fold(b), StringComparison.Ordinal);
+    }
+
+    /// <summary>
+    /// Case-sensitive identifier equality that still treats NFC and NFD spellings as the same name
+    /// </summary>
+    public static bool EqualsNormalized(string? a, string? b)
+    {
+        if (a == null || b == null)
+            return a == b;
+
+        return string.Equals(Normalize(a, NormalizationForm.FormC), Normalize(b, NormalizationForm.FormC), StringComparison.Ordinal);
+    }
+
+    /// <summary>
+    /// Ordinal comparison of folded identifiers, for the SQLite collation
+    /// </summary>
+    public static int CompareIgnoreCase(string? a, string? b)
+    {
+        return string.CompareOrdinal(a == null ? null : Fold(a), b == null ? null : Fold(b));
+    }
+
+    /// <summary>
+    /// Ordinal comparison of NFC-normalized identifiers, for the SQLite collation
+    /// </summary>
+    public static int CompareNormalized(string? a, string? b)
+    {
+        return string.CompareOrdinal(
+            a == null ? null : Normalize(a, NormalizationForm.FormC),
+            b == null ? null : Normalize(b, NormalizationForm.FormC));
+    }
+
+    /// <summary>
+    /// Converts a UTF-8 byte offset (what tree-sitter and the symbol database store) into a UTF-16 index into
+    /// the decoded string. Byte and char offsets only agree while the text before them is ASCII.
+    /// </summary>
+    public static int CharIndexOfUtf8Offset(byte[] utf8, int byteOffset)
+    {
+        return Encoding.UTF8.GetCharCount(utf8, 0, Math.Clamp(byteOffset, 0, utf8.Length));
+    }
+
+    private static bool IsAscii(string text)
+    {
+        foreach (var c in text)
+        {
+            if (c >= 128)
+                return false;
+        }
+        return true;
+    }
+}
@@ -0,0 +1,313 @@
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Scratch;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// A module listed in a Go vendor/modules.txt: the version go mod vendor copied, or the module or directory
+/// a replace directive substituted
+/// </summary>
+public class VendoredModule
+{
+    public string Path { get; set; } = string.Empty;
+    public string Version { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Module path or local directory of a replace directive; null when the module is not replaced
+    /// </summary>
+    public string? Replacement { get; set; }
+    public string? ReplacementVersion { get; set; }
+
+    /// <summary>
+    /// Packages copied into vendor/ from this module
+    /// </summary>
+    public List<string> Packages { get; set; } = new();
+
+    /// <summary>
+    /// True when the replacement is a directory (./fork, ../sys, /src/lib) rather than a module
+    /// </summary>
+    public bool IsLocalReplacement => Replacement != null && ReplacementVersion == null;
+}
+
+/// <summary>
+/// Where a copied file says it came from: a header comment such as "Copied from https://github.com/o/r/blob/v1.2/x.go"
+/// </summary>
+public class UpstreamOrigin
+{
+    public string Url { get; set; } = string.Empty;
+
+    /// <summary>
+    /// host/owner/repository, e.g. github.com/golang/go; null for URLs of unknown hosts
+    /// </summary>
+    public string? Repository { get; set; }
+
+    /// <summary>
+    /// Commit, tag or version the copy was taken at, from the URL or a Version/Commit line
+    /// </summary>
+    public string? Revision { get; set; }
+
+    /// <summary>
+    /// Path of the file in the upstream repository
+    /// </summary>
+    public string? UpstreamPath { get; set; }
+
+    /// <summary>
+    /// 1-based line of the origin comment
+    /// </summary>
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// Upstream provenance of vendored and copied third-party code: Go vendor/modules.txt, whose files go mod
+/// vendor copies unchanged from the module cache, and header comments naming the URL a file was copied,
+/// forked or adapted from. <see cref="Compare"/> tells a pristine copy from a locally modified one.
+/// </summary>
+public static class VendoredProvenance
+{
+    private static readonly Regex ModuleLine = new(@"^#\s+(?<path>\S+)(?:\s+(?<version>v\S+))?(?:\s+=>\s+(?<replacement>\S+)(?:\s+(?<replacementVersion>v\S+))?)?\s*$", RegexOptions.Compiled);
+    private static readonly Regex CommentLine = new(@"^\s*(?://+|#+|--|;+|/\*+|\*+|<!--|\{-|'|REM\b)?\s*(?<text>.*?)\s*(?:\*/|-->)?\s*$", RegexOptions.Compiled);
+    private static readonly Regex OriginText = new(
+        @"(?:\b(?:copied|vendored|forked|adapted|taken|imported|ported|derived|extracted|borrowed)\s+(?:\w+\s+){0,3}?from|^(?:source|origin|upstream|original|from|url)\s*:)\s*:?\s*<?(?<url>https?://[^\s>)""']+)",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex RevisionText = new(
+        @"^(?:version|revision|commit|tag|ref|upstream\s+(?:version|commit|revision))\s*[:=]\s*(?<revision>v?\d+(?:\.\d+)+[\w.+-]*|[0-9a-f]{7,40})\b|\b(?:at|@)\s+(?:commit\s+)?(?<revision>[0-9a-f]{12,40}|v\d+(?:\.\d+)+[\w.+-]*)\b",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex CommentStart = new(@"^\s*(?://|#|--|;|/\*|\*|<!--|-->)", RegexOptions.Compiled);
+    private static readonly Regex HunkHeader = new(@"^@@ -\d+(?:,\d+)? \+(?<start>\d+)(?:,\d+)? @@", RegexOptions.Compiled);
+    private static readonly Regex GitHubUrl = new(
+        @"^https?://(?:www\.)?github\.com/(?<owner>[^/]+)/(?<repo>[^/#?]+?)(?:\.git)?(?:/(?:blob|tree|raw)/(?<rev>[^/]+)(?:/(?<path>[^#?]+))?)?(?:[#?].*)?$",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex GitHubRawUrl = new(
+        @"^https?://raw\.githubusercontent\.com/(?<owner>[^/]+)/(?<repo>[^/]+)/(?<rev>[^/]+)/(?<path>[^#?]+)",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex GitLabUrl = new(
+        @"^https?://(?<host>gitlab\.[^/]+)/(?<repo>[^#?]+?)/-/(?:blob|tree|raw)/(?<rev>[^/]+)(?:/(?<path>[^#?]+))?",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    /// <summary>
+    /// How many leading lines are searched for an origin comment
+    /// </summary>
+    public const int HeaderLines = 40;
+
+    /// <summary>
+    /// The modules of a vendor/modules.txt with the packages vendored from each, in file order
+    /// </summary>
+    public static List<VendoredModule> ParseModulesTxt(string content)
+    {
+        var modules = new List<VendoredModule>();
+        VendoredModule? current = null;
+
+        foreach (var raw in content.Replace("\r\n", "\n").Split('\n'))
+        {
+            var line = raw.Trim();
+            if (line.Length == 0 || line.StartsWith("##", StringComparison.Ordinal))
+                continue;
+
+            if (line.StartsWith('#'))
+            {
+                current = null;
+                var match = ModuleLine.Match(line);
+                if (!match.Success)
+                    continue;
+
+                // "# example.com/a => ./a" with no version only records a replacement of a module not vendored
+                current = new VendoredModule
+                {
+                    Path = match.Groups["path"].Value,
+                    Version = match.Groups["version"].Value,
+                    Replacement = match.Groups["replacement"].Success ? match.Groups["replacement"].Value : null,
+                    ReplacementVersion = match.Groups["replacementVersion"].Success ? match.Groups["replacementVersion"].Value : null
+                };
+                modules.Add(current);
+            }
+            else
+            {
+                current?.Packages.Add(line);
+            }
+        }
+
+        return modules;
+    }
+
+    /// <summary>
+    /// The module a path below vendor/ belongs to, by longest package match, with the path inside the module
+    /// </summary>
+    public static (VendoredModule Module, string PathInModule)? FindModule(IEnumerable<VendoredModule> modules, string pathInVendor)
+    {
+        var normalized = pathInVendor.Replace('\\', '/');
+        var directory = normalized.Contains('/') ? normalized[..normalized.LastIndexOf('/')] : string.Empty;
+
+        // Packages decide first: a nested module's directory lies inside its parent module's
+        var owner = modules
+            .Where(m => m.Packages.Contains(directory, StringComparer.Ordinal))
+            .Concat(modules.Where(m => normalized.StartsWith(m.Path + "/", StringComparison.Ordinal)).OrderByDescending(m => m.Path.Length))
+            .FirstOrDefault();
+        return owner == null ? null : (owner, normalized[(owner.Path.Length + 1)..]);
+    }
+
+    /// <summary>
+    /// Where a module version is unpacked in the Go module cache: uppercase letters are escaped as !lower
+    /// (github.com/Azure/go-autorest@v14.2.0+incompatible → github.com/!azure/go-autorest@v14.2.0+incompatible)
+    /// </summary>
+    public static string ModuleCacheDirectory(string moduleCache, string modulePath, string version)
+    {
+        var escaped = new StringBuilder();
+        foreach (var c in $"{modulePath}@{version}")
+        {
+            if (char.IsAsciiLetterUpper(c))
+                escaped.Append('!').Append(char.ToLowerInvariant(c));
+            else
+                escaped.Append(c);
+        }
+        return Path.Combine(moduleCache, escaped.ToString().Replace('/', Path.DirectorySeparatorChar));
+    }
+
+    /// <summary>
+    /// The origin a file's leading comments name, or null when they name none
+    /// </summary>
+    public static UpstreamOrigin? DetectOrigin(string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        UpstreamOrigin? origin = null;
+        string? revision = null;
+
+        for (var i = 0; i < Math.Min(lines.Length, HeaderLines); i++)
+        {
+            var text = CommentLine.Match(lines[i]).Groups["text"].Value;
+            if (text.Length == 0)
+                continue;
+
+            if (origin == null && OriginText.Match(text) is { Success: true } match)
+            {
+                origin = ParseUrl(match.Groups["url"].Value.TrimEnd('.', ',', ';'));
+                origin.Line = i + 1;
+            }
+            if (revision == null && RevisionText.Match(text) is { Success: true } version)
+            {
+                revision = version.Groups["revision"].Value;
+            }
+        }
+
+        if (origin != null && origin.Revision == null)
+            origin.Revision = revision;
+        return origin;
+    }
+
+    /// <summary>
+    /// The repository, revision and file path a GitHub or GitLab URL names; other URLs are kept as they are
+    /// </summary>
+    public static UpstreamOrigin ParseUrl(string url)
+    {
+        var origin = new UpstreamOrigin { Url = url };
+        if (GitHubRawUrl.Match(url) is { Success: true } raw)
+        {
+            origin.Repository = $"github.com/{raw.Groups["owner"].Value}/{raw.Groups["repo"].Value}";
+            origin.Revision = raw.Groups["rev"].Value;
+            origin.UpstreamPath = raw.Groups["path"].Value;
+        }
+        else if (GitHubUrl.Match(url) is { Success: true } github)
+        {
+            origin.Repository = $"github.com/{github.Groups["owner"].Value}/{github.Groups["repo"].Value}";
+            origin.Revision = github.Groups["rev"].Success ? github.Groups["rev"].Value : null;
+            origin.UpstreamPath = github.Groups["path"].Success ? github.Groups["path"].Value : null;
+        }
+        else if (GitLabUrl.Match(url) is { Success: true } gitlab)
+        {
+            origin.Repository = $"{gitlab.Groups["host"].Value}/{gitlab.Groups["repo"].Value}";
+            origin.Revision = gitlab.Groups["rev"].Value;
+            origin.UpstreamPath = gitlab.Groups["path"].Success ? gitlab.Groups["path"].Value : null;
+        }
+        return origin;
+    }
+
+    /// <summary>
+    /// Where the upstream file can be downloaded at the recorded revision; null unless the origin names a
+    /// GitHub or GitLab file and a revision
+    /// </summary>
+    public static string? RawUrl(UpstreamOrigin origin)
+    {
+        if (origin.Repository == null || origin.Revision == null || origin.UpstreamPath == null)
+            return null;
+
+        var path = origin.UpstreamPath.TrimStart('/');
+        if (origin.Repository.StartsWith("github.com/", StringComparison.OrdinalIgnoreCase))
+            return $"https://raw.githubusercontent.com/{origin.Repository["github.com/".Length..]}/{origin.Revision}/{path}";
+
+        var slash = origin.Repository.IndexOf('/');
+        return $"https://{origin.Repository[..slash]}/{origin.Repository[(slash + 1)..]}/-/raw/{origin.Revision}/{path}";
+    }
+
+    /// <summary>
+    /// Compares a local copy with its upstream file, ignoring line endings, a byte order mark and trailing
+    /// newlines. Comment and blank lines the copy only added among its first <see cref="HeaderLines"/> lines -
+    /// the note recording where it came from - do not count as modifications.
+    /// </summary>
+    public static VendoredComparison Compare(string upstream, string local, string path, int contextLines = 2)
+    {
+        var upstreamText = Normalize(upstream);
+        var localText = Normalize(local);
+        if (upstreamText == localText)
+            return new VendoredComparison { Identical = true };
+
+        var diff = LineDiff.Unified(upstreamText, localText, path, contextLines);
+        var headerOnly = diff.Removed == 0 && AddedLines(LineDiff.Unified(upstreamText, localText, path, 0).Diff)
+            .All(added => added.Line <= HeaderLines && (added.Text.Trim().Length == 0 || CommentStart.IsMatch(added.Text)));
+
+        return new VendoredComparison
+        {
+            Identical = headerOnly,
+            HeaderOnly = headerOnly,
+            Diff = headerOnly ? null : diff.Diff,
+            Added = diff.Added,
+            Removed = diff.Removed
+        };
+    }
+
+    private static string Normalize(string text) => text.TrimStart('\uFEFF').Replace("\r\n", "\n").TrimEnd('\n');
+
+    /// <summary>
+    /// The added lines of a unified diff with their line numbers in the new text
+    /// </summary>
+    private static IEnumerable<(int Line, string Text)> AddedLines(string diff)
+    {
+        var line = 0;
+        foreach (var row in diff.Split('\n'))
+        {
+            if (row.StartsWith("+++", StringComparison.Ordinal) || row.StartsWith("---", StringComparison.Ordinal))
+                continue;
+            if (HunkHeader.Match(row) is { Success: true } hunk)
+            {
+                line = int.Parse(hunk.Groups["start"].Value);
+                continue;
+            }
+            if (row.StartsWith('+'))
+                yield return (line++, row[1..]);
+            else if (row.StartsWith(' '))
+                line++;
+        }
+    }
+}
+
+/// <summary>
+/// How a local copy differs from its upstream file
+/// </summary>
+public class VendoredComparison
+{
+    /// <summary>
+    /// True when the copy matches upstream, perhaps with a header note added
+    /// </summary>
+    public bool Identical { get; set; }
+
+    /// <summary>
+    /// True when the copy only added comment lines at the top
+    /// </summary>
+    public bool HeaderOnly { get; set; }
+
+    /// <summary>
+    /// Unified diff from upstream to the local copy; null when identical
+    /// </summary>
+    public string? Diff { get; set; }
+    public int Added { get; set; }
+    public int Removed { get; set; }
+}
@@ -0,0 +1,904 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Vue single-file components read as declarations: top-level declarations of the &lt;script&gt; and
+/// &lt;script setup&gt; blocks, props (defineProps, withDefaults, defineModel or the props option) as "property"
+/// symbols, emitted events (defineEmits or the emits option) as "event" symbols and template refs (ref="input")
+/// as "field" symbols, all at their line and column in the .vue file rather than in their block. The component,
+/// named after the file or its name option, is their parent and spans the file. <see cref="Repair"/> also moves
+/// the script symbols julie-codesearch numbered from the start of their block.
+/// </summary>
+public static class VueSfcSymbols
+{
+    private static readonly Regex BlockOpen = new(@"^<(?<tag>template|script|style)(?<attrs>(?:\s[^>]*)?)>", RegexOptions.Compiled | RegexOptions.Multiline);
+    private static readonly Regex Declaration = new(
+        @"\G[ \t]*(?:export[ \t]+(?:default[ \t]+)?)?(?:declare[ \t]+)?(?:(?<kind>async[ \t]+function|function|abstract[ \t]+class|class|interface|const[ \t]+enum|enum|type)(?:[ \t]*\*[ \t]*|[ \t]+)(?<name>[A-Za-z_$][\w$]*)|(?<kind>const|let|var)[ \t]+(?<name>[A-Za-z_$][\w$]*)(?:[ \t]*:[^=\n]+)?[ \t]*=[ \t]*(?<init>[^\n]*))",
+        RegexOptions.Compiled);
+    private static readonly Regex FunctionValue = new(@"^(?:async\b|function\b|\([^)]*\)\s*(?::[^=]+)?=>|[A-Za-z_$][\w$]*\s*=>)", RegexOptions.Compiled);
+    private static readonly Regex MemberHead = new(@"^(?:(?:async|get|set|readonly|static)\s+)*(?:(?<q>['""])(?<name>[^'""]+)\k<q>|(?<name>[A-Za-z_$][\w$]*))(?<optional>\?)?\s*", RegexOptions.Compiled);
+    private static readonly Regex StringLiteral = new(@"(?<q>['""])(?<value>[^'""\n]*)\k<q>", RegexOptions.Compiled);
+    private static readonly Regex DefineProps = new(@"\bdefineProps\s*(?=[<(])", RegexOptions.Compiled);
+    private static readonly Regex DefineEmits = new(@"\bdefineEmits\s*(?=[<(])", RegexOptions.Compiled);
+    private static readonly Regex DefineModel = new(@"\bdefineModel\s*(?=[<(])", RegexOptions.Compiled);
+    private static readonly Regex DefineOptions = new(@"\bdefineOptions\s*\(\s*(?=\{)", RegexOptions.Compiled);
+    private static readonly Regex WithDefaults = new(@"\bwithDefaults\s*\(\s*defineProps\b", RegexOptions.Compiled);
+    private static readonly Regex ExportDefault = new(@"\bexport\s+default\s+(?:defineComponent\s*\(\s*)?(?=\{)", RegexOptions.Compiled);
+    private static readonly Regex TemplateRef = new(@"(?<=\s)ref\s*=\s*(?<q>['""])(?<name>[^'""]+)\k<q>", RegexOptions.Compiled);
+
+    /// <summary>
+    /// True for Vue single-file components
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".vue", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// The component, the declarations of its script blocks, its props, events and template refs, in source order
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var text = content.Replace("\r\n", "\n");
+        var lines = new LineMap(text);
+        var blocks = Blocks(text);
+        var scripts = blocks.Where(b => b.Tag == "script").Select(b => new ScriptText(text, b)).ToList();
+
+        var component = new JulieSymbol
+        {
+            Id = StableId(filePath, "class", "component", 1),
+            Name = ComponentName(filePath, scripts),
+            Kind = "class",
+            Language = "vue",
+            FilePath = filePath,
+            StartLine = 1,
+            StartColumn = 0,
+            EndLine = lines.Count,
+            EndColumn = lines.LineLength(lines.Count),
+            Visibility = "public"
+        };
+        var symbols = new List<JulieSymbol> { component };
+        var props = new List<JulieSymbol>();
+        var events = new List<JulieSymbol>();
+
+        foreach (var script in scripts)
+        {
+            ScriptDeclarations(script, lines, filePath, symbols, component);
+            props.AddRange(Props(script, scripts, lines, filePath, component));
+            events.AddRange(Emits(script, lines, filePath, component));
+            foreach (var (prop, emit) in Models(script, lines, filePath, component))
+            {
+                props.Add(prop);
+                events.Add(emit);
+            }
+        }
+
+        symbols.AddRange(props);
+        symbols.AddRange(events);
+        foreach (var template in blocks.Where(b => b.Tag == "template"))
+            symbols.AddRange(TemplateRefs(text, template, lines, filePath, component));
+
+        component.Signature = $"<{component.Name}>"
+            + (props.Count > 0 ? $" props: {string.Join(", ", props.Select(p => p.Name).Distinct())}" : string.Empty)
+            + (events.Count > 0 ? $"; emits: {string.Join(", ", events.Select(e => e.Name).Distinct())}" : string.Empty);
+
+        return symbols.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a .vue file with script symbols numbered from the start of their block moved to their line
+    /// in the file, and the declarations julie-codesearch missed added. Extracted symbols keep their IDs. Returns
+    /// <paramref name="symbols"/> itself when nothing was wrong.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols)
+    {
+        var text = content.Replace("\r\n", "\n");
+        var lines = text.Split('\n');
+        var declarations = Extract(filePath, content);
+        var ownIds = declarations.Select(d => d.Id).ToHashSet(StringComparer.Ordinal);
+        var scriptStarts = Blocks(text).Where(b => b.Tag == "script").Select(b => b.TagLine).ToList();
+        var repaired = symbols.ToList();
+        var changed = false;
+
+        foreach (var symbol in repaired)
+        {
+            if (ownIds.Contains(symbol.Id) || NameOnLine(lines, symbol.StartLine, symbol.Name))
+                continue;
+
+            // Numbered from the first line of the block's content, or from the line of its <script> tag
+            foreach (var offset in scriptStarts.SelectMany(line => new[] { line - 1, line }))
+            {
+                if (offset > 0 && NameOnLine(lines, symbol.StartLine + offset, symbol.Name))
+                {
+                    symbol.StartLine += offset;
+                    symbol.EndLine += offset;
+                    changed = true;
+                    break;
+                }
+            }
+        }
+
+        foreach (var declaration in declarations)
+        {
+            if (!repaired.Any(s => s.Name == declaration.Name && s.StartLine == declaration.StartLine))
+            {
+                repaired.Add(declaration);
+                changed = true;
+            }
+        }
+
+        return changed ? repaired.OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn).ToList() : symbols;
+    }
+
+    /// <summary>
+    /// The top-level blocks of a component. A block's content runs from after its opening tag to its closing tag
+    /// at the start of a line, so nested &lt;template&gt; elements stay inside.
+    /// </summary>
+    private static List<SfcBlock> Blocks(string text)
+    {
+        var blocks = new List<SfcBlock>();
+        var position = 0;
+        while (BlockOpen.Match(text, position) is { Success: true } open)
+        {
+            var tag = open.Groups["tag"].Value;
+            var contentStart = open.Index + open.Length;
+            var close = Regex.Match(text[contentStart..], $@"^</{tag}\s*>", RegexOptions.Multiline);
+            if (!close.Success)
+            {
+                // <template><div /></template> on one line
+                close = Regex.Match(text[contentStart..], $@"</{tag}\s*>");
+            }
+            var contentEnd = close.Success ? contentStart + close.Index : text.Length;
+            blocks.Add(new SfcBlock(tag, contentStart, contentEnd,
+                text.Take(open.Index).Count(c => c == '\n') + 1));
+            position = close.Success ? contentEnd + close.Length : text.Length;
+        }
+        return blocks;
+    }
+
+    /// <summary>
+    /// The component's name: its name option or defineOptions name, otherwise the file name in PascalCase
+    /// (user-card.vue → UserCard)
+    /// </summary>
+    private static string ComponentName(string filePath, List<ScriptText> scripts)
+    {
+        foreach (var script in scripts)
+        {
+            foreach (var options in ComponentOptions(script))
+            {
+                if (options.FirstOrDefault(m => m.Name == "name") is { } name
+                    && StringLiteral.Match(name.Value) is { Success: true } literal && literal.Index == 0)
+                {
+                    return literal.Groups["value"].Value;
+                }
+            }
+        }
+
+        var fileName = Path.GetFileNameWithoutExtension(filePath);
+        return string.Concat(fileName.Split('-', '_', '.', ' ').Where(p => p.Length > 0).Select(p => char.ToUpperInvariant(p[0]) + p[1..]));
+    }
+
+    /// <summary>
+    /// Members of the options objects of a script: export default { ... }, defineComponent({ ... }) and defineOptions({ ... })
+    /// </summary>
+    private static IEnumerable<List<Member>> ComponentOptions(ScriptText script)
+    {
+        foreach (var regex in new[] { ExportDefault, DefineOptions })
+        {
+            foreach (var match in script.Matches(regex))
+            {
+                var open = match.Index + match.Length;
+                if (script.Close(open) is { } close)
+                    yield return script.Members(open, close, typeLiteral: false);
+            }
+        }
+    }
+
+    private static void ScriptDeclarations(ScriptText script, LineMap lines, string filePath, List<JulieSymbol> symbols, JulieSymbol component)
+    {
+        var text = script.Text;
+        var lineStart = script.Block.ContentStart;
+        while (lineStart < script.Block.ContentEnd)
+        {
+            var lineEnd = text.IndexOf('\n', lineStart);
+            if (lineEnd < 0 || lineEnd > script.Block.ContentEnd)
+                lineEnd = script.Block.ContentEnd;
+
+            if (script.IsCode(lineStart) && script.DepthAt(lineStart) == 0
+                && Declaration.Match(text, lineStart, lineEnd - lineStart) is { Success: true } declaration)
+            {
+                var keyword = Regex.Replace(declaration.Groups["kind"].Value, @"\s+", " ");
+                var kind = keyword switch
+                {
+                    "function" or "async function" => "function",
+                    "class" or "abstract class" => "class",
+                    "interface" => "interface",
+                    "enum" or "const enum" => "enum",
+                    "type" => "type",
+                    _ => FunctionValue.IsMatch(declaration.Groups["init"].Value) ? "function" : "variable"
+                };
+
+                var name = declaration.Groups["name"];
+                var (line, column) = lines.Position(name.Index);
+                var endLine = lines.Position(script.StatementEnd(lineStart, lineEnd)).Line;
+                var symbol = Declare(filePath, kind, name.Value, line, column, endLine,
+                    text[lineStart..lineEnd].Trim().TrimEnd('{').TrimEnd(), component);
+                symbol.EndColumn = lines.LineLength(endLine);
+                symbols.Add(symbol);
+            }
+
+            lineStart = lineEnd + 1;
+        }
+    }
+
+    /// <summary>
+    /// Props declared by defineProps (a type literal, an interface or type alias of the script, an object or an
+    /// array of names), with withDefaults defaults, or by the props option
+    /// </summary>
+    private static List<JulieSymbol> Props(ScriptText script, List<ScriptText> scripts, LineMap lines, string filePath, JulieSymbol component)
+    {
+        var props = new List<JulieSymbol>();
+        var defaults = new Dictionary<string, string>(StringComparer.Ordinal);
+
+        foreach (var call in script.Matches(WithDefaults))
+        {
+            var open = script.Text.IndexOf('(', call.Index);
+            if (script.Close(open) is not { } close)
+                continue;
+
+            var arguments = script.Arguments(open, close);
+            if (arguments.Count > 1 && arguments[1].Value.StartsWith('{') && script.Close(arguments[1].ValueIndex) is { } defaultsClose)
+            {
+                foreach (var member in script.Members(arguments[1].ValueIndex, defaultsClose, typeLiteral: false))
+                    defaults[member.Name] = member.Value;
+            }
+        }
+
+        foreach (var call in script.Matches(DefineProps))
+        {
+            var start = call.Index + call.Length;
+            if (script.Text[start] == '<')
+            {
+                if (script.AngleClose(start) is not { } angleClose)
+                    continue;
+
+                var typeArgument = script.Text[(start + 1)..angleClose];
+                var literal = start + 1 + (typeArgument.Length - typeArgument.TrimStart().Length);
+                if (script.Text[literal] != '{'
+                    && TypeDeclaration(scripts, typeArgument.Trim().Split('&', '|')[0].Trim()) is { } declared)
+                {
+                    foreach (var member in declared.Script.Members(declared.Open, declared.Close, typeLiteral: true))
+                        props.Add(Prop(lines, filePath, member.Name, member.NameIndex, member.Optional, member.Value, defaults, component));
+                    continue;
+                }
+
+                if (script.Close(literal) is { } literalClose)
+                {
+                    foreach (var member in script.Members(literal, literalClose, typeLiteral: true))
+                        props.Add(Prop(lines, filePath, member.Name, member.NameIndex, member.Optional, member.Value, defaults, component));
+                }
+            }
+            else
+            {
+                props.AddRange(RuntimeProps(script, start, lines, filePath, defaults, component));
+            }
+        }
+
+        foreach (var options in ComponentOptions(script))
+        {
+            if (options.FirstOrDefault(m => m.Name == "props") is { } option)
+                props.AddRange(RuntimeProps(script, option.ValueIndex - 1, lines, filePath, defaults, component, argumentOpen: false));
+        }
+
+        return props;
+    }
+
+    /// <summary>
+    /// Props of a runtime declaration: defineProps({ ... }) or defineProps([...]) when <paramref name="argumentOpen"/>,
+    /// otherwise the object or array starting after <paramref name="index"/>
+    /// </summary>
+    private static IEnumerable<JulieSymbol> RuntimeProps(ScriptText script, int index, LineMap lines, string filePath,
+        Dictionary<string, string> defaults, JulieSymbol component, bool argumentOpen = true)
+    {
+        var open = index + 1;
+        if (argumentOpen)
+        {
+            while (open < script.Block.ContentEnd && char.IsWhiteSpace(script.Text[open]))
+                open++;
+        }
+
+        if (open >= script.Block.ContentEnd || script.Close(open) is not { } close)
+            yield break;
+
+        if (script.Text[open] == '[')
+        {
+            foreach (var (name, nameIndex) in script.Strings(open, close))
+                yield return Prop(lines, filePath, name, nameIndex, true, null, defaults, component);
+            yield break;
+        }
+
+        if (script.Text[open] != '{')
+            yield break;
+
+        foreach (var member in script.Members(open, close, typeLiteral: false))
+        {
+            string? type = member.Value;
+            var optional = true;
+            var memberDefaults = defaults;
+            if (member.Value.StartsWith('{') && script.Close(member.ValueIndex) is { } optionsClose)
+            {
+                var options = script.Members(member.ValueIndex, optionsClose, typeLiteral: false);
+                type = options.FirstOrDefault(o => o.Name == "type")?.Value;
+                optional = options.FirstOrDefault(o => o.Name == "required")?.Value != "true";
+                if (options.FirstOrDefault(o => o.Name == "default") is { } option)
+                    memberDefaults = new Dictionary<string, string>(defaults) { [member.Name] = option.Value };
+            }
+
+            yield return Prop(lines, filePath, member.Name, member.NameIndex, optional, RuntimeType(type), memberDefaults, component);
+        }
+    }
+
+    /// <summary>
+    /// Events declared by defineEmits (call signatures, named tuples, an object or an array of names) or by the emits option
+    /// </summary>
+    private static List<JulieSymbol> Emits(ScriptText script, LineMap lines, string filePath, JulieSymbol component)
+    {
+        var events = new List<JulieSymbol>();
+
+        foreach (var call in script.Matches(DefineEmits))
+        {
+            var start = call.Index + call.Length;
+            if (script.Text[start] == '<')
+            {
+                if (script.AngleClose(start) is not { } angleClose)
+                    continue;
+
+                var literal = script.Text.IndexOf('{', start, angleClose - start);
+                if (literal < 0 || script.Close(literal) is not { } literalClose)
+                    continue;
+
+                foreach (var member in script.Members(literal, literalClose, typeLiteral: true))
+                {
+                    if (member.Name.Length == 0)
+                    {
+                        // (e: 'change' | 'input', id: number): void
+                        var parameters = SplitTopLevel(Parenthesized(member.Value) ?? string.Empty);
+                        if (parameters.Count == 0)
+                            continue;
+
+                        var rest = string.Join(", ", parameters.Skip(1));
+                        foreach (Match name in StringLiteral.Matches(parameters[0]))
+                        {
+                            var nameIndex = member.ValueIndex + member.Value.IndexOf(parameters[0], StringComparison.Ordinal) + name.Groups["value"].Index;
+                            events.Add(Event(lines, filePath, name.Groups["value"].Value, nameIndex, rest, component));
+                        }
+                    }
+                    else
+                    {
+                        // change: [id: number], or the older change: (id: number) => void
+                        var value = member.Value.Trim();
+                        var parameters = value.StartsWith('[') ? value.TrimStart('[').TrimEnd(']') : Parenthesized(value) ?? string.Empty;
+                        events.Add(Event(lines, filePath, member.Name, member.NameIndex, parameters.Trim(), component));
+                    }
+                }
+            }
+            else
+            {
+                events.AddRange(RuntimeEmits(script, start + 1, lines, filePath, component));
+            }
+        }
+
+        foreach (var options in ComponentOptions(script))
+        {
+            if (options.FirstOrDefault(m => m.Name == "emits") is { } option)
+                events.AddRange(RuntimeEmits(script, option.ValueIndex, lines, filePath, component));
+        }
+
+        return events;
+    }
+
+    private static IEnumerable<JulieSymbol> RuntimeEmits(ScriptText script, int open, LineMap lines, string filePath, JulieSymbol component)
+    {
+        while (open < script.Block.ContentEnd && char.IsWhiteSpace(script.Text[open]))
+            open++;
+        if (open >= script.Block.ContentEnd || script.Close(open) is not { } close)
+            yield break;
+
+        if (script.Text[open] == '[')
+        {
+            foreach (var (name, nameIndex) in script.Strings(open, close))
+                yield return Event(lines, filePath, name, nameIndex, string.Empty, component);
+        }
+        else if (script.Text[open] == '{')
+        {
+            // change: (id: number) => true validates the payload; null does not
+            foreach (var member in script.Members(open, close, typeLiteral: false))
+            {
+                var value = member.Value.StartsWith("function", StringComparison.Ordinal) ? member.Value[8..].TrimStart() : member.Value;
+                var parameters = value.StartsWith('(') ? Parenthesized(value) ?? string.Empty : string.Empty;
+                yield return Event(lines, filePath, member.Name, member.NameIndex, parameters.Trim(), component);
+            }
+        }
+    }
+
+    /// <summary>
+    /// defineModel: a prop (modelValue unless named) and its update:name event
+    /// </summary>
+    private static IEnumerable<(JulieSymbol Prop, JulieSymbol Event)> Models(ScriptText script, LineMap lines, string filePath, JulieSymbol component)
+    {
+        foreach (var call in script.Matches(DefineModel))
+        {
+            var start = call.Index + call.Length;
+            string? type = null;
+            if (script.Text[start] == '<')
+            {
+                if (script.AngleClose(start) is not { } angleClose)
+                    continue;
+                type = script.Text[(start + 1)..angleClose].Trim();
+                start = angleClose + 1;
+            }
+
+            var open = script.Text.IndexOf('(', start);
+            if (open < 0 || script.Close(open) is not { } close)
+                continue;
+
+            var name = "modelValue";
+            var nameIndex = call.Index;
+            var arguments = script.Arguments(open, close);
+            if (arguments.Count > 0 && StringLiteral.Match(arguments[0].Value) is { Success: true, Index: 0 } literal)
+            {
+                name = literal.Groups["value"].Value;
+                nameIndex = arguments[0].ValueIndex + literal.Groups["value"].Index;
+            }
+
+            var options = arguments.FirstOrDefault(a => a.Value.StartsWith('{'));
+            var required = options != null && script.Close(options.ValueIndex) is { } optionsClose
+                && script.Members(options.ValueIndex, optionsClose, typeLiteral: false).Any(o => o.Name == "required" && o.Value == "true");
+
+            var prop = Prop(lines, filePath, name, nameIndex, !required, type, new Dictionary<string, string>(), component);
+            prop.Signature += " (v-model)";
+            yield return (prop, Event(lines, filePath, $"update:{name}", nameIndex, type != null ? $"value: {type}" : "value", component));
+        }
+    }
+
+    /// <summary>
+    /// Static ref="name" attributes of a template; :ref bindings name no ref
+    /// </summary>
+    private static IEnumerable<JulieSymbol> TemplateRefs(string text, SfcBlock template, LineMap lines, string filePath, JulieSymbol component)
+    {
+        var content = text[template.ContentStart..template.ContentEnd];
+        foreach (Match match in TemplateRef.Matches(content))
+        {
+            var element = content.LastIndexOf('<', match.Index);
+            var tag = element < 0 ? string.Empty : Regex.Match(content[(element + 1)..], @"^[\w.:-]+").Value;
+            var name = match.Groups["name"];
+            var (line, column) = lines.Position(template.ContentStart + name.Index);
+            var symbol = Declare(filePath, "field", name.Value, line, column, line,
+                tag.Length > 0 ? $"ref=\"{name.Value}\" <{tag}>" : $"ref=\"{name.Value}\"", component);
+            symbol.EndColumn = column + name.Length;
+            yield return symbol;
+        }
+    }
+
+    private static JulieSymbol Prop(LineMap lines, string filePath, string name, int nameIndex, bool optional, string? type,
+        Dictionary<string, string> defaults, JulieSymbol component)
+    {
+        var (line, column) = lines.Position(nameIndex);
+        var signature = $"prop {name}{(optional ? "?" : string.Empty)}";
+        if (!string.IsNullOrWhiteSpace(type))
+            signature += $": {Regex.Replace(type.Trim(), @"\s+", " ")}";
+        if (defaults.TryGetValue(name, out var value) && !value.Contains('\n'))
+            signature += $" = {value}";
+
+        var symbol = Declare(filePath, "property", name, line, column, line, signature, component);
+        symbol.EndColumn = column + name.Length;
+        return symbol;
+    }
+
+    private static JulieSymbol Event(LineMap lines, string filePath, string name, int nameIndex, string parameters, JulieSymbol component)
+    {
+        var (line, column) = lines.Position(nameIndex);
+        var symbol = Declare(filePath, "event", name, line, column, line,
+            $"emit {name}({Regex.Replace(parameters, @"\s+", " ")})", component);
+        symbol.EndColumn = column + name.Length;
+        return symbol;
+    }
+
+    /// <summary>
+    /// The type of a runtime prop: String, String | Number for [String, Number], User for Object as PropType&lt;User&gt;
+    /// </summary>
+    private static string? RuntimeType(string? value)
+    {
+        if (string.IsNullOrWhiteSpace(value))
+            return null;
+
+        value = value.Trim();
+        if (Regex.Match(value, @"\bas\s+PropType<(?<type>.+)>$", RegexOptions.Singleline) is { Success: true } propType)
+            return propType.Groups["type"].Value.Trim();
+        if (value.StartsWith('['))
+            return string.Join(" | ", SplitTopLevel(value.TrimStart('[').TrimEnd(']')));
+        return value;
+    }
+
+    /// <summary>
+    /// The body of interface <paramref name="name"/> { ... } or type <paramref name="name"/> = { ... } in any script block
+    /// </summary>
+    private static (ScriptText Script, int Open, int Close)? TypeDeclaration(List<ScriptText> scripts, string name)
+    {
+        if (!Regex.IsMatch(name, @"^[A-Za-z_$][\w$]*$"))
+            return null;
+
+        var declaration = new Regex($@"\b(?:interface\s+{Regex.Escape(name)}\b[^{{=;]*|type\s+{Regex.Escape(name)}\s*=\s*)\{{");
+        foreach (var script in scripts)
+        {
+            foreach (var match in script.Matches(declaration))
+            {
+                var open = match.Index + match.Length - 1;
+                if (script.Close(open) is { } close)
+                    return (script, open, close);
+            }
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// The text inside the first parentheses of <paramref name="value"/>, or null when there are none
+    /// </summary>
+    private static string? Parenthesized(string value)
+    {
+        var open = value.IndexOf('(');
+        if (open < 0)
+            return null;
+
+        var depth = 0;
+        for (var i = open; i < value.Length; i++)
+        {
+            if (value[i] == '(')
+                depth++;
+            else if (value[i] == ')' && --depth == 0)
+                return value[(open + 1)..i];
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Comma-separated parts outside brackets, generics and strings, trimmed, empty parts dropped
+    /// </summary>
+    private static List<string> SplitTopLevel(string value)
+    {
+        var parts = new List<string>();
+        var depth = 0;
+        var start = 0;
+        char? quote = null;
+        for (var i = 0; i < value.Length; i++)
+        {
+            var c = value[i];
+            if (quote != null)
+            {
+                if (c == quote)
+                    quote = null;
+                continue;
+            }
+
+            switch (c)
+            {
+                case '\'' or '"' or '`':
+                    quote = c;
+                    break;
+                case '(' or '[' or '{' or '<':
+                    depth++;
+                    break;
+                case ')' or ']' or '}':
+                case '>' when i == 0 || value[i - 1] != '=':
+                    depth--;
+                    break;
+                case ',' when depth == 0:
+                    parts.Add(value[start..i].Trim());
+                    start = i + 1;
+                    break;
+            }
+        }
+        parts.Add(value[start..].Trim());
+        return parts.Where(p => p.Length > 0).ToList();
+    }
+
+    private static bool NameOnLine(string[] lines, int line, string name) =>
+        line >= 1 && line <= lines.Length && name.Length > 0
+        && Regex.IsMatch(lines[line - 1], $@"(?<![\w$]){Regex.Escape(name)}(?![\w$])");
+
+    private static JulieSymbol Declare(string filePath, string kind, string name, int line, int column, int endLine, string signature, JulieSymbol parent) =>
+        new()
+        {
+            Id = StableId(filePath, kind, name, line),
+            Name = name,
+            Kind = kind,
+            Language = "vue",
+            FilePath = filePath,
+            StartLine = line,
+            StartColumn = column,
+            EndLine = endLine,
+            Signature = signature,
+            Visibility = "public",
+            ParentId = parent.Id
+        };
+
+    private static string StableId(string filePath, string kind, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"vue:{filePath}:{kind}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed record SfcBlock(string Tag, int ContentStart, int ContentEnd, int TagLine);
+
+    /// <summary>
+    /// A member of an object or type literal. Call signatures have an empty name and the whole member as value.
+    /// </summary>
+    private sealed record Member(string Name, int NameIndex, bool Optional, string Value, int ValueIndex);
+
+    /// <summary>
+    /// Line and column of offsets into the component
+    /// </summary>
+    private sealed class LineMap
+    {
+        private readonly List<int> _starts = new() { 0 };
+        private readonly int _length;
+
+        public LineMap(string text)
+        {
+            for (var i = 0; i < text.Length; i++)
+            {
+                if (text[i] == '\n')
+                    _starts.Add(i + 1);
+            }
+            _length = text.Length;
+        }
+
+        public int Count => _starts.Count;
+
+        public (int Line, int Column) Position(int index)
+        {
+            var line = _starts.BinarySearch(index);
+            if (line < 0)
+                line = ~line - 1;
+            return (line + 1, index - _starts[line]);
+        }
+
+        public int LineLength(int line) =>
+            (line < _starts.Count ? _starts[line] - 1 : _length) - _starts[line - 1];
+    }
+
+    /// <summary>
+    /// A script block with the bracket depth of every character and whether it is code, a string or a comment,
+    /// so declarations and literals are found in code only
+    /// </summary>
+    private sealed class ScriptText
+    {
+        private const byte Code = 0, InString = 1, InComment = 2;
+
+        private readonly int[] _depth;
+        private readonly byte[] _kind;
+
+        public ScriptText(string text, SfcBlock block)
+        {
+            Text = text;
+            Block = block;
+            var length = block.ContentEnd - block.ContentStart;
+            _depth = new int[length + 1];
+            _kind = new byte[length + 1];
+
+            var depth = 0;
+            for (var i = 0; i < length; i++)
+            {
+                var at = block.ContentStart + i;
+                var c = text[at];
+                _depth[i] = depth;
+
+                if (c is '\'' or '"' or '`')
+                {
+                    var end = at + 1;
+                    while (end < block.ContentEnd && text[end] != c && !(c != '`' && text[end] == '\n'))
+                        end += text[end] == '\\' ? 2 : 1;
+                    i = Mark(i, Math.Min(end, block.ContentEnd - 1) - block.ContentStart, InString, depth);
+                }
+                else if (c == '/' && at + 1 < block.ContentEnd && text[at + 1] is '/' or '*')
+                {
+                    var end = text[at + 1] == '/'
+                        ? text.IndexOf('\n', at) - 1
+                        : text.IndexOf("*/", at + 2, StringComparison.Ordinal) + 1;
+                    if (end < at || end >= block.ContentEnd)
+                        end = block.ContentEnd - 1;
+                    i = Mark(i, end - block.ContentStart, InComment, depth);
+                }
+                else if (c is '(' or '[' or '{')
+                {
+                    depth++;
+                }
+                else if (c is ')' or ']' or '}')
+                {
+                    depth = Math.Max(0, depth - 1);
+                }
+            }
+            _depth[length] = depth;
+        }
+
+        public string Text { get; }
+
+        public SfcBlock Block { get; }
+
+        public bool IsCode(int index) => _kind[index - Block.ContentStart] == Code;
+
+        public int DepthAt(int index) => _depth[index - Block.ContentStart];
+
+        /// <summary>
+        /// Matches in the code of the block, at offsets into the component
+        /// </summary>
+        public IEnumerable<Match> Matches(Regex regex)
+        {
+            var position = Block.ContentStart;
+            while (position < Block.ContentEnd && regex.Match(Text, position, Block.ContentEnd - position) is { Success: true } match)
+            {
+                if (IsCode(match.Index))
+                    yield return match;
+                position = match.Index + Math.Max(1, match.Length);
+            }
+        }
+
+        /// <summary>
+        /// The closing bracket of the bracket at <paramref name="open"/>
+        /// </summary>
+        public int? Close(int open)
+        {
+            if (open < Block.ContentStart || open >= Block.ContentEnd || Text[open] is not ('(' or '[' or '{') || !IsCode(open))
+                return null;
+
+            var depth = DepthAt(open);
+            for (var i = open + 1; i < Block.ContentEnd; i++)
+            {
+                if (IsCode(i) && Text[i] is ')' or ']' or '}' && DepthAt(i) == depth + 1)
+                    return i;
+            }
+            return null;
+        }
+
+        /// <summary>
+        /// The &gt; closing the type arguments opened at <paramref name="open"/>; arrows (=&gt;) are not brackets
+        /// </summary>
+        public int? AngleClose(int open)
+        {
+            var depth = 0;
+            for (var i = open; i < Block.ContentEnd; i++)
+            {
+                if (!IsCode(i))
+                    continue;
+                if (Text[i] == '<')
+                    depth++;
+                else if (Text[i] == '>' && Text[i - 1] != '=' && --depth == 0)
+                    return i;
+            }
+            return null;
+        }
+
+        /// <summary>
+        /// The last character of the statement starting on the line at <paramref name="lineStart"/>: the end of the
+        /// line, or of the last line inside the brackets it opens
+        /// </summary>
+        public int StatementEnd(int lineStart, int lineEnd)
+        {
+            var depth = DepthAt(lineStart);
+            while (lineEnd < Block.ContentEnd && DepthAt(lineEnd) > depth)
+            {
+                var next = Text.IndexOf('\n', lineEnd + 1);
+                lineEnd = next < 0 || next > Block.ContentEnd ? Block.ContentEnd : next;
+            }
+            return Math.Max(lineStart, lineEnd - 1);
+        }
+
+        /// <summary>
+        /// String literals between two brackets, with the offset of their value
+        /// </summary>
+        public IEnumerable<(string Value, int Index)> Strings(int open, int close)
+        {
+            foreach (Match match in StringLiteral.Matches(Text[open..close]))
+            {
+                if (DepthAt(open + match.Index) == DepthAt(open) + 1)
+                    yield return (match.Groups["value"].Value, open + match.Groups["value"].Index);
+            }
+        }
+
+        /// <summary>
+        /// The members of the object or type literal between two brackets. Members are separated by commas, and in
+        /// type literals also by semicolons and line breaks. Comments are skipped; spread members are dropped.
+        /// </summary>
+        public List<Member> Members(int open, int close, bool typeLiteral)
+        {
+            var members = new List<Member>();
+            foreach (var (start, text) in Segments(open, close, typeLiteral))
+            {
+                if (Member(start, text) is { } member)
+                    members.Add(member);
+            }
+            return members;
+        }
+
+        /// <summary>
+        /// The comma-separated arguments between two parentheses, as members with an empty name
+        /// </summary>
+        public List<Member> Arguments(int open, int close) =>
+            Segments(open, close, typeLiteral: false).Select(s => new Member(string.Empty, s.Start, false, s.Text, s.Start)).ToList();
+
+        /// <summary>
+        /// The separated parts between two brackets, starting at their first character that is not blank or
+        /// commented, with comments blanked out
+        /// </summary>
+        private IEnumerable<(int Start, string Text)> Segments(int open, int close, bool typeLiteral)
+        {
+            var depth = DepthAt(open) + 1;
+            var angles = 0;
+            var start = open + 1;
+            for (var i = open + 1; i <= close; i++)
+            {
+                if (i < close)
+                {
+                    if (!IsCode(i) || DepthAt(i) != depth)
+                        continue;
+                    if (typeLiteral && Text[i] == '<')
+                        angles++;
+                    else if (typeLiteral && Text[i] == '>' && Text[i - 1] != '=' && angles > 0)
+                        angles--;
+                    if (angles > 0 || (typeLiteral ? Text[i] is not (',' or ';' or '\n') : Text[i] != ','))
+                        continue;
+                }
+
+                while (start < i && (char.IsWhiteSpace(Text[start]) || _kind[start - Block.ContentStart] == InComment))
+                    start++;
+                if (start < i)
+                {
+                    var segment = new StringBuilder(Text, start, i - start, i - start);
+                    for (var j = 0; j < segment.Length; j++)
+                    {
+                        if (_kind[start + j - Block.ContentStart] == InComment)
+                            segment[j] = ' ';
+                    }
+                    yield return (start, segment.ToString().TrimEnd());
+                }
+                start = i + 1;
+            }
+        }
+
+        private static Member? Member(int start, string text)
+        {
+            if (text.StartsWith('.'))
+                return null;
+
+            if (text.StartsWith('('))
+                return new Member(string.Empty, start, false, text, start);
+
+            var head = MemberHead.Match(text);
+            if (!head.Success)
+                return null;
+
+            var rest = text[head.Length..];
+            var name = head.Groups["name"];
+            var optional = head.Groups["optional"].Success;
+            if (rest.StartsWith(':'))
+            {
+                var value = rest[1..].TrimStart();
+                return new Member(name.Value, start + name.Index, optional, value, start + text.Length - value.Length);
+            }
+
+            // Method shorthand keeps its parameters and body as the value; { title } is title: title
+            return rest.Length == 0 || rest.StartsWith('(')
+                ? new Member(name.Value, start + name.Index, optional, rest.Length == 0 ? name.Value : rest, start + head.Length)
+                : null;
+        }
+
+        private int Mark(int from, int to, byte kind, int depth)
+        {
+            for (var j = from; j <= to; j++)
+            {
+                _kind[j] = kind;
+                _depth[j] = depth;
+            }
+            return to;
+        }
+    }
+}
@@ -0,0 +1,358 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// An onboarding report of a workspace, built from its indexed files
+/// </summary>
+public class WorkspaceReport
+{
+    public int Files { get; set; }
+    public int Lines { get; set; }
+
+    /// <summary>
+    /// Languages by file count, largest first
+    /// </summary>
+    public List<LanguageShare> Languages { get; set; } = new();
+
+    public List<WorkspaceEntryPoint> EntryPoints { get; set; } = new();
+
+    /// <summary>
+    /// Top-level directories grouped into clusters, with the import links between them
+    /// </summary>
+    public List<PackageCluster> Clusters { get; set; } = new();
+
+    public List<ConfigurationFile> Configuration { get; set; } = new();
+
+    public TestLayout Tests { get; set; } = new();
+}
+
+public class LanguageShare
+{
+    public string Language { get; set; } = string.Empty;
+    public int Files { get; set; }
+    public int Lines { get; set; }
+
+    /// <summary>
+    /// Share of the workspace's code lines, 0-100
+    /// </summary>
+    public double Percent { get; set; }
+}
+
+/// <summary>
+/// Where execution starts: a main function, a host builder, a script guard, a package.json bin or start script,
+/// or a container entrypoint
+/// </summary>
+public class WorkspaceEntryPoint
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// main, host, script, package or container
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The matching line, trimmed
+    /// </summary>
+    public string Detail { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// A group of source files under one directory prefix
+/// </summary>
+public class PackageCluster
+{
+    /// <summary>
+    /// Directory prefix, or "(root)" for files at the workspace root
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    public int Files { get; set; }
+    public List<string> Languages { get; set; } = new();
+
+    /// <summary>
+    /// Clusters this one imports, most import links first
+    /// </summary>
+    public List<string> DependsOn { get; set; } = new();
+
+    /// <summary>
+    /// Clusters importing this one
+    /// </summary>
+    public List<string> UsedBy { get; set; } = new();
+
+    /// <summary>
+    /// application (holds an entry point), top (nothing imports it), core (imports no other cluster but is
+    /// imported), middle, or isolated
+    /// </summary>
+    public string Role { get; set; } = string.Empty;
+}
+
+public class ConfigurationFile
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// build, settings, container, ci, deploy or tooling
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+}
+
+public class TestLayout
+{
+    public int TestFiles { get; set; }
+    public int Tests { get; set; }
+
+    /// <summary>
+    /// Test frameworks by number of tests, largest first
+    /// </summary>
+    public List<string> Frameworks { get; set; } = new();
+
+    /// <summary>
+    /// Directories holding the most test files
+    /// </summary>
+    public List<string> Directories { get; set; } = new();
+
+    /// <summary>
+    /// separate (test projects or folders), co-located (beside the code), mixed, or none
+    /// </summary>
+    public string Layout { get; set; } = "none";
+}
+
+/// <summary>
+/// Builds the workspace overview: languages, entry points, directory clusters linked by the imports between
+/// them (see <see cref="ImportGraph"/>), key configuration and test layout. Nothing is built or run, so the
+/// report works on any indexed workspace.
+/// </summary>
+public static class WorkspaceOverview
+{
+    private const string Root = "(root)";
+
+    private static readonly Regex GoMainPackage = new(@"^package\s+main\b", RegexOptions.Compiled | RegexOptions.Multiline);
+    private static readonly Regex EntryLine = new(
+        @"^\s*(?:func\s+main\s*\(\s*\)|(?:public\s+|private\s+|internal\s+)?static\s+(?:async\s+)?(?:void|int|Task|Task<int>)\s+Main\s*\(|public\s+static\s+void\s+main\s*\(\s*String|fun\s+main\s*\(|(?:pub\s+)?(?:async\s+)?fn\s+main\s*\(\s*\)|if\s+__name__\s*==\s*['""]__main__['""])",
+        RegexOptions.Compiled);
+    private static readonly Regex HostBuilder = new(
+        @"\b(?:WebApplication\.Create(?:Slim)?Builder|Host\.Create(?:Default|Application)Builder|express\(\)|\.listen\(\s*(?:\d+|port|PORT|process\.env)|uvicorn\.run|FastAPI\(\)|Flask\(__name__\)|http\.ListenAndServe)",
+        RegexOptions.Compiled);
+    private static readonly Regex ContainerEntry = new(@"^\s*(?:ENTRYPOINT|CMD)\s+(?<command>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex PackageField = new(@"^\s*""(?<field>main|bin|start)""\s*:\s*(?<value>""[^""]*""|\{)", RegexOptions.Compiled);
+
+
+    private static readonly (string Kind, Regex Pattern)[] ConfigurationPatterns =
+    {
+        ("build", new Regex(@"(?:^|/)(?:[^/]+\.(?:csproj|fsproj|vbproj|sln)|Directory\.Build\.(?:props|targets)|go\.mod|package\.json|tsconfig(?:\.[\w-]+)?\.json|pyproject\.toml|setup\.(?:py|cfg)|requirements(?:[-.\w]*)\.txt|Pipfile|Cargo\.toml|pom\.xml|build\.gradle(?:\.kts)?|settings\.gradle(?:\.kts)?|Makefile|CMakeLists\.txt|vite\.config\.\w+|webpack\.config\.\w+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase)),
+        ("settings", new Regex(@"(?:^|/)(?:appsettings(?:\.[\w-]+)?\.json|\.env(?:\.[\w-]+)?|[^/]+\.env|config(?:uration)?\.(?:json|ya?ml|toml|ini)|settings\.(?:py|json|ya?ml|toml)|application(?:-[\w-]+)?\.(?:ya?ml|properties)|web\.config|launchSettings\.json)$", RegexOptions.Compiled | RegexOptions.IgnoreCase)),
+        ("container", new Regex(@"(?:^|/)(?:Dockerfile(?:\.[\w-]+)?|[\w-]+\.Dockerfile|(?:docker-)?compose(?:\.[\w-]+)?\.ya?ml|\.dockerignore)$", RegexOptions.Compiled | RegexOptions.IgnoreCase)),
+        ("ci", new Regex(@"(?:^|/)(?:\.github/workflows/[^/]+\.ya?ml|azure-pipelines[\w.-]*\.ya?ml|\.gitlab-ci\.ya?ml|Jenkinsfile|\.circleci/config\.ya?ml)$", RegexOptions.Compiled | RegexOptions.IgnoreCase)),
+        ("deploy", new Regex(@"(?:^|/)(?:Chart\.yaml|values(?:\.[\w-]+)?\.yaml|kustomization\.ya?ml|[^/]+\.tf|serverless\.ya?ml|Procfile)$", RegexOptions.Compiled | RegexOptions.IgnoreCase)),
+        ("tooling", new Regex(@"(?:^|/)(?:\.editorconfig|\.eslintrc(?:\.\w+)?|eslint\.config\.\w+|\.prettierrc(?:\.\w+)?|\.golangci\.ya?ml|ruff\.toml|\.pre-commit-config\.yaml|global\.json|nuget\.config|\.nvmrc|\.tool-versions)$", RegexOptions.Compiled | RegexOptions.IgnoreCase))
+    };
+
+    /// <summary>
+    /// The report for a workspace's files (workspace-relative paths with forward slashes; content may be null
+    /// for files indexed by name only)
+    /// </summary>
+    /// <param name="clusterDepth">Directory levels that name a cluster: 2 groups src/Services/Auth/x.cs under src/Services</param>
+    public static WorkspaceReport Build(IReadOnlyList<(string Path, string? Content)> files, int clusterDepth = 2)
+    {
+        var report = new WorkspaceReport { Files = files.Count };
+        var lineCounts = files.ToDictionary(f => f.Path, f => f.Content == null ? 0 : f.Content.Count(c => c == '\n') + 1, StringComparer.Ordinal);
+        report.Lines = lineCounts.Values.Sum();
+
+        var tests = files.Where(f => TestFileDetector.IsTestFile(f.Path)).ToList();
+        var testPaths = tests.Select(f => f.Path).ToHashSet(StringComparer.Ordinal);
+        var sources = files.Where(f => !testPaths.Contains(f.Path) && LanguageOf(f.Path) != null).ToList();
+
+        // Languages
+        var code = files.Select(f => (f.Path, Language: LanguageOf(f.Path))).Where(f => f.Language != null).ToList();
+        var codeLines = Math.Max(1, code.Sum(f => lineCounts[f.Path]));
+        report.Languages = code
+            .GroupBy(f => f.Language!)
+            .Select(g => new LanguageShare
+            {
+                Language = g.Key,
+                Files = g.Count(),
+                Lines = g.Sum(f => lineCounts[f.Path]),
+                Percent = Math.Round(100.0 * g.Sum(f => lineCounts[f.Path]) / codeLines, 1)
+            })
+            .OrderByDescending(l => l.Lines)
+            .ThenBy(l => l.Language, StringComparer.Ordinal)
+            .ToList();
+
+        // Entry points
+        foreach (var (path, content) in files.Where(f => f.Content != null && !testPaths.Contains(f.Path)))
+        {
+            report.EntryPoints.AddRange(FindEntryPoints(path, content!));
+        }
+
+        // Configuration
+        report.Configuration = files
+            .Select(f => (f.Path, Kind: ConfigurationPatterns.FirstOrDefault(p => p.Pattern.IsMatch(f.Path)).Kind))
+            .Where(f => f.Kind != null)
+            .Select(f => new ConfigurationFile { FilePath = f.Path, Kind = f.Kind! })
+            .OrderBy(f => Array.FindIndex(ConfigurationPatterns, p => p.Kind == f.Kind))
+            .ThenBy(f => f.FilePath.Count(c => c == '/'))
+            .ThenBy(f => f.FilePath, StringComparer.Ordinal)
+            .ToList();
+
+        report.Clusters = BuildClusters(sources, report.EntryPoints, clusterDepth);
+        report.Tests = BuildTestLayout(tests, sources);
+        return report;
+    }
+
+    /// <summary>
+    /// Entry points declared in one file
+    /// </summary>
+    public static List<WorkspaceEntryPoint> FindEntryPoints(string path, string content)
+    {
+        var entries = new List<WorkspaceEntryPoint>();
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var fileName = path[(path.LastIndexOf('/') + 1)..];
+        var extension = Path.GetExtension(path).ToLowerInvariant();
+
+        if (fileName.Equals("package.json", StringComparison.OrdinalIgnoreCase))
+        {
+            for (var i = 0; i < lines.Length; i++)
+            {
+                if (PackageField.IsMatch(lines[i]))
+                    entries.Add(Entry(path, i, "package", lines[i]));
+            }
+            return entries;
+        }
+        if (fileName.StartsWith("Dockerfile", StringComparison.OrdinalIgnoreCase) || fileName.EndsWith(".Dockerfile", StringComparison.OrdinalIgnoreCase))
+        {
+            for (var i = 0; i < lines.Length; i++)
+            {
+                if (ContainerEntry.IsMatch(lines[i]))
+                    entries.Add(Entry(path, i, "container", lines[i]));
+            }
+            return entries;
+        }
+        if (LanguageOf(path) == null)
+            return entries;
+
+        var goMain = extension == ".go" && GoMainPackage.IsMatch(content);
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            if (EntryLine.IsMatch(line) && (extension != ".go" || goMain))
+            {
+                entries.Add(Entry(path, i, line.Contains("__main__") ? "script" : "main", line));
+            }
+            else if (HostBuilder.IsMatch(line))
+            {
+                entries.Add(Entry(path, i, "host", line));
+            }
+        }
+
+        if (entries.Count == 0 && fileName == "__main__.py")
+            entries.Add(Entry(path, 0, "main", lines[0]));
+
+        // One host per file is enough; a Program.cs both builds and runs the host
+        return entries.GroupBy(e => e.Kind).Select(g => g.First()).OrderBy(e => e.Line).ToList();
+    }
+
+    private static List<PackageCluster> BuildClusters(IReadOnlyList<(string Path, string? Content)> sources, IReadOnlyList<WorkspaceEntryPoint> entryPoints, int depth)
+    {
+        var links = new Dictionary<(string From, string To), int>();
+        foreach (var import in ImportGraph.Build(sources))
+        {
+            var from = ClusterOf(import.FromPath, depth);
+            foreach (var target in import.Targets.Select(t => ClusterOf(t, depth)).Distinct())
+            {
+                if (target != from)
+                    links[(from, target)] = links.GetValueOrDefault((from, target)) + 1;
+            }
+        }
+
+        var withEntries = entryPoints.Where(e => e.Kind is "main" or "host" or "script").Select(e => ClusterOf(e.FilePath, depth)).ToHashSet(StringComparer.Ordinal);
+        return sources
+            .GroupBy(s => ClusterOf(s.Path, depth))
+            .Select(g =>
+            {
+                var dependsOn = links.Where(l => l.Key.From == g.Key).OrderByDescending(l => l.Value).ThenBy(l => l.Key.To, StringComparer.Ordinal).Select(l => l.Key.To).ToList();
+                var usedBy = links.Where(l => l.Key.To == g.Key).OrderByDescending(l => l.Value).ThenBy(l => l.Key.From, StringComparer.Ordinal).Select(l => l.Key.From).ToList();
+                return new PackageCluster
+                {
+                    Name = g.Key,
+                    Files = g.Count(),
+                    Languages = g.GroupBy(s => LanguageOf(s.Path)!).OrderByDescending(l => l.Count()).Select(l => l.Key).ToList(),
+                    DependsOn = dependsOn,
+                    UsedBy = usedBy,
+                    Role = withEntries.Contains(g.Key) ? "application"
+                        : usedBy.Count == 0 && dependsOn.Count > 0 ? "top"
+                        : usedBy.Count > 0 && dependsOn.Count == 0 ? "core"
+                        : usedBy.Count > 0 ? "middle"
+                        : "isolated"
+                };
+            })
+            .OrderByDescending(c => c.UsedBy.Count + c.DependsOn.Count)
+            .ThenByDescending(c => c.Files)
+            .ThenBy(c => c.Name, StringComparer.Ordinal)
+            .ToList();
+    }
+
+    private static TestLayout BuildTestLayout(IReadOnlyList<(string Path, string? Content)> tests, IReadOnlyList<(string Path, string? Content)> sources)
+    {
+        var layout = new TestLayout { TestFiles = tests.Count };
+        if (tests.Count == 0)
+            return layout;
+
+        var frameworks = new Dictionary<string, int>(StringComparer.Ordinal);
+        foreach (var (path, content) in tests.Where(t => t.Content != null))
+        {
+            foreach (var test in TestDiscovery.FindTests(path, content!))
+            {
+                layout.Tests++;
+                frameworks[test.Framework] = frameworks.GetValueOrDefault(test.Framework) + 1;
+            }
+        }
+        layout.Frameworks = frameworks.OrderByDescending(f => f.Value).ThenBy(f => f.Key, StringComparer.Ordinal).Select(f => f.Key).Where(f => f.Length > 0).ToList();
+        layout.Directories = tests
+            .GroupBy(t => DirectoryOf(t.Path))
+            .OrderByDescending(g => g.Count())
+            .ThenBy(g => g.Key, StringComparer.Ordinal)
+            .Take(5)
+            .Select(g => g.Key.Length == 0 ? Root : g.Key)
+            .ToList();
+
+        // Beside the code when a test's directory also holds source files
+        var sourceDirectories = sources.Select(s => DirectoryOf(s.Path)).ToHashSet(StringComparer.Ordinal);
+        var colocated = tests.Count(t => sourceDirectories.Contains(DirectoryOf(t.Path)));
+        layout.Layout = colocated * 5 >= tests.Count * 4 ? "co-located"
+            : colocated * 5 <= tests.Count ? "separate"
+            : "mixed";
+        return layout;
+    }
+
+    /// <summary>
+    /// The language of a code file, or null for configuration, documentation and other files
+    /// </summary>
+    public static string? LanguageOf(string path)
+    {
+        var extension = Path.GetExtension(path);
+        return extension.Length == 0 ? null : LanguageCapabilities.Find(extension)?.Name;
+    }
+
+    /// <summary>
+    /// The first <paramref name="depth"/> directories of a path
+    /// </summary>
+    public static string ClusterOf(string path, int depth)
+    {
+        var segments = path.Split('/');
+        var directories = segments.Take(segments.Length - 1).Take(Math.Max(1, depth)).ToArray();
+        return directories.Length == 0 ? Root : string.Join('/', directories);
+    }
+
+    private static string DirectoryOf(string path) => path.Contains('/') ? path[..path.LastIndexOf('/')] : string.Empty;
+
+    private static WorkspaceEntryPoint Entry(string path, int index, string kind, string line) => new()
+    {
+        FilePath = path,
+        Line = index + 1,
+        Kind = kind,
+        Detail = line.Trim().Length > 120 ? line.Trim()[..117] + "..." : line.Trim()
+    };
+}
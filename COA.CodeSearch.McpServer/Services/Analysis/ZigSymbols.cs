@@ -0,0 +1,401 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Analysis;
+
+/// <summary>
+/// Zig declarations read from source: functions (pub, export, extern, inline), structs, unions, opaque types,
+/// enums and error sets bound to constants, their fields, values and errors, container-level constants and
+/// variables (comptime and threadlocal included) and generic types - functions taking comptime parameters and
+/// returning a type, whose returned struct's members are parented to the function. Locals inside function,
+/// test and comptime block bodies are left out. Fills in what julie-codesearch's Zig extraction misses.
+/// </summary>
+public static class ZigSymbols
+{
+    private const string Name = @"(?<name>[\p{L}_]\w*|@""[^""]*"")";
+    private const string Modifiers = @"(?<modifiers>(?:(?:pub|export|extern(?:\s+""[^""]*"")?|inline|noinline|threadlocal|comptime)\s+)*)";
+
+    private static readonly Regex Function = new($@"^\s*{Modifiers}fn\s+{Name}\s*\(", RegexOptions.Compiled);
+    private static readonly Regex Binding = new($@"^\s*{Modifiers}(?<keyword>const|var)\s+{Name}", RegexOptions.Compiled);
+    private static readonly Regex Container = new(
+        @"=\s*(?:(?:extern|packed)\s+)?(?<container>struct|enum|union|opaque|error)\b\s*(?:\([^()]*\)\s*)?\{", RegexOptions.Compiled);
+    private static readonly Regex Returned = new(
+        @"^\s*return\s+(?:(?:extern|packed)\s+)?(?<container>struct|enum|union|opaque)\b\s*(?:\([^()]*\)\s*)?\{", RegexOptions.Compiled);
+    private static readonly Regex Block = new(@"^\s*(?:test\b|comptime\s*\{)", RegexOptions.Compiled);
+    private static readonly Regex TypeFunction = new(@"\)\s*type\s*\{?\s*$", RegexOptions.Compiled);
+    private static readonly Regex Member = new(@"^\s*(?<name>[\p{L}_]\w*|@""[^""]*"")", RegexOptions.Compiled);
+    private static readonly Regex ComptimeParameter = new(@"[(,]\s*comptime\s+(?<name>[\p{L}_]\w*)\s*:", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> Keywords = new(StringComparer.Ordinal)
+    {
+        "pub", "fn", "const", "var", "comptime", "test", "usingnamespace", "export", "extern", "inline", "noinline", "threadlocal"
+    };
+
+    /// <summary>
+    /// True for Zig sources
+    /// </summary>
+    public static bool Handles(string filePath) =>
+        filePath.EndsWith(".zig", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Every declaration in a Zig file, in source order, with parents set
+    /// </summary>
+    public static List<JulieSymbol> Extract(string filePath, string content)
+    {
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+        var masked = DataFlowScanner.MaskLines(BlankMultilineStrings(lines), ".zig");
+        var found = new List<Found>();
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (!string.IsNullOrWhiteSpace(masked[i]) && Read(lines, masked, i) is { } declaration)
+                found.Add(declaration);
+        }
+
+        // Declarations inside function, test and comptime bodies are locals, except the struct a generic type returns
+        var kept = new List<Found>();
+        foreach (var declaration in found)
+        {
+            var container = Innermost(found, declaration);
+            if (declaration.Kind == "returned")
+            {
+                if (container?.Kind == "function" && TypeFunction.IsMatch(container.Signature))
+                {
+                    container.Kind = declaration.Keyword == "enum" ? "enum" : "struct";
+                    declaration.Container = container;
+                }
+                continue;
+            }
+            if (container?.Kind == "returned")
+                container = container.Container;
+            if (declaration.Kind == "block" || container != null && container.Kind is not ("struct" or "enum"))
+                continue;
+            declaration.Container = container;
+            kept.Add(declaration);
+        }
+
+        // Fields, enum values and errors are the comma-separated members of a container body
+        foreach (var type in kept.Where(d => d.Kind is "struct" or "enum").ToList())
+        {
+            var body = found.FirstOrDefault(d => d.Kind == "returned" && d.Container == type) ?? type;
+            kept.AddRange(Members(lines, masked, body, type, found));
+        }
+
+        var symbols = new Dictionary<Found, JulieSymbol>();
+        foreach (var declaration in kept.OrderBy(d => d.Line).ThenBy(d => d.Column))
+        {
+            var kind = declaration.Kind switch
+            {
+                "function" when declaration.Container != null => "method",
+                _ => declaration.Kind
+            };
+            symbols[declaration] = new JulieSymbol
+            {
+                Id = StableId(filePath, QualifiedName(declaration), declaration.Line),
+                Name = declaration.Name,
+                Kind = kind,
+                Language = "zig",
+                FilePath = filePath,
+                StartLine = declaration.Line,
+                StartColumn = declaration.Column,
+                EndLine = declaration.EndLine,
+                EndColumn = lines[declaration.EndLine - 1].Length,
+                Signature = declaration.Signature,
+                DocComment = DocComment(lines, declaration.Line - 1),
+                Visibility = declaration.Visibility
+            };
+        }
+        foreach (var (declaration, symbol) in symbols)
+        {
+            if (declaration.Container != null && symbols.TryGetValue(declaration.Container, out var parent))
+                symbol.ParentId = parent.Id;
+        }
+        return symbols.Values.ToList();
+    }
+
+    /// <summary>
+    /// The symbols of a Zig file with the declarations julie-codesearch missed added and parents filled in.
+    /// Extracted symbols keep their IDs. Returns <paramref name="symbols"/> itself when nothing was missing.
+    /// </summary>
+    public static List<JulieSymbol> Repair(string filePath, string content, List<JulieSymbol> symbols) =>
+        SymbolRepairs.Merge(symbols, Extract(filePath, content));
+
+    /// <summary>
+    /// The comptime parameters of a function signature (T for <c>fn ArrayList(comptime T: type) type</c>), which
+    /// make a function generic
+    /// </summary>
+    public static IReadOnlyList<string> ComptimeParametersOf(string? signature) =>
+        string.IsNullOrEmpty(signature)
+            ? Array.Empty<string>()
+            : ComptimeParameter.Matches(signature).Select(m => m.Groups["name"].Value).ToList();
+
+    private static Found? Read(string[] lines, string[] masked, int index)
+    {
+        var line = masked[index];
+        Match match;
+        string kind;
+        var keyword = string.Empty;
+
+        if ((match = Function.Match(line)).Success)
+        {
+            kind = "function";
+        }
+        else if ((match = Binding.Match(line)).Success)
+        {
+            keyword = match.Groups["keyword"].Value;
+            var container = Container.Match(line, match.Index + match.Length);
+            kind = container.Success
+                ? container.Groups["container"].Value is "enum" or "error" ? "enum" : "struct"
+                : keyword == "const" ? "constant" : "variable";
+        }
+        else if ((match = Returned.Match(line)).Success)
+        {
+            return new Found
+            {
+                Kind = "returned",
+                Keyword = match.Groups["container"].Value,
+                Line = index + 1,
+                Column = line.Length - line.TrimStart().Length,
+                EndLine = EndOf(masked, index, match.Index, true) + 1
+            };
+        }
+        else if ((match = Block.Match(line)).Success)
+        {
+            var column = line.Length - line.TrimStart().Length;
+            return new Found { Kind = "block", Line = index + 1, Column = column, EndLine = EndOf(masked, index, column, true) + 1 };
+        }
+        else
+        {
+            return null;
+        }
+
+        var name = match.Groups["name"];
+        return new Found
+        {
+            Kind = kind,
+            Keyword = keyword,
+            Name = lines[index].Substring(name.Index, name.Length),
+            Line = index + 1,
+            Column = name.Index,
+            EndLine = EndOf(masked, index, name.Index, kind == "function") + 1,
+            Signature = Signature(lines[index]),
+            Visibility = Regex.IsMatch(match.Groups["modifiers"].Value, @"\b(?:pub|export)\b") ? "public" : "private"
+        };
+    }
+
+    /// <summary>
+    /// The fields of a struct or union, or the values of an enum or error set: the comma-separated names at the
+    /// top level of the container body, skipping the functions and constants declared between them
+    /// </summary>
+    private static IEnumerable<Found> Members(string[] lines, string[] masked, Found body, Found type, List<Found> found)
+    {
+        var line = body.Line - 1;
+        var match = body == type ? Container.Match(masked[line], type.Column) : Returned.Match(masked[line]);
+        if (!match.Success)
+            yield break;
+
+        var open = match.Index + match.Length - 1;
+        var fields = match.Groups["container"].Value is not ("enum" or "error");
+        var depth = 0;
+        for (var i = line; i < body.EndLine; i++)
+        {
+            if (i > line && depth == 0 && found.FirstOrDefault(d => d.Line == i + 1 && d.Kind != "returned") is { } nested)
+            {
+                i = nested.EndLine - 1;
+                continue;
+            }
+
+            var segmentStart = i == line ? open + 1 : 0;
+            var pending = true;
+            for (var c = segmentStart; c <= masked[i].Length; c++)
+            {
+                var ch = c < masked[i].Length ? masked[i][c] : '\n';
+                if (depth == 0 && pending && ch is ':' or '=' or ',' or '}' or '\n' or '(')
+                {
+                    var segment = masked[i][segmentStart..c];
+                    var member = Member.Match(segment);
+                    var bare = member.Success && segment.Trim() == member.Groups["name"].Value;
+                    if (member.Success && !Keywords.Contains(member.Groups["name"].Value)
+                        && (fields ? ch == ':' || bare && ch is ',' or '}' : ch != ':' && ch != '(' && (bare || ch == '=')))
+                    {
+                        var start = segmentStart + member.Groups["name"].Index;
+                        pending = false;
+                        yield return new Found
+                        {
+                            Kind = fields ? "field" : "enum_member",
+                            Name = lines[i].Substring(start, member.Groups["name"].Length),
+                            Line = i + 1,
+                            Column = start,
+                            EndLine = MemberEnd(masked, i, start, body.EndLine) + 1,
+                            Signature = lines[i].Trim().TrimEnd(','),
+                            Visibility = "public",
+                            Container = type
+                        };
+                    }
+                    else if (ch != '\n')
+                    {
+                        pending = false;
+                    }
+                }
+
+                switch (ch)
+                {
+                    case '(' or '{' or '[':
+                        depth++;
+                        break;
+                    case ')' or ']':
+                        depth--;
+                        break;
+                    case '}' when depth == 0:
+                        yield break;
+                    case '}':
+                        depth--;
+                        break;
+                    case ',' when depth == 0:
+                        pending = true;
+                        segmentStart = c + 1;
+                        break;
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// The last line (0-based) of a field or value: up to its type and default, before the separating comma
+    /// </summary>
+    private static int MemberEnd(string[] masked, int index, int column, int bodyEnd)
+    {
+        var depth = 0;
+        var last = index;
+        for (var i = index; i < bodyEnd; i++)
+        {
+            for (var c = i == index ? column : 0; c < masked[i].Length; c++)
+            {
+                var ch = masked[i][c];
+                if (depth == 0 && ch is ',' or '}')
+                    return last;
+                if (ch is '(' or '{' or '[')
+                    depth++;
+                else if (ch is ')' or '}' or ']')
+                    depth--;
+                if (!char.IsWhiteSpace(ch))
+                    last = i;
+            }
+        }
+        return last;
+    }
+
+    /// <summary>
+    /// The innermost other declaration whose extent holds <paramref name="declaration"/>
+    /// </summary>
+    private static Found? Innermost(List<Found> found, Found declaration) =>
+        found.Where(d => d != declaration && d.Line <= declaration.Line && d.EndLine >= declaration.Line
+                && (d.Line < declaration.Line || d.Column < declaration.Column))
+            .OrderByDescending(d => d.Line)
+            .ThenByDescending(d => d.Column)
+            .FirstOrDefault();
+
+    /// <summary>
+    /// The line (0-based) ending a declaration: the semicolon after a binding's value, or for functions, tests
+    /// and comptime blocks the brace closing the body (an error set in a return type is not the body)
+    /// </summary>
+    private static int EndOf(string[] masked, int index, int column, bool body)
+    {
+        var depth = 0;
+        var bodyOpen = false;
+        for (var i = index; i < masked.Length; i++)
+        {
+            var text = masked[i];
+            for (var c = i == index ? column : 0; c < text.Length; c++)
+            {
+                switch (text[c])
+                {
+                    case '{':
+                        if (body && depth == 0 && !Regex.IsMatch(text[..c], @"\b(?:error|struct|enum|union|opaque)\s*(?:\([^()]*\)\s*)?$"))
+                            bodyOpen = true;
+                        depth++;
+                        break;
+                    case '(' or '[':
+                        depth++;
+                        break;
+                    case '}':
+                        if (--depth == 0 && bodyOpen)
+                            return i;
+                        break;
+                    case ')' or ']':
+                        depth--;
+                        break;
+                    case ';' when depth == 0:
+                        return i;
+                }
+            }
+        }
+        return masked.Length - 1;
+    }
+
+    /// <summary>
+    /// Lines of <c>\\</c> multiline string literals blanked, so their text is not read as declarations or braces
+    /// </summary>
+    private static string[] BlankMultilineStrings(string[] lines) =>
+        lines.Select(l =>
+        {
+            var start = l.IndexOf(@"\\", StringComparison.Ordinal);
+            return start < 0 || l[..start].Contains('"') || l[..start].Contains("//", StringComparison.Ordinal)
+                ? l
+                : l[..start] + new string(' ', l.Length - start);
+        }).ToArray();
+
+    private static string Signature(string line)
+    {
+        var signature = line.Trim();
+        if (signature.EndsWith('{'))
+            signature = signature[..^1].TrimEnd();
+        return signature.Length > 200 ? signature[..197] + "..." : signature;
+    }
+
+    /// <summary>
+    /// The <c>///</c> doc comment lines right above a declaration
+    /// </summary>
+    private static string? DocComment(string[] lines, int index)
+    {
+        var end = index - 1;
+        if (end < 0 || !lines[end].TrimStart().StartsWith("///", StringComparison.Ordinal))
+            return null;
+
+        var i = end;
+        while (i > 0 && lines[i - 1].TrimStart().StartsWith("///", StringComparison.Ordinal))
+            i--;
+        return string.Join("\n", lines[i..(end + 1)].Select(l => l.Trim()));
+    }
+
+    private static string QualifiedName(Found declaration) =>
+        declaration.Container == null ? declaration.Name : $"{QualifiedName(declaration.Container)}.{declaration.Name}";
+
+    private static string StableId(string filePath, string name, int line) =>
+        Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes($"zig:{filePath}:{name}:{line}")))[..32].ToLowerInvariant();
+
+    private sealed class Found
+    {
+        /// <summary>
+        /// The symbol kind, or returned (the container a generic type function returns) and block (a test or
+        /// comptime block) for extents that only hold other declarations
+        /// </summary>
+        public string Kind { get; set; } = string.Empty;
+
+        /// <summary>
+        /// const or var for bindings, the container keyword for a returned container; empty otherwise
+        /// </summary>
+        public string Keyword { get; init; } = string.Empty;
+
+        public string Name { get; init; } = string.Empty;
+        public int Line { get; init; }
+        public int Column { get; init; }
+        public int EndLine { get; init; }
+        public string Signature { get; init; } = string.Empty;
+        public string Visibility { get; init; } = "public";
+        public Found? Container { get; set; }
+    }
+}
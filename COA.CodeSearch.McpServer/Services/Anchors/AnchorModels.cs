@@ -0,0 +1,73 @@
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Anchors;
+
+/// <summary>
+/// A durable reference to a symbol: the file it was seen in, its dotted path through the containing symbols
+/// and a fingerprint of its body. Written as <c>anchor:src/Users/UserService.cs#UserService.FindAsync~3f9a1c2e0b7d4a65</c>.
+/// Unlike a line number it can be re-resolved after the file shifts, the symbol moves or is renamed.
+/// </summary>
+public sealed record SymbolAnchor(string FilePath, string SymbolPath, string Fingerprint)
+{
+    public const string Prefix = "anchor:";
+
+    public override string ToString() => $"{Prefix}{FilePath}#{SymbolPath}~{Fingerprint}";
+
+    /// <summary>
+    /// Parses the anchor form; false for anything else, including plain symbol names and positions
+    /// </summary>
+    public static bool TryParse(string? value, out SymbolAnchor anchor)
+    {
+        anchor = null!;
+        var text = value?.Trim();
+        if (text == null || !text.StartsWith(Prefix, StringComparison.Ordinal))
+            return false;
+
+        var fingerprintAt = text.LastIndexOf('~');
+        var symbolAt = fingerprintAt < 0 ? -1 : text.LastIndexOf('#', fingerprintAt);
+        if (symbolAt <= Prefix.Length || fingerprintAt <= symbolAt + 1 || fingerprintAt == text.Length - 1)
+            return false;
+
+        anchor = new SymbolAnchor(
+            text[Prefix.Length..symbolAt],
+            text[(symbolAt + 1)..fingerprintAt],
+            text[(fingerprintAt + 1)..]);
+        return true;
+    }
+}
+
+/// <summary>
+/// How an anchor was re-resolved
+/// </summary>
+public static class AnchorStatus
+{
+    /// <summary>Same file, same symbol path, same body</summary>
+    public const string Exact = "exact";
+
+    /// <summary>Same file and symbol path, the body was edited</summary>
+    public const string Changed = "changed";
+
+    /// <summary>Same symbol path found in another file</summary>
+    public const string Moved = "moved";
+
+    /// <summary>Same body found under another name</summary>
+    public const string Renamed = "renamed";
+
+    /// <summary>Nothing matching any more</summary>
+    public const string Missing = "missing";
+}
+
+public class AnchorResolution
+{
+    public string Status { get; set; } = AnchorStatus.Missing;
+
+    /// <summary>
+    /// The symbol the anchor points at now; null when missing
+    /// </summary>
+    public JulieSymbol? Symbol { get; set; }
+
+    /// <summary>
+    /// A fresh anchor for the symbol as it is now, to replace the stored one
+    /// </summary>
+    public string? CurrentAnchor { get; set; }
+}
@@ -0,0 +1,20 @@
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Anchors;
+
+/// <summary>
+/// Creates symbol anchors and re-resolves them against the current symbol database, so locations saved in
+/// baselines, notes or earlier results keep pointing at the same symbol after edits
+/// </summary>
+public interface ISymbolAnchorService
+{
+    /// <summary>
+    /// Anchor for a symbol from the symbol database; null when its file content is not available
+    /// </summary>
+    Task<SymbolAnchor?> CreateAnchorAsync(string workspacePath, JulieSymbol symbol, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Finds the symbol an anchor refers to now: in place, edited, moved to another file or renamed
+    /// </summary>
+    Task<AnchorResolution> ResolveAsync(string workspacePath, SymbolAnchor anchor, CancellationToken cancellationToken = default);
+}
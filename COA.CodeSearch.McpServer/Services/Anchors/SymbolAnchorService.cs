@@ -0,0 +1,193 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Anchors;
+
+/// <summary>
+/// Anchors symbols by dotted path and a fingerprint of their body. The fingerprint ignores whitespace and the
+/// symbol's own name, so reformatting leaves it unchanged and a renamed symbol can still be recognised.
+/// Everything is read from the symbol database, which the file watcher keeps current.
+/// </summary>
+public class SymbolAnchorService : ISymbolAnchorService
+{
+    private const int MaxMoveCandidates = 50;
+    private const int MaxParentDepth = 32;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<SymbolAnchorService> _logger;
+
+    public SymbolAnchorService(ISQLiteSymbolService sqliteService, ILogger<SymbolAnchorService> logger)
+    {
+        _sqliteService = sqliteService ?? throw new ArgumentNullException(nameof(sqliteService));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+    }
+
+    public async Task<SymbolAnchor?> CreateAnchorAsync(string workspacePath, JulieSymbol symbol, CancellationToken cancellationToken = default)
+    {
+        var file = await LoadFileAsync(workspacePath, symbol.FilePath, cancellationToken);
+        if (file == null)
+            return null;
+
+        var target = file.Symbols.FirstOrDefault(s => s.Id == symbol.Id) ?? symbol;
+        return file.AnchorFor(target);
+    }
+
+    public async Task<AnchorResolution> ResolveAsync(string workspacePath, SymbolAnchor anchor, CancellationToken cancellationToken = default)
+    {
+        // In place: same file and path, edited or not, or the same body under a new name
+        var file = await LoadFileAsync(workspacePath, anchor.FilePath, cancellationToken);
+        if (file != null)
+        {
+            var samePath = file.Symbols.Where(s => file.PathOf(s) == anchor.SymbolPath).ToList();
+            var exact = samePath.FirstOrDefault(s => file.FingerprintOf(s) == anchor.Fingerprint);
+            if (exact != null)
+                return Resolved(AnchorStatus.Exact, exact, file);
+            if (samePath.Count > 0)
+                return Resolved(AnchorStatus.Changed, samePath[0], file);
+
+            var renamed = file.Symbols.FirstOrDefault(s => file.FingerprintOf(s) == anchor.Fingerprint);
+            if (renamed != null)
+                return Resolved(AnchorStatus.Renamed, renamed, file);
+        }
+
+        // Moved: the same path in another file, preferring an unchanged body
+        var leafName = anchor.SymbolPath[(anchor.SymbolPath.LastIndexOf('.') + 1)..];
+        var candidates = await _sqliteService.GetSymbolsByNameAsync(workspacePath, leafName, caseSensitive: true, cancellationToken);
+        var moved = new List<(JulieSymbol Symbol, AnchorFile File)>();
+        foreach (var filePath in candidates
+                     .Select(c => c.FilePath)
+                     .Where(p => !string.Equals(p, anchor.FilePath, StringComparison.OrdinalIgnoreCase))
+                     .Distinct(StringComparer.OrdinalIgnoreCase)
+                     .Take(MaxMoveCandidates))
+        {
+            var other = await LoadFileAsync(workspacePath, filePath, cancellationToken);
+            if (other == null)
+                continue;
+
+            moved.AddRange(other.Symbols
+                .Where(s => other.PathOf(s) == anchor.SymbolPath)
+                .Select(s => (s, other)));
+        }
+
+        var match = moved.FirstOrDefault(m => m.File.FingerprintOf(m.Symbol) == anchor.Fingerprint);
+        if (match.Symbol == null && moved.Count == 1)
+            match = moved[0];
+        if (match.Symbol != null)
+            return Resolved(AnchorStatus.Moved, match.Symbol, match.File);
+
+        _logger.LogDebug("Anchor {Anchor} no longer resolves ({Candidates} candidates by path)", anchor, moved.Count);
+        return new AnchorResolution { Status = AnchorStatus.Missing };
+    }
+
+    private static AnchorResolution Resolved(string status, JulieSymbol symbol, AnchorFile file) => new()
+    {
+        Status = status,
+        Symbol = symbol,
+        CurrentAnchor = file.AnchorFor(symbol).ToString()
+    };
+
+    private async Task<AnchorFile?> LoadFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken)
+    {
+        var record = await _sqliteService.GetFileByPathAsync(workspacePath, filePath, cancellationToken);
+        if (record?.Content == null)
+            return null;
+
+        var symbols = await _sqliteService.GetSymbolsForFileAsync(workspacePath, filePath, cancellationToken);
+        return new AnchorFile(filePath, record.Content, symbols);
+    }
+
+    /// <summary>
+    /// Body text of a symbol with whitespace removed and its own name masked
+    /// </summary>
+    private static string NormalizeBody(string body, string name)
+    {
+        var masked = string.IsNullOrEmpty(name)
+            ? body
+            : Regex.Replace(body,
+                $@"(?<!{UnicodeIdentifiers.IdentifierCharClass}){Regex.Escape(name)}(?!{UnicodeIdentifiers.IdentifierCharClass})",
+                "\u0000");
+
+        var builder = new StringBuilder(masked.Length);
+        foreach (var c in masked)
+        {
+            if (!char.IsWhiteSpace(c))
+                builder.Append(c);
+        }
+        return builder.ToString();
+    }
+
+    private static string ComputeFingerprint(string normalizedBody)
+    {
+        var hash = SHA256.HashData(Encoding.UTF8.GetBytes(normalizedBody));
+        return Convert.ToHexString(hash, 0, 8).ToLowerInvariant();
+    }
+
+    /// <summary>
+    /// One file's content and symbols, with paths and fingerprints computed on demand
+    /// </summary>
+    private sealed class AnchorFile
+    {
+        private readonly string _filePath;
+        private readonly string _content;
+        private readonly Dictionary<string, JulieSymbol> _byId;
+        private byte[]? _utf8;
+        private string[]? _lines;
+
+        public AnchorFile(string filePath, string content, List<JulieSymbol> symbols)
+        {
+            _filePath = filePath;
+            _content = content;
+            Symbols = symbols;
+            _byId = symbols.Where(s => !string.IsNullOrEmpty(s.Id)).GroupBy(s => s.Id).ToDictionary(g => g.Key, g => g.First());
+        }
+
+        public List<JulieSymbol> Symbols { get; }
+
+        public SymbolAnchor AnchorFor(JulieSymbol symbol) => new(_filePath, PathOf(symbol), FingerprintOf(symbol));
+
+        public string PathOf(JulieSymbol symbol)
+        {
+            var names = new List<string> { symbol.Name };
+            var parentId = symbol.ParentId;
+            for (var depth = 0; parentId != null && depth < MaxParentDepth && _byId.TryGetValue(parentId, out var parent); depth++)
+            {
+                names.Add(parent.Name);
+                parentId = parent.ParentId;
+            }
+
+            // Go methods sit at file level; the receiver type is what tells same-named methods apart
+            if (symbol.ParentId == null && symbol.FilePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase)
+                && (GoMethodSets.ReceiverOf(symbol.Signature) ?? GoMethodSets.ReceiverOf(BodyOf(symbol))) is { } receiver)
+            {
+                names.Add(receiver);
+            }
+
+            names.Reverse();
+            return string.Join('.', names);
+        }
+
+        public string FingerprintOf(JulieSymbol symbol) => ComputeFingerprint(NormalizeBody(BodyOf(symbol), symbol.Name));
+
+        private string BodyOf(JulieSymbol symbol)
+        {
+            if (symbol.StartByte is { } start && symbol.EndByte is { } end)
+            {
+                _utf8 ??= Encoding.UTF8.GetBytes(_content);
+                start = Math.Clamp(start, 0, _utf8.Length);
+                end = Math.Clamp(end, start, _utf8.Length);
+                return Encoding.UTF8.GetString(_utf8, start, end - start);
+            }
+
+            // No byte offsets from the extractor: whole lines are close enough for a fingerprint
+            _lines ??= _content.Split('\n');
+            var first = Math.Clamp(symbol.StartLine - 1, 0, _lines.Length);
+            var last = Math.Clamp(symbol.EndLine, first, _lines.Length);
+            return string.Join('\n', _lines[first..last]);
+        }
+    }
+}
@@ -0,0 +1,345 @@
+using System.Security.Cryptography;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Assets;
+
+/// <summary>
+/// Keeps each workspace's asset inventory in assets.json beside its index. Assets are found by extension, or by
+/// lying under a fixtures directory; references are the lines of the indexed files naming them.
+/// </summary>
+public class AssetInventoryService : IAssetInventoryService
+{
+    private const string InventoryFileName = "assets.json";
+
+    private static readonly Dictionary<string, string[]> KindExtensions = new()
+    {
+        ["image"] = new[] { ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico", ".svg", ".webp", ".avif", ".tif", ".tiff", ".psd" },
+        ["font"] = new[] { ".woff", ".woff2", ".ttf", ".otf", ".eot" },
+        ["media"] = new[] { ".mp3", ".mp4", ".wav", ".ogg", ".flac", ".webm", ".mov", ".avi", ".mkv", ".wmv", ".flv" },
+        ["document"] = new[] { ".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx" },
+        ["archive"] = new[] { ".zip", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".7z", ".rar" },
+        ["binary"] = new[] { ".bin", ".dat", ".wasm", ".db", ".sqlite", ".parquet", ".avro", ".pb", ".onnx" }
+    };
+
+    private static readonly HashSet<string> FixtureDirectories = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "fixtures", "__fixtures__", "testdata", "test-data", "test_data", "golden", "goldens", "__snapshots__"
+    };
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly IConfiguration _configuration;
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILogger<AssetInventoryService> _logger;
+    private readonly SemaphoreSlim _refresh = new(1, 1);
+
+    public AssetInventoryService(
+        IPathResolutionService pathResolution,
+        IConfiguration configuration,
+        ILogger<AssetInventoryService> logger,
+        ISQLiteSymbolService? sqliteService = null)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _configuration = configuration ?? throw new ArgumentNullException(nameof(configuration));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _sqliteService = sqliteService;
+    }
+
+    public async Task<AssetInventory> RefreshAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        await _refresh.WaitAsync(cancellationToken);
+        try
+        {
+            // Unchanged files keep their hash
+            var previous = (Load(workspacePath)?.Assets ?? new List<AssetRecord>())
+                .ToDictionary(a => a.Path, StringComparer.Ordinal);
+
+            var assets = new List<AssetRecord>();
+            foreach (var file in EnumerateFiles(workspacePath))
+            {
+                var relativePath = Path.GetRelativePath(workspacePath, file).Replace('\\', '/');
+                var kind = Classify(relativePath);
+                if (kind == null)
+                    continue;
+
+                try
+                {
+                    var info = new FileInfo(file);
+                    var asset = new AssetRecord
+                    {
+                        Path = relativePath,
+                        Kind = kind,
+                        SizeBytes = info.Length,
+                        LastModified = info.LastWriteTimeUtc
+                    };
+                    asset.Hash = previous.TryGetValue(relativePath, out var known) && known.SizeBytes == asset.SizeBytes && known.LastModified == asset.LastModified
+                        ? known.Hash
+                        : await HashAsync(file, cancellationToken);
+                    assets.Add(asset);
+                }
+                catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+                {
+                    _logger.LogDebug(ex, "Could not read asset {Path}", file);
+                }
+            }
+
+            var inventory = new AssetInventory
+            {
+                WorkspacePath = workspacePath,
+                BuiltAt = DateTime.UtcNow,
+                Assets = assets.OrderBy(a => a.Path, StringComparer.Ordinal).ToList()
+            };
+
+            if (_sqliteService != null && _sqliteService.DatabaseExists(workspacePath))
+            {
+                var files = await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken);
+                ResolveReferences(inventory.Assets, files
+                    .Where(f => !string.IsNullOrEmpty(f.Content))
+                    .Select(f => (Path.IsPathRooted(f.Path) ? Path.GetRelativePath(workspacePath, f.Path).Replace('\\', '/') : f.Path.Replace('\\', '/'), f.Content!)));
+                inventory.ReferencesScanned = true;
+            }
+
+            Save(workspacePath, inventory);
+            _logger.LogInformation("Inventoried {Count} assets in {WorkspacePath}", inventory.Assets.Count, workspacePath);
+            return inventory;
+        }
+        finally
+        {
+            _refresh.Release();
+        }
+    }
+
+    public async Task<AssetInventory> GetAsync(string workspacePath, CancellationToken cancellationToken = default) =>
+        Load(workspacePath) ?? await RefreshAsync(workspacePath, cancellationToken);
+
+    /// <summary>
+    /// The kind of asset a workspace-relative path is, or null for code and other indexed text
+    /// </summary>
+    public static string? Classify(string relativePath)
+    {
+        var extension = Path.GetExtension(relativePath);
+        foreach (var (kind, extensions) in KindExtensions)
+        {
+            if (extensions.Contains(extension, StringComparer.OrdinalIgnoreCase))
+                return kind;
+        }
+
+        var directories = relativePath.Split('/')[..^1];
+        return directories.Any(FixtureDirectories.Contains) ? "fixture" : null;
+    }
+
+    /// <summary>
+    /// Fills in each asset's references from the lines of the given files naming its file name. A path written
+    /// with directories that resolves to one of the assets of that name - relative to the referencing file, or
+    /// as a suffix of the asset's path, which covers web roots like public/ - refers to that asset only and is
+    /// exact; a bare file name refers to every asset of that name.
+    /// </summary>
+    public static void ResolveReferences(IReadOnlyList<AssetRecord> assets, IEnumerable<(string Path, string Content)> files)
+    {
+        var byName = assets
+            .GroupBy(a => Path.GetFileName(a.Path), StringComparer.OrdinalIgnoreCase)
+            .ToDictionary(g => g.Key, g => g.ToList(), StringComparer.OrdinalIgnoreCase);
+        var extensions = assets
+            .Select(a => Path.GetExtension(a.Path).TrimStart('.'))
+            .Where(e => e.Length > 0)
+            .Distinct(StringComparer.OrdinalIgnoreCase)
+            .ToList();
+        if (extensions.Count == 0)
+            return;
+
+        var reference = new Regex(
+            $@"(?<directories>(?:[\w@~.\-]+[/\\])*)(?<name>[\w@.\-]+\.(?:{string.Join("|", extensions.Select(Regex.Escape))}))(?![\w])",
+            RegexOptions.IgnoreCase | RegexOptions.Compiled);
+
+        // An asset's directory, and the same without its first segment (public/, static/, src/)
+        var directories = assets
+            .Select(a => a.Path.Contains('/') ? a.Path[..a.Path.LastIndexOf('/')] : string.Empty)
+            .Where(d => d.Length > 0)
+            .Distinct(StringComparer.Ordinal)
+            .ToDictionary(d => d, d => new Regex($@"(?<![\w.\-])(?:{string.Join("|", DirectoryForms(d).Select(Regex.Escape))})/", RegexOptions.IgnoreCase));
+        var referencedDirectories = new HashSet<string>(StringComparer.Ordinal);
+
+        foreach (var (filePath, content) in files)
+        {
+            var lines = content.Split('\n');
+            for (var i = 0; i < lines.Length; i++)
+            {
+                var line = lines[i];
+                foreach (Match match in reference.Matches(line))
+                {
+                    if (!byName.TryGetValue(match.Groups["name"].Value, out var candidates))
+                        continue;
+
+                    var written = (match.Groups["directories"].Value + match.Groups["name"].Value).Replace('\\', '/');
+                    var exact = written.Contains('/')
+                        ? candidates.Where(a => Resolves(written, filePath, a.Path)).ToList()
+                        : new List<AssetRecord>();
+                    foreach (var asset in exact.Count > 0 ? exact : candidates)
+                    {
+                        if (asset.Path == filePath)
+                            continue;
+
+                        asset.References.Add(new AssetReference
+                        {
+                            FilePath = filePath,
+                            Line = i + 1,
+                            Text = line.Trim().Length > 200 ? line.Trim()[..197] + "..." : line.Trim(),
+                            Exact = exact.Count > 0
+                        });
+                    }
+                }
+
+                foreach (var (directory, pattern) in directories)
+                {
+                    if (!referencedDirectories.Contains(directory) && pattern.IsMatch(line))
+                        referencedDirectories.Add(directory);
+                }
+            }
+        }
+
+        foreach (var asset in assets)
+        {
+            var directory = asset.Path.Contains('/') ? asset.Path[..asset.Path.LastIndexOf('/')] : string.Empty;
+            asset.DirectoryReferenced = referencedDirectories.Contains(directory);
+        }
+    }
+
+    /// <summary>
+    /// Groups of two or more assets with the same content, largest waste first
+    /// </summary>
+    public static List<DuplicateAssetGroup> FindDuplicates(IEnumerable<AssetRecord> assets) =>
+        assets
+            .Where(a => a.SizeBytes > 0)
+            .GroupBy(a => a.Hash, StringComparer.Ordinal)
+            .Where(g => g.Count() > 1)
+            .Select(g => new DuplicateAssetGroup
+            {
+                Hash = g.Key,
+                Kind = g.First().Kind,
+                SizeBytes = g.First().SizeBytes,
+                Copies = g
+                    .OrderByDescending(a => a.References.Count)
+                    .ThenBy(a => a.Path, StringComparer.Ordinal)
+                    .Select(a => new DuplicateAssetCopy { Path = a.Path, References = a.References.Count })
+                    .ToList()
+            })
+            .OrderByDescending(g => g.WastedBytes)
+            .ThenBy(g => g.Copies[0].Path, StringComparer.Ordinal)
+            .ToList();
+
+    private static IEnumerable<string> DirectoryForms(string directory)
+    {
+        yield return directory;
+        var slash = directory.IndexOf('/');
+        if (slash > 0)
+            yield return directory[(slash + 1)..];
+    }
+
+    private static bool Resolves(string written, string referencingFile, string assetPath)
+    {
+        var fromFile = Normalize(Path.GetDirectoryName(referencingFile)?.Replace('\\', '/') + "/" + written);
+        if (string.Equals(fromFile, assetPath, StringComparison.OrdinalIgnoreCase))
+            return true;
+
+        var rooted = Normalize(written.TrimStart('~', '@').TrimStart('/'));
+        return rooted != null
+               && (string.Equals(rooted, assetPath, StringComparison.OrdinalIgnoreCase)
+                   || assetPath.EndsWith("/" + rooted, StringComparison.OrdinalIgnoreCase));
+    }
+
+    /// <summary>
+    /// A relative path with . and .. segments resolved, or null when it climbs out of the workspace
+    /// </summary>
+    private static string? Normalize(string path)
+    {
+        var segments = new List<string>();
+        foreach (var segment in path.Split('/', StringSplitOptions.RemoveEmptyEntries))
+        {
+            if (segment == ".")
+                continue;
+            if (segment == "..")
+            {
+                if (segments.Count == 0)
+                    return null;
+                segments.RemoveAt(segments.Count - 1);
+                continue;
+            }
+            segments.Add(segment);
+        }
+        return string.Join("/", segments);
+    }
+
+    private static async Task<string> HashAsync(string filePath, CancellationToken cancellationToken)
+    {
+        await using var stream = File.OpenRead(filePath);
+        return Convert.ToHexString(await SHA256.HashDataAsync(stream, cancellationToken)).ToLowerInvariant();
+    }
+
+    /// <summary>
+    /// Files under the workspace, skipping the same directories as indexing
+    /// </summary>
+    private IEnumerable<string> EnumerateFiles(string workspacePath)
+    {
+        var excluded = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>() ?? PathConstants.DefaultExcludedDirectories,
+            StringComparer.OrdinalIgnoreCase);
+
+        var pending = new Stack<string>();
+        pending.Push(workspacePath);
+        while (pending.Count > 0)
+        {
+            var directory = pending.Pop();
+            string[] entries;
+            string[] subdirectories;
+            try
+            {
+                entries = Directory.GetFiles(directory);
+                subdirectories = Directory.GetDirectories(directory);
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                continue;
+            }
+
+            foreach (var file in entries)
+                yield return file;
+
+            foreach (var subdirectory in subdirectories.Where(d => !excluded.Contains(Path.GetFileName(d))))
+                pending.Push(subdirectory);
+        }
+    }
+
+    private string GetInventoryPath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), InventoryFileName);
+
+    private AssetInventory? Load(string workspacePath)
+    {
+        var path = GetInventoryPath(workspacePath);
+        try
+        {
+            return File.Exists(path) ? JsonSerializer.Deserialize<AssetInventory>(File.ReadAllText(path)) : null;
+        }
+        catch (Exception ex) when (ex is IOException or JsonException)
+        {
+            _logger.LogWarning(ex, "Could not read asset inventory {Path} - rebuilding it", path);
+            return null;
+        }
+    }
+
+    private void Save(string workspacePath, AssetInventory inventory)
+    {
+        var path = GetInventoryPath(workspacePath);
+        try
+        {
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            File.WriteAllText(path, JsonSerializer.Serialize(inventory));
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            _logger.LogWarning(ex, "Could not save asset inventory {Path}", path);
+        }
+    }
+}
@@ -0,0 +1,106 @@
+namespace COA.CodeSearch.McpServer.Services.Assets;
+
+/// <summary>
+/// A non-code file of the workspace - image, font, media, document, archive, binary or test fixture - known by
+/// its metadata rather than its content
+/// </summary>
+public class AssetRecord
+{
+    /// <summary>
+    /// Workspace-relative path with forward slashes
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// image, font, media, document, archive, binary or fixture
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public long SizeBytes { get; set; }
+
+    /// <summary>
+    /// SHA-256 of the content, lowercase hex
+    /// </summary>
+    public string Hash { get; set; } = string.Empty;
+
+    public DateTime LastModified { get; set; }
+
+    /// <summary>
+    /// Lines of indexed files naming the asset
+    /// </summary>
+    public List<AssetReference> References { get; set; } = new();
+
+    /// <summary>
+    /// Code names the asset's directory, so it may be loaded by a built path or a directory listing
+    /// </summary>
+    public bool DirectoryReferenced { get; set; }
+}
+
+/// <summary>
+/// A line naming an asset
+/// </summary>
+public class AssetReference
+{
+    /// <summary>
+    /// Workspace-relative path of the referencing file
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+
+    public string Text { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The path written resolves to this asset; false when only the file name matched
+    /// </summary>
+    public bool Exact { get; set; }
+}
+
+/// <summary>
+/// The assets of a workspace as of the last refresh, saved beside its index
+/// </summary>
+public class AssetInventory
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public DateTime BuiltAt { get; set; }
+
+    /// <summary>
+    /// References were looked up in the indexed files; false when the workspace has no symbol database yet
+    /// </summary>
+    public bool ReferencesScanned { get; set; }
+
+    public List<AssetRecord> Assets { get; set; } = new();
+}
+
+/// <summary>
+/// Assets with identical content
+/// </summary>
+public class DuplicateAssetGroup
+{
+    public string Hash { get; set; } = string.Empty;
+
+    public string Kind { get; set; } = string.Empty;
+
+    public long SizeBytes { get; set; }
+
+    /// <summary>
+    /// The copies, most referenced first - the first is the one to keep
+    /// </summary>
+    public List<DuplicateAssetCopy> Copies { get; set; } = new();
+
+    /// <summary>
+    /// Bytes the copies beyond the first take up
+    /// </summary>
+    public long WastedBytes => SizeBytes * Math.Max(0, Copies.Count - 1);
+}
+
+/// <summary>
+/// One copy of a duplicated asset
+/// </summary>
+public class DuplicateAssetCopy
+{
+    public string Path { get; set; } = string.Empty;
+
+    public int References { get; set; }
+}
@@ -0,0 +1,19 @@
+namespace COA.CodeSearch.McpServer.Services.Assets;
+
+/// <summary>
+/// Inventory of a workspace's non-code files - size, content hash and the lines of code naming them - kept
+/// beside its index so unreferenced and duplicated assets can be found without reading them again
+/// </summary>
+public interface IAssetInventoryService
+{
+    /// <summary>
+    /// Rescans the workspace's assets, rehashing only files whose size or timestamp changed, looks up their
+    /// references in the indexed files and saves the inventory
+    /// </summary>
+    Task<AssetInventory> RefreshAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// The saved inventory, refreshed first when there is none
+    /// </summary>
+    Task<AssetInventory> GetAsync(string workspacePath, CancellationToken cancellationToken = default);
+}
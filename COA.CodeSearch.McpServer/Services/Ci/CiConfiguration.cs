@@ -0,0 +1,86 @@
+namespace COA.CodeSearch.McpServer.Services.Ci;
+
+/// <summary>
+/// Checks run by <c>codesearch ci</c>, loaded from codesearch-ci.json in the checkout
+/// </summary>
+public class CiConfiguration
+{
+    public const string DefaultFileName = "codesearch-ci.json";
+
+    /// <summary>
+    /// Text searches whose matches count as violations beyond a threshold
+    /// </summary>
+    public List<CiQueryCheck> Queries { get; set; } = new();
+
+    public CiAnalyzerCheck Analyzers { get; set; } = new();
+
+    public CiSecretCheck Secrets { get; set; } = new();
+}
+
+/// <summary>
+/// A search that should (almost) never match, e.g. a banned API
+/// </summary>
+public class CiQueryCheck
+{
+    /// <summary>
+    /// Rule id used in reports
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    public string Query { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Query type: literal (default), code, wildcard, regex, phrase, fuzzy
+    /// </summary>
+    public string SearchType { get; set; } = "literal";
+
+    /// <summary>
+    /// Only count matches in these extensions (empty = all)
+    /// </summary>
+    public List<string> Extensions { get; set; } = new();
+
+    /// <summary>
+    /// Matching files allowed before the check fails
+    /// </summary>
+    public int MaxMatches { get; set; }
+
+    /// <summary>
+    /// SARIF level: error, warning or note
+    /// </summary>
+    public string Severity { get; set; } = "error";
+
+    public string? Message { get; set; }
+}
+
+/// <summary>
+/// find_patterns detectors run over every matching file
+/// </summary>
+public class CiAnalyzerCheck
+{
+    public bool Enabled { get; set; } = true;
+
+    public List<string> Extensions { get; set; } = new() { ".cs" };
+
+    public bool DetectAsyncPatterns { get; set; } = true;
+    public bool DetectEmptyCatchBlocks { get; set; } = true;
+    public bool DetectUnusedUsings { get; set; } = false;
+    public bool DetectMagicNumbers { get; set; } = false;
+    public bool DetectLargeMethods { get; set; } = true;
+    public bool DetectDeadCode { get; set; } = false;
+    public List<string> CustomPatterns { get; set; } = new();
+
+    /// <summary>
+    /// Findings allowed per severity (Error, Warning, Info) before the check fails; missing severities are unlimited
+    /// </summary>
+    public Dictionary<string, int> MaxFindings { get; set; } = new(StringComparer.OrdinalIgnoreCase) { ["Error"] = 0 };
+}
+
+/// <summary>
+/// Credential scan over every indexed file
+/// </summary>
+public class CiSecretCheck
+{
+    public bool Enabled { get; set; } = true;
+
+    public int MaxFindings { get; set; }
+}
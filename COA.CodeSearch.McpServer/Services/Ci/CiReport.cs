@@ -0,0 +1,62 @@
+namespace COA.CodeSearch.McpServer.Services.Ci;
+
+/// <summary>
+/// Exit codes returned by <c>codesearch ci</c>
+/// </summary>
+public static class CiExitCodes
+{
+    public const int Passed = 0;
+    public const int ThresholdViolated = 1;
+    public const int ConfigurationError = 2;
+    public const int IndexingFailed = 3;
+}
+
+/// <summary>
+/// Outcome of a CI run, written as the JSON report
+/// </summary>
+public class CiReport
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+    public DateTime StartedAt { get; set; }
+    public TimeSpan Duration { get; set; }
+    public int IndexedFiles { get; set; }
+    public List<CiCheckResult> Checks { get; set; } = new();
+    public List<CiFinding> Findings { get; set; } = new();
+    public bool Passed => Checks.All(c => c.Passed);
+    public int ExitCode { get; set; }
+}
+
+/// <summary>
+/// One configured check and whether it stayed within its threshold
+/// </summary>
+public class CiCheckResult
+{
+    public string Name { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+    public int Count { get; set; }
+    public int Threshold { get; set; }
+    public bool Passed { get; set; }
+    public string? Detail { get; set; }
+}
+
+/// <summary>
+/// A single reported location
+/// </summary>
+public class CiFinding
+{
+    public string RuleId { get; set; } = string.Empty;
+
+    /// <summary>
+    /// SARIF level: error, warning or note
+    /// </summary>
+    public string Level { get; set; } = "warning";
+
+    public string Message { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Path relative to the workspace, with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    public int Line { get; set; } = 1;
+}
@@ -0,0 +1,399 @@
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Tools;
+using COA.CodeSearch.McpServer.Tools.Parameters;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Ci;
+
+/// <summary>
+/// Command-line options for <c>codesearch ci</c>
+/// </summary>
+public class CiOptions
+{
+    public string WorkspacePath { get; set; } = Environment.CurrentDirectory;
+    public string? ConfigPath { get; set; }
+    public string? SarifPath { get; set; }
+    public string? JsonPath { get; set; }
+
+    /// <summary>
+    /// Reuse an existing index instead of indexing the checkout first
+    /// </summary>
+    public bool SkipIndexing { get; set; }
+
+    public const string Usage =
+        "Usage: codesearch ci [--workspace <path>] [--config <codesearch-ci.json>] [--sarif <file>] [--json <file>] [--no-index]\n" +
+        "Exit codes: 0 passed, 1 threshold violated, 2 configuration error, 3 indexing failed";
+
+    /// <summary>
+    /// Parses arguments after "ci". Returns null and an error message for invalid input.
+    /// </summary>
+    public static CiOptions? Parse(IReadOnlyList<string> args, out string? error)
+    {
+        var options = new CiOptions();
+        error = null;
+
+        for (var i = 0; i < args.Count; i++)
+        {
+            string? NextValue()
+            {
+                if (i + 1 < args.Count && !args[i + 1].StartsWith("--"))
+                    return args[++i];
+                return null;
+            }
+
+            switch (args[i])
+            {
+                case "--workspace":
+                    options.WorkspacePath = NextValue() ?? string.Empty;
+                    break;
+                case "--config":
+                    options.ConfigPath = NextValue();
+                    break;
+                case "--sarif":
+                    options.SarifPath = NextValue();
+                    break;
+                case "--json":
+                    options.JsonPath = NextValue();
+                    break;
+                case "--no-index":
+                    options.SkipIndexing = true;
+                    break;
+                default:
+                    error = $"Unknown argument '{args[i]}'";
+                    return null;
+            }
+        }
+
+        if (string.IsNullOrWhiteSpace(options.WorkspacePath) || !Directory.Exists(options.WorkspacePath))
+        {
+            error = $"Workspace not found: '{options.WorkspacePath}'";
+            return null;
+        }
+
+        options.WorkspacePath = Path.GetFullPath(options.WorkspacePath);
+        options.ConfigPath ??= Path.Combine(options.WorkspacePath, CiConfiguration.DefaultFileName);
+        return options;
+    }
+}
+
+/// <summary>
+/// Non-interactive run of the search engine as a PR gate: index the checkout, run the configured
+/// queries, analyzers and secret scan, write reports, and return an exit code
+/// </summary>
+public class CiRunner
+{
+    private static readonly JsonSerializerOptions ConfigOptions = new()
+    {
+        PropertyNameCaseInsensitive = true,
+        ReadCommentHandling = JsonCommentHandling.Skip,
+        AllowTrailingCommas = true
+    };
+
+    private static readonly JsonSerializerOptions ReportOptions = new()
+    {
+        WriteIndented = true,
+        PropertyNamingPolicy = JsonNamingPolicy.CamelCase
+    };
+
+    private readonly IServiceProvider _serviceProvider;
+    private readonly IFileIndexingService _fileIndexingService;
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly QueryPreprocessor _queryPreprocessor;
+    private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly IConfiguration _configuration;
+    private readonly ILogger<CiRunner> _logger;
+
+    public CiRunner(
+        IServiceProvider serviceProvider,
+        IFileIndexingService fileIndexingService,
+        ILuceneIndexService luceneIndexService,
+        QueryPreprocessor queryPreprocessor,
+        CodeAnalyzer codeAnalyzer,
+        IConfiguration configuration,
+        ILogger<CiRunner> logger)
+    {
+        _serviceProvider = serviceProvider ?? throw new ArgumentNullException(nameof(serviceProvider));
+        _fileIndexingService = fileIndexingService ?? throw new ArgumentNullException(nameof(fileIndexingService));
+        _luceneIndexService = luceneIndexService ?? throw new ArgumentNullException(nameof(luceneIndexService));
+        _queryPreprocessor = queryPreprocessor ?? throw new ArgumentNullException(nameof(queryPreprocessor));
+        _codeAnalyzer = codeAnalyzer ?? throw new ArgumentNullException(nameof(codeAnalyzer));
+        _configuration = configuration ?? throw new ArgumentNullException(nameof(configuration));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+    }
+
+    /// <summary>
+    /// Loads the CI configuration; a missing file means the default checks (analyzers and secret scan)
+    /// </summary>
+    public static CiConfiguration LoadConfiguration(string path)
+    {
+        if (!File.Exists(path))
+            return new CiConfiguration();
+
+        return JsonSerializer.Deserialize<CiConfiguration>(File.ReadAllText(path), ConfigOptions) ?? new CiConfiguration();
+    }
+
+    public async Task<CiReport> RunAsync(CiOptions options, CiConfiguration ciConfig, string toolVersion, CancellationToken cancellationToken)
+    {
+        var report = new CiReport { WorkspacePath = options.WorkspacePath, StartedAt = DateTime.UtcNow };
+
+        if (!options.SkipIndexing)
+        {
+            var indexing = await _fileIndexingService.IndexWorkspaceAsync(options.WorkspacePath, cancellationToken);
+            if (!indexing.Success)
+            {
+                _logger.LogError("CI indexing failed: {Error}", indexing.ErrorMessage);
+                report.Checks.Add(new CiCheckResult { Name = "index", Kind = "index", Passed = false, Detail = indexing.ErrorMessage });
+                report.ExitCode = CiExitCodes.IndexingFailed;
+                return await FinishAsync(report, options, toolVersion, cancellationToken);
+            }
+            report.IndexedFiles = indexing.IndexedFileCount;
+        }
+        else
+        {
+            await _luceneIndexService.InitializeIndexAsync(options.WorkspacePath, cancellationToken);
+            report.IndexedFiles = await _luceneIndexService.GetDocumentCountAsync(options.WorkspacePath, cancellationToken);
+        }
+
+        foreach (var check in ciConfig.Queries)
+        {
+            await RunQueryCheckAsync(options.WorkspacePath, check, report, cancellationToken);
+        }
+
+        var files = EnumerateFiles(options.WorkspacePath).ToList();
+
+        if (ciConfig.Analyzers.Enabled)
+        {
+            await RunAnalyzersAsync(options.WorkspacePath, files, ciConfig.Analyzers, report, cancellationToken);
+        }
+
+        if (ciConfig.Secrets.Enabled)
+        {
+            await RunSecretScanAsync(options.WorkspacePath, files, ciConfig.Secrets, report, cancellationToken);
+        }
+
+        report.ExitCode = report.Passed ? CiExitCodes.Passed : CiExitCodes.ThresholdViolated;
+        return await FinishAsync(report, options, toolVersion, cancellationToken);
+    }
+
+    private async Task RunQueryCheckAsync(string workspacePath, CiQueryCheck check, CiReport report, CancellationToken cancellationToken)
+    {
+        var name = string.IsNullOrWhiteSpace(check.Name) ? check.Query : check.Name;
+        var result = await _luceneIndexService.SearchAsync(
+            workspacePath,
+            _queryPreprocessor.BuildQuery(check.Query, check.SearchType, false, _codeAnalyzer),
+            maxResults: 1000,
+            cancellationToken);
+
+        var hits = result.Hits
+            .Where(h => check.Extensions.Count == 0 ||
+                        check.Extensions.Contains(Path.GetExtension(h.FilePath), StringComparer.OrdinalIgnoreCase))
+            .ToList();
+
+        foreach (var hit in hits)
+        {
+            report.Findings.Add(new CiFinding
+            {
+                RuleId = $"query/{name}",
+                Level = NormalizeLevel(check.Severity),
+                Message = check.Message ?? $"Matches '{check.Query}'",
+                FilePath = ToReportPath(workspacePath, hit.FilePath),
+                Line = hit.LineNumber ?? FindLine(hit.FilePath, check.Query)
+            });
+        }
+
+        report.Checks.Add(new CiCheckResult
+        {
+            Name = name,
+            Kind = "query",
+            Count = hits.Count,
+            Threshold = check.MaxMatches,
+            Passed = hits.Count <= check.MaxMatches
+        });
+    }
+
+    private async Task RunAnalyzersAsync(string workspacePath, List<string> files, CiAnalyzerCheck check, CiReport report, CancellationToken cancellationToken)
+    {
+        using var scope = _serviceProvider.CreateScope();
+        var tool = scope.ServiceProvider.GetRequiredService<FindPatternsTool>();
+        var counts = new Dictionary<string, int>(StringComparer.OrdinalIgnoreCase);
+
+        foreach (var file in files.Where(f => check.Extensions.Count == 0 ||
+                                              check.Extensions.Contains(Path.GetExtension(f), StringComparer.OrdinalIgnoreCase)))
+        {
+            var response = await tool.ExecuteAsync(new FindPatternsParameters
+            {
+                FilePath = file,
+                DetectAsyncPatterns = check.DetectAsyncPatterns,
+                DetectEmptyCatchBlocks = check.DetectEmptyCatchBlocks,
+                DetectUnusedUsings = check.DetectUnusedUsings,
+                DetectMagicNumbers = check.DetectMagicNumbers,
+                DetectLargeMethods = check.DetectLargeMethods,
+                DetectDeadCode = check.DetectDeadCode,
+                CustomPatterns = check.CustomPatterns,
+                MaxResults = 0
+            }, cancellationToken);
+
+            if (response.Success != true || response.Data?.Results == null)
+            {
+                _logger.LogDebug("find_patterns failed for {FilePath}: {Error}", file, response.Error?.Message);
+                continue;
+            }
+
+            foreach (var pattern in response.Data.Results.PatternsFound)
+            {
+                counts[pattern.Severity] = counts.GetValueOrDefault(pattern.Severity) + 1;
+                report.Findings.Add(new CiFinding
+                {
+                    RuleId = $"pattern/{pattern.Type}",
+                    Level = NormalizeLevel(pattern.Severity),
+                    Message = pattern.Message,
+                    FilePath = ToReportPath(workspacePath, file),
+                    Line = pattern.LineNumber
+                });
+            }
+        }
+
+        foreach (var (severity, limit) in check.MaxFindings)
+        {
+            var count = counts.GetValueOrDefault(severity);
+            report.Checks.Add(new CiCheckResult
+            {
+                Name = $"analyzers:{severity.ToLowerInvariant()}",
+                Kind = "analyzer",
+                Count = count,
+                Threshold = limit,
+                Passed = count <= limit
+            });
+        }
+    }
+
+    private async Task RunSecretScanAsync(string workspacePath, List<string> files, CiSecretCheck check, CiReport report, CancellationToken cancellationToken)
+    {
+        var count = 0;
+        foreach (var file in files)
+        {
+            string content;
+            try
+            {
+                content = await File.ReadAllTextAsync(file, cancellationToken);
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                continue;
+            }
+
+            foreach (var secret in SecretScanner.Scan(content))
+            {
+                count++;
+                report.Findings.Add(new CiFinding
+                {
+                    RuleId = $"secret/{secret.Rule}",
+                    Level = "error",
+                    Message = $"Possible {secret.Rule}: {secret.MaskedPreview}",
+                    FilePath = ToReportPath(workspacePath, file),
+                    Line = secret.LineNumber
+                });
+            }
+        }
+
+        report.Checks.Add(new CiCheckResult
+        {
+            Name = "secrets",
+            Kind = "secrets",
+            Count = count,
+            Threshold = check.MaxFindings,
+            Passed = count <= check.MaxFindings
+        });
+    }
+
+    private async Task<CiReport> FinishAsync(CiReport report, CiOptions options, string toolVersion, CancellationToken cancellationToken)
+    {
+        report.Duration = DateTime.UtcNow - report.StartedAt;
+
+        if (!string.IsNullOrEmpty(options.SarifPath))
+        {
+            await SarifReportWriter.WriteAsync(options.SarifPath, report, toolVersion, cancellationToken);
+        }
+
+        if (!string.IsNullOrEmpty(options.JsonPath))
+        {
+            var directory = Path.GetDirectoryName(Path.GetFullPath(options.JsonPath));
+            if (!string.IsNullOrEmpty(directory))
+                Directory.CreateDirectory(directory);
+            await File.WriteAllTextAsync(options.JsonPath, JsonSerializer.Serialize(report, ReportOptions), cancellationToken);
+        }
+
+        return report;
+    }
+
+    /// <summary>
+    /// Files under the workspace, skipping the same directories and extensions as indexing
+    /// </summary>
+    private IEnumerable<string> EnumerateFiles(string workspacePath)
+    {
+        var excluded = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>() ?? PathConstants.DefaultExcludedDirectories,
+            StringComparer.OrdinalIgnoreCase);
+        var blacklisted = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Indexing:BlacklistedExtensions").Get<string[]>() ?? PathConstants.DefaultBlacklistedExtensions,
+            StringComparer.OrdinalIgnoreCase);
+
+        var pending = new Stack<string>();
+        pending.Push(workspacePath);
+        while (pending.Count > 0)
+        {
+            var directory = pending.Pop();
+            string[] entries;
+            string[] subdirectories;
+            try
+            {
+                entries = Directory.GetFiles(directory);
+                subdirectories = Directory.GetDirectories(directory);
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                continue;
+            }
+
+            foreach (var file in entries.Where(f => !blacklisted.Contains(Path.GetExtension(f))))
+                yield return file;
+
+            foreach (var subdirectory in subdirectories.Where(d => !excluded.Contains(Path.GetFileName(d))))
+                pending.Push(subdirectory);
+        }
+    }
+
+    private static int FindLine(string filePath, string query)
+    {
+        try
+        {
+            var lineNumber = 0;
+            foreach (var line in File.ReadLines(filePath))
+            {
+                lineNumber++;
+                if (line.Contains(query, StringComparison.OrdinalIgnoreCase))
+                    return lineNumber;
+            }
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            // Fall through to the first line
+        }
+
+        return 1;
+    }
+
+    private static string ToReportPath(string workspacePath, string filePath) =>
+        Path.GetRelativePath(workspacePath, filePath).Replace('\\', '/');
+
+    private static string NormalizeLevel(string severity) => severity.ToLowerInvariant() switch
+    {
+        "error" or "critical" => "error",
+        "info" or "note" or "information" => "note",
+        _ => "warning"
+    };
+}
@@ -0,0 +1,74 @@
+using System.Text.Json;
+
+namespace COA.CodeSearch.McpServer.Services.Ci;
+
+/// <summary>
+/// Writes CI findings as SARIF 2.1.0 for code scanning UIs (GitHub, Azure DevOps, GitLab)
+/// </summary>
+public static class SarifReportWriter
+{
+    private static readonly JsonSerializerOptions Options = new() { WriteIndented = true };
+
+    public static string ToSarif(CiReport report, string toolVersion)
+    {
+        var rules = report.Findings
+            .GroupBy(f => f.RuleId)
+            .Select(g => new
+            {
+                id = g.Key,
+                shortDescription = new { text = g.Key },
+                defaultConfiguration = new { level = g.First().Level }
+            })
+            .ToList();
+
+        var sarif = new Dictionary<string, object>
+        {
+            ["$schema"] = "https://json.schemastore.org/sarif-2.1.0.json",
+            ["version"] = "2.1.0",
+            ["runs"] = new[]
+            {
+                new
+                {
+                    tool = new
+                    {
+                        driver = new
+                        {
+                            name = "CodeSearch",
+                            version = toolVersion,
+                            informationUri = "https://github.com/anortham/coa-codesearch-mcp",
+                            rules
+                        }
+                    },
+                    results = report.Findings.Select(f => new
+                    {
+                        ruleId = f.RuleId,
+                        level = f.Level,
+                        message = new { text = f.Message },
+                        locations = new[]
+                        {
+                            new
+                            {
+                                physicalLocation = new
+                                {
+                                    artifactLocation = new { uri = f.FilePath, uriBaseId = "%SRCROOT%" },
+                                    region = new { startLine = Math.Max(1, f.Line) }
+                                }
+                            }
+                        }
+                    })
+                }
+            }
+        };
+
+        return JsonSerializer.Serialize(sarif, Options);
+    }
+
+    public static async Task WriteAsync(string path, CiReport report, string toolVersion, CancellationToken cancellationToken)
+    {
+        var directory = Path.GetDirectoryName(Path.GetFullPath(path));
+        if (!string.IsNullOrEmpty(directory))
+            Directory.CreateDirectory(directory);
+
+        await File.WriteAllTextAsync(path, ToSarif(report, toolVersion), cancellationToken);
+    }
+}
@@ -0,0 +1,66 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Line-oriented stdin wrapper that hands responses to server-initiated requests (sampling, elicitation)
+/// to <see cref="McpClientRequestChannel"/> and passes every other message through to the transport unchanged.
+/// </summary>
+public class ClientResponseReader : TextReader
+{
+    private readonly TextReader _inner;
+    private readonly Func<string, bool> _tryHandleInbound;
+
+    /// <param name="inner">Reader being wrapped</param>
+    /// <param name="tryHandleInbound">Returns true for lines it consumed, which the transport must not see</param>
+    public ClientResponseReader(TextReader inner, Func<string, bool> tryHandleInbound)
+    {
+        _inner = inner;
+        _tryHandleInbound = tryHandleInbound;
+    }
+
+    public override string? ReadLine()
+    {
+        while (true)
+        {
+            var line = _inner.ReadLine();
+            if (line == null || !_tryHandleInbound(line))
+                return line;
+        }
+    }
+
+    public override async Task<string?> ReadLineAsync()
+    {
+        while (true)
+        {
+            var line = await _inner.ReadLineAsync();
+            if (line == null || !_tryHandleInbound(line))
+                return line;
+        }
+    }
+
+    public override async ValueTask<string?> ReadLineAsync(CancellationToken cancellationToken)
+    {
+        while (true)
+        {
+            var line = await _inner.ReadLineAsync(cancellationToken);
+            if (line == null || !_tryHandleInbound(line))
+                return line;
+        }
+    }
+
+    public override int Peek() => _inner.Peek();
+
+    public override int Read() => _inner.Read();
+
+    public override int Read(char[] buffer, int index, int count) => _inner.Read(buffer, index, count);
+
+    public override string ReadToEnd() => _inner.ReadToEnd();
+
+    protected override void Dispose(bool disposing)
+    {
+        if (disposing)
+        {
+            _inner.Dispose();
+        }
+        base.Dispose(disposing);
+    }
+}
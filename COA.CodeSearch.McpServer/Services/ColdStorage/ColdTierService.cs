@@ -0,0 +1,140 @@
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.ColdStorage;
+
+/// <summary>
+/// Matches files against the configured cold path patterns and keeps the materialized files in
+/// cold-tier.json beside each workspace index, so promotions survive re-indexing and restarts
+/// </summary>
+public class ColdTierService : IColdTierService
+{
+    private const string MaterializedFileName = "cold-tier.json";
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILogger<ColdTierService> _logger;
+    private readonly List<Regex> _patterns;
+    private readonly object _sync = new();
+    private readonly Dictionary<string, HashSet<string>> _materialized = new(StringComparer.OrdinalIgnoreCase);
+
+    public ColdTierService(IPathResolutionService pathResolution, IConfiguration configuration, ILogger<ColdTierService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        Patterns = (configuration.GetSection("CodeSearch:ColdStorage:Paths").Get<string[]>() ?? Array.Empty<string>())
+            .Where(p => !string.IsNullOrWhiteSpace(p))
+            .ToList();
+        _patterns = Patterns.Select(GlobToRegex).ToList();
+    }
+
+    public bool IsEnabled => _patterns.Count > 0;
+
+    public IReadOnlyList<string> Patterns { get; }
+
+    public bool IsCold(string workspacePath, string filePath)
+    {
+        if (!IsInColdTier(workspacePath, filePath))
+            return false;
+
+        lock (_sync)
+        {
+            return !GetWorkspaceMaterialized(workspacePath).Contains(ToRelativePath(workspacePath, filePath));
+        }
+    }
+
+    public bool IsInColdTier(string workspacePath, string filePath)
+    {
+        if (_patterns.Count == 0)
+            return false;
+
+        var relativePath = ToRelativePath(workspacePath, filePath);
+        return _patterns.Any(p => p.IsMatch(relativePath));
+    }
+
+    public IReadOnlyList<string> Materialize(string workspacePath, IEnumerable<string> filePaths)
+    {
+        lock (_sync)
+        {
+            var materialized = GetWorkspaceMaterialized(workspacePath);
+            var added = filePaths
+                .Where(f => IsInColdTier(workspacePath, f))
+                .Where(f => materialized.Add(ToRelativePath(workspacePath, f)))
+                .ToList();
+
+            if (added.Count > 0)
+            {
+                Save(workspacePath, materialized);
+                _logger.LogInformation("Materialized {Count} cold-tier file(s) in {Workspace}", added.Count, workspacePath);
+            }
+            return added;
+        }
+    }
+
+    public IReadOnlyList<string> GetMaterialized(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceMaterialized(workspacePath).OrderBy(p => p, StringComparer.OrdinalIgnoreCase).ToList();
+        }
+    }
+
+    // "*.min.js" matches file names anywhere; patterns with a slash match the relative path, and a trailing slash
+    // ("archives/") covers everything below the directory
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        if (normalized.EndsWith('/'))
+            normalized += "**";
+
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    private static string ToRelativePath(string workspacePath, string filePath) =>
+        Path.GetRelativePath(workspacePath, Path.GetFullPath(filePath, workspacePath)).Replace('\\', '/');
+
+    private HashSet<string> GetWorkspaceMaterialized(string workspacePath)
+    {
+        if (_materialized.TryGetValue(workspacePath, out var materialized))
+            return materialized;
+
+        materialized = new HashSet<string>(StringComparer.OrdinalIgnoreCase);
+        var path = GetMaterializedPath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+                materialized.UnionWith(JsonSerializer.Deserialize<string[]>(File.ReadAllText(path)) ?? Array.Empty<string>());
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read materialized cold-tier files {Path} - treating all as cold", path);
+        }
+
+        _materialized[workspacePath] = materialized;
+        return materialized;
+    }
+
+    private void Save(string workspacePath, HashSet<string> materialized)
+    {
+        var path = GetMaterializedPath(workspacePath);
+        try
+        {
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            File.WriteAllText(path, JsonSerializer.Serialize(materialized.OrderBy(p => p, StringComparer.OrdinalIgnoreCase)));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not save materialized cold-tier files {Path}", path);
+        }
+    }
+
+    private string GetMaterializedPath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), MaterializedFileName);
+}
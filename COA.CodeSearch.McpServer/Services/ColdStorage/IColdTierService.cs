@@ -0,0 +1,38 @@
+namespace COA.CodeSearch.McpServer.Services.ColdStorage;
+
+/// <summary>
+/// Decides which files belong to the cold tier (CodeSearch:ColdStorage:Paths) and which of those have been
+/// materialized back to full fidelity on demand
+/// </summary>
+public interface IColdTierService
+{
+    /// <summary>
+    /// Whether any cold path patterns are configured
+    /// </summary>
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// Configured cold path patterns, relative to the workspace
+    /// </summary>
+    IReadOnlyList<string> Patterns { get; }
+
+    /// <summary>
+    /// Whether a file should be indexed with reduced fidelity: it matches a cold pattern and was not materialized
+    /// </summary>
+    bool IsCold(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Whether a file matches a cold pattern, materialized or not
+    /// </summary>
+    bool IsInColdTier(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Records cold-tier files to be indexed at full fidelity from now on. Returns the files that were not already materialized.
+    /// </summary>
+    IReadOnlyList<string> Materialize(string workspacePath, IEnumerable<string> filePaths);
+
+    /// <summary>
+    /// Files materialized so far, relative to the workspace
+    /// </summary>
+    IReadOnlyList<string> GetMaterialized(string workspacePath);
+}
@@ -97,7 +97,7 @@ public class ConfidenceCalculatorService
         // Bonus for correct usage patterns
         if (!string.IsNullOrEmpty(symbolType))
         {
-            if (_usagePatterns.TryGetValue(symbolType.ToLower(), out var pattern))
+            if (_usagePatterns.TryGetValue(symbolType.ToLowerInvariant(), out var pattern))
             {
                 if (pattern.IsMatch(cleanLine))
                 {
@@ -155,7 +155,7 @@ public class ConfidenceCalculatorService
             });
         }
 
-        if (_definitionPatterns.TryGetValue(symbolType.ToLower(), out var definitionPattern))
+        if (_definitionPatterns.TryGetValue(symbolType.ToLowerInvariant(), out var definitionPattern))
         {
             var match = definitionPattern.Match(line);
             return match.Success && match.Groups.Cast<Group>()
@@ -0,0 +1,83 @@
+namespace COA.CodeSearch.McpServer.Services.Constants;
+
+/// <summary>
+/// A constant or enum member with its value
+/// </summary>
+public class ConstantDefinition
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Enclosing enum or type, null for package- or module-level constants
+    /// </summary>
+    public string? Container { get; set; }
+
+    /// <summary>
+    /// enum_member or constant
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Normalised value (decimal integers, double-quoted strings, true/false); null when it is an expression
+    /// the catalog cannot evaluate
+    /// </summary>
+    public string? Value { get; set; }
+
+    /// <summary>
+    /// Initializer as written; null for implicitly numbered enum members and repeated iota specs
+    /// </summary>
+    public string? Initializer { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string SymbolId { get; set; } = string.Empty;
+
+    public string QualifiedName => Container == null ? Name : $"{Container}.{Name}";
+}
+
+/// <summary>
+/// A place a constant is used, by name or as the same raw literal
+/// </summary>
+public class ConstantUsage
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The source line, trimmed
+    /// </summary>
+    public string Code { get; set; } = string.Empty;
+
+    /// <summary>
+    /// reference (by name) or literal (the raw value)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Qualified name of the constant referenced, or whose value the literal equals
+    /// </summary>
+    public string Constant { get; set; } = string.Empty;
+
+    /// <summary>
+    /// For literals: the line also mentions the constant's name or a word of its enum, so the literal is
+    /// probably a stand-in for it rather than a coincidence
+    /// </summary>
+    public bool Likely { get; set; }
+}
+
+/// <summary>
+/// Definitions matching a name or value and where they are used
+/// </summary>
+public class ConstantUsageReport
+{
+    public List<ConstantDefinition> Definitions { get; set; } = new();
+    public List<ConstantUsage> References { get; set; } = new();
+    public List<ConstantUsage> Literals { get; set; } = new();
+
+    /// <summary>
+    /// Files scanned for raw literals
+    /// </summary>
+    public int FilesScanned { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,408 @@
+using System.Collections.Concurrent;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Constants;
+
+/// <summary>
+/// Builds the constant catalog from the index's enum, enum member, constant and const-qualified field symbols,
+/// reading values from their declaration lines, and maps usages: identifiers resolved to the constant (or,
+/// when unresolved, written with its enum qualifier) and literals equal to its value.
+/// </summary>
+public class ConstantUsageService : IConstantUsageService
+{
+    private static readonly string[] ConstantKinds = { "constant", "enum_member", "field", "variable", "property" };
+    private static readonly string[] TypeKinds = { "class", "interface", "struct", "record", "trait", "enum", "type" };
+    private static readonly Regex ConstModifier = new(@"\b(?:const|final|readonly|static\s+final)\b", RegexOptions.Compiled);
+    private static readonly Regex MemberModifier = new(@"\b(?:private|public|protected|internal|static|final|readonly)\b", RegexOptions.Compiled);
+    private static readonly Regex UpperCaseName = new(@"^[A-Z][A-Z0-9_]*$", RegexOptions.Compiled);
+    private static readonly Regex Words = new(@"[A-Z]?[a-z]+|[A-Z]+(?![a-z])", RegexOptions.Compiled);
+
+    // Enums whose unvalued members count up from the previous value; elsewhere members are names, not numbers
+    private static readonly HashSet<string> NumberedEnumExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".cs", ".ts", ".tsx", ".c", ".h", ".cpp", ".cc", ".hpp", ".rs", ".swift", ".m", ".mm"
+    };
+
+    // Languages where const/final also declares ordinary locals: require CONSTANT_CASE or a top-level declaration
+    private static readonly HashSet<string> LooseConstExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".py", ".dart", ".kt", ".kts", ".swift", ".rs"
+    };
+
+    private const int MaxDefinitions = 25;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<ConstantUsageService> _logger;
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, List<ConstantDefinition> Catalog)> _catalogs = new(StringComparer.OrdinalIgnoreCase);
+
+    public ConstantUsageService(ISQLiteSymbolService sqliteService, ILogger<ConstantUsageService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    public async Task<List<ConstantDefinition>> GetCatalogAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var stamp = DatabaseStamp(workspacePath);
+        if (_catalogs.TryGetValue(workspacePath, out var cached) && cached.Stamp == stamp)
+            return cached.Catalog;
+
+        var symbols = new List<JulieSymbol>();
+        foreach (var kind in ConstantKinds.Concat(TypeKinds))
+            symbols.AddRange(await _sqliteService.GetSymbolsByKindAsync(workspacePath, kind, cancellationToken));
+
+        var catalog = new List<ConstantDefinition>();
+        var files = new FileCache(_sqliteService, workspacePath, cancellationToken);
+        foreach (var group in symbols.GroupBy(s => s.FilePath))
+        {
+            var lines = await files.ReadAsync(group.Key);
+            if (lines != null)
+                catalog.AddRange(CatalogFile(group.Key, group.ToList(), lines));
+        }
+
+        _catalogs[workspacePath] = (stamp, catalog);
+        _logger.LogDebug("Constant catalog for {Workspace}: {Count} constants and enum members", workspacePath, catalog.Count);
+        return catalog;
+    }
+
+    public async Task<ConstantUsageReport> FindUsagesAsync(string workspacePath, string? name, string? value, bool includeLiterals,
+        string? filePattern, int maxResults, CancellationToken cancellationToken = default)
+    {
+        var catalog = await GetCatalogAsync(workspacePath, cancellationToken);
+        var report = new ConstantUsageReport();
+        var wanted = value == null ? null : ConstantValues.Normalize(value) ?? ConstantValues.Normalize($"\"{value.Trim()}\"");
+
+        var (container, member) = SplitName(name);
+        report.Definitions = catalog
+            .Where(d => member == null || d.Name == member && (container == null || d.Container == container || d.Container == null))
+            .Where(d => wanted == null || d.Value == wanted)
+            .OrderBy(d => d.FilePath, StringComparer.Ordinal).ThenBy(d => d.Line)
+            .Take(MaxDefinitions)
+            .ToList();
+
+        var filter = string.IsNullOrWhiteSpace(filePattern) ? null : GlobToRegex(filePattern);
+        var files = new FileCache(_sqliteService, workspacePath, cancellationToken);
+        var definitionLines = catalog.Select(d => (FullPath(workspacePath, d.FilePath), d.Line)).ToHashSet();
+
+        foreach (var definition in report.Definitions)
+        {
+            foreach (var identifier in await _sqliteService.GetIdentifiersByNameAsync(workspacePath, definition.Name, caseSensitive: true, cancellationToken))
+            {
+                if (!Refers(identifier, definition) || definitionLines.Contains((FullPath(workspacePath, identifier.FilePath), identifier.StartLine)))
+                    continue;
+                if (filter != null && !filter.IsMatch(Relative(workspacePath, identifier.FilePath)))
+                    continue;
+                if (report.References.Count == maxResults)
+                {
+                    report.Truncated = true;
+                    break;
+                }
+
+                var lines = await files.ReadAsync(identifier.FilePath);
+                report.References.Add(new ConstantUsage
+                {
+                    FilePath = identifier.FilePath,
+                    Line = identifier.StartLine,
+                    Code = lines != null && identifier.StartLine <= lines.Length ? lines[identifier.StartLine - 1].Trim() : identifier.CodeContext?.Trim() ?? string.Empty,
+                    Kind = "reference",
+                    Constant = definition.QualifiedName
+                });
+            }
+        }
+
+        var values = report.Definitions.Where(d => d.Value != null).GroupBy(d => d.Value!).ToDictionary(g => g.Key, g => g.ToList());
+        if (values.Count == 0 && wanted != null)
+            values[wanted] = new List<ConstantDefinition>();
+        if (includeLiterals && values.Count > 0)
+        {
+            await ScanLiteralsAsync(workspacePath, values, definitionLines, filter, maxResults, report, files, cancellationToken);
+        }
+
+        report.References = report.References.OrderBy(u => u.FilePath, StringComparer.Ordinal).ThenBy(u => u.Line).ToList();
+        report.Literals = report.Literals.OrderByDescending(u => u.Likely).ThenBy(u => u.FilePath, StringComparer.Ordinal).ThenBy(u => u.Line).ToList();
+        return report;
+    }
+
+    private static (string? Container, string? Member) SplitName(string? name)
+    {
+        if (string.IsNullOrWhiteSpace(name))
+            return (null, null);
+        var segments = name.Trim().Replace("::", ".").Split('.', StringSplitOptions.RemoveEmptyEntries);
+        return segments.Length > 1 ? (segments[^2], segments[^1]) : (null, segments[0]);
+    }
+
+    /// <summary>
+    /// The identifier resolves to the definition, or is unresolved and written the way a reference to it
+    /// would be: qualified by its enum or type, or bare in the declaring file (switch labels, siblings)
+    /// </summary>
+    private static bool Refers(JulieIdentifier identifier, ConstantDefinition definition)
+    {
+        if (!string.IsNullOrEmpty(identifier.TargetSymbolId))
+            return identifier.TargetSymbolId == definition.SymbolId;
+        if (definition.Container == null)
+            return true;
+        if (string.Equals(Path.GetFullPath(identifier.FilePath), Path.GetFullPath(definition.FilePath), StringComparison.OrdinalIgnoreCase))
+            return true;
+
+        var context = identifier.CodeContext ?? string.Empty;
+        return Regex.IsMatch(context, $@"\b{Regex.Escape(definition.Container)}\s*(?:\.|::)\s*{Regex.Escape(definition.Name)}\b")
+            || Regex.IsMatch(context, $@"\bcase\s+{Regex.Escape(definition.Name)}\b");
+    }
+
+    private async Task ScanLiteralsAsync(string workspacePath, Dictionary<string, List<ConstantDefinition>> values,
+        HashSet<(string, int)> definitionLines, Regex? filter, int maxResults, ConstantUsageReport report, FileCache files,
+        CancellationToken cancellationToken)
+    {
+        var hints = values.ToDictionary(v => v.Key, v => v.Value
+            .SelectMany(d => new[] { d.Name, d.Container ?? string.Empty })
+            .SelectMany(n => Words.Matches(n).Select(m => m.Value.ToLowerInvariant()))
+            .Where(w => w.Length >= 4)
+            .Distinct()
+            .ToList());
+
+        foreach (var path in await CandidateFilesAsync(workspacePath, values.Keys, cancellationToken))
+        {
+            var relative = Relative(workspacePath, path);
+            if (filter != null && !filter.IsMatch(relative))
+                continue;
+            var lines = await files.ReadAsync(path);
+            if (lines == null)
+                continue;
+
+            report.FilesScanned++;
+            var fullPath = FullPath(workspacePath, path);
+            var masked = DataFlowScanner.MaskLines(lines, Path.GetExtension(path));
+            for (var i = 0; i < lines.Length; i++)
+            {
+                if (definitionLines.Contains((fullPath, i + 1)))
+                    continue;
+
+                foreach (var literal in LiteralsOn(lines[i], masked[i]).Where(values.ContainsKey).Distinct())
+                {
+                    if (report.Literals.Count == maxResults)
+                    {
+                        report.Truncated = true;
+                        return;
+                    }
+
+                    var lower = lines[i].ToLowerInvariant();
+                    var definitions = values[literal];
+                    report.Literals.Add(new ConstantUsage
+                    {
+                        FilePath = path,
+                        Line = i + 1,
+                        Code = lines[i].Trim(),
+                        Kind = "literal",
+                        Constant = definitions.Count > 0 ? string.Join(", ", definitions.Select(d => d.QualifiedName).Distinct()) : literal,
+                        Likely = hints[literal].Any(lower.Contains)
+                    });
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// Normalised numeric and string literals on a line; comments and the insides of other strings are skipped
+    /// </summary>
+    private static IEnumerable<string> LiteralsOn(string line, string masked)
+    {
+        foreach (Match number in ConstantValues.NumberToken.Matches(masked))
+        {
+            var negative = number.Index > 0 && masked[number.Index - 1] == '-'
+                && (number.Index < 2 || !char.IsLetterOrDigit(masked[number.Index - 2]) && masked[number.Index - 2] is not (')' or ']' or '_'));
+            string? normalized;
+            try
+            {
+                var parsed = ConstantValues.ParseInteger(number.Value);
+                normalized = (negative ? -parsed : parsed).ToString(System.Globalization.CultureInfo.InvariantCulture);
+            }
+            catch (Exception ex) when (ex is FormatException or OverflowException)
+            {
+                continue;
+            }
+            yield return normalized;
+        }
+
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (masked[i] is not ('"' or '\'' or '`'))
+                continue;
+            var close = masked.IndexOf(masked[i], i + 1);
+            if (close < 0)
+                break;
+            yield return "\"" + line[(i + 1)..close] + "\"";
+            i = close;
+        }
+    }
+
+    /// <summary>
+    /// Files that may contain the values: a full-text match when every value has a searchable token, else every indexed file
+    /// </summary>
+    private async Task<List<string>> CandidateFilesAsync(string workspacePath, IEnumerable<string> values, CancellationToken cancellationToken)
+    {
+        var tokens = values.Select(v => v.Trim('"')).ToList();
+        if (tokens.All(t => t.Length >= 3 && Regex.IsMatch(t, @"^[\w ]+$")))
+        {
+            var match = string.Join(" OR ", tokens.Select(t => $"\"{t}\""));
+            try
+            {
+                var matched = await _sqliteService.SearchWithFTS5Async(workspacePath, match, 5000, null, cancellationToken);
+                if (matched.Count > 0)
+                    return matched.Select(f => f.Path).ToList();
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogDebug(ex, "Full-text candidate search failed, scanning all files");
+            }
+        }
+
+        return (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken)).Select(f => f.Path).ToList();
+    }
+
+    private static List<ConstantDefinition> CatalogFile(string filePath, List<JulieSymbol> symbols, string[] lines)
+    {
+        var extension = Path.GetExtension(filePath);
+        var types = symbols.Where(s => TypeKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase)).ToList();
+        var enums = types.Where(s => s.Kind.Equals("enum", StringComparison.OrdinalIgnoreCase)).ToList();
+        var result = new List<ConstantDefinition>();
+        var handled = new HashSet<string>();
+
+        foreach (var enumSymbol in enums)
+        {
+            var members = symbols
+                .Where(s => !TypeKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase)
+                    && (s.ParentId == enumSymbol.Id || s.ParentId == null && s.StartLine > enumSymbol.StartLine && s.EndLine <= enumSymbol.EndLine)
+                    && !(s.StartLine >= 1 && s.StartLine <= lines.Length && MemberModifier.IsMatch(lines[s.StartLine - 1])))
+                .OrderBy(s => s.StartLine).ThenBy(s => s.StartColumn)
+                .ToList();
+            var initializers = members.Select(m => Initializer(lines, m, extension)).ToList();
+            var values = NumberedEnumExtensions.Contains(extension)
+                ? ConstantValues.EnumSequence(initializers)
+                : initializers.Select(i => i == null ? null : ConstantValues.Normalize(i)).ToList();
+
+            for (var i = 0; i < members.Count; i++)
+            {
+                handled.Add(members[i].Id);
+                result.Add(Define(members[i], "enum_member", enumSymbol.Name, initializers[i], values[i]));
+            }
+        }
+
+        foreach (var symbol in symbols.Where(s => !handled.Contains(s.Id) && !TypeKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase)))
+        {
+            if (symbol.StartLine < 1 || symbol.StartLine > lines.Length)
+                continue;
+            var line = lines[symbol.StartLine - 1];
+            var isConstant = symbol.Kind.Equals("constant", StringComparison.OrdinalIgnoreCase) || ConstModifier.IsMatch(line);
+            if (!isConstant)
+                continue;
+            if (LooseConstExtensions.Contains(extension) && !UpperCaseName.IsMatch(symbol.Name) && line.Length - line.TrimStart().Length > 0)
+                continue;
+
+            var initializer = Initializer(lines, symbol, extension);
+            string? value;
+            if (extension.Equals(".go", StringComparison.OrdinalIgnoreCase)
+                && ConstantValues.GoConstBlockValues(lines, symbol.StartLine - 1) is { Count: > 0 } block)
+            {
+                value = block.GetValueOrDefault(symbol.StartLine - 1);
+            }
+            else
+            {
+                value = initializer == null ? null : ConstantValues.Normalize(initializer);
+            }
+
+            // Fields without a literal or constant-expression value are not useful to the catalog
+            if (value == null && !symbol.Kind.Equals("constant", StringComparison.OrdinalIgnoreCase))
+                continue;
+
+            var container = types
+                .Where(t => t.StartLine <= symbol.StartLine && t.EndLine >= symbol.EndLine && t.Id != symbol.Id)
+                .OrderBy(t => t.EndLine - t.StartLine)
+                .FirstOrDefault();
+            result.Add(Define(symbol, "constant", container?.Name, initializer, value));
+        }
+        return result;
+    }
+
+    private static string? Initializer(string[] lines, JulieSymbol symbol, string extension) =>
+        symbol.StartLine >= 1 && symbol.StartLine <= lines.Length ? ConstantValues.InitializerOf(lines[symbol.StartLine - 1], symbol.Name, extension) : null;
+
+    private static ConstantDefinition Define(JulieSymbol symbol, string kind, string? container, string? initializer, string? value) => new()
+    {
+        Name = symbol.Name,
+        Container = container,
+        Kind = kind,
+        Initializer = initializer,
+        Value = value,
+        FilePath = symbol.FilePath,
+        Line = symbol.StartLine,
+        SymbolId = symbol.Id
+    };
+
+    // The catalog is rebuilt when the database or its write-ahead log changes
+    private DateTime DatabaseStamp(string workspacePath)
+    {
+        var path = _sqliteService.GetDatabasePath(workspacePath);
+        var stamp = File.Exists(path) ? File.GetLastWriteTimeUtc(path) : DateTime.MinValue;
+        var wal = path + "-wal";
+        return File.Exists(wal) && File.GetLastWriteTimeUtc(wal) > stamp ? File.GetLastWriteTimeUtc(wal) : stamp;
+    }
+
+    private static string FullPath(string workspacePath, string filePath) =>
+        Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    // "*.cs" matches file names anywhere; patterns with a slash match the relative path
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    /// <summary>
+    /// File lines from disk, falling back to the content stored in the index
+    /// </summary>
+    private sealed class FileCache
+    {
+        private readonly ISQLiteSymbolService _sqliteService;
+        private readonly string _workspacePath;
+        private readonly CancellationToken _cancellationToken;
+        private readonly Dictionary<string, string[]?> _files = new(StringComparer.OrdinalIgnoreCase);
+
+        public FileCache(ISQLiteSymbolService sqliteService, string workspacePath, CancellationToken cancellationToken)
+        {
+            _sqliteService = sqliteService;
+            _workspacePath = workspacePath;
+            _cancellationToken = cancellationToken;
+        }
+
+        public async Task<string[]?> ReadAsync(string filePath)
+        {
+            if (_files.TryGetValue(filePath, out var cached))
+                return cached;
+
+            var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(_workspacePath, filePath);
+            string[]? lines = null;
+            if (File.Exists(fullPath))
+            {
+                lines = await File.ReadAllLinesAsync(fullPath, _cancellationToken);
+            }
+            else if (await _sqliteService.GetFileByPathAsync(_workspacePath, filePath, _cancellationToken) is { Content: { } content })
+            {
+                lines = content.Replace("\r\n", "\n").Split('\n');
+            }
+            _files[filePath] = lines;
+            return lines;
+        }
+    }
+}
@@ -0,0 +1,282 @@
+using System.Globalization;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Constants;
+
+/// <summary>
+/// Reads constant and enum member values from declaration lines: explicit initializers, implicit enum
+/// numbering and Go iota blocks. Values are normalised so different spellings compare equal: integers in
+/// decimal (0x10 is 16), strings double-quoted, booleans lower-case.
+/// </summary>
+public static class ConstantValues
+{
+    private static readonly Regex Initializer = new(@"^\s*(?::\s*[^=;,(){}]+?|\s+[\w.\[\]*]+)?\s*(?<op>=(?!=)|\()\s*", RegexOptions.Compiled);
+    private static readonly Regex GoConstBlock = new(@"^\s*const\s*\(\s*$", RegexOptions.Compiled);
+    private static readonly Regex GoSpec = new(@"^\s*(?<name>\w+)\b", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Numeric literal tokens on a masked line: decimal, hex, binary, with digit separators and suffixes
+    /// </summary>
+    public static readonly Regex NumberToken = new(
+        @"(?<![\w.$])(?:0[xX][0-9a-fA-F_]+|0[bB][01_]+|\d[\d_]*)(?:[uUlL]{0,3})(?![\w.])", RegexOptions.Compiled);
+
+    /// <summary>
+    /// The initializer of <paramref name="name"/> on its declaration line, as written: <c>3</c>,
+    /// <c>"archived"</c>, <c>1 &lt;&lt; 4</c>, <c>iota</c>. A Java-style enum constant <c>ACTIVE(3)</c> yields its
+    /// first argument. Null when the line gives no value.
+    /// </summary>
+    public static string? InitializerOf(string line, string name, string extension)
+    {
+        var masked = DataFlowScanner.Mask(line, extension);
+        foreach (var index in DataFlowScanner.FindOccurrences(masked, name))
+        {
+            var after = index + name.Length;
+            var match = Initializer.Match(masked[after..]);
+            if (!match.Success)
+                continue;
+
+            var start = after + match.Length;
+            var parenthesised = match.Groups["op"].Value == "(";
+            var end = start;
+            var depth = 0;
+            for (; end < masked.Length; end++)
+            {
+                var c = masked[end];
+                if (c is '(' or '[' or '{') depth++;
+                else if (c is ')' or ']' or '}')
+                {
+                    if (depth == 0) break;
+                    depth--;
+                }
+                else if (c is ',' or ';' && depth == 0) break;
+            }
+
+            var value = line[start..end].Trim();
+            if (value.Length == 0 || parenthesised && Normalize(value) == null)
+                return null;
+            return value;
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Normalised value of a literal or constant integer expression, or null when it is not one
+    /// </summary>
+    public static string? Normalize(string literal, long? iota = null)
+    {
+        var text = literal.Trim();
+        if (text.Length >= 2 && text[0] is '"' or '\'' or '`' && text[^1] == text[0])
+            return "\"" + text[1..^1] + "\"";
+        if (text is "true" or "True" or "TRUE")
+            return "true";
+        if (text is "false" or "False" or "FALSE")
+            return "false";
+
+        var evaluator = new IntegerExpression(text, iota);
+        return evaluator.TryEvaluate(out var number) ? number.ToString(CultureInfo.InvariantCulture) : null;
+    }
+
+    /// <summary>
+    /// Values of enum members in declaration order: an explicit value sets the member, a missing one is the
+    /// previous value plus one, starting at 0
+    /// </summary>
+    public static List<string?> EnumSequence(IReadOnlyList<string?> initializers)
+    {
+        var values = new List<string?>();
+        long? previous = -1;
+        foreach (var initializer in initializers)
+        {
+            string? value;
+            if (initializer == null)
+            {
+                value = previous is { } p ? (p + 1).ToString(CultureInfo.InvariantCulture) : null;
+            }
+            else
+            {
+                value = Normalize(initializer);
+            }
+            previous = long.TryParse(value, NumberStyles.Integer, CultureInfo.InvariantCulture, out var number) ? number : null;
+            values.Add(value);
+        }
+        return values;
+    }
+
+    /// <summary>
+    /// Values of the specs in a Go <c>const ( ... )</c> block containing <paramref name="line"/> (0-based),
+    /// keyed by line: iota counts the specs and a spec without a value repeats the previous expression.
+    /// Empty when the line is not in such a block.
+    /// </summary>
+    public static Dictionary<int, string?> GoConstBlockValues(IReadOnlyList<string> lines, int line)
+    {
+        var values = new Dictionary<int, string?>();
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+
+        var start = line;
+        while (start >= 0 && !GoConstBlock.IsMatch(masked[start]))
+        {
+            if (start < line && masked[start].Trim() == ")")
+                return values;
+            start--;
+        }
+        if (start < 0)
+            return values;
+
+        long iota = 0;
+        string? expression = null;
+        for (var i = start + 1; i < masked.Length && masked[i].Trim() != ")"; i++)
+        {
+            var spec = GoSpec.Match(masked[i]);
+            if (!spec.Success)
+                continue;
+
+            var initializer = InitializerOf(lines[i], spec.Groups["name"].Value, ".go");
+            if (initializer != null)
+                expression = initializer;
+            values[i] = expression == null ? null : Normalize(expression, iota);
+            iota++;
+        }
+        return values;
+    }
+
+    /// <summary>
+    /// Recursive descent over integer literals, iota, parentheses and the operators Go, C and C# constants
+    /// commonly use: unary -, * / %, + -, &lt;&lt; &gt;&gt;, &amp;, |
+    /// </summary>
+    private sealed class IntegerExpression
+    {
+        private readonly string _text;
+        private readonly long? _iota;
+        private int _position;
+
+        public IntegerExpression(string text, long? iota)
+        {
+            _text = text;
+            _iota = iota;
+        }
+
+        public bool TryEvaluate(out long value)
+        {
+            try
+            {
+                value = Or();
+                Skip();
+                return _position == _text.Length;
+            }
+            catch (FormatException)
+            {
+                value = 0;
+                return false;
+            }
+            catch (OverflowException)
+            {
+                value = 0;
+                return false;
+            }
+        }
+
+        private long Or()
+        {
+            var value = And();
+            while (Accept("|")) value |= And();
+            return value;
+        }
+
+        private long And()
+        {
+            var value = Shift();
+            while (Accept("&")) value &= Shift();
+            return value;
+        }
+
+        private long Shift()
+        {
+            var value = Sum();
+            while (true)
+            {
+                if (Accept("<<")) value <<= (int)Sum();
+                else if (Accept(">>")) value >>= (int)Sum();
+                else return value;
+            }
+        }
+
+        private long Sum()
+        {
+            var value = Product();
+            while (true)
+            {
+                if (Accept("+")) value = checked(value + Product());
+                else if (Accept("-")) value = checked(value - Product());
+                else return value;
+            }
+        }
+
+        private long Product()
+        {
+            var value = Unary();
+            while (true)
+            {
+                if (Accept("*")) value = checked(value * Unary());
+                else if (Accept("/")) value = Unary() is var d and not 0 ? value / d : throw new FormatException();
+                else if (Accept("%")) value = Unary() is var m and not 0 ? value % m : throw new FormatException();
+                else return value;
+            }
+        }
+
+        private long Unary()
+        {
+            if (Accept("-")) return checked(-Unary());
+            if (Accept("+")) return Unary();
+            if (Accept("("))
+            {
+                var value = Or();
+                if (!Accept(")")) throw new FormatException();
+                return value;
+            }
+
+            Skip();
+            if (_iota is { } iota && string.CompareOrdinal(_text, _position, "iota", 0, 4) == 0
+                && (_position + 4 == _text.Length || !char.IsLetterOrDigit(_text[_position + 4])))
+            {
+                _position += 4;
+                return iota;
+            }
+
+            var match = NumberToken.Match(_text, _position);
+            if (!match.Success || match.Index != _position)
+                throw new FormatException();
+            _position += match.Length;
+            return ParseInteger(match.Value);
+        }
+
+        private bool Accept(string token)
+        {
+            Skip();
+            if (string.CompareOrdinal(_text, _position, token, 0, token.Length) != 0)
+                return false;
+            // Keep "<" of "<<" and "|" of "||" from matching alone
+            if (token.Length == 1 && _position + 1 < _text.Length && _text[_position + 1] == token[0] && token[0] is '<' or '>' or '|' or '&')
+                return false;
+            _position += token.Length;
+            return true;
+        }
+
+        private void Skip()
+        {
+            while (_position < _text.Length && char.IsWhiteSpace(_text[_position])) _position++;
+        }
+    }
+
+    /// <summary>
+    /// Value of an integer literal token as matched by <see cref="NumberToken"/>
+    /// </summary>
+    public static long ParseInteger(string token)
+    {
+        var digits = token.TrimEnd('u', 'U', 'l', 'L').Replace("_", "");
+        if (digits.StartsWith("0x", StringComparison.OrdinalIgnoreCase))
+            return long.Parse(digits[2..], NumberStyles.HexNumber, CultureInfo.InvariantCulture);
+        if (digits.StartsWith("0b", StringComparison.OrdinalIgnoreCase))
+            return Convert.ToInt64(digits[2..], 2);
+        return long.Parse(digits, NumberStyles.Integer, CultureInfo.InvariantCulture);
+    }
+}
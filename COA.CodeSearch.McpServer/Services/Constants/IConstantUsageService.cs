@@ -0,0 +1,22 @@
+namespace COA.CodeSearch.McpServer.Services.Constants;
+
+/// <summary>
+/// Catalog of the workspace's constants and enum members with their values, and their usages - by name
+/// through the index's identifiers, and as raw literals of the same value
+/// </summary>
+public interface IConstantUsageService
+{
+    /// <summary>
+    /// Every indexed constant and enum member with its value, rebuilt when the symbol database changes
+    /// </summary>
+    Task<List<ConstantDefinition>> GetCatalogAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Constants matching <paramref name="name"/> (Name or Container.Name) and/or <paramref name="value"/>, and their usages.
+    /// </summary>
+    /// <param name="value">A literal as written in code, e.g. 3, 0x03 or "archived"</param>
+    /// <param name="includeLiterals">Also scan files for raw literals equal to the constants' values</param>
+    /// <param name="filePattern">Glob restricting the files whose usages are reported</param>
+    Task<ConstantUsageReport> FindUsagesAsync(string workspacePath, string? name, string? value, bool includeLiterals,
+        string? filePattern, int maxResults, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,373 @@
+using System.Net;
+using System.Reflection;
+using System.Security.Cryptography;
+using System.Text;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools;
+using COA.CodeSearch.McpServer.Tools.Parameters;
+using Microsoft.AspNetCore.Builder;
+using Microsoft.AspNetCore.Hosting;
+using Microsoft.AspNetCore.Http;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+using Serilog;
+
+namespace COA.CodeSearch.McpServer.Services.Dashboard;
+
+/// <summary>
+/// Optional read-only web UI for humans supervising what the agent can see: search, symbol explorer,
+/// analyzer findings and index health. Runs its own Kestrel listener next to the STDIO transport
+/// (stdout belongs to MCP), bound to loopback unless configured otherwise.
+/// </summary>
+public class DashboardService : BackgroundService
+{
+    private const string PageResource = "COA.CodeSearch.McpServer.Dashboard.dashboard.html";
+    private const string TokenHeader = "X-CodeSearch-Token";
+    private const int MaxResults = 200;
+
+    private readonly IServiceProvider _serviceProvider;
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly QueryPreprocessor _queryPreprocessor;
+    private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IIndexRetentionService _retentionService;
+    private readonly ILogger<DashboardService> _logger;
+    private readonly bool _enabled;
+    private readonly string _bindAddress;
+    private readonly int _port;
+    private readonly string? _accessToken;
+
+    public DashboardService(
+        IServiceProvider serviceProvider,
+        IPathResolutionService pathResolution,
+        ILuceneIndexService luceneIndexService,
+        QueryPreprocessor queryPreprocessor,
+        CodeAnalyzer codeAnalyzer,
+        ISQLiteSymbolService sqliteService,
+        IIndexRetentionService retentionService,
+        IConfiguration configuration,
+        ILogger<DashboardService> logger)
+    {
+        _serviceProvider = serviceProvider;
+        _pathResolution = pathResolution;
+        _luceneIndexService = luceneIndexService;
+        _queryPreprocessor = queryPreprocessor;
+        _codeAnalyzer = codeAnalyzer;
+        _sqliteService = sqliteService;
+        _retentionService = retentionService;
+        _logger = logger;
+
+        _enabled = configuration.GetValue("CodeSearch:Dashboard:Enabled", false);
+        _bindAddress = configuration.GetValue("CodeSearch:Dashboard:BindAddress", "127.0.0.1")!;
+        _port = configuration.GetValue("CodeSearch:Dashboard:Port", 5380);
+
+        // Environment variable wins so the token can stay out of config files
+        var token = Environment.GetEnvironmentVariable("CODESEARCH_DASHBOARD_TOKEN") ?? configuration["CodeSearch:Dashboard:AccessToken"];
+        _accessToken = string.IsNullOrWhiteSpace(token) ? null : token;
+    }
+
+    /// <summary>
+    /// URL the dashboard listens on, with the session token when none is configured, or null when it is not running
+    /// </summary>
+    public string? Url { get; private set; }
+
+    protected override async Task ExecuteAsync(CancellationToken stoppingToken)
+    {
+        if (!_enabled)
+            return;
+
+        if (!IPAddress.TryParse(_bindAddress, out var address))
+        {
+            _logger.LogWarning("Dashboard disabled: invalid bind address {BindAddress}", _bindAddress);
+            return;
+        }
+        var loopback = IPAddress.IsLoopback(address);
+        if (!loopback && _accessToken == null)
+        {
+            _logger.LogWarning("Dashboard disabled: binding to {BindAddress} requires CodeSearch:Dashboard:AccessToken or CODESEARCH_DASHBOARD_TOKEN", _bindAddress);
+            return;
+        }
+
+        // A token is required even on loopback, where any page the user opens could otherwise reach the API;
+        // without a configured one, each session makes its own and hands it out in the URL
+        var accessToken = _accessToken ?? Convert.ToHexString(RandomNumberGenerator.GetBytes(16)).ToLowerInvariant();
+
+        var builder = WebApplication.CreateSlimBuilder();
+        builder.Logging.ClearProviders();
+        builder.Logging.AddSerilog();
+        builder.Services.Configure<ConsoleLifetimeOptions>(options => options.SuppressStatusMessages = true);
+        builder.WebHost.ConfigureKestrel(kestrel => kestrel.Listen(address, _port));
+
+        await using var app = builder.Build();
+        app.Use(async (context, next) =>
+        {
+            // A DNS-rebinding page reaches loopback under its own host name, which browsers send in Host
+            if (loopback && !IsLoopbackHost(context.Request.Host))
+            {
+                context.Response.StatusCode = StatusCodes.Status400BadRequest;
+                await context.Response.WriteAsync("Host not allowed");
+                return;
+            }
+            if (!IsAuthorized(context.Request, accessToken))
+            {
+                context.Response.StatusCode = StatusCodes.Status401Unauthorized;
+                await context.Response.WriteAsync("Missing or invalid dashboard token");
+                return;
+            }
+            await next(context);
+        });
+        MapEndpoints(app);
+
+        try
+        {
+            await app.StartAsync(stoppingToken);
+            var url = $"http://{(address.AddressFamily == System.Net.Sockets.AddressFamily.InterNetworkV6 ? $"[{address}]" : address.ToString())}:{_port}/";
+
+            // A configured token stays out of logs; a session token is only any use with the URL
+            Url = _accessToken == null ? $"{url}?token={accessToken}" : url;
+            _logger.LogInformation("Dashboard listening on {Url}", Url);
+            Console.Error.WriteLine($"CodeSearch dashboard: {Url}");
+
+            await Task.Delay(Timeout.Infinite, stoppingToken);
+        }
+        catch (OperationCanceledException) when (stoppingToken.IsCancellationRequested)
+        {
+        }
+        catch (IOException ex)
+        {
+            // Typically the port is taken by another CodeSearch session
+            _logger.LogWarning(ex, "Dashboard could not listen on {BindAddress}:{Port}", _bindAddress, _port);
+        }
+        finally
+        {
+            Url = null;
+            await app.StopAsync(CancellationToken.None);
+        }
+    }
+
+    private void MapEndpoints(WebApplication app)
+    {
+        app.MapGet("/", () => Results.Content(LoadPage(), "text/html; charset=utf-8"));
+
+        app.MapGet("/api/workspaces", () =>
+        {
+            var primary = _pathResolution.GetPrimaryWorkspacePath();
+            return Results.Json(new
+            {
+                primary,
+                workspaces = GetKnownWorkspaces().Select(w => new { path = w, isPrimary = PathEquals(w, primary) })
+            });
+        });
+
+        app.MapGet("/api/health", async (string? workspace, CancellationToken ct) =>
+        {
+            var workspacePath = ResolveWorkspace(workspace);
+            if (workspacePath == null)
+                return Results.NotFound(new { error = $"Unknown workspace '{workspace}'" });
+
+            var health = await _luceneIndexService.GetHealthAsync(workspacePath, ct);
+            IndexStatistics? statistics = null;
+            if (health.Level != IndexHealthStatus.HealthLevel.Missing)
+            {
+                statistics = await _luceneIndexService.GetStatisticsAsync(workspacePath, ct);
+            }
+
+            var hasSymbols = _sqliteService.DatabaseExists(workspacePath);
+            return Results.Json(new
+            {
+                workspace = workspacePath,
+                health = new
+                {
+                    level = health.Level.ToString(),
+                    health.Description,
+                    health.DocumentCount,
+                    health.IndexSizeBytes,
+                    health.LastModified,
+                    health.IsLocked,
+                    health.Issues
+                },
+                statistics = statistics == null ? null : new
+                {
+                    statistics.DocumentCount,
+                    statistics.DeletedDocumentCount,
+                    statistics.SegmentCount,
+                    statistics.LastCommit,
+                    statistics.FileTypeDistribution
+                },
+                symbols = new
+                {
+                    available = hasSymbols,
+                    files = hasSymbols ? await _sqliteService.GetFileCountAsync(workspacePath, ct) : 0,
+                    symbols = hasSymbols ? await _sqliteService.GetSymbolCountAsync(workspacePath, ct) : 0
+                }
+            });
+        });
+
+        app.MapGet("/api/search", async (string? q, string? type, int? max, string? workspace, CancellationToken ct) =>
+        {
+            var workspacePath = ResolveWorkspace(workspace);
+            if (workspacePath == null)
+                return Results.NotFound(new { error = $"Unknown workspace '{workspace}'" });
+            if (string.IsNullOrWhiteSpace(q))
+                return Results.BadRequest(new { error = "Query 'q' is required" });
+
+            var query = _queryPreprocessor.BuildQuery(q, string.IsNullOrWhiteSpace(type) ? "standard" : type, false, _codeAnalyzer);
+            var result = await _luceneIndexService.SearchAsync(workspacePath, query, Math.Clamp(max ?? 50, 1, MaxResults), true, ct);
+            return Results.Json(new
+            {
+                result.TotalHits,
+                result.ProcessingTimeMs,
+                hits = result.Hits.Select(h => new
+                {
+                    path = ToRelative(workspacePath, h.FilePath),
+                    h.Score,
+                    line = h.LineNumber,
+                    snippet = h.Snippet ?? h.HighlightedFragments?.FirstOrDefault()
+                })
+            });
+        });
+
+        app.MapGet("/api/symbols", async (string? name, string? workspace, CancellationToken ct) =>
+        {
+            var workspacePath = ResolveWorkspace(workspace);
+            if (workspacePath == null)
+                return Results.NotFound(new { error = $"Unknown workspace '{workspace}'" });
+            if (string.IsNullOrWhiteSpace(name))
+                return Results.BadRequest(new { error = "Symbol 'name' is required" });
+            if (!_sqliteService.DatabaseExists(workspacePath))
+                return Results.Json(new { symbols = Array.Empty<object>(), error = "Symbol database not available - run index_workspace" });
+
+            var symbols = await _sqliteService.GetSymbolsByNameAsync(workspacePath, name.Trim(), caseSensitive: false, ct);
+            return Results.Json(new { symbols = symbols.Take(MaxResults).Select(s => ToSymbolView(workspacePath, s)) });
+        });
+
+        app.MapGet("/api/file", async (string? path, string? workspace, CancellationToken ct) =>
+        {
+            var workspacePath = ResolveWorkspace(workspace);
+            var fullPath = workspacePath == null ? null : ResolveFile(workspacePath, path);
+            if (workspacePath == null || fullPath == null)
+                return Results.NotFound(new { error = "Unknown workspace or file outside the workspace" });
+            if (!_sqliteService.DatabaseExists(workspacePath))
+                return Results.Json(new { symbols = Array.Empty<object>(), error = "Symbol database not available - run index_workspace" });
+
+            var symbols = await _sqliteService.GetSymbolsForFileAsync(workspacePath, fullPath, ct) ?? new();
+            return Results.Json(new
+            {
+                path = ToRelative(workspacePath, fullPath),
+                symbols = symbols.OrderBy(s => s.StartLine).Select(s => ToSymbolView(workspacePath, s))
+            });
+        });
+
+        app.MapGet("/api/findings", async (string? path, string? workspace, CancellationToken ct) =>
+        {
+            var workspacePath = ResolveWorkspace(workspace);
+            var fullPath = workspacePath == null ? null : ResolveFile(workspacePath, path);
+            if (workspacePath == null || fullPath == null || !File.Exists(fullPath))
+                return Results.NotFound(new { error = "Unknown workspace or file outside the workspace" });
+
+            using var scope = _serviceProvider.CreateScope();
+            var tool = scope.ServiceProvider.GetRequiredService<FindPatternsTool>();
+            var response = await tool.ExecuteAsync(new FindPatternsParameters { FilePath = fullPath, MaxResults = MaxResults }, ct);
+            if (response.Success != true || response.Data?.Results == null)
+                return Results.Json(new { findings = Array.Empty<object>(), error = response.Error?.Message });
+
+            return Results.Json(new
+            {
+                path = ToRelative(workspacePath, fullPath),
+                findings = response.Data.Results.PatternsFound.Select(p => new
+                {
+                    p.Type,
+                    p.Severity,
+                    p.Message,
+                    line = p.LineNumber,
+                    code = p.LineContent
+                })
+            });
+        });
+    }
+
+    private static bool IsAuthorized(HttpRequest request, string accessToken)
+    {
+        var supplied = request.Headers[TokenHeader].FirstOrDefault() ?? request.Query["token"].FirstOrDefault();
+        return supplied != null &&
+               CryptographicOperations.FixedTimeEquals(Encoding.UTF8.GetBytes(supplied), Encoding.UTF8.GetBytes(accessToken));
+    }
+
+    private bool IsLoopbackHost(HostString host)
+    {
+        if ((host.Port ?? 80) != _port)
+            return false;
+
+        var name = host.Host.Trim('[', ']');
+        return name.Equals("localhost", StringComparison.OrdinalIgnoreCase)
+               || (IPAddress.TryParse(name, out var address) && IPAddress.IsLoopback(address));
+    }
+
+    private List<string> GetKnownWorkspaces()
+    {
+        var workspaces = new List<string> { _pathResolution.GetPrimaryWorkspacePath() };
+        workspaces.AddRange(_retentionService.GetWorkspaceIndexes()
+            .Where(i => !string.IsNullOrEmpty(i.WorkspacePath))
+            .Select(i => i.WorkspacePath!));
+
+        return workspaces.Distinct(StringComparer.OrdinalIgnoreCase).ToList();
+    }
+
+    /// <summary>
+    /// Only workspaces this server has indexed can be browsed
+    /// </summary>
+    private string? ResolveWorkspace(string? requested)
+    {
+        if (string.IsNullOrWhiteSpace(requested))
+            return _pathResolution.GetPrimaryWorkspacePath();
+
+        var fullPath = Path.GetFullPath(requested);
+        return GetKnownWorkspaces().FirstOrDefault(w => PathEquals(w, fullPath));
+    }
+
+    private static string? ResolveFile(string workspacePath, string? path)
+    {
+        if (string.IsNullOrWhiteSpace(path))
+            return null;
+
+        var fullPath = Path.GetFullPath(Path.IsPathRooted(path) ? path : Path.Combine(workspacePath, path));
+        var root = Path.TrimEndingDirectorySeparator(Path.GetFullPath(workspacePath)) + Path.DirectorySeparatorChar;
+        return fullPath.StartsWith(root, OperatingSystem.IsWindows() ? StringComparison.OrdinalIgnoreCase : StringComparison.Ordinal)
+            ? fullPath
+            : null;
+    }
+
+    private static object ToSymbolView(string workspacePath, JulieSymbol symbol) => new
+    {
+        symbol.Name,
+        symbol.Kind,
+        symbol.Language,
+        path = ToRelative(workspacePath, symbol.FilePath),
+        line = symbol.StartLine,
+        endLine = symbol.EndLine,
+        symbol.Signature,
+        symbol.Visibility
+    };
+
+    private static string ToRelative(string workspacePath, string path) =>
+        Path.IsPathRooted(path) ? Path.GetRelativePath(workspacePath, path).Replace('\\', '/') : path.Replace('\\', '/');
+
+    private static bool PathEquals(string a, string b) =>
+        string.Equals(Path.TrimEndingDirectorySeparator(a), Path.TrimEndingDirectorySeparator(b), StringComparison.OrdinalIgnoreCase);
+
+    private static string LoadPage()
+    {
+        using var stream = Assembly.GetExecutingAssembly().GetManifestResourceStream(PageResource);
+        if (stream == null)
+            return "<!doctype html><title>CodeSearch</title><p>Dashboard page not embedded in this build.</p>";
+
+        using var reader = new StreamReader(stream);
+        return reader.ReadToEnd();
+    }
+}
@@ -0,0 +1,87 @@
+namespace COA.CodeSearch.McpServer.Services.DataFlow;
+
+/// <summary>
+/// A variable written by an assignment. Member targets (<c>this.name = ...</c>) are reported but not traced further.
+/// </summary>
+public record DataFlowTarget(string Name, int Index, bool IsMember);
+
+/// <summary>
+/// An assignment on one line: its targets, where the right-hand side starts and the operator (<c>=</c>,
+/// <c>:=</c>, <c>+=</c>, ... or <c>in</c> for a for-each loop variable)
+/// </summary>
+public record DataFlowAssignment(List<DataFlowTarget> Targets, int RhsStart, string Operator);
+
+/// <summary>
+/// A call whose argument list contains a position: the callee as written, the 0-based argument and the
+/// index of the opening parenthesis
+/// </summary>
+public record DataFlowCall(string Callee, int ArgumentIndex, int OpenParen);
+
+/// <summary>
+/// One place a traced value comes from or goes to
+/// </summary>
+public class DataFlowStep
+{
+    /// <summary>
+    /// Sources: assignment, return (of a function called on an assignment's right-hand side), parameter, caller-argument, unresolved.
+    /// Sinks: call-argument, callee-parameter, assignment, return, caller-result, use.
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Variable the value is in at this step
+    /// </summary>
+    public string Variable { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Function the step is in, as "Type.Method" when the index knows the containing type
+    /// </summary>
+    public string Function { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The source line, trimmed
+    /// </summary>
+    public string Code { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Right-hand side of an assignment or the argument passed
+    /// </summary>
+    public string? Expression { get; set; }
+
+    /// <summary>
+    /// Called function for call-argument steps, or functions called on an assignment's right-hand side
+    /// </summary>
+    public string? Callee { get; set; }
+
+    public int? ArgumentIndex { get; set; }
+
+    /// <summary>
+    /// The callee has no definition in the index - a library or framework call, where the value leaves the
+    /// workspace (a query, a command, a response)
+    /// </summary>
+    public bool External { get; set; }
+
+    /// <summary>
+    /// Calls crossed from the starting function to reach this step
+    /// </summary>
+    public int Depth { get; set; }
+}
+
+/// <summary>
+/// Where a variable's value comes from and where it goes
+/// </summary>
+public class DataFlowTrace
+{
+    public string Variable { get; set; } = string.Empty;
+    public string Function { get; set; } = string.Empty;
+    public List<DataFlowStep> Sources { get; set; } = new();
+    public List<DataFlowStep> Sinks { get; set; } = new();
+
+    /// <summary>
+    /// The configured step limit stopped the trace before it was complete
+    /// </summary>
+    public bool Truncated { get; set; }
+}
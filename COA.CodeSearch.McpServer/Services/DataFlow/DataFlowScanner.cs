@@ -0,0 +1,537 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Services.DataFlow;
+
+/// <summary>
+/// The line-level patterns data-flow tracing is built on: masking literals and comments, finding assignments,
+/// call arguments and parameter names. Text-based and language-agnostic, so when unsure it reports a flow
+/// rather than hiding one.
+/// </summary>
+public static class DataFlowScanner
+{
+    private static readonly HashSet<string> HashCommentExtensions = new(StringComparer.OrdinalIgnoreCase)
+    {
+        ".py", ".rb", ".sh", ".bash", ".pl", ".r", ".ps1", ".ex", ".exs"
+    };
+
+    // Rust and OCaml use ' for lifetimes and type variables, not only for character literals
+    private static readonly HashSet<string> NoApostropheStringExtensions = new(StringComparer.OrdinalIgnoreCase) { ".rs", ".ml" };
+
+    private static readonly HashSet<string> NonCallKeywords = new(StringComparer.Ordinal)
+    {
+        "if", "while", "for", "foreach", "switch", "catch", "using", "lock", "return", "when", "elif", "until", "with", "fixed", "sizeof", "typeof", "nameof"
+    };
+
+    private static readonly HashSet<string> Receivers = new(StringComparer.Ordinal) { "self", "cls", "this" };
+
+    private static readonly Regex Identifier = new(UnicodeIdentifiers.IdentifierPattern, RegexOptions.Compiled);
+
+    private static readonly Regex ForEach = new(
+        @"\bfor(?:each)?\s*\(?\s*(?:(?:var|let|const|final|auto|val|mut)\s+)?(?:[\w$<>\[\]?,.]+\s+)?(?<target>[\p{L}_$][\w$]*)\s+(?:in|of)\s+(?<rhs>\S)",
+        RegexOptions.Compiled);
+
+    private static readonly Regex ReturnPrefix = new(@"^\s*(?:(?:return|yield)\b|=>)", RegexOptions.Compiled);
+
+    private static readonly Regex NamedArgumentPrefix = new(@"^\s*(?<name>[\p{L}_$][\w$]*)\s*(?::(?![:=])|=(?![=>]))", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Blanks the contents of string and character literals and any trailing comment, keeping the length so
+    /// indexes still match the original line. Interpolation holes (<c>$"{name}"</c>, <c>f"{name}"</c>,
+    /// <c>`${name}`</c>) stay visible, since values flow into the string through them.
+    /// </summary>
+    public static string Mask(string line, string extension)
+    {
+        var hashComments = HashCommentExtensions.Contains(extension);
+        var apostropheStrings = !NoApostropheStringExtensions.Contains(extension);
+        var chars = line.ToCharArray();
+        var quote = '\0';
+        var interpolated = false;
+        var hole = 0;
+
+        for (var i = 0; i < chars.Length; i++)
+        {
+            var c = chars[i];
+            if (quote != '\0')
+            {
+                if (hole > 0)
+                {
+                    if (c == '{') hole++;
+                    else if (c == '}') hole--;
+                    continue;
+                }
+                if (interpolated && c == '{' && (i + 1 >= chars.Length || chars[i + 1] != '{'))
+                {
+                    hole = 1;
+                    continue;
+                }
+                if (c == '\\' && i + 1 < chars.Length)
+                {
+                    chars[i] = ' ';
+                    chars[++i] = ' ';
+                    continue;
+                }
+                if (c == quote)
+                {
+                    quote = '\0';
+                    continue;
+                }
+                chars[i] = ' ';
+                continue;
+            }
+
+            if (c == '"' || c == '`' || c == '\'' && apostropheStrings)
+            {
+                quote = c;
+                var prefix = i > 0 ? chars[i - 1] : ' ';
+                interpolated = c == '`' || prefix is '$' or 'f' or 'F' || i > 1 && prefix == '@' && chars[i - 2] == '$';
+                continue;
+            }
+
+            if (c == '/' && i + 1 < chars.Length && chars[i + 1] == '/' || c == '#' && hashComments || c == '-' && i + 1 < chars.Length && chars[i + 1] == '-' && extension.Equals(".sql", StringComparison.OrdinalIgnoreCase))
+            {
+                for (var j = i; j < chars.Length; j++)
+                    chars[j] = ' ';
+                break;
+            }
+        }
+
+        return new string(chars);
+    }
+
+    /// <summary>
+    /// <see cref="Mask"/> for a whole file, also blanking <c>/* ... */</c> comments that span lines
+    /// </summary>
+    public static string[] MaskLines(IReadOnlyList<string> lines, string extension)
+    {
+        var masked = new string[lines.Count];
+        var inComment = false;
+        for (var i = 0; i < lines.Count; i++)
+        {
+            var line = lines[i];
+            var offset = 0;
+            if (inComment)
+            {
+                var close = line.IndexOf("*/", StringComparison.Ordinal);
+                if (close < 0)
+                {
+                    masked[i] = new string(' ', line.Length);
+                    continue;
+                }
+                offset = close + 2;
+                inComment = false;
+            }
+
+            var text = new string(' ', offset) + Mask(line[offset..], extension);
+            var open = text.IndexOf("/*", offset, StringComparison.Ordinal);
+            if (open >= 0 && !HashCommentExtensions.Contains(extension))
+            {
+                var close = text.IndexOf("*/", open + 2, StringComparison.Ordinal);
+                var end = close < 0 ? text.Length : close + 2;
+                inComment = close < 0;
+                text = text[..open] + new string(' ', end - open) + text[end..];
+            }
+            masked[i] = text;
+        }
+        return masked;
+    }
+
+    /// <summary>
+    /// The assignment on a masked line: the first <c>=</c>, <c>:=</c> or compound assignment outside brackets,
+    /// or a for-each loop variable. Null for comparisons, lambdas, default parameters and named arguments.
+    /// </summary>
+    public static DataFlowAssignment? FindAssignment(string masked)
+    {
+        var depth = 0;
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var c = masked[i];
+            if (c is '(' or '[' or '{')
+            {
+                depth++;
+                continue;
+            }
+            if (c is ')' or ']' or '}')
+            {
+                depth--;
+                continue;
+            }
+            if (c != '=' || depth != 0)
+                continue;
+
+            var next = i + 1 < masked.Length ? masked[i + 1] : ' ';
+            if (next is '=' or '>')
+            {
+                while (i + 1 < masked.Length && masked[i + 1] is '=' or '>') i++;
+                continue;
+            }
+
+            var prev = i > 0 ? masked[i - 1] : ' ';
+            if (prev is '!' or '<' or '>' or '=')
+                continue;
+
+            var opStart = i;
+            if (prev is ':' or '+' or '-' or '*' or '/' or '%' or '|' or '&' or '^' or '.' or '?')
+            {
+                opStart = i - 1;
+                if (i > 1 && masked[i - 2] == prev && prev is '?' or '|' or '&' or '*' or '/')
+                    opStart = i - 2;
+            }
+
+            var targets = Targets(masked[..opStart]);
+            return targets.Count == 0
+                ? null
+                : new DataFlowAssignment(targets, i + 1, masked[opStart..(i + 1)]);
+        }
+
+        var loop = ForEach.Match(masked);
+        return loop.Success
+            ? new DataFlowAssignment(new List<DataFlowTarget> { new(loop.Groups["target"].Value, loop.Groups["target"].Index, false) },
+                loop.Groups["rhs"].Index, "in")
+            : null;
+    }
+
+    /// <summary>
+    /// Indexes where <paramref name="name"/> occurs as a whole identifier that is not a member access (<c>x.name</c>)
+    /// </summary>
+    public static List<int> FindOccurrences(string masked, string name)
+    {
+        var result = new List<int>();
+        for (var index = masked.IndexOf(name, StringComparison.Ordinal); index >= 0; index = masked.IndexOf(name, index + 1, StringComparison.Ordinal))
+        {
+            var end = index + name.Length;
+            if (index > 0 && (UnicodeIdentifiers.IsIdentifierPart(masked[index - 1]) || masked[index - 1] is '.' or '$' && !IsPhpVariable(masked, index)))
+                continue;
+            if (end < masked.Length && UnicodeIdentifiers.IsIdentifierPart(masked[end]))
+                continue;
+            result.Add(index);
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The innermost call whose argument list contains <paramref name="index"/>: the callee as written
+    /// (<c>db.Exec</c>), the 0-based argument and the index of the opening parenthesis. Null outside calls and
+    /// inside control-flow parentheses such as <c>if (...)</c>.
+    /// </summary>
+    public static DataFlowCall? EnclosingCall(string masked, int index)
+    {
+        var depth = 0;
+        var commas = 0;
+        for (var i = index - 1; i >= 0; i--)
+        {
+            var c = masked[i];
+            if (c is ')' or ']' or '}')
+            {
+                depth++;
+            }
+            else if (c is '[' or '{')
+            {
+                if (depth == 0)
+                    commas = 0;
+                else
+                    depth--;
+            }
+            else if (c == '(')
+            {
+                if (depth > 0)
+                {
+                    depth--;
+                    continue;
+                }
+
+                var callee = CalleeBefore(masked, i);
+                if (callee == null)
+                {
+                    commas = 0;
+                    continue;
+                }
+                return NonCallKeywords.Contains(callee) ? null : new DataFlowCall(callee, commas, i);
+            }
+            else if (c == ',' && depth == 0)
+            {
+                commas++;
+            }
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Spans of the top-level arguments of the call whose '(' is at <paramref name="openParen"/>; null when
+    /// the closing parenthesis is not in <paramref name="masked"/>
+    /// </summary>
+    public static List<(int Start, int End)>? SplitArguments(string masked, int openParen)
+    {
+        var spans = new List<(int Start, int End)>();
+        var depth = 0;
+        var start = openParen + 1;
+        for (var i = openParen + 1; i < masked.Length; i++)
+        {
+            var c = masked[i];
+            if (c is '(' or '[' or '{')
+            {
+                depth++;
+            }
+            else if (c is ')' or ']' or '}')
+            {
+                if (depth == 0)
+                {
+                    if (c != ')')
+                        return null;
+                    if (masked[start..i].Trim().Length > 0 || spans.Count > 0)
+                        spans.Add((start, i));
+                    return spans;
+                }
+                depth--;
+            }
+            else if (c == ',' && depth == 0)
+            {
+                spans.Add((start, i));
+                start = i + 1;
+            }
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// Parameter names of a declaration, from the parameter list after <paramref name="functionName"/> in
+    /// its (masked) declaration text. Receivers (self, cls, Go method receivers) are left out.
+    /// </summary>
+    public static List<string> ParameterNames(string maskedDeclaration, string functionName, string extension)
+    {
+        var match = Regex.Match(maskedDeclaration, $@"(?<![\w$]){Regex.Escape(functionName)}\s*(?:<[^()]*?>|\[[^()]*?\])?\s*\(");
+        if (!match.Success)
+            return new List<string>();
+
+        var spans = SplitArguments(maskedDeclaration, match.Index + match.Length - 1);
+        if (spans == null)
+            return new List<string>();
+
+        var go = extension.Equals(".go", StringComparison.OrdinalIgnoreCase);
+        var names = new List<string>();
+        foreach (var text in SplitParameters(spans.Count == 0 ? string.Empty : maskedDeclaration[spans[0].Start..spans[^1].End]))
+        {
+            var parameter = text;
+            var defaultValue = TopLevelIndexOf(parameter, '=');
+            if (defaultValue >= 0)
+                parameter = parameter[..defaultValue];
+            parameter = Regex.Replace(parameter, @"\[[^\]]*\]\s*(?=\S)|@\w+(?:\([^)]*\))?", " ");
+
+            var colon = TopLevelAnnotationColon(parameter);
+            var identifiers = Identifier.Matches(colon >= 0 ? parameter[..colon] : parameter).Select(m => m.Value).ToList();
+            if (identifiers.Count == 0)
+                continue;
+
+            var name = go && colon < 0 ? identifiers[0] : identifiers[^1];
+            if (names.Count == 0 && Receivers.Contains(name))
+                continue;
+            names.Add(name);
+        }
+        return names;
+    }
+
+    /// <summary>
+    /// Identifiers in a span of a masked line that could be local variables: not members (<c>x.name</c>)
+    /// and not called (<c>name(</c>)
+    /// </summary>
+    public static List<string> VariablesIn(string masked, int start, int end)
+    {
+        var result = new List<string>();
+        foreach (Match match in Identifier.Matches(masked[start..end]))
+        {
+            var index = start + match.Index;
+            if (index > 0 && masked[index - 1] == '.')
+                continue;
+            var after = index + match.Length;
+            while (after < masked.Length && masked[after] == ' ') after++;
+            if (after < masked.Length && masked[after] == '(')
+                continue;
+            if (!result.Contains(match.Value))
+                result.Add(match.Value);
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// Names of the functions called in a span of a masked line
+    /// </summary>
+    public static List<string> CallsIn(string masked, int start, int end)
+    {
+        var result = new List<string>();
+        for (var i = start; i < end && i < masked.Length; i++)
+        {
+            if (masked[i] != '(')
+                continue;
+            var callee = CalleeBefore(masked, i);
+            if (callee != null && !NonCallKeywords.Contains(callee) && !result.Contains(callee))
+                result.Add(callee);
+        }
+        return result;
+    }
+
+    /// <summary>
+    /// The parameter name of a named argument (<c>name: value</c>, <c>name=value</c>) and where its value starts;
+    /// null for a positional argument
+    /// </summary>
+    public static string? NamedArgument(string maskedArgument, out int valueStart)
+    {
+        var match = NamedArgumentPrefix.Match(maskedArgument);
+        valueStart = match.Success ? match.Length : 0;
+        return match.Success ? match.Groups["name"].Value : null;
+    }
+
+    /// <summary>
+    /// Where the returned expression starts on a <c>return</c>, <c>yield</c> or expression-bodied (<c>=&gt;</c>)
+    /// line; -1 for other lines
+    /// </summary>
+    public static int ReturnValueStart(string masked)
+    {
+        var match = ReturnPrefix.Match(masked);
+        return match.Success ? match.Length : -1;
+    }
+
+    /// <summary>
+    /// The last segment of a callee as written: <c>Exec</c> for <c>db.Exec</c>, <c>query</c> for <c>Repo::query</c>
+    /// </summary>
+    public static string LastSegment(string callee)
+    {
+        var index = callee.LastIndexOfAny(new[] { '.', ':', '>' });
+        return index < 0 ? callee : callee[(index + 1)..];
+    }
+
+    // Unlike call arguments, parameter lists can hold generic types with commas: Map<K, V> name
+    private static IEnumerable<string> SplitParameters(string list)
+    {
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i < list.Length; i++)
+        {
+            var c = list[i];
+            if (c is '(' or '[' or '{' or '<')
+                depth++;
+            else if (c is ')' or ']' or '}' or '>' && !(c == '>' && i > 0 && list[i - 1] is '-' or '='))
+                depth = Math.Max(0, depth - 1);
+            else if (c == ',' && depth == 0)
+            {
+                yield return list[start..i];
+                start = i + 1;
+            }
+        }
+        if (list[start..].Trim().Length > 0)
+            yield return list[start..];
+    }
+
+    private static List<DataFlowTarget> Targets(string lhs)
+    {
+        var targets = new List<DataFlowTarget>();
+        var depth = 0;
+        var start = 0;
+        for (var i = 0; i <= lhs.Length; i++)
+        {
+            var c = i < lhs.Length ? lhs[i] : ',';
+            if (c is '(' or '[' or '{' or '<')
+                depth++;
+            else if (c is ')' or ']' or '}' or '>')
+                depth = Math.Max(0, depth - 1);
+            else if (c == ',' && depth == 0)
+            {
+                if (TargetIn(lhs, start, i) is { } target)
+                    targets.Add(target);
+                start = i + 1;
+            }
+        }
+        return targets;
+    }
+
+    private static DataFlowTarget? TargetIn(string lhs, int start, int end)
+    {
+        var part = lhs[start..end];
+        var colon = TopLevelAnnotationColon(part);
+        if (colon >= 0)
+            part = part[..colon];
+
+        // a[i] = v assigns into a
+        var bracket = part.IndexOf('[');
+        if (bracket > 0 && part.TrimEnd().EndsWith(']'))
+            part = part[..bracket];
+
+        var identifiers = Identifier.Matches(part);
+        if (identifiers.Count == 0)
+            return null;
+
+        var last = identifiers[^1];
+        var index = start + last.Index;
+        var isMember = last.Index > 0 && part[last.Index - 1] == '.';
+        return new DataFlowTarget(last.Value, index, isMember);
+    }
+
+    private static string? CalleeBefore(string masked, int openParen)
+    {
+        var end = openParen;
+        while (end > 0 && masked[end - 1] == ' ') end--;
+
+        // Generic arguments: Query<User>(...)
+        if (end > 0 && masked[end - 1] == '>')
+        {
+            var depth = 0;
+            for (var i = end - 1; i >= 0; i--)
+            {
+                if (masked[i] == '>') depth++;
+                else if (masked[i] == '<' && --depth == 0)
+                {
+                    end = i;
+                    break;
+                }
+            }
+        }
+
+        var start = end;
+        while (start > 0 && (UnicodeIdentifiers.IsIdentifierPart(masked[start - 1]) || masked[start - 1] is '.' or ':' or '$'
+                             || masked[start - 1] == '>' && start > 1 && masked[start - 2] == '-'
+                             || masked[start - 1] == '-' && start < masked.Length && masked[start] == '>'))
+        {
+            start--;
+        }
+
+        var callee = masked[start..end].Trim('.', ':', '$');
+        return callee.Length == 0 || !UnicodeIdentifiers.IsIdentifierPart(callee[^1]) ? null : callee;
+    }
+
+    private static int TopLevelIndexOf(string text, char target)
+    {
+        var depth = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '(' or '[' or '{' or '<') depth++;
+            else if (c is ')' or ']' or '}' or '>') depth = Math.Max(0, depth - 1);
+            else if (c == target && depth == 0 && (target != '=' || (i + 1 >= text.Length || text[i + 1] != '=') && (i == 0 || text[i - 1] is not ('=' or '!' or '<' or '>'))))
+                return i;
+        }
+        return -1;
+    }
+
+    // "name: Type" annotations; "::" is a path separator, not an annotation
+    private static int TopLevelAnnotationColon(string text)
+    {
+        var depth = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '(' or '[' or '{' or '<') depth++;
+            else if (c is ')' or ']' or '}' or '>') depth = Math.Max(0, depth - 1);
+            else if (c == ':' && depth == 0)
+            {
+                if (i + 1 < text.Length && text[i + 1] == ':' || i > 0 && text[i - 1] == ':')
+                {
+                    i++;
+                    continue;
+                }
+                return i;
+            }
+        }
+        return -1;
+    }
+
+    private static bool IsPhpVariable(string masked, int index) => masked[index - 1] == '$';
+}
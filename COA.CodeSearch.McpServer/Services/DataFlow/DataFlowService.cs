@@ -0,0 +1,579 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.DataFlow;
+
+/// <summary>
+/// Text-based data-flow tracing over the indexed functions. Inside a function it follows assignments between
+/// local variables; across functions it maps arguments to parameters using the index's call sites and
+/// definitions. Fields, collections, aliasing and closures are not modelled, so the trace is an approximation
+/// that leans towards reporting too much.
+/// </summary>
+public class DataFlowService : IDataFlowService
+{
+    private static readonly string[] CallableKinds = { "method", "function", "constructor" };
+    private const int MaxCallSites = 25;
+    private const int MaxDefinitions = 3;
+    private const int MaxDeclarationLines = 15;
+    private const int MaxStatementLines = 8;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<DataFlowService> _logger;
+    private readonly int _maxSteps;
+
+    public DataFlowService(ISQLiteSymbolService sqliteService, IConfiguration configuration, ILogger<DataFlowService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+        _maxSteps = configuration.GetValue("CodeSearch:DataFlow:MaxSteps", 300);
+    }
+
+    public async Task<DataFlowTrace?> TraceAsync(string workspacePath, string filePath, int line, string variable,
+        bool sources, bool sinks, int maxDepth, CancellationToken cancellationToken = default)
+    {
+        var tracer = new Tracer(_sqliteService, workspacePath, maxDepth, _maxSteps, cancellationToken);
+        var scope = await tracer.ScopeAtAsync(filePath, line);
+        if (scope == null)
+            return null;
+
+        tracer.Trace.Variable = variable;
+        tracer.Trace.Function = scope.Name;
+        if (sources)
+            await tracer.BackwardAsync(scope, variable, 0);
+        if (sinks)
+            await tracer.ForwardAsync(scope, variable, 0);
+
+        _logger.LogDebug("Data flow for {Variable} in {Function}: {Sources} sources, {Sinks} sinks{Truncated}",
+            variable, scope.Name, tracer.Trace.Sources.Count, tracer.Trace.Sinks.Count, tracer.Trace.Truncated ? " (truncated)" : "");
+        return tracer.Trace;
+    }
+
+    /// <summary>
+    /// An indexed function with its file's lines, original and masked
+    /// </summary>
+    private sealed class FunctionScope
+    {
+        public FunctionScope(JulieSymbol symbol, string name, string fullPath, string[] lines, string[] masked)
+        {
+            Symbol = symbol;
+            Name = name;
+            FullPath = fullPath;
+            Lines = lines;
+            Masked = masked;
+            Extension = Path.GetExtension(fullPath);
+        }
+
+        public JulieSymbol Symbol { get; }
+        public string Name { get; }
+        public string FullPath { get; }
+        public string Extension { get; }
+        public string[] Lines { get; }
+        public string[] Masked { get; }
+        public List<string>? Parameters { get; set; }
+
+        /// <summary>
+        /// 0-based indexes of the function's lines
+        /// </summary>
+        public IEnumerable<int> LineIndexes =>
+            Enumerable.Range(Math.Max(0, Symbol.StartLine - 1), Math.Max(0, Math.Min(Symbol.EndLine, Lines.Length) - Math.Max(0, Symbol.StartLine - 1)));
+    }
+
+    /// <summary>
+    /// A call to a traced function: the caller, the statement text starting at the call's line and the argument spans
+    /// </summary>
+    private sealed record CallSite(FunctionScope Caller, int Line, string Statement, string MaskedStatement, int NameIndex,
+        List<(int Start, int End)> Arguments);
+
+    /// <summary>
+    /// State for one trace: file and symbol caches, visited (function, variable) pairs and the collected steps
+    /// </summary>
+    private sealed class Tracer
+    {
+        private readonly ISQLiteSymbolService _sqliteService;
+        private readonly string _workspacePath;
+        private readonly int _maxDepth;
+        private readonly int _maxSteps;
+        private readonly CancellationToken _cancellationToken;
+        private readonly Dictionary<string, (string[] Lines, string[] Masked)?> _files = new(StringComparer.OrdinalIgnoreCase);
+        private readonly Dictionary<string, List<JulieSymbol>> _fileSymbols = new(StringComparer.OrdinalIgnoreCase);
+        private readonly Dictionary<string, FunctionScope> _scopes = new();
+        private readonly Dictionary<string, List<CallSite>> _callSites = new();
+        private readonly Dictionary<string, List<JulieSymbol>> _definitions = new();
+        private readonly HashSet<string> _visited = new();
+        private readonly HashSet<string> _stepKeys = new();
+
+        public Tracer(ISQLiteSymbolService sqliteService, string workspacePath, int maxDepth, int maxSteps, CancellationToken cancellationToken)
+        {
+            _sqliteService = sqliteService;
+            _workspacePath = workspacePath;
+            _maxDepth = maxDepth;
+            _maxSteps = maxSteps;
+            _cancellationToken = cancellationToken;
+        }
+
+        public DataFlowTrace Trace { get; } = new();
+
+        private bool Full => Trace.Sources.Count + Trace.Sinks.Count >= _maxSteps;
+
+        /// <summary>
+        /// Follows where the variable's value comes from: its assignments, the locals they read and the values
+        /// returned by the functions they call, and for a parameter the arguments callers pass
+        /// </summary>
+        public async Task BackwardAsync(FunctionScope scope, string variable, int depth)
+        {
+            if (Full || !_visited.Add($"<|{scope.Symbol.Id}|{variable}"))
+                return;
+            _cancellationToken.ThrowIfCancellationRequested();
+
+            var found = false;
+            foreach (var line in scope.LineIndexes)
+            {
+                var masked = scope.Masked[line];
+                var assignment = DataFlowScanner.FindAssignment(masked);
+                if (assignment == null || !assignment.Targets.Any(t => t.Name == variable && !t.IsMember))
+                    continue;
+
+                found = true;
+                var calls = DataFlowScanner.CallsIn(masked, assignment.RhsStart, masked.Length);
+                Add(Trace.Sources, new DataFlowStep
+                {
+                    Kind = "assignment",
+                    Expression = Clean(scope.Lines[line][assignment.RhsStart..]),
+                    Callee = calls.Count > 0 ? string.Join(", ", calls) : null
+                }, scope, line, variable, depth);
+
+                foreach (var local in DataFlowScanner.VariablesIn(masked, assignment.RhsStart, masked.Length))
+                {
+                    if (local != variable && IsLocal(scope, local))
+                        await BackwardAsync(scope, local, depth);
+                }
+
+                if (depth < _maxDepth)
+                {
+                    foreach (var callee in calls)
+                    {
+                        foreach (var definition in await DefinitionsAsync(DataFlowScanner.LastSegment(callee), scope))
+                        {
+                            if (await ScopeForAsync(definition) is { } calleeScope && calleeScope.Symbol.Id != scope.Symbol.Id)
+                                await ReturnSourcesAsync(calleeScope, depth + 1);
+                        }
+                    }
+                }
+            }
+
+            var parameters = Parameters(scope);
+            var index = parameters.IndexOf(variable);
+            if (index >= 0)
+            {
+                found = true;
+                Add(Trace.Sources, new DataFlowStep { Kind = "parameter", ArgumentIndex = index }, scope, scope.Symbol.StartLine - 1, variable, depth);
+
+                if (depth < _maxDepth)
+                {
+                    foreach (var site in await CallSitesAsync(scope))
+                    {
+                        if (Full)
+                            break;
+                        if (ArgumentFor(site, parameters, index) is not { } argument)
+                            continue;
+
+                        Add(Trace.Sources, new DataFlowStep
+                        {
+                            Kind = "caller-argument",
+                            Expression = Clean(site.Statement[argument.ValueStart..argument.End]),
+                            Callee = scope.Name,
+                            ArgumentIndex = index
+                        }, site.Caller, site.Line, variable, depth + 1);
+
+                        foreach (var local in DataFlowScanner.VariablesIn(site.MaskedStatement, argument.ValueStart, argument.End))
+                        {
+                            if (IsLocal(site.Caller, local))
+                                await BackwardAsync(site.Caller, local, depth + 1);
+                        }
+                    }
+                }
+            }
+
+            if (!found)
+            {
+                var first = scope.LineIndexes.FirstOrDefault(l => DataFlowScanner.FindOccurrences(scope.Masked[l], variable).Count > 0, scope.Symbol.StartLine - 1);
+                Add(Trace.Sources, new DataFlowStep
+                {
+                    Kind = "unresolved",
+                    Expression = $"not assigned in {scope.Name}: a field, global, closure variable or pattern binding"
+                }, scope, first, variable, depth);
+            }
+        }
+
+        /// <summary>
+        /// The values a called function returns, traced backwards inside it
+        /// </summary>
+        private async Task ReturnSourcesAsync(FunctionScope scope, int depth)
+        {
+            if (Full || !_visited.Add($"return|{scope.Symbol.Id}"))
+                return;
+
+            foreach (var line in scope.LineIndexes)
+            {
+                var masked = scope.Masked[line];
+                var start = DataFlowScanner.ReturnValueStart(masked);
+                if (start < 0 || masked[start..].Trim().Length == 0)
+                    continue;
+
+                var calls = DataFlowScanner.CallsIn(masked, start, masked.Length);
+                Add(Trace.Sources, new DataFlowStep
+                {
+                    Kind = "return",
+                    Expression = Clean(scope.Lines[line][start..]),
+                    Callee = calls.Count > 0 ? string.Join(", ", calls) : null
+                }, scope, line, string.Empty, depth);
+
+                foreach (var local in DataFlowScanner.VariablesIn(masked, start, masked.Length))
+                {
+                    if (IsLocal(scope, local))
+                        await BackwardAsync(scope, local, depth);
+                }
+            }
+        }
+
+        /// <summary>
+        /// Follows where the variable's value goes: calls it is passed to (and on into the callee's parameter),
+        /// variables assigned from it, and returns (on into the callers' result variables)
+        /// </summary>
+        public async Task ForwardAsync(FunctionScope scope, string variable, int depth)
+        {
+            if (Full || !_visited.Add($">|{scope.Symbol.Id}|{variable}"))
+                return;
+            _cancellationToken.ThrowIfCancellationRequested();
+
+            var declarationLine = scope.Symbol.StartLine - 1;
+            foreach (var line in scope.LineIndexes)
+            {
+                if (Full)
+                    return;
+
+                var masked = scope.Masked[line];
+                var assignment = DataFlowScanner.FindAssignment(masked);
+                var occurrence = DataFlowScanner.FindOccurrences(masked, variable)
+                    .Where(i => assignment == null || assignment.Targets.All(t => t.Index != i))
+                    .DefaultIfEmpty(-1)
+                    .First();
+                if (occurrence < 0 || line == declarationLine && Parameters(scope).Contains(variable))
+                    continue;
+
+                // Locals assigned on the line first, so they are traced at this depth before any callee is entered
+                var call = EnclosingCall(scope, line, occurrence);
+                var assigned = assignment != null && occurrence >= assignment.RhsStart;
+                if (assigned)
+                {
+                    foreach (var target in assignment!.Targets.Where(t => t.Name != variable && t.Name != "_"))
+                    {
+                        Add(Trace.Sinks, new DataFlowStep { Kind = "assignment", Callee = call?.Callee }, scope, line, target.Name, depth);
+                        if (!target.IsMember)
+                            await ForwardAsync(scope, target.Name, depth);
+                    }
+                }
+
+                if (call != null)
+                {
+                    await CallArgumentAsync(scope, line, variable, call, depth);
+                }
+                else if (!assigned && DataFlowScanner.ReturnValueStart(masked) < 0)
+                {
+                    Add(Trace.Sinks, new DataFlowStep { Kind = "use" }, scope, line, variable, depth);
+                }
+
+                if (!assigned && DataFlowScanner.ReturnValueStart(masked) >= 0)
+                {
+                    Add(Trace.Sinks, new DataFlowStep { Kind = "return" }, scope, line, variable, depth);
+                    if (depth < _maxDepth)
+                        await CallerResultsAsync(scope, depth);
+                }
+            }
+        }
+
+        private async Task CallArgumentAsync(FunctionScope scope, int line, string variable, DataFlowCall call, int depth)
+        {
+            var definitions = await DefinitionsAsync(DataFlowScanner.LastSegment(call.Callee), scope);
+            Add(Trace.Sinks, new DataFlowStep
+            {
+                Kind = "call-argument",
+                Callee = call.Callee,
+                ArgumentIndex = call.ArgumentIndex,
+                External = definitions.Count == 0
+            }, scope, line, variable, depth);
+
+            if (depth >= _maxDepth)
+                return;
+
+            var named = NamedArgumentAt(scope, line, call);
+            foreach (var definition in definitions)
+            {
+                var callee = await ScopeForAsync(definition);
+                if (callee == null)
+                    continue;
+
+                var parameters = Parameters(callee);
+                var parameter = named != null && parameters.Contains(named) ? named
+                    : call.ArgumentIndex < parameters.Count ? parameters[call.ArgumentIndex]
+                    : null;
+                if (parameter == null || callee.Symbol.Id == scope.Symbol.Id && parameter == variable)
+                    continue;
+
+                Add(Trace.Sinks, new DataFlowStep
+                {
+                    Kind = "callee-parameter",
+                    Callee = callee.Name,
+                    ArgumentIndex = parameters.IndexOf(parameter)
+                }, callee, callee.Symbol.StartLine - 1, parameter, depth + 1);
+                await ForwardAsync(callee, parameter, depth + 1);
+            }
+        }
+
+        /// <summary>
+        /// The variables callers assign the function's result to, traced onwards in the caller
+        /// </summary>
+        private async Task CallerResultsAsync(FunctionScope scope, int depth)
+        {
+            foreach (var site in await CallSitesAsync(scope))
+            {
+                if (Full)
+                    return;
+
+                var masked = site.Caller.Masked[site.Line];
+                var assignment = DataFlowScanner.FindAssignment(masked);
+                if (assignment == null || site.NameIndex < assignment.RhsStart)
+                {
+                    Add(Trace.Sinks, new DataFlowStep { Kind = "caller-result", Callee = scope.Name }, site.Caller, site.Line, string.Empty, depth + 1);
+                    continue;
+                }
+
+                foreach (var target in assignment.Targets.Where(t => t.Name != "_"))
+                {
+                    Add(Trace.Sinks, new DataFlowStep { Kind = "caller-result", Callee = scope.Name }, site.Caller, site.Line, target.Name, depth + 1);
+                    if (!target.IsMember)
+                        await ForwardAsync(site.Caller, target.Name, depth + 1);
+                }
+            }
+        }
+
+        /// <summary>
+        /// The call around an occurrence, looking back over earlier lines of a call that spans several
+        /// </summary>
+        private static DataFlowCall? EnclosingCall(FunctionScope scope, int line, int index)
+        {
+            var text = scope.Masked[line];
+            var offset = 0;
+            for (var previous = line; ; previous--)
+            {
+                if (DataFlowScanner.EnclosingCall(text, index + offset) is { } call)
+                    return call with { OpenParen = call.OpenParen - offset };
+
+                if (previous == line - MaxStatementLines || previous <= Math.Max(0, scope.Symbol.StartLine - 1))
+                    return null;
+                var before = scope.Masked[previous - 1];
+                if (before.TrimEnd().Length == 0 || before.TrimEnd()[^1] is ';' or '{' or '}')
+                    return null;
+                text = before + "\n" + text;
+                offset += before.Length + 1;
+            }
+        }
+
+        private static string? NamedArgumentAt(FunctionScope scope, int line, DataFlowCall call)
+        {
+            if (call.OpenParen < 0)
+                return null;
+            var masked = scope.Masked[line];
+            var spans = DataFlowScanner.SplitArguments(masked, call.OpenParen);
+            if (spans == null || call.ArgumentIndex >= spans.Count)
+                return null;
+            var span = spans[call.ArgumentIndex];
+            return DataFlowScanner.NamedArgument(masked[span.Start..span.End], out _);
+        }
+
+        /// <summary>
+        /// The argument a call site passes for the parameter at <paramref name="index"/>: a named argument for it,
+        /// else the positional one
+        /// </summary>
+        private static (int ValueStart, int End)? ArgumentFor(CallSite site, List<string> parameters, int index)
+        {
+            var positional = 0;
+            (int ValueStart, int End)? byPosition = null;
+            foreach (var (start, end) in site.Arguments)
+            {
+                var name = DataFlowScanner.NamedArgument(site.MaskedStatement[start..end], out var valueStart);
+                if (name != null)
+                {
+                    if (name == parameters[index])
+                        return (start + valueStart, end);
+                    continue;
+                }
+                if (positional++ == index)
+                    byPosition = (start, end);
+            }
+            return byPosition;
+        }
+
+        private static bool IsLocal(FunctionScope scope, string name)
+        {
+            if (Parameters(scope).Contains(name))
+                return true;
+            return scope.LineIndexes.Any(line => DataFlowScanner.FindAssignment(scope.Masked[line]) is { } assignment
+                                                 && assignment.Targets.Any(t => t.Name == name && !t.IsMember));
+        }
+
+        private static List<string> Parameters(FunctionScope scope)
+        {
+            if (scope.Parameters != null)
+                return scope.Parameters;
+
+            var start = Math.Max(0, scope.Symbol.StartLine - 1);
+            var count = Math.Min(MaxDeclarationLines, scope.Masked.Length - start);
+            var declaration = count > 0 ? string.Join("\n", scope.Masked, start, count) : string.Empty;
+            scope.Parameters = DataFlowScanner.ParameterNames(declaration, scope.Symbol.Name, scope.Extension);
+            if (scope.Parameters.Count == 0 && scope.Symbol.Signature is { Length: > 0 } signature)
+                scope.Parameters = DataFlowScanner.ParameterNames(DataFlowScanner.Mask(signature, scope.Extension), scope.Symbol.Name, scope.Extension);
+            return scope.Parameters;
+        }
+
+        /// <summary>
+        /// Calls to the function from the index's call identifiers, skipping those resolved to a different symbol
+        /// </summary>
+        private async Task<List<CallSite>> CallSitesAsync(FunctionScope scope)
+        {
+            if (_callSites.TryGetValue(scope.Symbol.Id, out var cached))
+                return cached;
+
+            var sites = new List<CallSite>();
+            var identifiers = await _sqliteService.GetIdentifiersByNameAsync(_workspacePath, scope.Symbol.Name, caseSensitive: true, _cancellationToken);
+            foreach (var identifier in identifiers
+                         .Where(i => i.Kind == "call")
+                         .Where(i => i.TargetSymbolId == null || i.TargetSymbolId == scope.Symbol.Id)
+                         .Where(i => string.IsNullOrEmpty(i.Language) || string.IsNullOrEmpty(scope.Symbol.Language) || i.Language == scope.Symbol.Language)
+                         .Take(MaxCallSites))
+            {
+                var caller = await ScopeAtAsync(identifier.FilePath, identifier.StartLine);
+                if (caller == null)
+                    continue;
+
+                var line = identifier.StartLine - 1;
+                var count = Math.Min(MaxStatementLines, caller.Lines.Length - line);
+                var statement = string.Join("\n", caller.Lines, line, count);
+                var masked = string.Join("\n", caller.Masked, line, count);
+                var call = Regex.Match(masked, $@"(?<![\w$]){Regex.Escape(scope.Symbol.Name)}\s*(?:<[^()]*?>)?\s*\(");
+                if (!call.Success || DataFlowScanner.SplitArguments(masked, call.Index + call.Length - 1) is not { } arguments)
+                    continue;
+
+                sites.Add(new CallSite(caller, line, statement, masked, call.Index, arguments));
+            }
+
+            _callSites[scope.Symbol.Id] = sites;
+            return sites;
+        }
+
+        /// <summary>
+        /// Indexed functions named <paramref name="name"/>, same-language definitions first
+        /// </summary>
+        private async Task<List<JulieSymbol>> DefinitionsAsync(string name, FunctionScope from)
+        {
+            if (_definitions.TryGetValue(name, out var cached))
+                return cached;
+
+            var definitions = (await _sqliteService.GetSymbolsByNameAsync(_workspacePath, name, caseSensitive: true, _cancellationToken))
+                .Where(s => s.Name == name && CallableKinds.Contains(s.Kind))
+                .OrderByDescending(s => s.Language == from.Symbol.Language)
+                .ThenByDescending(s => string.Equals(FullPath(s.FilePath), from.FullPath, StringComparison.OrdinalIgnoreCase))
+                .Take(MaxDefinitions)
+                .ToList();
+
+            _definitions[name] = definitions;
+            return definitions;
+        }
+
+        /// <summary>
+        /// The innermost indexed function containing the 1-based line
+        /// </summary>
+        public async Task<FunctionScope?> ScopeAtAsync(string filePath, int line)
+        {
+            var fullPath = FullPath(filePath);
+            var symbols = await SymbolsForFileAsync(fullPath);
+            var symbol = symbols
+                .Where(s => CallableKinds.Contains(s.Kind) && s.StartLine <= line && line <= s.EndLine)
+                .OrderBy(s => s.EndLine - s.StartLine)
+                .FirstOrDefault();
+            return symbol == null ? null : await ScopeForAsync(symbol);
+        }
+
+        private async Task<FunctionScope?> ScopeForAsync(JulieSymbol symbol)
+        {
+            if (_scopes.TryGetValue(symbol.Id, out var cached))
+                return cached;
+
+            var fullPath = FullPath(symbol.FilePath);
+            if (await ReadAsync(fullPath) is not { } file)
+                return null;
+
+            var parent = symbol.ParentId == null
+                ? null
+                : (await SymbolsForFileAsync(fullPath)).FirstOrDefault(s => s.Id == symbol.ParentId);
+            var name = parent != null ? $"{parent.Name}.{symbol.Name}" : symbol.Name;
+
+            var scope = new FunctionScope(symbol, name, fullPath, file.Lines, file.Masked);
+            _scopes[symbol.Id] = scope;
+            return scope;
+        }
+
+        private async Task<List<JulieSymbol>> SymbolsForFileAsync(string fullPath)
+        {
+            if (!_fileSymbols.TryGetValue(fullPath, out var symbols))
+            {
+                symbols = await _sqliteService.GetSymbolsForFileAsync(_workspacePath, fullPath, _cancellationToken) ?? new List<JulieSymbol>();
+                _fileSymbols[fullPath] = symbols;
+            }
+            return symbols;
+        }
+
+        private async Task<(string[] Lines, string[] Masked)?> ReadAsync(string fullPath)
+        {
+            if (_files.TryGetValue(fullPath, out var cached))
+                return cached;
+
+            (string[] Lines, string[] Masked)? file = null;
+            if (File.Exists(fullPath))
+            {
+                var lines = await File.ReadAllLinesAsync(fullPath, _cancellationToken);
+                file = (lines, DataFlowScanner.MaskLines(lines, Path.GetExtension(fullPath)));
+            }
+            _files[fullPath] = file;
+            return file;
+        }
+
+        private void Add(List<DataFlowStep> steps, DataFlowStep step, FunctionScope scope, int line, string variable, int depth)
+        {
+            if (Full)
+            {
+                Trace.Truncated = true;
+                return;
+            }
+            if (!_stepKeys.Add($"{(steps == Trace.Sources ? "<" : ">")}|{step.Kind}|{scope.FullPath}|{line}|{variable}"))
+                return;
+
+            step.Variable = variable;
+            step.Function = scope.Name;
+            step.FilePath = scope.FullPath;
+            step.Line = line + 1;
+            step.Code = line >= 0 && line < scope.Lines.Length ? scope.Lines[line].Trim() : string.Empty;
+            step.Depth = depth;
+            steps.Add(step);
+        }
+
+        private string FullPath(string filePath) =>
+            Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(_workspacePath, filePath));
+
+        private static string Clean(string text) =>
+            Regex.Replace(text, @"\s+", " ").Trim().TrimEnd(';', '{', ':').TrimEnd();
+    }
+}
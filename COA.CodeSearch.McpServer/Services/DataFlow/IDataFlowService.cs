@@ -0,0 +1,16 @@
+namespace COA.CodeSearch.McpServer.Services.DataFlow;
+
+/// <summary>
+/// Traces a variable's value inside its function and, best-effort, across calls: the assignments and
+/// parameters it comes from and the calls, returns and variables it flows into
+/// </summary>
+public interface IDataFlowService
+{
+    /// <summary>
+    /// Traces <paramref name="variable"/> in the function containing <paramref name="line"/> of <paramref name="filePath"/>.
+    /// </summary>
+    /// <param name="maxDepth">How many calls to follow away from the starting function; 0 stays inside it</param>
+    /// <returns>Null when the line is not inside a function the index knows</returns>
+    Task<DataFlowTrace?> TraceAsync(string workspacePath, string filePath, int line, string variable,
+        bool sources, bool sinks, int maxDepth, CancellationToken cancellationToken = default);
+}
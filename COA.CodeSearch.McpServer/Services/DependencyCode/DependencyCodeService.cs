@@ -0,0 +1,165 @@
+using System.Text.Json;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.DependencyCode;
+
+/// <summary>
+/// Keeps workspace dependency code settings in dependency-code.json beside each workspace index and matches
+/// files against their dependency directories
+/// </summary>
+public class DependencyCodeService : IDependencyCodeService
+{
+    private const string SettingsFileName = "dependency-code.json";
+
+    private static readonly string[] DefaultDirectories =
+    {
+        "vendor", "third_party", "third-party", "thirdparty", "Pods", "Carthage", "bower_components", "jspm_packages"
+    };
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILogger<DependencyCodeService> _logger;
+    private readonly DependencyCodeSettings _default;
+    private readonly object _sync = new();
+    private readonly Dictionary<string, DependencyCodeSettings?> _settings = new(StringComparer.OrdinalIgnoreCase);
+
+    public DependencyCodeService(IPathResolutionService pathResolution, IConfiguration configuration, ILogger<DependencyCodeService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        var mode = configuration.GetValue("CodeSearch:DependencyCode:Mode", DependencyCodeSettings.Include)!.Trim().ToLowerInvariant();
+        if (!DependencyCodeSettings.Modes.Contains(mode))
+        {
+            _logger.LogWarning("Unknown CodeSearch:DependencyCode:Mode '{Mode}' - using include", mode);
+            mode = DependencyCodeSettings.Include;
+        }
+
+        _default = new DependencyCodeSettings
+        {
+            Mode = mode,
+            Directories = Normalize(configuration.GetSection("CodeSearch:DependencyCode:Directories").Get<string[]>() ?? DefaultDirectories)
+        };
+    }
+
+    public DependencyCodeSettings GetSettings(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceSettings(workspacePath) ?? _default;
+        }
+    }
+
+    public bool HasWorkspaceSettings(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceSettings(workspacePath) != null;
+        }
+    }
+
+    public void SetSettings(string workspacePath, DependencyCodeSettings? settings)
+    {
+        if (settings != null)
+        {
+            settings = new DependencyCodeSettings { Mode = settings.Mode.Trim().ToLowerInvariant(), Directories = Normalize(settings.Directories) };
+        }
+
+        lock (_sync)
+        {
+            _settings[workspacePath] = settings;
+            var path = GetSettingsPath(workspacePath);
+            try
+            {
+                if (settings == null)
+                {
+                    File.Delete(path);
+                }
+                else
+                {
+                    Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+                    File.WriteAllText(path, JsonSerializer.Serialize(settings));
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogWarning(ex, "Could not save dependency code settings {Path}", path);
+            }
+        }
+    }
+
+    public string? GetDependencyDirectory(string workspacePath, string filePath)
+    {
+        var relativePath = Path.GetRelativePath(workspacePath, Path.GetFullPath(filePath, workspacePath)).Replace('\\', '/');
+        return Match(GetSettings(workspacePath).Directories, relativePath);
+    }
+
+    public bool IsExcluded(string workspacePath, string filePath) =>
+        GetSettings(workspacePath).Mode == DependencyCodeSettings.Exclude
+        && GetDependencyDirectory(workspacePath, filePath) != null;
+
+    public IReadOnlyList<string> GetIgnorePatterns(string workspacePath)
+    {
+        var settings = GetSettings(workspacePath);
+        if (settings.Mode != DependencyCodeSettings.Exclude)
+            return Array.Empty<string>();
+
+        return settings.Directories
+            .Select(d => d.Contains('/') ? $"{d}/**" : $"**/{d}/**")
+            .ToList();
+    }
+
+    /// <summary>
+    /// The dependency directory containing a relative path: the path up to and including the first directory
+    /// named by a bare entry, or the entry itself when the path lies below it
+    /// </summary>
+    internal static string? Match(IReadOnlyList<string> directories, string relativePath)
+    {
+        var segments = relativePath.Split('/');
+        for (var i = 0; i < segments.Length - 1; i++)
+        {
+            foreach (var directory in directories)
+            {
+                if (directory.Contains('/'))
+                {
+                    if (relativePath.StartsWith(directory + "/", StringComparison.OrdinalIgnoreCase))
+                        return directory;
+                }
+                else if (segments[i].Equals(directory, StringComparison.OrdinalIgnoreCase))
+                {
+                    return string.Join('/', segments.Take(i + 1));
+                }
+            }
+        }
+        return null;
+    }
+
+    private static List<string> Normalize(IEnumerable<string> directories) => directories
+        .Select(d => d.Replace('\\', '/').Trim().Trim('/'))
+        .Where(d => d.Length > 0 && d != ".")
+        .Distinct(StringComparer.OrdinalIgnoreCase)
+        .ToList();
+
+    private DependencyCodeSettings? GetWorkspaceSettings(string workspacePath)
+    {
+        if (_settings.TryGetValue(workspacePath, out var settings))
+            return settings;
+
+        var path = GetSettingsPath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+                settings = JsonSerializer.Deserialize<DependencyCodeSettings>(File.ReadAllText(path));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read dependency code settings {Path} - using the default", path);
+        }
+
+        _settings[workspacePath] = settings;
+        return settings;
+    }
+
+    private string GetSettingsPath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), SettingsFileName);
+}
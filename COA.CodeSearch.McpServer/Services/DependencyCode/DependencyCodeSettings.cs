@@ -0,0 +1,27 @@
+namespace COA.CodeSearch.McpServer.Services.DependencyCode;
+
+/// <summary>
+/// How a workspace indexes vendored and third-party directories
+/// </summary>
+public class DependencyCodeSettings
+{
+    public const string Exclude = "exclude";
+    public const string Include = "include";
+    public const string Downrank = "downrank";
+
+    public static readonly string[] Modes = { Exclude, Include, Downrank };
+
+    /// <summary>
+    /// exclude leaves dependency code out of the index, include indexes it flagged as dependency code, downrank
+    /// also scores it below first-party code in text search
+    /// </summary>
+    public string Mode { get; set; } = Include;
+
+    /// <summary>
+    /// Dependency directories: a bare name (vendor) matches a directory of that name anywhere, a path
+    /// (src/legacy/libs) matches that directory relative to the workspace
+    /// </summary>
+    public List<string> Directories { get; set; } = new();
+
+    public override string ToString() => $"{Mode}: {string.Join(", ", Directories)}";
+}
@@ -0,0 +1,38 @@
+namespace COA.CodeSearch.McpServer.Services.DependencyCode;
+
+/// <summary>
+/// Which directories of a workspace hold vendored or third-party code, and whether that code is left out of the
+/// index, indexed as dependency code or indexed and ranked below first-party code
+/// </summary>
+public interface IDependencyCodeService
+{
+    /// <summary>
+    /// The workspace's settings: the ones set for it, else CodeSearch:DependencyCode
+    /// </summary>
+    DependencyCodeSettings GetSettings(string workspacePath);
+
+    /// <summary>
+    /// True when settings were set for this workspace rather than defaulted
+    /// </summary>
+    bool HasWorkspaceSettings(string workspacePath);
+
+    /// <summary>
+    /// Stores the workspace's settings; null goes back to the default
+    /// </summary>
+    void SetSettings(string workspacePath, DependencyCodeSettings? settings);
+
+    /// <summary>
+    /// The dependency directory a file lies in, relative to the workspace; null for first-party files
+    /// </summary>
+    string? GetDependencyDirectory(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Whether a file is dependency code and the workspace excludes dependency code from the index
+    /// </summary>
+    bool IsExcluded(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Scanner ignore patterns for the dependency directories when the workspace excludes them, else none
+    /// </summary>
+    IReadOnlyList<string> GetIgnorePatterns(string workspacePath);
+}
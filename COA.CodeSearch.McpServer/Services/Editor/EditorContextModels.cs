@@ -0,0 +1,65 @@
+namespace COA.CodeSearch.McpServer.Services.Editor;
+
+/// <summary>
+/// What the user has in front of them in the editor, as last reported by the client: the open file, the cursor
+/// and the selection. Lines are 1-based and columns 0-based, counted in <see cref="ColumnEncoding"/>, the same
+/// convention as "path:line:column" positions.
+/// </summary>
+public class EditorContext
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Open file, workspace-relative with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    public int? Line { get; set; }
+    public int? Column { get; set; }
+    public EditorSelection? Selection { get; set; }
+
+    /// <summary>
+    /// Name of the symbol under the cursor when the client knows it (e.g. from its language server)
+    /// </summary>
+    public string? SymbolAtCursor { get; set; }
+
+    /// <summary>
+    /// utf-16 (LSP) or utf-8
+    /// </summary>
+    public string ColumnEncoding { get; set; } = "utf-16";
+
+    public DateTime UpdatedAt { get; set; }
+
+    /// <summary>
+    /// The cursor as a "path:line:column" position; null without a cursor line
+    /// </summary>
+    public string? CursorPosition => Line == null ? null : $"{FilePath}:{Line}:{Column ?? 0}";
+}
+
+/// <summary>
+/// Selected range, start inclusive and end exclusive, with the selected text when the client sent it
+/// </summary>
+public class EditorSelection
+{
+    public int StartLine { get; set; }
+    public int StartColumn { get; set; }
+    public int EndLine { get; set; }
+    public int EndColumn { get; set; }
+    public string? Text { get; set; }
+}
+
+/// <summary>
+/// A tool argument filled from the editor context because the call left it empty
+/// </summary>
+public class EditorContextUse
+{
+    public string Parameter { get; set; } = string.Empty;
+    public string Value { get; set; } = string.Empty;
+
+    /// <summary>
+    /// selection, symbol-at-cursor, cursor or open-file
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    public override string ToString() => $"{Parameter} = {Value} (from the editor {Source.Replace('-', ' ')})";
+}
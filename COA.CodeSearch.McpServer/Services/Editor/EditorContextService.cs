@@ -0,0 +1,139 @@
+using System.Reflection;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Tools;
+
+namespace COA.CodeSearch.McpServer.Services.Editor;
+
+/// <summary>
+/// In-memory editor context for the session. Only read-only navigation and analysis tools take arguments from
+/// it - an edit must never land in whatever file happens to be open.
+/// </summary>
+public class EditorContextService : IEditorContextService
+{
+    private static readonly Regex QualifiedIdentifier = new(
+        $@"^{UnicodeIdentifiers.IdentifierPattern}(?:(?:\.|::|->){UnicodeIdentifiers.IdentifierPattern})*$", RegexOptions.Compiled);
+
+    // Tool → argument it can take from the context, and whether that argument accepts a "path:line:column" position
+    private static readonly Dictionary<string, (string Parameter, ArgumentKind Kind)> Arguments = new(StringComparer.Ordinal)
+    {
+        [ToolNames.FindReferences] = ("Symbol", ArgumentKind.SymbolOrPosition),
+        [ToolNames.GoToDefinition] = ("Symbol", ArgumentKind.SymbolOrPosition),
+        [ToolNames.TraceCallPath] = ("Symbol", ArgumentKind.Symbol),
+        [ToolNames.FindTests] = ("Symbol", ArgumentKind.Symbol),
+        [ToolNames.FindImplementations] = ("Interface", ArgumentKind.Symbol),
+        [ToolNames.Hover] = ("Position", ArgumentKind.Position),
+        [ToolNames.TypeOf] = ("Position", ArgumentKind.Position),
+        [ToolNames.NullableFlow] = ("Position", ArgumentKind.Position),
+        [ToolNames.DataFlow] = ("Position", ArgumentKind.Position),
+        [ToolNames.GetSymbolsOverview] = ("FilePath", ArgumentKind.File),
+        [ToolNames.ReadSymbols] = ("FilePath", ArgumentKind.File),
+        [ToolNames.FindPatterns] = ("FilePath", ArgumentKind.File)
+    };
+
+    private enum ArgumentKind { Symbol, SymbolOrPosition, Position, File }
+
+    private readonly object _lock = new();
+    private EditorContext? _current;
+
+    public EditorContext? Current
+    {
+        get { lock (_lock) return _current; }
+    }
+
+    public void Set(EditorContext context)
+    {
+        lock (_lock)
+        {
+            _current = context;
+        }
+    }
+
+    public bool Clear()
+    {
+        lock (_lock)
+        {
+            var had = _current != null;
+            _current = null;
+            return had;
+        }
+    }
+
+    public IReadOnlyList<EditorContextUse> Apply(string toolName, object parameters)
+    {
+        var context = Current;
+        var use = context == null ? null : ArgumentFor(context, toolName);
+        if (use == null)
+            return Array.Empty<EditorContextUse>();
+
+        var property = parameters.GetType().GetProperty(use.Parameter, BindingFlags.Public | BindingFlags.Instance);
+        if (property is not { CanWrite: true } || property.PropertyType != typeof(string) ||
+            !string.IsNullOrWhiteSpace((string?)property.GetValue(parameters)))
+            return Array.Empty<EditorContextUse>();
+
+        property.SetValue(parameters, use.Value);
+
+        // A position is only meaningful in the unit the editor counted its columns in
+        var encoding = parameters.GetType().GetProperty("ColumnEncoding", BindingFlags.Public | BindingFlags.Instance);
+        if (use.Source is "cursor" or "selection-start" && encoding is { CanWrite: true } && encoding.PropertyType == typeof(string))
+        {
+            encoding.SetValue(parameters, context!.ColumnEncoding);
+        }
+
+        return new[] { use };
+    }
+
+    /// <summary>
+    /// The argument a tool would take from the context when the call leaves it empty; null when the tool takes
+    /// none or the context has nothing suitable
+    /// </summary>
+    public static EditorContextUse? ArgumentFor(EditorContext context, string toolName)
+    {
+        if (!Arguments.TryGetValue(toolName, out var argument))
+            return null;
+
+        var (value, source) = Resolve(context, argument.Kind);
+        return value == null ? null : new EditorContextUse { Parameter = argument.Parameter, Value = value, Source = source! };
+    }
+
+    /// <summary>
+    /// Tools that take an argument from the editor context
+    /// </summary>
+    public static IEnumerable<string> ContextAwareTools => Arguments.Keys;
+
+    /// <summary>
+    /// Selected identifier, the last segment of a selected qualified name (UserService.GetUser → GetUser)
+    /// or null when the selection is not a name
+    /// </summary>
+    public static string? SelectedName(EditorContext context)
+    {
+        var text = context.Selection?.Text?.Trim();
+        if (string.IsNullOrEmpty(text) || !QualifiedIdentifier.IsMatch(text))
+            return null;
+
+        return Regex.Split(text, @"\.|::|->")[^1];
+    }
+
+    private static (string? Value, string? Source) Resolve(EditorContext context, ArgumentKind kind)
+    {
+        if (kind == ArgumentKind.File)
+            return string.IsNullOrEmpty(context.FilePath) ? (null, null) : (context.FilePath, "open-file");
+
+        // A selected name is what the user pointed at most deliberately
+        var selected = kind == ArgumentKind.Position ? null : SelectedName(context);
+        if (selected != null)
+            return (selected, "selection");
+
+        if (kind != ArgumentKind.Symbol && !string.IsNullOrEmpty(context.FilePath))
+        {
+            if (context.CursorPosition != null)
+                return (context.CursorPosition, "cursor");
+            if (context.Selection != null)
+                return ($"{context.FilePath}:{context.Selection.StartLine}:{context.Selection.StartColumn}", "selection-start");
+        }
+
+        return string.IsNullOrWhiteSpace(context.SymbolAtCursor) || kind == ArgumentKind.Position
+            ? (null, null)
+            : (context.SymbolAtCursor.Trim(), "symbol-at-cursor");
+    }
+}
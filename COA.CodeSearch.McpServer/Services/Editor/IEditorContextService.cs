@@ -0,0 +1,29 @@
+namespace COA.CodeSearch.McpServer.Services.Editor;
+
+/// <summary>
+/// Holds the editor context the client reported last, so "find references to this" needs no location: tools
+/// fill arguments the call left empty from it, and searches rank files near the open file higher
+/// </summary>
+public interface IEditorContextService
+{
+    /// <summary>
+    /// The current context; null when none was set
+    /// </summary>
+    EditorContext? Current { get; }
+
+    /// <summary>
+    /// Replaces the current context
+    /// </summary>
+    void Set(EditorContext context);
+
+    /// <summary>
+    /// Forgets the current context. Returns false when none was set.
+    /// </summary>
+    bool Clear();
+
+    /// <summary>
+    /// Fills the arguments of the given tool that the call left empty and the context can supply
+    /// </summary>
+    /// <returns>The arguments filled, empty when none were</returns>
+    IReadOnlyList<EditorContextUse> Apply(string toolName, object parameters);
+}
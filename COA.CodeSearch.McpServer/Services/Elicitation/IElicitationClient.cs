@@ -0,0 +1,69 @@
+namespace COA.CodeSearch.McpServer.Services.Elicitation;
+
+/// <summary>
+/// Asks the user a structured question through the connected client via MCP elicitation (elicitation/create)
+/// </summary>
+public interface IElicitationClient
+{
+    /// <summary>
+    /// True when elicitation is enabled and the client declared the elicitation capability during initialize
+    /// </summary>
+    bool IsAvailable { get; }
+
+    /// <summary>
+    /// Most options one question may offer
+    /// </summary>
+    int MaxOptions { get; }
+
+    /// <summary>
+    /// Asks the user to pick one of the options
+    /// </summary>
+    /// <exception cref="ElicitationException">Elicitation unavailable, failed or timed out</exception>
+    Task<ElicitationResult> ChooseAsync(ElicitationRequest request, CancellationToken cancellationToken = default);
+}
+
+/// <summary>
+/// A single-choice clarification question
+/// </summary>
+public class ElicitationRequest
+{
+    /// <summary>
+    /// Question shown to the user
+    /// </summary>
+    public string Message { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Label of the choice field in the client's form
+    /// </summary>
+    public string Title { get; set; } = "Choice";
+
+    public List<ElicitationOption> Options { get; set; } = new();
+}
+
+public class ElicitationOption
+{
+    public string Value { get; set; } = string.Empty;
+    public string Label { get; set; } = string.Empty;
+}
+
+public class ElicitationResult
+{
+    /// <summary>
+    /// accept, decline or cancel
+    /// </summary>
+    public string Action { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Value of the chosen option when accepted
+    /// </summary>
+    public string? Value { get; set; }
+
+    public bool Accepted => Action == "accept" && Value != null;
+}
+
+public class ElicitationException : Exception
+{
+    public ElicitationException(string message, Exception? innerException = null) : base(message, innerException)
+    {
+    }
+}
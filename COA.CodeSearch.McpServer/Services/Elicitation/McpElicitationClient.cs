@@ -0,0 +1,111 @@
+using System.Text.Json;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Elicitation;
+
+/// <summary>
+/// MCP elicitation (elicitation/create), sent through the same <see cref="McpClientRequestChannel"/> as sampling
+/// </summary>
+public class McpElicitationClient : IElicitationClient
+{
+    private const string ChoiceField = "choice";
+
+    private readonly McpClientRequestChannel _channel;
+    private readonly TimeSpan _timeout;
+    private readonly ILogger<McpElicitationClient> _logger;
+
+    /// <param name="configuration">CodeSearch:Elicitation settings</param>
+    /// <param name="channel">Carries requests to the client and its responses back</param>
+    /// <param name="logger">Logger instance</param>
+    public McpElicitationClient(IConfiguration configuration, McpClientRequestChannel channel, ILogger<McpElicitationClient> logger)
+    {
+        _channel = channel ?? throw new ArgumentNullException(nameof(channel));
+        _logger = logger;
+        Enabled = configuration.GetValue("CodeSearch:Elicitation:Enabled", false);
+        MaxOptions = configuration.GetValue("CodeSearch:Elicitation:MaxOptions", 10);
+        _timeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Elicitation:TimeoutSeconds", 120));
+    }
+
+    /// <summary>
+    /// CodeSearch:Elicitation:Enabled - when false tools never ask
+    /// </summary>
+    public bool Enabled { get; }
+
+    /// <summary>
+    /// Most options offered in one question; longer candidate lists are not worth a form
+    /// </summary>
+    public int MaxOptions { get; }
+
+    public bool IsAvailable => Enabled && _channel.ClientSupports("elicitation");
+
+    public async Task<ElicitationResult> ChooseAsync(ElicitationRequest request, CancellationToken cancellationToken = default)
+    {
+        if (!Enabled)
+            throw new ElicitationException("Elicitation is disabled (CodeSearch:Elicitation:Enabled)");
+        if (!IsAvailable)
+            throw new ElicitationException("The connected client does not support MCP elicitation");
+        if (request.Options.Count < 2 || request.Options.Count > MaxOptions)
+            throw new ElicitationException($"A choice needs 2-{MaxOptions} options, got {request.Options.Count}");
+
+        try
+        {
+            JsonElement response;
+            try
+            {
+                response = await _channel.SendRequestAsync("elicitation/create", new
+                {
+                    message = request.Message,
+                    requestedSchema = new
+                    {
+                        type = "object",
+                        properties = new Dictionary<string, object>
+                        {
+                            [ChoiceField] = new
+                            {
+                                type = "string",
+                                title = request.Title,
+                                @enum = request.Options.Select(o => o.Value).ToArray(),
+                                enumNames = request.Options.Select(o => o.Label).ToArray()
+                            }
+                        },
+                        required = new[] { ChoiceField }
+                    }
+                }, _timeout, cancellationToken);
+            }
+            catch (TimeoutException)
+            {
+                throw new ElicitationException($"No answer to the clarification question after {_timeout.TotalSeconds:0}s");
+            }
+
+            if (response.TryGetProperty("error", out var error))
+            {
+                var errorMessage = error.TryGetProperty("message", out var m) ? m.GetString() : error.ToString();
+                throw new ElicitationException($"Client rejected elicitation request: {errorMessage}");
+            }
+
+            var result = response.GetProperty("result");
+            var action = result.TryGetProperty("action", out var a) ? a.GetString() ?? "cancel" : "cancel";
+            var value = action == "accept" &&
+                        result.TryGetProperty("content", out var content) &&
+                        content.TryGetProperty(ChoiceField, out var choice) &&
+                        choice.ValueKind == JsonValueKind.String
+                ? choice.GetString()
+                : null;
+
+            // Only values we offered count as an answer
+            if (value != null && request.Options.All(o => o.Value != value))
+            {
+                _logger.LogWarning("Elicitation answer {Value} is not one of the offered options", value);
+                value = null;
+            }
+
+            _logger.LogDebug("Elicitation answered: {Action} {Value}", action, value);
+            return new ElicitationResult { Action = action, Value = value };
+        }
+        catch (Exception ex) when (ex is JsonException or KeyNotFoundException or InvalidOperationException or IOException)
+        {
+            throw new ElicitationException($"Invalid elicitation response: {ex.Message}", ex);
+        }
+    }
+}
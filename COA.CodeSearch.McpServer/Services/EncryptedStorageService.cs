@@ -0,0 +1,266 @@
+using Microsoft.Data.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Hosted service that unseals encrypted SQLite symbol databases on startup and seals them again on shutdown.
+/// SQLite (and julie-codesearch) need a real file to work with, so the symbol database is plaintext while the
+/// server is running. While it runs, a sealed copy is refreshed after every write so a crash loses nothing; on
+/// any exit the plaintext is sealed and deleted, and plaintext left by a kill is sealed on the next start.
+/// Lucene indexes are handled separately via encrypted snapshots.
+/// </summary>
+public class EncryptedStorageService : IHostedService, IDisposable
+{
+    private const string DatabaseDirectoryName = "db";
+    private const string DatabaseFileName = "workspace.db";
+
+    private readonly IIndexEncryptionService _encryption;
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILogger<EncryptedStorageService> _logger;
+    private readonly TimeSpan _resealInterval;
+    // Sealing runs on the timer, on shutdown and from exit handlers; one at a time
+    private readonly object _sealLock = new();
+    private readonly Dictionary<string, DateTime> _sealedAt = new(StringComparer.OrdinalIgnoreCase);
+    private Timer? _resealTimer;
+    private bool _exitHandlersRegistered;
+
+    public EncryptedStorageService(
+        IIndexEncryptionService encryption,
+        IPathResolutionService pathResolution,
+        IConfiguration configuration,
+        ILogger<EncryptedStorageService> logger)
+    {
+        _encryption = encryption ?? throw new ArgumentNullException(nameof(encryption));
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _resealInterval = TimeSpan.FromSeconds(Math.Max(1, configuration.GetValue("CodeSearch:Encryption:ResealIntervalSeconds", 10)));
+    }
+
+    public Task StartAsync(CancellationToken cancellationToken)
+    {
+        if (!_encryption.IsEnabled)
+            return Task.CompletedTask;
+
+        // Fail closed: refuse to start rather than silently writing plaintext caches
+        if (!_encryption.HasKey())
+        {
+            throw new InvalidOperationException(
+                "Index encryption is enabled but no key is available. See CodeSearch:Encryption in docs/CONFIGURATION.md.");
+        }
+
+        var unsealed = 0;
+        lock (_sealLock)
+        {
+            foreach (var dbPath in EnumerateDatabasePaths())
+            {
+                try
+                {
+                    // Plaintext on disk before we unsealed anything was left by a crash or kill: it holds the latest
+                    // writes, so it replaces the sealed copy rather than being trusted as is
+                    if (File.Exists(dbPath))
+                    {
+                        _logger.LogWarning("Sealing plaintext symbol database {Path} left by an unclean exit", dbPath);
+                        SealAndDelete(dbPath);
+                    }
+
+                    if (_encryption.UnsealFile(dbPath))
+                    {
+                        _sealedAt[dbPath] = GetLastWriteTimeUtc(dbPath);
+                        unsealed++;
+                    }
+                }
+                catch (Exception ex)
+                {
+                    _logger.LogError(ex, "Failed to unseal symbol database {Path} - it will be rebuilt on next index", dbPath);
+                }
+            }
+        }
+
+        // Hosted services aren't stopped on a crash; these seal what they can on the way out
+        AppDomain.CurrentDomain.ProcessExit += OnProcessExit;
+        AppDomain.CurrentDomain.UnhandledException += OnUnhandledException;
+        _exitHandlersRegistered = true;
+        _resealTimer = new Timer(_ => ResealChanged(), null, _resealInterval, _resealInterval);
+
+        _logger.LogInformation("Encrypted storage ready - unsealed {Count} symbol database(s)", unsealed);
+        return Task.CompletedTask;
+    }
+
+    public Task StopAsync(CancellationToken cancellationToken)
+    {
+        if (!_encryption.IsEnabled)
+            return Task.CompletedTask;
+
+        _resealTimer?.Dispose();
+        _resealTimer = null;
+
+        var sealedCount = SealAll();
+        _logger.LogInformation("Sealed {Count} symbol database(s) on shutdown", sealedCount);
+        return Task.CompletedTask;
+    }
+
+    public void Dispose()
+    {
+        _resealTimer?.Dispose();
+        if (_exitHandlersRegistered)
+        {
+            AppDomain.CurrentDomain.ProcessExit -= OnProcessExit;
+            AppDomain.CurrentDomain.UnhandledException -= OnUnhandledException;
+            _exitHandlersRegistered = false;
+        }
+    }
+
+    private void OnProcessExit(object? sender, EventArgs e) => SealAll();
+
+    private void OnUnhandledException(object? sender, UnhandledExceptionEventArgs e) => SealAll();
+
+    /// <summary>
+    /// Seals every plaintext symbol database and deletes the plaintext. Returns the number sealed.
+    /// </summary>
+    private int SealAll()
+    {
+        lock (_sealLock)
+        {
+            // Release pooled handles so the database can be checkpointed and removed
+            SqliteConnection.ClearAllPools();
+
+            var sealedCount = 0;
+            foreach (var dbPath in EnumerateDatabasePaths())
+            {
+                if (!File.Exists(dbPath))
+                    continue;
+
+                try
+                {
+                    SealAndDelete(dbPath);
+                    _sealedAt.Remove(dbPath);
+                    sealedCount++;
+                }
+                catch (Exception ex)
+                {
+                    _logger.LogError(ex, "Failed to seal symbol database {Path}", dbPath);
+                }
+            }
+            return sealedCount;
+        }
+    }
+
+    /// <summary>
+    /// Refreshes the sealed copy of every database written since it was last sealed, so the sealed copy is never
+    /// more than one interval behind. The database stays in use: a consistent copy is taken with SQLite's backup
+    /// API, sealed and deleted.
+    /// </summary>
+    private void ResealChanged()
+    {
+        if (!Monitor.TryEnter(_sealLock))
+            return;
+
+        try
+        {
+            foreach (var dbPath in EnumerateDatabasePaths())
+            {
+                if (!File.Exists(dbPath))
+                    continue;
+
+                var lastWrite = GetLastWriteTimeUtc(dbPath);
+                if (_sealedAt.TryGetValue(dbPath, out var sealedAt) && lastWrite <= sealedAt)
+                    continue;
+
+                try
+                {
+                    SealCopy(dbPath);
+                    _sealedAt[dbPath] = lastWrite;
+                    _logger.LogDebug("Resealed symbol database {Path} after writes", dbPath);
+                }
+                catch (Exception ex)
+                {
+                    // Typically julie-codesearch holding a write lock; the next interval tries again
+                    _logger.LogDebug(ex, "Could not reseal symbol database {Path}", dbPath);
+                }
+            }
+        }
+        finally
+        {
+            Monitor.Exit(_sealLock);
+        }
+    }
+
+    private void SealAndDelete(string dbPath)
+    {
+        CheckpointDatabase(dbPath);
+        _encryption.SealFile(dbPath);
+        DeleteIfExists(dbPath + "-wal");
+        DeleteIfExists(dbPath + "-shm");
+    }
+
+    private void SealCopy(string dbPath)
+    {
+        var copyPath = dbPath + ".reseal";
+        try
+        {
+            using (var source = new SqliteConnection($"Data Source={dbPath};Mode=ReadOnly;Pooling=false"))
+            using (var destination = new SqliteConnection($"Data Source={copyPath};Pooling=false"))
+            {
+                source.Open();
+                destination.Open();
+                source.BackupDatabase(destination);
+            }
+
+            _encryption.SealFile(copyPath);
+            File.Move(copyPath + _encryption.SealedExtension, dbPath + _encryption.SealedExtension, overwrite: true);
+        }
+        finally
+        {
+            DeleteIfExists(copyPath);
+            DeleteIfExists(copyPath + _encryption.SealedExtension);
+        }
+    }
+
+    private IEnumerable<string> EnumerateDatabasePaths()
+    {
+        var indexRoot = _pathResolution.GetIndexRootPath();
+        if (!Directory.Exists(indexRoot))
+            yield break;
+
+        foreach (var indexDirectory in Directory.EnumerateDirectories(indexRoot))
+        {
+            yield return Path.Combine(indexDirectory, DatabaseDirectoryName, DatabaseFileName);
+        }
+    }
+
+    /// <summary>
+    /// Latest write to the database, counting writes still in its WAL
+    /// </summary>
+    private static DateTime GetLastWriteTimeUtc(string dbPath)
+    {
+        var lastWrite = File.GetLastWriteTimeUtc(dbPath);
+        var walPath = dbPath + "-wal";
+        return File.Exists(walPath) && File.GetLastWriteTimeUtc(walPath) > lastWrite ? File.GetLastWriteTimeUtc(walPath) : lastWrite;
+    }
+
+    /// <summary>
+    /// Folds the WAL back into the main database file so the sealed copy is complete
+    /// </summary>
+    private static void CheckpointDatabase(string dbPath)
+    {
+        using (var connection = new SqliteConnection($"Data Source={dbPath};Pooling=false"))
+        {
+            connection.Open();
+            using var cmd = connection.CreateCommand();
+            cmd.CommandText = "PRAGMA wal_checkpoint(TRUNCATE)";
+            cmd.ExecuteNonQuery();
+        }
+        SqliteConnection.ClearAllPools();
+    }
+
+    private static void DeleteIfExists(string path)
+    {
+        if (File.Exists(path))
+        {
+            File.Delete(path);
+        }
+    }
+}
@@ -3,7 +3,13 @@ using Microsoft.Extensions.Configuration;
 using Microsoft.Extensions.Logging;
 using Microsoft.Extensions.Options;
 using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Plugins;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Assets;
+using COA.CodeSearch.McpServer.Services.ColdStorage;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
 using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.NetworkFileSystem;
 using COA.CodeSearch.McpServer.Services.TypeExtraction;
 using COA.CodeSearch.McpServer.Services.Julie;
 using COA.CodeSearch.McpServer.Services.Sqlite;
@@ -29,9 +35,19 @@ public class FileIndexingService : IFileIndexingService
     private readonly IJulieCodeSearchService? _julieCodeSearchService;
     private readonly ISQLiteSymbolService? _sqliteSymbolService;
     private readonly ISemanticIntelligenceService? _semanticIntelligenceService;
+    private readonly IShutdownCoordinator? _shutdown;
+    private readonly Dictionary<string, ISymbolExtractorPlugin> _extractorPlugins;
+    private readonly IWebhookService? _webhooks;
+    private readonly IFindingsBaselineService? _findingsBaseline;
+    private readonly IColdTierService? _coldTier;
+    private readonly IIndexBudgetService? _indexBudget;
+    private readonly IDependencyCodeService? _dependencyCode;
+    private readonly IAssetInventoryService? _assetInventory;
+    private readonly INetworkFileSystemService? _networkFileSystem;
     private readonly MemoryLimitsConfiguration _memoryLimits;
     private readonly HashSet<string> _blacklistedExtensions;
     private readonly HashSet<string> _excludedDirectories;
+    private readonly SymlinkPolicy _symlinkPolicy;
     private const int MAX_FILE_SIZE = 10 * 1024 * 1024; // 10MB max file size
 
     public FileIndexingService(
@@ -45,7 +61,16 @@ public class FileIndexingService : IFileIndexingService
         IOptions<MemoryLimitsConfiguration> memoryLimits,
         IJulieCodeSearchService? julieCodeSearchService = null,
         ISQLiteSymbolService? sqliteSymbolService = null,
-        ISemanticIntelligenceService? semanticIntelligenceService = null)
+        ISemanticIntelligenceService? semanticIntelligenceService = null,
+        IShutdownCoordinator? shutdown = null,
+        IEnumerable<ISymbolExtractorPlugin>? extractorPlugins = null,
+        IWebhookService? webhooks = null,
+        IFindingsBaselineService? findingsBaseline = null,
+        IColdTierService? coldTier = null,
+        IIndexBudgetService? indexBudget = null,
+        IDependencyCodeService? dependencyCode = null,
+        IAssetInventoryService? assetInventory = null,
+        INetworkFileSystemService? networkFileSystem = null)
     {
         _logger = logger ?? throw new ArgumentNullException(nameof(logger));
         _configuration = configuration ?? throw new ArgumentNullException(nameof(configuration));
@@ -58,6 +83,24 @@ public class FileIndexingService : IFileIndexingService
         _julieCodeSearchService = julieCodeSearchService;
         _sqliteSymbolService = sqliteSymbolService;
         _semanticIntelligenceService = semanticIntelligenceService;
+        _shutdown = shutdown;
+        _webhooks = webhooks;
+        _findingsBaseline = findingsBaseline;
+        _coldTier = coldTier;
+        _indexBudget = indexBudget;
+        _dependencyCode = dependencyCode;
+        _assetInventory = assetInventory;
+        _networkFileSystem = networkFileSystem;
+
+        // First plugin registered for an extension wins
+        _extractorPlugins = new Dictionary<string, ISymbolExtractorPlugin>(StringComparer.OrdinalIgnoreCase);
+        foreach (var plugin in extractorPlugins ?? Enumerable.Empty<ISymbolExtractorPlugin>())
+        {
+            foreach (var extension in plugin.Extensions)
+            {
+                _extractorPlugins.TryAdd(extension, plugin);
+            }
+        }
 
         // Debug: Log julie service injection
         _logger.LogDebug("FileIndexingService initialized - Julie codesearch: {CodeSearchAvailable}, SQLite: {SqliteAvailable}, Semantic: {SemanticAvailable}",
@@ -74,6 +117,8 @@ public class FileIndexingService : IFileIndexingService
         var excluded = configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>() 
             ?? PathConstants.DefaultExcludedDirectories;
         _excludedDirectories = new HashSet<string>(excluded, StringComparer.OrdinalIgnoreCase);
+
+        _symlinkPolicy = PlatformPaths.ParseSymlinkPolicy(configuration["CodeSearch:Symlinks:Policy"]);
     }
         /// <summary>
         /// Extract only symbol names from content and type data for symbol-only search field
@@ -109,6 +154,20 @@ public class FileIndexingService : IFileIndexingService
         var result = new IndexingResult { WorkspacePath = workspacePath };
         var startTime = DateTime.UtcNow;
 
+        if (_shutdown?.IsShuttingDown == true)
+        {
+            result.Success = false;
+            result.ErrorMessage = "Server is shutting down";
+            return result;
+        }
+
+        // Stop at the next checkpoint when the server shuts down; the incomplete marker makes the next start resume
+        using var operation = _shutdown?.TrackOperation($"index_workspace {workspacePath}");
+        using var shutdownCts = _shutdown != null
+            ? CancellationTokenSource.CreateLinkedTokenSource(cancellationToken, _shutdown.ShutdownToken)
+            : null;
+        cancellationToken = shutdownCts?.Token ?? cancellationToken;
+
         try
         {
             // Initialize the index
@@ -120,6 +179,12 @@ public class FileIndexingService : IFileIndexingService
                 return result;
             }
 
+            var luceneIndexPath = _pathResolution.GetLuceneIndexPath(workspacePath);
+            IndexWriteJournal.MarkFullIndexStarted(luceneIndexPath);
+
+            // A full pass re-indexes every file, so only journaled deletions still need replaying
+            var interruptedWrites = _luceneIndexService.TakeInterruptedWrites(workspacePath);
+
             // Note: Index clearing/rebuilding is now handled by the calling tool
             // ForceRebuildIndexAsync handles schema recreation when needed
             // ClearIndexAsync is only used for document removal without schema changes
@@ -160,6 +225,18 @@ public class FileIndexingService : IFileIndexingService
                         ignorePatterns.AddRange(customPatterns);
                     }
 
+                    // Cold-tier files get no symbols
+                    if (_coldTier?.IsEnabled == true)
+                    {
+                        ignorePatterns.AddRange(_coldTier.Patterns.Select(p => p.EndsWith('/') ? $"{p}**" : p));
+                    }
+
+                    // Vendored and third-party directories the workspace excludes
+                    if (_dependencyCode != null)
+                    {
+                        ignorePatterns.AddRange(_dependencyCode.GetIgnorePatterns(workspacePath));
+                    }
+
                     // Scan workspace with julie-codesearch
                     var scanResult = await _julieCodeSearchService.ScanDirectoryAsync(
                         workspacePath,
@@ -190,6 +267,9 @@ public class FileIndexingService : IFileIndexingService
                                 _logger.LogWarning(ex, "Failed to initialize vec0 tables - semantic search may not work");
                             }
                         }
+
+                        await ApplySymlinkPolicyAsync(workspacePath, cancellationToken);
+                        await RepairSymbolsAsync(workspacePath, cancellationToken);
                     }
                     else
                     {
@@ -338,17 +418,68 @@ public class FileIndexingService : IFileIndexingService
                 }
             }, TaskScheduler.Default);
 
+            foreach (var deletedPath in interruptedWrites.Where(p => !File.Exists(p)))
+            {
+                await _luceneIndexService.DeleteDocumentAsync(workspacePath, deletedPath, cancellationToken);
+            }
+
             // Commit changes
             await _luceneIndexService.CommitAsync(workspacePath, cancellationToken);
+            _findingsBaseline?.Flush(workspacePath);
+            IndexWriteJournal.MarkFullIndexCompleted(luceneIndexPath);
+            await _luceneIndexService.RetirePreviousFormatAsync(workspacePath, cancellationToken);
+            
+            var recovery = _luceneIndexService.GetRecoveryReport(workspacePath);
+            if (recovery != null)
+            {
+                recovery.Resolved = true;
+            }
+
+            // Assets are left out of the index; their metadata is kept beside it
+            if (_assetInventory != null)
+            {
+                try
+                {
+                    await _assetInventory.RefreshAsync(workspacePath, cancellationToken);
+                }
+                catch (Exception ex) when (ex is not OperationCanceledException)
+                {
+                    _logger.LogWarning(ex, "Failed to inventory assets for {WorkspacePath}", workspacePath);
+                }
+            }
             
             result.Success = true;
             result.Duration = DateTime.UtcNow - startTime;
             
             _logger.LogInformation("Indexed workspace {WorkspacePath}: {FileCount} files in {Duration}ms",
                 workspacePath, result.IndexedFileCount, result.Duration.TotalMilliseconds);
+
+            _webhooks?.Publish(new WebhookEvent
+            {
+                Event = WebhookEvents.IndexCompleted,
+                WorkspacePath = workspacePath,
+                Summary = $"Indexed {Path.GetFileName(workspacePath)}: {result.IndexedFileCount} files in {result.Duration.TotalSeconds:F1}s",
+                Data = new
+                {
+                    result.IndexedFileCount,
+                    result.SkippedFileCount,
+                    result.ErrorCount,
+                    DurationMs = (long)result.Duration.TotalMilliseconds
+                }
+            });
                 
             return result;
         }
+        catch (OperationCanceledException) when (_shutdown?.IsShuttingDown == true)
+        {
+            // Progress so far is committed by the shutdown flush; the next start resumes the pass
+            _logger.LogWarning("Indexing of {WorkspacePath} interrupted by shutdown after {FileCount} files", 
+                workspacePath, result.IndexedFileCount);
+            result.Success = false;
+            result.ErrorMessage = "Indexing interrupted by shutdown - it will resume on the next start";
+            result.Duration = DateTime.UtcNow - startTime;
+            return result;
+        }
         catch (Exception ex)
         {
             _logger.LogError(ex, "Failed to index workspace {WorkspacePath}", workspacePath);
@@ -477,6 +608,7 @@ public class FileIndexingService : IFileIndexingService
                             // Commit after each batch to persist merges to disk (prevents corruption on macOS)
                             // This ensures crash recovery works even if process is killed during indexing
                             await _luceneIndexService.CommitAsync(workspacePath, cancellationToken);
+                            _findingsBaseline?.Flush(workspacePath);
                             _logger.LogDebug("Committed batch {IndexedCount} files", indexedCount);
                         }
                     }
@@ -502,6 +634,7 @@ public class FileIndexingService : IFileIndexingService
 
                 // Commit final batch to persist merges (prevents corruption on macOS)
                 await _luceneIndexService.CommitAsync(workspacePath, cancellationToken);
+                _findingsBaseline?.Flush(workspacePath);
                 _logger.LogDebug("Committed final batch - total {IndexedCount} files", indexedCount);
             }
             
@@ -523,13 +656,20 @@ public class FileIndexingService : IFileIndexingService
         }
     }
 
-    public async Task<bool> IndexFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default)
+    public async Task<FileIndexOutcome> IndexFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default)
     {
         try
         {
             _logger.LogDebug("IndexFileAsync called - Workspace: {WorkspacePath}, File: {FilePath}",
                 workspacePath, filePath);
 
+            var skipReason = GetSkipReason(workspacePath, filePath);
+            if (skipReason != null)
+            {
+                _logger.LogDebug("Not indexing {FilePath}: {Reason}", filePath, skipReason);
+                return FileIndexOutcome.Skipped;
+            }
+
             var document = await CreateDocumentFromFileAsync(filePath, workspacePath, symbolCache: null, cancellationToken);
             if (document != null)
             {
@@ -540,17 +680,35 @@ public class FileIndexingService : IFileIndexingService
                 var count = await _luceneIndexService.GetDocumentCountAsync(workspacePath, cancellationToken);
                 _logger.LogDebug("After indexing {FilePath}, document count is: {Count}", filePath, count);
                 
-                return true;
+                return FileIndexOutcome.Indexed;
             }
-            return false;
+            return FileIndexOutcome.Failed;
         }
         catch (Exception ex)
         {
             _logger.LogError(ex, "Failed to index file {FilePath}", filePath);
-            return false;
+            return FileIndexOutcome.Failed;
         }
     }
 
+    /// <summary>
+    /// Why a file is left out of the index on purpose - missing, pruned by the index budget or excluded
+    /// dependency code - or null when it should be indexed
+    /// </summary>
+    private string? GetSkipReason(string workspacePath, string filePath)
+    {
+        if (!File.Exists(filePath))
+            return "file does not exist";
+
+        if (_indexBudget?.IsPruned(workspacePath, filePath) == true)
+            return "pruned to keep the index within its size budget";
+
+        if (_dependencyCode?.IsExcluded(workspacePath, filePath) == true)
+            return "dependency code the workspace excludes";
+
+        return null;
+    }
+
     public async Task<bool> RemoveFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default)
     {
         try
@@ -566,6 +724,42 @@ public class FileIndexingService : IFileIndexingService
         }
     }
 
+    public async Task<int> ReplayInterruptedWritesAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var pending = _luceneIndexService.TakeInterruptedWrites(workspacePath);
+        if (pending.Count == 0)
+            return 0;
+
+        _logger.LogInformation("Replaying {Count} uncommitted file change(s) for {WorkspacePath}", pending.Count, workspacePath);
+
+        var documents = new List<Document>();
+        foreach (var filePath in pending)
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+
+            if (File.Exists(filePath))
+            {
+                var document = await CreateDocumentFromFileAsync(filePath, workspacePath, symbolCache: null, cancellationToken);
+                if (document != null)
+                {
+                    documents.Add(document);
+                }
+            }
+            else
+            {
+                await _luceneIndexService.DeleteDocumentAsync(workspacePath, filePath, cancellationToken);
+            }
+        }
+
+        if (documents.Count > 0)
+        {
+            await _luceneIndexService.IndexDocumentsAsync(workspacePath, documents, cancellationToken);
+        }
+        await _luceneIndexService.CommitAsync(workspacePath, cancellationToken);
+
+        return pending.Count;
+    }
+
     private IEnumerable<string> GetFilesToIndex(string directoryPath)
     {
         // PHASE 1: Check if SQLite database exists and use it as source of truth
@@ -609,134 +803,127 @@ public class FileIndexingService : IFileIndexingService
         }
 
         _logger.LogDebug("Starting file enumeration for {DirectoryPath}", directoryPath);
-        var directoriesToProcess = new Stack<string>();
-        directoriesToProcess.Push(directoryPath);
         var totalFilesFound = 0;
-        var totalDirsProcessed = 0;
-        
-        while (directoriesToProcess.Count > 0)
+
+        // Junctions and symlinks are walked as CodeSearch:Symlinks:Policy says; link cycles end under every policy
+        var files = PlatformPaths.EnumerateFileInfos(
+            directoryPath,
+            enterDirectory: subDir => !_excludedDirectories.Contains(Path.GetFileName(subDir)),
+            onError: (path, ex) => _logger.LogDebug(ex, "Skipping unreadable path: {Path}", path),
+            policy: _symlinkPolicy);
+
+        foreach (var fileInfo in files)
         {
-            var currentDir = directoriesToProcess.Pop();
-            totalDirsProcessed++;
-            
-            if (!Directory.Exists(currentDir))
-            {
-                _logger.LogDebug("Directory no longer exists: {Directory}", currentDir);
-                continue;
-            }
-                
-            // Skip excluded directories by checking if any part of the path contains excluded directory names
-            var relativePath = Path.GetRelativePath(directoryPath, currentDir);
-            var pathParts = relativePath.Split(Path.DirectorySeparatorChar, Path.AltDirectorySeparatorChar);
-            var shouldSkip = pathParts.Any(part => _excludedDirectories.Contains(part));
-            
-            if (shouldSkip)
-            {
-                _logger.LogDebug("Skipping excluded directory: {Directory} (contains excluded path component)", currentDir);
-                continue;
-            }
-            
-            _logger.LogTrace("Processing directory: {Directory}", currentDir);
-            
-            // Enumerate files in current directory
-            IEnumerable<string> files;
+            var file = fileInfo.FullName;
+            bool shouldInclude = false;
             try
             {
-                files = Directory.EnumerateFiles(currentDir, "*", SearchOption.TopDirectoryOnly);
-            }
-            catch (UnauthorizedAccessException)
-            {
-                _logger.LogDebug("Access denied to directory: {Directory}", currentDir);
-                continue;
-            }
-            catch (Exception ex)
-            {
-                _logger.LogWarning(ex, "Error enumerating files in directory: {Directory}", currentDir);
-                continue;
-            }
-            
-            var fileList = files.ToList();
-            _logger.LogTrace("Found {FileCount} files in {Directory}", fileList.Count, currentDir);
-            
-            // Process files in current directory
-            foreach (var file in fileList)
-            {
-                bool shouldInclude = false;
-                try
-                {
-                    var extension = Path.GetExtension(file);
-                    
-                    // Skip blacklisted extensions
-                    if (_blacklistedExtensions.Contains(extension))
-                    {
-                        _logger.LogTrace("Skipping blacklisted file type {File} (extension: {Extension})", file, extension);
-                        continue;
-                    }
-                    
-                    // Check file size
-                    var fileInfo = new FileInfo(file);
-                    if (fileInfo.Length > MAX_FILE_SIZE)
-                    {
-                        _logger.LogDebug("Skipping large file {File} ({Size} bytes)", file, fileInfo.Length);
-                        continue;
-                    }
-                    
-                    // Skip hidden/system files
-                    if ((fileInfo.Attributes & (FileAttributes.Hidden | FileAttributes.System)) != 0)
-                        continue;
-                    
-                    shouldInclude = true;
-                }
-                catch (Exception ex)
+                var extension = Path.GetExtension(file);
+                
+                // Skip blacklisted extensions
+                if (_blacklistedExtensions.Contains(extension))
                 {
-                    _logger.LogDebug(ex, "Error checking file: {File}", file);
+                    _logger.LogTrace("Skipping blacklisted file type {File} (extension: {Extension})", file, extension);
+                    continue;
                 }
                 
-                if (shouldInclude)
+                // Check file size, as the directory listing reported it
+                if (fileInfo.Length > MAX_FILE_SIZE)
                 {
-                    totalFilesFound++;
-                    yield return file;
+                    _logger.LogDebug("Skipping large file {File} ({Size} bytes)", file, fileInfo.Length);
+                    continue;
                 }
+                
+                // Skip hidden/system files
+                if ((fileInfo.Attributes & (FileAttributes.Hidden | FileAttributes.System)) != 0)
+                    continue;
+                
+                shouldInclude = true;
+            }
+            catch (Exception ex)
+            {
+                _logger.LogDebug(ex, "Error checking file: {File}", file);
             }
             
-            // Add subdirectories to process
-            IEnumerable<string> subdirectories;
-            try
+            if (shouldInclude)
+            {
+                totalFilesFound++;
+                yield return file;
+            }
+        }
+
+        _logger.LogDebug("Found {FileCount} files to index in {DirectoryPath}", totalFilesFound, directoryPath);
+    }
+
+    /// <summary>
+    /// Removes the files julie-codesearch's scan reached through links that CodeSearch:Symlinks:Policy leaves out,
+    /// so a workspace has the same files whichever platform's link handling the scan used
+    /// </summary>
+    private async Task ApplySymlinkPolicyAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        if (_sqliteSymbolService == null || !_sqliteSymbolService.DatabaseExists(workspacePath))
+            return;
+
+        try
+        {
+            var admits = PlatformPaths.SymlinkFilter(workspacePath, _symlinkPolicy);
+            var removed = 0;
+            foreach (var file in await _sqliteSymbolService.GetAllFilesAsync(workspacePath, cancellationToken))
             {
-                subdirectories = Directory.EnumerateDirectories(currentDir, "*", SearchOption.TopDirectoryOnly);
+                if (admits(file.Path))
+                    continue;
+
+                await _sqliteSymbolService.DeleteFileAsync(workspacePath, file.Path, cancellationToken);
+                removed++;
             }
-            catch (UnauthorizedAccessException)
+
+            if (removed > 0)
             {
-                _logger.LogDebug("Access denied to subdirectories of: {Directory}", currentDir);
-                continue;
+                _logger.LogInformation("Removed {Count} files reached through links (symlink policy {Policy})", removed, _symlinkPolicy);
             }
-            catch (Exception ex)
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "Failed to apply symlink policy {Policy} for {WorkspacePath}", _symlinkPolicy, workspacePath);
+        }
+    }
+
+    /// <summary>
+    /// Corrects the symbols julie-codesearch gets wrong or misses - cgo preambles and Go 1.22+ constructs, and the
+    /// declarations of the languages it extracts partly or not at all - in one pass over the files
+    /// <see cref="SymbolRepairs"/> handles
+    /// </summary>
+    private async Task RepairSymbolsAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        if (_sqliteSymbolService == null || !_sqliteSymbolService.DatabaseExists(workspacePath))
+            return;
+
+        try
+        {
+            var repaired = 0;
+            foreach (var file in await _sqliteSymbolService.GetAllFilesAsync(workspacePath, cancellationToken))
             {
-                _logger.LogWarning(ex, "Error enumerating subdirectories in: {Directory}", currentDir);
-                continue;
+                if (!SymbolRepairs.Handles(file.Path) || string.IsNullOrEmpty(file.Content))
+                    continue;
+
+                var symbols = await _sqliteSymbolService.GetSymbolsForFileAsync(workspacePath, file.Path, cancellationToken);
+                var fixedSymbols = SymbolRepairs.Repair(file.Path, file.Content, symbols);
+                if (ReferenceEquals(fixedSymbols, symbols))
+                    continue;
+
+                await _sqliteSymbolService.ReplaceFileSymbolsAsync(workspacePath, file.Path, fixedSymbols, cancellationToken);
+                repaired++;
             }
-            
-            var subDirList = subdirectories.ToList();
-            _logger.LogTrace("Found {SubDirCount} subdirectories in {Directory}", subDirList.Count, currentDir);
-            
-            foreach (var subDir in subDirList)
+
+            if (repaired > 0)
             {
-                // Check if subdirectory path contains any excluded directory names
-                var subRelativePath = Path.GetRelativePath(directoryPath, subDir);
-                var subPathParts = subRelativePath.Split(Path.DirectorySeparatorChar, Path.AltDirectorySeparatorChar);
-                var subShouldSkip = subPathParts.Any(part => _excludedDirectories.Contains(part));
-                
-                if (!subShouldSkip)
-                {
-                    _logger.LogTrace("Adding subdirectory to process: {SubDir}", subDir);
-                    directoriesToProcess.Push(subDir);
-                }
-                else
-                {
-                    _logger.LogTrace("Skipping excluded subdirectory: {SubDir} (contains excluded path component)", subDir);
-                }
+                _logger.LogInformation("Repaired symbols in {Count} files", repaired);
             }
         }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "Failed to repair symbols for {WorkspacePath}", workspacePath);
+        }
     }
 
     private async Task<Document?> CreateDocumentFromFileAsync(
@@ -748,14 +935,18 @@ public class FileIndexingService : IFileIndexingService
         try
         {
             var fileInfo = new FileInfo(filePath);
-            if (!fileInfo.Exists)
+            if (GetSkipReason(workspacePath, filePath) != null)
                 return null;
+            var dependencyDirectory = _dependencyCode?.GetDependencyDirectory(workspacePath, filePath);
 
             // Read file content
             string content;
             try
             {
-                content = await File.ReadAllTextAsync(filePath, Encoding.UTF8, cancellationToken);
+                // Reads that fail for a moment on a network share are tried again
+                content = _networkFileSystem != null
+                    ? await _networkFileSystem.RetryAsync(filePath, ct => File.ReadAllTextAsync(filePath, Encoding.UTF8, ct), cancellationToken)
+                    : await File.ReadAllTextAsync(filePath, Encoding.UTF8, cancellationToken);
             }
             catch (Exception ex)
             {
@@ -763,6 +954,28 @@ public class FileIndexingService : IFileIndexingService
                 return null;
             }
 
+            // Notebooks are indexed as their code cells, not the JSON around them; the cell ranges map hits back
+            var notebook = JupyterNotebook.Handles(filePath) ? JupyterNotebook.Parse(content) : null;
+            if (notebook != null)
+            {
+                content = notebook.Text;
+            }
+
+            // Cold-tier files are searchable by content and path only; SearchAsync reads their text from disk on a hit
+            if (_coldTier?.IsCold(workspacePath, filePath) == true)
+            {
+                var coldDocument = CreateColdDocument(filePath, workspacePath, fileInfo, content);
+                if (dependencyDirectory != null)
+                {
+                    coldDocument.Add(new StringField("origin", "dependency", Field.Store.YES));
+                }
+                if (notebook != null)
+                {
+                    coldDocument.Add(new StoredField(JupyterNotebook.CellsField, JupyterNotebook.FormatCells(notebook.Cells)));
+                }
+                return coldDocument;
+            }
+
             // Extract type information if enabled
             TypeExtractionResult? typeData = null;
             if (_configuration.GetValue("CodeSearch:TypeExtraction:Enabled", true))
@@ -793,6 +1006,39 @@ public class FileIndexingService : IFileIndexingService
                         _logger.LogDebug(ex, "Failed to extract types from SQLite for {FilePath}", filePath);
                     }
                 }
+
+                // Plugin extractors cover file types julie-codesearch doesn't parse
+                if (typeData == null && _extractorPlugins.TryGetValue(fileInfo.Extension, out var extractor))
+                {
+                    try
+                    {
+                        typeData = await extractor.ExtractAsync(filePath, content, cancellationToken);
+                    }
+                    catch (Exception ex) when (ex is not OperationCanceledException)
+                    {
+                        _logger.LogWarning(ex, "Extractor plugin {Plugin} failed for {FilePath}", extractor.Name, filePath);
+                    }
+                }
+
+                // Tree-sitter signatures drop Go type parameters, embedded fields and interface method specs; read them,
+                // instantiations and method receivers from the source, so method sets can be built from the index
+                if (typeData?.Success == true && fileInfo.Extension.Equals(".go", StringComparison.OrdinalIgnoreCase))
+                {
+                    try
+                    {
+                        GoGenerics.Enrich(typeData, content);
+                        GoMethodSets.AddMethodSets(typeData, content);
+                    }
+                    catch (Exception ex)
+                    {
+                        _logger.LogDebug(ex, "Failed to read Go generics and method sets for {FilePath}", filePath);
+                    }
+                }
+            }
+
+            if (_webhooks?.IsSubscribed(WebhookEvents.SecretsDetected) == true)
+            {
+                ReportSecrets(workspacePath, filePath, content);
             }
 
             // Get directory information
@@ -827,6 +1073,17 @@ public class FileIndexingService : IFileIndexingService
                 // Simple line count for statistics
                 new Int32Field("line_count", content.Count(c => c == '\n') + 1, Field.Store.YES)
             };
+
+            // Flagged so results from vendored and third-party code can be told apart and downranked
+            if (dependencyDirectory != null)
+            {
+                document.Add(new StringField("origin", "dependency", Field.Store.YES));
+            }
+
+            if (notebook != null)
+            {
+                document.Add(new StoredField(JupyterNotebook.CellsField, JupyterNotebook.FormatCells(notebook.Cells)));
+            }
             
             // Add type-specific fields if extraction succeeded
             if (typeData?.Success == true && (typeData.Types.Any() || typeData.Methods.Any()))
@@ -848,7 +1105,8 @@ public class FileIndexingService : IFileIndexingService
                 {
                     types = typeData.Types,
                     methods = typeData.Methods,
-                    language = typeData.Language
+                    language = typeData.Language,
+                    instantiations = typeData.Instantiations
                 }, new JsonSerializerOptions
                 {
                     PropertyNamingPolicy = JsonNamingPolicy.CamelCase,
@@ -867,6 +1125,23 @@ public class FileIndexingService : IFileIndexingService
                 document.Add(new Int32Field("method_count", typeData.Methods.Count, Field.Store.YES));
             }
 
+            // Structural summary returned with search hits
+            if (_configuration.GetValue("CodeSearch:Summaries:Enabled", true))
+            {
+                try
+                {
+                    var summary = FileSummarizer.Summarize(content, filePath, typeData);
+                    if (summary != null)
+                    {
+                        document.Add(new StoredField("file_summary", JsonSerializer.Serialize(summary)));
+                    }
+                }
+                catch (Exception ex)
+                {
+                    _logger.LogDebug(ex, "Failed to summarize {FilePath}", filePath);
+                }
+            }
+
             // Add searchable path components
             var pathParts = Path.GetRelativePath(workspacePath, filePath).Split(Path.DirectorySeparatorChar);
             foreach (var part in pathParts)
@@ -883,6 +1158,77 @@ public class FileIndexingService : IFileIndexingService
         }
     }
 
+    /// <summary>
+    /// Reduced-fidelity document for the cold tier: content postings without stored text, pattern or symbol
+    /// fields, type information or summaries
+    /// </summary>
+    private static Document CreateColdDocument(string filePath, string workspacePath, FileInfo fileInfo, string content)
+    {
+        var directoryPath = Path.GetDirectoryName(filePath) ?? "";
+        var relativePath = Path.GetRelativePath(workspacePath, filePath);
+
+        var document = new Document
+        {
+            new StringField("path", filePath, Field.Store.YES),
+            new StringField("relativePath", relativePath, Field.Store.YES),
+            new TextField("content", content, Field.Store.NO),
+            new StringField("tier", "cold", Field.Store.YES),
+            new StringField("extension", fileInfo.Extension.ToLowerInvariant(), Field.Store.YES),
+            new Int64Field("size", fileInfo.Length, Field.Store.YES),
+            new Int64Field("modified", fileInfo.LastWriteTimeUtc.Ticks, Field.Store.YES),
+            new StringField("filename", fileInfo.Name, Field.Store.YES),
+            new StringField("filename_lower", fileInfo.Name.ToLowerInvariant(), Field.Store.NO),
+            new StringField("directory", directoryPath, Field.Store.YES),
+            new StringField("relativeDirectory", Path.GetRelativePath(workspacePath, directoryPath), Field.Store.YES),
+            new StringField("directoryName", Path.GetFileName(directoryPath) ?? "", Field.Store.YES),
+            new Int32Field("line_count", content.Count(c => c == '\n') + 1, Field.Store.YES)
+        };
+
+        foreach (var part in relativePath.Split(Path.DirectorySeparatorChar))
+        {
+            document.Add(new TextField("pathComponent", part, Field.Store.NO));
+        }
+
+        return document;
+    }
+
+    /// <summary>
+    /// Scans the file for credentials and publishes a secrets.detected event for any not already
+    /// reported for this file. Scan failures are logged and never fail indexing.
+    /// </summary>
+    private void ReportSecrets(string workspacePath, string filePath, string content)
+    {
+        try
+        {
+            var secrets = SecretScanner.Scan(content);
+            var newFingerprints = _findingsBaseline?.UpdateBaseline(workspacePath, "secrets", filePath,
+                secrets.Select(s => s.Fingerprint), reportFirstSight: true)
+                ?? secrets.Select(s => s.Fingerprint).ToList();
+
+            var newSecrets = secrets.Where(s => newFingerprints.Contains(s.Fingerprint)).ToList();
+            if (newSecrets.Count == 0)
+                return;
+
+            var relativePath = Path.GetRelativePath(workspacePath, filePath);
+            _logger.LogWarning("Possible secrets in {FilePath}: {Rules}", relativePath, string.Join(", ", newSecrets.Select(s => s.Rule).Distinct()));
+            _webhooks!.Publish(new WebhookEvent
+            {
+                Event = WebhookEvents.SecretsDetected,
+                WorkspacePath = workspacePath,
+                Summary = $"{newSecrets.Count} possible secret(s) in {relativePath}",
+                Data = new
+                {
+                    FilePath = relativePath,
+                    Findings = newSecrets.Select(s => new { s.Rule, s.LineNumber, s.MaskedPreview })
+                }
+            });
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Secret scan failed for {FilePath}", filePath);
+        }
+    }
+
     /// <summary>
     /// Convert JulieSymbols from cache to TypeExtractionResult format
     /// </summary>
@@ -1069,8 +1415,38 @@ public interface IFileIndexingService
 {
     Task<IndexingResult> IndexWorkspaceAsync(string workspacePath, CancellationToken cancellationToken = default);
     Task<int> IndexDirectoryAsync(string workspacePath, string directoryPath, CancellationToken cancellationToken = default);
-    Task<bool> IndexFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Indexes one file and commits. Files left out on purpose (missing, pruned or excluded) are
+    /// <see cref="FileIndexOutcome.Skipped"/>, not <see cref="FileIndexOutcome.Failed"/>.
+    /// </summary>
+    Task<FileIndexOutcome> IndexFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default);
+
     Task<bool> RemoveFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Re-indexes files whose changes were journaled but never committed before the last shutdown.
+    /// Returns the number of files replayed.
+    /// </summary>
+    Task<int> ReplayInterruptedWritesAsync(string workspacePath, CancellationToken cancellationToken = default);
+}
+
+/// <summary>
+/// What indexing a single file did
+/// </summary>
+public enum FileIndexOutcome
+{
+    Indexed,
+
+    /// <summary>
+    /// Left out of the index on purpose; trying again gives the same answer until the file or settings change
+    /// </summary>
+    Skipped,
+
+    /// <summary>
+    /// The file should be indexed but could not be read or written; worth trying again
+    /// </summary>
+    Failed
 }
 
 /// <summary>
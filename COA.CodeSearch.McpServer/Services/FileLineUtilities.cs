@@ -1,5 +1,6 @@
 using System.Text;
 using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Logging;
 
 namespace COA.CodeSearch.McpServer.Services;
 
@@ -20,6 +21,7 @@ public static class FileLineUtilities
     {
         // Read raw bytes and detect encoding
         var bytes = await File.ReadAllBytesAsync(filePath, cancellationToken);
+        QueryUsage.RecordBytes(bytes.Length);
         var encoding = DetectEncoding(bytes);
         
         // Convert to string and split lines consistently
@@ -16,9 +16,18 @@ public class FileWatcherService : BackgroundService
     private readonly IConfiguration _configuration;
     private readonly IFileIndexingService _fileIndexingService;
     private readonly IPathResolutionService _pathResolution;
-    private readonly ConcurrentDictionary<string, FileSystemWatcher> _watchers = new();
-    private readonly ConcurrentDictionary<string, FileChangeEvent> _pendingChanges = new();
-    private readonly ConcurrentDictionary<string, PendingDelete> _pendingDeletes = new();
+    // Keyed by path, compared the way the file system compares names (case-insensitive on Windows and macOS).
+    // A FileSystemWatcher, or a PollingWatcher for workspaces on network file systems.
+    private readonly ConcurrentDictionary<string, IDisposable> _watchers = new(PlatformPaths.Comparer);
+    private readonly ConcurrentDictionary<string, FileChangeEvent> _pendingChanges = new(PlatformPaths.Comparer);
+    private readonly ConcurrentDictionary<string, PendingDelete> _pendingDeletes = new(PlatformPaths.Comparer);
+    private readonly ConcurrentDictionary<string, FileChangeEvent> _coalescing = new(PlatformPaths.Comparer);
+    private readonly ConcurrentDictionary<string, DateTime> _recordedWrites = new(PlatformPaths.Comparer);
+    // Guards updating a pending change against removing it once indexed, so no edit lands on a removed entry
+    private readonly object _pendingLock = new();
+    private readonly SemaphoreSlim _processLock = new(1, 1);
+    // Queries index pending changes first, so one that keeps failing is dropped rather than retried by every query
+    private const int MaxIndexAttempts = 3;
     private readonly BlockingCollection<FileChangeEvent> _changeQueue = new();
     private readonly ConcurrentQueue<RetryQueueItem> _retryQueue = new();
     private Timer? _retryTimer;
@@ -27,20 +36,28 @@ public class FileWatcherService : BackgroundService
     private readonly Sqlite.ISQLiteSymbolService? _sqliteService;
     private readonly Julie.IJulieCodeSearchService? _julieCodeSearchService;
     private readonly Julie.ISemanticIntelligenceService? _semanticIntelligenceService;
+    private readonly Lucene.ILuceneIndexService? _luceneIndexService;
+    private readonly Watches.IWatchService? _watchService;
+    private readonly WorkingSet.IWorkingSetService? _workingSet;
+    private readonly NetworkFileSystem.INetworkFileSystemService? _networkFileSystem;
     // Timing configuration
     private readonly TimeSpan _debounceInterval;
     private readonly TimeSpan _deleteQuietPeriod;
     private readonly TimeSpan _atomicWriteWindow;
+    private readonly TimeSpan _coalesceQuietPeriod;
+    private readonly TimeSpan _coalesceMaxDelay;
     private readonly int _batchSize;
     
     // Blacklisted extensions (changed from whitelist to blacklist to match FileIndexingService)
     private readonly HashSet<string> _blacklistedExtensions;
     private readonly HashSet<string> _excludedDirectories;
+    private readonly SymlinkPolicy _symlinkPolicy;
     
     // Self-starting background task
     private bool _backgroundTaskStarted = false;
     private readonly object _startLock = new object();
     private Task? _executeTask;
+    private CancellationTokenSource? _executeCts;
 
     public FileWatcherService(
         ILogger<FileWatcherService> logger,
@@ -58,11 +75,17 @@ public class FileWatcherService : BackgroundService
         _sqliteService = serviceProvider.GetService<Sqlite.ISQLiteSymbolService>();
         _julieCodeSearchService = serviceProvider.GetService<Julie.IJulieCodeSearchService>();
         _semanticIntelligenceService = serviceProvider.GetService<Julie.ISemanticIntelligenceService>();
+        _luceneIndexService = serviceProvider.GetService<Lucene.ILuceneIndexService>();
+        _watchService = serviceProvider.GetService<Watches.IWatchService>();
+        _workingSet = serviceProvider.GetService<WorkingSet.IWorkingSetService>();
+        _networkFileSystem = serviceProvider.GetService<NetworkFileSystem.INetworkFileSystemService>();
 
         // Configure timing based on lessons learned
         _debounceInterval = TimeSpan.FromMilliseconds(configuration.GetValue("CodeSearch:FileWatcher:DebounceMilliseconds", 500));
         _deleteQuietPeriod = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:FileWatcher:DeleteQuietPeriodSeconds", 5));
         _atomicWriteWindow = TimeSpan.FromMilliseconds(configuration.GetValue("CodeSearch:FileWatcher:AtomicWriteWindowMs", 100));
+        _coalesceQuietPeriod = TimeSpan.FromMilliseconds(configuration.GetValue("CodeSearch:FileWatcher:CoalesceQuietMilliseconds", 750));
+        _coalesceMaxDelay = TimeSpan.FromMilliseconds(configuration.GetValue("CodeSearch:FileWatcher:CoalesceMaxDelayMilliseconds", 10000));
         _batchSize = configuration.GetValue("CodeSearch:FileWatcher:BatchSize", 50);
         
         // Load blacklisted extensions (using same source as FileIndexingService)
@@ -74,9 +97,10 @@ public class FileWatcherService : BackgroundService
         var excluded = configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>()
             ?? new[] { "node_modules", ".git", "bin", "obj", "dist", "build", ".vs", ".vscode" };
         _excludedDirectories = new HashSet<string>(excluded, StringComparer.OrdinalIgnoreCase);
+        _symlinkPolicy = PlatformPaths.ParseSymlinkPolicy(configuration["CodeSearch:Symlinks:Policy"]);
         
-        _logger.LogInformation("FileWatcher configured - Debounce: {Debounce}ms, Delete quiet: {DeleteQuiet}s, Atomic window: {AtomicWindow}ms",
-            _debounceInterval.TotalMilliseconds, _deleteQuietPeriod.TotalSeconds, _atomicWriteWindow.TotalMilliseconds);
+        _logger.LogInformation("FileWatcher configured - Debounce: {Debounce}ms, Delete quiet: {DeleteQuiet}s, Atomic window: {AtomicWindow}ms, Coalesce quiet: {CoalesceQuiet}ms",
+            _debounceInterval.TotalMilliseconds, _deleteQuietPeriod.TotalSeconds, _atomicWriteWindow.TotalMilliseconds, _coalesceQuietPeriod.TotalMilliseconds);
 
         // Start retry timer (process locked updates every 2 seconds)
         _retryTimer = new Timer(ProcessRetryQueue, null, TimeSpan.FromSeconds(2), TimeSpan.FromSeconds(2));
@@ -88,6 +112,8 @@ public class FileWatcherService : BackgroundService
         // This solves the dual ServiceProvider issue - the background task runs on the same instance
         EnsureBackgroundTaskStarted();
         
+        // One watcher per directory on disk, however its path was spelled
+        workspacePath = PlatformPaths.Canonicalize(workspacePath);
         if (_watchers.ContainsKey(workspacePath))
         {
             _logger.LogDebug("Already watching workspace: {Workspace}", workspacePath);
@@ -96,6 +122,29 @@ public class FileWatcherService : BackgroundService
 
         try
         {
+            // Change notifications over NFS and SMB arrive late, partly or not at all
+            if (_networkFileSystem?.AppliesTo(workspacePath) == true)
+            {
+                var poller = new NetworkFileSystem.PollingWatcher(
+                    workspacePath,
+                    _networkFileSystem.PollInterval,
+                    (path, changeType) => HandleFileEvent(workspacePath, path, changeType),
+                    enterDirectory: directory => !_excludedDirectories.Contains(Path.GetFileName(directory)),
+                    policy: _symlinkPolicy,
+                    onError: ex => _logger.LogWarning(ex, "Polling {Workspace} for changes failed", workspacePath));
+
+                if (_watchers.TryAdd(workspacePath, poller))
+                {
+                    _logger.LogInformation("Started polling workspace every {Interval}s (network file system): {Workspace}",
+                        _networkFileSystem.PollInterval.TotalSeconds, workspacePath);
+                }
+                else
+                {
+                    poller.Dispose();
+                }
+                return;
+            }
+
             var watcher = new FileSystemWatcher(workspacePath)
             {
                 NotifyFilter = NotifyFilters.FileName | NotifyFilters.LastWrite | NotifyFilters.Size | NotifyFilters.DirectoryName,
@@ -128,9 +177,10 @@ public class FileWatcherService : BackgroundService
     
     public void StopWatching(string workspacePath)
     {
-        if (_watchers.TryRemove(workspacePath, out var watcher))
+        if (_watchers.TryRemove(PlatformPaths.Canonicalize(workspacePath), out var watcher))
         {
-            watcher.EnableRaisingEvents = false;
+            if (watcher is FileSystemWatcher fileSystemWatcher)
+                fileSystemWatcher.EnableRaisingEvents = false;
             watcher.Dispose();
             _logger.LogInformation("Stopped watching workspace: {Workspace}", workspacePath);
         }
@@ -138,11 +188,19 @@ public class FileWatcherService : BackgroundService
 
     private void HandleFileEvent(string workspacePath, string filePath, FileChangeType changeType)
     {
+        filePath = PlatformPaths.Normalize(filePath);
+
         // Filter out unsupported files
         if (!IsFileSupported(filePath))
         {
             return;
         }
+
+        // Changes seen through a link the symlink policy doesn't walk; deletes pass, the path may be gone
+        if (changeType != FileChangeType.Deleted && !PlatformPaths.SymlinkFilter(workspacePath, _symlinkPolicy)(filePath))
+        {
+            return;
+        }
         
 
         var changeEvent = new FileChangeEvent
@@ -160,6 +218,16 @@ public class FileWatcherService : BackgroundService
         }
         else
         {
+            // The echo of a write this server already recorded (see RecordWrite)
+            if (_recordedWrites.TryGetValue(filePath, out var recordedAt))
+            {
+                if (File.GetLastWriteTimeUtc(filePath) == recordedAt)
+                {
+                    return;
+                }
+                _recordedWrites.TryRemove(filePath, out _);
+            }
+
             // For creates/modifies, cancel any pending delete for this file
             if (_pendingDeletes.TryGetValue(filePath, out var pendingDelete))
             {
@@ -168,28 +236,34 @@ public class FileWatcherService : BackgroundService
             }
 
             // Deduplicate using _pendingChanges to prevent duplicate processing
-            if (_pendingChanges.TryGetValue(filePath, out var existingEvent))
+            lock (_pendingLock)
             {
-                // Update existing event with latest timestamp and change type
-                existingEvent.Timestamp = changeEvent.Timestamp;
-                existingEvent.ChangeType = changeEvent.ChangeType;
-                _logger.LogDebug("Updated pending {ChangeType} event for: {FilePath} (deduped)", changeType, filePath);
-            }
-            else
-            {
-                // New file event - add to pending dictionary and queue
-                if (_pendingChanges.TryAdd(filePath, changeEvent))
+                if (_pendingChanges.TryGetValue(filePath, out var existingEvent))
                 {
-                    // Add to queue to signal batch processor
-                    if (_changeQueue.TryAdd(changeEvent))
-                    {
-                        _logger.LogDebug("Queued {ChangeType} event for: {FilePath}", changeType, filePath);
-                    }
-                    else
+                    // Update existing event with latest timestamp and change type. It may have left the queue already -
+                    // indexed while this edit was made, or failed to index - so it is held back until the burst ends.
+                    existingEvent.Timestamp = changeEvent.Timestamp;
+                    existingEvent.ChangeType = changeEvent.ChangeType;
+                    existingEvent.FailedAttempts = 0;
+                    _coalescing[filePath] = existingEvent;
+                    _logger.LogDebug("Updated pending {ChangeType} event for: {FilePath} (deduped)", changeType, filePath);
+                }
+                else
+                {
+                    // New file event - add to pending dictionary and queue
+                    if (_pendingChanges.TryAdd(filePath, changeEvent))
                     {
-                        // Failed to queue - remove from pending
-                        _pendingChanges.TryRemove(filePath, out _);
-                        _logger.LogWarning("Failed to queue {ChangeType} event for: {FilePath}", changeType, filePath);
+                        // Add to queue to signal batch processor
+                        if (_changeQueue.TryAdd(changeEvent))
+                        {
+                            _logger.LogDebug("Queued {ChangeType} event for: {FilePath}", changeType, filePath);
+                        }
+                        else
+                        {
+                            // Failed to queue - remove from pending
+                            _pendingChanges.TryRemove(filePath, out _);
+                            _logger.LogWarning("Failed to queue {ChangeType} event for: {FilePath}", changeType, filePath);
+                        }
                     }
                 }
             }
@@ -333,10 +407,21 @@ public class FileWatcherService : BackgroundService
                     }
                 }
 
-                if (batch.Count > 0)
+                await _processLock.WaitAsync(stoppingToken);
+                try
                 {
-                    _logger.LogDebug("Processing batch of {Count} file changes", batch.Count);
-                    await ProcessBatchAsync(batch, stoppingToken);
+                    if (batch.Count > 0)
+                    {
+                        _logger.LogDebug("Processing batch of {Count} file changes", batch.Count);
+                        await ProcessBatchAsync(batch, stoppingToken);
+                    }
+
+                    // Index files whose burst of edits has ended
+                    await ProcessCoalescedAsync(stoppingToken);
+                }
+                finally
+                {
+                    _processLock.Release();
                 }
 
                 // Check for expired pending deletes
@@ -375,48 +460,221 @@ public class FileWatcherService : BackgroundService
             var deletes = coalescedEvents.Where(c => c.ChangeType == FileChangeType.Deleted).ToList();
             var updates = coalescedEvents.Where(c => c.ChangeType != FileChangeType.Deleted).ToList();
 
-            // Process updates first
+            // Hold back files still being edited, so a burst of edits is indexed once when it ends
+            var now = DateTime.UtcNow;
+            var ready = new List<FileChangeEvent>();
             foreach (var update in updates)
             {
-                try
+                if (!_pendingChanges.TryGetValue(update.FilePath, out var pending))
                 {
-                    // Cancel any pending delete for this file
-                    if (_pendingDeletes.TryGetValue(update.FilePath, out var pendingDelete))
-                    {
-                        pendingDelete.Cancelled = true;
-                    }
+                    // Already indexed by IndexPendingChangesAsync
+                    continue;
+                }
 
-                    // Phoenix: Update SQLite FIRST (source of truth)
-                    await UpdateSQLiteForFileAsync(workspacePath, update.FilePath, cancellationToken);
+                if (IsBurstActive(pending, now))
+                {
+                    _coalescing[update.FilePath] = pending;
+                    continue;
+                }
 
-                    // Then update Lucene (reads from SQLite)
-                    var indexed = await _fileIndexingService.IndexFileAsync(workspacePath, update.FilePath, cancellationToken);
-                    if (indexed)
-                    {
+                _coalescing.TryRemove(update.FilePath, out _);
+                ready.Add(update);
+            }
+
+            await IndexUpdatesAsync(workspacePath, ready, cancellationToken);
+
+            // Process deletes (will be verified in ProcessPendingDeletesAsync)
+            foreach (var delete in deletes)
+            {
+                _logger.LogDebug("Delete queued for verification: {FilePath}", delete.FilePath);
+            }
+        }
+    }
+
+    private async Task IndexUpdatesAsync(string workspacePath, List<FileChangeEvent> updates, CancellationToken cancellationToken)
+    {
+        foreach (var update in updates)
+        {
+            // Edits made from here on change the pending entry's timestamp and need indexing again
+            var indexedFrom = _pendingChanges.TryGetValue(update.FilePath, out var pending) ? pending.Timestamp : update.Timestamp;
+
+            try
+            {
+                // Cancel any pending delete for this file
+                if (_pendingDeletes.TryGetValue(update.FilePath, out var pendingDelete))
+                {
+                    pendingDelete.Cancelled = true;
+                }
+
+                // Watches compare against the content SQLite held before this update
+                var previousContent = await GetPreviousContentForWatchesAsync(workspacePath, update.FilePath, cancellationToken);
+
+                // Phoenix: Update SQLite FIRST (source of truth)
+                await UpdateSQLiteForFileAsync(workspacePath, update.FilePath, cancellationToken);
+
+                // Then update Lucene (reads from SQLite)
+                var outcome = await _fileIndexingService.IndexFileAsync(workspacePath, update.FilePath, cancellationToken);
+                switch (outcome)
+                {
+                    case FileIndexOutcome.Indexed:
                         _logger.LogDebug("Successfully updated in index: {FilePath} ({ChangeType})",
                             update.FilePath, update.ChangeType);
-                    }
-                    else
-                    {
+                        break;
+                    case FileIndexOutcome.Skipped:
+                        _logger.LogDebug("Left out of the index: {FilePath} ({ChangeType})",
+                            update.FilePath, update.ChangeType);
+                        break;
+                    default:
                         _logger.LogWarning("Failed to index file: {FilePath} ({ChangeType})",
                             update.FilePath, update.ChangeType);
-                    }
+                        break;
+                }
 
-                    // Clear from pending changes after successful processing
-                    _pendingChanges.TryRemove(update.FilePath, out _);
+                if (previousContent.Evaluate)
+                {
+                    await _watchService!.EvaluateAsync(workspacePath, update.FilePath, previousContent.Content, cancellationToken);
                 }
-                catch (Exception ex)
+
+                // Indexed and skipped files are done with, unless the file was edited meanwhile; failures are retried
+                if (outcome == FileIndexOutcome.Failed)
                 {
-                    _logger.LogError(ex, "Failed to update file in index: {FilePath}", update.FilePath);
-                    // Don't clear from pending on error - allow retry
+                    RecordFailedAttempt(update.FilePath, indexedFrom);
+                }
+                else
+                {
+                    CompletePendingChange(update.FilePath, indexedFrom);
                 }
             }
+            catch (Exception ex)
+            {
+                _logger.LogError(ex, "Failed to update file in index: {FilePath}", update.FilePath);
+                RecordFailedAttempt(update.FilePath, indexedFrom);
+            }
+        }
+    }
 
-            // Process deletes (will be verified in ProcessPendingDeletesAsync)
-            foreach (var delete in deletes)
+    /// <summary>
+    /// Counts a failed attempt to index a file's pending change. After <see cref="MaxIndexAttempts"/> the change
+    /// is dropped, so queries stop re-indexing a file that keeps failing; it is tried again when it next changes.
+    /// </summary>
+    private void RecordFailedAttempt(string filePath, DateTime indexedFrom)
+    {
+        int attempts;
+        lock (_pendingLock)
+        {
+            // An edit since then has attempts of its own
+            if (!_pendingChanges.TryGetValue(filePath, out var pending) || pending.Timestamp != indexedFrom)
+                return;
+
+            attempts = ++pending.FailedAttempts;
+            if (attempts < MaxIndexAttempts)
+                return;
+
+            _pendingChanges.TryRemove(filePath, out _);
+            _coalescing.TryRemove(filePath, out _);
+        }
+
+        _logger.LogWarning("Gave up indexing {FilePath} after {Attempts} failed attempts; it is indexed again when it next changes",
+            filePath, attempts);
+    }
+
+    /// <summary>
+    /// Removes a file's pending change once it is indexed, unless an edit arrived after <paramref name="indexedFrom"/>;
+    /// that edit stays pending and starts a new burst
+    /// </summary>
+    private void CompletePendingChange(string filePath, DateTime indexedFrom)
+    {
+        lock (_pendingLock)
+        {
+            if (!_pendingChanges.TryGetValue(filePath, out var pending))
+                return;
+
+            if (pending.Timestamp == indexedFrom)
+                _pendingChanges.TryRemove(filePath, out _);
+            else
+                pending.FirstSeen = DateTime.UtcNow;
+        }
+    }
+
+    /// <summary>
+    /// A file is still being edited while changes keep arriving within the quiet period, up to the max delay
+    /// after the first one so that a file edited continuously is still indexed now and then
+    /// </summary>
+    private bool IsBurstActive(FileChangeEvent pending, DateTime now) =>
+        now - pending.Timestamp < _coalesceQuietPeriod && now - pending.FirstSeen < _coalesceMaxDelay;
+
+    private async Task ProcessCoalescedAsync(CancellationToken cancellationToken)
+    {
+        if (_coalescing.IsEmpty)
+            return;
+
+        var now = DateTime.UtcNow;
+        var ready = new List<FileChangeEvent>();
+        foreach (var (filePath, pending) in _coalescing)
+        {
+            if (!IsBurstActive(pending, now) && _coalescing.TryRemove(filePath, out _))
             {
-                _logger.LogDebug("Delete queued for verification: {FilePath}", delete.FilePath);
+                ready.Add(pending);
+            }
+        }
+
+        foreach (var group in ready.GroupBy(c => c.WorkspacePath))
+        {
+            _logger.LogDebug("Indexing {Count} file(s) after their edits settled", group.Count());
+            await IndexUpdatesAsync(group.Key, group.ToList(), cancellationToken);
+        }
+    }
+
+    /// <summary>
+    /// Records a file this server's own tools just wrote, without waiting for the file system to report it, so
+    /// the next query on its workspace is guaranteed to index it first. Files outside watched workspaces, and
+    /// deletions, are left to the watcher. The file also joins the session's working set as an edit.
+    /// </summary>
+    public void RecordWrite(string filePath)
+    {
+        _workingSet?.RecordFile(filePath, WorkingSet.WorkingSetActivity.Edited);
+
+        var fullPath = PlatformPaths.Normalize(filePath);
+        var workspace = _watchers.Keys.FirstOrDefault(w => PlatformPaths.IsUnder(fullPath, w));
+        if (workspace == null || !File.Exists(fullPath))
+            return;
+
+        HandleFileEvent(workspace, fullPath, FileChangeType.Modified);
+        _recordedWrites[fullPath] = File.GetLastWriteTimeUtc(fullPath);
+    }
+
+    /// <summary>
+    /// Indexes changes to a workspace that are queued or held back while their files are being edited, without
+    /// waiting for the burst to end, and waits for any batch being indexed. Queries call this first so they
+    /// see edits made just before them, including every write recorded by <see cref="RecordWrite"/>. Returns the
+    /// number of files indexed.
+    /// </summary>
+    public async Task<int> IndexPendingChangesAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        bool IsInWorkspace(FileChangeEvent change) => PlatformPaths.PathEquals(change.WorkspacePath, workspacePath);
+
+        if (!_pendingChanges.Values.Any(IsInWorkspace) && _processLock.CurrentCount > 0)
+            return 0;
+
+        await _processLock.WaitAsync(cancellationToken);
+        try
+        {
+            var updates = _pendingChanges.Values.Where(IsInWorkspace).ToList();
+            foreach (var update in updates)
+            {
+                _coalescing.TryRemove(update.FilePath, out _);
             }
+
+            if (updates.Count > 0)
+            {
+                _logger.LogDebug("Indexing {Count} pending change(s) in {Workspace} ahead of a query", updates.Count, workspacePath);
+                await IndexUpdatesAsync(updates[0].WorkspacePath, updates, cancellationToken);
+            }
+            return updates.Count;
+        }
+        finally
+        {
+            _processLock.Release();
         }
     }
 
@@ -454,7 +712,7 @@ public class FileWatcherService : BackgroundService
                 _pendingDeletes.TryRemove(pending.FilePath, out _);
                 
                 // Get workspace for this file
-                var workspace = _watchers.Keys.FirstOrDefault(w => pending.FilePath.StartsWith(w));
+                var workspace = _watchers.Keys.FirstOrDefault(w => PlatformPaths.IsUnder(pending.FilePath, w));
                 if (workspace != null)
                 {
                     try
@@ -476,7 +734,7 @@ public class FileWatcherService : BackgroundService
                 // File really is deleted - remove from index
                 _pendingDeletes.TryRemove(pending.FilePath, out _);
                 
-                var workspace = _watchers.Keys.FirstOrDefault(w => pending.FilePath.StartsWith(w));
+                var workspace = _watchers.Keys.FirstOrDefault(w => PlatformPaths.IsUnder(pending.FilePath, w));
                 if (workspace != null)
                 {
                     try
@@ -563,7 +821,7 @@ public class FileWatcherService : BackgroundService
         // Try to recover by recreating the watcher
         if (sender is FileSystemWatcher watcher)
         {
-            var workspace = _watchers.FirstOrDefault(x => x.Value == watcher).Key;
+            var workspace = _watchers.FirstOrDefault(x => ReferenceEquals(x.Value, watcher)).Key;
             if (workspace != null)
             {
                 _logger.LogInformation("Attempting to recover watcher for {Workspace}", workspace);
@@ -585,12 +843,72 @@ public class FileWatcherService : BackgroundService
             
             // Start ExecuteAsync if it's not already running
             // This ensures we use the same instance that receives events
-            var cts = new CancellationTokenSource();
-            _executeTask = ExecuteAsync(cts.Token);
+            _executeCts = new CancellationTokenSource();
+            _executeTask = ExecuteAsync(_executeCts.Token);
             _backgroundTaskStarted = true;
         }
     }
     
+    /// <summary>
+    /// Stops all watchers and hands changes that were queued but not yet indexed to the index write journal,
+    /// so they are replayed on the next start instead of being lost. Returns the number of files journaled.
+    /// </summary>
+    public int FlushPendingChanges()
+    {
+        var workspaces = _watchers.Keys.ToList();
+        foreach (var workspace in workspaces)
+        {
+            StopWatching(workspace);
+        }
+
+        if (_luceneIndexService == null)
+            return 0;
+
+        var pending = new List<(string WorkspacePath, string FilePath)>();
+        while (_changeQueue.TryTake(out var change))
+        {
+            pending.Add((change.WorkspacePath, change.FilePath));
+        }
+        pending.AddRange(_pendingChanges.Values.Select(c => (c.WorkspacePath, c.FilePath)));
+        while (_retryQueue.TryDequeue(out var retry))
+        {
+            pending.Add((retry.WorkspacePath, retry.FilePath));
+        }
+
+        // Pending deletes don't carry their workspace; attribute them to the watched root that contains them
+        foreach (var delete in _pendingDeletes.Values.Where(d => !d.Cancelled))
+        {
+            var workspace = workspaces.FirstOrDefault(w => PlatformPaths.IsUnder(delete.FilePath, w));
+            if (workspace != null)
+            {
+                pending.Add((workspace, delete.FilePath));
+            }
+        }
+
+        var journaled = 0;
+        foreach (var group in pending.GroupBy(p => p.WorkspacePath))
+        {
+            var files = group.Select(p => p.FilePath).Distinct().ToList();
+            try
+            {
+                if (_luceneIndexService.JournalPendingWrites(group.Key, files))
+                {
+                    journaled += files.Count;
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogWarning(ex, "Failed to journal {Count} pending change(s) for {Workspace}", files.Count, group.Key);
+            }
+        }
+
+        _pendingChanges.Clear();
+        _coalescing.Clear();
+        _recordedWrites.Clear();
+        _pendingDeletes.Clear();
+        return journaled;
+    }
+    
     public override void Dispose()
     {
         // Stop all watchers
@@ -600,6 +918,7 @@ public class FileWatcherService : BackgroundService
         }
 
         _retryTimer?.Dispose();
+        _executeCts?.Cancel();
         _changeQueue?.Dispose();
         base.Dispose();
     }
@@ -656,6 +975,50 @@ public class FileWatcherService : BackgroundService
         }
     }
 
+    /// <summary>
+    /// Content of a file before it is re-indexed, for watch evaluation. Evaluate is false when the workspace has
+    /// no watches or there is no symbol database to tell new lines from existing ones.
+    /// </summary>
+    private async Task<(bool Evaluate, string? Content)> GetPreviousContentForWatchesAsync(
+        string workspacePath, string filePath, CancellationToken cancellationToken)
+    {
+        if (_watchService?.HasWatches(workspacePath) != true || _sqliteService == null || !_sqliteService.DatabaseExists(workspacePath))
+            return (false, null);
+
+        try
+        {
+            var record = await _sqliteService.GetFileByPathAsync(workspacePath, filePath, cancellationToken);
+            return (true, record?.Content);
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Could not read previous content of {FilePath} for watches", filePath);
+            return (false, null);
+        }
+    }
+
+    /// <summary>
+    /// Corrects the symbols julie-codesearch gets wrong or misses in a changed file (see <see cref="Analysis.SymbolRepairs"/>)
+    /// </summary>
+    private async Task RepairSymbolsAsync(string workspacePath, string filePath, CancellationToken cancellationToken)
+    {
+        if (_sqliteService == null || !Analysis.SymbolRepairs.Handles(filePath))
+            return;
+
+        try
+        {
+            var content = await File.ReadAllTextAsync(filePath, cancellationToken);
+            var symbols = await _sqliteService.GetSymbolsForFileAsync(workspacePath, filePath, cancellationToken);
+            var repaired = Analysis.SymbolRepairs.Repair(filePath, content, symbols);
+            if (!ReferenceEquals(repaired, symbols))
+                await _sqliteService.ReplaceFileSymbolsAsync(workspacePath, filePath, repaired, cancellationToken);
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "Phoenix: Failed to repair symbols for {FilePath}", filePath);
+        }
+    }
+
     /// <summary>
     /// Phoenix: Update SQLite database with symbols from a changed file using julie-codesearch
     /// </summary>
@@ -692,6 +1055,8 @@ public class FileWatcherService : BackgroundService
                         result.SymbolCount,
                         result.ElapsedMs);
 
+                    await RepairSymbolsAsync(workspacePath, filePath, cancellationToken);
+
                     // Update embeddings incrementally if semantic service is available
                     if (_semanticIntelligenceService?.IsAvailable() == true)
                     {
@@ -878,6 +1243,7 @@ public class FileWatcherService : BackgroundService
                 {
                     _logger.LogInformation("Phoenix: Retry succeeded for {FilePath} (attempt {Attempt})",
                         item.FilePath, item.AttemptCount);
+                    await RepairSymbolsAsync(item.WorkspacePath, item.FilePath, CancellationToken.None);
                 }
                 else if (result.ErrorMessage?.Contains("database is locked") == true)
                 {
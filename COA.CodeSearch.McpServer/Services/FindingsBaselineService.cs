@@ -0,0 +1,144 @@
+using System.Text.Json;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Keeps finding baselines in findings-baseline.json beside each workspace index.
+/// Baselines that only gained files without new findings are written when flushed, so indexing doesn't
+/// rewrite the whole file for every file it sees for the first time.
+/// </summary>
+public class FindingsBaselineService : IFindingsBaselineService, IDisposable
+{
+    private const string BaselineFileName = "findings-baseline.json";
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILogger<FindingsBaselineService> _logger;
+    private readonly object _sync = new();
+
+    // workspace -> category -> file -> fingerprints
+    private readonly Dictionary<string, Dictionary<string, Dictionary<string, HashSet<string>>>> _baselines =
+        new(StringComparer.OrdinalIgnoreCase);
+    // Workspaces with baseline changes not yet written
+    private readonly HashSet<string> _dirty = new(StringComparer.OrdinalIgnoreCase);
+
+    public FindingsBaselineService(IPathResolutionService pathResolution, ILogger<FindingsBaselineService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+    }
+
+    public IReadOnlyList<string> UpdateBaseline(string workspacePath, string category, string filePath,
+        IEnumerable<string> fingerprints, bool reportFirstSight)
+    {
+        var current = new HashSet<string>(fingerprints, StringComparer.Ordinal);
+
+        lock (_sync)
+        {
+            var workspace = GetWorkspaceBaseline(workspacePath);
+            if (!workspace.TryGetValue(category, out var files))
+            {
+                files = new Dictionary<string, HashSet<string>>(StringComparer.OrdinalIgnoreCase);
+                workspace[category] = files;
+            }
+
+            List<string> added;
+            if (files.TryGetValue(filePath, out var previous))
+            {
+                added = current.Where(f => !previous.Contains(f)).ToList();
+                if (added.Count == 0 && previous.SetEquals(current))
+                    return added;
+            }
+            else
+            {
+                added = reportFirstSight ? current.ToList() : new List<string>();
+            }
+
+            files[filePath] = current;
+            if (added.Count > 0)
+            {
+                // Written straight away so a restart doesn't report the same findings again
+                Save(workspacePath, workspace);
+                _dirty.Remove(workspacePath);
+            }
+            else
+            {
+                _dirty.Add(workspacePath);
+            }
+            return added;
+        }
+    }
+
+    public void Flush(string workspacePath)
+    {
+        lock (_sync)
+        {
+            if (_dirty.Remove(workspacePath) && _baselines.TryGetValue(workspacePath, out var workspace))
+            {
+                Save(workspacePath, workspace);
+            }
+        }
+    }
+
+    public void Dispose()
+    {
+        lock (_sync)
+        {
+            foreach (var workspacePath in _dirty)
+            {
+                Save(workspacePath, _baselines[workspacePath]);
+            }
+            _dirty.Clear();
+        }
+    }
+
+    private Dictionary<string, Dictionary<string, HashSet<string>>> GetWorkspaceBaseline(string workspacePath)
+    {
+        if (_baselines.TryGetValue(workspacePath, out var baseline))
+            return baseline;
+
+        baseline = new Dictionary<string, Dictionary<string, HashSet<string>>>(StringComparer.OrdinalIgnoreCase);
+        var path = GetBaselinePath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+            {
+                var stored = JsonSerializer.Deserialize<Dictionary<string, Dictionary<string, string[]>>>(File.ReadAllText(path));
+                foreach (var (category, files) in stored ?? new())
+                {
+                    baseline[category] = files.ToDictionary(
+                        f => f.Key,
+                        f => new HashSet<string>(f.Value, StringComparer.Ordinal),
+                        StringComparer.OrdinalIgnoreCase);
+                }
+            }
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read findings baseline {Path} - starting a new baseline", path);
+        }
+
+        _baselines[workspacePath] = baseline;
+        return baseline;
+    }
+
+    private void Save(string workspacePath, Dictionary<string, Dictionary<string, HashSet<string>>> baseline)
+    {
+        var path = GetBaselinePath(workspacePath);
+        try
+        {
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            var serializable = baseline.ToDictionary(
+                c => c.Key,
+                c => c.Value.Where(f => f.Value.Count > 0).ToDictionary(f => f.Key, f => f.Value.OrderBy(x => x, StringComparer.Ordinal).ToArray()));
+            File.WriteAllText(path, JsonSerializer.Serialize(serializable));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not save findings baseline {Path}", path);
+        }
+    }
+
+    private string GetBaselinePath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), BaselineFileName);
+}
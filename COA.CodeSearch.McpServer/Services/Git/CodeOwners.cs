@@ -0,0 +1,111 @@
+using System.Text;
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// A parsed CODEOWNERS file (GitHub/GitLab syntax). The last matching rule wins.
+/// </summary>
+public class CodeOwners
+{
+    /// <summary>
+    /// Locations searched, relative to the repository root, in GitHub's precedence order
+    /// </summary>
+    public static readonly string[] Locations = { ".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS" };
+
+    private readonly List<(string Pattern, Regex Regex, List<string> Owners)> _rules = new();
+
+    public CodeOwners(string content, string? sourcePath = null)
+    {
+        SourcePath = sourcePath;
+
+        foreach (var rawLine in content.Split('\n'))
+        {
+            var line = rawLine.Trim();
+            // Skip comments and GitLab [Section] headers
+            if (line.Length == 0 || line.StartsWith('#') || line.StartsWith('[') || line.StartsWith("^["))
+                continue;
+
+            var commentStart = line.IndexOf(" #", StringComparison.Ordinal);
+            if (commentStart >= 0)
+                line = line[..commentStart];
+
+            var parts = line.Split((char[]?)null, StringSplitOptions.RemoveEmptyEntries);
+            _rules.Add((parts[0], ToRegex(parts[0]), parts.Skip(1).ToList()));
+        }
+    }
+
+    public string? SourcePath { get; }
+
+    public int RuleCount => _rules.Count;
+
+    /// <summary>
+    /// Loads the first CODEOWNERS file found in the repository, or null if there is none
+    /// </summary>
+    public static CodeOwners? Load(string repositoryRoot)
+    {
+        foreach (var location in Locations)
+        {
+            var path = Path.Combine(repositoryRoot, location);
+            if (File.Exists(path))
+                return new CodeOwners(File.ReadAllText(path), location);
+        }
+
+        return null;
+    }
+
+    /// <summary>
+    /// Owners of a path relative to the repository root. An empty list means the path is
+    /// unowned (or explicitly has no owners).
+    /// </summary>
+    public IReadOnlyList<string> GetOwners(string repositoryRelativePath)
+    {
+        var path = repositoryRelativePath.Replace('\\', '/').TrimStart('/');
+        for (var i = _rules.Count - 1; i >= 0; i--)
+        {
+            if (_rules[i].Regex.IsMatch(path))
+                return _rules[i].Owners;
+        }
+
+        return Array.Empty<string>();
+    }
+
+    /// <summary>
+    /// Converts a gitignore-style pattern: a leading or inner slash anchors it to the root,
+    /// otherwise it matches at any depth; a match on a directory covers everything below it
+    /// </summary>
+    private static Regex ToRegex(string pattern)
+    {
+        var anchored = pattern.StartsWith('/') || pattern.TrimEnd('/').Contains('/');
+        var body = pattern.Trim('/');
+
+        var regex = new StringBuilder(anchored ? "^" : "^(?:.*/)?");
+        for (var i = 0; i < body.Length; i++)
+        {
+            var c = body[i];
+            if (c == '*' && i + 1 < body.Length && body[i + 1] == '*')
+            {
+                // "**/" matches zero or more directories, a trailing "**" everything below
+                if (i + 2 < body.Length && body[i + 2] == '/')
+                {
+                    regex.Append("(?:.*/)?");
+                    i += 2;
+                }
+                else
+                {
+                    regex.Append(".*");
+                    i++;
+                }
+            }
+            else if (c == '*')
+                regex.Append("[^/]*");
+            else if (c == '?')
+                regex.Append("[^/]");
+            else
+                regex.Append(Regex.Escape(c.ToString()));
+        }
+        regex.Append("(?:/.*)?$");
+
+        return new Regex(regex.ToString(), RegexOptions.Compiled | RegexOptions.CultureInvariant);
+    }
+}
@@ -0,0 +1,109 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// Declarations of a changed file before and after a diff
+/// </summary>
+public record ScannedFile(string? OldPath, IReadOnlyList<Declaration> Old, string NewPath, IReadOnlyList<Declaration> New);
+
+/// <summary>
+/// Reads both versions of changed files from git and scans their declarations for <see cref="SymbolDiffer"/>
+/// </summary>
+public static class GitDeclarationReader
+{
+    private const long MaxFileBytes = 1024 * 1024;
+
+    /// <summary>
+    /// Scans the old (fromRef) and new (toRef, or the working tree when null) version of each changed source file.
+    /// Files that are binary, unsupported, over 1 MB or unreadable are skipped and counted.
+    /// </summary>
+    public static async Task<(List<ScannedFile> Files, int Skipped)> ScanChangedFilesAsync(
+        this IGitService gitService,
+        string workspacePath,
+        IEnumerable<GitFileDiff> diff,
+        string fromRef,
+        string? toRef,
+        int maxFiles,
+        ILogger logger,
+        CancellationToken cancellationToken)
+    {
+        var files = new List<ScannedFile>();
+        var skipped = 0;
+
+        foreach (var file in diff.Where(f => !f.IsBinary && DeclarationScanner.Supports(f.Path)))
+        {
+            if (files.Count >= maxFiles)
+            {
+                skipped++;
+                continue;
+            }
+
+            var oldPath = file.OldPath ?? file.Path;
+            var oldContent = file.Status == "added"
+                ? string.Empty
+                : await TryGetFileAsync(gitService, workspacePath, fromRef, oldPath, logger, cancellationToken);
+            var newContent = file.Status == "deleted"
+                ? string.Empty
+                : toRef == null
+                    ? await TryReadWorkingFileAsync(workspacePath, file.Path, logger, cancellationToken)
+                    : await TryGetFileAsync(gitService, workspacePath, toRef, file.Path, logger, cancellationToken);
+
+            if (oldContent == null || newContent == null)
+            {
+                skipped++;
+                continue;
+            }
+
+            files.Add(new ScannedFile(
+                file.Status == "added" ? null : oldPath,
+                DeclarationScanner.Scan(oldContent, oldPath),
+                file.Path,
+                DeclarationScanner.Scan(newContent, file.Path)));
+        }
+
+        return (files, skipped);
+    }
+
+    /// <summary>
+    /// Compares scanned files with <see cref="SymbolDiffer.CompareFiles"/>
+    /// </summary>
+    public static List<SymbolChange> Compare(IEnumerable<ScannedFile> files, double renameThreshold = SymbolDiffer.DefaultRenameThreshold) =>
+        SymbolDiffer.CompareFiles(files.Select(f => (f.OldPath, f.Old, f.NewPath, f.New)), renameThreshold);
+
+    private static async Task<string?> TryGetFileAsync(
+        IGitService gitService,
+        string workspacePath,
+        string gitRef,
+        string path,
+        ILogger logger,
+        CancellationToken cancellationToken)
+    {
+        try
+        {
+            var content = await gitService.GetFileAtRefAsync(workspacePath, gitRef, path, cancellationToken);
+            return content != null && content.Length <= MaxFileBytes ? content : null;
+        }
+        catch (GitException ex)
+        {
+            logger.LogDebug(ex, "Could not read {FilePath} at {Ref}", path, gitRef);
+            return null;
+        }
+    }
+
+    private static async Task<string?> TryReadWorkingFileAsync(string workspacePath, string path, ILogger logger, CancellationToken cancellationToken)
+    {
+        var fullPath = Path.Combine(workspacePath, path);
+        try
+        {
+            var info = new FileInfo(fullPath);
+            return info.Exists && info.Length <= MaxFileBytes ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null;
+        }
+        catch (IOException ex)
+        {
+            logger.LogDebug(ex, "Could not read {FilePath}", fullPath);
+            return null;
+        }
+    }
+}
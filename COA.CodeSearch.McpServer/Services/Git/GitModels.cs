@@ -0,0 +1,108 @@
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// One file in a diff between two refs
+/// </summary>
+public class GitFileDiff
+{
+    /// <summary>
+    /// Path relative to the repository root (new path for renames), with forward slashes
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Previous path for renames and copies
+    /// </summary>
+    public string? OldPath { get; set; }
+
+    /// <summary>
+    /// added, modified, deleted or renamed
+    /// </summary>
+    public string Status { get; set; } = "modified";
+
+    public bool IsBinary { get; set; }
+
+    public List<DiffHunk> Hunks { get; set; } = new();
+
+    public int Additions => Hunks.Sum(h => h.AddedLines.Count);
+    public int Deletions => Hunks.Sum(h => h.RemovedLines.Count);
+}
+
+/// <summary>
+/// A hunk from a unified diff. Line numbers are 1-based; a count of 0 means a pure insertion or deletion.
+/// </summary>
+public class DiffHunk
+{
+    public int OldStart { get; set; }
+    public int OldCount { get; set; }
+    public int NewStart { get; set; }
+    public int NewCount { get; set; }
+
+    /// <summary>
+    /// Git's function context after the @@ markers, if any
+    /// </summary>
+    public string? Context { get; set; }
+
+    public List<string> AddedLines { get; set; } = new();
+    public List<string> RemovedLines { get; set; } = new();
+
+    /// <summary>
+    /// Last line of the hunk in the new file (equal to NewStart for pure deletions)
+    /// </summary>
+    public int NewEnd => NewCount == 0 ? NewStart : NewStart + NewCount - 1;
+
+    public bool ContainsNewLine(int line) => line >= NewStart && line <= NewEnd;
+}
+
+/// <summary>
+/// A file in a git tree
+/// </summary>
+public class GitTreeEntry
+{
+    public string Path { get; set; } = string.Empty;
+    public long Size { get; set; }
+}
+
+/// <summary>
+/// Raised when git is missing, the workspace is not a repository, or a ref does not resolve
+/// </summary>
+public class GitException : Exception
+{
+    public GitException(string message) : base(message)
+    {
+    }
+}
+
+/// <summary>
+/// Author of one line from <c>git blame</c>
+/// </summary>
+public class BlameLine
+{
+    /// <summary>
+    /// Line number in the blamed revision
+    /// </summary>
+    public int Line { get; set; }
+    public string AuthorName { get; set; } = string.Empty;
+    public string AuthorEmail { get; set; } = string.Empty;
+    public DateTime AuthorTime { get; set; }
+
+    /// <summary>
+    /// SHA of the commit that last changed the line
+    /// </summary>
+    public string Commit { get; set; } = string.Empty;
+
+    /// <summary>
+    /// First line of that commit's message
+    /// </summary>
+    public string Summary { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Author of one commit from <c>git log</c>
+/// </summary>
+public class CommitAuthor
+{
+    public string Name { get; set; } = string.Empty;
+    public string Email { get; set; } = string.Empty;
+    public DateTime Time { get; set; }
+}
@@ -0,0 +1,299 @@
+using System.Diagnostics;
+using System.Text.RegularExpressions;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// Runs the git CLI. Only read-only commands are issued, and refs are validated so they
+/// can never be interpreted as options.
+/// </summary>
+public class GitService : IGitService
+{
+    private static readonly Regex ValidRef = new(@"^[A-Za-z0-9_./@^~{}:+-]+$", RegexOptions.Compiled);
+
+    private readonly ILogger<GitService> _logger;
+    private readonly string _gitExecutable;
+    private readonly TimeSpan _timeout;
+
+    public GitService(IConfiguration configuration, ILogger<GitService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _gitExecutable = configuration.GetValue("CodeSearch:Git:Executable", "git") ?? "git";
+        _timeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Git:TimeoutSeconds", 30));
+    }
+
+    public async Task<bool> IsRepositoryAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        try
+        {
+            var (output, _, exitCode) = await RunAsync(workspacePath, cancellationToken, "rev-parse", "--is-inside-work-tree");
+            return exitCode == 0 && output.Trim() == "true";
+        }
+        catch (Exception ex) when (ex is System.ComponentModel.Win32Exception or GitException)
+        {
+            _logger.LogDebug(ex, "git is not available for {WorkspacePath}", workspacePath);
+            return false;
+        }
+    }
+
+    public async Task<List<GitFileDiff>> GetDiffAsync(string workspacePath, string baseRef, string? headRef, CancellationToken cancellationToken = default)
+    {
+        ValidateRef(baseRef);
+        if (headRef != null)
+            ValidateRef(headRef);
+
+        return headRef == null
+            ? await DiffAsync(workspacePath, cancellationToken, baseRef)
+            : await DiffAsync(workspacePath, cancellationToken, $"{baseRef}...{headRef}");
+    }
+
+    public async Task<List<GitFileDiff>> GetTreeDiffAsync(string workspacePath, string fromRef, string toRef, CancellationToken cancellationToken = default)
+    {
+        ValidateRef(fromRef);
+        ValidateRef(toRef);
+
+        return await DiffAsync(workspacePath, cancellationToken, fromRef, toRef);
+    }
+
+    public async Task<List<GitTreeEntry>> ListFilesAsync(string workspacePath, string gitRef, CancellationToken cancellationToken = default)
+    {
+        ValidateRef(gitRef);
+
+        // ls-tree lists the workspace directory, with paths relative to it
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken,
+            "-c", "core.quotePath=false", "ls-tree", "-r", "-l", gitRef);
+        if (exitCode != 0)
+        {
+            throw new GitException($"git ls-tree {gitRef} failed: {error.Trim()}");
+        }
+
+        var entries = new List<GitTreeEntry>();
+        foreach (var line in output.Split('\n', StringSplitOptions.RemoveEmptyEntries))
+        {
+            // <mode> <type> <object> <size>\t<path>; submodules have no size
+            var tab = line.IndexOf('\t');
+            if (tab < 0)
+                continue;
+
+            var header = line[..tab].Split(' ', StringSplitOptions.RemoveEmptyEntries);
+            if (header.Length < 4 || header[1] != "blob" || !long.TryParse(header[3], out var size))
+                continue;
+
+            entries.Add(new GitTreeEntry { Path = line[(tab + 1)..].TrimEnd('\r'), Size = size });
+        }
+
+        return entries;
+    }
+
+    public async Task<string> GetRepositoryRootAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken, "rev-parse", "--show-toplevel");
+        if (exitCode != 0)
+        {
+            throw new GitException($"git rev-parse failed: {error.Trim()}");
+        }
+
+        return Path.GetFullPath(output.Trim());
+    }
+
+    public async Task<List<BlameLine>> BlameAsync(
+        string workspacePath,
+        string gitRef,
+        string path,
+        IEnumerable<(int Start, int End)> ranges,
+        CancellationToken cancellationToken = default)
+    {
+        ValidateRef(gitRef);
+
+        var arguments = new List<string> { "blame", "--line-porcelain" };
+        foreach (var (start, end) in ranges.Where(r => r.Start > 0 && r.End >= r.Start))
+        {
+            arguments.Add("-L");
+            arguments.Add($"{start},{end}");
+        }
+        arguments.AddRange(new[] { gitRef, "--", path });
+
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken, arguments.ToArray());
+        if (exitCode != 0)
+        {
+            throw new GitException($"git blame {path} failed: {error.Trim()}");
+        }
+
+        return ParseLinePorcelain(output);
+    }
+
+    public async Task<List<CommitAuthor>> GetCommitAuthorsAsync(
+        string workspacePath,
+        string headRef,
+        string? excludeRef = null,
+        string? path = null,
+        int maxCommits = 50,
+        CancellationToken cancellationToken = default)
+    {
+        ValidateRef(headRef);
+        if (excludeRef != null)
+            ValidateRef(excludeRef);
+
+        var arguments = new List<string>
+        {
+            "log", "--no-merges", $"--max-count={Math.Max(1, maxCommits)}", "--format=%an%x09%ae%x09%at",
+            excludeRef == null ? headRef : $"{excludeRef}..{headRef}", "--"
+        };
+        if (!string.IsNullOrEmpty(path))
+            arguments.Add(path);
+
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken, arguments.ToArray());
+        if (exitCode != 0)
+        {
+            throw new GitException($"git log failed: {error.Trim()}");
+        }
+
+        var authors = new List<CommitAuthor>();
+        foreach (var line in output.Split('\n', StringSplitOptions.RemoveEmptyEntries))
+        {
+            var parts = line.TrimEnd('\r').Split('\t');
+            if (parts.Length < 3 || !long.TryParse(parts[2], out var seconds))
+                continue;
+
+            authors.Add(new CommitAuthor
+            {
+                Name = parts[0],
+                Email = parts[1],
+                Time = DateTimeOffset.FromUnixTimeSeconds(seconds).UtcDateTime
+            });
+        }
+
+        return authors;
+    }
+
+    public async Task<string?> GetFileAtRefAsync(string workspacePath, string gitRef, string path, CancellationToken cancellationToken = default)
+    {
+        ValidateRef(gitRef);
+        if (Path.IsPathRooted(path) || path.Replace('\\', '/').Split('/').Contains(".."))
+        {
+            throw new GitException($"Invalid path '{path}'");
+        }
+
+        // "./" makes the path relative to the workspace rather than the repository root
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken, "show", $"{gitRef}:./{path.Replace('\\', '/')}");
+        if (exitCode != 0)
+        {
+            if (error.Contains("does not exist") || error.Contains("exists on disk, but not in"))
+                return null;
+
+            throw new GitException($"git show {gitRef}:{path} failed: {error.Trim()}");
+        }
+
+        return output;
+    }
+
+    /// <summary>
+    /// Parses <c>git blame --line-porcelain</c>, where every line repeats its commit's author headers
+    /// </summary>
+    public static List<BlameLine> ParseLinePorcelain(string output)
+    {
+        var lines = new List<BlameLine>();
+        BlameLine? current = null;
+
+        foreach (var rawLine in output.Split('\n'))
+        {
+            var line = rawLine.TrimEnd('\r');
+            if (line.StartsWith('\t'))
+            {
+                if (current != null)
+                    lines.Add(current);
+                current = null;
+                continue;
+            }
+
+            if (current == null)
+            {
+                // Header: <sha> <original line> <final line> [<group size>]
+                var header = line.Split(' ');
+                if (header.Length >= 3 && header[0].Length >= 40 && int.TryParse(header[2], out var finalLine))
+                    current = new BlameLine { Line = finalLine, Commit = header[0] };
+                continue;
+            }
+
+            if (line.StartsWith("author "))
+                current.AuthorName = line["author ".Length..];
+            else if (line.StartsWith("author-mail "))
+                current.AuthorEmail = line["author-mail ".Length..].Trim('<', '>');
+            else if (line.StartsWith("author-time ") && long.TryParse(line["author-time ".Length..], out var seconds))
+                current.AuthorTime = DateTimeOffset.FromUnixTimeSeconds(seconds).UtcDateTime;
+            else if (line.StartsWith("summary "))
+                current.Summary = line["summary ".Length..];
+        }
+
+        return lines;
+    }
+
+    /// <summary>
+    /// Rejects anything that isn't a plain ref expression (branch, tag, SHA, HEAD~2, origin/main...)
+    /// </summary>
+    public static void ValidateRef(string gitRef)
+    {
+        if (string.IsNullOrWhiteSpace(gitRef) || gitRef.StartsWith('-') || gitRef.Contains("..") || !ValidRef.IsMatch(gitRef))
+        {
+            throw new GitException($"Invalid git ref '{gitRef}'");
+        }
+    }
+
+    private async Task<List<GitFileDiff>> DiffAsync(string workspacePath, CancellationToken cancellationToken, params string[] revisions)
+    {
+        var arguments = new List<string> { "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--relative", "-M", "--unified=0" };
+        arguments.AddRange(revisions);
+        arguments.Add("--");
+
+        var (output, error, exitCode) = await RunAsync(workspacePath, cancellationToken, arguments.ToArray());
+        if (exitCode != 0)
+        {
+            throw new GitException($"git diff {string.Join(" ", revisions)} failed: {error.Trim()}");
+        }
+
+        return UnifiedDiffParser.Parse(output);
+    }
+
+    private async Task<(string Output, string Error, int ExitCode)> RunAsync(
+        string workingDirectory,
+        CancellationToken cancellationToken,
+        params string[] arguments)
+    {
+        var startInfo = new ProcessStartInfo
+        {
+            FileName = _gitExecutable,
+            WorkingDirectory = workingDirectory,
+            RedirectStandardOutput = true,
+            RedirectStandardError = true,
+            UseShellExecute = false,
+            CreateNoWindow = true
+        };
+        foreach (var argument in arguments)
+        {
+            startInfo.ArgumentList.Add(argument);
+        }
+
+        using var process = Process.Start(startInfo)
+            ?? throw new GitException("Failed to start git");
+
+        using var timeout = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+        timeout.CancelAfter(_timeout);
+
+        var outputTask = process.StandardOutput.ReadToEndAsync(timeout.Token);
+        var errorTask = process.StandardError.ReadToEndAsync(timeout.Token);
+
+        try
+        {
+            await process.WaitForExitAsync(timeout.Token);
+        }
+        catch (OperationCanceledException) when (!cancellationToken.IsCancellationRequested)
+        {
+            process.Kill(entireProcessTree: true);
+            throw new GitException($"git {arguments.FirstOrDefault(a => !a.StartsWith('-') && !a.Contains('='))} timed out after {_timeout.TotalSeconds:F0}s");
+        }
+
+        return (await outputTask, await errorTask, process.ExitCode);
+    }
+}
@@ -0,0 +1,62 @@
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// Read-only access to the git repository containing a workspace
+/// </summary>
+public interface IGitService
+{
+    /// <summary>
+    /// Whether git is installed and the workspace is inside a repository
+    /// </summary>
+    Task<bool> IsRepositoryAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Gets the changes a branch introduces: <c>git diff base...head</c>, or the working tree against base when head is null.
+    /// Paths are relative to the workspace and changes outside it are excluded.
+    /// </summary>
+    /// <exception cref="GitException">A ref does not resolve or git fails</exception>
+    Task<List<GitFileDiff>> GetDiffAsync(string workspacePath, string baseRef, string? headRef, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Gets the differences between two trees (<c>git diff from to</c>), without going through their merge base
+    /// </summary>
+    /// <exception cref="GitException">A ref does not resolve or git fails</exception>
+    Task<List<GitFileDiff>> GetTreeDiffAsync(string workspacePath, string fromRef, string toRef, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Lists the files under the workspace as of a ref, with their sizes. Paths are relative to the workspace.
+    /// </summary>
+    Task<List<GitTreeEntry>> ListFilesAsync(string workspacePath, string gitRef, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Gets the top-level directory of the repository containing the workspace
+    /// </summary>
+    Task<string> GetRepositoryRootAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Blames line ranges of a file as of a ref. Path is relative to the workspace.
+    /// </summary>
+    Task<List<BlameLine>> BlameAsync(
+        string workspacePath,
+        string gitRef,
+        string path,
+        IEnumerable<(int Start, int End)> ranges,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Gets the authors of the most recent commits reachable from headRef (and not from excludeRef, when given),
+    /// optionally limited to a path relative to the workspace
+    /// </summary>
+    Task<List<CommitAuthor>> GetCommitAuthorsAsync(
+        string workspacePath,
+        string headRef,
+        string? excludeRef = null,
+        string? path = null,
+        int maxCommits = 50,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Gets a file's content as of a ref, or null when the file doesn't exist there. Path is relative to the workspace.
+    /// </summary>
+    Task<string?> GetFileAtRefAsync(string workspacePath, string gitRef, string path, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,84 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Git;
+
+/// <summary>
+/// Parses <c>git diff</c> output into files and hunks
+/// </summary>
+public static class UnifiedDiffParser
+{
+    private static readonly Regex HunkHeader = new(@"^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$", RegexOptions.Compiled);
+    private static readonly Regex DiffHeader = new(@"^diff --git a/(.+) b/(.+)$", RegexOptions.Compiled);
+
+    public static List<GitFileDiff> Parse(string diff)
+    {
+        var files = new List<GitFileDiff>();
+        GitFileDiff? current = null;
+        DiffHunk? hunk = null;
+
+        foreach (var rawLine in diff.Split('\n'))
+        {
+            var line = rawLine.TrimEnd('\r');
+
+            var header = DiffHeader.Match(line);
+            if (header.Success)
+            {
+                current = new GitFileDiff { Path = header.Groups[2].Value, OldPath = header.Groups[1].Value };
+                files.Add(current);
+                hunk = null;
+                continue;
+            }
+
+            if (current == null)
+                continue;
+
+            if (hunk == null)
+            {
+                if (line.StartsWith("new file mode"))
+                    current.Status = "added";
+                else if (line.StartsWith("deleted file mode"))
+                    current.Status = "deleted";
+                else if (line.StartsWith("rename from "))
+                {
+                    current.Status = "renamed";
+                    current.OldPath = line["rename from ".Length..];
+                }
+                else if (line.StartsWith("rename to "))
+                    current.Path = line["rename to ".Length..];
+                else if (line.StartsWith("Binary files "))
+                    current.IsBinary = true;
+            }
+
+            var hunkHeader = HunkHeader.Match(line);
+            if (hunkHeader.Success)
+            {
+                hunk = new DiffHunk
+                {
+                    OldStart = int.Parse(hunkHeader.Groups[1].Value),
+                    OldCount = hunkHeader.Groups[2].Success ? int.Parse(hunkHeader.Groups[2].Value) : 1,
+                    NewStart = int.Parse(hunkHeader.Groups[3].Value),
+                    NewCount = hunkHeader.Groups[4].Success ? int.Parse(hunkHeader.Groups[4].Value) : 1,
+                    Context = string.IsNullOrWhiteSpace(hunkHeader.Groups[5].Value) ? null : hunkHeader.Groups[5].Value.Trim()
+                };
+                current.Hunks.Add(hunk);
+                continue;
+            }
+
+            if (hunk == null)
+                continue;
+
+            if (line.StartsWith('+'))
+                hunk.AddedLines.Add(line[1..]);
+            else if (line.StartsWith('-'))
+                hunk.RemovedLines.Add(line[1..]);
+        }
+
+        foreach (var file in files)
+        {
+            if (file.Status != "renamed")
+                file.OldPath = null;
+        }
+
+        return files;
+    }
+}
@@ -0,0 +1,154 @@
+using System.Collections.Concurrent;
+using System.Runtime.InteropServices;
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.GoBuild;
+
+/// <summary>
+/// Keeps workspace build profiles in go-build-profile.json beside each workspace index and reads build
+/// constraints from file headers, cached until the file changes
+/// </summary>
+public class GoBuildProfileService : IGoBuildProfileService
+{
+    private const string ProfileFileName = "go-build-profile.json";
+
+    // Constraints must precede the package clause; a header longer than this is a licence wall or generated code
+    private const int MaxHeaderLines = 200;
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<GoBuildProfileService> _logger;
+    private readonly GoBuildProfile _default;
+    private readonly object _sync = new();
+    private readonly Dictionary<string, GoBuildProfile?> _profiles = new(StringComparer.OrdinalIgnoreCase);
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, string? Constraint)> _constraints = new(StringComparer.OrdinalIgnoreCase);
+
+    public GoBuildProfileService(IPathResolutionService pathResolution, ISQLiteSymbolService sqliteService,
+        IConfiguration configuration, ILogger<GoBuildProfileService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _sqliteService = sqliteService ?? throw new ArgumentNullException(nameof(sqliteService));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        _default = new GoBuildProfile
+        {
+            Goos = configuration.GetValue<string?>("CodeSearch:GoBuild:Goos", null)
+                   ?? Environment.GetEnvironmentVariable("GOOS") ?? HostOs(),
+            Goarch = configuration.GetValue<string?>("CodeSearch:GoBuild:Goarch", null)
+                     ?? Environment.GetEnvironmentVariable("GOARCH") ?? HostArch(),
+            Tags = (configuration.GetSection("CodeSearch:GoBuild:Tags").Get<string[]>() ?? Array.Empty<string>())
+                .Where(t => !string.IsNullOrWhiteSpace(t))
+                .ToList()
+        };
+    }
+
+    public GoBuildProfile GetProfile(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceProfile(workspacePath) ?? _default;
+        }
+    }
+
+    public bool HasWorkspaceProfile(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceProfile(workspacePath) != null;
+        }
+    }
+
+    public void SetProfile(string workspacePath, GoBuildProfile? profile)
+    {
+        lock (_sync)
+        {
+            _profiles[workspacePath] = profile;
+            var path = GetProfilePath(workspacePath);
+            try
+            {
+                if (profile == null)
+                {
+                    File.Delete(path);
+                }
+                else
+                {
+                    Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+                    File.WriteAllText(path, JsonSerializer.Serialize(profile));
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogWarning(ex, "Could not save Go build profile {Path}", path);
+            }
+        }
+    }
+
+    public async Task<string?> GetConstraintAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default)
+    {
+        if (!filePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+            return null;
+
+        var fullPath = Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+        var stamp = File.Exists(fullPath) ? File.GetLastWriteTimeUtc(fullPath) : DateTime.MinValue;
+        if (_constraints.TryGetValue(fullPath, out var cached) && cached.Stamp == stamp)
+            return cached.Constraint;
+
+        IEnumerable<string>? header = null;
+        if (stamp != DateTime.MinValue)
+        {
+            header = File.ReadLines(fullPath).Take(MaxHeaderLines).ToList();
+        }
+        else if (await _sqliteService.GetFileByPathAsync(workspacePath, filePath, cancellationToken) is { Content: { } content })
+        {
+            header = content.Replace("\r\n", "\n").Split('\n').Take(MaxHeaderLines);
+        }
+
+        var constraint = GoBuildConstraints.Read(fullPath, header ?? Enumerable.Empty<string>());
+        _constraints[fullPath] = (stamp, constraint);
+        return constraint;
+    }
+
+    private GoBuildProfile? GetWorkspaceProfile(string workspacePath)
+    {
+        if (_profiles.TryGetValue(workspacePath, out var profile))
+            return profile;
+
+        var path = GetProfilePath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+                profile = JsonSerializer.Deserialize<GoBuildProfile>(File.ReadAllText(path));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read Go build profile {Path} - using the default", path);
+        }
+
+        _profiles[workspacePath] = profile;
+        return profile;
+    }
+
+    private string GetProfilePath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), ProfileFileName);
+
+    private static string HostOs() =>
+        RuntimeInformation.IsOSPlatform(OSPlatform.Windows) ? "windows"
+        : RuntimeInformation.IsOSPlatform(OSPlatform.OSX) ? "darwin"
+        : RuntimeInformation.IsOSPlatform(OSPlatform.FreeBSD) ? "freebsd"
+        : "linux";
+
+    private static string HostArch() => RuntimeInformation.OSArchitecture switch
+    {
+        Architecture.X86 => "386",
+        Architecture.Arm => "arm",
+        Architecture.Arm64 => "arm64",
+        Architecture.S390x => "s390x",
+        Architecture.LoongArch64 => "loong64",
+        Architecture.Ppc64le => "ppc64le",
+        _ => "amd64"
+    };
+}
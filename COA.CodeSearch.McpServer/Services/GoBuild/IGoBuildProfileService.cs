@@ -0,0 +1,32 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Services.GoBuild;
+
+/// <summary>
+/// The GOOS/GOARCH profile each workspace's Go code is read under, and the build constraints of its files, so
+/// results from files guarded for another platform can be told apart
+/// </summary>
+public interface IGoBuildProfileService
+{
+    /// <summary>
+    /// The workspace's profile: the one set for it, else the configured one, else GOOS/GOARCH from the
+    /// environment or the host
+    /// </summary>
+    GoBuildProfile GetProfile(string workspacePath);
+
+    /// <summary>
+    /// True when a profile was set for this workspace rather than defaulted
+    /// </summary>
+    bool HasWorkspaceProfile(string workspacePath);
+
+    /// <summary>
+    /// Stores the workspace's profile; null goes back to the default
+    /// </summary>
+    void SetProfile(string workspacePath, GoBuildProfile? profile);
+
+    /// <summary>
+    /// The build constraint of a Go file, read from its header and name; null for other files and for files
+    /// that build everywhere
+    /// </summary>
+    Task<string?> GetConstraintAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default);
+}
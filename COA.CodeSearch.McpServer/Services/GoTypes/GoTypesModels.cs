@@ -0,0 +1,115 @@
+namespace COA.CodeSearch.McpServer.Services.GoTypes;
+
+/// <summary>
+/// A position in a Go file as go/types reports it: 1-based line, 0-based UTF-8 byte column and file offset
+/// </summary>
+public class GoLocation
+{
+    public string File { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public int Column { get; set; }
+    public int Offset { get; set; }
+
+    /// <summary>
+    /// Length of the identifier in bytes
+    /// </summary>
+    public int Length { get; set; }
+
+    public bool IsDefinition { get; set; }
+
+    /// <summary>
+    /// Enclosing function, method ("Repo.Find") or type declaration
+    /// </summary>
+    public string? ContainedIn { get; set; }
+
+    public string? LineText { get; set; }
+}
+
+/// <summary>
+/// A types.Object: what an identifier denotes
+/// </summary>
+public class GoObjectInfo
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// func, method, var, field, const, type, package, label, builtin or nil
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Import path of the declaring package
+    /// </summary>
+    public string? Package { get; set; }
+
+    /// <summary>
+    /// Declaration as go/types prints it, e.g. "func (*Repo).Find(name string) *User"
+    /// </summary>
+    public string Signature { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Doc comment of the declaration, for objects declared in the module's sources
+    /// </summary>
+    public string? Doc { get; set; }
+
+    public GoLocation? Declared { get; set; }
+}
+
+/// <summary>
+/// The type of the expression at a position
+/// </summary>
+public class GoTypeInfo
+{
+    /// <summary>
+    /// Source text of the expression, widened from a selector's name to the selector ("user.Name")
+    /// </summary>
+    public string Expression { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Type with other packages written by name, e.g. "*store.User"
+    /// </summary>
+    public string Type { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Underlying type, when it differs (the struct behind a named type)
+    /// </summary>
+    public string? Underlying { get; set; }
+
+    /// <summary>
+    /// variable, value, constant, type, builtin, void, mapindex, package, or definition for a declared name
+    /// </summary>
+    public string Mode { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Exact value of a constant expression
+    /// </summary>
+    public string? Value { get; set; }
+
+    public GoObjectInfo? Object { get; set; }
+
+    /// <summary>
+    /// Type errors in the package; answers can be incomplete when there are any
+    /// </summary>
+    public List<string>? TypeErrors { get; set; }
+}
+
+/// <summary>
+/// Every identifier that denotes one of the resolved objects, across the module and its tests
+/// </summary>
+public class GoReferenceResult
+{
+    public string Symbol { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Objects that matched a name lookup; greater than 1 when several types have a method of that name
+    /// </summary>
+    public int SymbolCount { get; set; } = 1;
+
+    public List<GoLocation> Locations { get; set; } = new();
+
+    /// <summary>
+    /// Packages that had type errors; references in them may be missing
+    /// </summary>
+    public int TypeErrors { get; set; }
+}
@@ -0,0 +1,258 @@
+using System.Diagnostics;
+using System.Runtime.InteropServices;
+using System.Text.Json;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.GoTypes;
+
+/// <summary>
+/// Runs the codesearch-gotypes helper (tools/gotypes) as one long-lived process and sends it one request
+/// at a time as a line of JSON. The helper caches loaded modules, so only the first request per module pays
+/// for go list and type-checking. A helper that is missing or fails to start is not retried until
+/// <c>RetryAfterSeconds</c> has passed; one that times out is killed and restarted on the next request.
+/// </summary>
+public class GoTypesService : IGoTypesService, IDisposable
+{
+    private static readonly JsonSerializerOptions JsonOptions = new(JsonSerializerDefaults.Web)
+    {
+        DefaultIgnoreCondition = System.Text.Json.Serialization.JsonIgnoreCondition.WhenWritingNull
+    };
+
+    private readonly string? _command;
+    private readonly TimeSpan _requestTimeout;
+    private readonly TimeSpan _retryAfter;
+    private readonly ILogger<GoTypesService> _logger;
+    private readonly SemaphoreSlim _lock = new(1, 1);
+    private Process? _process;
+    private DateTime? _failedStartAt;
+    private long _nextId;
+
+    public GoTypesService(IConfiguration configuration, ILogger<GoTypesService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        IsEnabled = configuration.GetValue("CodeSearch:GoTypes:Enabled", false);
+        _command = configuration.GetValue<string?>("CodeSearch:GoTypes:Command", null);
+        _requestTimeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:GoTypes:RequestTimeoutSeconds", 120));
+        _retryAfter = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:GoTypes:RetryAfterSeconds", 300));
+    }
+
+    public bool IsEnabled { get; }
+
+    public bool Handles(string filePath) =>
+        IsEnabled && Path.GetExtension(filePath).Equals(".go", StringComparison.OrdinalIgnoreCase);
+
+    public async Task<GoTypeInfo?> TypeOfAsync(string workspacePath, string filePath, int line, int byteColumn,
+        CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var result = await RequestAsync(new GoTypesRequest("typeof", Path.GetFullPath(workspacePath), Path.GetFullPath(filePath), line, byteColumn),
+            cancellationToken);
+        return result?.Deserialize<GoTypeInfo>(JsonOptions);
+    }
+
+    public async Task<GoReferenceResult?> FindReferencesAsync(string workspacePath, string filePath, int line, int byteColumn,
+        CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var result = await RequestAsync(new GoTypesRequest("references", Path.GetFullPath(workspacePath), Path.GetFullPath(filePath), line, byteColumn),
+            cancellationToken);
+        return result?.Deserialize<GoReferenceResult>(JsonOptions);
+    }
+
+    public async Task<GoReferenceResult?> FindReferencesByNameAsync(string workspacePath, string name,
+        CancellationToken cancellationToken = default)
+    {
+        if (!IsEnabled)
+            return null;
+
+        var result = await RequestAsync(new GoTypesRequest("references", Path.GetFullPath(workspacePath), Name: name), cancellationToken);
+        return result?.Deserialize<GoReferenceResult>(JsonOptions);
+    }
+
+    private async Task<JsonElement?> RequestAsync(GoTypesRequest request, CancellationToken cancellationToken)
+    {
+        if (_failedStartAt.HasValue && DateTime.UtcNow - _failedStartAt.Value < _retryAfter)
+            return null;
+
+        await _lock.WaitAsync(cancellationToken);
+        try
+        {
+            var process = EnsureStarted();
+            if (process == null)
+                return null;
+
+            var line = JsonSerializer.Serialize(request with { Id = ++_nextId }, JsonOptions);
+
+            using var timeout = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+            timeout.CancelAfter(_requestTimeout);
+            string? answer;
+            try
+            {
+                await process.StandardInput.WriteLineAsync(line.AsMemory(), timeout.Token);
+                await process.StandardInput.FlushAsync(timeout.Token);
+                answer = await process.StandardOutput.ReadLineAsync(timeout.Token);
+            }
+            catch (OperationCanceledException) when (!cancellationToken.IsCancellationRequested)
+            {
+                // The reply may still arrive and would answer the next request; start over instead
+                _logger.LogWarning("codesearch-gotypes did not answer within {Seconds}s, restarting it", _requestTimeout.TotalSeconds);
+                Stop();
+                return null;
+            }
+            catch (IOException ex)
+            {
+                _logger.LogWarning("codesearch-gotypes exited: {Message}", ex.Message);
+                Stop();
+                return null;
+            }
+
+            if (answer == null)
+            {
+                _logger.LogWarning("codesearch-gotypes exited");
+                Stop();
+                return null;
+            }
+
+            using var document = JsonDocument.Parse(answer);
+            var root = document.RootElement;
+            if (root.TryGetProperty("error", out var error))
+            {
+                _logger.LogDebug("codesearch-gotypes: {Error}", error.GetString());
+                return null;
+            }
+            return root.TryGetProperty("result", out var result) ? result.Clone() : null;
+        }
+        finally
+        {
+            _lock.Release();
+        }
+    }
+
+    private Process? EnsureStarted()
+    {
+        if (_process is { HasExited: false })
+            return _process;
+
+        var command = ResolveCommand();
+        if (command == null)
+        {
+            _logger.LogWarning("codesearch-gotypes not found; build it from tools/gotypes or set CodeSearch:GoTypes:Command. Using the index.");
+            _failedStartAt = DateTime.UtcNow;
+            return null;
+        }
+
+        try
+        {
+            var process = Process.Start(new ProcessStartInfo(command)
+            {
+                RedirectStandardInput = true,
+                RedirectStandardOutput = true,
+                RedirectStandardError = true,
+                UseShellExecute = false,
+                CreateNoWindow = true
+            });
+            if (process == null)
+            {
+                _failedStartAt = DateTime.UtcNow;
+                return null;
+            }
+
+            process.ErrorDataReceived += (_, e) =>
+            {
+                if (e.Data != null)
+                    _logger.LogDebug("codesearch-gotypes: {Line}", e.Data);
+            };
+            process.BeginErrorReadLine();
+
+            _logger.LogInformation("Started codesearch-gotypes from {Command}", command);
+            _failedStartAt = null;
+            _process = process;
+            return process;
+        }
+        catch (System.ComponentModel.Win32Exception ex)
+        {
+            _logger.LogWarning("Could not start codesearch-gotypes from {Command}: {Message}", command, ex.Message);
+            _failedStartAt = DateTime.UtcNow;
+            return null;
+        }
+    }
+
+    /// <summary>
+    /// The configured command, else the binary bundled under bin/gotypes-binaries, else codesearch-gotypes on PATH
+    /// </summary>
+    private string? ResolveCommand()
+    {
+        if (!string.IsNullOrWhiteSpace(_command))
+            return _command;
+
+        var bundled = Path.Combine(AppContext.BaseDirectory, "bin", "gotypes-binaries", GetPlatformBinaryName());
+        if (File.Exists(bundled))
+            return bundled;
+
+        var fileName = RuntimeInformation.IsOSPlatform(OSPlatform.Windows) ? "codesearch-gotypes.exe" : "codesearch-gotypes";
+        return (Environment.GetEnvironmentVariable("PATH") ?? string.Empty)
+            .Split(Path.PathSeparator, StringSplitOptions.RemoveEmptyEntries)
+            .Select(directory => Path.Combine(directory, fileName))
+            .FirstOrDefault(File.Exists);
+    }
+
+    private static string GetPlatformBinaryName()
+    {
+        if (RuntimeInformation.IsOSPlatform(OSPlatform.Windows))
+            return "codesearch-gotypes-windows-x64.exe";
+
+        var architecture = RuntimeInformation.ProcessArchitecture == Architecture.Arm64 ? "arm64" : "x64";
+        return RuntimeInformation.IsOSPlatform(OSPlatform.OSX)
+            ? $"codesearch-gotypes-macos-{architecture}"
+            : $"codesearch-gotypes-linux-{architecture}";
+    }
+
+    private void Stop()
+    {
+        if (_process == null)
+            return;
+
+        try
+        {
+            if (!_process.HasExited)
+                _process.Kill(entireProcessTree: true);
+        }
+        catch (InvalidOperationException)
+        {
+        }
+        _process.Dispose();
+        _process = null;
+    }
+
+    /// <summary>
+    /// One request line; lines are 1-based, columns 0-based bytes
+    /// </summary>
+    private sealed record GoTypesRequest(string Op, string Dir, string? File = null, int Line = 0, int Column = 0, string? Name = null)
+    {
+        public long Id { get; init; }
+    }
+
+    public void Dispose()
+    {
+        if (_process is { HasExited: false })
+        {
+            try
+            {
+                // Closing stdin ends the helper's read loop
+                _process.StandardInput.Close();
+                _process.WaitForExit(2000);
+            }
+            catch (Exception ex) when (ex is IOException or InvalidOperationException)
+            {
+            }
+        }
+        Stop();
+        _lock.Dispose();
+    }
+}
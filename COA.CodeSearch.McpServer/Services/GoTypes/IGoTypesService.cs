@@ -0,0 +1,35 @@
+namespace COA.CodeSearch.McpServer.Services.GoTypes;
+
+/// <summary>
+/// Optional Go type-checking tier: the codesearch-gotypes helper loads the module with go list and checks it
+/// with go/types. Tree-sitter extraction still feeds the index; this tier confirms which identifiers really
+/// denote a symbol (for find_references and rename) and answers type-of-expression queries. Every method
+/// returns null when the tier is disabled, the helper is missing, or the module does not load.
+/// </summary>
+public interface IGoTypesService
+{
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// True for Go source files when enabled
+    /// </summary>
+    bool Handles(string filePath);
+
+    /// <summary>
+    /// Type of the expression at a 1-based line and 0-based byte column
+    /// </summary>
+    Task<GoTypeInfo?> TypeOfAsync(string workspacePath, string filePath, int line, int byteColumn,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// References to the object named at a 1-based line and 0-based byte column
+    /// </summary>
+    Task<GoReferenceResult?> FindReferencesAsync(string workspacePath, string filePath, int line, int byteColumn,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// References to every package-level object, method and field with this name
+    /// </summary>
+    Task<GoReferenceResult?> FindReferencesByNameAsync(string workspacePath, string name,
+        CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,163 @@
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Data.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Hosted service that turns SIGTERM, Ctrl+C or stdin close into an orderly, time-bounded shutdown:
+/// in-flight indexing is signalled to stop at a checkpoint, queued file-watcher changes are written to the
+/// index write journal, open indexes are committed, and connected clients are told the server is going away.
+/// Registered after the other hosted services so its StopAsync runs before theirs.
+/// </summary>
+public class GracefulShutdownService : IHostedService
+{
+    private readonly IShutdownCoordinator _coordinator;
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly FileWatcherService _fileWatcherService;
+    private readonly IHostApplicationLifetime _lifetime;
+    private readonly ILogger<GracefulShutdownService> _logger;
+    private readonly bool _notifyClients;
+    private readonly SemaphoreSlim _flushLock = new(1, 1);
+    private CancellationTokenRegistration _stoppingRegistration;
+    private bool _flushed;
+
+    public GracefulShutdownService(
+        IShutdownCoordinator coordinator,
+        ILuceneIndexService luceneIndexService,
+        FileWatcherService fileWatcherService,
+        IHostApplicationLifetime lifetime,
+        IConfiguration configuration,
+        ILogger<GracefulShutdownService> logger)
+    {
+        _coordinator = coordinator ?? throw new ArgumentNullException(nameof(coordinator));
+        _luceneIndexService = luceneIndexService ?? throw new ArgumentNullException(nameof(luceneIndexService));
+        _fileWatcherService = fileWatcherService ?? throw new ArgumentNullException(nameof(fileWatcherService));
+        _lifetime = lifetime ?? throw new ArgumentNullException(nameof(lifetime));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _notifyClients = configuration.GetValue("CodeSearch:Shutdown:NotifyClients", true);
+    }
+
+    public Task StartAsync(CancellationToken cancellationToken)
+    {
+        // ApplicationStopping fires before any hosted service is stopped, while the transport can still write
+        _stoppingRegistration = _lifetime.ApplicationStopping.Register(OnApplicationStopping);
+
+        // Last resort when the process exits without the host stopping (e.g. the transport ends on stdin close)
+        AppDomain.CurrentDomain.ProcessExit += OnProcessExit;
+        return Task.CompletedTask;
+    }
+
+    public async Task StopAsync(CancellationToken cancellationToken)
+    {
+        _coordinator.BeginShutdown();
+        await FlushAsync(cancellationToken);
+    }
+
+    private void OnApplicationStopping()
+    {
+        if (_coordinator.BeginShutdown() && _notifyClients)
+        {
+            var active = _coordinator.GetActiveOperations();
+            NotifyClients(active.Count > 0
+                ? $"CodeSearch server is shutting down; checkpointing {active.Count} operation(s) in progress"
+                : "CodeSearch server is shutting down");
+        }
+    }
+
+    private void OnProcessExit(object? sender, EventArgs e)
+    {
+        if (_flushed)
+            return;
+
+        _coordinator.BeginShutdown();
+        try
+        {
+            FlushAsync(CancellationToken.None).Wait(_coordinator.Timeout);
+        }
+        catch (Exception ex)
+        {
+            _logger.LogError(ex, "Shutdown flush failed during process exit");
+        }
+    }
+
+    /// <summary>
+    /// Waits for in-flight work, journals queued changes and commits open indexes, all within the shutdown timeout
+    /// </summary>
+    private async Task FlushAsync(CancellationToken cancellationToken)
+    {
+        await _flushLock.WaitAsync(cancellationToken);
+        try
+        {
+            if (_flushed)
+                return;
+
+            using var timeoutCts = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+            timeoutCts.CancelAfter(_coordinator.Timeout);
+            var started = DateTime.UtcNow;
+
+            // In-flight indexing was cancelled by BeginShutdown; give it half the budget to reach a checkpoint
+            using (var waitCts = CancellationTokenSource.CreateLinkedTokenSource(timeoutCts.Token))
+            {
+                waitCts.CancelAfter(_coordinator.Timeout / 2);
+                if (!await _coordinator.WaitForOperationsAsync(waitCts.Token))
+                {
+                    _logger.LogWarning("Shutdown continuing with operations still running: {Operations}",
+                        string.Join(", ", _coordinator.GetActiveOperations()));
+                }
+            }
+
+            var committed = 0;
+            try
+            {
+                committed = await _luceneIndexService.CommitAllAsync(timeoutCts.Token);
+            }
+            catch (OperationCanceledException)
+            {
+                _logger.LogWarning("Shutdown timeout reached while committing indexes - uncommitted changes remain in the write journal");
+            }
+
+            // Journal after committing, since a commit truncates the journal
+            var journaled = _fileWatcherService.FlushPendingChanges();
+
+            // Release pooled SQLite handles so WAL files are checkpointed on close
+            SqliteConnection.ClearAllPools();
+
+            _flushed = true;
+            _logger.LogInformation(
+                "Graceful shutdown flush completed in {Elapsed}ms - committed {Committed} index(es), journaled {Journaled} pending change(s)",
+                (DateTime.UtcNow - started).TotalMilliseconds, committed, journaled);
+        }
+        finally
+        {
+            _flushLock.Release();
+            _stoppingRegistration.Dispose();
+            AppDomain.CurrentDomain.ProcessExit -= OnProcessExit;
+        }
+    }
+
+    /// <summary>
+    /// Sends an MCP notifications/message to the client over stdout. Best effort - the client may already be gone.
+    /// </summary>
+    private void NotifyClients(string message)
+    {
+        try
+        {
+            var notification = JsonSerializer.Serialize(new
+            {
+                jsonrpc = "2.0",
+                method = "notifications/message",
+                @params = new { level = "warning", logger = "codesearch", data = message }
+            });
+            Console.Out.WriteLine(notification);
+            Console.Out.Flush();
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Could not notify client of shutdown");
+        }
+    }
+}
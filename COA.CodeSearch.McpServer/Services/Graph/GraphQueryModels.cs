@@ -0,0 +1,111 @@
+using COA.CodeSearch.McpServer.Services.Julie;
+
+namespace COA.CodeSearch.McpServer.Services.Graph;
+
+/// <summary>
+/// One step of a graph query pipeline. Sources and filters narrow the current set of symbols,
+/// traversals replace it with the symbols one edge away.
+/// </summary>
+public class GraphQueryStep
+{
+    /// <summary>
+    /// Step operator, e.g. symbol, callers, in, implements
+    /// </summary>
+    public string Op { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Text after the colon; null for traversals that take none
+    /// </summary>
+    public string? Argument { get; set; }
+
+    /// <summary>
+    /// Filter written with a leading '!': keep the symbols that do NOT match
+    /// </summary>
+    public bool Negated { get; set; }
+
+    public override string ToString() => $"{(Negated ? "!" : "")}{Op}{(Argument != null ? ":" + Argument : "")}";
+}
+
+/// <summary>
+/// How many symbols were left after each step, so a query that comes back empty shows where it lost them
+/// </summary>
+public class GraphStepReport
+{
+    public string Step { get; set; } = string.Empty;
+    public int Count { get; set; }
+}
+
+public class GraphQueryOutcome
+{
+    public List<JulieSymbol> Symbols { get; set; } = new();
+    public List<GraphStepReport> Steps { get; set; } = new();
+
+    /// <summary>
+    /// A step produced more than the configured node limit and was cut off
+    /// </summary>
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One symbol on a call path, with the call in the previous symbol that leads to it
+/// </summary>
+public class GraphPathHop
+{
+    public JulieSymbol Symbol { get; set; } = new();
+
+    /// <summary>
+    /// Line of the call in the previous hop's file; null for the first hop
+    /// </summary>
+    public int? CallLine { get; set; }
+}
+
+/// <summary>
+/// A shortest call path from a source symbol to a sink: a sink symbol (the last hop) or a call to code outside
+/// the index made from the last hop
+/// </summary>
+public class GraphPath
+{
+    public List<GraphPathHop> Hops { get; set; } = new();
+
+    /// <summary>
+    /// Sink call pattern matched by a call in the last hop, e.g. exec.Command; null when the last hop is a sink symbol
+    /// </summary>
+    public string? SinkCall { get; set; }
+
+    /// <summary>
+    /// Line of the matched sink call
+    /// </summary>
+    public int? SinkCallLine { get; set; }
+
+    /// <summary>
+    /// Calls from the source to the sink
+    /// </summary>
+    public int Length => Hops.Count - 1 + (SinkCall != null ? 1 : 0);
+}
+
+public class GraphPathOutcome
+{
+    public List<GraphPath> Paths { get; set; } = new();
+    public int SourceCount { get; set; }
+    public int SinkCount { get; set; }
+
+    /// <summary>
+    /// Symbols expanded by the search
+    /// </summary>
+    public int Visited { get; set; }
+
+    /// <summary>
+    /// The search stopped at the node limit before exhausting the depth
+    /// </summary>
+    public bool Truncated { get; set; }
+}
+
+public class GraphQueryException : Exception
+{
+    public GraphQueryException(string code, string message) : base(message)
+    {
+        Code = code;
+    }
+
+    public string Code { get; }
+}
@@ -0,0 +1,515 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Graph;
+
+/// <summary>
+/// Evaluates graph query pipelines in memory over one snapshot of the workspace's symbols; calls, references
+/// and inheritance edges are fetched from the symbol database as the steps need them.
+/// </summary>
+public class GraphQueryService : IGraphQueryService
+{
+    private static readonly string[] Filters = { "symbol", "kind", "in", "language", "within", "implements", "limit" };
+    private static readonly string[] Traversals = { "callers", "callees", "references", "members", "parent", "bases", "implementations" };
+    private static readonly List<string> InheritanceKinds = new() { "extends", "implements" };
+    private static readonly string[] CallableKinds = { "method", "function", "constructor" };
+    private static readonly string[] TypeKinds = { "class", "interface", "struct", "record", "trait", "enum", "type" };
+    private static readonly Regex InheritanceClause = new(@":|\b(extends|implements|inherits)\b", RegexOptions.Compiled);
+    private const int MaxInheritanceDepth = 10;
+    private const int RelationshipBatchSize = 500;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<GraphQueryService> _logger;
+    private readonly int _maxNodes;
+
+    public GraphQueryService(ISQLiteSymbolService sqliteService, IConfiguration configuration, ILogger<GraphQueryService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+        _maxNodes = configuration.GetValue("CodeSearch:GraphQuery:MaxNodes", 2000);
+    }
+
+    public List<GraphQueryStep> Parse(string query)
+    {
+        var steps = new List<GraphQueryStep>();
+        foreach (var part in query.Split('|', StringSplitOptions.TrimEntries | StringSplitOptions.RemoveEmptyEntries))
+        {
+            var negated = part.StartsWith('!');
+            var text = negated ? part[1..].TrimStart() : part;
+            var colon = text.IndexOf(':');
+            var op = (colon < 0 ? text : text[..colon]).Trim().ToLowerInvariant();
+            var argument = colon < 0 ? null : text[(colon + 1)..].Trim();
+            if (op == "name")
+                op = "symbol";
+
+            if (Traversals.Contains(op))
+            {
+                if (negated || !string.IsNullOrEmpty(argument))
+                    throw new GraphQueryException("INVALID_STEP", $"'{part}': traversals take no argument and cannot be negated");
+                if (steps.Count == 0)
+                    throw new GraphQueryException("MISSING_SOURCE", $"Query starts with the traversal '{op}'; start with symbol:, kind:, in: or within: to pick the symbols to traverse from");
+            }
+            else if (Filters.Contains(op))
+            {
+                if (string.IsNullOrEmpty(argument))
+                    throw new GraphQueryException("INVALID_STEP", $"'{part}': {op} needs an argument, e.g. {op}:{ExampleArgument(op)}");
+                if (op == "limit" && (negated || !int.TryParse(argument, out var limit) || limit < 1))
+                    throw new GraphQueryException("INVALID_STEP", $"'{part}': limit needs a positive number");
+            }
+            else
+            {
+                throw new GraphQueryException("UNKNOWN_STEP",
+                    $"Unknown step '{op}'. Filters: {string.Join(", ", Filters)}. Traversals: {string.Join(", ", Traversals)}");
+            }
+
+            steps.Add(new GraphQueryStep { Op = op, Argument = argument, Negated = negated });
+        }
+
+        if (steps.Count == 0)
+            throw new GraphQueryException("EMPTY_QUERY", "Query has no steps");
+
+        return steps;
+    }
+
+    public async Task<GraphQueryOutcome> ExecuteAsync(string workspacePath, IReadOnlyList<GraphQueryStep> steps, bool caseSensitive,
+        CancellationToken cancellationToken = default)
+    {
+        var graph = new CodeGraph(await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken));
+        var outcome = await RunAsync(workspacePath, graph, steps, caseSensitive, cancellationToken);
+
+        _logger.LogDebug("Graph query {Steps} returned {Count} symbols", string.Join(" | ", steps), outcome.Symbols.Count);
+        return outcome;
+    }
+
+    public async Task<GraphPathOutcome> FindPathsAsync(string workspacePath, IReadOnlyList<GraphQueryStep> sources, IReadOnlyList<GraphQueryStep>? sinks,
+        IReadOnlyList<string> sinkCalls, int maxDepth, int maxPaths, bool caseSensitive, CancellationToken cancellationToken = default)
+    {
+        var graph = new CodeGraph(await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken));
+        var sourceSymbols = WithCallableMembers(graph, (await RunAsync(workspacePath, graph, sources, caseSensitive, cancellationToken)).Symbols);
+        var sinkIds = sinks == null
+            ? new HashSet<string>(StringComparer.Ordinal)
+            : WithCallableMembers(graph, (await RunAsync(workspacePath, graph, sinks, caseSensitive, cancellationToken)).Symbols)
+                .Select(s => s.Id).ToHashSet(StringComparer.Ordinal);
+        var callMatchers = sinkCalls.Select(pattern => (Pattern: pattern, Matches: CallMatcher(pattern, caseSensitive))).ToList();
+
+        var outcome = new GraphPathOutcome { SourceCount = sourceSymbols.Count, SinkCount = sinkIds.Count };
+        var previous = new Dictionary<string, (string? From, int? Line)>(StringComparer.Ordinal);
+        var reached = new HashSet<string>(StringComparer.Ordinal);
+        var frontier = sourceSymbols.Where(s => previous.TryAdd(s.Id, (null, null))).ToList();
+
+        // Breadth-first from all sources at once: the first time a sink is reached, the path to it is a shortest one
+        for (var depth = 0; frontier.Count > 0 && outcome.Paths.Count < maxPaths; depth++)
+        {
+            var next = new List<JulieSymbol>();
+            foreach (var symbol in frontier)
+            {
+                cancellationToken.ThrowIfCancellationRequested();
+                if (sinkIds.Contains(symbol.Id) && reached.Add(symbol.Id))
+                    outcome.Paths.Add(new GraphPath { Hops = PathTo(graph, previous, symbol.Id) });
+                if (depth == maxDepth || outcome.Paths.Count >= maxPaths)
+                    continue;
+
+                outcome.Visited++;
+                var identifiers = await _sqliteService.GetIdentifiersByContainingSymbolAsync(workspacePath, symbol.Id, cancellationToken);
+                foreach (var call in identifiers.Where(i => i.Kind == "call").OrderBy(i => i.StartLine))
+                {
+                    foreach (var (pattern, matches) in callMatchers)
+                    {
+                        if (matches(call) && reached.Add($"{symbol.Id}|{pattern}"))
+                        {
+                            outcome.Paths.Add(new GraphPath
+                            {
+                                Hops = PathTo(graph, previous, symbol.Id),
+                                SinkCall = pattern,
+                                SinkCallLine = call.StartLine
+                            });
+                        }
+                    }
+
+                    foreach (var target in CallTargets(graph, call))
+                    {
+                        if (previous.Count >= _maxNodes)
+                        {
+                            outcome.Truncated = true;
+                            break;
+                        }
+                        if (previous.TryAdd(target.Id, (symbol.Id, call.StartLine)))
+                            next.Add(target);
+                    }
+                }
+            }
+            frontier = next;
+        }
+
+        outcome.Paths = outcome.Paths
+            .OrderBy(p => p.Length)
+            .ThenBy(p => p.Hops[0].Symbol.FilePath, StringComparer.OrdinalIgnoreCase)
+            .Take(maxPaths)
+            .ToList();
+
+        _logger.LogDebug("Path search from {Sources} sources found {Count} paths after expanding {Visited} symbols",
+            outcome.SourceCount, outcome.Paths.Count, outcome.Visited);
+        return outcome;
+    }
+
+    private async Task<GraphQueryOutcome> RunAsync(string workspacePath, CodeGraph graph, IReadOnlyList<GraphQueryStep> steps, bool caseSensitive,
+        CancellationToken cancellationToken)
+    {
+        var outcome = new GraphQueryOutcome();
+        IEnumerable<JulieSymbol> current = graph.All;
+
+        foreach (var step in steps)
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var result = step.Op switch
+            {
+                "callers" => await ReferencingSymbolsAsync(workspacePath, graph, current, callsOnly: true, caseSensitive, cancellationToken),
+                "references" => await ReferencingSymbolsAsync(workspacePath, graph, current, callsOnly: false, caseSensitive, cancellationToken),
+                "callees" => await CalleesAsync(workspacePath, graph, current, cancellationToken),
+                "members" => current.SelectMany(graph.ChildrenOf),
+                "parent" => current.Select(s => graph.ParentOf(s)).OfType<JulieSymbol>(),
+                "bases" => await RelatedAsync(workspacePath, graph, current, incoming: false, cancellationToken),
+                "implementations" => await RelatedAsync(workspacePath, graph, current, incoming: true, cancellationToken),
+                "limit" => current.Take(int.Parse(step.Argument!)),
+                _ => await FilterAsync(workspacePath, graph, current, step, caseSensitive, cancellationToken)
+            };
+
+            var distinct = result.DistinctBy(s => s.Id).ToList();
+            if (distinct.Count > _maxNodes)
+            {
+                outcome.Truncated = true;
+                distinct = distinct.Take(_maxNodes).ToList();
+            }
+
+            current = distinct;
+            outcome.Steps.Add(new GraphStepReport { Step = step.ToString(), Count = distinct.Count });
+        }
+
+        outcome.Symbols = current
+            .OrderBy(s => s.FilePath, StringComparer.OrdinalIgnoreCase)
+            .ThenBy(s => s.StartLine)
+            .ToList();
+        return outcome;
+    }
+
+    private async Task<IEnumerable<JulieSymbol>> FilterAsync(string workspacePath, CodeGraph graph, IEnumerable<JulieSymbol> symbols,
+        GraphQueryStep step, bool caseSensitive, CancellationToken cancellationToken)
+    {
+        var argument = step.Argument!;
+        Func<JulieSymbol, bool> predicate;
+        switch (step.Op)
+        {
+            case "symbol":
+                var name = NameMatcher(argument, caseSensitive);
+                predicate = s => name(s.Name);
+                break;
+            case "kind":
+                var kinds = argument.Split(',', StringSplitOptions.TrimEntries | StringSplitOptions.RemoveEmptyEntries);
+                predicate = s => kinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase);
+                break;
+            case "language":
+                predicate = s => string.Equals(s.Language, argument, StringComparison.OrdinalIgnoreCase);
+                break;
+            case "in":
+                var path = PathMatcher(workspacePath, argument);
+                predicate = s => path(s.FilePath);
+                break;
+            case "within":
+                var container = NameMatcher(argument, caseSensitive);
+                predicate = s => graph.AncestorsOf(s).Any(a => container(a.Name));
+                break;
+            case "implements":
+                var candidates = symbols.ToList();
+                var matching = await ImplementingAsync(workspacePath, graph, candidates, NameMatcher(argument, caseSensitive), cancellationToken);
+                predicate = s => matching.Contains(s.Id);
+                symbols = candidates;
+                break;
+            default:
+                throw new GraphQueryException("UNKNOWN_STEP", $"Unknown filter '{step.Op}'");
+        }
+
+        return symbols.Where(s => predicate(s) != step.Negated);
+    }
+
+    /// <summary>
+    /// Symbols containing an identifier that names one of the given symbols. An identifier already resolved to a
+    /// different symbol of the same name is skipped, so overloads in unrelated types don't leak in.
+    /// </summary>
+    private async Task<IEnumerable<JulieSymbol>> ReferencingSymbolsAsync(string workspacePath, CodeGraph graph,
+        IEnumerable<JulieSymbol> symbols, bool callsOnly, bool caseSensitive, CancellationToken cancellationToken)
+    {
+        var targets = symbols.ToList();
+        var targetIds = targets.Select(s => s.Id).ToHashSet(StringComparer.Ordinal);
+        var result = new List<JulieSymbol>();
+
+        foreach (var name in targets.Select(s => s.Name).Distinct(StringComparer.Ordinal))
+        {
+            var identifiers = await _sqliteService.GetIdentifiersByNameAsync(workspacePath, name, caseSensitive, cancellationToken);
+            foreach (var identifier in identifiers)
+            {
+                if (callsOnly && identifier.Kind != "call")
+                    continue;
+                if (identifier.TargetSymbolId != null && graph.Contains(identifier.TargetSymbolId) && !targetIds.Contains(identifier.TargetSymbolId))
+                    continue;
+                if (identifier.ContainingSymbolId != null && graph.TryGet(identifier.ContainingSymbolId, out var containing))
+                    result.Add(containing);
+            }
+        }
+
+        return result;
+    }
+
+    private async Task<IEnumerable<JulieSymbol>> CalleesAsync(string workspacePath, CodeGraph graph,
+        IEnumerable<JulieSymbol> symbols, CancellationToken cancellationToken)
+    {
+        var result = new List<JulieSymbol>();
+        foreach (var symbol in symbols)
+        {
+            var identifiers = await _sqliteService.GetIdentifiersByContainingSymbolAsync(workspacePath, symbol.Id, cancellationToken);
+            foreach (var call in identifiers.Where(i => i.Kind == "call"))
+                result.AddRange(CallTargets(graph, call));
+        }
+
+        return result;
+    }
+
+    private static IEnumerable<JulieSymbol> CallTargets(CodeGraph graph, JulieIdentifier call)
+    {
+        if (call.TargetSymbolId != null && graph.TryGet(call.TargetSymbolId, out var target))
+            return new[] { target };
+
+        // Unresolved call: every callable of that name is a candidate
+        var named = graph.Named(call.Name);
+        var callables = named.Where(s => CallableKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase)).ToList();
+        return callables.Count > 0 ? callables : named;
+    }
+
+    // A type stands for its methods: "implements:IController" as sources starts from every action
+    private static List<JulieSymbol> WithCallableMembers(CodeGraph graph, List<JulieSymbol> symbols) =>
+        symbols
+            .SelectMany(s => TypeKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase)
+                ? graph.ChildrenOf(s).Where(c => CallableKinds.Contains(c.Kind, StringComparer.OrdinalIgnoreCase))
+                : new[] { s })
+            .DistinctBy(s => s.Id)
+            .ToList();
+
+    private static List<GraphPathHop> PathTo(CodeGraph graph, Dictionary<string, (string? From, int? Line)> previous, string id)
+    {
+        var hops = new List<GraphPathHop>();
+        for (string? current = id; current != null && graph.TryGet(current, out var symbol); current = previous[current].From)
+            hops.Add(new GraphPathHop { Symbol = symbol, CallLine = previous[current].Line });
+        hops.Reverse();
+        return hops;
+    }
+
+    /// <summary>
+    /// Matches calls against a sink pattern such as <c>exec.Command</c>, <c>Runtime::exec</c> or <c>*.ExecuteSqlRaw</c>.
+    /// The last segment is compared with the call's name; a qualifier other than * must also appear before the
+    /// name in the call's source text, when the index recorded it.
+    /// </summary>
+    private static Func<JulieIdentifier, bool> CallMatcher(string pattern, bool caseSensitive)
+    {
+        var separator = Regex.Match(pattern, @"(\.|::|->)(?=[^.:>-]*$)");
+        var name = NameMatcher(separator.Success ? pattern[(separator.Index + separator.Length)..] : pattern, caseSensitive);
+        var qualifier = separator.Success ? pattern[..separator.Index] : "*";
+        if (qualifier == "*")
+            return call => name(call.Name);
+
+        var options = caseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase | RegexOptions.CultureInvariant;
+        var qualified = new Regex(@"(?<![\w$])" + Regex.Escape(qualifier).Replace(@"\*", @"[\w$]*") + @"\s*(?:\??\.|::|->)\s*", options);
+        return call => name(call.Name)
+                       && (string.IsNullOrEmpty(call.CodeContext) || qualified.Matches(call.CodeContext)
+                           .Any(m => call.CodeContext.AsSpan(m.Index + m.Length).StartsWith(call.Name, StringComparison.Ordinal)));
+    }
+
+
+    private async Task<IEnumerable<JulieSymbol>> RelatedAsync(string workspacePath, CodeGraph graph,
+        IEnumerable<JulieSymbol> symbols, bool incoming, CancellationToken cancellationToken)
+    {
+        var relationships = await GetInheritanceAsync(workspacePath, symbols.Select(s => s.Id).ToList(), incoming, cancellationToken);
+        return relationships
+            .SelectMany(r => r.Value)
+            .Select(r => incoming ? r.FromSymbolId : r.ToSymbolId)
+            .Select(id => graph.TryGet(id, out var related) ? related : null)
+            .OfType<JulieSymbol>();
+    }
+
+    /// <summary>
+    /// Ids of the symbols that, themselves or through a containing type, extend or implement a type matching the
+    /// name - directly or through their base types. Types missing from the database (framework interfaces) are
+    /// recognised from the declaration's signature.
+    /// </summary>
+    private async Task<HashSet<string>> ImplementingAsync(string workspacePath, CodeGraph graph, List<JulieSymbol> symbols,
+        Func<string, bool> baseName, CancellationToken cancellationToken)
+    {
+        var ownerTypes = symbols.ToDictionary(s => s.Id, s => new[] { s }.Concat(graph.AncestorsOf(s)).ToList());
+        var implementing = new HashSet<string>(StringComparer.Ordinal);
+        var satisfied = new Dictionary<string, bool>(StringComparer.Ordinal);
+
+        // Walk up the inheritance graph one level per query, starting from every owner type
+        var frontier = ownerTypes.Values.SelectMany(t => t).Select(t => t.Id).Distinct().ToList();
+        var reachedFrom = frontier.ToDictionary(id => id, id => new HashSet<string> { id }, StringComparer.Ordinal);
+        var visited = new HashSet<string>(frontier, StringComparer.Ordinal);
+
+        foreach (var id in frontier)
+        {
+            if (graph.TryGet(id, out var type) && SignatureInherits(type, baseName))
+                MarkSatisfied(reachedFrom[id], satisfied);
+        }
+
+        for (var depth = 0; depth < MaxInheritanceDepth && frontier.Count > 0; depth++)
+        {
+            var relationships = await GetInheritanceAsync(workspacePath, frontier, incoming: false, cancellationToken);
+            var next = new List<string>();
+            foreach (var (fromId, edges) in relationships)
+            {
+                foreach (var edge in edges)
+                {
+                    var origins = reachedFrom[fromId];
+                    if (graph.TryGet(edge.ToSymbolId, out var baseType) &&
+                        (baseName(baseType.Name) || SignatureInherits(baseType, baseName)))
+                    {
+                        MarkSatisfied(origins, satisfied);
+                    }
+
+                    if (!reachedFrom.TryGetValue(edge.ToSymbolId, out var baseOrigins))
+                    {
+                        baseOrigins = new HashSet<string>(StringComparer.Ordinal);
+                        reachedFrom[edge.ToSymbolId] = baseOrigins;
+                    }
+                    baseOrigins.UnionWith(origins);
+
+                    if (visited.Add(edge.ToSymbolId))
+                        next.Add(edge.ToSymbolId);
+                }
+            }
+            frontier = next;
+        }
+
+        foreach (var (symbolId, owners) in ownerTypes)
+        {
+            if (owners.Any(o => satisfied.GetValueOrDefault(o.Id)))
+                implementing.Add(symbolId);
+        }
+
+        return implementing;
+    }
+
+    private static void MarkSatisfied(IEnumerable<string> typeIds, Dictionary<string, bool> satisfied)
+    {
+        foreach (var id in typeIds)
+            satisfied[id] = true;
+    }
+
+    private static bool SignatureInherits(JulieSymbol type, Func<string, bool> baseName)
+    {
+        if (string.IsNullOrEmpty(type.Signature) || !TypeKinds.Contains(type.Kind, StringComparer.OrdinalIgnoreCase))
+            return false;
+
+        var clause = InheritanceClause.Match(type.Signature);
+        return clause.Success && Regex.Matches(type.Signature[(clause.Index + clause.Length)..], UnicodeIdentifiers.IdentifierPattern)
+            .Any(m => baseName(m.Value));
+    }
+
+    private async Task<Dictionary<string, List<JulieRelationship>>> GetInheritanceAsync(string workspacePath, List<string> ids,
+        bool incoming, CancellationToken cancellationToken)
+    {
+        var result = new Dictionary<string, List<JulieRelationship>>(StringComparer.Ordinal);
+        foreach (var batch in ids.Chunk(RelationshipBatchSize))
+        {
+            var relationships = incoming
+                ? await _sqliteService.GetRelationshipsToSymbolsAsync(workspacePath, batch.ToList(), InheritanceKinds, cancellationToken)
+                : await _sqliteService.GetRelationshipsForSymbolsAsync(workspacePath, batch.ToList(), InheritanceKinds, cancellationToken);
+            foreach (var (id, edges) in relationships)
+                result[id] = edges;
+        }
+        return result;
+    }
+
+    private static Func<string, bool> NameMatcher(string pattern, bool caseSensitive)
+    {
+        if (!pattern.Contains('*') && !pattern.Contains('?'))
+        {
+            return caseSensitive
+                ? name => UnicodeIdentifiers.EqualsNormalized(name, pattern)
+                : name => UnicodeIdentifiers.EqualsIgnoreCase(name, pattern);
+        }
+
+        var regex = new Regex("^" + Regex.Escape(pattern).Replace(@"\*", ".*").Replace(@"\?", ".") + "$",
+            caseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase | RegexOptions.CultureInvariant);
+        return name => regex.IsMatch(name);
+    }
+
+    // "src/Billing" means the directory and everything below it; globs match the workspace-relative path
+    private static Func<string, bool> PathMatcher(string workspacePath, string pattern)
+    {
+        var normalized = pattern.Replace('\\', '/').TrimEnd('/');
+        if (!normalized.Contains('*') && !normalized.Contains('?'))
+            normalized += "/**";
+
+        var regex = new Regex("^" + Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"/\*\*", "(/.*)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]") + "$", RegexOptions.IgnoreCase);
+
+        return filePath =>
+        {
+            var relative = Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath;
+            return regex.IsMatch(relative.Replace('\\', '/'));
+        };
+    }
+
+    private static string ExampleArgument(string op) => op switch
+    {
+        "kind" => "class,interface",
+        "in" => "src/Billing",
+        "language" => "csharp",
+        "limit" => "20",
+        _ => "IPaymentHandler"
+    };
+
+    /// <summary>
+    /// All symbols of the workspace indexed by id, name and parent
+    /// </summary>
+    private sealed class CodeGraph
+    {
+        private readonly Dictionary<string, JulieSymbol> _byId;
+        private readonly ILookup<string, JulieSymbol> _byName;
+        private readonly ILookup<string, JulieSymbol> _byParent;
+
+        public CodeGraph(List<JulieSymbol> symbols)
+        {
+            All = symbols;
+            _byId = symbols.GroupBy(s => s.Id, StringComparer.Ordinal).ToDictionary(g => g.Key, g => g.First(), StringComparer.Ordinal);
+            _byName = symbols.ToLookup(s => s.Name, StringComparer.Ordinal);
+            _byParent = symbols.Where(s => s.ParentId != null).ToLookup(s => s.ParentId!, StringComparer.Ordinal);
+        }
+
+        public List<JulieSymbol> All { get; }
+
+        public bool Contains(string id) => _byId.ContainsKey(id);
+
+        public bool TryGet(string id, out JulieSymbol symbol) => _byId.TryGetValue(id, out symbol!);
+
+        public List<JulieSymbol> Named(string name) => _byName[name].ToList();
+
+        public IEnumerable<JulieSymbol> ChildrenOf(JulieSymbol symbol) => _byParent[symbol.Id];
+
+        public JulieSymbol? ParentOf(JulieSymbol symbol) =>
+            symbol.ParentId != null && _byId.TryGetValue(symbol.ParentId, out var parent) ? parent : null;
+
+        public IEnumerable<JulieSymbol> AncestorsOf(JulieSymbol symbol)
+        {
+            var seen = new HashSet<string>(StringComparer.Ordinal) { symbol.Id };
+            for (var parent = ParentOf(symbol); parent != null && seen.Add(parent.Id); parent = ParentOf(parent))
+                yield return parent;
+        }
+    }
+}
@@ -0,0 +1,29 @@
+namespace COA.CodeSearch.McpServer.Services.Graph;
+
+/// <summary>
+/// Runs small path queries over the symbol database's code graph (symbols, containment, calls, references and
+/// inheritance), e.g. <c>symbol:Charge | callers | in:src/Billing/** | implements:IPaymentHandler</c>
+/// </summary>
+public interface IGraphQueryService
+{
+    /// <summary>
+    /// Splits a query into steps and checks each operator and argument
+    /// </summary>
+    /// <exception cref="GraphQueryException">Empty query, unknown operator or missing argument</exception>
+    List<GraphQueryStep> Parse(string query);
+
+    /// <summary>
+    /// Runs the steps in order against the workspace's symbol database
+    /// </summary>
+    Task<GraphQueryOutcome> ExecuteAsync(string workspacePath, IReadOnlyList<GraphQueryStep> steps, bool caseSensitive,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Shortest call paths from the symbols selected by <paramref name="sources"/> to those selected by
+    /// <paramref name="sinks"/>, or to calls matching one of <paramref name="sinkCalls"/> (e.g. <c>exec.Command</c>,
+    /// <c>*.ExecuteSqlRaw</c>) for sinks outside the index. One path per sink reached, shortest first.
+    /// </summary>
+    /// <param name="maxDepth">Longest path, in calls</param>
+    Task<GraphPathOutcome> FindPathsAsync(string workspacePath, IReadOnlyList<GraphQueryStep> sources, IReadOnlyList<GraphQueryStep>? sinks,
+        IReadOnlyList<string> sinkCalls, int maxDepth, int maxPaths, bool caseSensitive, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,240 @@
+using System.Collections.Concurrent;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.GraphQL;
+
+/// <summary>
+/// Builds the GraphQL index: SDL documents are merged into one schema (extensions included), resolvers found in
+/// source are attached to the fields they serve, and object types link to models of the same name, whose members
+/// resolve fields by default. Root fields need a resolver; other fields are only checked when a model was found.
+/// </summary>
+public class GraphQLIndexService : IGraphQLIndexService
+{
+    private static readonly HashSet<string> TypeKinds = new(StringComparer.Ordinal) { "class", "struct", "record", "interface", "type" };
+    private static readonly (string Operation, string Type)[] DefaultRoots = { ("query", "Query"), ("mutation", "Mutation"), ("subscription", "Subscription") };
+    private static readonly string[] ResolverHints = { "Resolver", "Query", "Mutation", "Subscription", "ObjectType" };
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<GraphQLIndexService> _logger;
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, GraphQLIndex Index)> _indexes = new(StringComparer.OrdinalIgnoreCase);
+
+    public GraphQLIndexService(ISQLiteSymbolService sqliteService, ILogger<GraphQLIndexService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    public async Task<GraphQLIndex> GetIndexAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var stamp = DatabaseStamp(workspacePath);
+        if (_indexes.TryGetValue(workspacePath, out var cached) && cached.Stamp == stamp)
+            return cached.Index;
+
+        var index = new GraphQLIndex();
+        var documents = new List<GraphQLDocument>();
+        var resolvers = new List<GraphQLResolver>();
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var source = GraphQLResolverScanner.IsSourceFile(file.Path);
+            var extension = Path.GetExtension(file.Path).ToLowerInvariant();
+            if (!source && extension is not (".graphql" or ".graphqls" or ".gql"))
+                continue;
+
+            var fullPath = FullPath(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            var relative = Relative(workspacePath, file.Path);
+            if (GraphQLSdlParser.IsCandidate(file.Path, content) && GraphQLSdlParser.Parse(relative, content) is { } document)
+                documents.Add(document);
+            if (source && ResolverHints.Any(h => content.Contains(h, StringComparison.Ordinal)))
+                resolvers.AddRange(GraphQLResolverScanner.Scan(relative, content.Replace("\r\n", "\n").Split('\n')));
+        }
+
+        index.SchemaFiles = documents.Select(d => d.FilePath).Distinct().ToList();
+        index.Resolvers = resolvers.Count;
+        Merge(index, documents);
+
+        if (index.Types.Count > 0)
+        {
+            LinkResolvers(index, resolvers);
+            await LinkModelsAsync(workspacePath, index, cancellationToken);
+            AddUnresolvedFields(index);
+        }
+
+        _indexes[workspacePath] = (stamp, index);
+        _logger.LogDebug("GraphQL index for {Workspace}: {Files} schema files, {Types} types, {Resolvers} resolvers, {Drift} drift",
+            workspacePath, index.SchemaFiles.Count, index.Types.Count, resolvers.Count, index.Drift.Count);
+        return index;
+    }
+
+    /// <summary>
+    /// One schema from every document: a type defined twice keeps its first definition plus the other's new
+    /// fields, and extensions add to the type they extend
+    /// </summary>
+    private static void Merge(GraphQLIndex index, List<GraphQLDocument> documents)
+    {
+        var types = new Dictionary<string, GraphQLType>(StringComparer.Ordinal);
+        foreach (var type in documents.SelectMany(d => d.Types).Concat(documents.SelectMany(d => d.Extensions)))
+        {
+            if (!types.TryGetValue(type.Name, out var merged))
+            {
+                types[type.Name] = type;
+                continue;
+            }
+
+            merged.Fields.AddRange(type.Fields.Where(f => merged.Fields.All(m => m.Name != f.Name)));
+            merged.Implements.AddRange(type.Implements.Except(merged.Implements));
+            merged.Members.AddRange(type.Members.Except(merged.Members));
+        }
+        index.Types = types.Values.OrderBy(t => t.Name, StringComparer.Ordinal).ToList();
+
+        foreach (var (operation, name) in DefaultRoots.Where(r => types.ContainsKey(r.Type)))
+            index.RootTypes[operation] = name;
+        foreach (var (operation, name) in documents.SelectMany(d => d.RootTypes))
+            index.RootTypes[operation] = name;
+    }
+
+    private static void LinkResolvers(GraphQLIndex index, List<GraphQLResolver> resolvers)
+    {
+        var types = index.Types.GroupBy(t => TypeMembers.Comparable(t.Name)).ToDictionary(g => g.Key, g => g.First());
+        var roots = index.RootTypes.Values.Select(n => types.GetValueOrDefault(TypeMembers.Comparable(n))).OfType<GraphQLType>().ToList();
+
+        foreach (var resolver in resolvers)
+        {
+            // Query, Mutation and Subscription resolvers serve whatever the schema names as that root
+            var candidates = resolver.Type == null
+                ? roots
+                : index.RootTypes.TryGetValue(resolver.Type.ToLowerInvariant(), out var root) && types.TryGetValue(TypeMembers.Comparable(root), out var rootType)
+                    ? new List<GraphQLType> { rootType }
+                    : types.TryGetValue(TypeMembers.Comparable(resolver.Type), out var type) ? new List<GraphQLType> { type } : new List<GraphQLType>();
+
+            var fieldName = TypeMembers.Comparable(resolver.Field);
+            var match = candidates
+                .Select(t => (Type: t, Field: t.Fields.FirstOrDefault(f => TypeMembers.Comparable(f.Name) == fieldName)))
+                .FirstOrDefault(m => m.Field != null);
+            if (match.Field != null)
+            {
+                match.Field.Resolvers.Add(new GraphQLCodeLink
+                {
+                    Name = resolver.Name,
+                    Kind = "resolver",
+                    FilePath = resolver.FilePath,
+                    Line = resolver.Line,
+                    Framework = resolver.Framework
+                });
+                continue;
+            }
+
+            index.Drift.Add(new GraphQLDrift
+            {
+                Kind = "orphan-resolver",
+                Subject = $"{resolver.Type ?? "(root)"}.{resolver.Field}",
+                FilePath = resolver.FilePath,
+                Line = resolver.Line,
+                Detail = candidates.Count == 0
+                    ? resolver.Type == null ? "The schema declares no root operation type" : $"The schema has no type {resolver.Type}"
+                    : $"{string.Join(" or ", candidates.Select(c => c.Name))} has no field {resolver.Field} for {resolver.Name} ({resolver.Framework}) to resolve"
+            });
+        }
+    }
+
+    /// <summary>
+    /// Links object types to classes and structs of the same name, and fields without a resolver to the model
+    /// member - field, property or Go method - that resolves them by default
+    /// </summary>
+    private async Task LinkModelsAsync(string workspacePath, GraphQLIndex index, CancellationToken cancellationToken)
+    {
+        var roots = index.RootTypes.Values.ToHashSet(StringComparer.Ordinal);
+        var files = new Dictionary<string, string[]?>(StringComparer.OrdinalIgnoreCase);
+        foreach (var type in index.Types.Where(t => t.Kind == "type" && !roots.Contains(t.Name)))
+        {
+            var models = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, type.Name, caseSensitive: true, cancellationToken))
+                .Where(s => TypeKinds.Contains(s.Kind) && !index.SchemaFiles.Contains(Relative(workspacePath, s.FilePath)))
+                .ToList();
+            foreach (var model in models)
+            {
+                var relative = Relative(workspacePath, model.FilePath);
+                type.Models.Add(new GraphQLCodeLink { Name = model.Name, Kind = model.Kind, FilePath = relative, Line = model.StartLine });
+
+                if (!files.TryGetValue(relative, out var lines))
+                {
+                    lines = await ReadLinesAsync(workspacePath, model.FilePath, cancellationToken);
+                    files[relative] = lines;
+                }
+                if (lines == null)
+                    continue;
+
+                var members = TypeMembers.Of(lines, model);
+                if (relative.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+                {
+                    members.AddRange(GoMethodSets.Parse(relative, lines).Methods
+                        .Where(m => m.Receiver == model.Name)
+                        .Select(m => (m.Method.Name, m.Method.Line)));
+                }
+
+                foreach (var field in type.Fields.Where(f => f.Resolvers.Count == 0))
+                {
+                    var member = members.FirstOrDefault(m => TypeMembers.Comparable(m.Name) == TypeMembers.Comparable(field.Name));
+                    if (member.Name == null)
+                        continue;
+                    field.Resolvers.Add(new GraphQLCodeLink { Name = $"{model.Name}.{member.Name}", Kind = "field", FilePath = relative, Line = member.Line });
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// Root fields without a resolver, and fields of modelled types that neither a resolver nor a model member serves
+    /// </summary>
+    private static void AddUnresolvedFields(GraphQLIndex index)
+    {
+        var roots = index.RootTypes.Values.ToHashSet(StringComparer.Ordinal);
+        foreach (var type in index.Types.Where(t => t.Kind == "type" && (roots.Contains(t.Name) || t.Models.Count > 0)))
+        {
+            foreach (var field in type.Fields.Where(f => f.Resolvers.Count == 0 && !f.Name.StartsWith("__", StringComparison.Ordinal)))
+            {
+                index.Drift.Add(new GraphQLDrift
+                {
+                    Kind = "unresolved-field",
+                    Subject = $"{type.Name}.{field.Name}",
+                    FilePath = field.FilePath,
+                    Line = field.Line,
+                    Detail = roots.Contains(type.Name)
+                        ? $"No resolver serves {type.Name}.{field.Name}"
+                        : $"No resolver serves {type.Name}.{field.Name} and {string.Join(", ", type.Models.Select(m => m.Name).Distinct())} has no member named like it"
+                });
+            }
+        }
+    }
+
+    private async Task<string[]?> ReadLinesAsync(string workspacePath, string filePath, CancellationToken cancellationToken)
+    {
+        var fullPath = FullPath(workspacePath, filePath);
+        if (File.Exists(fullPath))
+            return (await File.ReadAllTextAsync(fullPath, cancellationToken)).Replace("\r\n", "\n").Split('\n');
+
+        return await _sqliteService.GetFileByPathAsync(workspacePath, filePath, cancellationToken) is { Content: { } content }
+            ? content.Replace("\r\n", "\n").Split('\n')
+            : null;
+    }
+
+    private DateTime DatabaseStamp(string workspacePath)
+    {
+        var path = _sqliteService.GetDatabasePath(workspacePath);
+        var stamp = File.Exists(path) ? File.GetLastWriteTimeUtc(path) : DateTime.MinValue;
+        var wal = path + "-wal";
+        return File.Exists(wal) && File.GetLastWriteTimeUtc(wal) > stamp ? File.GetLastWriteTimeUtc(wal) : stamp;
+    }
+
+    private static string FullPath(string workspacePath, string filePath) =>
+        Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+}
@@ -0,0 +1,164 @@
+namespace COA.CodeSearch.McpServer.Services.GraphQL;
+
+/// <summary>
+/// The definitions one SDL document or template declares; file paths are workspace-relative
+/// </summary>
+public class GraphQLDocument
+{
+    public string FilePath { get; set; } = string.Empty;
+    public List<GraphQLType> Types { get; set; } = new();
+
+    /// <summary>
+    /// <c>extend type</c> definitions, merged into the type of the same name
+    /// </summary>
+    public List<GraphQLType> Extensions { get; set; } = new();
+
+    /// <summary>
+    /// Root operation types a <c>schema { }</c> block names, by operation: query, mutation, subscription
+    /// </summary>
+    public Dictionary<string, string> RootTypes { get; set; } = new(StringComparer.Ordinal);
+}
+
+/// <summary>
+/// A named type of the schema with its fields, the model types backing it and the resolvers of its fields
+/// </summary>
+public class GraphQLType
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// type, interface, input, enum, union or scalar
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public List<string> Implements { get; set; } = new();
+    public List<GraphQLField> Fields { get; set; } = new();
+
+    /// <summary>
+    /// Enum values, or the member types of a union
+    /// </summary>
+    public List<string> Members { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Classes, structs and interfaces named like the type, whose members resolve fields by default
+    /// </summary>
+    public List<GraphQLCodeLink> Models { get; set; } = new();
+}
+
+/// <summary>
+/// A field of an object, interface or input type
+/// </summary>
+public class GraphQLField
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The field's type as written: [User!]!
+    /// </summary>
+    public string Type { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Arguments as written: id: ID!
+    /// </summary>
+    public List<string> Arguments { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Resolver functions and methods for the field, or the model member resolving it by default
+    /// </summary>
+    public List<GraphQLCodeLink> Resolvers { get; set; } = new();
+}
+
+/// <summary>
+/// A resolver found in source: the schema type and field it serves and the function serving it
+/// </summary>
+public class GraphQLResolver
+{
+    /// <summary>
+    /// Type the resolver belongs to; null for root resolvers that do not say which operation type they serve
+    /// </summary>
+    public string? Type { get; set; }
+
+    /// <summary>
+    /// Field name as the framework derives it from the function (GetUserAsync serves user)
+    /// </summary>
+    public string Field { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Function or method name as declared
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// gqlgen, hotchocolate, nestjs, type-graphql or resolver-map
+    /// </summary>
+    public string Framework { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// Code location a schema type or field links to
+/// </summary>
+public class GraphQLCodeLink
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// resolver for a resolver function, field for a model member, or the symbol kind of a model type
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Framework the resolver was recognised by; null for models
+    /// </summary>
+    public string? Framework { get; set; }
+}
+
+/// <summary>
+/// Where schema and resolvers disagree: unresolved-field (a field nothing resolves) or orphan-resolver (a
+/// resolver for a type or field the schema does not declare)
+/// </summary>
+public class GraphQLDrift
+{
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Type.field the entry is about
+    /// </summary>
+    public string Subject { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Detail { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// The workspace's schema, merged from every SDL document, linked to resolvers and models
+/// </summary>
+public class GraphQLIndex
+{
+    public List<string> SchemaFiles { get; set; } = new();
+    public List<GraphQLType> Types { get; set; } = new();
+
+    /// <summary>
+    /// Root operation types by operation, from a schema block or the default Query, Mutation and Subscription
+    /// </summary>
+    public Dictionary<string, string> RootTypes { get; set; } = new(StringComparer.Ordinal);
+
+    /// <summary>
+    /// Resolvers recognised in source, linked or not
+    /// </summary>
+    public int Resolvers { get; set; }
+
+    public List<GraphQLDrift> Drift { get; set; } = new();
+}
@@ -0,0 +1,276 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.GraphQL;
+
+/// <summary>
+/// Finds GraphQL resolvers in source by the conventions of the common frameworks: gqlgen and graph-gophers
+/// receivers (<c>func (r *queryResolver) User</c>), Hot Chocolate type classes (<c>[QueryType]</c>,
+/// <c>[ExtendObjectType]</c>), NestJS and TypeGraphQL decorators, and resolver maps passed to Apollo or
+/// graphql-tools (<c>{ Query: { user: ... } }</c>)
+/// </summary>
+public static class GraphQLResolverScanner
+{
+    private static readonly HashSet<string> ScriptExtensions = new(StringComparer.OrdinalIgnoreCase) { ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs" };
+    private static readonly HashSet<string> RootReceivers = new(StringComparer.Ordinal) { "Resolver", "RootResolver", "rootResolver" };
+
+    // Go: func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error)
+    private static readonly Regex GoResolverMethod = new(
+        @"^func\s*\(\s*\w*\s*\*?\s*(?<receiver>\w*Resolver)\s*\)\s*(?<name>[A-Z]\w*)\s*\(", RegexOptions.Compiled);
+
+    // gqlgen's root accessors hand out the per-type resolvers: func (r *Resolver) Query() QueryResolver { ... }
+    private static readonly Regex GoResolverAccessor = new(@"\)\s*\*?[\w.]*Resolver\s*\{", RegexOptions.Compiled);
+
+    // C#: [QueryType], [ExtendObjectType("Query")], [ExtendObjectType(typeof(User))], [ObjectType<User>]
+    private static readonly Regex CSharpTypeAttribute = new(
+        @"\[\s*(?<operation>Query|Mutation|Subscription)Type\b|\[\s*(?:ExtendObjectType|ObjectType)\s*(?:<\s*(?<type>[\w.]+)\s*>|\(\s*(?:typeof\s*\(\s*(?<type>[\w.]+)\s*\)|""(?<type>\w+)""|OperationTypeNames\.(?<type>\w+)))",
+        RegexOptions.Compiled);
+    private static readonly Regex CSharpClass = new(@"\bclass\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex CSharpMethod = new(
+        @"^\s*(?:\[[^\]]*\]\s*)*public\s+(?:static\s+|async\s+|virtual\s+|override\s+)*[\w<>\[\]?,. ]+?\s+(?<name>\w+)\s*(?:<[^>]*>)?\s*\(",
+        RegexOptions.Compiled);
+    private static readonly Regex CSharpGraphQLName = new(@"\[\s*GraphQLName\s*\(\s*""(?<name>\w+)""", RegexOptions.Compiled);
+
+    // TypeScript: @Resolver(() => User), @Resolver(of => User), @Resolver('User'), @Resolver(User), @Resolver()
+    private static readonly Regex TsResolverClass = new(
+        @"^\s*@Resolver\s*\(\s*(?:(?:\(\s*\w*\s*\)|\w+)\s*=>\s*\[?\s*(?<type>\w+)|['""](?<type>\w+)['""]|(?<type>[A-Z]\w*))?", RegexOptions.Compiled);
+    private static readonly Regex TsFieldDecorator = new(
+        @"^\s*@(?<kind>Query|Mutation|Subscription|ResolveField|FieldResolver|ResolveProperty)\s*\((?<arguments>.*)", RegexOptions.Compiled);
+    private static readonly Regex TsNameOption = new(@"\bname\s*:\s*['""](?<name>\w+)['""]", RegexOptions.Compiled);
+    private static readonly Regex TsLeadingString = new(@"^\s*['""](?<name>\w+)['""]", RegexOptions.Compiled);
+    private static readonly Regex TsMethod = new(
+        @"^\s*(?:(?:public|private|protected|async|static)\s+)*(?<name>[A-Za-z_$][\w$]*)\s*(?:<[^>]*>)?\s*\(", RegexOptions.Compiled);
+
+    // Resolver maps: Query: { user: (_, args) => ..., async users() { ... }, posts }
+    private static readonly Regex MapRoot = new(@"^\s*['""]?(?:Query|Mutation|Subscription)['""]?\s*:\s*\{", RegexOptions.Multiline | RegexOptions.Compiled);
+    private static readonly Regex MapType = new(@"^\s*['""]?(?<type>[A-Z]\w*)['""]?\s*:\s*\{", RegexOptions.Compiled);
+    private static readonly Regex MapKey = new(@"^\s*(?:async\s+)?(?:\*\s*)?['""]?(?<name>[A-Za-z_$][\w$]*)['""]?\s*(?:\??:|\(|,|$)", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Source files resolvers are read from
+    /// </summary>
+    public static bool IsSourceFile(string filePath)
+    {
+        var extension = Path.GetExtension(filePath);
+        return extension.Equals(".go", StringComparison.OrdinalIgnoreCase) || extension.Equals(".cs", StringComparison.OrdinalIgnoreCase)
+            || ScriptExtensions.Contains(extension) && !filePath.EndsWith(".d.ts", StringComparison.OrdinalIgnoreCase)
+                && !filePath.EndsWith(".min.js", StringComparison.OrdinalIgnoreCase);
+    }
+
+    public static List<GraphQLResolver> Scan(string filePath, IReadOnlyList<string> lines)
+    {
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var masked = DataFlowScanner.MaskLines(lines, extension);
+        return extension switch
+        {
+            ".go" => ScanGo(filePath, masked),
+            ".cs" => ScanCSharp(filePath, lines, masked),
+            _ when ScriptExtensions.Contains(extension) => ScanScript(filePath, lines, masked),
+            _ => new List<GraphQLResolver>()
+        };
+    }
+
+    private static List<GraphQLResolver> ScanGo(string filePath, string[] masked)
+    {
+        var resolvers = new List<GraphQLResolver>();
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var method = GoResolverMethod.Match(masked[i]);
+            if (!method.Success || GoResolverAccessor.IsMatch(masked[i][(method.Index + method.Length - 1)..]))
+                continue;
+
+            // queryResolver serves Query, userResolver User; graph-gophers' root Resolver any operation type
+            var receiver = method.Groups["receiver"].Value;
+            var name = method.Groups["name"].Value;
+            resolvers.Add(new GraphQLResolver
+            {
+                Type = RootReceivers.Contains(receiver) ? null : Capitalize(receiver[..^"Resolver".Length]),
+                Field = Uncapitalize(name),
+                Name = name,
+                Framework = "gqlgen",
+                FilePath = filePath,
+                Line = i + 1
+            });
+        }
+        return resolvers;
+    }
+
+    private static List<GraphQLResolver> ScanCSharp(string filePath, IReadOnlyList<string> lines, string[] masked)
+    {
+        var resolvers = new List<GraphQLResolver>();
+        var usesGraphQL = lines.Any(l => l.Contains("HotChocolate", StringComparison.Ordinal) || l.Contains("GraphQL", StringComparison.Ordinal));
+        for (var i = 0; i < masked.Length; i++)
+        {
+            var header = CSharpClass.Match(masked[i]);
+            if (!header.Success)
+                continue;
+
+            // The type attribute sits on the class line or the lines just above it
+            string? type = null;
+            for (var j = Math.Max(0, i - 3); j <= i && type == null; j++)
+            {
+                var attribute = CSharpTypeAttribute.Match(lines[j]);
+                if (attribute.Success)
+                    type = attribute.Groups["operation"].Success ? attribute.Groups["operation"].Value : LastSegment(attribute.Groups["type"].Value);
+            }
+            if (type == null && usesGraphQL && header.Groups["name"].Value is "Query" or "Mutation" or "Subscription")
+                type = header.Groups["name"].Value;
+            if (type == null)
+                continue;
+
+            var depth = 0;
+            var opened = false;
+            for (var j = i; j < masked.Length; j++)
+            {
+                var before = depth;
+                depth += masked[j].Count(c => c == '{') - masked[j].Count(c => c == '}');
+                opened |= depth > 0;
+                if (before == 1 && CSharpMethod.Match(masked[j]) is { Success: true } method
+                    && !lines[j].Contains("GraphQLIgnore", StringComparison.Ordinal) && !(j > 0 && lines[j - 1].Contains("GraphQLIgnore", StringComparison.Ordinal)))
+                {
+                    var name = method.Groups["name"].Value;
+                    var renamed = CSharpGraphQLName.Match(lines[j]);
+                    if (!renamed.Success && j > 0)
+                        renamed = CSharpGraphQLName.Match(lines[j - 1]);
+                    resolvers.Add(new GraphQLResolver
+                    {
+                        Type = type,
+                        Field = renamed.Success ? renamed.Groups["name"].Value : HotChocolateFieldName(name),
+                        Name = name,
+                        Framework = "hotchocolate",
+                        FilePath = filePath,
+                        Line = j + 1
+                    });
+                }
+                if (opened && depth <= 0)
+                {
+                    i = j;
+                    break;
+                }
+            }
+        }
+        return resolvers;
+    }
+
+    private static List<GraphQLResolver> ScanScript(string filePath, IReadOnlyList<string> lines, string[] masked)
+    {
+        var resolvers = new List<GraphQLResolver>();
+        var framework = lines.Any(l => l.Contains("type-graphql", StringComparison.Ordinal)) ? "type-graphql" : "nestjs";
+        var decorated = lines.Any(l => l.Contains("graphql", StringComparison.OrdinalIgnoreCase));
+
+        // Decorated resolver classes; the @Resolver type applies until the next one
+        string? classType = null;
+        for (var i = 0; decorated && i < masked.Length; i++)
+        {
+            // Decorator arguments name types and fields in strings, so they are read unmasked
+            if (!masked[i].TrimStart().StartsWith('@'))
+                continue;
+            if (TsResolverClass.Match(lines[i]) is { Success: true } resolverClass)
+            {
+                classType = resolverClass.Groups["type"].Success ? resolverClass.Groups["type"].Value : null;
+                continue;
+            }
+
+            var decorator = TsFieldDecorator.Match(lines[i]);
+            if (!decorator.Success)
+                continue;
+
+            var kind = decorator.Groups["kind"].Value;
+            var type = kind is "Query" or "Mutation" or "Subscription" ? kind : classType;
+            if (type == null)
+                continue;
+
+            // The decorator's options may run over several lines before the method they decorate
+            var options = lines[i][decorator.Groups["arguments"].Index..];
+            string? method = null;
+            var methodLine = i;
+            for (var j = i + 1; j < masked.Length && j <= i + 8; j++)
+            {
+                if (!masked[j].TrimStart().StartsWith('@') && TsMethod.Match(masked[j]) is { Success: true } declaration)
+                {
+                    method = declaration.Groups["name"].Value;
+                    methodLine = j;
+                    break;
+                }
+                options += "\n" + lines[j];
+            }
+            if (method == null)
+                continue;
+
+            var named = TsNameOption.Match(options);
+            var field = named.Success ? named.Groups["name"].Value
+                : kind is "ResolveField" or "FieldResolver" or "ResolveProperty" && TsLeadingString.Match(options) is { Success: true } leading ? leading.Groups["name"].Value
+                : method;
+            resolvers.Add(new GraphQLResolver
+            {
+                Type = type,
+                Field = field,
+                Name = method,
+                Framework = framework,
+                FilePath = filePath,
+                Line = methodLine + 1
+            });
+        }
+
+        if (resolvers.Count == 0 && MapRoot.IsMatch(string.Join('\n', masked)))
+            resolvers.AddRange(ScanResolverMap(filePath, masked));
+        return resolvers;
+    }
+
+    /// <summary>
+    /// Keys of object literals under a capitalized key, in a file that declares a Query, Mutation or Subscription map
+    /// </summary>
+    private static List<GraphQLResolver> ScanResolverMap(string filePath, string[] masked)
+    {
+        var resolvers = new List<GraphQLResolver>();
+        var depth = 0;
+        string? type = null;
+        var typeDepth = 0;
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (type != null && depth <= typeDepth)
+                type = null;
+
+            if (type != null && depth == typeDepth + 1 && MapKey.Match(masked[i]) is { Success: true } key
+                && !key.Groups["name"].Value.StartsWith("__", StringComparison.Ordinal))
+            {
+                resolvers.Add(new GraphQLResolver
+                {
+                    Type = type,
+                    Field = key.Groups["name"].Value,
+                    Name = key.Groups["name"].Value,
+                    Framework = "resolver-map",
+                    FilePath = filePath,
+                    Line = i + 1
+                });
+            }
+            else if (type == null && MapType.Match(masked[i]) is { Success: true } block)
+            {
+                type = block.Groups["type"].Value;
+                typeDepth = depth;
+            }
+
+            depth += masked[i].Count(c => c == '{') - masked[i].Count(c => c == '}');
+        }
+        return resolvers;
+    }
+
+    /// <summary>
+    /// The field Hot Chocolate infers from a method: GetUserAsync serves user
+    /// </summary>
+    private static string HotChocolateFieldName(string method)
+    {
+        var name = method;
+        if (name.Length > 3 && name.StartsWith("Get", StringComparison.Ordinal) && char.IsUpper(name[3]))
+            name = name[3..];
+        if (name.Length > 5 && name.EndsWith("Async", StringComparison.Ordinal))
+            name = name[..^5];
+        return Uncapitalize(name);
+    }
+
+    private static string LastSegment(string name) => name[(name.LastIndexOf('.') + 1)..];
+
+    private static string Capitalize(string name) => name.Length == 0 ? name : char.ToUpperInvariant(name[0]) + name[1..];
+
+    private static string Uncapitalize(string name) => name.Length == 0 ? name : char.ToLowerInvariant(name[0]) + name[1..];
+}
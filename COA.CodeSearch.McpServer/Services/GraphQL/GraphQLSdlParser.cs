@@ -0,0 +1,368 @@
+using System.Text;
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.GraphQL;
+
+/// <summary>
+/// Reads GraphQL SDL - from .graphql files or gql/graphql tagged templates - into types and fields. Operations,
+/// fragments and directive definitions are skipped, so a document mixing them with type definitions reads fine.
+/// </summary>
+public static class GraphQLSdlParser
+{
+    private static readonly HashSet<string> SchemaExtensions = new(StringComparer.OrdinalIgnoreCase) { ".graphql", ".graphqls", ".gql" };
+    private static readonly HashSet<string> TemplateExtensions = new(StringComparer.OrdinalIgnoreCase) { ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs" };
+    private static readonly HashSet<string> TypeKeywords = new(StringComparer.Ordinal) { "type", "interface", "input", "enum", "union", "scalar" };
+    private static readonly Regex TaggedTemplate = new(@"\b(?:gql|graphql)\s*(?:\(\s*)?`(?<body>[^`]*)`", RegexOptions.Compiled);
+    private static readonly Regex TypeDefinition = new(@"(?:^|\s)(?:extend\s+)?(?:type|interface|input|enum|union|scalar)\s+[_A-Za-z]", RegexOptions.Compiled);
+
+    /// <summary>
+    /// Files holding SDL: schema files, and scripts that may define it in tagged templates
+    /// </summary>
+    public static bool IsCandidate(string filePath, string content)
+    {
+        var extension = Path.GetExtension(filePath);
+        return SchemaExtensions.Contains(extension)
+            || TemplateExtensions.Contains(extension) && !filePath.EndsWith(".d.ts", StringComparison.OrdinalIgnoreCase)
+                && (content.Contains("gql", StringComparison.Ordinal) || content.Contains("graphql", StringComparison.Ordinal))
+                && TypeDefinition.IsMatch(content);
+    }
+
+    /// <summary>
+    /// The definitions of a schema file, or of every tagged template in a script; null when there are none
+    /// </summary>
+    public static GraphQLDocument? Parse(string filePath, string content)
+    {
+        var document = new GraphQLDocument { FilePath = filePath };
+        if (SchemaExtensions.Contains(Path.GetExtension(filePath)))
+        {
+            Read(document, content, 0);
+        }
+        else
+        {
+            foreach (Match template in TaggedTemplate.Matches(content))
+            {
+                var body = template.Groups["body"];
+                if (!TypeDefinition.IsMatch(body.Value))
+                    continue;
+                var lineOffset = content.AsSpan(0, body.Index).Count('\n');
+                Read(document, body.Value, lineOffset);
+            }
+        }
+
+        return document.Types.Count > 0 || document.Extensions.Count > 0 || document.RootTypes.Count > 0 ? document : null;
+    }
+
+    private static void Read(GraphQLDocument document, string text, int lineOffset)
+    {
+        var tokens = Tokenize(text, lineOffset);
+        var reader = new Reader(tokens);
+        while (!reader.AtEnd)
+        {
+            var token = reader.Next();
+            if (token.Kind == TokenKind.String)
+                continue; // description
+
+            var extend = token.Text == "extend" && token.Kind == TokenKind.Name;
+            if (extend)
+                token = reader.Next();
+
+            if (token.Kind == TokenKind.Name && TypeKeywords.Contains(token.Text) && reader.Peek().Kind == TokenKind.Name)
+            {
+                var type = ReadType(reader, token, document.FilePath);
+                (extend ? document.Extensions : document.Types).Add(type);
+            }
+            else if (token.Kind == TokenKind.Name && token.Text == "schema")
+            {
+                SkipDirectives(reader);
+                if (reader.Peek().Text != "{")
+                    continue;
+                reader.Next();
+                while (!reader.AtEnd && reader.Peek().Text != "}")
+                {
+                    var operation = reader.Next();
+                    if (reader.Peek().Text == ":")
+                    {
+                        reader.Next();
+                        document.RootTypes[operation.Text] = reader.Next().Text;
+                    }
+                }
+                reader.Next();
+            }
+            else if (token.Kind == TokenKind.Name && token.Text == "directive")
+            {
+                // directive @name(args) repeatable on FIELD | OBJECT
+                reader.Next();
+                reader.Next();
+                if (reader.Peek().Text == "(")
+                    reader.SkipGroup();
+                while (!reader.AtEnd && (reader.Peek().Kind == TokenKind.Name && !IsDefinitionStart(reader.Peek().Text) || reader.Peek().Text == "|"))
+                    reader.Next();
+            }
+            else
+            {
+                // An operation or fragment: skip its selection set
+                if (token.Text == "{")
+                {
+                    reader.SkipGroup(1);
+                    continue;
+                }
+                while (!reader.AtEnd && reader.Peek().Text != "{")
+                {
+                    if (reader.Peek().Text == "(")
+                        reader.SkipGroup();
+                    else
+                        reader.Next();
+                }
+                if (!reader.AtEnd)
+                    reader.SkipGroup();
+            }
+        }
+    }
+
+    private static GraphQLType ReadType(Reader reader, Token keyword, string filePath)
+    {
+        var name = reader.Next();
+        var type = new GraphQLType { Name = name.Text, Kind = keyword.Text, FilePath = filePath, Line = keyword.Line };
+
+        if (reader.Peek().Text == "implements")
+        {
+            reader.Next();
+            while (reader.Peek().Kind == TokenKind.Name && !IsDefinitionStart(reader.Peek().Text) || reader.Peek().Text == "&")
+            {
+                var next = reader.Next();
+                if (next.Text != "&")
+                    type.Implements.Add(next.Text);
+            }
+        }
+        SkipDirectives(reader);
+
+        if (keyword.Text == "union")
+        {
+            if (reader.Peek().Text != "=")
+                return type;
+            reader.Next();
+            while (reader.Peek().Kind == TokenKind.Name && !IsDefinitionStart(reader.Peek().Text) || reader.Peek().Text == "|")
+            {
+                var next = reader.Next();
+                if (next.Text != "|")
+                    type.Members.Add(next.Text);
+            }
+            return type;
+        }
+
+        if (reader.Peek().Text != "{")
+            return type;
+        reader.Next();
+
+        while (!reader.AtEnd && reader.Peek().Text != "}")
+        {
+            var token = reader.Next();
+            if (token.Kind != TokenKind.Name)
+                continue; // description
+
+            if (keyword.Text == "enum")
+            {
+                type.Members.Add(token.Text);
+                SkipDirectives(reader);
+                continue;
+            }
+
+            var field = new GraphQLField { Name = token.Text, FilePath = filePath, Line = token.Line };
+            if (reader.Peek().Text == "(")
+                field.Arguments = ReadArguments(reader);
+            if (reader.Peek().Text == ":")
+            {
+                reader.Next();
+                field.Type = ReadTypeReference(reader);
+                if (reader.Peek().Text == "=")
+                {
+                    reader.Next();
+                    SkipValue(reader);
+                }
+            }
+            SkipDirectives(reader);
+            type.Fields.Add(field);
+        }
+        reader.Next();
+        return type;
+    }
+
+    private static List<string> ReadArguments(Reader reader)
+    {
+        var arguments = new List<string>();
+        reader.Next();
+        while (!reader.AtEnd && reader.Peek().Text != ")")
+        {
+            var token = reader.Next();
+            if (token.Kind != TokenKind.Name || reader.Peek().Text != ":")
+                continue; // description
+
+            reader.Next();
+            var argument = $"{token.Text}: {ReadTypeReference(reader)}";
+            if (reader.Peek().Text == "=")
+            {
+                reader.Next();
+                argument += " = " + SkipValue(reader);
+            }
+            SkipDirectives(reader);
+            arguments.Add(argument);
+        }
+        reader.Next();
+        return arguments;
+    }
+
+    /// <summary>
+    /// A named or list type with its non-null markers: [User!]!
+    /// </summary>
+    private static string ReadTypeReference(Reader reader)
+    {
+        var text = new StringBuilder();
+        if (reader.Peek().Text == "[")
+        {
+            reader.Next();
+            text.Append('[').Append(ReadTypeReference(reader));
+            if (reader.Peek().Text == "]")
+                reader.Next();
+            text.Append(']');
+        }
+        else if (reader.Peek().Kind == TokenKind.Name)
+        {
+            text.Append(reader.Next().Text);
+        }
+        if (reader.Peek().Text == "!")
+        {
+            reader.Next();
+            text.Append('!');
+        }
+        return text.ToString();
+    }
+
+    private static string SkipValue(Reader reader)
+    {
+        var first = reader.Peek();
+        if (first.Text is "[" or "{")
+        {
+            reader.SkipGroup();
+            return first.Text == "[" ? "[...]" : "{...}";
+        }
+        if (first.Text == "$")
+            reader.Next();
+        return reader.AtEnd ? string.Empty : reader.Next().Text;
+    }
+
+    private static void SkipDirectives(Reader reader)
+    {
+        while (reader.Peek().Text == "@")
+        {
+            reader.Next();
+            reader.Next();
+            if (reader.Peek().Text == "(")
+                reader.SkipGroup();
+        }
+    }
+
+    private static bool IsDefinitionStart(string text) =>
+        TypeKeywords.Contains(text) || text is "extend" or "schema" or "directive" or "query" or "mutation" or "subscription" or "fragment";
+
+    private enum TokenKind { Name, Punctuator, String }
+
+    private readonly record struct Token(TokenKind Kind, string Text, int Line);
+
+    private static List<Token> Tokenize(string text, int lineOffset)
+    {
+        var tokens = new List<Token>();
+        var line = lineOffset + 1;
+        for (var i = 0; i < text.Length;)
+        {
+            var c = text[i];
+            if (c == '\n')
+            {
+                line++;
+                i++;
+            }
+            else if (char.IsWhiteSpace(c) || c == ',' || c == '\uFEFF')
+            {
+                i++;
+            }
+            else if (c == '#')
+            {
+                while (i < text.Length && text[i] != '\n')
+                    i++;
+            }
+            else if (c == '"')
+            {
+                var start = line;
+                var first = i;
+                var block = string.CompareOrdinal(text, i, "\"\"\"", 0, 3) == 0;
+                i += block ? 3 : 1;
+                while (i < text.Length)
+                {
+                    if (text[i] == '\n')
+                        line++;
+                    if (text[i] == '\\')
+                    {
+                        i += 2;
+                        continue;
+                    }
+                    if (block ? string.CompareOrdinal(text, i, "\"\"\"", 0, 3) == 0 : text[i] == '"' || text[i] == '\n')
+                        break;
+                    i++;
+                }
+                i = Math.Min(i + (block ? 3 : 1), text.Length);
+                tokens.Add(new Token(TokenKind.String, text[first..i], start));
+            }
+            else if (char.IsLetterOrDigit(c) || c is '_' or '-')
+            {
+                var start = i;
+                while (i < text.Length && (char.IsLetterOrDigit(text[i]) || text[i] is '_' or '.' or '-' or '+'))
+                    i++;
+                tokens.Add(new Token(TokenKind.Name, text[start..i], line));
+            }
+            else if (c == '.' && string.CompareOrdinal(text, i, "...", 0, 3) == 0)
+            {
+                tokens.Add(new Token(TokenKind.Punctuator, "...", line));
+                i += 3;
+            }
+            else
+            {
+                tokens.Add(new Token(TokenKind.Punctuator, c.ToString(), line));
+                i++;
+            }
+        }
+        return tokens;
+    }
+
+    private sealed class Reader
+    {
+        private static readonly Token End = new(TokenKind.Punctuator, string.Empty, 0);
+        private readonly List<Token> _tokens;
+        private int _position;
+
+        public Reader(List<Token> tokens) => _tokens = tokens;
+
+        public bool AtEnd => _position >= _tokens.Count;
+
+        public Token Peek() => AtEnd ? End : _tokens[_position];
+
+        public Token Next() => AtEnd ? End : _tokens[_position++];
+
+        /// <summary>
+        /// Skips a bracketed group - (), [] or {} - with everything nested in it; depth 1 when its opening
+        /// bracket was already read
+        /// </summary>
+        public void SkipGroup(int depth = 0)
+        {
+            do
+            {
+                var token = Next();
+                if (token.Kind != TokenKind.Punctuator)
+                    continue;
+                if (token.Text is "(" or "[" or "{")
+                    depth++;
+                else if (token.Text is ")" or "]" or "}")
+                    depth--;
+            }
+            while (depth > 0 && !AtEnd);
+        }
+    }
+}
@@ -0,0 +1,14 @@
+namespace COA.CodeSearch.McpServer.Services.GraphQL;
+
+/// <summary>
+/// Indexes the GraphQL schema a workspace defines in SDL and links its fields to the resolvers serving them and
+/// its object types to the models backing them
+/// </summary>
+public interface IGraphQLIndexService
+{
+    /// <summary>
+    /// The merged schema, linked to code, with the drift found between them; rebuilt when the symbol database
+    /// changes
+    /// </summary>
+    Task<GraphQLIndex> GetIndexAsync(string workspacePath, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,26 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Service that remembers which findings each file already had, so only new ones trigger notifications
+/// </summary>
+public interface IFindingsBaselineService
+{
+    /// <summary>
+    /// Replaces a file's baseline with the current findings and returns the fingerprints that were not in it.
+    /// The baseline is saved at once when there are new findings; other changes wait for <see cref="Flush"/>.
+    /// </summary>
+    /// <param name="workspacePath">Workspace the file belongs to</param>
+    /// <param name="category">Finding category, e.g. "patterns" or "secrets"</param>
+    /// <param name="filePath">The analyzed file</param>
+    /// <param name="fingerprints">Stable identities of the file's current findings</param>
+    /// <param name="reportFirstSight">
+    /// When false, a file seen for the first time only establishes its baseline and nothing is reported as new
+    /// </param>
+    IReadOnlyList<string> UpdateBaseline(string workspacePath, string category, string filePath,
+        IEnumerable<string> fingerprints, bool reportFirstSight);
+
+    /// <summary>
+    /// Saves the workspace's baseline if it has unsaved changes; call once a batch of files has been analyzed
+    /// </summary>
+    void Flush(string workspacePath);
+}
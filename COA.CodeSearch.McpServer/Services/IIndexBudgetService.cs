@@ -0,0 +1,73 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Keeps a workspace index within its size budget (CodeSearch:IndexStorage:MaxWorkspaceSizeMB) by pruning files
+/// from it in strategy order, and remembers what was pruned so re-indexing doesn't bring it back
+/// </summary>
+public interface IIndexBudgetService
+{
+    /// <summary>
+    /// Whether pruning is enabled and a budget is configured
+    /// </summary>
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// Whether a file was pruned and must be left out of the index
+    /// </summary>
+    bool IsPruned(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Prunes files until the index fits the budget. With dryRun, reports what would be pruned without touching the index.
+    /// </summary>
+    Task<IndexPruneReport> EnforceBudgetAsync(string workspacePath, bool dryRun = false, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Every file currently pruned from a workspace index, most recent first
+    /// </summary>
+    IReadOnlyList<PrunedFile> GetPrunedFiles(string workspacePath);
+
+    /// <summary>
+    /// Forgets pruned files so the next full index includes them again (used by force rebuilds)
+    /// </summary>
+    void ResetPruned(string workspacePath);
+}
+
+/// <summary>
+/// A file left out of the index to keep it within budget
+/// </summary>
+public class PrunedFile
+{
+    /// <summary>
+    /// Path relative to the workspace
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Strategy that selected the file: cold, generated or largest
+    /// </summary>
+    public string Strategy { get; set; } = string.Empty;
+
+    public long SizeBytes { get; set; }
+    public DateTime PrunedAt { get; set; }
+}
+
+/// <summary>
+/// Outcome of a budget enforcement pass
+/// </summary>
+public class IndexPruneReport
+{
+    public bool DryRun { get; set; }
+    public long BudgetBytes { get; set; }
+    public long SizeBeforeBytes { get; set; }
+    public long SizeAfterBytes { get; set; }
+
+    /// <summary>
+    /// Files pruned by this pass (or that would be, in a dry run)
+    /// </summary>
+    public List<PrunedFile> Pruned { get; set; } = new();
+
+    /// <summary>
+    /// False when every strategy was exhausted and the index is still over budget
+    /// </summary>
+    public bool WithinBudget { get; set; }
+}
@@ -0,0 +1,48 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Service for encrypting index data at rest (Lucene snapshots and SQLite symbol databases).
+/// Keys are resolved from the OS keychain or an environment variable; never from configuration files.
+/// </summary>
+public interface IIndexEncryptionService
+{
+    /// <summary>
+    /// Whether encryption at rest is enabled in configuration
+    /// </summary>
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// Extension appended to plaintext file names when they are sealed (e.g. workspace.db → workspace.db.enc)
+    /// </summary>
+    string SealedExtension { get; }
+
+    /// <summary>
+    /// Checks whether a usable key can be resolved from the configured key source
+    /// </summary>
+    bool HasKey();
+
+    /// <summary>
+    /// Encrypts a buffer using AES-256-GCM. Throws if encryption is enabled but no key is available.
+    /// </summary>
+    byte[] Encrypt(byte[] plaintext);
+
+    /// <summary>
+    /// Decrypts a buffer produced by <see cref="Encrypt"/>. Throws if the payload was tampered with or the key is wrong.
+    /// </summary>
+    byte[] Decrypt(byte[] payload);
+
+    /// <summary>
+    /// Checks whether a buffer carries the encrypted payload header
+    /// </summary>
+    bool IsEncryptedPayload(byte[] payload);
+
+    /// <summary>
+    /// Encrypts a plaintext file to its sealed path and deletes the plaintext
+    /// </summary>
+    void SealFile(string plaintextPath);
+
+    /// <summary>
+    /// Decrypts a sealed file back to its plaintext path. Returns false if no sealed file exists.
+    /// </summary>
+    bool UnsealFile(string plaintextPath);
+}
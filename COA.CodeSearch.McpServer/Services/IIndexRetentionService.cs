@@ -0,0 +1,86 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Service for workspace index retention: size quotas, LRU eviction of stale indexes, and explicit purges
+/// </summary>
+public interface IIndexRetentionService
+{
+    /// <summary>
+    /// Records that a workspace index was used, keeping it at the front of the LRU order
+    /// </summary>
+    Task RecordAccessAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Lists all workspace indexes under the index root, most recently used first
+    /// </summary>
+    IReadOnlyList<RetainedIndex> GetWorkspaceIndexes();
+
+    /// <summary>
+    /// Checks a workspace index against the per-workspace size quota
+    /// </summary>
+    IndexQuotaStatus CheckQuota(string workspacePath);
+
+    /// <summary>
+    /// Deletes the index for a single workspace, closing it first if open
+    /// </summary>
+    Task<IndexPurgeResult> PurgeWorkspaceAsync(string workspacePath, bool dryRun = false, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Deletes every workspace index under the index root
+    /// </summary>
+    Task<IndexPurgeResult> PurgeAllAsync(bool dryRun = false, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Applies the configured retention policy (age, count, total size), evicting least recently used indexes first
+    /// </summary>
+    Task<IndexPurgeResult> EnforceRetentionAsync(bool dryRun = false, CancellationToken cancellationToken = default);
+}
+
+/// <summary>
+/// A workspace index found on disk
+/// </summary>
+public class RetainedIndex
+{
+    public string IndexPath { get; set; } = string.Empty;
+    public string WorkspaceHash { get; set; } = string.Empty;
+    public string? WorkspacePath { get; set; }
+    public long SizeBytes { get; set; }
+    public DateTime LastAccessed { get; set; }
+    public bool IsOpen { get; set; }
+    public bool IsPrimary { get; set; }
+}
+
+/// <summary>
+/// Result of comparing a workspace index against its size quota
+/// </summary>
+public class IndexQuotaStatus
+{
+    public long SizeBytes { get; set; }
+
+    /// <summary>
+    /// Configured quota in bytes; 0 means unlimited
+    /// </summary>
+    public long QuotaBytes { get; set; }
+
+    public bool IsExceeded => QuotaBytes > 0 && SizeBytes > QuotaBytes;
+}
+
+/// <summary>
+/// Outcome of a purge or retention pass
+/// </summary>
+public class IndexPurgeResult
+{
+    public bool DryRun { get; set; }
+    public List<PurgedIndex> Removed { get; set; } = new();
+    public List<string> Errors { get; set; } = new();
+    public long BytesFreed => Removed.Sum(r => r.Index.SizeBytes);
+}
+
+/// <summary>
+/// An index removed (or that would be removed in a dry run) and why
+/// </summary>
+public class PurgedIndex
+{
+    public RetainedIndex Index { get; set; } = new();
+    public string Reason { get; set; } = string.Empty;
+}
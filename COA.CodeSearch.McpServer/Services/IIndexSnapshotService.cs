@@ -0,0 +1,76 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Saves point-in-time snapshots of a workspace index (files, sizes and declarations) for later comparison.
+/// Snapshots are stored beside the index and encrypted when encryption at rest is enabled.
+/// </summary>
+public interface IIndexSnapshotService
+{
+    /// <summary>
+    /// Captures the current index without saving it
+    /// </summary>
+    /// <exception cref="InvalidOperationException">The workspace has no symbol database</exception>
+    Task<IndexSnapshot> CaptureAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Captures the current index and saves it under a name, pruning the oldest snapshots beyond the configured limit
+    /// </summary>
+    Task<IndexSnapshotInfo> SaveAsync(string workspacePath, string name, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Loads a saved snapshot, or null when there is none with that name
+    /// </summary>
+    Task<IndexSnapshot?> LoadAsync(string workspacePath, string name, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Lists saved snapshots, newest first
+    /// </summary>
+    IReadOnlyList<IndexSnapshotInfo> List(string workspacePath);
+}
+
+/// <summary>
+/// The indexed files of a workspace at a point in time
+/// </summary>
+public class IndexSnapshot
+{
+    public string Name { get; set; } = string.Empty;
+    public DateTime CreatedAt { get; set; }
+
+    /// <summary>
+    /// Files keyed by path relative to the workspace
+    /// </summary>
+    public Dictionary<string, SnapshotFile> Files { get; set; } = new(StringComparer.OrdinalIgnoreCase);
+}
+
+/// <summary>
+/// One indexed file in a snapshot
+/// </summary>
+public class SnapshotFile
+{
+    public string Language { get; set; } = string.Empty;
+    public long SizeBytes { get; set; }
+    public int Lines { get; set; }
+
+    /// <summary>
+    /// Hash of the content, to skip unchanged files when comparing
+    /// </summary>
+    public string ContentHash { get; set; } = string.Empty;
+
+    public List<Declaration> Declarations { get; set; } = new();
+}
+
+/// <summary>
+/// A saved snapshot without its contents
+/// </summary>
+public class IndexSnapshotInfo
+{
+    public string Name { get; set; } = string.Empty;
+    public DateTime CreatedAt { get; set; }
+
+    /// <summary>
+    /// Size of the stored (compressed) snapshot
+    /// </summary>
+    public long SizeBytes { get; set; }
+}
@@ -0,0 +1,21 @@
+using COA.CodeSearch.McpServer.Models;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Service for accepting older parameter shapes during a deprecation window
+/// </summary>
+public interface IParameterMigrationService
+{
+    /// <summary>
+    /// Copies values sent under deprecated parameter names to their replacements
+    /// </summary>
+    /// <typeparam name="T">The parameter type</typeparam>
+    /// <param name="toolName">Name of the tool being invoked</param>
+    /// <param name="parameters">The parameters to migrate in place</param>
+    /// <returns>One notice per deprecated parameter the client used</returns>
+    /// <exception cref="System.ComponentModel.DataAnnotations.ValidationException">
+    /// When deprecated parameters are rejected by configuration
+    /// </exception>
+    IReadOnlyList<DeprecationNotice> Migrate<T>(string toolName, T parameters) where T : class;
+}
@@ -50,6 +50,11 @@ public interface IPathResolutionService
     /// <returns>The full path to the index root directory</returns>
     string GetIndexRootPath();
     
+    /// <summary>
+    /// Gets the configured index storage location (CodeSearch:IndexStorage:Location)
+    /// </summary>
+    IndexStorageLocation StorageLocation { get; }
+    
     /// <summary>
     /// Computes a hash for the workspace path to use as directory name
     /// </summary>
@@ -137,4 +142,19 @@ public interface IPathResolutionService
     /// <param name="searchOption">Search option for subdirectories</param>
     /// <returns>Enumerable of directory paths, empty if enumeration fails</returns>
     IEnumerable<string> EnumerateDirectories(string path, string searchPattern = "*", SearchOption searchOption = SearchOption.TopDirectoryOnly);
-}
\ No newline at end of file
+}
+
+/// <summary>
+/// Where workspace indexes are stored
+/// </summary>
+public enum IndexStorageLocation
+{
+    /// <summary>Primary workspace's .coa/codesearch/indexes directory (default)</summary>
+    Workspace,
+    /// <summary>Each indexed repository's own .codesearch/indexes directory</summary>
+    Repository,
+    /// <summary>Shared per-user cache under ~/.coa/codesearch/indexes</summary>
+    Global,
+    /// <summary>Explicit directory from CodeSearch:IndexStorage:CustomPath</summary>
+    Custom
+}
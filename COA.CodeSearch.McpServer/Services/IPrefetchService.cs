@@ -0,0 +1,20 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Warms the response cache in the background with the queries an agent usually makes next, so they are
+/// answered from the cache when it does
+/// </summary>
+public interface IPrefetchService
+{
+    /// <summary>
+    /// Whether prefetching is enabled (CodeSearch:Prefetch:Enabled)
+    /// </summary>
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// Queues find_references for a symbol and get_symbols_overview for the file defining it, with the
+    /// workspacePath the caller passed (null for the primary workspace). Never blocks; requests already queued
+    /// are not queued twice, and the oldest are dropped when the queue is full.
+    /// </summary>
+    void PrefetchSymbol(string? workspacePath, string symbolName, string? definitionFile);
+}
@@ -0,0 +1,42 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Coordinates graceful shutdown: signals long-running work to checkpoint and tracks what is still in flight
+/// </summary>
+public interface IShutdownCoordinator
+{
+    /// <summary>
+    /// True once shutdown has begun; new long-running work should not start
+    /// </summary>
+    bool IsShuttingDown { get; }
+
+    /// <summary>
+    /// Cancelled when shutdown begins so in-flight indexing can stop at the next checkpoint
+    /// </summary>
+    CancellationToken ShutdownToken { get; }
+
+    /// <summary>
+    /// Maximum time shutdown may take before the process exits anyway
+    /// </summary>
+    TimeSpan Timeout { get; }
+
+    /// <summary>
+    /// Registers a long-running operation; dispose the handle when it finishes
+    /// </summary>
+    IDisposable TrackOperation(string name);
+
+    /// <summary>
+    /// Names of operations that are currently running
+    /// </summary>
+    IReadOnlyList<string> GetActiveOperations();
+
+    /// <summary>
+    /// Signals shutdown. Returns false if it had already begun.
+    /// </summary>
+    bool BeginShutdown();
+
+    /// <summary>
+    /// Waits until all tracked operations have finished. Returns false if the token fired first.
+    /// </summary>
+    Task<bool> WaitForOperationsAsync(CancellationToken cancellationToken);
+}
@@ -0,0 +1,19 @@
+using COA.CodeSearch.McpServer.Models;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Service for posting server events (index completion, new findings, detected secrets) to configured webhooks
+/// </summary>
+public interface IWebhookService
+{
+    /// <summary>
+    /// Whether any endpoint subscribes to an event, so callers can skip work nobody will receive
+    /// </summary>
+    bool IsSubscribed(string eventName);
+
+    /// <summary>
+    /// Queues an event for delivery. Never blocks on the network and never throws for delivery failures.
+    /// </summary>
+    void Publish(WebhookEvent webhookEvent);
+}
@@ -0,0 +1,268 @@
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// A file that could be pruned, with what pruning it is expected to free
+/// </summary>
+public class PruneCandidate
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string RelativePath { get; set; } = string.Empty;
+    public long SizeBytes { get; set; }
+    public long EstimatedIndexBytes { get; set; }
+    public bool IsCold { get; set; }
+    public bool IsGenerated { get; set; }
+}
+
+/// <summary>
+/// Enforces the per-workspace size budget by deleting file documents from the Lucene index: cold-tier files first,
+/// then generated code, then the largest files, in the configured order. Pruned files are kept in pruned-files.json
+/// beside the index and skipped by indexing until a force rebuild.
+/// </summary>
+public class IndexBudgetService : IIndexBudgetService
+{
+    private const string PrunedFileName = "pruned-files.json";
+    private static readonly string[] DefaultStrategies = { "cold", "generated", "largest" };
+    private static readonly string[] DefaultGeneratedPatterns =
+    {
+        @"\.g\.cs$", @"\.g\.i\.cs$", @"\.designer\.cs$", @"\.generated\.\w+$", @"\.min\.(js|css)$",
+        @"\.pb\.go$", @"_pb2(_grpc)?\.py$", @"\.g\.dart$", @"\.freezed\.dart$", @"(^|/)generated/"
+    };
+
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly IIndexRetentionService _retentionService;
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILogger<IndexBudgetService> _logger;
+    private readonly bool _enabled;
+    private readonly IReadOnlyList<string> _strategies;
+    private readonly List<Regex> _generatedPatterns;
+    private readonly object _sync = new();
+    private readonly Dictionary<string, Dictionary<string, PrunedFile>> _pruned = new(StringComparer.OrdinalIgnoreCase);
+
+    public IndexBudgetService(
+        ILuceneIndexService luceneIndexService,
+        IIndexRetentionService retentionService,
+        IPathResolutionService pathResolution,
+        IConfiguration configuration,
+        ILogger<IndexBudgetService> logger)
+    {
+        _luceneIndexService = luceneIndexService ?? throw new ArgumentNullException(nameof(luceneIndexService));
+        _retentionService = retentionService ?? throw new ArgumentNullException(nameof(retentionService));
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        _enabled = configuration.GetValue("CodeSearch:IndexStorage:Pruning:Enabled", false)
+                   && configuration.GetValue("CodeSearch:IndexStorage:MaxWorkspaceSizeMB", 0L) > 0;
+        _strategies = (configuration.GetSection("CodeSearch:IndexStorage:Pruning:Strategies").Get<string[]>() ?? DefaultStrategies)
+            .Select(s => s.Trim().ToLowerInvariant())
+            .Where(s => DefaultStrategies.Contains(s))
+            .Distinct()
+            .ToList();
+        _generatedPatterns = (configuration.GetSection("CodeSearch:IndexStorage:Pruning:GeneratedPatterns").Get<string[]>() ?? DefaultGeneratedPatterns)
+            .Select(p => new Regex(p, RegexOptions.IgnoreCase | RegexOptions.CultureInvariant))
+            .ToList();
+    }
+
+    public bool IsEnabled => _enabled;
+
+    public bool IsPruned(string workspacePath, string filePath)
+    {
+        if (!_enabled)
+            return false;
+
+        lock (_sync)
+        {
+            var pruned = GetWorkspacePruned(workspacePath);
+            return pruned.Count > 0 && pruned.ContainsKey(ToRelativePath(workspacePath, filePath));
+        }
+    }
+
+    public async Task<IndexPruneReport> EnforceBudgetAsync(string workspacePath, bool dryRun = false, CancellationToken cancellationToken = default)
+    {
+        var quota = _retentionService.CheckQuota(workspacePath);
+        var report = new IndexPruneReport
+        {
+            DryRun = dryRun,
+            BudgetBytes = quota.QuotaBytes,
+            SizeBeforeBytes = quota.SizeBytes,
+            SizeAfterBytes = quota.SizeBytes,
+            WithinBudget = !quota.IsExceeded
+        };
+        if (!_enabled || !quota.IsExceeded)
+            return report;
+
+        var files = await _luceneIndexService.GetIndexedFilesAsync(workspacePath, cancellationToken);
+        var contentBytes = Math.Max(1, files.Sum(f => f.SizeBytes));
+
+        // Index bytes per byte of source, so a file's share of the index can be estimated from its size
+        var ratio = (double)quota.SizeBytes / contentBytes;
+        var candidates = files.Select(f =>
+        {
+            var relativePath = ToRelativePath(workspacePath, f.FilePath);
+            return new PruneCandidate
+            {
+                FilePath = f.FilePath,
+                RelativePath = relativePath,
+                SizeBytes = f.SizeBytes,
+                EstimatedIndexBytes = (long)(f.SizeBytes * ratio),
+                IsCold = f.IsCold,
+                IsGenerated = IsGenerated(relativePath)
+            };
+        }).ToList();
+
+        var selected = SelectForPruning(candidates, quota.SizeBytes - quota.QuotaBytes, _strategies);
+        var now = DateTime.UtcNow;
+        report.Pruned = selected.Select(s => new PrunedFile
+        {
+            Path = s.Candidate.RelativePath,
+            Strategy = s.Strategy,
+            SizeBytes = s.Candidate.SizeBytes,
+            PrunedAt = now
+        }).ToList();
+
+        if (dryRun)
+        {
+            report.SizeAfterBytes = quota.SizeBytes - selected.Sum(s => s.Candidate.EstimatedIndexBytes);
+            report.WithinBudget = report.SizeAfterBytes <= quota.QuotaBytes;
+            return report;
+        }
+
+        // Record first, so files re-indexed by the watcher meanwhile are already skipped
+        lock (_sync)
+        {
+            var pruned = GetWorkspacePruned(workspacePath);
+            foreach (var file in report.Pruned)
+            {
+                pruned[file.Path] = file;
+            }
+            Save(workspacePath, pruned);
+        }
+
+        foreach (var (candidate, _) in selected)
+        {
+            await _luceneIndexService.DeleteDocumentAsync(workspacePath, candidate.FilePath, cancellationToken);
+        }
+        await _luceneIndexService.CommitAsync(workspacePath, cancellationToken);
+
+        // Deleted documents only give space back once their segments are merged
+        await _luceneIndexService.OptimizeIndexAsync(workspacePath, cancellationToken: cancellationToken);
+
+        var after = _retentionService.CheckQuota(workspacePath);
+        report.SizeAfterBytes = after.SizeBytes;
+        report.WithinBudget = !after.IsExceeded;
+
+        _logger.LogWarning("Index for {Workspace} was {Before} bytes, over its {Budget} byte budget: pruned {Count} file(s) ({Strategies}), now {After} bytes",
+            workspacePath, report.SizeBeforeBytes, report.BudgetBytes, report.Pruned.Count,
+            string.Join(", ", report.Pruned.GroupBy(p => p.Strategy).Select(g => $"{g.Count()} {g.Key}")), report.SizeAfterBytes);
+        return report;
+    }
+
+    public IReadOnlyList<PrunedFile> GetPrunedFiles(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspacePruned(workspacePath).Values
+                .OrderByDescending(p => p.PrunedAt)
+                .ThenByDescending(p => p.SizeBytes)
+                .ToList();
+        }
+    }
+
+    public void ResetPruned(string workspacePath)
+    {
+        lock (_sync)
+        {
+            var pruned = GetWorkspacePruned(workspacePath);
+            if (pruned.Count == 0)
+                return;
+
+            pruned.Clear();
+            Save(workspacePath, pruned);
+        }
+    }
+
+    /// <summary>
+    /// Picks files to prune until their estimated index bytes cover bytesToFree. Strategies run in order; within
+    /// one, the largest files go first. A file is only selected once, by the first strategy that matches it.
+    /// </summary>
+    public static List<(PruneCandidate Candidate, string Strategy)> SelectForPruning(
+        IReadOnlyList<PruneCandidate> candidates, long bytesToFree, IReadOnlyList<string> strategies)
+    {
+        var selected = new List<(PruneCandidate, string)>();
+        var remaining = candidates.OrderByDescending(c => c.SizeBytes).ToList();
+        long freed = 0;
+
+        foreach (var strategy in strategies)
+        {
+            Func<PruneCandidate, bool> matches = strategy switch
+            {
+                "cold" => c => c.IsCold,
+                "generated" => c => c.IsGenerated,
+                _ => _ => true
+            };
+
+            foreach (var candidate in remaining.Where(matches).ToList())
+            {
+                if (freed >= bytesToFree)
+                    return selected;
+
+                selected.Add((candidate, strategy));
+                remaining.Remove(candidate);
+                freed += candidate.EstimatedIndexBytes;
+            }
+        }
+
+        return selected;
+    }
+
+    private bool IsGenerated(string relativePath) => _generatedPatterns.Any(p => p.IsMatch(relativePath));
+
+    private static string ToRelativePath(string workspacePath, string filePath) =>
+        Path.GetRelativePath(workspacePath, Path.GetFullPath(filePath, workspacePath)).Replace('\\', '/');
+
+    private Dictionary<string, PrunedFile> GetWorkspacePruned(string workspacePath)
+    {
+        if (_pruned.TryGetValue(workspacePath, out var pruned))
+            return pruned;
+
+        pruned = new Dictionary<string, PrunedFile>(StringComparer.OrdinalIgnoreCase);
+        var path = GetPrunedPath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+            {
+                foreach (var file in JsonSerializer.Deserialize<List<PrunedFile>>(File.ReadAllText(path)) ?? new())
+                    pruned[file.Path] = file;
+            }
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read pruned files {Path} - nothing is treated as pruned", path);
+        }
+
+        _pruned[workspacePath] = pruned;
+        return pruned;
+    }
+
+    private void Save(string workspacePath, Dictionary<string, PrunedFile> pruned)
+    {
+        var path = GetPrunedPath(workspacePath);
+        try
+        {
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            File.WriteAllText(path, JsonSerializer.Serialize(pruned.Values.OrderBy(p => p.Path, StringComparer.OrdinalIgnoreCase)));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not save pruned files {Path}", path);
+        }
+    }
+
+    private string GetPrunedPath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), PrunedFileName);
+}
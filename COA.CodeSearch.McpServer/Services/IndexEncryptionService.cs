@@ -0,0 +1,334 @@
+using System.Diagnostics;
+using System.Runtime.InteropServices;
+using System.Security.Cryptography;
+using System.Text;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// AES-256-GCM encryption for index data at rest.
+/// Payload layout: "CSE1" magic | 12-byte nonce | 16-byte tag | ciphertext.
+/// Fails closed: when enabled and no key can be resolved, every operation throws rather than writing plaintext.
+/// </summary>
+public class IndexEncryptionService : IIndexEncryptionService
+{
+    private static readonly byte[] Magic = Encoding.ASCII.GetBytes("CSE1");
+    private static readonly byte[] PassphraseSalt = Encoding.ASCII.GetBytes("coa-codesearch-index-key-v1");
+    private const int NonceSize = 12;
+    private const int TagSize = 16;
+    private const int KeySize = 32;
+    private const int Pbkdf2Iterations = 100_000;
+    private const int KeychainTimeoutMs = 5000;
+    private const int CredentialTypeGeneric = 1;
+
+    private readonly ILogger<IndexEncryptionService> _logger;
+    private readonly string _keySource;
+    private readonly string _keyEnvironmentVariable;
+    private readonly string _keychainService;
+    private readonly string _keychainAccount;
+    private readonly object _keyLock = new();
+    private byte[]? _key;
+    private bool _keyResolved;
+
+    public IndexEncryptionService(IConfiguration configuration, ILogger<IndexEncryptionService> logger)
+    {
+        if (configuration == null) throw new ArgumentNullException(nameof(configuration));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        IsEnabled = configuration.GetValue("CodeSearch:Encryption:Enabled", false);
+        _keySource = configuration.GetValue("CodeSearch:Encryption:KeySource", "Environment") ?? "Environment";
+        _keyEnvironmentVariable = configuration.GetValue("CodeSearch:Encryption:KeyEnvironmentVariable", "CODESEARCH_INDEX_KEY") ?? "CODESEARCH_INDEX_KEY";
+        _keychainService = configuration.GetValue("CodeSearch:Encryption:KeychainService", "coa-codesearch") ?? "coa-codesearch";
+        _keychainAccount = configuration.GetValue("CodeSearch:Encryption:KeychainAccount", "index-key") ?? "index-key";
+
+        if (IsEnabled)
+        {
+            _logger.LogInformation("Index encryption at rest enabled (key source: {KeySource})", _keySource);
+        }
+    }
+
+    public bool IsEnabled { get; }
+
+    public string SealedExtension => ".enc";
+
+    public bool HasKey() => ResolveKey() != null;
+
+    public byte[] Encrypt(byte[] plaintext)
+    {
+        if (plaintext == null) throw new ArgumentNullException(nameof(plaintext));
+        var key = GetRequiredKey();
+
+        var nonce = RandomNumberGenerator.GetBytes(NonceSize);
+        var tag = new byte[TagSize];
+        var ciphertext = new byte[plaintext.Length];
+
+        using (var aes = new AesGcm(key, TagSize))
+        {
+            aes.Encrypt(nonce, plaintext, ciphertext, tag, Magic);
+        }
+
+        var payload = new byte[Magic.Length + NonceSize + TagSize + ciphertext.Length];
+        Buffer.BlockCopy(Magic, 0, payload, 0, Magic.Length);
+        Buffer.BlockCopy(nonce, 0, payload, Magic.Length, NonceSize);
+        Buffer.BlockCopy(tag, 0, payload, Magic.Length + NonceSize, TagSize);
+        Buffer.BlockCopy(ciphertext, 0, payload, Magic.Length + NonceSize + TagSize, ciphertext.Length);
+        return payload;
+    }
+
+    public byte[] Decrypt(byte[] payload)
+    {
+        if (payload == null) throw new ArgumentNullException(nameof(payload));
+        if (!IsEncryptedPayload(payload))
+        {
+            throw new InvalidDataException("Payload is not an encrypted CodeSearch index file");
+        }
+
+        var key = GetRequiredKey();
+        var headerSize = Magic.Length + NonceSize + TagSize;
+        var nonce = new byte[NonceSize];
+        var tag = new byte[TagSize];
+        var ciphertext = new byte[payload.Length - headerSize];
+        Buffer.BlockCopy(payload, Magic.Length, nonce, 0, NonceSize);
+        Buffer.BlockCopy(payload, Magic.Length + NonceSize, tag, 0, TagSize);
+        Buffer.BlockCopy(payload, headerSize, ciphertext, 0, ciphertext.Length);
+
+        var plaintext = new byte[ciphertext.Length];
+        using (var aes = new AesGcm(key, TagSize))
+        {
+            // Throws AuthenticationTagMismatchException (a CryptographicException) on wrong key or tampering
+            aes.Decrypt(nonce, ciphertext, tag, plaintext, Magic);
+        }
+        return plaintext;
+    }
+
+    public bool IsEncryptedPayload(byte[] payload)
+    {
+        if (payload == null || payload.Length < Magic.Length + NonceSize + TagSize)
+            return false;
+
+        for (int i = 0; i < Magic.Length; i++)
+        {
+            if (payload[i] != Magic[i]) return false;
+        }
+        return true;
+    }
+
+    public void SealFile(string plaintextPath)
+    {
+        if (!File.Exists(plaintextPath))
+            return;
+
+        var sealedPath = plaintextPath + SealedExtension;
+        var tempPath = sealedPath + ".tmp";
+
+        var payload = Encrypt(File.ReadAllBytes(plaintextPath));
+        File.WriteAllBytes(tempPath, payload);
+        File.Move(tempPath, sealedPath, overwrite: true);
+        File.Delete(plaintextPath);
+
+        _logger.LogDebug("Sealed {Path} ({Bytes} bytes)", plaintextPath, payload.Length);
+    }
+
+    public bool UnsealFile(string plaintextPath)
+    {
+        var sealedPath = plaintextPath + SealedExtension;
+        if (!File.Exists(sealedPath))
+            return false;
+
+        var plaintext = Decrypt(File.ReadAllBytes(sealedPath));
+        var tempPath = plaintextPath + ".tmp";
+        File.WriteAllBytes(tempPath, plaintext);
+        File.Move(tempPath, plaintextPath, overwrite: true);
+
+        _logger.LogDebug("Unsealed {Path} ({Bytes} bytes)", plaintextPath, plaintext.Length);
+        return true;
+    }
+
+    private byte[] GetRequiredKey()
+    {
+        var key = ResolveKey();
+        if (key == null)
+        {
+            throw new InvalidOperationException(
+                $"Index encryption is enabled but no key was found. Set the {_keyEnvironmentVariable} environment variable " +
+                $"or store a key in the OS keychain under service '{_keychainService}', account '{_keychainAccount}'.");
+        }
+        return key;
+    }
+
+    private byte[]? ResolveKey()
+    {
+        lock (_keyLock)
+        {
+            if (_keyResolved)
+                return _key;
+
+            string? secret = null;
+            if (_keySource.Equals("Keychain", StringComparison.OrdinalIgnoreCase))
+            {
+                secret = ReadFromKeychain();
+            }
+            else if (_keySource.Equals("Auto", StringComparison.OrdinalIgnoreCase))
+            {
+                secret = ReadFromKeychain() ?? Environment.GetEnvironmentVariable(_keyEnvironmentVariable);
+            }
+            else
+            {
+                secret = Environment.GetEnvironmentVariable(_keyEnvironmentVariable);
+            }
+
+            _key = string.IsNullOrWhiteSpace(secret) ? null : DeriveKey(secret.Trim());
+            _keyResolved = true;
+
+            if (_key == null)
+            {
+                _logger.LogWarning("No index encryption key found (key source: {KeySource})", _keySource);
+            }
+            return _key;
+        }
+    }
+
+    /// <summary>
+    /// Accepts a base64-encoded 256-bit key directly; anything else is treated as a passphrase and stretched with PBKDF2.
+    /// </summary>
+    internal static byte[] DeriveKey(string secret)
+    {
+        try
+        {
+            var raw = Convert.FromBase64String(secret);
+            if (raw.Length == KeySize)
+                return raw;
+        }
+        catch (FormatException)
+        {
+            // Not base64 - fall through to passphrase derivation
+        }
+
+        return Rfc2898DeriveBytes.Pbkdf2(secret, PassphraseSalt, Pbkdf2Iterations, HashAlgorithmName.SHA256, KeySize);
+    }
+
+    private string? ReadFromKeychain()
+    {
+        if (RuntimeInformation.IsOSPlatform(OSPlatform.Windows))
+        {
+            return ReadFromCredentialManager();
+        }
+
+        ProcessStartInfo startInfo;
+        if (RuntimeInformation.IsOSPlatform(OSPlatform.OSX))
+        {
+            startInfo = new ProcessStartInfo("security");
+            startInfo.ArgumentList.Add("find-generic-password");
+            startInfo.ArgumentList.Add("-s");
+            startInfo.ArgumentList.Add(_keychainService);
+            startInfo.ArgumentList.Add("-a");
+            startInfo.ArgumentList.Add(_keychainAccount);
+            startInfo.ArgumentList.Add("-w");
+        }
+        else if (RuntimeInformation.IsOSPlatform(OSPlatform.Linux))
+        {
+            startInfo = new ProcessStartInfo("secret-tool");
+            startInfo.ArgumentList.Add("lookup");
+            startInfo.ArgumentList.Add("service");
+            startInfo.ArgumentList.Add(_keychainService);
+            startInfo.ArgumentList.Add("account");
+            startInfo.ArgumentList.Add(_keychainAccount);
+        }
+        else
+        {
+            _logger.LogError("OS keychain lookup is not supported on this platform; use the {EnvVar} environment variable", _keyEnvironmentVariable);
+            return null;
+        }
+
+        startInfo.RedirectStandardOutput = true;
+        startInfo.RedirectStandardError = true;
+        startInfo.UseShellExecute = false;
+        startInfo.CreateNoWindow = true;
+
+        try
+        {
+            using var process = Process.Start(startInfo);
+            if (process == null)
+                return null;
+
+            // Read both streams while waiting: a tool blocked on a prompt never closes stdout, so a blocking read
+            // would never reach the timeout
+            var output = process.StandardOutput.ReadToEndAsync();
+            var error = process.StandardError.ReadToEndAsync();
+            if (!Task.WaitAll(new Task[] { output, error, process.WaitForExitAsync() }, KeychainTimeoutMs))
+            {
+                try { process.Kill(entireProcessTree: true); } catch { /* best effort */ }
+                _logger.LogWarning("Keychain lookup timed out after {Timeout}ms", KeychainTimeoutMs);
+                return null;
+            }
+
+            return process.ExitCode == 0 ? output.Result.Trim() : null;
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Failed to read index encryption key from OS keychain");
+            return null;
+        }
+    }
+
+    /// <summary>
+    /// Reads the key from a generic Windows Credential Manager entry whose target is the keychain service
+    /// and whose user name is the keychain account, as stored by <c>cmdkey /generic</c>.
+    /// </summary>
+    private string? ReadFromCredentialManager()
+    {
+        if (!CredRead(_keychainService, CredentialTypeGeneric, 0, out var handle))
+        {
+            _logger.LogWarning("No Windows Credential Manager entry '{Service}' (error {Error})", _keychainService, Marshal.GetLastWin32Error());
+            return null;
+        }
+
+        try
+        {
+            var credential = Marshal.PtrToStructure<NativeCredential>(handle);
+            var userName = Marshal.PtrToStringUni(credential.UserName);
+            if (!string.Equals(userName, _keychainAccount, StringComparison.OrdinalIgnoreCase))
+            {
+                _logger.LogWarning("Windows Credential Manager entry '{Service}' belongs to '{User}', not '{Account}'",
+                    _keychainService, userName, _keychainAccount);
+                return null;
+            }
+            if (credential.CredentialBlob == IntPtr.Zero || credential.CredentialBlobSize == 0)
+                return null;
+
+            var blob = new byte[credential.CredentialBlobSize];
+            Marshal.Copy(credential.CredentialBlob, blob, 0, blob.Length);
+            return Encoding.Unicode.GetString(blob).TrimEnd('\0');
+        }
+        finally
+        {
+            CredFree(handle);
+        }
+    }
+
+    [StructLayout(LayoutKind.Sequential, CharSet = CharSet.Unicode)]
+    private struct NativeCredential
+    {
+        public int Flags;
+        public int Type;
+        public IntPtr TargetName;
+        public IntPtr Comment;
+        public int LastWrittenLow;
+        public int LastWrittenHigh;
+        public int CredentialBlobSize;
+        public IntPtr CredentialBlob;
+        public int Persist;
+        public int AttributeCount;
+        public IntPtr Attributes;
+        public IntPtr TargetAlias;
+        public IntPtr UserName;
+    }
+
+    [DllImport("advapi32.dll", EntryPoint = "CredReadW", CharSet = CharSet.Unicode, SetLastError = true)]
+    private static extern bool CredRead(string target, int type, int flags, out IntPtr credential);
+
+    [DllImport("advapi32.dll")]
+    private static extern void CredFree(IntPtr credential);
+}
@@ -0,0 +1,339 @@
+using System.Text.Json;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Data.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Retention policy settings (CodeSearch:IndexStorage). Zero disables a limit.
+/// </summary>
+public class IndexRetentionPolicy
+{
+    public int StaleAfterDays { get; set; } = 30;
+    public int MaxWorkspaceIndexes { get; set; }
+    public long MaxTotalSizeBytes { get; set; }
+    public long MaxWorkspaceSizeBytes { get; set; }
+}
+
+/// <summary>
+/// Manages workspace index retention. Runs as a background service that periodically evicts
+/// stale indexes (least recently used first) and backs the purge_index tool.
+/// The primary workspace and indexes currently open in this process are never evicted automatically.
+/// </summary>
+public class IndexRetentionService : BackgroundService, IIndexRetentionService
+{
+    private static readonly JsonSerializerOptions JsonOptions = new()
+    {
+        WriteIndented = true,
+        PropertyNamingPolicy = JsonNamingPolicy.CamelCase,
+        PropertyNameCaseInsensitive = true
+    };
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly IServiceProvider _serviceProvider;
+    private readonly ILogger<IndexRetentionService> _logger;
+    private readonly IndexRetentionPolicy _policy;
+    private readonly bool _autoEvict;
+    private readonly TimeSpan _checkInterval;
+    private readonly SemaphoreSlim _purgeLock = new(1, 1);
+
+    public IndexRetentionService(
+        IPathResolutionService pathResolution,
+        ILuceneIndexService luceneIndexService,
+        IServiceProvider serviceProvider,
+        IConfiguration configuration,
+        ILogger<IndexRetentionService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _luceneIndexService = luceneIndexService ?? throw new ArgumentNullException(nameof(luceneIndexService));
+        _serviceProvider = serviceProvider ?? throw new ArgumentNullException(nameof(serviceProvider));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        _policy = new IndexRetentionPolicy
+        {
+            StaleAfterDays = configuration.GetValue("CodeSearch:IndexStorage:StaleAfterDays", 30),
+            MaxWorkspaceIndexes = configuration.GetValue("CodeSearch:IndexStorage:MaxWorkspaceIndexes", 0),
+            MaxTotalSizeBytes = configuration.GetValue("CodeSearch:IndexStorage:MaxTotalSizeMB", 0L) * 1024 * 1024,
+            MaxWorkspaceSizeBytes = configuration.GetValue("CodeSearch:IndexStorage:MaxWorkspaceSizeMB", 0L) * 1024 * 1024
+        };
+        _autoEvict = configuration.GetValue("CodeSearch:IndexStorage:AutoEvict", true);
+        _checkInterval = TimeSpan.FromHours(Math.Max(1, configuration.GetValue("CodeSearch:IndexStorage:CheckIntervalHours", 24)));
+    }
+
+    protected override async Task ExecuteAsync(CancellationToken stoppingToken)
+    {
+        if (!_autoEvict)
+        {
+            _logger.LogInformation("Automatic index eviction is disabled in configuration");
+            return;
+        }
+
+        try
+        {
+            // Let startup indexing claim its workspace before the first pass
+            await Task.Delay(TimeSpan.FromMinutes(1), stoppingToken);
+
+            while (!stoppingToken.IsCancellationRequested)
+            {
+                var result = await EnforceRetentionAsync(dryRun: false, stoppingToken);
+                if (result.Removed.Count > 0)
+                {
+                    _logger.LogInformation("Retention pass evicted {Count} index(es), freed {Bytes} bytes",
+                        result.Removed.Count, result.BytesFreed);
+                }
+
+                await Task.Delay(_checkInterval, stoppingToken);
+            }
+        }
+        catch (OperationCanceledException)
+        {
+            // Shutting down
+        }
+        catch (Exception ex)
+        {
+            _logger.LogError(ex, "Index retention service failed");
+        }
+    }
+
+    public async Task RecordAccessAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        try
+        {
+            var indexPath = _pathResolution.GetIndexPath(workspacePath);
+            if (!Directory.Exists(indexPath))
+                return;
+
+            var metadataPath = Path.Combine(indexPath, PathConstants.WorkspaceMetadataFileName);
+            var info = ReadMetadata(metadataPath) ?? new WorkspaceIndexInfo
+            {
+                OriginalPath = Path.GetFullPath(workspacePath),
+                HashPath = Path.GetFileName(indexPath),
+                CreatedAt = DateTime.UtcNow
+            };
+            info.LastAccessed = DateTime.UtcNow;
+
+            await File.WriteAllTextAsync(metadataPath, JsonSerializer.Serialize(info, JsonOptions), cancellationToken);
+        }
+        catch (Exception ex)
+        {
+            // Access tracking is advisory - never fail the caller
+            _logger.LogDebug(ex, "Failed to record index access for {Workspace}", workspacePath);
+        }
+    }
+
+    public IReadOnlyList<RetainedIndex> GetWorkspaceIndexes()
+    {
+        var indexRoot = _pathResolution.GetIndexRootPath();
+        if (!Directory.Exists(indexRoot))
+            return Array.Empty<RetainedIndex>();
+
+        var primaryHash = _pathResolution.ComputeWorkspaceHash(_pathResolution.GetPrimaryWorkspacePath());
+        var indexes = new List<RetainedIndex>();
+
+        foreach (var indexPath in Directory.EnumerateDirectories(indexRoot))
+        {
+            var workspaceHash = ExtractWorkspaceHash(indexPath);
+            if (workspaceHash == null)
+                continue;
+
+            var metadata = ReadMetadata(Path.Combine(indexPath, PathConstants.WorkspaceMetadataFileName));
+            var (sizeBytes, lastWrite) = MeasureDirectory(indexPath);
+
+            indexes.Add(new RetainedIndex
+            {
+                IndexPath = indexPath,
+                WorkspaceHash = workspaceHash,
+                WorkspacePath = metadata?.OriginalPath is { Length: > 0 } originalPath ? originalPath : null,
+                SizeBytes = sizeBytes,
+                LastAccessed = metadata != null && metadata.LastAccessed > lastWrite ? metadata.LastAccessed : lastWrite,
+                IsOpen = _luceneIndexService.IsIndexOpen(workspaceHash),
+                IsPrimary = workspaceHash == primaryHash
+            });
+        }
+
+        return indexes.OrderByDescending(i => i.LastAccessed).ToList();
+    }
+
+    public IndexQuotaStatus CheckQuota(string workspacePath)
+    {
+        var indexPath = _pathResolution.GetIndexPath(workspacePath);
+        return new IndexQuotaStatus
+        {
+            SizeBytes = Directory.Exists(indexPath) ? MeasureDirectory(indexPath).SizeBytes : 0,
+            QuotaBytes = _policy.MaxWorkspaceSizeBytes
+        };
+    }
+
+    public Task<IndexPurgeResult> PurgeWorkspaceAsync(string workspacePath, bool dryRun = false, CancellationToken cancellationToken = default)
+    {
+        var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
+        var targets = GetWorkspaceIndexes()
+            .Where(i => i.WorkspaceHash == workspaceHash)
+            .Select(i => new PurgedIndex { Index = i, Reason = "Purged on request" })
+            .ToList();
+
+        return DeleteIndexesAsync(targets, dryRun, cancellationToken);
+    }
+
+    public Task<IndexPurgeResult> PurgeAllAsync(bool dryRun = false, CancellationToken cancellationToken = default)
+    {
+        var targets = GetWorkspaceIndexes()
+            .Select(i => new PurgedIndex { Index = i, Reason = "Purged on request" })
+            .ToList();
+
+        return DeleteIndexesAsync(targets, dryRun, cancellationToken);
+    }
+
+    public Task<IndexPurgeResult> EnforceRetentionAsync(bool dryRun = false, CancellationToken cancellationToken = default)
+    {
+        var targets = SelectForEviction(GetWorkspaceIndexes(), _policy, DateTime.UtcNow);
+        return DeleteIndexesAsync(targets, dryRun, cancellationToken);
+    }
+
+    /// <summary>
+    /// Chooses indexes to evict under the policy: stale ones first, then least recently used until
+    /// the count and total size limits are met. Primary and open indexes are never selected.
+    /// </summary>
+    public static List<PurgedIndex> SelectForEviction(IReadOnlyList<RetainedIndex> indexes, IndexRetentionPolicy policy, DateTime utcNow)
+    {
+        var selected = new List<PurgedIndex>();
+        var remaining = indexes.OrderBy(i => i.LastAccessed).ToList();
+
+        bool CanEvict(RetainedIndex index) => !index.IsPrimary && !index.IsOpen;
+
+        void Evict(RetainedIndex index, string reason)
+        {
+            selected.Add(new PurgedIndex { Index = index, Reason = reason });
+            remaining.Remove(index);
+        }
+
+        if (policy.StaleAfterDays > 0)
+        {
+            var cutoff = utcNow.AddDays(-policy.StaleAfterDays);
+            foreach (var index in remaining.Where(i => i.LastAccessed < cutoff && CanEvict(i)).ToList())
+            {
+                Evict(index, $"Not used in over {policy.StaleAfterDays} days");
+            }
+        }
+
+        if (policy.MaxWorkspaceIndexes > 0)
+        {
+            foreach (var index in remaining.Where(CanEvict).ToList())
+            {
+                if (remaining.Count <= policy.MaxWorkspaceIndexes)
+                    break;
+                Evict(index, $"Exceeds limit of {policy.MaxWorkspaceIndexes} workspace indexes");
+            }
+        }
+
+        if (policy.MaxTotalSizeBytes > 0)
+        {
+            foreach (var index in remaining.Where(CanEvict).ToList())
+            {
+                if (remaining.Sum(i => i.SizeBytes) <= policy.MaxTotalSizeBytes)
+                    break;
+                Evict(index, $"Total index size exceeds {policy.MaxTotalSizeBytes / (1024 * 1024)} MB");
+            }
+        }
+
+        return selected;
+    }
+
+    private async Task<IndexPurgeResult> DeleteIndexesAsync(List<PurgedIndex> targets, bool dryRun, CancellationToken cancellationToken)
+    {
+        var result = new IndexPurgeResult { DryRun = dryRun };
+        if (dryRun)
+        {
+            result.Removed.AddRange(targets);
+            return result;
+        }
+
+        await _purgeLock.WaitAsync(cancellationToken);
+        try
+        {
+            var fileWatcher = _serviceProvider.GetService<FileWatcherService>();
+
+            foreach (var target in targets)
+            {
+                try
+                {
+                    if (target.Index.WorkspacePath != null)
+                    {
+                        fileWatcher?.StopWatching(target.Index.WorkspacePath);
+                    }
+
+                    await _luceneIndexService.CloseIndexAsync(target.Index.WorkspaceHash, cancellationToken);
+
+                    // Release pooled SQLite handles so the symbol database can be deleted (required on Windows)
+                    SqliteConnection.ClearAllPools();
+
+                    Directory.Delete(target.Index.IndexPath, recursive: true);
+                    result.Removed.Add(target);
+                    _logger.LogInformation("Removed workspace index {Path}: {Reason}", target.Index.IndexPath, target.Reason);
+                }
+                catch (Exception ex)
+                {
+                    _logger.LogWarning(ex, "Failed to remove workspace index {Path}", target.Index.IndexPath);
+                    result.Errors.Add($"{target.Index.IndexPath}: {ex.Message}");
+                }
+            }
+        }
+        finally
+        {
+            _purgeLock.Release();
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Index directories are named "{workspace}_{hash}"; anything else (registry files, backups) is skipped
+    /// </summary>
+    private static string? ExtractWorkspaceHash(string indexPath)
+    {
+        var name = Path.GetFileName(indexPath);
+        var separator = name.LastIndexOf('_');
+        if (separator < 0 || name.Length - separator - 1 != PathConstants.WorkspaceHashLength)
+            return null;
+
+        return name.Substring(separator + 1);
+    }
+
+    private static (long SizeBytes, DateTime LastWrite) MeasureDirectory(string path)
+    {
+        long size = 0;
+        var lastWrite = Directory.GetLastWriteTimeUtc(path);
+
+        foreach (var file in new DirectoryInfo(path).EnumerateFiles("*", SearchOption.AllDirectories))
+        {
+            size += file.Length;
+            if (file.LastWriteTimeUtc > lastWrite)
+                lastWrite = file.LastWriteTimeUtc;
+        }
+
+        return (size, lastWrite);
+    }
+
+    private WorkspaceIndexInfo? ReadMetadata(string metadataPath)
+    {
+        if (!File.Exists(metadataPath))
+            return null;
+
+        try
+        {
+            var json = File.ReadAllText(metadataPath);
+            return JsonSerializer.Deserialize<WorkspaceIndexInfo>(json, JsonOptions);
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Ignoring unreadable index metadata {Path}", metadataPath);
+            return null;
+        }
+    }
+}
@@ -0,0 +1,182 @@
+using System.IO.Compression;
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Stores index snapshots as gzipped JSON in a snapshots directory beside each workspace index.
+/// Files come from the SQLite symbol database; declarations are scanned with <see cref="DeclarationScanner"/>
+/// so snapshots can be compared with the same heuristics as git revisions.
+/// </summary>
+public class IndexSnapshotService : IIndexSnapshotService
+{
+    private const string SnapshotDirectory = "snapshots";
+    private const string SnapshotExtension = ".json.gz";
+
+    // Bodies are only used for rename similarity; the start of a long body is enough
+    private const int MaxBodyLength = 4000;
+
+    private static readonly Regex ValidName = new(@"^[A-Za-z0-9._-]{1,64}$", RegexOptions.Compiled);
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IIndexEncryptionService _encryption;
+    private readonly ILogger<IndexSnapshotService> _logger;
+    private readonly int _maxSnapshots;
+
+    public IndexSnapshotService(
+        IPathResolutionService pathResolution,
+        ISQLiteSymbolService sqliteService,
+        IIndexEncryptionService encryption,
+        IConfiguration configuration,
+        ILogger<IndexSnapshotService> logger)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _sqliteService = sqliteService ?? throw new ArgumentNullException(nameof(sqliteService));
+        _encryption = encryption ?? throw new ArgumentNullException(nameof(encryption));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _maxSnapshots = Math.Max(1, configuration.GetValue("CodeSearch:Snapshots:MaxSnapshots", 20));
+    }
+
+    public async Task<IndexSnapshot> CaptureAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            throw new InvalidOperationException($"No symbol database for {workspacePath} - run index_workspace first");
+        }
+
+        var snapshot = new IndexSnapshot { CreatedAt = DateTime.UtcNow };
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var relativePath = Path.IsPathRooted(file.Path)
+                ? Path.GetRelativePath(workspacePath, file.Path).Replace('\\', '/')
+                : file.Path.Replace('\\', '/');
+            var content = file.Content ?? string.Empty;
+
+            var declarations = DeclarationScanner.Supports(relativePath)
+                ? DeclarationScanner.Scan(content, relativePath)
+                : new List<Declaration>();
+            foreach (var declaration in declarations.Where(d => d.Body.Length > MaxBodyLength))
+            {
+                declaration.Body = declaration.Body[..MaxBodyLength];
+            }
+
+            snapshot.Files[relativePath] = new SnapshotFile
+            {
+                Language = file.Language,
+                SizeBytes = file.Size,
+                Lines = content.Length == 0 ? 0 : content.Count(c => c == '\n') + 1,
+                ContentHash = Convert.ToHexString(SHA256.HashData(Encoding.UTF8.GetBytes(content)))[..16],
+                Declarations = declarations
+            };
+        }
+
+        return snapshot;
+    }
+
+    public async Task<IndexSnapshotInfo> SaveAsync(string workspacePath, string name, CancellationToken cancellationToken = default)
+    {
+        var path = GetSnapshotPath(workspacePath, name);
+        var snapshot = await CaptureAsync(workspacePath, cancellationToken);
+        snapshot.Name = name;
+
+        using (var buffer = new MemoryStream())
+        {
+            using (var gzip = new GZipStream(buffer, CompressionLevel.Optimal, leaveOpen: true))
+            {
+                await JsonSerializer.SerializeAsync(gzip, snapshot, cancellationToken: cancellationToken);
+            }
+
+            var bytes = _encryption.IsEnabled ? _encryption.Encrypt(buffer.ToArray()) : buffer.ToArray();
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            await File.WriteAllBytesAsync(path, bytes, cancellationToken);
+        }
+
+        _logger.LogInformation("Saved index snapshot {Name} for {WorkspacePath} ({FileCount} files)",
+            name, workspacePath, snapshot.Files.Count);
+        Prune(workspacePath);
+
+        return List(workspacePath).First(s => s.Name == name);
+    }
+
+    public async Task<IndexSnapshot?> LoadAsync(string workspacePath, string name, CancellationToken cancellationToken = default)
+    {
+        var path = GetSnapshotPath(workspacePath, name);
+        if (!File.Exists(path))
+            return null;
+
+        var bytes = await File.ReadAllBytesAsync(path, cancellationToken);
+        if (_encryption.IsEncryptedPayload(bytes))
+        {
+            bytes = _encryption.Decrypt(bytes);
+        }
+
+        using var gzip = new GZipStream(new MemoryStream(bytes), CompressionMode.Decompress);
+        var snapshot = await JsonSerializer.DeserializeAsync<IndexSnapshot>(gzip, cancellationToken: cancellationToken);
+        if (snapshot == null)
+            return null;
+
+        // Restore the case-insensitive path lookup the serializer drops
+        snapshot.Files = new Dictionary<string, SnapshotFile>(snapshot.Files, StringComparer.OrdinalIgnoreCase);
+        return snapshot;
+    }
+
+    public IReadOnlyList<IndexSnapshotInfo> List(string workspacePath)
+    {
+        var directory = Path.Combine(_pathResolution.GetIndexPath(workspacePath), SnapshotDirectory);
+        if (!Directory.Exists(directory))
+            return Array.Empty<IndexSnapshotInfo>();
+
+        return new DirectoryInfo(directory)
+            .GetFiles("*" + SnapshotExtension)
+            .Select(f => new IndexSnapshotInfo
+            {
+                Name = f.Name[..^SnapshotExtension.Length],
+                CreatedAt = f.LastWriteTimeUtc,
+                SizeBytes = f.Length
+            })
+            .OrderByDescending(s => s.CreatedAt)
+            .ToList();
+    }
+
+    /// <summary>
+    /// Snapshot names become file names, so only letters, digits, '.', '_' and '-' are allowed
+    /// </summary>
+    public static bool IsValidName(string name) =>
+        ValidName.IsMatch(name) && name.Trim('.').Length > 0;
+
+    private void Prune(string workspacePath)
+    {
+        foreach (var stale in List(workspacePath).Skip(_maxSnapshots))
+        {
+            try
+            {
+                File.Delete(GetSnapshotPath(workspacePath, stale.Name));
+                _logger.LogDebug("Pruned index snapshot {Name}", stale.Name);
+            }
+            catch (IOException ex)
+            {
+                _logger.LogWarning(ex, "Could not delete index snapshot {Name}", stale.Name);
+            }
+        }
+    }
+
+    private string GetSnapshotPath(string workspacePath, string name)
+    {
+        if (!IsValidName(name))
+        {
+            throw new ArgumentException($"Invalid snapshot name '{name}' - use letters, digits, '.', '_' and '-'", nameof(name));
+        }
+
+        return Path.Combine(_pathResolution.GetIndexPath(workspacePath), SnapshotDirectory, name + SnapshotExtension);
+    }
+}
@@ -0,0 +1,214 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// How completely a feature works for a language
+/// </summary>
+public enum FeatureLevel
+{
+    /// <summary>Not available - tools fall back to text search or return nothing</summary>
+    None,
+    /// <summary>Works for common constructs; embedded or dynamic code may be missed</summary>
+    Partial,
+    /// <summary>Tree-sitter backed with identifier positions</summary>
+    Full
+}
+
+/// <summary>
+/// Extraction feature levels for one language
+/// </summary>
+public class LanguageCapability
+{
+    public string Name { get; init; } = string.Empty;
+    public string[] Extensions { get; init; } = Array.Empty<string>();
+
+    /// <summary>
+    /// symbol_search, goto_definition, get_symbols_overview, read_symbols
+    /// </summary>
+    public FeatureLevel Symbols { get; init; }
+
+    /// <summary>
+    /// find_references and trace_call_path
+    /// </summary>
+    public FeatureLevel References { get; init; }
+
+    /// <summary>
+    /// smart_refactor rename_symbol (needs identifier positions)
+    /// </summary>
+    public FeatureLevel Rename { get; init; }
+
+    public string? Notes { get; init; }
+}
+
+/// <summary>
+/// Catalog of languages julie-codesearch extracts, used for capability discovery
+/// </summary>
+public static class LanguageCapabilities
+{
+    private static LanguageCapability Code(string name, params string[] extensions) => new()
+    {
+        Name = name,
+        Extensions = extensions,
+        Symbols = FeatureLevel.Full,
+        References = FeatureLevel.Full,
+        Rename = FeatureLevel.Full
+    };
+
+    private static LanguageCapability Scripting(string name, params string[] extensions) => new()
+    {
+        Name = name,
+        Extensions = extensions,
+        Symbols = FeatureLevel.Full,
+        References = FeatureLevel.Partial,
+        Rename = FeatureLevel.Partial,
+        Notes = "Dynamic dispatch and string-built names are not resolved"
+    };
+
+    private static LanguageCapability Markup(string name, string notes, params string[] extensions) => new()
+    {
+        Name = name,
+        Extensions = extensions,
+        Symbols = FeatureLevel.Partial,
+        References = FeatureLevel.None,
+        Rename = FeatureLevel.None,
+        Notes = notes
+    };
+
+    /// <summary>
+    /// All languages with type extraction support
+    /// </summary>
+    public static IReadOnlyList<LanguageCapability> All { get; } = new List<LanguageCapability>
+    {
+        // Core languages
+        Code("rust", ".rs"),
+        Code("typescript", ".ts", ".tsx", ".d.ts"),
+        Code("javascript", ".js", ".jsx", ".mjs", ".cjs"),
+        Code("python", ".py", ".pyi"),
+        Code("java", ".java"),
+        Code("csharp", ".cs"),
+        Code("php", ".php"),
+        Code("ruby", ".rb"),
+        Code("swift", ".swift"),
+        Code("kotlin", ".kt", ".kts"),
+        new LanguageCapability
+        {
+            Name = "scala",
+            Extensions = new[] { ".scala", ".sc" },
+            Symbols = FeatureLevel.Partial,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.None,
+            Notes = "Symbols come from CodeSearch's own declaration extraction; references are found by text search"
+        },
+
+        // Systems languages
+        Code("c", ".c", ".h"),
+        Code("cpp", ".cpp", ".cc", ".cxx", ".hpp", ".hh"),
+        Code("go", ".go"),
+        Scripting("lua", ".lua"),
+
+        // Specialized languages
+        Scripting("gdscript", ".gd"),
+        new LanguageCapability
+        {
+            Name = "vue",
+            Extensions = new[] { ".vue" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.Partial,
+            Notes = "Symbols come from <script> and <script setup> blocks, at their lines in the .vue file; props, emits and template refs (ref=\"...\") are symbols too. Other template bindings are not tracked"
+        },
+        new LanguageCapability
+        {
+            Name = "svelte",
+            Extensions = new[] { ".svelte" },
+            Symbols = FeatureLevel.Partial,
+            References = FeatureLevel.None,
+            Rename = FeatureLevel.None,
+            Notes = "Symbols come from CodeSearch's own extraction of top-level <script> declarations, props and reactive statements ($:, $derived, $effect); template markup is not read"
+        },
+        new LanguageCapability
+        {
+            Name = "razor",
+            Extensions = new[] { ".razor", ".cshtml" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.Partial,
+            Notes = "Symbols come from @code and @functions blocks; markup expressions are not tracked"
+        },
+        Markup("sql", "Tables and their columns, views, procedures, functions, triggers, indexes and types; statement bodies are not indexed", ".sql"),
+        new LanguageCapability
+        {
+            Name = "protobuf",
+            Extensions = new[] { ".proto" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.None,
+            Notes = "Symbols come from CodeSearch's own declaration extraction; references include the Go and C# code protoc generates"
+        },
+        new LanguageCapability
+        {
+            Name = "graphql",
+            Extensions = new[] { ".graphql", ".graphqls", ".gql" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.None,
+            Notes = "Symbols come from CodeSearch's own schema extraction; references link schema fields to the resolvers serving them"
+        },
+        new LanguageCapability
+        {
+            Name = "terraform",
+            Extensions = new[] { ".tf", ".tfvars" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.None,
+            Notes = "Symbols come from CodeSearch's own declaration extraction; references are found by address (var.name, module.name) within the declaring module"
+        },
+        new LanguageCapability
+        {
+            Name = "dockerfile",
+            Extensions = new[] { ".dockerfile" },
+            Symbols = FeatureLevel.Full,
+            References = FeatureLevel.Partial,
+            Rename = FeatureLevel.None,
+            Notes = "Files named Dockerfile, Dockerfile.* or Containerfile too. Symbols come from CodeSearch's own instruction extraction; references are $-expansions and stage uses within the declaring Dockerfile"
+        },
+        new LanguageCapability
+        {
+            Name = "markdown",
+            Extensions = new[] { ".md", ".mdx", ".markdown" },
+            Symbols = FeatureLevel.Partial,
+            References = FeatureLevel.None,
+            Rename = FeatureLevel.None,
+            Notes = "Headings (with their GitHub anchors) and fenced code block languages only; get_symbols_overview lists them as the document outline"
+        },
+        new LanguageCapability
+        {
+            Name = "jupyter",
+            Extensions = new[] { ".ipynb" },
+            Symbols = FeatureLevel.None,
+            References = FeatureLevel.None,
+            Rename = FeatureLevel.None,
+            Notes = "Code cells are indexed for text and line search; hits report the cell number and the line within the cell"
+        },
+        Markup("html", "Element ids and structure only", ".html", ".htm"),
+        Markup("css", "Selectors and custom properties only", ".css", ".scss", ".less"),
+        Markup("regex", "Named groups only", ".regex"),
+        Scripting("bash", ".sh", ".bash"),
+        Scripting("powershell", ".ps1", ".psm1"),
+        Code("zig", ".zig"),
+        Code("dart", ".dart")
+    };
+
+    /// <summary>
+    /// Finds a language by name or file extension (case-insensitive)
+    /// </summary>
+    public static LanguageCapability? Find(string nameOrExtension)
+    {
+        var key = nameOrExtension.Trim();
+        if (key.StartsWith('.'))
+        {
+            return All.FirstOrDefault(l => l.Extensions.Contains(key, StringComparer.OrdinalIgnoreCase));
+        }
+
+        return All.FirstOrDefault(l => string.Equals(l.Name, key, StringComparison.OrdinalIgnoreCase));
+    }
+}
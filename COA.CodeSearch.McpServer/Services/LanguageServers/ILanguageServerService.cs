@@ -0,0 +1,37 @@
+namespace COA.CodeSearch.McpServer.Services.LanguageServers;
+
+/// <summary>
+/// Optional connections to real language servers (gopls, OmniSharp, typescript-language-server) used to verify
+/// or refine the index's heuristic answers when precision matters. Every method returns null when no server
+/// handles the file, the server can't be started or it fails, and callers then keep the index's answer.
+/// </summary>
+public interface ILanguageServerService
+{
+    /// <summary>
+    /// CodeSearch:LanguageServers:Enabled
+    /// </summary>
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// Name of the configured server for the file's extension, or null
+    /// </summary>
+    string? GetServerFor(string filePath);
+
+    /// <summary>
+    /// textDocument/definition at a position in <paramref name="filePath"/>
+    /// </summary>
+    Task<IReadOnlyList<LspLocation>?> GetDefinitionAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// textDocument/hover at a position: the server's hover contents as markdown or plain text
+    /// </summary>
+    Task<string?> GetHoverAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// textDocument/rename at a position: the edits per file that rename the symbol there to <paramref name="newName"/>
+    /// </summary>
+    Task<Dictionary<string, List<LspTextEdit>>?> GetRenameEditsAsync(string workspacePath, string filePath, LspPosition position,
+        string newName, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,367 @@
+using System.Collections.Concurrent;
+using System.Diagnostics;
+using System.Text;
+using System.Text.Json;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.LanguageServers;
+
+/// <summary>
+/// One running language server for one workspace root: JSON-RPC over the process's stdin/stdout with
+/// Content-Length framing. Requests the server sends back (configuration, progress) get empty answers.
+/// </summary>
+internal sealed class LanguageServerConnection : IAsyncDisposable
+{
+    private readonly Process _process;
+    private readonly Stream _input;
+    private readonly Stream _output;
+    private readonly TimeSpan _timeout;
+    private readonly ILogger _logger;
+    private readonly ConcurrentDictionary<long, TaskCompletionSource<JsonElement>> _pending = new();
+    private readonly Dictionary<string, int> _documentVersions = new(StringComparer.OrdinalIgnoreCase);
+    private readonly SemaphoreSlim _writeLock = new(1, 1);
+    private readonly SemaphoreSlim _documentLock = new(1, 1);
+    private readonly CancellationTokenSource _stopping = new();
+    private Task? _readLoop;
+    private long _nextId;
+
+    private LanguageServerConnection(LanguageServerOptions server, Process process, TimeSpan timeout, ILogger logger)
+    {
+        Server = server;
+        _process = process;
+        _input = process.StandardInput.BaseStream;
+        _output = process.StandardOutput.BaseStream;
+        _timeout = timeout;
+        _logger = logger;
+    }
+
+    public LanguageServerOptions Server { get; }
+
+    public bool IsAlive => !_process.HasExited && _readLoop is { IsCompleted: false };
+
+    /// <summary>
+    /// Starts the server process in <paramref name="rootPath"/> and completes the initialize handshake
+    /// </summary>
+    public static async Task<LanguageServerConnection> StartAsync(LanguageServerOptions server, string rootPath,
+        TimeSpan timeout, ILogger logger, CancellationToken cancellationToken)
+    {
+        var startInfo = new ProcessStartInfo(server.Command)
+        {
+            WorkingDirectory = rootPath,
+            RedirectStandardInput = true,
+            RedirectStandardOutput = true,
+            RedirectStandardError = true,
+            UseShellExecute = false,
+            CreateNoWindow = true
+        };
+        foreach (var argument in server.Arguments)
+        {
+            startInfo.ArgumentList.Add(argument);
+        }
+
+        Process process;
+        try
+        {
+            process = Process.Start(startInfo) ?? throw new LanguageServerException($"{server.Name} did not start");
+        }
+        catch (System.ComponentModel.Win32Exception ex)
+        {
+            throw new LanguageServerException($"{server.Name}: '{server.Command}' not found or not executable", ex);
+        }
+
+        process.ErrorDataReceived += (_, e) =>
+        {
+            if (e.Data != null)
+                logger.LogTrace("{Server} stderr: {Line}", server.Name, e.Data);
+        };
+        process.BeginErrorReadLine();
+
+        var connection = new LanguageServerConnection(server, process, timeout, logger);
+        connection._readLoop = Task.Run(() => connection.ReadLoopAsync(connection._stopping.Token));
+
+        try
+        {
+            var rootUri = ToUri(rootPath);
+            await connection.RequestAsync("initialize", new
+            {
+                processId = Environment.ProcessId,
+                rootUri,
+                workspaceFolders = new[] { new { uri = rootUri, name = Path.GetFileName(rootPath.TrimEnd(Path.DirectorySeparatorChar)) } },
+                capabilities = new
+                {
+                    textDocument = new
+                    {
+                        synchronization = new { didSave = false },
+                        definition = new { linkSupport = true },
+                        references = new { },
+                        hover = new { contentFormat = new[] { "markdown", "plaintext" } },
+                        rename = new { prepareSupport = false }
+                    },
+                    workspace = new { workspaceFolders = true, configuration = true, workspaceEdit = new { documentChanges = true } }
+                }
+            }, cancellationToken);
+            await connection.NotifyAsync("initialized", new { }, cancellationToken);
+        }
+        catch
+        {
+            await connection.DisposeAsync();
+            throw;
+        }
+
+        logger.LogInformation("Started language server {Server} for {Root}", server.Name, rootPath);
+        return connection;
+    }
+
+    /// <summary>
+    /// Sends a request and waits for its result; error responses, timeouts and exits throw
+    /// <see cref="LanguageServerException"/>
+    /// </summary>
+    public async Task<JsonElement> RequestAsync(string method, object? parameters, CancellationToken cancellationToken)
+    {
+        var id = Interlocked.Increment(ref _nextId);
+        var completion = new TaskCompletionSource<JsonElement>(TaskCreationOptions.RunContinuationsAsynchronously);
+        _pending[id] = completion;
+
+        try
+        {
+            await WriteAsync(new { jsonrpc = "2.0", id, method, @params = parameters }, cancellationToken);
+
+            using var timeout = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+            timeout.CancelAfter(_timeout);
+            try
+            {
+                return await completion.Task.WaitAsync(timeout.Token);
+            }
+            catch (OperationCanceledException) when (!cancellationToken.IsCancellationRequested)
+            {
+                throw new LanguageServerException($"{Server.Name} did not answer {method} within {_timeout.TotalSeconds:0}s");
+            }
+        }
+        finally
+        {
+            _pending.TryRemove(id, out _);
+        }
+    }
+
+    public Task NotifyAsync(string method, object? parameters, CancellationToken cancellationToken) =>
+        WriteAsync(new { jsonrpc = "2.0", method, @params = parameters }, cancellationToken);
+
+    /// <summary>
+    /// Sends the current text of a file: didOpen the first time, a full-text didChange afterwards, so the
+    /// server answers against what is on disk even when no editor has the file open
+    /// </summary>
+    public async Task SyncDocumentAsync(string filePath, CancellationToken cancellationToken)
+    {
+        var text = await File.ReadAllTextAsync(filePath, cancellationToken);
+        var uri = ToUri(filePath);
+
+        await _documentLock.WaitAsync(cancellationToken);
+        try
+        {
+            if (_documentVersions.TryGetValue(filePath, out var version))
+            {
+                _documentVersions[filePath] = ++version;
+                await NotifyAsync("textDocument/didChange", new
+                {
+                    textDocument = new { uri, version },
+                    contentChanges = new[] { new { text } }
+                }, cancellationToken);
+            }
+            else
+            {
+                _documentVersions[filePath] = 1;
+                await NotifyAsync("textDocument/didOpen", new
+                {
+                    textDocument = new { uri, languageId = LanguageIdFor(filePath), version = 1, text }
+                }, cancellationToken);
+            }
+        }
+        finally
+        {
+            _documentLock.Release();
+        }
+    }
+
+    public static string ToUri(string path) => new Uri(Path.GetFullPath(path)).AbsoluteUri;
+
+    private static string LanguageIdFor(string filePath) => Path.GetExtension(filePath).ToLowerInvariant() switch
+    {
+        ".cs" => "csharp",
+        ".go" => "go",
+        ".ts" or ".mts" or ".cts" => "typescript",
+        ".tsx" => "typescriptreact",
+        ".js" => "javascript",
+        ".jsx" => "javascriptreact",
+        ".py" => "python",
+        ".rs" => "rust",
+        var extension => extension.TrimStart('.')
+    };
+
+    private async Task WriteAsync(object message, CancellationToken cancellationToken)
+    {
+        var body = JsonSerializer.SerializeToUtf8Bytes(message);
+        var header = Encoding.ASCII.GetBytes($"Content-Length: {body.Length}\r\n\r\n");
+
+        await _writeLock.WaitAsync(cancellationToken);
+        try
+        {
+            await _input.WriteAsync(header, cancellationToken);
+            await _input.WriteAsync(body, cancellationToken);
+            await _input.FlushAsync(cancellationToken);
+        }
+        catch (IOException ex)
+        {
+            throw new LanguageServerException($"{Server.Name} is no longer running", ex);
+        }
+        finally
+        {
+            _writeLock.Release();
+        }
+    }
+
+    private async Task ReadLoopAsync(CancellationToken cancellationToken)
+    {
+        try
+        {
+            while (!cancellationToken.IsCancellationRequested)
+            {
+                var body = await ReadMessageAsync(cancellationToken);
+                if (body == null)
+                    break;
+
+                using var document = JsonDocument.Parse(body);
+                await DispatchAsync(document.RootElement, cancellationToken);
+            }
+        }
+        catch (OperationCanceledException) when (cancellationToken.IsCancellationRequested)
+        {
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Language server {Server} connection failed", Server.Name);
+        }
+        finally
+        {
+            foreach (var pending in _pending.Values)
+            {
+                pending.TrySetException(new LanguageServerException($"{Server.Name} exited"));
+            }
+        }
+    }
+
+    private async Task<byte[]?> ReadMessageAsync(CancellationToken cancellationToken)
+    {
+        var contentLength = -1;
+        while (true)
+        {
+            var line = await ReadHeaderLineAsync(cancellationToken);
+            if (line == null)
+                return null;
+            if (line.Length == 0)
+                break;
+
+            var separator = line.IndexOf(':');
+            if (separator > 0 && line[..separator].Trim().Equals("Content-Length", StringComparison.OrdinalIgnoreCase))
+            {
+                contentLength = int.Parse(line[(separator + 1)..].Trim());
+            }
+        }
+
+        if (contentLength < 0)
+            throw new LanguageServerException($"{Server.Name} sent a message without Content-Length");
+
+        var body = new byte[contentLength];
+        await _output.ReadExactlyAsync(body, cancellationToken);
+        return body;
+    }
+
+    private async Task<string?> ReadHeaderLineAsync(CancellationToken cancellationToken)
+    {
+        var bytes = new List<byte>();
+        var buffer = new byte[1];
+        while (true)
+        {
+            if (await _output.ReadAsync(buffer, cancellationToken) == 0)
+                return null;
+            if (buffer[0] == '\n')
+                break;
+            if (buffer[0] != '\r')
+                bytes.Add(buffer[0]);
+        }
+        return Encoding.ASCII.GetString(bytes.ToArray());
+    }
+
+    private async Task DispatchAsync(JsonElement message, CancellationToken cancellationToken)
+    {
+        var hasMethod = message.TryGetProperty("method", out var method);
+        var hasId = message.TryGetProperty("id", out var id);
+
+        if (!hasMethod)
+        {
+            // Response to one of our requests
+            if (hasId && id.ValueKind == JsonValueKind.Number && _pending.TryGetValue(id.GetInt64(), out var completion))
+            {
+                if (message.TryGetProperty("error", out var error))
+                {
+                    var text = error.TryGetProperty("message", out var m) ? m.GetString() : error.ToString();
+                    completion.TrySetException(new LanguageServerException($"{Server.Name}: {text}"));
+                }
+                else
+                {
+                    completion.TrySetResult(message.TryGetProperty("result", out var result) ? result.Clone() : default);
+                }
+            }
+            return;
+        }
+
+        if (!hasId)
+            return; // Notifications (diagnostics, log messages) are not used
+
+        // workspace/configuration wants one entry per requested item; everything else accepts null
+        object?[]? answer = null;
+        if (method.GetString() == "workspace/configuration"
+            && message.TryGetProperty("params", out var parameters)
+            && parameters.TryGetProperty("items", out var items))
+        {
+            answer = new object?[items.GetArrayLength()];
+        }
+        await WriteAsync(new { jsonrpc = "2.0", id = id.Clone(), result = answer }, cancellationToken);
+    }
+
+    public async ValueTask DisposeAsync()
+    {
+        if (!_process.HasExited)
+        {
+            try
+            {
+                using var shutdown = new CancellationTokenSource(TimeSpan.FromSeconds(2));
+                await RequestAsync("shutdown", null, shutdown.Token);
+                await NotifyAsync("exit", null, shutdown.Token);
+                await _process.WaitForExitAsync(shutdown.Token);
+            }
+            catch (Exception ex) when (ex is LanguageServerException or OperationCanceledException or InvalidOperationException)
+            {
+                _logger.LogDebug("Language server {Server} did not shut down cleanly, killing it", Server.Name);
+            }
+
+            if (!_process.HasExited)
+            {
+                try
+                {
+                    _process.Kill(entireProcessTree: true);
+                }
+                catch (InvalidOperationException)
+                {
+                }
+            }
+        }
+
+        _stopping.Cancel();
+        if (_readLoop != null)
+        {
+            await Task.WhenAny(_readLoop, Task.Delay(TimeSpan.FromSeconds(1)));
+        }
+        _process.Dispose();
+        _stopping.Dispose();
+    }
+}
@@ -0,0 +1,67 @@
+namespace COA.CodeSearch.McpServer.Services.LanguageServers;
+
+/// <summary>
+/// A configured language server (CodeSearch:LanguageServers:Servers)
+/// </summary>
+public class LanguageServerOptions
+{
+    /// <summary>
+    /// Shown in results as the source of precise answers, e.g. "gopls"
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Executable, resolved on PATH
+    /// </summary>
+    public string Command { get; set; } = string.Empty;
+
+    public string[] Arguments { get; set; } = Array.Empty<string>();
+
+    /// <summary>
+    /// File extensions the server answers for, with the leading dot
+    /// </summary>
+    public string[] Extensions { get; set; } = Array.Empty<string>();
+
+    public bool Handles(string filePath) =>
+        Extensions.Contains(Path.GetExtension(filePath), StringComparer.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Servers used when none are configured
+    /// </summary>
+    public static LanguageServerOptions[] Defaults => new[]
+    {
+        new LanguageServerOptions { Name = "gopls", Command = "gopls", Extensions = new[] { ".go" } },
+        new LanguageServerOptions { Name = "omnisharp", Command = "OmniSharp", Arguments = new[] { "-lsp" }, Extensions = new[] { ".cs" } },
+        new LanguageServerOptions
+        {
+            Name = "typescript-language-server",
+            Command = "typescript-language-server",
+            Arguments = new[] { "--stdio" },
+            Extensions = new[] { ".ts", ".tsx", ".js", ".jsx", ".mts", ".cts" }
+        }
+    };
+}
+
+/// <summary>
+/// Zero-based line and UTF-16 character offset, as LSP counts them
+/// </summary>
+public record LspPosition(int Line, int Character);
+
+public record LspRange(LspPosition Start, LspPosition End);
+
+/// <summary>
+/// A range in a file, with the path already converted from the server's file URI
+/// </summary>
+public record LspLocation(string FilePath, LspRange Range);
+
+public record LspTextEdit(LspRange Range, string NewText);
+
+/// <summary>
+/// Thrown for error responses, timeouts and servers that exit
+/// </summary>
+public class LanguageServerException : Exception
+{
+    public LanguageServerException(string message, Exception? innerException = null) : base(message, innerException)
+    {
+    }
+}
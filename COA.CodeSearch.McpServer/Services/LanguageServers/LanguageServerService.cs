@@ -0,0 +1,138 @@
+using System.Collections.Concurrent;
+using System.Text.Json;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.LanguageServers;
+
+/// <summary>
+/// Starts language servers lazily, one per server and workspace, and keeps them running for later requests.
+/// A server that fails to start is not retried until <c>RetryAfterSeconds</c> has passed, so a missing
+/// binary costs one failed start rather than one per query.
+/// </summary>
+public class LanguageServerService : ILanguageServerService, IAsyncDisposable
+{
+    private readonly IReadOnlyList<LanguageServerOptions> _servers;
+    private readonly TimeSpan _requestTimeout;
+    private readonly TimeSpan _retryAfter;
+    private readonly ILogger<LanguageServerService> _logger;
+    private readonly ConcurrentDictionary<string, Lazy<Task<LanguageServerConnection>>> _connections = new(StringComparer.OrdinalIgnoreCase);
+    private readonly ConcurrentDictionary<string, DateTime> _failedStarts = new(StringComparer.OrdinalIgnoreCase);
+
+    public LanguageServerService(IConfiguration configuration, ILogger<LanguageServerService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        IsEnabled = configuration.GetValue("CodeSearch:LanguageServers:Enabled", false);
+        var configured = configuration.GetSection("CodeSearch:LanguageServers:Servers").Get<LanguageServerOptions[]>();
+        _servers = (configured is { Length: > 0 } ? configured : LanguageServerOptions.Defaults)
+            .Where(s => !string.IsNullOrWhiteSpace(s.Command) && s.Extensions.Length > 0)
+            .ToList();
+        _requestTimeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:LanguageServers:RequestTimeoutSeconds", 10));
+        _retryAfter = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:LanguageServers:RetryAfterSeconds", 300));
+    }
+
+    public bool IsEnabled { get; }
+
+    public string? GetServerFor(string filePath) => IsEnabled ? FindServer(filePath)?.Name : null;
+
+    public async Task<IReadOnlyList<LspLocation>?> GetDefinitionAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default)
+    {
+        var result = await RequestAtAsync(workspacePath, filePath, "textDocument/definition", position, null, cancellationToken);
+        return result.HasValue ? LspProtocol.ParseLocations(result.Value) : null;
+    }
+
+    public async Task<string?> GetHoverAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default)
+    {
+        var result = await RequestAtAsync(workspacePath, filePath, "textDocument/hover", position, null, cancellationToken);
+        return result.HasValue ? LspProtocol.ParseHover(result.Value) : null;
+    }
+
+    public async Task<Dictionary<string, List<LspTextEdit>>?> GetRenameEditsAsync(string workspacePath, string filePath, LspPosition position,
+        string newName, CancellationToken cancellationToken = default)
+    {
+        var result = await RequestAtAsync(workspacePath, filePath, "textDocument/rename", position, newName, cancellationToken);
+        return result.HasValue ? LspProtocol.ParseWorkspaceEdit(result.Value) : null;
+    }
+
+    private async Task<JsonElement?> RequestAtAsync(string workspacePath, string filePath, string method, LspPosition position,
+        string? newName, CancellationToken cancellationToken)
+    {
+        if (!IsEnabled)
+            return null;
+
+        var server = FindServer(filePath);
+        if (server == null)
+            return null;
+
+        var connection = await GetConnectionAsync(server, workspacePath, cancellationToken);
+        if (connection == null)
+            return null;
+
+        try
+        {
+            await connection.SyncDocumentAsync(filePath, cancellationToken);
+            var textDocument = new { uri = LanguageServerConnection.ToUri(filePath) };
+            var lspPosition = new { line = position.Line, character = position.Character };
+            object parameters = newName == null
+                ? new { textDocument, position = lspPosition }
+                : new { textDocument, position = lspPosition, newName };
+
+            return await connection.RequestAsync(method, parameters, cancellationToken);
+        }
+        catch (Exception ex) when (ex is LanguageServerException or IOException)
+        {
+            _logger.LogWarning("{Server} {Method} failed for {File}: {Message}", server.Name, method, filePath, ex.Message);
+            return null;
+        }
+    }
+
+    private LanguageServerOptions? FindServer(string filePath) => _servers.FirstOrDefault(s => s.Handles(filePath));
+
+    private async Task<LanguageServerConnection?> GetConnectionAsync(LanguageServerOptions server, string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        var key = $"{server.Name}|{root}";
+
+        if (_failedStarts.TryGetValue(key, out var failedAt) && DateTime.UtcNow - failedAt < _retryAfter)
+            return null;
+
+        var lazy = _connections.GetOrAdd(key, _ => new Lazy<Task<LanguageServerConnection>>(() =>
+            LanguageServerConnection.StartAsync(server, root, _requestTimeout, _logger, CancellationToken.None)));
+
+        try
+        {
+            var connection = await lazy.Value.WaitAsync(cancellationToken);
+            if (connection.IsAlive)
+                return connection;
+
+            // Exited since the last request - start a fresh one next time
+            _logger.LogInformation("Language server {Server} for {Root} has exited", server.Name, root);
+            _connections.TryRemove(new KeyValuePair<string, Lazy<Task<LanguageServerConnection>>>(key, lazy));
+            await connection.DisposeAsync();
+            return null;
+        }
+        catch (LanguageServerException ex)
+        {
+            _logger.LogWarning("Language server {Server} unavailable for {Root}, using the index: {Message}", server.Name, root, ex.Message);
+            _failedStarts[key] = DateTime.UtcNow;
+            _connections.TryRemove(new KeyValuePair<string, Lazy<Task<LanguageServerConnection>>>(key, lazy));
+            return null;
+        }
+    }
+
+    public async ValueTask DisposeAsync()
+    {
+        foreach (var lazy in _connections.Values)
+        {
+            if (lazy.IsValueCreated && lazy.Value.IsCompletedSuccessfully)
+            {
+                await lazy.Value.Result.DisposeAsync();
+            }
+        }
+        _connections.Clear();
+    }
+}
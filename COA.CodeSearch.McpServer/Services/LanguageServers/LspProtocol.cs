@@ -0,0 +1,170 @@
+using System.Text;
+using System.Text.Json;
+
+namespace COA.CodeSearch.McpServer.Services.LanguageServers;
+
+/// <summary>
+/// Reading LSP results (locations, workspace edits, hovers) and applying text edits
+/// </summary>
+public static class LspProtocol
+{
+    /// <summary>
+    /// Locations from a definition or references result: null, a Location, Location[] or LocationLink[]
+    /// </summary>
+    public static List<LspLocation> ParseLocations(JsonElement result)
+    {
+        var locations = new List<LspLocation>();
+        if (result.ValueKind == JsonValueKind.Object)
+        {
+            AddLocation(result, locations);
+        }
+        else if (result.ValueKind == JsonValueKind.Array)
+        {
+            foreach (var item in result.EnumerateArray())
+            {
+                AddLocation(item, locations);
+            }
+        }
+        return locations;
+    }
+
+    /// <summary>
+    /// Text edits per file from a WorkspaceEdit, from either "changes" or "documentChanges".
+    /// File create/rename/delete operations are not supported and are ignored.
+    /// </summary>
+    public static Dictionary<string, List<LspTextEdit>> ParseWorkspaceEdit(JsonElement result)
+    {
+        var edits = new Dictionary<string, List<LspTextEdit>>(StringComparer.OrdinalIgnoreCase);
+        if (result.ValueKind != JsonValueKind.Object)
+            return edits;
+
+        if (result.TryGetProperty("documentChanges", out var documentChanges) && documentChanges.ValueKind == JsonValueKind.Array)
+        {
+            foreach (var change in documentChanges.EnumerateArray())
+            {
+                if (change.TryGetProperty("textDocument", out var document)
+                    && document.TryGetProperty("uri", out var uri)
+                    && change.TryGetProperty("edits", out var fileEdits))
+                {
+                    AddEdits(edits, uri.GetString()!, fileEdits);
+                }
+            }
+        }
+        else if (result.TryGetProperty("changes", out var changes) && changes.ValueKind == JsonValueKind.Object)
+        {
+            foreach (var file in changes.EnumerateObject())
+            {
+                AddEdits(edits, file.Name, file.Value);
+            }
+        }
+        return edits;
+    }
+
+    /// <summary>
+    /// Text of a Hover result. Contents may be MarkupContent, a MarkedString (a string or a language/value
+    /// pair, written as a fenced code block) or an array of MarkedStrings. Null when there is nothing to show.
+    /// </summary>
+    public static string? ParseHover(JsonElement result)
+    {
+        if (result.ValueKind != JsonValueKind.Object || !result.TryGetProperty("contents", out var contents))
+            return null;
+
+        var parts = contents.ValueKind == JsonValueKind.Array
+            ? contents.EnumerateArray().Select(MarkedStringText)
+            : new[] { MarkedStringText(contents) };
+        var text = string.Join("\n\n", parts.Where(p => !string.IsNullOrWhiteSpace(p))).Trim();
+        return text.Length == 0 ? null : text;
+    }
+
+    /// <summary>
+    /// Applies edits to <paramref name="text"/>. Ranges refer to the original text, so they are applied from
+    /// last to first.
+    /// </summary>
+    public static string ApplyEdits(string text, IEnumerable<LspTextEdit> edits)
+    {
+        var lineStarts = GetLineStarts(text);
+        var builder = new StringBuilder(text);
+        foreach (var edit in edits.OrderByDescending(e => e.Range.Start.Line).ThenByDescending(e => e.Range.Start.Character))
+        {
+            var start = OffsetOf(text, lineStarts, edit.Range.Start);
+            var end = OffsetOf(text, lineStarts, edit.Range.End);
+            builder.Remove(start, end - start);
+            builder.Insert(start, edit.NewText);
+        }
+        return builder.ToString();
+    }
+
+    public static string ToFilePath(string uri) =>
+        Uri.TryCreate(uri, UriKind.Absolute, out var parsed) && parsed.IsFile ? parsed.LocalPath : uri;
+
+    private static void AddLocation(JsonElement item, List<LspLocation> locations)
+    {
+        // LocationLink points at the whole target; targetSelectionRange is the name itself
+        if (item.TryGetProperty("targetUri", out var targetUri))
+        {
+            var range = item.TryGetProperty("targetSelectionRange", out var selection) ? selection : item.GetProperty("targetRange");
+            locations.Add(new LspLocation(ToFilePath(targetUri.GetString()!), ParseRange(range)));
+        }
+        else if (item.TryGetProperty("uri", out var uri) && item.TryGetProperty("range", out var range))
+        {
+            locations.Add(new LspLocation(ToFilePath(uri.GetString()!), ParseRange(range)));
+        }
+    }
+
+    private static string? MarkedStringText(JsonElement content)
+    {
+        if (content.ValueKind == JsonValueKind.String)
+            return content.GetString();
+        if (content.ValueKind != JsonValueKind.Object || !content.TryGetProperty("value", out var value))
+            return null;
+
+        // { language, value } is code; { kind, value } is MarkupContent, already markdown or plain text
+        return content.TryGetProperty("language", out var language)
+            ? $"```{language.GetString()}\n{value.GetString()}\n```"
+            : value.GetString();
+    }
+
+    private static void AddEdits(Dictionary<string, List<LspTextEdit>> edits, string uri, JsonElement fileEdits)
+    {
+        var path = ToFilePath(uri);
+        if (!edits.TryGetValue(path, out var list))
+        {
+            list = new List<LspTextEdit>();
+            edits[path] = list;
+        }
+
+        foreach (var edit in fileEdits.EnumerateArray())
+        {
+            list.Add(new LspTextEdit(ParseRange(edit.GetProperty("range")), edit.GetProperty("newText").GetString() ?? string.Empty));
+        }
+    }
+
+    private static LspRange ParseRange(JsonElement range) =>
+        new(ParsePosition(range.GetProperty("start")), ParsePosition(range.GetProperty("end")));
+
+    private static LspPosition ParsePosition(JsonElement position) =>
+        new(position.GetProperty("line").GetInt32(), position.GetProperty("character").GetInt32());
+
+    private static List<int> GetLineStarts(string text)
+    {
+        var starts = new List<int> { 0 };
+        for (var i = 0; i < text.Length; i++)
+        {
+            if (text[i] == '\n')
+                starts.Add(i + 1);
+        }
+        return starts;
+    }
+
+    private static int OffsetOf(string text, List<int> lineStarts, LspPosition position)
+    {
+        if (position.Line >= lineStarts.Count)
+            return text.Length;
+
+        var lineStart = lineStarts[position.Line];
+        var lineEnd = position.Line + 1 < lineStarts.Count ? lineStarts[position.Line + 1] - 1 : text.Length;
+        if (lineEnd > lineStart && text[lineEnd - 1] == '\r')
+            lineEnd--;
+        return Math.Min(lineStart + position.Character, lineEnd);
+    }
+}
@@ -0,0 +1,33 @@
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Ambient correlation ID for the current tool invocation.
+/// Flows with the async call chain, so every log line written while serving a request carries the same ID.
+/// </summary>
+public static class CorrelationContext
+{
+    private static readonly AsyncLocal<CorrelationScope?> _current = new();
+
+    /// <summary>
+    /// Correlation ID of the current request, if any
+    /// </summary>
+    public static string? CorrelationId => _current.Value?.CorrelationId;
+
+    /// <summary>
+    /// Name of the tool serving the current request, if any
+    /// </summary>
+    public static string? ToolName => _current.Value?.ToolName;
+
+    /// <summary>
+    /// Starts a new correlation scope for a tool invocation and returns its ID.
+    /// The scope ends automatically when the enclosing async operation completes.
+    /// </summary>
+    public static string Begin(string toolName)
+    {
+        var correlationId = Guid.NewGuid().ToString("N").Substring(0, 12);
+        _current.Value = new CorrelationScope(correlationId, toolName);
+        return correlationId;
+    }
+
+    private sealed record CorrelationScope(string CorrelationId, string ToolName);
+}
@@ -0,0 +1,23 @@
+using Serilog.Core;
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Serilog enricher that stamps CorrelationId and ToolName from <see cref="CorrelationContext"/> onto each event
+/// </summary>
+public class CorrelationIdEnricher : ILogEventEnricher
+{
+    public const string CorrelationIdProperty = "CorrelationId";
+    public const string ToolNameProperty = "ToolName";
+
+    public void Enrich(LogEvent logEvent, ILogEventPropertyFactory propertyFactory)
+    {
+        var correlationId = CorrelationContext.CorrelationId;
+        if (correlationId == null)
+            return;
+
+        logEvent.AddPropertyIfAbsent(propertyFactory.CreateProperty(CorrelationIdProperty, correlationId));
+        logEvent.AddPropertyIfAbsent(propertyFactory.CreateProperty(ToolNameProperty, CorrelationContext.ToolName));
+    }
+}
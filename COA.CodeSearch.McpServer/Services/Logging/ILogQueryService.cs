@@ -0,0 +1,37 @@
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Service for querying recent log entries and adjusting log levels at runtime
+/// </summary>
+public interface ILogQueryService
+{
+    /// <summary>
+    /// Returns recent entries matching the filter, newest first
+    /// </summary>
+    IReadOnlyList<LogEntry> Query(LogQuery query);
+
+    /// <summary>
+    /// Current minimum levels for the default and CodeSearch switches
+    /// </summary>
+    (LogEventLevel Default, LogEventLevel CodeSearch) GetLevels();
+
+    /// <summary>
+    /// Changes the minimum level. When codeSearchOnly is true only COA.CodeSearch.* loggers are affected.
+    /// </summary>
+    void SetLevel(LogEventLevel level, bool codeSearchOnly);
+}
+
+/// <summary>
+/// Filter for <see cref="ILogQueryService.Query"/>
+/// </summary>
+public class LogQuery
+{
+    public LogEventLevel MinimumLevel { get; set; } = LogEventLevel.Verbose;
+    public string? ToolName { get; set; }
+    public string? CorrelationId { get; set; }
+    public string? Contains { get; set; }
+    public DateTimeOffset? Since { get; set; }
+    public int MaxEntries { get; set; } = 100;
+}
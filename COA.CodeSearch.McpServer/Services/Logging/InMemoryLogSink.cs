@@ -0,0 +1,71 @@
+using System.Collections.Concurrent;
+using Serilog.Core;
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Bounded ring buffer of recent log events, queried by the get_logs tool.
+/// The file sink remains the durable record; this keeps the last few thousand entries cheap to filter.
+/// </summary>
+public class InMemoryLogSink : ILogEventSink
+{
+    public const int DefaultCapacity = 2000;
+
+    private readonly ConcurrentQueue<LogEntry> _entries = new();
+    private readonly int _capacity;
+
+    /// <summary>
+    /// Process-wide instance wired into Serilog at startup and registered in DI
+    /// </summary>
+    public static InMemoryLogSink Instance { get; } = new(DefaultCapacity);
+
+    public InMemoryLogSink(int capacity)
+    {
+        _capacity = Math.Max(1, capacity);
+    }
+
+    public void Emit(LogEvent logEvent)
+    {
+        _entries.Enqueue(new LogEntry
+        {
+            Timestamp = logEvent.Timestamp,
+            Level = logEvent.Level,
+            Message = logEvent.RenderMessage(),
+            SourceContext = GetScalar(logEvent, "SourceContext"),
+            CorrelationId = GetScalar(logEvent, CorrelationIdEnricher.CorrelationIdProperty),
+            ToolName = GetScalar(logEvent, CorrelationIdEnricher.ToolNameProperty),
+            Exception = logEvent.Exception?.ToString()
+        });
+
+        while (_entries.Count > _capacity && _entries.TryDequeue(out _))
+        {
+        }
+    }
+
+    /// <summary>
+    /// Snapshot of buffered entries, oldest first
+    /// </summary>
+    public IReadOnlyList<LogEntry> GetEntries() => _entries.ToArray();
+
+    private static string? GetScalar(LogEvent logEvent, string propertyName)
+    {
+        return logEvent.Properties.TryGetValue(propertyName, out var value) && value is ScalarValue scalar
+            ? scalar.Value?.ToString()
+            : null;
+    }
+}
+
+/// <summary>
+/// A buffered log entry
+/// </summary>
+public class LogEntry
+{
+    public DateTimeOffset Timestamp { get; set; }
+    public LogEventLevel Level { get; set; }
+    public string Message { get; set; } = string.Empty;
+    public string? SourceContext { get; set; }
+    public string? CorrelationId { get; set; }
+    public string? ToolName { get; set; }
+    public string? Exception { get; set; }
+}
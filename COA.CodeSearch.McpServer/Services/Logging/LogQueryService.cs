@@ -0,0 +1,64 @@
+using Microsoft.Extensions.Logging;
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Reads from the in-memory log buffer and drives the runtime level switches
+/// </summary>
+public class LogQueryService : ILogQueryService
+{
+    private readonly InMemoryLogSink _sink;
+    private readonly ILogger<LogQueryService> _logger;
+
+    public LogQueryService(InMemoryLogSink sink, ILogger<LogQueryService> logger)
+    {
+        _sink = sink ?? throw new ArgumentNullException(nameof(sink));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+    }
+
+    public IReadOnlyList<LogEntry> Query(LogQuery query)
+    {
+        IEnumerable<LogEntry> entries = _sink.GetEntries();
+
+        entries = entries.Where(e => e.Level >= query.MinimumLevel);
+
+        if (!string.IsNullOrWhiteSpace(query.ToolName))
+            entries = entries.Where(e => string.Equals(e.ToolName, query.ToolName, StringComparison.OrdinalIgnoreCase));
+
+        if (!string.IsNullOrWhiteSpace(query.CorrelationId))
+            entries = entries.Where(e => string.Equals(e.CorrelationId, query.CorrelationId, StringComparison.OrdinalIgnoreCase));
+
+        if (!string.IsNullOrWhiteSpace(query.Contains))
+            entries = entries.Where(e => e.Message.Contains(query.Contains, StringComparison.OrdinalIgnoreCase) ||
+                                         (e.Exception?.Contains(query.Contains, StringComparison.OrdinalIgnoreCase) ?? false));
+
+        if (query.Since.HasValue)
+            entries = entries.Where(e => e.Timestamp >= query.Since.Value);
+
+        return entries
+            .Reverse()
+            .Take(Math.Max(1, query.MaxEntries))
+            .ToList();
+    }
+
+    public (LogEventLevel Default, LogEventLevel CodeSearch) GetLevels()
+    {
+        return (RuntimeLogLevel.Default.MinimumLevel, RuntimeLogLevel.CodeSearch.MinimumLevel);
+    }
+
+    public void SetLevel(LogEventLevel level, bool codeSearchOnly)
+    {
+        var (previousDefault, previousCodeSearch) = GetLevels();
+
+        RuntimeLogLevel.CodeSearch.MinimumLevel = level;
+        if (!codeSearchOnly)
+        {
+            RuntimeLogLevel.Default.MinimumLevel = level;
+        }
+
+        // Logged at Warning so the change is visible regardless of the new level
+        _logger.LogWarning("Log level changed at runtime - Default: {PreviousDefault} → {Default}, CodeSearch: {PreviousCodeSearch} → {CodeSearch}",
+            previousDefault, RuntimeLogLevel.Default.MinimumLevel, previousCodeSearch, RuntimeLogLevel.CodeSearch.MinimumLevel);
+    }
+}
@@ -0,0 +1,97 @@
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Ambient resource counters for the current tool invocation: documents scanned, cache hits and misses, bytes read.
+/// Services record into them wherever the work is done; the counters flow with the async call chain like
+/// <see cref="CorrelationContext"/>, so work outside a tool invocation is not counted.
+/// </summary>
+public static class QueryUsage
+{
+    private static readonly AsyncLocal<QueryUsageCounters?> _current = new();
+
+    /// <summary>
+    /// Counters of the current invocation, if any
+    /// </summary>
+    public static QueryUsageCounters? Current => _current.Value;
+
+    /// <summary>
+    /// Starts counting for a tool invocation. The counters end with the enclosing async operation.
+    /// </summary>
+    public static QueryUsageCounters Begin()
+    {
+        var counters = new QueryUsageCounters();
+        _current.Value = counters;
+        return counters;
+    }
+
+    /// <summary>
+    /// Records documents (index documents, files or database rows of file content) a query examined
+    /// </summary>
+    public static void RecordDocuments(long documents, long bytes = 0)
+    {
+        if (_current.Value is { } counters)
+        {
+            Interlocked.Add(ref counters.DocumentsScanned, documents);
+            Interlocked.Add(ref counters.BytesRead, bytes);
+        }
+    }
+
+    /// <summary>
+    /// Records bytes read from disk or the index outside a document scan
+    /// </summary>
+    public static void RecordBytes(long bytes)
+    {
+        if (_current.Value is { } counters)
+            Interlocked.Add(ref counters.BytesRead, bytes);
+    }
+
+    /// <summary>
+    /// Records a cache lookup
+    /// </summary>
+    public static void RecordCacheLookup(bool hit)
+    {
+        if (_current.Value is { } counters)
+        {
+            if (hit)
+                Interlocked.Increment(ref counters.CacheHits);
+            else
+                Interlocked.Increment(ref counters.CacheMisses);
+        }
+    }
+}
+
+/// <summary>
+/// Mutable counters shared by everything serving one tool invocation; updated with <see cref="Interlocked"/>
+/// because parallel work within an invocation records into the same instance
+/// </summary>
+public sealed class QueryUsageCounters
+{
+    internal long DocumentsScanned;
+    internal long CacheHits;
+    internal long CacheMisses;
+    internal long BytesRead;
+
+    /// <summary>
+    /// The counters as they are now, with the invocation's elapsed time
+    /// </summary>
+    public QueryUsageReport ToReport(long elapsedMs) => new()
+    {
+        ElapsedMs = elapsedMs,
+        DocumentsScanned = Interlocked.Read(ref DocumentsScanned),
+        CacheHits = Interlocked.Read(ref CacheHits),
+        CacheMisses = Interlocked.Read(ref CacheMisses),
+        BytesRead = Interlocked.Read(ref BytesRead)
+    };
+}
+
+/// <summary>
+/// Resource usage of one tool invocation, returned as response metadata
+/// </summary>
+public class QueryUsageReport
+{
+    public long ElapsedMs { get; set; }
+    public long DocumentsScanned { get; set; }
+    public long CacheHits { get; set; }
+    public long CacheMisses { get; set; }
+    public long BytesRead { get; set; }
+}
@@ -0,0 +1,75 @@
+using System.Reflection;
+using COA.Mcp.Framework.Pipeline;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Stamps the resource usage of each tool invocation (<see cref="QueryUsage"/>) on its response as
+/// meta.resourceUsage, so operators of a shared deployment can see which agent behaviors are expensive.
+/// Opt-in via CodeSearch:QueryUsage:Enabled.
+/// </summary>
+public class QueryUsageMiddleware : SimpleMiddlewareBase
+{
+    public const string MetaKey = "resourceUsage";
+
+    private readonly ILogger<QueryUsageMiddleware> _logger;
+    private readonly bool _enabled;
+
+    public QueryUsageMiddleware(IConfiguration configuration, ILogger<QueryUsageMiddleware> logger)
+    {
+        _logger = logger;
+        _enabled = configuration.GetValue("CodeSearch:QueryUsage:Enabled", false);
+
+        // Run last, after any other middleware has finished with the result
+        Order = int.MaxValue;
+    }
+
+    public override Task OnAfterExecutionAsync(string toolName, object? parameters, object? result, long elapsedMs)
+    {
+        if (!_enabled || QueryUsage.Current is not { } counters)
+            return Task.CompletedTask;
+
+        var meta = GetMeta(result);
+        if (meta == null)
+            return Task.CompletedTask;
+
+        meta.ExtensionData ??= new Dictionary<string, object>();
+
+        // Responses served from the response cache are flagged by the tool rather than counted by a service
+        if (meta.ExtensionData.TryGetValue("cacheHit", out var cacheHit) && cacheHit is true)
+            QueryUsage.RecordCacheLookup(hit: true);
+
+        var report = counters.ToReport(elapsedMs);
+        meta.ExtensionData[MetaKey] = report;
+
+        _logger.LogDebug("{ToolName} took {ElapsedMs}ms: {Documents} document(s) scanned, {Bytes} byte(s) read, {CacheHits} cache hit(s), {CacheMisses} miss(es)",
+            toolName, report.ElapsedMs, report.DocumentsScanned, report.BytesRead, report.CacheHits, report.CacheMisses);
+        return Task.CompletedTask;
+    }
+
+    /// <summary>
+    /// The AIResponseMeta of a tool result, created when the response has none; null for results without one
+    /// </summary>
+    private static AIResponseMeta? GetMeta(object? result)
+    {
+        // Tool results are AIOptimizedResponse<T> for many T - find the meta property rather than the type
+        var property = result?.GetType()
+            .GetProperties(BindingFlags.Public | BindingFlags.Instance)
+            .FirstOrDefault(p => p.Name == "Meta" && p.PropertyType == typeof(AIResponseMeta));
+        if (property == null)
+            return null;
+
+        if (property.GetValue(result) is AIResponseMeta meta)
+            return meta;
+
+        if (!property.CanWrite)
+            return null;
+
+        meta = new AIResponseMeta();
+        property.SetValue(result, meta);
+        return meta;
+    }
+}
@@ -0,0 +1,65 @@
+using Microsoft.Extensions.Configuration;
+using Serilog.Core;
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Services.Logging;
+
+/// <summary>
+/// Level switches shared by the Serilog pipeline and the set_log_level tool.
+/// Static because Serilog is configured before the DI container exists.
+/// </summary>
+public static class RuntimeLogLevel
+{
+    /// <summary>
+    /// Source context prefix for CodeSearch's own loggers
+    /// </summary>
+    public const string CodeSearchSource = "COA.CodeSearch";
+
+    /// <summary>
+    /// Minimum level for everything not covered by a more specific switch
+    /// </summary>
+    public static LoggingLevelSwitch Default { get; } = new(LogEventLevel.Information);
+
+    /// <summary>
+    /// Minimum level for COA.CodeSearch.* loggers
+    /// </summary>
+    public static LoggingLevelSwitch CodeSearch { get; } = new(LogEventLevel.Information);
+
+    /// <summary>
+    /// Seeds the switches from Serilog:MinimumLevel so appsettings.json remains the starting point
+    /// </summary>
+    public static void Initialize(IConfiguration configuration)
+    {
+        if (TryParse(configuration["Serilog:MinimumLevel:Default"], out var defaultLevel))
+        {
+            Default.MinimumLevel = defaultLevel;
+        }
+
+        if (TryParse(configuration[$"Serilog:MinimumLevel:Override:{CodeSearchSource}"], out var codeSearchLevel))
+        {
+            CodeSearch.MinimumLevel = codeSearchLevel;
+        }
+    }
+
+    /// <summary>
+    /// Parses a level name, accepting both Serilog (Verbose, Fatal) and Microsoft (Trace, Critical) spellings
+    /// </summary>
+    public static bool TryParse(string? value, out LogEventLevel level)
+    {
+        level = LogEventLevel.Information;
+        if (string.IsNullOrWhiteSpace(value))
+            return false;
+
+        switch (value.Trim().ToLowerInvariant())
+        {
+            case "trace":
+                level = LogEventLevel.Verbose;
+                return true;
+            case "critical":
+                level = LogEventLevel.Fatal;
+                return true;
+            default:
+                return Enum.TryParse(value.Trim(), ignoreCase: true, out level);
+        }
+    }
+}
@@ -0,0 +1,221 @@
+using System.Runtime.CompilerServices;
+using Lucene.Net.Index;
+using Lucene.Net.Store;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Lucene;
+
+/// <summary>
+/// Persists a Lucene index as encrypted files, one per index file, under <c>sealed/</c> with random names, and an
+/// encrypted manifest mapping index file names to them. When encryption at rest is enabled the live index is held
+/// in a RAMDirectory and only sealed files ever touch disk, so no plaintext segment files exist in the cache directory.
+/// Index files are written once, so a commit seals only the files it added and drops those merged away.
+/// </summary>
+internal static class EncryptedIndexSnapshot
+{
+    public const string ManifestFileName = "manifest.cse";
+    public const string SealedDirectoryName = "sealed";
+
+    // The single-file snapshot written before index files were sealed one by one; migrated on load
+    private const string LegacySnapshotFileName = "index.cse";
+    private const string WriteLockFileName = "write.lock";
+
+    // Sealed file of each index file, per directory, as last persisted. A directory that was not loaded from the
+    // sealed files (a new or rebuilt index) starts with none, so it never reuses a sealed file of the same name.
+    private static readonly ConditionalWeakTable<global::Lucene.Net.Store.Directory, Dictionary<string, string>> Persisted = new();
+
+    /// <summary>
+    /// Gets the manifest path for a Lucene index directory
+    /// </summary>
+    public static string GetManifestPath(string indexPath) => Path.Combine(indexPath, ManifestFileName);
+
+    /// <summary>
+    /// Checks whether an encrypted index exists for the index directory
+    /// </summary>
+    public static bool Exists(string indexPath) =>
+        File.Exists(GetManifestPath(indexPath)) || File.Exists(Path.Combine(indexPath, LegacySnapshotFileName));
+
+    /// <summary>
+    /// Loads the sealed index into a RAMDirectory. A single-file snapshot, or a plaintext index (encryption was just
+    /// turned on), is migrated to sealed files.
+    /// </summary>
+    public static RAMDirectory Load(string indexPath, IIndexEncryptionService encryption, ILogger logger)
+    {
+        var ramDirectory = new RAMDirectory();
+        var manifestPath = GetManifestPath(indexPath);
+
+        if (File.Exists(manifestPath))
+        {
+            var manifest = ReadManifest(encryption.Decrypt(File.ReadAllBytes(manifestPath)));
+            foreach (var (name, sealedName) in manifest)
+            {
+                var bytes = encryption.Decrypt(File.ReadAllBytes(Path.Combine(indexPath, SealedDirectoryName, sealedName)));
+                using var output = ramDirectory.CreateOutput(name, IOContext.DEFAULT);
+                output.WriteBytes(bytes, 0, bytes.Length);
+            }
+
+            Persisted.AddOrUpdate(ramDirectory, manifest);
+            logger.LogDebug("Loaded encrypted index {Path} ({Files} files)", indexPath, manifest.Count);
+            return ramDirectory;
+        }
+
+        var legacyPath = Path.Combine(indexPath, LegacySnapshotFileName);
+        if (File.Exists(legacyPath))
+        {
+            logger.LogInformation("Migrating encrypted snapshot {Path} to sealed index files", legacyPath);
+            LoadLegacySnapshot(encryption.Decrypt(File.ReadAllBytes(legacyPath)), ramDirectory);
+            Save(ramDirectory, indexPath, encryption);
+            File.Delete(legacyPath);
+            return ramDirectory;
+        }
+
+        if (System.IO.Directory.Exists(indexPath))
+        {
+            using var fsDirectory = FSDirectory.Open(indexPath, new SimpleFSLockFactory(indexPath));
+            if (DirectoryReader.IndexExists(fsDirectory))
+            {
+                logger.LogInformation("Migrating plaintext index at {Path} to encrypted snapshot", indexPath);
+                CopyFiles(fsDirectory, ramDirectory);
+                Save(ramDirectory, indexPath, encryption);
+                DeletePlaintextFiles(indexPath);
+            }
+        }
+
+        return ramDirectory;
+    }
+
+    /// <summary>
+    /// Seals the files of the directory's last commit that were added since it was last persisted, then replaces the
+    /// manifest atomically and deletes sealed files it no longer lists. Files a merge is still writing are not part
+    /// of the commit. A crash before the manifest is replaced leaves the previous commit intact; the sealed files
+    /// written meanwhile are deleted by the next save.
+    /// </summary>
+    public static void Save(global::Lucene.Net.Store.Directory directory, string indexPath, IIndexEncryptionService encryption)
+    {
+        if (!DirectoryReader.IndexExists(directory))
+            return;
+
+        var commit = new SegmentInfos();
+        commit.Read(directory);
+        var sealedPath = Path.Combine(indexPath, SealedDirectoryName);
+        System.IO.Directory.CreateDirectory(sealedPath);
+
+        var persisted = Persisted.TryGetValue(directory, out var previous) ? previous : new Dictionary<string, string>();
+        var manifest = new Dictionary<string, string>(StringComparer.Ordinal);
+        foreach (var file in commit.GetFiles(directory, true))
+        {
+            if (persisted.TryGetValue(file, out var sealedName))
+            {
+                manifest[file] = sealedName;
+                continue;
+            }
+
+            // One file's plaintext and ciphertext are in memory at a time, never the whole index
+            using var input = directory.OpenInput(file, IOContext.READ_ONCE);
+            var bytes = new byte[input.Length];
+            input.ReadBytes(bytes, 0, bytes.Length);
+
+            sealedName = Guid.NewGuid().ToString("N") + ".cse";
+            File.WriteAllBytes(Path.Combine(sealedPath, sealedName), encryption.Encrypt(bytes));
+            manifest[file] = sealedName;
+        }
+
+        var manifestPath = GetManifestPath(indexPath);
+        var tempPath = manifestPath + ".tmp";
+        File.WriteAllBytes(tempPath, encryption.Encrypt(WriteManifest(manifest)));
+        File.Move(tempPath, manifestPath, overwrite: true);
+        Persisted.AddOrUpdate(directory, manifest);
+
+        var live = manifest.Values.ToHashSet(StringComparer.Ordinal);
+        foreach (var sealedFile in System.IO.Directory.GetFiles(sealedPath))
+        {
+            if (live.Contains(Path.GetFileName(sealedFile)))
+                continue;
+
+            try
+            {
+                File.Delete(sealedFile);
+            }
+            catch (IOException)
+            {
+                // Best effort - it is not in the manifest, so the next save retries
+            }
+        }
+    }
+
+    private static byte[] WriteManifest(Dictionary<string, string> manifest)
+    {
+        using var stream = new MemoryStream();
+        using (var writer = new BinaryWriter(stream, System.Text.Encoding.UTF8, leaveOpen: true))
+        {
+            writer.Write(manifest.Count);
+            foreach (var (name, sealedName) in manifest)
+            {
+                writer.Write(name);
+                writer.Write(sealedName);
+            }
+        }
+        return stream.ToArray();
+    }
+
+    private static Dictionary<string, string> ReadManifest(byte[] plaintext)
+    {
+        using var stream = new MemoryStream(plaintext);
+        using var reader = new BinaryReader(stream);
+
+        var manifest = new Dictionary<string, string>(StringComparer.Ordinal);
+        var fileCount = reader.ReadInt32();
+        for (int i = 0; i < fileCount; i++)
+        {
+            var name = reader.ReadString();
+            manifest[name] = reader.ReadString();
+        }
+        return manifest;
+    }
+
+    private static void LoadLegacySnapshot(byte[] plaintext, RAMDirectory ramDirectory)
+    {
+        using var stream = new MemoryStream(plaintext);
+        using var reader = new BinaryReader(stream);
+
+        var fileCount = reader.ReadInt32();
+        for (int i = 0; i < fileCount; i++)
+        {
+            var name = reader.ReadString();
+            var length = reader.ReadInt32();
+            var bytes = reader.ReadBytes(length);
+
+            using var output = ramDirectory.CreateOutput(name, IOContext.DEFAULT);
+            output.WriteBytes(bytes, 0, bytes.Length);
+        }
+    }
+
+    private static void CopyFiles(global::Lucene.Net.Store.Directory source, global::Lucene.Net.Store.Directory target)
+    {
+        foreach (var file in source.ListAll())
+        {
+            if (string.Equals(file, WriteLockFileName, StringComparison.Ordinal))
+                continue;
+
+            source.Copy(target, file, file, IOContext.DEFAULT);
+        }
+    }
+
+    private static void DeletePlaintextFiles(string indexPath)
+    {
+        foreach (var file in System.IO.Directory.GetFiles(indexPath))
+        {
+            if (string.Equals(Path.GetFileName(file), ManifestFileName, StringComparison.Ordinal))
+                continue;
+
+            try
+            {
+                File.Delete(file);
+            }
+            catch (IOException)
+            {
+                // Best effort - a locked file will be cleaned up on the next migration attempt
+            }
+        }
+    }
+}
@@ -74,6 +74,11 @@ public interface ILuceneIndexService
     /// </summary>
     Task<IndexStatistics> GetStatisticsAsync(string workspacePath, CancellationToken cancellationToken = default);
     
+    /// <summary>
+    /// List the file documents in the index with their size and tier, without loading content
+    /// </summary>
+    Task<IReadOnlyList<IndexedFile>> GetIndexedFilesAsync(string workspacePath, CancellationToken cancellationToken = default);
+    
     /// <summary>
     /// Repair a corrupted index
     /// </summary>
@@ -83,4 +88,43 @@ public interface ILuceneIndexService
     /// Optimize an index for better performance
     /// </summary>
     Task<bool> OptimizeIndexAsync(string workspacePath, int maxSegments = 1, CancellationToken cancellationToken = default);
-}
\ No newline at end of file
+    
+    /// <summary>
+    /// Check whether an index is currently open in this process
+    /// </summary>
+    bool IsIndexOpen(string workspaceHash);
+    
+    /// <summary>
+    /// Commit and close an open index so its files can be moved or deleted. Returns false if it was not open.
+    /// </summary>
+    Task<bool> CloseIndexAsync(string workspaceHash, CancellationToken cancellationToken = default);
+    
+    /// <summary>
+    /// Get what crash or corruption recovery did when the index was last opened, if anything
+    /// </summary>
+    IndexRecoveryReport? GetRecoveryReport(string workspacePath);
+    
+    /// <summary>
+    /// Take the files whose changes were never committed by the previous run so they can be re-indexed.
+    /// Returns each set once.
+    /// </summary>
+    IReadOnlyList<string> TakeInterruptedWrites(string workspacePath);
+    
+    /// <summary>
+    /// Stop serving the snapshot kept during a rebuild (the previous-format index, or the index as it was before a
+    /// force rebuild) once a full pass has refilled the workspace, and delete a previous-format index. Returns false
+    /// when there was none.
+    /// </summary>
+    Task<bool> RetirePreviousFormatAsync(string workspacePath, CancellationToken cancellationToken = default);
+    
+    /// <summary>
+    /// Record file changes that could not be indexed (e.g. at shutdown) so they are replayed on the next open.
+    /// Returns false for index types without a write journal.
+    /// </summary>
+    bool JournalPendingWrites(string workspacePath, IEnumerable<string> filePaths);
+    
+    /// <summary>
+    /// Commit every open index with uncommitted changes. Returns the number of indexes committed.
+    /// </summary>
+    Task<int> CommitAllAsync(CancellationToken cancellationToken = default);
+}
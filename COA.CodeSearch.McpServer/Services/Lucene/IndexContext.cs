@@ -1,3 +1,4 @@
+using System.Diagnostics.CodeAnalysis;
 using Lucene.Net.Index;
 using Lucene.Net.Search;
 using LuceneDirectory = Lucene.Net.Store.Directory;
@@ -9,22 +10,30 @@ internal class IndexContext : IDisposable
     private readonly SemaphoreSlim _lock = new(1, 1);
     private readonly object _readerLock = new object();
     private IndexWriter? _writer;
-    private DirectoryReader? _reader;
-    private IndexSearcher? _searcher;
+    private SearcherManager? _searcherManager;
+    private PinnedSnapshot? _pinned;
     private DateTime _lastAccess;
     private DateTime _lastReaderUpdate;
     private bool _disposed;
+    private bool _directoryDetached;
+    private int _leases;
+    private bool _closing;
+    private readonly TaskCompletionSource _drained = new(TaskCreationOptions.RunContinuationsAsynchronously);
     private long _lastCommitGeneration;
     private long _refreshVersion; // increments each refresh
 
-    private readonly TimeSpan _maxReaderAge = TimeSpan.FromSeconds(30);
-    private readonly bool _enableAutoRefresh = true;
+    private readonly TimeSpan _maxReaderAge;
 
     public string WorkspacePath { get; }
     public string WorkspaceHash { get; }
     public string IndexPath { get; }
     public LuceneDirectory Directory { get; }
 
+    /// <summary>
+    /// Write-ahead journal of uncommitted paths; null for RAM and encrypted indexes
+    /// </summary>
+    public IndexWriteJournal? Journal { get; set; }
+
     public IndexWriter? Writer
     {
         get => _writer;
@@ -35,142 +44,286 @@ internal class IndexContext : IDisposable
         }
     }
 
-    public DirectoryReader? Reader
+    /// <summary>
+    /// Whether searches are served from a pinned snapshot (a previous-format index, or the index as it was before
+    /// a force rebuild) instead of the writer's reader, until <see cref="RetireSnapshot"/>
+    /// </summary>
+    public bool IsServingSnapshot
     {
-        get => _reader;
-        set
+        get
         {
-            _reader = value;
-            _lastAccess = DateTime.UtcNow;
+            lock (_readerLock)
+            {
+                return _pinned != null;
+            }
         }
     }
 
     public DateTime LastAccess => _lastAccess;
     public SemaphoreSlim Lock => _lock;
 
-    public IndexContext(string workspacePath, string workspaceHash, string indexPath, LuceneDirectory directory)
+    public IndexContext(string workspacePath, string workspaceHash, string indexPath, LuceneDirectory directory, TimeSpan? maxReaderAge = null)
     {
         WorkspacePath = workspacePath;
         WorkspaceHash = workspaceHash;
         IndexPath = indexPath;
         Directory = directory;
+        _maxReaderAge = maxReaderAge ?? TimeSpan.FromSeconds(30);
         _lastAccess = DateTime.UtcNow;
     }
 
     /// <summary>
-    /// Standard cached searcher (may reuse existing).
+    /// Leases a searcher without taking <see cref="Lock"/>, so queries run alongside writes and commits. The
+    /// reader is pooled and shared by concurrent queries; it is reopened near-real-time from the writer when
+    /// documents were added since, or when it is older than the max reader age. A reopen already running on
+    /// another thread is not waited for: the current reader is served meanwhile. The reader stays open until
+    /// the lease is disposed, even if a refresh or retirement replaces it, and the context is not disposed
+    /// before then (see <see cref="DrainAsync"/>). Returns false once the context is closing.
     /// </summary>
-    public IndexSearcher GetSearcher(IndexWriter writer)
+    public bool TryAcquireSearcher([NotNullWhen(true)] out SearcherLease? lease)
     {
         lock (_readerLock)
         {
-            var now = DateTime.UtcNow;
-            var shouldRefresh = false;
-
-            if (_reader == null)
+            if (_closing)
             {
-                _reader = writer.GetReader(applyAllDeletes: true);
-                _searcher = new IndexSearcher(_reader);
-                _lastReaderUpdate = now;
-                _lastCommitGeneration = writer.MaxDoc;
-                _refreshVersion++;
+                lease = null;
+                return false;
             }
-            else
+            _leases++;
+        }
+
+        try
+        {
+            var reader = AcquireReader();
+            lease = new SearcherLease(reader.Searcher, () =>
             {
-                var currentGeneration = writer.MaxDoc;
-                var isStale = _enableAutoRefresh &&
-                              (now - _lastReaderUpdate > _maxReaderAge ||
-                               currentGeneration > _lastCommitGeneration);
+                reader.Dispose();
+                ReleaseLease();
+            });
+            return true;
+        }
+        catch
+        {
+            ReleaseLease();
+            throw;
+        }
+    }
 
-                if (isStale)
-                {
-                    shouldRefresh = true;
-                }
+    /// <summary>
+    /// Stops leasing searchers so the context can be disposed. Completes once the last query holding a lease
+    /// has finished.
+    /// </summary>
+    public Task DrainAsync()
+    {
+        lock (_readerLock)
+        {
+            _closing = true;
+            if (_leases == 0)
+            {
+                _drained.TrySetResult();
             }
+        }
+        return _drained.Task;
+    }
 
-            if (shouldRefresh)
+    private void ReleaseLease()
+    {
+        lock (_readerLock)
+        {
+            if (--_leases == 0 && _closing)
             {
-                // Use writer-aware OpenIfChanged to ensure NRT reopen (important)
-                var newReader = DirectoryReader.OpenIfChanged(_reader, writer, applyAllDeletes: true);
-                if (newReader != null)
-                {
-                    _reader.Dispose();
-                    _reader = newReader;
-                    _searcher = new IndexSearcher(_reader);
-                    _lastReaderUpdate = now;
-                    _lastCommitGeneration = writer.MaxDoc;
-                    _refreshVersion++;
-                }
+                _drained.TrySetResult();
+            }
+        }
+    }
+
+    private SearcherLease AcquireReader()
+    {
+        _lastAccess = DateTime.UtcNow;
+
+        lock (_readerLock)
+        {
+            if (_pinned != null)
+            {
+                return _pinned.Acquire();
             }
+        }
 
-            _lastAccess = now;
-            return _searcher ?? new IndexSearcher(_reader!);
+        var manager = GetSearcherManager();
+        var writer = _writer;
+        if (writer != null && (writer.MaxDoc != _lastCommitGeneration || DateTime.UtcNow - _lastReaderUpdate > _maxReaderAge))
+        {
+            manager.MaybeRefresh();
         }
+
+        var searcher = manager.Acquire();
+        return new SearcherLease(searcher, () => manager.Release(searcher));
     }
 
     /// <summary>
-    /// Always reopen via writer (force fresh NRT view).
+    /// Reopens the pooled reader after a commit so the next query sees it without paying for the reopen.
+    /// Queries holding the previous reader finish on it.
     /// </summary>
-    public IndexSearcher GetFreshSearcher(IndexWriter writer)
+    public void RefreshSearcher()
     {
+        SearcherManager? manager;
         lock (_readerLock)
         {
-            if (_reader != null)
+            manager = _searcherManager;
+        }
+
+        // Nothing to refresh until the first query opens a reader
+        manager?.MaybeRefreshBlocking();
+    }
+
+    /// <summary>
+    /// Serves a read-only index of the previous format until the current one is rebuilt. The directory is
+    /// disposed once the snapshot is retired and the last query on it finishes.
+    /// </summary>
+    public void ServePreviousFormat(LuceneDirectory directory)
+    {
+        PinSnapshot(new PinnedSnapshot(DirectoryReader.Open(directory), directory));
+    }
+
+    /// <summary>
+    /// Takes a point-in-time view of this index that outlives the context, so its replacement can keep serving
+    /// it (see <see cref="PinSnapshot"/>). The snapshot takes over <see cref="Directory"/>, which this context
+    /// then leaves open when disposed; the snapshot closes it after its last query. A snapshot already pinned
+    /// here is handed over as is. Returns null when nothing was indexed yet.
+    /// </summary>
+    public PinnedSnapshot? DetachSnapshot()
+    {
+        lock (_readerLock)
+        {
+            if (_pinned != null)
             {
-                _reader.Dispose();
-                _reader = null;
+                var pinned = _pinned;
+                _pinned = null;
+                return pinned;
             }
-            _reader = writer.GetReader(applyAllDeletes: true);
-            _searcher = new IndexSearcher(_reader);
-            _lastReaderUpdate = DateTime.UtcNow;
-            _lastCommitGeneration = writer.MaxDoc;
-            _refreshVersion++;
-            return _searcher;
         }
+
+        if (_writer == null || _writer.NumDocs == 0)
+        {
+            return null;
+        }
+
+        var snapshot = new PinnedSnapshot(DirectoryReader.Open(_writer, applyAllDeletes: true), Directory);
+        _directoryDetached = true;
+        return snapshot;
     }
 
-    public void InvalidateReader()
+    /// <summary>
+    /// Serves the snapshot to every query instead of the writer's reader, taking ownership of it
+    /// </summary>
+    public void PinSnapshot(PinnedSnapshot snapshot)
     {
+        PinnedSnapshot? replaced;
         lock (_readerLock)
         {
-            _reader?.Dispose();
-            _reader = null;
-            _searcher = null;
-            _lastReaderUpdate = DateTime.MinValue;
+            replaced = _pinned;
+            _pinned = snapshot;
         }
+        replaced?.Retire();
     }
 
-    public void ForceReaderRefresh()
+    /// <summary>
+    /// Switches queries back to the writer's reader. Queries still running on the snapshot finish on it.
+    /// </summary>
+    public bool RetireSnapshot()
     {
+        PinnedSnapshot? retired;
         lock (_readerLock)
         {
-            if (_writer == null) return;
-            _reader?.Dispose();
-            _reader = _writer.GetReader(applyAllDeletes: true);
-            _searcher = new IndexSearcher(_reader);
-            _lastReaderUpdate = DateTime.UtcNow;
-            _lastCommitGeneration = _writer.MaxDoc;
-            _refreshVersion++;
+            retired = _pinned;
+            _pinned = null;
         }
+
+        retired?.Retire();
+        return retired != null;
     }
 
     public bool ShouldEvict(TimeSpan inactivityThreshold) =>
         DateTime.UtcNow - _lastAccess > inactivityThreshold;
 
     public ReaderStats GetReaderStats()
+    {
+        using var lease = AcquireSearcherIfOpen();
+        var reader = lease?.Searcher.IndexReader;
+        return new ReaderStats
+        {
+            HasReader = reader != null,
+            LastUpdate = _lastReaderUpdate,
+            Generation = _lastCommitGeneration,
+            Age = _lastReaderUpdate == DateTime.MinValue ? TimeSpan.MaxValue : DateTime.UtcNow - _lastReaderUpdate,
+            Version = _refreshVersion,
+            ReaderVersion = lease?.Version ?? -1,
+            ReaderMaxDoc = reader?.MaxDoc ?? -1,
+            ReaderNumDocs = reader?.NumDocs ?? -1,
+            ServingSnapshot = IsServingSnapshot
+        };
+    }
+
+    private SearcherLease? AcquireSearcherIfOpen()
     {
         lock (_readerLock)
         {
-            return new ReaderStats
+            if (_pinned == null && _searcherManager == null)
             {
-                HasReader = _reader != null,
-                LastUpdate = _lastReaderUpdate,
-                Generation = _lastCommitGeneration,
-                Age = _lastReaderUpdate == DateTime.MinValue ? TimeSpan.MaxValue : DateTime.UtcNow - _lastReaderUpdate,
-                Version = _refreshVersion,
-                ReaderMaxDoc = _reader?.MaxDoc ?? -1,
-                ReaderNumDocs = _reader?.NumDocs ?? -1
-            };
+                return null;
+            }
+        }
+        return TryAcquireSearcher(out var lease) ? lease : null;
+    }
+
+    private SearcherManager GetSearcherManager()
+    {
+        lock (_readerLock)
+        {
+            if (_searcherManager != null)
+            {
+                return _searcherManager;
+            }
+
+            var writer = _writer ?? throw new InvalidOperationException($"No writer available for workspace {WorkspacePath}");
+            _searcherManager = new SearcherManager(writer, true, null);
+            _searcherManager.AddListener(new RefreshListener(this, writer));
+            MarkRefreshed(writer);
+            return _searcherManager;
+        }
+    }
+
+    private void MarkRefreshed(IndexWriter writer)
+    {
+        _lastReaderUpdate = DateTime.UtcNow;
+        _lastCommitGeneration = writer.MaxDoc;
+        Interlocked.Increment(ref _refreshVersion);
+    }
+
+    private sealed class RefreshListener : ReferenceManager.IRefreshListener
+    {
+        private readonly IndexContext _context;
+        private readonly IndexWriter _writer;
+
+        public RefreshListener(IndexContext context, IndexWriter writer)
+        {
+            _context = context;
+            _writer = writer;
+        }
+
+        public void BeforeRefresh()
+        {
+        }
+
+        public void AfterRefresh(bool didRefresh)
+        {
+            // Checked against the writer either way; the version only moves when the reader changed
+            _context._lastReaderUpdate = DateTime.UtcNow;
+            _context._lastCommitGeneration = _writer.MaxDoc;
+            if (didRefresh)
+            {
+                Interlocked.Increment(ref _context._refreshVersion);
+            }
         }
     }
 
@@ -178,12 +331,14 @@ internal class IndexContext : IDisposable
     {
         if (_disposed) return;
         
-        // First, dispose the reader and searcher
+        // First, stop leasing and close the pooled reader and any snapshot; leased readers close when their
+        // queries finish
+        _ = DrainAsync();
+        RetireSnapshot();
         lock (_readerLock)
         {
-            _reader?.Dispose();
-            _reader = null;
-            _searcher = null;
+            _searcherManager?.Dispose();
+            _searcherManager = null;
         }
         
         // Then, properly close the writer with commit
@@ -195,6 +350,7 @@ internal class IndexContext : IDisposable
                 if (_writer.HasUncommittedChanges())
                 {
                     _writer.Commit();
+                    Journal?.Clear();
                 }
                 _writer.Dispose();
             }
@@ -209,13 +365,82 @@ internal class IndexContext : IDisposable
             }
         }
         
-        // Finally, dispose the directory and lock
-        Directory?.Dispose();
+        // Finally, dispose the journal, directory (unless a detached snapshot owns it) and lock
+        Journal?.Dispose();
+        if (!_directoryDetached)
+        {
+            Directory?.Dispose();
+        }
         _lock.Dispose();
         _disposed = true;
     }
 }
 
+/// <summary>
+/// A searcher leased for one query. Disposing it hands the reader back to the pool.
+/// </summary>
+internal sealed class SearcherLease : IDisposable
+{
+    private Action? _release;
+
+    public SearcherLease(IndexSearcher searcher, Action release)
+    {
+        Searcher = searcher;
+        _release = release;
+    }
+
+    public IndexSearcher Searcher { get; }
+
+    /// <summary>
+    /// Version of the index the reader sees; increases with every commit or reopen that changed it
+    /// </summary>
+    public long Version => Searcher.IndexReader is DirectoryReader reader ? reader.Version : -1;
+
+    public void Dispose()
+    {
+        Interlocked.Exchange(ref _release, null)?.Invoke();
+    }
+}
+
+/// <summary>
+/// A reader served in place of the writer's, ref-counted so that retiring it never closes it under a running
+/// query: the last lease to be released closes the reader and, when owned, its directory.
+/// </summary>
+internal sealed class PinnedSnapshot
+{
+    private readonly DirectoryReader _reader;
+    private readonly IndexSearcher _searcher;
+    private readonly LuceneDirectory? _directory;
+    private int _closed;
+
+    public PinnedSnapshot(DirectoryReader reader, LuceneDirectory? directory)
+    {
+        _reader = reader;
+        _searcher = new IndexSearcher(reader);
+        _directory = directory;
+    }
+
+    public SearcherLease Acquire()
+    {
+        _reader.IncRef();
+        return new SearcherLease(_searcher, Release);
+    }
+
+    /// <summary>
+    /// Drops the reference taken when the snapshot was pinned
+    /// </summary>
+    public void Retire() => Release();
+
+    private void Release()
+    {
+        _reader.DecRef();
+        if (_reader.RefCount == 0 && Interlocked.Exchange(ref _closed, 1) == 0)
+        {
+            _directory?.Dispose();
+        }
+    }
+}
+
 public class ReaderStats
 {
     public bool HasReader { get; set; }
@@ -223,6 +448,8 @@ public class ReaderStats
     public long Generation { get; set; }
     public TimeSpan Age { get; set; }
     public long Version { get; set; }
+    public long ReaderVersion { get; set; }
     public int ReaderMaxDoc { get; set; }
     public int ReaderNumDocs { get; set; }
+    public bool ServingSnapshot { get; set; }
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+using Lucene.Net.Index;
+using Lucene.Net.Store;
+
+namespace COA.CodeSearch.McpServer.Services.Lucene;
+
+/// <summary>
+/// Version of the document schema the server writes, stamped into the commit data of every index.
+/// Bump <see cref="Current"/> whenever fields or analysis change in a way that needs a re-index; the release
+/// that does must still be able to search documents written by <see cref="Previous"/>, because such indexes
+/// are served read-only while the rebuild runs. Indexes written before stamping began count as version 1.
+/// </summary>
+public static class IndexFormat
+{
+    public const int Current = 1;
+    public const int Previous = Current - 1;
+    public const string CommitDataKey = "codesearch.format";
+
+    public static IDictionary<string, string> CommitData => new Dictionary<string, string>
+    {
+        [CommitDataKey] = Current.ToString()
+    };
+
+    /// <summary>
+    /// Format of the last commit in an index directory; null when there is no committed index
+    /// </summary>
+    public static int? ReadVersion(string indexPath)
+    {
+        if (!System.IO.Directory.Exists(indexPath))
+        {
+            return null;
+        }
+
+        using var directory = FSDirectory.Open(indexPath, new SimpleFSLockFactory(indexPath));
+        if (!DirectoryReader.IndexExists(directory))
+        {
+            return null;
+        }
+
+        var infos = new SegmentInfos();
+        infos.Read(directory);
+        return infos.UserData.TryGetValue(CommitDataKey, out var value) && int.TryParse(value, out var version)
+            ? version
+            : 1;
+    }
+
+    /// <summary>
+    /// Where an index of another format is moved while the current format is rebuilt beside it
+    /// </summary>
+    public static string GetSetAsidePath(string luceneIndexPath, int version) =>
+        $"{Path.TrimEndingDirectorySeparator(luceneIndexPath)}.v{version}";
+}
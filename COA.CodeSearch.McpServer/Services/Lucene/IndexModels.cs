@@ -16,6 +16,79 @@ public class IndexInitResult
     public bool IsNewIndex { get; set; }
     public int ExistingDocumentCount { get; set; }
     public string? ErrorMessage { get; set; }
+    
+    /// <summary>
+    /// Set when opening the index required crash or corruption recovery
+    /// </summary>
+    public IndexRecoveryReport? Recovery { get; set; }
+}
+
+/// <summary>
+/// What startup recovery found and did for an index
+/// </summary>
+public class IndexRecoveryReport
+{
+    public enum RecoveryAction { ReplayPending, Repaired, Rebuilt, FormatUpgrade, FormatReset }
+    
+    public string WorkspacePath { get; set; } = string.Empty;
+    public DateTime DetectedAt { get; set; }
+    public RecoveryAction Action { get; set; }
+    
+    /// <summary>
+    /// Why the index could not be opened, if it was corrupt
+    /// </summary>
+    public string? Corruption { get; set; }
+    
+    /// <summary>
+    /// Files changed after the last commit of the previous run (from the write journal)
+    /// </summary>
+    public List<string> InterruptedFiles { get; set; } = new();
+    
+    public int LostDocuments { get; set; }
+    
+    /// <summary>
+    /// A full index_workspace pass was stopped (e.g. by shutdown) before it finished
+    /// </summary>
+    public bool InterruptedFullIndex { get; set; }
+    
+    /// <summary>
+    /// Where the unreadable index was moved before a fresh one was created
+    /// </summary>
+    public string? QuarantinePath { get; set; }
+    
+    /// <summary>
+    /// Format version found on disk, for FormatUpgrade and FormatReset
+    /// </summary>
+    public int? FormatVersion { get; set; }
+    
+    /// <summary>
+    /// Where the index of the other format was moved; for FormatUpgrade it keeps serving searches until the rebuild finishes
+    /// </summary>
+    public string? SetAsidePath { get; set; }
+    
+    /// <summary>
+    /// Set once a full re-index has restored the content lost to recovery
+    /// </summary>
+    public bool Resolved { get; set; }
+    
+    /// <summary>
+    /// Documents were dropped (or the index recreated empty) and a full index_workspace run is needed
+    /// </summary>
+    public bool RequiresFullReindex => !Resolved &&
+        (Action is RecoveryAction.Rebuilt or RecoveryAction.FormatUpgrade or RecoveryAction.FormatReset || LostDocuments > 0 || InterruptedFullIndex);
+    
+    public string Describe()
+    {
+        return Action switch
+        {
+            RecoveryAction.Rebuilt => $"Index was unreadable ({Corruption}) and was recreated; the old copy was moved to {QuarantinePath}",
+            RecoveryAction.Repaired => $"Index was corrupted ({Corruption}) and was repaired, dropping {LostDocuments} document(s)",
+            RecoveryAction.FormatUpgrade => $"Index uses format v{FormatVersion} (current v{IndexFormat.Current}); searches use it read-only while a v{IndexFormat.Current} index is rebuilt",
+            RecoveryAction.FormatReset => $"Index uses unsupported format v{FormatVersion} (current v{IndexFormat.Current}); it was moved to {SetAsidePath} and a new index is being built",
+            _ when InterruptedFullIndex => "The previous full index was interrupted before it finished and will be resumed",
+            _ => $"{InterruptedFiles.Count} file change(s) were not committed before the previous shutdown and will be re-indexed"
+        };
+    }
 }
 
 /// <summary>
@@ -28,6 +101,72 @@ public class SearchResult
     public TimeSpan SearchTime { get; set; }
     public string? Query { get; set; }
     public long ProcessingTimeMs => (long)SearchTime.TotalMilliseconds;
+
+    /// <summary>
+    /// Set when re-ranking via MCP sampling was requested; Hits are then in re-ranked order
+    /// </summary>
+    public SearchReranking? Reranking { get; set; }
+
+    /// <summary>
+    /// Set in sample mode; Hits and TotalHits then cover only the sampled documents
+    /// </summary>
+    public SampleEstimate? Sample { get; set; }
+}
+
+/// <summary>
+/// Match count extrapolated from a random sample of the index, with a confidence interval
+/// </summary>
+public class SampleEstimate
+{
+    public double SampleRate { get; set; }
+    public int TotalDocuments { get; set; }
+    public int SampledDocuments { get; set; }
+    public int MatchesInSample { get; set; }
+
+    /// <summary>
+    /// Estimated number of matching files in the whole index
+    /// </summary>
+    public int EstimatedMatches { get; set; }
+
+    public int LowerBound { get; set; }
+    public int UpperBound { get; set; }
+    public double ConfidenceLevel { get; set; }
+}
+
+/// <summary>
+/// Raw and re-ranked order of the candidates sent to the client's LLM
+/// </summary>
+public class SearchReranking
+{
+    public bool Applied { get; set; }
+    public string? Model { get; set; }
+
+    /// <summary>
+    /// Why the raw order was kept (sampling unavailable, refused, unparseable answer)
+    /// </summary>
+    public string? FallbackReason { get; set; }
+
+    public List<RankedHit> RawOrder { get; set; } = new();
+    public List<RankedHit> RerankedOrder { get; set; } = new();
+
+    /// <summary>
+    /// Candidates the model judged irrelevant; removed from Hits
+    /// </summary>
+    public List<RankedHit> Filtered { get; set; } = new();
+}
+
+public class RankedHit
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int? LineNumber { get; set; }
+    public float Score { get; set; }
+
+    /// <summary>
+    /// 1-based position in the raw Lucene order
+    /// </summary>
+    public int RawRank { get; set; }
+
+    public string? Reason { get; set; }
 }
 
 /// <summary>
@@ -54,11 +193,52 @@ public class SearchHit
     public List<string>? ContextLines { get; set; }
     public int? StartLine { get; set; }
     public int? EndLine { get; set; }
+
+    /// <summary>
+    /// 0-based column of the match on LineNumber/StartLine in UTF-8 bytes, when known exactly
+    /// </summary>
+    public int? Column { get; set; }
+
+    /// <summary>
+    /// The same column in UTF-16 code units, as LSP clients count it
+    /// </summary>
+    public int? Utf16Column { get; set; }
+
+    /// <summary>
+    /// UTF-8 byte offset of the match from the start of the file, when known
+    /// </summary>
+    public int? ByteOffset { get; set; }
     
     
     // Type information from Tree-sitter extraction
     public TypeContext? TypeContext { get; set; }
 
+    /// <summary>
+    /// Structural summary stored at index time, so hits can be triaged without opening the file
+    /// </summary>
+    public FileSummary? Summary { get; set; }
+
+    /// <summary>
+    /// ContextLines split into classified segments (snippetFormat: classified), one list per line
+    /// </summary>
+    public List<List<SnippetSegment>>? ClassifiedLines { get; set; }
+
+    /// <summary>
+    /// True when the file lies in a vendored or third-party directory (dependency code)
+    /// </summary>
+    public bool? DependencyCode { get; set; }
+
+    /// <summary>
+    /// For Jupyter notebooks: 1-based position of the cell holding the match; line numbers of a notebook count
+    /// the lines of its code cells, a blank line between cells
+    /// </summary>
+    public int? Cell { get; set; }
+
+    /// <summary>
+    /// For Jupyter notebooks: 1-based line of the match within <see cref="Cell"/>
+    /// </summary>
+    public int? CellLine { get; set; }
+
     // Helper properties for common fields
     public string? FileName => Fields.GetValueOrDefault("filename");
     public string? RelativePath => Fields.GetValueOrDefault("relativePath");
@@ -76,6 +256,61 @@ public class TypeContext
     public string? Language { get; set; }
 }
 
+/// <summary>
+/// A run of snippet text with its lexical class, for client-side syntax highlighting
+/// </summary>
+public class SnippetSegment
+{
+    public string Text { get; set; } = string.Empty;
+
+    /// <summary>
+    /// One of <see cref="SnippetTokenKinds"/>
+    /// </summary>
+    public string Kind { get; set; } = SnippetTokenKinds.Text;
+}
+
+/// <summary>
+/// Segment kinds produced by SnippetHighlighter
+/// </summary>
+public static class SnippetTokenKinds
+{
+    public const string Keyword = "keyword";
+    public const string Type = "type";
+    public const string Function = "function";
+    public const string Identifier = "identifier";
+    public const string String = "string";
+    public const string Number = "number";
+    public const string Comment = "comment";
+    public const string Operator = "operator";
+    public const string Punctuation = "punctuation";
+    public const string Text = "text";
+}
+
+/// <summary>
+/// Compact structural summary of a file, built by FileSummarizer during indexing
+/// </summary>
+public class FileSummary
+{
+    /// <summary>
+    /// One sentence from the main type's doc comment or the file header, otherwise inferred from the symbols
+    /// </summary>
+    public string? Purpose { get; set; }
+
+    /// <summary>
+    /// Main types ("class UserService : BaseService"), or functions when the file declares no types
+    /// </summary>
+    public List<string> KeySymbols { get; set; } = new();
+
+    /// <summary>
+    /// Imported modules in source order, capped; see <see cref="DependencyCount"/> for the total
+    /// </summary>
+    public List<string> Dependencies { get; set; } = new();
+
+    public int DependencyCount { get; set; }
+    public int TypeCount { get; set; }
+    public int MemberCount { get; set; }
+}
+
 /// <summary>
 /// Index health status
 /// </summary>
@@ -93,6 +328,16 @@ public class IndexHealthStatus
     public List<string> Issues { get; set; } = new();
 }
 
+/// <summary>
+/// A file document in the index, as read for size budgeting
+/// </summary>
+public class IndexedFile
+{
+    public string FilePath { get; set; } = string.Empty;
+    public long SizeBytes { get; set; }
+    public bool IsCold { get; set; }
+}
+
 /// <summary>
 /// Index statistics
 /// </summary>
@@ -103,6 +348,12 @@ public class IndexStatistics
     public int DocumentCount { get; set; }
     public int DeletedDocumentCount { get; set; }
     public long IndexSizeBytes { get; set; }
+    
+    /// <summary>
+    /// Bytes in stored-field files (.fdt/.fdx) - the part StoredFieldCompression affects
+    /// </summary>
+    public long StoredFieldsBytes { get; set; }
+    public string StoredFieldCompression { get; set; } = string.Empty;
     public int SegmentCount { get; set; }
     public DateTime CreatedAt { get; set; }
     public DateTime LastModified { get; set; }
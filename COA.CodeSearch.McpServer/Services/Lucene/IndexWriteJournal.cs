@@ -0,0 +1,166 @@
+using System.Text;
+
+namespace COA.CodeSearch.McpServer.Services.Lucene;
+
+/// <summary>
+/// Write-ahead journal of file paths changed since the last Lucene commit.
+/// Lucene commits are already atomic (segments_N is written last), so a crash can only lose work
+/// that was buffered but not yet committed. Each path is appended and flushed to disk before the
+/// writer sees the change and the journal is truncated after a successful commit; anything left in
+/// the journal on startup was never committed and must be re-indexed.
+/// </summary>
+public sealed class IndexWriteJournal : IDisposable
+{
+    public const string JournalFileName = "write-journal.log";
+    public const string IncompleteMarkerFileName = "full-index.incomplete";
+
+    private readonly object _sync = new();
+    private readonly FileStream _stream;
+    private bool _disposed;
+
+    public string JournalPath { get; }
+
+    public IndexWriteJournal(string journalPath)
+    {
+        JournalPath = journalPath;
+        var directory = Path.GetDirectoryName(journalPath);
+        if (!string.IsNullOrEmpty(directory))
+        {
+            System.IO.Directory.CreateDirectory(directory);
+        }
+
+        // Append to any entries left by a previous crash - they stay pending until the next commit
+        _stream = new FileStream(journalPath, FileMode.Append, FileAccess.Write, FileShare.Read);
+    }
+
+    /// <summary>
+    /// Gets the journal path for a Lucene index directory. The journal sits beside the index
+    /// rather than inside it so Lucene's file deleter never touches it.
+    /// </summary>
+    public static string GetJournalPath(string luceneIndexPath)
+    {
+        var parent = Path.GetDirectoryName(Path.TrimEndingDirectorySeparator(luceneIndexPath)) ?? luceneIndexPath;
+        return Path.Combine(parent, JournalFileName);
+    }
+
+    /// <summary>
+    /// Marks a full workspace pass as started. If the marker is still present on the next start,
+    /// the pass was interrupted and the index only holds part of the workspace.
+    /// </summary>
+    public static void MarkFullIndexStarted(string luceneIndexPath)
+    {
+        var markerPath = GetIncompleteMarkerPath(luceneIndexPath);
+        System.IO.Directory.CreateDirectory(Path.GetDirectoryName(markerPath)!);
+        File.WriteAllText(markerPath, DateTime.UtcNow.ToString("O"));
+    }
+
+    /// <summary>
+    /// Clears the marker written by <see cref="MarkFullIndexStarted"/>
+    /// </summary>
+    public static void MarkFullIndexCompleted(string luceneIndexPath)
+    {
+        var markerPath = GetIncompleteMarkerPath(luceneIndexPath);
+        if (File.Exists(markerPath))
+        {
+            File.Delete(markerPath);
+        }
+    }
+
+    public static bool IsFullIndexIncomplete(string luceneIndexPath) => File.Exists(GetIncompleteMarkerPath(luceneIndexPath));
+
+    private static string GetIncompleteMarkerPath(string luceneIndexPath)
+    {
+        return Path.Combine(Path.GetDirectoryName(GetJournalPath(luceneIndexPath))!, IncompleteMarkerFileName);
+    }
+
+    /// <summary>
+    /// Records paths about to be written and forces them to disk
+    /// </summary>
+    public void Append(IEnumerable<string> filePaths)
+    {
+        var builder = new StringBuilder();
+        foreach (var path in filePaths)
+        {
+            if (!string.IsNullOrEmpty(path))
+            {
+                builder.Append(path).Append('\n');
+            }
+        }
+
+        if (builder.Length == 0)
+            return;
+
+        var bytes = Encoding.UTF8.GetBytes(builder.ToString());
+        lock (_sync)
+        {
+            ThrowIfDisposed();
+            _stream.Write(bytes, 0, bytes.Length);
+            _stream.Flush(flushToDisk: true);
+        }
+    }
+
+    /// <summary>
+    /// Truncates the journal once its entries are covered by a durable commit
+    /// </summary>
+    public void Clear()
+    {
+        lock (_sync)
+        {
+            ThrowIfDisposed();
+            if (_stream.Length == 0)
+                return;
+
+            _stream.SetLength(0);
+            _stream.Flush(flushToDisk: true);
+        }
+    }
+
+    /// <summary>
+    /// Reads uncommitted paths left by a previous run, de-duplicated in journal order.
+    /// A torn final line from a crash mid-append is ignored.
+    /// </summary>
+    public static IReadOnlyList<string> ReadPending(string journalPath)
+    {
+        if (!File.Exists(journalPath))
+            return Array.Empty<string>();
+
+        string content;
+        using (var stream = new FileStream(journalPath, FileMode.Open, FileAccess.Read, FileShare.ReadWrite))
+        using (var reader = new StreamReader(stream, Encoding.UTF8))
+        {
+            content = reader.ReadToEnd();
+        }
+
+        var lines = content.Split('\n');
+        // The last element is either empty (clean newline) or a partially written entry
+        var complete = lines.Take(lines.Length - 1);
+
+        var seen = new HashSet<string>(StringComparer.Ordinal);
+        var pending = new List<string>();
+        foreach (var line in complete)
+        {
+            if (line.Length > 0 && seen.Add(line))
+            {
+                pending.Add(line);
+            }
+        }
+
+        return pending;
+    }
+
+    private void ThrowIfDisposed()
+    {
+        if (_disposed)
+            throw new ObjectDisposedException(nameof(IndexWriteJournal));
+    }
+
+    public void Dispose()
+    {
+        lock (_sync)
+        {
+            if (_disposed) return;
+            _disposed = true;
+            _stream.Dispose();
+        }
+    }
+}
@@ -1,4 +1,5 @@
 using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Logging;
 using Lucene.Net.Analysis;
 using Lucene.Net.Documents;
 using Lucene.Net.Index;
@@ -35,13 +36,30 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
     private readonly SmartSnippetService _snippetService;
     private readonly CodeAnalyzer _codeAnalyzer;
     private readonly IWriteLockManager _writeLockManager;
+    private readonly IIndexEncryptionService? _encryption;
+    private readonly NetworkFileSystem.INetworkFileSystemService? _networkFileSystem;
     private readonly ConcurrentDictionary<string, IndexContext> _indexes = new();
+    private readonly ConcurrentDictionary<string, IndexRecoveryReport> _recoveryReports = new();
+    private readonly ConcurrentDictionary<string, IReadOnlyList<string>> _pendingReplays = new();
     private readonly SemaphoreSlim _globalLock = new(1, 1);
     private readonly Timer _cleanupTimer;
     private bool _disposed;
     
     // Configuration
     private readonly bool _useRamDirectory;
+    private readonly bool _encryptAtRest;
+    private readonly bool _verifyOnOpen;
+    private readonly bool _autoRecover;
+    private readonly bool _servePreviousFormat;
+    private readonly TimeSpan _maxReaderAge;
+    private readonly string _storedFieldCompression;
+    private readonly global::Lucene.Net.Codecs.Codec? _storedFieldCodec;
+    
+    static LuceneIndexService()
+    {
+        // Segments written with high compression must be readable whatever the current setting
+        StoredFieldCompression.EnsureRegistered();
+    }
     private readonly TimeSpan _inactivityThreshold;
     private readonly int _maxConcurrentIndexes;
     
@@ -54,7 +72,9 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         LineAwareSearchService lineAwareSearchService,
         SmartSnippetService snippetService,
         IWriteLockManager writeLockManager,
-        CodeAnalyzer codeAnalyzer)
+        CodeAnalyzer codeAnalyzer,
+        IIndexEncryptionService? encryption = null,
+        NetworkFileSystem.INetworkFileSystemService? networkFileSystem = null)
     {
         _logger = logger;
         _configuration = configuration;
@@ -65,17 +85,35 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         _snippetService = snippetService;
         _writeLockManager = writeLockManager;
         _codeAnalyzer = codeAnalyzer;
+        _encryption = encryption;
+        _networkFileSystem = networkFileSystem;
         
         // Load configuration
         _useRamDirectory = configuration.GetValue("CodeSearch:Lucene:UseRamDirectory", false);
+        // Encrypted indexes live in RAM and are persisted as sealed index files (see EncryptedIndexSnapshot)
+        _encryptAtRest = !_useRamDirectory && _encryption?.IsEnabled == true;
         _inactivityThreshold = TimeSpan.FromMinutes(configuration.GetValue("CodeSearch:Lucene:InactivityThresholdMinutes", 30));
         _maxConcurrentIndexes = configuration.GetValue("CodeSearch:Lucene:MaxConcurrentIndexes", 10);
+        _verifyOnOpen = configuration.GetValue("CodeSearch:Lucene:Recovery:VerifyOnOpen", true);
+        _autoRecover = configuration.GetValue("CodeSearch:Lucene:Recovery:AutoRecover", true);
+        _servePreviousFormat = configuration.GetValue("CodeSearch:Lucene:Compatibility:ServePreviousFormat", true);
+        _maxReaderAge = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Lucene:Searcher:MaxReaderAgeSeconds", 30));
+        _storedFieldCompression = configuration.GetValue("CodeSearch:Lucene:StoredFieldCompression", StoredFieldCompression.Lz4)!;
+        try
+        {
+            _storedFieldCodec = StoredFieldCompression.CreateCodec(_storedFieldCompression);
+        }
+        catch (ArgumentException ex)
+        {
+            _logger.LogWarning("{Message} Falling back to {Default}.", ex.Message, StoredFieldCompression.Lz4);
+            _storedFieldCompression = StoredFieldCompression.Lz4;
+        }
         
         // Start cleanup timer for inactive indexes
         _cleanupTimer = new Timer(CleanupInactiveIndexes, null, _inactivityThreshold, _inactivityThreshold);
         
-        _logger.LogInformation("LuceneIndexService initialized - UseRam: {UseRam}, MaxIndexes: {MaxIndexes}", 
-            _useRamDirectory, _maxConcurrentIndexes);
+        _logger.LogInformation("LuceneIndexService initialized - UseRam: {UseRam}, Encrypted: {Encrypted}, MaxIndexes: {MaxIndexes}, StoredFieldCompression: {Compression}", 
+            _useRamDirectory, _encryptAtRest, _maxConcurrentIndexes, _storedFieldCompression);
     }
     
     public async Task<IndexInitResult> InitializeIndexAsync(string workspacePath, CancellationToken cancellationToken = default)
@@ -85,17 +123,42 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         // Check if already initialized
         if (_indexes.ContainsKey(workspaceHash))
         {
-            var existingContext = _indexes[workspaceHash];
-            return new IndexInitResult
-            {
-                Success = true,
-                WorkspaceHash = workspaceHash,
-                IndexPath = existingContext.IndexPath,
-                IsNewIndex = false,
-                ExistingDocumentCount = await GetDocumentCountAsync(workspacePath, cancellationToken)
-            };
+            return await ExistingIndexResultAsync(workspacePath, workspaceHash, cancellationToken);
         }
         
+        // Contexts are opened, closed and replaced (force rebuild) under the global lock, so a query arriving
+        // while a context is replaced waits for the new one instead of opening the index alongside it
+        await _globalLock.WaitAsync(cancellationToken);
+        try
+        {
+            if (_indexes.ContainsKey(workspaceHash))
+            {
+                return await ExistingIndexResultAsync(workspacePath, workspaceHash, cancellationToken);
+            }
+            
+            return await OpenIndexAsync(workspacePath, workspaceHash, cancellationToken);
+        }
+        finally
+        {
+            _globalLock.Release();
+        }
+    }
+    
+    private async Task<IndexInitResult> ExistingIndexResultAsync(string workspacePath, string workspaceHash, CancellationToken cancellationToken)
+    {
+        var existingContext = _indexes[workspaceHash];
+        return new IndexInitResult
+        {
+            Success = true,
+            WorkspaceHash = workspaceHash,
+            IndexPath = existingContext.IndexPath,
+            IsNewIndex = false,
+            ExistingDocumentCount = await GetDocumentCountAsync(workspacePath, cancellationToken)
+        };
+    }
+    
+    private async Task<IndexInitResult> OpenIndexAsync(string workspacePath, string workspaceHash, CancellationToken cancellationToken)
+    {
         // Ensure we don't exceed max concurrent indexes
         await EnforceMaxIndexesAsync(cancellationToken);
         
@@ -105,6 +168,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
             {
                 var indexPath = _pathResolution.GetLuceneIndexPath(workspacePath);
                 var isNewIndex = !System.IO.Directory.Exists(indexPath) || !System.IO.Directory.GetFiles(indexPath).Any();
+                IndexRecoveryReport? recovery = null;
                 
                 // Create directory with explicit SimpleFSLockFactory for cross-platform consistency
                 global::Lucene.Net.Store.Directory directory;
@@ -112,17 +176,31 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 {
                     directory = new RAMDirectory();
                 }
+                else if (_encryptAtRest)
+                {
+                    directory = EncryptedIndexSnapshot.Load(indexPath, _encryption!, _logger);
+                    isNewIndex = !EncryptedIndexSnapshot.Exists(indexPath);
+                }
                 else
                 {
+                    // Detect crash leftovers and corruption before handing the directory to a writer
+                    recovery = RecoverIndexIfNeeded(workspacePath, indexPath);
+                    
+                    // An index of another format is moved aside; a full pass rebuilds it in the current format
+                    recovery = PrepareIndexFormat(workspacePath, indexPath) ?? recovery;
+                    
                     // Use SimpleFSLockFactory for consistent cross-platform behavior
                     // This avoids platform-specific issues with NativeFSLockFactory
                     var lockFactory = new SimpleFSLockFactory(indexPath);
-                    directory = FSDirectory.Open(indexPath, lockFactory);
-                    _logger.LogDebug("Created FSDirectory with SimpleFSLockFactory for {Path}", indexPath);
+                    directory = OpenIndexDirectory(indexPath, lockFactory);
+                    _logger.LogDebug("Created {Directory} with SimpleFSLockFactory for {Path}", directory.GetType().Name, indexPath);
+                    
+                    // Segment files from a crash before the first commit don't make an index
+                    isNewIndex = !DirectoryReader.IndexExists(directory);
                 }
                 
                 // Create context
-                var context = new IndexContext(workspacePath, workspaceHash, indexPath, directory);
+                var context = new IndexContext(workspacePath, workspaceHash, indexPath, directory, _maxReaderAge);
                 
                 try
                 {
@@ -153,6 +231,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     
                     // Configure merge scheduler
                     config.MaxThreadStates = maxThreadStates;
+                    ApplyStoredFieldCodec(config);
                     
                     // Try to create IndexWriter with lock recovery
                     try
@@ -177,6 +256,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                                 MaxBufferedDocs = maxBufferedDocs,
                                 MaxThreadStates = maxThreadStates
                             };
+                            ApplyStoredFieldCodec(retryConfig);
 
                             // Reapply merge policy if configured
                             if (mergePolicyType == "TieredMergePolicy")
@@ -197,6 +277,18 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                         }
                     }
                     
+                    StampFormat(context.Writer);
+                    
+                    if (!_useRamDirectory && !_encryptAtRest)
+                    {
+                        context.Journal = new IndexWriteJournal(IndexWriteJournal.GetJournalPath(indexPath));
+                    }
+                    
+                    if (recovery?.Action == IndexRecoveryReport.RecoveryAction.FormatUpgrade)
+                    {
+                        ServePreviousFormat(context, recovery);
+                    }
+                    
                     // Add to dictionary
                     if (!_indexes.TryAdd(workspaceHash, context))
                     {
@@ -217,13 +309,23 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 _logger.LogInformation("Initialized index for workspace {Hash} - New: {IsNew}, Docs: {DocCount}", 
                     workspaceHash, isNewIndex, docCount);
                 
+                if (recovery != null)
+                {
+                    _recoveryReports[workspaceHash] = recovery;
+                    if (!recovery.RequiresFullReindex && recovery.InterruptedFiles.Count > 0)
+                    {
+                        _pendingReplays[workspaceHash] = recovery.InterruptedFiles;
+                    }
+                }
+                
                 return new IndexInitResult
                 {
                     Success = true,
                     WorkspaceHash = workspaceHash,
                     IndexPath = indexPath,
                     IsNewIndex = isNewIndex,
-                    ExistingDocumentCount = docCount
+                    ExistingDocumentCount = docCount,
+                    Recovery = recovery
                 };
             }, cancellationToken);
         }
@@ -256,7 +358,12 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 throw new InvalidOperationException($"No writer available for workspace {workspacePath}");
             }
             
-            foreach (var doc in documents)
+            var batch = documents as IList<Document> ?? documents.ToList();
+            
+            // Write-ahead: record the paths before the writer buffers them
+            context.Journal?.Append(batch.Select(d => d.Get("path")).OfType<string>());
+            
+            foreach (var doc in batch)
             {
                 // Check for file path to enable updates
                 var pathField = doc.GetField("path");
@@ -302,6 +409,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 throw new InvalidOperationException($"No writer available for workspace {workspacePath}");
             }
             
+            context.Journal?.Append(new[] { filePath });
             context.Writer.DeleteDocuments(new Term("path", filePath));
             _logger.LogDebug("Deleted document {Path} from index", filePath);
         }
@@ -318,18 +426,13 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
 
     public async Task<SearchResult> SearchAsync(string workspacePath, Query query, int maxResults, bool includeSnippets, CancellationToken cancellationToken = default)
     {
-        var context = await GetOrCreateContextAsync(workspacePath, cancellationToken);
         var stopwatch = Stopwatch.StartNew();
         
-        await context.Lock.WaitAsync(TimeSpan.FromSeconds(LOCK_TIMEOUT_SECONDS), cancellationToken);
-        try
+        // Searches lease the pooled reader instead of taking the context lock, so they never wait on commits.
+        // While a format upgrade or force rebuild runs, the lease is on the index as it was before.
+        using (var lease = await AcquireSearcherAsync(workspacePath, cancellationToken))
         {
-            if (context.Writer == null)
-            {
-                throw new InvalidOperationException($"No writer available for workspace {workspacePath}");
-            }
-            
-            var searcher = context.GetSearcher(context.Writer);
+            var searcher = lease.Searcher;
             
             // Perform search
             var topDocs = searcher.Search(query, maxResults);
@@ -340,11 +443,18 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 var scoreDoc = topDocs.ScoreDocs[i];
                 var doc = searcher.Doc(scoreDoc.Doc);
                 
+                // Cold-tier documents keep no text; materialize it from disk for line numbers and snippets
+                if (doc.Get("tier") == "cold")
+                {
+                    MaterializeColdContent(doc);
+                }
+                
                 var hit = new SearchHit
                 {
                     FilePath = doc.Get("path") ?? string.Empty,
                     Score = scoreDoc.Score,
                     DocId = scoreDoc.Doc, // Lucene document ID for efficient retrieval
+                    DependencyCode = doc.Get("origin") == "dependency" ? true : null,
                     // Content included in Fields for LineSearchTool
                     Fields = new Dictionary<string, string>()
                 };
@@ -376,6 +486,21 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     }
                 }
                 
+                // File summary is surfaced as a typed property rather than a raw field
+                hit.Fields.Remove("file_summary");
+                var summaryJson = doc.Get("file_summary");
+                if (!string.IsNullOrEmpty(summaryJson))
+                {
+                    try
+                    {
+                        hit.Summary = JsonSerializer.Deserialize<FileSummary>(summaryJson);
+                    }
+                    catch (JsonException ex)
+                    {
+                        _logger.LogDebug(ex, "Failed to deserialize file_summary for {FilePath}", hit.FilePath);
+                    }
+                }
+
                 // Parse modified date if available
                 if (hit.Fields.TryGetValue("modified", out var modifiedTicks) && 
                     long.TryParse(modifiedTicks, out var ticks))
@@ -409,6 +534,15 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     hit.EndLine = lineResult.Context.EndLine;
                 }
                 
+                // Lines of a notebook are lines of its code cells run together; say which cell they are in
+                var notebookCells = JupyterNotebook.ParseCells(doc.Get(JupyterNotebook.CellsField));
+                if (notebookCells.Count > 0 && lineResult.LineNumber is { } notebookLine
+                    && JupyterNotebook.Locate(notebookCells, notebookLine) is { } cellLocation)
+                {
+                    hit.Cell = cellLocation.Cell;
+                    hit.CellLine = cellLocation.Line;
+                }
+                
                 // Add debug info to fields
                 hit.Fields["line_accurate"] = lineResult.IsAccurate.ToString().ToLowerInvariant();
                 hit.Fields["line_from_cache"] = lineResult.IsFromCache.ToString().ToLowerInvariant();
@@ -462,6 +596,10 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
             
             stopwatch.Stop();
             
+            // Every matching document was scored; the stored text was loaded for the returned ones
+            QueryUsage.RecordDocuments(topDocs.TotalHits,
+                hits.Sum(h => h.Fields.TryGetValue("content", out var content) ? (long)System.Text.Encoding.UTF8.GetByteCount(content) : 0));
+            
             var searchResult = new SearchResult
             {
                 TotalHits = topDocs.TotalHits,
@@ -483,10 +621,6 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
 
             return searchResult;
         }
-        finally
-        {
-            context.Lock.Release();
-        }
     }
     
     public async Task<int> GetDocumentCountAsync(string workspacePath, CancellationToken cancellationToken = default)
@@ -518,6 +652,9 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
             
             context.Writer.DeleteAll();
             context.Writer.Commit();
+            context.Journal?.Clear();
+            PersistEncryptedSnapshot(context);
+            context.RefreshSearcher();
             
             _logger.LogInformation("Cleared all documents from index for workspace {Path}", workspacePath);
         }
@@ -573,22 +710,34 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         {
             _logger.LogInformation("Starting force rebuild for workspace {Path}", workspacePath);
             
-            // Step 1: Close and dispose existing context if it exists
+            // Step 1: Close and dispose existing context if it exists, keeping a snapshot of it to search
+            // until the full pass has refilled the index, instead of serving an empty one meanwhile
+            PinnedSnapshot? snapshot = null;
             if (_indexes.TryGetValue(workspaceHash, out var existingContext))
             {
-                await DisposeContextAsync(existingContext);
+                try
+                {
+                    snapshot = existingContext.DetachSnapshot();
+                }
+                catch (Exception ex)
+                {
+                    _logger.LogWarning(ex, "Could not keep a snapshot of {Path} during force rebuild - searches see the rebuilt index as it fills", workspacePath);
+                }
+                
+                // Queries arriving from here on wait on the global lock for the rebuilt context
                 _indexes.TryRemove(workspaceHash, out _);
+                await DisposeContextAsync(existingContext);
                 _logger.LogDebug("Disposed existing index context for force rebuild");
             }
             
             // Step 2: Create new IndexWriter with OpenMode.CREATE to rebuild schema
             var indexPath = _pathResolution.GetLuceneIndexPath(workspacePath);
-            var directory = _useRamDirectory
+            var directory = _useRamDirectory || _encryptAtRest
                 ? new RAMDirectory() as global::Lucene.Net.Store.Directory
-                : FSDirectory.Open(indexPath, new SimpleFSLockFactory(indexPath));
+                : OpenIndexDirectory(indexPath, new SimpleFSLockFactory(indexPath));
             
             // Create new context
-            var context = new IndexContext(workspacePath, workspaceHash, indexPath, directory);
+            var context = new IndexContext(workspacePath, workspaceHash, indexPath, directory, _maxReaderAge);
             
             try
             {
@@ -619,6 +768,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 }
                 
                 config.MaxThreadStates = maxThreadStates;
+                ApplyStoredFieldCodec(config);
                 
                 // Create new IndexWriter with CREATE mode and lock recovery
                 try
@@ -642,18 +792,35 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     }
                 }
                 
+                StampFormat(context.Writer);
+                
+                // A CREATE-mode rebuild supersedes anything left in the journal
+                if (!_useRamDirectory && !_encryptAtRest)
+                {
+                    context.Journal = new IndexWriteJournal(IndexWriteJournal.GetJournalPath(indexPath));
+                    context.Journal.Clear();
+                }
+                _pendingReplays.TryRemove(workspaceHash, out _);
+                
                 // Step 3: Register the new context
                 if (!_indexes.TryAdd(workspaceHash, context))
                 {
                     context.Dispose();
                     throw new InvalidOperationException($"Failed to add rebuilt index context for workspace {workspaceHash}");
                 }
+                
+                if (snapshot != null)
+                {
+                    context.PinSnapshot(snapshot);
+                    snapshot = null;
+                }
             }
             catch
             {
                 // Critical: Ensure context is disposed if IndexWriter creation fails
                 // This prevents lock files from being left orphaned
                 context.Dispose();
+                snapshot?.Retire();
                 throw;
             }
             
@@ -678,18 +845,13 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
             }
             
             context.Writer.Commit();
+            context.Journal?.Clear();
+            PersistEncryptedSnapshot(context);
             
-            // CRITICAL: Invalidate the cached reader after commit to ensure NRT visibility
-            context.InvalidateReader();
-            
-            // Optional: Force refresh immediately if we expect searches soon
-            // This trades memory for latency by eagerly creating the new reader
-            if (_configuration.GetValue("CodeSearch:Lucene:EagerReaderRefresh", false))
-            {
-                context.ForceReaderRefresh();
-            }
+            // Reopen the pooled reader now rather than on the next query; queries already running keep theirs
+            context.RefreshSearcher();
             
-            _logger.LogDebug("Committed changes to index for workspace {Path}, reader invalidated", workspacePath);
+            _logger.LogDebug("Committed changes to index for workspace {Path}, searcher refreshed", workspacePath);
         }
         finally
         {
@@ -707,6 +869,11 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         }
         
         var indexPath = _pathResolution.GetLuceneIndexPath(workspacePath);
+        if (_encryptAtRest && EncryptedIndexSnapshot.Exists(indexPath))
+        {
+            return true;
+        }
+        
         return await Task.FromResult(System.IO.Directory.Exists(indexPath) && 
                                      System.IO.Directory.GetFiles(indexPath).Any());
     }
@@ -744,7 +911,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 var dirInfo = new DirectoryInfo(indexPath);
                 var sizeBytes = dirInfo.Exists ? dirInfo.GetFiles("*", SearchOption.AllDirectories).Sum(f => f.Length) : 0;
                 
-                return new IndexHealthStatus
+                var health = new IndexHealthStatus
                 {
                     Level = IndexHealthStatus.HealthLevel.Healthy,
                     Description = "Index is healthy",
@@ -752,6 +919,21 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     IndexSizeBytes = sizeBytes,
                     LastModified = dirInfo.LastWriteTimeUtc
                 };
+                
+                // Surface startup recovery so callers can see why results may be incomplete
+                if (_recoveryReports.TryGetValue(workspaceHash, out var recovery))
+                {
+                    health.Issues.Add($"{recovery.DetectedAt:u}: {recovery.Describe()}");
+                    if (recovery.RequiresFullReindex)
+                    {
+                        health.Level = IndexHealthStatus.HealthLevel.Degraded;
+                        health.Description = recovery.Action == IndexRecoveryReport.RecoveryAction.FormatUpgrade
+                            ? $"Serving the previous index format (v{recovery.FormatVersion}) read-only while it is rebuilt"
+                            : "Index was recovered and needs a full re-index";
+                    }
+                }
+                
+                return health;
             }
             finally
             {
@@ -781,6 +963,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         {
             var dirInfo = new DirectoryInfo(indexPath);
             var readerStats = context.GetReaderStats();
+            var files = dirInfo.Exists ? dirInfo.GetFiles("*", SearchOption.AllDirectories) : Array.Empty<FileInfo>();
             
             return new IndexStatistics
             {
@@ -788,7 +971,9 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 WorkspaceHash = workspaceHash,
                 DocumentCount = context.Writer?.NumDocs ?? 0,
                 DeletedDocumentCount = 0, // Not directly available in Lucene.NET 4.8
-                IndexSizeBytes = dirInfo.Exists ? dirInfo.GetFiles("*", SearchOption.AllDirectories).Sum(f => f.Length) : 0,
+                IndexSizeBytes = files.Sum(f => f.Length),
+                StoredFieldsBytes = files.Where(f => f.Extension is ".fdt" or ".fdx").Sum(f => f.Length),
+                StoredFieldCompression = _storedFieldCompression,
                 SegmentCount = 1, // Default for simplicity
                 CreatedAt = dirInfo.CreationTimeUtc,
                 LastModified = dirInfo.LastWriteTimeUtc,
@@ -802,6 +987,36 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         }
     }
     
+    public async Task<IReadOnlyList<IndexedFile>> GetIndexedFilesAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var fieldsToLoad = new HashSet<string> { "path", "size", "tier" };
+        
+        using (var lease = await AcquireSearcherAsync(workspacePath, cancellationToken))
+        {
+            var reader = lease.Searcher.IndexReader;
+            var liveDocs = MultiFields.GetLiveDocs(reader);
+            var files = new List<IndexedFile>(reader.NumDocs);
+            for (var docId = 0; docId < reader.MaxDoc; docId++)
+            {
+                if (liveDocs != null && !liveDocs.Get(docId))
+                    continue;
+                
+                var doc = reader.Document(docId, fieldsToLoad);
+                var path = doc.Get("path");
+                if (string.IsNullOrEmpty(path))
+                    continue;
+                
+                files.Add(new IndexedFile
+                {
+                    FilePath = path,
+                    SizeBytes = doc.GetField("size")?.GetInt64Value() ?? 0,
+                    IsCold = doc.Get("tier") == "cold"
+                });
+            }
+            return files;
+        }
+    }
+    
     /// <summary>
     /// Get detailed reader diagnostics for troubleshooting NRT issues
     /// </summary>
@@ -825,7 +1040,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 ReaderGeneration = readerStats.Generation,
                 WriterGeneration = writerGeneration,
                 IsReaderStale = readerStats.Generation < writerGeneration,
-                RecommendRefresh = readerStats.Age > TimeSpan.FromSeconds(30) || readerStats.Generation < writerGeneration
+                RecommendRefresh = readerStats.Age > _maxReaderAge || readerStats.Generation < writerGeneration
             };
         }
         finally
@@ -834,6 +1049,31 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         }
     }
     
+    public bool IsIndexOpen(string workspaceHash)
+    {
+        return _indexes.ContainsKey(workspaceHash);
+    }
+    
+    public async Task<bool> CloseIndexAsync(string workspaceHash, CancellationToken cancellationToken = default)
+    {
+        await _globalLock.WaitAsync(cancellationToken);
+        try
+        {
+            if (!_indexes.TryRemove(workspaceHash, out var context))
+            {
+                return false;
+            }
+            
+            await DisposeContextAsync(context);
+            _logger.LogInformation("Closed index for workspace {Hash}", workspaceHash);
+            return true;
+        }
+        finally
+        {
+            _globalLock.Release();
+        }
+    }
+    
     private async Task<IndexContext> GetOrCreateContextAsync(string workspacePath, CancellationToken cancellationToken)
     {
         var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
@@ -853,6 +1093,30 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         return _indexes[workspaceHash];
     }
     
+    /// <summary>
+    /// Leases a searcher on a workspace's index. A context stops leasing once it is closed (evicted, or
+    /// replaced by a force rebuild) after leaving <c>_indexes</c>, so a query that looked it up just before
+    /// looks again and gets the context that replaced it.
+    /// </summary>
+    private async Task<SearcherLease> AcquireSearcherAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        var deadline = DateTime.UtcNow.AddSeconds(LOCK_TIMEOUT_SECONDS);
+        while (true)
+        {
+            var context = await GetOrCreateContextAsync(workspacePath, cancellationToken);
+            if (context.TryAcquireSearcher(out var lease))
+            {
+                return lease;
+            }
+            
+            if (_disposed || DateTime.UtcNow > deadline)
+            {
+                throw new InvalidOperationException($"Index for workspace {workspacePath} was closed");
+            }
+            await Task.Delay(10, cancellationToken);
+        }
+    }
+    
     private async Task EnforceMaxIndexesAsync(CancellationToken cancellationToken)
     {
         if (_indexes.Count >= _maxConcurrentIndexes)
@@ -894,10 +1158,29 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         }
     }
     
+    /// <summary>
+    /// Seals the files an encrypted index's commit added. No-op when encryption is disabled.
+    /// </summary>
+    private void PersistEncryptedSnapshot(IndexContext context)
+    {
+        if (!_encryptAtRest)
+            return;
+        
+        EncryptedIndexSnapshot.Save(context.Directory, context.IndexPath, _encryption!);
+        _logger.LogDebug("Persisted encrypted index for workspace {Hash}", context.WorkspaceHash);
+    }
+    
     private async Task DisposeContextAsync(IndexContext context)
     {
         try
         {
+            // Let queries holding a searcher finish before their reader, writer and directory go away
+            var drain = context.DrainAsync();
+            if (await Task.WhenAny(drain, Task.Delay(TimeSpan.FromSeconds(5))) != drain)
+            {
+                _logger.LogWarning("Queries on context {Hash} still running after 5s, disposing it anyway", context.WorkspaceHash);
+            }
+            
             // Use a shorter timeout for disposal to avoid hanging
             var lockAcquired = await context.Lock.WaitAsync(TimeSpan.FromSeconds(5));
             
@@ -922,6 +1205,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                                 if (directoryFiles.Length > 0)
                                 {
                                     context.Writer.Commit();
+                                    context.Journal?.Clear();
                                 }
                                 else
                                 {
@@ -945,6 +1229,7 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                             }
                         }
                         context.Writer.Dispose();
+                        PersistEncryptedSnapshot(context);
                     }
                     catch (global::Lucene.Net.Store.LockObtainFailedException lockEx)
                     {
@@ -982,6 +1267,365 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
         }
     }
     
+    public IndexRecoveryReport? GetRecoveryReport(string workspacePath)
+    {
+        var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
+        return _recoveryReports.TryGetValue(workspaceHash, out var report) ? report : null;
+    }
+    
+    public IReadOnlyList<string> TakeInterruptedWrites(string workspacePath)
+    {
+        var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
+        return _pendingReplays.TryRemove(workspaceHash, out var files) ? files : Array.Empty<string>();
+    }
+    
+    public bool JournalPendingWrites(string workspacePath, IEnumerable<string> filePaths)
+    {
+        if (_useRamDirectory || _encryptAtRest)
+            return false;
+        
+        var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
+        if (_indexes.TryGetValue(workspaceHash, out var context) && context.Journal != null)
+        {
+            context.Journal.Append(filePaths);
+            return true;
+        }
+        
+        // Not open - append straight to the journal file so the next open replays it
+        using var journal = new IndexWriteJournal(IndexWriteJournal.GetJournalPath(_pathResolution.GetLuceneIndexPath(workspacePath)));
+        journal.Append(filePaths);
+        return true;
+    }
+    
+    public async Task<int> CommitAllAsync(CancellationToken cancellationToken = default)
+    {
+        var committed = 0;
+        foreach (var context in _indexes.Values.ToList())
+        {
+            if (!await context.Lock.WaitAsync(TimeSpan.FromSeconds(5), cancellationToken))
+            {
+                _logger.LogWarning("Timed out waiting to commit index {Hash} - uncommitted changes stay in the write journal", context.WorkspaceHash);
+                continue;
+            }
+            
+            try
+            {
+                if (context.Writer?.HasUncommittedChanges() == true)
+                {
+                    context.Writer.Commit();
+                    context.Journal?.Clear();
+                    PersistEncryptedSnapshot(context);
+                    committed++;
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogError(ex, "Failed to commit index {Hash}", context.WorkspaceHash);
+            }
+            finally
+            {
+                context.Lock.Release();
+            }
+        }
+        
+        return committed;
+    }
+    
+    /// <summary>
+    /// The index directory on disk. FSDirectory.Open memory-maps the index on 64-bit platforms, and mapped pages of
+    /// a file on a network share fault or go stale when the share drops or another client writes it, so indexes
+    /// on network file systems are read with positional reads (NIOFSDirectory) instead.
+    /// </summary>
+    private FSDirectory OpenIndexDirectory(string indexPath, LockFactory lockFactory) =>
+        _networkFileSystem?.AppliesTo(indexPath) == true
+            ? new NIOFSDirectory(indexPath, lockFactory)
+            : FSDirectory.Open(indexPath, lockFactory);
+    
+    /// <summary>
+    /// Checks a file-system index before it is opened for writing. Uncommitted paths from the write
+    /// journal are reported for replay; an unreadable index is repaired with CheckIndex, or quarantined
+    /// and recreated empty when repair is not possible, so queries keep working instead of failing.
+    /// </summary>
+    private IndexRecoveryReport? RecoverIndexIfNeeded(string workspacePath, string indexPath)
+    {
+        var journalPath = IndexWriteJournal.GetJournalPath(indexPath);
+        var interrupted = IndexWriteJournal.ReadPending(journalPath);
+        
+        var interruptedFullIndex = IndexWriteJournal.IsFullIndexIncomplete(indexPath);
+        var corruption = _verifyOnOpen && System.IO.Directory.Exists(indexPath)
+            ? ProbeIndex(indexPath)
+            : null;
+        
+        if (corruption == null && interrupted.Count == 0 && !interruptedFullIndex)
+            return null;
+        
+        var report = new IndexRecoveryReport
+        {
+            WorkspacePath = workspacePath,
+            DetectedAt = DateTime.UtcNow,
+            Action = IndexRecoveryReport.RecoveryAction.ReplayPending,
+            InterruptedFiles = interrupted.ToList(),
+            InterruptedFullIndex = interruptedFullIndex,
+            Corruption = corruption
+        };
+        
+        if (corruption != null)
+        {
+            if (!_autoRecover)
+            {
+                throw new InvalidOperationException(
+                    $"Index is corrupted ({corruption}). Run index_workspace with forceRebuild or enable CodeSearch:Lucene:Recovery:AutoRecover.");
+            }
+            
+            _logger.LogWarning("Index for {WorkspacePath} is unreadable: {Corruption} - attempting automatic recovery", workspacePath, corruption);
+            
+            if (TryRepairInPlace(indexPath, out var lostDocuments))
+            {
+                report.Action = IndexRecoveryReport.RecoveryAction.Repaired;
+                report.LostDocuments = lostDocuments;
+            }
+            else
+            {
+                report.Action = IndexRecoveryReport.RecoveryAction.Rebuilt;
+                report.QuarantinePath = QuarantineIndex(indexPath);
+                
+                // A full re-index supersedes the journal
+                if (File.Exists(journalPath))
+                {
+                    File.Delete(journalPath);
+                }
+            }
+        }
+        
+        _logger.LogWarning("Index recovery for {WorkspacePath}: {Description}", workspacePath, report.Describe());
+        return report;
+    }
+    
+    /// <summary>
+    /// Checks the format stamped in the last commit before a writer opens the index. An index of the previous
+    /// format is moved aside and served read-only until a full pass has rebuilt it (<see cref="RetirePreviousFormatAsync"/>);
+    /// any other format, including one written by a newer server, is moved aside and rebuilt without serving it.
+    /// </summary>
+    private IndexRecoveryReport? PrepareIndexFormat(string workspacePath, string indexPath)
+    {
+        // A rebuild started before the last shutdown is still running
+        var previousPath = IndexFormat.GetSetAsidePath(indexPath, IndexFormat.Previous);
+        if (_servePreviousFormat && System.IO.Directory.Exists(previousPath))
+        {
+            return new IndexRecoveryReport
+            {
+                WorkspacePath = workspacePath,
+                DetectedAt = DateTime.UtcNow,
+                Action = IndexRecoveryReport.RecoveryAction.FormatUpgrade,
+                FormatVersion = IndexFormat.Previous,
+                SetAsidePath = previousPath
+            };
+        }
+        
+        int? version;
+        try
+        {
+            version = IndexFormat.ReadVersion(indexPath);
+        }
+        catch (Exception ex) when (ex is CorruptIndexException or IOException)
+        {
+            // Unreadable commits are RecoverIndexIfNeeded's business
+            _logger.LogDebug(ex, "Could not read the index format of {Path}", indexPath);
+            return null;
+        }
+        
+        if (version == null || version == IndexFormat.Current)
+        {
+            return null;
+        }
+        
+        var report = new IndexRecoveryReport
+        {
+            WorkspacePath = workspacePath,
+            DetectedAt = DateTime.UtcNow,
+            Action = _servePreviousFormat && version == IndexFormat.Previous
+                ? IndexRecoveryReport.RecoveryAction.FormatUpgrade
+                : IndexRecoveryReport.RecoveryAction.FormatReset,
+            FormatVersion = version,
+            SetAsidePath = IndexFormat.GetSetAsidePath(indexPath, version.Value)
+        };
+        
+        // Keep only the latest copy of each format
+        if (System.IO.Directory.Exists(report.SetAsidePath))
+        {
+            System.IO.Directory.Delete(report.SetAsidePath, recursive: true);
+        }
+        System.IO.Directory.Move(Path.TrimEndingDirectorySeparator(indexPath), report.SetAsidePath);
+        System.IO.Directory.CreateDirectory(indexPath);
+        
+        // The full pass supersedes the journal
+        var journalPath = IndexWriteJournal.GetJournalPath(indexPath);
+        if (File.Exists(journalPath))
+        {
+            File.Delete(journalPath);
+        }
+        
+        _logger.LogWarning("Index format for {WorkspacePath}: {Description}", workspacePath, report.Describe());
+        return report;
+    }
+    
+    private void ServePreviousFormat(IndexContext context, IndexRecoveryReport report)
+    {
+        try
+        {
+            context.ServePreviousFormat(FSDirectory.Open(report.SetAsidePath!, new SimpleFSLockFactory(report.SetAsidePath!)));
+            _logger.LogInformation("Serving {Path} read-only until the v{Version} index is rebuilt", report.SetAsidePath, IndexFormat.Current);
+        }
+        catch (Exception ex)
+        {
+            // Searches see the new index as it fills instead
+            _logger.LogWarning(ex, "Could not open the previous-format index {Path}", report.SetAsidePath);
+            report.Action = IndexRecoveryReport.RecoveryAction.FormatReset;
+        }
+    }
+    
+    public async Task<bool> RetirePreviousFormatAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
+        var previousPath = IndexFormat.GetSetAsidePath(_pathResolution.GetLuceneIndexPath(workspacePath), IndexFormat.Previous);
+        
+        // Queries still running on the snapshot finish on it; new ones get the rebuilt index
+        var retired = _indexes.TryGetValue(workspaceHash, out var context) && context.RetireSnapshot();
+        
+        if (!System.IO.Directory.Exists(previousPath))
+        {
+            return retired;
+        }
+        
+        try
+        {
+            System.IO.Directory.Delete(previousPath, recursive: true);
+            _logger.LogInformation("Index for {WorkspacePath} rebuilt in format v{Version}; removed {Path}", workspacePath, IndexFormat.Current, previousPath);
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not remove the previous-format index {Path}", previousPath);
+        }
+        return true;
+    }
+    
+    private void MaterializeColdContent(Document doc)
+    {
+        var path = doc.Get("path");
+        try
+        {
+            if (!string.IsNullOrEmpty(path) && File.Exists(path))
+            {
+                var content = File.ReadAllText(path);
+                if (JupyterNotebook.Handles(path) && JupyterNotebook.Parse(content) is { } notebook)
+                {
+                    content = notebook.Text;
+                }
+                doc.Add(new StoredField("content", content));
+            }
+        }
+        catch (IOException ex)
+        {
+            _logger.LogDebug(ex, "Could not read cold-tier file {FilePath}", path);
+        }
+    }
+    
+    private void ApplyStoredFieldCodec(IndexWriterConfig config)
+    {
+        if (_storedFieldCodec != null)
+        {
+            config.Codec = _storedFieldCodec;
+        }
+    }
+    
+    private static void StampFormat(IndexWriter writer)
+    {
+        if (!writer.CommitData.TryGetValue(IndexFormat.CommitDataKey, out var stamped) || stamped != IndexFormat.Current.ToString())
+        {
+            writer.SetCommitData(IndexFormat.CommitData);
+        }
+    }
+    
+    /// <summary>
+    /// Opens a throwaway reader to check the last commit is readable. Returns a description of the
+    /// problem, or null if the index is readable or has never been committed.
+    /// </summary>
+    private string? ProbeIndex(string indexPath)
+    {
+        try
+        {
+            using var directory = FSDirectory.Open(indexPath, new SimpleFSLockFactory(indexPath));
+            if (!DirectoryReader.IndexExists(directory))
+            {
+                return null;
+            }
+            
+            using var reader = DirectoryReader.Open(directory);
+            return null;
+        }
+        catch (Exception ex) when (ex is CorruptIndexException or IOException or IndexOutOfRangeException)
+        {
+            _logger.LogDebug(ex, "Index probe failed for {Path}", indexPath);
+            return $"{ex.GetType().Name}: {ex.Message}";
+        }
+    }
+    
+    private bool TryRepairInPlace(string indexPath, out int lostDocuments)
+    {
+        lostDocuments = 0;
+        try
+        {
+            using (var directory = FSDirectory.Open(indexPath, new SimpleFSLockFactory(indexPath)))
+            {
+                var checkIndex = new CheckIndex(directory);
+                var status = checkIndex.DoCheckIndex();
+                
+                // Nothing CheckIndex can fix if the commit point itself is missing or unreadable
+                if (status.Clean || status.MissingSegments || status.CantOpenSegments)
+                {
+                    return false;
+                }
+                
+                checkIndex.FixIndex(status);
+                lostDocuments = (int)status.TotLoseDocCount;
+            }
+            
+            return ProbeIndex(indexPath) == null;
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "In-place repair failed for {Path}", indexPath);
+            return false;
+        }
+    }
+    
+    /// <summary>
+    /// Moves an unreadable index aside so a fresh one can be created, keeping only the latest quarantined copy
+    /// </summary>
+    private string QuarantineIndex(string indexPath)
+    {
+        var trimmed = Path.TrimEndingDirectorySeparator(indexPath);
+        var parent = Path.GetDirectoryName(trimmed)!;
+        var name = Path.GetFileName(trimmed);
+        
+        foreach (var previous in System.IO.Directory.GetDirectories(parent, $"{name}.corrupt-*"))
+        {
+            try
+            {
+                System.IO.Directory.Delete(previous, recursive: true);
+            }
+            catch (Exception ex)
+            {
+                _logger.LogDebug(ex, "Could not remove old quarantined index {Path}", previous);
+            }
+        }
+        
+        var quarantinePath = $"{trimmed}.corrupt-{DateTime.UtcNow:yyyyMMddHHmmss}";
+        System.IO.Directory.Move(trimmed, quarantinePath);
+        System.IO.Directory.CreateDirectory(trimmed);
+        return quarantinePath;
+    }
+    
     public async Task<IndexRepairResult> RepairIndexAsync(string workspacePath, IndexRepairOptions? options = null, CancellationToken cancellationToken = default)
     {
         options ??= new IndexRepairOptions();
@@ -998,6 +1642,15 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                 return result;
             }
             
+            // CheckIndex operates on segment files; encrypted indexes only have a sealed snapshot on disk
+            if (_encryptAtRest)
+            {
+                result.Success = false;
+                result.Message = "Repair is not supported for encrypted indexes - use index_workspace with forceRebuild instead";
+                result.EndTime = DateTime.UtcNow;
+                return result;
+            }
+            
             // Create backup if requested
             if (options.CreateBackup)
             {
@@ -1016,10 +1669,9 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
             
             // Close existing writer if any
             var workspaceHash = _pathResolution.ComputeWorkspaceHash(workspacePath);
-            if (_indexes.TryGetValue(workspaceHash, out var context))
+            if (_indexes.TryRemove(workspaceHash, out var context))
             {
                 await DisposeContextAsync(context);
-                _indexes.TryRemove(workspaceHash, out _);
             }
             
             // Use CheckIndex to repair (use SimpleFSLockFactory for consistent cross-platform behavior)
@@ -1090,6 +1742,8 @@ public class LuceneIndexService : ILuceneIndexService, IAsyncDisposable
                     // Force merge to optimize
                     context.Writer.ForceMerge(maxSegments, doWait: true);
                     context.Writer.Commit();
+                    context.Journal?.Clear();
+                    PersistEncryptedSnapshot(context);
                     
                     _logger.LogInformation("Optimized index for {WorkspacePath} to {MaxSegments} segments", 
                         workspacePath, maxSegments);
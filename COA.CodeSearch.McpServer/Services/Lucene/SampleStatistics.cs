@@ -0,0 +1,57 @@
+namespace COA.CodeSearch.McpServer.Services.Lucene;
+
+/// <summary>
+/// Extrapolates match counts found in a <see cref="SampledDocFilter"/> sample to the whole index
+/// </summary>
+public static class SampleStatistics
+{
+    /// <summary>
+    /// z-score for a two-sided 95% interval
+    /// </summary>
+    private const double Z95 = 1.96;
+
+    /// <summary>
+    /// Scales the sample proportion up to <paramref name="totalDocuments"/> with a 95% Wilson score interval.
+    /// Sampling is without replacement, so the sample size is inflated by the finite population correction:
+    /// a sample covering the whole index gives an exact count.
+    /// </summary>
+    public static SampleEstimate Extrapolate(int matchesInSample, int sampledDocuments, int totalDocuments, double sampleRate)
+    {
+        var estimate = new SampleEstimate
+        {
+            SampleRate = sampleRate,
+            TotalDocuments = totalDocuments,
+            SampledDocuments = sampledDocuments,
+            MatchesInSample = matchesInSample,
+            ConfidenceLevel = 0.95
+        };
+
+        if (sampledDocuments <= 0 || totalDocuments <= 0)
+        {
+            estimate.UpperBound = Math.Max(0, totalDocuments);
+            return estimate;
+        }
+
+        var n = (double)sampledDocuments;
+        var p = Math.Clamp(matchesInSample / n, 0.0, 1.0);
+        estimate.EstimatedMatches = (int)Math.Round(p * totalDocuments);
+
+        if (sampledDocuments >= totalDocuments)
+        {
+            estimate.LowerBound = estimate.UpperBound = matchesInSample;
+            return estimate;
+        }
+
+        var effectiveN = n * (totalDocuments - 1) / (totalDocuments - n);
+        var z2 = Z95 * Z95;
+        var denominator = 1 + z2 / effectiveN;
+        var center = (p + z2 / (2 * effectiveN)) / denominator;
+        var margin = Z95 * Math.Sqrt(p * (1 - p) / effectiveN + z2 / (4 * effectiveN * effectiveN)) / denominator;
+
+        // Matches already seen in the sample are certain; unsampled documents can add at most the rest
+        estimate.LowerBound = Math.Max(matchesInSample, (int)Math.Floor(Math.Max(0, center - margin) * totalDocuments));
+        estimate.UpperBound = Math.Min(totalDocuments - sampledDocuments + matchesInSample,
+            (int)Math.Ceiling(Math.Min(1, center + margin) * totalDocuments));
+        return estimate;
+    }
+}
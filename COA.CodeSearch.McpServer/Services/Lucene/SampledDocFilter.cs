@@ -0,0 +1,58 @@
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+using Lucene.Net.Util;
+
+namespace COA.CodeSearch.McpServer.Services.Lucene;
+
+/// <summary>
+/// Accepts a pseudo-random fraction of an index's documents so a query can be evaluated over a sample
+/// instead of everything. Selection hashes the top-level doc id with a seed, so the same seed picks the same
+/// documents until segments merge.
+/// </summary>
+public sealed class SampledDocFilter : Filter
+{
+    private readonly uint _threshold;
+    private readonly int _seed;
+
+    public SampledDocFilter(double rate, int seed = 0)
+    {
+        Rate = Math.Clamp(rate, 0.0, 1.0);
+        _threshold = (uint)(Rate * uint.MaxValue);
+        _seed = seed;
+    }
+
+    /// <summary>
+    /// Fraction of documents accepted
+    /// </summary>
+    public double Rate { get; }
+
+    public override DocIdSet GetDocIdSet(AtomicReaderContext context, IBits acceptDocs)
+    {
+        var maxDoc = context.AtomicReader.MaxDoc;
+        var bits = new FixedBitSet(maxDoc);
+        for (var doc = 0; doc < maxDoc; doc++)
+        {
+            if (acceptDocs != null && !acceptDocs.Get(doc))
+                continue;
+            if (Accepts(context.DocBase + doc))
+                bits.Set(doc);
+        }
+        return bits;
+    }
+
+    /// <summary>
+    /// Whether the document with this top-level id is in the sample
+    /// </summary>
+    public bool Accepts(int docId) => Mix((uint)docId ^ (uint)_seed) <= _threshold;
+
+    // Murmur3 finalizer: spreads sequential doc ids evenly over the 32-bit range
+    private static uint Mix(uint h)
+    {
+        h ^= h >> 16;
+        h *= 0x85ebca6b;
+        h ^= h >> 13;
+        h *= 0xc2b2ae35;
+        h ^= h >> 16;
+        return h;
+    }
+}
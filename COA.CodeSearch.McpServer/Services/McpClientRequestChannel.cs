@@ -0,0 +1,146 @@
+using System.Collections.Concurrent;
+using System.Text.Json;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Sends server-initiated requests (sampling, elicitation) to the client over the STDIO transport.
+/// The framework has no API for requests from the server, so this is the one place that works around it:
+/// requests are written to stdout like any other server message, and a single <see cref="ClientResponseReader"/>
+/// takes the matching responses out of stdin before the transport sees them. The initialize request is
+/// observed to learn which capabilities the client declared.
+/// </summary>
+public class McpClientRequestChannel
+{
+    internal const string RequestIdPrefix = "codesearch-request-";
+
+    private readonly ILogger<McpClientRequestChannel> _logger;
+    private readonly TextWriter? _output;
+    private readonly ConcurrentDictionary<string, TaskCompletionSource<JsonElement>> _pending = new();
+    private long _nextId;
+    private volatile bool _initialized;
+    private JsonElement _capabilities;
+    private int _installed;
+
+    /// <param name="logger">Logger instance</param>
+    /// <param name="output">Where requests are written; defaults to the current Console.Out (the transport's stdout)</param>
+    public McpClientRequestChannel(ILogger<McpClientRequestChannel> logger, TextWriter? output = null)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _output = output;
+    }
+
+    /// <summary>
+    /// Wraps Console.In so responses can be intercepted. Must run before the transport starts reading;
+    /// later calls do nothing, so stdin is only ever wrapped once.
+    /// </summary>
+    public void Install()
+    {
+        if (Interlocked.Exchange(ref _installed, 1) == 0)
+        {
+            Console.SetIn(Attach(Console.In));
+        }
+    }
+
+    /// <summary>
+    /// Wraps the reader the transport reads client messages from
+    /// </summary>
+    public TextReader Attach(TextReader input) => new ClientResponseReader(input, TryHandleInbound);
+
+    /// <summary>
+    /// True once the client's initialize request declared the capability, e.g. "sampling" or "elicitation"
+    /// </summary>
+    public bool ClientSupports(string capability) =>
+        _initialized && _capabilities.ValueKind == JsonValueKind.Object && _capabilities.TryGetProperty(capability, out _);
+
+    /// <summary>
+    /// Sends a request to the client and returns its whole JSON-RPC response, which carries either
+    /// a "result" or an "error"
+    /// </summary>
+    /// <exception cref="TimeoutException">No response within <paramref name="timeout"/></exception>
+    public async Task<JsonElement> SendRequestAsync(string method, object parameters, TimeSpan timeout,
+        CancellationToken cancellationToken = default)
+    {
+        var id = RequestIdPrefix + Interlocked.Increment(ref _nextId);
+        var completion = new TaskCompletionSource<JsonElement>(TaskCreationOptions.RunContinuationsAsynchronously);
+        _pending[id] = completion;
+
+        try
+        {
+            var message = JsonSerializer.Serialize(new { jsonrpc = "2.0", id, method, @params = parameters });
+
+            // Single WriteLine on the synchronized Console.Out so the message can't interleave with transport output
+            var output = _output ?? Console.Out;
+            output.WriteLine(message);
+            await output.FlushAsync(cancellationToken);
+
+            using var timeoutSource = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+            timeoutSource.CancelAfter(timeout);
+            try
+            {
+                var response = await completion.Task.WaitAsync(timeoutSource.Token);
+                _logger.LogDebug("Client answered {Method} request {Id}", method, id);
+                return response;
+            }
+            catch (OperationCanceledException) when (!cancellationToken.IsCancellationRequested)
+            {
+                throw new TimeoutException($"No response to {method} after {timeout.TotalSeconds:0}s");
+            }
+        }
+        finally
+        {
+            _pending.TryRemove(id, out _);
+        }
+    }
+
+    /// <summary>
+    /// Inspects a line read from stdin. Returns true when it was a response to one of our requests
+    /// and must not reach the transport.
+    /// </summary>
+    public bool TryHandleInbound(string line)
+    {
+        var isInitialize = !_initialized && line.Contains("\"initialize\"", StringComparison.Ordinal);
+        if (!isInitialize && !line.Contains(RequestIdPrefix, StringComparison.Ordinal))
+            return false;
+
+        try
+        {
+            using var document = JsonDocument.Parse(line);
+            var root = document.RootElement;
+            if (root.ValueKind != JsonValueKind.Object)
+                return false;
+
+            if (isInitialize &&
+                root.TryGetProperty("method", out var method) && method.GetString() == "initialize")
+            {
+                _capabilities = root.TryGetProperty("params", out var initParams) &&
+                                initParams.TryGetProperty("capabilities", out var capabilities)
+                    ? capabilities.Clone()
+                    : default;
+                _initialized = true;
+                _logger.LogInformation("Client capabilities: {Capabilities}",
+                    _capabilities.ValueKind == JsonValueKind.Object ? _capabilities.GetRawText() : "none");
+                return false;
+            }
+
+            if (root.TryGetProperty("id", out var id) && id.ValueKind == JsonValueKind.String &&
+                id.GetString()!.StartsWith(RequestIdPrefix, StringComparison.Ordinal) &&
+                !root.TryGetProperty("method", out _))
+            {
+                // Late answers to timed-out requests are swallowed too - the transport never sent those ids
+                if (_pending.TryGetValue(id.GetString()!, out var completion))
+                {
+                    completion.TrySetResult(root.Clone());
+                }
+                return true;
+            }
+        }
+        catch (JsonException)
+        {
+            // Not ours - let the transport deal with it
+        }
+
+        return false;
+    }
+}
@@ -0,0 +1,24 @@
+namespace COA.CodeSearch.McpServer.Services.Migrations;
+
+/// <summary>
+/// Indexes a workspace's migration files as ordered timelines, derives the schema each leaves, and links its
+/// tables to the code using them
+/// </summary>
+public interface IMigrationIndexService
+{
+    /// <summary>
+    /// Every migration set in the workspace with its ordered migrations and resulting schema, rebuilt when the
+    /// symbol database changes
+    /// </summary>
+    Task<List<MigrationSet>> GetSetsAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// ORM entities mapped to <paramref name="table"/> and SQL outside the migrations naming it
+    /// </summary>
+    Task<List<SchemaReference>> FindReferencesAsync(string workspacePath, string table, int maxResults, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Columns ORM entities map that the set's schema lacks, and schema columns no entity of a mapped table maps
+    /// </summary>
+    Task<List<SchemaDrift>> CompareWithEntitiesAsync(string workspacePath, MigrationSet set, CancellationToken cancellationToken = default);
+}
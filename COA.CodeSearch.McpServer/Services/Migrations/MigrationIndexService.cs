@@ -0,0 +1,199 @@
+using System.Collections.Concurrent;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Orm;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Migrations;
+
+/// <summary>
+/// Builds migration sets from the indexed files: migrations are grouped by directory and tool, ordered by version
+/// (Flyway repeatables last, by name) and replayed into a schema. Tables link to the ORM entities mapping them and
+/// to SQL strings naming them after FROM, JOIN, INTO, UPDATE or TABLE.
+/// </summary>
+public class MigrationIndexService : IMigrationIndexService
+{
+    private static readonly HashSet<string> MigrationExtensions = new(StringComparer.OrdinalIgnoreCase) { ".sql", ".go", ".cs" };
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IOrmSchemaService _ormSchemaService;
+    private readonly ILogger<MigrationIndexService> _logger;
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, List<MigrationSet> Sets)> _sets = new(StringComparer.OrdinalIgnoreCase);
+
+    public MigrationIndexService(ISQLiteSymbolService sqliteService, IOrmSchemaService ormSchemaService, ILogger<MigrationIndexService> logger)
+    {
+        _sqliteService = sqliteService;
+        _ormSchemaService = ormSchemaService;
+        _logger = logger;
+    }
+
+    public async Task<List<MigrationSet>> GetSetsAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var stamp = DatabaseStamp(workspacePath);
+        if (_sets.TryGetValue(workspacePath, out var cached) && cached.Stamp == stamp)
+            return cached.Sets;
+
+        var migrations = new List<MigrationFile>();
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            if (!MigrationExtensions.Contains(Path.GetExtension(file.Path)))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var fullPath = FullPath(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null || MigrationParser.Identify(Relative(workspacePath, file.Path), content) is not { } migration)
+                continue;
+
+            MigrationParser.ReadChanges(migration, content.Replace("\r\n", "\n").Split('\n'));
+            migrations.Add(migration);
+        }
+
+        var sets = migrations
+            .GroupBy(m => (Directory: Path.GetDirectoryName(m.FilePath)?.Replace('\\', '/') ?? string.Empty, m.Tool))
+            .Select(g =>
+            {
+                var ordered = g.Where(m => !m.Repeatable)
+                    .OrderBy(m => m.Version, Comparer<string>.Create(MigrationParser.CompareVersions))
+                    .ThenBy(m => m.Name, StringComparer.Ordinal)
+                    .Concat(g.Where(m => m.Repeatable).OrderBy(m => m.Name, StringComparer.Ordinal))
+                    .ToList();
+                return new MigrationSet
+                {
+                    Directory = g.Key.Directory,
+                    Tool = g.Key.Tool,
+                    Migrations = ordered,
+                    Schema = MigrationParser.BuildSchema(ordered)
+                };
+            })
+            .OrderBy(s => s.Directory, StringComparer.Ordinal)
+            .ToList();
+
+        _sets[workspacePath] = (stamp, sets);
+        _logger.LogDebug("Migration index for {Workspace}: {Sets} sets, {Migrations} migrations", workspacePath, sets.Count, migrations.Count);
+        return sets;
+    }
+
+    public async Task<List<SchemaReference>> FindReferencesAsync(string workspacePath, string table, int maxResults, CancellationToken cancellationToken = default)
+    {
+        var references = new List<SchemaReference>();
+        var bare = table.Split('.').Last();
+
+        foreach (var entity in await _ormSchemaService.GetEntitiesAsync(workspacePath, cancellationToken))
+        {
+            if (entity.Table.Equals(table, StringComparison.OrdinalIgnoreCase) || entity.Table.Equals(bare, StringComparison.OrdinalIgnoreCase))
+                references.Add(new SchemaReference { Via = "entity", Code = entity.Name, FilePath = Relative(workspacePath, entity.FilePath), Line = entity.Line });
+        }
+
+        var migrationFiles = (await GetSetsAsync(workspacePath, cancellationToken))
+            .SelectMany(s => s.Migrations)
+            .Select(m => m.FilePath)
+            .ToHashSet(StringComparer.OrdinalIgnoreCase);
+        var sql = new Regex($@"\b(?:FROM|JOIN|INTO|UPDATE|TABLE)\s+[""`\[]?(?:\w+[""`\]]?\.[""`\[]?)?{Regex.Escape(bare)}\b", RegexOptions.IgnoreCase);
+
+        foreach (var path in await CandidateFilesAsync(workspacePath, bare, cancellationToken))
+        {
+            var relative = Relative(workspacePath, path);
+            if (migrationFiles.Contains(relative))
+                continue;
+
+            var fullPath = FullPath(workspacePath, path);
+            var content = File.Exists(fullPath)
+                ? await File.ReadAllTextAsync(fullPath, cancellationToken)
+                : (await _sqliteService.GetFileByPathAsync(workspacePath, path, cancellationToken))?.Content;
+            if (content == null)
+                continue;
+
+            var extension = Path.GetExtension(path).ToLowerInvariant();
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            var masked = extension == ".sql" ? null : DataFlowScanner.MaskLines(lines, extension);
+            for (var i = 0; i < lines.Length; i++)
+            {
+                // Outside .sql files only SQL in string literals counts
+                var match = sql.Matches(lines[i]).FirstOrDefault(m => masked == null || m.Index < masked[i].Length && masked[i][m.Index] == ' ' && lines[i][m.Index] != ' ');
+                if (match == null)
+                    continue;
+                if (references.Count >= maxResults)
+                    return references;
+                references.Add(new SchemaReference { Via = "sql", Code = lines[i].Trim(), FilePath = relative, Line = i + 1 });
+            }
+        }
+        return references;
+    }
+
+    public async Task<List<SchemaDrift>> CompareWithEntitiesAsync(string workspacePath, MigrationSet set, CancellationToken cancellationToken = default)
+    {
+        var drift = new List<SchemaDrift>();
+        var tables = set.Schema.Where(t => t.Kind == "table").ToDictionary(t => t.Name, StringComparer.OrdinalIgnoreCase);
+        var mapped = new Dictionary<string, HashSet<string>>(StringComparer.OrdinalIgnoreCase);
+
+        foreach (var entity in await _ormSchemaService.GetEntitiesAsync(workspacePath, cancellationToken))
+        {
+            if (!tables.TryGetValue(entity.Table, out var table))
+                continue;
+            if (!mapped.TryGetValue(table.Name, out var columns))
+                mapped[table.Name] = columns = new HashSet<string>(StringComparer.OrdinalIgnoreCase);
+
+            foreach (var column in entity.Columns)
+            {
+                columns.Add(column.Column);
+                if (!table.Columns.Any(c => c.Name.Equals(column.Column, StringComparison.OrdinalIgnoreCase)))
+                {
+                    drift.Add(new SchemaDrift
+                    {
+                        Kind = "unmigrated", Table = table.Name, Column = column.Column, Entity = entity.Name,
+                        FilePath = Relative(workspacePath, column.FilePath), Line = column.Line
+                    });
+                }
+            }
+        }
+
+        foreach (var (name, columns) in mapped)
+        {
+            var table = tables[name];
+            foreach (var column in table.Columns.Where(c => !columns.Contains(c.Name)))
+            {
+                drift.Add(new SchemaDrift { Kind = "unmapped", Table = table.Name, Column = column.Name, FilePath = table.FilePath, Line = table.Line });
+            }
+        }
+        return drift;
+    }
+
+    /// <summary>
+    /// Files that may name the table: a full-text match when the name is searchable, else every indexed file
+    /// </summary>
+    private async Task<List<string>> CandidateFilesAsync(string workspacePath, string table, CancellationToken cancellationToken)
+    {
+        if (table.Length >= 3 && Regex.IsMatch(table, @"^\w+$"))
+        {
+            try
+            {
+                var matched = await _sqliteService.SearchWithFTS5Async(workspacePath, $"\"{table}\"", 5000, null, cancellationToken);
+                if (matched.Count > 0)
+                    return matched.Select(f => f.Path).ToList();
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogDebug(ex, "Full-text candidate search failed, scanning all files");
+            }
+        }
+
+        return (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken)).Select(f => f.Path).ToList();
+    }
+
+    // The sets are rebuilt when the database or its write-ahead log changes
+    private DateTime DatabaseStamp(string workspacePath)
+    {
+        var path = _sqliteService.GetDatabasePath(workspacePath);
+        var stamp = File.Exists(path) ? File.GetLastWriteTimeUtc(path) : DateTime.MinValue;
+        var wal = path + "-wal";
+        return File.Exists(wal) && File.GetLastWriteTimeUtc(wal) > stamp ? File.GetLastWriteTimeUtc(wal) : stamp;
+    }
+
+    private static string FullPath(string workspacePath, string filePath) =>
+        Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+}
@@ -0,0 +1,182 @@
+namespace COA.CodeSearch.McpServer.Services.Migrations;
+
+/// <summary>
+/// One migration file: its place in the timeline and the schema changes it applies going up
+/// </summary>
+public class MigrationFile
+{
+    /// <summary>
+    /// Version as written: 20240105120000, 00042, 1.2.3; empty for Flyway repeatable migrations
+    /// </summary>
+    public string Version { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Description from the file or class name: create_users, AddEmailToUsers
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// goose, golang-migrate, dbmate, flyway or ef-core
+    /// </summary>
+    public string Tool { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Flyway R__ migrations, applied after all versioned ones whenever they change
+    /// </summary>
+    public bool Repeatable { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public List<MigrationChange> Changes { get; set; } = new();
+}
+
+/// <summary>
+/// A schema change a migration makes
+/// </summary>
+public class MigrationChange
+{
+    /// <summary>
+    /// create-table, drop-table, rename-table, add-column, drop-column, rename-column, alter-column, create-index,
+    /// drop-index, create-view or drop-view
+    /// </summary>
+    public string Action { get; set; } = string.Empty;
+
+    public string Table { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Column, index or new name the change concerns
+    /// </summary>
+    public string? Target { get; set; }
+
+    /// <summary>
+    /// Column type and constraints, index columns, or the old name of a rename
+    /// </summary>
+    public string? Detail { get; set; }
+
+    /// <summary>
+    /// A unique index
+    /// </summary>
+    public bool Unique { get; set; }
+
+    /// <summary>
+    /// Version and file of the migration making the change
+    /// </summary>
+    public string Version { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Columns of a created table, in order, or the column an add-column adds
+    /// </summary>
+    public List<SchemaColumn> Columns { get; set; } = new();
+
+    // What an alter-column changes; null or false leaves that part of the column as it was
+    internal string? NewType { get; set; }
+    internal bool? NewNullable { get; set; }
+    internal string? NewDefault { get; set; }
+    internal bool SetsPrimaryKey { get; set; }
+    internal string? RenamedFrom { get; set; }
+}
+
+/// <summary>
+/// Migrations that apply to one database: the files of one tool in one directory, in the order they run
+/// </summary>
+public class MigrationSet
+{
+    /// <summary>
+    /// Workspace-relative directory holding the migrations
+    /// </summary>
+    public string Directory { get; set; } = string.Empty;
+
+    public string Tool { get; set; } = string.Empty;
+    public List<MigrationFile> Migrations { get; set; } = new();
+
+    /// <summary>
+    /// Tables and views left after every migration ran, by name
+    /// </summary>
+    public List<SchemaTable> Schema { get; set; } = new();
+}
+
+/// <summary>
+/// A table or view in the schema the migrations build
+/// </summary>
+public class SchemaTable
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// table or view
+    /// </summary>
+    public string Kind { get; set; } = "table";
+
+    public List<SchemaColumn> Columns { get; set; } = new();
+    public List<SchemaIndex> Indexes { get; set; } = new();
+
+    /// <summary>
+    /// Version of the migration creating it, and of the last one changing it
+    /// </summary>
+    public string CreatedIn { get; set; } = string.Empty;
+    public string LastChangedIn { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A column as the migrations last left it
+/// </summary>
+public class SchemaColumn
+{
+    public string Name { get; set; } = string.Empty;
+    public string Type { get; set; } = string.Empty;
+    public bool Nullable { get; set; } = true;
+    public bool PrimaryKey { get; set; }
+    public string? Default { get; set; }
+
+    /// <summary>
+    /// Version of the migration adding it
+    /// </summary>
+    public string AddedIn { get; set; } = string.Empty;
+}
+
+public class SchemaIndex
+{
+    public string Name { get; set; } = string.Empty;
+    public List<string> Columns { get; set; } = new();
+    public bool Unique { get; set; }
+}
+
+/// <summary>
+/// Code using a schema table: an ORM entity mapped to it, or SQL text naming it outside the migrations
+/// </summary>
+public class SchemaReference
+{
+    /// <summary>
+    /// entity or sql
+    /// </summary>
+    public string Via { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The entity's name, or the trimmed source line for SQL
+    /// </summary>
+    public string Code { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A column the ORM maps that the migrations never create, or one they create that no entity of the table maps
+/// </summary>
+public class SchemaDrift
+{
+    /// <summary>
+    /// unmigrated (mapped, not in the schema) or unmapped (in the schema, not mapped)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Table { get; set; } = string.Empty;
+    public string Column { get; set; } = string.Empty;
+    public string Entity { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
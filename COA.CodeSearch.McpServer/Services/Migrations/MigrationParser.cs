@@ -0,0 +1,772 @@
+using System.Text;
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services.Migrations;
+
+/// <summary>
+/// Recognizes migration files - goose, golang-migrate, dbmate and Flyway SQL, goose Go migrations and EF Core
+/// migration classes - reads the DDL each applies going up, and replays an ordered set into the schema it
+/// leaves. Only the up direction is read: down sections, .down.sql and Flyway undo files are skipped.
+/// </summary>
+public static class MigrationParser
+{
+    private const string Ident = @"(?:[""`\[]?[\w$]+[""`\]]?\.)?[""`\[]?[\w$]+[""`\]]?";
+
+    private static readonly Regex Flyway = new(@"^(?<kind>[VR])(?<version>[\d._]+)?__(?<name>.+)\.sql$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex GolangMigrate = new(@"^(?<version>\d+)_(?<name>.+)\.(?<direction>up|down)\.sql$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex Numbered = new(@"^(?<version>\d+)_(?<name>.+)\.(?:sql|go|cs)$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex GooseUp = new(@"^\s*--\s*\+goose\s+Up\b", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Multiline);
+    private static readonly Regex DbmateUp = new(@"^\s*--\s*migrate:up\b", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Multiline);
+    private static readonly Regex EfMigrationClass = new(@"\bclass\s+(?<name>\w+)\s*:\s*Migration\b", RegexOptions.Compiled);
+    private static readonly Regex EfMigrationAttribute = new(@"\[Migration\(\s*""(?<id>[^""]+)""\s*\)\]", RegexOptions.Compiled);
+
+    private static readonly Regex CreateTable = new(
+        $@"^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(?<name>{Ident})",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex AlterTable = new(
+        $@"^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(?<name>{Ident})\s+(?<actions>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex DropTable = new(
+        @"^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?<names>.+?)(?:\s+(?:CASCADE|RESTRICT))?$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex RenameTable = new(
+        $@"^RENAME\s+TABLE\s+(?<old>{Ident})\s+TO\s+(?<new>{Ident})", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex CreateIndex = new(
+        $@"^CREATE\s+(?<unique>UNIQUE\s+)?(?:(?:CLUSTERED|NONCLUSTERED)\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?<name>{Ident})?\s*ON\s+(?:ONLY\s+)?(?<table>{Ident})\s*(?:USING\s+\w+\s*)?\((?<columns>[^)]*)\)",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex DropIndex = new(
+        $@"^DROP\s+INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+EXISTS\s+)?(?<name>{Ident})(?:\s+ON\s+(?<table>{Ident}))?", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex CreateView = new(
+        $@"^CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:MATERIALIZED\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(?<name>{Ident})", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex DropView = new(
+        @"^DROP\s+(?:MATERIALIZED\s+)?VIEW\s+(?:IF\s+EXISTS\s+)?(?<names>.+?)(?:\s+(?:CASCADE|RESTRICT))?$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+
+    private static readonly Regex AddColumn = new(@"^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?<def>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex AddPrimaryKey = new(@"^ADD\s+(?:CONSTRAINT\s+\S+\s+)?PRIMARY\s+KEY\s*(?:CLUSTERED\s*)?\((?<columns>[^)]*)\)", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex DropColumn = new($@"^DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?(?<column>{Ident})", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex RenameColumn = new($@"^RENAME\s+(?:COLUMN\s+)?(?<old>{Ident})\s+TO\s+(?<new>{Ident})", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex RenameTo = new($@"^RENAME\s+TO\s+(?<new>{Ident})", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex AlterColumn = new($@"^ALTER\s+(?:COLUMN\s+)?(?<column>{Ident})\s+(?<change>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex ModifyColumn = new(@"^MODIFY\s+(?:COLUMN\s+)?(?<def>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex ChangeColumn = new($@"^CHANGE\s+(?:COLUMN\s+)?(?<old>{Ident})\s+(?<def>.+)$", RegexOptions.Compiled | RegexOptions.IgnoreCase | RegexOptions.Singleline);
+    private static readonly Regex ConstraintStart = new(
+        @"^(?:CONSTRAINT|PRIMARY|UNIQUE|FOREIGN|CHECK|INDEX|KEY|EXCLUDE|FULLTEXT|SPATIAL|PERIOD)\b", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex ColumnConstraint = new(
+        @"\s+(?:NOT\s+NULL|NULL|DEFAULT|PRIMARY\s+KEY|REFERENCES|UNIQUE|CHECK|CONSTRAINT|GENERATED|AUTO_INCREMENT|AUTOINCREMENT|IDENTITY|COLLATE|COMMENT|ON\s+UPDATE)\b",
+        RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex DefaultValue = new(
+        @"\bDEFAULT\s+(?<value>'[^']*'|\([^)]*\)|[^\s,]+(?:\(\))?)", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    private static readonly Regex EfCall = new(@"\bmigrationBuilder\s*\.\s*(?<op>\w+)\s*(?:<(?<clr>[^>()]+)>)?\s*\(", RegexOptions.Compiled);
+    private static readonly Regex EfStringArgument = new(@"\b(?<name>\w+)\s*:\s*@?""(?<value>(?:[^""]|"""")*)""", RegexOptions.Compiled);
+    private static readonly Regex EfBoolArgument = new(@"\b(?<name>nullable|unique)\s*:\s*(?<value>true|false)\b", RegexOptions.Compiled);
+    private static readonly Regex EfColumn = new(@"(?<property>\w+)\s*=\s*table\.Column<(?<clr>[^>]+)>\s*\((?<arguments>(?:""[^""]*""|[^)""])*)\)", RegexOptions.Compiled);
+    private static readonly Regex EfPrimaryKey = new(@"table\.PrimaryKey\(\s*""[^""]*""\s*,\s*\w+\s*=>\s*(?:\w+\.(?<column>\w+)|new\s*\{(?<columns>[^}]*)\})", RegexOptions.Compiled);
+    private static readonly Regex EfSql = new(@"^\s*(?<verbatim>@)?""(?<sql>(?:[^""\\]|\\.|"""")*)""", RegexOptions.Compiled | RegexOptions.Singleline);
+
+    private static readonly HashSet<string> DefaultSchemas = new(StringComparer.OrdinalIgnoreCase) { "public", "dbo", "main" };
+
+    /// <summary>
+    /// The file's migration header - tool, version and name - with no changes read yet; null when it is not an
+    /// up migration
+    /// </summary>
+    public static MigrationFile? Identify(string filePath, string content)
+    {
+        var fileName = Path.GetFileName(filePath);
+        var extension = Path.GetExtension(fileName).ToLowerInvariant();
+
+        if (extension == ".sql")
+        {
+            if (Flyway.Match(fileName) is { Success: true } flyway)
+            {
+                var repeatable = flyway.Groups["kind"].Value.Equals("R", StringComparison.OrdinalIgnoreCase);
+                if (!repeatable && !flyway.Groups["version"].Success)
+                    return null;
+                return new MigrationFile
+                {
+                    Tool = "flyway",
+                    Version = repeatable ? string.Empty : flyway.Groups["version"].Value.Replace('_', '.'),
+                    Name = flyway.Groups["name"].Value,
+                    Repeatable = repeatable,
+                    FilePath = filePath
+                };
+            }
+            if (GolangMigrate.Match(fileName) is { Success: true } migrate)
+            {
+                return migrate.Groups["direction"].Value.Equals("up", StringComparison.OrdinalIgnoreCase)
+                    ? new MigrationFile { Tool = "golang-migrate", Version = migrate.Groups["version"].Value, Name = migrate.Groups["name"].Value, FilePath = filePath }
+                    : null;
+            }
+            if (Numbered.Match(fileName) is { Success: true } numbered)
+            {
+                var tool = GooseUp.IsMatch(content) ? "goose" : DbmateUp.IsMatch(content) ? "dbmate" : null;
+                return tool == null ? null
+                    : new MigrationFile { Tool = tool, Version = numbered.Groups["version"].Value, Name = numbered.Groups["name"].Value, FilePath = filePath };
+            }
+            return null;
+        }
+
+        if (extension == ".go")
+        {
+            // goose Go migrations run code, so they only mark their place in the timeline
+            return Numbered.Match(fileName) is { Success: true } numbered && content.Contains("goose.AddMigration", StringComparison.Ordinal)
+                ? new MigrationFile { Tool = "goose", Version = numbered.Groups["version"].Value, Name = numbered.Groups["name"].Value, FilePath = filePath }
+                : null;
+        }
+
+        if (extension == ".cs" && !fileName.EndsWith(".Designer.cs", StringComparison.OrdinalIgnoreCase)
+            && EfMigrationClass.Match(content) is { Success: true } efClass && content.Contains("MigrationBuilder", StringComparison.Ordinal))
+        {
+            var id = EfMigrationAttribute.Match(content) is { Success: true } attribute ? attribute.Groups["id"].Value : Path.GetFileNameWithoutExtension(fileName);
+            var underscore = id.IndexOf('_');
+            var versioned = underscore > 0 && id[..underscore].All(char.IsDigit);
+            return new MigrationFile
+            {
+                Tool = "ef-core",
+                Version = versioned ? id[..underscore] : string.Empty,
+                Name = versioned ? id[(underscore + 1)..] : efClass.Groups["name"].Value,
+                FilePath = filePath
+            };
+        }
+
+        return null;
+    }
+
+    /// <summary>
+    /// Reads the changes the migration's up direction makes into <see cref="MigrationFile.Changes"/>
+    /// </summary>
+    public static void ReadChanges(MigrationFile migration, IReadOnlyList<string> lines)
+    {
+        var changes = migration.Tool switch
+        {
+            "ef-core" => ParseEfCore(lines),
+            "goose" when migration.FilePath.EndsWith(".sql", StringComparison.OrdinalIgnoreCase) => ParseSql(UpSection(lines, "+goose up", "+goose down"), 0),
+            "dbmate" => ParseSql(UpSection(lines, "migrate:up", "migrate:down"), 0),
+            "goose" => new List<MigrationChange>(),
+            _ => ParseSql(string.Join('\n', lines), 0)
+        };
+        foreach (var change in changes)
+        {
+            change.Version = migration.Version;
+            change.FilePath = migration.FilePath;
+        }
+        migration.Changes = changes;
+    }
+
+    // The lines between the up and down markers, with everything else blanked so line numbers hold
+    private static string UpSection(IReadOnlyList<string> lines, string up, string down)
+    {
+        var text = new StringBuilder();
+        var inUp = false;
+        foreach (var line in lines)
+        {
+            var marker = line.TrimStart().StartsWith("--", StringComparison.Ordinal)
+                ? Regex.Replace(line.TrimStart()[2..].Trim(), @"\s+", " ").ToLowerInvariant()
+                : null;
+            if (marker != null && marker.StartsWith(up, StringComparison.Ordinal))
+                inUp = true;
+            else if (marker != null && marker.StartsWith(down, StringComparison.Ordinal))
+                inUp = false;
+            text.Append(inUp && marker == null ? line : string.Empty).Append('\n');
+        }
+        return text.ToString();
+    }
+
+    /// <summary>
+    /// Schema changes in SQL text: CREATE/ALTER/DROP/RENAME TABLE, CREATE/DROP INDEX and CREATE/DROP VIEW in the
+    /// PostgreSQL, MySQL, SQLite and SQL Server dialects. Other statements are skipped.
+    /// </summary>
+    /// <param name="lineOffset">Lines before <paramref name="sql"/> in its file</param>
+    public static List<MigrationChange> ParseSql(string sql, int lineOffset)
+    {
+        var changes = new List<MigrationChange>();
+        foreach (var (statement, line) in Statements(sql))
+        {
+            var text = statement.Trim();
+            var lineNumber = lineOffset + line;
+
+            if (CreateTable.Match(text) is { Success: true } create)
+            {
+                var change = new MigrationChange { Action = "create-table", Table = Name(create.Groups["name"].Value), Line = lineNumber };
+                var open = text.IndexOf('(', create.Length);
+                if (open >= 0 && !Regex.IsMatch(text[create.Length..open], @"\bAS\b", RegexOptions.IgnoreCase))
+                {
+                    var primaryKey = new List<string>();
+                    foreach (var element in SplitTopLevel(Balanced(text, open)))
+                    {
+                        if (ConstraintStart.IsMatch(element))
+                        {
+                            var key = Regex.Match(element, @"PRIMARY\s+KEY\s*(?:CLUSTERED\s*)?\((?<columns>[^)]*)\)", RegexOptions.IgnoreCase);
+                            if (key.Success)
+                                primaryKey.AddRange(key.Groups["columns"].Value.Split(',').Select(c => Name(c.Trim())));
+                            continue;
+                        }
+                        if (ReadColumn(element) is { } column)
+                            change.Columns.Add(column);
+                    }
+                    foreach (var column in change.Columns.Where(c => primaryKey.Contains(c.Name, StringComparer.OrdinalIgnoreCase)))
+                    {
+                        column.PrimaryKey = true;
+                        column.Nullable = false;
+                    }
+                }
+                changes.Add(change);
+            }
+            else if (AlterTable.Match(text) is { Success: true } alter)
+            {
+                var table = Name(alter.Groups["name"].Value);
+                foreach (var action in SplitTopLevel(alter.Groups["actions"].Value))
+                {
+                    if (ReadAlterAction(table, action.Trim(), lineNumber) is { } change)
+                        changes.Add(change);
+                }
+            }
+            else if (DropTable.Match(text) is { Success: true } drop)
+            {
+                changes.AddRange(drop.Groups["names"].Value.Split(',')
+                    .Select(n => new MigrationChange { Action = "drop-table", Table = Name(n.Trim()), Line = lineNumber }));
+            }
+            else if (RenameTable.Match(text) is { Success: true } rename)
+            {
+                changes.Add(new MigrationChange
+                {
+                    Action = "rename-table", Table = Name(rename.Groups["old"].Value), Target = Name(rename.Groups["new"].Value), Line = lineNumber
+                });
+            }
+            else if (CreateIndex.Match(text) is { Success: true } index)
+            {
+                changes.Add(new MigrationChange
+                {
+                    Action = "create-index",
+                    Table = Name(index.Groups["table"].Value),
+                    Target = index.Groups["name"].Success ? Name(index.Groups["name"].Value) : string.Empty,
+                    Detail = string.Join(", ", index.Groups["columns"].Value.Split(',').Select(c => Name(c.Trim().Split(' ')[0]))),
+                    Unique = index.Groups["unique"].Success,
+                    Line = lineNumber
+                });
+            }
+            else if (DropIndex.Match(text) is { Success: true } dropIndex)
+            {
+                changes.Add(new MigrationChange
+                {
+                    Action = "drop-index",
+                    Table = dropIndex.Groups["table"].Success ? Name(dropIndex.Groups["table"].Value) : string.Empty,
+                    Target = Name(dropIndex.Groups["name"].Value),
+                    Line = lineNumber
+                });
+            }
+            else if (CreateView.Match(text) is { Success: true } view)
+            {
+                changes.Add(new MigrationChange { Action = "create-view", Table = Name(view.Groups["name"].Value), Line = lineNumber });
+            }
+            else if (DropView.Match(text) is { Success: true } dropView)
+            {
+                changes.AddRange(dropView.Groups["names"].Value.Split(',')
+                    .Select(n => new MigrationChange { Action = "drop-view", Table = Name(n.Trim()), Line = lineNumber }));
+            }
+        }
+        return changes;
+    }
+
+    private static MigrationChange? ReadAlterAction(string table, string action, int line)
+    {
+        if (AddPrimaryKey.Match(action) is { Success: true } primaryKey)
+        {
+            return new MigrationChange
+            {
+                Action = "alter-column", Table = table, Line = line, Detail = "primary key",
+                Target = string.Join(", ", primaryKey.Groups["columns"].Value.Split(',').Select(c => Name(c.Trim()))),
+                SetsPrimaryKey = true
+            };
+        }
+        if (AddColumn.Match(action) is { Success: true } add)
+        {
+            if (ConstraintStart.IsMatch(add.Groups["def"].Value) || ReadColumn(add.Groups["def"].Value) is not { } column)
+                return null;
+            return new MigrationChange
+            {
+                Action = "add-column", Table = table, Target = column.Name, Detail = Describe(column), Line = line, Columns = { column }
+            };
+        }
+        if (RenameTo.Match(action) is { Success: true } renameTable)
+            return new MigrationChange { Action = "rename-table", Table = table, Target = Name(renameTable.Groups["new"].Value), Line = line };
+        if (RenameColumn.Match(action) is { Success: true } rename)
+        {
+            return new MigrationChange
+            {
+                Action = "rename-column", Table = table, Target = Name(rename.Groups["new"].Value), Detail = Name(rename.Groups["old"].Value), Line = line
+            };
+        }
+        if (DropColumn.Match(action) is { Success: true } drop && !ConstraintStart.IsMatch(action[4..].TrimStart()))
+            return new MigrationChange { Action = "drop-column", Table = table, Target = Name(drop.Groups["column"].Value), Line = line };
+
+        if (ModifyColumn.Match(action) is { Success: true } modify && ReadColumn(modify.Groups["def"].Value) is { } modified)
+            return Altered(table, modified.Name, modified, line);
+        if (ChangeColumn.Match(action) is { Success: true } changeColumn && ReadColumn(changeColumn.Groups["def"].Value) is { } changed)
+        {
+            var altered = Altered(table, changed.Name, changed, line);
+            altered.Detail = $"from {Name(changeColumn.Groups["old"].Value)}: {altered.Detail}";
+            altered.RenamedFrom = Name(changeColumn.Groups["old"].Value);
+            return altered;
+        }
+        if (AlterColumn.Match(action) is { Success: true } alterColumn)
+        {
+            var column = Name(alterColumn.Groups["column"].Value);
+            var change = alterColumn.Groups["change"].Value.Trim();
+            var result = new MigrationChange { Action = "alter-column", Table = table, Target = column, Detail = Regex.Replace(change, @"\s+", " "), Line = line };
+            if (Regex.Match(change, @"^(?:SET\s+DATA\s+)?TYPE\s+(?<type>.+?)(?:\s+USING\b.*)?$", RegexOptions.IgnoreCase | RegexOptions.Singleline) is { Success: true } type)
+                result.NewType = Regex.Replace(type.Groups["type"].Value.Trim(), @"\s+", " ");
+            else if (Regex.IsMatch(change, @"^SET\s+NOT\s+NULL\b", RegexOptions.IgnoreCase))
+                result.NewNullable = false;
+            else if (Regex.IsMatch(change, @"^DROP\s+NOT\s+NULL\b", RegexOptions.IgnoreCase))
+                result.NewNullable = true;
+            else if (Regex.Match(change, @"^SET\s+DEFAULT\s+(?<value>.+)$", RegexOptions.IgnoreCase | RegexOptions.Singleline) is { Success: true } setDefault)
+                result.NewDefault = setDefault.Groups["value"].Value.Trim();
+            else if (Regex.IsMatch(change, @"^DROP\s+DEFAULT\b", RegexOptions.IgnoreCase))
+                result.NewDefault = string.Empty;
+            else if (ReadColumn($"{column} {change}") is { } redefined)
+                return Altered(table, column, redefined, line); // SQL Server: ALTER COLUMN name type [NOT] NULL
+            return result;
+        }
+        return null;
+    }
+
+    private static MigrationChange Altered(string table, string column, SchemaColumn definition, int line) => new()
+    {
+        Action = "alter-column", Table = table, Target = column, Detail = Describe(definition), Line = line,
+        NewType = definition.Type, NewNullable = definition.Nullable, NewDefault = definition.Default
+    };
+
+    /// <summary>
+    /// A column definition: name, type up to the first constraint keyword, NOT NULL, PRIMARY KEY and DEFAULT
+    /// </summary>
+    private static SchemaColumn? ReadColumn(string definition)
+    {
+        var text = Regex.Replace(definition.Trim(), @"\s+", " ");
+        var nameMatch = Regex.Match(text, $@"^(?<name>{Ident})(?:\s+|$)");
+        if (!nameMatch.Success)
+            return null;
+
+        var rest = text[nameMatch.Length..];
+        var constraint = ColumnConstraint.Match(" " + rest);
+        var type = (constraint.Success ? rest[..Math.Max(0, constraint.Index - 1)] : rest).Trim();
+        var constraints = constraint.Success ? rest[Math.Max(0, constraint.Index - 1)..] : string.Empty;
+        var primaryKey = Regex.IsMatch(constraints, @"\bPRIMARY\s+KEY\b", RegexOptions.IgnoreCase);
+        return new SchemaColumn
+        {
+            Name = Name(nameMatch.Groups["name"].Value),
+            Type = type,
+            PrimaryKey = primaryKey,
+            Nullable = !primaryKey && !Regex.IsMatch(constraints, @"\bNOT\s+NULL\b", RegexOptions.IgnoreCase),
+            Default = DefaultValue.Match(constraints) is { Success: true } value ? value.Groups["value"].Value : null
+        };
+    }
+
+    private static string Describe(SchemaColumn column) =>
+        string.Join(" ", new[]
+        {
+            column.Type,
+            column.PrimaryKey ? "primary key" : column.Nullable ? null : "not null",
+            column.Default != null ? $"default {column.Default}" : null
+        }.Where(p => !string.IsNullOrEmpty(p)));
+
+    /// <summary>
+    /// Changes in an EF Core migration's Up method: the MigrationBuilder operations and the SQL passed to Sql()
+    /// </summary>
+    private static List<MigrationChange> ParseEfCore(IReadOnlyList<string> lines)
+    {
+        var text = string.Join('\n', lines);
+        var up = Regex.Match(text, @"\bvoid\s+Up\s*\(\s*MigrationBuilder\s+\w+\s*\)\s*\{");
+        if (!up.Success)
+            return new List<MigrationChange>();
+        var body = Balanced(text, up.Index + up.Length - 1);
+        var bodyStart = up.Index + up.Length;
+
+        var changes = new List<MigrationChange>();
+        foreach (Match call in EfCall.Matches(body))
+        {
+            var open = call.Index + call.Length - 1;
+            var arguments = Balanced(body, open);
+            var line = text.AsSpan(0, bodyStart + call.Index).Count('\n') + 1;
+            var strings = EfStringArgument.Matches(arguments)
+                .GroupBy(m => m.Groups["name"].Value)
+                .ToDictionary(g => g.Key, g => g.First().Groups["value"].Value.Replace("\"\"", "\""));
+            var bools = EfBoolArgument.Matches(arguments).GroupBy(m => m.Groups["name"].Value).ToDictionary(g => g.Key, g => g.First().Groups["value"].Value == "true");
+            var clr = call.Groups["clr"].Value;
+            var name = strings.GetValueOrDefault("name") ?? string.Empty;
+            var table = strings.GetValueOrDefault("table") ?? string.Empty;
+
+            switch (call.Groups["op"].Value)
+            {
+                case "CreateTable":
+                    changes.Add(EfCreateTable(name, arguments, line));
+                    break;
+                case "DropTable":
+                    changes.Add(new MigrationChange { Action = "drop-table", Table = name, Line = line });
+                    break;
+                case "RenameTable":
+                    changes.Add(new MigrationChange { Action = "rename-table", Table = name, Target = strings.GetValueOrDefault("newName"), Line = line });
+                    break;
+                case "AddColumn":
+                    var column = EfColumnOf(name, clr, strings, bools);
+                    changes.Add(new MigrationChange { Action = "add-column", Table = table, Target = name, Detail = Describe(column), Line = line, Columns = { column } });
+                    break;
+                case "DropColumn":
+                    changes.Add(new MigrationChange { Action = "drop-column", Table = table, Target = name, Line = line });
+                    break;
+                case "RenameColumn":
+                    changes.Add(new MigrationChange { Action = "rename-column", Table = table, Target = strings.GetValueOrDefault("newName"), Detail = name, Line = line });
+                    break;
+                case "AlterColumn":
+                    changes.Add(Altered(table, name, EfColumnOf(name, clr, strings, bools), line));
+                    break;
+                case "CreateIndex":
+                    var columns = strings.TryGetValue("column", out var single)
+                        ? new List<string> { single }
+                        : Regex.Matches(Regex.Match(arguments, @"columns\s*:\s*new\s*\[\]\s*\{(?<list>[^}]*)\}").Groups["list"].Value, @"""(?<c>[^""]+)""")
+                            .Select(m => m.Groups["c"].Value).ToList();
+                    changes.Add(new MigrationChange
+                    {
+                        Action = "create-index", Table = table, Target = name, Detail = string.Join(", ", columns),
+                        Unique = bools.GetValueOrDefault("unique"), Line = line
+                    });
+                    break;
+                case "DropIndex":
+                    changes.Add(new MigrationChange { Action = "drop-index", Table = table, Target = name, Line = line });
+                    break;
+                case "Sql":
+                    if (EfSql.Match(arguments) is { Success: true } sql)
+                    {
+                        var statement = sql.Groups["verbatim"].Success
+                            ? sql.Groups["sql"].Value.Replace("\"\"", "\"")
+                            : Regex.Unescape(sql.Groups["sql"].Value);
+                        changes.AddRange(ParseSql(statement, line - 1));
+                    }
+                    break;
+            }
+        }
+        return changes;
+    }
+
+    private static MigrationChange EfCreateTable(string table, string arguments, int line)
+    {
+        var change = new MigrationChange { Action = "create-table", Table = table, Line = line };
+        var columnNames = new Dictionary<string, string>(StringComparer.Ordinal);
+        foreach (Match column in EfColumn.Matches(arguments))
+        {
+            var columnArguments = column.Groups["arguments"].Value;
+            var strings = EfStringArgument.Matches(columnArguments).GroupBy(m => m.Groups["name"].Value).ToDictionary(g => g.Key, g => g.First().Groups["value"].Value);
+            var bools = EfBoolArgument.Matches(columnArguments).GroupBy(m => m.Groups["name"].Value).ToDictionary(g => g.Key, g => g.First().Groups["value"].Value == "true");
+            var name = strings.GetValueOrDefault("name") ?? column.Groups["property"].Value;
+            columnNames[column.Groups["property"].Value] = name;
+            change.Columns.Add(EfColumnOf(name, column.Groups["clr"].Value, strings, bools));
+        }
+
+        if (EfPrimaryKey.Match(arguments) is { Success: true } key)
+        {
+            var properties = key.Groups["column"].Success
+                ? new[] { key.Groups["column"].Value }
+                : key.Groups["columns"].Value.Split(',').Select(p => p.Trim().Split('.').Last()).ToArray();
+            foreach (var column in change.Columns.Where(c => properties.Any(p => columnNames.GetValueOrDefault(p, p) == c.Name)))
+            {
+                column.PrimaryKey = true;
+                column.Nullable = false;
+            }
+        }
+        return change;
+    }
+
+    private static SchemaColumn EfColumnOf(string name, string clr, Dictionary<string, string> strings, Dictionary<string, bool> bools) => new()
+    {
+        Name = name,
+        Type = strings.GetValueOrDefault("type") ?? clr,
+        Nullable = bools.TryGetValue("nullable", out var nullable) ? nullable : clr.EndsWith('?') || clr == "string" || clr == "byte[]",
+        Default = strings.GetValueOrDefault("defaultValueSql")
+    };
+
+    /// <summary>
+    /// Statements split at semicolons outside quotes, comments, dollar-quoted bodies and goose
+    /// StatementBegin/End blocks, each with the line it starts on
+    /// </summary>
+    private static IEnumerable<(string Statement, int Line)> Statements(string sql)
+    {
+        var current = new StringBuilder();
+        var line = 1;
+        var startLine = 0;
+        string? dollarTag = null;
+        var block = false;
+
+        for (var i = 0; i < sql.Length; i++)
+        {
+            var c = sql[i];
+            if (c == '\n')
+            {
+                line++;
+                if (startLine > 0)
+                    current.Append(c);
+                continue;
+            }
+
+            if (dollarTag == null && c == '-' && i + 1 < sql.Length && sql[i + 1] == '-')
+            {
+                var end = sql.IndexOf('\n', i);
+                var comment = sql[i..(end < 0 ? sql.Length : end)];
+                if (Regex.IsMatch(comment, @"\+goose\s+StatementBegin", RegexOptions.IgnoreCase))
+                {
+                    block = true;
+                }
+                else if (Regex.IsMatch(comment, @"\+goose\s+StatementEnd", RegexOptions.IgnoreCase))
+                {
+                    block = false;
+                    if (startLine > 0)
+                        yield return (current.ToString(), startLine);
+                    current.Clear();
+                    startLine = 0;
+                }
+                i = (end < 0 ? sql.Length : end) - 1;
+                continue;
+            }
+            if (dollarTag == null && c == '/' && i + 1 < sql.Length && sql[i + 1] == '*')
+            {
+                var end = sql.IndexOf("*/", i + 2, StringComparison.Ordinal);
+                end = end < 0 ? sql.Length : end + 2;
+                line += sql.AsSpan(i, end - i).Count('\n');
+                current.Append(' ');
+                i = end - 1;
+                continue;
+            }
+
+            if (startLine == 0)
+            {
+                if (char.IsWhiteSpace(c) || c == ';' && !block)
+                    continue;
+                startLine = line;
+            }
+
+            if (c is '\'' or '"' or '`' && dollarTag == null)
+            {
+                var end = i + 1;
+                while (end < sql.Length && (sql[end] != c || end + 1 < sql.Length && sql[end + 1] == c && ++end > 0))
+                    end++;
+                end = Math.Min(end, sql.Length - 1);
+                line += sql.AsSpan(i, end - i + 1).Count('\n');
+                current.Append(sql, i, end - i + 1);
+                i = end;
+                continue;
+            }
+            if (c == '$' && Regex.Match(sql[i..], @"^\$\w*\$") is { Success: true } tag)
+            {
+                dollarTag = dollarTag == null ? tag.Value : dollarTag == tag.Value ? null : dollarTag;
+                current.Append(tag.Value);
+                i += tag.Length - 1;
+                continue;
+            }
+
+            if (c == ';' && dollarTag == null && !block)
+            {
+                yield return (current.ToString(), startLine);
+                current.Clear();
+                startLine = 0;
+                continue;
+            }
+            current.Append(c);
+        }
+
+        if (startLine > 0 && current.ToString().Trim().Length > 0)
+            yield return (current.ToString(), startLine);
+    }
+
+    // The text inside the bracket opening at openIndex, skipping brackets in string literals
+    private static string Balanced(string text, int openIndex)
+    {
+        var open = text[openIndex];
+        var close = open switch { '(' => ')', '{' => '}', _ => ']' };
+        var depth = 0;
+        for (var i = openIndex; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (c is '"' or '\'')
+            {
+                var verbatim = i > 0 && text[i - 1] == '@';
+                for (i++; i < text.Length && text[i] != c; i++)
+                {
+                    if (text[i] == '\\' && !verbatim)
+                        i++;
+                }
+                continue;
+            }
+            if (c == open)
+                depth++;
+            else if (c == close && --depth == 0)
+                return text[(openIndex + 1)..i];
+        }
+        return text[(openIndex + 1)..];
+    }
+
+    private static List<string> SplitTopLevel(string text)
+    {
+        var parts = new List<string>();
+        var depth = 0;
+        var quote = '\0';
+        var start = 0;
+        for (var i = 0; i < text.Length; i++)
+        {
+            var c = text[i];
+            if (quote != '\0')
+            {
+                if (c == quote)
+                    quote = '\0';
+                continue;
+            }
+            if (c is '\'' or '"' or '`')
+                quote = c;
+            else if (c == '(')
+                depth++;
+            else if (c == ')')
+                depth--;
+            else if (c == ',' && depth == 0)
+            {
+                parts.Add(text[start..i].Trim());
+                start = i + 1;
+            }
+        }
+        parts.Add(text[start..].Trim());
+        return parts.Where(p => p.Length > 0).ToList();
+    }
+
+    /// <summary>
+    /// An identifier without its quotes, and without a default schema: "public"."users" is users
+    /// </summary>
+    public static string Name(string identifier)
+    {
+        var parts = identifier.Trim().Split('.').Select(p => p.Trim().Trim('"', '`', '[', ']')).ToList();
+        if (parts.Count > 1 && DefaultSchemas.Contains(parts[0]))
+            parts.RemoveAt(0);
+        return string.Join('.', parts);
+    }
+
+    /// <summary>
+    /// Versions compared part by part numerically: 1.10 follows 1.9, and 00042 equals 42
+    /// </summary>
+    public static int CompareVersions(string a, string b)
+    {
+        var left = a.Split('.', '_', '-');
+        var right = b.Split('.', '_', '-');
+        for (var i = 0; i < Math.Max(left.Length, right.Length); i++)
+        {
+            var l = i < left.Length ? left[i].TrimStart('0') : string.Empty;
+            var r = i < right.Length ? right[i].TrimStart('0') : string.Empty;
+            var compared = l.Length != r.Length && l.All(char.IsDigit) && r.All(char.IsDigit)
+                ? l.Length.CompareTo(r.Length)
+                : string.CompareOrdinal(l, r);
+            if (compared != 0)
+                return compared;
+        }
+        return 0;
+    }
+
+    /// <summary>
+    /// Replays migrations in order into the schema they leave: tables and views with their columns and indexes
+    /// </summary>
+    public static List<SchemaTable> BuildSchema(IEnumerable<MigrationFile> migrations)
+    {
+        var tables = new Dictionary<string, SchemaTable>(StringComparer.OrdinalIgnoreCase);
+        foreach (var migration in migrations)
+        {
+            var version = migration.Repeatable ? $"R__{migration.Name}" : migration.Version;
+            foreach (var change in migration.Changes)
+            {
+                tables.TryGetValue(change.Table, out var table);
+                switch (change.Action)
+                {
+                    case "create-table":
+                    case "create-view":
+                        tables[change.Table] = table = new SchemaTable
+                        {
+                            Name = change.Table,
+                            Kind = change.Action == "create-view" ? "view" : "table",
+                            CreatedIn = version,
+                            FilePath = migration.FilePath,
+                            Line = change.Line,
+                            Columns = change.Columns.Select(c => Copy(c, version)).ToList()
+                        };
+                        break;
+                    case "drop-table":
+                    case "drop-view":
+                        tables.Remove(change.Table);
+                        table = null;
+                        break;
+                    case "rename-table" when table != null && change.Target != null:
+                        tables.Remove(change.Table);
+                        table.Name = change.Target;
+                        tables[change.Target] = table;
+                        break;
+                    case "add-column" when table != null:
+                        table.Columns.RemoveAll(c => c.Name.Equals(change.Target, StringComparison.OrdinalIgnoreCase));
+                        table.Columns.AddRange(change.Columns.Select(c => Copy(c, version)));
+                        break;
+                    case "drop-column" when table != null:
+                        table.Columns.RemoveAll(c => c.Name.Equals(change.Target, StringComparison.OrdinalIgnoreCase));
+                        break;
+                    case "rename-column" when table != null && change.Target != null:
+                        if (table.Columns.FirstOrDefault(c => c.Name.Equals(change.Detail, StringComparison.OrdinalIgnoreCase)) is { } renamed)
+                            renamed.Name = change.Target;
+                        break;
+                    case "alter-column" when table != null:
+                        Alter(table, change);
+                        break;
+                    case "create-index":
+                        table?.Indexes.RemoveAll(i => i.Name.Length > 0 && i.Name.Equals(change.Target, StringComparison.OrdinalIgnoreCase));
+                        table?.Indexes.Add(new SchemaIndex
+                        {
+                            Name = change.Target ?? string.Empty,
+                            Columns = (change.Detail ?? string.Empty).Split(", ", StringSplitOptions.RemoveEmptyEntries).ToList(),
+                            Unique = change.Unique
+                        });
+                        break;
+                    case "drop-index":
+                        foreach (var candidate in table != null ? new[] { table } : tables.Values.ToArray())
+                            candidate.Indexes.RemoveAll(i => i.Name.Equals(change.Target, StringComparison.OrdinalIgnoreCase));
+                        break;
+                }
+
+                if (table != null && tables.ContainsKey(table.Name))
+                    table.LastChangedIn = version;
+            }
+        }
+        return tables.Values.OrderBy(t => t.Name, StringComparer.OrdinalIgnoreCase).ToList();
+    }
+
+    private static void Alter(SchemaTable table, MigrationChange change)
+    {
+        if (change.SetsPrimaryKey)
+        {
+            var keys = (change.Target ?? string.Empty).Split(", ");
+            foreach (var column in table.Columns.Where(c => keys.Contains(c.Name, StringComparer.OrdinalIgnoreCase)))
+            {
+                column.PrimaryKey = true;
+                column.Nullable = false;
+            }
+            return;
+        }
+
+        var name = change.RenamedFrom ?? change.Target;
+        if (table.Columns.FirstOrDefault(c => c.Name.Equals(name, StringComparison.OrdinalIgnoreCase)) is not { } existing)
+            return;
+        existing.Name = change.Target ?? existing.Name;
+        if (!string.IsNullOrEmpty(change.NewType))
+            existing.Type = change.NewType;
+        if (change.NewNullable is { } nullable)
+            existing.Nullable = nullable;
+        if (change.NewDefault != null)
+            existing.Default = change.NewDefault.Length == 0 ? null : change.NewDefault;
+    }
+
+    private static SchemaColumn Copy(SchemaColumn column, string version) => new()
+    {
+        Name = column.Name,
+        Type = column.Type,
+        Nullable = column.Nullable,
+        PrimaryKey = column.PrimaryKey,
+        Default = column.Default,
+        AddedIn = version
+    };
+}
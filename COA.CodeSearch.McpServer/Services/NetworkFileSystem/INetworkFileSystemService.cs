@@ -0,0 +1,26 @@
+namespace COA.CodeSearch.McpServer.Services.NetworkFileSystem;
+
+/// <summary>
+/// Resilience mode for workspaces and indexes on network file systems (NFS, SMB) and Dev Drives: transient I/O
+/// errors are retried, indexes are read without memory mapping and the file watcher polls instead of waiting for
+/// change notifications those file systems deliver late or not at all. Configured via CodeSearch:NetworkFileSystem.
+/// </summary>
+public interface INetworkFileSystemService
+{
+    /// <summary>
+    /// True when <paramref name="path"/> is treated as being on a network file system - always with Mode On,
+    /// never with Off, and with Auto when it is under one of the configured Paths or on a network or ReFS volume
+    /// </summary>
+    bool AppliesTo(string path);
+
+    /// <summary>
+    /// How often the file watcher lists a workspace the mode applies to
+    /// </summary>
+    TimeSpan PollInterval { get; }
+
+    /// <summary>
+    /// Runs <paramref name="operation"/>, trying again with a growing delay when it fails with a transient I/O
+    /// error and <paramref name="path"/> is one the mode applies to. Other errors, and the last one, are thrown.
+    /// </summary>
+    Task<T> RetryAsync<T>(string path, Func<CancellationToken, Task<T>> operation, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,154 @@
+using System.Collections.Concurrent;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.NetworkFileSystem;
+
+/// <summary>
+/// Decides per volume whether the resilience mode applies and retries transient I/O errors there. Volumes are
+/// looked up once; mounts added while the server runs are picked up by listing them under Paths.
+/// </summary>
+public class NetworkFileSystemService : INetworkFileSystemService
+{
+    // File system types reported for NFS, SMB and other remote mounts, and ReFS for Windows Dev Drives
+    private static readonly HashSet<string> RemoteFormats = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "nfs", "nfs4", "cifs", "smb", "smb2", "smb3", "smbfs", "afpfs", "webdav", "davfs", "fuse.sshfs", "fuse.rclone",
+        "9p", "ceph", "glusterfs", "lustre", "afs", "ReFS"
+    };
+
+    private readonly ILogger<NetworkFileSystemService> _logger;
+    private readonly NetworkFileSystemMode _mode;
+    private readonly List<string> _paths;
+    private readonly int _retryCount;
+    private readonly TimeSpan _retryDelay;
+    private readonly Lazy<List<(string Root, bool Remote)>> _volumes;
+    private readonly ConcurrentDictionary<string, bool> _verdicts = new(PlatformPaths.Comparer);
+
+    public NetworkFileSystemService(IConfiguration configuration, ILogger<NetworkFileSystemService> logger)
+    {
+        _logger = logger;
+        _mode = Enum.TryParse<NetworkFileSystemMode>(configuration["CodeSearch:NetworkFileSystem:Mode"], ignoreCase: true, out var mode)
+            ? mode
+            : NetworkFileSystemMode.Auto;
+        _paths = (configuration.GetSection("CodeSearch:NetworkFileSystem:Paths").Get<string[]>() ?? Array.Empty<string>())
+            .Where(p => !string.IsNullOrWhiteSpace(p))
+            .Select(p => PlatformPaths.Normalize(Environment.ExpandEnvironmentVariables(p)))
+            .ToList();
+        _retryCount = Math.Max(0, configuration.GetValue("CodeSearch:NetworkFileSystem:RetryCount", 3));
+        _retryDelay = TimeSpan.FromMilliseconds(Math.Max(0, configuration.GetValue("CodeSearch:NetworkFileSystem:RetryDelayMilliseconds", 250)));
+        PollInterval = TimeSpan.FromSeconds(Math.Max(1, configuration.GetValue("CodeSearch:NetworkFileSystem:PollIntervalSeconds", 30)));
+        _volumes = new Lazy<List<(string Root, bool Remote)>>(ListVolumes);
+    }
+
+    public TimeSpan PollInterval { get; }
+
+    public bool AppliesTo(string path)
+    {
+        if (_mode != NetworkFileSystemMode.Auto || string.IsNullOrEmpty(path))
+            return _mode == NetworkFileSystemMode.On;
+
+        path = PlatformPaths.Normalize(path);
+        if (_paths.Any(p => PlatformPaths.IsUnder(path, p)))
+            return true;
+
+        // UNC paths (\\server\share) are remote whatever drive list says
+        if (OperatingSystem.IsWindows() && path.StartsWith(@"\\", StringComparison.Ordinal))
+            return true;
+
+        var volume = VolumeOf(path, _volumes.Value.Select(v => v.Root));
+        return volume != null && _verdicts.GetOrAdd(volume, root =>
+        {
+            var remote = _volumes.Value.First(v => PlatformPaths.PathEquals(v.Root, root)).Remote;
+            if (remote)
+                _logger.LogInformation("{Volume} is a network file system or Dev Drive: retrying I/O errors, reading indexes without mmap and polling for changes", root);
+            return remote;
+        });
+    }
+
+    public async Task<T> RetryAsync<T>(string path, Func<CancellationToken, Task<T>> operation, CancellationToken cancellationToken = default)
+    {
+        var retries = AppliesTo(path) ? _retryCount : 0;
+        for (var attempt = 0; ; attempt++)
+        {
+            try
+            {
+                return await operation(cancellationToken);
+            }
+            catch (Exception ex) when (attempt < retries && IsTransient(ex))
+            {
+                var delay = _retryDelay * Math.Pow(2, attempt);
+                _logger.LogDebug(ex, "Transient I/O error on {Path}, retrying in {Delay}ms ({Attempt} of {Retries})",
+                    path, delay.TotalMilliseconds, attempt + 1, retries);
+                await Task.Delay(delay, cancellationToken);
+            }
+        }
+    }
+
+    /// <summary>
+    /// True for I/O errors worth trying again - a share that stalled, a lock the server held, a dropped
+    /// connection - as opposed to files and directories that aren't there
+    /// </summary>
+    public static bool IsTransient(Exception exception) =>
+        exception is IOException and not (FileNotFoundException or DirectoryNotFoundException or PathTooLongException or EndOfStreamException);
+
+    /// <summary>
+    /// The root of the volume <paramref name="path"/> is on: the longest of <paramref name="roots"/> it is under,
+    /// so a path under /mnt/share is on the /mnt/share mount rather than on /
+    /// </summary>
+    public static string? VolumeOf(string path, IEnumerable<string> roots) =>
+        roots.Where(r => PlatformPaths.IsUnder(path, r)).OrderByDescending(r => r.Length).FirstOrDefault();
+
+    /// <summary>
+    /// True for volumes the resilience mode is for: network drives and the remote and Dev Drive file systems
+    /// </summary>
+    public static bool IsRemoteVolume(DriveType driveType, string? format) =>
+        driveType == DriveType.Network || (format != null && RemoteFormats.Contains(format));
+
+    private List<(string Root, bool Remote)> ListVolumes()
+    {
+        var volumes = new List<(string Root, bool Remote)>();
+        try
+        {
+            foreach (var drive in DriveInfo.GetDrives())
+            {
+                string? format = null;
+                try
+                {
+                    format = drive.DriveFormat;
+                }
+                catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+                {
+                    // Not ready (a disconnected share, an empty card reader): judged by its drive type alone
+                }
+                volumes.Add((drive.RootDirectory.FullName, IsRemoteVolume(drive.DriveType, format)));
+            }
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            _logger.LogDebug(ex, "Could not list volumes; only CodeSearch:NetworkFileSystem:Paths are treated as network file systems");
+        }
+        return volumes;
+    }
+}
+
+/// <summary>
+/// When the network file system resilience mode is used (CodeSearch:NetworkFileSystem:Mode)
+/// </summary>
+public enum NetworkFileSystemMode
+{
+    /// <summary>
+    /// For paths under CodeSearch:NetworkFileSystem:Paths and on network and Dev Drive volumes
+    /// </summary>
+    Auto,
+
+    /// <summary>
+    /// For every path
+    /// </summary>
+    On,
+
+    /// <summary>
+    /// Never
+    /// </summary>
+    Off
+}
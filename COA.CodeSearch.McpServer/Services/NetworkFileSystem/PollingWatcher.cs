@@ -0,0 +1,131 @@
+using COA.CodeSearch.McpServer.Models;
+
+namespace COA.CodeSearch.McpServer.Services.NetworkFileSystem;
+
+/// <summary>
+/// Watches a workspace by listing it every interval and comparing sizes and write times, for file systems whose
+/// change notifications are missing or unreliable (NFS, SMB). Each directory is listed once per pass with the
+/// metadata of its files, rather than stat-ing files one by one. Directories that can't be listed in a pass keep
+/// their last listing, so a share that drops for a moment doesn't read as every file deleted.
+/// </summary>
+public sealed class PollingWatcher : IDisposable
+{
+    private readonly string _root;
+    private readonly Func<string, bool>? _enterDirectory;
+    private readonly SymlinkPolicy _policy;
+    private readonly Action<string, FileChangeType> _changed;
+    private readonly Action<Exception>? _onError;
+    private readonly Timer _timer;
+    private Dictionary<string, FileStamp>? _snapshot;
+    private int _polling;
+
+    /// <param name="root">The workspace to watch</param>
+    /// <param name="interval">Time between passes; the first pass, which reports nothing, starts at once</param>
+    /// <param name="changed">Called for each file created, modified or deleted since the last pass</param>
+    /// <param name="enterDirectory">False for directories not to list, as for the indexer's walk</param>
+    /// <param name="policy">How links are walked</param>
+    /// <param name="onError">Called when a pass fails</param>
+    public PollingWatcher(string root, TimeSpan interval, Action<string, FileChangeType> changed,
+        Func<string, bool>? enterDirectory = null, SymlinkPolicy policy = SymlinkPolicy.Follow, Action<Exception>? onError = null)
+    {
+        _root = root;
+        _changed = changed;
+        _enterDirectory = enterDirectory;
+        _policy = policy;
+        _onError = onError;
+        _timer = new Timer(_ => Poll(), null, TimeSpan.Zero, interval);
+    }
+
+    /// <summary>
+    /// Lists the workspace and reports what changed since the last pass. Skipped while a pass is running.
+    /// </summary>
+    public void Poll()
+    {
+        if (Interlocked.Exchange(ref _polling, 1) == 1)
+            return;
+
+        try
+        {
+            var unreadable = new List<string>();
+            var current = new Dictionary<string, FileStamp>(PlatformPaths.Comparer);
+
+            // Broken links are reported as not found; they aren't listed and can't be carried over
+            void OnUnreadable(string path, Exception error)
+            {
+                if (error is not FileNotFoundException)
+                    unreadable.Add(path);
+            }
+
+            foreach (var file in PlatformPaths.EnumerateFileInfos(_root, _enterDirectory, OnUnreadable, _policy))
+            {
+                try
+                {
+                    current[file.FullName] = new FileStamp(file.Length, file.LastWriteTimeUtc);
+                }
+                catch (FileNotFoundException)
+                {
+                    // Deleted since it was listed
+                }
+            }
+
+            var previous = _snapshot;
+            _snapshot = Carry(previous, current, unreadable);
+            if (previous == null)
+                return;
+
+            foreach (var (path, change) in Compare(previous, _snapshot))
+                _changed(path, change);
+        }
+        catch (Exception ex)
+        {
+            _onError?.Invoke(ex);
+        }
+        finally
+        {
+            Volatile.Write(ref _polling, 0);
+        }
+    }
+
+    /// <summary>
+    /// The changes from one listing to the next: files only in <paramref name="after"/> were created, files only
+    /// in <paramref name="before"/> deleted, and files whose size or write time differ modified
+    /// </summary>
+    public static IEnumerable<(string Path, FileChangeType Change)> Compare(IReadOnlyDictionary<string, FileStamp> before, IReadOnlyDictionary<string, FileStamp> after)
+    {
+        foreach (var (path, stamp) in after)
+        {
+            if (!before.TryGetValue(path, out var previous))
+                yield return (path, FileChangeType.Created);
+            else if (previous != stamp)
+                yield return (path, FileChangeType.Modified);
+        }
+
+        foreach (var path in before.Keys.Where(p => !after.ContainsKey(p)))
+            yield return (path, FileChangeType.Deleted);
+    }
+
+    /// <summary>
+    /// <paramref name="current"/> with the files <paramref name="previous"/> had in directories that could not be
+    /// listed now
+    /// </summary>
+    public static Dictionary<string, FileStamp> Carry(IReadOnlyDictionary<string, FileStamp>? previous, Dictionary<string, FileStamp> current,
+        IReadOnlyCollection<string> unreadable)
+    {
+        if (previous == null || unreadable.Count == 0)
+            return current;
+
+        foreach (var (path, stamp) in previous)
+        {
+            if (!current.ContainsKey(path) && unreadable.Any(u => PlatformPaths.IsUnder(path, u)))
+                current[path] = stamp;
+        }
+        return current;
+    }
+
+    public void Dispose() => _timer.Dispose();
+}
+
+/// <summary>
+/// A file's size and last write time, as a directory listing reports them
+/// </summary>
+public readonly record struct FileStamp(long Length, DateTime LastWriteUtc);
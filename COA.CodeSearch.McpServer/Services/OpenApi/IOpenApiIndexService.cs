@@ -0,0 +1,14 @@
+namespace COA.CodeSearch.McpServer.Services.OpenApi;
+
+/// <summary>
+/// Indexes the OpenAPI and Swagger documents in a workspace - operations and schemas - and links operations to
+/// the handlers serving them and schemas to the DTO types they describe
+/// </summary>
+public interface IOpenApiIndexService
+{
+    /// <summary>
+    /// Every spec in the workspace, linked to code, with the drift found between them; rebuilt when the symbol
+    /// database changes
+    /// </summary>
+    Task<OpenApiIndex> GetIndexAsync(string workspacePath, CancellationToken cancellationToken = default);
+}
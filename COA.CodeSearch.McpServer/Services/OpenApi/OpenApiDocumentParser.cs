@@ -0,0 +1,343 @@
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Services.OpenApi;
+
+/// <summary>
+/// Reads OpenAPI 3 and Swagger 2 documents, JSON or YAML, into operations and schemas with the line each is
+/// declared on. YAML is read by indentation - block maps and lists, flow lists and simple flow maps; anchors
+/// and multi-document files are not followed.
+/// </summary>
+public static class OpenApiDocumentParser
+{
+    private static readonly string[] HttpMethods = { "get", "post", "put", "delete", "patch", "head", "options" };
+    private static readonly Regex YamlKey = new(
+        @"^(?:""(?<key>[^""]*)""|'(?<key>[^']*)'|(?<key>[^\s#'""\-][^#]*?|-[^\s#][^#]*?))\s*:(?:\s+(?<value>.*))?$", RegexOptions.Compiled);
+
+    /// <summary>
+    /// A parsed document node: a map, a list or a scalar, with the line its key (or list item) is on
+    /// </summary>
+    private sealed class Node
+    {
+        public Dictionary<string, Node>? Map;
+        public List<Node>? Items;
+        public string? Value;
+        public int Line;
+
+        public Node? this[string key] => Map != null && Map.TryGetValue(key, out var child) ? child : null;
+    }
+
+    /// <summary>
+    /// The spec in <paramref name="content"/>, or null when it is not an OpenAPI or Swagger document
+    /// </summary>
+    public static OpenApiSpec? Parse(string filePath, string content)
+    {
+        Node? root;
+        try
+        {
+            root = Path.GetExtension(filePath).Equals(".json", StringComparison.OrdinalIgnoreCase)
+                ? ReadJson(content)
+                : ReadYaml(content.Replace("\r\n", "\n").Split('\n'));
+        }
+        catch (JsonException)
+        {
+            return null;
+        }
+
+        var version = root?["openapi"]?.Value ?? root?["swagger"]?.Value;
+        if (root?.Map == null || version == null)
+            return null;
+
+        var spec = new OpenApiSpec { FilePath = filePath, Version = version, Title = root["info"]?["title"]?.Value };
+        var basePath = root["basePath"]?.Value
+            ?? (root["servers"]?.Items?.FirstOrDefault()?["url"]?.Value is { } url ? ApiContracts.ServerPath(url) : string.Empty);
+
+        foreach (var (path, item) in root["paths"]?.Map ?? new Dictionary<string, Node>())
+        {
+            foreach (var (method, operation) in item.Map ?? new Dictionary<string, Node>())
+            {
+                if (!HttpMethods.Contains(method) || operation.Map == null)
+                    continue;
+
+                var parsed = new OpenApiOperation
+                {
+                    Method = method.ToUpperInvariant(),
+                    Path = ApiContracts.NormalizePath(basePath.TrimEnd('/') + "/" + path.TrimStart('/')),
+                    OperationId = operation["operationId"]?.Value,
+                    Summary = operation["summary"]?.Value,
+                    Tags = operation["tags"]?.Items?.Select(t => t.Value).OfType<string>().ToList() ?? new List<string>(),
+                    FilePath = filePath,
+                    Line = operation.Line
+                };
+
+                // OpenAPI 3 puts bodies under requestBody/content; Swagger 2 under an in: body parameter
+                parsed.RequestSchema = SchemaName(Content(operation["requestBody"]))
+                    ?? SchemaName(operation["parameters"]?.Items?.FirstOrDefault(p => p["in"]?.Value == "body")?["schema"]);
+                foreach (var (status, response) in operation["responses"]?.Map ?? new Dictionary<string, Node>())
+                {
+                    if (SchemaName(Content(response) ?? response["schema"]) is { } name)
+                        parsed.ResponseSchemas[status] = name;
+                }
+                spec.Operations.Add(parsed);
+            }
+        }
+
+        var schemas = root["components"]?["schemas"]?.Map ?? root["definitions"]?.Map ?? new Dictionary<string, Node>();
+        foreach (var (name, schema) in schemas)
+        {
+            var parsed = new OpenApiSchema { Name = name, FilePath = filePath, Line = schema.Line };
+            var parts = new List<Node> { schema };
+            foreach (var member in schema["allOf"]?.Items ?? new List<Node>())
+            {
+                if (RefName(member) is { } extended)
+                    parsed.Extends.Add(extended);
+                else
+                    parts.Add(member);
+            }
+
+            foreach (var part in parts)
+            {
+                var required = part["required"]?.Items?.Select(r => r.Value).OfType<string>().ToHashSet(StringComparer.Ordinal)
+                    ?? new HashSet<string>();
+                foreach (var (property, definition) in part["properties"]?.Map ?? new Dictionary<string, Node>())
+                {
+                    parsed.Properties.Add(new OpenApiProperty
+                    {
+                        Name = property,
+                        Type = TypeOf(definition),
+                        Required = required.Contains(property),
+                        Line = definition.Line
+                    });
+                }
+            }
+            spec.Schemas.Add(parsed);
+        }
+
+        // allOf references are resolved once every schema is read; a schema may extend one declared after it
+        var byName = spec.Schemas.ToDictionary(s => s.Name, StringComparer.Ordinal);
+        foreach (var schema in spec.Schemas.Where(s => s.Extends.Count > 0))
+        {
+            var visited = new HashSet<string>(StringComparer.Ordinal) { schema.Name };
+            var pending = new Queue<string>(schema.Extends);
+            while (pending.TryDequeue(out var name))
+            {
+                if (!visited.Add(name) || !byName.TryGetValue(name, out var extended))
+                    continue;
+                schema.Properties.AddRange(extended.Properties.Where(p => schema.Properties.All(o => o.Name != p.Name)));
+                foreach (var next in extended.Extends)
+                    pending.Enqueue(next);
+            }
+        }
+
+        return spec;
+    }
+
+    private static Node? Content(Node? owner) =>
+        owner?["content"]?.Map?.OrderBy(c => c.Key.Contains("json", StringComparison.OrdinalIgnoreCase) ? 0 : 1).FirstOrDefault().Value?["schema"];
+
+    /// <summary>
+    /// The named schema a body is, or is an array of
+    /// </summary>
+    private static string? SchemaName(Node? schema) => RefName(schema) ?? RefName(schema?["items"]);
+
+    private static string? RefName(Node? node) =>
+        node?["$ref"]?.Value is { } reference ? reference[(reference.LastIndexOf('/') + 1)..] : null;
+
+    private static string TypeOf(Node definition)
+    {
+        if (RefName(definition) is { } name)
+            return name;
+        var type = definition["type"]?.Value ?? (definition["allOf"] ?? definition["oneOf"] ?? definition["anyOf"])?.Items?
+            .Select(RefName).FirstOrDefault(n => n != null) ?? "object";
+        return type == "array" ? $"array of {(definition["items"] is { } items ? TypeOf(items) : "any")}" : type;
+    }
+
+    /// <summary>
+    /// JSON through Utf8JsonReader, so every node knows its line
+    /// </summary>
+    private static Node ReadJson(string content)
+    {
+        var bytes = Encoding.UTF8.GetBytes(content);
+        var newlines = new List<long>();
+        for (var i = 0; i < bytes.Length; i++)
+        {
+            if (bytes[i] == (byte)'\n')
+                newlines.Add(i);
+        }
+        int LineAt(long offset)
+        {
+            var index = newlines.BinarySearch(offset);
+            return (index < 0 ? ~index : index) + 1;
+        }
+
+        var reader = new Utf8JsonReader(bytes, new JsonReaderOptions { CommentHandling = JsonCommentHandling.Skip, AllowTrailingCommas = true });
+        var stack = new Stack<Node>();
+        Node? root = null;
+        string? key = null;
+        var keyLine = 0;
+
+        void Attach(Node node)
+        {
+            if (stack.Count == 0)
+                root = node;
+            else if (stack.Peek().Items is { } items)
+                items.Add(node);
+            else if (key != null)
+                stack.Peek().Map![key] = node;
+            key = null;
+        }
+
+        while (reader.Read())
+        {
+            var line = key != null ? keyLine : LineAt(reader.TokenStartIndex);
+            switch (reader.TokenType)
+            {
+                case JsonTokenType.PropertyName:
+                    key = reader.GetString();
+                    keyLine = LineAt(reader.TokenStartIndex);
+                    break;
+                case JsonTokenType.StartObject:
+                case JsonTokenType.StartArray:
+                    var container = reader.TokenType == JsonTokenType.StartObject
+                        ? new Node { Map = new Dictionary<string, Node>(StringComparer.Ordinal), Line = line }
+                        : new Node { Items = new List<Node>(), Line = line };
+                    Attach(container);
+                    stack.Push(container);
+                    break;
+                case JsonTokenType.EndObject:
+                case JsonTokenType.EndArray:
+                    stack.Pop();
+                    break;
+                case JsonTokenType.String:
+                    Attach(new Node { Value = reader.GetString(), Line = line });
+                    break;
+                default:
+                    Attach(new Node { Value = Encoding.UTF8.GetString(reader.ValueSpan), Line = line });
+                    break;
+            }
+        }
+        return root ?? new Node();
+    }
+
+    /// <summary>
+    /// Block YAML by indentation. List items are split first, so "- name: id" becomes a "-" entry followed by
+    /// "name: id" indented past the dash and both shapes parse as nested blocks.
+    /// </summary>
+    private static Node ReadYaml(string[] lines)
+    {
+        var entries = new List<(int Indent, string Text, int Line)>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i].TrimEnd();
+            var text = line.TrimStart();
+            if (text.Length == 0 || text.StartsWith('#') || text == "---" || text == "...")
+                continue;
+
+            var indent = line.Length - text.Length;
+            while (text == "-" || text.StartsWith("- ", StringComparison.Ordinal))
+            {
+                entries.Add((indent, "-", i + 1));
+                var rest = text[1..].TrimStart();
+                indent += text.Length - rest.Length;
+                text = rest;
+            }
+            if (text.Length > 0)
+                entries.Add((indent, text, i + 1));
+        }
+
+        var position = 0;
+        return ReadBlock(entries, ref position, -1) ?? new Node();
+    }
+
+    private static Node? ReadBlock(List<(int Indent, string Text, int Line)> entries, ref int i, int parentIndent)
+    {
+        if (i >= entries.Count || entries[i].Indent <= parentIndent)
+            return null;
+
+        var indent = entries[i].Indent;
+        if (entries[i].Text == "-")
+        {
+            var list = new Node { Items = new List<Node>(), Line = entries[i].Line };
+            while (i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-")
+            {
+                var line = entries[i++].Line;
+                var item = ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+                item.Line = line;
+                list.Items.Add(item);
+            }
+            return list;
+        }
+
+        var key = YamlKey.Match(entries[i].Text);
+        if (!key.Success)
+            return Scalar(entries[i].Text, entries[i++].Line);
+
+        var map = new Node { Map = new Dictionary<string, Node>(StringComparer.Ordinal), Line = entries[i].Line };
+        while (i < entries.Count && entries[i].Indent == indent)
+        {
+            key = YamlKey.Match(entries[i].Text);
+            var line = entries[i++].Line;
+            if (!key.Success)
+                continue;
+
+            var value = key.Groups["value"].Value.Trim();
+            Node child;
+            if (value.Length == 0 || value.StartsWith('&'))
+            {
+                // A list may sit at its key's own indentation
+                child = i < entries.Count && entries[i].Indent == indent && entries[i].Text == "-"
+                    ? ReadBlock(entries, ref i, indent - 1)!
+                    : ReadBlock(entries, ref i, indent) ?? new Node { Value = string.Empty };
+            }
+            else if (value[0] is '|' or '>')
+            {
+                var text = new List<string>();
+                while (i < entries.Count && entries[i].Indent > indent)
+                    text.Add(entries[i++].Text);
+                child = new Node { Value = string.Join(value[0] == '|' ? "\n" : " ", text) };
+            }
+            else
+            {
+                child = Scalar(value, line);
+            }
+            child.Line = line;
+            map.Map[key.Groups["key"].Value.Trim()] = child;
+        }
+        return map;
+    }
+
+    private static Node Scalar(string text, int line)
+    {
+        if (text.StartsWith('[') && text.EndsWith(']'))
+        {
+            return new Node
+            {
+                Items = text[1..^1].Split(',').Select(v => v.Trim()).Where(v => v.Length > 0).Select(v => Scalar(v, line)).ToList(),
+                Line = line
+            };
+        }
+        if (text.StartsWith('{') && text.EndsWith('}'))
+        {
+            var map = new Dictionary<string, Node>(StringComparer.Ordinal);
+            foreach (var pair in text[1..^1].Split(','))
+            {
+                var colon = pair.IndexOf(':');
+                if (colon > 0)
+                    map[Unquote(pair[..colon].Trim())] = Scalar(pair[(colon + 1)..].Trim(), line);
+            }
+            return new Node { Map = map, Line = line };
+        }
+        if (text[0] is not ('"' or '\''))
+        {
+            var comment = text.IndexOf(" #", StringComparison.Ordinal);
+            if (comment >= 0)
+                text = text[..comment].TrimEnd();
+        }
+        return new Node { Value = Unquote(text), Line = line };
+    }
+
+    private static string Unquote(string text) =>
+        text.Length >= 2 && (text[0] == '"' && text[^1] == '"' || text[0] == '\'' && text[^1] == '\'') ? text[1..^1] : text;
+}
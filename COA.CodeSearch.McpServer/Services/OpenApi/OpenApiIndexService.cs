@@ -0,0 +1,336 @@
+using System.Collections.Concurrent;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.OpenApi;
+
+/// <summary>
+/// Builds the spec index: operations are matched to server routes the way api_contracts matches them, and the
+/// route's handler resolved through the symbol index (the method under a route attribute or decorator, the
+/// function a registration call passes), with operationId as a fallback. Schemas link to types of the same
+/// name, whose fields are compared with the schema's properties.
+/// </summary>
+public class OpenApiIndexService : IOpenApiIndexService
+{
+    private static readonly HashSet<string> HandlerKinds = new(StringComparer.Ordinal) { "method", "function" };
+    private static readonly HashSet<string> TypeKinds = new(StringComparer.Ordinal) { "class", "struct", "record", "interface", "type" };
+
+    // Frameworks whose route sits on the handler (attribute, decorator) rather than in a registration call
+    private static readonly HashSet<string> DecoratedFrameworks = new(StringComparer.Ordinal) { "aspnet", "nestjs", "flask", "fastapi" };
+
+    // The last identifier a registration call passes: r.GET("/users/:id", auth, h.GetUser)
+    private static readonly Regex HandlerArgument = new(@",\s*(?:[\w$]+\.)*(?<name>[A-Za-z_$][\w$]*)\s*(?=[,)])", RegexOptions.Compiled);
+    private static readonly Regex InlineHandler = new(@"=>|\bfunc\s*\(|\bfunction\b|\blambda\b", RegexOptions.Compiled);
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<OpenApiIndexService> _logger;
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, OpenApiIndex Index)> _indexes = new(StringComparer.OrdinalIgnoreCase);
+
+    public OpenApiIndexService(ISQLiteSymbolService sqliteService, ILogger<OpenApiIndexService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    public async Task<OpenApiIndex> GetIndexAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var stamp = DatabaseStamp(workspacePath);
+        if (_indexes.TryGetValue(workspacePath, out var cached) && cached.Stamp == stamp)
+            return cached.Index;
+
+        var index = new OpenApiIndex();
+        var routes = new List<ApiEndpoint>();
+        var files = new FileCache(_sqliteService, workspacePath, cancellationToken);
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var source = ApiContracts.IsSourceFile(file.Path);
+            var extension = Path.GetExtension(file.Path).ToLowerInvariant();
+            if (!source && extension is not (".json" or ".yaml" or ".yml"))
+                continue;
+
+            var fullPath = FullPath(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            var relative = Relative(workspacePath, file.Path);
+            if (source)
+            {
+                var lines = content.Replace("\r\n", "\n").Split('\n');
+                var declared = ApiContracts.ScanServer(relative, lines);
+                if (declared.Count > 0)
+                {
+                    routes.AddRange(declared);
+                    files.Add(relative, lines);
+                }
+            }
+            else if (ApiContracts.IsSpecCandidate(file.Path, content) && OpenApiDocumentParser.Parse(relative, content) is { } spec)
+            {
+                index.Specs.Add(spec);
+            }
+        }
+        index.Routes = routes.Count;
+
+        if (index.Specs.Count > 0)
+        {
+            var symbols = new SymbolLookup(_sqliteService, workspacePath, cancellationToken);
+            foreach (var spec in index.Specs)
+            {
+                foreach (var operation in spec.Operations)
+                    await LinkOperationAsync(workspacePath, operation, routes, symbols, files, index.Drift);
+                foreach (var schema in spec.Schemas)
+                    await LinkSchemaAsync(workspacePath, schema, symbols, files, index.Drift);
+            }
+        }
+
+        _indexes[workspacePath] = (stamp, index);
+        _logger.LogDebug("OpenAPI index for {Workspace}: {Specs} specs, {Operations} operations, {Schemas} schemas, {Drift} drift",
+            workspacePath, index.Specs.Count, index.Specs.Sum(s => s.Operations.Count), index.Specs.Sum(s => s.Schemas.Count), index.Drift.Count);
+        return index;
+    }
+
+    private async Task LinkOperationAsync(string workspacePath, OpenApiOperation operation, List<ApiEndpoint> routes, SymbolLookup symbols,
+        FileCache files, List<OpenApiDrift> drift)
+    {
+        var endpoint = new ApiEndpoint { Method = operation.Method, Path = operation.Path };
+        foreach (var route in ApiContracts.Implementing(routes, endpoint))
+        {
+            var handler = await HandlerOfAsync(workspacePath, route, symbols, files);
+            if (!operation.Handlers.Any(h => h.FilePath == handler.FilePath && h.Line == handler.Line))
+                operation.Handlers.Add(handler);
+        }
+
+        if (operation.Handlers.Count == 0 && !string.IsNullOrEmpty(operation.OperationId))
+        {
+            foreach (var symbol in (await symbols.ByNameAsync(operation.OperationId)).Where(s => HandlerKinds.Contains(s.Kind)))
+                operation.Handlers.Add(Link(workspacePath, symbol, "operationId"));
+        }
+
+        if (operation.Handlers.Count == 0)
+        {
+            drift.Add(new OpenApiDrift
+            {
+                Kind = "unlinked-operation",
+                Subject = $"{operation.Method} {operation.Path}",
+                FilePath = operation.FilePath,
+                Line = operation.Line,
+                Detail = operation.OperationId != null
+                    ? $"No route serves this operation and no function is named {operation.OperationId}"
+                    : "No route serves this operation"
+            });
+        }
+    }
+
+    /// <summary>
+    /// The function or method serving a route; the route itself when its handler is inline or unresolved
+    /// </summary>
+    private async Task<OpenApiCodeLink> HandlerOfAsync(string workspacePath, ApiEndpoint route, SymbolLookup symbols, FileCache files)
+    {
+        var inFile = (await symbols.InFileAsync(route.FilePath)).Where(s => HandlerKinds.Contains(s.Kind)).ToList();
+        if (DecoratedFrameworks.Contains(route.Framework))
+        {
+            var below = inFile.Where(s => s.StartLine >= route.Line && s.StartLine <= route.Line + 10).MinBy(s => s.StartLine);
+            if (below != null)
+                return Link(workspacePath, below, "route");
+        }
+        else if (files.Get(route.FilePath) is { } lines && route.Line <= lines.Length)
+        {
+            var masked = DataFlowScanner.Mask(lines[route.Line - 1], Path.GetExtension(route.FilePath));
+            var argument = HandlerArgument.Matches(masked).LastOrDefault();
+            if (argument != null && !InlineHandler.IsMatch(masked[argument.Index..]))
+            {
+                var name = argument.Groups["name"].Value;
+                var candidates = (await symbols.ByNameAsync(name)).Where(s => HandlerKinds.Contains(s.Kind)).ToList();
+                var directory = Path.GetDirectoryName(route.FilePath) ?? string.Empty;
+                var best = candidates.FirstOrDefault(s => Relative(workspacePath, s.FilePath) == route.FilePath)
+                    ?? candidates.FirstOrDefault(s => (Path.GetDirectoryName(Relative(workspacePath, s.FilePath)) ?? string.Empty) == directory)
+                    ?? candidates.FirstOrDefault();
+                if (best != null)
+                    return Link(workspacePath, best, "route");
+            }
+        }
+
+        return new OpenApiCodeLink
+        {
+            Name = $"{route.Method} {route.Path}",
+            Kind = "route",
+            FilePath = route.FilePath,
+            Line = route.Line,
+            Via = "route"
+        };
+    }
+
+    private async Task LinkSchemaAsync(string workspacePath, OpenApiSchema schema, SymbolLookup symbols, FileCache files, List<OpenApiDrift> drift)
+    {
+        var types = (await symbols.ByNameAsync(schema.Name)).Where(s => TypeKinds.Contains(s.Kind)).ToList();
+        if (types.Count == 0)
+            types = (await symbols.ByNameAsync(schema.Name + "Dto")).Where(s => TypeKinds.Contains(s.Kind)).ToList();
+        if (types.Count == 0)
+        {
+            drift.Add(new OpenApiDrift
+            {
+                Kind = "unlinked-schema",
+                Subject = schema.Name,
+                FilePath = schema.FilePath,
+                Line = schema.Line,
+                Detail = "No class, struct, record or interface is named like this schema"
+            });
+            return;
+        }
+
+        foreach (var type in types)
+        {
+            var link = Link(workspacePath, type, "name");
+            schema.Types.Add(link);
+
+            var lines = await files.ReadAsync(type.FilePath, link.FilePath);
+            if (lines == null || schema.Properties.Count == 0)
+                continue;
+            var members = TypeMembers.Of(lines, type);
+            if (members.Count == 0)
+                continue;
+
+            var properties = schema.Properties.ToDictionary(p => TypeMembers.Comparable(p.Name), p => p);
+            var fields = members.GroupBy(m => TypeMembers.Comparable(m.Name)).ToDictionary(g => g.Key, g => g.First());
+            foreach (var (_, property) in properties.Where(p => !fields.ContainsKey(p.Key)))
+            {
+                drift.Add(new OpenApiDrift
+                {
+                    Kind = "missing-property",
+                    Subject = $"{schema.Name}.{property.Name}",
+                    FilePath = schema.FilePath,
+                    Line = property.Line,
+                    Detail = $"{type.Name} has no field for {(property.Required ? "required " : string.Empty)}property {property.Name} ({property.Type})",
+                    Code = link
+                });
+            }
+            foreach (var (_, field) in fields.Where(f => !properties.ContainsKey(f.Key)))
+            {
+                drift.Add(new OpenApiDrift
+                {
+                    Kind = "extra-property",
+                    Subject = $"{schema.Name}.{field.Name}",
+                    FilePath = link.FilePath,
+                    Line = field.Line,
+                    Detail = $"{type.Name}.{field.Name} is not a property of schema {schema.Name}",
+                    Code = link
+                });
+            }
+        }
+    }
+
+    private static OpenApiCodeLink Link(string workspacePath, JulieSymbol symbol, string via) => new()
+    {
+        Name = symbol.Name,
+        Kind = symbol.Kind,
+        FilePath = Relative(workspacePath, symbol.FilePath),
+        Line = symbol.StartLine,
+        Via = via
+    };
+
+    private DateTime DatabaseStamp(string workspacePath)
+    {
+        var path = _sqliteService.GetDatabasePath(workspacePath);
+        var stamp = File.Exists(path) ? File.GetLastWriteTimeUtc(path) : DateTime.MinValue;
+        var wal = path + "-wal";
+        return File.Exists(wal) && File.GetLastWriteTimeUtc(wal) > stamp ? File.GetLastWriteTimeUtc(wal) : stamp;
+    }
+
+    private static string FullPath(string workspacePath, string filePath) =>
+        Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    /// <summary>
+    /// Symbol lookups by name and file, each made once per build
+    /// </summary>
+    private sealed class SymbolLookup
+    {
+        private readonly ISQLiteSymbolService _sqliteService;
+        private readonly string _workspacePath;
+        private readonly CancellationToken _cancellationToken;
+        private readonly Dictionary<string, List<JulieSymbol>> _byName = new(StringComparer.Ordinal);
+        private readonly Dictionary<string, List<JulieSymbol>> _byFile = new(StringComparer.OrdinalIgnoreCase);
+        private List<FileRecord>? _files;
+
+        public SymbolLookup(ISQLiteSymbolService sqliteService, string workspacePath, CancellationToken cancellationToken)
+        {
+            _sqliteService = sqliteService;
+            _workspacePath = workspacePath;
+            _cancellationToken = cancellationToken;
+        }
+
+        public async Task<List<JulieSymbol>> ByNameAsync(string name)
+        {
+            if (!_byName.TryGetValue(name, out var symbols))
+            {
+                symbols = await _sqliteService.GetSymbolsByNameAsync(_workspacePath, name, caseSensitive: false, _cancellationToken);
+                _byName[name] = symbols;
+            }
+            return symbols;
+        }
+
+        /// <summary>
+        /// Symbols of a workspace-relative file, looked up under the path form the index stores it with
+        /// </summary>
+        public async Task<List<JulieSymbol>> InFileAsync(string relativePath)
+        {
+            if (_byFile.TryGetValue(relativePath, out var symbols))
+                return symbols;
+
+            _files ??= await _sqliteService.GetAllFilesAsync(_workspacePath, _cancellationToken);
+            var stored = _files.FirstOrDefault(f => Relative(_workspacePath, f.Path) == relativePath)?.Path ?? relativePath;
+            symbols = await _sqliteService.GetSymbolsForFileAsync(_workspacePath, stored, _cancellationToken);
+            _byFile[relativePath] = symbols;
+            return symbols;
+        }
+    }
+
+    /// <summary>
+    /// Lines of route files, kept from the scan, and of type files, read on first use
+    /// </summary>
+    private sealed class FileCache
+    {
+        private readonly ISQLiteSymbolService _sqliteService;
+        private readonly string _workspacePath;
+        private readonly CancellationToken _cancellationToken;
+        private readonly Dictionary<string, string[]?> _files = new(StringComparer.OrdinalIgnoreCase);
+
+        public FileCache(ISQLiteSymbolService sqliteService, string workspacePath, CancellationToken cancellationToken)
+        {
+            _sqliteService = sqliteService;
+            _workspacePath = workspacePath;
+            _cancellationToken = cancellationToken;
+        }
+
+        public void Add(string relativePath, string[] lines) => _files[relativePath] = lines;
+
+        public string[]? Get(string relativePath) => _files.GetValueOrDefault(relativePath);
+
+        public async Task<string[]?> ReadAsync(string filePath, string relativePath)
+        {
+            if (_files.TryGetValue(relativePath, out var cached))
+                return cached;
+
+            var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(_workspacePath, filePath);
+            string[]? lines = null;
+            if (File.Exists(fullPath))
+            {
+                lines = (await File.ReadAllTextAsync(fullPath, _cancellationToken)).Replace("\r\n", "\n").Split('\n');
+            }
+            else if (await _sqliteService.GetFileByPathAsync(_workspacePath, filePath, _cancellationToken) is { Content: { } content })
+            {
+                lines = content.Replace("\r\n", "\n").Split('\n');
+            }
+            _files[relativePath] = lines;
+            return lines;
+        }
+    }
+}
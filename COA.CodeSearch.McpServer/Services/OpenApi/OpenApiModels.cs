@@ -0,0 +1,150 @@
+namespace COA.CodeSearch.McpServer.Services.OpenApi;
+
+/// <summary>
+/// An OpenAPI 3 or Swagger 2 document with its operations and schemas; file paths are workspace-relative
+/// </summary>
+public class OpenApiSpec
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string? Title { get; set; }
+
+    /// <summary>
+    /// The openapi or swagger version the document declares: 3.0.3, 2.0
+    /// </summary>
+    public string Version { get; set; } = string.Empty;
+
+    public List<OpenApiOperation> Operations { get; set; } = new();
+    public List<OpenApiSchema> Schemas { get; set; } = new();
+}
+
+/// <summary>
+/// A path and method the spec declares, with the code handling it
+/// </summary>
+public class OpenApiOperation
+{
+    public string Method { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Path with the server base path applied, in the form ApiContracts.NormalizePath gives: /api/users/{id}
+    /// </summary>
+    public string Path { get; set; } = string.Empty;
+
+    public string? OperationId { get; set; }
+    public string? Summary { get; set; }
+    public List<string> Tags { get; set; } = new();
+
+    /// <summary>
+    /// Schema of the request body, when it is a named schema
+    /// </summary>
+    public string? RequestSchema { get; set; }
+
+    /// <summary>
+    /// Named schemas of the responses, by status code
+    /// </summary>
+    public Dictionary<string, string> ResponseSchemas { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Handler functions and methods serving the operation
+    /// </summary>
+    public List<OpenApiCodeLink> Handlers { get; set; } = new();
+}
+
+/// <summary>
+/// A named schema (components/schemas or definitions) with the code types it describes
+/// </summary>
+public class OpenApiSchema
+{
+    public string Name { get; set; } = string.Empty;
+    public List<OpenApiProperty> Properties { get; set; } = new();
+
+    /// <summary>
+    /// Schemas merged in through allOf; their properties are already in Properties
+    /// </summary>
+    public List<string> Extends { get; set; } = new();
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Classes, structs, records and interfaces the schema describes
+    /// </summary>
+    public List<OpenApiCodeLink> Types { get; set; } = new();
+}
+
+public class OpenApiProperty
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The declared type, the referenced schema's name, or array of either: string, User, array of User
+    /// </summary>
+    public string Type { get; set; } = string.Empty;
+
+    public bool Required { get; set; }
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A code symbol linked to a spec operation or schema
+/// </summary>
+public class OpenApiCodeLink
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Symbol kind (method, function, class, struct...) or route when the handler is inline in the registration
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// How the link was made: route (the registration or attribute serving the path), operationId, or name
+    /// (the type is named like the schema)
+    /// </summary>
+    public string Via { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// A place spec and code disagree
+/// </summary>
+public class OpenApiDrift
+{
+    /// <summary>
+    /// unlinked-operation, unlinked-schema, missing-property (in the schema, not the type) or extra-property
+    /// (in the type, not the schema)
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The operation (GET /users/{id}) or schema (User, User.email) concerned
+    /// </summary>
+    public string Subject { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Detail { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The code side of the comparison, for property drift
+    /// </summary>
+    public OpenApiCodeLink? Code { get; set; }
+}
+
+/// <summary>
+/// Every spec in a workspace, linked to code, with the drift between them
+/// </summary>
+public class OpenApiIndex
+{
+    public List<OpenApiSpec> Specs { get; set; } = new();
+    public List<OpenApiDrift> Drift { get; set; } = new();
+
+    /// <summary>
+    /// Server routes read from the workspace's source files, for operations to be matched against
+    /// </summary>
+    public int Routes { get; set; }
+}
@@ -0,0 +1,23 @@
+namespace COA.CodeSearch.McpServer.Services.Orm;
+
+/// <summary>
+/// Maps code entities to database tables and columns from ORM declarations - Go struct tags, EF Core
+/// attributes and fluent configuration, SQLAlchemy and Django models - and finds the code reading and
+/// writing a column
+/// </summary>
+public interface IOrmSchemaService
+{
+    /// <summary>
+    /// Every ORM entity in the workspace with its table and columns, rebuilt when the symbol database changes
+    /// </summary>
+    Task<List<OrmEntity>> GetEntitiesAsync(string workspacePath, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Mappings of <paramref name="column"/> and the code touching it
+    /// </summary>
+    /// <param name="column">Column name, optionally qualified by its table: is_active or users.is_active</param>
+    /// <param name="access">write, read or all</param>
+    /// <param name="filePattern">Glob restricting the files whose usages are reported</param>
+    Task<ColumnUsageReport> FindColumnUsagesAsync(string workspacePath, string column, string access, string? filePattern,
+        int maxResults, CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,86 @@
+namespace COA.CodeSearch.McpServer.Services.Orm;
+
+/// <summary>
+/// A code type mapped to a database table by an ORM
+/// </summary>
+public class OrmEntity
+{
+    public string Name { get; set; } = string.Empty;
+    public string Table { get; set; } = string.Empty;
+
+    /// <summary>
+    /// gorm, sqlx, ef-core, sqlalchemy or django
+    /// </summary>
+    public string Framework { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Where the table name came from: an attribute, fluent call, TableName() method or naming convention
+    /// </summary>
+    public string TableSource { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public List<OrmColumn> Columns { get; set; } = new();
+}
+
+/// <summary>
+/// A field or property mapped to a column
+/// </summary>
+public class OrmColumn
+{
+    public string Member { get; set; } = string.Empty;
+    public string Column { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The column name is spelled out (tag, attribute, fluent call) rather than derived from the member name
+    /// </summary>
+    public bool Explicit { get; set; }
+
+    public bool PrimaryKey { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A place code touches a mapped column: through the entity member, or in SQL text naming the column
+/// </summary>
+public class ColumnUsage
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The source line, trimmed
+    /// </summary>
+    public string Code { get; set; } = string.Empty;
+
+    /// <summary>
+    /// write or read
+    /// </summary>
+    public string Access { get; set; } = string.Empty;
+
+    /// <summary>
+    /// member (through the mapped field or property) or sql (a string naming the column)
+    /// </summary>
+    public string Via { get; set; } = string.Empty;
+
+    /// <summary>
+    /// table.column touched
+    /// </summary>
+    public string Column { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// The mappings of a column and the code reading and writing it
+/// </summary>
+public class ColumnUsageReport
+{
+    /// <summary>
+    /// Entities mapping the column, each with only that column in <see cref="OrmEntity.Columns"/>
+    /// </summary>
+    public List<OrmEntity> Mappings { get; set; } = new();
+
+    public List<ColumnUsage> Usages { get; set; } = new();
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,529 @@
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Services.Orm;
+
+/// <summary>
+/// Reads ORM entity declarations from source text: Go struct tags (gorm, db, bun) with TableName methods,
+/// EF Core entity classes with their attributes, DbSets and fluent configuration, and SQLAlchemy and Django
+/// models. Names the ORM would derive by convention are derived the same way and marked as not explicit.
+/// </summary>
+public static class OrmSchemaParser
+{
+    private static readonly Regex GoTableName = new(@"^\s*func\s*\(\s*(?:\w+\s+)?\*?\s*(?<type>\w+)\s*\)\s*TableName\s*\(\s*\)\s*string\s*\{", RegexOptions.Compiled);
+    private static readonly Regex ReturnString = new(@"\breturn\s+[""`](?<table>[^""`]+)[""`]", RegexOptions.Compiled);
+
+    private static readonly Regex CsClass = new(@"\b(?:class|record)\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex CsProperty = new(
+        @"^\s*(?:\[.*\]\s*)*public\s+(?<modifiers>(?:(?:virtual|required|override|new|static)\s+)*)(?<type>[\w.<>?,\[\] ]+?)\s+(?<name>\w+)\s*\{\s*(?:get|init)",
+        RegexOptions.Compiled);
+    private static readonly Regex CsAttribute = new(@"\[\s*(?:System\.ComponentModel\.DataAnnotations(?:\.Schema)?\.)?(?<name>\w+?)(?:Attribute)?\s*(?:\((?<args>[^\]]*)\))?\s*\]", RegexOptions.Compiled);
+    private static readonly Regex CsDbSet = new(@"\bDbSet\s*<\s*(?<entity>\w+)\s*>\s+(?<name>\w+)", RegexOptions.Compiled);
+    private static readonly Regex CsFluentEntity = new(@"\b(?:Entity|IEntityTypeConfiguration|EntityTypeBuilder)\s*<\s*(?<entity>\w+)\s*>", RegexOptions.Compiled);
+    private static readonly Regex CsToTable = new(@"\.ToTable\s*\(\s*""(?<table>[^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex CsPropertyCall = new(@"\.(?<call>Property|Ignore|HasKey)\s*\(\s*\w+\s*=>\s*\w+\.(?<member>\w+)\s*\)", RegexOptions.Compiled);
+    private static readonly Regex CsColumnName = new(@"^[^;]*?\.HasColumnName\s*\(\s*""(?<column>[^""]+)""", RegexOptions.Compiled);
+    private static readonly Regex CsCollection = new(@"^(?:I?Collection|I?List|IEnumerable|HashSet|ISet|ICollection|IReadOnlyCollection|IReadOnlyList)\s*<", RegexOptions.Compiled);
+    private static readonly Regex QuotedArgument = new(@"^\s*""(?<value>[^""]+)""", RegexOptions.Compiled);
+
+    private static readonly Regex PyClass = new(@"^(?<indent>\s*)class\s+(?<name>\w+)\s*\((?<bases>[^)]*)\)\s*:", RegexOptions.Compiled);
+    private static readonly Regex PyTableName = new(@"^\s*__tablename__\s*=\s*[""'](?<table>[^""']+)[""']", RegexOptions.Compiled);
+    private static readonly Regex PyAlchemyColumn = new(
+        @"^\s*(?<name>\w+)\s*(?::\s*[\w.]+\[.*\])?\s*=\s*(?:\w+\.)?(?:Column|mapped_column)\s*\((?<args>.*)$", RegexOptions.Compiled);
+    private static readonly Regex PyDjangoField = new(@"^\s*(?<name>\w+)\s*=\s*(?:models\.)?(?<field>\w+Field|ForeignKey|OneToOneField)\s*\((?<args>.*)$", RegexOptions.Compiled);
+    private static readonly Regex PyDbTable = new(@"^\s*db_table\s*=\s*[""'](?<table>[^""']+)[""']", RegexOptions.Compiled);
+    private static readonly Regex PyDbColumn = new(@"\bdb_column\s*=\s*[""'](?<column>[^""']+)[""']", RegexOptions.Compiled);
+    private static readonly Regex PyFirstString = new(@"^\s*[""'](?<column>[^""']+)[""']", RegexOptions.Compiled);
+
+    private static readonly Regex SnakeBoundary = new(@"(?<=[a-z0-9])(?=[A-Z])|(?<=[A-Z])(?=[A-Z][a-z])", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> GoScalarTypes = new(StringComparer.Ordinal)
+    {
+        "bool", "byte", "rune", "string", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32",
+        "uint64", "uintptr", "float32", "float64", "complex64", "complex128", "any"
+    };
+
+    private static readonly string[] GormModelColumns = { "id", "created_at", "updated_at", "deleted_at" };
+
+    /// <summary>
+    /// Cheap check for text an entity parser could use, so most files are never parsed
+    /// </summary>
+    public static bool MayDeclareEntities(string extension, string content) => extension.ToLowerInvariant() switch
+    {
+        ".go" => content.Contains("gorm") || content.Contains("db:\"") || content.Contains("bun:\"") || content.Contains("TableName()"),
+        ".cs" => content.Contains("DbSet<") || content.Contains("[Table") || content.Contains("[Column") || content.Contains("Entity<")
+                 || content.Contains("IEntityTypeConfiguration<"),
+        ".py" => content.Contains("__tablename__") || content.Contains("mapped_column") || content.Contains("Column(") || content.Contains("models.Model"),
+        _ => false
+    };
+
+    /// <summary>
+    /// Go structs whose fields carry gorm, db or bun tags (or embed gorm.Model), and the tables named by
+    /// TableName methods keyed by receiver type
+    /// </summary>
+    public static (List<OrmEntity> Entities, Dictionary<string, string> TableNames) ParseGo(string filePath, string[] lines)
+    {
+        var entities = new List<OrmEntity>();
+        var tableNames = new Dictionary<string, string>(StringComparer.Ordinal);
+        var masked = DataFlowScanner.MaskLines(lines, ".go");
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (GoTableName.Match(masked[i]) is not { Success: true } tableName)
+                continue;
+
+            for (var j = i; j < Math.Min(lines.Length, i + 4); j++)
+            {
+                if (ReturnString.Match(lines[j]) is { Success: true } returned)
+                {
+                    tableNames[tableName.Groups["type"].Value] = returned.Groups["table"].Value;
+                    break;
+                }
+            }
+        }
+
+        foreach (var type in GoMethodSets.Parse(filePath, lines).Types.Where(t => t.Kind == "struct" && char.IsUpper(t.Name[0])))
+        {
+            var entity = new OrmEntity { Name = type.Name, FilePath = filePath, Line = type.Line };
+            string? framework = null;
+            string? bunTable = null;
+            var untagged = new List<(string Name, int Line)>();
+
+            // Fields of inline struct types are not the entity's columns
+            foreach (var field in type.Fields.Where(f => !f.Inline))
+            {
+                var tags = GoStructTags.Parse(field.Tag ?? string.Empty);
+                var name = field.Name;
+                if (!field.Embedded)
+                {
+                    if (!char.IsUpper(name[0]))
+                        continue;
+
+                    if (tags.TryGetValue("gorm", out var gorm))
+                    {
+                        framework = "gorm";
+                        var settings = gorm.Split(';').Select(s => s.Trim()).ToList();
+                        if (settings.Contains("-"))
+                            continue;
+                        var column = settings.FirstOrDefault(s => s.StartsWith("column:", StringComparison.OrdinalIgnoreCase))?[7..];
+                        entity.Columns.Add(Column(name, column ?? SnakeCase(name), column != null,
+                            settings.Any(s => s.Equals("primaryKey", StringComparison.OrdinalIgnoreCase) || s.Equals("primary_key", StringComparison.OrdinalIgnoreCase)),
+                            filePath, field.Line));
+                    }
+                    else if (tags.TryGetValue("db", out var db))
+                    {
+                        framework ??= "sqlx";
+                        var column = db.Split(',')[0];
+                        if (column != "-")
+                            entity.Columns.Add(Column(name, column.Length > 0 ? column : name.ToLowerInvariant(), column.Length > 0, false, filePath, field.Line));
+                    }
+                    else if (tags.TryGetValue("bun", out var bun))
+                    {
+                        framework = "bun";
+                        var options = bun.Split(',');
+                        if (options[0] != "-")
+                            entity.Columns.Add(Column(name, options[0].Length > 0 ? options[0] : SnakeCase(name), options[0].Length > 0,
+                                options.Contains("pk"), filePath, field.Line));
+                    }
+                    else if (IsColumnType(field.Type))
+                    {
+                        untagged.Add((name, field.Line));
+                    }
+                }
+                else
+                {
+                    var embedded = field.Type.TrimStart('*');
+                    if (embedded == "gorm.Model")
+                    {
+                        framework = "gorm";
+                        entity.Columns.AddRange(GormModelColumns.Select(c => Column(c == "id" ? "ID" : PascalCase(c), c, false, c == "id", filePath, field.Line)));
+                    }
+                    else if (embedded == "bun.BaseModel" && tags.TryGetValue("bun", out var bun))
+                    {
+                        framework = "bun";
+                        bunTable = bun.Split(',').FirstOrDefault(o => o.StartsWith("table:", StringComparison.Ordinal))?[6..];
+                    }
+                }
+            }
+
+            if (framework == null)
+                continue;
+
+            // gorm and bun map untagged fields by convention; sqlx lower-cases them
+            foreach (var (name, line) in untagged)
+            {
+                entity.Columns.Add(Column(name, framework == "sqlx" ? name.ToLowerInvariant() : SnakeCase(name), false, framework == "gorm" && name == "ID", filePath, line));
+            }
+            entity.Columns = entity.Columns.OrderBy(c => c.Line).ToList();
+            entity.Framework = framework;
+            if (bunTable != null)
+            {
+                entity.Table = bunTable;
+                entity.TableSource = "bun.BaseModel tag";
+            }
+            else
+            {
+                entity.Table = Pluralize(SnakeCase(entity.Name));
+                entity.TableSource = "convention";
+            }
+            entities.Add(entity);
+        }
+
+        return (entities, tableNames);
+    }
+
+    /// <summary>
+    /// EF Core declarations in one C# file, resolved across files by <see cref="ResolveCSharp"/>
+    /// </summary>
+    public static CSharpOrmFile ParseCSharp(string filePath, string[] lines)
+    {
+        var file = new CSharpOrmFile();
+        var masked = DataFlowScanner.MaskLines(lines, ".cs");
+
+        // Classes still open, innermost last, so a property belongs to the latest; a class closes when the
+        // depth falls back after its body opened
+        var open = new List<(CSharpClass Class, int Depth, bool Opened)>();
+        var depth = 0;
+        var pendingAttributes = new List<(string Name, string Args)>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var attributes = CsAttribute.Matches(lines[i]).Select(m => (Name: m.Groups["name"].Value, Args: m.Groups["args"].Value)).ToList();
+            var classMatch = CsClass.Match(masked[i]);
+            if (classMatch.Success)
+            {
+                var csClass = new CSharpClass
+                {
+                    Name = classMatch.Groups["name"].Value,
+                    FilePath = filePath,
+                    Line = i + 1,
+                    Table = pendingAttributes.Concat(attributes).Where(a => a.Name == "Table").Select(a => QuotedArgument.Match(a.Args))
+                        .Where(m => m.Success).Select(m => m.Groups["value"].Value).FirstOrDefault()
+                };
+                file.Classes.Add(csClass);
+                open.Add((csClass, depth, false));
+                pendingAttributes.Clear();
+            }
+            else if (CsProperty.Match(masked[i]) is { Success: true } property && open.Count > 0)
+            {
+                var all = pendingAttributes.Concat(attributes).ToList();
+                var type = property.Groups["type"].Value.Trim();
+                open[^1].Class.Properties.Add(new CSharpProperty
+                {
+                    Name = property.Groups["name"].Value,
+                    Type = type,
+                    Line = i + 1,
+                    Column = all.Where(a => a.Name == "Column").Select(a => QuotedArgument.Match(a.Args))
+                        .Where(m => m.Success).Select(m => m.Groups["value"].Value).FirstOrDefault(),
+                    Key = all.Any(a => a.Name == "Key"),
+                    NotMapped = all.Any(a => a.Name == "NotMapped")
+                        || property.Groups["modifiers"].Value.Contains("virtual") || property.Groups["modifiers"].Value.Contains("static")
+                        || CsCollection.IsMatch(type)
+                });
+                pendingAttributes.Clear();
+            }
+            else if (attributes.Count > 0 && masked[i].TrimStart().StartsWith('['))
+            {
+                pendingAttributes.AddRange(attributes);
+            }
+            else if (masked[i].Trim().Length > 0)
+            {
+                pendingAttributes.Clear();
+            }
+
+            depth += masked[i].Count(c => c == '{') - masked[i].Count(c => c == '}');
+            for (var k = open.Count - 1; k >= 0; k--)
+            {
+                if (depth > open[k].Depth)
+                    open[k] = (open[k].Class, open[k].Depth, true);
+                else if (open[k].Opened || masked[i].Contains(';'))
+                    open.RemoveAt(k);
+            }
+
+            foreach (Match dbSet in CsDbSet.Matches(masked[i]))
+                file.DbSets.Add((dbSet.Groups["entity"].Value, dbSet.Groups["name"].Value));
+        }
+
+        ParseFluent(file, lines, masked);
+        return file;
+    }
+
+    /// <summary>
+    /// Fluent configuration: statements are read whole, since chains like .Property(...).HasColumnName(...)
+    /// usually span lines
+    /// </summary>
+    private static void ParseFluent(CSharpOrmFile file, string[] lines, string[] masked)
+    {
+        string? entity = null;
+        var statement = new StringBuilder();
+        var start = 0;
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (statement.Length == 0)
+                start = i;
+            statement.Append(lines[i].Trim()).Append(' ');
+
+            var end = masked[i].TrimEnd();
+            if (!(end.EndsWith(';') || end.EndsWith('{') || end.EndsWith('}')) && i < lines.Length - 1)
+                continue;
+
+            var text = statement.ToString();
+            statement.Clear();
+
+            if (CsFluentEntity.Match(text) is { Success: true } entityMatch)
+                entity = entityMatch.Groups["entity"].Value;
+            if (entity == null)
+                continue;
+
+            file.FluentEntities.Add(entity);
+            if (CsToTable.Match(text) is { Success: true } toTable)
+                file.FluentTables[entity] = (toTable.Groups["table"].Value, start + 1);
+
+            foreach (Match call in CsPropertyCall.Matches(text))
+            {
+                var member = call.Groups["member"].Value;
+                switch (call.Groups["call"].Value)
+                {
+                    case "Ignore":
+                        file.FluentIgnored.Add((entity, member));
+                        break;
+                    case "HasKey":
+                        file.FluentKeys.Add((entity, member));
+                        break;
+                    case "Property" when CsColumnName.Match(text[(call.Index + call.Length)..]) is { Success: true } column:
+                        file.FluentColumns[(entity, member)] = (column.Groups["column"].Value, start + 1);
+                        break;
+                }
+            }
+        }
+    }
+
+    /// <summary>
+    /// EF Core entities: classes with [Table], exposed as a DbSet or configured fluently. The table comes
+    /// from ToTable, then [Table], then the DbSet property name, then the class name; a column from
+    /// HasColumnName, then [Column], then the property name.
+    /// </summary>
+    public static List<OrmEntity> ResolveCSharp(IReadOnlyList<CSharpOrmFile> files)
+    {
+        var classes = files.SelectMany(f => f.Classes).GroupBy(c => c.Name).ToDictionary(g => g.Key, g => g.OrderByDescending(c => c.Properties.Count).First());
+        var dbSets = files.SelectMany(f => f.DbSets).GroupBy(d => d.Entity).ToDictionary(g => g.Key, g => g.First().Name);
+        var fluentTables = files.SelectMany(f => f.FluentTables).GroupBy(t => t.Key).ToDictionary(g => g.Key, g => g.First().Value);
+        var fluentColumns = files.SelectMany(f => f.FluentColumns).GroupBy(c => c.Key).ToDictionary(g => g.Key, g => g.First().Value);
+        var ignored = files.SelectMany(f => f.FluentIgnored).ToHashSet();
+        var keys = files.SelectMany(f => f.FluentKeys).ToHashSet();
+
+        var names = classes.Values.Where(c => c.Table != null).Select(c => c.Name)
+            .Concat(dbSets.Keys)
+            .Concat(files.SelectMany(f => f.FluentEntities))
+            .Where(classes.ContainsKey)
+            .ToHashSet(StringComparer.Ordinal);
+
+        var entities = new List<OrmEntity>();
+        foreach (var name in names.OrderBy(n => n, StringComparer.Ordinal))
+        {
+            var csClass = classes[name];
+            var entity = new OrmEntity { Name = name, Framework = "ef-core", FilePath = csClass.FilePath, Line = csClass.Line };
+            if (fluentTables.TryGetValue(name, out var fluentTable))
+                (entity.Table, entity.TableSource) = (fluentTable.Table, "ToTable");
+            else if (csClass.Table != null)
+                (entity.Table, entity.TableSource) = (csClass.Table, "[Table]");
+            else if (dbSets.TryGetValue(name, out var dbSet))
+                (entity.Table, entity.TableSource) = (dbSet, "DbSet name");
+            else
+                (entity.Table, entity.TableSource) = (name, "convention");
+
+            foreach (var property in csClass.Properties)
+            {
+                // Navigation properties reference other entities rather than columns
+                if (property.NotMapped || ignored.Contains((name, property.Name)) || names.Contains(property.Type.TrimEnd('?')))
+                    continue;
+
+                var fluent = fluentColumns.TryGetValue((name, property.Name), out var c) ? c.Column : null;
+                var column = fluent ?? property.Column;
+                entity.Columns.Add(new OrmColumn
+                {
+                    Member = property.Name,
+                    Column = column ?? property.Name,
+                    Explicit = column != null,
+                    PrimaryKey = property.Key || keys.Contains((name, property.Name))
+                        || !keys.Any(k => k.Entity == name) && (property.Name == "Id" || property.Name == name + "Id"),
+                    FilePath = csClass.FilePath,
+                    Line = property.Line
+                });
+            }
+            entities.Add(entity);
+        }
+        return entities;
+    }
+
+    /// <summary>
+    /// SQLAlchemy declarative models (<c>__tablename__</c> with Column or mapped_column attributes) and Django
+    /// models (subclasses of models.Model)
+    /// </summary>
+    public static List<OrmEntity> ParsePython(string filePath, string[] lines)
+    {
+        var entities = new List<OrmEntity>();
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var header = PyClass.Match(lines[i]);
+            if (!header.Success)
+                continue;
+
+            var indent = header.Groups["indent"].Value.Length;
+            var django = Regex.IsMatch(header.Groups["bases"].Value, @"\bmodels\.Model\b");
+            var entity = new OrmEntity { Name = header.Groups["name"].Value, FilePath = filePath, Line = i + 1 };
+            string? table = null;
+            var hasPrimaryKey = false;
+
+            for (var j = i + 1; j < lines.Length; j++)
+            {
+                var line = lines[j];
+                if (line.Trim().Length == 0 || line.TrimStart().StartsWith('#'))
+                    continue;
+                if (line.Length - line.TrimStart().Length <= indent)
+                    break;
+
+                if (PyTableName.Match(line) is { Success: true } tableName)
+                {
+                    table = tableName.Groups["table"].Value;
+                }
+                else if (django && PyDbTable.Match(line) is { Success: true } dbTable)
+                {
+                    table = dbTable.Groups["table"].Value;
+                }
+                else if (!django && PyAlchemyColumn.Match(line) is { Success: true } column)
+                {
+                    var name = column.Groups["name"].Value;
+                    var explicitName = PyFirstString.Match(column.Groups["args"].Value);
+                    entity.Columns.Add(Column(name, explicitName.Success ? explicitName.Groups["column"].Value : name, explicitName.Success,
+                        column.Groups["args"].Value.Contains("primary_key=True"), filePath, j + 1));
+                }
+                else if (django && PyDjangoField.Match(line) is { Success: true } field)
+                {
+                    var name = field.Groups["name"].Value;
+                    var dbColumn = PyDbColumn.Match(field.Groups["args"].Value);
+                    var relation = field.Groups["field"].Value is "ForeignKey" or "OneToOneField";
+                    var primary = field.Groups["args"].Value.Contains("primary_key=True");
+                    hasPrimaryKey |= primary;
+                    entity.Columns.Add(Column(name, dbColumn.Success ? dbColumn.Groups["column"].Value : relation ? name + "_id" : name,
+                        dbColumn.Success, primary, filePath, j + 1));
+                }
+            }
+
+            if (django)
+            {
+                if (!hasPrimaryKey)
+                    entity.Columns.Insert(0, Column("id", "id", false, true, filePath, i + 1));
+                entity.Framework = "django";
+                entity.Table = table ?? $"{DjangoApp(filePath)}_{entity.Name.ToLowerInvariant()}";
+                entity.TableSource = table != null ? "Meta.db_table" : "convention";
+            }
+            else if (table != null)
+            {
+                entity.Framework = "sqlalchemy";
+                entity.Table = table;
+                entity.TableSource = "__tablename__";
+            }
+            else
+            {
+                continue;
+            }
+            entities.Add(entity);
+        }
+        return entities;
+    }
+
+    /// <summary>
+    /// snake_case of a Go or C# name the way gorm derives columns: UserID becomes user_id
+    /// </summary>
+    public static string SnakeCase(string name) => SnakeBoundary.Replace(name, "_").ToLowerInvariant();
+
+    /// <summary>
+    /// English plural for a table name derived by convention
+    /// </summary>
+    public static string Pluralize(string name)
+    {
+        if (Regex.IsMatch(name, @"[^aeiou]y$"))
+            return name[..^1] + "ies";
+        if (Regex.IsMatch(name, @"(?:s|x|z|ch|sh)$"))
+            return name + "es";
+        return name + "s";
+    }
+
+    // Scalars, qualified types (time.Time, sql.NullString) and byte slices are columns; other structs and slices are associations
+    private static bool IsColumnType(string type)
+    {
+        var bare = type.TrimStart('*');
+        return GoScalarTypes.Contains(bare) || bare.Contains('.') || bare == "[]byte" || char.IsLower(bare[0]) && !bare.StartsWith('[') && !bare.StartsWith("map[");
+    }
+
+    private static string PascalCase(string snake) =>
+        string.Concat(snake.Split('_').Select(p => p.Length == 0 ? p : char.ToUpperInvariant(p[0]) + p[1..]));
+
+    // Django prefixes tables with the app label, the directory holding models.py (or the models package)
+    private static string DjangoApp(string filePath)
+    {
+        var directory = Path.GetDirectoryName(filePath) ?? string.Empty;
+        if (Path.GetFileName(directory) == "models")
+            directory = Path.GetDirectoryName(directory) ?? string.Empty;
+        return Path.GetFileName(directory).ToLowerInvariant();
+    }
+
+    private static OrmColumn Column(string member, string column, bool isExplicit, bool primaryKey, string filePath, int line) => new()
+    {
+        Member = member,
+        Column = column,
+        Explicit = isExplicit,
+        PrimaryKey = primaryKey,
+        FilePath = filePath,
+        Line = line
+    };
+}
+
+/// <summary>
+/// EF Core declarations found in one C# file
+/// </summary>
+public class CSharpOrmFile
+{
+    public List<CSharpClass> Classes { get; } = new();
+    public List<(string Entity, string Name)> DbSets { get; } = new();
+    public HashSet<string> FluentEntities { get; } = new(StringComparer.Ordinal);
+    public Dictionary<string, (string Table, int Line)> FluentTables { get; } = new(StringComparer.Ordinal);
+    public Dictionary<(string Entity, string Member), (string Column, int Line)> FluentColumns { get; } = new();
+    public HashSet<(string Entity, string Member)> FluentIgnored { get; } = new();
+    public HashSet<(string Entity, string Member)> FluentKeys { get; } = new();
+}
+
+public class CSharpClass
+{
+    public string Name { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// From a [Table] attribute
+    /// </summary>
+    public string? Table { get; set; }
+
+    public List<CSharpProperty> Properties { get; } = new();
+}
+
+public class CSharpProperty
+{
+    public string Name { get; set; } = string.Empty;
+    public string Type { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// From a [Column] attribute
+    /// </summary>
+    public string? Column { get; set; }
+
+    public bool Key { get; set; }
+
+    /// <summary>
+    /// [NotMapped], static, virtual (lazy-loaded navigation) or a collection
+    /// </summary>
+    public bool NotMapped { get; set; }
+}
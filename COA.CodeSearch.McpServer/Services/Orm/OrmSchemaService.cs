@@ -0,0 +1,417 @@
+using System.Collections.Concurrent;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Orm;
+
+/// <summary>
+/// Builds the entity map from the indexed Go, C# and Python files and finds column usages: uses of the mapped
+/// member through the index's identifiers, and string literals naming the column (raw SQL, gorm Update calls),
+/// each classed as a write or a read.
+/// </summary>
+public class OrmSchemaService : IOrmSchemaService
+{
+    private static readonly string[] EntityExtensions = { ".go", ".cs", ".py" };
+    private static readonly Regex SqlClause = new(
+        @"\b(?<clause>SET|WHERE|SELECT|INSERT\s+INTO|VALUES|RETURNING|ORDER\s+BY|GROUP\s+BY|JOIN|ON|HAVING)\b", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex MappingCall = new(@"\b(?:HasColumnName|ToTable|Table|Column|mapped_column)\s*\(|\bdb_(?:column|table)\s*=", RegexOptions.Compiled);
+    private static readonly Regex UpdateCall = new(@"\.(?:Update|Updates|UpdateColumn|UpdateColumns|Set|update|values|Insert|insert)\s*\(", RegexOptions.Compiled);
+
+    // Calls whose keyword or lambda arguments filter rows instead of assigning them
+    private static readonly HashSet<string> QueryCalls = new(StringComparer.Ordinal)
+    {
+        "filter", "filter_by", "exclude", "get", "where", "order_by", "Where", "First", "FirstOrDefault", "Single",
+        "SingleOrDefault", "Any", "Count", "OrderBy", "OrderByDescending", "Find", "Select"
+    };
+
+    // SQL spans lines; a clause this far above the column still applies to it
+    private const int SqlWindowLines = 6;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly ILogger<OrmSchemaService> _logger;
+    private readonly ConcurrentDictionary<string, (DateTime Stamp, List<OrmEntity> Entities)> _entities = new(StringComparer.OrdinalIgnoreCase);
+
+    public OrmSchemaService(ISQLiteSymbolService sqliteService, ILogger<OrmSchemaService> logger)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    public async Task<List<OrmEntity>> GetEntitiesAsync(string workspacePath, CancellationToken cancellationToken = default)
+    {
+        var stamp = DatabaseStamp(workspacePath);
+        if (_entities.TryGetValue(workspacePath, out var cached) && cached.Stamp == stamp)
+            return cached.Entities;
+
+        var entities = new List<OrmEntity>();
+        var goTables = new Dictionary<(string Package, string Type), string>();
+        var csharp = new List<CSharpOrmFile>();
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var extension = Path.GetExtension(file.Path).ToLowerInvariant();
+            if (!EntityExtensions.Contains(extension))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var fullPath = FullPath(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null || !OrmSchemaParser.MayDeclareEntities(extension, content))
+                continue;
+
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            switch (extension)
+            {
+                case ".go":
+                    var (structs, tableNames) = OrmSchemaParser.ParseGo(file.Path, lines);
+                    entities.AddRange(structs);
+                    foreach (var (type, table) in tableNames)
+                        goTables[(Package(file.Path), type)] = table;
+                    break;
+                case ".cs":
+                    csharp.Add(OrmSchemaParser.ParseCSharp(file.Path, lines));
+                    break;
+                case ".py":
+                    entities.AddRange(OrmSchemaParser.ParsePython(file.Path, lines));
+                    break;
+            }
+        }
+
+        // A TableName method overrides the conventional name; it lives in the struct's package
+        foreach (var entity in entities.Where(e => e.FilePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase)))
+        {
+            if (goTables.TryGetValue((Package(entity.FilePath), entity.Name), out var table))
+            {
+                entity.Table = table;
+                entity.TableSource = "TableName()";
+            }
+        }
+        entities.AddRange(OrmSchemaParser.ResolveCSharp(csharp));
+
+        entities = entities.OrderBy(e => e.Table, StringComparer.OrdinalIgnoreCase).ThenBy(e => e.Name, StringComparer.Ordinal).ToList();
+        _entities[workspacePath] = (stamp, entities);
+        _logger.LogDebug("ORM entity map for {Workspace}: {Count} entities", workspacePath, entities.Count);
+        return entities;
+    }
+
+    public async Task<ColumnUsageReport> FindColumnUsagesAsync(string workspacePath, string column, string access, string? filePattern,
+        int maxResults, CancellationToken cancellationToken = default)
+    {
+        var entities = await GetEntitiesAsync(workspacePath, cancellationToken);
+        var (table, name) = SplitColumn(column);
+        var report = new ColumnUsageReport();
+
+        foreach (var entity in entities.Where(e => table == null || e.Table.Equals(table, StringComparison.OrdinalIgnoreCase)))
+        {
+            var mapped = entity.Columns.Where(c => c.Column.Equals(name, StringComparison.OrdinalIgnoreCase)).ToList();
+            if (mapped.Count > 0)
+            {
+                report.Mappings.Add(new OrmEntity
+                {
+                    Name = entity.Name,
+                    Table = entity.Table,
+                    Framework = entity.Framework,
+                    TableSource = entity.TableSource,
+                    FilePath = entity.FilePath,
+                    Line = entity.Line,
+                    Columns = mapped
+                });
+            }
+        }
+
+        var filter = string.IsNullOrWhiteSpace(filePattern) ? null : GlobToRegex(filePattern);
+        var files = new FileCache(_sqliteService, workspacePath, cancellationToken);
+        var declarations = entities.SelectMany(e => e.Columns).Select(c => (FullPath(workspacePath, c.FilePath), c.Line)).ToHashSet();
+        var seen = new HashSet<(string, int)>();
+
+        bool Add(ColumnUsage usage)
+        {
+            if (access != "all" && usage.Access != access)
+                return true;
+            if (!seen.Add((FullPath(workspacePath, usage.FilePath), usage.Line)))
+                return true;
+            if (report.Usages.Count == maxResults)
+            {
+                report.Truncated = true;
+                return false;
+            }
+            report.Usages.Add(usage);
+            return true;
+        }
+
+        foreach (var mapping in report.Mappings)
+        {
+            var member = mapping.Columns[0];
+            var label = $"{mapping.Table}.{member.Column}";
+            var targetIds = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, member.Member, caseSensitive: true, cancellationToken))
+                .Where(s => FullPath(workspacePath, s.FilePath) == FullPath(workspacePath, member.FilePath) && s.StartLine == member.Line)
+                .Select(s => s.Id)
+                .ToHashSet();
+
+            foreach (var identifier in await _sqliteService.GetIdentifiersByNameAsync(workspacePath, member.Member, caseSensitive: true, cancellationToken))
+            {
+                var fullPath = FullPath(workspacePath, identifier.FilePath);
+                if (declarations.Contains((fullPath, identifier.StartLine)))
+                    continue;
+                if (filter != null && !filter.IsMatch(Relative(workspacePath, identifier.FilePath)))
+                    continue;
+                if (!string.IsNullOrEmpty(identifier.TargetSymbolId) && targetIds.Count > 0 && !targetIds.Contains(identifier.TargetSymbolId))
+                    continue;
+
+                var lines = await files.ReadAsync(identifier.FilePath);
+                if (lines == null || identifier.StartLine < 1 || identifier.StartLine > lines.Length)
+                    continue;
+
+                // Unresolved uses of a common member name count only where the entity itself is mentioned
+                if (string.IsNullOrEmpty(identifier.TargetSymbolId) && fullPath != FullPath(workspacePath, mapping.FilePath)
+                    && !lines.Any(l => Regex.IsMatch(l, $@"\b{Regex.Escape(mapping.Name)}\b")))
+                    continue;
+
+                var line = lines[identifier.StartLine - 1];
+                var masked = DataFlowScanner.Mask(line, Path.GetExtension(identifier.FilePath));
+                if (!Add(new ColumnUsage
+                {
+                    FilePath = identifier.FilePath,
+                    Line = identifier.StartLine,
+                    Code = line.Trim(),
+                    Access = MemberAccess(masked, member.Member, Path.GetExtension(identifier.FilePath)),
+                    Via = "member",
+                    Column = label
+                }))
+                    return Sorted(report);
+            }
+        }
+
+        await ScanSqlAsync(workspacePath, table, name, report, declarations, filter, files, Add, cancellationToken);
+        return Sorted(report);
+    }
+
+    /// <summary>
+    /// String literals naming the column, in files that mention it
+    /// </summary>
+    private async Task ScanSqlAsync(string workspacePath, string? table, string column, ColumnUsageReport report,
+        HashSet<(string, int)> declarations, Regex? filter, FileCache files, Func<ColumnUsage, bool> add, CancellationToken cancellationToken)
+    {
+        var tables = report.Mappings.Select(m => m.Table).Distinct(StringComparer.OrdinalIgnoreCase).ToList();
+        var unambiguous = table == null && tables.Count <= 1;
+        var columnPattern = new Regex($@"(?<![\w.]){Regex.Escape(column)}\b|\.{Regex.Escape(column)}\b", RegexOptions.IgnoreCase);
+
+        foreach (var path in await CandidateFilesAsync(workspacePath, column, cancellationToken))
+        {
+            if (filter != null && !filter.IsMatch(Relative(workspacePath, path)))
+                continue;
+            var lines = await files.ReadAsync(path);
+            if (lines == null)
+                continue;
+
+            report.FilesScanned++;
+            var fullPath = FullPath(workspacePath, path);
+            var masked = DataFlowScanner.MaskLines(lines, Path.GetExtension(path));
+            for (var i = 0; i < lines.Length; i++)
+            {
+                // Mapping declarations name the column without touching it
+                if (declarations.Contains((fullPath, i + 1)) || MappingCall.IsMatch(masked[i]))
+                    continue;
+
+                var literal = StringsOn(lines[i], masked[i]).FirstOrDefault(s => columnPattern.IsMatch(s.Text));
+                if (literal.Text == null)
+                    continue;
+
+                // A table given, or several tables sharing the column name: the SQL or the call has to name the
+                // table or its entity
+                var window = string.Join("\n", lines.Skip(Math.Max(0, i - SqlWindowLines)).Take(Math.Min(i, SqlWindowLines) + 1));
+                bool Mentions(string word) => Regex.IsMatch(window, $@"\b{Regex.Escape(word)}\b", RegexOptions.IgnoreCase);
+                var named = report.Mappings.FirstOrDefault(m => Mentions(m.Table) || Mentions(m.Name))?.Table
+                    ?? (table != null && Mentions(table) ? table : null);
+                if (named == null && !unambiguous)
+                    continue;
+
+                var label = (named ?? tables.FirstOrDefault()) is { } t ? $"{t}.{column}" : column;
+                var access = SqlAccess(lines, masked, i, literal.Start + columnPattern.Match(literal.Text).Index);
+                if (!add(new ColumnUsage { FilePath = path, Line = i + 1, Code = lines[i].Trim(), Access = access, Via = "sql", Column = label }))
+                    return;
+            }
+        }
+    }
+
+    /// <summary>
+    /// A member use writes when it is assigned, incremented, set through a keyword argument or Go struct literal
+    /// key, or named in an EF ExecuteUpdate SetProperty; filter arguments and everything else read
+    /// </summary>
+    private static string MemberAccess(string masked, string member, string extension)
+    {
+        var assignment = extension.Equals(".go", StringComparison.OrdinalIgnoreCase)
+            ? @"^\s*(?:[-+*/%|&^]?=(?![=>])|\+\+|--|:(?![:=]))"
+            : @"^\s*(?:[-+*/%|&^]?=(?![=>])|\+\+|--)";
+
+        foreach (var index in DataFlowScanner.FindOccurrences(masked, member).Concat(MemberAccesses(masked, member)).Distinct())
+        {
+            var after = masked[(index + member.Length)..];
+            if (Regex.IsMatch(masked[..index], @"SetProperty\s*\(\s*\w+\s*=>\s*\w+\.$"))
+                return "write";
+            if (!Regex.IsMatch(after, assignment))
+                continue;
+
+            var call = DataFlowScanner.EnclosingCall(masked, index);
+            if (call != null && QueryCalls.Contains(call.Callee[(call.Callee.LastIndexOf('.') + 1)..]))
+                continue;
+            return "write";
+        }
+        return "read";
+    }
+
+    // x.Member occurrences, which FindOccurrences leaves out as member accesses
+    private static IEnumerable<int> MemberAccesses(string masked, string member) =>
+        Regex.Matches(masked, $@"(?<=\.\s*){Regex.Escape(member)}\b").Select(m => m.Index);
+
+    /// <summary>
+    /// SQL writes the column when the nearest clause before it is SET or INSERT INTO (or its VALUES); with no
+    /// clause in sight, an update call on the line (gorm Update("is_active", ...)) writes. Only string text is
+    /// read for clauses, so a .Where( call is not taken for SQL
+    /// </summary>
+    private static string SqlAccess(string[] lines, string[] masked, int lineIndex, int columnIndex)
+    {
+        var sql = new List<string>();
+        for (var i = Math.Max(0, lineIndex - SqlWindowLines); i <= lineIndex; i++)
+        {
+            sql.AddRange(StringsOn(lines[i], masked[i])
+                .Where(s => i < lineIndex || s.Start < columnIndex)
+                .Select(s => i < lineIndex || s.Start + s.Text.Length <= columnIndex ? s.Text : s.Text[..(columnIndex - s.Start)]));
+        }
+
+        var clause = SqlClause.Matches(string.Join("\n", sql)).LastOrDefault()?.Groups["clause"].Value.ToUpperInvariant();
+        if (clause != null)
+            return clause == "SET" || clause.StartsWith("INSERT") || clause == "VALUES" ? "write" : "read";
+        return UpdateCall.IsMatch(masked[lineIndex]) ? "write" : "read";
+    }
+
+    private static IEnumerable<(string Text, int Start)> StringsOn(string line, string masked)
+    {
+        for (var i = 0; i < masked.Length; i++)
+        {
+            if (masked[i] is not ('"' or '\'' or '`'))
+                continue;
+            var close = masked.IndexOf(masked[i], i + 1);
+            if (close < 0)
+            {
+                // A raw string continuing on the next line
+                yield return (line[(i + 1)..], i + 1);
+                yield break;
+            }
+            yield return (line[(i + 1)..close], i + 1);
+            i = close;
+        }
+
+        // Lines inside a multi-line raw string are masked whole
+        if (masked.Trim().Length == 0 && line.Trim().Length > 0)
+            yield return (line, 0);
+    }
+
+    /// <summary>
+    /// Files that may name the column: a full-text match when the name is searchable, else every indexed file
+    /// </summary>
+    private async Task<List<string>> CandidateFilesAsync(string workspacePath, string column, CancellationToken cancellationToken)
+    {
+        if (column.Length >= 3 && Regex.IsMatch(column, @"^\w+$"))
+        {
+            try
+            {
+                var matched = await _sqliteService.SearchWithFTS5Async(workspacePath, $"\"{column}\"", 5000, null, cancellationToken);
+                if (matched.Count > 0)
+                    return matched.Select(f => f.Path).ToList();
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogDebug(ex, "Full-text candidate search failed, scanning all files");
+            }
+        }
+
+        return (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken)).Select(f => f.Path).ToList();
+    }
+
+    private static ColumnUsageReport Sorted(ColumnUsageReport report)
+    {
+        report.Usages = report.Usages
+            .OrderBy(u => u.Access == "write" ? 0 : 1)
+            .ThenBy(u => u.FilePath, StringComparer.Ordinal)
+            .ThenBy(u => u.Line)
+            .ToList();
+        return report;
+    }
+
+    private static (string? Table, string Column) SplitColumn(string column)
+    {
+        var trimmed = column.Trim().Trim('"', '`', '[', ']');
+        var dot = trimmed.LastIndexOf('.');
+        return dot > 0 ? (trimmed[..dot].Trim('"', '`', '[', ']'), trimmed[(dot + 1)..].Trim('"', '`', '[', ']')) : (null, trimmed);
+    }
+
+    // Go methods live in the package of their receiver type, which is the file's directory
+    private static string Package(string filePath) => Path.GetDirectoryName(filePath)?.Replace('\\', '/') ?? string.Empty;
+
+    // The entity map is rebuilt when the database or its write-ahead log changes
+    private DateTime DatabaseStamp(string workspacePath)
+    {
+        var path = _sqliteService.GetDatabasePath(workspacePath);
+        var stamp = File.Exists(path) ? File.GetLastWriteTimeUtc(path) : DateTime.MinValue;
+        var wal = path + "-wal";
+        return File.Exists(wal) && File.GetLastWriteTimeUtc(wal) > stamp ? File.GetLastWriteTimeUtc(wal) : stamp;
+    }
+
+    private static string FullPath(string workspacePath, string filePath) =>
+        Path.GetFullPath(Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath));
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    // "*.cs" matches file names anywhere; patterns with a slash match the relative path
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    /// <summary>
+    /// File lines from disk, falling back to the content stored in the index
+    /// </summary>
+    private sealed class FileCache
+    {
+        private readonly ISQLiteSymbolService _sqliteService;
+        private readonly string _workspacePath;
+        private readonly CancellationToken _cancellationToken;
+        private readonly Dictionary<string, string[]?> _files = new(StringComparer.OrdinalIgnoreCase);
+
+        public FileCache(ISQLiteSymbolService sqliteService, string workspacePath, CancellationToken cancellationToken)
+        {
+            _sqliteService = sqliteService;
+            _workspacePath = workspacePath;
+            _cancellationToken = cancellationToken;
+        }
+
+        public async Task<string[]?> ReadAsync(string filePath)
+        {
+            if (_files.TryGetValue(filePath, out var cached))
+                return cached;
+
+            var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(_workspacePath, filePath);
+            string[]? lines = null;
+            if (File.Exists(fullPath))
+            {
+                lines = await File.ReadAllLinesAsync(fullPath, _cancellationToken);
+            }
+            else if (await _sqliteService.GetFileByPathAsync(_workspacePath, filePath, _cancellationToken) is { Content: { } content })
+            {
+                lines = content.Replace("\r\n", "\n").Split('\n');
+            }
+            _files[filePath] = lines;
+            return lines;
+        }
+    }
+}
@@ -0,0 +1,110 @@
+using System.Collections.Concurrent;
+using System.ComponentModel.DataAnnotations;
+using System.Reflection;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Tools;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Maps deprecated parameter names (marked with <see cref="DeprecatedParameterAttribute"/>) onto their replacements
+/// </summary>
+public class ParameterMigrationService : IParameterMigrationService
+{
+    private static readonly ConcurrentDictionary<Type, (PropertyInfo Legacy, PropertyInfo Replacement, DeprecatedParameterAttribute Attribute)[]> _aliasCache = new();
+
+    private readonly ILogger<ParameterMigrationService> _logger;
+    private readonly bool _acceptDeprecated;
+    private readonly ConcurrentDictionary<string, byte> _warnedOnce = new();
+
+    public ParameterMigrationService(IConfiguration configuration, ILogger<ParameterMigrationService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _acceptDeprecated = configuration.GetValue("CodeSearch:Schema:AcceptDeprecatedParameters", true);
+    }
+
+    public IReadOnlyList<DeprecationNotice> Migrate<T>(string toolName, T parameters) where T : class
+    {
+        if (parameters == null)
+            return Array.Empty<DeprecationNotice>();
+
+        var aliases = _aliasCache.GetOrAdd(parameters.GetType(), FindAliases);
+        if (aliases.Length == 0)
+            return Array.Empty<DeprecationNotice>();
+
+        var notices = new List<DeprecationNotice>();
+        foreach (var (legacy, replacement, attribute) in aliases)
+        {
+            var legacyValue = legacy.GetValue(parameters);
+            if (IsUnset(legacyValue, legacy.PropertyType))
+                continue;
+
+            var notice = new DeprecationNotice
+            {
+                ToolName = toolName,
+                Parameter = ToCamelCase(legacy.Name),
+                ReplacedBy = ToCamelCase(replacement.Name),
+                Since = attribute.Since,
+                RemovedIn = attribute.RemovedIn
+            };
+
+            if (!_acceptDeprecated)
+            {
+                throw new ValidationException(notice.ToString());
+            }
+
+            // The new name wins when a client sends both
+            if (IsUnset(replacement.GetValue(parameters), replacement.PropertyType))
+            {
+                replacement.SetValue(parameters, legacyValue);
+            }
+            legacy.SetValue(parameters, legacy.PropertyType.IsValueType ? Activator.CreateInstance(legacy.PropertyType) : null);
+
+            if (_warnedOnce.TryAdd($"{toolName}:{legacy.Name}", 0))
+            {
+                _logger.LogWarning("Client used deprecated parameter: {Notice}", notice.ToString());
+            }
+            notices.Add(notice);
+        }
+
+        return notices;
+    }
+
+    private (PropertyInfo, PropertyInfo, DeprecatedParameterAttribute)[] FindAliases(Type type)
+    {
+        var result = new List<(PropertyInfo, PropertyInfo, DeprecatedParameterAttribute)>();
+        foreach (var property in type.GetProperties(BindingFlags.Public | BindingFlags.Instance))
+        {
+            var attribute = property.GetCustomAttribute<DeprecatedParameterAttribute>();
+            if (attribute == null || !property.CanWrite)
+                continue;
+
+            var replacement = type.GetProperty(attribute.ReplacedBy, BindingFlags.Public | BindingFlags.Instance);
+            if (replacement == null || !replacement.CanWrite || !replacement.PropertyType.IsAssignableFrom(property.PropertyType))
+            {
+                _logger.LogError("Deprecated parameter {Type}.{Property} points at missing or incompatible replacement {Replacement}",
+                    type.Name, property.Name, attribute.ReplacedBy);
+                continue;
+            }
+
+            result.Add((property, replacement, attribute));
+        }
+
+        return result.ToArray();
+    }
+
+    private static bool IsUnset(object? value, Type type)
+    {
+        return value switch
+        {
+            null => true,
+            string s => string.IsNullOrEmpty(s),
+            _ => type.IsValueType && value.Equals(Activator.CreateInstance(type))
+        };
+    }
+
+    private static string ToCamelCase(string name) =>
+        string.IsNullOrEmpty(name) ? name : char.ToLowerInvariant(name[0]) + name.Substring(1);
+}
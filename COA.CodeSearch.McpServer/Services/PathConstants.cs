@@ -11,6 +11,8 @@ public static class PathConstants
     public const string IndexDirectoryName = "indexes";
     public const string LogsDirectoryName = "logs";
     public const string BackupsDirectoryName = "backups";
+    public const string RepositoryIndexDirectoryName = ".codesearch";
+    public const string SessionsDirectoryName = "sessions";
     
     // File names
     public const string WorkspaceMetadataFileName = "workspace_metadata.json";
@@ -19,6 +21,8 @@ public static class PathConstants
     // Configuration keys
     public const string IndexBasePathConfigKey = "Lucene:IndexBasePath";
     public const string BasePathConfigKey = "CodeSearch:BasePath";
+    public const string IndexStorageLocationConfigKey = "CodeSearch:IndexStorage:Location";
+    public const string IndexStorageCustomPathConfigKey = "CodeSearch:IndexStorage:CustomPath";
     
     // Default paths
     public static readonly string DefaultBasePath = Path.Combine(Environment.GetFolderPath(Environment.SpecialFolder.UserProfile), BaseDirectoryName, CodeSearchDirectoryName);
@@ -28,7 +32,7 @@ public static class PathConstants
     {
         "bin", "obj", "node_modules", ".git", ".vs", ".vscode", "packages", "TestResults",
         "target", "dist", "build",
-        BaseDirectoryName, CodeSearchDirectoryName, ".codenav"
+        BaseDirectoryName, CodeSearchDirectoryName, RepositoryIndexDirectoryName, ".codenav"
     };
     
     // Blacklisted extensions (common across services) - files to exclude from indexing
@@ -16,6 +16,8 @@ public class PathResolutionService : IPathResolutionService
     private readonly IConfiguration _configuration;
     private readonly ILogger<PathResolutionService> _logger;
     private readonly string _primaryWorkspacePath;
+    private readonly IndexStorageLocation _storageLocation;
+    private readonly string? _customIndexRoot;
     private static readonly ConcurrentDictionary<string, SemaphoreSlim> _metadataLocks = new();
     private static readonly SemaphoreSlim _lockCreationSemaphore = new(1, 1);
     
@@ -24,8 +26,58 @@ public class PathResolutionService : IPathResolutionService
         _configuration = configuration;
         _logger = logger;
         _primaryWorkspacePath = InitializePrimaryWorkspace();
+        (_storageLocation, _customIndexRoot) = InitializeStorageLocation();
     }
     
+    private (IndexStorageLocation Location, string? CustomRoot) InitializeStorageLocation()
+    {
+        var configuredLocation = _configuration[PathConstants.IndexStorageLocationConfigKey];
+        if (string.IsNullOrWhiteSpace(configuredLocation))
+        {
+            return (IndexStorageLocation.Workspace, null);
+        }
+        
+        if (!Enum.TryParse<IndexStorageLocation>(configuredLocation, ignoreCase: true, out var location))
+        {
+            _logger.LogWarning("Unknown index storage location '{Location}', falling back to Workspace", configuredLocation);
+            return (IndexStorageLocation.Workspace, null);
+        }
+        
+        if (location == IndexStorageLocation.Custom)
+        {
+            var customPath = _configuration[PathConstants.IndexStorageCustomPathConfigKey];
+            if (string.IsNullOrWhiteSpace(customPath))
+            {
+                _logger.LogWarning("Index storage location is Custom but {Key} is not set, falling back to Workspace", 
+                    PathConstants.IndexStorageCustomPathConfigKey);
+                return (IndexStorageLocation.Workspace, null);
+            }
+            
+            var expanded = ExpandUserPath(customPath);
+            _logger.LogInformation("Using custom index storage location: {Path}", expanded);
+            return (location, expanded);
+        }
+        
+        _logger.LogInformation("Using {Location} index storage location", location);
+        return (location, null);
+    }
+    
+    /// <summary>
+    /// Expands a leading ~ and environment variables in a configured path
+    /// </summary>
+    private static string ExpandUserPath(string path)
+    {
+        var expanded = Environment.ExpandEnvironmentVariables(path.Trim());
+        if (expanded == "~" || expanded.StartsWith("~/") || expanded.StartsWith("~\\"))
+        {
+            var home = Environment.GetFolderPath(Environment.SpecialFolder.UserProfile);
+            expanded = Path.Combine(home, expanded.Length > 2 ? expanded.Substring(2) : string.Empty);
+        }
+        return Path.GetFullPath(expanded);
+    }
+    
+    public IndexStorageLocation StorageLocation => _storageLocation;
+    
     private string InitializePrimaryWorkspace()
     {
         // First check if explicitly configured
@@ -35,7 +87,7 @@ public class PathResolutionService : IPathResolutionService
         {
             try
             {
-                var fullPath = Path.GetFullPath(configuredWorkspace);
+                var fullPath = PlatformPaths.Canonicalize(configuredWorkspace);
                 _logger.LogInformation("Using configured primary workspace: {Workspace}", fullPath);
                 return fullPath;
             }
@@ -67,8 +119,8 @@ public class PathResolutionService : IPathResolutionService
         // Validate input path for security
         ValidateWorkspacePath(workspacePath);
 
-        // Get the indexes root directory in primary workspace
-        var indexRoot = GetIndexRootPath();
+        // Get the indexes root directory for the configured storage location
+        var indexRoot = GetIndexRootPath(workspacePath);
 
         // Compute workspace hash for uniqueness
         var hash = ComputeWorkspaceHash(workspacePath);
@@ -97,6 +149,12 @@ public class PathResolutionService : IPathResolutionService
         if (!Directory.Exists(lucenePath))
         {
             Directory.CreateDirectory(lucenePath);
+            
+            // In-repo indexes must never be committed alongside the source
+            if (_storageLocation == IndexStorageLocation.Repository)
+            {
+                WriteIgnoreAllGitIgnore(Path.Combine(GetFullPath(workspacePath), PathConstants.RepositoryIndexDirectoryName));
+            }
         }
 
         return lucenePath;
@@ -175,7 +233,22 @@ public class PathResolutionService : IPathResolutionService
     
     public string GetIndexRootPath()
     {
-        return Path.Combine(GetBasePath(), PathConstants.IndexDirectoryName);
+        return GetIndexRootPath(_primaryWorkspacePath);
+    }
+    
+    /// <summary>
+    /// Resolves the index root for a workspace. Only Repository storage varies per workspace;
+    /// every other location shares a single root.
+    /// </summary>
+    private string GetIndexRootPath(string workspacePath)
+    {
+        return _storageLocation switch
+        {
+            IndexStorageLocation.Repository => Path.Combine(GetFullPath(workspacePath), PathConstants.RepositoryIndexDirectoryName, PathConstants.IndexDirectoryName),
+            IndexStorageLocation.Global => Path.Combine(PathConstants.DefaultBasePath, PathConstants.IndexDirectoryName),
+            IndexStorageLocation.Custom => _customIndexRoot!,
+            _ => Path.Combine(GetBasePath(), PathConstants.IndexDirectoryName)
+        };
     }
     
     public void EnsureDirectoryExists(string path)
@@ -190,10 +263,17 @@ public class PathResolutionService : IPathResolutionService
     /// Ensures .gitignore file exists in the .coa/codesearch directory to prevent committing indexes
     /// </summary>
     public void EnsureGitIgnoreExists()
+    {
+        WriteIgnoreAllGitIgnore(GetBasePath());
+    }
+
+    /// <summary>
+    /// Writes an ignore-everything .gitignore into a CodeSearch-owned directory (never overwrites user edits)
+    /// </summary>
+    private void WriteIgnoreAllGitIgnore(string basePath)
     {
         try
         {
-            var basePath = GetBasePath();
             var gitignorePath = Path.Combine(basePath, ".gitignore");
 
             // Only create if it doesn't exist - don't overwrite user modifications
@@ -217,7 +297,7 @@ public class PathResolutionService : IPathResolutionService
         catch (Exception ex)
         {
             // Log error but don't fail startup if .gitignore creation fails
-            _logger.LogWarning(ex, "Failed to create .gitignore in .coa/codesearch directory");
+            _logger.LogWarning(ex, "Failed to create .gitignore in {Path}", basePath);
         }
     }
 
@@ -0,0 +1,332 @@
+using System.Security;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Path comparison and traversal that behave the way the file system underneath does. On Windows (NTFS) and
+/// macOS (APFS) names are case-insensitive, so C:\Repo\Foo.cs and c:\repo\foo.cs are one file; elsewhere they are
+/// two. Extended-length forms (\\?\C:\..., \\?\UNC\server\share\...) that long paths and link targets come back in
+/// are folded to their plain form, so one file never has two spellings in the index.
+/// </summary>
+public static class PlatformPaths
+{
+    /// <summary>
+    /// True where the file system matches names case-insensitively
+    /// </summary>
+    public static bool IgnoreCase { get; } = OperatingSystem.IsWindows() || OperatingSystem.IsMacOS();
+
+    public static StringComparison Comparison => IgnoreCase ? StringComparison.OrdinalIgnoreCase : StringComparison.Ordinal;
+
+    public static StringComparer Comparer => IgnoreCase ? StringComparer.OrdinalIgnoreCase : StringComparer.Ordinal;
+
+    /// <summary>
+    /// The full path without an extended-length prefix or a trailing separator. Casing is kept as given; see
+    /// <see cref="Canonicalize"/> for the casing on disk.
+    /// </summary>
+    public static string Normalize(string path)
+    {
+        if (string.IsNullOrEmpty(path))
+            return path;
+
+        if (OperatingSystem.IsWindows())
+            path = WithoutExtendedPrefix(path);
+        return Path.TrimEndingDirectorySeparator(Path.GetFullPath(path));
+    }
+
+    /// <summary>
+    /// <see cref="Normalize"/>d, and where names are case-insensitive, spelled the way the directories on disk are
+    /// (c:\repo\src → C:\Repo\src), so a workspace opened under two spellings is indexed and watched as one.
+    /// The part of the path that does not exist is kept as given.
+    /// </summary>
+    public static string Canonicalize(string path)
+    {
+        path = Normalize(path);
+        if (!IgnoreCase || string.IsNullOrEmpty(path))
+            return path;
+
+        var root = Path.GetPathRoot(path) ?? string.Empty;
+        // Drive letters are shown upper case whatever was typed
+        var actual = root.Length >= 2 && root[1] == ':' ? char.ToUpperInvariant(root[0]) + root[1..] : root;
+        var segments = path[root.Length..].Split(Path.DirectorySeparatorChar, StringSplitOptions.RemoveEmptyEntries);
+        var options = new EnumerationOptions { MatchCasing = MatchCasing.CaseInsensitive, AttributesToSkip = 0, IgnoreInaccessible = true };
+
+        for (var i = 0; i < segments.Length; i++)
+        {
+            string? name = null;
+            try
+            {
+                if (segments[i].IndexOfAny(new[] { '*', '?' }) < 0)
+                    name = new DirectoryInfo(actual).EnumerateFileSystemInfos(segments[i], options).FirstOrDefault()?.Name;
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException or SecurityException)
+            {
+                // Unreadable directory: keep the rest as given
+            }
+
+            if (name == null)
+                return Path.Combine(new[] { actual }.Concat(segments[i..]).ToArray());
+            actual = Path.Combine(actual, name);
+        }
+        return actual;
+    }
+
+    /// <summary>
+    /// The plain form of an extended-length or NT path: \\?\C:\src → C:\src, \\?\UNC\server\share → \\server\share,
+    /// \??\C:\src → C:\src. Other paths are returned unchanged.
+    /// </summary>
+    public static string WithoutExtendedPrefix(string path)
+    {
+        foreach (var prefix in new[] { @"\\?\", @"\??\", @"\\.\" })
+        {
+            if (!path.StartsWith(prefix, StringComparison.Ordinal))
+                continue;
+
+            var rest = path[prefix.Length..];
+            if (rest.StartsWith(@"UNC\", StringComparison.OrdinalIgnoreCase))
+                return @"\\" + rest[4..];
+            // \\.\pipe\name and other device paths have no plain form
+            return rest.Length >= 2 && rest[1] == ':' ? rest : path;
+        }
+        return path;
+    }
+
+    /// <summary>
+    /// True when both paths name the same file or directory, compared the way the platform compares names
+    /// </summary>
+    public static bool PathEquals(string first, string second) =>
+        string.Equals(Normalize(first), Normalize(second), Comparison);
+
+    /// <summary>
+    /// True when <paramref name="path"/> is <paramref name="root"/> or inside it. C:\repo2 is not inside C:\repo.
+    /// </summary>
+    public static bool IsUnder(string path, string root)
+    {
+        var normalizedPath = Normalize(path);
+        var normalizedRoot = Normalize(root);
+        if (string.Equals(normalizedPath, normalizedRoot, Comparison))
+            return true;
+
+        if (!Path.EndsInDirectorySeparator(normalizedRoot))
+            normalizedRoot += Path.DirectorySeparatorChar;
+        return normalizedPath.StartsWith(normalizedRoot, Comparison);
+    }
+
+    /// <summary>
+    /// The symlink policy named by a CodeSearch:Symlinks:Policy value - follow, skip or index-as-link, in any case,
+    /// with or without separators. Unset and unknown values are <see cref="SymlinkPolicy.Follow"/>.
+    /// </summary>
+    public static SymlinkPolicy ParseSymlinkPolicy(string? value) =>
+        Enum.TryParse<SymlinkPolicy>(value?.Replace("-", string.Empty).Replace("_", string.Empty), ignoreCase: true, out var policy)
+        && Enum.IsDefined(policy)
+            ? policy
+            : SymlinkPolicy.Follow;
+
+    /// <summary>
+    /// Every file under <paramref name="root"/>, with junctions and symbolic links handled by
+    /// <paramref name="policy"/> (see <see cref="SymlinkPolicy"/>). Broken links are skipped, and link cycles end
+    /// under every policy. Directories <paramref name="enterDirectory"/> rejects are not walked; unreadable ones,
+    /// broken links and cycles are reported to <paramref name="onError"/> and skipped.
+    /// </summary>
+    public static IEnumerable<string> EnumerateFiles(string root, Func<string, bool>? enterDirectory = null, Action<string, Exception>? onError = null,
+        SymlinkPolicy policy = SymlinkPolicy.Follow) =>
+        EnumerateFileInfos(root, enterDirectory, onError, policy).Select(f => f.FullName);
+
+    /// <summary>
+    /// <see cref="EnumerateFiles"/> with each file's size and times as the directory listing returned them, so
+    /// callers needing them make one call per directory instead of one per file - which counts over NFS and SMB
+    /// </summary>
+    public static IEnumerable<FileInfo> EnumerateFileInfos(string root, Func<string, bool>? enterDirectory = null, Action<string, Exception>? onError = null,
+        SymlinkPolicy policy = SymlinkPolicy.Follow)
+    {
+        var rootPath = Normalize(root);
+        var realRoot = TryResolveLink(new DirectoryInfo(rootPath), out var rootTarget) && rootTarget != null ? rootTarget : rootPath;
+        var visited = new HashSet<string>(Comparer);
+        var pending = new Stack<Walk>();
+        pending.Push(new Walk(rootPath, realRoot, null));
+
+        while (pending.Count > 0)
+        {
+            var walk = pending.Pop();
+            // Under link names a directory is walked once per link leading to it; otherwise once
+            if (policy != SymlinkPolicy.IndexAsLink && !visited.Add(walk.RealPath))
+                continue;
+
+            List<FileSystemInfo> entries;
+            try
+            {
+                // In name order, so which of two links to one folder is followed doesn't vary between runs
+                entries = new DirectoryInfo(walk.Path).EnumerateFileSystemInfos().OrderBy(e => e.Name, StringComparer.Ordinal).ToList();
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException or SecurityException)
+            {
+                onError?.Invoke(walk.Path, ex);
+                continue;
+            }
+
+            var subdirectories = new List<Walk>();
+            foreach (var entry in entries)
+            {
+                if (!TryResolveLink(entry, out var target))
+                {
+                    onError?.Invoke(entry.FullName, new FileNotFoundException($"Broken link: {entry.FullName}", entry.FullName));
+                    continue;
+                }
+
+                if (target != null && !FollowsLink(policy, target, realRoot, entry is DirectoryInfo ? walk : null))
+                {
+                    if (policy == SymlinkPolicy.IndexAsLink)
+                        onError?.Invoke(entry.FullName, new IOException($"Link cycle: {entry.FullName} leads back to {target}"));
+                    continue;
+                }
+
+                if (entry is DirectoryInfo)
+                {
+                    if (enterDirectory?.Invoke(entry.FullName) != false)
+                        subdirectories.Add(new Walk(entry.FullName, target ?? Path.Combine(walk.RealPath, entry.Name), walk));
+                }
+                else if (entry is FileInfo file)
+                {
+                    yield return file;
+                }
+            }
+
+            // Pushed in reverse so directories are walked in name order
+            for (var i = subdirectories.Count - 1; i >= 0; i--)
+                pending.Push(subdirectories[i]);
+        }
+    }
+
+    /// <summary>
+    /// A filter telling whether a file another walker listed - julie-codesearch's scan, a watcher event - is one
+    /// <see cref="EnumerateFiles"/> would return under that path with <paramref name="policy"/>. Under
+    /// <see cref="SymlinkPolicy.Follow"/> the first link seen to a folder outside the root is the one kept.
+    /// Directories are looked at once per filter, so use one filter per listing. Paths outside the root pass.
+    /// </summary>
+    public static Func<string, bool> SymlinkFilter(string root, SymlinkPolicy policy)
+    {
+        var rootPath = Normalize(root);
+        var realRoot = TryResolveLink(new DirectoryInfo(rootPath), out var rootTarget) && rootTarget != null ? rootTarget : rootPath;
+        var directories = new Dictionary<string, Walk?>(Comparer);
+        // Real folder outside the root → the link path it is kept under
+        var followed = new Dictionary<string, string>(Comparer);
+
+        return path =>
+        {
+            path = Normalize(path);
+            if (!IsUnder(path, rootPath) || PathEquals(path, rootPath))
+                return true;
+
+            var walk = new Walk(rootPath, realRoot, null);
+            var segments = path[(rootPath.Length + 1)..].Split(Path.DirectorySeparatorChar, StringSplitOptions.RemoveEmptyEntries);
+            for (var i = 0; i < segments.Length; i++)
+            {
+                var current = Path.Combine(walk.Path, segments[i]);
+                var isFile = i == segments.Length - 1;
+                if (!isFile && directories.TryGetValue(current, out var known))
+                {
+                    if (known == null)
+                        return false;
+                    walk = known;
+                    continue;
+                }
+
+                FileSystemInfo entry = isFile ? new FileInfo(current) : new DirectoryInfo(current);
+                Walk? next = null;
+                if (TryResolveLink(entry, out var target)
+                    && (target == null || (FollowsLink(policy, target, realRoot, isFile ? null : walk)
+                        && (policy != SymlinkPolicy.Follow || isFile || followed.TryAdd(target, current) || PathEquals(followed[target], current)))))
+                {
+                    next = new Walk(current, target ?? Path.Combine(walk.RealPath, segments[i]), walk);
+                }
+
+                if (isFile)
+                    return next != null;
+                directories[current] = next;
+                if (next == null)
+                    return false;
+                walk = next;
+            }
+            return true;
+        };
+    }
+
+    /// <summary>
+    /// Whether a link to <paramref name="target"/> is walked. For a link to a directory, <paramref name="parent"/>
+    /// is the walk it was found in: under <see cref="SymlinkPolicy.IndexAsLink"/> a link to a folder the walk is
+    /// already inside would never end.
+    /// </summary>
+    private static bool FollowsLink(SymlinkPolicy policy, string target, string realRoot, Walk? parent) => policy switch
+    {
+        SymlinkPolicy.Skip => false,
+        // Inside the root, the target is reached where it really is
+        SymlinkPolicy.Follow => !IsUnder(target, realRoot),
+        _ => parent == null || !parent.IsInside(target)
+    };
+
+    /// <summary>
+    /// The final target of a junction or symbolic link, normalized; null target for an entry that is not a link.
+    /// False for a broken link or a chain of links that never ends.
+    /// </summary>
+    public static bool TryResolveLink(FileSystemInfo entry, out string? target)
+    {
+        target = null;
+        try
+        {
+            if (entry.LinkTarget == null)
+                return true;
+
+            var resolved = entry.ResolveLinkTarget(returnFinalTarget: true);
+            if (resolved == null || !resolved.Exists)
+                return false;
+
+            target = Normalize(resolved.FullName);
+            return true;
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException or SecurityException)
+        {
+            return false;
+        }
+    }
+
+    /// <summary>
+    /// A directory being walked: the path it is listed under and where it really is
+    /// </summary>
+    private sealed record Walk(string Path, string RealPath, Walk? Parent)
+    {
+        /// <summary>
+        /// True when this directory or one it was reached through is <paramref name="directory"/> or inside it
+        /// </summary>
+        public bool IsInside(string directory)
+        {
+            for (var walk = this; walk != null; walk = walk.Parent)
+            {
+                if (IsUnder(walk.RealPath, directory))
+                    return true;
+            }
+            return false;
+        }
+    }
+}
+
+/// <summary>
+/// How junctions and symbolic links in a workspace are indexed (CodeSearch:Symlinks:Policy)
+/// </summary>
+public enum SymlinkPolicy
+{
+    /// <summary>
+    /// Links are followed, and every real file is indexed once: a link into the workspace is skipped because its
+    /// target is indexed where it is, and a folder outside it is indexed under the first link leading there
+    /// </summary>
+    Follow,
+
+    /// <summary>
+    /// Links are not followed; what they point to is indexed only if it is in the workspace anyway
+    /// </summary>
+    Skip,
+
+    /// <summary>
+    /// Files are indexed under every link path leading to them, so a shared package symlinked into three
+    /// node_modules folders is found in all three. A link back to a folder it is inside is not followed.
+    /// </summary>
+    IndexAsLink
+}
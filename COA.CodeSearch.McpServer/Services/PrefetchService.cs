@@ -0,0 +1,119 @@
+using System.Collections.Concurrent;
+using System.Threading.Channels;
+using COA.CodeSearch.McpServer.Services.RateLimiting;
+using COA.CodeSearch.McpServer.Tools;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Runs prefetch requests one at a time through the real tools in their own scope, so results land in the
+/// response cache under the same keys a later call computes. Prefetching never delays the tool that asked
+/// for it, and failures are only logged.
+/// </summary>
+public class PrefetchService : BackgroundService, IPrefetchService
+{
+    private readonly IServiceProvider _serviceProvider;
+    private readonly ILogger<PrefetchService> _logger;
+    private readonly bool _enabled;
+    private readonly Channel<PrefetchRequest> _queue;
+    private readonly ConcurrentDictionary<PrefetchRequest, byte> _queued = new();
+
+    public PrefetchService(IServiceProvider serviceProvider, IConfiguration configuration, ILogger<PrefetchService> logger)
+    {
+        _serviceProvider = serviceProvider ?? throw new ArgumentNullException(nameof(serviceProvider));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        _enabled = configuration.GetValue("CodeSearch:Prefetch:Enabled", true);
+
+        // Only recent requests matter: the agent has moved on from the oldest ones
+        _queue = Channel.CreateBounded<PrefetchRequest>(new BoundedChannelOptions(configuration.GetValue("CodeSearch:Prefetch:QueueSize", 32))
+        {
+            FullMode = BoundedChannelFullMode.DropOldest,
+            SingleReader = true
+        }, dropped => _queued.TryRemove(dropped, out _));
+    }
+
+    public bool IsEnabled => _enabled;
+
+    public void PrefetchSymbol(string? workspacePath, string symbolName, string? definitionFile)
+    {
+        if (!_enabled || string.IsNullOrWhiteSpace(symbolName))
+            return;
+
+        Enqueue(new PrefetchRequest(PrefetchKind.References, workspacePath, symbolName));
+        if (!string.IsNullOrEmpty(definitionFile))
+        {
+            Enqueue(new PrefetchRequest(PrefetchKind.Outline, workspacePath, definitionFile));
+        }
+    }
+
+    private void Enqueue(PrefetchRequest request)
+    {
+        if (_queued.TryAdd(request, 0) && !_queue.Writer.TryWrite(request))
+        {
+            _queued.TryRemove(request, out _);
+        }
+    }
+
+    protected override async Task ExecuteAsync(CancellationToken stoppingToken)
+    {
+        if (!_enabled)
+            return;
+
+        try
+        {
+            await foreach (var request in _queue.Reader.ReadAllAsync(stoppingToken))
+            {
+                _queued.TryRemove(request, out _);
+                await RunAsync(request, stoppingToken);
+            }
+        }
+        catch (OperationCanceledException) when (stoppingToken.IsCancellationRequested)
+        {
+            // Queued prefetches are dropped on shutdown
+        }
+    }
+
+    private async Task RunAsync(PrefetchRequest request, CancellationToken cancellationToken)
+    {
+        try
+        {
+            using var scope = _serviceProvider.CreateScope();
+            // Prefetching is the server's own work; it must not use up the agent's rate limit
+            using var unlimited = SessionLimiter.Unlimited();
+            bool success;
+            switch (request.Kind)
+            {
+                case PrefetchKind.References:
+                    var references = await scope.ServiceProvider.GetRequiredService<FindReferencesTool>().ExecuteAsync(
+                        new FindReferencesParameters { Symbol = request.Target, WorkspacePath = request.WorkspacePath }, cancellationToken);
+                    success = references.Success == true;
+                    break;
+
+                default:
+                    var outline = await scope.ServiceProvider.GetRequiredService<GetSymbolsOverviewTool>().ExecuteAsync(
+                        new GetSymbolsOverviewParameters { FilePath = request.Target, WorkspacePath = request.WorkspacePath }, cancellationToken);
+                    success = outline.Success == true;
+                    break;
+            }
+
+            _logger.LogDebug("Prefetched {Kind} for {Target}: {Outcome}", request.Kind, request.Target, success ? "cached" : "failed");
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "Prefetch of {Kind} for {Target} failed", request.Kind, request.Target);
+        }
+    }
+
+    private enum PrefetchKind
+    {
+        References,
+        Outline
+    }
+
+    private sealed record PrefetchRequest(PrefetchKind Kind, string? WorkspacePath, string Target);
+}
@@ -1,3 +1,4 @@
+using COA.CodeSearch.McpServer.Services.Logging;
 using Microsoft.Extensions.Caching.Memory;
 using Microsoft.Extensions.Configuration;
 using Microsoft.Extensions.Logging;
@@ -52,17 +53,20 @@ public class QueryCacheService : IQueryCacheService, IDisposable
         if (!_cacheEnabled)
         {
             Interlocked.Increment(ref _cacheMisses);
+            QueryUsage.RecordCacheLookup(hit: false);
             return Task.FromResult<T?>(null);
         }
 
         if (_cache.TryGetValue(key, out T? value))
         {
             Interlocked.Increment(ref _cacheHits);
+            QueryUsage.RecordCacheLookup(hit: true);
             _logger.LogDebug("Cache hit for key: {Key}", key);
             return Task.FromResult<T?>(value);
         }
 
         Interlocked.Increment(ref _cacheMisses);
+        QueryUsage.RecordCacheLookup(hit: false);
         return Task.FromResult<T?>(null);
     }
 
@@ -0,0 +1,159 @@
+using System.Text.RegularExpressions;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// What happens to a query whose estimated cost is over the limit
+/// </summary>
+public enum QueryGuardrailAction
+{
+    /// <summary>Run it anyway and say so in the insights</summary>
+    Warn,
+    /// <summary>Refuse until the caller passes confirmExpensive</summary>
+    Confirm,
+    /// <summary>Run it over a sample of the documents instead of all of them</summary>
+    Sample
+}
+
+/// <summary>
+/// Pre-execution cost estimate for a search query
+/// </summary>
+public class QueryCostEstimate
+{
+    /// <summary>
+    /// Documents the query has to be evaluated against
+    /// </summary>
+    public int CandidateDocuments { get; set; }
+
+    /// <summary>
+    /// Pattern multiplier, 1 for queries anchored on a literal term
+    /// </summary>
+    public int Complexity { get; set; } = 1;
+
+    public long Cost => (long)CandidateDocuments * Complexity;
+
+    public bool IsExpensive { get; set; }
+
+    /// <summary>
+    /// Why the pattern is costly, e.g. a leading wildcard
+    /// </summary>
+    public List<string> Reasons { get; set; } = new();
+}
+
+/// <summary>
+/// Estimates how much work a query will do before it runs so that accidental full-index scans
+/// (a bare <c>.*</c> regex over a monorepo) can be warned about, held for confirmation or sampled.
+/// Only the pattern shape and the index size are considered - nothing is executed.
+/// </summary>
+public class QueryCostEstimator
+{
+    private const int MaxAlternations = 8;
+
+    private static readonly Regex LiteralRun = new(@"[A-Za-z0-9_]{3,}", RegexOptions.Compiled);
+    private static readonly Regex NestedQuantifier = new(@"\([^)]*[*+][^)]*\)[*+{]", RegexOptions.Compiled);
+    private static readonly Regex RegexSyntax = new(@"\\.|\[[^\]]*\]|\{\d+(,\d*)?\}", RegexOptions.Compiled);
+
+    private readonly ILogger<QueryCostEstimator> _logger;
+
+    public QueryCostEstimator(IConfiguration configuration, ILogger<QueryCostEstimator> logger)
+    {
+        _logger = logger;
+        Enabled = configuration.GetValue("CodeSearch:QueryGuardrails:Enabled", true);
+        Action = Enum.TryParse<QueryGuardrailAction>(configuration.GetValue("CodeSearch:QueryGuardrails:Action", "warn"), true, out var action)
+            ? action
+            : QueryGuardrailAction.Warn;
+        MaxCost = Math.Max(1, configuration.GetValue("CodeSearch:QueryGuardrails:MaxCost", 250_000L));
+        SampleRate = Math.Clamp(configuration.GetValue("CodeSearch:QueryGuardrails:SampleRate", 0.1), 0.001, 1.0);
+    }
+
+    public bool Enabled { get; }
+
+    /// <summary>
+    /// Applied to queries over <see cref="MaxCost"/> (CodeSearch:QueryGuardrails:Action)
+    /// </summary>
+    public QueryGuardrailAction Action { get; }
+
+    /// <summary>
+    /// Candidate documents times pattern complexity above which a query counts as expensive
+    /// </summary>
+    public long MaxCost { get; }
+
+    /// <summary>
+    /// Fraction of documents searched when an expensive query is downgraded to a sample
+    /// </summary>
+    public double SampleRate { get; }
+
+    /// <summary>
+    /// Estimates the cost of <paramref name="query"/> in the given text search mode against an index of
+    /// <paramref name="documentCount"/> documents. Queries anchored on a literal term are never expensive.
+    /// </summary>
+    public QueryCostEstimate Estimate(string query, string searchMode, int documentCount)
+    {
+        var estimate = new QueryCostEstimate { CandidateDocuments = documentCount };
+        var mode = searchMode.ToLowerInvariant();
+
+        if (mode == "regex")
+        {
+            ScoreRegex(query, estimate);
+        }
+        else if (mode == "auto")
+        {
+            ScoreWildcard(query, estimate);
+        }
+
+        estimate.IsExpensive = estimate.Complexity > 1 && estimate.Cost > MaxCost;
+        if (estimate.IsExpensive)
+        {
+            _logger.LogInformation("Expensive query '{Query}': {Docs} candidate documents x complexity {Complexity} ({Reasons})",
+                query, documentCount, estimate.Complexity, string.Join("; ", estimate.Reasons));
+        }
+        return estimate;
+    }
+
+    private static void ScoreRegex(string pattern, QueryCostEstimate estimate)
+    {
+        var trimmed = pattern.TrimStart('^');
+        if (trimmed.StartsWith('.'))
+        {
+            estimate.Complexity += 4;
+            estimate.Reasons.Add("pattern starts with a wildcard, so every indexed term is scanned");
+        }
+
+        if (!LiteralRun.IsMatch(RegexSyntax.Replace(pattern, " ")))
+        {
+            estimate.Complexity += 4;
+            estimate.Reasons.Add("no literal run of 3+ characters to narrow the candidate terms");
+        }
+
+        if (NestedQuantifier.IsMatch(pattern))
+        {
+            estimate.Complexity += 8;
+            estimate.Reasons.Add("nested quantifiers can backtrack heavily");
+        }
+
+        var alternations = pattern.Count(c => c == '|');
+        if (alternations > MaxAlternations)
+        {
+            estimate.Complexity += 2;
+            estimate.Reasons.Add($"{alternations + 1} alternatives in one pattern");
+        }
+    }
+
+    private static void ScoreWildcard(string query, QueryCostEstimate estimate)
+    {
+        var terms = query.Split(' ', StringSplitOptions.RemoveEmptyEntries);
+        if (terms.Any(t => t.StartsWith('*') || t.StartsWith('?')))
+        {
+            estimate.Complexity += 4;
+            estimate.Reasons.Add("leading wildcard, so every indexed term is scanned");
+        }
+
+        if (terms.Any(t => t.IndexOfAny(new[] { '*', '?' }) >= 0) && terms.All(t => !LiteralRun.IsMatch(t)))
+        {
+            estimate.Complexity += 2;
+            estimate.Reasons.Add("wildcards with no term of 3+ literal characters");
+        }
+    }
+}
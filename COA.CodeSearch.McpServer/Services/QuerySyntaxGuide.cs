@@ -0,0 +1,192 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Tools.Models;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Catalog of text_search syntax for query_help. Examples are templated on identifiers from the
+/// workspace so they can be executed and return real hit counts.
+/// </summary>
+public static class QuerySyntaxGuide
+{
+    public const string GenericTypeName = "UserService";
+    public const string GenericMemberName = "getUserById";
+
+    public static readonly string[] TopicNames = { "auto", "exact", "fuzzy", "regex", "operators", "semantic" };
+
+    // Any script; combining marks stay with the letter they modify
+    private static readonly Regex CamelWords = new(
+        @"(?:\p{Lu}[\p{Mn}\p{Mc}]*)+(?!\p{Ll})|(?:\p{Lu}[\p{Mn}\p{Mc}]*)?(?:[\p{Ll}\p{Lo}\p{Lm}][\p{Mn}\p{Mc}]*)+|\p{Nd}+",
+        RegexOptions.Compiled);
+
+    public static readonly string[] Tips =
+    {
+        "Queries need at least 3 characters, except operators such as =>, ?. and ::",
+        "Matching is case-insensitive unless caseSensitive is true",
+        "Regex and wildcards match single indexed terms, not whole lines - use line_search for line-level patterns",
+        "Leading wildcards and bare '*' are rejected; anchor wildcards with a prefix (Query*)",
+        "Use symbol_search or goto_definition for declarations, search_files for file names, find_references for usages"
+    };
+
+    /// <summary>
+    /// Builds the topics with examples based on a type and a member name from the workspace
+    /// </summary>
+    /// <param name="typeName">A PascalCase type name, e.g. UserService</param>
+    /// <param name="memberName">A method or function name, e.g. getUserById</param>
+    /// <param name="topic">Only this topic, or all when null</param>
+    public static List<QueryHelpTopic> Build(string typeName, string memberName, string? topic = null)
+    {
+        var typeWords = SplitWords(typeName);
+        var memberWords = SplitWords(memberName);
+        var typeLower = typeName.ToLowerInvariant();
+        var firstTypeWord = typeWords[0].ToLowerInvariant();
+        var lastTypeWord = typeWords[^1].ToLowerInvariant();
+        var firstMemberWord = memberWords[0].ToLowerInvariant();
+        var prefix = typeName.Length > 4 ? typeName[..(typeName.Length * 2 / 3)] : typeName;
+        var phrase = typeWords.Length > 1 ? string.Join(' ', typeWords).ToLowerInvariant() : $"{typeLower} {firstMemberWord}";
+
+        var topics = new List<QueryHelpTopic>
+        {
+            new()
+            {
+                Name = "auto",
+                SearchMode = "auto",
+                Summary = "Default. The query shape picks the field: identifiers go to the symbol field, anything with punctuation to the pattern field, plain words to full text.",
+                Syntax = new()
+                {
+                    "Identifier → content_symbols (keywords like class/interface/function are dropped)",
+                    "Contains . : ( ) [ ] { } \" * etc. → content_patterns, punctuation kept",
+                    "Several plain words → content, all words required"
+                },
+                Examples = new()
+                {
+                    Example(typeName, "Bare identifier, searched in the symbol field"),
+                    Example($"class {typeName}", "The 'class' keyword is dropped and the name searched in the symbol field"),
+                    Example($"{typeName}.{memberName}", "Member access keeps the dot, routed to the pattern field"),
+                    Example($"{prefix}*", "Trailing wildcard, matches identifiers starting with the prefix"),
+                    Example(phrase, "Plain words, every word must appear in the file")
+                }
+            },
+            new()
+            {
+                Name = "exact",
+                SearchMode = "exact",
+                Summary = "Literal match of the whole query; special characters are escaped instead of interpreted.",
+                Syntax = new() { "Query text is matched as written - no wildcards, no fuzzy matching" },
+                Examples = new()
+                {
+                    Example(typeName, "The identifier exactly"),
+                    Example($"{memberName}(", "Call sites and declarations - '(' is escaped, not a syntax error")
+                }
+            },
+            new()
+            {
+                Name = "fuzzy",
+                SearchMode = "fuzzy",
+                Summary = "Typo-tolerant single-term search (up to 2 character edits).",
+                Syntax = new() { "One term; the closest indexed terms within 2 edits match" },
+                Examples = new()
+                {
+                    Example(Typo(typeLower), $"Misspelled '{typeName}' still finds it"),
+                    Example(Typo(firstMemberWord.Length >= 4 ? firstMemberWord : memberName.ToLowerInvariant()), "Works on partial words too")
+                }
+            },
+            new()
+            {
+                Name = "regex",
+                SearchMode = "regex",
+                Summary = "Lucene regular expression matched against individual indexed terms (lowercased), not lines.",
+                Syntax = new()
+                {
+                    "a|b alternation, [a-z] classes, + * ? repetition - no anchors (^ $) or \\d shorthands",
+                    "a.*b between two words becomes 'a near b' (within 20 words)"
+                },
+                Examples = new()
+                {
+                    Example("TODO|FIXME", "Either marker"),
+                    Example($"{firstMemberWord}[a-z0-9_]*", $"Terms starting with '{firstMemberWord}'"),
+                    Example($"{firstTypeWord}.*{lastTypeWord}", $"'{firstTypeWord}' followed by '{lastTypeWord}' within 20 words")
+                }
+            },
+            new()
+            {
+                Name = "operators",
+                SearchMode = "auto",
+                Summary = "Lucene boolean syntax, available in auto mode.",
+                Syntax = new()
+                {
+                    "a b - both required (default AND)",
+                    "a OR b - either",
+                    "+a -b - require a, exclude b",
+                    "\"a b\" - exact phrase"
+                },
+                Examples = new()
+                {
+                    Example($"{typeName} OR {memberName}", "Files with either identifier"),
+                    Example($"+{typeName} -{memberName}", $"'{typeName}' without '{memberName}'"),
+                    Example($"\"{phrase}\"", "Words adjacent and in order")
+                }
+            },
+            new()
+            {
+                Name = "semantic",
+                SearchMode = "semantic",
+                Summary = "Concept search over embeddings - describe behaviour instead of naming identifiers. Needs embeddings (see capabilities).",
+                Syntax = new() { "Natural language; results are ranked by similarity, there is no boolean syntax" },
+                Examples = new()
+                {
+                    new QueryHelpExample { Query = $"where {phrase} is created", Explanation = "Describes intent; not executed by query_help" }
+                }
+            }
+        };
+
+        return topic == null
+            ? topics
+            : topics.Where(t => t.Name.Equals(topic, StringComparison.OrdinalIgnoreCase)).ToList();
+    }
+
+    /// <summary>
+    /// Splits an identifier into its camelCase, PascalCase or snake_case words
+    /// </summary>
+    public static string[] SplitWords(string identifier)
+    {
+        var words = identifier
+            .Split('_', StringSplitOptions.RemoveEmptyEntries)
+            .SelectMany(part => CamelWords.Matches(part).Select(m => m.Value))
+            .ToArray();
+
+        return words.Length > 0 ? words : new[] { identifier };
+    }
+
+    /// <summary>
+    /// True for names worth using in examples: compound identifiers, not too short or long
+    /// </summary>
+    public static bool IsGoodSample(string name, bool requireUpperFirst) =>
+        name.Length is >= 6 and <= 30 &&
+        (!requireUpperFirst || char.IsUpper(name[0])) &&
+        name.All(c => char.IsLetterOrDigit(c) || c == '_') &&
+        SplitWords(name).Length >= 2;
+
+    /// <summary>
+    /// Swaps two characters in the middle of the term to simulate a typo
+    /// </summary>
+    private static string Typo(string term)
+    {
+        if (term.Length < 4)
+            return term + term[^1];
+
+        var i = term.Length / 2;
+        var chars = term.ToCharArray();
+        (chars[i - 1], chars[i]) = (chars[i], chars[i - 1]);
+        var swapped = new string(chars);
+
+        // Swapping equal characters changes nothing - drop one instead
+        return swapped == term ? term.Remove(i, 1) : swapped;
+    }
+
+    private static QueryHelpExample Example(string query, string explanation) => new()
+    {
+        Query = query,
+        Explanation = explanation
+    };
+}
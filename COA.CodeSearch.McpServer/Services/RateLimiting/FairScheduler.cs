@@ -0,0 +1,176 @@
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// Runs this session's tool calls within its share of the concurrent query slots the sessions on the machine
+/// divide between them. Calls beyond the share wait first-in, first-out for a bounded time; once too many are
+/// waiting, further calls are refused at once, so a runaway agent is slowed down instead of piling up work.
+/// </summary>
+public class FairScheduler
+{
+    private readonly object _lock = new();
+    private readonly LinkedList<TaskCompletionSource<bool>> _waiting = new();
+    private readonly int _maxQueued;
+    private readonly TimeSpan _queueTimeout;
+    private readonly Action? _demandChanged;
+    private int _share;
+    private int _running;
+    private double _averageMs = 500;
+
+    /// <param name="share">Calls this session may run at once</param>
+    /// <param name="maxQueued">Calls that may wait for a slot before more are refused</param>
+    /// <param name="queueTimeout">How long a call waits for a slot before it is refused</param>
+    /// <param name="demandChanged">Called when the session goes from idle to busy or back</param>
+    public FairScheduler(int share, int maxQueued, TimeSpan queueTimeout, Action? demandChanged = null)
+    {
+        _share = Math.Max(1, share);
+        _maxQueued = Math.Max(0, maxQueued);
+        _queueTimeout = queueTimeout;
+        _demandChanged = demandChanged;
+    }
+
+    /// <summary>
+    /// Calls this session may run at once. Raising it starts waiting calls; lowering it lets running calls finish.
+    /// </summary>
+    public int Share
+    {
+        get { lock (_lock) return _share; }
+        set
+        {
+            lock (_lock)
+            {
+                _share = Math.Max(1, value);
+                StartWaitingLocked();
+            }
+        }
+    }
+
+    /// <summary>
+    /// Calls running or waiting
+    /// </summary>
+    public int Demand
+    {
+        get { lock (_lock) return _running + _waiting.Count; }
+    }
+
+    /// <summary>
+    /// The divide of <paramref name="maxConcurrent"/> slots between the sessions with work, at least one each
+    /// </summary>
+    public static int FairShare(int maxConcurrent, int busySessions) =>
+        Math.Max(1, maxConcurrent / Math.Max(1, busySessions));
+
+    /// <summary>
+    /// Waits for a slot. Disposing the returned lease frees it. Throws <see cref="RateLimitExceededException"/>
+    /// when the queue is full or the wait times out.
+    /// </summary>
+    public async Task<IDisposable> EnterAsync(CancellationToken cancellationToken = default)
+    {
+        var node = StartOrEnqueue(out var wasIdle);
+        if (node == null)
+        {
+            if (wasIdle)
+                _demandChanged?.Invoke();
+            return new Lease(this);
+        }
+
+        try
+        {
+            await node.Value.Task.WaitAsync(_queueTimeout, cancellationToken);
+            return new Lease(this);
+        }
+        catch (Exception ex) when (ex is TimeoutException or OperationCanceledException)
+        {
+            lock (_lock)
+            {
+                // Still waiting: give up the place in line. Otherwise a slot was handed over just now.
+                if (node.List != null)
+                {
+                    _waiting.Remove(node);
+                    if (ex is TimeoutException)
+                    {
+                        throw new RateLimitExceededException(
+                            $"No query slot became free within {_queueTimeout.TotalSeconds:0}s ({_running} running, {_share} slot(s) for this session)",
+                            EstimateWaitLocked(_waiting.Count));
+                    }
+                    throw;
+                }
+            }
+
+            if (ex is TimeoutException)
+                return new Lease(this);
+
+            new Lease(this).Dispose();
+            throw;
+        }
+    }
+
+    /// <summary>
+    /// Takes a slot when one is free and nobody is waiting (returns null), or joins the line
+    /// </summary>
+    private LinkedListNode<TaskCompletionSource<bool>>? StartOrEnqueue(out bool wasIdle)
+    {
+        lock (_lock)
+        {
+            wasIdle = _running + _waiting.Count == 0;
+            if (_waiting.Count == 0 && _running < _share)
+            {
+                _running++;
+                return null;
+            }
+
+            if (_waiting.Count >= _maxQueued)
+            {
+                throw new RateLimitExceededException(
+                    $"Too many concurrent calls in this session ({_running} running, {_waiting.Count} waiting for {_share} slot(s))",
+                    EstimateWaitLocked(_waiting.Count));
+            }
+
+            return _waiting.AddLast(new TaskCompletionSource<bool>(TaskCreationOptions.RunContinuationsAsynchronously));
+        }
+    }
+
+    private void Exit(TimeSpan elapsed)
+    {
+        bool idle;
+        lock (_lock)
+        {
+            _running--;
+            _averageMs = _averageMs * 0.8 + elapsed.TotalMilliseconds * 0.2;
+            StartWaitingLocked();
+            idle = _running + _waiting.Count == 0;
+        }
+
+        if (idle)
+            _demandChanged?.Invoke();
+    }
+
+    private void StartWaitingLocked()
+    {
+        while (_running < _share && _waiting.First is { } first)
+        {
+            _waiting.RemoveFirst();
+            _running++;
+            first.Value.TrySetResult(true);
+        }
+    }
+
+    /// <summary>
+    /// Time until the call after <paramref name="queuedAhead"/> waiting ones would start, at the recent pace
+    /// </summary>
+    private TimeSpan EstimateWaitLocked(int queuedAhead) =>
+        TimeSpan.FromMilliseconds(Math.Max(1000, _averageMs * (queuedAhead + 1) / _share));
+
+    private sealed class Lease : IDisposable
+    {
+        private readonly FairScheduler _scheduler;
+        private readonly DateTime _started = DateTime.UtcNow;
+        private int _disposed;
+
+        public Lease(FairScheduler scheduler) => _scheduler = scheduler;
+
+        public void Dispose()
+        {
+            if (Interlocked.Exchange(ref _disposed, 1) == 0)
+                _scheduler.Exit(DateTime.UtcNow - _started);
+        }
+    }
+}
@@ -0,0 +1,25 @@
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// Per-session request rate limit and fair scheduling of concurrent queries between the CodeSearch sessions
+/// (server processes) on a machine, so one runaway agent can't starve the others. Opt-in via CodeSearch:RateLimiting.
+/// </summary>
+public interface ISessionLimiter
+{
+    /// <summary>
+    /// False when CodeSearch:RateLimiting:Enabled is off; every call is admitted then
+    /// </summary>
+    bool Enabled { get; }
+
+    /// <summary>
+    /// Admits a tool call: takes one request from the session's budget and waits for a query slot. Dispose the
+    /// returned lease when the call ends; null when the call is not limited. Throws
+    /// <see cref="RateLimitExceededException"/> with a retry-after hint when the call is refused.
+    /// </summary>
+    Task<IDisposable?> EnterAsync(string toolName, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Sessions on this machine with calls running or waiting, this one included when busy
+    /// </summary>
+    int BusySessions { get; }
+}
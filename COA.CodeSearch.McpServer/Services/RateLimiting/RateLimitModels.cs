@@ -0,0 +1,29 @@
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// A tool call refused by the session limits. The message tells the agent when to try again.
+/// </summary>
+public class RateLimitExceededException : Exception
+{
+    public RateLimitExceededException(string reason, TimeSpan retryAfter)
+        : base($"{reason}. Retry after {(int)Math.Ceiling(Math.Max(1, retryAfter.TotalSeconds))}s.")
+    {
+        Reason = reason;
+        RetryAfter = retryAfter;
+    }
+
+    /// <summary>
+    /// Why the call was refused, without the retry hint
+    /// </summary>
+    public string Reason { get; }
+
+    /// <summary>
+    /// How long to wait before calling again
+    /// </summary>
+    public TimeSpan RetryAfter { get; }
+
+    /// <summary>
+    /// <see cref="RetryAfter"/> in whole seconds, at least 1
+    /// </summary>
+    public int RetryAfterSeconds => (int)Math.Ceiling(Math.Max(1, RetryAfter.TotalSeconds));
+}
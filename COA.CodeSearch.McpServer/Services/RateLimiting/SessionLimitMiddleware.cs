@@ -0,0 +1,65 @@
+using System.Runtime.CompilerServices;
+using COA.Mcp.Framework.Pipeline;
+
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// Admits each tool call through the <see cref="ISessionLimiter"/> before it runs and frees its query slot when
+/// it ends. A refused call fails with a <see cref="RateLimitExceededException"/> whose message carries the
+/// retry-after hint. Opt-in via CodeSearch:RateLimiting:Enabled.
+/// </summary>
+public class SessionLimitMiddleware : SimpleMiddlewareBase
+{
+    private readonly ISessionLimiter _limiter;
+
+    // Leases of the calls in flight, keyed by their parameters object
+    private readonly ConditionalWeakTable<object, IDisposable> _leases = new();
+
+    public SessionLimitMiddleware(ISessionLimiter limiter)
+    {
+        _limiter = limiter;
+
+        // Run first, so a refused call does no other work
+        Order = int.MinValue;
+    }
+
+    public override async Task OnBeforeExecutionAsync(string toolName, object? parameters)
+    {
+        if (!_limiter.Enabled)
+            return;
+
+        var lease = await _limiter.EnterAsync(toolName);
+        if (lease == null)
+            return;
+
+        if (parameters == null)
+        {
+            // Nothing to find the call by afterwards - admit it without holding a slot
+            lease.Dispose();
+            return;
+        }
+
+        _leases.AddOrUpdate(parameters, lease);
+    }
+
+    public override Task OnAfterExecutionAsync(string toolName, object? parameters, object? result, long elapsedMs)
+    {
+        Release(parameters);
+        return Task.CompletedTask;
+    }
+
+    public override Task OnErrorAsync(string toolName, object? parameters, Exception exception, long elapsedMs)
+    {
+        Release(parameters);
+        return Task.CompletedTask;
+    }
+
+    private void Release(object? parameters)
+    {
+        if (parameters != null && _leases.TryGetValue(parameters, out var lease))
+        {
+            _leases.Remove(parameters);
+            lease.Dispose();
+        }
+    }
+}
@@ -0,0 +1,196 @@
+using System.Globalization;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// Each server process is one session. Sessions find each other through heartbeat files in
+/// ~/.coa/codesearch/sessions holding their current demand (calls running or waiting); every few seconds a
+/// session counts the busy ones and takes its fair share of CodeSearch:RateLimiting:MaxConcurrentQueries.
+/// Idle sessions don't count, so a session alone on the machine may use every slot.
+/// </summary>
+public class SessionLimiter : BackgroundService, ISessionLimiter
+{
+    private static readonly TimeSpan HeartbeatInterval = TimeSpan.FromSeconds(5);
+    private static readonly TimeSpan SessionTimeout = TimeSpan.FromSeconds(15);
+    private static readonly TimeSpan StaleSessionAge = TimeSpan.FromMinutes(1);
+
+    // Background work of this server (prefetching) is not the agent's and is not limited
+    private static readonly AsyncLocal<bool> _unlimited = new();
+
+    private readonly ILogger<SessionLimiter> _logger;
+    private readonly TokenBucket _requests;
+    private readonly FairScheduler _scheduler;
+    private readonly int _requestsPerMinute;
+    private readonly int _maxConcurrent;
+    private readonly string _sessionsDirectory;
+    private readonly string _sessionFile;
+    private int _busySessions = 1;
+
+    public SessionLimiter(IConfiguration configuration, ILogger<SessionLimiter> logger)
+        : this(configuration, logger, Path.Combine(PathConstants.DefaultBasePath, PathConstants.SessionsDirectoryName))
+    {
+    }
+
+    public SessionLimiter(IConfiguration configuration, ILogger<SessionLimiter> logger, string sessionsDirectory)
+    {
+        _logger = logger;
+        Enabled = configuration.GetValue("CodeSearch:RateLimiting:Enabled", false);
+        _requestsPerMinute = Math.Max(1, configuration.GetValue("CodeSearch:RateLimiting:RequestsPerMinute", 120));
+        _maxConcurrent = Math.Max(1, configuration.GetValue("CodeSearch:RateLimiting:MaxConcurrentQueries", 8));
+        _requests = new TokenBucket(_requestsPerMinute, configuration.GetValue("CodeSearch:RateLimiting:Burst", 30), DateTime.UtcNow);
+        _scheduler = new FairScheduler(
+            _maxConcurrent,
+            configuration.GetValue("CodeSearch:RateLimiting:MaxQueuedPerSession", 16),
+            TimeSpan.FromSeconds(Math.Max(1, configuration.GetValue("CodeSearch:RateLimiting:QueueTimeoutSeconds", 30))),
+            () => WriteHeartbeat());
+
+        _sessionsDirectory = sessionsDirectory;
+        _sessionFile = Path.Combine(_sessionsDirectory, $"{Environment.ProcessId}-{Guid.NewGuid():N}.session");
+    }
+
+    public bool Enabled { get; }
+
+    public int BusySessions => Volatile.Read(ref _busySessions);
+
+    /// <summary>
+    /// Exempts the calls made in the current async flow until disposed - for the server's own background work
+    /// </summary>
+    public static IDisposable Unlimited()
+    {
+        var previous = _unlimited.Value;
+        _unlimited.Value = true;
+        return new UnlimitedScope(previous);
+    }
+
+    public async Task<IDisposable?> EnterAsync(string toolName, CancellationToken cancellationToken = default)
+    {
+        if (!Enabled || _unlimited.Value)
+            return null;
+
+        if (!_requests.TryTake(DateTime.UtcNow, out var retryAfter))
+        {
+            _logger.LogWarning("Refused {ToolName}: session exceeded {Limit} requests per minute", toolName, _requestsPerMinute);
+            throw new RateLimitExceededException(
+                $"Rate limit exceeded: this session may make {_requestsPerMinute} CodeSearch calls per minute", retryAfter);
+        }
+
+        try
+        {
+            return await _scheduler.EnterAsync(cancellationToken);
+        }
+        catch (RateLimitExceededException ex)
+        {
+            _logger.LogWarning("Refused {ToolName}: {Reason} ({BusySessions} busy session(s) share {Slots} slot(s))",
+                toolName, ex.Reason, BusySessions, _maxConcurrent);
+            throw;
+        }
+    }
+
+    protected override async Task ExecuteAsync(CancellationToken stoppingToken)
+    {
+        if (!Enabled)
+            return;
+
+        try
+        {
+            while (!stoppingToken.IsCancellationRequested)
+            {
+                WriteHeartbeat();
+                Rebalance();
+                await Task.Delay(HeartbeatInterval, stoppingToken);
+            }
+        }
+        catch (OperationCanceledException) when (stoppingToken.IsCancellationRequested)
+        {
+            // Shutting down
+        }
+    }
+
+    public override async Task StopAsync(CancellationToken cancellationToken)
+    {
+        await base.StopAsync(cancellationToken);
+        try
+        {
+            File.Delete(_sessionFile);
+        }
+        catch (IOException ex)
+        {
+            _logger.LogDebug(ex, "Could not remove session file {SessionFile}", _sessionFile);
+        }
+    }
+
+    /// <summary>
+    /// Counts the busy sessions on the machine and resizes this session's share of the query slots
+    /// </summary>
+    private void Rebalance()
+    {
+        var busy = _scheduler.Demand > 0 ? 1 : 0;
+        try
+        {
+            foreach (var file in new DirectoryInfo(_sessionsDirectory).EnumerateFiles("*.session"))
+            {
+                if (string.Equals(file.FullName, Path.GetFullPath(_sessionFile), StringComparison.OrdinalIgnoreCase))
+                    continue;
+
+                var age = DateTime.UtcNow - file.LastWriteTimeUtc;
+                if (age > StaleSessionAge)
+                {
+                    // Left behind by a process that didn't shut down cleanly
+                    file.Delete();
+                    continue;
+                }
+
+                if (age <= SessionTimeout
+                    && int.TryParse(File.ReadAllText(file.FullName).Trim(), NumberStyles.None, CultureInfo.InvariantCulture, out var demand)
+                    && demand > 0)
+                {
+                    busy++;
+                }
+            }
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            _logger.LogDebug(ex, "Could not read session files in {SessionsDirectory}", _sessionsDirectory);
+        }
+
+        busy = Math.Max(1, busy);
+        var share = FairScheduler.FairShare(_maxConcurrent, busy);
+        if (Interlocked.Exchange(ref _busySessions, busy) != busy || _scheduler.Share != share)
+        {
+            _logger.LogDebug("{BusySessions} busy session(s): this session may run {Share} of {Slots} concurrent queries",
+                busy, share, _maxConcurrent);
+        }
+        _scheduler.Share = share;
+    }
+
+    /// <summary>
+    /// Publishes this session's demand, on every heartbeat and whenever the session turns busy or idle
+    /// </summary>
+    private void WriteHeartbeat()
+    {
+        if (!Enabled)
+            return;
+
+        try
+        {
+            Directory.CreateDirectory(_sessionsDirectory);
+            File.WriteAllText(_sessionFile, _scheduler.Demand.ToString(CultureInfo.InvariantCulture));
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            _logger.LogDebug(ex, "Could not write session file {SessionFile}", _sessionFile);
+        }
+    }
+
+    private sealed class UnlimitedScope : IDisposable
+    {
+        private readonly bool _previous;
+
+        public UnlimitedScope(bool previous) => _previous = previous;
+
+        public void Dispose() => _unlimited.Value = _previous;
+    }
+}
@@ -0,0 +1,47 @@
+namespace COA.CodeSearch.McpServer.Services.RateLimiting;
+
+/// <summary>
+/// Request budget refilled at a steady rate: up to <c>burst</c> calls at once, <c>requestsPerMinute</c> sustained.
+/// Times are passed in so the bucket can be driven by a test clock.
+/// </summary>
+public class TokenBucket
+{
+    private readonly object _lock = new();
+    private readonly double _capacity;
+    private readonly double _tokensPerSecond;
+    private double _tokens;
+    private DateTime _updated;
+
+    public TokenBucket(int requestsPerMinute, int burst, DateTime now)
+    {
+        _tokensPerSecond = Math.Max(1, requestsPerMinute) / 60.0;
+        _capacity = Math.Max(1, burst);
+        _tokens = _capacity;
+        _updated = now;
+    }
+
+    /// <summary>
+    /// Takes one request from the budget; when it is spent, says how long until the next request is available
+    /// </summary>
+    public bool TryTake(DateTime now, out TimeSpan retryAfter)
+    {
+        lock (_lock)
+        {
+            if (now > _updated)
+            {
+                _tokens = Math.Min(_capacity, _tokens + (now - _updated).TotalSeconds * _tokensPerSecond);
+                _updated = now;
+            }
+
+            if (_tokens >= 1)
+            {
+                _tokens -= 1;
+                retryAfter = TimeSpan.Zero;
+                return true;
+            }
+
+            retryAfter = TimeSpan.FromSeconds((1 - _tokens) / _tokensPerSecond);
+            return false;
+        }
+    }
+}
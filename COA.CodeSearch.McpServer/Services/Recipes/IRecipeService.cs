@@ -0,0 +1,37 @@
+namespace COA.CodeSearch.McpServer.Services.Recipes;
+
+/// <summary>
+/// Parses and plans refactoring recipes, runs their build check and remembers applied runs for rollback.
+/// Writing the planned files is left to <see cref="UnifiedFileEditService.ApplyFileSetAsync"/>.
+/// </summary>
+public interface IRecipeService
+{
+    /// <summary>
+    /// Parses recipe JSON and checks every step has what its op needs
+    /// </summary>
+    /// <exception cref="RecipeException">Malformed JSON, unknown op or missing step arguments</exception>
+    RecipeDefinition Parse(string json);
+
+    /// <summary>
+    /// Runs the steps against in-memory copies of the files. Stops at the first failing step.
+    /// </summary>
+    Task<RecipePlan> PlanAsync(string workspacePath, RecipeDefinition recipe, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Runs the build command of a verify_build step in the workspace
+    /// </summary>
+    /// <exception cref="RecipeException">No build command is configured or detectable</exception>
+    Task<RecipeBuildResult> VerifyBuildAsync(string workspacePath, RecipeStep step, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Remembers an applied plan, hashing the files as written so a later rollback can detect edits
+    /// </summary>
+    RecipeRun RecordRun(string workspacePath, RecipePlan plan);
+
+    RecipeRun? GetRun(string runId);
+
+    /// <summary>
+    /// Applied runs of a workspace, newest first
+    /// </summary>
+    IReadOnlyList<RecipeRun> ListRuns(string workspacePath);
+}
@@ -0,0 +1,176 @@
+namespace COA.CodeSearch.McpServer.Services.Recipes;
+
+/// <summary>
+/// A declarative refactoring script: steps run in order against an in-memory copy of the workspace,
+/// so the whole recipe can be previewed before any file is written
+/// </summary>
+public class RecipeDefinition
+{
+    public string? Name { get; set; }
+    public List<RecipeStep> Steps { get; set; } = new();
+}
+
+/// <summary>
+/// One recipe step. Which properties apply depends on <see cref="Op"/>:
+/// search (pattern, filePattern), replace (pattern, replacement), rename (oldName, newName),
+/// edit (filePath, operation, startLine, endLine, content), organize_imports (filePath) and
+/// verify_build (command).
+/// </summary>
+public class RecipeStep
+{
+    public string Op { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Text or regex for search and replace
+    /// </summary>
+    public string? Pattern { get; set; }
+
+    public bool IsRegex { get; set; }
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Glob limiting the files a step looks at, e.g. "*.cs" or "src/**/*.ts"
+    /// </summary>
+    public string? FilePattern { get; set; }
+
+    public string? Replacement { get; set; }
+    public string? OldName { get; set; }
+    public string? NewName { get; set; }
+
+    /// <summary>
+    /// Single file for edit and organize_imports; limits replace and rename to that file
+    /// </summary>
+    public string? FilePath { get; set; }
+
+    /// <summary>
+    /// Edit operation: write, insert, replace or delete
+    /// </summary>
+    public string? Operation { get; set; }
+
+    public int? StartLine { get; set; }
+    public int? EndLine { get; set; }
+    public string? Content { get; set; }
+
+    /// <summary>
+    /// Build command for verify_build (default: CodeSearch:Recipes:BuildCommand, then detected from the workspace)
+    /// </summary>
+    public string? Command { get; set; }
+}
+
+/// <summary>
+/// What one step did during planning
+/// </summary>
+public class RecipeStepReport
+{
+    public int Index { get; set; }
+    public string Op { get; set; } = string.Empty;
+
+    /// <summary>
+    /// ok, no_match, failed, skipped (a previous step failed) or pending (verify_build, runs after apply)
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public string Message { get; set; } = string.Empty;
+    public List<string> Files { get; set; } = new();
+}
+
+/// <summary>
+/// A file touched by a recipe, before and after the steps
+/// </summary>
+public class RecipeFileChange
+{
+    /// <summary>
+    /// Workspace-relative path with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Content read from disk (null if the file didn't exist)
+    /// </summary>
+    public string? Original { get; set; }
+
+    /// <summary>
+    /// SHA-256 of the bytes <see cref="Original"/> was read from, used to refuse the apply if the file changed
+    /// </summary>
+    public string? OriginalHash { get; set; }
+
+    public string? Current { get; set; }
+
+    public bool Changed => !string.Equals(Original, Current, StringComparison.Ordinal);
+}
+
+/// <summary>
+/// Result of running every step in memory
+/// </summary>
+public class RecipePlan
+{
+    public string? Name { get; set; }
+    public List<RecipeStepReport> Steps { get; set; } = new();
+    public List<RecipeFileChange> Files { get; set; } = new();
+
+    /// <summary>
+    /// Step that failed; nothing may be applied when set
+    /// </summary>
+    public RecipeStepReport? FailedStep { get; set; }
+
+    /// <summary>
+    /// The verify_build step to run after the files are written, if the recipe has one
+    /// </summary>
+    public RecipeStep? BuildStep { get; set; }
+
+    public IEnumerable<RecipeFileChange> ChangedFiles => Files.Where(f => f.Changed);
+}
+
+/// <summary>
+/// An applied recipe, kept with the content each file had before so it can be rolled back as a unit
+/// </summary>
+public class RecipeRun
+{
+    public string RunId { get; set; } = string.Empty;
+    public string WorkspacePath { get; set; } = string.Empty;
+    public string? Name { get; set; }
+    public DateTime AppliedAt { get; set; }
+    public List<RecipeRunFile> Files { get; set; } = new();
+    public bool RolledBack { get; set; }
+}
+
+public class RecipeRunFile
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Content before the recipe (null: the recipe created the file)
+    /// </summary>
+    public string? Before { get; set; }
+
+    /// <summary>
+    /// Hash of what the recipe wrote (null: the recipe deleted the file); rollback refuses files changed since
+    /// </summary>
+    public string? AfterHash { get; set; }
+}
+
+public class RecipeBuildResult
+{
+    public string Command { get; set; } = string.Empty;
+    public bool Succeeded { get; set; }
+    public int ExitCode { get; set; }
+    public TimeSpan Duration { get; set; }
+
+    /// <summary>
+    /// Last lines of the build output
+    /// </summary>
+    public string Output { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Invalid recipe or a recipe that cannot run (bad step, missing build command)
+/// </summary>
+public class RecipeException : Exception
+{
+    public RecipeException(string code, string message) : base(message)
+    {
+        Code = code;
+    }
+
+    public string Code { get; }
+}
@@ -0,0 +1,592 @@
+using System.Collections.Concurrent;
+using System.Diagnostics;
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Recipes;
+
+/// <summary>
+/// Plans recipes against in-memory copies of the workspace files and keeps the pre-images of
+/// applied runs in memory for the lifetime of the server process
+/// </summary>
+public class RecipeService : IRecipeService
+{
+    private static readonly string[] KnownOps = { "search", "replace", "rename", "edit", "organize_imports", "verify_build" };
+    private static readonly string[] EditOperations = { "write", "insert", "replace", "delete" };
+    private static readonly TimeSpan RegexTimeout = TimeSpan.FromSeconds(2);
+    private const int BuildOutputLines = 40;
+
+    private static readonly JsonSerializerOptions RecipeOptions = new()
+    {
+        PropertyNameCaseInsensitive = true,
+        ReadCommentHandling = JsonCommentHandling.Skip,
+        AllowTrailingCommas = true
+    };
+
+    private readonly ConcurrentDictionary<string, RecipeRun> _runs = new(StringComparer.Ordinal);
+    private readonly IConfiguration _configuration;
+    private readonly ILogger<RecipeService> _logger;
+    private readonly int _maxFiles;
+    private readonly int _maxRuns;
+    private readonly TimeSpan _buildTimeout;
+
+    public RecipeService(IConfiguration configuration, ILogger<RecipeService> logger)
+    {
+        _configuration = configuration;
+        _logger = logger;
+        _maxFiles = configuration.GetValue("CodeSearch:Recipes:MaxFiles", 200);
+        _maxRuns = configuration.GetValue("CodeSearch:Recipes:MaxRuns", 20);
+        _buildTimeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Recipes:BuildTimeoutSeconds", 600));
+    }
+
+    public RecipeDefinition Parse(string json)
+    {
+        RecipeDefinition? recipe;
+        try
+        {
+            recipe = JsonSerializer.Deserialize<RecipeDefinition>(json, RecipeOptions);
+        }
+        catch (JsonException ex)
+        {
+            throw new RecipeException("INVALID_RECIPE", $"Recipe is not valid JSON: {ex.Message}");
+        }
+
+        if (recipe == null || recipe.Steps.Count == 0)
+            throw new RecipeException("INVALID_RECIPE", "Recipe has no steps");
+
+        for (var i = 0; i < recipe.Steps.Count; i++)
+        {
+            var step = recipe.Steps[i];
+            step.Op = step.Op.Trim().ToLowerInvariant();
+            var error = ValidateStep(step, isLast: i == recipe.Steps.Count - 1);
+            if (error != null)
+                throw new RecipeException("INVALID_RECIPE", $"Step {i + 1} ({step.Op}): {error}");
+        }
+
+        return recipe;
+    }
+
+    private string? ValidateStep(RecipeStep step, bool isLast)
+    {
+        switch (step.Op)
+        {
+            case "search":
+            case "replace":
+                if (string.IsNullOrEmpty(step.Pattern))
+                    return "needs pattern";
+                if (step.IsRegex)
+                {
+                    try
+                    {
+                        _ = new Regex(step.Pattern);
+                    }
+                    catch (ArgumentException ex)
+                    {
+                        return $"invalid regex: {ex.Message}";
+                    }
+                }
+                return null;
+
+            case "rename":
+                if (!IsIdentifier(step.OldName) || !IsIdentifier(step.NewName))
+                    return "needs oldName and newName, both identifiers";
+                return null;
+
+            case "edit":
+                step.Operation = (step.Operation ?? "write").ToLowerInvariant();
+                if (string.IsNullOrWhiteSpace(step.FilePath))
+                    return "needs filePath";
+                if (!EditOperations.Contains(step.Operation))
+                    return $"operation must be one of {string.Join(", ", EditOperations)}";
+                if (step.Operation != "write" && step.StartLine == null)
+                    return $"{step.Operation} needs startLine";
+                if (step.Operation != "delete" && step.Content == null)
+                    return $"{step.Operation} needs content";
+                return null;
+
+            case "organize_imports":
+                return null;
+
+            case "verify_build":
+                if (!isLast)
+                    return "verify_build runs against the written files, so it must be the last step";
+                if (step.Command != null && !_configuration.GetValue("CodeSearch:Recipes:AllowStepCommands", false))
+                    return "custom commands are disabled; set CodeSearch:Recipes:BuildCommand or enable CodeSearch:Recipes:AllowStepCommands";
+                return null;
+
+            default:
+                return $"unknown op, expected one of {string.Join(", ", KnownOps)}";
+        }
+    }
+
+    public async Task<RecipePlan> PlanAsync(string workspacePath, RecipeDefinition recipe, CancellationToken cancellationToken = default)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        var plan = new RecipePlan { Name = recipe.Name };
+        var files = new Dictionary<string, RecipeFileChange>(StringComparer.Ordinal);
+        List<string>? selection = null;
+
+        for (var i = 0; i < recipe.Steps.Count; i++)
+        {
+            var step = recipe.Steps[i];
+            var report = new RecipeStepReport { Index = i + 1, Op = step.Op };
+            plan.Steps.Add(report);
+
+            if (plan.FailedStep != null)
+            {
+                report.Status = "skipped";
+                report.Message = $"Step {plan.FailedStep.Index} failed";
+                continue;
+            }
+
+            try
+            {
+                switch (step.Op)
+                {
+                    case "search":
+                        var regex = BuildRegex(step.Pattern!, step.IsRegex, step.CaseSensitive);
+                        selection = new List<string>();
+                        foreach (var path in await TargetsAsync(root, files, step, null, cancellationToken))
+                        {
+                            var current = (await LoadAsync(root, files, path, cancellationToken)).Current;
+                            if (current != null && regex.IsMatch(current))
+                                selection.Add(path);
+                        }
+                        report.Files = selection.ToList();
+                        report.Status = selection.Count > 0 ? "ok" : "no_match";
+                        report.Message = $"{selection.Count} file(s) match '{step.Pattern}'";
+                        break;
+
+                    case "replace":
+                        var pattern = BuildRegex(step.Pattern!, step.IsRegex, step.CaseSensitive);
+                        var replacement = step.IsRegex ? step.Replacement ?? string.Empty : (step.Replacement ?? string.Empty).Replace("$", "$$");
+                        var replaced = await RewriteAsync(root, files, step, selection, report,
+                            text => pattern.Replace(text, replacement), pattern, cancellationToken);
+                        report.Message = $"Replaced {replaced} occurrence(s) of '{step.Pattern}' in {report.Files.Count} file(s)";
+                        break;
+
+                    case "rename":
+                        // Files may spell the name decomposed (NFD) or precomposed (NFC); match either
+                        var spellings = new[] { NormalizationForm.FormC, NormalizationForm.FormD }
+                            .Select(form => Regex.Escape(step.OldName!.Normalize(form))).Distinct();
+                        var identifier = new Regex(
+                            $"(?<!{UnicodeIdentifiers.IdentifierCharClass})(?:{string.Join("|", spellings)})(?!{UnicodeIdentifiers.IdentifierCharClass})",
+                            RegexOptions.None, RegexTimeout);
+                        var renamed = await RewriteAsync(root, files, step, selection, report,
+                            text => identifier.Replace(text, step.NewName!.Replace("$", "$$")), identifier, cancellationToken);
+                        report.Message = $"Renamed {renamed} occurrence(s) of '{step.OldName}' to '{step.NewName}' in {report.Files.Count} file(s)";
+                        break;
+
+                    case "edit":
+                        var target = await LoadAsync(root, files, ToRelativePath(root, step.FilePath!), cancellationToken);
+                        if (target.Current == null && step.Operation != "write")
+                            throw new RecipeException("FILE_NOT_FOUND", $"{target.FilePath} does not exist");
+                        target.Current = step.Operation == "write"
+                            ? step.Content
+                            : ScratchWorkspaceService.ApplyLineEdit(target.Current!, step.Operation!, step.StartLine!.Value, step.EndLine, step.Content);
+                        report.Files.Add(target.FilePath);
+                        report.Status = "ok";
+                        report.Message = $"{step.Operation} {target.FilePath}";
+                        break;
+
+                    case "organize_imports":
+                        var candidates = step.FilePath != null
+                            ? new List<string> { ToRelativePath(root, step.FilePath) }
+                            : files.Values.Where(f => f.Changed && f.Current != null).Select(f => f.FilePath).ToList();
+                        var skipped = 0;
+                        foreach (var path in candidates)
+                        {
+                            var file = await LoadAsync(root, files, path, cancellationToken);
+                            if (file.Current == null || !path.EndsWith(".cs", StringComparison.OrdinalIgnoreCase))
+                            {
+                                skipped++;
+                                continue;
+                            }
+
+                            var organized = OrganizeCSharpUsings(file.Current);
+                            if (organized != file.Current)
+                            {
+                                file.Current = organized;
+                                report.Files.Add(path);
+                            }
+                        }
+                        report.Status = "ok";
+                        report.Message = $"Organized usings in {report.Files.Count} file(s)" +
+                                         (skipped > 0 ? $"; {skipped} non-C# file(s) left as is" : "");
+                        break;
+
+                    case "verify_build":
+                        plan.BuildStep = step;
+                        report.Status = "pending";
+                        report.Message = "Runs after the changes are written";
+                        break;
+                }
+
+                var changed = files.Values.Count(f => f.Changed);
+                if (changed > _maxFiles)
+                    throw new RecipeException("TOO_MANY_FILES", $"The recipe changes {changed} files; the limit is {_maxFiles}");
+            }
+            catch (Exception ex) when (ex is RecipeException or ScratchException or RegexMatchTimeoutException or IOException or UnauthorizedAccessException)
+            {
+                report.Status = "failed";
+                report.Message = ex.Message;
+                plan.FailedStep = report;
+            }
+        }
+
+        plan.Files = files.Values.Where(f => f.Changed).OrderBy(f => f.FilePath, StringComparer.Ordinal).ToList();
+        _logger.LogDebug("Planned recipe {Name} for {Workspace}: {Files} file(s) changed{Failure}",
+            recipe.Name, root, plan.Files.Count, plan.FailedStep != null ? $", step {plan.FailedStep.Index} failed" : "");
+        return plan;
+    }
+
+    private async Task<int> RewriteAsync(
+        string root,
+        Dictionary<string, RecipeFileChange> files,
+        RecipeStep step,
+        List<string>? selection,
+        RecipeStepReport report,
+        Func<string, string> rewrite,
+        Regex pattern,
+        CancellationToken cancellationToken)
+    {
+        var count = 0;
+        foreach (var path in await TargetsAsync(root, files, step, selection, cancellationToken))
+        {
+            var file = await LoadAsync(root, files, path, cancellationToken);
+            if (file.Current == null)
+                continue;
+
+            var matches = pattern.Matches(file.Current).Count;
+            if (matches == 0)
+                continue;
+
+            file.Current = rewrite(file.Current);
+            count += matches;
+            report.Files.Add(path);
+        }
+
+        report.Status = count > 0 ? "ok" : "no_match";
+        return count;
+    }
+
+    /// <summary>
+    /// Files a step applies to: its filePath, else the last search's matches, else every workspace file,
+    /// narrowed by filePattern
+    /// </summary>
+    private async Task<IReadOnlyList<string>> TargetsAsync(
+        string root,
+        Dictionary<string, RecipeFileChange> files,
+        RecipeStep step,
+        List<string>? selection,
+        CancellationToken cancellationToken)
+    {
+        if (!string.IsNullOrWhiteSpace(step.FilePath))
+            return new[] { ToRelativePath(root, step.FilePath) };
+
+        var glob = string.IsNullOrWhiteSpace(step.FilePattern) ? null : GlobToRegex(step.FilePattern);
+        IEnumerable<string> paths = selection
+            ?? await Task.Run(() => EnumerateFiles(root)
+                .Select(f => Path.GetRelativePath(root, f).Replace('\\', '/'))
+                .Union(files.Values.Where(f => f.Current != null).Select(f => f.FilePath))
+                .ToList(), cancellationToken);
+
+        return paths.Where(p => glob == null || glob.IsMatch(p)).OrderBy(p => p, StringComparer.Ordinal).ToList();
+    }
+
+    private static async Task<RecipeFileChange> LoadAsync(
+        string root,
+        Dictionary<string, RecipeFileChange> files,
+        string relativePath,
+        CancellationToken cancellationToken)
+    {
+        if (files.TryGetValue(relativePath, out var file))
+            return file;
+
+        var fullPath = Path.Combine(root, relativePath);
+        string? content = null;
+        string? hash = null;
+        if (File.Exists(fullPath))
+        {
+            var bytes = await File.ReadAllBytesAsync(fullPath, cancellationToken);
+            hash = UnifiedFileEditService.ComputeHash(bytes);
+            content = FileLineUtilities.DetectEncoding(bytes).GetString(bytes).TrimStart('\uFEFF');
+        }
+
+        file = new RecipeFileChange { FilePath = relativePath, Original = content, OriginalHash = hash, Current = content };
+        files[relativePath] = file;
+        return file;
+    }
+
+    /// <summary>
+    /// Sorts and de-duplicates the leading block of C# using directives: plain usings (System first),
+    /// then using static, then aliases. Global usings keep their own block ahead of the rest.
+    /// The block ends at the first line that is neither a using directive nor blank.
+    /// </summary>
+    public static string OrganizeCSharpUsings(string content)
+    {
+        var newline = content.Contains("\r\n") ? "\r\n" : "\n";
+        var lines = content.Replace("\r\n", "\n").Split('\n');
+
+        var start = 0;
+        while (start < lines.Length && (lines[start].Trim().Length == 0 || lines[start].TrimStart().StartsWith("//")))
+            start++;
+
+        var end = start;
+        var usings = new List<string>();
+        while (end < lines.Length)
+        {
+            var line = lines[end].Trim();
+            if (IsUsingDirective(line))
+                usings.Add(line);
+            else if (line.Length != 0)
+                break;
+            end++;
+        }
+
+        // Keep the blank lines that separated the block from the code below
+        while (end > start && lines[end - 1].Trim().Length == 0)
+            end--;
+
+        if (usings.Count < 2)
+            return content;
+
+        var organized = usings
+            .Distinct(StringComparer.Ordinal)
+            .OrderBy(u => u.StartsWith("global ") ? 0 : 1)
+            .ThenBy(UsingGroup)
+            .ThenBy(u => UsingName(u).StartsWith("System") ? 0 : 1)
+            .ThenBy(UsingName, StringComparer.Ordinal)
+            .ToList();
+
+        var rebuilt = lines.Take(start).Concat(organized).Concat(lines.Skip(end));
+        return string.Join(newline, rebuilt);
+    }
+
+    private static bool IsUsingDirective(string line) =>
+        Regex.IsMatch(line, @"^(global\s+)?using\s+(static\s+)?[\w.]+(\s*=\s*[\w.<>, ]+)?\s*;$");
+
+    private static int UsingGroup(string line)
+    {
+        var body = line.StartsWith("global ") ? line[7..].TrimStart() : line;
+        return body.StartsWith("using static ") ? 1 : body.Contains('=') ? 2 : 0;
+    }
+
+    private static string UsingName(string line) =>
+        Regex.Replace(line, @"^(global\s+)?using\s+(static\s+)?", "").TrimEnd(';').Trim();
+
+    public async Task<RecipeBuildResult> VerifyBuildAsync(string workspacePath, RecipeStep step, CancellationToken cancellationToken = default)
+    {
+        var command = step.Command
+            ?? _configuration.GetValue<string?>("CodeSearch:Recipes:BuildCommand")
+            ?? DetectBuildCommand(workspacePath)
+            ?? throw new RecipeException("NO_BUILD_COMMAND",
+                "No build command configured and none could be detected (no .sln, .csproj, package.json, go.mod or Cargo.toml at the workspace root)");
+
+        var arguments = SplitCommand(command);
+        var startInfo = new ProcessStartInfo
+        {
+            FileName = arguments[0],
+            WorkingDirectory = workspacePath,
+            RedirectStandardOutput = true,
+            RedirectStandardError = true,
+            UseShellExecute = false,
+            CreateNoWindow = true
+        };
+        foreach (var argument in arguments.Skip(1))
+        {
+            startInfo.ArgumentList.Add(argument);
+        }
+
+        var stopwatch = Stopwatch.StartNew();
+        using var process = StartProcess(startInfo, command);
+
+        using var timeout = CancellationTokenSource.CreateLinkedTokenSource(cancellationToken);
+        timeout.CancelAfter(_buildTimeout);
+
+        var outputTask = process.StandardOutput.ReadToEndAsync(timeout.Token);
+        var errorTask = process.StandardError.ReadToEndAsync(timeout.Token);
+
+        try
+        {
+            await process.WaitForExitAsync(timeout.Token);
+        }
+        catch (OperationCanceledException) when (!cancellationToken.IsCancellationRequested)
+        {
+            process.Kill(entireProcessTree: true);
+            return new RecipeBuildResult
+            {
+                Command = command,
+                ExitCode = -1,
+                Duration = stopwatch.Elapsed,
+                Output = $"Build timed out after {_buildTimeout.TotalSeconds:F0}s"
+            };
+        }
+
+        var output = (await outputTask + await errorTask).Replace("\r\n", "\n").TrimEnd().Split('\n');
+        var result = new RecipeBuildResult
+        {
+            Command = command,
+            ExitCode = process.ExitCode,
+            Succeeded = process.ExitCode == 0,
+            Duration = stopwatch.Elapsed,
+            Output = string.Join("\n", output.TakeLast(BuildOutputLines))
+        };
+
+        _logger.LogInformation("Recipe build '{Command}' in {Workspace} exited with {ExitCode} after {Seconds:F1}s",
+            command, workspacePath, result.ExitCode, result.Duration.TotalSeconds);
+        return result;
+    }
+
+    private static Process StartProcess(ProcessStartInfo startInfo, string command)
+    {
+        try
+        {
+            return Process.Start(startInfo)
+                ?? throw new RecipeException("BUILD_COMMAND_FAILED", $"Could not start '{command}'");
+        }
+        catch (System.ComponentModel.Win32Exception ex)
+        {
+            throw new RecipeException("BUILD_COMMAND_FAILED", $"Could not start '{command}': {ex.Message}");
+        }
+    }
+
+    private static string? DetectBuildCommand(string workspacePath)
+    {
+        if (Directory.EnumerateFiles(workspacePath, "*.sln").Any() || Directory.EnumerateFiles(workspacePath, "*.csproj").Any())
+            return "dotnet build";
+        if (File.Exists(Path.Combine(workspacePath, "package.json")))
+            return "npm run build";
+        if (File.Exists(Path.Combine(workspacePath, "go.mod")))
+            return "go build ./...";
+        if (File.Exists(Path.Combine(workspacePath, "Cargo.toml")))
+            return "cargo check";
+        return null;
+    }
+
+    // Splits on whitespace, keeping double-quoted arguments together
+    private static List<string> SplitCommand(string command)
+    {
+        var arguments = Regex.Matches(command, "\"([^\"]*)\"|(\\S+)")
+            .Select(m => m.Groups[1].Success ? m.Groups[1].Value : m.Groups[2].Value)
+            .ToList();
+        return arguments.Count > 0
+            ? arguments
+            : throw new RecipeException("NO_BUILD_COMMAND", "Build command is empty");
+    }
+
+    public RecipeRun RecordRun(string workspacePath, RecipePlan plan)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        var run = new RecipeRun
+        {
+            RunId = Guid.NewGuid().ToString("N")[..12],
+            WorkspacePath = root,
+            Name = plan.Name,
+            AppliedAt = DateTime.UtcNow,
+            Files = plan.ChangedFiles
+                .Select(f =>
+                {
+                    var fullPath = Path.Combine(root, f.FilePath);
+                    return new RecipeRunFile
+                    {
+                        FilePath = f.FilePath,
+                        Before = f.Original,
+                        AfterHash = File.Exists(fullPath) ? UnifiedFileEditService.ComputeHash(File.ReadAllBytes(fullPath)) : null
+                    };
+                })
+                .ToList()
+        };
+
+        _runs[run.RunId] = run;
+        foreach (var stale in _runs.Values.OrderByDescending(r => r.AppliedAt).Skip(_maxRuns).ToList())
+        {
+            _runs.TryRemove(stale.RunId, out _);
+        }
+
+        return run;
+    }
+
+    public RecipeRun? GetRun(string runId) => _runs.TryGetValue(runId, out var run) ? run : null;
+
+    public IReadOnlyList<RecipeRun> ListRuns(string workspacePath)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        return _runs.Values
+            .Where(r => string.Equals(r.WorkspacePath, root, StringComparison.OrdinalIgnoreCase))
+            .OrderByDescending(r => r.AppliedAt)
+            .ToList();
+    }
+
+    /// <summary>
+    /// Files under the workspace, skipping the same directories and extensions as indexing
+    /// </summary>
+    private IEnumerable<string> EnumerateFiles(string workspacePath)
+    {
+        var excluded = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>() ?? PathConstants.DefaultExcludedDirectories,
+            StringComparer.OrdinalIgnoreCase);
+        var blacklisted = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Indexing:BlacklistedExtensions").Get<string[]>() ?? PathConstants.DefaultBlacklistedExtensions,
+            StringComparer.OrdinalIgnoreCase);
+
+        var pending = new Stack<string>();
+        pending.Push(workspacePath);
+        while (pending.Count > 0)
+        {
+            var directory = pending.Pop();
+            string[] entries;
+            string[] subdirectories;
+            try
+            {
+                entries = Directory.GetFiles(directory);
+                subdirectories = Directory.GetDirectories(directory);
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                continue;
+            }
+
+            foreach (var file in entries.Where(f => !blacklisted.Contains(Path.GetExtension(f))))
+                yield return file;
+
+            foreach (var subdirectory in subdirectories.Where(d => !excluded.Contains(Path.GetFileName(d))))
+                pending.Push(subdirectory);
+        }
+    }
+
+    private static Regex BuildRegex(string pattern, bool isRegex, bool caseSensitive) =>
+        new(isRegex ? pattern : Regex.Escape(pattern),
+            caseSensitive ? RegexOptions.Multiline : RegexOptions.Multiline | RegexOptions.IgnoreCase, RegexTimeout);
+
+    // "*.cs" matches file names anywhere; patterns with a slash match the relative path
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    private static bool IsIdentifier(string? name) =>
+        !string.IsNullOrEmpty(name) && Regex.IsMatch(name, $"^{UnicodeIdentifiers.IdentifierPattern}$");
+
+    private static string ToRelativePath(string root, string filePath)
+    {
+        var relativePath = Path.GetRelativePath(root, Path.GetFullPath(filePath, root));
+        if (relativePath == "." || relativePath == ".." || relativePath.StartsWith(".." + Path.DirectorySeparatorChar) || Path.IsPathRooted(relativePath))
+        {
+            throw new RecipeException("PATH_OUTSIDE_WORKSPACE", $"{filePath} is not inside the workspace {root}");
+        }
+
+        return relativePath.Replace('\\', '/');
+    }
+}
@@ -0,0 +1,50 @@
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+
+namespace COA.CodeSearch.McpServer.Services.Roslyn;
+
+/// <summary>
+/// Optional C# analysis tier backed by a loaded Roslyn solution. Text and symbol search keep using the index;
+/// this answers the questions a name match cannot: which references bind to this symbol, what a rename must
+/// touch, and whether an expression can be null at a given point. Every method returns null when the tier is
+/// disabled, the file is not C#, or no solution or project loads, so callers fall back to the index.
+/// </summary>
+public interface IRoslynAnalysisService
+{
+    bool IsEnabled { get; }
+
+    /// <summary>
+    /// True for files the tier answers for (C# sources) when enabled
+    /// </summary>
+    bool Handles(string filePath);
+
+    /// <summary>
+    /// References to the symbol at a position (0-based line, UTF-16 character)
+    /// </summary>
+    Task<RoslynReferenceResult?> FindReferencesAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// References to every source symbol declared with <paramref name="symbolName"/>
+    /// </summary>
+    Task<RoslynReferenceResult?> FindReferencesByNameAsync(string workspacePath, string symbolName, bool caseSensitive,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Edits per file that rename the symbol at a position, including overrides, interface implementations
+    /// and references in other projects of the solution
+    /// </summary>
+    Task<Dictionary<string, List<LspTextEdit>>?> GetRenameEditsAsync(string workspacePath, string filePath, LspPosition position,
+        string newName, CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Type, symbol signature and documentation summary of the expression or declaration at a position
+    /// </summary>
+    Task<RoslynHoverResult?> GetHoverAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default);
+
+    /// <summary>
+    /// Nullable annotation and flow state of the expression or declaration at a position
+    /// </summary>
+    Task<NullableFlowResult?> GetNullableFlowAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,743 @@
+using System.Collections.Concurrent;
+using System.Diagnostics;
+using System.Text.RegularExpressions;
+using System.Xml;
+using System.Xml.Linq;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using Microsoft.Build.Locator;
+using Microsoft.CodeAnalysis;
+using Microsoft.CodeAnalysis.CSharp.Syntax;
+using Microsoft.CodeAnalysis.FindSymbols;
+using Microsoft.CodeAnalysis.MSBuild;
+using Microsoft.CodeAnalysis.Rename;
+using Microsoft.CodeAnalysis.Text;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Roslyn;
+
+/// <summary>
+/// Loads the workspace's solution (or its projects) into an MSBuildWorkspace on first use and keeps it.
+/// Before each query, documents changed on disk are swapped into the cached solution; a changed solution or
+/// project file, a deleted document or a new file the query needs reloads it. Loading needs a .NET SDK;
+/// without one, or without any solution or project, the workspace is not retried until
+/// <c>RetryAfterSeconds</c> has passed.
+/// </summary>
+public class RoslynAnalysisService : IRoslynAnalysisService, IDisposable
+{
+    private static readonly object RegistrationLock = new();
+    private static bool? _msbuildRegistered;
+
+    private static readonly HashSet<string> SkippedDirectories = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "bin", "obj", "node_modules", ".git", ".vs", ".coa"
+    };
+
+    private readonly string? _solutionPath;
+    private readonly int _maxProjects;
+    private readonly TimeSpan _retryAfter;
+    private readonly ILogger<RoslynAnalysisService> _logger;
+    private readonly ConcurrentDictionary<string, LoadedSolution> _solutions = new(StringComparer.OrdinalIgnoreCase);
+    private readonly ConcurrentDictionary<string, DateTime> _failedLoads = new(StringComparer.OrdinalIgnoreCase);
+    private readonly SemaphoreSlim _loadLock = new(1, 1);
+
+    public RoslynAnalysisService(IConfiguration configuration, ILogger<RoslynAnalysisService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        IsEnabled = configuration.GetValue("CodeSearch:Roslyn:Enabled", false);
+        _solutionPath = configuration.GetValue<string?>("CodeSearch:Roslyn:SolutionPath", null);
+        _maxProjects = configuration.GetValue("CodeSearch:Roslyn:MaxProjects", 50);
+        _retryAfter = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Roslyn:RetryAfterSeconds", 300));
+    }
+
+    public bool IsEnabled { get; }
+
+    public bool Handles(string filePath) =>
+        IsEnabled && Path.GetExtension(filePath).Equals(".cs", StringComparison.OrdinalIgnoreCase);
+
+    public async Task<RoslynReferenceResult?> FindReferencesAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var solution = await GetSolutionAsync(workspacePath, filePath, cancellationToken);
+        if (solution == null)
+            return null;
+
+        var symbol = await FindSymbolAtAsync(solution, filePath, position, cancellationToken);
+        return symbol == null ? null : await CollectReferencesAsync(solution, new[] { symbol }, cancellationToken);
+    }
+
+    public async Task<RoslynReferenceResult?> FindReferencesByNameAsync(string workspacePath, string symbolName, bool caseSensitive,
+        CancellationToken cancellationToken = default)
+    {
+        if (!IsEnabled)
+            return null;
+
+        var solution = await GetSolutionAsync(workspacePath, null, cancellationToken);
+        if (solution == null)
+            return null;
+
+        // Multi-targeted projects declare each symbol once per target framework; keep one per source location
+        var symbols = new Dictionary<string, ISymbol>();
+        foreach (var project in solution.Projects)
+        {
+            var declarations = await SymbolFinder.FindSourceDeclarationsAsync(project, symbolName, ignoreCase: !caseSensitive, cancellationToken);
+            foreach (var symbol in declarations)
+            {
+                var location = symbol.Locations.FirstOrDefault(l => l.IsInSource);
+                if (location != null)
+                    symbols.TryAdd($"{location.SourceTree!.FilePath}|{location.SourceSpan.Start}", symbol);
+            }
+        }
+
+        return symbols.Count == 0 ? null : await CollectReferencesAsync(solution, symbols.Values.ToList(), cancellationToken);
+    }
+
+    public async Task<Dictionary<string, List<LspTextEdit>>?> GetRenameEditsAsync(string workspacePath, string filePath, LspPosition position,
+        string newName, CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var solution = await GetSolutionAsync(workspacePath, filePath, cancellationToken);
+        if (solution == null)
+            return null;
+
+        var symbol = await FindSymbolAtAsync(solution, filePath, position, cancellationToken);
+        if (symbol == null || !symbol.Locations.Any(l => l.IsInSource))
+            return null;
+
+        Solution renamed;
+        try
+        {
+            renamed = await Renamer.RenameSymbolAsync(solution, symbol, new SymbolRenameOptions(), newName, cancellationToken);
+        }
+        catch (ArgumentException ex)
+        {
+            _logger.LogWarning("Roslyn could not rename {Symbol} to {NewName}: {Message}", symbol.Name, newName, ex.Message);
+            return null;
+        }
+
+        var edits = new Dictionary<string, List<LspTextEdit>>(StringComparer.OrdinalIgnoreCase);
+        foreach (var projectChanges in renamed.GetChanges(solution).GetProjectChanges())
+        {
+            foreach (var documentId in projectChanges.GetChangedDocuments())
+            {
+                var before = solution.GetDocument(documentId);
+                var after = renamed.GetDocument(documentId);
+                // Linked documents (one file in several target frameworks) carry the same edits
+                if (before?.FilePath == null || after == null || edits.ContainsKey(before.FilePath))
+                    continue;
+
+                var text = await before.GetTextAsync(cancellationToken);
+                var changes = await after.GetTextChangesAsync(before, cancellationToken);
+                edits[before.FilePath] = changes.Select(c => new LspTextEdit(ToLspRange(text, c.Span), c.NewText ?? string.Empty)).ToList();
+            }
+        }
+        return edits;
+    }
+
+    public async Task<RoslynHoverResult?> GetHoverAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var solution = await GetSolutionAsync(workspacePath, filePath, cancellationToken);
+        if (solution == null)
+            return null;
+
+        var located = await LocateAsync(solution, filePath, position, cancellationToken);
+        if (located == null)
+            return null;
+
+        var (document, offset) = located.Value;
+        var root = await document.GetSyntaxRootAsync(cancellationToken);
+        var model = await document.GetSemanticModelAsync(cancellationToken);
+        if (root == null || model == null)
+            return null;
+
+        var token = root.FindToken(offset);
+        if (!token.Span.Contains(offset) && offset > 0)
+            token = root.FindToken(offset - 1);
+        if (token.Parent == null)
+            return null;
+
+        var symbol = await FindSymbolAtAsync(solution, filePath, position, cancellationToken);
+        var result = new RoslynHoverResult { Expression = token.Text };
+
+        // Expressions have a type of their own (var locals, generic calls); declarations take their symbol's
+        var declared = model.GetDeclaredSymbol(token.Parent, cancellationToken);
+        if (declared == null && OutermostNameExpression(token.Parent) is { } expression)
+        {
+            result.Expression = expression.ToString();
+            result.Type = model.GetTypeInfo(expression, cancellationToken).Type?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            var constant = model.GetConstantValue(expression, cancellationToken);
+            if (constant.HasValue)
+                result.ConstantValue = constant.Value?.ToString() ?? "null";
+        }
+
+        symbol ??= declared;
+        if (symbol == null && result.Type == null)
+            return null;
+
+        if (symbol != null)
+        {
+            result.Signature = symbol.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.SymbolKind = symbol.Kind.ToString();
+            result.Type ??= TypeOf(symbol)?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.Documentation = DocumentationText(symbol.GetDocumentationCommentXml(expandIncludes: true, cancellationToken: cancellationToken));
+            if (symbol is IFieldSymbol { HasConstantValue: true } field)
+                result.ConstantValue ??= field.ConstantValue?.ToString() ?? "null";
+
+            var source = symbol.Locations.FirstOrDefault(l => l.IsInSource);
+            if (source != null)
+            {
+                var span = source.GetLineSpan();
+                result.DeclaredInFile = span.Path;
+                result.DeclaredAtLine = span.StartLinePosition.Line + 1;
+            }
+        }
+        return result;
+    }
+
+    public async Task<NullableFlowResult?> GetNullableFlowAsync(string workspacePath, string filePath, LspPosition position,
+        CancellationToken cancellationToken = default)
+    {
+        if (!Handles(filePath))
+            return null;
+
+        var solution = await GetSolutionAsync(workspacePath, filePath, cancellationToken);
+        if (solution == null)
+            return null;
+
+        var located = await LocateAsync(solution, filePath, position, cancellationToken);
+        if (located == null)
+            return null;
+
+        var (document, offset) = located.Value;
+        var root = await document.GetSyntaxRootAsync(cancellationToken);
+        var model = await document.GetSemanticModelAsync(cancellationToken);
+        if (root == null || model == null)
+            return null;
+
+        var token = root.FindToken(offset);
+        if (!token.Span.Contains(offset) && offset > 0)
+            token = root.FindToken(offset - 1);
+        if (token.Parent == null)
+            return null;
+
+        var context = model.GetNullableContext(token.SpanStart);
+        var result = new NullableFlowResult
+        {
+            FilePath = document.FilePath ?? filePath,
+            Line = position.Line + 1,
+            Column = position.Character,
+            AnnotationsEnabled = context.AnnotationsEnabled(),
+            WarningsEnabled = context.WarningsEnabled()
+        };
+
+        var declared = model.GetDeclaredSymbol(token.Parent, cancellationToken);
+        if (declared != null && token.Parent is VariableDeclaratorSyntax or ParameterSyntax or PropertyDeclarationSyntax
+                or FieldDeclarationSyntax or MethodDeclarationSyntax or SingleVariableDesignationSyntax)
+        {
+            result.Expression = token.Text;
+            result.Symbol = declared.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.SymbolKind = declared.Kind.ToString();
+            result.Type = TypeOf(declared)?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.DeclaredAnnotation = Describe(AnnotationOf(declared));
+
+            // A declaration's state is its initializer's; without one, it is what the annotation promises
+            result.FlowState = token.Parent is VariableDeclaratorSyntax { Initializer: { } initializer }
+                ? Describe(model.GetTypeInfo(initializer.Value, cancellationToken).Nullability.FlowState)
+                : result.DeclaredAnnotation switch
+                {
+                    "annotated" => "maybe-null",
+                    "not-annotated" => "not-null",
+                    _ => "none"
+                };
+        }
+        else
+        {
+            var expression = OutermostNameExpression(token.Parent);
+            if (expression == null)
+                return null;
+
+            var typeInfo = model.GetTypeInfo(expression, cancellationToken);
+            var symbol = model.GetSymbolInfo(expression, cancellationToken).Symbol;
+            result.Expression = expression.ToString();
+            result.Type = typeInfo.Type?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.Symbol = symbol?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat);
+            result.SymbolKind = symbol?.Kind.ToString();
+            result.DeclaredAnnotation = Describe(symbol != null ? AnnotationOf(symbol) : typeInfo.Nullability.Annotation);
+            result.FlowState = Describe(typeInfo.Nullability.FlowState);
+        }
+
+        var text = await document.GetTextAsync(cancellationToken);
+        result.Diagnostics = model.GetDiagnostics(text.Lines[position.Line].Span, cancellationToken)
+            .Where(d => IsNullableWarning(d.Id))
+            .Select(d => $"{d.Id}: {d.GetMessage()}")
+            .ToList();
+        return result;
+    }
+
+    private async Task<RoslynReferenceResult> CollectReferencesAsync(Solution solution, IReadOnlyList<ISymbol> symbols,
+        CancellationToken cancellationToken)
+    {
+        var result = new RoslynReferenceResult
+        {
+            SymbolDisplay = symbols[0].ToDisplayString(),
+            SymbolKind = symbols[0].Kind.ToString(),
+            SymbolCount = symbols.Count
+        };
+
+        var models = new Dictionary<DocumentId, SemanticModel?>();
+        var seen = new HashSet<(string, int, int)>();
+
+        async Task AddAsync(Location location, string referenceType)
+        {
+            if (!location.IsInSource)
+                return;
+
+            var document = solution.GetDocument(location.SourceTree);
+            var span = location.GetLineSpan();
+            if (document == null || !seen.Add((span.Path, span.StartLinePosition.Line, span.StartLinePosition.Character)))
+                return;
+
+            if (!models.TryGetValue(document.Id, out var model))
+            {
+                model = await document.GetSemanticModelAsync(cancellationToken);
+                models[document.Id] = model;
+            }
+
+            var text = await location.SourceTree!.GetTextAsync(cancellationToken);
+            result.Locations.Add(new RoslynLocation
+            {
+                FilePath = span.Path,
+                Line = span.StartLinePosition.Line + 1,
+                Column = span.StartLinePosition.Character,
+                IsDefinition = referenceType == "definition",
+                ReferenceType = referenceType,
+                ContainedIn = model?.GetEnclosingSymbol(location.SourceSpan.Start, cancellationToken)
+                    ?.ToDisplayString(SymbolDisplayFormat.MinimallyQualifiedFormat),
+                LineText = text.Lines[span.StartLinePosition.Line].ToString()
+            });
+        }
+
+        foreach (var symbol in symbols)
+        {
+            var referenced = await SymbolFinder.FindReferencesAsync(symbol, solution, cancellationToken);
+            foreach (var entry in referenced)
+            {
+                foreach (var location in entry.Definition.Locations)
+                {
+                    await AddAsync(location, "definition");
+                }
+                foreach (var reference in entry.Locations)
+                {
+                    await AddAsync(reference.Location, reference.IsImplicit ? "implicit" : "reference");
+                }
+            }
+        }
+
+        result.Locations = result.Locations
+            .OrderBy(l => l.FilePath, StringComparer.OrdinalIgnoreCase)
+            .ThenBy(l => l.Line)
+            .ThenBy(l => l.Column)
+            .ToList();
+        return result;
+    }
+
+    private static async Task<ISymbol?> FindSymbolAtAsync(Solution solution, string filePath, LspPosition position,
+        CancellationToken cancellationToken)
+    {
+        var located = await LocateAsync(solution, filePath, position, cancellationToken);
+        if (located == null)
+            return null;
+
+        var (document, offset) = located.Value;
+        var symbol = await SymbolFinder.FindSymbolAtPositionAsync(document, offset, cancellationToken);
+        // A column just past the identifier, as editors report the caret
+        if (symbol == null && offset > 0)
+            symbol = await SymbolFinder.FindSymbolAtPositionAsync(document, offset - 1, cancellationToken);
+        return symbol;
+    }
+
+    private static async Task<(Document Document, int Offset)?> LocateAsync(Solution solution, string filePath, LspPosition position,
+        CancellationToken cancellationToken)
+    {
+        var documentId = solution.GetDocumentIdsWithFilePath(Path.GetFullPath(filePath)).FirstOrDefault();
+        var document = documentId == null ? null : solution.GetDocument(documentId);
+        if (document == null)
+            return null;
+
+        var text = await document.GetTextAsync(cancellationToken);
+        if (position.Line < 0 || position.Line >= text.Lines.Count)
+            return null;
+
+        var line = text.Lines[position.Line];
+        return (document, Math.Min(line.Start + Math.Max(0, position.Character), line.End));
+    }
+
+    /// <summary>
+    /// The cached solution for the workspace with on-disk edits applied, loading or reloading it as needed
+    /// </summary>
+    private async Task<Solution?> GetSolutionAsync(string workspacePath, string? requiredFile, CancellationToken cancellationToken)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        if (_failedLoads.TryGetValue(root, out var failedAt) && DateTime.UtcNow - failedAt < _retryAfter)
+            return null;
+
+        await _loadLock.WaitAsync(cancellationToken);
+        try
+        {
+            if (_solutions.TryGetValue(root, out var loaded))
+            {
+                if (loaded.TryRefresh(requiredFile, _logger))
+                    return loaded.Solution;
+
+                _logger.LogInformation("Solution for {Root} changed on disk, reloading", root);
+                _solutions.TryRemove(root, out _);
+                loaded.Dispose();
+            }
+
+            if (!EnsureMSBuildRegistered())
+            {
+                _failedLoads[root] = DateTime.UtcNow;
+                return null;
+            }
+
+            var fresh = await LoadAsync(root, cancellationToken);
+            if (fresh == null)
+            {
+                _failedLoads[root] = DateTime.UtcNow;
+                return null;
+            }
+
+            _failedLoads.TryRemove(root, out _);
+            _solutions[root] = fresh;
+            return fresh.Solution;
+        }
+        finally
+        {
+            _loadLock.Release();
+        }
+    }
+
+    private async Task<LoadedSolution?> LoadAsync(string root, CancellationToken cancellationToken)
+    {
+        var targets = FindLoadTargets(root);
+        if (targets.Count == 0)
+        {
+            _logger.LogDebug("No solution or C# project under {Root}; Roslyn analysis unavailable", root);
+            return null;
+        }
+
+        var stopwatch = Stopwatch.StartNew();
+        var workspace = MSBuildWorkspace.Create();
+        workspace.WorkspaceFailed += (_, e) =>
+            _logger.LogDebug("MSBuild workspace {Kind}: {Message}", e.Diagnostic.Kind, e.Diagnostic.Message);
+
+        try
+        {
+            if (targets[0].EndsWith(".sln", StringComparison.OrdinalIgnoreCase))
+            {
+                await workspace.OpenSolutionAsync(targets[0], cancellationToken: cancellationToken);
+            }
+            else
+            {
+                foreach (var project in targets)
+                {
+                    // Opening a project loads the projects it references; skip those already in
+                    if (workspace.CurrentSolution.Projects.Any(p => string.Equals(p.FilePath, project, StringComparison.OrdinalIgnoreCase)))
+                        continue;
+                    await workspace.OpenProjectAsync(project, cancellationToken: cancellationToken);
+                }
+            }
+        }
+        catch (Exception ex) when (ex is InvalidOperationException or IOException)
+        {
+            _logger.LogWarning("Could not load {Target} for Roslyn analysis: {Message}", targets[0], ex.Message);
+            workspace.Dispose();
+            return null;
+        }
+
+        var solution = workspace.CurrentSolution;
+        if (!solution.Projects.Any(p => p.Language == LanguageNames.CSharp))
+        {
+            workspace.Dispose();
+            return null;
+        }
+
+        var loaded = new LoadedSolution(workspace, targets);
+        _logger.LogInformation("Loaded {Target} for Roslyn analysis: {Projects} projects, {Documents} documents in {Ms}ms",
+            Path.GetFileName(targets[0]), solution.ProjectIds.Count, loaded.DocumentCount, stopwatch.ElapsedMilliseconds);
+        return loaded;
+    }
+
+    /// <summary>
+    /// The configured solution, else the solution at the workspace root (the one named after the folder when
+    /// there are several), else every C# project outside build output
+    /// </summary>
+    private List<string> FindLoadTargets(string root)
+    {
+        if (!string.IsNullOrWhiteSpace(_solutionPath))
+        {
+            var configured = Path.GetFullPath(Path.Combine(root, _solutionPath));
+            return File.Exists(configured) ? new List<string> { configured } : new List<string>();
+        }
+
+        var solutions = Directory.GetFiles(root, "*.sln").OrderBy(s => s, StringComparer.OrdinalIgnoreCase).ToList();
+        if (solutions.Count > 0)
+        {
+            var folderName = Path.GetFileName(root.TrimEnd(Path.DirectorySeparatorChar));
+            var preferred = solutions.FirstOrDefault(s => Path.GetFileNameWithoutExtension(s).Equals(folderName, StringComparison.OrdinalIgnoreCase));
+            return new List<string> { preferred ?? solutions[0] };
+        }
+
+        var projects = new List<string>();
+        var pending = new Stack<string>();
+        pending.Push(root);
+        while (pending.Count > 0 && projects.Count < _maxProjects)
+        {
+            var directory = pending.Pop();
+            try
+            {
+                projects.AddRange(Directory.GetFiles(directory, "*.csproj"));
+                foreach (var child in Directory.GetDirectories(directory))
+                {
+                    if (!SkippedDirectories.Contains(Path.GetFileName(child)))
+                        pending.Push(child);
+                }
+            }
+            catch (Exception ex) when (ex is UnauthorizedAccessException or IOException)
+            {
+            }
+        }
+        return projects.Take(_maxProjects).OrderBy(p => p, StringComparer.OrdinalIgnoreCase).ToList();
+    }
+
+    /// <summary>
+    /// MSBuildLocator has to run before any MSBuild type is loaded, so this method must not touch them
+    /// </summary>
+    private bool EnsureMSBuildRegistered()
+    {
+        lock (RegistrationLock)
+        {
+            if (_msbuildRegistered == null)
+            {
+                try
+                {
+                    if (!MSBuildLocator.IsRegistered)
+                        MSBuildLocator.RegisterDefaults();
+                    _msbuildRegistered = true;
+                }
+                catch (InvalidOperationException ex)
+                {
+                    _logger.LogWarning("No .NET SDK found for Roslyn analysis, using the index: {Message}", ex.Message);
+                    _msbuildRegistered = false;
+                }
+            }
+            return _msbuildRegistered.Value;
+        }
+    }
+
+    private static ExpressionSyntax? OutermostNameExpression(SyntaxNode node)
+    {
+        if (node is not ExpressionSyntax expression)
+            return node.FirstAncestorOrSelf<ExpressionSyntax>();
+
+        // "user.Address" on "Address" is about the member access, not the bare name
+        while (expression.Parent is MemberAccessExpressionSyntax access && access.Name == expression
+               || expression.Parent is MemberBindingExpressionSyntax)
+        {
+            expression = (ExpressionSyntax)expression.Parent;
+        }
+        return expression;
+    }
+
+    /// <summary>
+    /// The summary and returns sections of a documentation comment, with cref and paramref written as names
+    /// </summary>
+    private static string? DocumentationText(string? xml)
+    {
+        if (string.IsNullOrWhiteSpace(xml))
+            return null;
+
+        XElement element;
+        try
+        {
+            element = XElement.Parse(xml);
+        }
+        catch (XmlException)
+        {
+            return null;
+        }
+
+        static string Text(XElement section) => Regex.Replace(string.Concat(section.Nodes().Select(node => node switch
+        {
+            XText text => text.Value,
+            XElement { Name.LocalName: "see" or "seealso" } reference => ReferenceName(reference),
+            XElement { Name.LocalName: "paramref" or "typeparamref" } reference => (string?)reference.Attribute("name") ?? string.Empty,
+            XElement other => other.Value,
+            _ => string.Empty
+        })), @"\s+", " ").Trim();
+
+        var parts = new List<string>();
+        if (element.Element("summary") is { } summary && Text(summary) is { Length: > 0 } summaryText)
+            parts.Add(summaryText);
+        if (element.Element("returns") is { } returns && Text(returns) is { Length: > 0 } returnsText)
+            parts.Add($"Returns: {returnsText}");
+        return parts.Count == 0 ? null : string.Join("\n", parts);
+    }
+
+    private static string ReferenceName(XElement reference)
+    {
+        var name = (string?)reference.Attribute("cref") ?? (string?)reference.Attribute("langword") ?? reference.Value;
+        // Crefs carry a kind prefix: "T:MyApp.User", "M:MyApp.User.Save"
+        return name.Length > 2 && name[1] == ':' ? name[2..] : name;
+    }
+
+    private static ITypeSymbol? TypeOf(ISymbol symbol) => symbol switch
+    {
+        ILocalSymbol local => local.Type,
+        IParameterSymbol parameter => parameter.Type,
+        IFieldSymbol field => field.Type,
+        IPropertySymbol property => property.Type,
+        IMethodSymbol method => method.ReturnType,
+        IEventSymbol @event => @event.Type,
+        _ => null
+    };
+
+    private static NullableAnnotation AnnotationOf(ISymbol symbol) => symbol switch
+    {
+        ILocalSymbol local => local.NullableAnnotation,
+        IParameterSymbol parameter => parameter.NullableAnnotation,
+        IFieldSymbol field => field.NullableAnnotation,
+        IPropertySymbol property => property.NullableAnnotation,
+        IMethodSymbol method => method.ReturnNullableAnnotation,
+        IEventSymbol @event => @event.NullableAnnotation,
+        _ => NullableAnnotation.None
+    };
+
+    private static string Describe(NullableAnnotation annotation) => annotation switch
+    {
+        NullableAnnotation.Annotated => "annotated",
+        NullableAnnotation.NotAnnotated => "not-annotated",
+        _ => "none"
+    };
+
+    private static string Describe(NullableFlowState state) => state switch
+    {
+        NullableFlowState.NotNull => "not-null",
+        NullableFlowState.MaybeNull => "maybe-null",
+        _ => "none"
+    };
+
+    /// <summary>
+    /// Nullable reference type warnings: CS8597-CS8670, CS8714 and the attribute-driven CS8762-CS8777
+    /// </summary>
+    private static bool IsNullableWarning(string id) =>
+        id.StartsWith("CS", StringComparison.Ordinal)
+        && int.TryParse(id.AsSpan(2), out var number)
+        && (number is >= 8597 and <= 8670 or 8714 or >= 8762 and <= 8777);
+
+    private static LspRange ToLspRange(SourceText text, TextSpan span)
+    {
+        var lineSpan = text.Lines.GetLinePositionSpan(span);
+        return new LspRange(
+            new LspPosition(lineSpan.Start.Line, lineSpan.Start.Character),
+            new LspPosition(lineSpan.End.Line, lineSpan.End.Character));
+    }
+
+    public void Dispose()
+    {
+        foreach (var loaded in _solutions.Values)
+        {
+            loaded.Dispose();
+        }
+        _solutions.Clear();
+        _loadLock.Dispose();
+    }
+
+    /// <summary>
+    /// A loaded workspace plus the write times its solution reflects
+    /// </summary>
+    private sealed class LoadedSolution : IDisposable
+    {
+        private readonly MSBuildWorkspace _workspace;
+        private readonly Dictionary<string, DateTime> _projectFiles = new(StringComparer.OrdinalIgnoreCase);
+        private readonly Dictionary<string, DateTime> _documents = new(StringComparer.OrdinalIgnoreCase);
+        private readonly DateTime _loadedAt = DateTime.UtcNow;
+
+        public LoadedSolution(MSBuildWorkspace workspace, IEnumerable<string> targets)
+        {
+            _workspace = workspace;
+            Solution = workspace.CurrentSolution;
+
+            foreach (var path in targets.Concat(Solution.Projects.Select(p => p.FilePath).OfType<string>()))
+            {
+                _projectFiles[path] = File.GetLastWriteTimeUtc(path);
+            }
+            foreach (var path in Solution.Projects.SelectMany(p => p.Documents).Select(d => d.FilePath).OfType<string>())
+            {
+                _documents[path] = File.GetLastWriteTimeUtc(path);
+            }
+        }
+
+        public Solution Solution { get; private set; }
+
+        public int DocumentCount => _documents.Count;
+
+        /// <summary>
+        /// Applies changed documents; false when the solution has to be reloaded instead
+        /// </summary>
+        public bool TryRefresh(string? requiredFile, ILogger logger)
+        {
+            if (_projectFiles.Any(p => File.GetLastWriteTimeUtc(p.Key) != p.Value))
+                return false;
+
+            // A file created since loading may belong to a project through its default globs
+            if (requiredFile != null
+                && !_documents.ContainsKey(Path.GetFullPath(requiredFile))
+                && File.Exists(requiredFile)
+                && File.GetLastWriteTimeUtc(requiredFile) > _loadedAt)
+            {
+                return false;
+            }
+
+            var solution = Solution;
+            foreach (var (path, writtenAt) in _documents.ToList())
+            {
+                if (!File.Exists(path))
+                    return false;
+
+                var current = File.GetLastWriteTimeUtc(path);
+                if (current == writtenAt)
+                    continue;
+
+                try
+                {
+                    var text = SourceText.From(File.ReadAllText(path));
+                    foreach (var documentId in solution.GetDocumentIdsWithFilePath(path))
+                    {
+                        solution = solution.WithDocumentText(documentId, text);
+                    }
+                    _documents[path] = current;
+                }
+                catch (IOException ex)
+                {
+                    logger.LogDebug("Could not re-read {File}, keeping the loaded text: {Message}", path, ex.Message);
+                }
+            }
+
+            Solution = solution;
+            return true;
+        }
+
+        public void Dispose() => _workspace.Dispose();
+    }
+}
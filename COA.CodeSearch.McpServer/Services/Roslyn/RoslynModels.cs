@@ -0,0 +1,138 @@
+namespace COA.CodeSearch.McpServer.Services.Roslyn;
+
+/// <summary>
+/// Every reference to one resolved C# symbol, from the compiler's semantic model
+/// </summary>
+public class RoslynReferenceResult
+{
+    /// <summary>
+    /// Fully qualified display name, e.g. "MyApp.Services.UserService.UpdateUser(int)"
+    /// </summary>
+    public string SymbolDisplay { get; set; } = string.Empty;
+
+    public string SymbolKind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Symbols that matched a name lookup; greater than 1 for overloads or same-named types
+    /// </summary>
+    public int SymbolCount { get; set; } = 1;
+
+    public List<RoslynLocation> Locations { get; set; } = new();
+}
+
+/// <summary>
+/// A declaration or reference in a solution document
+/// </summary>
+public class RoslynLocation
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// 1-based line
+    /// </summary>
+    public int Line { get; set; }
+
+    /// <summary>
+    /// 0-based UTF-16 column
+    /// </summary>
+    public int Column { get; set; }
+
+    public bool IsDefinition { get; set; }
+
+    /// <summary>
+    /// "definition", "reference" or "implicit" (e.g. a foreach's GetEnumerator)
+    /// </summary>
+    public string ReferenceType { get; set; } = "reference";
+
+    /// <summary>
+    /// Method, property or type the location sits in
+    /// </summary>
+    public string? ContainedIn { get; set; }
+
+    public string? LineText { get; set; }
+}
+
+/// <summary>
+/// What the compiler's nullable analysis knows about the expression at a position
+/// </summary>
+public class NullableFlowResult
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+
+    public int Column { get; set; }
+
+    /// <summary>
+    /// Source text of the expression analysed
+    /// </summary>
+    public string Expression { get; set; } = string.Empty;
+
+    public string? Type { get; set; }
+
+    /// <summary>
+    /// Symbol the expression refers to or declares, when there is one
+    /// </summary>
+    public string? Symbol { get; set; }
+
+    public string? SymbolKind { get; set; }
+
+    /// <summary>
+    /// Declared annotation of the symbol or type: "annotated" (T?), "not-annotated" (T) or "none" (oblivious)
+    /// </summary>
+    public string DeclaredAnnotation { get; set; } = "none";
+
+    /// <summary>
+    /// Flow state at this point: "not-null", "maybe-null" or "none" when nullable analysis is off
+    /// </summary>
+    public string FlowState { get; set; } = "none";
+
+    public bool AnnotationsEnabled { get; set; }
+
+    public bool WarningsEnabled { get; set; }
+
+    /// <summary>
+    /// Nullable warnings (CS86xx) reported on the same line
+    /// </summary>
+    public List<string> Diagnostics { get; set; } = new();
+}
+
+/// <summary>
+/// What an editor shows when hovering the expression or declaration at a position
+/// </summary>
+public class RoslynHoverResult
+{
+    /// <summary>
+    /// Source text of the expression or declared name
+    /// </summary>
+    public string Expression { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Type of the expression (inferred for var), or of the declared symbol
+    /// </summary>
+    public string? Type { get; set; }
+
+    /// <summary>
+    /// Symbol signature, e.g. "Task&lt;User?&gt; UserService.FindAsync(string name, CancellationToken ct = default)"
+    /// </summary>
+    public string? Signature { get; set; }
+
+    public string? SymbolKind { get; set; }
+
+    /// <summary>
+    /// Summary and returns sections of the XML doc comment, as plain text
+    /// </summary>
+    public string? Documentation { get; set; }
+
+    /// <summary>
+    /// Value of a constant expression
+    /// </summary>
+    public string? ConstantValue { get; set; }
+
+    /// <summary>
+    /// Source declaration of the symbol; null for symbols from referenced assemblies
+    /// </summary>
+    public string? DeclaredInFile { get; set; }
+
+    public int? DeclaredAtLine { get; set; }
+}
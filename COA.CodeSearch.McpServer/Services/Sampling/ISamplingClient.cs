@@ -0,0 +1,43 @@
+namespace COA.CodeSearch.McpServer.Services.Sampling;
+
+/// <summary>
+/// Asks the connected client's LLM for a completion via MCP sampling (sampling/createMessage)
+/// </summary>
+public interface ISamplingClient
+{
+    /// <summary>
+    /// True when sampling is enabled and the client declared the sampling capability during initialize
+    /// </summary>
+    bool IsAvailable { get; }
+
+    /// <summary>
+    /// Sends a single-turn prompt to the client's model
+    /// </summary>
+    /// <exception cref="SamplingException">Sampling unavailable, refused by the user, failed or timed out</exception>
+    Task<SamplingResponse> CreateMessageAsync(SamplingRequest request, CancellationToken cancellationToken = default);
+}
+
+public class SamplingRequest
+{
+    public string SystemPrompt { get; set; } = string.Empty;
+    public string UserMessage { get; set; } = string.Empty;
+    public int MaxTokens { get; set; } = 800;
+
+    /// <summary>
+    /// 0-1 hint that a fast, cheap model is preferred over a capable one
+    /// </summary>
+    public double SpeedPriority { get; set; } = 0.7;
+}
+
+public class SamplingResponse
+{
+    public string Text { get; set; } = string.Empty;
+    public string? Model { get; set; }
+}
+
+public class SamplingException : Exception
+{
+    public SamplingException(string message, Exception? innerException = null) : base(message, innerException)
+    {
+    }
+}
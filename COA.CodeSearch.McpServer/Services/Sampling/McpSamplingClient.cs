@@ -0,0 +1,78 @@
+using System.Text.Json;
+using Microsoft.Extensions.Configuration;
+
+namespace COA.CodeSearch.McpServer.Services.Sampling;
+
+/// <summary>
+/// MCP sampling (sampling/createMessage), sent through the <see cref="McpClientRequestChannel"/>
+/// </summary>
+public class McpSamplingClient : ISamplingClient
+{
+    private readonly McpClientRequestChannel _channel;
+    private readonly TimeSpan _timeout;
+
+    /// <param name="configuration">CodeSearch:Sampling settings</param>
+    /// <param name="channel">Carries requests to the client and its responses back</param>
+    public McpSamplingClient(IConfiguration configuration, McpClientRequestChannel channel)
+    {
+        _channel = channel ?? throw new ArgumentNullException(nameof(channel));
+        Enabled = configuration.GetValue("CodeSearch:Sampling:Enabled", false);
+        _timeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Sampling:TimeoutSeconds", 30));
+    }
+
+    /// <summary>
+    /// CodeSearch:Sampling:Enabled - when false sampling is never attempted
+    /// </summary>
+    public bool Enabled { get; }
+
+    public bool IsAvailable => Enabled && _channel.ClientSupports("sampling");
+
+    public async Task<SamplingResponse> CreateMessageAsync(SamplingRequest request, CancellationToken cancellationToken = default)
+    {
+        if (!Enabled)
+            throw new SamplingException("Sampling is disabled (CodeSearch:Sampling:Enabled)");
+        if (!IsAvailable)
+            throw new SamplingException("The connected client does not support MCP sampling");
+
+        try
+        {
+            JsonElement response;
+            try
+            {
+                response = await _channel.SendRequestAsync("sampling/createMessage", new
+                {
+                    messages = new[] { new { role = "user", content = new { type = "text", text = request.UserMessage } } },
+                    systemPrompt = request.SystemPrompt,
+                    includeContext = "none",
+                    maxTokens = request.MaxTokens,
+                    modelPreferences = new { speedPriority = request.SpeedPriority, intelligencePriority = Math.Round(1 - request.SpeedPriority, 2) }
+                }, _timeout, cancellationToken);
+            }
+            catch (TimeoutException)
+            {
+                throw new SamplingException($"Sampling request timed out after {_timeout.TotalSeconds:0}s");
+            }
+
+            if (response.TryGetProperty("error", out var error))
+            {
+                var errorMessage = error.TryGetProperty("message", out var m) ? m.GetString() : error.ToString();
+                throw new SamplingException($"Client rejected sampling request: {errorMessage}");
+            }
+
+            var result = response.GetProperty("result");
+            var text = result.TryGetProperty("content", out var content) && content.TryGetProperty("text", out var t)
+                ? t.GetString()
+                : null;
+
+            return new SamplingResponse
+            {
+                Text = text ?? string.Empty,
+                Model = result.TryGetProperty("model", out var model) ? model.GetString() : null
+            };
+        }
+        catch (Exception ex) when (ex is JsonException or KeyNotFoundException or InvalidOperationException or IOException)
+        {
+            throw new SamplingException($"Invalid sampling response: {ex.Message}", ex);
+        }
+    }
+}
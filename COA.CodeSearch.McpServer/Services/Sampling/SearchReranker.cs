@@ -0,0 +1,230 @@
+using System.Text;
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Sampling;
+
+/// <summary>
+/// One candidate in the model's ranking, by 1-based candidate number
+/// </summary>
+public record RankedCandidate(int Id, string? Reason);
+
+/// <summary>
+/// The model's answer: candidates in relevance order plus those it judged irrelevant
+/// </summary>
+public record RankingAnswer(List<RankedCandidate> Ranked, List<int> Filtered);
+
+/// <summary>
+/// Re-ranks the top search candidates for ambiguous natural-language queries by asking the client's LLM
+/// through MCP sampling. Lucene order is always kept alongside so callers can compare or ignore the model.
+/// </summary>
+public class SearchReranker
+{
+    private const int MaxSnippetChars = 300;
+
+    private const string SystemPrompt =
+        "You rank code search results. Given a query and numbered candidates (file, line, snippet), " +
+        "order the candidates by how well they answer the query and list the ones that are clearly irrelevant. " +
+        "Reply with JSON only: {\"ranking\":[{\"id\":3,\"reason\":\"short reason\"}],\"filtered\":[5]}";
+
+    private static readonly Regex NumberPattern = new(@"\d+", RegexOptions.Compiled);
+
+    private readonly ISamplingClient _samplingClient;
+    private readonly ILogger<SearchReranker> _logger;
+    private readonly int _maxTokens;
+
+    public SearchReranker(ISamplingClient samplingClient, IConfiguration configuration, ILogger<SearchReranker> logger)
+    {
+        _samplingClient = samplingClient;
+        _logger = logger;
+        MaxCandidates = Math.Clamp(configuration.GetValue("CodeSearch:Sampling:MaxCandidates", 20), 2, 50);
+        _maxTokens = configuration.GetValue("CodeSearch:Sampling:MaxTokens", 800);
+    }
+
+    /// <summary>
+    /// Number of top hits sent to the model (CodeSearch:Sampling:MaxCandidates)
+    /// </summary>
+    public int MaxCandidates { get; }
+
+    public bool IsAvailable => _samplingClient.IsAvailable;
+
+    /// <summary>
+    /// Re-orders <paramref name="result"/>'s top hits in place and records both orders in <see cref="SearchResult.Reranking"/>.
+    /// Never throws for sampling failures - the raw order is kept and the reason recorded.
+    /// </summary>
+    public async Task<SearchReranking> RerankAsync(SearchResult result, string query, CancellationToken cancellationToken)
+    {
+        var candidates = result.Hits.Take(MaxCandidates).ToList();
+        var reranking = new SearchReranking { RawOrder = candidates.Select((h, i) => ToRanked(h, i)).ToList() };
+        result.Reranking = reranking;
+
+        if (candidates.Count < 2)
+        {
+            reranking.FallbackReason = "Fewer than two candidates - nothing to re-rank";
+            return reranking;
+        }
+        if (!_samplingClient.IsAvailable)
+        {
+            reranking.FallbackReason = "MCP sampling unavailable - enable CodeSearch:Sampling and use a client that supports sampling";
+            return reranking;
+        }
+
+        SamplingResponse response;
+        try
+        {
+            response = await _samplingClient.CreateMessageAsync(new SamplingRequest
+            {
+                SystemPrompt = SystemPrompt,
+                UserMessage = BuildPrompt(query, candidates),
+                MaxTokens = _maxTokens
+            }, cancellationToken);
+        }
+        catch (SamplingException ex)
+        {
+            _logger.LogDebug(ex, "Sampling failed while re-ranking '{Query}'", query);
+            reranking.FallbackReason = ex.Message;
+            return reranking;
+        }
+
+        reranking.Model = response.Model;
+        var answer = ParseAnswer(response.Text, candidates.Count);
+        if (answer == null)
+        {
+            reranking.FallbackReason = "Could not parse the model's ranking - raw order kept";
+            return reranking;
+        }
+        if (answer.Filtered.Count >= candidates.Count)
+        {
+            reranking.FallbackReason = "Model filtered every candidate - raw order kept";
+            return reranking;
+        }
+
+        Apply(result, candidates, answer, reranking);
+        return reranking;
+    }
+
+    public static string BuildPrompt(string query, IReadOnlyList<SearchHit> candidates)
+    {
+        var prompt = new StringBuilder();
+        prompt.AppendLine($"Query: {query}");
+        prompt.AppendLine();
+        for (var i = 0; i < candidates.Count; i++)
+        {
+            var hit = candidates[i];
+            var path = hit.RelativePath ?? hit.FilePath;
+            prompt.AppendLine(hit.LineNumber.HasValue ? $"[{i + 1}] {path}:{hit.LineNumber}" : $"[{i + 1}] {path}");
+
+            var snippet = hit.Snippet ?? hit.HighlightedFragments?.FirstOrDefault();
+            if (!string.IsNullOrWhiteSpace(snippet))
+            {
+                snippet = snippet.Trim();
+                prompt.AppendLine(snippet.Length > MaxSnippetChars ? snippet[..MaxSnippetChars] + "…" : snippet);
+            }
+            prompt.AppendLine();
+        }
+
+        return prompt.ToString();
+    }
+
+    /// <summary>
+    /// Parses the model's reply. Accepts the requested JSON (optionally fenced) or a bare list of
+    /// candidate numbers; ids outside 1..<paramref name="candidateCount"/> and duplicates are dropped.
+    /// Returns null when nothing usable was found.
+    /// </summary>
+    public static RankingAnswer? ParseAnswer(string text, int candidateCount)
+    {
+        if (string.IsNullOrWhiteSpace(text))
+            return null;
+
+        var ranked = new List<RankedCandidate>();
+        var filtered = new List<int>();
+        var seen = new HashSet<int>();
+
+        bool Accept(int id) => id >= 1 && id <= candidateCount && seen.Add(id);
+
+        var start = text.IndexOf('{');
+        var end = text.LastIndexOf('}');
+        var parsedJson = false;
+        if (start >= 0 && end > start)
+        {
+            try
+            {
+                using var document = JsonDocument.Parse(text[start..(end + 1)]);
+                var root = document.RootElement;
+                parsedJson = true;
+
+                if (root.TryGetProperty("ranking", out var ranking) && ranking.ValueKind == JsonValueKind.Array)
+                {
+                    foreach (var item in ranking.EnumerateArray())
+                    {
+                        var (id, reason) = item.ValueKind switch
+                        {
+                            JsonValueKind.Number => (item.GetInt32(), (string?)null),
+                            JsonValueKind.Object when item.TryGetProperty("id", out var idElement) && idElement.ValueKind == JsonValueKind.Number =>
+                                (idElement.GetInt32(), item.TryGetProperty("reason", out var r) && r.ValueKind == JsonValueKind.String ? r.GetString() : null),
+                            _ => (0, null)
+                        };
+                        if (Accept(id))
+                            ranked.Add(new RankedCandidate(id, reason));
+                    }
+                }
+
+                if (root.TryGetProperty("filtered", out var filteredElement) && filteredElement.ValueKind == JsonValueKind.Array)
+                {
+                    foreach (var item in filteredElement.EnumerateArray())
+                    {
+                        if (item.ValueKind == JsonValueKind.Number && Accept(item.GetInt32()))
+                            filtered.Add(item.GetInt32());
+                    }
+                }
+            }
+            catch (JsonException)
+            {
+                parsedJson = false;
+            }
+        }
+
+        if (!parsedJson)
+        {
+            foreach (Match match in NumberPattern.Matches(text))
+            {
+                if (int.TryParse(match.Value, out var id) && Accept(id))
+                    ranked.Add(new RankedCandidate(id, null));
+            }
+        }
+
+        return ranked.Count == 0 && filtered.Count == 0 ? null : new RankingAnswer(ranked, filtered);
+    }
+
+    /// <summary>
+    /// Puts ranked candidates first, then unmentioned ones in raw order, then the hits beyond the candidate window.
+    /// Filtered candidates are removed.
+    /// </summary>
+    public static void Apply(SearchResult result, IReadOnlyList<SearchHit> candidates, RankingAnswer answer, SearchReranking reranking)
+    {
+        var reasons = answer.Ranked.ToDictionary(r => r.Id, r => r.Reason);
+        var order = answer.Ranked.Select(r => r.Id)
+            .Concat(Enumerable.Range(1, candidates.Count).Where(id => !reasons.ContainsKey(id) && !answer.Filtered.Contains(id)))
+            .ToList();
+
+        reranking.RerankedOrder = order.Select(id => ToRanked(candidates[id - 1], id - 1, reasons.GetValueOrDefault(id))).ToList();
+        reranking.Filtered = answer.Filtered.Select(id => ToRanked(candidates[id - 1], id - 1)).ToList();
+        reranking.Applied = true;
+
+        var beyondWindow = result.Hits.Skip(candidates.Count);
+        result.Hits = order.Select(id => candidates[id - 1]).Concat(beyondWindow).ToList();
+        result.TotalHits = Math.Max(0, result.TotalHits - answer.Filtered.Count);
+    }
+
+    private static RankedHit ToRanked(SearchHit hit, int index, string? reason = null) => new()
+    {
+        FilePath = hit.FilePath,
+        LineNumber = hit.LineNumber,
+        Score = hit.Score,
+        RawRank = index + 1,
+        Reason = reason
+    };
+}
@@ -0,0 +1,51 @@
+namespace COA.CodeSearch.McpServer.Services.Scratch;
+
+/// <summary>
+/// In-memory staging area for generated files and snippets. Nothing touches the workspace until
+/// the staged files are committed through <see cref="UnifiedFileEditService.ApplyFileSetAsync"/>.
+/// </summary>
+public interface IScratchWorkspaceService
+{
+    /// <summary>
+    /// Stages the full content of a file (null stages a deletion), replacing any earlier staging of it
+    /// </summary>
+    /// <returns>The staged entry</returns>
+    /// <exception cref="ScratchException">Path outside the workspace or a limit exceeded</exception>
+    ScratchEntry Stage(string workspacePath, string filePath, string? content, string? note = null);
+
+    /// <summary>
+    /// The staged content if the file is staged, otherwise the file on disk (null if neither exists)
+    /// </summary>
+    string? GetEffectiveContent(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Drops one staged file. Returns false when it wasn't staged.
+    /// </summary>
+    bool Unstage(string workspacePath, string filePath);
+
+    /// <summary>
+    /// Drops every staged file of the workspace and returns how many there were
+    /// </summary>
+    int Clear(string workspacePath);
+
+    /// <summary>
+    /// Staged files ordered by path
+    /// </summary>
+    IReadOnlyList<ScratchEntry> List(string workspacePath);
+
+    /// <summary>
+    /// Compares staged files (all, or the given paths) with the workspace
+    /// </summary>
+    IReadOnlyList<ScratchFileDiff> Diff(string workspacePath, IEnumerable<string>? filePaths = null, int contextLines = 3);
+
+    /// <summary>
+    /// Searches staged content line by line
+    /// </summary>
+    IReadOnlyList<ScratchSearchMatch> Search(string workspacePath, string pattern, bool isRegex, bool caseSensitive, int maxResults);
+
+    /// <summary>
+    /// Resolves a path to its workspace-relative form (forward slashes)
+    /// </summary>
+    /// <exception cref="ScratchException">The path is outside the workspace</exception>
+    string ToRelativePath(string workspacePath, string filePath);
+}
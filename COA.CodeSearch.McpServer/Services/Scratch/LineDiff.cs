@@ -0,0 +1,153 @@
+using System.Text;
+
+namespace COA.CodeSearch.McpServer.Services.Scratch;
+
+/// <summary>
+/// Unified diff text and line counts for one file
+/// </summary>
+public record LineDiffResult(string Diff, int Added, int Removed);
+
+/// <summary>
+/// Line-level unified diff between two versions of a file, in the same format as <c>git diff</c>
+/// so it can be read (or applied) with the usual tools
+/// </summary>
+public static class LineDiff
+{
+    // Middle sections larger than this (old × new lines) are shown as a single replace hunk
+    private const long MaxLcsCells = 4_000_000;
+
+    /// <summary>
+    /// Diffs <paramref name="oldText"/> (null for a new file) against <paramref name="newText"/>
+    /// (null for a deleted file). Returns an empty diff when the texts are equal.
+    /// </summary>
+    public static LineDiffResult Unified(string? oldText, string? newText, string path, int context = 3)
+    {
+        var oldLines = SplitLines(oldText);
+        var newLines = SplitLines(newText);
+        var ops = Compute(oldLines, newLines);
+
+        var added = ops.Count(o => o.Kind == '+');
+        var removed = ops.Count(o => o.Kind == '-');
+        if (added == 0 && removed == 0)
+            return new LineDiffResult(string.Empty, 0, 0);
+
+        var builder = new StringBuilder();
+        var displayPath = path.Replace('\\', '/');
+        builder.Append("--- ").AppendLine(oldText == null ? "/dev/null" : $"a/{displayPath}");
+        builder.Append("+++ ").AppendLine(newText == null ? "/dev/null" : $"b/{displayPath}");
+
+        foreach (var (start, end) in Hunks(ops, context))
+        {
+            var oldStart = ops.Take(start).Count(o => o.Kind != '+') + 1;
+            var newStart = ops.Take(start).Count(o => o.Kind != '-') + 1;
+            var hunk = ops.GetRange(start, end - start);
+            var oldCount = hunk.Count(o => o.Kind != '+');
+            var newCount = hunk.Count(o => o.Kind != '-');
+
+            builder.AppendLine($"@@ -{(oldCount == 0 ? oldStart - 1 : oldStart)},{oldCount} +{(newCount == 0 ? newStart - 1 : newStart)},{newCount} @@");
+            foreach (var op in hunk)
+            {
+                builder.Append(op.Kind).AppendLine(op.Line);
+            }
+        }
+
+        return new LineDiffResult(builder.ToString(), added, removed);
+    }
+
+    private static List<(char Kind, string Line)> Compute(string[] a, string[] b)
+    {
+        var prefix = 0;
+        while (prefix < a.Length && prefix < b.Length && a[prefix] == b[prefix])
+            prefix++;
+
+        var suffix = 0;
+        while (suffix < a.Length - prefix && suffix < b.Length - prefix && a[^(suffix + 1)] == b[^(suffix + 1)])
+            suffix++;
+
+        var ops = new List<(char, string)>();
+        ops.AddRange(a.Take(prefix).Select(l => (' ', l)));
+
+        var midA = a[prefix..(a.Length - suffix)];
+        var midB = b[prefix..(b.Length - suffix)];
+        if ((long)midA.Length * midB.Length > MaxLcsCells)
+        {
+            ops.AddRange(midA.Select(l => ('-', l)));
+            ops.AddRange(midB.Select(l => ('+', l)));
+        }
+        else
+        {
+            ops.AddRange(Lcs(midA, midB));
+        }
+
+        ops.AddRange(a.Skip(a.Length - suffix).Select(l => (' ', l)));
+        return ops;
+    }
+
+    private static IEnumerable<(char, string)> Lcs(string[] a, string[] b)
+    {
+        // lengths[i, j] = LCS of a[i..] and b[j..]
+        var lengths = new int[a.Length + 1, b.Length + 1];
+        for (var i = a.Length - 1; i >= 0; i--)
+        {
+            for (var j = b.Length - 1; j >= 0; j--)
+            {
+                lengths[i, j] = a[i] == b[j] ? lengths[i + 1, j + 1] + 1 : Math.Max(lengths[i + 1, j], lengths[i, j + 1]);
+            }
+        }
+
+        int x = 0, y = 0;
+        while (x < a.Length && y < b.Length)
+        {
+            if (a[x] == b[y])
+            {
+                yield return (' ', a[x]);
+                x++;
+                y++;
+            }
+            else if (lengths[x + 1, y] >= lengths[x, y + 1])
+            {
+                yield return ('-', a[x++]);
+            }
+            else
+            {
+                yield return ('+', b[y++]);
+            }
+        }
+        while (x < a.Length)
+            yield return ('-', a[x++]);
+        while (y < b.Length)
+            yield return ('+', b[y++]);
+    }
+
+    /// <summary>
+    /// Ranges of ops to print: each change with up to <paramref name="context"/> unchanged lines
+    /// around it, merging changes whose context overlaps
+    /// </summary>
+    private static List<(int Start, int End)> Hunks(List<(char Kind, string Line)> ops, int context)
+    {
+        var hunks = new List<(int Start, int End)>();
+        for (var i = 0; i < ops.Count; i++)
+        {
+            if (ops[i].Kind == ' ')
+                continue;
+
+            var start = Math.Max(0, i - context);
+            var end = Math.Min(ops.Count, i + context + 1);
+            if (hunks.Count > 0 && start <= hunks[^1].End)
+                hunks[^1] = (hunks[^1].Start, Math.Max(hunks[^1].End, end));
+            else
+                hunks.Add((start, end));
+        }
+
+        return hunks;
+    }
+
+    private static string[] SplitLines(string? text)
+    {
+        if (string.IsNullOrEmpty(text))
+            return Array.Empty<string>();
+
+        var lines = text.Replace("\r\n", "\n").Split('\n');
+        return text.EndsWith('\n') ? lines[..^1] : lines;
+    }
+}
@@ -0,0 +1,76 @@
+namespace COA.CodeSearch.McpServer.Services.Scratch;
+
+/// <summary>
+/// A file staged in the scratch area. Holds the full proposed content, so diffs and commits never
+/// depend on re-applying edits.
+/// </summary>
+public class ScratchEntry
+{
+    /// <summary>
+    /// Workspace-relative path with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Proposed content; null stages a deletion
+    /// </summary>
+    public string? Content { get; set; }
+
+    /// <summary>
+    /// SHA-256 of the real file when it was first staged (null if it didn't exist), used to detect
+    /// edits made to the workspace in the meantime
+    /// </summary>
+    public string? BaseHash { get; set; }
+
+    public string? Note { get; set; }
+    public DateTime StagedAt { get; set; }
+    public DateTime UpdatedAt { get; set; }
+}
+
+/// <summary>
+/// Staged file compared with the real workspace
+/// </summary>
+public class ScratchFileDiff
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// added, modified, deleted or unchanged
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public int Added { get; set; }
+    public int Removed { get; set; }
+
+    /// <summary>
+    /// Unified diff against the file on disk
+    /// </summary>
+    public string? Diff { get; set; }
+
+    /// <summary>
+    /// The real file changed after this entry was staged; committing would overwrite that change
+    /// </summary>
+    public bool Conflict { get; set; }
+
+    public string? Note { get; set; }
+}
+
+public class ScratchSearchMatch
+{
+    public string FilePath { get; set; } = string.Empty;
+    public int LineNumber { get; set; }
+    public string Line { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Invalid scratch operation (path outside the workspace, limits exceeded, bad line range)
+/// </summary>
+public class ScratchException : Exception
+{
+    public ScratchException(string code, string message) : base(message)
+    {
+        Code = code;
+    }
+
+    public string Code { get; }
+}
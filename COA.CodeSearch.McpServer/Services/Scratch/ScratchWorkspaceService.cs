@@ -0,0 +1,211 @@
+using System.Collections.Concurrent;
+using System.Text.RegularExpressions;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Scratch;
+
+/// <summary>
+/// Keeps staged files per workspace in memory for the lifetime of the server process
+/// </summary>
+public class ScratchWorkspaceService : IScratchWorkspaceService
+{
+    private readonly ConcurrentDictionary<string, ConcurrentDictionary<string, ScratchEntry>> _workspaces = new(StringComparer.OrdinalIgnoreCase);
+    private readonly ILogger<ScratchWorkspaceService> _logger;
+    private readonly int _maxFiles;
+    private readonly int _maxFileBytes;
+
+    public ScratchWorkspaceService(IConfiguration configuration, ILogger<ScratchWorkspaceService> logger)
+    {
+        _logger = logger;
+        _maxFiles = configuration.GetValue("CodeSearch:Scratch:MaxFiles", 200);
+        _maxFileBytes = configuration.GetValue("CodeSearch:Scratch:MaxFileSizeKB", 1024) * 1024;
+    }
+
+    public ScratchEntry Stage(string workspacePath, string filePath, string? content, string? note = null)
+    {
+        var relativePath = ToRelativePath(workspacePath, filePath);
+        if (content != null && content.Length > _maxFileBytes)
+        {
+            throw new ScratchException("SCRATCH_FILE_TOO_LARGE",
+                $"{relativePath} is {content.Length / 1024} KB; the scratch limit is {_maxFileBytes / 1024} KB per file");
+        }
+
+        var entries = GetEntries(workspacePath);
+        if (!entries.ContainsKey(relativePath) && entries.Count >= _maxFiles)
+        {
+            throw new ScratchException("SCRATCH_FULL", $"The scratch area already holds {_maxFiles} files");
+        }
+
+        var now = DateTime.UtcNow;
+        var entry = entries.AddOrUpdate(relativePath,
+            _ => new ScratchEntry
+            {
+                FilePath = relativePath,
+                Content = content,
+                BaseHash = HashOnDisk(workspacePath, relativePath),
+                Note = note,
+                StagedAt = now,
+                UpdatedAt = now
+            },
+            // Re-staging keeps the original base so a conflict is still detected
+            (_, existing) =>
+            {
+                existing.Content = content;
+                existing.Note = note ?? existing.Note;
+                existing.UpdatedAt = now;
+                return existing;
+            });
+
+        _logger.LogDebug("Staged {FilePath} in scratch for {Workspace} ({State})",
+            relativePath, workspacePath, content == null ? "deletion" : $"{content.Length} chars");
+        return entry;
+    }
+
+    public string? GetEffectiveContent(string workspacePath, string filePath)
+    {
+        var relativePath = ToRelativePath(workspacePath, filePath);
+        if (GetEntries(workspacePath).TryGetValue(relativePath, out var entry))
+            return entry.Content;
+
+        var fullPath = Path.Combine(workspacePath, relativePath);
+        return File.Exists(fullPath) ? File.ReadAllText(fullPath) : null;
+    }
+
+    public bool Unstage(string workspacePath, string filePath) =>
+        GetEntries(workspacePath).TryRemove(ToRelativePath(workspacePath, filePath), out _);
+
+    public int Clear(string workspacePath)
+    {
+        var entries = GetEntries(workspacePath);
+        var count = entries.Count;
+        entries.Clear();
+        return count;
+    }
+
+    public IReadOnlyList<ScratchEntry> List(string workspacePath) =>
+        GetEntries(workspacePath).Values.OrderBy(e => e.FilePath, StringComparer.Ordinal).ToList();
+
+    public IReadOnlyList<ScratchFileDiff> Diff(string workspacePath, IEnumerable<string>? filePaths = null, int contextLines = 3)
+    {
+        var wanted = filePaths?.Select(p => ToRelativePath(workspacePath, p)).ToHashSet(StringComparer.Ordinal);
+        var diffs = new List<ScratchFileDiff>();
+
+        foreach (var entry in List(workspacePath).Where(e => wanted == null || wanted.Contains(e.FilePath)))
+        {
+            var fullPath = Path.Combine(workspacePath, entry.FilePath);
+            var onDisk = File.Exists(fullPath) ? File.ReadAllText(fullPath) : null;
+            var diff = LineDiff.Unified(onDisk, entry.Content, entry.FilePath, contextLines);
+
+            diffs.Add(new ScratchFileDiff
+            {
+                FilePath = entry.FilePath,
+                Status = onDisk == null
+                    ? entry.Content == null ? "unchanged" : "added"
+                    : entry.Content == null ? "deleted" : diff.Diff.Length == 0 ? "unchanged" : "modified",
+                Added = diff.Added,
+                Removed = diff.Removed,
+                Diff = diff.Diff.Length > 0 ? diff.Diff : null,
+                Conflict = HashOnDisk(workspacePath, entry.FilePath) != entry.BaseHash,
+                Note = entry.Note
+            });
+        }
+
+        return diffs;
+    }
+
+    public IReadOnlyList<ScratchSearchMatch> Search(string workspacePath, string pattern, bool isRegex, bool caseSensitive, int maxResults)
+    {
+        var regex = new Regex(isRegex ? pattern : Regex.Escape(pattern),
+            caseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase, TimeSpan.FromSeconds(2));
+
+        var matches = new List<ScratchSearchMatch>();
+        foreach (var entry in List(workspacePath).Where(e => e.Content != null))
+        {
+            var lines = entry.Content!.Replace("\r\n", "\n").Split('\n');
+            for (var i = 0; i < lines.Length; i++)
+            {
+                if (!regex.IsMatch(lines[i]))
+                    continue;
+
+                matches.Add(new ScratchSearchMatch { FilePath = entry.FilePath, LineNumber = i + 1, Line = lines[i].Trim() });
+                if (matches.Count >= maxResults)
+                    return matches;
+            }
+        }
+
+        return matches;
+    }
+
+    public string ToRelativePath(string workspacePath, string filePath)
+    {
+        var root = Path.GetFullPath(workspacePath);
+        var fullPath = Path.GetFullPath(filePath, root);
+        var relativePath = Path.GetRelativePath(root, fullPath);
+
+        if (relativePath == "." || relativePath == ".." || relativePath.StartsWith(".." + Path.DirectorySeparatorChar) || Path.IsPathRooted(relativePath))
+        {
+            throw new ScratchException("PATH_OUTSIDE_WORKSPACE", $"{filePath} is not inside the workspace {root}");
+        }
+
+        return relativePath.Replace('\\', '/');
+    }
+
+    /// <summary>
+    /// Applies a line edit to content the way edit_lines does: insert before <paramref name="startLine"/>
+    /// (up to one past the end), or replace/delete lines <paramref name="startLine"/>-<paramref name="endLine"/>
+    /// </summary>
+    /// <exception cref="ScratchException">The line range is outside the content</exception>
+    public static string ApplyLineEdit(string content, string operation, int startLine, int? endLine, string? text)
+    {
+        var newline = content.Contains("\r\n") ? "\r\n" : "\n";
+        var trailingNewline = content.EndsWith('\n');
+        var lines = SplitLines(content);
+        var newLines = text == null ? new List<string>() : SplitLines(text);
+        if (text != null && newLines.Count == 0)
+            newLines.Add(string.Empty);
+
+        var last = endLine ?? startLine;
+        switch (operation)
+        {
+            case "insert":
+                if (startLine < 1 || startLine > lines.Count + 1)
+                    throw new ScratchException("INVALID_LINE_RANGE", $"Insert line {startLine} is outside 1-{lines.Count + 1}");
+                lines.InsertRange(startLine - 1, newLines);
+                break;
+
+            case "replace":
+            case "delete":
+                if (startLine < 1 || last < startLine || last > lines.Count)
+                    throw new ScratchException("INVALID_LINE_RANGE", $"Lines {startLine}-{last} are outside 1-{lines.Count}");
+                lines.RemoveRange(startLine - 1, last - startLine + 1);
+                if (operation == "replace")
+                    lines.InsertRange(startLine - 1, newLines);
+                break;
+
+            default:
+                throw new ArgumentException($"Unknown line edit '{operation}'", nameof(operation));
+        }
+
+        var result = string.Join(newline, lines);
+        return trailingNewline || content.Length == 0 && result.Length > 0 ? result + newline : result;
+    }
+
+    // A single trailing newline ends the last line rather than starting an empty one
+    private static List<string> SplitLines(string text)
+    {
+        var normalized = text.Replace("\r\n", "\n");
+        if (normalized.EndsWith('\n'))
+            normalized = normalized[..^1];
+        return normalized.Length == 0 && text.Length == 0 ? new List<string>() : normalized.Split('\n').ToList();
+    }
+
+    private ConcurrentDictionary<string, ScratchEntry> GetEntries(string workspacePath) =>
+        _workspaces.GetOrAdd(Path.GetFullPath(workspacePath), _ => new ConcurrentDictionary<string, ScratchEntry>(StringComparer.Ordinal));
+
+    private static string? HashOnDisk(string workspacePath, string relativePath)
+    {
+        var fullPath = Path.Combine(workspacePath, relativePath);
+        return File.Exists(fullPath) ? UnifiedFileEditService.ComputeHash(File.ReadAllBytes(fullPath)) : null;
+    }
+}
@@ -0,0 +1,83 @@
+using System.Text.RegularExpressions;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// A line that looks like it contains a credential. The value itself is never kept - only a masked preview.
+/// </summary>
+public class SecretFinding
+{
+    public string Rule { get; set; } = string.Empty;
+    public int LineNumber { get; set; }
+    public string MaskedPreview { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Stable identity of the finding (rule + hash of the line), used to avoid repeat notifications
+    /// </summary>
+    public string Fingerprint { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// Detects well-known credential formats in file content
+/// </summary>
+public static class SecretScanner
+{
+    private static readonly (string Rule, Regex Pattern)[] Rules =
+    {
+        ("aws-access-key", new Regex(@"\b(AKIA|ASIA)[0-9A-Z]{16}\b", RegexOptions.Compiled)),
+        ("github-token", new Regex(@"\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,}\b", RegexOptions.Compiled)),
+        ("slack-token", new Regex(@"\bxox[abprs]-[A-Za-z0-9-]{10,}\b", RegexOptions.Compiled)),
+        ("private-key", new Regex(@"-----BEGIN (RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY( BLOCK)?-----", RegexOptions.Compiled)),
+        ("google-api-key", new Regex(@"\bAIza[0-9A-Za-z_\-]{35}\b", RegexOptions.Compiled)),
+        ("stripe-key", new Regex(@"\b(sk|rk)_live_[0-9A-Za-z]{24,}\b", RegexOptions.Compiled)),
+        ("connection-string-password", new Regex(@"(?i)(?:^|[;""'])\s*(password|pwd)\s*=\s*[^;'""\s]{6,}", RegexOptions.Compiled)),
+        ("generic-api-key", new Regex(@"(?i)\b(api[_-]?key|secret[_-]?key|access[_-]?token|client[_-]?secret)\b[""']?\s*[:=]\s*[""'][A-Za-z0-9_\-+/=]{16,}[""']", RegexOptions.Compiled))
+    };
+
+    /// <summary>
+    /// Scans content line by line; at most one finding per line
+    /// </summary>
+    public static IReadOnlyList<SecretFinding> Scan(string content)
+    {
+        var findings = new List<SecretFinding>();
+        if (string.IsNullOrEmpty(content))
+            return findings;
+
+        var lines = content.Split('\n');
+        for (var i = 0; i < lines.Length; i++)
+        {
+            var line = lines[i];
+            foreach (var (rule, pattern) in Rules)
+            {
+                var match = pattern.Match(line);
+                if (!match.Success)
+                    continue;
+
+                findings.Add(new SecretFinding
+                {
+                    Rule = rule,
+                    LineNumber = i + 1,
+                    MaskedPreview = Mask(line.Trim(), match.Value),
+                    Fingerprint = $"{rule}:{HashLine(line.Trim())}"
+                });
+                break;
+            }
+        }
+
+        return findings;
+    }
+
+    private static string Mask(string line, string secret)
+    {
+        var visible = Math.Min(4, secret.Length / 4);
+        var masked = secret.Substring(0, visible) + new string('*', Math.Min(12, secret.Length - visible));
+        var preview = line.Replace(secret, masked);
+        return preview.Length > 120 ? preview.Substring(0, 117) + "..." : preview;
+    }
+
+    private static string HashLine(string line)
+    {
+        var hash = System.Security.Cryptography.SHA256.HashData(System.Text.Encoding.UTF8.GetBytes(line));
+        return Convert.ToHexString(hash, 0, 8);
+    }
+}
@@ -0,0 +1,87 @@
+using System.Collections.Concurrent;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Default <see cref="IShutdownCoordinator"/> backed by a cancellation source and a registry of running operations
+/// </summary>
+public class ShutdownCoordinator : IShutdownCoordinator, IDisposable
+{
+    private readonly ILogger<ShutdownCoordinator> _logger;
+    private readonly CancellationTokenSource _shutdownCts = new();
+    private readonly ConcurrentDictionary<long, string> _operations = new();
+    private long _nextOperationId;
+    private int _shutdownStarted;
+
+    public ShutdownCoordinator(IConfiguration configuration, ILogger<ShutdownCoordinator> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        Timeout = TimeSpan.FromSeconds(Math.Max(1, configuration.GetValue("CodeSearch:Shutdown:TimeoutSeconds", 10)));
+    }
+
+    public bool IsShuttingDown => Volatile.Read(ref _shutdownStarted) == 1;
+
+    public CancellationToken ShutdownToken => _shutdownCts.Token;
+
+    public TimeSpan Timeout { get; }
+
+    public IDisposable TrackOperation(string name)
+    {
+        var id = Interlocked.Increment(ref _nextOperationId);
+        _operations[id] = name;
+        return new OperationHandle(this, id);
+    }
+
+    public IReadOnlyList<string> GetActiveOperations() => _operations.Values.ToList();
+
+    public bool BeginShutdown()
+    {
+        if (Interlocked.Exchange(ref _shutdownStarted, 1) == 1)
+            return false;
+
+        _logger.LogInformation("Shutdown requested - {Count} operation(s) in flight", _operations.Count);
+        _shutdownCts.Cancel();
+        return true;
+    }
+
+    public async Task<bool> WaitForOperationsAsync(CancellationToken cancellationToken)
+    {
+        while (!_operations.IsEmpty)
+        {
+            if (cancellationToken.IsCancellationRequested)
+                return false;
+
+            try
+            {
+                await Task.Delay(50, cancellationToken);
+            }
+            catch (OperationCanceledException)
+            {
+                return false;
+            }
+        }
+
+        return true;
+    }
+
+    public void Dispose()
+    {
+        _shutdownCts.Dispose();
+    }
+
+    private sealed class OperationHandle : IDisposable
+    {
+        private readonly ShutdownCoordinator _owner;
+        private readonly long _id;
+
+        public OperationHandle(ShutdownCoordinator owner, long id)
+        {
+            _owner = owner;
+            _id = id;
+        }
+
+        public void Dispose() => _owner._operations.TryRemove(_id, out _);
+    }
+}
@@ -0,0 +1,205 @@
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Rule-based planner for smart_search: splits a natural-language request into clauses, reduces words to
+/// prefixes with programming synonyms, and emits an identifier query and a full-text query per clause
+/// </summary>
+public static class SmartSearchPlanner
+{
+    public const int MaxClauses = 4;
+    private const int MinPrefixLength = 3;
+
+    private static readonly Regex ClauseSeparator = new(@"\b(?:and|or|then|also|plus|as well as)\b|[,;]", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+    private static readonly Regex WordPattern = new(@"[A-Za-z][A-Za-z0-9_]*", RegexOptions.Compiled);
+
+    private static readonly HashSet<string> StopWords = new(StringComparer.OrdinalIgnoreCase)
+    {
+        "a", "an", "the", "of", "to", "in", "on", "at", "for", "with", "by", "from", "into", "about", "as",
+        "is", "are", "was", "were", "be", "been", "being", "get", "gets", "got", "do", "does", "did", "done",
+        "find", "show", "me", "where", "how", "what", "which", "when", "why", "who", "whether",
+        "that", "this", "these", "those", "there", "it", "its", "they", "them", "their", "we", "our", "i", "my", "you",
+        "all", "any", "some", "every", "each", "code", "logic", "place", "places", "part", "parts", "thing", "things",
+        "function", "functions", "method", "methods", "class", "classes", "file", "files",
+        "implemented", "implementation", "defined", "located", "happen", "happens", "handled", "look", "looking",
+        "search", "locate", "please", "can", "could", "would", "should", "need", "needs", "want", "not"
+    };
+
+    private static readonly string[][] SynonymGroups =
+    {
+        new[] { "deactivate", "disable", "suspend" },
+        new[] { "activate", "enable" },
+        new[] { "delete", "remove", "destroy", "purge" },
+        new[] { "create", "add", "insert", "register" },
+        new[] { "update", "modify", "edit", "change" },
+        new[] { "fetch", "load", "retrieve", "read" },
+        new[] { "save", "store", "persist", "write" },
+        new[] { "cache", "memoize" },
+        new[] { "validate", "verify", "check" },
+        new[] { "login", "signin", "authenticate" },
+        new[] { "logout", "signout" },
+        new[] { "error", "exception", "failure" },
+        new[] { "send", "publish", "emit", "dispatch" },
+        new[] { "notify", "alert" },
+        new[] { "config", "configuration", "settings", "options" },
+        new[] { "parse", "deserialize" },
+        new[] { "serialize", "encode" }
+    };
+
+    private static readonly Dictionary<string, string[]> Synonyms = BuildSynonyms();
+
+    /// <summary>
+    /// Builds the plan for a request. Returns an empty plan when the request has no searchable words.
+    /// </summary>
+    public static SmartSearchPlan Plan(string request, bool expandSynonyms = true)
+    {
+        var plan = new SmartSearchPlan();
+
+        foreach (var clauseText in ClauseSeparator.Split(request).Select(c => c.Trim()).Where(c => c.Length > 0))
+        {
+            var concepts = ExtractConcepts(clauseText, expandSynonyms);
+            if (concepts.Count == 0)
+                continue;
+
+            var clause = new PlanClause { Text = clauseText, Concepts = concepts };
+
+            // "users are deactivated and cached" - a lone verb borrows the previous clause's subject
+            var previous = plan.Clauses.LastOrDefault();
+            if (concepts.Count == 1 && previous is { Concepts.Count: > 1 } &&
+                previous.Concepts[0].Prefix != concepts[0].Prefix)
+            {
+                clause.Concepts.Insert(0, previous.Concepts[0]);
+                clause.Inherited = previous.Concepts[0].Word;
+            }
+
+            plan.Clauses.Add(clause);
+            if (plan.Clauses.Count == MaxClauses)
+                break;
+        }
+
+        var id = 1;
+        for (var i = 0; i < plan.Clauses.Count; i++)
+        {
+            var concepts = plan.Clauses[i].Concepts;
+            plan.Steps.Add(new PlanStep
+            {
+                Id = id++,
+                Clause = i,
+                Field = "content_symbols",
+                Purpose = $"Identifiers combining {Describe(concepts)}",
+                Query = Format("content_symbols", concepts),
+                Weight = 1.5
+            });
+            plan.Steps.Add(new PlanStep
+            {
+                Id = id++,
+                Clause = i,
+                Field = "content",
+                Purpose = $"Any text mentioning {Describe(concepts)}",
+                Query = Format("content", concepts),
+                Weight = 1.0
+            });
+        }
+
+        return plan;
+    }
+
+    /// <summary>
+    /// Lucene query for a step: every concept required, each matched by its prefix or a synonym prefix
+    /// </summary>
+    public static Query ToLuceneQuery(PlanStep step, PlanClause clause)
+    {
+        var query = new BooleanQuery();
+        foreach (var concept in clause.Concepts)
+        {
+            var alternatives = new BooleanQuery();
+            foreach (var prefix in concept.Alternatives.Prepend(concept.Prefix))
+            {
+                alternatives.Add(new PrefixQuery(new Term(step.Field, prefix)), Occur.SHOULD);
+            }
+            query.Add(alternatives, Occur.MUST);
+        }
+        return query;
+    }
+
+    /// <summary>
+    /// Reduces a word to a search prefix: plural, tense and -ation endings and a trailing e are dropped,
+    /// so "deactivated", "deactivation" and "deactivate" all become a prefix of each other
+    /// </summary>
+    public static string ToPrefix(string word)
+    {
+        var w = word.ToLowerInvariant();
+        if (w.Length > 4 && (w.EndsWith("ies") || w.EndsWith("ied")))
+            return w[..^3] + "y";
+
+        foreach (var suffix in new[] { "ations", "ation", "ings", "ing", "ed", "es", "s" })
+        {
+            if (w.EndsWith(suffix) && w.Length - suffix.Length >= MinPrefixLength && !w.EndsWith("ss"))
+            {
+                w = w[..^suffix.Length];
+
+                // "settings" → "set", "running" → "run"
+                if (suffix.StartsWith("ing") && w.Length > MinPrefixLength && w[^1] == w[^2] && !"aeiousl".Contains(w[^1]))
+                    w = w[..^1];
+                break;
+            }
+        }
+
+        return w.Length > MinPrefixLength && w.EndsWith('e') ? w[..^1] : w;
+    }
+
+    private static List<PlanConcept> ExtractConcepts(string clause, bool expandSynonyms)
+    {
+        var concepts = new List<PlanConcept>();
+        foreach (Match match in WordPattern.Matches(clause))
+        {
+            // Identifiers in the request ("UserCache") contribute each of their words
+            foreach (var word in QuerySyntaxGuide.SplitWords(match.Value))
+            {
+                if (word.Length < MinPrefixLength || StopWords.Contains(word))
+                    continue;
+
+                // Synonyms of a word already in the clause ("configuration settings") add nothing
+                var prefix = ToPrefix(word);
+                if (concepts.Any(c => c.Prefix == prefix || (expandSynonyms && c.Alternatives.Contains(prefix))))
+                    continue;
+
+                concepts.Add(new PlanConcept
+                {
+                    Word = word.ToLowerInvariant(),
+                    Prefix = prefix,
+                    Alternatives = expandSynonyms && Synonyms.TryGetValue(prefix, out var synonyms) ? synonyms.ToList() : new List<string>()
+                });
+            }
+        }
+        return concepts;
+    }
+
+    private static Dictionary<string, string[]> BuildSynonyms()
+    {
+        var synonyms = new Dictionary<string, string[]>();
+        foreach (var group in SynonymGroups)
+        {
+            var prefixes = group.Select(ToPrefix).ToArray();
+            foreach (var prefix in prefixes)
+            {
+                synonyms[prefix] = prefixes.Where(p => p != prefix).ToArray();
+            }
+        }
+        return synonyms;
+    }
+
+    private static string Describe(List<PlanConcept> concepts) =>
+        string.Join(" + ", concepts.Select(c => $"'{c.Word}'"));
+
+    private static string Format(string field, List<PlanConcept> concepts) =>
+        string.Join(" AND ", concepts.Select(c =>
+        {
+            var prefixes = c.Alternatives.Prepend(c.Prefix).Select(p => $"{field}:{p}*").ToList();
+            return prefixes.Count == 1 ? prefixes[0] : $"({string.Join(" OR ", prefixes)})";
+        }));
+}
@@ -0,0 +1,162 @@
+using System.Text;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Converts columns between the two conventions in play: the symbol database (tree-sitter) counts UTF-8 bytes,
+/// editors speaking LSP count UTF-16 code units. They only agree on ASCII lines, so every location returned
+/// carries both. Lines are 1-based, columns 0-based. One instance caches file lines for the duration of a request.
+/// </summary>
+public sealed class SourcePositions
+{
+    public const string Utf16 = "utf-16";
+    public const string Utf8 = "utf-8";
+
+    private static readonly Regex LocationPattern = new(@"^(?<path>.+?):(?<line>\d+):(?<column>\d+)$", RegexOptions.Compiled);
+
+    private readonly Func<string, CancellationToken, Task<string?>> _loadContent;
+    private readonly Dictionary<string, string[]?> _lines = new(StringComparer.OrdinalIgnoreCase);
+
+    /// <param name="loadContent">Loads a file's text by the path used in locations; null when it is unavailable</param>
+    public SourcePositions(Func<string, CancellationToken, Task<string?>> loadContent)
+    {
+        _loadContent = loadContent;
+    }
+
+    /// <summary>
+    /// True for utf-16 (LSP default) and utf-8 (byte columns)
+    /// </summary>
+    public static bool IsValidEncoding(string? encoding) =>
+        string.Equals(encoding, Utf16, StringComparison.OrdinalIgnoreCase) ||
+        string.Equals(encoding, Utf8, StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// UTF-16 column of a byte column. A byte column inside a multibyte character maps to that character;
+    /// one past the end of the line keeps counting past it.
+    /// </summary>
+    public static int ByteToUtf16Column(string lineText, int byteColumn)
+    {
+        var bytes = 0;
+        for (var i = 0; i < lineText.Length; i++)
+        {
+            if (bytes >= byteColumn)
+                return i;
+
+            var c = lineText[i];
+            if (char.IsHighSurrogate(c) && i + 1 < lineText.Length && char.IsLowSurrogate(lineText[i + 1]))
+            {
+                bytes += 4;
+                if (bytes > byteColumn)
+                    return i;
+                i++;
+            }
+            else
+            {
+                bytes += c < 0x80 ? 1 : c < 0x800 ? 2 : 3;
+                if (bytes > byteColumn)
+                    return i;
+            }
+        }
+
+        return lineText.Length + Math.Max(0, byteColumn - bytes);
+    }
+
+    /// <summary>
+    /// Byte column of a UTF-16 column. A column between the halves of a surrogate pair maps to the pair's start.
+    /// </summary>
+    public static int Utf16ToByteColumn(string lineText, int utf16Column)
+    {
+        var column = Math.Max(0, utf16Column);
+        var within = Math.Min(column, lineText.Length);
+        if (within > 0 && within < lineText.Length && char.IsHighSurrogate(lineText[within - 1]))
+            within--;
+
+        return Encoding.UTF8.GetByteCount(lineText.AsSpan(0, within)) + Math.Max(0, column - lineText.Length);
+    }
+
+    /// <summary>
+    /// Parses "path:line:column" (1-based line, 0-based column) as accepted in place of a symbol name
+    /// </summary>
+    public static bool TryParseLocation(string value, out string filePath, out int line, out int column)
+    {
+        var match = LocationPattern.Match(value.Trim());
+        filePath = match.Success ? match.Groups["path"].Value : string.Empty;
+        line = match.Success ? int.Parse(match.Groups["line"].Value) : 0;
+        column = match.Success ? int.Parse(match.Groups["column"].Value) : 0;
+        return match.Success && line > 0;
+    }
+
+    /// <summary>
+    /// Text of a 1-based line, or null when the file or line is unavailable
+    /// </summary>
+    public async Task<string?> GetLineAsync(string filePath, int line, CancellationToken cancellationToken = default)
+    {
+        if (!_lines.TryGetValue(filePath, out var lines))
+        {
+            string? content = null;
+            try
+            {
+                content = await _loadContent(filePath, cancellationToken);
+            }
+            catch (IOException)
+            {
+            }
+            catch (UnauthorizedAccessException)
+            {
+            }
+
+            lines = content?.Split('\n').Select(l => l.TrimEnd('\r')).ToArray();
+            _lines[filePath] = lines;
+        }
+
+        return lines != null && line >= 1 && line <= lines.Length ? lines[line - 1] : null;
+    }
+
+    /// <summary>
+    /// UTF-16 column for a byte column; the byte column itself when the line can't be read
+    /// </summary>
+    public async Task<int> ToUtf16ColumnAsync(string filePath, int line, int byteColumn, CancellationToken cancellationToken = default)
+    {
+        var text = await GetLineAsync(filePath, line, cancellationToken);
+        return text == null ? byteColumn : ByteToUtf16Column(text, byteColumn);
+    }
+
+    /// <summary>
+    /// Byte column for a UTF-16 column; the column itself when the line can't be read
+    /// </summary>
+    public async Task<int> ToByteColumnAsync(string filePath, int line, int utf16Column, CancellationToken cancellationToken = default)
+    {
+        var text = await GetLineAsync(filePath, line, cancellationToken);
+        return text == null ? utf16Column : Utf16ToByteColumn(text, utf16Column);
+    }
+
+    /// <summary>
+    /// The identifier touching the given position, or null. Column is in the given encoding (utf-16 or utf-8).
+    /// </summary>
+    public async Task<string?> GetIdentifierAtAsync(string filePath, int line, int column, string encoding,
+        CancellationToken cancellationToken = default)
+    {
+        var text = await GetLineAsync(filePath, line, cancellationToken);
+        if (text == null)
+            return null;
+
+        var index = string.Equals(encoding, Utf8, StringComparison.OrdinalIgnoreCase)
+            ? ByteToUtf16Column(text, column)
+            : column;
+
+        // LSP positions sit between characters; a cursor right after a name still means that name
+        if (index >= text.Length || !UnicodeIdentifiers.IsIdentifierPart(text[index]))
+            index--;
+        if (index < 0 || index >= text.Length || !UnicodeIdentifiers.IsIdentifierPart(text[index]))
+            return null;
+
+        var start = index;
+        while (start > 0 && UnicodeIdentifiers.IsIdentifierPart(text[start - 1])) start--;
+        var end = index + 1;
+        while (end < text.Length && UnicodeIdentifiers.IsIdentifierPart(text[end])) end++;
+
+        return text[start..end];
+    }
+}
@@ -59,6 +59,12 @@ public interface ISQLiteSymbolService
         long lastModified,
         CancellationToken cancellationToken = default);
 
+    /// <summary>
+    /// Replace a file's symbols without touching its file record, for corrections made after extraction.
+    /// Identifiers whose containing symbol is gone are deleted with it.
+    /// </summary>
+    Task ReplaceFileSymbolsAsync(string workspacePath, string filePath, List<JulieSymbol> symbols, CancellationToken cancellationToken = default);
+
     /// <summary>
     /// Delete file and all its symbols (for file watcher deletions)
     /// </summary>
@@ -188,6 +194,16 @@ public interface ISQLiteSymbolService
         List<string>? relationshipKinds = null,
         CancellationToken cancellationToken = default);
 
+    /// <summary>
+    /// Get relationships pointing at specific symbols (e.g. the types that extend or implement them).
+    /// Same as <see cref="GetRelationshipsForSymbolsAsync"/>, keyed by the target symbol ID instead of the source.
+    /// </summary>
+    Task<Dictionary<string, List<JulieRelationship>>> GetRelationshipsToSymbolsAsync(
+        string workspacePath,
+        List<string> symbolIds,
+        List<string>? relationshipKinds = null,
+        CancellationToken cancellationToken = default);
+
     /// <summary>
     /// Search for symbols using semantic similarity (Tier 3 - semantic search).
     /// Returns symbols semantically similar to the query text, ordered by similarity score.
@@ -1,7 +1,9 @@
 using System.IO;
 using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Services.Analysis;
 using COA.CodeSearch.McpServer.Services.Embeddings;
 using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Logging;
 using Microsoft.Data.Sqlite;
 using Microsoft.Extensions.Logging;
 
@@ -18,17 +20,20 @@ public class SQLiteSymbolService : ISQLiteSymbolService
     private readonly IPathResolutionService _pathResolution;
     private readonly ISqliteVecExtensionService _vecExtension;
     private readonly IEmbeddingService _embeddingService;
+    private readonly NetworkFileSystem.INetworkFileSystemService? _networkFileSystem;
 
     public SQLiteSymbolService(
         ILogger<SQLiteSymbolService> logger,
         IPathResolutionService pathResolution,
         ISqliteVecExtensionService vecExtension,
-        IEmbeddingService embeddingService)
+        IEmbeddingService embeddingService,
+        NetworkFileSystem.INetworkFileSystemService? networkFileSystem = null)
     {
         _logger = logger ?? throw new ArgumentNullException(nameof(logger));
         _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
         _vecExtension = vecExtension ?? throw new ArgumentNullException(nameof(vecExtension));
         _embeddingService = embeddingService ?? throw new ArgumentNullException(nameof(embeddingService));
+        _networkFileSystem = networkFileSystem;
     }
 
     /// <summary>
@@ -61,6 +66,33 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         using var cmd = connection.CreateCommand();
         cmd.CommandText = "PRAGMA busy_timeout = 5000";
         cmd.ExecuteNonQuery();
+
+        // Memory-mapped pages of a database on a network share go stale or fault when the share drops
+        if (_networkFileSystem?.AppliesTo(connection.DataSource) == true)
+        {
+            cmd.CommandText = "PRAGMA mmap_size = 0";
+            cmd.ExecuteNonQuery();
+        }
+
+        // NOCASE only folds ASCII; these compare identifiers the way the Lucene analyzer does
+        connection.CreateCollation(IdentifierCollation, UnicodeIdentifiers.CompareNormalized);
+        connection.CreateCollation(IdentifierNoCaseCollation, UnicodeIdentifiers.CompareIgnoreCase);
+    }
+
+    private const string IdentifierCollation = "IDENTIFIER";
+    private const string IdentifierNoCaseCollation = "IDENTIFIER_NOCASE";
+
+    /// <summary>
+    /// WHERE predicate matching the name column against @name. Case-insensitive matching folds Unicode case
+    /// (including Turkish I); both modes treat NFC and NFD spellings as equal. Plain ASCII case-sensitive
+    /// lookups keep the indexed comparison.
+    /// </summary>
+    private static string NameEquals(string name, bool caseSensitive)
+    {
+        if (!caseSensitive)
+            return $"name = @name COLLATE {IdentifierNoCaseCollation}";
+
+        return name.All(char.IsAscii) ? "name = @name" : $"name = @name COLLATE {IdentifierCollation}";
     }
 
     public string GetDatabasePath(string workspacePath)
@@ -109,10 +141,11 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         await connection.OpenAsync(cancellationToken);
         ConfigureConnection(connection);
 
-        // Enable WAL mode for concurrent access
+        // Enable WAL mode for concurrent access. WAL's shared-memory index only works when every process is on
+        // one host, so databases on network file systems keep a rollback journal.
         using (var walCmd = connection.CreateCommand())
         {
-            walCmd.CommandText = "PRAGMA journal_mode=WAL;";
+            walCmd.CommandText = _networkFileSystem?.AppliesTo(dbPath) == true ? "PRAGMA journal_mode=DELETE;" : "PRAGMA journal_mode=WAL;";
             await walCmd.ExecuteNonQueryAsync(cancellationToken);
         }
 
@@ -290,10 +323,7 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         ConfigureConnection(connection);
 
         using var cmd = connection.CreateCommand();
-        // Use COLLATE NOCASE for case-insensitive searches
-        cmd.CommandText = caseSensitive
-            ? "SELECT * FROM symbols WHERE name = @name"
-            : "SELECT * FROM symbols WHERE name = @name COLLATE NOCASE";
+        cmd.CommandText = $"SELECT * FROM symbols WHERE {NameEquals(name, caseSensitive)}";
         cmd.Parameters.AddWithValue("@name", name);
 
         return await ReadSymbolsAsync(cmd, cancellationToken);
@@ -404,35 +434,7 @@ public class SQLiteSymbolService : ISQLiteSymbolService
             // Insert new symbols
             foreach (var symbol in symbols)
             {
-                using var cmd = connection.CreateCommand();
-                cmd.Transaction = transaction;
-                cmd.CommandText = @"
-                    INSERT INTO symbols
-                    (id, name, kind, language, file_path, signature, start_line, start_col, end_line, end_col,
-                     start_byte, end_byte, doc_comment, visibility, parent_id, workspace_id, file_hash, last_indexed)
-                    VALUES (@id, @name, @kind, @language, @file_path, @signature, @start_line, @start_col, @end_line, @end_col,
-                            @start_byte, @end_byte, @doc_comment, @visibility, @parent_id, @workspace_id, @file_hash, @last_indexed)";
-
-                cmd.Parameters.AddWithValue("@id", symbol.Id);
-                cmd.Parameters.AddWithValue("@name", symbol.Name);
-                cmd.Parameters.AddWithValue("@kind", symbol.Kind);
-                cmd.Parameters.AddWithValue("@language", symbol.Language);
-                cmd.Parameters.AddWithValue("@file_path", symbol.FilePath);
-                cmd.Parameters.AddWithValue("@signature", (object?)symbol.Signature ?? DBNull.Value);
-                cmd.Parameters.AddWithValue("@start_line", symbol.StartLine);
-                cmd.Parameters.AddWithValue("@start_col", symbol.StartColumn);
-                cmd.Parameters.AddWithValue("@end_line", symbol.EndLine);
-                cmd.Parameters.AddWithValue("@end_col", symbol.EndColumn);
-                cmd.Parameters.AddWithValue("@start_byte", 0); // Not provided by julie-extract JSON
-                cmd.Parameters.AddWithValue("@end_byte", 0);
-                cmd.Parameters.AddWithValue("@doc_comment", (object?)symbol.DocComment ?? DBNull.Value);
-                cmd.Parameters.AddWithValue("@visibility", (object?)symbol.Visibility ?? DBNull.Value);
-                cmd.Parameters.AddWithValue("@parent_id", (object?)symbol.ParentId ?? DBNull.Value);
-                cmd.Parameters.AddWithValue("@workspace_id", workspacePath);
-                cmd.Parameters.AddWithValue("@file_hash", hash);
-                cmd.Parameters.AddWithValue("@last_indexed", DateTimeOffset.UtcNow.ToUnixTimeSeconds());
-
-                await cmd.ExecuteNonQueryAsync(cancellationToken);
+                await InsertSymbolAsync(connection, transaction, symbol, workspacePath, hash, cancellationToken);
             }
 
             // NOTE: Embeddings for incremental updates are now handled by julie-semantic + FileWatcherService
@@ -450,6 +452,108 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         }
     }
 
+    public async Task ReplaceFileSymbolsAsync(string workspacePath, string filePath, List<JulieSymbol> symbols, CancellationToken cancellationToken = default)
+    {
+        var dbPath = GetDatabasePath(workspacePath);
+        using var connection = new SqliteConnection(GetConnectionString(dbPath));
+        await connection.OpenAsync(cancellationToken);
+        ConfigureConnection(connection);
+
+        using var transaction = connection.BeginTransaction();
+        try
+        {
+            string? hash;
+            using (var cmd = connection.CreateCommand())
+            {
+                cmd.Transaction = transaction;
+                cmd.CommandText = "SELECT hash FROM files WHERE path = @path";
+                cmd.Parameters.AddWithValue("@path", filePath);
+                hash = await cmd.ExecuteScalarAsync(cancellationToken) as string;
+            }
+
+            using (var cmd = connection.CreateCommand())
+            {
+                cmd.Transaction = transaction;
+                cmd.CommandText = "DELETE FROM symbols WHERE file_path = @file_path";
+                cmd.Parameters.AddWithValue("@file_path", filePath);
+                await cmd.ExecuteNonQueryAsync(cancellationToken);
+            }
+
+            foreach (var symbol in symbols)
+            {
+                await InsertSymbolAsync(connection, transaction, symbol, workspacePath, hash ?? string.Empty, cancellationToken);
+            }
+
+            using (var cmd = connection.CreateCommand())
+            {
+                cmd.Transaction = transaction;
+                cmd.CommandText = @"
+                    DELETE FROM identifiers
+                    WHERE file_path = @file_path
+                      AND containing_symbol_id IS NOT NULL
+                      AND containing_symbol_id NOT IN (SELECT id FROM symbols WHERE file_path = @file_path)";
+                cmd.Parameters.AddWithValue("@file_path", filePath);
+                await cmd.ExecuteNonQueryAsync(cancellationToken);
+            }
+
+            using (var cmd = connection.CreateCommand())
+            {
+                cmd.Transaction = transaction;
+                cmd.CommandText = "UPDATE files SET symbol_count = @symbol_count WHERE path = @path";
+                cmd.Parameters.AddWithValue("@symbol_count", symbols.Count);
+                cmd.Parameters.AddWithValue("@path", filePath);
+                await cmd.ExecuteNonQueryAsync(cancellationToken);
+            }
+
+            await transaction.CommitAsync(cancellationToken);
+            _logger.LogDebug("Replaced symbols of {FilePath} with {SymbolCount} symbols", filePath, symbols.Count);
+        }
+        catch
+        {
+            await transaction.RollbackAsync(cancellationToken);
+            throw;
+        }
+    }
+
+    private static async Task InsertSymbolAsync(
+        SqliteConnection connection,
+        SqliteTransaction transaction,
+        JulieSymbol symbol,
+        string workspacePath,
+        string hash,
+        CancellationToken cancellationToken)
+    {
+        using var cmd = connection.CreateCommand();
+        cmd.Transaction = transaction;
+        cmd.CommandText = @"
+            INSERT INTO symbols
+            (id, name, kind, language, file_path, signature, start_line, start_col, end_line, end_col,
+             start_byte, end_byte, doc_comment, visibility, parent_id, workspace_id, file_hash, last_indexed)
+            VALUES (@id, @name, @kind, @language, @file_path, @signature, @start_line, @start_col, @end_line, @end_col,
+                    @start_byte, @end_byte, @doc_comment, @visibility, @parent_id, @workspace_id, @file_hash, @last_indexed)";
+
+        cmd.Parameters.AddWithValue("@id", symbol.Id);
+        cmd.Parameters.AddWithValue("@name", symbol.Name);
+        cmd.Parameters.AddWithValue("@kind", symbol.Kind);
+        cmd.Parameters.AddWithValue("@language", symbol.Language);
+        cmd.Parameters.AddWithValue("@file_path", symbol.FilePath);
+        cmd.Parameters.AddWithValue("@signature", (object?)symbol.Signature ?? DBNull.Value);
+        cmd.Parameters.AddWithValue("@start_line", symbol.StartLine);
+        cmd.Parameters.AddWithValue("@start_col", symbol.StartColumn);
+        cmd.Parameters.AddWithValue("@end_line", symbol.EndLine);
+        cmd.Parameters.AddWithValue("@end_col", symbol.EndColumn);
+        cmd.Parameters.AddWithValue("@start_byte", 0); // Not provided by julie-extract JSON
+        cmd.Parameters.AddWithValue("@end_byte", 0);
+        cmd.Parameters.AddWithValue("@doc_comment", (object?)symbol.DocComment ?? DBNull.Value);
+        cmd.Parameters.AddWithValue("@visibility", (object?)symbol.Visibility ?? DBNull.Value);
+        cmd.Parameters.AddWithValue("@parent_id", (object?)symbol.ParentId ?? DBNull.Value);
+        cmd.Parameters.AddWithValue("@workspace_id", workspacePath);
+        cmd.Parameters.AddWithValue("@file_hash", hash);
+        cmd.Parameters.AddWithValue("@last_indexed", DateTimeOffset.UtcNow.ToUnixTimeSeconds());
+
+        await cmd.ExecuteNonQueryAsync(cancellationToken);
+    }
+
     public async Task DeleteFileAsync(string workspacePath, string filePath, CancellationToken cancellationToken = default)
     {
         var dbPath = GetDatabasePath(workspacePath);
@@ -827,6 +931,7 @@ public class SQLiteSymbolService : ISQLiteSymbolService
             ));
         }
 
+        QueryUsage.RecordDocuments(files.Count, files.Sum(f => f.Content != null ? f.Size : 0));
         _logger.LogDebug("Retrieved {FileCount} files from SQLite database", files.Count);
         return files;
     }
@@ -854,13 +959,15 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         using var reader = await cmd.ExecuteReaderAsync(cancellationToken);
         if (await reader.ReadAsync(cancellationToken))
         {
-            return new FileRecord(
+            var file = new FileRecord(
                 Path: reader.GetString(reader.GetOrdinal("path")),
                 Content: reader.IsDBNull(reader.GetOrdinal("content")) ? null : reader.GetString(reader.GetOrdinal("content")),
                 Language: reader.GetString(reader.GetOrdinal("language")),
                 Size: reader.GetInt64(reader.GetOrdinal("size")),
                 LastModified: reader.GetInt64(reader.GetOrdinal("last_modified"))
             );
+            QueryUsage.RecordDocuments(1, file.Content != null ? file.Size : 0);
+            return file;
         }
 
         return null;
@@ -1206,9 +1313,7 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         ConfigureConnection(connection);
 
         using var cmd = connection.CreateCommand();
-        cmd.CommandText = caseSensitive
-            ? "SELECT COUNT(*) FROM identifiers WHERE name = @name"
-            : "SELECT COUNT(*) FROM identifiers WHERE name = @name COLLATE NOCASE";
+        cmd.CommandText = $"SELECT COUNT(*) FROM identifiers WHERE {NameEquals(name, caseSensitive)}";
         cmd.Parameters.AddWithValue("@name", name);
 
         var result = await cmd.ExecuteScalarAsync(cancellationToken);
@@ -1235,9 +1340,7 @@ public class SQLiteSymbolService : ISQLiteSymbolService
         await connection.OpenAsync(cancellationToken);
         ConfigureConnection(connection);
 
-        var query = caseSensitive
-            ? "SELECT * FROM identifiers WHERE name = @name"
-            : "SELECT * FROM identifiers WHERE name = @name COLLATE NOCASE";
+        var query = $"SELECT * FROM identifiers WHERE {NameEquals(name, caseSensitive)}";
 
         using var cmd = connection.CreateCommand();
         cmd.CommandText = query;
@@ -1558,7 +1661,7 @@ WITH RECURSIVE call_chain AS (
     i.id as path  -- Track visited nodes for cycle detection
   FROM identifiers i
   LEFT JOIN symbols s ON i.containing_symbol_id = s.id
-  WHERE i.name " + (caseSensitive ? "=" : "LIKE") + @" @symbol_name
+  WHERE i.name " + (caseSensitive ? "=" : $"COLLATE {IdentifierNoCaseCollation} =") + @" @symbol_name
     AND i.kind = 'call'
 
   UNION ALL
@@ -1581,7 +1684,7 @@ WITH RECURSIVE call_chain AS (
     cc.path || '|' || i2.id  -- Pipe-separated path for cycle detection
   FROM identifiers i2
   LEFT JOIN symbols s2 ON i2.containing_symbol_id = s2.id
-  INNER JOIN call_chain cc ON i2.name " + (caseSensitive ? "=" : "LIKE") + @" cc.containing_symbol_name
+  INNER JOIN call_chain cc ON i2.name " + (caseSensitive ? "=" : $"COLLATE {IdentifierNoCaseCollation} =") + @" cc.containing_symbol_name
   WHERE
     cc.depth < @max_depth  -- Prevent runaway queries
     AND i2.kind = 'call'
@@ -1674,7 +1777,7 @@ WITH RECURSIVE call_chain AS (
   FROM symbols target
   INNER JOIN identifiers i ON i.containing_symbol_id = target.id
   LEFT JOIN symbols s ON i.containing_symbol_id = s.id
-  WHERE target.name " + (caseSensitive ? "=" : "LIKE") + @" @symbol_name
+  WHERE target.name " + (caseSensitive ? "=" : $"COLLATE {IdentifierNoCaseCollation} =") + @" @symbol_name
     AND i.kind = 'call'
 
   UNION ALL
@@ -1696,7 +1799,7 @@ WITH RECURSIVE call_chain AS (
     cc.depth + 1,
     cc.path || '|' || i2.id
   FROM call_chain cc
-  INNER JOIN symbols target2 ON target2.name " + (caseSensitive ? "=" : "LIKE") + @" cc.name
+  INNER JOIN symbols target2 ON target2.name " + (caseSensitive ? "=" : $"COLLATE {IdentifierNoCaseCollation} =") + @" cc.name
   INNER JOIN identifiers i2 ON i2.containing_symbol_id = target2.id
   WHERE
     cc.depth < @max_depth
@@ -1747,11 +1850,30 @@ LIMIT 1000;";
         return results;
     }
 
-    public async Task<Dictionary<string, List<JulieRelationship>>> GetRelationshipsForSymbolsAsync(
+    public Task<Dictionary<string, List<JulieRelationship>>> GetRelationshipsForSymbolsAsync(
+        string workspacePath,
+        List<string> symbolIds,
+        List<string>? relationshipKinds = null,
+        CancellationToken cancellationToken = default)
+    {
+        return GetRelationshipsAsync(workspacePath, symbolIds, relationshipKinds, incoming: false, cancellationToken);
+    }
+
+    public Task<Dictionary<string, List<JulieRelationship>>> GetRelationshipsToSymbolsAsync(
         string workspacePath,
         List<string> symbolIds,
         List<string>? relationshipKinds = null,
         CancellationToken cancellationToken = default)
+    {
+        return GetRelationshipsAsync(workspacePath, symbolIds, relationshipKinds, incoming: true, cancellationToken);
+    }
+
+    private async Task<Dictionary<string, List<JulieRelationship>>> GetRelationshipsAsync(
+        string workspacePath,
+        List<string> symbolIds,
+        List<string>? relationshipKinds,
+        bool incoming,
+        CancellationToken cancellationToken)
     {
         var dbPath = GetDatabasePath(workspacePath);
         if (!File.Exists(dbPath))
@@ -1787,7 +1909,7 @@ LIMIT 1000;";
         var sql = $@"
             SELECT id, from_symbol_id, to_symbol_id, kind, file_path, line_number, confidence, metadata
             FROM relationships
-            WHERE from_symbol_id IN ({symbolIdParams})";
+            WHERE {(incoming ? "to_symbol_id" : "from_symbol_id")} IN ({symbolIdParams})";
 
         // Add kind filter if specified
         if (relationshipKinds != null && relationshipKinds.Count > 0)
@@ -1796,7 +1918,7 @@ LIMIT 1000;";
             sql += $" AND kind IN ({kindParams})";
         }
 
-        sql += " ORDER BY from_symbol_id, kind";
+        sql += incoming ? " ORDER BY to_symbol_id, kind" : " ORDER BY from_symbol_id, kind";
 
         using var cmd = connection.CreateCommand();
         cmd.CommandText = sql;
@@ -1840,9 +1962,10 @@ LIMIT 1000;";
             };
 
             // Add to the appropriate symbol's list
-            if (results.ContainsKey(relationship.FromSymbolId))
+            var key = incoming ? relationship.ToSymbolId : relationship.FromSymbolId;
+            if (results.ContainsKey(key))
             {
-                results[relationship.FromSymbolId].Add(relationship);
+                results[key].Add(relationship);
             }
         }
 
@@ -102,12 +102,17 @@ public class StartupIndexingService : BackgroundService
                 {
                     var stats = await _luceneIndexService.GetStatisticsAsync(workspacePath, stoppingToken);
                     
+                    // Opening the index above runs crash recovery; a recreated or repaired index always needs a full pass
+                    var recovery = _luceneIndexService.GetRecoveryReport(workspacePath);
+                    
                     // Skip if index was updated in the last hour
-                    if (DateTimeOffset.UtcNow - new DateTimeOffset(stats.LastModified) < TimeSpan.FromHours(1))
+                    if (recovery?.RequiresFullReindex != true &&
+                        DateTimeOffset.UtcNow - new DateTimeOffset(stats.LastModified) < TimeSpan.FromHours(1))
                     {
+                        var replayed = await _fileIndexingService.ReplayInterruptedWritesAsync(workspacePath, stoppingToken);
                         _logger.LogInformation(
-                            "Index for {Path} is up-to-date (last modified: {LastModified}). Skipping auto-index.",
-                            workspacePath, stats.LastModified);
+                            "Index for {Path} is up-to-date (last modified: {LastModified}, replayed {Replayed} interrupted writes). Skipping auto-index.",
+                            workspacePath, stats.LastModified, replayed);
                         return;
                     }
                 }
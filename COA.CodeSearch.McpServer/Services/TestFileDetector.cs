@@ -0,0 +1,52 @@
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// Classifies test files by path and naming conventions across languages
+/// (FooTests.cs, foo.test.ts, foo_test.go, test_foo.py, tests/ and __tests__/ folders)
+/// </summary>
+public static class TestFileDetector
+{
+    private static readonly string[] TestDirectories = { "test", "tests", "spec", "specs", "__tests__" };
+
+    public static bool IsTestFile(string path)
+    {
+        var normalized = path.Replace('\\', '/');
+        var segments = normalized.Split('/', StringSplitOptions.RemoveEmptyEntries);
+
+        // Directories only: a file named "test.cs" in src/ is not enough on its own
+        foreach (var directory in segments.Take(segments.Length - 1))
+        {
+            if (TestDirectories.Contains(directory, StringComparer.OrdinalIgnoreCase) ||
+                directory.EndsWith(".Tests", StringComparison.OrdinalIgnoreCase) ||
+                directory.EndsWith(".Test", StringComparison.OrdinalIgnoreCase))
+            {
+                return true;
+            }
+        }
+
+        var name = Path.GetFileNameWithoutExtension(segments.LastOrDefault() ?? string.Empty);
+        return name.EndsWith("Test", StringComparison.Ordinal) ||
+               name.EndsWith("Tests", StringComparison.Ordinal) ||
+               name.EndsWith(".test", StringComparison.OrdinalIgnoreCase) ||
+               name.EndsWith(".spec", StringComparison.OrdinalIgnoreCase) ||
+               name.EndsWith("_test", StringComparison.OrdinalIgnoreCase) ||
+               name.EndsWith("_spec", StringComparison.OrdinalIgnoreCase) ||
+               name.StartsWith("test_", StringComparison.OrdinalIgnoreCase);
+    }
+
+    /// <summary>
+    /// File name patterns (for SearchFilesByPatternAsync) of tests conventionally named after a source file
+    /// </summary>
+    public static IEnumerable<string> GetConventionalTestPatterns(string sourcePath)
+    {
+        var stem = Path.GetFileNameWithoutExtension(sourcePath);
+        if (string.IsNullOrEmpty(stem))
+            yield break;
+
+        yield return $"{stem}Test*";
+        yield return $"{stem}.test.*";
+        yield return $"{stem}.spec.*";
+        yield return $"{stem}_test.*";
+        yield return $"test_{stem}.*";
+    }
+}
@@ -13,6 +13,11 @@ public class TypeExtractionResult
     public List<MethodInfo> Methods { get; set; } = new();
     public string? Language { get; set; }
 
+    /// <summary>
+    /// Generic types and functions instantiated in the file with explicit type arguments (Go: <c>Stack[int]</c>)
+    /// </summary>
+    public List<GenericInstantiation>? Instantiations { get; set; }
+
     /// <summary>
     /// Shared JSON deserialization options for TypeExtractionResult.
     /// Uses case-insensitive property matching to handle varying JSON casing from different sources.
@@ -36,6 +41,18 @@ public class TypeInfo
     public List<string> Modifiers { get; set; } = new();
     public string? BaseType { get; set; }
     public List<string>? Interfaces { get; set; }
+    public List<TypeParameterInfo>? TypeParameters { get; set; }
+
+    /// <summary>
+    /// For constraint interfaces: the union terms of the type set, e.g. <c>int</c>, <c>~float64</c>
+    /// </summary>
+    public List<string>? TypeSet { get; set; }
+
+    /// <summary>
+    /// Go: types embedded in a struct or interface as written (<c>Base</c>, <c>*io.Reader</c>), whose fields
+    /// and methods are promoted to it
+    /// </summary>
+    public List<string>? Embedded { get; set; }
 }
 
 /// <summary>
@@ -51,4 +68,46 @@ public class MethodInfo
     public string? ContainingType { get; set; }
     public List<string> Parameters { get; set; } = new();
     public List<string> Modifiers { get; set; } = new();
+    public List<TypeParameterInfo>? TypeParameters { get; set; }
+
+    /// <summary>
+    /// Go: parameter and result types with names and package qualifiers dropped, as method sets compare them
+    /// (<c>(Context,string)(*User,error)</c>); ContainingType is then the receiver type or the interface
+    /// </summary>
+    public string? MethodSetSignature { get; set; }
+
+    /// <summary>
+    /// Go: whether the receiver is a pointer (<c>func (s *Store)</c>), so only the pointer type has the method;
+    /// null for an interface method spec
+    /// </summary>
+    public bool? PointerReceiver { get; set; }
+}
+
+/// <summary>
+/// A type parameter of a generic type or function
+/// </summary>
+public class TypeParameterInfo
+{
+    public required string Name { get; set; }
+
+    /// <summary>
+    /// Constraint as written (<c>any</c>, <c>comparable</c>, <c>Number</c>, <c>~int | ~string</c>); null for a
+    /// receiver's type parameter whose type is declared elsewhere
+    /// </summary>
+    public string? Constraint { get; set; }
+
+    /// <summary>
+    /// Union terms of the constraint, when it is a union or names a constraint interface declared in the same file
+    /// </summary>
+    public List<string>? Union { get; set; }
+}
+
+/// <summary>
+/// A use of a generic type or function with explicit type arguments
+/// </summary>
+public class GenericInstantiation
+{
+    public required string Name { get; set; }
+    public List<string> TypeArguments { get; set; } = new();
+    public int Line { get; set; }
 }
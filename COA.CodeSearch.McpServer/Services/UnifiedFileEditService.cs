@@ -15,14 +15,16 @@ public class UnifiedFileEditService
 {
     private readonly diff_match_patch _dmp;
     private readonly ILogger<UnifiedFileEditService> _logger;
+    private readonly FileWatcherService? _fileWatcher;
     
     // File-level synchronization to prevent concurrent edits
     private static readonly ConcurrentDictionary<string, SemaphoreSlim> _fileLocks = new();
     private static readonly SemaphoreSlim _lockCreationSemaphore = new(1, 1);
 
-    public UnifiedFileEditService(ILogger<UnifiedFileEditService> logger)
+    public UnifiedFileEditService(ILogger<UnifiedFileEditService> logger, FileWatcherService? fileWatcher = null)
     {
         _logger = logger;
+        _fileWatcher = fileWatcher; // Records writes so the next query sees them (read-your-writes)
         
         // Configure DiffMatchPatch for optimal performance
         _dmp = new diff_match_patch();
@@ -101,6 +103,7 @@ public class UnifiedFileEditService
                     encoding,
                     originalContent,
                     cancellationToken);
+                _fileWatcher?.RecordWrite(normalizedPath);
 
                 _logger.LogInformation("Applied {ChangeCount} changes to {FilePath}", 
                     CountChanges(diffs.ToList()), normalizedPath);
@@ -412,6 +415,7 @@ public class UnifiedFileEditService
                 encoding,
                 originalContent,
                 cancellationToken);
+            _fileWatcher?.RecordWrite(normalizedPath);
 
             return result;
         }
@@ -542,6 +546,7 @@ public class UnifiedFileEditService
                 encoding,
                 originalContent,
                 cancellationToken);
+            _fileWatcher?.RecordWrite(normalizedPath);
 
             return result;
         }
@@ -647,6 +652,7 @@ public class UnifiedFileEditService
                 encoding,
                 originalContent,
                 cancellationToken);
+            _fileWatcher?.RecordWrite(normalizedPath);
 
             return result;
         }
@@ -665,4 +671,131 @@ public class UnifiedFileEditService
             fileLock.Release();
         }
     }
-}
\ No newline at end of file
+
+    /// <summary>
+    /// Applies changes to several files as one unit: all files are locked, checked against their
+    /// expected hashes, then replaced through temp files. If any write fails, the files already
+    /// written are restored, so the workspace ends up either fully updated or unchanged.
+    /// </summary>
+    public async Task<FileSetResult> ApplyFileSetAsync(
+        IReadOnlyList<FileSetChange> changes,
+        CancellationToken cancellationToken = default)
+    {
+        var result = new FileSetResult();
+        var ordered = changes
+            .Select(c => (Change: c, Path: Path.GetFullPath(c.FilePath)))
+            .OrderBy(c => c.Path, StringComparer.Ordinal)
+            .ToList();
+
+        if (ordered.Select(c => c.Path).Distinct(StringComparer.Ordinal).Count() != ordered.Count)
+            throw new ArgumentException("Each file may appear only once in a file set", nameof(changes));
+
+        // Fixed lock order so two overlapping sets can't deadlock
+        var locks = new List<SemaphoreSlim>();
+        try
+        {
+            foreach (var (_, path) in ordered)
+            {
+                var fileLock = await GetFileLockAsync(path);
+                await fileLock.WaitAsync(cancellationToken);
+                locks.Add(fileLock);
+            }
+
+            var originals = new Dictionary<string, byte[]?>(StringComparer.Ordinal);
+            foreach (var (change, path) in ordered)
+            {
+                var bytes = File.Exists(path) ? await File.ReadAllBytesAsync(path, cancellationToken) : null;
+                originals[path] = bytes;
+
+                if (change.CheckExpectedHash && ComputeHash(bytes) != change.ExpectedHash)
+                {
+                    result.Conflicts.Add(path);
+                }
+            }
+
+            if (result.Conflicts.Count > 0)
+            {
+                result.ErrorMessage = $"{result.Conflicts.Count} file(s) changed since the edit was prepared";
+                return result;
+            }
+
+            var applied = new List<string>();
+            try
+            {
+                foreach (var (change, path) in ordered)
+                {
+                    cancellationToken.ThrowIfCancellationRequested();
+                    var original = originals[path];
+
+                    if (change.NewContent == null)
+                    {
+                        if (original != null)
+                        {
+                            File.Delete(path);
+                            applied.Add(path);
+                            result.Deleted.Add(path);
+                        }
+                        continue;
+                    }
+
+                    // Keep the file's BOM/encoding; new files are UTF-8 without BOM
+                    var encoding = original != null ? FileLineUtilities.DetectEncoding(original) : new UTF8Encoding(false);
+                    Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+                    var tempPath = path + ".codesearch-tmp";
+                    await File.WriteAllTextAsync(tempPath, change.NewContent, encoding, cancellationToken);
+                    File.Move(tempPath, path, overwrite: true);
+                    applied.Add(path);
+                    result.Written.Add(path);
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogError(ex, "File set failed after {Count} file(s); restoring", applied.Count);
+                foreach (var (_, path) in ordered.Where(c => File.Exists(c.Path + ".codesearch-tmp")))
+                {
+                    File.Delete(path + ".codesearch-tmp");
+                }
+                foreach (var path in applied)
+                {
+                    try
+                    {
+                        if (originals[path] is { } bytes)
+                            await File.WriteAllBytesAsync(path, bytes, CancellationToken.None);
+                        else
+                            File.Delete(path);
+                    }
+                    catch (Exception restoreEx)
+                    {
+                        _logger.LogError(restoreEx, "Failed to restore {FilePath}", path);
+                    }
+                }
+
+                result.Written.Clear();
+                result.Deleted.Clear();
+                result.RolledBack = applied.Count > 0;
+                result.ErrorMessage = $"Failed to apply changes: {ex.Message}";
+                return result;
+            }
+
+            result.Success = true;
+            foreach (var path in result.Written)
+            {
+                _fileWatcher?.RecordWrite(path);
+            }
+            return result;
+        }
+        finally
+        {
+            foreach (var fileLock in locks)
+            {
+                fileLock.Release();
+            }
+        }
+    }
+
+    /// <summary>
+    /// SHA-256 (lowercase hex) of a file's bytes; null for a missing file
+    /// </summary>
+    public static string? ComputeHash(byte[]? bytes) =>
+        bytes == null ? null : Convert.ToHexString(System.Security.Cryptography.SHA256.HashData(bytes)).ToLowerInvariant();
+}
@@ -0,0 +1,41 @@
+namespace COA.CodeSearch.McpServer.Services.Watches;
+
+/// <summary>
+/// Standing queries per workspace. The file watcher hands every re-indexed file to
+/// <see cref="EvaluateAsync"/>; lines that newly match a watch become alerts, which are pushed to the client
+/// as notifications and kept for retrieval through the watch tool.
+/// </summary>
+public interface IWatchService
+{
+    /// <summary>
+    /// Whether the workspace has any watch, so the file watcher can skip reading the previous content
+    /// </summary>
+    bool HasWatches(string workspacePath);
+
+    /// <summary>
+    /// Registers a watch and saves it beside the workspace index
+    /// </summary>
+    /// <exception cref="WatchException">Empty or invalid pattern, or too many watches</exception>
+    WatchDefinition Add(string workspacePath, WatchDefinition watch);
+
+    bool Remove(string workspacePath, string watchId);
+
+    IReadOnlyList<WatchDefinition> List(string workspacePath);
+
+    /// <summary>
+    /// Alerts newer than <paramref name="afterSequence"/>, oldest first
+    /// </summary>
+    IReadOnlyList<WatchAlert> GetAlerts(string workspacePath, string? watchId = null, long afterSequence = 0);
+
+    /// <summary>
+    /// Compares a re-indexed file with its content before the update and raises an alert for every line that
+    /// matches a watch and was not there before
+    /// </summary>
+    /// <param name="workspacePath">Workspace the file belongs to</param>
+    /// <param name="filePath">Absolute path of the updated file</param>
+    /// <param name="previousContent">Content before the update; null for a new file</param>
+    /// <param name="cancellationToken">Cancellation token</param>
+    /// <returns>The alerts raised</returns>
+    Task<IReadOnlyList<WatchAlert>> EvaluateAsync(string workspacePath, string filePath, string? previousContent,
+        CancellationToken cancellationToken = default);
+}
@@ -0,0 +1,63 @@
+namespace COA.CodeSearch.McpServer.Services.Watches;
+
+/// <summary>
+/// A standing query evaluated on every incremental index update, e.g. new usages of Thread.Sleep
+/// or new TODOs under payments/
+/// </summary>
+public class WatchDefinition
+{
+    public string Id { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Label shown in alerts; defaults to the pattern
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Text (or regex) a line must contain to match
+    /// </summary>
+    public string Pattern { get; set; } = string.Empty;
+
+    public bool IsRegex { get; set; }
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Glob limiting the files watched: "*.cs" matches names anywhere, "payments/**" a directory
+    /// </summary>
+    public string? FilePattern { get; set; }
+
+    public DateTime CreatedAt { get; set; } = DateTime.UtcNow;
+}
+
+/// <summary>
+/// A line that started matching a watch in an index update
+/// </summary>
+public class WatchAlert
+{
+    /// <summary>
+    /// Increases with every alert; pass the last one seen to fetch only newer alerts
+    /// </summary>
+    public long Sequence { get; set; }
+
+    public string WatchId { get; set; } = string.Empty;
+    public string WatchName { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Workspace-relative path with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    public int Line { get; set; }
+    public string LineText { get; set; } = string.Empty;
+    public DateTime DetectedAt { get; set; } = DateTime.UtcNow;
+}
+
+public class WatchException : Exception
+{
+    public WatchException(string code, string message) : base(message)
+    {
+        Code = code;
+    }
+
+    public string Code { get; }
+}
@@ -0,0 +1,318 @@
+using System.Text.Json;
+using System.Text.RegularExpressions;
+using COA.CodeSearch.McpServer.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.Watches;
+
+/// <summary>
+/// Keeps watches in watches.json beside each workspace index and recent alerts in memory. A line counts as new
+/// when the updated file has more lines with that text than the previous content did, so edits that only move
+/// existing matches around raise nothing.
+/// </summary>
+public class WatchService : IWatchService
+{
+    private const string WatchesFileName = "watches.json";
+    private static readonly TimeSpan RegexTimeout = TimeSpan.FromSeconds(1);
+
+    private readonly IPathResolutionService _pathResolution;
+    private readonly IWebhookService? _webhooks;
+    private readonly TextWriter? _output;
+    private readonly ILogger<WatchService> _logger;
+    private readonly int _maxWatches;
+    private readonly int _maxAlerts;
+    private readonly int _maxAlertsPerUpdate;
+    private readonly bool _notifyClients;
+    private readonly object _sync = new();
+    private readonly Dictionary<string, List<WatchDefinition>> _watches = new(StringComparer.OrdinalIgnoreCase);
+    private readonly Dictionary<string, Regex> _regexCache = new(StringComparer.Ordinal);
+    private readonly LinkedList<(string WorkspacePath, WatchAlert Alert)> _alerts = new();
+    private long _nextSequence;
+
+    /// <param name="pathResolution">Locates the workspace index directory watches are saved in</param>
+    /// <param name="configuration">CodeSearch:Watches settings</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="webhooks">Optional webhook delivery for watch.matched events</param>
+    /// <param name="output">Where client notifications are written; defaults to the current Console.Out (the transport's stdout)</param>
+    public WatchService(
+        IPathResolutionService pathResolution,
+        IConfiguration configuration,
+        ILogger<WatchService> logger,
+        IWebhookService? webhooks = null,
+        TextWriter? output = null)
+    {
+        _pathResolution = pathResolution ?? throw new ArgumentNullException(nameof(pathResolution));
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _webhooks = webhooks;
+        _output = output;
+        _maxWatches = configuration.GetValue("CodeSearch:Watches:MaxWatches", 50);
+        _maxAlerts = configuration.GetValue("CodeSearch:Watches:MaxAlerts", 500);
+        _maxAlertsPerUpdate = configuration.GetValue("CodeSearch:Watches:MaxAlertsPerUpdate", 20);
+        _notifyClients = configuration.GetValue("CodeSearch:Watches:NotifyClients", true);
+    }
+
+    public bool HasWatches(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceWatches(workspacePath).Count > 0;
+        }
+    }
+
+    public WatchDefinition Add(string workspacePath, WatchDefinition watch)
+    {
+        if (string.IsNullOrWhiteSpace(watch.Pattern))
+            throw new WatchException("MISSING_PATTERN", "A watch needs a pattern");
+
+        try
+        {
+            BuildRegex(watch);
+        }
+        catch (ArgumentException ex)
+        {
+            throw new WatchException("INVALID_PATTERN", $"Invalid regex '{watch.Pattern}': {ex.Message}");
+        }
+
+        lock (_sync)
+        {
+            var watches = GetWorkspaceWatches(workspacePath);
+            if (watches.Count >= _maxWatches)
+                throw new WatchException("TOO_MANY_WATCHES", $"The workspace already has {watches.Count} watches (CodeSearch:Watches:MaxWatches)");
+
+            watch.Id = Guid.NewGuid().ToString("N")[..8];
+            watch.Name = string.IsNullOrWhiteSpace(watch.Name) ? watch.Pattern : watch.Name;
+            watch.FilePattern = string.IsNullOrWhiteSpace(watch.FilePattern) ? null : watch.FilePattern;
+            watch.CreatedAt = DateTime.UtcNow;
+            watches.Add(watch);
+            Save(workspacePath, watches);
+        }
+
+        _logger.LogInformation("Added watch {Id} '{Name}' for {Workspace}", watch.Id, watch.Name, workspacePath);
+        return watch;
+    }
+
+    public bool Remove(string workspacePath, string watchId)
+    {
+        lock (_sync)
+        {
+            var watches = GetWorkspaceWatches(workspacePath);
+            if (watches.RemoveAll(w => string.Equals(w.Id, watchId, StringComparison.OrdinalIgnoreCase)) == 0)
+                return false;
+
+            Save(workspacePath, watches);
+            return true;
+        }
+    }
+
+    public IReadOnlyList<WatchDefinition> List(string workspacePath)
+    {
+        lock (_sync)
+        {
+            return GetWorkspaceWatches(workspacePath).ToList();
+        }
+    }
+
+    public IReadOnlyList<WatchAlert> GetAlerts(string workspacePath, string? watchId = null, long afterSequence = 0)
+    {
+        lock (_sync)
+        {
+            return _alerts
+                .Where(a => string.Equals(a.WorkspacePath, workspacePath, StringComparison.OrdinalIgnoreCase))
+                .Select(a => a.Alert)
+                .Where(a => a.Sequence > afterSequence)
+                .Where(a => watchId == null || string.Equals(a.WatchId, watchId, StringComparison.OrdinalIgnoreCase))
+                .ToList();
+        }
+    }
+
+    public async Task<IReadOnlyList<WatchAlert>> EvaluateAsync(string workspacePath, string filePath, string? previousContent,
+        CancellationToken cancellationToken = default)
+    {
+        var relativePath = Path.GetRelativePath(workspacePath, filePath).Replace('\\', '/');
+        var watches = List(workspacePath)
+            .Where(w => w.FilePattern == null || GlobToRegex(w.FilePattern).IsMatch(relativePath))
+            .ToList();
+        if (watches.Count == 0 || !File.Exists(filePath))
+            return Array.Empty<WatchAlert>();
+
+        var lines = (await File.ReadAllTextAsync(filePath, cancellationToken)).Split('\n');
+        var previousLines = previousContent?.Split('\n') ?? Array.Empty<string>();
+        var raised = new List<WatchAlert>();
+
+        foreach (var watch in watches)
+        {
+            try
+            {
+                raised.AddRange(FindNewMatches(watch, relativePath, lines, previousLines).Take(_maxAlertsPerUpdate));
+            }
+            catch (RegexMatchTimeoutException)
+            {
+                _logger.LogWarning("Watch {Id} pattern timed out on {File}", watch.Id, relativePath);
+            }
+        }
+
+        if (raised.Count == 0)
+            return raised;
+
+        lock (_sync)
+        {
+            foreach (var alert in raised)
+            {
+                alert.Sequence = ++_nextSequence;
+                _alerts.AddLast((workspacePath, alert));
+            }
+            while (_alerts.Count > _maxAlerts)
+                _alerts.RemoveFirst();
+        }
+
+        _logger.LogInformation("{Count} new watch match(es) in {File}", raised.Count, relativePath);
+        Deliver(workspacePath, raised);
+        return raised;
+    }
+
+    private IEnumerable<WatchAlert> FindNewMatches(WatchDefinition watch, string relativePath, string[] lines, string[] previousLines)
+    {
+        var regex = BuildRegex(watch);
+
+        // Matching lines the file already had, by text, so moved or re-indented lines aren't reported as new
+        var known = new Dictionary<string, int>(StringComparer.Ordinal);
+        foreach (var line in previousLines)
+        {
+            if (regex.IsMatch(line))
+            {
+                var key = line.Trim();
+                known[key] = known.GetValueOrDefault(key) + 1;
+            }
+        }
+
+        for (var i = 0; i < lines.Length; i++)
+        {
+            if (!regex.IsMatch(lines[i]))
+                continue;
+
+            var key = lines[i].Trim();
+            if (known.TryGetValue(key, out var count) && count > 0)
+            {
+                known[key] = count - 1;
+                continue;
+            }
+
+            yield return new WatchAlert
+            {
+                WatchId = watch.Id,
+                WatchName = watch.Name,
+                FilePath = relativePath,
+                Line = i + 1,
+                LineText = key
+            };
+        }
+    }
+
+    private void Deliver(string workspacePath, List<WatchAlert> alerts)
+    {
+        foreach (var group in alerts.GroupBy(a => a.WatchId))
+        {
+            var first = group.First();
+            var summary = $"Watch '{first.WatchName}': {group.Count()} new match(es) in {first.FilePath}, first at line {first.Line}";
+
+            if (_notifyClients)
+                NotifyClient(summary, group.ToList());
+
+            _webhooks?.Publish(new WebhookEvent
+            {
+                Event = WebhookEvents.WatchMatched,
+                WorkspacePath = workspacePath,
+                Summary = summary,
+                Data = group.ToList()
+            });
+        }
+    }
+
+    /// <summary>
+    /// Sends an MCP notifications/message to the client over stdout. Best effort - the alerts stay retrievable.
+    /// </summary>
+    private void NotifyClient(string summary, List<WatchAlert> alerts)
+    {
+        try
+        {
+            var output = _output ?? Console.Out;
+            output.WriteLine(JsonSerializer.Serialize(new
+            {
+                jsonrpc = "2.0",
+                method = "notifications/message",
+                @params = new { level = "notice", logger = "codesearch.watch", data = new { message = summary, alerts } }
+            }, new JsonSerializerOptions { PropertyNamingPolicy = JsonNamingPolicy.CamelCase }));
+            output.Flush();
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Could not notify client of watch alerts");
+        }
+    }
+
+    private Regex BuildRegex(WatchDefinition watch)
+    {
+        var key = $"{watch.IsRegex}|{watch.CaseSensitive}|{watch.Pattern}";
+        lock (_regexCache)
+        {
+            if (!_regexCache.TryGetValue(key, out var regex))
+            {
+                regex = new Regex(watch.IsRegex ? watch.Pattern : Regex.Escape(watch.Pattern),
+                    watch.CaseSensitive ? RegexOptions.None : RegexOptions.IgnoreCase | RegexOptions.CultureInvariant, RegexTimeout);
+                _regexCache[key] = regex;
+            }
+            return regex;
+        }
+    }
+
+    // "*.cs" matches file names anywhere; patterns with a slash match the relative path
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    private List<WatchDefinition> GetWorkspaceWatches(string workspacePath)
+    {
+        if (_watches.TryGetValue(workspacePath, out var watches))
+            return watches;
+
+        watches = new List<WatchDefinition>();
+        var path = GetWatchesPath(workspacePath);
+        try
+        {
+            if (File.Exists(path))
+                watches = JsonSerializer.Deserialize<List<WatchDefinition>>(File.ReadAllText(path)) ?? watches;
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not read watches {Path} - starting with none", path);
+        }
+
+        _watches[workspacePath] = watches;
+        return watches;
+    }
+
+    private void Save(string workspacePath, List<WatchDefinition> watches)
+    {
+        var path = GetWatchesPath(workspacePath);
+        try
+        {
+            Directory.CreateDirectory(Path.GetDirectoryName(path)!);
+            File.WriteAllText(path, JsonSerializer.Serialize(watches));
+        }
+        catch (Exception ex)
+        {
+            _logger.LogWarning(ex, "Could not save watches {Path}", path);
+        }
+    }
+
+    private string GetWatchesPath(string workspacePath) =>
+        Path.Combine(_pathResolution.GetIndexPath(workspacePath), WatchesFileName);
+}
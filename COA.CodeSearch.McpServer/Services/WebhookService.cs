@@ -0,0 +1,185 @@
+using System.Security.Cryptography;
+using System.Text;
+using System.Text.Json;
+using System.Threading.Channels;
+using COA.CodeSearch.McpServer.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Hosting;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services;
+
+/// <summary>
+/// A configured webhook endpoint
+/// </summary>
+public class WebhookEndpoint
+{
+    public string Url { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Subscribed events; empty means all events
+    /// </summary>
+    public string[] Events { get; set; } = Array.Empty<string>();
+
+    /// <summary>
+    /// "json" posts the full event; "slack" posts {"text": ...} for Slack/Teams/Mattermost incoming webhooks
+    /// </summary>
+    public string Format { get; set; } = "json";
+
+    /// <summary>
+    /// Environment variable holding the HMAC signing secret. Secrets are never read from configuration files.
+    /// </summary>
+    public string? SecretEnvironmentVariable { get; set; }
+
+    public bool Subscribes(string eventName) =>
+        Events.Length == 0 || Events.Contains(eventName, StringComparer.OrdinalIgnoreCase);
+}
+
+/// <summary>
+/// Background delivery of webhook events. Events are queued in memory and posted with retries;
+/// a slow or failing endpoint never delays indexing or tool responses.
+/// </summary>
+public class WebhookService : BackgroundService, IWebhookService
+{
+    public const string SignatureHeader = "X-CodeSearch-Signature";
+    public const string EventHeader = "X-CodeSearch-Event";
+
+    private static readonly JsonSerializerOptions SerializerOptions = new() { PropertyNamingPolicy = JsonNamingPolicy.CamelCase };
+
+    private readonly ILogger<WebhookService> _logger;
+    private readonly IReadOnlyList<WebhookEndpoint> _endpoints;
+    private readonly Channel<WebhookEvent> _queue;
+    private readonly HttpClient _httpClient;
+    private readonly int _maxAttempts;
+
+    public WebhookService(IConfiguration configuration, ILogger<WebhookService> logger)
+    {
+        _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+
+        var enabled = configuration.GetValue("CodeSearch:Webhooks:Enabled", false);
+        _endpoints = enabled
+            ? (configuration.GetSection("CodeSearch:Webhooks:Endpoints").Get<WebhookEndpoint[]>() ?? Array.Empty<WebhookEndpoint>())
+                .Where(e => Uri.TryCreate(e.Url, UriKind.Absolute, out var uri) && (uri.Scheme == Uri.UriSchemeHttps || uri.Scheme == Uri.UriSchemeHttp))
+                .ToList()
+            : new List<WebhookEndpoint>();
+
+        _maxAttempts = Math.Max(1, configuration.GetValue("CodeSearch:Webhooks:MaxAttempts", 3));
+        _httpClient = new HttpClient
+        {
+            Timeout = TimeSpan.FromSeconds(configuration.GetValue("CodeSearch:Webhooks:TimeoutSeconds", 10))
+        };
+
+        // Oldest events are dropped if endpoints fall far behind
+        _queue = Channel.CreateBounded<WebhookEvent>(new BoundedChannelOptions(configuration.GetValue("CodeSearch:Webhooks:QueueSize", 500))
+        {
+            FullMode = BoundedChannelFullMode.DropOldest,
+            SingleReader = true
+        });
+
+        if (enabled && _endpoints.Count == 0)
+        {
+            _logger.LogWarning("Webhooks are enabled but no valid endpoints are configured");
+        }
+    }
+
+    public bool IsSubscribed(string eventName) => _endpoints.Any(e => e.Subscribes(eventName));
+
+    public void Publish(WebhookEvent webhookEvent)
+    {
+        if (!IsSubscribed(webhookEvent.Event))
+            return;
+
+        _queue.Writer.TryWrite(webhookEvent);
+    }
+
+    protected override async Task ExecuteAsync(CancellationToken stoppingToken)
+    {
+        if (_endpoints.Count == 0)
+            return;
+
+        try
+        {
+            await foreach (var webhookEvent in _queue.Reader.ReadAllAsync(stoppingToken))
+            {
+                foreach (var endpoint in _endpoints.Where(e => e.Subscribes(webhookEvent.Event)))
+                {
+                    await DeliverAsync(endpoint, webhookEvent, stoppingToken);
+                }
+            }
+        }
+        catch (OperationCanceledException) when (stoppingToken.IsCancellationRequested)
+        {
+            // Undelivered events are dropped on shutdown
+        }
+    }
+
+    private async Task DeliverAsync(WebhookEndpoint endpoint, WebhookEvent webhookEvent, CancellationToken cancellationToken)
+    {
+        var body = endpoint.Format.Equals("slack", StringComparison.OrdinalIgnoreCase)
+            ? JsonSerializer.Serialize(new { text = $"[CodeSearch] {webhookEvent.Summary}" }, SerializerOptions)
+            : JsonSerializer.Serialize(webhookEvent, SerializerOptions);
+
+        var secret = string.IsNullOrEmpty(endpoint.SecretEnvironmentVariable)
+            ? null
+            : Environment.GetEnvironmentVariable(endpoint.SecretEnvironmentVariable);
+
+        for (var attempt = 1; attempt <= _maxAttempts; attempt++)
+        {
+            try
+            {
+                using var request = new HttpRequestMessage(HttpMethod.Post, endpoint.Url)
+                {
+                    Content = new StringContent(body, Encoding.UTF8, "application/json")
+                };
+                request.Headers.Add(EventHeader, webhookEvent.Event);
+                if (!string.IsNullOrEmpty(secret))
+                {
+                    request.Headers.Add(SignatureHeader, ComputeSignature(secret, body));
+                }
+
+                using var response = await _httpClient.SendAsync(request, cancellationToken);
+                if (response.IsSuccessStatusCode)
+                {
+                    _logger.LogDebug("Delivered {Event} to {Host}", webhookEvent.Event, new Uri(endpoint.Url).Host);
+                    return;
+                }
+
+                // Client errors won't succeed on retry
+                if ((int)response.StatusCode is >= 400 and < 500 and not 429)
+                {
+                    _logger.LogWarning("Webhook {Host} rejected {Event}: {StatusCode}", new Uri(endpoint.Url).Host, webhookEvent.Event, (int)response.StatusCode);
+                    return;
+                }
+
+                _logger.LogDebug("Webhook {Host} returned {StatusCode} (attempt {Attempt})", new Uri(endpoint.Url).Host, (int)response.StatusCode, attempt);
+            }
+            catch (Exception ex) when (ex is HttpRequestException or TaskCanceledException && !cancellationToken.IsCancellationRequested)
+            {
+                _logger.LogDebug(ex, "Webhook {Host} delivery failed (attempt {Attempt})", new Uri(endpoint.Url).Host, attempt);
+            }
+
+            if (attempt < _maxAttempts)
+            {
+                await Task.Delay(TimeSpan.FromSeconds(Math.Pow(2, attempt)), cancellationToken);
+            }
+        }
+
+        _logger.LogWarning("Giving up delivering {Event} to {Host} after {Attempts} attempts",
+            webhookEvent.Event, new Uri(endpoint.Url).Host, _maxAttempts);
+    }
+
+    /// <summary>
+    /// HMAC-SHA256 of the request body, formatted as "sha256=&lt;hex&gt;" so receivers can verify the sender
+    /// </summary>
+    public static string ComputeSignature(string secret, string body)
+    {
+        var hash = HMACSHA256.HashData(Encoding.UTF8.GetBytes(secret), Encoding.UTF8.GetBytes(body));
+        return "sha256=" + Convert.ToHexString(hash).ToLowerInvariant();
+    }
+
+    public override void Dispose()
+    {
+        _httpClient.Dispose();
+        base.Dispose();
+    }
+}
@@ -0,0 +1,61 @@
+namespace COA.CodeSearch.McpServer.Services.WorkingSet;
+
+/// <summary>
+/// The files and symbols this session has recently viewed or edited. Search ranking boosts them and their
+/// neighbors in the reference graph, so results follow the task at hand rather than global popularity.
+/// Paths are kept absolute; every touch fades with a half-life, so an abandoned task stops steering results.
+/// </summary>
+public interface IWorkingSetService
+{
+    /// <summary>
+    /// False when CodeSearch:WorkingSet:Enabled is off; nothing is recorded or boosted then
+    /// </summary>
+    bool Enabled { get; }
+
+    /// <summary>
+    /// Changes whenever the working set does, so cached rankings can be told apart
+    /// </summary>
+    long Version { get; }
+
+    /// <summary>
+    /// Records a touch of a file. Without a workspace path the workspace is inferred from earlier records;
+    /// neighbors are only looked up for files in a known workspace.
+    /// </summary>
+    void RecordFile(string filePath, WorkingSetActivity activity, string? workspacePath = null);
+
+    /// <summary>
+    /// Records a lookup of a symbol name in a workspace
+    /// </summary>
+    void RecordSymbol(string workspacePath, string name);
+
+    /// <summary>
+    /// Records the files and symbols a tool call names: its WorkspacePath with FilePath, FilePaths, Symbol
+    /// (a name, "path:line:column" or anchor) and SymbolNames arguments
+    /// </summary>
+    void RecordParameters(object parameters);
+
+    /// <summary>
+    /// Boost per absolute file path, 0 to 1, for the workspace's working-set files and their neighbors
+    /// </summary>
+    IReadOnlyDictionary<string, float> GetFileWeights(string workspacePath);
+
+    /// <summary>
+    /// Boost of a symbol name, 0 to 1
+    /// </summary>
+    float GetSymbolWeight(string workspacePath, string name);
+
+    /// <summary>
+    /// Working-set files of a workspace, highest weight first
+    /// </summary>
+    IReadOnlyList<WorkingSetEntry> GetEntries(string workspacePath);
+
+    /// <summary>
+    /// Symbols of a workspace, highest weight first
+    /// </summary>
+    IReadOnlyList<WorkingSetSymbol> GetSymbols(string workspacePath);
+
+    /// <summary>
+    /// Forgets the workspace's working set and returns how many files it held
+    /// </summary>
+    int Clear(string workspacePath);
+}
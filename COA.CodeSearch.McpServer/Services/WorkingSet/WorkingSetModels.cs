@@ -0,0 +1,47 @@
+namespace COA.CodeSearch.McpServer.Services.WorkingSet;
+
+/// <summary>
+/// How the session touched a file; edits count more than a look
+/// </summary>
+public enum WorkingSetActivity
+{
+    Viewed,
+    Opened,
+    Edited
+}
+
+/// <summary>
+/// A file in the session's working set
+/// </summary>
+public class WorkingSetEntry
+{
+    /// <summary>
+    /// Workspace-relative path with forward slashes
+    /// </summary>
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Current boost, 0 to 1: the file's touches, each fading with the configured half-life
+    /// </summary>
+    public float Weight { get; set; }
+
+    public int Views { get; set; }
+    public int Edits { get; set; }
+    public DateTime LastTouched { get; set; }
+
+    /// <summary>
+    /// Files referencing this one's symbols or defining symbols it uses, boosted at a fraction of its weight
+    /// </summary>
+    public List<string> Neighbors { get; set; } = new();
+}
+
+/// <summary>
+/// A symbol name the session asked about
+/// </summary>
+public class WorkingSetSymbol
+{
+    public string Name { get; set; } = string.Empty;
+    public float Weight { get; set; }
+    public int Lookups { get; set; }
+    public DateTime LastTouched { get; set; }
+}
@@ -0,0 +1,358 @@
+using System.Collections.Concurrent;
+using System.Reflection;
+using COA.CodeSearch.McpServer.Services.Anchors;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Services.WorkingSet;
+
+/// <summary>
+/// In-memory working set for the session. Neighbors of a file are looked up once, in the background, from the
+/// symbol database: files using the symbols it defines and files defining the symbols it uses.
+/// </summary>
+public class WorkingSetService : IWorkingSetService
+{
+    private const int MaxTouchesPerFile = 16;
+    private const int MaxNeighbors = 20;
+    private static readonly string[] NeighborKinds = { "class", "interface", "struct", "enum", "type", "function", "method", "trait" };
+
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILogger<WorkingSetService> _logger;
+    private readonly TimeSpan _halfLife;
+    private readonly float _neighborBoost;
+    private readonly int _maxFiles;
+
+    private readonly object _lock = new();
+    private readonly Dictionary<string, FileState> _files = new(StringComparer.OrdinalIgnoreCase);
+    private readonly Dictionary<(string Workspace, string Name), List<DateTime>> _symbols = new();
+    private readonly ConcurrentDictionary<string, byte> _workspaces = new(StringComparer.OrdinalIgnoreCase);
+    private long _version;
+
+    private sealed class FileState
+    {
+        public string? Workspace;
+        public readonly List<(DateTime At, WorkingSetActivity Activity)> Touches = new();
+        public int Views;
+        public int Edits;
+        public List<string>? Neighbors;
+    }
+
+    public WorkingSetService(IConfiguration configuration, ILogger<WorkingSetService> logger, ISQLiteSymbolService? sqliteService = null)
+    {
+        _sqliteService = sqliteService;
+        _logger = logger;
+        Enabled = configuration.GetValue("CodeSearch:WorkingSet:Enabled", true);
+        _halfLife = TimeSpan.FromMinutes(Math.Max(1, configuration.GetValue("CodeSearch:WorkingSet:HalfLifeMinutes", 30)));
+        _neighborBoost = Math.Clamp(configuration.GetValue("CodeSearch:WorkingSet:NeighborBoost", 0.5f), 0f, 1f);
+        _maxFiles = Math.Max(1, configuration.GetValue("CodeSearch:WorkingSet:MaxFiles", 200));
+    }
+
+    public bool Enabled { get; }
+
+    public long Version => Interlocked.Read(ref _version);
+
+    public void RecordFile(string filePath, WorkingSetActivity activity, string? workspacePath = null)
+    {
+        if (!Enabled || string.IsNullOrWhiteSpace(filePath))
+            return;
+
+        var fullPath = Path.GetFullPath(workspacePath != null ? Path.Combine(workspacePath, filePath) : filePath);
+        var workspace = workspacePath != null
+            ? Path.GetFullPath(workspacePath)
+            : _workspaces.Keys.Where(w => IsUnder(fullPath, w)).OrderByDescending(w => w.Length).FirstOrDefault();
+        if (workspace != null && !IsUnder(fullPath, workspace))
+            return;
+
+        bool lookUpNeighbors;
+        lock (_lock)
+        {
+            if (!_files.TryGetValue(fullPath, out var state))
+            {
+                state = new FileState();
+                _files[fullPath] = state;
+                Evict();
+            }
+
+            state.Workspace ??= workspace;
+            state.Touches.Add((DateTime.UtcNow, activity));
+            if (state.Touches.Count > MaxTouchesPerFile)
+                state.Touches.RemoveAt(0);
+            if (activity == WorkingSetActivity.Edited)
+                state.Edits++;
+            else
+                state.Views++;
+
+            lookUpNeighbors = state.Neighbors == null && state.Workspace != null && _sqliteService != null;
+            if (lookUpNeighbors)
+                state.Neighbors = new List<string>();
+        }
+
+        if (workspace != null)
+            _workspaces.TryAdd(workspace, 0);
+        Interlocked.Increment(ref _version);
+
+        if (lookUpNeighbors)
+        {
+            _ = Task.Run(() => FindNeighborsAsync(workspace!, fullPath));
+        }
+    }
+
+    public void RecordSymbol(string workspacePath, string name)
+    {
+        if (!Enabled || string.IsNullOrWhiteSpace(name))
+            return;
+
+        var workspace = Path.GetFullPath(workspacePath);
+        lock (_lock)
+        {
+            var key = (workspace, name.Trim());
+            if (!_symbols.TryGetValue(key, out var touches))
+            {
+                touches = new List<DateTime>();
+                _symbols[key] = touches;
+            }
+            touches.Add(DateTime.UtcNow);
+            if (touches.Count > MaxTouchesPerFile)
+                touches.RemoveAt(0);
+        }
+        _workspaces.TryAdd(workspace, 0);
+        Interlocked.Increment(ref _version);
+    }
+
+    public void RecordParameters(object parameters)
+    {
+        if (!Enabled)
+            return;
+
+        var workspace = Read(parameters, "WorkspacePath") as string;
+        if (string.IsNullOrWhiteSpace(workspace))
+            return;
+
+        if (Read(parameters, "FilePath") is string { Length: > 0 } filePath)
+            RecordFile(filePath, WorkingSetActivity.Viewed, workspace);
+        if (Read(parameters, "FilePaths") is IEnumerable<string> filePaths)
+        {
+            foreach (var path in filePaths.Where(p => !string.IsNullOrWhiteSpace(p)))
+                RecordFile(path, WorkingSetActivity.Viewed, workspace);
+        }
+
+        if (Read(parameters, "Symbol") is string { Length: > 0 } symbol)
+        {
+            if (SymbolAnchor.TryParse(symbol, out var anchor))
+                RecordFile(anchor.FilePath, WorkingSetActivity.Viewed, workspace);
+            else if (SourcePositions.TryParseLocation(symbol, out var location, out _, out _))
+                RecordFile(location, WorkingSetActivity.Viewed, workspace);
+            else
+                RecordSymbol(workspace, symbol);
+        }
+        if (Read(parameters, "SymbolNames") is IEnumerable<string> names)
+        {
+            foreach (var name in names)
+                RecordSymbol(workspace, name);
+        }
+    }
+
+    public IReadOnlyDictionary<string, float> GetFileWeights(string workspacePath)
+    {
+        var workspace = Path.GetFullPath(workspacePath);
+        var now = DateTime.UtcNow;
+        var weights = new Dictionary<string, float>(StringComparer.OrdinalIgnoreCase);
+        lock (_lock)
+        {
+            foreach (var (path, state) in _files.Where(f => IsUnder(f.Key, workspace)))
+            {
+                var weight = Weight(state.Touches, now);
+                weights[path] = Math.Max(weights.GetValueOrDefault(path), weight);
+                foreach (var neighbor in state.Neighbors ?? new List<string>())
+                {
+                    weights[neighbor] = Math.Max(weights.GetValueOrDefault(neighbor), weight * _neighborBoost);
+                }
+            }
+        }
+        return weights.Where(w => w.Value > 0.01f).ToDictionary(w => w.Key, w => w.Value, StringComparer.OrdinalIgnoreCase);
+    }
+
+    public float GetSymbolWeight(string workspacePath, string name)
+    {
+        lock (_lock)
+        {
+            return _symbols.TryGetValue((Path.GetFullPath(workspacePath), name), out var touches)
+                ? Weight(touches.Select(t => (t, WorkingSetActivity.Viewed)), DateTime.UtcNow)
+                : 0f;
+        }
+    }
+
+    public IReadOnlyList<WorkingSetEntry> GetEntries(string workspacePath)
+    {
+        var workspace = Path.GetFullPath(workspacePath);
+        var now = DateTime.UtcNow;
+        lock (_lock)
+        {
+            return _files.Where(f => IsUnder(f.Key, workspace))
+                .Select(f => new WorkingSetEntry
+                {
+                    FilePath = Relative(workspace, f.Key),
+                    Weight = Weight(f.Value.Touches, now),
+                    Views = f.Value.Views,
+                    Edits = f.Value.Edits,
+                    LastTouched = f.Value.Touches[^1].At,
+                    Neighbors = (f.Value.Neighbors ?? new List<string>()).Select(n => Relative(workspace, n)).ToList()
+                })
+                .OrderByDescending(e => e.Weight)
+                .ThenBy(e => e.FilePath, StringComparer.Ordinal)
+                .ToList();
+        }
+    }
+
+    public IReadOnlyList<WorkingSetSymbol> GetSymbols(string workspacePath)
+    {
+        var workspace = Path.GetFullPath(workspacePath);
+        var now = DateTime.UtcNow;
+        lock (_lock)
+        {
+            return _symbols.Where(s => string.Equals(s.Key.Workspace, workspace, StringComparison.OrdinalIgnoreCase))
+                .Select(s => new WorkingSetSymbol
+                {
+                    Name = s.Key.Name,
+                    Weight = Weight(s.Value.Select(t => (t, WorkingSetActivity.Viewed)), now),
+                    Lookups = s.Value.Count,
+                    LastTouched = s.Value[^1]
+                })
+                .OrderByDescending(s => s.Weight)
+                .ThenBy(s => s.Name, StringComparer.Ordinal)
+                .ToList();
+        }
+    }
+
+    public int Clear(string workspacePath)
+    {
+        var workspace = Path.GetFullPath(workspacePath);
+        int removed;
+        lock (_lock)
+        {
+            var files = _files.Keys.Where(f => IsUnder(f, workspace)).ToList();
+            files.ForEach(f => _files.Remove(f));
+            foreach (var key in _symbols.Keys.Where(k => string.Equals(k.Workspace, workspace, StringComparison.OrdinalIgnoreCase)).ToList())
+                _symbols.Remove(key);
+            removed = files.Count;
+        }
+        Interlocked.Increment(ref _version);
+        return removed;
+    }
+
+    /// <summary>
+    /// Sets the neighbors of a working-set file (absolute paths)
+    /// </summary>
+    public void SetNeighbors(string filePath, IEnumerable<string> neighbors)
+    {
+        var fullPath = Path.GetFullPath(filePath);
+        lock (_lock)
+        {
+            if (_files.TryGetValue(fullPath, out var state))
+            {
+                state.Neighbors = neighbors.Select(Path.GetFullPath)
+                    .Where(n => !string.Equals(n, fullPath, StringComparison.OrdinalIgnoreCase))
+                    .Distinct(StringComparer.OrdinalIgnoreCase)
+                    .Take(MaxNeighbors)
+                    .ToList();
+            }
+        }
+        Interlocked.Increment(ref _version);
+    }
+
+    /// <summary>
+    /// Files sharing the most symbol links with the file, in either direction
+    /// </summary>
+    private async Task FindNeighborsAsync(string workspacePath, string fullPath)
+    {
+        try
+        {
+            var links = new Dictionary<string, int>(StringComparer.OrdinalIgnoreCase);
+            void Link(string path)
+            {
+                var neighbor = Path.GetFullPath(Path.Combine(workspacePath, path));
+                if (!string.Equals(neighbor, fullPath, StringComparison.OrdinalIgnoreCase))
+                    links[neighbor] = links.GetValueOrDefault(neighbor) + 1;
+            }
+
+            // Who uses what this file defines
+            var defined = (await _sqliteService!.GetSymbolsForFileAsync(workspacePath, fullPath))
+                .Where(s => NeighborKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase))
+                .Select(s => s.Name)
+                .Distinct(StringComparer.Ordinal)
+                .Take(20);
+            foreach (var name in defined)
+            {
+                foreach (var identifier in (await _sqliteService.GetIdentifiersByNameAsync(workspacePath, name)).Take(200))
+                    Link(identifier.FilePath);
+            }
+
+            // What defines the names this file uses most
+            var used = (await _sqliteService.GetIdentifiersForFileAsync(workspacePath, fullPath))
+                .GroupBy(i => i.Name, StringComparer.Ordinal)
+                .OrderByDescending(g => g.Count())
+                .Select(g => g.Key)
+                .Take(30);
+            foreach (var name in used)
+            {
+                var definitions = await _sqliteService.GetSymbolsByNameAsync(workspacePath, name);
+                if (definitions.Count is > 0 and <= 3)
+                {
+                    foreach (var definition in definitions)
+                        Link(definition.FilePath);
+                }
+            }
+
+            SetNeighbors(fullPath, links.OrderByDescending(l => l.Value).ThenBy(l => l.Key, StringComparer.Ordinal).Select(l => l.Key));
+            _logger.LogDebug("Working set: {Count} neighbor(s) for {FilePath}", links.Count, fullPath);
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Could not look up working-set neighbors of {FilePath}", fullPath);
+        }
+    }
+
+    /// <summary>
+    /// Sum of the touches' activity weights, each halved per half-life since it happened, capped at 1
+    /// </summary>
+    private float Weight(IEnumerable<(DateTime At, WorkingSetActivity Activity)> touches, DateTime now)
+    {
+        var weight = 0.0;
+        foreach (var (at, activity) in touches)
+        {
+            var baseWeight = activity switch
+            {
+                WorkingSetActivity.Edited => 1.0,
+                WorkingSetActivity.Opened => 0.8,
+                _ => 0.5
+            };
+            weight += baseWeight * Math.Pow(0.5, Math.Max(0, (now - at).TotalMinutes) / _halfLife.TotalMinutes);
+        }
+        return (float)Math.Min(1.0, weight);
+    }
+
+    /// <summary>
+    /// Drops the files touched longest ago once the set outgrows its limit; caller holds the lock
+    /// </summary>
+    private void Evict()
+    {
+        while (_files.Count > _maxFiles)
+        {
+            var oldest = _files.MinBy(f => f.Value.Touches.Count > 0 ? f.Value.Touches[^1].At : DateTime.MinValue).Key;
+            _files.Remove(oldest);
+        }
+    }
+
+    private static object? Read(object parameters, string name)
+    {
+        var property = parameters.GetType().GetProperty(name, BindingFlags.Public | BindingFlags.Instance);
+        return property?.CanRead == true ? property.GetValue(parameters) : null;
+    }
+
+    private static bool IsUnder(string path, string directory) =>
+        path.StartsWith(Path.TrimEndingDirectorySeparator(directory) + Path.DirectorySeparatorChar, StringComparison.OrdinalIgnoreCase);
+
+    private static string Relative(string workspacePath, string path) => Path.GetRelativePath(workspacePath, path).Replace('\\', '/');
+}
@@ -232,7 +232,7 @@ public class WorkspacePermissionService : IWorkspacePermissionService
         
         // Find the workspace that contains this file (longest path match)
         return metadata.AllowedWorkspaces
-            .Where(w => w.IsActive && filePath.StartsWith(w.Path, StringComparison.OrdinalIgnoreCase))
+            .Where(w => w.IsActive && PlatformPaths.IsUnder(filePath, w.Path))
             .OrderByDescending(w => w.Path.Length) // Longest path wins
             .FirstOrDefault();
     }
@@ -0,0 +1,208 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Cross-checks the HTTP routes servers in the workspace declare against the URLs its clients call and the
+/// OpenAPI specs it carries, reporting calls to routes that don't exist, method and parameter drift, and
+/// routes nobody calls or documents
+/// </summary>
+public class ApiContractsTool : CodeSearchToolBase<ApiContractsParameters, AIOptimizedResponse<ApiContractsResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<ApiContractsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the ApiContractsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service the source files and specs are read from</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public ApiContractsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<ApiContractsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.ApiContracts;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DO CLIENT AND SERVER AGREE? Reads HTTP routes (ASP.NET, minimal APIs, NestJS, Express, Go net/http/gorilla/gin/echo/chi, " +
+        "Flask, FastAPI, Django), client calls to URL literals (fetch, axios, HttpClient, http.NewRequest, requests, generated clients) " +
+        "and OpenAPI/Swagger specs, then reports calls to routes that don't exist, wrong HTTP methods, parameter drift, " +
+        "spec operations nobody implements, undocumented routes and routes no client calls.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads routes, calls and specs from every indexed file and compares them.
+    /// </summary>
+    /// <param name="parameters">Finding kinds, report filter and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Contract findings with the routes, calls or operations they concern</returns>
+    protected override async Task<AIOptimizedResponse<ApiContractsResult>> ExecuteInternalAsync(
+        ApiContractsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var kinds = parameters.Kinds?.Where(k => !string.IsNullOrWhiteSpace(k)).Select(k => k.Trim().ToLowerInvariant()).ToList() ?? new List<string>();
+        var unknown = kinds.Where(k => !ApiContracts.Kinds.Contains(k)).ToList();
+        if (unknown.Count > 0)
+        {
+            return CreateErrorResponse("INVALID_KIND", $"Unknown finding kind(s): {string.Join(", ", unknown)}",
+                $"Use any of: {string.Join(", ", ApiContracts.Kinds)}");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try api_contracts again");
+        }
+
+        var routes = new List<ApiEndpoint>();
+        var spec = new List<ApiEndpoint>();
+        var calls = new List<ApiCall>();
+        var result = new ApiContractsResult();
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var source = ApiContracts.IsSourceFile(file.Path);
+            var extension = Path.GetExtension(file.Path).ToLowerInvariant();
+            if (!source && extension is not (".json" or ".yaml" or ".yml"))
+                continue;
+
+            var fullPath = Path.IsPathRooted(file.Path) ? file.Path : Path.Combine(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            var relative = Relative(workspacePath, file.Path);
+            if (source)
+            {
+                result.FilesScanned++;
+                var lines = content.Replace("\r\n", "\n").Split('\n');
+                routes.AddRange(ApiContracts.ScanServer(relative, lines));
+                calls.AddRange(ApiContracts.ScanClient(relative, lines));
+            }
+            else if (ApiContracts.IsSpecCandidate(file.Path, content))
+            {
+                result.FilesScanned++;
+                var operations = ApiContracts.ParseOpenApi(relative, content);
+                if (operations.Count > 0)
+                {
+                    spec.AddRange(operations);
+                    result.Specs.Add(relative);
+                }
+            }
+        }
+
+        var filter = string.IsNullOrWhiteSpace(parameters.FilePattern) ? null : GlobToRegex(parameters.FilePattern);
+        var findings = ApiContracts.Compare(routes, spec, calls)
+            .Where(f => kinds.Count == 0 || kinds.Contains(f.Kind))
+            .Where(f => filter == null || filter.IsMatch(f.FilePath))
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        result.Findings = findings.Take(maxResults).ToList();
+        result.Truncated = findings.Count > maxResults;
+        result.Counts = findings.GroupBy(f => f.Kind).ToDictionary(g => g.Key, g => g.Count());
+        result.Routes = routes.Count;
+        result.Calls = calls.Count;
+        result.ExternalCalls = calls.Count(ApiContracts.IsExternal);
+        result.SpecOperations = spec.Count;
+
+        _logger.LogDebug("api_contracts: {Routes} routes, {Calls} calls, {Operations} spec operations, {Findings} findings",
+            routes.Count, calls.Count, spec.Count, findings.Count);
+
+        var response = new AIOptimizedResponse<ApiContractsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<ApiContractsResult> { Results = result },
+            Message = $"{routes.Count} route(s), {calls.Count - result.ExternalCalls} internal call(s), {spec.Count} spec operation(s): " +
+                      (findings.Count > 0
+                          ? string.Join(", ", ApiContracts.Kinds.Where(result.Counts.ContainsKey).Select(k => $"{result.Counts[k]} {k}"))
+                          : "no mismatches")
+        };
+
+        var insights = new List<string>();
+        if (routes.Count == 0)
+        {
+            insights.Add(spec.Count > 0
+                ? "No server routes were recognized, so calls were checked against the spec only"
+                : "No server routes or specs were recognized - routes registered through helpers or reflection are not read");
+        }
+        if (routes.Count > 0 && calls.Count == result.ExternalCalls)
+        {
+            insights.Add("No client calls to the workspace's own API were found, so uncalled routes are not reported");
+        }
+        if (result.Counts.GetValueOrDefault("uncalled") > 0)
+        {
+            insights.Add("Uncalled routes may be used by clients outside this workspace or by URLs built at runtime - treat them as leads, not dead code");
+        }
+        if (result.Counts.GetValueOrDefault("missing") > 0)
+        {
+            insights.Add("A missing call can also mean its route is mounted under a prefix set in another file (app.use, include_router, global prefixes)");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped at {maxResults} findings - narrow with kinds or filePattern");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    // "*.go" matches file names anywhere; patterns with a slash match the relative path
+    private static Regex GlobToRegex(string glob)
+    {
+        var normalized = glob.Replace('\\', '/');
+        var pattern = Regex.Escape(normalized)
+            .Replace(@"\*\*/", "(.*/)?")
+            .Replace(@"\*\*", ".*")
+            .Replace(@"\*", "[^/]*")
+            .Replace(@"\?", "[^/]");
+        return new Regex(normalized.Contains('/') ? $"^{pattern}$" : $"(^|/){pattern}$", RegexOptions.IgnoreCase);
+    }
+
+    private static AIOptimizedResponse<ApiContractsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,243 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Plugins;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Embeddings;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Elicitation;
+using COA.CodeSearch.McpServer.Services.Sampling;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.DependencyInjection;
+using Microsoft.Extensions.Logging;
+using Microsoft.Extensions.Options;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Reports supported languages, analyzers, transport features and limits so clients can avoid unsupported calls
+/// </summary>
+public class CapabilitiesTool : CodeSearchToolBase<CapabilitiesParameters, AIOptimizedResponse<CapabilitiesResult>>
+{
+    private const string ServerVersion = "2.1.8";
+    private static readonly string[] PromptNames = { "code-explorer", "bug-hunter", "refactoring-assistant" };
+
+    private readonly IServiceProvider _serviceProvider;
+    private readonly IConfiguration _configuration;
+    private readonly MemoryLimitsConfiguration _memoryLimits;
+    private readonly ILogger<CapabilitiesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CapabilitiesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider used to probe optional engines</param>
+    /// <param name="configuration">Configuration for feature flags and limits</param>
+    /// <param name="memoryLimits">Memory and result limits</param>
+    /// <param name="logger">Logger instance</param>
+    public CapabilitiesTool(
+        IServiceProvider serviceProvider,
+        IConfiguration configuration,
+        IOptions<MemoryLimitsConfiguration> memoryLimits,
+        ILogger<CapabilitiesTool> logger) : base(serviceProvider, logger)
+    {
+        _serviceProvider = serviceProvider;
+        _configuration = configuration;
+        _memoryLimits = memoryLimits.Value;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.Capabilities;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DISCOVER SUPPORT - Languages with symbol/reference/rename support levels, active analyzers, transport features and limits. " +
+        "Call once per session before relying on symbol tools for an unfamiliar language.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Executes the capabilities query.
+    /// </summary>
+    /// <param name="parameters">Optional language filter</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The server's capabilities</returns>
+    protected override Task<AIOptimizedResponse<CapabilitiesResult>> ExecuteInternalAsync(
+        CapabilitiesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        IEnumerable<LanguageCapability> languages = LanguageCapabilities.All;
+        if (!string.IsNullOrWhiteSpace(parameters.Language))
+        {
+            var match = LanguageCapabilities.Find(parameters.Language);
+            if (match == null)
+            {
+                return Task.FromResult(new AIOptimizedResponse<CapabilitiesResult>
+                {
+                    Success = false,
+                    Error = new ErrorInfo
+                    {
+                        Code = "UNSUPPORTED_LANGUAGE",
+                        Message = $"No symbol extraction for '{parameters.Language}'. text_search, line_search and search_files still work for any text file.",
+                        Recovery = new RecoveryInfo
+                        {
+                            Steps = new[]
+                            {
+                                "Call capabilities without a language to list supported languages",
+                                "Use text_search or line_search instead of symbol tools for this language"
+                            }
+                        }
+                    }
+                });
+            }
+
+            languages = new[] { match };
+        }
+
+        var julieAvailable = _serviceProvider.GetService<IJulieCodeSearchService>()?.IsAvailable() ?? false;
+        var embeddingsAvailable = _serviceProvider.GetService<IEmbeddingService>()?.IsAvailable() ?? false;
+        var vecAvailable = _serviceProvider.GetService<ISqliteVecExtensionService>()?.IsAvailable() ?? false;
+        var encryptionEnabled = _serviceProvider.GetService<IIndexEncryptionService>()?.IsEnabled ?? false;
+
+        var result = new CapabilitiesResult
+        {
+            ServerVersion = ServerVersion,
+            // Without julie-codesearch there is no symbol extraction, only full-text search
+            Languages = languages.Select(l => new LanguageCapabilityInfo
+            {
+                Name = l.Name,
+                Extensions = l.Extensions.ToList(),
+                Symbols = FormatLevel(julieAvailable ? l.Symbols : FeatureLevel.None),
+                References = FormatLevel(julieAvailable ? l.References : FeatureLevel.None),
+                Rename = FormatLevel(julieAvailable ? l.Rename : FeatureLevel.None),
+                Notes = l.Notes
+            }).ToList(),
+            Analyzers = new List<AnalyzerInfo>
+            {
+                new() { Name = "code", Enabled = true, Description = "Lucene code analyzer - case-insensitive, splits camelCase and snake_case identifiers" },
+                new() { Name = "julie-codesearch", Enabled = julieAvailable, Description = "Tree-sitter symbol and identifier extraction" },
+                new() { Name = "embeddings", Enabled = embeddingsAvailable, Description = "ONNX embedding model for semantic search" },
+                new() { Name = "sqlite-vec", Enabled = vecAvailable, Description = "Vector similarity search over symbol embeddings" },
+                new() { Name = "encryption", Enabled = encryptionEnabled, Description = "Encryption at rest for indexes and symbol databases" },
+                new() { Name = "file-watcher", Enabled = true, Description = "Incremental re-indexing of changed files" }
+            },
+            Transport = new TransportCapabilities
+            {
+                PromptNames = PromptNames.ToList(),
+                LogNotifications = _configuration.GetValue("CodeSearch:Shutdown:NotifyClients", true),
+                Sampling = _serviceProvider.GetService<ISamplingClient>()?.IsAvailable ?? false,
+                Elicitation = _serviceProvider.GetService<IElicitationClient>()?.IsAvailable ?? false
+            },
+            Limits = new CapabilityLimits
+            {
+                MaxFileSizeBytes = _memoryLimits.MaxFileSize,
+                MaxResults = _memoryLimits.MaxAllowedResults,
+                MaxIndexingConcurrency = _memoryLimits.MaxIndexingConcurrency,
+                MaxActiveIndexes = _memoryLimits.MaxActiveIndexes,
+                MaxConcurrentIndexes = _configuration.GetValue("CodeSearch:Lucene:MaxConcurrentIndexes", 10),
+                MaxBatchSize = _memoryLimits.MaxBatchSize,
+                ShutdownTimeoutSeconds = _configuration.GetValue("CodeSearch:Shutdown:TimeoutSeconds", 10)
+            },
+            Tools = GetToolNames(),
+            SchemaVersions = new Dictionary<string, string>(ToolSchemaVersions.Versioned),
+            Plugins = (_serviceProvider.GetService<PluginCatalog>()?.Plugins ?? Array.Empty<LoadedPlugin>())
+                .Select(p => new PluginInfo { Name = p.Name, Version = p.Version, Loaded = p.Loaded, Error = p.Error })
+                .ToList()
+        };
+
+        foreach (var analyzer in _serviceProvider.GetServices<IPatternAnalyzerPlugin>())
+        {
+            result.Analyzers.Add(new AnalyzerInfo { Name = analyzer.Name, Enabled = true, Description = "Plugin analyzer run by find_patterns" });
+        }
+        foreach (var extractor in _serviceProvider.GetServices<ISymbolExtractorPlugin>())
+        {
+            result.Analyzers.Add(new AnalyzerInfo
+            {
+                Name = extractor.Name,
+                Enabled = true,
+                Description = $"Plugin symbol extractor for {string.Join(", ", extractor.Extensions)}"
+            });
+        }
+
+        var insights = new List<string>();
+        if (!julieAvailable)
+        {
+            insights.Add("julie-codesearch is not available - symbol, reference and rename tools are disabled; use text_search and line_search");
+        }
+        if (!embeddingsAvailable || !vecAvailable)
+        {
+            insights.Add("Semantic search is unavailable - searches are lexical only");
+        }
+        foreach (var failed in result.Plugins.Where(p => !p.Loaded))
+        {
+            insights.Add($"Plugin {failed.Name} failed to load: {failed.Error}");
+        }
+
+        var response = new AIOptimizedResponse<CapabilitiesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CapabilitiesResult> { Results = result },
+            Message = $"{result.Languages.Count} language(s), {result.Analyzers.Count(a => a.Enabled)}/{result.Analyzers.Count} analyzers enabled, {result.Tools.Count} tools"
+        };
+
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        _logger.LogDebug("Reported capabilities (julie: {Julie}, embeddings: {Embeddings})", julieAvailable, embeddingsAvailable);
+        return Task.FromResult(response);
+    }
+
+    private static string FormatLevel(FeatureLevel level) => level.ToString().ToLowerInvariant();
+
+    /// <summary>
+    /// Lists registered tools by resolving every CodeSearch tool type from the container,
+    /// so legacy names in <see cref="ToolNames"/> that no longer have a tool are not reported
+    /// </summary>
+    private List<string> GetToolNames()
+    {
+        var names = new List<string>();
+        var toolTypes = typeof(CapabilitiesTool).Assembly.GetTypes()
+            .Where(t => t.IsClass && !t.IsAbstract && IsCodeSearchTool(t));
+
+        foreach (var type in toolTypes)
+        {
+            try
+            {
+                var tool = type == typeof(CapabilitiesTool) ? this : _serviceProvider.GetService(type);
+                if (tool?.GetType().GetProperty(nameof(Name))?.GetValue(tool) is string name)
+                {
+                    names.Add(name);
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogDebug(ex, "Could not resolve tool {ToolType}", type.Name);
+            }
+        }
+
+        return names.OrderBy(n => n, StringComparer.Ordinal).ToList();
+    }
+
+    private static bool IsCodeSearchTool(Type type)
+    {
+        for (var current = type.BaseType; current != null; current = current.BaseType)
+        {
+            if (current.IsGenericType && current.GetGenericTypeDefinition() == typeof(CodeSearchToolBase<,>))
+                return true;
+        }
+
+        return false;
+    }
+}
@@ -0,0 +1,217 @@
+using System.Text.Json;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Checks declared architecture layering rules against the imports and references of the indexed files
+/// </summary>
+public class CheckArchitectureTool : CodeSearchToolBase<CheckArchitectureParameters, AIOptimizedResponse<CheckArchitectureResult>>
+{
+    private const string RulesFileName = ".codesearch-architecture.json";
+
+    private static readonly JsonSerializerOptions RulesOptions = new()
+    {
+        PropertyNameCaseInsensitive = true,
+        ReadCommentHandling = JsonCommentHandling.Skip,
+        AllowTrailingCommas = true
+    };
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly IConfiguration _configuration;
+    private readonly IDependencyCodeService? _dependencyCode;
+    private readonly ILogger<CheckArchitectureTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CheckArchitectureTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="configuration">Configuration holding rules for workspaces without a rules file</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="dependencyCode">Vendored and third-party directories left out of the check</param>
+    public CheckArchitectureTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        IConfiguration configuration,
+        ILogger<CheckArchitectureTool> logger,
+        IDependencyCodeService? dependencyCode = null) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _configuration = configuration;
+        _dependencyCode = dependencyCode;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CheckArchitecture;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DOES THE CODE KEEP ITS LAYERS? Checks layering rules declared in .codesearch-architecture.json (or CodeSearch:Architecture " +
+        "configuration) - e.g. domain must not depend on infrastructure, nothing outside persistence references SQL - against every " +
+        "file's imports and referenced names. Reports each violation with the offending line and the file it reaches.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Loads the rules, assigns indexed files to layers and checks their imports and references.
+    /// </summary>
+    /// <param name="parameters">Rule filter and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Violations of the workspace's layering rules</returns>
+    protected override async Task<AIOptimizedResponse<CheckArchitectureResult>> ExecuteInternalAsync(
+        CheckArchitectureParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try check_architecture again");
+        }
+
+        ArchitectureSettings? settings;
+        string source;
+        var rulesFile = Path.Combine(workspacePath, RulesFileName);
+        if (File.Exists(rulesFile))
+        {
+            source = RulesFileName;
+            try
+            {
+                settings = JsonSerializer.Deserialize<ArchitectureSettings>(await File.ReadAllTextAsync(rulesFile, cancellationToken), RulesOptions);
+            }
+            catch (JsonException ex)
+            {
+                return CreateErrorResponse("INVALID_ARCHITECTURE_RULES", $"{RulesFileName} is not valid JSON: {ex.Message}",
+                    $"Fix {RulesFileName} at line {ex.LineNumber + 1}", "Try check_architecture again");
+            }
+        }
+        else
+        {
+            source = "CodeSearch:Architecture";
+            settings = _configuration.GetSection("CodeSearch:Architecture").Get<ArchitectureSettings>();
+        }
+
+        if (settings == null || settings.Rules.Count == 0)
+        {
+            return CreateErrorResponse("NO_ARCHITECTURE_RULES", "No layering rules are declared for this workspace",
+                $"Add {RulesFileName} to the workspace root with \"layers\" (name, paths, namespaces) and \"rules\" (layer, mustNotDependOn, mayOnlyDependOn, mustNotReference)",
+                "Or declare them under CodeSearch:Architecture in configuration");
+        }
+
+        var problems = ArchitectureLayers.Validate(settings);
+        if (problems.Count > 0)
+        {
+            return CreateErrorResponse("INVALID_ARCHITECTURE_RULES", $"{source}: {string.Join("; ", problems)}",
+                $"Fix the rules in {source}", "Try check_architecture again");
+        }
+
+        if (!string.IsNullOrWhiteSpace(parameters.Rule))
+        {
+            settings.Rules = settings.Rules.Where(r => r.DisplayName.Contains(parameters.Rule, StringComparison.OrdinalIgnoreCase)).ToList();
+            if (settings.Rules.Count == 0)
+            {
+                return CreateErrorResponse("RULE_NOT_FOUND", $"No rule in {source} matches '{parameters.Rule}'",
+                    "Omit rule to check every rule", "Try check_architecture again");
+            }
+        }
+
+        var files = new List<(string Path, string? Content)>();
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!parameters.IncludeDependencyCode && _dependencyCode?.GetDependencyDirectory(workspacePath, file.Path) != null)
+                continue;
+            files.Add((Relative(workspacePath, file.Path), file.Content));
+        }
+
+        var layers = ArchitectureLayers.AssignLayers(settings, files);
+        var violations = ArchitectureLayers.Check(settings, files, layers, ImportGraph.Build(files));
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        var result = new CheckArchitectureResult
+        {
+            WorkspacePath = workspacePath,
+            Source = source,
+            LayerFiles = settings.Layers.ToDictionary(l => l.Name, l => layers.Values.Count(v => v.Equals(l.Name, StringComparison.OrdinalIgnoreCase))),
+            RuleViolations = settings.Rules.ToDictionary(r => r.DisplayName, r => violations.Count(v => v.Rule == r.DisplayName)),
+            Violations = violations.Take(maxResults).ToList(),
+            TotalViolations = violations.Count,
+            FilesChecked = files.Count,
+            UnassignedFiles = files.Count - layers.Count,
+            Truncated = violations.Count > maxResults
+        };
+
+        _logger.LogDebug("check_architecture: {Rules} rules from {Source}, {Files} files, {Violations} violations",
+            settings.Rules.Count, source, files.Count, violations.Count);
+
+        var response = new AIOptimizedResponse<CheckArchitectureResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CheckArchitectureResult> { Results = result },
+            Message = violations.Count == 0
+                ? $"No violations of {settings.Rules.Count} rule(s) in {files.Count} file(s)"
+                : $"{violations.Count} violation(s) of {result.RuleViolations.Count(r => r.Value > 0)} rule(s) in {violations.Select(v => v.FilePath).Distinct().Count()} file(s)"
+        };
+
+        var insights = new List<string>();
+        foreach (var empty in result.LayerFiles.Where(l => l.Value == 0))
+        {
+            insights.Add($"Layer '{empty.Key}' matched no files - check its paths and namespaces, or its rules check nothing");
+        }
+        var worst = violations.GroupBy(v => v.FilePath).OrderByDescending(g => g.Count()).FirstOrDefault();
+        if (worst != null && worst.Count() > 1)
+        {
+            insights.Add($"{worst.Key} has {worst.Count()} violations - the place to start");
+        }
+        if (result.UnassignedFiles > 0 && settings.Rules.Any(r => r.Layer != "*"))
+        {
+            insights.Add($"{result.UnassignedFiles} file(s) are in no layer; only rules for \"*\" apply to them");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {violations.Count} violations - raise maxResults or filter by rule");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CheckArchitectureResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
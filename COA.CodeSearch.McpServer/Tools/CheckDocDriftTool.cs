@@ -0,0 +1,166 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Checks the doc comments of the indexed symbols against the signatures and bodies they document
+/// </summary>
+public class CheckDocDriftTool : CodeSearchToolBase<CheckDocDriftParameters, AIOptimizedResponse<CheckDocDriftResult>>
+{
+    private static readonly string[] Kinds = { "parameter", "reference", "name", "value" };
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<CheckDocDriftTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CheckDocDriftTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed symbols and files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public CheckDocDriftTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<CheckDocDriftTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CheckDocDrift;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "ARE THE COMMENTS STILL TRUE? Finds doc comments that drifted from their code: documented parameters the signature " +
+        "no longer has (<param>, @param, :param, Args: sections), cref/link/code-span names nothing declares or uses any more, " +
+        "comments still opening with a symbol's old name (\"Helper ...\" above HelperHandler), and stated defaults or literals " +
+        "the signature and body no longer contain. Each finding names the stale text and, where there is one, what it should say.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads the indexed symbols and files and checks the doc comments.
+    /// </summary>
+    /// <param name="parameters">Kind, language and path filters and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Stale mentions in doc comments, with suggested replacements</returns>
+    protected override async Task<AIOptimizedResponse<CheckDocDriftResult>> ExecuteInternalAsync(
+        CheckDocDriftParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try check_doc_drift again");
+        }
+
+        if (!string.IsNullOrWhiteSpace(parameters.Kind) && !Kinds.Contains(parameters.Kind, StringComparer.OrdinalIgnoreCase))
+        {
+            return CreateErrorResponse("INVALID_KIND", $"Unknown drift kind '{parameters.Kind}'",
+                $"Use one of: {string.Join(", ", Kinds)}", "Or leave kind out to report every kind");
+        }
+
+        var symbols = await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken);
+        var declared = symbols.Select(s => s.Name).ToHashSet(StringComparer.Ordinal);
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var documented = symbols
+            .Where(s => !string.IsNullOrWhiteSpace(s.DocComment))
+            .Where(s => string.IsNullOrEmpty(parameters.Language) || s.Language.Equals(parameters.Language, StringComparison.OrdinalIgnoreCase))
+            .Where(s => string.IsNullOrEmpty(scope) || Relative(workspacePath, s.FilePath).StartsWith(scope + "/", StringComparison.Ordinal))
+            .ToList();
+
+        var paths = documented.Select(s => Relative(workspacePath, s.FilePath)).ToHashSet(StringComparer.Ordinal);
+        var contents = new Dictionary<string, string>(StringComparer.Ordinal);
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var path = Relative(workspacePath, file.Path);
+            if (paths.Contains(path) && !string.IsNullOrEmpty(file.Content))
+                contents[path] = file.Content;
+        }
+
+        var findings = DocDrift.Check(workspacePath, documented, declared, contents)
+            .Where(f => string.IsNullOrEmpty(parameters.Kind) || f.Kind.Equals(parameters.Kind, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        var result = new CheckDocDriftResult
+        {
+            WorkspacePath = workspacePath,
+            Findings = findings.Take(maxResults).ToList(),
+            ByKind = findings.GroupBy(f => f.Kind).ToDictionary(g => g.Key, g => g.Count()),
+            TotalFindings = findings.Count,
+            SymbolsChecked = documented.Count,
+            Truncated = findings.Count > maxResults
+        };
+
+        _logger.LogDebug("check_doc_drift: {Symbols} documented symbols, {Findings} findings", documented.Count, findings.Count);
+
+        var response = new AIOptimizedResponse<CheckDocDriftResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CheckDocDriftResult> { Results = result },
+            Message = findings.Count == 0
+                ? $"The doc comments of {documented.Count} symbol(s) match their code"
+                : $"{findings.Count} stale mention(s) in the doc comments of {findings.Select(f => (f.FilePath, f.Symbol)).Distinct().Count()} symbol(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.ByKind.TryGetValue("name", out var names))
+        {
+            insights.Add($"{names} comment(s) still open with an old name - usually left behind by a rename; rename_symbol does not rewrite prose");
+        }
+        if (result.ByKind.TryGetValue("parameter", out var stale) && findings.Any(f => f.Kind == "parameter" && f.Suggestion != null))
+        {
+            insights.Add($"{stale} documented parameter(s) no longer exist - where a suggestion is given, one parameter is undocumented and was likely renamed from it");
+        }
+        if (documented.Count == 0)
+        {
+            insights.Add("No indexed symbol in scope has a doc comment - the extractor may not capture comments for this language");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {findings.Count} findings - raise maxResults or filter by kind, language or path");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CheckDocDriftResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,150 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Checks namespace and package declarations in the indexed files against their directories
+/// </summary>
+public class CheckNamespacesTool : CodeSearchToolBase<CheckNamespacesParameters, AIOptimizedResponse<CheckNamespacesResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly NamespaceConventionSettings _settings;
+    private readonly ILogger<CheckNamespacesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CheckNamespacesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="configuration">Configuration for root namespaces, source roots and ignored files</param>
+    /// <param name="logger">Logger instance</param>
+    public CheckNamespacesTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        IConfiguration configuration,
+        ILogger<CheckNamespacesTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _settings = configuration.GetSection("CodeSearch:NamespaceConventions").Get<NamespaceConventionSettings>() ?? new NamespaceConventionSettings();
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CheckNamespaces;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DO NAMESPACES MATCH FOLDERS? Checks namespace and package declarations against the directory structure: C# namespaces " +
+        "follow the folder path under the project's root namespace, a Go package is named after its directory, Java/Kotlin/Scala " +
+        "packages follow the path under their source root. Fix them with smart_refactor operation fix_namespaces.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads the indexed files and checks their declarations.
+    /// </summary>
+    /// <param name="parameters">Language and path filters and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Declarations not matching their directories</returns>
+    protected override async Task<AIOptimizedResponse<CheckNamespacesResult>> ExecuteInternalAsync(
+        CheckNamespacesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try check_namespaces again");
+        }
+
+        var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Select(f => (Path: Relative(workspacePath, f.Path), f.Content))
+            .ToList();
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var violations = NamespaceConventions.Check(files, _settings)
+            .Where(v => string.IsNullOrEmpty(parameters.Language) || v.Language.Equals(parameters.Language, StringComparison.OrdinalIgnoreCase))
+            .Where(v => string.IsNullOrEmpty(scope) || v.FilePath.StartsWith(scope + "/", StringComparison.Ordinal))
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        var result = new CheckNamespacesResult
+        {
+            WorkspacePath = workspacePath,
+            Violations = violations.Take(maxResults).ToList(),
+            ByLanguage = violations.GroupBy(v => v.Language).ToDictionary(g => g.Key, g => g.Count()),
+            TotalViolations = violations.Count,
+            FilesChecked = files.Count(f => Path.GetExtension(f.Path).ToLowerInvariant() is ".cs" or ".go" or ".java" or ".kt" or ".kts" or ".scala"),
+            Truncated = violations.Count > maxResults
+        };
+
+        _logger.LogDebug("check_namespaces: {Files} files, {Violations} violations", result.FilesChecked, violations.Count);
+
+        var response = new AIOptimizedResponse<CheckNamespacesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CheckNamespacesResult> { Results = result },
+            Message = violations.Count == 0
+                ? $"All namespace and package declarations in {result.FilesChecked} file(s) match their directories"
+                : $"{violations.Count} declaration(s) do not match their directories"
+        };
+
+        var insights = new List<string>();
+        if (violations.Count > 0)
+        {
+            insights.Add("Preview the fix with smart_refactor operation fix_namespaces (dry run by default) - it also moves the using directives, imports and qualified names that refer to the declarations");
+        }
+        var common = violations.GroupBy(v => (v.Declared, v.Expected)).OrderByDescending(g => g.Count()).FirstOrDefault();
+        if (common != null && common.Count() > 2)
+        {
+            insights.Add($"{common.Count()} files declare {common.Key.Declared} where {common.Key.Expected} is expected - a folder may have been renamed or moved");
+        }
+        if (files.Any(f => f.Path.EndsWith(".cs", StringComparison.OrdinalIgnoreCase)) && !files.Any(f => f.Path.EndsWith(".csproj", StringComparison.OrdinalIgnoreCase))
+            && _settings.RootNamespaces.Count == 0)
+        {
+            insights.Add("No .csproj is indexed and no CodeSearch:NamespaceConventions:RootNamespaces are configured, so C# files were not checked");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {violations.Count} violations - raise maxResults or filter by language or path");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CheckNamespacesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,183 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Checks the names of the indexed symbols against built-in and configured naming rules
+/// </summary>
+public class CheckNamingTool : CodeSearchToolBase<CheckNamingParameters, AIOptimizedResponse<CheckNamingResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly NamingConventionSettings _settings;
+    private readonly ILogger<CheckNamingTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CheckNamingTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed symbols and files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="configuration">Configuration for naming rules and ignored files</param>
+    /// <param name="logger">Logger instance</param>
+    public CheckNamingTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        IConfiguration configuration,
+        ILogger<CheckNamingTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _settings = configuration.GetSection("CodeSearch:NamingConventions").Get<NamingConventionSettings>() ?? new NamingConventionSettings();
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CheckNaming;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DO NAMES FOLLOW THE CONVENTIONS? Audits symbol names against naming rules: exported Go identifiers are PascalCase " +
+        "without underscores, C# interfaces start with I, and optionally tests are named Test_Subject_Scenario - plus rules " +
+        "configured under CodeSearch:NamingConventions (language, kind, visibility, style, prefix, suffix, pattern). " +
+        "Each violation comes with a suggested name where one can be derived; rename them in batch with smart_refactor operation fix_naming.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads the indexed symbols and checks their names.
+    /// </summary>
+    /// <param name="parameters">Rule, language and path filters and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Names breaking the rules, with suggested renames</returns>
+    protected override async Task<AIOptimizedResponse<CheckNamingResult>> ExecuteInternalAsync(
+        CheckNamingParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try check_naming again");
+        }
+
+        var problems = NamingConventions.Validate(_settings);
+        if (problems.Count > 0)
+        {
+            return CreateErrorResponse("INVALID_NAMING_RULES", string.Join("; ", problems),
+                "Fix the rules under CodeSearch:NamingConventions:Rules", "Try check_naming again");
+        }
+
+        var rules = NamingConventions.RulesFor(_settings);
+        if (!string.IsNullOrWhiteSpace(parameters.Rule))
+        {
+            rules = rules.Where(r => r.Name.Equals(parameters.Rule, StringComparison.OrdinalIgnoreCase)).ToList();
+            if (rules.Count == 0)
+            {
+                var known = NamingConventions.RulesFor(_settings).Select(r => r.Name);
+                return CreateErrorResponse("RULE_NOT_FOUND", $"No enabled naming rule is named '{parameters.Rule}'",
+                    $"Enabled rules: {string.Join(", ", known)}",
+                    "Built-in rules that are off by default are switched on with CodeSearch:NamingConventions:Enable");
+            }
+        }
+        if (rules.Count == 0)
+        {
+            return CreateErrorResponse("NO_NAMING_RULES", "Every naming rule is disabled",
+                "Add rules under CodeSearch:NamingConventions:Rules or remove built-ins from CodeSearch:NamingConventions:Disable");
+        }
+
+        var tests = new HashSet<(string FilePath, string Name)>();
+        if (rules.Any(r => r.Tests != null))
+        {
+            var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+                .Select(f => (Path: Relative(workspacePath, f.Path), f.Content));
+            tests = NamingConventions.TestsIn(files);
+        }
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var symbols = (await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken))
+            .Where(s => string.IsNullOrEmpty(parameters.Language) || s.Language.Equals(parameters.Language, StringComparison.OrdinalIgnoreCase))
+            .Where(s => string.IsNullOrEmpty(scope) || Relative(workspacePath, s.FilePath).StartsWith(scope + "/", StringComparison.Ordinal))
+            .ToList();
+        var violations = NamingConventions.Check(workspacePath, symbols, rules, tests, _settings.Ignore);
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        var result = new CheckNamingResult
+        {
+            WorkspacePath = workspacePath,
+            Rules = rules.Select(r => r.Name).ToList(),
+            Violations = violations.Take(maxResults).ToList(),
+            ByRule = violations.GroupBy(v => v.Rule).ToDictionary(g => g.Key, g => g.Count()),
+            TotalViolations = violations.Count,
+            Fixable = violations.Count(v => v.Suggested != null),
+            SymbolsChecked = symbols.Count,
+            Truncated = violations.Count > maxResults
+        };
+
+        _logger.LogDebug("check_naming: {Symbols} symbols, {Rules} rules, {Violations} violations", symbols.Count, rules.Count, violations.Count);
+
+        var response = new AIOptimizedResponse<CheckNamingResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CheckNamingResult> { Results = result },
+            Message = violations.Count == 0
+                ? $"All {symbols.Count} symbol names follow the {rules.Count} naming rule(s)"
+                : $"{violations.Count} name(s) break the naming rules, {result.Fixable} with a suggested rename"
+        };
+
+        var insights = new List<string>();
+        if (result.Fixable > 0)
+        {
+            insights.Add("Preview the renames with smart_refactor operation fix_naming (dry run by default) - each renames the declaration and its references");
+        }
+        if (violations.Count > result.Fixable)
+        {
+            insights.Add($"{violations.Count - result.Fixable} name(s) have no mechanical fix (pattern rules) - rename them with smart_refactor rename_symbol");
+        }
+        var noisy = result.ByRule.OrderByDescending(r => r.Value).FirstOrDefault();
+        if (noisy.Value > 50 && noisy.Value > violations.Count / 2)
+        {
+            insights.Add($"Most violations come from {noisy.Key} - if the codebase follows a different convention, list it under CodeSearch:NamingConventions:Disable or replace it with a configured rule of the same name");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {violations.Count} violations - raise maxResults or filter by rule, language or path");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CheckNamingResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
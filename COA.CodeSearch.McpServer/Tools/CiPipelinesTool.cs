@@ -0,0 +1,273 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Reads the workspace's GitHub Actions workflows and Azure Pipelines files and checks the scripts, directories,
+/// projects, templates and local actions their steps name against the workspace, and their variables against
+/// the code that reads them
+/// </summary>
+public class CiPipelinesTool : CodeSearchToolBase<CiPipelinesParameters, AIOptimizedResponse<CiPipelinesResult>>
+{
+    private static readonly HashSet<string> CheckedKinds = new(StringComparer.Ordinal) { "script", "directory", "project", "template", "action", "workflow" };
+    private static readonly HashSet<string> ProseExtensions = new(StringComparer.OrdinalIgnoreCase) { ".md", ".mdx", ".rst", ".txt", ".adoc", ".tex" };
+    private const int MaxReadsPerVariable = 20;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<CiPipelinesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CiPipelinesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public CiPipelinesTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<CiPipelinesTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CiPipelines;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT DOES CI RUN, AND IS IT STILL THERE? Reads GitHub Actions workflows and Azure Pipelines files and checks " +
+        "every script, working directory, project, template and local action their steps name against the workspace - " +
+        "flagging steps that point at files that were moved or deleted. Also joins pipeline env vars with the code that " +
+        "reads them. Use before moving scripts or projects, and when a pipeline fails with 'file not found'.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Parses every indexed pipeline file and the templates they include, then resolves their references.
+    /// </summary>
+    /// <param name="parameters">Pipeline filter, problem filter and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Pipelines with their missing references and variables</returns>
+    protected override async Task<AIOptimizedResponse<CiPipelinesResult>> ExecuteInternalAsync(
+        CiPipelinesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try ci_pipelines again");
+        }
+
+        var files = new Dictionary<string, FileRecord>(StringComparer.Ordinal);
+        var directories = new HashSet<string>(StringComparer.Ordinal);
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var relative = Relative(workspacePath, file.Path);
+            files[relative] = file;
+            for (var directory = Path.GetDirectoryName(relative)?.Replace('\\', '/'); !string.IsNullOrEmpty(directory); directory = Path.GetDirectoryName(directory)?.Replace('\\', '/'))
+            {
+                if (!directories.Add(directory))
+                    break;
+            }
+        }
+
+        // Pipelines first, then the Azure templates they include, wherever those live
+        var result = new CiPipelinesResult();
+        var pipelines = new List<CiPipeline>();
+        var templates = new HashSet<string>(StringComparer.Ordinal);
+        var pending = new Queue<string>(files.Keys.Where(CiPipelines.IsPipelineFile).OrderBy(p => p, StringComparer.Ordinal));
+        var parsed = new HashSet<string>(pending, StringComparer.Ordinal);
+        while (pending.Count > 0)
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var relative = pending.Dequeue();
+            var content = await ReadAsync(workspacePath, relative, files[relative], cancellationToken);
+            if (content == null || CiPipelines.Parse(relative, content) is not { } pipeline)
+                continue;
+
+            result.FilesScanned++;
+            pipelines.Add(pipeline);
+            foreach (var template in pipeline.References.Where(r => r.Kind == "template" && r.Path != null))
+            {
+                if (files.ContainsKey(template.Path!) && parsed.Add(template.Path!))
+                {
+                    templates.Add(template.Path!);
+                    pending.Enqueue(template.Path!);
+                }
+            }
+        }
+
+        var filter = parameters.Pipeline?.Trim();
+        var selected = pipelines
+            .Where(p => string.IsNullOrEmpty(filter)
+                        || p.FilePath.Contains(filter, StringComparison.OrdinalIgnoreCase)
+                        || p.Name.Contains(filter, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+
+        var references = selected.SelectMany(p => p.References).ToList();
+        foreach (var reference in references)
+            reference.Exists = Exists(workspacePath, reference.Path, files, directories);
+        var missing = references.Where(r => r.Exists == false && CheckedKinds.Contains(r.Kind)).ToList();
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var listed = parameters.ProblemsOnly ? references.Where(r => r.Exists == false).ToList() : references;
+        result.Missing = missing.Take(limit).ToList();
+        result.References = listed.Take(limit).ToList();
+        result.Truncated = listed.Count > limit || missing.Count > limit;
+        result.Pipelines = selected.Select(p => new CiPipelineSummary
+        {
+            FilePath = p.FilePath,
+            System = p.System,
+            Name = p.Name,
+            Jobs = p.Steps.Select(s => s.Job).Distinct(StringComparer.Ordinal).ToList(),
+            Steps = p.Steps.Count,
+            References = p.References.Count,
+            Missing = p.References.Count(r => r.Exists == false && CheckedKinds.Contains(r.Kind)),
+            Template = templates.Contains(p.FilePath)
+        }).ToList();
+        result.Secrets = selected.SelectMany(p => p.EnvUses)
+            .Where(u => u.Kind is "secret" or "setting")
+            .GroupBy(u => (u.Kind == "secret" ? "secrets." : "vars.") + u.Value, StringComparer.Ordinal)
+            .OrderBy(g => g.Key, StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => g.Select(u => (u.FilePath, u.Step)).Distinct().Count());
+
+        var variables = new List<CiEnvironmentVariable>();
+        if (parameters.IncludeVariables && selected.Count > 0)
+        {
+            var codeReads = new Dictionary<string, List<string>>(StringComparer.Ordinal);
+            foreach (var (relative, file) in files)
+            {
+                if (parsed.Contains(relative) || ProseExtensions.Contains(Path.GetExtension(relative)))
+                    continue;
+                cancellationToken.ThrowIfCancellationRequested();
+                var content = await ReadAsync(workspacePath, relative, file, cancellationToken);
+                if (content == null)
+                    continue;
+                foreach (var (name, line) in CiPipelines.ReadEnvironment(relative, content.Replace("\r\n", "\n").Split('\n')))
+                {
+                    if (!codeReads.TryGetValue(name, out var reads))
+                        codeReads[name] = reads = new List<string>();
+                    if (reads.Count < MaxReadsPerVariable)
+                        reads.Add($"{relative}:{line}");
+                }
+            }
+            variables = CiPipelines.LinkEnvironment(selected, codeReads)
+                .Where(v => !parameters.ProblemsOnly || v.Status != "read")
+                .ToList();
+            result.Variables = variables.Take(limit).ToList();
+            result.Truncated |= variables.Count > limit;
+        }
+
+        var unread = variables.Count(v => v.Status == "unread");
+        var undefined = variables.Count(v => v.Status == "undefined");
+        _logger.LogDebug("ci_pipelines: {Pipelines} pipelines, {References} references, {Missing} missing, {Variables} variables",
+            selected.Count, references.Count, missing.Count, variables.Count);
+
+        var response = new AIOptimizedResponse<CiPipelinesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CiPipelinesResult> { Results = result },
+            Message = selected.Count == 0
+                ? "No GitHub Actions or Azure Pipelines definitions found"
+                : $"{selected.Count} pipeline(s), {selected.Sum(p => p.Steps.Count)} step(s): {missing.Count} missing path(s), " +
+                  $"{unread} unread and {undefined} undefined variable(s)"
+        };
+
+        var insights = new List<string>();
+        if (pipelines.Count == 0)
+        {
+            insights.Add("No .github/workflows/*.yml, .github/**/action.yml or azure-pipelines*.yml file is indexed");
+        }
+        if (missing.Count > 0)
+        {
+            var first = missing[0];
+            insights.Add($"'{first.Value}' in step '{first.Step}' ({first.FilePath}:{first.Line}) is not in the workspace - " +
+                         "detect_renames can tell whether it was moved");
+        }
+        var notFound = references.Count(r => r.Exists == false && !CheckedKinds.Contains(r.Kind));
+        if (notFound > 0)
+        {
+            insights.Add($"{notFound} other path(s) mentioned in scripts were not found - they may be build outputs or downloaded files");
+        }
+        var unresolved = references.Count(r => r.Exists == null);
+        if (unresolved > 0)
+        {
+            insights.Add($"{unresolved} reference(s) are built from variables or point outside the workspace and were not checked");
+        }
+        if (unread > 0)
+        {
+            insights.Add("Unread variables may still configure the tools a step runs (DOTNET_*, NODE_OPTIONS) - check before removing them");
+        }
+        if (selected.Any(p => p.VariableGroups.Count > 0))
+        {
+            insights.Add($"Variable groups ({string.Join(", ", selected.SelectMany(p => p.VariableGroups).Distinct())}) are defined in Azure DevOps - $(name) reads are not reported as undefined");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Results limited to {limit} - narrow with pipeline or problemsOnly");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static bool? Exists(string workspacePath, string? path, Dictionary<string, FileRecord> files, HashSet<string> directories)
+    {
+        if (path == null || path == ".." || path.StartsWith("../", StringComparison.Ordinal))
+            return null;
+        if (path.Length == 0)
+            return true;
+        if (path.Contains('*') || path.Contains('?'))
+        {
+            var glob = new Regex("^" + Regex.Escape(path).Replace(@"\*\*/", "(?:.*/)?").Replace(@"\*\*", ".*").Replace(@"\*", "[^/]*").Replace(@"\?", "[^/]") + "$");
+            return files.Keys.Any(glob.IsMatch) || directories.Any(glob.IsMatch);
+        }
+        // Files the index skips - executables without an extension, binaries - are checked on disk
+        var fullPath = Path.Combine(workspacePath, path);
+        return files.ContainsKey(path) || directories.Contains(path) || File.Exists(fullPath) || Directory.Exists(fullPath);
+    }
+
+    private static async Task<string?> ReadAsync(string workspacePath, string relative, FileRecord file, CancellationToken cancellationToken)
+    {
+        var fullPath = Path.Combine(workspacePath, relative);
+        return file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CiPipelinesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
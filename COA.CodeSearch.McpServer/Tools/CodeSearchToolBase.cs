@@ -1,7 +1,21 @@
 using System;
 using System.ComponentModel.DataAnnotations;
+using System.Text;
+using COA.Mcp.Framework;
 using COA.Mcp.Framework.Base;
+using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Anchors;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Editor;
+using COA.CodeSearch.McpServer.Services.Elicitation;
+using COA.CodeSearch.McpServer.Services.GoBuild;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Logging;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
+using COA.CodeSearch.McpServer.Tools.Models;
 using Microsoft.Extensions.DependencyInjection;
 using Microsoft.Extensions.Logging;
 
@@ -16,6 +30,16 @@ public abstract class CodeSearchToolBase<TParams, TResult> : McpToolBase<TParams
     where TParams : class
 {
     private readonly IParameterDefaultsService? _parameterDefaults;
+    private readonly IParameterMigrationService? _parameterMigration;
+    private readonly IElicitationClient? _elicitation;
+    private readonly IIndexRetentionService? _indexRetention;
+    private readonly ISQLiteSymbolService? _symbolDatabase;
+    private readonly ISymbolAnchorService? _anchors;
+    private readonly FileWatcherService? _fileWatcher;
+    private readonly IPrefetchService? _prefetch;
+    private readonly IEditorContextService? _editorContext;
+    private readonly IWorkingSetService? _workingSet;
+    private readonly ILogger? _logger;
 
     /// <summary>
     /// Initializes a new instance of the CodeSearchToolBase class
@@ -27,8 +51,33 @@ public abstract class CodeSearchToolBase<TParams, TResult> : McpToolBase<TParams
     {
         // Try to resolve parameter defaults service (graceful degradation if not available)
         _parameterDefaults = serviceProvider?.GetService<IParameterDefaultsService>();
+        _parameterMigration = serviceProvider?.GetService<IParameterMigrationService>();
+        _elicitation = serviceProvider?.GetService<IElicitationClient>();
+        _indexRetention = serviceProvider?.GetService<IIndexRetentionService>();
+        _symbolDatabase = serviceProvider?.GetService<ISQLiteSymbolService>();
+        _anchors = serviceProvider?.GetService<ISymbolAnchorService>();
+        _fileWatcher = serviceProvider?.GetService<FileWatcherService>();
+        _prefetch = serviceProvider?.GetService<IPrefetchService>();
+        _editorContext = serviceProvider?.GetService<IEditorContextService>();
+        _workingSet = serviceProvider?.GetService<IWorkingSetService>();
+        _logger = logger;
     }
 
+    /// <summary>
+    /// Current input/output schema version of this tool
+    /// </summary>
+    public string SchemaVersion => ToolSchemaVersions.GetVersion(Name);
+
+    /// <summary>
+    /// Deprecated parameters the client used in the current invocation
+    /// </summary>
+    protected IReadOnlyList<DeprecationNotice> DeprecationNotices => ToolCallNotes.Deprecations;
+
+    /// <summary>
+    /// Arguments the current invocation took from the editor context because the call left them empty
+    /// </summary>
+    protected IReadOnlyList<EditorContextUse> EditorContextUses => ToolCallNotes.EditorContextUses;
+
     /// <summary>
     /// CodeSearch tools use Data Annotations validation with custom error handling
     /// </summary>
@@ -41,6 +90,27 @@ public abstract class CodeSearchToolBase<TParams, TResult> : McpToolBase<TParams
     /// <param name="parameters">The parameters to validate</param>
     protected override void ValidateParameters(TParams parameters)
     {
+        // Validation is the first step of every invocation - start the request's correlation scope here
+        // so all logs written while serving it (including validation failures) share one ID, and its
+        // resource counters so everything the invocation reads is counted
+        var correlationId = CorrelationContext.Begin(Name);
+        QueryUsage.Begin();
+        _logger?.LogDebug("Tool {ToolName} invoked (correlation {CorrelationId})", Name, correlationId);
+
+        // Accept older parameter names before defaults and validation see the parameters
+        ToolCallNotes.Deprecations = parameters != null && _parameterMigration != null
+            ? _parameterMigration.Migrate(Name, parameters)
+            : Array.Empty<DeprecationNotice>();
+
+        // Fill what the call left empty from the editor (find references to "this") before defaults and validation
+        ToolCallNotes.EditorContextUses = parameters != null && _editorContext != null
+            ? _editorContext.Apply(Name, parameters)
+            : Array.Empty<EditorContextUse>();
+        if (EditorContextUses.Count > 0)
+        {
+            _logger?.LogDebug("{ToolName} took {Arguments} from the editor context", Name, string.Join(", ", EditorContextUses));
+        }
+
         // Apply parameter defaults if available
         if (parameters != null)
         {
@@ -55,6 +125,12 @@ public abstract class CodeSearchToolBase<TParams, TResult> : McpToolBase<TParams
             }
         }
 
+        // Files and symbols a search or analysis names join the session's working set, steering later rankings
+        if (parameters != null && _workingSet is { Enabled: true } && Category is ToolCategory.Query or ToolCategory.Analysis)
+        {
+            _workingSet.RecordParameters(parameters);
+        }
+
         // Call base validation but catch and simplify validation errors for test compatibility
         try
         {
@@ -80,4 +156,258 @@ public abstract class CodeSearchToolBase<TParams, TResult> : McpToolBase<TParams
             throw;
         }
     }
-}
\ No newline at end of file
+
+    /// <summary>
+    /// Asks the user which candidate they meant through MCP elicitation. Returns null when the client can't be
+    /// asked, there are too many candidates for one question, or the user declined - callers then fall back
+    /// to their usual pick.
+    /// </summary>
+    protected async Task<T?> AskUserToChooseAsync<T>(
+        string message,
+        string title,
+        IReadOnlyList<T> candidates,
+        Func<T, string> describe,
+        CancellationToken cancellationToken) where T : class
+    {
+        if (_elicitation is not { IsAvailable: true } || candidates.Count < 2 || candidates.Count > _elicitation.MaxOptions)
+            return null;
+
+        try
+        {
+            var answer = await _elicitation.ChooseAsync(new ElicitationRequest
+            {
+                Message = message,
+                Title = title,
+                Options = candidates
+                    .Select((c, i) => new ElicitationOption { Value = (i + 1).ToString(), Label = describe(c) })
+                    .ToList()
+            }, cancellationToken);
+
+            return answer.Accepted ? candidates[int.Parse(answer.Value!) - 1] : null;
+        }
+        catch (ElicitationException ex)
+        {
+            _logger?.LogDebug(ex, "Could not ask the user to disambiguate for {ToolName}", Name);
+            return null;
+        }
+    }
+
+    /// <summary>
+    /// Resolves a workspacePath argument. Paths are used as given; a bare folder name such as "api" is matched
+    /// against the indexed workspaces, and when several share that name the user is asked which one was meant.
+    /// </summary>
+    protected async Task<string> ResolveWorkspacePathAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        var fullPath = Path.GetFullPath(workspacePath);
+        var isBareName = workspacePath.IndexOfAny(new[] { '/', '\\', ':' }) < 0 && !workspacePath.StartsWith('.');
+        if (!isBareName || Directory.Exists(fullPath) || _indexRetention == null)
+            return fullPath;
+
+        var matches = _indexRetention.GetWorkspaceIndexes()
+            .Select(i => i.WorkspacePath)
+            .OfType<string>()
+            .Where(p => string.Equals(Path.GetFileName(Path.TrimEndingDirectorySeparator(p)), workspacePath, StringComparison.OrdinalIgnoreCase))
+            .Distinct(StringComparer.OrdinalIgnoreCase)
+            .ToList();
+
+        if (matches.Count == 1)
+            return matches[0];
+
+        return await AskUserToChooseAsync(
+            $"Several indexed workspaces are named '{workspacePath}'. Which one should {Name} use?",
+            "Workspace",
+            matches,
+            p => p,
+            cancellationToken) ?? fullPath;
+    }
+
+    /// <summary>
+    /// Indexes edits the file watcher is still holding back for this workspace (see
+    /// <see cref="FileWatcherService.IndexPendingChangesAsync"/>), so a query made right after an edit sees it.
+    /// A query that sets <paramref name="skipPendingEdits"/> answers from the index as it is instead.
+    /// </summary>
+    protected async Task EnsurePendingChangesIndexedAsync(string workspacePath, bool skipPendingEdits, CancellationToken cancellationToken)
+    {
+        if (_fileWatcher == null || skipPendingEdits)
+            return;
+
+        var indexed = await _fileWatcher.IndexPendingChangesAsync(workspacePath, cancellationToken);
+        if (indexed > 0)
+        {
+            _logger?.LogDebug("{ToolName} indexed {Count} recently edited file(s) before querying", Name, indexed);
+        }
+    }
+
+    /// <summary>
+    /// Tells the file watcher this tool wrote a file, so the session's next query indexes it first
+    /// </summary>
+    protected void RecordWrite(string filePath) => _fileWatcher?.RecordWrite(filePath);
+
+    /// <summary>
+    /// Queues the follow-up queries an agent usually makes after finding a symbol - its references and the
+    /// outline of the file defining it - so they are already cached when asked for. Pass the workspacePath
+    /// argument as the caller gave it: the prefetched responses are cached under the same key only if a later
+    /// call passes it the same way.
+    /// </summary>
+    protected void PrefetchSymbol(string? workspacePathArgument, string resolvedWorkspacePath, string symbolName, string? definitionFile)
+    {
+        // Bare workspace names may have been resolved by asking the user; prefetch must never ask again
+        var workspacePath = workspacePathArgument == null ? null : resolvedWorkspacePath;
+        _prefetch?.PrefetchSymbol(workspacePath, symbolName, definitionFile);
+    }
+
+    /// <summary>
+    /// Column converter for locations in this workspace. File text comes from the symbol database, which holds
+    /// exactly what the byte offsets were computed from, and from disk for files it doesn't have.
+    /// </summary>
+    protected SourcePositions CreateSourcePositions(string workspacePath)
+    {
+        return new SourcePositions(async (filePath, cancellationToken) =>
+        {
+            if (_symbolDatabase != null)
+            {
+                var record = await _symbolDatabase.GetFileByPathAsync(workspacePath, filePath, cancellationToken);
+                if (record?.Content is { Length: > 0 } content)
+                    return content;
+            }
+
+            var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+            if (!File.Exists(fullPath))
+                return null;
+
+            var text = await File.ReadAllTextAsync(fullPath, cancellationToken);
+            QueryUsage.RecordBytes(Encoding.UTF8.GetByteCount(text));
+            return text;
+        });
+    }
+
+    /// <summary>
+    /// Resolves a symbol argument given as "path:line:column" to the identifier at that position, with the column
+    /// counted in the given encoding, or as a symbol anchor to the name the anchored symbol has now.
+    /// Plain names are returned without type arguments; null when nothing identifier-like is there.
+    /// </summary>
+    protected async Task<string?> ResolveSymbolArgumentAsync(string symbol, string workspacePath, string columnEncoding,
+        CancellationToken cancellationToken)
+    {
+        var anchored = await ResolveAnchorArgumentAsync(symbol, workspacePath, cancellationToken);
+        if (anchored != null)
+            return anchored.Symbol?.Name;
+
+        // A generic written with type arguments (Stack[int]) resolves to its name
+        if (!SourcePositions.TryParseLocation(symbol, out var filePath, out var line, out var column))
+            return GoGenerics.StripTypeArguments(symbol);
+
+        return await CreateSourcePositions(workspacePath)
+            .GetIdentifierAtAsync(filePath, line, column, columnEncoding, cancellationToken);
+    }
+
+    /// <summary>
+    /// Re-resolves a symbol argument written as an anchor ("anchor:path#Type.Member~fingerprint");
+    /// null when the argument is not an anchor. A missing symbol comes back with status missing.
+    /// </summary>
+    protected async Task<AnchorResolution?> ResolveAnchorArgumentAsync(string symbol, string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        if (!SymbolAnchor.TryParse(symbol, out var anchor))
+            return null;
+
+        if (_anchors == null)
+            return new AnchorResolution { Status = AnchorStatus.Missing };
+
+        var resolution = await _anchors.ResolveAsync(workspacePath, anchor, cancellationToken);
+        if (resolution.Status != AnchorStatus.Exact)
+        {
+            _logger?.LogInformation("Anchor {Anchor} resolved as {Status} to {Current}", symbol, resolution.Status, resolution.CurrentAnchor);
+        }
+        return resolution;
+    }
+
+    /// <summary>
+    /// Anchor text for a symbol from the symbol database, to return alongside its location; null when unavailable
+    /// </summary>
+    protected async Task<string?> CreateAnchorAsync(string workspacePath, JulieSymbol symbol, CancellationToken cancellationToken)
+    {
+        if (_anchors == null)
+            return null;
+
+        var anchor = await _anchors.CreateAnchorAsync(workspacePath, symbol, cancellationToken);
+        return anchor?.ToString();
+    }
+
+    /// <summary>
+    /// Fills <see cref="SymbolDefinition.Utf16Column"/> from the byte columns the symbol database returned
+    /// </summary>
+    protected static async Task AddUtf16ColumnsAsync(SourcePositions positions, IEnumerable<SymbolDefinition> definitions,
+        CancellationToken cancellationToken)
+    {
+        foreach (var definition in definitions)
+        {
+            definition.Utf16Column = await positions.ToUtf16ColumnAsync(
+                definition.FilePath, definition.Line, definition.Column, cancellationToken);
+        }
+    }
+
+    /// <summary>
+    /// Fills the build constraint of Go definitions from their files, and whether it admits the workspace's
+    /// build profile
+    /// </summary>
+    protected static async Task AddBuildConstraintsAsync(IGoBuildProfileService? goBuildProfiles, string workspacePath,
+        IEnumerable<SymbolDefinition> definitions, CancellationToken cancellationToken)
+    {
+        if (goBuildProfiles == null)
+            return;
+
+        var profile = goBuildProfiles.GetProfile(workspacePath);
+        foreach (var definition in definitions)
+        {
+            definition.BuildConstraint = await goBuildProfiles.GetConstraintAsync(workspacePath, definition.FilePath, cancellationToken);
+            definition.InBuild = definition.BuildConstraint != null ? GoBuildConstraints.Matches(definition.BuildConstraint, profile) : null;
+        }
+    }
+
+    /// <summary>
+    /// Flags definitions in vendored and third-party directories as dependency code
+    /// </summary>
+    protected static void AddDependencyCode(IDependencyCodeService? dependencyCode, string workspacePath,
+        IEnumerable<SymbolDefinition> definitions)
+    {
+        if (dependencyCode == null)
+            return;
+
+        foreach (var definition in definitions)
+        {
+            definition.DependencyCode = dependencyCode.GetDependencyDirectory(workspacePath, definition.FilePath) != null ? true : null;
+        }
+    }
+
+    /// <summary>
+    /// Fills type parameters and constraint type sets of Go definitions from their declarations, and the
+    /// type parameter list their signature lost
+    /// </summary>
+    protected static async Task AddGoGenericsAsync(SourcePositions positions, IEnumerable<SymbolDefinition> definitions,
+        CancellationToken cancellationToken)
+    {
+        foreach (var definition in definitions)
+        {
+            if (!definition.FilePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+                continue;
+
+            // Enough lines for a type parameter list or a constraint interface's body
+            var lines = new List<string>();
+            for (var line = definition.Line; lines.Count < 20; line++)
+            {
+                var text = await positions.GetLineAsync(definition.FilePath, line, cancellationToken);
+                if (text == null)
+                    break;
+                lines.Add(text);
+            }
+            if (lines.Count == 0)
+                continue;
+
+            var generics = GoGenerics.Describe(string.Join("\n", lines), definition.Name, definition.Kind.ToLowerInvariant(), definition.Signature);
+            definition.TypeParameters = generics.TypeParameters;
+            definition.TypeSet = generics.TypeSet;
+            definition.Signature = generics.Signature;
+        }
+    }
+}
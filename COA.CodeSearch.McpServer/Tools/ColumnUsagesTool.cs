@@ -0,0 +1,187 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Orm;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Code reading or writing a database column - through the ORM member mapped to it and in SQL text naming it -
+/// so "which code writes to is_active" has a direct answer
+/// </summary>
+public class ColumnUsagesTool : CodeSearchToolBase<ColumnUsagesParameters, AIOptimizedResponse<ColumnUsagesResult>>
+{
+    private static readonly string[] AccessModes = { "all", "write", "read" };
+
+    private readonly IOrmSchemaService _ormSchemaService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<ColumnUsagesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the ColumnUsagesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="ormSchemaService">ORM entity catalog and column usage search</param>
+    /// <param name="sqliteService">SQLite symbol service for the index check</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public ColumnUsagesTool(
+        IServiceProvider serviceProvider,
+        IOrmSchemaService ormSchemaService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<ColumnUsagesTool> logger) : base(serviceProvider, logger)
+    {
+        _ormSchemaService = ormSchemaService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.ColumnUsages;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHO WRITES THIS COLUMN? Finds the ORM fields and properties mapped to a database column (gorm/db tags, EF Core, " +
+        "SQLAlchemy, Django) and every line touching it - assignments and reads of the mapped member, and SQL strings " +
+        "naming the column - each classified as write or read, writes first. Qualify with the table ('users.is_active') when several tables share a column name.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Resolves the column's mappings and collects the code touching it.
+    /// </summary>
+    /// <param name="parameters">Column, access filter, file pattern and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Mappings and usages of the column</returns>
+    protected override async Task<AIOptimizedResponse<ColumnUsagesResult>> ExecuteInternalAsync(
+        ColumnUsagesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var column = parameters.Column?.Trim();
+        if (string.IsNullOrEmpty(column))
+        {
+            return CreateErrorResponse("MISSING_COLUMN", "Give the column to look for",
+                "Example: column 'is_active'", "Example: column 'users.is_active' to pick one table");
+        }
+
+        var access = string.IsNullOrWhiteSpace(parameters.Access) ? "all" : parameters.Access.Trim().ToLowerInvariant();
+        if (!AccessModes.Contains(access))
+        {
+            return CreateErrorResponse("INVALID_ACCESS", $"Unknown access '{parameters.Access}'",
+                "Use 'write', 'read' or 'all'");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try column_usages again");
+        }
+
+        var report = await _ormSchemaService.FindColumnUsagesAsync(workspacePath, column, access, parameters.FilePattern,
+            Math.Clamp(parameters.MaxResults, 1, 2000), cancellationToken);
+
+        if (report.Mappings.Count == 0 && report.Usages.Count == 0)
+        {
+            return CreateErrorResponse("COLUMN_NOT_FOUND", $"No ORM mapping or SQL mentions column '{column}'",
+                "List the known tables and columns with orm_entities",
+                "Check the spelling - columns are matched by their database name, e.g. 'is_active' rather than 'IsActive'");
+        }
+
+        // Mapped columns are shared with the service's cached catalog, so they are copied rather than edited
+        var mappings = report.Mappings.Select(m => new OrmEntity
+        {
+            Name = m.Name,
+            Table = m.Table,
+            Framework = m.Framework,
+            TableSource = m.TableSource,
+            FilePath = Relative(workspacePath, m.FilePath),
+            Line = m.Line,
+            Columns = m.Columns.Select(c => new OrmColumn
+            {
+                Member = c.Member,
+                Column = c.Column,
+                Explicit = c.Explicit,
+                PrimaryKey = c.PrimaryKey,
+                FilePath = Relative(workspacePath, c.FilePath),
+                Line = c.Line
+            }).ToList()
+        }).ToList();
+        foreach (var usage in report.Usages)
+            usage.FilePath = Relative(workspacePath, usage.FilePath);
+
+        var result = new ColumnUsagesResult
+        {
+            Mappings = mappings,
+            Usages = report.Usages,
+            Writes = report.Usages.Count(u => u.Access == "write"),
+            Reads = report.Usages.Count(u => u.Access == "read"),
+            FilesScanned = report.FilesScanned,
+            Truncated = report.Truncated
+        };
+
+        _logger.LogDebug("column_usages: {Mappings} mappings, {Writes} writes, {Reads} reads for {Column}",
+            result.Mappings.Count, result.Writes, result.Reads, column);
+
+        var response = new AIOptimizedResponse<ColumnUsagesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<ColumnUsagesResult> { Results = result },
+            Message = $"'{column}' is mapped by {result.Mappings.Count} entit{(result.Mappings.Count == 1 ? "y" : "ies")}; " +
+                      $"{result.Writes} write(s), {result.Reads} read(s)"
+        };
+
+        var insights = new List<string>();
+        var tables = result.Mappings.Select(m => m.Table).Distinct(StringComparer.OrdinalIgnoreCase).ToList();
+        if (tables.Count > 1 && !column.Contains('.'))
+        {
+            insights.Add($"The column exists in {string.Join(", ", tables.Take(5))} - qualify it, e.g. '{tables[0]}.{column}', to follow one table");
+        }
+        if (result.Mappings.Count == 0)
+        {
+            insights.Add("No ORM member maps this column, so only SQL text was searched");
+        }
+        if (result.Usages.Any(u => u.Via == "sql"))
+        {
+            insights.Add("SQL usages are matched in string literals - queries built at runtime or kept in .sql files outside the index are not seen");
+        }
+        if (result.Truncated)
+        {
+            insights.Add("Results were cut at maxResults - narrow with filePattern or access");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<ColumnUsagesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
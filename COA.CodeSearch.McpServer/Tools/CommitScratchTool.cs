@@ -0,0 +1,204 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Writes staged scratch files to the workspace through <see cref="UnifiedFileEditService.ApplyFileSetAsync"/>:
+/// all files or none, refusing files edited on disk since they were staged
+/// </summary>
+public class CommitScratchTool : CodeSearchToolBase<CommitScratchParameters, AIOptimizedResponse<CommitScratchResult>>
+{
+    private readonly IScratchWorkspaceService _scratch;
+    private readonly UnifiedFileEditService _fileEditService;
+    private readonly IWorkspacePermissionService _permissionService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<CommitScratchTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the CommitScratchTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="scratch">Scratch area holding staged files</param>
+    /// <param name="fileEditService">Edit service that applies the files as one unit</param>
+    /// <param name="permissionService">Workspace permission service for edit checks</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public CommitScratchTool(
+        IServiceProvider serviceProvider,
+        IScratchWorkspaceService scratch,
+        UnifiedFileEditService fileEditService,
+        IWorkspacePermissionService permissionService,
+        IPathResolutionService pathResolutionService,
+        ILogger<CommitScratchTool> logger) : base(serviceProvider, logger)
+    {
+        _scratch = scratch;
+        _fileEditService = fileEditService;
+        _permissionService = permissionService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.CommitScratch;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "APPLY STAGED CODE - Write files staged with stage_scratch to the workspace as one unit: either every file is written or none is. " +
+        "Refuses files edited on disk since staging unless force is set. Review with scratch_diff first.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Refactoring;
+
+    /// <summary>
+    /// Commits staged files.
+    /// </summary>
+    /// <param name="parameters">Files to commit and conflict handling</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Written and deleted files</returns>
+    protected override async Task<AIOptimizedResponse<CommitScratchResult>> ExecuteInternalAsync(
+        CommitScratchParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        IReadOnlyList<ScratchEntry> entries;
+        IReadOnlyList<ScratchFileDiff> diffs;
+        try
+        {
+            var wanted = parameters.FilePaths?.Select(p => _scratch.ToRelativePath(workspacePath, p)).ToHashSet(StringComparer.Ordinal);
+            entries = _scratch.List(workspacePath).Where(e => wanted == null || wanted.Contains(e.FilePath)).ToList();
+            diffs = _scratch.Diff(workspacePath, entries.Select(e => e.FilePath));
+        }
+        catch (ScratchException ex)
+        {
+            return CreateErrorResponse(ex.Code, ex.Message, "Use paths inside the workspace");
+        }
+
+        if (entries.Count == 0)
+        {
+            return CreateErrorResponse("NOTHING_STAGED", "No staged files to commit",
+                "Stage files with stage_scratch first", "Check filePaths against stage_scratch operation 'list'");
+        }
+
+        var permission = await _permissionService.IsEditAllowedAsync(new EditPermissionRequest
+        {
+            FilePath = Path.Combine(workspacePath, entries[0].FilePath),
+            OperationType = "commit_scratch",
+            Context = $"{entries.Count} staged file(s)"
+        }, cancellationToken);
+        if (!permission.Allowed)
+        {
+            return CreateErrorResponse("EDIT_NOT_ALLOWED", $"Edit not allowed: {permission.Reason}");
+        }
+
+        var conflicts = diffs.Where(d => d.Conflict).Select(d => d.FilePath).ToList();
+        if (conflicts.Count > 0 && !parameters.Force)
+        {
+            return CreateConflictResponse(conflicts, entries.Count);
+        }
+
+        var changes = entries
+            .Select(e => new FileSetChange
+            {
+                FilePath = Path.Combine(workspacePath, e.FilePath),
+                NewContent = e.Content,
+                ExpectedHash = e.BaseHash,
+                CheckExpectedHash = !parameters.Force
+            })
+            .ToList();
+
+        var applied = await _fileEditService.ApplyFileSetAsync(changes, cancellationToken);
+        if (applied.Conflicts.Count > 0)
+        {
+            // Edited between the check above and taking the file locks
+            return CreateConflictResponse(applied.Conflicts.Select(p => ToRelative(workspacePath, p)).ToList(), entries.Count);
+        }
+        if (!applied.Success)
+        {
+            return CreateErrorResponse("COMMIT_FAILED",
+                $"{applied.ErrorMessage}{(applied.RolledBack ? " - files already written were restored" : "")}",
+                "Nothing was changed; staged files are kept", "Check file permissions and retry");
+        }
+
+        foreach (var entry in entries)
+        {
+            _scratch.Unstage(workspacePath, entry.FilePath);
+        }
+
+        var result = new CommitScratchResult
+        {
+            Written = applied.Written.Select(p => ToRelative(workspacePath, p)).ToList(),
+            Deleted = applied.Deleted.Select(p => ToRelative(workspacePath, p)).ToList(),
+            Conflicts = conflicts,
+            Remaining = _scratch.List(workspacePath).Count
+        };
+        _logger.LogInformation("Committed scratch for {Workspace}: {Written} written, {Deleted} deleted",
+            workspacePath, result.Written.Count, result.Deleted.Count);
+
+        var response = new AIOptimizedResponse<CommitScratchResult>
+        {
+            Success = true,
+            Data = new AIResponseData<CommitScratchResult> { Results = result },
+            Message = $"Committed {result.Written.Count} file(s), deleted {result.Deleted.Count}" +
+                      (result.Remaining > 0 ? $"; {result.Remaining} still staged" : "")
+        };
+
+        if (conflicts.Count > 0)
+        {
+            response.Insights = new List<string> { $"Overwrote edits made on disk after staging: {string.Join(", ", conflicts)}" };
+        }
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<CommitScratchResult> CreateConflictResponse(List<string> conflicts, int staged) => new()
+    {
+        Success = false,
+        Data = new AIResponseData<CommitScratchResult>
+        {
+            Results = new CommitScratchResult { Conflicts = conflicts, Remaining = staged }
+        },
+        Error = new ErrorInfo
+        {
+            Code = "SCRATCH_CONFLICT",
+            Message = $"{conflicts.Count} file(s) changed on disk since they were staged: {string.Join(", ", conflicts)}",
+            Recovery = new RecoveryInfo
+            {
+                Steps = new[]
+                {
+                    "Review with scratch_diff and re-stage from the current file",
+                    "Or commit with force: true to overwrite the on-disk changes"
+                }
+            }
+        }
+    };
+
+    private static string ToRelative(string workspacePath, string path) =>
+        Path.GetRelativePath(workspacePath, path).Replace('\\', '/');
+
+    private static AIOptimizedResponse<CommitScratchResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,176 @@
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DataFlow;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Traces a variable or parameter: the assignments and caller arguments its value comes from, and the calls,
+/// variables and returns it flows into, following calls in both directions up to a depth.
+/// </summary>
+public class DataFlowTool : CodeSearchToolBase<DataFlowParameters, AIOptimizedResponse<DataFlowResult>>
+{
+    private static readonly string[] Directions = { "sources", "sinks", "both" };
+
+    private readonly IDataFlowService _dataFlowService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<DataFlowTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the DataFlowTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="dataFlowService">Tracer over the indexed functions</param>
+    /// <param name="sqliteService">Symbol database, checked for an index before tracing</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public DataFlowTool(
+        IServiceProvider serviceProvider,
+        IDataFlowService dataFlowService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<DataFlowTool> logger) : base(serviceProvider, logger)
+    {
+        _dataFlowService = dataFlowService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.DataFlow;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DATA FLOW - What can end up in this variable, and where does it go? Given a variable or parameter position, lists the assignments and caller arguments " +
+        "its value comes from and the calls, variables and returns it flows into, following calls up to a depth. " +
+        "Flags calls into code outside the index (SQL, shell, HTTP). Text-based: fields, collections and aliasing are not tracked.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Executes the trace from the position.
+    /// </summary>
+    /// <param name="parameters">Position, direction and depth</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Sources and sinks of the variable's value</returns>
+    protected override async Task<AIOptimizedResponse<DataFlowResult>> ExecuteInternalAsync(
+        DataFlowParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var position = ValidateRequired(parameters.Position, nameof(parameters.Position));
+        if (!SourcePositions.TryParseLocation(position, out var filePath, out var line, out var column))
+        {
+            return Failure("INVALID_POSITION", $"'{position}' is not a position 'path:line:column'",
+                "Use a 1-based line and 0-based column, e.g. src/Data/UserRepository.cs:58:24");
+        }
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return Failure("INVALID_COLUMN_ENCODING", $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                "Use 'utf-16' for LSP positions or 'utf-8' for byte columns");
+        }
+        var direction = parameters.Direction.Trim().ToLowerInvariant();
+        if (!Directions.Contains(direction))
+        {
+            return Failure("INVALID_DIRECTION", $"Unknown direction '{parameters.Direction}'",
+                "Use 'sources', 'sinks' or 'both'");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return Failure("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try data_flow again");
+        }
+
+        var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+        var variable = await CreateSourcePositions(workspacePath)
+            .GetIdentifierAtAsync(filePath, line, column, parameters.ColumnEncoding, cancellationToken);
+        if (variable == null)
+        {
+            return Failure("INVALID_POSITION", $"No identifier at {position}",
+                "Point the column at the variable or parameter name");
+        }
+
+        var trace = await _dataFlowService.TraceAsync(workspacePath, fullPath, line, variable,
+            sources: direction != "sinks", sinks: direction != "sources", Math.Clamp(parameters.Depth, 0, 5), cancellationToken);
+        if (trace == null)
+        {
+            return Failure("NO_FUNCTION", $"{filePath}:{line} is not inside a function the index knows",
+                "Data flow is traced within functions and methods; pick a variable inside one",
+                "Run index_workspace if the function was added recently");
+        }
+
+        var result = new DataFlowResult
+        {
+            Variable = trace.Variable,
+            Function = trace.Function,
+            FilePath = fullPath,
+            Line = line,
+            Sources = trace.Sources,
+            Sinks = trace.Sinks,
+            ExternalCalls = trace.Sinks
+                .Where(s => s.External)
+                .Select(s => $"{s.Callee} (argument {s.ArgumentIndex + 1}) at {s.FilePath}:{s.Line}")
+                .ToList(),
+            Truncated = trace.Truncated
+        };
+
+        _logger.LogInformation("Data flow for {Variable} at {Position}: {Sources} sources, {Sinks} sinks",
+            variable, position, result.Sources.Count, result.Sinks.Count);
+
+        var response = new AIOptimizedResponse<DataFlowResult>
+        {
+            Success = true,
+            Data = new AIResponseData<DataFlowResult> { Results = result },
+            Message = $"{variable} in {trace.Function}: {result.Sources.Count} source(s), {result.Sinks.Count} sink(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.ExternalCalls.Count > 0)
+        {
+            insights.Add($"Reaches {result.ExternalCalls.Count} call(s) outside the index: {string.Join("; ", result.ExternalCalls.Take(5))}");
+        }
+        var unresolved = result.Sources.Where(s => s.Kind == "unresolved").Select(s => s.Variable).Distinct().ToList();
+        if (unresolved.Count > 0)
+        {
+            insights.Add($"Not assigned in their function, so not traced further: {string.Join(", ", unresolved)}");
+        }
+        if (result.Truncated)
+        {
+            insights.Add("Stopped at the CodeSearch:DataFlow:MaxSteps limit - lower the depth or trace one direction");
+        }
+        insights.Add("Text-based: flows through fields, collections, aliases and closures are not tracked, and same-named functions may add extra paths");
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<DataFlowResult> Failure(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,197 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Flags conditionals that known configuration makes always true or false - feature flags that are off,
+/// build constants - and the branches behind them that can never run, as input to cleanup refactors
+/// </summary>
+public class DeadBranchesTool : CodeSearchToolBase<DeadBranchesParameters, AIOptimizedResponse<DeadBranchesResult>>
+{
+    // Candidate files requested from the full-text index per scan
+    private const int CandidateFileLimit = 2000;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<DeadBranchesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the DeadBranchesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service the candidate files are read from</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public DeadBranchesTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<DeadBranchesTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.DeadBranches;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT CAN THIS FLAG REMOVAL DELETE? Given known constant values (feature flags switched off, build constants), finds if/while/#if " +
+        "conditions that are always true or false and the line ranges of the branches that can never run. " +
+        "Use before cleaning up a retired feature flag or build symbol; pass the ranges to edit_lines or smart_refactor.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Scans the files mentioning the constants for conditionals they decide.
+    /// </summary>
+    /// <param name="parameters">Constants, file filter and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Decided conditionals and their dead ranges</returns>
+    protected override async Task<AIOptimizedResponse<DeadBranchesResult>> ExecuteInternalAsync(
+        DeadBranchesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var entries = parameters.Constants?.Where(c => !string.IsNullOrWhiteSpace(c)).ToList() ?? new List<string>();
+        if (entries.Count == 0)
+        {
+            return CreateErrorResponse("MISSING_CONSTANTS", "Give at least one constant as NAME=value",
+                "Example: [\"Features.NewCheckout=false\", \"DEBUG=0\"]");
+        }
+        if (!ConstantConditions.TryParseConstants(entries, out var constants, out var invalid))
+        {
+            return CreateErrorResponse("INVALID_CONSTANT", $"'{invalid}' is not NAME=value",
+                "Write each constant as NAME=value, e.g. EnableBeta=false or Region=\"eu\"");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try dead_branches again");
+        }
+
+        var files = await CandidateFilesAsync(workspacePath, constants.Keys, parameters.FilePattern, cancellationToken);
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var result = new DeadBranchesResult();
+
+        foreach (var file in files.OrderBy(f => f.Path, StringComparer.Ordinal))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var content = file.Content ?? (File.Exists(file.Path) ? await File.ReadAllTextAsync(file.Path, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            result.FilesScanned++;
+            var lines = content.Split('\n').Select(l => l.TrimEnd('\r')).ToArray();
+            foreach (var branch in ConstantConditions.Scan(file.Path, lines, constants))
+            {
+                if (result.Branches.Count == maxResults)
+                {
+                    result.Truncated = true;
+                    break;
+                }
+                branch.FilePath = (Path.IsPathRooted(file.Path) ? Path.GetRelativePath(workspacePath, file.Path) : file.Path).Replace('\\', '/');
+                result.Branches.Add(branch);
+            }
+            if (result.Truncated)
+                break;
+        }
+
+        result.DeadLines = result.Branches
+            .Where(b => b.DeadStartLine.HasValue && b.DeadEndLine >= b.DeadStartLine)
+            .Sum(b => b.DeadEndLine!.Value - b.DeadStartLine!.Value + 1);
+
+        _logger.LogDebug("dead_branches found {Count} decided conditionals in {Files} files", result.Branches.Count, result.FilesScanned);
+
+        var response = new AIOptimizedResponse<DeadBranchesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<DeadBranchesResult> { Results = result },
+            Message = result.Branches.Count > 0
+                ? $"{result.Branches.Count} conditional(s) decided by the constants, {result.DeadLines} dead line(s) in {result.Branches.Select(b => b.FilePath).Distinct().Count()} file(s)"
+                : $"No conditionals decided by the constants in {result.FilesScanned} file(s) mentioning them"
+        };
+
+        var insights = new List<string>();
+        var unused = constants.Keys.Where(k => result.Branches.All(b => !b.Constants.Contains(k))).ToList();
+        if (unused.Count > 0 && result.Branches.Count > 0 && !result.Truncated)
+        {
+            insights.Add($"Not found in any decided condition: {string.Join(", ", unused)} - check the names, or they are read outside conditionals");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped at {maxResults} results - narrow with filePattern or raise maxResults");
+        }
+        insights.Add("Only the condition text is evaluated: flags read through variables, helpers or config lookups are not followed, and overlapping ranges of nested conditionals should be removed outermost first");
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// Files mentioning any constant's last name segment, from the full-text index. Falls back to every
+    /// indexed file when a name is too short for the index's tokenizer or the match finds nothing.
+    /// </summary>
+    private async Task<List<FileRecord>> CandidateFilesAsync(string workspacePath, IEnumerable<string> names, string? filePattern,
+        CancellationToken cancellationToken)
+    {
+        var segments = names.Select(n => n[(n.LastIndexOfAny(new[] { '.', ':' }) + 1)..]).Distinct().ToList();
+        if (segments.All(s => s.Length >= 3))
+        {
+            var match = string.Join(" OR ", segments.Select(s => $"\"{s.Replace("\"", "\"\"")}\""));
+            try
+            {
+                var files = await _sqliteService.SearchWithFTS5Async(workspacePath, match, CandidateFileLimit, filePattern, cancellationToken);
+                if (files.Count > 0)
+                    return files;
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogDebug(ex, "Full-text candidate search failed, scanning all files");
+            }
+        }
+
+        var all = await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken);
+        return all.Where(f => f.Content == null || segments.Any(s => f.Content.Contains(s, StringComparison.Ordinal)))
+            .Where(f => filePattern == null || GlobMatches(filePattern, f.Path))
+            .ToList();
+    }
+
+    // Same semantics as the full-text search's LIKE filter: * and ? over the whole path
+    private static bool GlobMatches(string pattern, string path) =>
+        Regex.IsMatch(path.Replace('\\', '/'),
+            "^" + Regex.Escape(pattern.Replace('\\', '/')).Replace(@"\*", ".*").Replace(@"\?", ".") + "$",
+            RegexOptions.IgnoreCase);
+
+    private static AIOptimizedResponse<DeadBranchesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
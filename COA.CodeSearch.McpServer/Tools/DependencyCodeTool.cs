@@ -0,0 +1,169 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Shows or sets how a workspace indexes vendored and third-party directories, and counts the indexed files in
+/// each of them
+/// </summary>
+public class DependencyCodeTool : CodeSearchToolBase<DependencyCodeParameters, AIOptimizedResponse<DependencyCodeResult>>
+{
+    private readonly IDependencyCodeService _dependencyCode;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<DependencyCodeTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the DependencyCodeTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="dependencyCode">Dependency code settings</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public DependencyCodeTool(
+        IServiceProvider serviceProvider,
+        IDependencyCodeService dependencyCode,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<DependencyCodeTool> logger) : base(serviceProvider, logger)
+    {
+        _dependencyCode = dependencyCode;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.DependencyCode;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "VENDORED CODE DROWNING OUT RESULTS? Shows or sets how this workspace indexes vendor/, third_party/ and other " +
+        "dependency directories: exclude them, include them flagged as dependency code, or downrank them below first-party " +
+        "code in text_search. Shows how many indexed files each dependency directory holds.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Updates the settings when asked and counts the indexed files they cover.
+    /// </summary>
+    /// <param name="parameters">Settings changes</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The settings with the indexed dependency files per directory</returns>
+    protected override async Task<AIOptimizedResponse<DependencyCodeResult>> ExecuteInternalAsync(
+        DependencyCodeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        var mode = parameters.Mode?.Trim().ToLowerInvariant();
+        if (!string.IsNullOrEmpty(mode) && !DependencyCodeSettings.Modes.Contains(mode))
+        {
+            return CreateErrorResponse("INVALID_MODE", $"Unknown mode '{parameters.Mode}'",
+                $"Use one of: {string.Join(", ", DependencyCodeSettings.Modes)}");
+        }
+
+        var previous = _dependencyCode.GetSettings(workspacePath);
+        if (parameters.Reset)
+        {
+            _dependencyCode.SetSettings(workspacePath, null);
+        }
+        if (!string.IsNullOrEmpty(mode) || parameters.Directories != null)
+        {
+            var current = _dependencyCode.GetSettings(workspacePath);
+            _dependencyCode.SetSettings(workspacePath, new DependencyCodeSettings
+            {
+                Mode = string.IsNullOrEmpty(mode) ? current.Mode : mode,
+                Directories = parameters.Directories ?? current.Directories
+            });
+        }
+
+        var settings = _dependencyCode.GetSettings(workspacePath);
+        var changed = settings.Mode != previous.Mode || !settings.Directories.SequenceEqual(previous.Directories, StringComparer.OrdinalIgnoreCase);
+        var result = new DependencyCodeResult
+        {
+            Settings = settings,
+            Source = _dependencyCode.HasWorkspaceSettings(workspacePath) ? "workspace" : "default"
+        };
+
+        var indexed = _sqliteService.DatabaseExists(workspacePath);
+        if (indexed)
+        {
+            var counts = new Dictionary<string, int>(StringComparer.OrdinalIgnoreCase);
+            foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            {
+                result.IndexedFiles++;
+                var directory = _dependencyCode.GetDependencyDirectory(workspacePath, file.Path);
+                if (directory == null)
+                    continue;
+
+                result.DependencyFiles++;
+                counts[directory] = counts.GetValueOrDefault(directory) + 1;
+            }
+            result.Directories = counts
+                .OrderByDescending(c => c.Value)
+                .ThenBy(c => c.Key, StringComparer.Ordinal)
+                .ToDictionary(c => c.Key, c => c.Value);
+        }
+
+        _logger.LogDebug("dependency_code {Settings}: {Dependency} of {Files} indexed files", settings, result.DependencyFiles, result.IndexedFiles);
+
+        var response = new AIOptimizedResponse<DependencyCodeResult>
+        {
+            Success = true,
+            Data = new AIResponseData<DependencyCodeResult> { Results = result },
+            Message = $"{(changed ? "Dependency code mode set to" : "Dependency code mode")} {settings.Mode} ({result.Source})" +
+                      (indexed ? $": {result.DependencyFiles} of {result.IndexedFiles} indexed file(s) in {result.Directories.Count} dependency director(ies)" : string.Empty)
+        };
+
+        var insights = new List<string>();
+        if (!indexed)
+        {
+            insights.Add("Workspace has not been indexed yet - the settings apply when index_workspace first runs");
+        }
+        if (changed && indexed)
+        {
+            insights.Add("The index still reflects the previous settings - run index_workspace with forceRebuild to apply them");
+        }
+        else if (indexed && settings.Mode == DependencyCodeSettings.Exclude && result.DependencyFiles > 0)
+        {
+            insights.Add($"{result.DependencyFiles} dependency file(s) were indexed before exclude was set - run index_workspace with forceRebuild to drop them");
+        }
+        if (settings.Mode != DependencyCodeSettings.Exclude)
+        {
+            insights.Add("text_search and symbol_search flag hits in dependency directories with dependencyCode true" +
+                         (settings.Mode == DependencyCodeSettings.Downrank ? "; text_search ranks them below first-party code" : string.Empty));
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<DependencyCodeResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,215 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Detects symbols renamed or moved over a diff or commit range, pairing removed and added
+/// declarations by body, signature and name similarity (e.g. <c>Helper</c> → <c>HelperHandler</c>)
+/// </summary>
+public class DetectRenamesTool : CodeSearchToolBase<DetectRenamesParameters, AIOptimizedResponse<DetectRenamesResult>>
+{
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILogger<DetectRenamesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the DetectRenamesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="gitService">Git service for reading the diff and file versions</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="sqliteService">Optional symbol database for counting stale references</param>
+    public DetectRenamesTool(
+        IServiceProvider serviceProvider,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        ILogger<DetectRenamesTool> logger,
+        ISQLiteSymbolService? sqliteService = null) : base(serviceProvider, logger)
+    {
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.DetectRenames;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "FIND RENAMED SYMBOLS - Old → new symbol mappings with confidence scores over a diff or commit range (baseRef..headRef), " +
+        "including moves between files and references to old names still in the code. Use after pulling a refactor or before updating callers.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Executes rename detection.
+    /// </summary>
+    /// <param name="parameters">Range and thresholds</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Symbol and file renames</returns>
+    protected override async Task<AIOptimizedResponse<DetectRenamesResult>> ExecuteInternalAsync(
+        DetectRenamesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _gitService.IsRepositoryAsync(workspacePath, cancellationToken))
+        {
+            return CreateGitErrorResponse("NOT_A_GIT_REPOSITORY", $"{workspacePath} is not inside a git repository (or git is not installed)");
+        }
+
+        var headRef = string.IsNullOrWhiteSpace(parameters.HeadRef) || parameters.HeadRef.Equals("worktree", StringComparison.OrdinalIgnoreCase)
+            ? null
+            : parameters.HeadRef;
+
+        List<GitFileDiff> diff;
+        try
+        {
+            diff = await _gitService.GetDiffAsync(workspacePath, parameters.BaseRef, headRef, cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            return CreateGitErrorResponse("GIT_DIFF_FAILED", ex.Message);
+        }
+
+        var (files, skipped) = await _gitService.ScanChangedFilesAsync(
+            workspacePath, diff, parameters.BaseRef, headRef, parameters.MaxFiles, _logger, cancellationToken);
+        var changes = GitDeclarationReader.Compare(files, parameters.MinConfidence);
+
+        var result = new DetectRenamesResult
+        {
+            BaseRef = parameters.BaseRef,
+            HeadRef = headRef ?? "worktree",
+            FilesScanned = files.Count,
+            UnpairedRemoved = changes.Count(c => c.Change == "removed"),
+            UnpairedAdded = changes.Count(c => c.Change == "added"),
+            FileRenames = diff
+                .Where(f => f.Status == "renamed" && f.OldPath != null)
+                .Select(f => new FileRename { OldPath = f.OldPath!, NewPath = f.Path })
+                .ToList()
+        };
+
+        result.Renames = changes
+            .Where(c => c.Change == "renamed" || (parameters.IncludeMoves && c.Change == "moved"))
+            .OrderByDescending(c => c.Confidence)
+            .ThenBy(c => c.FilePath, StringComparer.Ordinal)
+            .Select(c => new SymbolRename
+            {
+                OldName = c.OldName ?? c.Name,
+                NewName = c.Name,
+                Kind = c.Kind,
+                Change = c.Change,
+                OldContainer = c.OldContainer,
+                NewContainer = c.Container,
+                OldFile = c.OldFilePath ?? c.FilePath,
+                NewFile = c.FilePath,
+                OldLine = c.OldLine,
+                NewLine = c.Line,
+                IsPublic = c.IsPublic || c.WasPublic,
+                Confidence = c.Confidence ?? 0
+            })
+            .ToList();
+
+        var checkedReferences = false;
+        if (parameters.CheckReferences && _sqliteService != null && _sqliteService.DatabaseExists(workspacePath))
+        {
+            checkedReferences = true;
+            foreach (var rename in result.Renames.Where(r => r.Change == "renamed"))
+            {
+                rename.StaleReferences = await _sqliteService.GetIdentifierCountByNameAsync(
+                    workspacePath, rename.OldName, caseSensitive: true, cancellationToken);
+            }
+        }
+
+        var response = new AIOptimizedResponse<DetectRenamesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<DetectRenamesResult> { Results = result },
+            Message = result.Renames.Count == 0
+                ? $"No renamed symbols found in {diff.Count} changed file(s)"
+                : $"{result.Renames.Count(r => r.Change == "renamed")} rename(s) and {result.Renames.Count(r => r.Change == "moved")} move(s): " +
+                  string.Join(", ", result.Renames.Take(5).Select(r => $"{r.OldName} → {r.NewName} ({r.Confidence:P0})"))
+        };
+
+        var insights = new List<string>();
+        var stale = result.Renames.Where(r => r.StaleReferences > 0).ToList();
+        if (stale.Count > 0)
+        {
+            insights.Add($"Old names still referenced: {string.Join(", ", stale.Take(5).Select(r => $"{r.OldName} ({r.StaleReferences})"))}");
+        }
+        if (result.Renames.Any(r => r.Confidence < 0.75))
+        {
+            insights.Add("Some mappings have low confidence - the body changed along with the name; verify before relying on them");
+        }
+        if (result.UnpairedRemoved > 0 && result.UnpairedAdded > 0)
+        {
+            insights.Add($"{result.UnpairedRemoved} removed and {result.UnpairedAdded} added symbol(s) were not paired; lower minConfidence to see weaker candidates");
+        }
+        if (skipped > 0)
+        {
+            insights.Add($"{skipped} changed source file(s) were not scanned (maxFiles, size limit or unreadable)");
+        }
+        if (parameters.CheckReferences && !checkedReferences)
+        {
+            insights.Add("Symbol database not available - stale references were not checked; run index_workspace first");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        if (stale.Count > 0)
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction
+                {
+                    Action = ToolNames.FindReferences,
+                    Description = $"Find the remaining references to '{stale[0].OldName}' and update them to '{stale[0].NewName}'",
+                    Priority = 85
+                }
+            };
+        }
+
+        _logger.LogDebug("Detected {Count} renames between {Base} and {Head}", result.Renames.Count, result.BaseRef, result.HeadRef);
+        return response;
+    }
+
+    private static AIOptimizedResponse<DetectRenamesResult> CreateGitErrorResponse(string code, string message) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo
+            {
+                Steps = new[]
+                {
+                    "Make sure the workspace is inside a git repository and git is on PATH",
+                    "Check that baseRef and headRef exist (git fetch for remote branches)",
+                    "Use headRef 'worktree' to include uncommitted changes"
+                }
+            }
+        }
+    };
+}
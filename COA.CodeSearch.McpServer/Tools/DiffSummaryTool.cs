@@ -0,0 +1,286 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Summarizes a diff structurally - symbols added, removed and renamed, public API changes and
+/// test changes - so commit messages and changelog entries don't have to be derived from raw patches
+/// </summary>
+public class DiffSummaryTool : CodeSearchToolBase<DiffSummaryParameters, AIOptimizedResponse<DiffSummaryResult>>
+{
+    private static readonly string[] DocumentationExtensions = { ".md", ".mdx", ".rst", ".adoc", ".txt" };
+    private static readonly string[] ConfigExtensions = { ".json", ".yml", ".yaml", ".toml", ".xml", ".config", ".ini", ".env", ".editorconfig" };
+    private static readonly string[] BuildFiles =
+    {
+        "Makefile", "Dockerfile", "package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.mod", "go.sum",
+        "Cargo.toml", "Cargo.lock", "pom.xml", "build.gradle", "Directory.Build.props", "Directory.Packages.props", "global.json"
+    };
+    private static readonly string[] BuildExtensions = { ".csproj", ".fsproj", ".vbproj", ".sln", ".props", ".targets" };
+
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<DiffSummaryTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the DiffSummaryTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="gitService">Git service for reading the diff and file versions</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public DiffSummaryTool(
+        IServiceProvider serviceProvider,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        ILogger<DiffSummaryTool> logger) : base(serviceProvider, logger)
+    {
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.DiffSummary;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "SUMMARIZE A CHANGE BEFORE WRITING ABOUT IT - Structural summary of a diff: symbols added/removed/renamed, public API changes " +
+        "(breaking changes flagged), test changes, a suggested commit type and scopes. Use for commit messages and changelog entries instead of reading raw patches.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Executes the diff summary.
+    /// </summary>
+    /// <param name="parameters">Base and head refs and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The structural summary</returns>
+    protected override async Task<AIOptimizedResponse<DiffSummaryResult>> ExecuteInternalAsync(
+        DiffSummaryParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _gitService.IsRepositoryAsync(workspacePath, cancellationToken))
+        {
+            return CreateGitErrorResponse("NOT_A_GIT_REPOSITORY", $"{workspacePath} is not inside a git repository (or git is not installed)");
+        }
+
+        var headRef = string.IsNullOrWhiteSpace(parameters.HeadRef) || parameters.HeadRef.Equals("worktree", StringComparison.OrdinalIgnoreCase)
+            ? null
+            : parameters.HeadRef;
+
+        List<GitFileDiff> diff;
+        try
+        {
+            diff = await _gitService.GetDiffAsync(workspacePath, parameters.BaseRef, headRef, cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            return CreateGitErrorResponse("GIT_DIFF_FAILED", ex.Message);
+        }
+
+        var result = new DiffSummaryResult
+        {
+            BaseRef = parameters.BaseRef,
+            HeadRef = headRef ?? "worktree",
+            Stats = new DiffStats
+            {
+                FilesChanged = diff.Count,
+                Additions = diff.Sum(f => f.Additions),
+                Deletions = diff.Sum(f => f.Deletions),
+                ByLanguage = diff
+                    .GroupBy(f => LanguageCapabilities.Find(Path.GetExtension(f.Path))?.Name ?? "other")
+                    .OrderByDescending(g => g.Count())
+                    .ToDictionary(g => g.Key, g => g.Count())
+            }
+        };
+
+        foreach (var file in diff)
+        {
+            result.Files.Add(new DiffSummaryFile
+            {
+                Path = file.Path,
+                OldPath = file.OldPath,
+                Status = file.Status,
+                Category = Categorize(file.Path),
+                Additions = file.Additions,
+                Deletions = file.Deletions
+            });
+        }
+
+        // Scan both versions of each changed source file and diff their declarations
+        var (scanned, skipped) = await _gitService.ScanChangedFilesAsync(
+            workspacePath, diff, parameters.BaseRef, headRef, parameters.MaxFiles, _logger, cancellationToken);
+        result.SymbolChanges = GitDeclarationReader.Compare(scanned)
+            .OrderBy(c => c.FilePath, StringComparer.Ordinal)
+            .ThenBy(c => c.Line)
+            .ToList();
+
+        result.PublicApiChanges = result.SymbolChanges
+            .Where(c => c.IsPublic || c.WasPublic)
+            .Select(c => new ApiChange
+            {
+                Symbol = c.Container == null ? c.Name : $"{c.Container}.{c.Name}",
+                Change = c.Change == "modified" && c.IsPublic != c.WasPublic
+                    ? (c.IsPublic ? "made public" : "made non-public")
+                    : c.Change,
+                FilePath = c.FilePath,
+                Before = c.OldSignature,
+                After = c.Signature,
+                Breaking = c.IsBreaking
+            })
+            .ToList();
+
+        SummarizeTests(result, diff);
+        result.SuggestedType = SuggestType(result);
+        result.Scopes = diff
+            .Select(f => f.Path.Replace('\\', '/'))
+            .Select(p => p.Contains('/') ? p[..p.IndexOf('/')] : "(root)")
+            .GroupBy(s => s, StringComparer.OrdinalIgnoreCase)
+            .OrderByDescending(g => g.Count())
+            .Select(g => g.Key)
+            .Take(5)
+            .ToList();
+
+        var breaking = result.PublicApiChanges.Count(c => c.Breaking);
+        var response = new AIOptimizedResponse<DiffSummaryResult>
+        {
+            Success = true,
+            Data = new AIResponseData<DiffSummaryResult> { Results = result },
+            Message = $"{result.Stats.FilesChanged} file(s) (+{result.Stats.Additions}/-{result.Stats.Deletions}), " +
+                      $"{result.SymbolChanges.Count} symbol change(s), {result.PublicApiChanges.Count} public API change(s)" +
+                      (breaking > 0 ? $" including {breaking} breaking" : string.Empty)
+        };
+
+        var insights = new List<string>();
+        if (breaking > 0)
+        {
+            insights.Add($"Breaking API changes: {string.Join(", ", result.PublicApiChanges.Where(c => c.Breaking).Take(5).Select(c => c.Symbol))} - " +
+                         "mention them in the changelog (and use '!' or a BREAKING CHANGE footer in conventional commits)");
+        }
+        if (result.SymbolChanges.Any(c => c.Change == "renamed"))
+        {
+            insights.Add("Renames are inferred from similar bodies and signatures; check the confidence scores");
+        }
+        if (result.Files.Any(f => f.Category == "source") && result.TestChanges.FilesAdded.Count + result.TestChanges.FilesModified.Count == 0)
+        {
+            insights.Add("Source changed without test changes");
+        }
+        if (skipped > 0)
+        {
+            insights.Add($"{skipped} source file(s) were not scanned for symbols (maxFiles, size limit or unreadable)");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        _logger.LogDebug("Summarized {Base}..{Head}: {Files} files, {Symbols} symbol changes",
+            result.BaseRef, result.HeadRef, diff.Count, result.SymbolChanges.Count);
+        return response;
+    }
+
+    /// <summary>
+    /// source, test, docs, config or build
+    /// </summary>
+    public static string Categorize(string path)
+    {
+        var fileName = Path.GetFileName(path);
+        var extension = Path.GetExtension(path);
+        var normalized = path.Replace('\\', '/');
+
+        if (TestFileDetector.IsTestFile(path))
+            return "test";
+        if (DocumentationExtensions.Contains(extension, StringComparer.OrdinalIgnoreCase) ||
+            normalized.StartsWith("docs/", StringComparison.OrdinalIgnoreCase))
+            return "docs";
+        if (BuildFiles.Contains(fileName, StringComparer.OrdinalIgnoreCase) ||
+            BuildExtensions.Contains(extension, StringComparer.OrdinalIgnoreCase) ||
+            normalized.StartsWith(".github/", StringComparison.OrdinalIgnoreCase))
+            return "build";
+        if (ConfigExtensions.Contains(extension, StringComparer.OrdinalIgnoreCase) || fileName.StartsWith('.'))
+            return "config";
+
+        return "source";
+    }
+
+    private static void SummarizeTests(DiffSummaryResult result, List<GitFileDiff> diff)
+    {
+        foreach (var file in diff.Where(f => TestFileDetector.IsTestFile(f.Path)))
+        {
+            var list = file.Status switch
+            {
+                "added" => result.TestChanges.FilesAdded,
+                "deleted" => result.TestChanges.FilesRemoved,
+                _ => result.TestChanges.FilesModified
+            };
+            list.Add(file.Path);
+        }
+
+        foreach (var change in result.SymbolChanges.Where(c => c.Kind is "method" or "function" && TestFileDetector.IsTestFile(c.FilePath)))
+        {
+            var name = change.Container == null ? change.Name : $"{change.Container}.{change.Name}";
+            if (change.Change == "added")
+                result.TestChanges.TestsAdded.Add(name);
+            else if (change.Change == "removed")
+                result.TestChanges.TestsRemoved.Add(name);
+        }
+    }
+
+    private static string? SuggestType(DiffSummaryResult result)
+    {
+        if (result.Files.Count == 0)
+            return null;
+        if (result.Files.All(f => f.Category == "docs"))
+            return "docs";
+        if (result.Files.All(f => f.Category == "test"))
+            return "test";
+        if (result.Files.All(f => f.Category is "build" or "config"))
+            return result.Files.All(f => f.Category == "build") ? "build" : "chore";
+
+        var source = result.SymbolChanges.Where(c => !TestFileDetector.IsTestFile(c.FilePath)).ToList();
+        if (source.Any(c => c.Change == "added" && c.IsPublic))
+            return "feat";
+        if (source.Count > 0 && source.All(c => c.Change is "renamed" or "moved" or "modified"))
+            return "refactor";
+
+        // Body-only changes to existing code: fix or refactor, which the diff alone can't tell
+        return null;
+    }
+
+    private static AIOptimizedResponse<DiffSummaryResult> CreateGitErrorResponse(string code, string message) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo
+            {
+                Steps = new[]
+                {
+                    "Make sure the workspace is inside a git repository and git is on PATH",
+                    "Check that baseRef and headRef exist (git fetch for remote branches)",
+                    "Use headRef 'worktree' to summarize uncommitted changes"
+                }
+            }
+        }
+    };
+}
@@ -0,0 +1,195 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds commented-out code in the indexed files and blames it to tell when, by whom and why it was disabled
+/// </summary>
+public class FindCommentedCodeTool : CodeSearchToolBase<FindCommentedCodeParameters, AIOptimizedResponse<FindCommentedCodeResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindCommentedCodeTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindCommentedCodeTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files and symbols</param>
+    /// <param name="gitService">Git service for blaming the blocks</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindCommentedCodeTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindCommentedCodeTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindCommentedCode;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DELETE OR RESTORE? Finds blocks of commented-out code (not prose, doc comments or license headers), parses what each " +
+        "declares and calls, and blames it to report the commit that disabled it - who, when and the commit message. Blocks " +
+        "whose functions live code now declares, fragments that don't parse and blocks disabled longer than staleDays are " +
+        "recommended for deletion; the rest for review.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Scans the indexed files for commented-out code and assesses each block.
+    /// </summary>
+    /// <param name="parameters">Path and language filters, thresholds and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Commented-out blocks with their history and a recommendation</returns>
+    protected override async Task<AIOptimizedResponse<FindCommentedCodeResult>> ExecuteInternalAsync(
+        FindCommentedCodeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try find_commented_code again");
+        }
+
+        var history = parameters.IncludeHistory && await _gitService.IsRepositoryAsync(workspacePath, cancellationToken);
+        var declared = (await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken))
+            .Select(s => s.Name)
+            .ToHashSet(StringComparer.Ordinal);
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var blocks = new List<CommentedCodeBlock>();
+        var scanned = 0;
+        var unblamed = 0;
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var path = Relative(workspacePath, file.Path);
+            if (string.IsNullOrEmpty(file.Content) || !CommentedCode.Supports(path)
+                || !string.IsNullOrEmpty(parameters.Language) && !file.Language.Equals(parameters.Language, StringComparison.OrdinalIgnoreCase)
+                || !string.IsNullOrEmpty(scope) && !path.StartsWith(scope + "/", StringComparison.Ordinal))
+                continue;
+
+            scanned++;
+            var found = CommentedCode.Find(path, file.Content, parameters.MinLines);
+            if (found.Count == 0)
+                continue;
+
+            var blame = new List<BlameLine>();
+            if (history)
+            {
+                try
+                {
+                    blame = await _gitService.BlameAsync(workspacePath, "HEAD", path, found.Select(b => (b.StartLine, b.EndLine)), cancellationToken);
+                }
+                catch (GitException ex)
+                {
+                    // Untracked, or changed since HEAD so the lines no longer line up
+                    _logger.LogDebug(ex, "Blame failed for {FilePath}", path);
+                    unblamed++;
+                }
+            }
+
+            foreach (var block in found)
+            {
+                CommentedCode.Assess(block, declared, blame.Where(b => b.Line >= block.StartLine && b.Line <= block.EndLine).ToList(),
+                    parameters.StaleDays, DateTime.UtcNow);
+                blocks.Add(block);
+            }
+        }
+
+        var ordered = blocks
+            .OrderBy(b => b.Recommendation == "delete" ? 0 : 1)
+            .ThenByDescending(b => b.CodeLines)
+            .ThenBy(b => b.FilePath, StringComparer.Ordinal)
+            .ThenBy(b => b.StartLine)
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 500);
+
+        var result = new FindCommentedCodeResult
+        {
+            WorkspacePath = workspacePath,
+            Blocks = ordered.Take(maxResults).ToList(),
+            TotalBlocks = ordered.Count,
+            TotalCodeLines = ordered.Sum(b => b.CodeLines),
+            FilesScanned = scanned,
+            HistoryAvailable = history,
+            Truncated = ordered.Count > maxResults
+        };
+
+        _logger.LogDebug("find_commented_code: {Files} files scanned, {Blocks} blocks", scanned, ordered.Count);
+
+        var deletable = ordered.Count(b => b.Recommendation == "delete");
+        var response = new AIOptimizedResponse<FindCommentedCodeResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindCommentedCodeResult> { Results = result },
+            Message = ordered.Count == 0
+                ? $"No commented-out code of {parameters.MinLines}+ lines in {scanned} file(s)"
+                : $"{ordered.Count} commented-out block(s), {result.TotalCodeLines} line(s) of code; {deletable} recommended for deletion"
+        };
+
+        var insights = new List<string>();
+        var replaced = ordered.Where(b => b.DeclaredElsewhere.Count > 0).ToList();
+        if (replaced.Count > 0)
+        {
+            insights.Add($"{replaced.Count} block(s) declare functions live code declares again - compare before deleting: {string.Join(", ", replaced.SelectMany(b => b.DeclaredElsewhere).Distinct().Take(5))}");
+        }
+        if (!history && parameters.IncludeHistory)
+        {
+            insights.Add("The workspace is not a git repository - no disabling commits, and age plays no part in the recommendations");
+        }
+        if (unblamed > 0)
+        {
+            insights.Add($"{unblamed} file(s) could not be blamed at HEAD - untracked, or the block moved in uncommitted changes");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {ordered.Count} blocks - raise maxResults or minLines, or filter by path or language");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<FindCommentedCodeResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
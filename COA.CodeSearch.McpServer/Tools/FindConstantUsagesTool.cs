@@ -0,0 +1,173 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Constants;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Usages of a constant or enum member across files - by name, and as raw literals of the same value - for
+/// audits like "who still uses status code 3"
+/// </summary>
+public class FindConstantUsagesTool : CodeSearchToolBase<FindConstantUsagesParameters, AIOptimizedResponse<FindConstantUsagesResult>>
+{
+    private readonly IConstantUsageService _constantUsageService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindConstantUsagesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindConstantUsagesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="constantUsageService">Constant catalog and usage search</param>
+    /// <param name="sqliteService">SQLite symbol service for the index check</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindConstantUsagesTool(
+        IServiceProvider serviceProvider,
+        IConstantUsageService constantUsageService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindConstantUsagesTool> logger) : base(serviceProvider, logger)
+    {
+        _constantUsageService = constantUsageService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindConstantUsages;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHO STILL USES THIS VALUE? Finds a constant or enum member's definition and value (explicit, implicit enum numbering, Go iota), " +
+        "every use by name, and raw literals of the same value such as 'status == 3' - ranked by whether the line looks related. " +
+        "Search by name ('OrderStatus.Archived'), by value ('3'), or both. Use for audits before changing or retiring a value.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Looks up matching constants and collects their usages.
+    /// </summary>
+    /// <param name="parameters">Name and/or value, literal scan options and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Definitions, references by name and raw literal uses</returns>
+    protected override async Task<AIOptimizedResponse<FindConstantUsagesResult>> ExecuteInternalAsync(
+        FindConstantUsagesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        if (string.IsNullOrWhiteSpace(parameters.Name) && string.IsNullOrWhiteSpace(parameters.Value))
+        {
+            return CreateErrorResponse("MISSING_QUERY", "Give a constant name, a value, or both",
+                "Example: name 'OrderStatus.Archived'", "Example: value '3' to find every constant holding 3 and literal uses of it");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try find_constant_usages again");
+        }
+
+        var name = string.IsNullOrWhiteSpace(parameters.Name) ? null : parameters.Name.Trim();
+        var value = string.IsNullOrWhiteSpace(parameters.Value) ? null : parameters.Value.Trim();
+        var report = await _constantUsageService.FindUsagesAsync(workspacePath, name, value, parameters.IncludeLiterals,
+            parameters.FilePattern, Math.Clamp(parameters.MaxResults, 1, 2000), cancellationToken);
+
+        if (name != null && report.Definitions.Count == 0)
+        {
+            return CreateErrorResponse("CONSTANT_NOT_FOUND",
+                value == null ? $"No constant or enum member named '{name}' in the index" : $"No constant named '{name}' has the value {value}",
+                $"Check the name with symbol_search: {name[(name.LastIndexOf('.') + 1)..]}",
+                "Search by value alone to find constants holding it");
+        }
+
+        // Definitions come from the service's cached catalog, so they are copied rather than edited
+        var definitions = report.Definitions.Select(d => new ConstantDefinition
+        {
+            Name = d.Name,
+            Container = d.Container,
+            Kind = d.Kind,
+            Value = d.Value,
+            Initializer = d.Initializer,
+            FilePath = Relative(workspacePath, d.FilePath),
+            Line = d.Line,
+            SymbolId = d.SymbolId
+        }).ToList();
+        foreach (var usage in report.References.Concat(report.Literals))
+            usage.FilePath = Relative(workspacePath, usage.FilePath);
+
+        var result = new FindConstantUsagesResult
+        {
+            Definitions = definitions,
+            References = report.References,
+            Literals = report.Literals,
+            FilesScanned = report.FilesScanned,
+            Truncated = report.Truncated
+        };
+
+        _logger.LogDebug("find_constant_usages: {Definitions} definitions, {References} references, {Literals} literals",
+            result.Definitions.Count, result.References.Count, result.Literals.Count);
+
+        var likely = result.Literals.Count(l => l.Likely);
+        var response = new AIOptimizedResponse<FindConstantUsagesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindConstantUsagesResult> { Results = result },
+            Message = $"{result.Definitions.Count} definition(s), {result.References.Count} use(s) by name, " +
+                      $"{result.Literals.Count} raw literal(s) ({likely} likely related)"
+        };
+
+        var insights = new List<string>();
+        var unvalued = result.Definitions.Where(d => d.Value == null).Select(d => d.QualifiedName).ToList();
+        if (unvalued.Count > 0)
+        {
+            insights.Add($"No value could be read for {string.Join(", ", unvalued.Take(5))} - its initializer is not a literal or integer expression, so literals were not matched");
+        }
+        if (result.Literals.Count > likely && likely > 0)
+        {
+            insights.Add("Literals not marked likely share the value by coincidence more often than not - check them before changing anything");
+        }
+        if (value is "0" or "1" or "-1" && parameters.IncludeLiterals)
+        {
+            insights.Add("0, 1 and -1 appear everywhere; narrow with filePattern or rely on the likely flag");
+        }
+        if (result.Truncated)
+        {
+            insights.Add("Results were cut at maxResults - narrow with filePattern");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<FindConstantUsagesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,124 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Assets;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds non-code files with identical content by their hash in the asset inventory
+/// </summary>
+public class FindDuplicateAssetsTool : CodeSearchToolBase<FindDuplicateAssetsParameters, AIOptimizedResponse<FindDuplicateAssetsResult>>
+{
+    private readonly IAssetInventoryService _assetInventory;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindDuplicateAssetsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindDuplicateAssetsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="assetInventory">Asset inventory service holding sizes, hashes and references</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindDuplicateAssetsTool(
+        IServiceProvider serviceProvider,
+        IAssetInventoryService assetInventory,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindDuplicateAssetsTool> logger) : base(serviceProvider, logger)
+    {
+        _assetInventory = assetInventory;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindDuplicateAssets;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHICH ASSETS ARE COPIES? Groups images, fonts, media, documents, binaries and fixtures with identical content (SHA-256), " +
+        "largest waste first. Each group lists its copies most referenced first - keep the first, point the references of the " +
+        "others at it and delete them. Uses the asset inventory from the last index unless refresh is set.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Groups the workspace's assets by content hash.
+    /// </summary>
+    /// <param name="parameters">Path and kind filters, size threshold, refresh flag and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Duplicate groups with the bytes their extra copies take up</returns>
+    protected override async Task<AIOptimizedResponse<FindDuplicateAssetsResult>> ExecuteInternalAsync(
+        FindDuplicateAssetsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        var inventory = parameters.Refresh
+            ? await _assetInventory.RefreshAsync(workspacePath, cancellationToken)
+            : await _assetInventory.GetAsync(workspacePath, cancellationToken);
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var assets = inventory.Assets
+            .Where(a => a.SizeBytes >= parameters.MinSizeBytes)
+            .Where(a => string.IsNullOrEmpty(parameters.Kind) || a.Kind.Equals(parameters.Kind, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+        var groups = AssetInventoryService.FindDuplicates(assets)
+            .Where(g => string.IsNullOrEmpty(scope) || g.Copies.Any(c => c.Path.StartsWith(scope + "/", StringComparison.Ordinal)))
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 500);
+
+        var result = new FindDuplicateAssetsResult
+        {
+            WorkspacePath = workspacePath,
+            Groups = groups.Take(maxResults).ToList(),
+            TotalGroups = groups.Count,
+            TotalWastedBytes = groups.Sum(g => g.WastedBytes),
+            AssetsScanned = assets.Count,
+            InventoryBuiltAt = inventory.BuiltAt,
+            Truncated = groups.Count > maxResults
+        };
+
+        _logger.LogDebug("find_duplicate_assets: {Assets} assets, {Groups} duplicate groups", assets.Count, groups.Count);
+
+        var response = new AIOptimizedResponse<FindDuplicateAssetsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindDuplicateAssetsResult> { Results = result },
+            Message = groups.Count == 0
+                ? $"No duplicates among {assets.Count} asset(s)"
+                : $"{groups.Count} duplicate group(s), {groups.Sum(g => g.Copies.Count - 1)} extra copies taking {result.TotalWastedBytes / 1024.0:F1} KB"
+        };
+
+        var insights = new List<string>();
+        var unused = groups.Sum(g => g.Copies.Skip(1).Count(c => c.References == 0));
+        if (unused > 0)
+        {
+            insights.Add($"{unused} extra copies have no references - find_unreferenced_assets lists them for deletion");
+        }
+        if (!inventory.ReferencesScanned)
+        {
+            insights.Add("References were not looked up (the workspace had no symbol database) - copies are in path order; index the workspace and pass refresh: true");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {groups.Count} groups - filter by path or kind, raise minSizeBytes or maxResults");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+}
@@ -0,0 +1,228 @@
+using System.Text.Json;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.TypeExtraction;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Answers "which types implement this interface?" for Go, where satisfaction is implicit: method sets built
+/// from the type information indexed with each Go file - declared methods plus those promoted from embedded
+/// fields - are matched against the interface's methods by name and signature
+/// </summary>
+public class FindImplementationsTool : CodeSearchToolBase<FindImplementationsParameters, AIOptimizedResponse<FindImplementationsResult>>
+{
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindImplementationsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindImplementationsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="luceneIndexService">Lucene index service for the type information of Go files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindImplementationsTool(
+        IServiceProvider serviceProvider,
+        ILuceneIndexService luceneIndexService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindImplementationsTool> logger) : base(serviceProvider, logger)
+    {
+        _luceneIndexService = luceneIndexService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindImplementations;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHO IMPLEMENTS THIS? Go types whose method sets satisfy an interface - implicit satisfaction included, with methods promoted " +
+        "from embedded structs - plus the files defining those methods and whether only the pointer type implements it. " +
+        "Near misses (one method missing or with a different signature) are listed too. For explicit implements in other languages use graph_query 'implements:Name'.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Locates the interface and matches every Go type's method set against it.
+    /// </summary>
+    /// <param name="parameters">Interface name and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Implementing types and near misses</returns>
+    protected override async Task<AIOptimizedResponse<FindImplementationsResult>> ExecuteInternalAsync(
+        FindImplementationsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var name = ValidateRequired(parameters.Interface, nameof(parameters.Interface)).Trim();
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _luceneIndexService.IndexExistsAsync(workspacePath, cancellationToken))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try find_implementations again");
+        }
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
+
+        var files = await LoadGoFilesAsync(workspacePath, cancellationToken);
+        if (files.Count > 0 && files.All(f => f.Methods.Count == 0 && f.Types.All(t => t.Methods.Count == 0)))
+        {
+            return CreateErrorResponse("METHOD_SETS_NOT_INDEXED", "The Go files were indexed without method set information",
+                "Run mcp__codesearch__index_workspace with forceRebuild", "Try find_implementations again");
+        }
+
+        var target = FindInterface(name, files);
+        if (target == null)
+        {
+            return CreateErrorResponse("INTERFACE_NOT_FOUND", $"No Go interface named '{name}' in the index",
+                $"Check the name with symbol_search: {name[(name.LastIndexOf('.') + 1)..]}",
+                "For interfaces in other languages use graph_query 'implements:Name'");
+        }
+
+        var implementations = GoMethodSets.FindImplementations(target, files, out var unresolved);
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 500);
+        var result = new FindImplementationsResult
+        {
+            Interface = target.Name,
+            FilePath = Relative(workspacePath, target.FilePath),
+            Line = target.Line,
+            Methods = GoMethodSets.InterfaceMethodSet(target, files).Select(m => m.Name + m.Signature).ToList(),
+            UnresolvedEmbeds = unresolved,
+            FilesScanned = files.Count
+        };
+
+        foreach (var implementation in implementations.OrderBy(i => i.Type.FilePath, StringComparer.Ordinal).ThenBy(i => i.Type.Line))
+        {
+            var info = new ImplementationInfo
+            {
+                Type = implementation.PointerOnly ? "*" + implementation.Type.Name : implementation.Type.Name,
+                Kind = implementation.Type.Kind,
+                FilePath = Relative(workspacePath, implementation.Type.FilePath),
+                Line = implementation.Type.Line,
+                PointerOnly = implementation.PointerOnly,
+                MethodFiles = implementation.Methods.Where(m => m.Line > 0).Select(m => Relative(workspacePath, m.FilePath)).Distinct().ToList(),
+                Methods = implementation.Methods.Where(m => m.Line > 0).Select(m => $"{m.Name} ({Relative(workspacePath, m.FilePath)}:{m.Line})").ToList()
+            };
+
+            if (implementation.Missing.Count == 0)
+            {
+                if (result.Implementations.Count < maxResults)
+                    result.Implementations.Add(info);
+            }
+            else if (parameters.IncludeNearMisses && result.NearMisses.Count < maxResults)
+            {
+                info.Missing = implementation.Missing;
+                result.NearMisses.Add(info);
+            }
+        }
+
+        _logger.LogDebug("find_implementations found {Count} implementations of {Interface} in {Files} Go files",
+            result.Implementations.Count, target.Name, files.Count);
+
+        var response = new AIOptimizedResponse<FindImplementationsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindImplementationsResult> { Results = result },
+            Message = result.Methods.Count == 0
+                ? $"{target.Name} has no methods - every type implements it"
+                : result.Implementations.Count > 0
+                    ? $"{result.Implementations.Count} type(s) implement {target.Name}: {string.Join(", ", result.Implementations.Take(5).Select(i => i.Type))}"
+                    : $"No type implements {target.Name} ({result.Methods.Count} method(s))"
+        };
+
+        var insights = new List<string>();
+        if (result.NearMisses.Count > 0)
+        {
+            insights.Add($"Near misses: {string.Join(", ", result.NearMisses.Take(5).Select(n => $"{n.Type} lacks {string.Join(", ", n.Missing!)}"))} - a signature mismatch counts as missing");
+        }
+        if (result.Implementations.Any(i => i.PointerOnly))
+        {
+            insights.Add("Types shown as *T have pointer-receiver methods: only a pointer satisfies the interface");
+        }
+        if (unresolved.Count > 0)
+        {
+            insights.Add($"Embedded interfaces outside the index were not checked: {string.Join(", ", unresolved)}");
+        }
+        insights.Add("Matching is by method name and signature text with package qualifiers dropped; the go/types tier is not consulted");
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// Declarations of every indexed Go file, from the type information stored with it
+    /// </summary>
+    private async Task<List<GoFileDeclarations>> LoadGoFilesAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        var documentCount = await _luceneIndexService.GetDocumentCountAsync(workspacePath, cancellationToken);
+        var search = await _luceneIndexService.SearchAsync(workspacePath, new TermQuery(new Term("extension", ".go")),
+            Math.Max(documentCount, 1), cancellationToken);
+
+        var files = new List<GoFileDeclarations>();
+        foreach (var hit in search.Hits)
+        {
+            var typeInfoJson = hit.Fields.GetValueOrDefault("type_info");
+            if (string.IsNullOrEmpty(typeInfoJson))
+                continue;
+
+            try
+            {
+                var typeData = JsonSerializer.Deserialize<TypeExtractionResult>(typeInfoJson, TypeExtractionResult.DeserializationOptions);
+                if (typeData != null)
+                    files.Add(GoMethodSets.FromTypeInfo(hit.FilePath, typeData));
+            }
+            catch (JsonException ex)
+            {
+                _logger.LogDebug(ex, "Failed to parse type_info for {FilePath}", hit.FilePath);
+            }
+        }
+        return files;
+    }
+
+    /// <summary>
+    /// The interface declaration; a package qualifier narrows by directory
+    /// </summary>
+    private static GoTypeDeclaration? FindInterface(string name, List<GoFileDeclarations> files)
+    {
+        var bare = name[(name.LastIndexOf('.') + 1)..];
+        var interfaces = files.SelectMany(f => f.Types).Where(t => t.Kind == "interface" && t.Name == bare);
+        if (name.Contains('.'))
+        {
+            var qualifier = name[..name.LastIndexOf('.')].Replace('\\', '/');
+            interfaces = interfaces.Where(t => t.Package.EndsWith("/" + qualifier, StringComparison.Ordinal) || t.Package == qualifier);
+        }
+        return interfaces.OrderBy(t => t.FilePath, StringComparer.Ordinal).FirstOrDefault();
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<FindImplementationsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
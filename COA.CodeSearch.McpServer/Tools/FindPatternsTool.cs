@@ -8,6 +8,8 @@ using COA.Mcp.Framework.TokenOptimization.ResponseBuilders;
 using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Plugins;
 using COA.CodeSearch.McpServer.Services;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Julie;
@@ -27,6 +29,9 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
 {
     private readonly ISQLiteSymbolService? _sqliteService;
     private readonly IPathResolutionService _pathResolutionService;
+    private readonly IReadOnlyList<IPatternAnalyzerPlugin> _analyzerPlugins;
+    private readonly IFindingsBaselineService? _findingsBaseline;
+    private readonly IWebhookService? _webhooks;
     private readonly ILogger<FindPatternsTool> _logger;
 
     /// <summary>
@@ -36,14 +41,23 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
     /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
     /// <param name="logger">Logger instance</param>
     /// <param name="sqliteService">SQLite symbol service for symbol lookups</param>
+    /// <param name="analyzerPlugins">Analyzers contributed by plugins</param>
+    /// <param name="findingsBaseline">Baseline used to tell new findings from known ones</param>
+    /// <param name="webhooks">Webhook service notified about new findings</param>
     public FindPatternsTool(
         IServiceProvider serviceProvider,
         IPathResolutionService pathResolutionService,
         ILogger<FindPatternsTool> logger,
-        ISQLiteSymbolService? sqliteService = null) : base(serviceProvider, logger)
+        ISQLiteSymbolService? sqliteService = null,
+        IEnumerable<IPatternAnalyzerPlugin>? analyzerPlugins = null,
+        IFindingsBaselineService? findingsBaseline = null,
+        IWebhookService? webhooks = null) : base(serviceProvider, logger)
     {
         _sqliteService = sqliteService;
         _pathResolutionService = pathResolutionService;
+        _analyzerPlugins = analyzerPlugins?.ToList() ?? new List<IPatternAnalyzerPlugin>();
+        _findingsBaseline = findingsBaseline;
+        _webhooks = webhooks;
         _logger = logger;
     }
 
@@ -123,7 +137,7 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
             }
 
             // Detect patterns
-            var patterns = await DetectPatternsAsync(fileContent, symbols, language, parameters, cancellationToken);
+            var patterns = await DetectPatternsAsync(filePath, workspacePath, fileContent, symbols, language, parameters, cancellationToken);
 
             var result = new FindPatternsResult
             {
@@ -134,6 +148,8 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
                 AnalysisTime = DateTime.UtcNow
             };
 
+            result.NewSinceBaseline = ReportNewFindings(workspacePath, filePath, patterns);
+
             return CreateSuccessResponse(result);
         }
         catch (Exception ex)
@@ -146,7 +162,9 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
     /// <summary>
     /// Detects semantic patterns in the code based on SQLite symbols and file content.
     /// </summary>
-    private Task<List<CodePattern>> DetectPatternsAsync(
+    private async Task<List<CodePattern>> DetectPatternsAsync(
+        string filePath,
+        string workspacePath,
         string fileContent,
         List<JulieSymbol>? symbols,
         string? language,
@@ -198,6 +216,12 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
             patterns.AddRange(DetectCustomPatterns(fileContent, parameters.CustomPatterns, lines));
         }
 
+        // Pattern 8: Analyzers contributed by plugins
+        if (parameters.IncludePluginAnalyzers && _analyzerPlugins.Count > 0)
+        {
+            patterns.AddRange(await RunPluginAnalyzersAsync(filePath, workspacePath, fileContent, lines, symbols, language, cancellationToken));
+        }
+
         // Apply severity level filtering
         var filteredPatterns = patterns;
         if (parameters.SeverityLevels != null && parameters.SeverityLevels.Any())
@@ -211,7 +235,92 @@ public class FindPatternsTool : CodeSearchToolBase<FindPatternsParameters, AIOpt
             filteredPatterns = filteredPatterns.Take(parameters.MaxResults).ToList();
         }
 
-        return Task.FromResult(filteredPatterns.OrderBy(p => p.LineNumber).ToList());
+        return filteredPatterns.OrderBy(p => p.LineNumber).ToList();
+    }
+
+    /// <summary>
+    /// Compares findings with the file's baseline and publishes a findings.new event for any that are new.
+    /// Baselines are kept per pattern type, so analyses with different detector flags don't disturb each other;
+    /// the first analysis of a type in a file only establishes its baseline.
+    /// </summary>
+    private int ReportNewFindings(string workspacePath, string filePath, List<CodePattern> patterns)
+    {
+        if (_findingsBaseline == null || patterns.Count == 0)
+            return 0;
+
+        var newPatterns = new List<CodePattern>();
+        foreach (var group in patterns.GroupBy(p => p.Type))
+        {
+            var byFingerprint = group
+                .GroupBy(p => $"{p.Type}:{p.LineContent.Trim()}")
+                .ToDictionary(g => g.Key, g => g.First());
+            var added = _findingsBaseline.UpdateBaseline(workspacePath, $"patterns:{group.Key}", filePath,
+                byFingerprint.Keys, reportFirstSight: false);
+            newPatterns.AddRange(added.Select(f => byFingerprint[f]));
+        }
+        _findingsBaseline.Flush(workspacePath);
+
+        if (newPatterns.Count > 0)
+        {
+            var relativePath = Path.GetRelativePath(workspacePath, filePath);
+            _webhooks?.Publish(new WebhookEvent
+            {
+                Event = WebhookEvents.FindingsNew,
+                WorkspacePath = workspacePath,
+                Summary = $"{newPatterns.Count} new finding(s) in {relativePath}",
+                Data = new
+                {
+                    FilePath = relativePath,
+                    Findings = newPatterns.Select(p => new { p.Type, p.Severity, p.LineNumber, p.Message })
+                }
+            });
+        }
+
+        return newPatterns.Count;
+    }
+
+    /// <summary>
+    /// Runs plugin analyzers that support the file. A failing plugin is logged and skipped.
+    /// </summary>
+    private async Task<List<CodePattern>> RunPluginAnalyzersAsync(
+        string filePath,
+        string workspacePath,
+        string fileContent,
+        string[] lines,
+        List<JulieSymbol>? symbols,
+        string? language,
+        CancellationToken cancellationToken)
+    {
+        var patterns = new List<CodePattern>();
+        var extension = Path.GetExtension(filePath).ToLowerInvariant();
+        var context = new PatternAnalysisContext
+        {
+            FilePath = filePath,
+            WorkspacePath = workspacePath,
+            Content = fileContent,
+            Lines = lines,
+            Language = language,
+            Symbols = symbols
+        };
+
+        foreach (var analyzer in _analyzerPlugins.Where(a => a.Supports(language, extension)))
+        {
+            try
+            {
+                var found = await analyzer.AnalyzeAsync(context, cancellationToken);
+                foreach (var pattern in found)
+                {
+                    pattern.Metadata["analyzer"] = analyzer.Name;
+                    patterns.Add(pattern);
+                }
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogWarning(ex, "Analyzer plugin {Analyzer} failed for {FilePath}", analyzer.Name, filePath);
+            }
+        }
+
+        return patterns;
     }
 
     /// <summary>
@@ -13,7 +13,14 @@ using COA.Mcp.Framework.TokenOptimization.Storage;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.Services;
 using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Anchors;
+using COA.CodeSearch.McpServer.Services.GoTypes;
+using COA.CodeSearch.McpServer.Services.GraphQL;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
 using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Roslyn;
+using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.ResponseBuilders;
 using COA.Mcp.Framework.Interfaces;
 using Microsoft.Extensions.Logging;
@@ -39,6 +46,10 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
     private readonly ILogger<FindReferencesTool> _logger;
     private readonly CodeAnalyzer _codeAnalyzer;
     private readonly IReferenceResolverService? _referenceResolver;
+    private readonly IRoslynAnalysisService? _roslyn;
+    private readonly IGoTypesService? _goTypes;
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly IGraphQLIndexService? _graphQL;
     private const LuceneVersion LUCENE_VERSION = LuceneVersion.LUCENE_48;
 
     /// <summary>
@@ -54,6 +65,10 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
     /// <param name="codeAnalyzer">Code analysis service</param>
     /// <param name="referenceResolver">Reference resolver service for identifier lookup</param>
     /// <param name="logger">Logger instance</param>
+    /// <param name="roslyn">Optional Roslyn tier for compiler-resolved C# references</param>
+    /// <param name="goTypes">Optional go/types tier that confirms Go references from the index</param>
+    /// <param name="sqliteService">Optional SQLite symbol service for tracing generated names to .proto definitions</param>
+    /// <param name="graphQL">Optional GraphQL schema index linking schema fields and their resolvers</param>
     public FindReferencesTool(
         IServiceProvider serviceProvider,
         ILuceneIndexService luceneIndexService,
@@ -64,7 +79,11 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
         SmartQueryPreprocessor queryProcessor,
         CodeAnalyzer codeAnalyzer,
         IReferenceResolverService referenceResolver,
-        ILogger<FindReferencesTool> logger) : base(serviceProvider, logger)
+        ILogger<FindReferencesTool> logger,
+        IRoslynAnalysisService? roslyn = null,
+        IGoTypesService? goTypes = null,
+        ISQLiteSymbolService? sqliteService = null,
+        IGraphQLIndexService? graphQL = null) : base(serviceProvider, logger)
     {
         _luceneIndexService = luceneIndexService;
         _pathResolutionService = pathResolutionService;
@@ -74,6 +93,10 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
         _queryProcessor = queryProcessor;
         _codeAnalyzer = codeAnalyzer;
         _referenceResolver = referenceResolver;
+        _roslyn = roslyn;
+        _goTypes = goTypes;
+        _sqliteService = sqliteService;
+        _graphQL = graphQL;
         _logger = logger;
         _responseBuilder = new FindReferencesResponseBuilder(logger as ILogger<FindReferencesResponseBuilder>, storageService);
     }
@@ -115,12 +138,73 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
         CancellationToken cancellationToken)
     {
         // Validate required parameters
-        var symbolName = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol));
+        var symbolArgument = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol));
+
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return new AIOptimizedResponse<SearchResult>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "INVALID_COLUMN_ENCODING",
+                    Message = $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[] { "Use 'utf-16' for LSP positions or 'utf-8' for byte columns" }
+                    }
+                }
+            };
+        }
 
         // Use provided workspace path or default to current workspace
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
-            : Path.GetFullPath(parameters.WorkspacePath);
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
+
+        var symbolName = await ResolveSymbolArgumentAsync(symbolArgument, workspacePath, parameters.ColumnEncoding, cancellationToken);
+        if (symbolName == null && SymbolAnchor.TryParse(symbolArgument, out _))
+        {
+            return new AIOptimizedResponse<SearchResult>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "ANCHOR_NOT_FOUND",
+                    Message = $"Anchor '{symbolArgument}' no longer resolves to a symbol",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "The symbol was deleted, or moved and edited at the same time",
+                            "Use symbol_search to find it by name and store its new anchor"
+                        }
+                    }
+                }
+            };
+        }
+        if (symbolName == null)
+        {
+            return new AIOptimizedResponse<SearchResult>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "NO_SYMBOL_AT_POSITION",
+                    Message = $"No identifier at position '{symbolArgument}'",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Check the line is 1-based and the column 0-based",
+                            "Pass columnEncoding 'utf-8' if the column counts bytes",
+                            "Pass the symbol name instead"
+                        }
+                    }
+                }
+            };
+        }
         
         // Generate cache key
         var cacheKey = _keyGenerator.GenerateKey(Name, parameters);
@@ -140,6 +224,21 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
         {
             var stopwatch = System.Diagnostics.Stopwatch.StartNew();
 
+            // PRECISE: C# symbols resolved by the compiler, when the Roslyn tier is enabled and the solution loads
+            if (_roslyn is { IsEnabled: true })
+            {
+                var roslynResponse = await TryRoslynReferencesAsync(symbolArgument, symbolName, workspacePath, parameters, cancellationToken);
+                if (roslynResponse != null)
+                {
+                    _logger.LogInformation("✅ Found references using Roslyn ({Ms}ms)", stopwatch.ElapsedMilliseconds);
+                    if (!parameters.NoCache)
+                    {
+                        await _cacheService.SetAsync(cacheKey, roslynResponse);
+                    }
+                    return roslynResponse;
+                }
+            }
+
             // FAST-PATH: Try identifier-based reference finding first (LSP-quality, <10ms)
             if (_referenceResolver != null)
             {
@@ -151,30 +250,62 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
                         caseSensitive: parameters.CaseSensitive,
                         cancellationToken);
 
-                    if (resolvedRefs.Any())
+                    // Go and C# names protoc generated, or a .proto name: references to the other names count too
+                    var protoLinks = await FindProtoLinksAsync(workspacePath, symbolName, cancellationToken);
+
+                    // A GraphQL schema field and the resolvers serving it stand for each other
+                    var graphQLLinks = await FindGraphQLLinksAsync(workspacePath, symbolName, cancellationToken);
+
+                    // Terraform addresses (var.cluster_name) have no identifiers in the index; uses are found by address
+                    var terraformDefinitions = await FindTerraformDefinitionsAsync(workspacePath, symbolName, cancellationToken);
+
+                    // So are Dockerfile arguments, variables and stages ($PORT, --from=build)
+                    var dockerfileDefinitions = await FindDockerfileDefinitionsAsync(workspacePath, symbolName, cancellationToken);
+
+                    if (resolvedRefs.Any() || protoLinks.Definitions.Count > 0 || graphQLLinks.Count > 0 || terraformDefinitions.Count > 0
+                        || dockerfileDefinitions.Count > 0)
                     {
                         _logger.LogInformation("✅ Found {Count} references using identifier fast-path ({Ms}ms)",
                             resolvedRefs.Count, stopwatch.ElapsedMilliseconds);
 
                         // Convert ResolvedReferences to SearchHits
-                        var hits = resolvedRefs.Select(rr => new SearchHit
+                        var hits = resolvedRefs.Select(ToHit).ToList();
+
+                        // Tree-sitter matches Go identifiers by name; go/types knows which ones denote the symbol
+                        string? goTypesInsight = null;
+                        if (_goTypes is { IsEnabled: true } && hits.Any(h => _goTypes.Handles(h.FilePath)))
                         {
-                            FilePath = rr.Identifier.FilePath,
-                            StartLine = rr.Identifier.StartLine,
-                            Score = rr.Identifier.Confidence,
-                            Fields = new Dictionary<string, string>
-                            {
-                                ["kind"] = rr.Identifier.Kind,
-                                ["language"] = rr.Identifier.Language,
-                                ["referenceType"] = rr.Identifier.Kind, // call, member_access, etc.
-                                ["containedIn"] = rr.ContainingSymbol?.Name ?? "unknown",
-                                ["containedInKind"] = rr.ContainingSymbol?.Kind ?? "unknown",
-                                ["resolved"] = rr.IsResolved.ToString()
-                            },
-                            ContextLines = rr.Identifier.CodeContext != null
-                                ? rr.Identifier.CodeContext.Split('\n').ToList()
-                                : null
-                        }).ToList();
+                            goTypesInsight = await ValidateGoHitsAsync(hits, symbolArgument, symbolName, workspacePath, parameters, cancellationToken);
+                        }
+
+                        // Added after go/types, which only confirms references to the name asked about
+                        string? protoInsight = null;
+                        if (protoLinks.Definitions.Count > 0)
+                        {
+                            protoInsight = await AddProtoHitsAsync(hits, protoLinks, symbolName, workspacePath, cancellationToken);
+                        }
+                        string? graphQLInsight = null;
+                        if (graphQLLinks.Count > 0)
+                        {
+                            graphQLInsight = await AddGraphQLHitsAsync(hits, graphQLLinks, symbolName, workspacePath, cancellationToken);
+                        }
+                        string? terraformInsight = null;
+                        if (terraformDefinitions.Count > 0)
+                        {
+                            terraformInsight = await AddTerraformHitsAsync(hits, terraformDefinitions, symbolName, workspacePath, cancellationToken);
+                        }
+                        string? dockerfileInsight = null;
+                        if (dockerfileDefinitions.Count > 0)
+                        {
+                            dockerfileInsight = await AddDockerfileHitsAsync(hits, dockerfileDefinitions, symbolName, workspacePath, cancellationToken);
+                        }
+
+                        var positions = CreateSourcePositions(workspacePath);
+                        foreach (var hit in hits)
+                        {
+                            hit.Utf16Column = await positions.ToUtf16ColumnAsync(
+                                hit.FilePath, hit.StartLine!.Value, hit.Column!.Value, cancellationToken);
+                        }
 
                         var identifierSearchResult = new SearchResult
                         {
@@ -196,6 +327,23 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
                             identifierSearchResult,
                             responseContext);
 
+                        if (goTypesInsight != null || protoInsight != null || graphQLInsight != null || terraformInsight != null
+                            || dockerfileInsight != null)
+                        {
+                            var insights = identifierResponse.Insights?.ToList() ?? new List<string>();
+                            if (dockerfileInsight != null)
+                                insights.Insert(0, dockerfileInsight);
+                            if (terraformInsight != null)
+                                insights.Insert(0, terraformInsight);
+                            if (graphQLInsight != null)
+                                insights.Insert(0, graphQLInsight);
+                            if (protoInsight != null)
+                                insights.Insert(0, protoInsight);
+                            if (goTypesInsight != null)
+                                insights.Insert(0, goTypesInsight);
+                            identifierResponse.Insights = insights;
+                        }
+
                         // Cache the result
                         if (!parameters.NoCache && identifierResponse != null)
                         {
@@ -350,6 +498,636 @@ public class FindReferencesTool : CodeSearchToolBase<FindReferencesParameters, A
         }
     }
     
+    /// <summary>
+    /// References from the Roslyn tier: the symbol at a position, or every C# symbol declared with the name.
+    /// Returns null when the tier has no answer, so the index paths run as before.
+    /// </summary>
+    private async Task<AIOptimizedResponse<SearchResult>?> TryRoslynReferencesAsync(
+        string symbolArgument,
+        string symbolName,
+        string workspacePath,
+        FindReferencesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var positions = CreateSourcePositions(workspacePath);
+        RoslynReferenceResult? references;
+        try
+        {
+            if (SourcePositions.TryParseLocation(symbolArgument, out var filePath, out var line, out var column))
+            {
+                var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+                if (!_roslyn!.Handles(fullPath))
+                    return null;
+
+                var character = string.Equals(parameters.ColumnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+                    ? await positions.ToUtf16ColumnAsync(filePath, line, column, cancellationToken)
+                    : column;
+                references = await _roslyn.FindReferencesAsync(workspacePath, fullPath, new LspPosition(line - 1, character), cancellationToken);
+            }
+            else
+            {
+                references = await _roslyn!.FindReferencesByNameAsync(workspacePath, symbolName, parameters.CaseSensitive, cancellationToken);
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "Roslyn references failed for {Symbol}, using the index", symbolName);
+            return null;
+        }
+
+        if (references == null || references.Locations.Count == 0)
+            return null;
+
+        var hits = new List<SearchHit>();
+        foreach (var location in references.Locations.Take(parameters.MaxResults))
+        {
+            hits.Add(new SearchHit
+            {
+                FilePath = location.FilePath,
+                StartLine = location.Line,
+                Column = await positions.ToByteColumnAsync(location.FilePath, location.Line, location.Column, cancellationToken),
+                Utf16Column = location.Column,
+                Score = 1.0f,
+                Fields = new Dictionary<string, string>
+                {
+                    ["kind"] = location.ReferenceType,
+                    ["language"] = "csharp",
+                    ["referenceType"] = location.ReferenceType,
+                    ["containedIn"] = location.ContainedIn ?? "unknown",
+                    ["resolved"] = bool.TrueString,
+                    ["source"] = "roslyn"
+                },
+                ContextLines = location.LineText != null ? new List<string> { location.LineText } : null
+            });
+        }
+
+        var response = await _responseBuilder.BuildResponseAsync(
+            new SearchResult { Hits = hits, TotalHits = references.Locations.Count, Query = symbolName },
+            new ResponseContext
+            {
+                TokenLimit = parameters.MaxTokens,
+                ResponseMode = "adaptive",
+                ToolName = Name,
+                StoreFullResults = false
+            });
+
+        response.Meta ??= new AIResponseMeta();
+        response.Meta.ExtensionData ??= new Dictionary<string, object>();
+        response.Meta.ExtensionData["source"] = "roslyn";
+        response.Meta.ExtensionData["symbol"] = references.SymbolDisplay;
+
+        var insights = response.Insights?.ToList() ?? new List<string>();
+        insights.Insert(0, $"🎯 Roslyn resolved {references.SymbolKind} {references.SymbolDisplay}: compiler-bound references only, " +
+                           "same-named symbols and text matches excluded");
+        if (references.SymbolCount > 1)
+        {
+            insights.Insert(1, $"{references.SymbolCount} C# symbols are named '{symbolName}' (overloads or separate types); " +
+                               "all are included - pass a position 'path:line:column' to pick one");
+        }
+        response.Insights = insights;
+        return response;
+    }
+
+    private static SearchHit ToHit(ResolvedReference rr) => new()
+    {
+        FilePath = rr.Identifier.FilePath,
+        StartLine = rr.Identifier.StartLine,
+        Column = rr.Identifier.StartColumn,
+        ByteOffset = rr.Identifier.StartByte,
+        Score = rr.Identifier.Confidence,
+        Fields = new Dictionary<string, string>
+        {
+            ["kind"] = rr.Identifier.Kind,
+            ["language"] = rr.Identifier.Language,
+            ["referenceType"] = rr.Identifier.Kind, // call, member_access, etc.
+            ["containedIn"] = rr.ContainingSymbol?.Name ?? "unknown",
+            ["containedInKind"] = rr.ContainingSymbol?.Kind ?? "unknown",
+            ["resolved"] = rr.IsResolved.ToString()
+        },
+        ContextLines = rr.Identifier.CodeContext != null
+            ? rr.Identifier.CodeContext.Split('\n').ToList()
+            : null
+    };
+
+    /// <summary>
+    /// The .proto declarations a name is, or was generated from by protoc, and the other Go and C# names
+    /// generated from them: GetUserId leads to the user_id field, and from it to UserId
+    /// </summary>
+    private async Task<(List<JulieSymbol> Definitions, List<(string Language, string Name)> Generated)> FindProtoLinksAsync(
+        string workspacePath,
+        string symbolName,
+        CancellationToken cancellationToken)
+    {
+        var definitions = new List<JulieSymbol>();
+        var generated = new List<(string Language, string Name)>();
+        if (_sqliteService == null || !_sqliteService.DatabaseExists(workspacePath))
+            return (definitions, generated);
+
+        try
+        {
+            foreach (var candidate in ProtoSymbols.CandidateProtoNames(symbolName))
+            {
+                foreach (var symbol in await _sqliteService.GetSymbolsByNameAsync(workspacePath, candidate, caseSensitive: true, cancellationToken))
+                {
+                    if (symbol.Language != "protobuf" || definitions.Any(d => d.Id == symbol.Id))
+                        continue;
+
+                    // Generated names depend on the messages a declaration is nested in
+                    var fileSymbols = await _sqliteService.GetSymbolsForFileAsync(workspacePath, symbol.FilePath, cancellationToken);
+                    var containers = new List<string>();
+                    for (var parent = fileSymbols.FirstOrDefault(s => s.Id == symbol.ParentId);
+                         parent != null;
+                         parent = fileSymbols.FirstOrDefault(s => s.Id == parent.ParentId))
+                        containers.Insert(0, parent.Name);
+
+                    var names = ProtoSymbols.GeneratedNames(symbol.Kind, symbol.Name, containers);
+                    if (symbol.Name != symbolName && names.All(n => n.Name != symbolName))
+                        continue;
+
+                    definitions.Add(symbol);
+                    generated.AddRange(names.Where(n => n.Name != symbolName && !generated.Contains(n)));
+                }
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "Protobuf lookup failed for {Symbol}", symbolName);
+        }
+        return (definitions, generated);
+    }
+
+    /// <summary>
+    /// Adds the .proto definitions, the .proto fields and RPCs using a message or enum, and the references to the
+    /// other generated names - in Go or C# files only, as the generator emits them - to the hits
+    /// </summary>
+    private async Task<string> AddProtoHitsAsync(
+        List<SearchHit> hits,
+        (List<JulieSymbol> Definitions, List<(string Language, string Name)> Generated) links,
+        string symbolName,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        var seen = hits.Select(h => $"{h.FilePath}:{h.StartLine}:{h.Column}").ToHashSet(StringComparer.Ordinal);
+        var added = 0;
+        void Add(SearchHit hit)
+        {
+            if (seen.Add($"{hit.FilePath}:{hit.StartLine}:{hit.Column}"))
+            {
+                hits.Add(hit);
+                added++;
+            }
+        }
+
+        SearchHit ProtoHit(string filePath, int line, int column, string referenceType, string containedIn, string context) => new()
+        {
+            FilePath = filePath,
+            StartLine = line,
+            Column = column,
+            Score = 1.0f,
+            Fields = new Dictionary<string, string>
+            {
+                ["kind"] = referenceType,
+                ["language"] = "protobuf",
+                ["referenceType"] = referenceType,
+                ["containedIn"] = containedIn,
+                ["resolved"] = bool.TrueString
+            },
+            ContextLines = new List<string> { context }
+        };
+
+        foreach (var definition in links.Definitions)
+        {
+            Add(ProtoHit(definition.FilePath, definition.StartLine, definition.StartColumn, "definition", definition.Name, definition.Signature ?? definition.Name));
+        }
+
+        var types = links.Definitions.Where(d => d.Kind is "class" or "enum").Select(d => d.Name).Distinct().ToList();
+        if (types.Count > 0)
+        {
+            foreach (var file in await _sqliteService!.GetAllFilesAsync(workspacePath, cancellationToken))
+            {
+                if (!file.Path.EndsWith(".proto", StringComparison.OrdinalIgnoreCase) || string.IsNullOrEmpty(file.Content))
+                    continue;
+
+                var lines = file.Content.Replace("\r\n", "\n").Split('\n');
+                foreach (var type in types)
+                {
+                    foreach (var line in ProtoSymbols.TypeUses(file.Content, type))
+                    {
+                        var column = Regex.Match(lines[line - 1], $@"\b{Regex.Escape(type)}\b").Index;
+                        Add(ProtoHit(file.Path, line, column, "type_usage", type, lines[line - 1].Trim()));
+                    }
+                }
+            }
+        }
+
+        foreach (var (language, name) in links.Generated)
+        {
+            foreach (var reference in await _referenceResolver!.FindReferencesAsync(workspacePath, name, caseSensitive: true, cancellationToken))
+            {
+                if (reference.Identifier.Language != language)
+                    continue;
+
+                var hit = ToHit(reference);
+                hit.Fields["generatedName"] = name;
+                Add(hit);
+            }
+        }
+
+        var files = links.Definitions.Select(d => Path.GetFileName(d.FilePath)).Distinct();
+        return $"'{symbolName}' comes from {string.Join(", ", links.Definitions.Select(d => d.Name).Distinct())} in {string.Join(", ", files)}: " +
+               $"added {added} hit(s) from the .proto definition and the other generated names " +
+               $"({string.Join(", ", links.Generated.Select(g => g.Name).Distinct().Take(6))}) - generated names are matched by name, check before renaming";
+    }
+
+    /// <summary>
+    /// The GraphQL schema fields a name is - a field with resolvers - or resolves: GetUserAsync leads to
+    /// Query.user. Fields only a model member resolves by default are left out, as their names are everywhere.
+    /// </summary>
+    private async Task<List<(GraphQLType Type, GraphQLField Field)>> FindGraphQLLinksAsync(
+        string workspacePath,
+        string symbolName,
+        CancellationToken cancellationToken)
+    {
+        var links = new List<(GraphQLType, GraphQLField)>();
+        if (_graphQL == null || _sqliteService == null || !_sqliteService.DatabaseExists(workspacePath))
+            return links;
+
+        try
+        {
+            var index = await _graphQL.GetIndexAsync(workspacePath, cancellationToken);
+            foreach (var type in index.Types)
+            {
+                foreach (var field in type.Fields)
+                {
+                    var resolvers = field.Resolvers.Where(r => r.Kind == "resolver").ToList();
+                    if (resolvers.Count == 0)
+                        continue;
+                    if (field.Name == symbolName
+                        || resolvers.Any(r => r.Name == symbolName || r.Name.EndsWith("." + symbolName, StringComparison.Ordinal)))
+                        links.Add((type, field));
+                }
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "GraphQL lookup failed for {Symbol}", symbolName);
+        }
+        return links;
+    }
+
+    /// <summary>
+    /// Adds the schema definitions of the linked fields and the resolvers serving them to the hits
+    /// </summary>
+    private async Task<string> AddGraphQLHitsAsync(
+        List<SearchHit> hits,
+        List<(GraphQLType Type, GraphQLField Field)> links,
+        string symbolName,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        var seen = hits.Select(h => $"{h.FilePath}:{h.StartLine}").ToHashSet(StringComparer.Ordinal);
+        var added = 0;
+        async Task AddAsync(string relativePath, int line, string name, string referenceType, string containedIn, string language)
+        {
+            var filePath = Path.GetFullPath(Path.Combine(workspacePath, relativePath));
+            if (!seen.Add($"{filePath}:{line}"))
+                return;
+
+            var text = File.Exists(filePath) ? (await File.ReadAllLinesAsync(filePath, cancellationToken)).ElementAtOrDefault(line - 1) ?? string.Empty : string.Empty;
+            var shortName = name[(name.LastIndexOf('.') + 1)..];
+            var column = Regex.Match(text, $@"\b{Regex.Escape(shortName)}\b") is { Success: true } found ? found.Index : 0;
+            hits.Add(new SearchHit
+            {
+                FilePath = filePath,
+                StartLine = line,
+                Column = column,
+                Score = 1.0f,
+                Fields = new Dictionary<string, string>
+                {
+                    ["kind"] = referenceType,
+                    ["language"] = language,
+                    ["referenceType"] = referenceType,
+                    ["containedIn"] = containedIn,
+                    ["resolved"] = bool.TrueString
+                },
+                ContextLines = new List<string> { text.Trim() }
+            });
+            added++;
+        }
+
+        foreach (var (type, field) in links)
+        {
+            await AddAsync(field.FilePath, field.Line, field.Name, "definition", type.Name, "graphql");
+            foreach (var resolver in field.Resolvers.Where(r => r.Kind == "resolver"))
+            {
+                await AddAsync(resolver.FilePath, resolver.Line, resolver.Name, "resolver", $"{type.Name}.{field.Name}",
+                    LanguageCapabilities.Find(Path.GetExtension(resolver.FilePath))?.Name ?? "unknown");
+            }
+        }
+
+        return $"'{symbolName}' is the GraphQL field {string.Join(", ", links.Select(l => $"{l.Type.Name}.{l.Field.Name}").Distinct())}: " +
+               $"added {added} hit(s) from the schema definition and its resolvers - rename the field and its resolvers together";
+    }
+
+    /// <summary>
+    /// The Terraform declarations a name or address as written in an expression denotes: var.cluster_name,
+    /// aws_eks_cluster.main.endpoint (the resource), module.network.vpc_id (the module and its output)
+    /// </summary>
+    private async Task<List<JulieSymbol>> FindTerraformDefinitionsAsync(
+        string workspacePath,
+        string symbolName,
+        CancellationToken cancellationToken)
+    {
+        var definitions = new List<JulieSymbol>();
+        if (_sqliteService == null || !_sqliteService.DatabaseExists(workspacePath))
+            return definitions;
+
+        try
+        {
+            foreach (var candidate in TerraformSymbols.CandidateNames(symbolName))
+            {
+                foreach (var symbol in await _sqliteService.GetSymbolsByNameAsync(workspacePath, candidate, caseSensitive: true, cancellationToken))
+                {
+                    if (symbol.Language != "terraform" || TerraformSymbols.Address(symbol) is not { } address || definitions.Any(d => d.Id == symbol.Id))
+                        continue;
+
+                    var written = $"^{Regex.Escape(address).Replace(@"\*", @"[\w-]+")}(?:[.\[].+)?$";
+                    if (symbol.Name == symbolName || Regex.IsMatch(symbolName, written))
+                        definitions.Add(symbol);
+                }
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "Terraform lookup failed for {Symbol}", symbolName);
+        }
+        return definitions;
+    }
+
+    /// <summary>
+    /// Adds the Terraform definitions and the uses of their addresses - in the .tf and .tfvars files of the
+    /// declaring module, or in any module for outputs, which calling modules read - to the hits
+    /// </summary>
+    private async Task<string> AddTerraformHitsAsync(
+        List<SearchHit> hits,
+        List<JulieSymbol> definitions,
+        string symbolName,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        var seen = hits.Select(h => $"{h.FilePath}:{h.StartLine}:{h.Column}").ToHashSet(StringComparer.Ordinal);
+        var added = 0;
+        void Add(string filePath, int line, int column, string referenceType, string containedIn, string context)
+        {
+            if (!seen.Add($"{filePath}:{line}:{column}"))
+                return;
+
+            hits.Add(new SearchHit
+            {
+                FilePath = filePath,
+                StartLine = line,
+                Column = column,
+                Score = 1.0f,
+                Fields = new Dictionary<string, string>
+                {
+                    ["kind"] = referenceType,
+                    ["language"] = "terraform",
+                    ["referenceType"] = referenceType,
+                    ["containedIn"] = containedIn,
+                    ["resolved"] = bool.TrueString
+                },
+                ContextLines = new List<string> { context }
+            });
+            added++;
+        }
+
+        foreach (var definition in definitions)
+        {
+            Add(definition.FilePath, definition.StartLine, definition.StartColumn, "definition", definition.Name, definition.Signature ?? definition.Name);
+        }
+
+        var files = (await _sqliteService!.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Where(f => TerraformSymbols.CanReference(f.Path) && !string.IsNullOrEmpty(f.Content))
+            .ToList();
+        foreach (var definition in definitions)
+        {
+            var address = TerraformSymbols.Address(definition)!;
+            var module = Path.GetDirectoryName(definition.FilePath);
+            foreach (var file in files)
+            {
+                // Addresses are scoped to the module (directory) declaring them; outputs are read from outside it
+                if (!address.StartsWith("module.*.", StringComparison.Ordinal) && Path.GetDirectoryName(file.Path) != module)
+                    continue;
+
+                var uses = TerraformSymbols.References(file.Path, file.Content!, address);
+                if (uses.Count == 0)
+                    continue;
+
+                var lines = file.Content!.Replace("\r\n", "\n").Split('\n');
+                var blocks = TerraformSymbols.Handles(file.Path) ? TerraformSymbols.Extract(file.Path, file.Content!) : new List<JulieSymbol>();
+                foreach (var (line, column) in uses)
+                {
+                    var containedIn = blocks.LastOrDefault(b => b.StartLine <= line && b.EndLine >= line)?.Name ?? Path.GetFileName(file.Path);
+                    Add(file.Path, line, column, "reference", containedIn, lines[line - 1].Trim());
+                }
+            }
+        }
+
+        var declared = definitions.Select(d => $"{d.Signature} in {Path.GetFileName(d.FilePath)}").Distinct();
+        return $"'{symbolName}' is the Terraform {string.Join(", ", declared)}: added {added} hit(s) found by address " +
+               $"({string.Join(", ", definitions.Select(TerraformSymbols.Address).Distinct())}) - indexed uses such as aws_instance.web[0] count as uses of the whole resource";
+    }
+
+    /// <summary>
+    /// The Dockerfile build arguments, environment variables and stages named <paramref name="symbolName"/>;
+    /// a leading $ or ${...} as written in an instruction is accepted
+    /// </summary>
+    private async Task<List<JulieSymbol>> FindDockerfileDefinitionsAsync(
+        string workspacePath,
+        string symbolName,
+        CancellationToken cancellationToken)
+    {
+        var definitions = new List<JulieSymbol>();
+        if (_sqliteService == null || !_sqliteService.DatabaseExists(workspacePath))
+            return definitions;
+
+        var name = symbolName.TrimStart('$').Trim('{', '}');
+        try
+        {
+            foreach (var symbol in await _sqliteService.GetSymbolsByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken))
+            {
+                if (symbol.Language == "dockerfile" && symbol.Kind is "variable" or "constant" or "module")
+                    definitions.Add(symbol);
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogDebug(ex, "Dockerfile lookup failed for {Symbol}", symbolName);
+        }
+        return definitions;
+    }
+
+    /// <summary>
+    /// Adds the Dockerfile definitions and their expansions or stage uses in the declaring Dockerfile to the hits.
+    /// Arguments and variables do not cross files: another Dockerfile's $PORT is another variable.
+    /// </summary>
+    private async Task<string> AddDockerfileHitsAsync(
+        List<SearchHit> hits,
+        List<JulieSymbol> definitions,
+        string symbolName,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        var seen = hits.Select(h => $"{h.FilePath}:{h.StartLine}:{h.Column}").ToHashSet(StringComparer.Ordinal);
+        var added = 0;
+        void Add(string filePath, int line, int column, string referenceType, string containedIn, string context)
+        {
+            if (!seen.Add($"{filePath}:{line}:{column}"))
+                return;
+
+            hits.Add(new SearchHit
+            {
+                FilePath = filePath,
+                StartLine = line,
+                Column = column,
+                Score = 1.0f,
+                Fields = new Dictionary<string, string>
+                {
+                    ["kind"] = referenceType,
+                    ["language"] = "dockerfile",
+                    ["referenceType"] = referenceType,
+                    ["containedIn"] = containedIn,
+                    ["resolved"] = bool.TrueString
+                },
+                ContextLines = new List<string> { context }
+            });
+            added++;
+        }
+
+        foreach (var definition in definitions)
+        {
+            Add(definition.FilePath, definition.StartLine, definition.StartColumn, "definition", definition.Name, definition.Signature ?? definition.Name);
+        }
+
+        var files = (await _sqliteService!.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Where(f => !string.IsNullOrEmpty(f.Content) && definitions.Any(d => d.FilePath == f.Path))
+            .ToList();
+        foreach (var file in files)
+        {
+            var lines = file.Content!.Replace("\r\n", "\n").Split('\n');
+            var stages = DockerfileSymbols.Extract(file.Path, file.Content!).Where(s => s.Kind == "module").ToList();
+            foreach (var (line, column) in DockerfileSymbols.References(file.Content!, definitions[0].Name))
+            {
+                var containedIn = stages.LastOrDefault(s => s.StartLine <= line && s.EndLine >= line)?.Name ?? Path.GetFileName(file.Path);
+                Add(file.Path, line, column, "reference", containedIn, lines[line - 1].Trim());
+            }
+        }
+
+        var declared = definitions.Select(d => $"{d.Signature} in {Path.GetFileName(d.FilePath)}").Distinct();
+        return $"'{symbolName}' is the Dockerfile {string.Join(", ", declared)}: added {added} hit(s) from $-expansions and stage uses " +
+               "in the declaring Dockerfile - values passed with --build-arg or docker run -e are not searched";
+    }
+
+    /// <summary>
+    /// Replaces the Go hits with the identifiers go/types resolves to the symbol: hits that only share the name
+    /// are dropped and ones the index missed are added. Non-Go hits are left alone. Returns an insight line,
+    /// or null when the helper has no answer and the hits stay as they were.
+    /// </summary>
+    private async Task<string?> ValidateGoHitsAsync(
+        List<SearchHit> hits,
+        string symbolArgument,
+        string symbolName,
+        string workspacePath,
+        FindReferencesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        GoReferenceResult? references;
+        try
+        {
+            if (SourcePositions.TryParseLocation(symbolArgument, out var filePath, out var line, out var column))
+            {
+                var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+                if (!_goTypes!.Handles(fullPath))
+                    return null;
+
+                var byteColumn = string.Equals(parameters.ColumnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+                    ? column
+                    : await CreateSourcePositions(workspacePath).ToByteColumnAsync(filePath, line, column, cancellationToken);
+                references = await _goTypes.FindReferencesAsync(workspacePath, fullPath, line, byteColumn, cancellationToken);
+            }
+            else
+            {
+                references = await _goTypes!.FindReferencesByNameAsync(workspacePath, symbolName, cancellationToken);
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "go/types references failed for {Symbol}, keeping the index results", symbolName);
+            return null;
+        }
+
+        if (references == null)
+            return null;
+
+        string Key(string file, int line, int column) =>
+            $"{Path.GetFullPath(Path.IsPathRooted(file) ? file : Path.Combine(workspacePath, file))}:{line}:{column}";
+
+        var confirmed = references.Locations
+            .GroupBy(l => Key(l.File, l.Line, l.Column), StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => g.First(), StringComparer.Ordinal);
+
+        var seen = new HashSet<string>(StringComparer.Ordinal);
+        var removed = hits.RemoveAll(hit =>
+        {
+            if (!_goTypes!.Handles(hit.FilePath))
+                return false;
+            var key = Key(hit.FilePath, hit.StartLine ?? 0, hit.Column ?? 0);
+            if (!confirmed.ContainsKey(key))
+                return true;
+            hit.Fields["resolved"] = bool.TrueString;
+            hit.Fields["source"] = "go/types";
+            return !seen.Add(key);
+        });
+        var kept = seen.Count;
+
+        var added = 0;
+        foreach (var (key, location) in confirmed)
+        {
+            if (seen.Contains(key))
+                continue;
+            hits.Add(new SearchHit
+            {
+                FilePath = location.File,
+                StartLine = location.Line,
+                Column = location.Column,
+                ByteOffset = location.Offset,
+                Score = 1.0f,
+                Fields = new Dictionary<string, string>
+                {
+                    ["kind"] = location.IsDefinition ? "definition" : "identifier",
+                    ["language"] = "go",
+                    ["referenceType"] = location.IsDefinition ? "definition" : "identifier",
+                    ["containedIn"] = location.ContainedIn ?? "unknown",
+                    ["resolved"] = bool.TrueString,
+                    ["source"] = "go/types"
+                },
+                ContextLines = location.LineText != null ? new List<string> { location.LineText } : null
+            });
+            added++;
+        }
+
+        var insight = $"🎯 go/types confirmed {kept} Go reference(s) to {references.Kind} {references.Symbol}";
+        if (removed > 0)
+            insight += $", dropped {removed} that only share the name";
+        if (added > 0)
+            insight += $", added {added} the index missed";
+        if (references.SymbolCount > 1)
+            insight += $"; {references.SymbolCount} Go symbols are named '{symbolName}' - pass a position 'path:line:column' to pick one";
+        if (references.TypeErrors > 0)
+            insight += $"; {references.TypeErrors} package(s) have type errors, so some references may be missing";
+        return insight;
+    }
+
     private string BuildReferenceQueryString(string symbolName)
     {
         // Build a query that looks for various usage patterns
@@ -0,0 +1,396 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Lucene.Net.Index;
+using Lucene.Net.Search;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds functions that resemble a snippet or an existing symbol. Candidate files come from the
+/// Lucene index, their functions are compared by TF-IDF token vectors, and symbol embeddings are
+/// added when semantic search is available.
+/// </summary>
+public class FindSimilarCodeTool : CodeSearchToolBase<FindSimilarCodeParameters, AIOptimizedResponse<FindSimilarCodeResult>>
+{
+    private const int CandidateFileLimit = 100;
+    private const int MaxEmbeddingQueryLength = 2000;
+
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILogger<FindSimilarCodeTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindSimilarCodeTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="luceneIndexService">Lucene index service for finding candidate files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="sqliteService">Optional symbol database for locating symbols and embedding search</param>
+    public FindSimilarCodeTool(
+        IServiceProvider serviceProvider,
+        ILuceneIndexService luceneIndexService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindSimilarCodeTool> logger,
+        ISQLiteSymbolService? sqliteService = null) : base(serviceProvider, logger)
+    {
+        _luceneIndexService = luceneIndexService;
+        _pathResolutionService = pathResolutionService;
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindSimilarCode;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DO WE ALREADY HAVE THIS? - Functions elsewhere in the workspace that resemble a code snippet or an existing symbol, " +
+        "ranked by similarity with the shared terms. Use BEFORE writing a new helper, or to find duplicated logic worth consolidating.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Finds similar functions.
+    /// </summary>
+    /// <param name="parameters">Snippet or symbol, and thresholds</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Similar functions, most similar first</returns>
+    protected override async Task<AIOptimizedResponse<FindSimilarCodeResult>> ExecuteInternalAsync(
+        FindSimilarCodeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
+
+        if (string.IsNullOrWhiteSpace(parameters.Snippet) && string.IsNullOrWhiteSpace(parameters.Symbol))
+        {
+            return CreateErrorResponse("MISSING_SOURCE", "Either snippet or symbol is required",
+                "Pass the code you are about to write as snippet", "Or pass the name of an existing function as symbol");
+        }
+
+        var method = parameters.Method.ToLowerInvariant();
+        if (method is not ("auto" or "tokens" or "embedding"))
+        {
+            return CreateErrorResponse("INVALID_METHOD", $"Unknown method '{parameters.Method}'", "Use 'auto', 'tokens' or 'embedding'");
+        }
+
+        var embeddingsAvailable = _sqliteService?.IsSemanticSearchAvailable() == true && _sqliteService.DatabaseExists(workspacePath);
+        if (method == "embedding" && !embeddingsAvailable)
+        {
+            return CreateErrorResponse("EMBEDDINGS_UNAVAILABLE", "Semantic search is not available for this workspace",
+                "Use method 'tokens' (or 'auto')", "Embeddings need the sqlite-vec extension and the embedding model");
+        }
+
+        if (!await _luceneIndexService.IndexExistsAsync(workspacePath, cancellationToken))
+        {
+            return CreateErrorResponse("INDEX_NOT_FOUND", $"No index found for workspace: {workspacePath}",
+                "Run index_workspace first", "Then retry find_similar_code");
+        }
+
+        var result = new FindSimilarCodeResult { Source = "snippet" };
+        string sourceCode;
+        string? sourceName = null;
+        string? sourcePath = null;
+        if (!string.IsNullOrWhiteSpace(parameters.Snippet))
+        {
+            sourceCode = parameters.Snippet;
+        }
+        else
+        {
+            var source = await FindSourceAsync(workspacePath, parameters.Symbol!.Trim(), parameters.FilePath, cancellationToken);
+            if (source == null)
+            {
+                return CreateErrorResponse("SYMBOL_NOT_FOUND", $"No function or method named '{parameters.Symbol}' found",
+                    "Check the spelling with symbol_search", "Or pass the code as snippet instead");
+            }
+
+            (sourcePath, var declaration) = source.Value;
+            sourceCode = declaration.Body;
+            sourceName = declaration.Name;
+            result.Source = declaration.QualifiedName;
+            result.SourceFile = ToRelative(workspacePath, sourcePath);
+            result.SourceLine = declaration.Line;
+        }
+
+        var sourceVector = CodeSimilarity.Vectorize(sourceCode, new[] { sourceName });
+        var candidates = new List<(string Path, Declaration Declaration, Dictionary<string, int> Vector)>();
+        var unsupportedFiles = 0;
+
+        if (method != "embedding")
+        {
+            if (sourceVector.Values.Sum() < CodeSimilarity.MinTokens && !embeddingsAvailable)
+            {
+                return CreateErrorResponse("SOURCE_TOO_SHORT", "The code is too short to compare (fewer than 6 meaningful words)",
+                    "Pass the whole function body, not a single line");
+            }
+
+            var terms = CodeSimilarity.DistinctiveTerms(sourceVector);
+            if (terms.Count > 0)
+            {
+                var query = new BooleanQuery();
+                foreach (var term in terms)
+                {
+                    query.Add(new TermQuery(new Term("content", term)), Occur.SHOULD);
+                }
+                query.MinimumNumberShouldMatch = Math.Max(1, terms.Count / 3);
+
+                var hits = await _luceneIndexService.SearchAsync(workspacePath, query, CandidateFileLimit, cancellationToken);
+                result.CandidateFiles = hits.Hits.Count;
+                foreach (var hit in hits.Hits)
+                {
+                    if (!DeclarationScanner.Supports(hit.FilePath))
+                    {
+                        unsupportedFiles++;
+                        continue;
+                    }
+
+                    var content = hit.Fields.GetValueOrDefault("content");
+                    if (string.IsNullOrEmpty(content))
+                        continue;
+
+                    foreach (var declaration in DeclarationScanner.Scan(content, hit.FilePath))
+                    {
+                        if (declaration.IsType || declaration.Kind == "property")
+                            continue;
+                        if (sourcePath != null && PathsEqual(hit.FilePath, sourcePath) && declaration.Line == result.SourceLine)
+                            continue;
+
+                        var vector = CodeSimilarity.Vectorize(declaration.Body, new[] { declaration.Name });
+                        if (vector.Values.Sum() >= CodeSimilarity.MinTokens)
+                        {
+                            candidates.Add((hit.FilePath, declaration, vector));
+                        }
+                    }
+                }
+            }
+
+            result.CandidateFunctions = candidates.Count;
+            result.MethodsUsed.Add("tokens");
+        }
+
+        var matches = new Dictionary<string, SimilarCodeMatch>(StringComparer.OrdinalIgnoreCase);
+        if (candidates.Count > 0)
+        {
+            var idf = CodeSimilarity.InverseDocumentFrequency(candidates.Select(c => c.Vector).Append(sourceVector).ToList());
+            foreach (var (path, declaration, vector) in candidates)
+            {
+                var similarity = CodeSimilarity.Cosine(sourceVector, vector, idf);
+                if (similarity < parameters.MinSimilarity)
+                    continue;
+
+                var relativePath = ToRelative(workspacePath, path);
+                matches[$"{relativePath}:{declaration.Line}"] = new SimilarCodeMatch
+                {
+                    Name = declaration.Name,
+                    Kind = declaration.Kind,
+                    Container = declaration.Container,
+                    FilePath = relativePath,
+                    Line = declaration.Line,
+                    Signature = declaration.Signature,
+                    Similarity = Math.Round(similarity, 3),
+                    Method = "tokens",
+                    SharedTerms = CodeSimilarity.SharedTerms(sourceVector, vector, idf)
+                };
+            }
+        }
+
+        if (method != "tokens" && embeddingsAvailable)
+        {
+            await AddEmbeddingMatchesAsync(workspacePath, sourceCode, sourcePath, result.SourceLine, parameters, matches, cancellationToken);
+            result.MethodsUsed.Add("embedding");
+        }
+
+        result.Matches = matches.Values
+            .OrderByDescending(m => m.Similarity)
+            .ThenBy(m => m.FilePath, StringComparer.Ordinal)
+            .ThenBy(m => m.Line)
+            .Take(parameters.MaxResults)
+            .ToList();
+
+        var response = new AIOptimizedResponse<FindSimilarCodeResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindSimilarCodeResult> { Results = result },
+            Message = result.Matches.Count == 0
+                ? $"No functions with similarity ≥ {parameters.MinSimilarity:0.##} to {result.Source}"
+                : $"{result.Matches.Count} similar function(s) to {result.Source}; best {result.Matches[0].Name} ({result.Matches[0].Similarity:0.00})"
+        };
+
+        var insights = new List<string>();
+        var nearDuplicates = result.Matches.Count(m => m.Similarity >= 0.9);
+        if (nearDuplicates > 0)
+        {
+            insights.Add($"{nearDuplicates} near-duplicate(s) (≥ 0.9) - reuse or consolidate rather than add another copy");
+        }
+        if (unsupportedFiles > 0)
+        {
+            insights.Add($"{unsupportedFiles} candidate file(s) skipped: token comparison supports {string.Join(" ", DeclarationScanner.SupportedExtensions)}");
+        }
+        if (method == "auto" && !embeddingsAvailable)
+        {
+            insights.Add("Embeddings unavailable - compared by shared identifiers only, so same logic under different names may be missed");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        if (result.Matches.Count > 0)
+        {
+            var best = result.Matches[0];
+            response.Actions = new List<AIAction>
+            {
+                new AIAction
+                {
+                    Action = ToolNames.ReadSymbols,
+                    Description = $"Read {best.Name} in {best.FilePath} to decide whether to reuse it",
+                    Priority = 80
+                }
+            };
+        }
+
+        return response;
+    }
+
+    /// <summary>
+    /// Locates a function by name: the symbol database when present, otherwise files whose
+    /// identifiers include the name
+    /// </summary>
+    private async Task<(string Path, Declaration Declaration)?> FindSourceAsync(
+        string workspacePath, string symbol, string? filePath, CancellationToken cancellationToken)
+    {
+        var name = symbol.Contains('.') ? symbol[(symbol.LastIndexOf('.') + 1)..] : symbol;
+        var container = symbol.Contains('.') ? symbol[..symbol.LastIndexOf('.')] : null;
+        var fullFilePath = string.IsNullOrWhiteSpace(filePath) ? null : Path.GetFullPath(filePath, workspacePath);
+
+        var paths = new List<string>();
+        if (fullFilePath != null)
+        {
+            paths.Add(fullFilePath);
+        }
+        else if (_sqliteService != null && _sqliteService.DatabaseExists(workspacePath))
+        {
+            var symbols = await _sqliteService.GetSymbolsByNameAsync(workspacePath, name, true, cancellationToken);
+            paths.AddRange(symbols
+                .Where(s => s.Kind is "method" or "function" or "constructor")
+                .Select(s => Path.GetFullPath(s.FilePath, workspacePath))
+                .Distinct(StringComparer.OrdinalIgnoreCase));
+        }
+
+        if (paths.Count == 0)
+        {
+            var hits = await _luceneIndexService.SearchAsync(
+                workspacePath, new TermQuery(new Term("content_symbols", name.ToLowerInvariant())), 20, cancellationToken);
+            paths.AddRange(hits.Hits.Select(h => h.FilePath));
+        }
+
+        foreach (var path in paths.Where(DeclarationScanner.Supports))
+        {
+            string content;
+            try
+            {
+                content = await File.ReadAllTextAsync(path, cancellationToken);
+            }
+            catch (IOException ex)
+            {
+                _logger.LogDebug(ex, "Could not read {FilePath} while locating {Symbol}", path, symbol);
+                continue;
+            }
+
+            var declaration = DeclarationScanner.Scan(content, path)
+                .FirstOrDefault(d => !d.IsType && d.Name == name && (container == null || d.Container == container));
+            if (declaration != null)
+            {
+                return (path, declaration);
+            }
+        }
+
+        return null;
+    }
+
+    private async Task AddEmbeddingMatchesAsync(
+        string workspacePath,
+        string sourceCode,
+        string? sourcePath,
+        int? sourceLine,
+        FindSimilarCodeParameters parameters,
+        Dictionary<string, SimilarCodeMatch> matches,
+        CancellationToken cancellationToken)
+    {
+        List<SemanticSymbolMatch> semantic;
+        try
+        {
+            var query = sourceCode.Length > MaxEmbeddingQueryLength ? sourceCode[..MaxEmbeddingQueryLength] : sourceCode;
+            semantic = await _sqliteService!.SearchSymbolsSemanticAsync(workspacePath, query, parameters.MaxResults * 3, cancellationToken);
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "Embedding search failed; keeping token matches only");
+            return;
+        }
+
+        foreach (var match in semantic.Where(m => m.Symbol.Kind is "method" or "function" or "constructor"))
+        {
+            var path = Path.GetFullPath(match.Symbol.FilePath, workspacePath);
+            if (sourcePath != null && PathsEqual(path, sourcePath) && match.Symbol.StartLine == sourceLine)
+                continue;
+            if (match.SimilarityScore < parameters.MinSimilarity)
+                continue;
+
+            var relativePath = ToRelative(workspacePath, path);
+            var key = $"{relativePath}:{match.Symbol.StartLine}";
+            if (matches.TryGetValue(key, out var existing))
+            {
+                existing.Similarity = Math.Max(existing.Similarity, Math.Round(match.SimilarityScore, 3));
+                existing.Method = "tokens+embedding";
+                continue;
+            }
+
+            matches[key] = new SimilarCodeMatch
+            {
+                Name = match.Symbol.Name,
+                Kind = match.Symbol.Kind,
+                FilePath = relativePath,
+                Line = match.Symbol.StartLine,
+                Signature = match.Symbol.Signature,
+                Similarity = Math.Round(match.SimilarityScore, 3),
+                Method = "embedding"
+            };
+        }
+    }
+
+    private static bool PathsEqual(string a, string b) =>
+        string.Equals(Path.GetFullPath(a), Path.GetFullPath(b), StringComparison.OrdinalIgnoreCase);
+
+    private static string ToRelative(string workspacePath, string path) =>
+        Path.IsPathRooted(path) ? Path.GetRelativePath(workspacePath, path) : path;
+
+    private static AIOptimizedResponse<FindSimilarCodeResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,356 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Answers "which tests do I run after changing this?": locates the symbol in the index, then reads every test file
+/// for the tests, benchmarks and examples that call it, mention it or are named after it - and, one level up, the
+/// tests of its direct callers - with the command that runs just those tests
+/// </summary>
+public class FindTestsTool : CodeSearchToolBase<FindTestsParameters, AIOptimizedResponse<FindTestsResult>>
+{
+    private const int MaxCallers = 10;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindTestsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindTestsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service for definitions, identifiers and test files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindTestsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindTestsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindTests;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHICH TESTS COVER THIS? Given a symbol like 'UserService.GetUser', finds the tests that exercise it - Go tests, " +
+        "table-driven cases, benchmarks and examples in _test.go files, xUnit/NUnit/MSTest methods, Jest/Vitest specs, " +
+        "pytest, JUnit and Rust tests - plus tests of its direct callers, and returns the command that runs exactly those. " +
+        "Use after editing a symbol, before running the whole suite.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Resolves the symbol, scans the test files for its uses and those of its callers, and builds run commands.
+    /// </summary>
+    /// <param name="parameters">Symbol, caller expansion and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Tests reaching the symbol and the commands to run them</returns>
+    protected override async Task<AIOptimizedResponse<FindTestsResult>> ExecuteInternalAsync(
+        FindTestsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var symbol = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol)).Trim();
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try find_tests again");
+        }
+
+        var name = symbol[(symbol.LastIndexOf('.') + 1)..];
+        var qualifier = symbol.Contains('.') ? symbol[..symbol.LastIndexOf('.')].Split('.', '/').Last() : null;
+
+        var definitions = await FindDefinitionsAsync(workspacePath, name, qualifier, cancellationToken);
+        if (definitions.Count == 0)
+        {
+            return CreateErrorResponse("SYMBOL_NOT_FOUND", $"No symbol named '{symbol}' in the index",
+                $"Check the name with symbol_search: {name}",
+                "Qualify methods with their type (Type.Method) and functions with their package (pkg.Func)");
+        }
+
+        // Without a qualifier, a method whose definitions share one type is qualified by it
+        var types = definitions.Select(d => d.Type).Distinct().ToList();
+        var typeQualifier = qualifier != null && definitions.All(d => d.Type == qualifier) ? qualifier
+            : qualifier == null && types.Count == 1 ? types[0]
+            : null;
+        var definitionDirectories = definitions.Select(d => DirectoryOf(d.FilePath)).ToHashSet(StringComparer.Ordinal);
+
+        var allFiles = await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken);
+        var relativePaths = allFiles.Select(f => Relative(workspacePath, f.Path)).ToHashSet(StringComparer.Ordinal);
+        var indexedLines = (await _sqliteService.GetIdentifiersByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken))
+            .GroupBy(i => Relative(workspacePath, i.FilePath), StringComparer.Ordinal)
+            .ToDictionary(g => g.Key, g => (IReadOnlySet<int>)g.Select(i => i.StartLine).ToHashSet(), StringComparer.Ordinal);
+
+        var callers = parameters.IncludeCallers
+            ? await FindCallersAsync(workspacePath, name, cancellationToken)
+            : new List<string>();
+
+        var result = new FindTestsResult
+        {
+            Symbol = symbol,
+            Definitions = definitions.Select(d => $"{d.FilePath}:{d.Line}").ToList()
+        };
+        var found = new List<(FoundTest Test, TestCase Case, int Rank)>();
+        foreach (var file in allFiles)
+        {
+            var relative = Relative(workspacePath, file.Path);
+            if (!TestFileDetector.IsTestFile(relative))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var fullPath = Path.Combine(workspacePath, relative);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+            var tests = TestDiscovery.FindTests(relative, content);
+            if (tests.Count == 0)
+                continue;
+            result.TestFilesScanned++;
+
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            // A test in the symbol's own Go package calls package functions unqualified
+            var fileQualifier = typeQualifier
+                ?? (qualifier != null && !(relative.EndsWith(".go", StringComparison.Ordinal) && definitionDirectories.Contains(DirectoryOf(relative))) ? qualifier : null);
+            var direct = TestDiscovery.FindUses(tests, lines, name, fileQualifier, indexedLines.GetValueOrDefault(relative));
+            foreach (var use in direct)
+            {
+                var rank = use.How switch { "calls" => 0, "named" => 1, _ => 2 };
+                found.Add((ToFoundTest(relative, use, null), use.Test, rank));
+            }
+
+            foreach (var caller in callers)
+            {
+                if (!content.Contains(caller, StringComparison.Ordinal))
+                    continue;
+                foreach (var use in TestDiscovery.FindUses(tests, lines, caller, null).Where(u => u.How != "named"))
+                {
+                    if (found.Any(f => f.Case == use.Test))
+                        continue;
+                    found.Add((ToFoundTest(relative, use, caller), use.Test, 3));
+                }
+            }
+        }
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 500);
+        var selected = found
+            .OrderBy(f => f.Rank)
+            .ThenBy(f => f.Test.FilePath, StringComparer.Ordinal)
+            .ThenBy(f => f.Test.Line)
+            .Take(limit)
+            .ToList();
+        result.Tests = selected.Select(f => f.Test).ToList();
+        result.Truncated = found.Count > limit;
+
+        foreach (var group in selected.Where(f => f.Test.How != "mentions" || selected.All(s => s.Test.How == "mentions"))
+                     .GroupBy(f => f.Test.FilePath, StringComparer.Ordinal))
+        {
+            var project = NearestProject(group.Key, relativePaths);
+            var runner = await JavaScriptRunnerAsync(workspacePath, group.Key, allFiles, relativePaths, cancellationToken);
+            var command = TestDiscovery.RunCommand(group.Key, group.Select(f => f.Case).ToList(), project, runner);
+            if (command.Length > 0 && !result.Commands.Contains(command))
+                result.Commands.Add(command);
+        }
+
+        var calls = found.Count(f => f.Test.How == "calls");
+        var via = found.Count(f => f.Test.How == "via");
+        _logger.LogDebug("find_tests: {Count} tests for {Symbol} in {Files} test files", found.Count, symbol, result.TestFilesScanned);
+
+        var response = new AIOptimizedResponse<FindTestsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindTestsResult> { Results = result },
+            Message = found.Count == 0
+                ? $"No test reaches {symbol} ({result.TestFilesScanned} test file(s) scanned)"
+                : $"{found.Count} test(s) for {symbol}: {calls} calling it, {found.Count(f => f.Test.How == "named")} named after it, " +
+                  $"{found.Count(f => f.Test.How == "mentions")} mentioning it, {via} through its callers"
+        };
+
+        var insights = new List<string>();
+        if (found.Count == 0)
+        {
+            insights.Add(callers.Count > 0
+                ? $"Neither {name} nor its callers ({string.Join(", ", callers.Take(5))}) appear in a test - it is untested"
+                : $"{name} appears in no test - it is untested, or only reached through code the index cannot resolve");
+        }
+        if (found.Any(f => f.Test.How == "mentions"))
+        {
+            insights.Add($"'mentions' tests use the name {name} without {typeQualifier ?? qualifier ?? "its type"} in sight - another symbol with the same name may be meant");
+        }
+        if (via > 0)
+        {
+            insights.Add($"Tests marked 'via' call a direct caller ({string.Join(", ", found.Where(f => f.Test.Via != null).Select(f => f.Test.Via).Distinct().Take(5))}) - run them when behavior, not only the signature, changes");
+        }
+        if (definitions.Count > 1)
+        {
+            insights.Add($"{definitions.Count} definitions match - qualify the symbol (Type.Method or pkg.Func) to narrow");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Results limited to {limit}; commands cover the listed tests only");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// Non-test definitions of the name, with the type that declares each (parent symbol or Go receiver); a
+    /// qualifier keeps definitions whose type, package or directory matches it
+    /// </summary>
+    private async Task<List<(string FilePath, int Line, string? Type)>> FindDefinitionsAsync(string workspacePath, string name, string? qualifier,
+        CancellationToken cancellationToken)
+    {
+        var symbols = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken))
+            .Where(s => s.Kind is not ("import" or "variable" or "field" or "property" or "module"))
+            .ToList();
+        var nonTest = symbols.Where(s => !TestFileDetector.IsTestFile(s.FilePath)).ToList();
+        if (nonTest.Count > 0)
+            symbols = nonTest;
+
+        var definitions = new List<(string FilePath, int Line, string? Type)>();
+        var fileSymbols = new Dictionary<string, List<JulieSymbol>>(StringComparer.Ordinal);
+        foreach (var definition in symbols)
+        {
+            string? type = null;
+            if (definition.Signature != null && Regex.Match(definition.Signature, @"^\s*func\s*\(\s*\w*\s*\*?(?<type>\w+)") is { Success: true } receiver)
+            {
+                type = receiver.Groups["type"].Value;
+            }
+            else if (definition.ParentId != null)
+            {
+                if (!fileSymbols.TryGetValue(definition.FilePath, out var siblings))
+                    fileSymbols[definition.FilePath] = siblings = await _sqliteService.GetSymbolsForFileAsync(workspacePath, definition.FilePath, cancellationToken);
+                type = siblings.FirstOrDefault(s => s.Id == definition.ParentId)?.Name;
+            }
+
+            var relative = Relative(workspacePath, definition.FilePath);
+            if (qualifier != null && type != qualifier
+                && !DirectoryOf(relative).Split('/').Last().Equals(qualifier, StringComparison.OrdinalIgnoreCase)
+                && !Path.GetFileNameWithoutExtension(relative).Equals(qualifier, StringComparison.OrdinalIgnoreCase))
+            {
+                continue;
+            }
+            definitions.Add((relative, definition.StartLine, type));
+        }
+        return definitions;
+    }
+
+    /// <summary>
+    /// Names of the non-test functions and methods that call the symbol, most frequent first
+    /// </summary>
+    private async Task<List<string>> FindCallersAsync(string workspacePath, string name, CancellationToken cancellationToken)
+    {
+        var identifiers = await _sqliteService.GetIdentifiersByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken);
+        var counts = new Dictionary<string, int>(StringComparer.Ordinal);
+        var fileSymbols = new Dictionary<string, List<JulieSymbol>>(StringComparer.Ordinal);
+        foreach (var identifier in identifiers.Where(i => i.ContainingSymbolId != null && !TestFileDetector.IsTestFile(i.FilePath)))
+        {
+            if (!fileSymbols.TryGetValue(identifier.FilePath, out var symbols))
+                fileSymbols[identifier.FilePath] = symbols = await _sqliteService.GetSymbolsForFileAsync(workspacePath, identifier.FilePath, cancellationToken);
+            var caller = symbols.FirstOrDefault(s => s.Id == identifier.ContainingSymbolId);
+            if (caller == null || caller.Name == name || caller.Name.Length < 3 || caller.Kind is not ("function" or "method" or "constructor"))
+                continue;
+            counts[caller.Name] = counts.GetValueOrDefault(caller.Name) + 1;
+        }
+        return counts.OrderByDescending(c => c.Value).ThenBy(c => c.Key, StringComparer.Ordinal).Take(MaxCallers).Select(c => c.Key).ToList();
+    }
+
+    /// <summary>
+    /// The .csproj, pom.xml or build.gradle nearest a test file, walking up its directories
+    /// </summary>
+    private static string? NearestProject(string relative, HashSet<string> files)
+    {
+        for (var directory = DirectoryOf(relative); ; directory = DirectoryOf(directory))
+        {
+            var prefix = directory.Length == 0 ? string.Empty : directory + "/";
+            var project = files.Where(f => f.StartsWith(prefix, StringComparison.Ordinal) && !f[prefix.Length..].Contains('/'))
+                .FirstOrDefault(f => f.EndsWith(".csproj", StringComparison.Ordinal) || f.EndsWith(".fsproj", StringComparison.Ordinal)
+                                     || f.EndsWith("/pom.xml", StringComparison.Ordinal) || f == "pom.xml"
+                                     || Path.GetFileName(f) is "build.gradle" or "build.gradle.kts");
+            if (project != null)
+                return project;
+            if (directory.Length == 0)
+                return null;
+        }
+    }
+
+    /// <summary>
+    /// vitest, mocha or jest from the package.json nearest a JavaScript test file; null for other languages
+    /// </summary>
+    private static async Task<string?> JavaScriptRunnerAsync(string workspacePath, string relative, List<FileRecord> allFiles, HashSet<string> files,
+        CancellationToken cancellationToken)
+    {
+        if (!Regex.IsMatch(relative, @"\.[cm]?[jt]sx?$"))
+            return null;
+        for (var directory = DirectoryOf(relative); ; directory = DirectoryOf(directory))
+        {
+            var packageJson = directory.Length == 0 ? "package.json" : directory + "/package.json";
+            if (files.Contains(packageJson))
+            {
+                var record = allFiles.FirstOrDefault(f => Relative(workspacePath, f.Path) == packageJson);
+                var fullPath = Path.Combine(workspacePath, packageJson);
+                var content = record?.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : string.Empty);
+                return content.Contains("\"vitest\"", StringComparison.Ordinal) ? "vitest"
+                    : content.Contains("\"mocha\"", StringComparison.Ordinal) && !content.Contains("\"jest\"", StringComparison.Ordinal) ? "mocha"
+                    : "jest";
+            }
+            if (directory.Length == 0)
+                return null;
+        }
+    }
+
+    private static FoundTest ToFoundTest(string relative, TestUse use, string? via) => new()
+    {
+        FilePath = relative,
+        Name = use.Test.Name,
+        Container = use.Test.Container,
+        Kind = use.Test.Kind,
+        Framework = use.Test.Framework,
+        Line = use.Test.Line,
+        How = via != null ? "via" : use.How,
+        Via = via,
+        Lines = use.Lines,
+        Cases = use.Test.Cases
+    };
+
+    private static string DirectoryOf(string relative) => Path.GetDirectoryName(relative)?.Replace('\\', '/') ?? string.Empty;
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<FindTestsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,164 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Assets;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds images, fonts, media and fixtures that no indexed file names
+/// </summary>
+public class FindUnreferencedAssetsTool : CodeSearchToolBase<FindUnreferencedAssetsParameters, AIOptimizedResponse<FindUnreferencedAssetsResult>>
+{
+    private readonly IAssetInventoryService _assetInventory;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<FindUnreferencedAssetsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the FindUnreferencedAssetsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="assetInventory">Asset inventory service holding sizes, hashes and references</param>
+    /// <param name="sqliteService">SQLite symbol service, checked for an indexed workspace</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public FindUnreferencedAssetsTool(
+        IServiceProvider serviceProvider,
+        IAssetInventoryService assetInventory,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<FindUnreferencedAssetsTool> logger) : base(serviceProvider, logger)
+    {
+        _assetInventory = assetInventory;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.FindUnreferencedAssets;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHICH ASSETS ARE UNUSED? Lists images, fonts, media, documents, binaries and test fixtures whose file name appears in no " +
+        "indexed file - code, styles, markup or docs - with size and kind. Assets in a directory the code names are listed last: " +
+        "they may be loaded by a built path or a directory listing. Uses the asset inventory from the last index unless refresh is set.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Lists the workspace's assets without references.
+    /// </summary>
+    /// <param name="parameters">Path and kind filters, refresh flag and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Unreferenced assets, the ones safest to delete first</returns>
+    protected override async Task<AIOptimizedResponse<FindUnreferencedAssetsResult>> ExecuteInternalAsync(
+        FindUnreferencedAssetsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try find_unreferenced_assets again");
+        }
+
+        var inventory = parameters.Refresh
+            ? await _assetInventory.RefreshAsync(workspacePath, cancellationToken)
+            : await _assetInventory.GetAsync(workspacePath, cancellationToken);
+        if (!inventory.ReferencesScanned)
+        {
+            // Built before the workspace had a symbol database, so every asset would look unreferenced
+            inventory = await _assetInventory.RefreshAsync(workspacePath, cancellationToken);
+        }
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var assets = inventory.Assets
+            .Where(a => string.IsNullOrEmpty(parameters.Kind) || a.Kind.Equals(parameters.Kind, StringComparison.OrdinalIgnoreCase))
+            .Where(a => string.IsNullOrEmpty(scope) || a.Path.StartsWith(scope + "/", StringComparison.Ordinal))
+            .ToList();
+        var unreferenced = assets
+            .Where(a => a.References.Count == 0)
+            .OrderBy(a => a.DirectoryReferenced)
+            .ThenByDescending(a => a.SizeBytes)
+            .ThenBy(a => a.Path, StringComparer.Ordinal)
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        var result = new FindUnreferencedAssetsResult
+        {
+            WorkspacePath = workspacePath,
+            Assets = unreferenced.Take(maxResults).ToList(),
+            TotalUnreferenced = unreferenced.Count,
+            TotalBytes = unreferenced.Sum(a => a.SizeBytes),
+            AssetsScanned = assets.Count,
+            InventoryBuiltAt = inventory.BuiltAt,
+            Truncated = unreferenced.Count > maxResults
+        };
+
+        _logger.LogDebug("find_unreferenced_assets: {Assets} assets, {Unreferenced} unreferenced", assets.Count, unreferenced.Count);
+
+        var response = new AIOptimizedResponse<FindUnreferencedAssetsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<FindUnreferencedAssetsResult> { Results = result },
+            Message = unreferenced.Count == 0
+                ? $"All {assets.Count} asset(s) are referenced"
+                : $"{unreferenced.Count} of {assets.Count} asset(s) unreferenced, {result.TotalBytes / 1024.0:F1} KB"
+        };
+
+        var insights = new List<string>();
+        var byDirectory = unreferenced.Count(a => a.DirectoryReferenced);
+        if (byDirectory > 0)
+        {
+            insights.Add($"{byDirectory} asset(s) lie in directories the code names - check for paths built at runtime before deleting them");
+        }
+        var sharedNames = inventory.Assets
+            .GroupBy(a => Path.GetFileName(a.Path), StringComparer.OrdinalIgnoreCase)
+            .Where(g => g.Count() > 1)
+            .Select(g => g.Key)
+            .ToHashSet(StringComparer.OrdinalIgnoreCase);
+        var nameOnly = assets.Count(a => a.References.Count > 0 && a.References.All(r => !r.Exact) && sharedNames.Contains(Path.GetFileName(a.Path)));
+        if (nameOnly > 0)
+        {
+            insights.Add($"{nameOnly} asset(s) share a file name with another asset and are only referenced by that name - one copy may be unused; see find_duplicate_assets");
+        }
+        if (unreferenced.Any(a => a.Kind == "fixture"))
+        {
+            insights.Add("Fixtures are often loaded by glob or golden-file helpers - confirm with the tests before deleting them");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {maxResults} of {unreferenced.Count} assets - filter by path or kind, or raise maxResults");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<FindUnreferencedAssetsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,158 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services.Logging;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+using Serilog.Events;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Returns recent server log entries filtered by level, tool, correlation ID, or text
+/// </summary>
+public class GetLogsTool : CodeSearchToolBase<GetLogsParameters, AIOptimizedResponse<GetLogsResult>>
+{
+    private static readonly Regex AgePattern = new(@"^(\d+)\s*(s|m|min|h|d)$", RegexOptions.Compiled | RegexOptions.IgnoreCase);
+
+    private readonly ILogQueryService _logQueryService;
+    private readonly ILogger<GetLogsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the GetLogsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="logQueryService">Log query service backed by the in-memory log buffer</param>
+    /// <param name="logger">Logger instance</param>
+    public GetLogsTool(
+        IServiceProvider serviceProvider,
+        ILogQueryService logQueryService,
+        ILogger<GetLogsTool> logger) : base(serviceProvider, logger)
+    {
+        _logQueryService = logQueryService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.GetLogs;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "DEBUG WHY a search returned nothing or a tool failed - Fetch recent server log entries filtered by level, tool name, or correlation ID. " +
+        "Every tool call gets a correlation ID; filter by it to see exactly what happened during that request.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Executes the log retrieval.
+    /// </summary>
+    /// <param name="parameters">Log filter parameters</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Matching log entries, newest first</returns>
+    protected override Task<AIOptimizedResponse<GetLogsResult>> ExecuteInternalAsync(
+        GetLogsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var minimumLevel = LogEventLevel.Information;
+        if (!string.IsNullOrWhiteSpace(parameters.Level) && !RuntimeLogLevel.TryParse(parameters.Level, out minimumLevel))
+        {
+            return Task.FromResult(CreateError("INVALID_LOG_LEVEL", $"Unknown log level '{parameters.Level}'",
+                "Use one of: Trace, Debug, Information, Warning, Error, Critical"));
+        }
+
+        DateTimeOffset? since = null;
+        if (!string.IsNullOrWhiteSpace(parameters.Since))
+        {
+            var age = ParseAge(parameters.Since);
+            if (age == null)
+            {
+                return Task.FromResult(CreateError("INVALID_TIME_FRAME", $"Could not parse time frame '{parameters.Since}'",
+                    "Use a number followed by s, m, h, or d - e.g. '15m', '2h', '1d'"));
+            }
+            since = DateTimeOffset.UtcNow - age.Value;
+        }
+
+        var entries = _logQueryService.Query(new LogQuery
+        {
+            MinimumLevel = minimumLevel,
+            ToolName = parameters.ToolName,
+            CorrelationId = parameters.CorrelationId,
+            Contains = parameters.Contains,
+            Since = since,
+            MaxEntries = parameters.MaxEntries
+        });
+
+        var (defaultLevel, codeSearchLevel) = _logQueryService.GetLevels();
+        var result = new GetLogsResult
+        {
+            Entries = entries.Select(e => new LogEntryInfo
+            {
+                Timestamp = e.Timestamp,
+                Level = e.Level.ToString(),
+                Message = e.Message,
+                Source = e.SourceContext,
+                CorrelationId = e.CorrelationId,
+                ToolName = e.ToolName,
+                Exception = e.Exception
+            }).ToList(),
+            DefaultLevel = defaultLevel.ToString(),
+            CodeSearchLevel = codeSearchLevel.ToString()
+        };
+
+        var response = new AIOptimizedResponse<GetLogsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<GetLogsResult> { Results = result },
+            Message = $"Found {result.Entries.Count} log entries"
+        };
+
+        if (result.Entries.Count == 0 && codeSearchLevel > LogEventLevel.Debug)
+        {
+            response.Insights = new List<string>
+            {
+                $"CodeSearch is logging at {codeSearchLevel} - use set_log_level with 'Debug' and retry the failing call for more detail"
+            };
+        }
+
+        _logger.LogDebug("get_logs returned {Count} entries", result.Entries.Count);
+        return Task.FromResult(response);
+    }
+
+    private static TimeSpan? ParseAge(string value)
+    {
+        var match = AgePattern.Match(value.Trim());
+        if (!match.Success || !int.TryParse(match.Groups[1].Value, out var amount))
+            return null;
+
+        return match.Groups[2].Value.ToLowerInvariant() switch
+        {
+            "s" => TimeSpan.FromSeconds(amount),
+            "m" or "min" => TimeSpan.FromMinutes(amount),
+            "h" => TimeSpan.FromHours(amount),
+            "d" => TimeSpan.FromDays(amount),
+            _ => null
+        };
+    }
+
+    private static AIOptimizedResponse<GetLogsResult> CreateError(string code, string message, string recovery)
+    {
+        return new AIOptimizedResponse<GetLogsResult>
+        {
+            Success = false,
+            Error = new ErrorInfo
+            {
+                Code = code,
+                Message = message,
+                Recovery = new RecoveryInfo { Steps = new[] { recovery } }
+            }
+        };
+    }
+}
@@ -8,6 +8,7 @@ using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Julie;
 using COA.CodeSearch.McpServer.Tools.Models;
@@ -124,6 +125,7 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
             : Path.GetFullPath(parameters.WorkspacePath);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
 
         // Validate file exists
         if (!File.Exists(filePath))
@@ -291,6 +293,7 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
 
         // Create a mapping from symbol ID to TypeOverview for populating inheritance later
         var symbolIdToTypeOverview = new Dictionary<string, TypeOverview>();
+        var positions = CreateSourcePositions(workspacePath);
 
         // Julie stores all symbols in a flat list with Kind field
         // Categorize by kind: class, interface, struct, enum, function, method
@@ -308,6 +311,9 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
                     Signature = symbol.Signature ?? symbol.Name,
                     Line = parameters.IncludeLineNumbers ? symbol.StartLine : 0,
                     Column = parameters.IncludeLineNumbers ? symbol.StartColumn : 0,
+                    Utf16Column = parameters.IncludeLineNumbers
+                        ? await positions.ToUtf16ColumnAsync(symbol.FilePath, symbol.StartLine, symbol.StartColumn, cancellationToken)
+                        : null,
                     Modifiers = new List<string>(), // Julie doesn't extract modifiers currently
                     BaseType = null, // Will be populated from relationships if IncludeInheritance is true
                     Interfaces = null // Will be populated from relationships if IncludeInheritance is true
@@ -346,6 +352,9 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
                     ReturnType = "void", // Not extracted by Julie
                     Line = parameters.IncludeLineNumbers ? symbol.StartLine : 0,
                     Column = parameters.IncludeLineNumbers ? symbol.StartColumn : 0,
+                    Utf16Column = parameters.IncludeLineNumbers
+                        ? await positions.ToUtf16ColumnAsync(symbol.FilePath, symbol.StartLine, symbol.StartColumn, cancellationToken)
+                        : null,
                     Modifiers = new List<string>(),
                     Parameters = new List<string>(),
                     ContainingType = null! // Julie symbols are flat, no nesting info
@@ -353,6 +362,23 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
 
                 result.Methods.Add(methodOverview);
             }
+            // Markdown headings, with the languages of the code blocks directly under them
+            else if (kind == "heading")
+            {
+                result.Sections.Add(new SectionOverview
+                {
+                    Name = symbol.Name,
+                    Level = MarkdownSymbols.Level(symbol),
+                    Signature = symbol.Signature ?? symbol.Name,
+                    Line = parameters.IncludeLineNumbers ? symbol.StartLine : 0,
+                    EndLine = parameters.IncludeLineNumbers ? symbol.EndLine : 0,
+                    CodeBlocks = symbols
+                        .Where(s => s.Kind == "block" && s.ParentId == symbol.Id)
+                        .Select(s => s.Name)
+                        .Distinct()
+                        .ToList()
+                });
+            }
         }
 
         // Query inheritance relationships if IncludeInheritance is true
@@ -411,7 +437,7 @@ public class GetSymbolsOverviewTool : CodeSearchToolBase<GetSymbolsOverviewParam
 
         // Calculate total symbols
         result.TotalSymbols = result.Classes.Count + result.Interfaces.Count +
-                             result.Structs.Count + result.Enums.Count + result.Methods.Count;
+                             result.Structs.Count + result.Enums.Count + result.Methods.Count + result.Sections.Count;
 
         _logger.LogDebug("Built overview: {Classes} classes, {Interfaces} interfaces, {Structs} structs, {Enums} enums, {Methods} methods",
             result.Classes.Count, result.Interfaces.Count, result.Structs.Count, result.Enums.Count, result.Methods.Count);
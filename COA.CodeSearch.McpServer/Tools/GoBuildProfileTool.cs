@@ -0,0 +1,197 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.GoBuild;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Shows or sets the GOOS/GOARCH profile a workspace's Go code is read under, and lists the files whose build
+/// constraints it excludes
+/// </summary>
+public class GoBuildProfileTool : CodeSearchToolBase<GoBuildProfileParameters, AIOptimizedResponse<GoBuildProfileResult>>
+{
+    private readonly IGoBuildProfileService _goBuildProfiles;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<GoBuildProfileTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the GoBuildProfileTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="goBuildProfiles">Go build profiles and file constraints</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public GoBuildProfileTool(
+        IServiceProvider serviceProvider,
+        IGoBuildProfileService goBuildProfiles,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<GoBuildProfileTool> logger) : base(serviceProvider, logger)
+    {
+        _goBuildProfiles = goBuildProfiles;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.GoBuildProfile;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHICH GO FILES BUILD HERE. Shows or sets the workspace's GOOS/GOARCH/tags profile and lists Go files whose build " +
+        "constraints (//go:build, // +build, _linux.go suffixes) it excludes. symbol_search and goto_definition mark or skip " +
+        "symbols from excluded files, so parse_linux.go and parse_windows.go no longer pollute each other's results.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Updates the profile when asked and evaluates every indexed Go file's constraint against it.
+    /// </summary>
+    /// <param name="parameters">Profile changes and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The profile with the constrained files</returns>
+    protected override async Task<AIOptimizedResponse<GoBuildProfileResult>> ExecuteInternalAsync(
+        GoBuildProfileParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try go_build_profile again");
+        }
+
+        var goos = parameters.Goos?.Trim().ToLowerInvariant();
+        var goarch = parameters.Goarch?.Trim().ToLowerInvariant();
+        if (!string.IsNullOrEmpty(goos) && !GoBuildConstraints.KnownOs.Contains(goos))
+        {
+            return CreateErrorResponse("INVALID_GOOS", $"'{parameters.Goos}' is not a GOOS the go command knows",
+                $"Use one of: {string.Join(", ", GoBuildConstraints.KnownOs.OrderBy(o => o, StringComparer.Ordinal))}");
+        }
+        if (!string.IsNullOrEmpty(goarch) && !GoBuildConstraints.KnownArch.Contains(goarch))
+        {
+            return CreateErrorResponse("INVALID_GOARCH", $"'{parameters.Goarch}' is not a GOARCH the go command knows",
+                $"Use one of: {string.Join(", ", GoBuildConstraints.KnownArch.OrderBy(a => a, StringComparer.Ordinal))}");
+        }
+
+        var changed = false;
+        if (parameters.Reset)
+        {
+            _goBuildProfiles.SetProfile(workspacePath, null);
+            changed = true;
+        }
+        if (!string.IsNullOrEmpty(goos) || !string.IsNullOrEmpty(goarch) || parameters.Tags != null)
+        {
+            var current = _goBuildProfiles.GetProfile(workspacePath);
+            _goBuildProfiles.SetProfile(workspacePath, new GoBuildProfile
+            {
+                Goos = string.IsNullOrEmpty(goos) ? current.Goos : goos,
+                Goarch = string.IsNullOrEmpty(goarch) ? current.Goarch : goarch,
+                Tags = parameters.Tags?.Select(t => t.Trim()).Where(t => t.Length > 0).Distinct().ToList() ?? current.Tags
+            });
+            changed = true;
+        }
+
+        var profile = _goBuildProfiles.GetProfile(workspacePath);
+        var result = new GoBuildProfileResult
+        {
+            Profile = profile,
+            Source = _goBuildProfiles.HasWorkspaceProfile(workspacePath) ? "workspace" : "default"
+        };
+
+        var files = new List<GoConstrainedFile>();
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            if (!file.Path.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            result.GoFiles++;
+            var constraint = await _goBuildProfiles.GetConstraintAsync(workspacePath, file.Path, cancellationToken);
+            if (constraint == null)
+                continue;
+
+            files.Add(new GoConstrainedFile
+            {
+                FilePath = Relative(workspacePath, file.Path),
+                Constraint = constraint,
+                InBuild = GoBuildConstraints.Matches(constraint, profile)
+            });
+        }
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        result.ConstrainedFiles = files.Count;
+        result.ExcludedFiles = files.Count(f => !f.InBuild);
+        result.Files = files
+            .OrderBy(f => f.InBuild)
+            .ThenBy(f => f.FilePath, StringComparer.Ordinal)
+            .Take(limit)
+            .ToList();
+        result.Truncated = files.Count > limit;
+
+        _logger.LogDebug("go_build_profile {Profile}: {Constrained} constrained, {Excluded} excluded of {Files} Go files",
+            profile, result.ConstrainedFiles, result.ExcludedFiles, result.GoFiles);
+
+        var response = new AIOptimizedResponse<GoBuildProfileResult>
+        {
+            Success = true,
+            Data = new AIResponseData<GoBuildProfileResult> { Results = result },
+            Message = $"{(changed ? "Profile set to" : "Profile")} {profile} ({result.Source}): {result.ExcludedFiles} of " +
+                      $"{result.GoFiles} Go file(s) excluded, {result.ConstrainedFiles - result.ExcludedFiles} constrained file(s) included"
+        };
+
+        var insights = new List<string>();
+        if (result.GoFiles == 0)
+        {
+            insights.Add("No Go files are indexed in this workspace");
+        }
+        if (result.ExcludedFiles > 0)
+        {
+            insights.Add("symbol_search marks symbols from excluded files with inBuild false (buildProfileOnly drops them); goto_definition prefers definitions that build");
+        }
+        if (changed)
+        {
+            insights.Add("Cached symbol_search and goto_definition results may still reflect the previous profile - pass noCache to refresh them");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Listed {limit} of {files.Count} constrained files - raise maxResults for more");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<GoBuildProfileResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,357 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.GoBuild;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Builds the package import graph of a workspace's Go modules from go.mod, go.sum and the import blocks of the
+/// indexed Go files, and answers which packages import a package and what it imports
+/// </summary>
+public class GoPackageGraphTool : CodeSearchToolBase<GoPackageGraphParameters, AIOptimizedResponse<GoPackageGraphResult>>
+{
+    private static readonly string[] Directions = { "importers", "imports", "both" };
+
+    private readonly IGoBuildProfileService _goBuildProfiles;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<GoPackageGraphTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the GoPackageGraphTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="goBuildProfiles">Go build profile the constrained files are judged by</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public GoPackageGraphTool(
+        IServiceProvider serviceProvider,
+        IGoBuildProfileService goBuildProfiles,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<GoPackageGraphTool> logger) : base(serviceProvider, logger)
+    {
+        _goBuildProfiles = goBuildProfiles;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.GoPackageGraph;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHO IMPORTS THIS GO PACKAGE? Builds the package graph from go.mod, go.sum and import blocks. Give a package " +
+        "(internal/auth) to list its importers and imports, directly or transitively; without one, lists every package " +
+        "with import counts and flags go.mod requirements nothing imports. Import paths resolve to workspace packages, " +
+        "required modules with versions, or the standard library.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads the modules and Go files, links them into a graph and walks it from the requested package.
+    /// </summary>
+    /// <param name="parameters">Package, direction, depth and filters</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The package's importers and imports, or an overview of all packages</returns>
+    protected override async Task<AIOptimizedResponse<GoPackageGraphResult>> ExecuteInternalAsync(
+        GoPackageGraphParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try go_package_graph again");
+        }
+
+        var direction = (parameters.Direction ?? "both").Trim().ToLowerInvariant();
+        if (!Directions.Contains(direction))
+        {
+            return CreateErrorResponse("INVALID_DIRECTION", $"Unknown direction '{parameters.Direction}'",
+                $"Use one of: {string.Join(", ", Directions)}");
+        }
+
+        var (graph, filesScanned) = await LoadGraphAsync(workspacePath, cancellationToken);
+        if (graph.Modules.Count == 0)
+        {
+            return CreateErrorResponse("NO_GO_MODULES", "No go.mod was found for the workspace's Go files",
+                "go_package_graph resolves import paths through go.mod - check the workspace contains a Go module",
+                "GOPATH-mode code without go.mod is not supported");
+        }
+
+        var profile = _goBuildProfiles.GetProfile(workspacePath);
+        var imports = graph.Imports
+            .Where(i => parameters.IncludeTests || !i.Test)
+            .Where(i => !parameters.BuildProfileOnly || GoBuildConstraints.Matches(i.Constraint, profile))
+            .ToList();
+        var internalEdges = imports.Where(i => i.Kind == "internal" && i.From != i.To).ToList();
+        var limit = Math.Clamp(parameters.MaxResults, 1, 2000);
+
+        var result = new GoPackageGraphResult
+        {
+            FilesScanned = filesScanned,
+            Modules = graph.Modules.Select(m => new GoModuleSummary
+            {
+                Path = m.Path,
+                Directory = m.Directory,
+                GoVersion = m.GoVersion,
+                Packages = graph.Packages.Values.Count(p => p.Module == m.Path),
+                Requires = m.Requires.Count,
+                Indirect = m.Requires.Count(r => r.Indirect),
+                UnusedRequires = GoModules.UnusedRequirements(graph, m).Select(r => r.Path).ToList(),
+                MissingSums = GoModules.MissingSums(m).Select(r => $"{r.Path}@{r.Version}").ToList()
+            }).ToList(),
+            Unresolved = imports.Where(i => i.Kind == "unknown").Take(limit).ToList()
+        };
+
+        var package = parameters.Package?.Trim().Trim('/').Replace('\\', '/');
+        if (string.IsNullOrEmpty(package) || package == ".")
+        {
+            var importsOf = internalEdges.GroupBy(i => i.From).ToDictionary(g => g.Key, g => g.Select(i => i.To).Distinct().Count());
+            var importedBy = internalEdges.GroupBy(i => i.To).ToDictionary(g => g.Key, g => g.Select(i => i.From).Distinct().Count());
+            var packages = graph.Packages.Values
+                .Select(p => new GoPackageSummary
+                {
+                    ImportPath = p.ImportPath,
+                    Name = p.Name,
+                    Directory = p.Directory,
+                    Files = p.Files,
+                    Imports = importsOf.GetValueOrDefault(p.ImportPath),
+                    ImportedBy = importedBy.GetValueOrDefault(p.ImportPath)
+                })
+                .OrderByDescending(p => p.ImportedBy)
+                .ThenBy(p => p.ImportPath, StringComparer.Ordinal)
+                .ToList();
+            result.Packages = packages.Take(limit).ToList();
+            result.Truncated = packages.Count > limit;
+        }
+        else
+        {
+            result.Focus = graph.Packages.Values
+                .Where(p => p.ImportPath == package || p.ImportPath.EndsWith("/" + package, StringComparison.Ordinal)
+                            || p.Directory.Equals(package, StringComparison.OrdinalIgnoreCase))
+                .Select(p => p.ImportPath)
+                .OrderBy(p => p, StringComparer.Ordinal)
+                .ToList();
+            if (result.Focus.Count == 0)
+            {
+                var last = package.Split('/').Last();
+                var similar = graph.Packages.Keys.Where(p => p.Split('/').Last().Contains(last, StringComparison.OrdinalIgnoreCase)).Take(5).ToList();
+                return CreateErrorResponse("PACKAGE_NOT_FOUND", $"No Go package in the workspace matches '{parameters.Package}'",
+                    similar.Count > 0 ? $"Similar packages: {string.Join(", ", similar)}" : "Call go_package_graph without a package to list every package",
+                    "Give the full import path, its tail (internal/auth) or the package directory");
+            }
+
+            var depth = Math.Clamp(parameters.Depth, 1, 20);
+            if (direction != "imports")
+            {
+                result.Importers = Walk(result.Focus, internalEdges, depth, reverse: true);
+            }
+            if (direction != "importers")
+            {
+                result.Imports = Walk(result.Focus, internalEdges, depth, reverse: false);
+                var focus = result.Focus.ToHashSet(StringComparer.Ordinal);
+                result.Imports.AddRange(imports
+                    .Where(i => focus.Contains(i.From) && i.Kind != "internal" && (parameters.IncludeStd || i.Kind != "std"))
+                    .GroupBy(i => i.To)
+                    .Select(g => g.First())
+                    .OrderBy(i => i.Kind == "std")
+                    .ThenBy(i => i.To, StringComparer.Ordinal)
+                    .Select(i => Edge(i, i.To, i.From, 1)));
+            }
+            result.Truncated = result.Importers.Count > limit || result.Imports.Count > limit;
+            result.Importers = result.Importers.Take(limit).ToList();
+            result.Imports = result.Imports.Take(limit).ToList();
+        }
+
+        _logger.LogDebug("go_package_graph: {Modules} modules, {Packages} packages, {Imports} imports from {Files} files",
+            graph.Modules.Count, graph.Packages.Count, imports.Count, filesScanned);
+
+        var response = new AIOptimizedResponse<GoPackageGraphResult>
+        {
+            Success = true,
+            Data = new AIResponseData<GoPackageGraphResult> { Results = result },
+            Message = result.Focus.Count == 0
+                ? $"{graph.Packages.Count} package(s) in {graph.Modules.Count} module(s), {internalEdges.Select(i => (i.From, i.To)).Distinct().Count()} internal import link(s)"
+                : $"{string.Join(", ", result.Focus)}: imported by {result.Importers.Count(e => e.Depth == 1)} package(s) directly" +
+                  (result.Importers.Any(e => e.Depth > 1) ? $" and {result.Importers.Count(e => e.Depth > 1)} transitively" : string.Empty) +
+                  $", imports {result.Imports.Count(e => e.Kind == "internal")} workspace and {result.Imports.Count(e => e.Kind == "module")} module package(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.Focus.Count > 1)
+        {
+            insights.Add($"'{parameters.Package}' matched {result.Focus.Count} packages - give the full import path to pick one");
+        }
+        if (result.Focus.Count > 0 && result.Importers.Count == 0 && direction != "imports")
+        {
+            insights.Add(parameters.IncludeTests
+                ? "Nothing in the workspace imports this package - it may be a main package or unused"
+                : "No non-test package imports this package - pass includeTests to count test imports");
+        }
+        if (result.Focus.Any(f => f.Contains("/internal/", StringComparison.Ordinal) || f.EndsWith("/internal", StringComparison.Ordinal)))
+        {
+            insights.Add("Go only lets packages rooted at the parent of an internal directory import from it");
+        }
+        if (result.Modules.Any(m => m.UnusedRequires.Count > 0))
+        {
+            insights.Add("Direct requirements nothing imports may be tools or leftovers - go mod tidy settles them");
+        }
+        if (result.Unresolved.Count > 0)
+        {
+            insights.Add($"{result.Unresolved.Count} import(s) match no workspace package or go.mod requirement - go build will fail on them");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Results were cut at {limit} - narrow with package, direction or depth");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// Reads go.mod and go.sum beside the workspace's Go packages and the package clause and imports of each
+    /// indexed Go file; vendor and testdata directories are skipped as the go command skips them
+    /// </summary>
+    private async Task<(GoPackageGraph Graph, int Files)> LoadGraphAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        var modules = new Dictionary<string, GoModule?>(StringComparer.Ordinal);
+        var files = new List<GoSourceFile>();
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var relative = Relative(workspacePath, file.Path);
+            if (!relative.EndsWith(".go", StringComparison.OrdinalIgnoreCase)
+                || relative.Split('/').Any(s => s is "vendor" or "testdata" || s.StartsWith('_') || s.StartsWith('.')))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var fullPath = Path.Combine(workspacePath, relative);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            var source = GoModules.ReadFile(relative, lines);
+            source.Constraint = GoBuildConstraints.Read(relative, lines);
+            files.Add(source);
+
+            // go.mod is looked up once per directory on the way to the workspace root
+            for (var directory = Path.GetDirectoryName(relative)?.Replace('\\', '/') ?? string.Empty; ;
+                 directory = directory.Contains('/') ? directory[..directory.LastIndexOf('/')] : string.Empty)
+            {
+                if (!modules.ContainsKey(directory))
+                    modules[directory] = await ReadModuleAsync(workspacePath, directory, cancellationToken);
+                if (modules[directory] != null || directory.Length == 0)
+                    break;
+            }
+        }
+
+        var found = modules.Values.OfType<GoModule>().ToList();
+        return (GoModules.Build(found, files), files.Count);
+    }
+
+    private async Task<GoModule?> ReadModuleAsync(string workspacePath, string directory, CancellationToken cancellationToken)
+    {
+        var goMod = Path.Combine(workspacePath, directory, "go.mod");
+        if (!File.Exists(goMod))
+            return null;
+
+        try
+        {
+            var module = GoModules.ParseGoMod(await File.ReadAllTextAsync(goMod, cancellationToken));
+            if (module.Path.Length == 0)
+                return null;
+            module.Directory = directory;
+
+            var goSum = Path.Combine(workspacePath, directory, "go.sum");
+            if (File.Exists(goSum))
+                module.Sums = GoModules.ParseGoSum(await File.ReadAllTextAsync(goSum, cancellationToken));
+            return module;
+        }
+        catch (IOException ex)
+        {
+            _logger.LogDebug(ex, "Could not read {GoMod}", goMod);
+            return null;
+        }
+    }
+
+    /// <summary>
+    /// Breadth-first over workspace imports from the focus packages, backwards for importers; each package is
+    /// reported once, at its nearest depth
+    /// </summary>
+    private static List<GoPackageEdge> Walk(List<string> focus, List<GoImport> edges, int maxDepth, bool reverse)
+    {
+        var next = edges
+            .GroupBy(e => reverse ? e.To : e.From)
+            .ToDictionary(g => g.Key, g => g.OrderBy(e => e.FilePath, StringComparer.Ordinal).ThenBy(e => e.Line).ToList());
+        var seen = new HashSet<string>(focus, StringComparer.Ordinal);
+        var frontier = focus.ToList();
+        var found = new List<GoPackageEdge>();
+
+        for (var depth = 1; depth <= maxDepth && frontier.Count > 0; depth++)
+        {
+            var reached = new List<string>();
+            foreach (var package in frontier)
+            {
+                foreach (var edge in next.GetValueOrDefault(package) ?? new List<GoImport>())
+                {
+                    var other = reverse ? edge.From : edge.To;
+                    if (!seen.Add(other))
+                        continue;
+                    found.Add(Edge(edge, other, package, depth));
+                    reached.Add(other);
+                }
+            }
+            frontier = reached;
+        }
+        return found.OrderBy(e => e.Depth).ThenBy(e => e.Package, StringComparer.Ordinal).ToList();
+    }
+
+    private static GoPackageEdge Edge(GoImport import, string package, string via, int depth) => new()
+    {
+        Package = package,
+        Kind = import.Kind,
+        Module = import.Module,
+        Version = import.Version,
+        Depth = depth,
+        Via = via,
+        FilePath = import.FilePath,
+        Line = import.Line,
+        Test = import.Test
+    };
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<GoPackageGraphResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
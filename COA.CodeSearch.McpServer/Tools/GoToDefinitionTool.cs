@@ -1,4 +1,5 @@
 using System.ComponentModel;
+using System.Text.RegularExpressions;
 using COA.Mcp.Framework;
 using COA.Mcp.Framework.Base;
 using COA.Mcp.Framework.Models;
@@ -12,6 +13,10 @@ using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Julie;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.GoBuild;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using COA.CodeSearch.McpServer.Services.Orm;
 using COA.CodeSearch.McpServer.Tools.Models;
 using COA.CodeSearch.McpServer.ResponseBuilders;
 using Microsoft.Extensions.Logging;
@@ -30,6 +35,8 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
     private readonly GoToDefinitionResponseBuilder _responseBuilder;
     private readonly ILogger<GoToDefinitionTool> _logger;
     private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILanguageServerService? _languageServers;
+    private readonly IGoBuildProfileService? _goBuildProfiles;
 
     /// <summary>
     /// Initializes a new instance of the GoToDefinitionTool with required dependencies.
@@ -41,6 +48,8 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
     /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
     /// <param name="logger">Logger instance</param>
     /// <param name="sqliteService">SQLite symbol service for symbol lookups</param>
+    /// <param name="languageServers">Optional language servers that resolve positions precisely</param>
+    /// <param name="goBuildProfiles">Optional Go build profiles, to prefer definitions built for the workspace's platform</param>
     public GoToDefinitionTool(
         IServiceProvider serviceProvider,
         IResponseCacheService cacheService,
@@ -48,7 +57,9 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
         ICacheKeyGenerator keyGenerator,
         IPathResolutionService pathResolutionService,
         ILogger<GoToDefinitionTool> logger,
-        ISQLiteSymbolService? sqliteService = null) : base(serviceProvider, logger)
+        ISQLiteSymbolService? sqliteService = null,
+        ILanguageServerService? languageServers = null,
+        IGoBuildProfileService? goBuildProfiles = null) : base(serviceProvider, logger)
     {
         _cacheService = cacheService;
         _storageService = storageService;
@@ -57,6 +68,8 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
         _responseBuilder = new GoToDefinitionResponseBuilder(logger as ILogger<GoToDefinitionResponseBuilder>, storageService);
         _logger = logger;
         _sqliteService = sqliteService;
+        _languageServers = languageServers;
+        _goBuildProfiles = goBuildProfiles;
     }
 
     /// <summary>
@@ -95,12 +108,85 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
         CancellationToken cancellationToken)
     {
         // Validate required parameters
-        var symbolName = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol));
+        var symbolArgument = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol));
+
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return new AIOptimizedResponse<SymbolDefinition>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "INVALID_COLUMN_ENCODING",
+                    Message = $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[] { "Use 'utf-16' for LSP positions or 'utf-8' for byte columns" }
+                    }
+                }
+            };
+        }
 
         // Use provided workspace path or default to current workspace
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
-            : Path.GetFullPath(parameters.WorkspacePath);
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
+
+        // An anchor names one particular symbol, so it bypasses the lookup by name below
+        var anchorResolution = await ResolveAnchorArgumentAsync(symbolArgument, workspacePath, cancellationToken);
+        var symbolName = anchorResolution != null
+            ? anchorResolution.Symbol?.Name
+            : await ResolveSymbolArgumentAsync(symbolArgument, workspacePath, parameters.ColumnEncoding, cancellationToken);
+        if (symbolName == null && anchorResolution != null)
+        {
+            return new AIOptimizedResponse<SymbolDefinition>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "ANCHOR_NOT_FOUND",
+                    Message = $"Anchor '{symbolArgument}' no longer resolves to a symbol",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "The symbol was deleted, or moved and edited at the same time",
+                            "Use symbol_search to find it by name and store its new anchor"
+                        }
+                    }
+                }
+            };
+        }
+        if (symbolName == null)
+        {
+            return new AIOptimizedResponse<SymbolDefinition>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "NO_SYMBOL_AT_POSITION",
+                    Message = $"No identifier at position '{symbolArgument}'",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Check the line is 1-based and the column 0-based",
+                            "Pass columnEncoding 'utf-8' if the column counts bytes",
+                            "Pass the symbol name instead"
+                        }
+                    }
+                }
+            };
+        }
+
+        // A Go selector - Admin.Name, or a position on admin.Name - names a member through a type, which may
+        // have it only by promotion from an embedded field
+        var selector = anchorResolution == null
+            ? await FindGoSelectorAsync(symbolArgument, symbolName, workspacePath, parameters.ColumnEncoding, cancellationToken)
+            : null;
+        if (selector != null)
+            symbolName = selector.Value.Member;
 
         // Generate cache key
         var cacheKey = _keyGenerator.GenerateKey(Name, parameters);
@@ -144,15 +230,39 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
                 };
             }
 
+            // A position can be resolved exactly by a language server; its answer then picks among the index's candidates
+            var precise = anchorResolution == null
+                ? await FindPreciseDefinitionAsync(symbolArgument, workspacePath, parameters.ColumnEncoding, cancellationToken)
+                : null;
+
             // Query SQLite for the symbol (source of truth)
             _logger.LogDebug("Querying SQLite for symbol '{Symbol}' (caseSensitive={CaseSensitive})",
                 symbolName, parameters.CaseSensitive);
 
-            var sqliteSymbols = await _sqliteService.GetSymbolsByNameAsync(
-                workspacePath,
-                symbolName,
-                parameters.CaseSensitive,
-                cancellationToken);
+            var sqliteSymbols = anchorResolution?.Symbol != null
+                ? new List<JulieSymbol> { anchorResolution.Symbol }
+                : await _sqliteService.GetSymbolsByNameAsync(
+                    workspacePath,
+                    symbolName,
+                    parameters.CaseSensitive,
+                    cancellationToken);
+
+            if (selector != null && precise == null)
+            {
+                var member = await FindGoMemberAsync(workspacePath, selector.Value.Type, symbolName, selector.Value.FilePath, cancellationToken);
+                if (member != null)
+                {
+                    var promoted = await MapGoMemberToDefinitionAsync(member, sqliteSymbols, workspacePath, parameters.ContextLines, cancellationToken);
+                    return await BuildDefinitionResponseAsync(promoted, symbolName, "sqlite", parameters, cacheKey, cache: true);
+                }
+            }
+
+            if ((sqliteSymbols == null || sqliteSymbols.Count == 0) && precise != null)
+            {
+                // Defined outside what the index covers (generated code, dependencies) - the server still knows where
+                var external = await MapLocationToDefinitionAsync(precise.Value.Location, symbolName, workspacePath, parameters.ContextLines, cancellationToken);
+                return await BuildDefinitionResponseAsync(external, symbolName, precise.Value.Server, parameters, cacheKey, cache: true);
+            }
 
             if (sqliteSymbols == null || sqliteSymbols.Count == 0)
             {
@@ -179,8 +289,31 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
                 };
             }
 
-            // Find best match (prefer types over methods)
-            var matchingSymbol = FindBestMatch(sqliteSymbols, symbolName, parameters.CaseSensitive);
+            var candidates = await PreferInBuildAsync(workspacePath, FindCandidates(sqliteSymbols, symbolName, parameters.CaseSensitive),
+                cancellationToken);
+            if (anchorResolution == null && await FindTaggedTableAsync(symbolArgument, symbolName, workspacePath, cancellationToken) is { } table)
+                candidates = await PreferTableColumnsAsync(workspacePath, table, candidates, cancellationToken);
+            var preciseSymbol = precise != null ? FindSymbolAt(candidates, precise.Value.Location) : null;
+            if (precise != null && preciseSymbol == null)
+            {
+                // The server disagrees with every indexed candidate; its location is the precise one
+                _logger.LogInformation("{Server} resolved '{Symbol}' to {File}:{Line}, which the index has no definition for",
+                    precise.Value.Server, symbolName, precise.Value.Location.FilePath, precise.Value.Location.Range.Start.Line + 1);
+                var located = await MapLocationToDefinitionAsync(precise.Value.Location, symbolName, workspacePath, parameters.ContextLines, cancellationToken);
+                return await BuildDefinitionResponseAsync(located, symbolName, precise.Value.Server, parameters, cacheKey, cache: true);
+            }
+
+            // Several definitions in different places: ask which one was meant, else prefer types over methods
+            var ambiguous = preciseSymbol == null && candidates.Select(s => s.FilePath).Distinct().Count() > 1;
+            var chosenSymbol = ambiguous
+                ? await AskUserToChooseAsync(
+                    $"'{symbolName}' is defined in {candidates.Count} places. Which definition do you want?",
+                    "Definition",
+                    candidates,
+                    s => $"{s.Kind} {s.Signature ?? s.Name} - {s.FilePath}:{s.StartLine}",
+                    cancellationToken)
+                : null;
+            var matchingSymbol = preciseSymbol ?? chosenSymbol ?? FindBestMatch(candidates);
 
             if (matchingSymbol == null)
             {
@@ -204,31 +337,18 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
                 workspacePath,
                 parameters.ContextLines,
                 cancellationToken);
+            definition.AnchorStatus = anchorResolution?.Status;
+            await AddBuildConstraintsAsync(_goBuildProfiles, workspacePath, new[] { definition }, cancellationToken);
 
-            // Build response
-            var context = new ResponseContext
-            {
-                ResponseMode = "adaptive",
-                TokenLimit = parameters.ContextLines * 50 + 500,
-                StoreFullResults = false,
-                ToolName = Name,
-                CacheKey = cacheKey,
-                CustomMetadata = new Dictionary<string, object>
-                {
-                    ["symbolName"] = symbolName,
-                    ["source"] = "sqlite"
-                }
-            };
-
-            var response = await _responseBuilder.BuildResponseAsync(definition, context);
+            // Don't cache an ambiguous name, so the next caller gets asked too,
+            // or an anchor, which has to be re-resolved as files change
+            var response = await BuildDefinitionResponseAsync(definition, symbolName, precise?.Server ?? "sqlite", parameters, cacheKey,
+                cache: !ambiguous && anchorResolution == null);
 
-            // Cache the response
-            if (!parameters.NoCache && response.Success)
+            // References and the defining file's outline are almost always asked for next
+            if (response.Success)
             {
-                await _cacheService.SetAsync(cacheKey, response, new CacheEntryOptions
-                {
-                    AbsoluteExpiration = TimeSpan.FromMinutes(10)
-                });
+                PrefetchSymbol(parameters.WorkspacePath, workspacePath, matchingSymbol.Name, definition.FilePath);
             }
 
             return response;
@@ -258,18 +378,307 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
         }
     }
 
+    private async Task<AIOptimizedResponse<SymbolDefinition>> BuildDefinitionResponseAsync(
+        SymbolDefinition definition,
+        string symbolName,
+        string source,
+        GoToDefinitionParameters parameters,
+        string cacheKey,
+        bool cache)
+    {
+        var context = new ResponseContext
+        {
+            ResponseMode = "adaptive",
+            TokenLimit = parameters.ContextLines * 50 + 500,
+            StoreFullResults = false,
+            ToolName = Name,
+            CacheKey = cacheKey,
+            CustomMetadata = new Dictionary<string, object>
+            {
+                ["symbolName"] = symbolName,
+                ["source"] = source
+            }
+        };
+
+        var response = await _responseBuilder.BuildResponseAsync(definition, context);
+
+        if (!parameters.NoCache && response.Success && cache)
+        {
+            await _cacheService.SetAsync(cacheKey, response, new CacheEntryOptions
+            {
+                AbsoluteExpiration = TimeSpan.FromMinutes(10)
+            });
+        }
+
+        return response;
+    }
+
     /// <summary>
-    /// Finds the best matching symbol from a list based on case sensitivity.
+    /// Asks the language server for the file's language where a "path:line:column" argument points.
+    /// Null for symbol names, when no server is configured or running, or when it has no answer.
     /// </summary>
-    private JulieSymbol? FindBestMatch(List<JulieSymbol> symbols, string symbolName, bool caseSensitive)
+    private async Task<(LspLocation Location, string Server)?> FindPreciseDefinitionAsync(
+        string symbolArgument,
+        string workspacePath,
+        string columnEncoding,
+        CancellationToken cancellationToken)
     {
-        if (symbols == null || symbols.Count == 0)
+        if (_languageServers is not { IsEnabled: true }
+            || !SourcePositions.TryParseLocation(symbolArgument, out var filePath, out var line, out var column))
+        {
             return null;
+        }
+
+        var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+        var server = _languageServers.GetServerFor(fullPath);
+        if (server == null || !File.Exists(fullPath))
+            return null;
+
+        var character = string.Equals(columnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+            ? await CreateSourcePositions(workspacePath).ToUtf16ColumnAsync(filePath, line, column, cancellationToken)
+            : column;
+
+        var locations = await _languageServers.GetDefinitionAsync(workspacePath, fullPath, new LspPosition(line - 1, character), cancellationToken);
+        return locations is { Count: > 0 } ? (locations[0], server) : null;
+    }
 
-        // Filter by case sensitivity
+    /// <summary>
+    /// The type and member of a Go selector: a qualified name (<c>Admin.Name</c>, <c>(*Admin).Name</c>), or a
+    /// position on the member of <c>x.Name</c> where x is a type or was declared with one. Null otherwise.
+    /// </summary>
+    private async Task<(string Type, string Member, string? FilePath)?> FindGoSelectorAsync(
+        string symbolArgument,
+        string symbolName,
+        string workspacePath,
+        string columnEncoding,
+        CancellationToken cancellationToken)
+    {
+        if (!SourcePositions.TryParseLocation(symbolArgument, out var filePath, out var line, out var column))
+        {
+            var qualified = Regex.Match(symbolName.Trim(), @"^\(?\*?(?<type>[A-Za-z_][\w.]*?)\)?\.(?<member>[A-Za-z_]\w*)$");
+            return qualified.Success ? (qualified.Groups["type"].Value, qualified.Groups["member"].Value, null) : null;
+        }
+        if (!filePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+            return null;
+
+        var positions = CreateSourcePositions(workspacePath);
+        var text = await positions.GetLineAsync(filePath, line, cancellationToken);
+        if (text == null)
+            return null;
+
+        var index = string.Equals(columnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+            ? SourcePositions.ByteToUtf16Column(text, column)
+            : column;
+        var selector = Regex.Matches(text, $@"\b(?<operand>[A-Za-z_]\w*)\s*\.\s*(?<member>{Regex.Escape(symbolName)})\b")
+            .FirstOrDefault(m => m.Groups["member"].Index <= index && index <= m.Groups["member"].Index + m.Groups["member"].Length);
+        if (selector == null)
+            return null;
+
+        var lines = new List<string>();
+        for (var i = 1; i <= line; i++)
+            lines.Add(await positions.GetLineAsync(filePath, i, cancellationToken) ?? string.Empty);
+
+        var operand = selector.Groups["operand"].Value;
+        return (GoMethodSets.DeclaredTypeOf(lines, line, operand) ?? operand, symbolName, filePath);
+    }
+
+    /// <summary>
+    /// The table a Go struct tag maps the column at a position to - users for user_id in <c>db:"user_id"</c> on a
+    /// struct mapped to users - or null when the position isn't on a tagged column name
+    /// </summary>
+    private async Task<string?> FindTaggedTableAsync(string symbolArgument, string symbolName, string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        if (!SourcePositions.TryParseLocation(symbolArgument, out var filePath, out var line, out _)
+            || !filePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+            return null;
+
+        var positions = CreateSourcePositions(workspacePath);
+        var text = await positions.GetLineAsync(filePath, line, cancellationToken);
+        if (text == null || !text.Contains('`'))
+            return null;
+
+        var lines = new List<string>();
+        for (var i = 1; await positions.GetLineAsync(filePath, i, cancellationToken) is { } next; i++)
+            lines.Add(next);
+
+        var (entities, tableNames) = OrmSchemaParser.ParseGo(filePath, lines.ToArray());
+        var entity = entities.FirstOrDefault(e => e.Columns.Any(c => c.Line == line && c.Column.Equals(symbolName, StringComparison.OrdinalIgnoreCase)));
+        return entity == null ? null : tableNames.GetValueOrDefault(entity.Name) ?? entity.Table;
+    }
+
+    /// <summary>
+    /// The column candidates declared in the named table, so a column name shared by several tables resolves to
+    /// the one a struct maps; all candidates when no table in the index declares it
+    /// </summary>
+    private async Task<List<JulieSymbol>> PreferTableColumnsAsync(string workspacePath, string table, List<JulieSymbol> candidates,
+        CancellationToken cancellationToken)
+    {
+        var inTable = new List<JulieSymbol>();
+        foreach (var candidate in candidates.Where(c => c.Kind == "column" && c.ParentId != null))
+        {
+            var symbols = await _sqliteService!.GetSymbolsForFileAsync(workspacePath, candidate.FilePath, cancellationToken);
+            if (symbols.FirstOrDefault(s => s.Id == candidate.ParentId) is { } parent && parent.Name.Equals(table, StringComparison.OrdinalIgnoreCase))
+                inTable.Add(candidate);
+        }
+        return inTable.Count > 0 ? inTable : candidates;
+    }
+
+    /// <summary>
+    /// The field or method a Go type has under this name, declared on it or promoted through embedded fields;
+    /// null when the type isn't a Go type in the index or the selector is ambiguous. A type named in a file
+    /// resolves to that file's package first.
+    /// </summary>
+    private async Task<GoMemberResolution?> FindGoMemberAsync(string workspacePath, string typeName, string member, string? fromFile,
+        CancellationToken cancellationToken)
+    {
+        var files = new List<GoFileDeclarations>();
+        foreach (var file in await _sqliteService!.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            if (!file.Path.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var fullPath = Path.IsPathRooted(file.Path) ? file.Path : Path.Combine(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content != null)
+                files.Add(GoMethodSets.Parse(fullPath, content.Replace("\r\n", "\n").Split('\n')));
+        }
+
+        var package = fromFile == null
+            ? string.Empty
+            : Path.GetDirectoryName(Path.IsPathRooted(fromFile) ? fromFile : Path.Combine(workspacePath, fromFile))?.Replace('\\', '/') ?? string.Empty;
+        var types = files.SelectMany(f => f.Types).ToList();
+        var type = GoMethodSets.Resolve(typeName, package, types, "struct") ?? GoMethodSets.Resolve(typeName, package, types, null);
+        return type != null ? GoMethodSets.FindMember(type, member, files) : null;
+    }
+
+    /// <summary>
+    /// A definition for a Go member found through its type, from the indexed symbol at its line when there is one
+    /// </summary>
+    private async Task<SymbolDefinition> MapGoMemberToDefinitionAsync(
+        GoMemberResolution member,
+        List<JulieSymbol>? symbols,
+        string workspacePath,
+        int contextLines,
+        CancellationToken cancellationToken)
+    {
+        var path = Path.GetFullPath(member.FilePath);
+        var symbol = symbols?.FirstOrDefault(s => s.StartLine == member.Line && s.Name == member.Name
+            && string.Equals(Path.GetFullPath(Path.IsPathRooted(s.FilePath) ? s.FilePath : Path.Combine(workspacePath, s.FilePath)), path,
+                StringComparison.OrdinalIgnoreCase));
+
+        SymbolDefinition definition;
+        if (symbol != null)
+        {
+            definition = await MapJulieSymbolToDefinitionAsync(symbol, workspacePath, contextLines, cancellationToken);
+        }
+        else
+        {
+            var text = await CreateSourcePositions(workspacePath).GetLineAsync(member.FilePath, member.Line, cancellationToken) ?? string.Empty;
+            var character = Math.Max(0, Regex.Match(text, $@"\b{Regex.Escape(member.Name)}\b").Index);
+            var position = new LspPosition(member.Line - 1, character);
+            definition = await MapLocationToDefinitionAsync(new LspLocation(member.FilePath, new LspRange(position, position)),
+                member.Name, workspacePath, contextLines, cancellationToken);
+            definition.Kind = member.Kind;
+            definition.Language = "go";
+        }
+
+        definition.ContainingType = member.DeclaringType.Name;
+        definition.QualifiedName = $"{member.DeclaringType.Name}.{member.Name}";
+        definition.PromotedVia = member.PromotedVia.Count > 0 ? member.PromotedVia : null;
+        return definition;
+    }
+
+    /// <summary>
+    /// The candidate whose declaration spans the location's line in the same file
+    /// </summary>
+    private static JulieSymbol? FindSymbolAt(List<JulieSymbol> candidates, LspLocation location)
+    {
+        var line = location.Range.Start.Line + 1;
+        var path = Path.GetFullPath(location.FilePath);
+        return candidates
+            .Where(s => string.Equals(Path.GetFullPath(s.FilePath), path, StringComparison.OrdinalIgnoreCase)
+                        && s.StartLine <= line && line <= Math.Max(s.StartLine, s.EndLine))
+            .OrderBy(s => s.EndLine - s.StartLine)
+            .FirstOrDefault();
+    }
+
+    /// <summary>
+    /// A definition for a location only the language server knows, with the snippet read from the file
+    /// </summary>
+    private async Task<SymbolDefinition> MapLocationToDefinitionAsync(
+        LspLocation location,
+        string symbolName,
+        string workspacePath,
+        int contextLines,
+        CancellationToken cancellationToken)
+    {
+        var positions = CreateSourcePositions(workspacePath);
+        var line = location.Range.Start.Line + 1;
+        var definition = new SymbolDefinition
+        {
+            Name = symbolName,
+            Kind = "unknown",
+            Signature = (await positions.GetLineAsync(location.FilePath, line, cancellationToken))?.Trim() ?? symbolName,
+            FilePath = location.FilePath,
+            Line = line,
+            Column = await positions.ToByteColumnAsync(location.FilePath, line, location.Range.Start.Character, cancellationToken),
+            Utf16Column = location.Range.Start.Character,
+            Score = 1.0f
+        };
+
+        if (contextLines > 0)
+        {
+            var snippet = new List<string>();
+            for (var i = Math.Max(1, line - contextLines); i <= line + contextLines; i++)
+            {
+                var text = await positions.GetLineAsync(location.FilePath, i, cancellationToken);
+                if (text == null)
+                    break;
+                snippet.Add(text);
+            }
+            definition.Snippet = snippet.Count > 0 ? string.Join("\n", snippet) : null;
+        }
+
+        return definition;
+    }
+
+    /// <summary>
+    /// Symbols whose name matches exactly, honoring case sensitivity.
+    /// </summary>
+    private static List<JulieSymbol> FindCandidates(List<JulieSymbol> symbols, string symbolName, bool caseSensitive)
+    {
         var comparisonType = caseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase;
-        var matches = symbols.Where(s => s.Name.Equals(symbolName, comparisonType)).ToList();
+        return symbols.Where(s => s.Name.Equals(symbolName, comparisonType)).ToList();
+    }
 
+    /// <summary>
+    /// Drops Go candidates whose build constraints exclude the workspace's profile - parse_linux.go and
+    /// parse_windows.go defining the same function - unless that would leave none
+    /// </summary>
+    private async Task<List<JulieSymbol>> PreferInBuildAsync(string workspacePath, List<JulieSymbol> candidates,
+        CancellationToken cancellationToken)
+    {
+        if (_goBuildProfiles == null || candidates.Count < 2)
+            return candidates;
+
+        var profile = _goBuildProfiles.GetProfile(workspacePath);
+        var inBuild = new List<JulieSymbol>();
+        foreach (var candidate in candidates)
+        {
+            var constraint = await _goBuildProfiles.GetConstraintAsync(workspacePath, candidate.FilePath, cancellationToken);
+            if (GoBuildConstraints.Matches(constraint, profile))
+                inBuild.Add(candidate);
+        }
+        return inBuild.Count > 0 ? inBuild : candidates;
+    }
+
+    /// <summary>
+    /// Finds the best matching symbol among the candidates.
+    /// </summary>
+    private JulieSymbol? FindBestMatch(List<JulieSymbol> matches)
+    {
         if (matches.Count == 0)
             return null;
 
@@ -304,10 +713,16 @@ public class GoToDefinitionTool : CodeSearchToolBase<GoToDefinitionParameters, A
             FilePath = symbol.FilePath,
             Line = symbol.StartLine,
             Column = symbol.StartColumn,
+            ByteOffset = symbol.StartByte,
             Language = symbol.Language,
             Score = 1.0f // SQLite exact match gets perfect score
         };
 
+        var positions = CreateSourcePositions(workspacePath);
+        definition.Utf16Column = await positions.ToUtf16ColumnAsync(symbol.FilePath, symbol.StartLine, symbol.StartColumn, cancellationToken);
+        await AddGoGenericsAsync(positions, new[] { definition }, cancellationToken);
+        definition.Anchor = await CreateAnchorAsync(workspacePath, symbol, cancellationToken);
+
         // Add visibility as modifiers
         if (!string.IsNullOrEmpty(symbol.Visibility))
         {
@@ -0,0 +1,189 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.GraphQL;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Lists the types and fields of the workspace's GraphQL schema with the resolvers serving each field and the
+/// models backing each type, and reports fields nothing resolves and resolvers the schema does not declare
+/// </summary>
+public class GraphQLLinksTool : CodeSearchToolBase<GraphQLLinksParameters, AIOptimizedResponse<GraphQLLinksResult>>
+{
+    private static readonly string[] DriftKinds = { "unresolved-field", "orphan-resolver" };
+
+    private readonly IGraphQLIndexService _graphQLIndexService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<GraphQLLinksTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the GraphQLLinksTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="graphQLIndexService">GraphQL schema index</param>
+    /// <param name="sqliteService">SQLite symbol service for the index check</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public GraphQLLinksTool(
+        IServiceProvider serviceProvider,
+        IGraphQLIndexService graphQLIndexService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<GraphQLLinksTool> logger) : base(serviceProvider, logger)
+    {
+        _graphQLIndexService = graphQLIndexService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.GraphQLLinks;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "FROM SCHEMA TO RESOLVER. Indexes GraphQL SDL (.graphql/.graphqls files and gql templates) and links each field to the " +
+        "resolver serving it - gqlgen, Hot Chocolate, NestJS, TypeGraphQL or an Apollo resolver map - and each object type to " +
+        "its model. Reports drift: root fields without a resolver, model fields nothing resolves, resolvers for fields the schema lacks.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Loads the schema index and applies the filter.
+    /// </summary>
+    /// <param name="parameters">Type filter, drift switch and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Types and drift with their code locations</returns>
+    protected override async Task<AIOptimizedResponse<GraphQLLinksResult>> ExecuteInternalAsync(
+        GraphQLLinksParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try graphql_links again");
+        }
+
+        var index = await _graphQLIndexService.GetIndexAsync(workspacePath, cancellationToken);
+        if (index.Types.Count == 0)
+        {
+            return CreateErrorResponse("NO_SCHEMA", "No GraphQL schema was found in this workspace",
+                "The schema is read from indexed .graphql, .graphqls and .gql files and from gql/graphql templates in scripts",
+                "Code-first schemas without SDL are not indexed - export the schema to a .graphql file to link it");
+        }
+
+        // 'User' shows a type, 'Query.user' one field of it
+        var filter = parameters.Type?.Trim();
+        var typeName = filter?.Split('.')[0];
+        var fieldName = filter?.Contains('.') == true ? filter[(filter.IndexOf('.') + 1)..] : null;
+
+        var types = index.Types.Where(t => string.IsNullOrEmpty(typeName) || t.Name.Equals(typeName, StringComparison.OrdinalIgnoreCase)).ToList();
+        if (types.Count == 0)
+        {
+            return CreateErrorResponse("TYPE_NOT_FOUND", $"The schema has no type named '{typeName}'",
+                "Run graphql_links without type to list every type");
+        }
+        if (fieldName != null)
+        {
+            var type = types[0];
+            var fields = type.Fields.Where(f => f.Name.Equals(fieldName, StringComparison.OrdinalIgnoreCase)).ToList();
+            if (fields.Count == 0)
+            {
+                return CreateErrorResponse("FIELD_NOT_FOUND", $"{type.Name} has no field named '{fieldName}'",
+                    $"Run graphql_links with type '{type.Name}' to list its fields");
+            }
+            types = new List<GraphQLType>
+            {
+                new()
+                {
+                    Name = type.Name, Kind = type.Kind, Implements = type.Implements, Members = type.Members,
+                    FilePath = type.FilePath, Line = type.Line, Models = type.Models, Fields = fields
+                }
+            };
+        }
+
+        var subjects = types.SelectMany(t => t.Fields.Select(f => $"{t.Name}.{f.Name}")).ToHashSet(StringComparer.OrdinalIgnoreCase);
+        var drift = index.Drift
+            .Where(d => string.IsNullOrEmpty(filter) || subjects.Contains(d.Subject)
+                || fieldName == null && d.Subject.StartsWith(types[0].Name + ".", StringComparison.OrdinalIgnoreCase))
+            .OrderBy(d => Array.IndexOf(DriftKinds, d.Kind))
+            .ThenBy(d => d.FilePath, StringComparer.Ordinal)
+            .ThenBy(d => d.Line)
+            .ToList();
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var result = new GraphQLLinksResult
+        {
+            SchemaFiles = index.SchemaFiles,
+            RootTypes = index.RootTypes,
+            Types = parameters.DriftOnly ? new List<GraphQLType>() : types.Take(limit).ToList(),
+            Drift = drift.Take(limit).ToList(),
+            DriftCounts = drift.GroupBy(d => d.Kind).ToDictionary(g => g.Key, g => g.Count()),
+            TotalTypes = types.Count,
+            Truncated = drift.Count > limit || !parameters.DriftOnly && types.Count > limit
+        };
+
+        _logger.LogDebug("graphql_links: {Types} types, {Drift} drift", types.Count, drift.Count);
+
+        var objectFields = types.Where(t => t.Kind == "type").SelectMany(t => t.Fields).ToList();
+        var resolved = objectFields.Count(f => f.Resolvers.Count > 0);
+        var response = new AIOptimizedResponse<GraphQLLinksResult>
+        {
+            Success = true,
+            Data = new AIResponseData<GraphQLLinksResult> { Results = result },
+            Message = $"{index.SchemaFiles.Count} schema file(s), {types.Count} type(s): {resolved}/{objectFields.Count} object field(s) resolved" +
+                      (drift.Count > 0
+                          ? "; drift: " + string.Join(", ", DriftKinds.Where(result.DriftCounts.ContainsKey).Select(k => $"{result.DriftCounts[k]} {k}"))
+                          : "; no drift")
+        };
+
+        var insights = new List<string>();
+        if (index.Resolvers == 0)
+        {
+            insights.Add("No resolvers were recognized - gqlgen receivers, Hot Chocolate type attributes, NestJS/TypeGraphQL decorators and Query/Mutation resolver maps are");
+        }
+        if (objectFields.Any(f => f.Resolvers.Any(r => r.Kind == "field")))
+        {
+            insights.Add("Resolvers of kind 'field' are model members resolving the field by default");
+        }
+        if (types.Any(t => t.Kind == "type" && t.Models.Count == 0 && !index.RootTypes.ContainsValue(t.Name)))
+        {
+            insights.Add("Fields of types without a model are not checked - a parent resolver may return any object for them");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped at {limit} entries - filter by type or use driftOnly");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<GraphQLLinksResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,155 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Graph;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Answers chained navigation questions ("callers of X inside package Y that implement Z") in one call by running
+/// a pipeline of filters and traversals over the symbol database, instead of joining several tools' outputs
+/// </summary>
+public class GraphQueryTool : CodeSearchToolBase<GraphQueryParameters, AIOptimizedResponse<GraphQueryResult>>
+{
+    private readonly IGraphQueryService _graphQueryService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<GraphQueryTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the GraphQueryTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="graphQueryService">Query parser and evaluator</param>
+    /// <param name="sqliteService">SQLite symbol service the graph is read from</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public GraphQueryTool(
+        IServiceProvider serviceProvider,
+        IGraphQueryService graphQueryService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<GraphQueryTool> logger) : base(serviceProvider, logger)
+    {
+        _graphQueryService = graphQueryService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.GraphQuery;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "CHAINED NAVIGATION IN ONE CALL - Filters and traversals over the code graph, separated by '|'. " +
+        "e.g. 'symbol:Charge | callers | in:src/Billing | implements:IPaymentHandler' finds callers of Charge under src/Billing " +
+        "whose class implements IPaymentHandler. Returns the symbols with locations and the count after each step.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Parses and runs the query against the workspace's symbol database.
+    /// </summary>
+    /// <param name="parameters">Query, matching options and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Resulting symbols and per-step counts</returns>
+    protected override async Task<AIOptimizedResponse<GraphQueryResult>> ExecuteInternalAsync(
+        GraphQueryParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try graph_query again");
+        }
+
+        GraphQueryOutcome outcome;
+        try
+        {
+            var steps = _graphQueryService.Parse(parameters.Query);
+            outcome = await _graphQueryService.ExecuteAsync(workspacePath, steps, parameters.CaseSensitive, cancellationToken);
+        }
+        catch (GraphQueryException ex)
+        {
+            return CreateErrorResponse(ex.Code, ex.Message,
+                "Separate steps with '|' and start with a filter such as symbol:Name",
+                "Example: symbol:Charge | callers | in:src/Billing | implements:IPaymentHandler");
+        }
+
+        var positions = CreateSourcePositions(workspacePath);
+        var result = new GraphQueryResult
+        {
+            Query = parameters.Query,
+            Steps = outcome.Steps,
+            TotalCount = outcome.Symbols.Count,
+            Truncated = outcome.Truncated
+        };
+
+        foreach (var symbol in outcome.Symbols.Take(parameters.MaxResults))
+        {
+            result.Symbols.Add(new SymbolDefinition
+            {
+                Name = symbol.Name,
+                Kind = symbol.Kind,
+                Signature = symbol.Signature ?? symbol.Name,
+                FilePath = symbol.FilePath,
+                Line = symbol.StartLine,
+                Column = symbol.StartColumn,
+                ByteOffset = symbol.StartByte,
+                Language = symbol.Language,
+                Anchor = await CreateAnchorAsync(workspacePath, symbol, cancellationToken),
+                Modifiers = string.IsNullOrEmpty(symbol.Visibility) ? new List<string>() : new List<string> { symbol.Visibility }
+            });
+        }
+        await AddUtf16ColumnsAsync(positions, result.Symbols, cancellationToken);
+
+        _logger.LogDebug("graph_query '{Query}' matched {Count} symbols", parameters.Query, result.TotalCount);
+
+        if (result.TotalCount == 0)
+        {
+            var emptiedBy = outcome.Steps.FirstOrDefault(s => s.Count == 0);
+            return CreateResponse(result, emptiedBy != null
+                ? $"No symbols left after '{emptiedBy.Step}'"
+                : "No symbols matched");
+        }
+
+        return CreateResponse(result,
+            $"{result.TotalCount} symbol(s)" +
+            (result.TotalCount > result.Symbols.Count ? $", showing {result.Symbols.Count}" : "") +
+            (result.Truncated ? "; a step hit the node limit, so results may be incomplete" : ""));
+    }
+
+    private static AIOptimizedResponse<GraphQueryResult> CreateResponse(GraphQueryResult result, string message) => new()
+    {
+        Success = true,
+        Data = new AIResponseData<GraphQueryResult> { Results = result },
+        Message = message
+    };
+
+    private static AIOptimizedResponse<GraphQueryResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,274 @@
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.GoTypes;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using COA.CodeSearch.McpServer.Services.Roslyn;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Editor-style hover for the expression at a position. Roslyn (C#), go/types (Go) and configured language
+/// servers answer precisely; otherwise the index's definition of the name under the cursor supplies the
+/// signature and doc comment, without a resolved type.
+/// </summary>
+public class HoverTool : CodeSearchToolBase<HoverParameters, AIOptimizedResponse<HoverResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<HoverTool> _logger;
+    private readonly IRoslynAnalysisService? _roslyn;
+    private readonly IGoTypesService? _goTypes;
+    private readonly ILanguageServerService? _languageServers;
+
+    /// <summary>
+    /// Initializes a new instance of the HoverTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">Symbol database for the index fallback</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="roslyn">Optional Roslyn tier for C#</param>
+    /// <param name="goTypes">Optional go/types tier for Go</param>
+    /// <param name="languageServers">Optional language servers for other languages</param>
+    public HoverTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<HoverTool> logger,
+        IRoslynAnalysisService? roslyn = null,
+        IGoTypesService? goTypes = null,
+        ILanguageServerService? languageServers = null) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+        _roslyn = roslyn;
+        _goTypes = goTypes;
+        _languageServers = languageServers;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.Hover;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "HOVER - What is this? Returns the type, signature and doc comment for the identifier at a position, like hovering in an editor. " +
+        "Compiler-accurate for C# (Roslyn tier), Go (go/types tier) and languages with a configured language server; otherwise the index's definition of the name. " +
+        "Use instead of opening the declaring file to read a signature.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Executes the hover at the position.
+    /// </summary>
+    /// <param name="parameters">Position and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Type, signature and documentation at the position</returns>
+    protected override async Task<AIOptimizedResponse<HoverResult>> ExecuteInternalAsync(
+        HoverParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var position = ValidateRequired(parameters.Position, nameof(parameters.Position));
+        if (!SourcePositions.TryParseLocation(position, out var filePath, out var line, out var column))
+        {
+            return Failure("INVALID_POSITION", $"'{position}' is not a position 'path:line:column'",
+                "Use a 1-based line and 0-based column, e.g. src/Services/UserService.cs:42:16");
+        }
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return Failure("INVALID_COLUMN_ENCODING", $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                "Use 'utf-16' for LSP positions or 'utf-8' for byte columns");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+
+        var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+        var positions = CreateSourcePositions(workspacePath);
+        var lineText = await positions.GetLineAsync(filePath, line, cancellationToken);
+        if (lineText == null)
+        {
+            return Failure("INVALID_POSITION", $"Line {line} of {filePath} does not exist",
+                "Check the path is relative to the workspace and the line is 1-based");
+        }
+
+        var utf8 = string.Equals(parameters.ColumnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase);
+        var character = utf8 ? SourcePositions.ByteToUtf16Column(lineText, column) : column;
+        var byteColumn = utf8 ? column : SourcePositions.Utf16ToByteColumn(lineText, column);
+        var lspPosition = new LspPosition(line - 1, character);
+
+        var result = await TryPreciseHoverAsync(workspacePath, fullPath, line, byteColumn, lspPosition, cancellationToken);
+        if (result == null)
+        {
+            var name = await positions.GetIdentifierAtAsync(filePath, line, character, SourcePositions.Utf16, cancellationToken);
+            if (name != null)
+            {
+                result = await IndexHoverAsync(workspacePath, fullPath, name, cancellationToken);
+            }
+        }
+
+        if (result == null)
+        {
+            _logger.LogInformation("Nothing to show for hover at {Position}", position);
+            return Failure("NO_HOVER", $"No identifier with a known definition at {position}",
+                "Point the column at an identifier rather than whitespace, a keyword or punctuation",
+                "Run index_workspace if the definition was added recently",
+                "Enable the Roslyn (C#) or go/types (Go) tier, or a language server, for resolved types");
+        }
+
+        var response = new AIOptimizedResponse<HoverResult>
+        {
+            Success = true,
+            Data = new AIResponseData<HoverResult> { Results = result },
+            Message = result.Type != null
+                ? $"{result.Expression}: {result.Type}"
+                : result.Signature ?? result.Markdown?.Split('\n')[0] ?? result.Expression
+        };
+
+        var insights = new List<string>();
+        if (!result.Precise)
+        {
+            insights.Add("From the index's definition of the name, not a compiler - the type is not resolved and the definition may be a same-named symbol");
+            if (result.Candidates > 1)
+            {
+                insights.Add($"{result.Candidates} definitions share the name '{result.Expression}'; this one is in the same file or language when possible");
+            }
+        }
+        if (result.DeclaredAt != null)
+        {
+            insights.Add($"Declared at {result.DeclaredAt}");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        return response;
+    }
+
+    /// <summary>
+    /// Hover from Roslyn for C#, go/types for Go, then the file's language server; null when none answers
+    /// </summary>
+    private async Task<HoverResult?> TryPreciseHoverAsync(string workspacePath, string fullPath, int line, int byteColumn,
+        LspPosition lspPosition, CancellationToken cancellationToken)
+    {
+        try
+        {
+            if (_roslyn != null && _roslyn.Handles(fullPath)
+                && await _roslyn.GetHoverAsync(workspacePath, fullPath, lspPosition, cancellationToken) is { } roslyn)
+            {
+                return new HoverResult
+                {
+                    Expression = roslyn.Expression,
+                    Type = roslyn.Type,
+                    Signature = roslyn.Signature,
+                    Kind = roslyn.SymbolKind,
+                    Documentation = roslyn.Documentation,
+                    Value = roslyn.ConstantValue,
+                    DeclaredAt = roslyn.DeclaredInFile != null ? $"{roslyn.DeclaredInFile}:{roslyn.DeclaredAtLine}" : null,
+                    Source = "roslyn",
+                    Precise = true
+                };
+            }
+
+            if (_goTypes != null && _goTypes.Handles(fullPath)
+                && await _goTypes.TypeOfAsync(workspacePath, fullPath, line, byteColumn, cancellationToken) is { } go)
+            {
+                return new HoverResult
+                {
+                    Expression = go.Expression,
+                    Type = go.Type,
+                    Signature = go.Object?.Signature,
+                    Kind = go.Object?.Kind ?? go.Mode,
+                    Documentation = go.Object?.Doc is { Length: > 0 } doc ? doc : null,
+                    Value = go.Value,
+                    DeclaredAt = go.Object?.Declared is { } declared ? $"{declared.File}:{declared.Line}" : null,
+                    Source = "go/types",
+                    Precise = true
+                };
+            }
+
+            if (_languageServers is { IsEnabled: true } && _languageServers.GetServerFor(fullPath) is { } server
+                && await _languageServers.GetHoverAsync(workspacePath, fullPath, lspPosition, cancellationToken) is { } markdown)
+            {
+                var name = await CreateSourcePositions(workspacePath)
+                    .GetIdentifierAtAsync(fullPath, line, lspPosition.Character, SourcePositions.Utf16, cancellationToken);
+                return new HoverResult
+                {
+                    Expression = name ?? string.Empty,
+                    Markdown = markdown,
+                    Source = server,
+                    Precise = true
+                };
+            }
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "Precise hover failed for {File}:{Line}, using the index", fullPath, line);
+        }
+        return null;
+    }
+
+    /// <summary>
+    /// The index's definition of <paramref name="name"/>: one in the same file first, then one in a file with
+    /// the same extension, then any
+    /// </summary>
+    private async Task<HoverResult?> IndexHoverAsync(string workspacePath, string fullPath, string name,
+        CancellationToken cancellationToken)
+    {
+        var definitions = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken))
+            .Where(s => s.Name == name)
+            .ToList();
+        if (definitions.Count == 0)
+            return null;
+
+        var file = Path.GetFullPath(fullPath);
+        var extension = Path.GetExtension(fullPath);
+        var definition = definitions
+            .OrderByDescending(s => string.Equals(FullPath(workspacePath, s), file, StringComparison.OrdinalIgnoreCase))
+            .ThenByDescending(s => string.Equals(Path.GetExtension(s.FilePath), extension, StringComparison.OrdinalIgnoreCase))
+            .ThenBy(s => s.FilePath, StringComparer.OrdinalIgnoreCase)
+            .ThenBy(s => s.StartLine)
+            .First();
+
+        return new HoverResult
+        {
+            Expression = name,
+            Signature = definition.Signature,
+            Kind = definition.Kind,
+            Documentation = definition.DocComment != null ? FileSummarizer.CommentText(definition.DocComment.Split('\n')) : null,
+            DeclaredAt = $"{definition.FilePath}:{definition.StartLine}",
+            Source = "index",
+            Candidates = definitions.Count
+        };
+    }
+
+    private static string FullPath(string workspacePath, JulieSymbol symbol) =>
+        Path.GetFullPath(Path.IsPathRooted(symbol.FilePath) ? symbol.FilePath : Path.Combine(workspacePath, symbol.FilePath));
+
+    private static AIOptimizedResponse<HoverResult> Failure(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
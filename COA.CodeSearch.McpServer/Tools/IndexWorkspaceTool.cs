@@ -9,6 +9,7 @@ using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
 using COA.Mcp.Framework.TokenOptimization.ResponseBuilders;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.ColdStorage;
 using COA.CodeSearch.McpServer.Services.Lucene;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.ResponseBuilders;
@@ -30,6 +31,9 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
     private readonly ICacheKeyGenerator _keyGenerator;
     private readonly IndexResponseBuilder _responseBuilder;
     private readonly FileWatcherService? _fileWatcherService;
+    private readonly IIndexRetentionService? _retentionService;
+    private readonly IColdTierService? _coldTier;
+    private readonly IIndexBudgetService? _indexBudget;
     private readonly ILogger<IndexWorkspaceTool> _logger;
 
     // SQLite service for force rebuild database cleanup
@@ -64,6 +68,9 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
         _keyGenerator = keyGenerator;
         _responseBuilder = new IndexResponseBuilder(null, storageService);
         _fileWatcherService = serviceProvider.GetService<FileWatcherService>();
+        _retentionService = serviceProvider.GetService<IIndexRetentionService>();
+        _coldTier = serviceProvider.GetService<IColdTierService>();
+        _indexBudget = serviceProvider.GetService<IIndexBudgetService>();
         _logger = logger;
 
         // SQLite service for force rebuild database cleanup
@@ -98,8 +105,8 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
         // Validate required parameters
         var workspacePath = ValidateRequired(parameters.WorkspacePath, nameof(parameters.WorkspacePath));
         
-        // Resolve to absolute path
-        workspacePath = Path.GetFullPath(workspacePath);
+        // Resolve to absolute path, spelled as on disk so c:\repo and C:\Repo share one index on Windows
+        workspacePath = PlatformPaths.Canonicalize(workspacePath);
         
         if (!Directory.Exists(workspacePath))
         {
@@ -143,8 +150,21 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                 return errorResult;
             }
 
-            // Check if force rebuild is requested or if it's a new index
-            if (parameters.ForceRebuild || initResult.IsNewIndex)
+            // Recovery may have dropped documents (or recreated the index) when it was opened, possibly by an earlier search
+            var recovery = _luceneIndexService.GetRecoveryReport(workspacePath);
+            if (recovery != null && recovery != initResult.Recovery && !recovery.RequiresFullReindex)
+            {
+                recovery = null; // Already reported and nothing left to do
+            }
+            var replayedFiles = 0;
+            
+            // Promote requested cold-tier files before any full pass, so it indexes them at full fidelity too
+            var materialized = parameters.Materialize is { Length: > 0 } targets && _coldTier?.IsEnabled == true
+                ? _coldTier.Materialize(workspacePath, ResolveMaterializeTargets(workspacePath, targets))
+                : Array.Empty<string>();
+
+            // Check if force rebuild is requested, if it's a new index, or if recovery needs a full pass
+            if (parameters.ForceRebuild || initResult.IsNewIndex || recovery?.RequiresFullReindex == true)
             {
                 _logger.LogInformation("Starting full index for workspace: {WorkspacePath}", workspacePath);
 
@@ -153,6 +173,7 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                 {
                     // Step 1: Rebuild Lucene index
                     await _luceneIndexService.ForceRebuildIndexAsync(workspacePath, cancellationToken);
+                    _indexBudget?.ResetPruned(workspacePath); // Start from every file; the budget prunes again if still needed
                     _logger.LogInformation("Force rebuild: Lucene index rebuilt for workspace: {WorkspacePath}", workspacePath);
 
                     // Step 2: Delete SQLite database to force fresh extraction
@@ -244,6 +265,8 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                         DeletedDocumentCount = stats.DeletedDocumentCount,
                         SegmentCount = stats.SegmentCount,
                         IndexSizeBytes = stats.IndexSizeBytes,
+                        StoredFieldsBytes = stats.StoredFieldsBytes,
+                        StoredFieldCompression = stats.StoredFieldCompression,
                         FileTypeDistribution = stats.FileTypeDistribution
                     }
                 };
@@ -260,13 +283,20 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                 
                 // Use response builder to create optimized response
                 var result = await _responseBuilder.BuildResponseAsync(indexResultData, context);
-                
+                await ApplyRetentionAsync(workspacePath, result, cancellationToken);
+                AddRecoveryInsights(recovery, replayedFiles, result);
+                AddMaterializeInsights(parameters, materialized, result);
                 
                 return result;
             }
             else
             {
-                // Index already exists and no force rebuild requested
+                // Index already exists and no force rebuild requested - just finish any writes a crash interrupted
+                replayedFiles = await _fileIndexingService.ReplayInterruptedWritesAsync(workspacePath, cancellationToken);
+                foreach (var file in materialized)
+                {
+                    await _fileIndexingService.IndexFileAsync(workspacePath, file, cancellationToken);
+                }
                 var documentCount = await _luceneIndexService.GetDocumentCountAsync(workspacePath, cancellationToken);
                 var stats = await _luceneIndexService.GetStatisticsAsync(workspacePath, cancellationToken);
                 
@@ -302,6 +332,8 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                         DeletedDocumentCount = stats.DeletedDocumentCount,
                         SegmentCount = stats.SegmentCount,
                         IndexSizeBytes = stats.IndexSizeBytes,
+                        StoredFieldsBytes = stats.StoredFieldsBytes,
+                        StoredFieldCompression = stats.StoredFieldCompression,
                         FileTypeDistribution = stats.FileTypeDistribution
                     }
                 };
@@ -318,7 +350,9 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
                 
                 // Use response builder to create optimized response
                 var result = await _responseBuilder.BuildResponseAsync(indexResultData, context);
-                
+                await ApplyRetentionAsync(workspacePath, result, cancellationToken);
+                AddRecoveryInsights(recovery, replayedFiles, result);
+                AddMaterializeInsights(parameters, materialized, result);
                 
                 return result;
             }
@@ -350,6 +384,104 @@ public class IndexWorkspaceTool : CodeSearchToolBase<IndexWorkspaceParameters, A
         }
     }
     
+    /// <summary>
+    /// Marks the workspace as recently used for LRU eviction and warns when its index exceeds the size quota
+    /// </summary>
+    private async Task ApplyRetentionAsync(string workspacePath, AIOptimizedResponse<IndexWorkspaceResult> result, CancellationToken cancellationToken)
+    {
+        if (_retentionService == null)
+            return;
+
+        await _retentionService.RecordAccessAsync(workspacePath, cancellationToken);
+
+        var quota = _retentionService.CheckQuota(workspacePath);
+        if (quota.IsExceeded && _indexBudget?.IsEnabled == true)
+        {
+            var prune = await _indexBudget.EnforceBudgetAsync(workspacePath, cancellationToken: cancellationToken);
+            result.Insights ??= new List<string>();
+            result.Insights.Add($"Index was {prune.SizeBeforeBytes / (1024 * 1024)} MB, over the {prune.BudgetBytes / (1024 * 1024)} MB budget - pruned " +
+                                $"{prune.Pruned.Count} file(s) ({string.Join(", ", prune.Pruned.GroupBy(p => p.Strategy).Select(g => $"{g.Count()} {g.Key}"))}), " +
+                                $"now {prune.SizeAfterBytes / (1024 * 1024)} MB. Use purge_index with scope 'budget' and dryRun to list pruned files");
+            if (!prune.WithinBudget)
+            {
+                result.Insights.Add("Still over budget after every pruning strategy - add exclusions to .codesearchignore or raise CodeSearch:IndexStorage:MaxWorkspaceSizeMB");
+            }
+        }
+        else if (quota.IsExceeded)
+        {
+            _logger.LogWarning("Index for {WorkspacePath} is {Size} bytes, over the {Quota} byte quota", 
+                workspacePath, quota.SizeBytes, quota.QuotaBytes);
+            result.Insights ??= new List<string>();
+            result.Insights.Add($"Index size {quota.SizeBytes / (1024 * 1024)} MB exceeds the workspace quota of {quota.QuotaBytes / (1024 * 1024)} MB - " +
+                                "add exclusions to .codesearchignore or raise CodeSearch:IndexStorage:MaxWorkspaceSizeMB");
+        }
+    }
+    
+    /// <summary>
+    /// Reports crash or corruption recovery performed when the index was opened
+    /// </summary>
+    private static void AddRecoveryInsights(IndexRecoveryReport? recovery, int replayedFiles, AIOptimizedResponse<IndexWorkspaceResult> result)
+    {
+        if (recovery == null && replayedFiles == 0)
+            return;
+
+        result.Insights ??= new List<string>();
+        if (recovery != null && recovery.Action != IndexRecoveryReport.RecoveryAction.ReplayPending)
+        {
+            result.Insights.Add($"Recovered index: {recovery.Describe()}");
+        }
+        if (replayedFiles > 0)
+        {
+            result.Insights.Add($"Re-indexed {replayedFiles} file(s) whose changes were not committed before the previous shutdown");
+        }
+    }
+    
+    /// <summary>
+    /// Expands materialize entries (files or directories relative to the workspace) to the files they cover
+    /// </summary>
+    private static IEnumerable<string> ResolveMaterializeTargets(string workspacePath, IEnumerable<string> entries)
+    {
+        foreach (var entry in entries.Where(e => !string.IsNullOrWhiteSpace(e)))
+        {
+            var fullPath = Path.GetFullPath(entry, workspacePath);
+            if (!PlatformPaths.IsUnder(fullPath, workspacePath))
+                continue;
+
+            if (File.Exists(fullPath))
+            {
+                yield return fullPath;
+            }
+            else if (Directory.Exists(fullPath))
+            {
+                foreach (var file in PlatformPaths.EnumerateFiles(fullPath))
+                    yield return file;
+            }
+        }
+    }
+    
+    /// <summary>
+    /// Reports which cold-tier files were promoted to full fidelity
+    /// </summary>
+    private void AddMaterializeInsights(IndexWorkspaceParameters parameters, IReadOnlyList<string> materialized, AIOptimizedResponse<IndexWorkspaceResult> result)
+    {
+        if (parameters.Materialize == null || parameters.Materialize.Length == 0)
+            return;
+
+        result.Insights ??= new List<string>();
+        if (_coldTier?.IsEnabled != true)
+        {
+            result.Insights.Add("materialize ignored - no cold paths are configured (CodeSearch:ColdStorage:Paths)");
+        }
+        else if (materialized.Count == 0)
+        {
+            result.Insights.Add("No cold-tier files to materialize - the paths are not cold or were already materialized");
+        }
+        else
+        {
+            result.Insights.Add($"Materialized {materialized.Count} cold-tier file(s): they now keep stored content, pattern and symbol fields and summaries");
+        }
+    }
+    
     private AIOptimizedResponse<IndexWorkspaceResult> CreateDirectoryNotFoundError(string workspacePath)
     {
         var result = new AIOptimizedResponse<IndexWorkspaceResult>
@@ -430,6 +562,14 @@ public class IndexWorkspaceParameters
     [Description("File extensions to exclude from indexing (default: none). Examples: '[\".min.js\", \".map\"]', '[\".dll\", \".exe\", \".bin\"]'")]
     public string[]? ExcludeExtensions { get; set; } = null;
 
+    /// <summary>
+    /// Cold-tier files or directories to index at full fidelity from now on.
+    /// </summary>
+    /// <example>["legacy/billing"]</example>
+    /// <example>["archives/2019/Invoice.cs"]</example>
+    [Description("Cold-tier files or directories (relative to the workspace) to materialize: index at full fidelity from now on. Examples: '[\"legacy/billing\"]'")]
+    public string[]? Materialize { get; set; } = null;
+
     /// <summary>
     /// Response mode: 'summary' or 'full' (default: summary)
     /// </summary>
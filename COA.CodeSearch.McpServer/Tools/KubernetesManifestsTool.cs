@@ -0,0 +1,313 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Reads the workspace's Kubernetes manifests and Helm charts and ties their containers to the directories that
+/// build the images, their environment variables to the code reading them, and their ConfigMap and Secret
+/// references to the objects the workspace defines
+/// </summary>
+public class KubernetesManifestsTool : CodeSearchToolBase<KubernetesManifestsParameters, AIOptimizedResponse<KubernetesManifestsResult>>
+{
+    private static readonly HashSet<string> ProseExtensions = new(StringComparer.OrdinalIgnoreCase) { ".md", ".mdx", ".rst", ".txt", ".adoc", ".tex" };
+    private static readonly HashSet<string> ConfigExtensions = new(StringComparer.OrdinalIgnoreCase) { ".yaml", ".yml", ".tpl" };
+    private const int MaxReadsPerVariable = 20;
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<KubernetesManifestsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the KubernetesManifestsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public KubernetesManifestsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<KubernetesManifestsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.KubernetesManifests;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT RUNS WHERE, AND WITH WHICH SETTINGS? Reads Kubernetes manifests and Helm charts (rendering templates from " +
+        "values.yaml) and links each container image to the directory that builds it, each env var to the code that " +
+        "reads it, and each ConfigMap/Secret reference to the object that defines it. Flags unread variables, missing " +
+        "ConfigMap keys and Helm values read but never set. Use for deployment questions and before renaming settings.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Reads the charts, parses every manifest and template, and links them with the workspace code.
+    /// </summary>
+    /// <param name="parameters">Resource filter, problem filter and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Objects, containers, variables, config references and charts</returns>
+    protected override async Task<AIOptimizedResponse<KubernetesManifestsResult>> ExecuteInternalAsync(
+        KubernetesManifestsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try k8s_manifests again");
+        }
+
+        var files = new Dictionary<string, FileRecord>(StringComparer.Ordinal);
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            files[Relative(workspacePath, file.Path)] = file;
+
+        // Charts first so their templates render with values.yaml
+        var charts = new List<HelmChart>();
+        foreach (var chartFile in files.Keys.Where(p => Path.GetFileName(p) == "Chart.yaml").OrderBy(p => p, StringComparer.Ordinal))
+        {
+            var directory = Path.GetDirectoryName(chartFile)?.Replace('\\', '/') ?? string.Empty;
+            var valuesPath = directory.Length == 0 ? "values.yaml" : directory + "/values.yaml";
+            var chartYaml = await ReadAsync(workspacePath, chartFile, files[chartFile], cancellationToken);
+            var valuesYaml = files.TryGetValue(valuesPath, out var values) ? await ReadAsync(workspacePath, valuesPath, values, cancellationToken) : null;
+            if (chartYaml != null)
+                charts.Add(KubernetesManifests.ReadChart(directory, chartYaml, valuesYaml));
+        }
+
+        var result = new KubernetesManifestsResult();
+        var resources = new List<K8sResource>();
+        var scanned = new HashSet<string>(StringComparer.Ordinal);
+        var templates = new Dictionary<HelmChart, int>();
+        foreach (var (relative, file) in files.OrderBy(f => f.Key, StringComparer.Ordinal))
+        {
+            if (!ConfigExtensions.Contains(Path.GetExtension(relative)) || Path.GetExtension(relative) == ".tpl" || CiPipelines.IsPipelineFile(relative))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+            var content = await ReadAsync(workspacePath, relative, file, cancellationToken);
+            if (content == null)
+                continue;
+
+            var chart = KubernetesManifests.ChartOf(relative, charts);
+            if (chart == null && !KubernetesManifests.IsManifest(relative, content))
+                continue;
+            if (chart != null)
+                templates[chart] = templates.GetValueOrDefault(chart) + 1;
+            scanned.Add(relative);
+            resources.AddRange(KubernetesManifests.Parse(relative, content, chart));
+        }
+        result.FilesScanned = scanned.Count;
+
+        foreach (var container in resources.SelectMany(r => r.Containers))
+            container.Source = KubernetesManifests.FindImageSource(container.Image, files.Keys);
+
+        var codeReads = new Dictionary<string, List<string>>(StringComparer.Ordinal);
+        if (parameters.IncludeVariables && resources.Any(r => r.Containers.Count > 0))
+        {
+            foreach (var (relative, file) in files)
+            {
+                var extension = Path.GetExtension(relative);
+                if (scanned.Contains(relative) || ProseExtensions.Contains(extension) || ConfigExtensions.Contains(extension))
+                    continue;
+                cancellationToken.ThrowIfCancellationRequested();
+                var content = await ReadAsync(workspacePath, relative, file, cancellationToken);
+                if (content == null)
+                    continue;
+                foreach (var (name, line) in CiPipelines.ReadEnvironment(relative, content.Replace("\r\n", "\n").Split('\n')))
+                {
+                    if (!codeReads.TryGetValue(name, out var reads))
+                        codeReads[name] = reads = new List<string>();
+                    if (reads.Count < MaxReadsPerVariable)
+                        reads.Add($"{relative}:{line}");
+                }
+            }
+        }
+        KubernetesManifests.Link(resources, codeReads);
+
+        var filter = parameters.Resource?.Trim();
+        var selected = resources
+            .Where(r => string.IsNullOrEmpty(filter)
+                        || $"{r.Kind}/{r.Name}".Contains(filter, StringComparison.OrdinalIgnoreCase)
+                        || r.FilePath.Contains(filter, StringComparison.OrdinalIgnoreCase)
+                        || r.Containers.Any(c => c.Image.Contains(filter, StringComparison.OrdinalIgnoreCase)))
+            .ToList();
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        result.Resources = selected.Select(r => new K8sResourceSummary
+        {
+            Kind = r.Kind,
+            Name = r.Name,
+            Namespace = r.Namespace,
+            FilePath = r.FilePath,
+            Line = r.Line,
+            Chart = r.Chart,
+            Containers = r.Containers.Count
+        }).ToList();
+
+        var containers = selected.SelectMany(r => r.Containers.Select(c => (Resource: r, Container: c))).ToList();
+        result.Containers = containers.Take(limit).Select(x => new K8sContainerSummary
+        {
+            Resource = $"{x.Resource.Kind}/{x.Resource.Name}",
+            Name = x.Container.Name,
+            Image = x.Container.Image,
+            Init = x.Container.Init,
+            Source = x.Container.Source,
+            FilePath = x.Resource.FilePath,
+            Line = x.Container.Line,
+            Variables = x.Container.Env.Count
+        }).ToList();
+
+        var variables = new List<K8sVariableSummary>();
+        if (parameters.IncludeVariables)
+        {
+            variables = containers
+                .SelectMany(x => x.Container.Env.Select(e => new K8sVariableSummary
+                {
+                    Container = $"{x.Resource.Kind}/{x.Resource.Name}/{x.Container.Name}",
+                    Name = e.Name,
+                    Value = e.Value,
+                    Source = e.Source == "value" ? "value" : $"{e.Source} {e.SourceName}{(e.Key != null ? "/" + e.Key : string.Empty)}",
+                    Status = e.Status ?? "unread",
+                    CodeReads = e.CodeReads,
+                    FilePath = e.FilePath,
+                    Line = e.Line
+                }))
+                .Where(v => !parameters.ProblemsOnly || v.Status != "read")
+                .ToList();
+            result.Variables = variables.Take(limit).ToList();
+        }
+
+        var references = selected.SelectMany(r => r.ConfigReferences)
+            .Where(r => !parameters.ProblemsOnly || r.Status != "defined")
+            .ToList();
+        result.ConfigReferences = references.Take(limit).ToList();
+
+        result.Charts = charts
+            .Where(c => string.IsNullOrEmpty(filter) || selected.Any(r => r.Chart == c.Directory))
+            .Select(c => new HelmChartSummary
+            {
+                Directory = c.Directory,
+                Name = c.Name,
+                Version = c.Version,
+                AppVersion = c.AppVersion,
+                Templates = templates.GetValueOrDefault(c),
+                Values = c.Values.Count(v => v.Value != null),
+                UndefinedValues = c.References
+                    .Where(r => !r.Defined)
+                    .GroupBy(r => r.Path, StringComparer.Ordinal)
+                    .Select(g => $"{g.Key} ({g.First().FilePath}:{g.First().Line})")
+                    .Take(limit)
+                    .ToList(),
+                UnusedValues = UnusedValues(c).Take(limit).ToList()
+            })
+            .ToList();
+        result.Truncated = containers.Count > limit || variables.Count > limit || references.Count > limit;
+
+        var unread = variables.Count(v => v.Status == "unread");
+        var broken = references.Count(r => r.Status == "missing-key");
+        var external = references.Where(r => r.Status == "external").Select(r => r.Name).Distinct(StringComparer.Ordinal).ToList();
+        var undefinedValues = result.Charts.Sum(c => c.UndefinedValues.Count);
+        _logger.LogDebug("k8s_manifests: {Resources} objects, {Containers} containers, {Charts} charts, {Variables} variables",
+            selected.Count, containers.Count, charts.Count, variables.Count);
+
+        var response = new AIOptimizedResponse<KubernetesManifestsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<KubernetesManifestsResult> { Results = result },
+            Message = selected.Count == 0
+                ? "No Kubernetes manifests or Helm templates found"
+                : $"{selected.Count} object(s), {containers.Count} container(s) in {result.FilesScanned} file(s): {unread} unread variable(s), " +
+                  $"{broken} missing ConfigMap/Secret key(s), {undefinedValues} undefined Helm value(s)"
+        };
+
+        var insights = new List<string>();
+        if (resources.Count == 0)
+        {
+            insights.Add("No indexed YAML file has top-level apiVersion and kind, and no Chart.yaml is indexed");
+        }
+        var unmatched = containers.Where(x => x.Container.Source == null).Select(x => x.Container.Image).Where(i => i.Length > 0).Distinct().ToList();
+        if (unmatched.Count > 0 && unmatched.Count < containers.Count)
+        {
+            insights.Add($"{unmatched.Count} image(s) match no Dockerfile directory or project ({string.Join(", ", unmatched.Take(3))}) - " +
+                         "their variables are checked against all code");
+        }
+        if (external.Count > 0)
+        {
+            insights.Add($"ConfigMaps/Secrets not defined in the workspace: {string.Join(", ", external.Take(5))} - created by another repo, an operator or kubectl");
+        }
+        if (unread > 0)
+        {
+            insights.Add("Unread variables may still configure the runtime or a framework (ASPNETCORE_*, JAVA_OPTS) - check before removing them");
+        }
+        if (charts.Any(c => c.References.Any(r => !r.Defined)))
+        {
+            insights.Add("Helm values read without a default in values.yaml render empty unless every install sets them");
+        }
+        if (charts.Count > 0)
+        {
+            insights.Add("Templates are rendered from values.yaml only; include, range and tpl output is not expanded");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Results limited to {limit} - narrow with resource or problemsOnly");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// values.yaml settings no template reads, directly or through a parent read with toYaml or with
+    /// </summary>
+    private static IEnumerable<string> UnusedValues(HelmChart chart)
+    {
+        var read = chart.References.Select(r => r.Path).ToHashSet(StringComparer.Ordinal);
+        return chart.Values
+            .Where(v => v.Value != null)
+            .Select(v => v.Key)
+            .Where(path => !read.Any(r => r == path || path.StartsWith(r + ".", StringComparison.Ordinal)))
+            .OrderBy(path => path, StringComparer.Ordinal);
+    }
+
+    private static async Task<string?> ReadAsync(string workspacePath, string relative, FileRecord file, CancellationToken cancellationToken)
+    {
+        var fullPath = Path.Combine(workspacePath, relative);
+        return file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<KubernetesManifestsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -104,6 +104,8 @@ public class LineSearchTool : CodeSearchToolBase<LineSearchParams, AIOptimizedRe
                 throw new DirectoryNotFoundException($"Workspace path not found: {workspacePath}");
             }
 
+            await EnsurePendingChangesIndexedAsync(normalizedPath, parameters.SkipPendingEdits, cancellationToken);
+
             // Ensure index exists
             if (!await _indexService.IndexExistsAsync(normalizedPath, cancellationToken))
             {
@@ -260,6 +262,17 @@ public class LineSearchTool : CodeSearchToolBase<LineSearchParams, AIOptimizedRe
 
             // OPTIMIZATION: Trust Lucene - it found the pattern, so we just need to locate WHERE
             matches = FindMatchingLinesEfficiently(content, parameters, hit.FilePath);
+
+            // Notebook content is its code cells run together; give each match its cell and line in the cell
+            var notebookCells = JupyterNotebook.ParseCells(hit.Fields.GetValueOrDefault(JupyterNotebook.CellsField));
+            foreach (var match in matches)
+            {
+                if (JupyterNotebook.Locate(notebookCells, match.LineNumber) is { } location)
+                {
+                    match.Cell = location.Cell;
+                    match.CellLine = location.Line;
+                }
+            }
             
             _logger.LogTrace("Efficiently extracted {Count} line matches from {FilePath} using optimized search", 
                 matches.Count, hit.FilePath);
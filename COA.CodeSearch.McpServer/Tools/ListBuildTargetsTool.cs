@@ -0,0 +1,214 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Lists the targets of the workspace's Makefiles, MSBuild files, package.json scripts and Taskfiles with the
+/// commands they run, the targets they depend on and the files they read and write
+/// </summary>
+public class ListBuildTargetsTool : CodeSearchToolBase<ListBuildTargetsParameters, AIOptimizedResponse<ListBuildTargetsResult>>
+{
+    private static readonly string[] Tools = { "make", "msbuild", "npm", "task" };
+
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<ListBuildTargetsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the ListBuildTargetsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public ListBuildTargetsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<ListBuildTargetsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.ListBuildTargets;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "HOW DO I BUILD OR TEST THIS? Lists targets from Makefiles, MSBuild files, package.json scripts and Taskfiles " +
+        "with the exact command line to run each, the commands it runs, what it depends on and the files it reads and " +
+        "writes. Use directory to focus on one component and query to find test, lint or generate targets.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Parses every indexed build file and filters the targets.
+    /// </summary>
+    /// <param name="parameters">Name, directory and build system filters</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The matching targets</returns>
+    protected override async Task<AIOptimizedResponse<ListBuildTargetsResult>> ExecuteInternalAsync(
+        ListBuildTargetsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try list_build_targets again");
+        }
+
+        var tool = parameters.Tool?.Trim().ToLowerInvariant();
+        if (!string.IsNullOrEmpty(tool) && !Tools.Contains(tool))
+        {
+            return CreateErrorResponse("INVALID_TOOL", $"Unknown build system '{parameters.Tool}'", $"Use one of: {string.Join(", ", Tools)}");
+        }
+
+        var directory = parameters.Directory?.Trim().Replace('\\', '/').Trim('/');
+        if (directory is "" or ".")
+            directory = null;
+
+        var result = new ListBuildTargetsResult();
+        var targets = new List<BuildTarget>();
+        var projects = 0;
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var relative = Relative(workspacePath, file.Path);
+            if (!BuildTargets.IsBuildFile(relative))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            // A build file above the component only counts for targets that mention it
+            var fileDirectory = Path.GetDirectoryName(relative)?.Replace('\\', '/') ?? string.Empty;
+            var ancestor = directory != null && !Within(fileDirectory, directory);
+            if (ancestor && !Within(directory!, fileDirectory))
+                continue;
+
+            var fullPath = Path.Combine(workspacePath, relative);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            result.FilesScanned++;
+            if (Path.GetExtension(relative).EndsWith("proj", StringComparison.OrdinalIgnoreCase) && !relative.EndsWith(".proj", StringComparison.OrdinalIgnoreCase))
+                projects++;
+            var found = BuildTargets.Parse(relative, content, Runner(workspacePath, fileDirectory));
+            targets.AddRange(ancestor
+                ? found.Where(t => t.Commands.Concat(t.Inputs).Concat(t.Outputs).Any(c => c.Contains(directory!, StringComparison.OrdinalIgnoreCase)))
+                : found);
+        }
+
+        var query = parameters.Query?.Trim();
+        var matched = targets
+            .Where(t => string.IsNullOrEmpty(tool) || t.Tool == tool)
+            .Where(t => string.IsNullOrEmpty(query)
+                        || t.Name.Contains(query, StringComparison.OrdinalIgnoreCase)
+                        || t.Description?.Contains(query, StringComparison.OrdinalIgnoreCase) == true)
+            .OrderBy(t => t.FilePath.Count(c => c == '/'))
+            .ThenBy(t => t.FilePath, StringComparer.Ordinal)
+            .ThenByDescending(t => t.Default)
+            .ThenBy(t => t.Line)
+            .ToList();
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        result.Counts = matched.GroupBy(t => t.Tool).ToDictionary(g => g.Key, g => g.Count());
+        result.Targets = matched.Take(limit).ToList();
+        result.Truncated = matched.Count > limit;
+        if (!parameters.IncludeCommands)
+        {
+            result.Targets.ForEach(t => t.Commands.Clear());
+        }
+
+        _logger.LogDebug("list_build_targets: {Targets} targets from {Files} build files", matched.Count, result.FilesScanned);
+
+        var response = new AIOptimizedResponse<ListBuildTargetsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<ListBuildTargetsResult> { Results = result },
+            Message = matched.Count == 0
+                ? $"No build targets found in {result.FilesScanned} build file(s)"
+                : $"{matched.Count} target(s): {string.Join(", ", result.Counts.Select(c => $"{c.Value} {c.Key}"))} from {result.FilesScanned} build file(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.FilesScanned == 0)
+        {
+            insights.Add("No Makefile, *.mk, MSBuild project or targets file, package.json or Taskfile is indexed" +
+                         (directory != null ? $" in or above '{directory}'" : string.Empty));
+        }
+        if (projects > 0 && (string.IsNullOrEmpty(tool) || tool == "msbuild"))
+        {
+            insights.Add("The .NET projects also have built-in targets not listed here: dotnet build, dotnet test and dotnet pack on the project or solution");
+        }
+        if (result.Targets.FirstOrDefault(t => t.Default) is { } defaultTarget)
+        {
+            insights.Add($"'{defaultTarget.Invocation}' is what a bare '{defaultTarget.Tool}' runs in {Path.GetDirectoryName(defaultTarget.FilePath) is { Length: > 0 } d ? d : "the workspace root"}");
+        }
+        if (result.Targets.Any(t => t.Triggers.Count > 0))
+        {
+            insights.Add("Targets with triggers run on their own around the targets they name - pre/post scripts, BeforeTargets/AfterTargets");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Listed {limit} of {matched.Count} targets - narrow with directory, query or tool");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// The package manager a package.json is run with, from the nearest lockfile at or above its directory
+    /// </summary>
+    private static string Runner(string workspacePath, string directory)
+    {
+        for (var current = directory; ; current = current.Contains('/') ? current[..current.LastIndexOf('/')] : string.Empty)
+        {
+            var path = Path.Combine(workspacePath, current);
+            if (File.Exists(Path.Combine(path, "pnpm-lock.yaml")))
+                return "pnpm";
+            if (File.Exists(Path.Combine(path, "yarn.lock")))
+                return "yarn";
+            if (File.Exists(Path.Combine(path, "package-lock.json")) || current.Length == 0)
+                return "npm";
+        }
+    }
+
+    private static bool Within(string path, string directory) =>
+        directory.Length == 0
+        || path.Equals(directory, StringComparison.OrdinalIgnoreCase)
+        || path.StartsWith(directory + "/", StringComparison.OrdinalIgnoreCase);
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<ListBuildTargetsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,171 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Lists message channels - Kafka topics, queues, subjects, event names and event types - with the code that
+/// publishes to and consumes from each, flagging channels with only one side in the workspace
+/// </summary>
+public class MessageFlowsTool : CodeSearchToolBase<MessageFlowsParameters, AIOptimizedResponse<MessageFlowsResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<MessageFlowsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the MessageFlowsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service the source files are read from</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public MessageFlowsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<MessageFlowsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.MessageFlows;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHO PUBLISHES, WHO CONSUMES? Finds publish and subscribe sites for Kafka topics, RabbitMQ queues and routing keys, " +
+        "NATS/Redis subjects, Azure Service Bus queues, NestJS and Node events, and event types (MassTransit, MediatR, " +
+        "NServiceBus, event-bus handlers), then lists per channel its producers and consumers across the workspace - " +
+        "including channels nothing consumes or nothing publishes to.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads publish and subscribe sites and string constants from every indexed source file and pairs them.
+    /// </summary>
+    /// <param name="parameters">Channel and broker filters and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Channels with their producers and consumers</returns>
+    protected override async Task<AIOptimizedResponse<MessageFlowsResult>> ExecuteInternalAsync(
+        MessageFlowsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try message_flows again");
+        }
+
+        var sites = new List<MessageSite>();
+        var constants = new Dictionary<string, string?>(StringComparer.Ordinal);
+        var result = new MessageFlowsResult();
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!MessageContracts.IsSourceFile(file.Path))
+                continue;
+
+            var fullPath = Path.IsPathRooted(file.Path) ? file.Path : Path.Combine(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null)
+                continue;
+
+            result.FilesScanned++;
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            MessageContracts.ReadConstants(lines, constants);
+            sites.AddRange(MessageContracts.Scan(Relative(workspacePath, file.Path), lines));
+        }
+
+        var unresolved = new List<MessageSite>();
+        var brokers = parameters.Brokers?.Where(b => !string.IsNullOrWhiteSpace(b)).Select(b => b.Trim().ToLowerInvariant()).ToHashSet();
+        var flows = MessageContracts.Link(sites, constants, unresolved)
+            .Where(f => string.IsNullOrWhiteSpace(parameters.Channel) || f.Channel.Contains(parameters.Channel.Trim(), StringComparison.OrdinalIgnoreCase))
+            .Where(f => brokers == null || brokers.Count == 0 || f.Brokers.Any(brokers.Contains))
+            .Where(f => !parameters.OrphansOnly || f.Status != "linked")
+            .ToList();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        result.Flows = flows.Take(maxResults).ToList();
+        result.Truncated = flows.Count > maxResults;
+        result.Counts = flows.GroupBy(f => f.Status).ToDictionary(g => g.Key, g => g.Count());
+        result.Producers = flows.Sum(f => f.Producers.Count);
+        result.Consumers = flows.Sum(f => f.Consumers.Count);
+        result.Unresolved = unresolved
+            .Where(s => brokers == null || brokers.Count == 0 || brokers.Contains(s.Broker))
+            .Take(maxResults)
+            .ToList();
+
+        _logger.LogDebug("message_flows: {Sites} sites in {Files} files, {Flows} channels, {Unresolved} unresolved",
+            sites.Count, result.FilesScanned, flows.Count, unresolved.Count);
+
+        var response = new AIOptimizedResponse<MessageFlowsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<MessageFlowsResult> { Results = result },
+            Message = flows.Count == 0
+                ? "No message channels found"
+                : $"{flows.Count} channel(s), {result.Producers} producer(s), {result.Consumers} consumer(s): " +
+                  string.Join(", ", MessageContracts.Statuses.Where(result.Counts.ContainsKey).Select(s => $"{result.Counts[s]} {s}"))
+        };
+
+        var insights = new List<string>();
+        if (flows.Count == 0 && sites.Count == 0)
+        {
+            insights.Add("No publish or subscribe calls were recognized - messaging wrapped in custom helpers or configured in YAML/XML is not read");
+        }
+        if (result.Counts.GetValueOrDefault("no-consumer") + result.Counts.GetValueOrDefault("no-producer") > 0)
+        {
+            insights.Add("Channels with one side only may be served by another repository or service - treat them as leads, not dead code");
+        }
+        if (unresolved.Count > 0)
+        {
+            insights.Add($"{unresolved.Count} site(s) name their channel through a variable or configuration value that has no string constant in the workspace - see unresolved");
+        }
+        if (flows.Any(f => f.Kind == "type"))
+        {
+            insights.Add("Type channels are matched by class name only; messages that share a name across namespaces are merged");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped at {maxResults} channels - narrow with channel, brokers or orphansOnly");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<MessageFlowsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
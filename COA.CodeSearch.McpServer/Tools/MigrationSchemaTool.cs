@@ -0,0 +1,222 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Migrations;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Shows the schema a workspace's migration files build - goose, golang-migrate, dbmate, Flyway and EF Core -
+/// with their timeline, a table's change history, and the entities and SQL that use it
+/// </summary>
+public class MigrationSchemaTool : CodeSearchToolBase<MigrationSchemaParameters, AIOptimizedResponse<MigrationSchemaResult>>
+{
+    private readonly IMigrationIndexService _migrationIndex;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<MigrationSchemaTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the MigrationSchemaTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="migrationIndex">Migration index with the derived schemas</param>
+    /// <param name="sqliteService">SQLite symbol service for workspace checks</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public MigrationSchemaTool(
+        IServiceProvider serviceProvider,
+        IMigrationIndexService migrationIndex,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<MigrationSchemaTool> logger) : base(serviceProvider, logger)
+    {
+        _migrationIndex = migrationIndex;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.MigrationSchema;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT DOES THE DATABASE LOOK LIKE NOW? Replays migration files (goose, golang-migrate, dbmate, Flyway, EF Core) in " +
+        "version order into the current tables, columns and indexes. Give a table to see every migration that changed it " +
+        "and the ORM entities and SQL that use it; columns entities map but no migration creates are flagged as drift.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Reads the migration sets and reports their schemas, or one table's history and references.
+    /// </summary>
+    /// <param name="parameters">Table and set filters, timeline switch and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The migrated schema per set</returns>
+    protected override async Task<AIOptimizedResponse<MigrationSchemaResult>> ExecuteInternalAsync(
+        MigrationSchemaParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try migration_schema again");
+        }
+
+        var sets = (await _migrationIndex.GetSetsAsync(workspacePath, cancellationToken))
+            .Where(s => string.IsNullOrWhiteSpace(parameters.Directory) || s.Directory.Contains(parameters.Directory.Trim().Replace('\\', '/'), StringComparison.OrdinalIgnoreCase))
+            .ToList();
+        if (sets.Count == 0)
+        {
+            return CreateErrorResponse("NO_MIGRATIONS",
+                string.IsNullOrWhiteSpace(parameters.Directory) ? "No migration files were found in the workspace" : $"No migration set lives under '{parameters.Directory}'",
+                "Migrations are recognized by name: 20240101120000_x.sql with -- +goose Up or -- migrate:up, 1_x.up.sql, V1__x.sql, or EF Core Migration classes",
+                "Check that .sql files are included in the index");
+        }
+
+        var table = parameters.Table?.Trim();
+        var maxResults = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var result = new MigrationSchemaResult();
+        var remaining = maxResults;
+
+        foreach (var set in sets)
+        {
+            var tables = set.Schema.Where(t => table == null || Matches(t.Name, table)).ToList();
+            if (table != null && tables.Count == 0 && !set.Migrations.SelectMany(m => m.Changes).Any(c => Matches(c.Table, table)))
+                continue;
+
+            var setResult = new MigrationSetResult
+            {
+                Directory = set.Directory,
+                Tool = set.Tool,
+                Migrations = set.Migrations.Count,
+                LatestVersion = set.Migrations.LastOrDefault(m => !m.Repeatable)?.Version ?? string.Empty,
+                Tables = tables.Take(remaining).ToList(),
+                Drift = (await _migrationIndex.CompareWithEntitiesAsync(workspacePath, set, cancellationToken))
+                    .Where(d => table == null || Matches(d.Table, table))
+                    .ToList()
+            };
+            remaining -= setResult.Tables.Count;
+            result.Truncated |= setResult.Tables.Count < tables.Count;
+
+            if (parameters.IncludeTimeline)
+            {
+                setResult.Timeline = set.Migrations
+                    .Where(m => table == null || m.Changes.Any(c => Matches(c.Table, table)))
+                    .TakeLast(maxResults)
+                    .ToList();
+            }
+            if (table != null)
+            {
+                result.History.AddRange(History(set, table));
+            }
+            result.Sets.Add(setResult);
+        }
+
+        if (table != null)
+        {
+            if (result.Sets.Count == 0)
+            {
+                var known = sets.SelectMany(s => s.Schema).Select(t => t.Name).Distinct(StringComparer.OrdinalIgnoreCase).ToList();
+                var similar = known.Where(n => n.Contains(table, StringComparison.OrdinalIgnoreCase) || table.Contains(n, StringComparison.OrdinalIgnoreCase)).Take(5).ToList();
+                return CreateErrorResponse("TABLE_NOT_FOUND", $"No migration creates or changes table '{table}'",
+                    similar.Count > 0 ? $"Similar tables: {string.Join(", ", similar)}" : $"Known tables: {string.Join(", ", known.Take(10))}",
+                    "Call migration_schema without a table to list every table");
+            }
+            result.References = await _migrationIndex.FindReferencesAsync(workspacePath, table, maxResults + 1, cancellationToken);
+            result.Truncated |= result.References.Count > maxResults;
+            result.References = result.References.Take(maxResults).ToList();
+        }
+
+        var tableCount = result.Sets.Sum(s => s.Tables.Count);
+        var driftCount = result.Sets.Sum(s => s.Drift.Count);
+        _logger.LogDebug("migration_schema: {Sets} sets, {Tables} tables, {Drift} drift, {References} references",
+            result.Sets.Count, tableCount, driftCount, result.References.Count);
+
+        var response = new AIOptimizedResponse<MigrationSchemaResult>
+        {
+            Success = true,
+            Data = new AIResponseData<MigrationSchemaResult> { Results = result },
+            Message = table == null
+                ? $"{result.Sets.Count} migration set(s), {result.Sets.Sum(s => s.Migrations)} migration(s), {tableCount} table(s) and view(s)" +
+                  (driftCount > 0 ? $", {driftCount} drifted column(s)" : string.Empty)
+                : tableCount == 0
+                    ? $"'{table}' no longer exists after the migrations - {result.History.Count} change(s) in its history"
+                    : $"'{table}': {result.Sets.SelectMany(s => s.Tables).First().Columns.Count} column(s), {result.History.Count} change(s), " +
+                      $"{result.References.Count(r => r.Via == "entity")} entit{(result.References.Count(r => r.Via == "entity") == 1 ? "y" : "ies")}, " +
+                      $"{result.References.Count(r => r.Via == "sql")} SQL reference(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.Sets.Count > 1)
+        {
+            insights.Add("Each migration directory is treated as its own database - narrow with directory when they are one");
+        }
+        if (sets.Any(s => s.Tool == "goose" && s.Migrations.Any(m => m.FilePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase))))
+        {
+            insights.Add("goose Go migrations appear in the timeline, but the schema changes their code makes are not read");
+        }
+        if (result.Sets.Any(s => s.Drift.Any(d => d.Kind == "unmigrated")))
+        {
+            insights.Add("Unmigrated columns are mapped by an entity but created by no migration - a missing migration, or a mapping the ORM ignores");
+        }
+        if (table != null && result.References.Count > 0)
+        {
+            insights.Add($"Use column_usages with '{table}.<column>' to follow a single column into the code");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Results were cut at {maxResults} - narrow with table or directory");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// The table's changes in migration order, followed back through renames to its earlier names
+    /// </summary>
+    private static IEnumerable<MigrationChange> History(MigrationSet set, string table)
+    {
+        var changes = set.Migrations.SelectMany(m => m.Changes).ToList();
+        var names = new HashSet<string>(StringComparer.OrdinalIgnoreCase) { table };
+        for (var i = changes.Count - 1; i >= 0; i--)
+        {
+            if (changes[i].Action == "rename-table" && changes[i].Target != null && names.Contains(changes[i].Target!))
+                names.Add(changes[i].Table);
+        }
+        return changes.Where(c => names.Contains(c.Table) || Matches(c.Table, table));
+    }
+
+    // users matches users and app.users
+    private static bool Matches(string name, string table) =>
+        name.Equals(table, StringComparison.OrdinalIgnoreCase)
+        || !table.Contains('.') && name.EndsWith("." + table, StringComparison.OrdinalIgnoreCase);
+
+    private static AIOptimizedResponse<MigrationSchemaResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
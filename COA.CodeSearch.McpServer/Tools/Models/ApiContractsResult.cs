@@ -0,0 +1,37 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Mismatches between server routes, client calls and OpenAPI specs; file paths are workspace-relative
+/// </summary>
+public class ApiContractsResult
+{
+    /// <summary>
+    /// Findings by kind (missing first, uncalled last), then file and line
+    /// </summary>
+    public List<ApiContractFinding> Findings { get; set; } = new();
+
+    /// <summary>
+    /// Findings per kind, before the limit
+    /// </summary>
+    public Dictionary<string, int> Counts { get; set; } = new();
+
+    public int Routes { get; set; }
+    public int Calls { get; set; }
+
+    /// <summary>
+    /// Calls to absolute URLs on other hosts, left out of the comparison
+    /// </summary>
+    public int ExternalCalls { get; set; }
+
+    public int SpecOperations { get; set; }
+
+    /// <summary>
+    /// OpenAPI and Swagger documents found
+    /// </summary>
+    public List<string> Specs { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
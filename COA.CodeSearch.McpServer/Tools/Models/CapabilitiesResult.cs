@@ -0,0 +1,107 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Result of a capabilities operation
+/// </summary>
+public class CapabilitiesResult
+{
+    public string ServerVersion { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Languages with their effective extraction feature levels on this server
+    /// </summary>
+    public List<LanguageCapabilityInfo> Languages { get; set; } = new();
+
+    /// <summary>
+    /// Analyzers and optional engines, and whether each is active
+    /// </summary>
+    public List<AnalyzerInfo> Analyzers { get; set; } = new();
+
+    public TransportCapabilities Transport { get; set; } = new();
+
+    public CapabilityLimits Limits { get; set; } = new();
+
+    /// <summary>
+    /// Names of all registered tools
+    /// </summary>
+    public List<string> Tools { get; set; } = new();
+
+    /// <summary>
+    /// Schema version of every tool whose parameters have changed shape (all others are version 1)
+    /// </summary>
+    public Dictionary<string, string> SchemaVersions { get; set; } = new();
+
+    /// <summary>
+    /// Plugins found at startup, including ones that failed to load
+    /// </summary>
+    public List<PluginInfo> Plugins { get; set; } = new();
+}
+
+/// <summary>
+/// A loaded (or failed) plugin
+/// </summary>
+public class PluginInfo
+{
+    public string Name { get; set; } = string.Empty;
+    public string Version { get; set; } = string.Empty;
+    public bool Loaded { get; set; }
+    public string? Error { get; set; }
+}
+
+/// <summary>
+/// Feature levels for one language: "full", "partial" or "none"
+/// </summary>
+public class LanguageCapabilityInfo
+{
+    public string Name { get; set; } = string.Empty;
+    public List<string> Extensions { get; set; } = new();
+    public string Symbols { get; set; } = string.Empty;
+    public string References { get; set; } = string.Empty;
+    public string Rename { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Text search works for every file regardless of symbol support
+    /// </summary>
+    public bool TextSearch { get; set; } = true;
+
+    public string? Notes { get; set; }
+}
+
+/// <summary>
+/// An analyzer or optional engine
+/// </summary>
+public class AnalyzerInfo
+{
+    public string Name { get; set; } = string.Empty;
+    public bool Enabled { get; set; }
+    public string Description { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// MCP transport features this server implements
+/// </summary>
+public class TransportCapabilities
+{
+    public string Transport { get; set; } = "stdio";
+    public bool Tools { get; set; } = true;
+    public bool Resources { get; set; } = true;
+    public bool Prompts { get; set; } = true;
+    public List<string> PromptNames { get; set; } = new();
+    public bool LogNotifications { get; set; }
+    public bool Sampling { get; set; }
+    public bool Elicitation { get; set; }
+}
+
+/// <summary>
+/// Limits that bound requests and indexing
+/// </summary>
+public class CapabilityLimits
+{
+    public long MaxFileSizeBytes { get; set; }
+    public int MaxResults { get; set; }
+    public int MaxIndexingConcurrency { get; set; }
+    public int MaxActiveIndexes { get; set; }
+    public int MaxConcurrentIndexes { get; set; }
+    public int MaxBatchSize { get; set; }
+    public int ShutdownTimeoutSeconds { get; set; }
+}
@@ -0,0 +1,39 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Layering rule violations in a workspace; file paths are workspace-relative
+/// </summary>
+public class CheckArchitectureResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Where the rules came from: the workspace's .codesearch-architecture.json or configuration
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Files assigned to each layer
+    /// </summary>
+    public Dictionary<string, int> LayerFiles { get; set; } = new();
+
+    /// <summary>
+    /// Violations of each checked rule, before MaxResults applied
+    /// </summary>
+    public Dictionary<string, int> RuleViolations { get; set; } = new();
+
+    public List<LayerViolation> Violations { get; set; } = new();
+
+    public int TotalViolations { get; set; }
+
+    public int FilesChecked { get; set; }
+
+    /// <summary>
+    /// Files in no declared layer; only "*" rules apply to them
+    /// </summary>
+    public int UnassignedFiles { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,27 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Doc comments that no longer match their code; file paths are workspace-relative
+/// </summary>
+public class CheckDocDriftResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public List<DocDriftFinding> Findings { get; set; } = new();
+
+    /// <summary>
+    /// Findings per kind (parameter, reference, name, value), before MaxResults applied
+    /// </summary>
+    public Dictionary<string, int> ByKind { get; set; } = new();
+
+    public int TotalFindings { get; set; }
+
+    /// <summary>
+    /// Symbols with a doc comment that were checked
+    /// </summary>
+    public int SymbolsChecked { get; set; }
+
+    public bool Truncated { get; set; }
+}
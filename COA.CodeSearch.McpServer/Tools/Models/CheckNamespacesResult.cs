@@ -0,0 +1,24 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Namespace and package declarations that do not match their directories; file paths are workspace-relative
+/// </summary>
+public class CheckNamespacesResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public List<NamespaceViolation> Violations { get; set; } = new();
+
+    /// <summary>
+    /// Violations per language, before MaxResults applied
+    /// </summary>
+    public Dictionary<string, int> ByLanguage { get; set; } = new();
+
+    public int TotalViolations { get; set; }
+
+    public int FilesChecked { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,34 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Symbols whose names break naming rules; file paths are workspace-relative
+/// </summary>
+public class CheckNamingResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The rules checked, built-in and configured
+    /// </summary>
+    public List<string> Rules { get; set; } = new();
+
+    public List<NamingViolation> Violations { get; set; } = new();
+
+    /// <summary>
+    /// Violations per rule, before MaxResults applied
+    /// </summary>
+    public Dictionary<string, int> ByRule { get; set; } = new();
+
+    public int TotalViolations { get; set; }
+
+    /// <summary>
+    /// Violations with a suggested name, which smart_refactor operation fix_naming can rename
+    /// </summary>
+    public int Fixable { get; set; }
+
+    public int SymbolsChecked { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,50 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// CI pipelines with the paths and variables they use; file paths are workspace-relative
+/// </summary>
+public class CiPipelinesResult
+{
+    public List<CiPipelineSummary> Pipelines { get; set; } = new();
+
+    /// <summary>
+    /// Scripts, directories, projects, templates and actions the steps name that are not in the workspace
+    /// </summary>
+    public List<CiReference> Missing { get; set; } = new();
+
+    /// <summary>
+    /// Every path reference with whether it exists; only the missing ones when problemsOnly is set
+    /// </summary>
+    public List<CiReference> References { get; set; } = new();
+
+    public List<CiEnvironmentVariable> Variables { get; set; } = new();
+
+    /// <summary>
+    /// Secrets and repository settings by name, with the number of steps reading each
+    /// </summary>
+    public Dictionary<string, int> Secrets { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One pipeline file and how many of its path references resolve
+/// </summary>
+public class CiPipelineSummary
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string System { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public List<string> Jobs { get; set; } = new();
+    public int Steps { get; set; }
+    public int References { get; set; }
+    public int Missing { get; set; }
+
+    /// <summary>
+    /// Read because another pipeline names it as a template
+    /// </summary>
+    public bool Template { get; set; }
+}
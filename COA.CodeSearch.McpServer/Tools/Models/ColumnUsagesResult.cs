@@ -0,0 +1,25 @@
+using COA.CodeSearch.McpServer.Services.Orm;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// The entities mapping a column and the code touching it, writes first; file paths are workspace-relative
+/// </summary>
+public class ColumnUsagesResult
+{
+    /// <summary>
+    /// Entities mapping the column, each listing just that column
+    /// </summary>
+    public List<OrmEntity> Mappings { get; set; } = new();
+
+    public List<ColumnUsage> Usages { get; set; } = new();
+    public int Writes { get; set; }
+    public int Reads { get; set; }
+
+    /// <summary>
+    /// Files scanned for SQL naming the column
+    /// </summary>
+    public int FilesScanned { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,36 @@
+using COA.CodeSearch.McpServer.Services.DataFlow;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Where a variable's value comes from and where it goes, within its function and across calls
+/// </summary>
+public class DataFlowResult
+{
+    public string Variable { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Function containing the position
+    /// </summary>
+    public string Function { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Assignments, parameters and caller arguments the value can come from
+    /// </summary>
+    public List<DataFlowStep> Sources { get; set; } = new();
+
+    /// <summary>
+    /// Calls, assignments and returns the value can reach
+    /// </summary>
+    public List<DataFlowStep> Sinks { get; set; } = new();
+
+    /// <summary>
+    /// Calls receiving the value whose callee is not in the index (database, shell, HTTP and other library calls)
+    /// </summary>
+    public List<string> ExternalCalls { get; set; } = new();
+
+    public bool Truncated { get; set; }
+}
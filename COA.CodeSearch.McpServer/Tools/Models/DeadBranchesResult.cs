@@ -0,0 +1,26 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Conditionals the given constants make always true or false, with the branches that cannot run
+/// </summary>
+public class DeadBranchesResult
+{
+    /// <summary>
+    /// Findings in file and line order; file paths are workspace-relative
+    /// </summary>
+    public List<ConstantBranch> Branches { get; set; } = new();
+
+    /// <summary>
+    /// Files that mention at least one of the constants
+    /// </summary>
+    public int FilesScanned { get; set; }
+
+    /// <summary>
+    /// Dead lines across all findings
+    /// </summary>
+    public int DeadLines { get; set; }
+
+    public bool Truncated { get; set; }
+}
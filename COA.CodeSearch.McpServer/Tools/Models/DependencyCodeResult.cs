@@ -0,0 +1,24 @@
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// A workspace's dependency code settings and the indexed files they cover, by dependency directory
+/// </summary>
+public class DependencyCodeResult
+{
+    public DependencyCodeSettings Settings { get; set; } = new();
+
+    /// <summary>
+    /// workspace when set for this workspace, default when configured
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    public int IndexedFiles { get; set; }
+    public int DependencyFiles { get; set; }
+
+    /// <summary>
+    /// Indexed files per dependency directory (vendor, services/api/vendor), largest first
+    /// </summary>
+    public Dictionary<string, int> Directories { get; set; } = new();
+}
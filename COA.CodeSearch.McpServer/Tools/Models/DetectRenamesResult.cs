@@ -0,0 +1,67 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Symbols renamed or moved between two refs
+/// </summary>
+public class DetectRenamesResult
+{
+    public string BaseRef { get; set; } = string.Empty;
+    public string HeadRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Old → new symbol mappings, most confident first
+    /// </summary>
+    public List<SymbolRename> Renames { get; set; } = new();
+
+    /// <summary>
+    /// Files git detected as renamed
+    /// </summary>
+    public List<FileRename> FileRenames { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+
+    /// <summary>
+    /// Removed and added symbols left unpaired - possible renames below the confidence threshold
+    /// </summary>
+    public int UnpairedRemoved { get; set; }
+    public int UnpairedAdded { get; set; }
+}
+
+/// <summary>
+/// One old → new symbol mapping
+/// </summary>
+public class SymbolRename
+{
+    public string OldName { get; set; } = string.Empty;
+    public string NewName { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// renamed, or moved when only the file or containing type changed
+    /// </summary>
+    public string Change { get; set; } = string.Empty;
+
+    public string? OldContainer { get; set; }
+    public string? NewContainer { get; set; }
+    public string OldFile { get; set; } = string.Empty;
+    public string NewFile { get; set; } = string.Empty;
+    public int? OldLine { get; set; }
+    public int NewLine { get; set; }
+    public bool IsPublic { get; set; }
+
+    /// <summary>
+    /// 0-1, from body, signature and name similarity
+    /// </summary>
+    public double Confidence { get; set; }
+
+    /// <summary>
+    /// References to the old name still in the index; null when not checked
+    /// </summary>
+    public int? StaleReferences { get; set; }
+}
+
+public class FileRename
+{
+    public string OldPath { get; set; } = string.Empty;
+    public string NewPath { get; set; } = string.Empty;
+}
@@ -0,0 +1,99 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Structural summary of a diff, input for commit messages and changelog entries
+/// </summary>
+public class DiffSummaryResult
+{
+    public string BaseRef { get; set; } = string.Empty;
+    public string HeadRef { get; set; } = string.Empty;
+
+    public DiffStats Stats { get; set; } = new();
+
+    public List<DiffSummaryFile> Files { get; set; } = new();
+
+    /// <summary>
+    /// Declarations added, removed, renamed or with a changed signature
+    /// </summary>
+    public List<SymbolChange> SymbolChanges { get; set; } = new();
+
+    /// <summary>
+    /// Symbol changes touching public API, with breaking changes flagged
+    /// </summary>
+    public List<ApiChange> PublicApiChanges { get; set; } = new();
+
+    public TestChangeSummary TestChanges { get; set; } = new();
+
+    /// <summary>
+    /// Conventional commit type the changes suggest (feat, fix, refactor, test, docs, chore); null when unclear
+    /// </summary>
+    public string? SuggestedType { get; set; }
+
+    /// <summary>
+    /// Top-level directories touched, most changed first - candidates for a commit scope
+    /// </summary>
+    public List<string> Scopes { get; set; } = new();
+
+    public bool HasBreakingChanges => PublicApiChanges.Any(c => c.Breaking);
+}
+
+public class DiffStats
+{
+    public int FilesChanged { get; set; }
+    public int Additions { get; set; }
+    public int Deletions { get; set; }
+
+    /// <summary>
+    /// Changed files per language
+    /// </summary>
+    public Dictionary<string, int> ByLanguage { get; set; } = new();
+}
+
+/// <summary>
+/// A changed file with its category
+/// </summary>
+public class DiffSummaryFile
+{
+    public string Path { get; set; } = string.Empty;
+    public string? OldPath { get; set; }
+    public string Status { get; set; } = string.Empty;
+
+    /// <summary>
+    /// source, test, docs, config or build
+    /// </summary>
+    public string Category { get; set; } = string.Empty;
+
+    public int Additions { get; set; }
+    public int Deletions { get; set; }
+}
+
+/// <summary>
+/// A public API change
+/// </summary>
+public class ApiChange
+{
+    /// <summary>
+    /// Qualified name in the new version (old version for removals)
+    /// </summary>
+    public string Symbol { get; set; } = string.Empty;
+    public string Change { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public string? Before { get; set; }
+    public string? After { get; set; }
+
+    /// <summary>
+    /// Removes, renames, narrows or changes the signature of something callers may use
+    /// </summary>
+    public bool Breaking { get; set; }
+}
+
+public class TestChangeSummary
+{
+    public List<string> FilesAdded { get; set; } = new();
+    public List<string> FilesModified { get; set; } = new();
+    public List<string> FilesRemoved { get; set; } = new();
+    public List<string> TestsAdded { get; set; } = new();
+    public List<string> TestsRemoved { get; set; } = new();
+}
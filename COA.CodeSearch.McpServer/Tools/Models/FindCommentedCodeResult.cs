@@ -0,0 +1,29 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Commented-out code blocks, deletion candidates first; file paths are workspace-relative
+/// </summary>
+public class FindCommentedCodeResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public List<CommentedCodeBlock> Blocks { get; set; } = new();
+
+    public int TotalBlocks { get; set; }
+
+    /// <summary>
+    /// Commented-out code lines across all blocks, before MaxResults applied
+    /// </summary>
+    public int TotalCodeLines { get; set; }
+
+    public int FilesScanned { get; set; }
+
+    /// <summary>
+    /// The workspace is a git repository, so blocks carry the commit that disabled them
+    /// </summary>
+    public bool HistoryAvailable { get; set; }
+
+    public bool Truncated { get; set; }
+}
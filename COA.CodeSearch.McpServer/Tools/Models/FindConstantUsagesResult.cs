@@ -0,0 +1,24 @@
+using COA.CodeSearch.McpServer.Services.Constants;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Constants matching a name or value and where they are used; file paths are workspace-relative
+/// </summary>
+public class FindConstantUsagesResult
+{
+    public List<ConstantDefinition> Definitions { get; set; } = new();
+
+    /// <summary>
+    /// Uses by name
+    /// </summary>
+    public List<ConstantUsage> References { get; set; } = new();
+
+    /// <summary>
+    /// Raw literals equal to a definition's value, likely stand-ins first
+    /// </summary>
+    public List<ConstantUsage> Literals { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
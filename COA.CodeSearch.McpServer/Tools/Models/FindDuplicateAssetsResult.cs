@@ -0,0 +1,29 @@
+using COA.CodeSearch.McpServer.Services.Assets;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Groups of assets with identical content, most wasted bytes first; paths are workspace-relative
+/// </summary>
+public class FindDuplicateAssetsResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public List<DuplicateAssetGroup> Groups { get; set; } = new();
+
+    public int TotalGroups { get; set; }
+
+    /// <summary>
+    /// Bytes taken by the copies beyond the first of each group, before MaxResults applied
+    /// </summary>
+    public long TotalWastedBytes { get; set; }
+
+    public int AssetsScanned { get; set; }
+
+    /// <summary>
+    /// When the inventory was last refreshed
+    /// </summary>
+    public DateTime InventoryBuiltAt { get; set; }
+
+    public bool Truncated { get; set; }
+}
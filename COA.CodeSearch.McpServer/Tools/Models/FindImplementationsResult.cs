@@ -0,0 +1,66 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Types satisfying a Go interface
+/// </summary>
+public class FindImplementationsResult
+{
+    public string Interface { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// The interface's method set, embedded interfaces included, as "Name(params)(results)"
+    /// </summary>
+    public List<string> Methods { get; set; } = new();
+
+    public List<ImplementationInfo> Implementations { get; set; } = new();
+
+    /// <summary>
+    /// Types lacking exactly one method, or one with a different signature
+    /// </summary>
+    public List<ImplementationInfo> NearMisses { get; set; } = new();
+
+    /// <summary>
+    /// Embedded interfaces that are neither indexed nor well known; their methods are not required
+    /// </summary>
+    public List<string> UnresolvedEmbeds { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+}
+
+/// <summary>
+/// One implementing type and where its methods are defined
+/// </summary>
+public class ImplementationInfo
+{
+    public string Type { get; set; } = string.Empty;
+
+    /// <summary>
+    /// struct, or type for other named types
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Only *Type implements the interface: a method has a pointer receiver
+    /// </summary>
+    public bool PointerOnly { get; set; }
+
+    /// <summary>
+    /// Files defining the matched methods, including methods promoted from embedded types
+    /// </summary>
+    public List<string> MethodFiles { get; set; } = new();
+
+    /// <summary>
+    /// Matched methods as "Name (path:line)"
+    /// </summary>
+    public List<string> Methods { get; set; } = new();
+
+    /// <summary>
+    /// Interface methods the type lacks (near misses only)
+    /// </summary>
+    public List<string>? Missing { get; set; }
+}
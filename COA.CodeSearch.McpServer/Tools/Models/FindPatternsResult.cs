@@ -31,6 +31,12 @@ public class FindPatternsResult
     [JsonPropertyName("totalPatterns")]
     public int TotalPatterns { get; set; }
 
+    /// <summary>
+    /// Patterns not reported for this file by earlier analyses.
+    /// </summary>
+    [JsonPropertyName("newSinceBaseline")]
+    public int NewSinceBaseline { get; set; }
+
     /// <summary>
     /// When the analysis was performed.
     /// </summary>
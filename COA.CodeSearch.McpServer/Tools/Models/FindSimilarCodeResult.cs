@@ -0,0 +1,48 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Functions resembling a snippet or symbol, most similar first
+/// </summary>
+public class FindSimilarCodeResult
+{
+    /// <summary>
+    /// "snippet" or the symbol that was compared against
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+    public string? SourceFile { get; set; }
+    public int? SourceLine { get; set; }
+
+    /// <summary>
+    /// Methods that actually ran: tokens, embedding or both
+    /// </summary>
+    public List<string> MethodsUsed { get; set; } = new();
+
+    public int CandidateFiles { get; set; }
+    public int CandidateFunctions { get; set; }
+    public List<SimilarCodeMatch> Matches { get; set; } = new();
+}
+
+public class SimilarCodeMatch
+{
+    public string Name { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+    public string? Container { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string? Signature { get; set; }
+
+    /// <summary>
+    /// 0-1; the higher of the token and embedding scores when both found the function
+    /// </summary>
+    public double Similarity { get; set; }
+
+    /// <summary>
+    /// tokens, embedding, or tokens+embedding
+    /// </summary>
+    public string Method { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Most informative words the two bodies share (token method only)
+    /// </summary>
+    public List<string>? SharedTerms { get; set; }
+}
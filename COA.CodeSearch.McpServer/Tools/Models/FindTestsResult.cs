@@ -0,0 +1,66 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Tests exercising a symbol and the commands that run them; file paths are workspace-relative
+/// </summary>
+public class FindTestsResult
+{
+    public string Symbol { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Where the symbol is defined, as file:line
+    /// </summary>
+    public List<string> Definitions { get; set; } = new();
+
+    public List<FoundTest> Tests { get; set; } = new();
+
+    /// <summary>
+    /// One command per test file, running only the tests listed
+    /// </summary>
+    public List<string> Commands { get; set; } = new();
+
+    public int TestFilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// A test and how it reaches the symbol
+/// </summary>
+public class FoundTest
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Test class or describe block
+    /// </summary>
+    public string? Container { get; set; }
+
+    /// <summary>
+    /// test, benchmark, fuzz or example
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string Framework { get; set; } = string.Empty;
+    public int Line { get; set; }
+
+    /// <summary>
+    /// calls, mentions, named, or via when it calls one of the symbol's callers
+    /// </summary>
+    public string How { get; set; } = string.Empty;
+
+    /// <summary>
+    /// The caller the test goes through, for via
+    /// </summary>
+    public string? Via { get; set; }
+
+    /// <summary>
+    /// Lines in the test that use the symbol or caller
+    /// </summary>
+    public List<int> Lines { get; set; } = new();
+
+    /// <summary>
+    /// Subtests, table cases and data rows
+    /// </summary>
+    public List<string> Cases { get; set; } = new();
+}
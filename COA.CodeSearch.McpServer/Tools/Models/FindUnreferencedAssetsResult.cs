@@ -0,0 +1,29 @@
+using COA.CodeSearch.McpServer.Services.Assets;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Assets no indexed file names, safest to delete first; paths are workspace-relative
+/// </summary>
+public class FindUnreferencedAssetsResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public List<AssetRecord> Assets { get; set; } = new();
+
+    public int TotalUnreferenced { get; set; }
+
+    /// <summary>
+    /// Size of all unreferenced assets, before MaxResults applied
+    /// </summary>
+    public long TotalBytes { get; set; }
+
+    public int AssetsScanned { get; set; }
+
+    /// <summary>
+    /// When the inventory was last refreshed
+    /// </summary>
+    public DateTime InventoryBuiltAt { get; set; }
+
+    public bool Truncated { get; set; }
+}
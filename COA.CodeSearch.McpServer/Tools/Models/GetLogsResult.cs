@@ -0,0 +1,47 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Result of a get_logs operation
+/// </summary>
+public class GetLogsResult
+{
+    /// <summary>
+    /// Matching entries, newest first
+    /// </summary>
+    public List<LogEntryInfo> Entries { get; set; } = new();
+
+    /// <summary>
+    /// Current default minimum level
+    /// </summary>
+    public string DefaultLevel { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Current minimum level for CodeSearch loggers
+    /// </summary>
+    public string CodeSearchLevel { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// A single log entry
+/// </summary>
+public class LogEntryInfo
+{
+    public DateTimeOffset Timestamp { get; set; }
+    public string Level { get; set; } = string.Empty;
+    public string Message { get; set; } = string.Empty;
+    public string? Source { get; set; }
+    public string? CorrelationId { get; set; }
+    public string? ToolName { get; set; }
+    public string? Exception { get; set; }
+}
+
+/// <summary>
+/// Result of a set_log_level operation
+/// </summary>
+public class SetLogLevelResult
+{
+    public string PreviousDefaultLevel { get; set; } = string.Empty;
+    public string PreviousCodeSearchLevel { get; set; } = string.Empty;
+    public string DefaultLevel { get; set; } = string.Empty;
+    public string CodeSearchLevel { get; set; } = string.Empty;
+}
@@ -0,0 +1,38 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// A workspace's Go build profile and the indexed Go files whose build constraints it admits or excludes; file
+/// paths are workspace-relative
+/// </summary>
+public class GoBuildProfileResult
+{
+    public GoBuildProfile Profile { get; set; } = new();
+
+    /// <summary>
+    /// workspace when set for this workspace, default when configured or taken from the host
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    public int GoFiles { get; set; }
+    public int ConstrainedFiles { get; set; }
+    public int ExcludedFiles { get; set; }
+
+    /// <summary>
+    /// Constrained files, excluded ones first
+    /// </summary>
+    public List<GoConstrainedFile> Files { get; set; } = new();
+
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// A Go file with a build constraint
+/// </summary>
+public class GoConstrainedFile
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string Constraint { get; set; } = string.Empty;
+    public bool InBuild { get; set; }
+}
@@ -0,0 +1,103 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Package import graph of the workspace's Go modules; file paths are workspace-relative
+/// </summary>
+public class GoPackageGraphResult
+{
+    public List<GoModuleSummary> Modules { get; set; } = new();
+
+    /// <summary>
+    /// Import paths the package argument matched
+    /// </summary>
+    public List<string> Focus { get; set; } = new();
+
+    /// <summary>
+    /// Every package with its import counts, when no package was given
+    /// </summary>
+    public List<GoPackageSummary> Packages { get; set; } = new();
+
+    /// <summary>
+    /// Packages importing the focus, directly (depth 1) or through other packages
+    /// </summary>
+    public List<GoPackageEdge> Importers { get; set; } = new();
+
+    /// <summary>
+    /// Packages the focus imports: workspace packages to the requested depth, external ones directly
+    /// </summary>
+    public List<GoPackageEdge> Imports { get; set; } = new();
+
+    /// <summary>
+    /// Imports no workspace module, standard library package or requirement provides
+    /// </summary>
+    public List<GoImport> Unresolved { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
+
+public class GoModuleSummary
+{
+    public string Path { get; set; } = string.Empty;
+    public string Directory { get; set; } = string.Empty;
+    public string GoVersion { get; set; } = string.Empty;
+    public int Packages { get; set; }
+    public int Requires { get; set; }
+    public int Indirect { get; set; }
+
+    /// <summary>
+    /// Direct requirements no package imports from - candidates for go mod tidy
+    /// </summary>
+    public List<string> UnusedRequires { get; set; } = new();
+
+    /// <summary>
+    /// Requirements go.sum has no checksum for
+    /// </summary>
+    public List<string> MissingSums { get; set; } = new();
+}
+
+public class GoPackageSummary
+{
+    public string ImportPath { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string Directory { get; set; } = string.Empty;
+    public int Files { get; set; }
+
+    /// <summary>
+    /// Distinct workspace packages it imports, and distinct ones importing it
+    /// </summary>
+    public int Imports { get; set; }
+    public int ImportedBy { get; set; }
+}
+
+/// <summary>
+/// A package reached from the focus, with the first import statement making the link
+/// </summary>
+public class GoPackageEdge
+{
+    public string Package { get; set; } = string.Empty;
+
+    /// <summary>
+    /// internal, module, std or unknown
+    /// </summary>
+    public string Kind { get; set; } = string.Empty;
+
+    public string? Module { get; set; }
+    public string? Version { get; set; }
+
+    /// <summary>
+    /// Import hops from the focus
+    /// </summary>
+    public int Depth { get; set; }
+
+    /// <summary>
+    /// The package on the other end of the import statement: what an importer imports, or what imports this one
+    /// </summary>
+    public string Via { get; set; } = string.Empty;
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public bool Test { get; set; }
+}
@@ -0,0 +1,34 @@
+using COA.CodeSearch.McpServer.Services.GraphQL;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// GraphQL schema types with the resolvers and models they link to, and the drift between them; file paths are workspace-relative
+/// </summary>
+public class GraphQLLinksResult
+{
+    /// <summary>
+    /// Files the schema was read from: SDL files and scripts with gql templates
+    /// </summary>
+    public List<string> SchemaFiles { get; set; } = new();
+
+    /// <summary>
+    /// Root operation types by operation: query, mutation, subscription
+    /// </summary>
+    public Dictionary<string, string> RootTypes { get; set; } = new();
+
+    public List<GraphQLType> Types { get; set; } = new();
+    public List<GraphQLDrift> Drift { get; set; } = new();
+
+    /// <summary>
+    /// Drift entries per kind, before the limit
+    /// </summary>
+    public Dictionary<string, int> DriftCounts { get; set; } = new();
+
+    /// <summary>
+    /// Types matching the filter, before the limit
+    /// </summary>
+    public int TotalTypes { get; set; }
+
+    public bool Truncated { get; set; }
+}
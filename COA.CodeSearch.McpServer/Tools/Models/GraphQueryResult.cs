@@ -0,0 +1,23 @@
+using COA.CodeSearch.McpServer.Services.Graph;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Symbols left at the end of a graph query, with the count after each step
+/// </summary>
+public class GraphQueryResult
+{
+    public string Query { get; set; } = string.Empty;
+    public List<GraphStepReport> Steps { get; set; } = new();
+    public List<SymbolDefinition> Symbols { get; set; } = new();
+
+    /// <summary>
+    /// Symbols matched by the last step, before maxResults
+    /// </summary>
+    public int TotalCount { get; set; }
+
+    /// <summary>
+    /// A step hit the node limit, so later steps saw only part of its symbols
+    /// </summary>
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,56 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// What an editor hover shows for the expression at a position: type, signature and documentation
+/// </summary>
+public class HoverResult
+{
+    /// <summary>
+    /// Expression or name the hover describes
+    /// </summary>
+    public string Expression { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Resolved type; null when only the index answered
+    /// </summary>
+    public string? Type { get; set; }
+
+    /// <summary>
+    /// Declaration signature of the symbol the expression refers to
+    /// </summary>
+    public string? Signature { get; set; }
+
+    public string? Kind { get; set; }
+
+    public string? Documentation { get; set; }
+
+    /// <summary>
+    /// Value of a constant
+    /// </summary>
+    public string? Value { get; set; }
+
+    /// <summary>
+    /// Declaration as "path:line", when it is in the workspace
+    /// </summary>
+    public string? DeclaredAt { get; set; }
+
+    /// <summary>
+    /// Hover text from a language server, as markdown; the structured fields above stay empty then
+    /// </summary>
+    public string? Markdown { get; set; }
+
+    /// <summary>
+    /// What answered: "roslyn", "go/types", a language server name, or "index"
+    /// </summary>
+    public string Source { get; set; } = "index";
+
+    /// <summary>
+    /// True when a compiler or language server resolved the expression; false for the index's name match
+    /// </summary>
+    public bool Precise { get; set; }
+
+    /// <summary>
+    /// Index definitions sharing the name; greater than 1 means the index had to guess which one is meant
+    /// </summary>
+    public int Candidates { get; set; } = 1;
+}
@@ -0,0 +1,116 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Kubernetes objects and Helm charts with the code they deploy; file paths are workspace-relative
+/// </summary>
+public class KubernetesManifestsResult
+{
+    public List<K8sResourceSummary> Resources { get; set; } = new();
+    public List<K8sContainerSummary> Containers { get; set; } = new();
+
+    /// <summary>
+    /// Container environment variables with the code reading them
+    /// </summary>
+    public List<K8sVariableSummary> Variables { get; set; } = new();
+
+    /// <summary>
+    /// ConfigMaps and Secrets the workloads use, with whether the workspace defines them
+    /// </summary>
+    public List<K8sConfigReference> ConfigReferences { get; set; } = new();
+
+    public List<HelmChartSummary> Charts { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One Kubernetes object
+/// </summary>
+public class K8sResourceSummary
+{
+    public string Kind { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string? Namespace { get; set; }
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string? Chart { get; set; }
+    public int Containers { get; set; }
+}
+
+/// <summary>
+/// A container with the workspace directory that builds its image
+/// </summary>
+public class K8sContainerSummary
+{
+    /// <summary>
+    /// Kind/name of the workload
+    /// </summary>
+    public string Resource { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+    public string Image { get; set; } = string.Empty;
+    public bool Init { get; set; }
+
+    /// <summary>
+    /// Directory whose Dockerfile or project name matches the image; null when none does
+    /// </summary>
+    public string? Source { get; set; }
+
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public int Variables { get; set; }
+}
+
+/// <summary>
+/// A container environment variable and where it comes from
+/// </summary>
+public class K8sVariableSummary
+{
+    /// <summary>
+    /// Kind/name/container
+    /// </summary>
+    public string Container { get; set; } = string.Empty;
+
+    public string Name { get; set; } = string.Empty;
+    public string? Value { get; set; }
+
+    /// <summary>
+    /// value, configMap, secret, field or resource, with the object and key: configMap orders-config/db-host
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    /// <summary>
+    /// read, read-elsewhere or unread
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public List<string> CodeReads { get; set; } = new();
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+}
+
+/// <summary>
+/// A Helm chart with the values its templates read but values.yaml does not set, and the reverse
+/// </summary>
+public class HelmChartSummary
+{
+    public string Directory { get; set; } = string.Empty;
+    public string Name { get; set; } = string.Empty;
+    public string? Version { get; set; }
+    public string? AppVersion { get; set; }
+    public int Templates { get; set; }
+    public int Values { get; set; }
+
+    /// <summary>
+    /// .Values reads with no default in values.yaml, as path (file:line)
+    /// </summary>
+    public List<string> UndefinedValues { get; set; } = new();
+
+    /// <summary>
+    /// values.yaml settings no template reads
+    /// </summary>
+    public List<string> UnusedValues { get; set; } = new();
+}
@@ -0,0 +1,19 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Build targets found in the workspace; file paths are workspace-relative
+/// </summary>
+public class ListBuildTargetsResult
+{
+    public List<BuildTarget> Targets { get; set; } = new();
+
+    /// <summary>
+    /// Targets per build system, before the limit
+    /// </summary>
+    public Dictionary<string, int> Counts { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
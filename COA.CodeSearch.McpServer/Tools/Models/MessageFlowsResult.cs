@@ -0,0 +1,29 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Message channels with their producers and consumers; file paths are workspace-relative
+/// </summary>
+public class MessageFlowsResult
+{
+    /// <summary>
+    /// Channels by name
+    /// </summary>
+    public List<MessageFlow> Flows { get; set; } = new();
+
+    /// <summary>
+    /// Channels per status (linked, no-consumer, no-producer), before the limit
+    /// </summary>
+    public Dictionary<string, int> Counts { get; set; } = new();
+
+    /// <summary>
+    /// Publish and subscribe sites whose channel is a variable or constant with no string value in the workspace
+    /// </summary>
+    public List<MessageSite> Unresolved { get; set; } = new();
+
+    public int Producers { get; set; }
+    public int Consumers { get; set; }
+    public int FilesScanned { get; set; }
+    public bool Truncated { get; set; }
+}
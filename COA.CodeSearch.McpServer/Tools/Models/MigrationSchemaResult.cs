@@ -0,0 +1,50 @@
+using COA.CodeSearch.McpServer.Services.Migrations;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// The schema each migration set builds; file paths are workspace-relative
+/// </summary>
+public class MigrationSchemaResult
+{
+    public List<MigrationSetResult> Sets { get; set; } = new();
+
+    /// <summary>
+    /// Every change made to the requested table, under its current or former names, in migration order
+    /// </summary>
+    public List<MigrationChange> History { get; set; } = new();
+
+    /// <summary>
+    /// Entities mapped to the requested table and SQL outside the migrations naming it
+    /// </summary>
+    public List<SchemaReference> References { get; set; } = new();
+
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One migration set: where it lives, how far it has got, and the schema it leaves
+/// </summary>
+public class MigrationSetResult
+{
+    public string Directory { get; set; } = string.Empty;
+
+    /// <summary>
+    /// goose, golang-migrate, dbmate, flyway or ef-core
+    /// </summary>
+    public string Tool { get; set; } = string.Empty;
+
+    public int Migrations { get; set; }
+    public string LatestVersion { get; set; } = string.Empty;
+    public List<SchemaTable> Tables { get; set; } = new();
+
+    /// <summary>
+    /// Migrations in the order they run, when the timeline was asked for
+    /// </summary>
+    public List<MigrationFile>? Timeline { get; set; }
+
+    /// <summary>
+    /// Columns where ORM entities and the migrated schema disagree
+    /// </summary>
+    public List<SchemaDrift> Drift { get; set; } = new();
+}
@@ -0,0 +1,31 @@
+using COA.CodeSearch.McpServer.Services.OpenApi;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// OpenAPI operations and schemas with the code they link to, and the drift between them; file paths are workspace-relative
+/// </summary>
+public class OpenApiLinksResult
+{
+    /// <summary>
+    /// Spec files read, with their title and version
+    /// </summary>
+    public List<string> Specs { get; set; } = new();
+
+    public List<OpenApiOperation> Operations { get; set; } = new();
+    public List<OpenApiSchema> Schemas { get; set; } = new();
+    public List<OpenApiDrift> Drift { get; set; } = new();
+
+    /// <summary>
+    /// Drift entries per kind, before the limit
+    /// </summary>
+    public Dictionary<string, int> DriftCounts { get; set; } = new();
+
+    /// <summary>
+    /// Operations and schemas matching the filters, before the limit
+    /// </summary>
+    public int TotalOperations { get; set; }
+    public int TotalSchemas { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,18 @@
+using COA.CodeSearch.McpServer.Services.Orm;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// ORM entities with their tables and columns; file paths are workspace-relative
+/// </summary>
+public class OrmEntitiesResult
+{
+    public List<OrmEntity> Entities { get; set; } = new();
+
+    /// <summary>
+    /// Entities matching the filters, before the limit
+    /// </summary>
+    public int TotalCount { get; set; }
+
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,81 @@
+using COA.CodeSearch.McpServer.Services;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Result of a purge_index operation
+/// </summary>
+public class PurgeIndexResult
+{
+    /// <summary>
+    /// Purge scope that was applied (workspace, stale, all)
+    /// </summary>
+    public string Scope { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Whether this was a preview only
+    /// </summary>
+    public bool DryRun { get; set; }
+
+    /// <summary>
+    /// Indexes removed (or that would be removed in a dry run)
+    /// </summary>
+    public List<PurgedIndexInfo> Removed { get; set; } = new();
+
+    /// <summary>
+    /// Total bytes reclaimed
+    /// </summary>
+    public long BytesFreed { get; set; }
+
+    /// <summary>
+    /// Number of workspace indexes left on disk
+    /// </summary>
+    public int RemainingIndexes { get; set; }
+
+    /// <summary>
+    /// Index root that was examined
+    /// </summary>
+    public string IndexRootPath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Indexes that could not be removed
+    /// </summary>
+    public List<string> Errors { get; set; } = new();
+
+    /// <summary>
+    /// Size budget and pruned files, for scope 'budget'
+    /// </summary>
+    public IndexBudgetInfo? Budget { get; set; }
+}
+
+/// <summary>
+/// A workspace index measured against its size budget
+/// </summary>
+public class IndexBudgetInfo
+{
+    public long BudgetBytes { get; set; }
+    public long SizeBytes { get; set; }
+    public bool WithinBudget { get; set; }
+
+    /// <summary>
+    /// Files pruned by this call (or that would be, in a dry run)
+    /// </summary>
+    public List<PrunedFile> PrunedThisPass { get; set; } = new();
+
+    /// <summary>
+    /// Every file currently left out of the index, most recent first
+    /// </summary>
+    public List<PrunedFile> PrunedFiles { get; set; } = new();
+}
+
+/// <summary>
+/// A single removed workspace index
+/// </summary>
+public class PurgedIndexInfo
+{
+    public string IndexPath { get; set; } = string.Empty;
+    public string? WorkspacePath { get; set; }
+    public long SizeBytes { get; set; }
+    public DateTime LastAccessed { get; set; }
+    public string Reason { get; set; } = string.Empty;
+}
@@ -0,0 +1,64 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Query syntax reference with examples executed against the workspace
+/// </summary>
+public class QueryHelpResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// True when examples were run against the index and carry hit counts
+    /// </summary>
+    public bool Validated { get; set; }
+
+    /// <summary>
+    /// Where the example identifiers came from: symbols, files or generic
+    /// </summary>
+    public string SampleSource { get; set; } = "generic";
+
+    public List<QueryHelpTopic> Topics { get; set; } = new();
+
+    /// <summary>
+    /// Cross-cutting rules and which tool to use instead of text_search
+    /// </summary>
+    public List<string> Tips { get; set; } = new();
+}
+
+/// <summary>
+/// One text_search mode or syntax feature
+/// </summary>
+public class QueryHelpTopic
+{
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Value to pass as text_search searchMode
+    /// </summary>
+    public string SearchMode { get; set; } = "auto";
+
+    public string Summary { get; set; } = string.Empty;
+    public List<string> Syntax { get; set; } = new();
+    public List<QueryHelpExample> Examples { get; set; } = new();
+}
+
+public class QueryHelpExample
+{
+    public string Query { get; set; } = string.Empty;
+    public string Explanation { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Index field the query was routed to (content, content_symbols, content_patterns)
+    /// </summary>
+    public string? RoutedTo { get; set; }
+
+    /// <summary>
+    /// Matching documents; null when not executed
+    /// </summary>
+    public int? HitCount { get; set; }
+
+    /// <summary>
+    /// Why the query was rejected, if it was
+    /// </summary>
+    public string? Error { get; set; }
+}
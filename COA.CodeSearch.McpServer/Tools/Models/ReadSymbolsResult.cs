@@ -92,15 +92,35 @@ public class SymbolCode
     public int EndLine { get; set; }
 
     /// <summary>
-    /// Starting column (0-based)
+    /// Starting column (0-based, UTF-8 bytes)
     /// </summary>
     public int StartColumn { get; set; }
 
     /// <summary>
-    /// Ending column (0-based)
+    /// Ending column (0-based, UTF-8 bytes)
     /// </summary>
     public int EndColumn { get; set; }
 
+    /// <summary>
+    /// Starting column in UTF-16 code units, as LSP clients count it
+    /// </summary>
+    public int StartUtf16Column { get; set; }
+
+    /// <summary>
+    /// Ending column in UTF-16 code units
+    /// </summary>
+    public int EndUtf16Column { get; set; }
+
+    /// <summary>
+    /// UTF-8 byte offsets of the symbol within the file, when known
+    /// </summary>
+    public int? StartByte { get; set; }
+
+    /// <summary>
+    /// UTF-8 byte offset just past the symbol, when known
+    /// </summary>
+    public int? EndByte { get; set; }
+
     /// <summary>
     /// Symbols that this symbol calls/uses (dependencies)
     /// </summary>
@@ -148,10 +168,15 @@ public class SymbolReference
     public int Line { get; set; }
 
     /// <summary>
-    /// Column where the reference occurs
+    /// Column where the reference occurs (0-based, UTF-8 bytes)
     /// </summary>
     public int Column { get; set; }
 
+    /// <summary>
+    /// Column where the reference occurs in UTF-16 code units
+    /// </summary>
+    public int? Utf16Column { get; set; }
+
     /// <summary>
     /// Count of how many times this symbol is referenced (for aggregated references)
     /// </summary>
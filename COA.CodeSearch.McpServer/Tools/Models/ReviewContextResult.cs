@@ -0,0 +1,116 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Review packet for the changes between two refs
+/// </summary>
+public class ReviewContextResult
+{
+    public string BaseRef { get; set; } = string.Empty;
+    public string HeadRef { get; set; } = string.Empty;
+
+    public List<ReviewFile> Files { get; set; } = new();
+
+    /// <summary>
+    /// Symbols touched by at least one hunk
+    /// </summary>
+    public List<ReviewSymbol> ChangedSymbols { get; set; } = new();
+
+    /// <summary>
+    /// Usages of changed symbols that are not part of the diff - code the change may break
+    /// </summary>
+    public List<ReviewCaller> AffectedCallers { get; set; } = new();
+
+    public List<RelatedTest> RelatedTests { get; set; } = new();
+
+    /// <summary>
+    /// TODO/FIXME comments added in the diff and documentation that mentions changed symbols
+    /// </summary>
+    public List<ReviewNote> Notes { get; set; } = new();
+
+    /// <summary>
+    /// Total changed files; more than Files.Count when MaxFiles was reached
+    /// </summary>
+    public int TotalFiles { get; set; }
+}
+
+/// <summary>
+/// A changed file
+/// </summary>
+public class ReviewFile
+{
+    public string Path { get; set; } = string.Empty;
+    public string? OldPath { get; set; }
+    public string Status { get; set; } = string.Empty;
+    public int Additions { get; set; }
+    public int Deletions { get; set; }
+    public bool IsTest { get; set; }
+    public List<ReviewHunk> Hunks { get; set; } = new();
+}
+
+/// <summary>
+/// A hunk with the innermost symbols that enclose it
+/// </summary>
+public class ReviewHunk
+{
+    public int OldStart { get; set; }
+    public int OldCount { get; set; }
+    public int NewStart { get; set; }
+    public int NewCount { get; set; }
+    public int Added { get; set; }
+    public int Removed { get; set; }
+    public List<string> EnclosingSymbols { get; set; } = new();
+}
+
+/// <summary>
+/// A symbol touched by the diff
+/// </summary>
+public class ReviewSymbol
+{
+    public string Name { get; set; } = string.Empty;
+    public string Kind { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int StartLine { get; set; }
+    public int EndLine { get; set; }
+    public string? Signature { get; set; }
+}
+
+/// <summary>
+/// A usage of a changed symbol outside the diff
+/// </summary>
+public class ReviewCaller
+{
+    public string Symbol { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Kind { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Symbol that contains the usage, when known
+    /// </summary>
+    public string? CallerSymbol { get; set; }
+
+    public bool IsTest { get; set; }
+}
+
+/// <summary>
+/// A test file related to the change
+/// </summary>
+public class RelatedTest
+{
+    public string FilePath { get; set; } = string.Empty;
+    public string Reason { get; set; } = string.Empty;
+}
+
+/// <summary>
+/// A note relevant to the review
+/// </summary>
+public class ReviewNote
+{
+    /// <summary>
+    /// diff (comment added in the change) or docs (documentation mentioning a changed symbol)
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+    public string FilePath { get; set; } = string.Empty;
+    public int Line { get; set; }
+    public string Text { get; set; } = string.Empty;
+}
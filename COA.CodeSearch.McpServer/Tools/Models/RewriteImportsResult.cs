@@ -0,0 +1,40 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Files whose imports of a path or namespace were rewritten, or would be in preview; paths are workspace-relative
+/// </summary>
+public class RewriteImportsResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+    public string From { get; set; } = string.Empty;
+    public string To { get; set; } = string.Empty;
+    public bool Preview { get; set; }
+
+    /// <summary>
+    /// True once the files were written
+    /// </summary>
+    public bool Applied { get; set; }
+
+    public List<ImportRewriteFile> Files { get; set; } = new();
+    public int TotalRewrites { get; set; }
+    public int FilesScanned { get; set; }
+    public int FilesChanged { get; set; }
+    public bool Truncated { get; set; }
+
+    /// <summary>
+    /// Files changed on disk between reading and writing; nothing was written
+    /// </summary>
+    public List<string>? Conflicts { get; set; }
+}
+
+/// <summary>
+/// One file's rewritten lines and its diff
+/// </summary>
+public class ImportRewriteFile
+{
+    public string FilePath { get; set; } = string.Empty;
+    public List<ImportRewrite> Rewrites { get; set; } = new();
+    public string? Diff { get; set; }
+}
@@ -0,0 +1,48 @@
+using COA.CodeSearch.McpServer.Services.Recipes;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Outcome of previewing, applying or rolling back a recipe
+/// </summary>
+public class RunRecipeResult
+{
+    public string Mode { get; set; } = string.Empty;
+    public string? Name { get; set; }
+
+    /// <summary>
+    /// Id of the applied (or rolled back) run
+    /// </summary>
+    public string? RunId { get; set; }
+
+    public List<RecipeStepReport> Steps { get; set; } = new();
+    public List<RecipeFileDiff> Files { get; set; } = new();
+    public int TotalAdded { get; set; }
+    public int TotalRemoved { get; set; }
+
+    /// <summary>
+    /// Files changed on disk since the recipe read (or wrote) them
+    /// </summary>
+    public List<string> Conflicts { get; set; } = new();
+
+    public RecipeBuildResult? Build { get; set; }
+
+    /// <summary>
+    /// The applied files were restored, because the build failed or a rollback was requested
+    /// </summary>
+    public bool RolledBack { get; set; }
+}
+
+public class RecipeFileDiff
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// added, modified or deleted
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    public int Added { get; set; }
+    public int Removed { get; set; }
+    public string? Diff { get; set; }
+}
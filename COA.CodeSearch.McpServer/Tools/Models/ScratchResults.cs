@@ -0,0 +1,65 @@
+using COA.CodeSearch.McpServer.Services.Scratch;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// State of the scratch area after a stage_scratch operation
+/// </summary>
+public class StageScratchResult
+{
+    public string Operation { get; set; } = string.Empty;
+    public string? FilePath { get; set; }
+
+    /// <summary>
+    /// Line count of the staged version of the file (null for deletions and clear)
+    /// </summary>
+    public int? Lines { get; set; }
+
+    public List<StagedFileInfo> StagedFiles { get; set; } = new();
+}
+
+public class StagedFileInfo
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// write or delete
+    /// </summary>
+    public string Action { get; set; } = string.Empty;
+
+    public string? Note { get; set; }
+    public DateTime UpdatedAt { get; set; }
+}
+
+public class ScratchDiffResult
+{
+    public List<ScratchFileDiff> Files { get; set; } = new();
+    public int TotalAdded { get; set; }
+    public int TotalRemoved { get; set; }
+    public int Conflicts { get; set; }
+}
+
+public class SearchScratchResult
+{
+    public string Pattern { get; set; } = string.Empty;
+    public int FilesSearched { get; set; }
+    public List<ScratchSearchMatch> Matches { get; set; } = new();
+}
+
+public class CommitScratchResult
+{
+    public List<string> Written { get; set; } = new();
+    public List<string> Deleted { get; set; } = new();
+
+    /// <summary>
+    /// Files edited on disk since they were staged; set when the commit was refused because of them
+    /// </summary>
+    public List<string> Conflicts { get; set; } = new();
+
+    public bool RolledBack { get; set; }
+
+    /// <summary>
+    /// Files still staged after the commit
+    /// </summary>
+    public int Remaining { get; set; }
+}
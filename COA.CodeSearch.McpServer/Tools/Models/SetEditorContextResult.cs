@@ -0,0 +1,19 @@
+using COA.CodeSearch.McpServer.Services.Editor;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// The editor context now in effect and what tools will take from it
+/// </summary>
+public class SetEditorContextResult
+{
+    /// <summary>
+    /// Null after clear
+    /// </summary>
+    public EditorContext? Context { get; set; }
+
+    /// <summary>
+    /// Arguments each context-aware tool takes when a call leaves them empty, keyed by tool name
+    /// </summary>
+    public Dictionary<string, EditorContextUse> Defaults { get; set; } = new();
+}
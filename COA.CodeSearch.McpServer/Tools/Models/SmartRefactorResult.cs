@@ -40,6 +40,21 @@ public class SmartRefactorResult
     /// </summary>
     public List<string> Errors { get; set; } = new();
 
+    /// <summary>
+    /// What found the occurrences: "index", "roslyn", "go/types", or the language server that computed them
+    /// </summary>
+    public string Source { get; set; } = "index";
+
+    /// <summary>
+    /// How Roslyn's, go/types' or the language server's answer differed from the index's, when one was used
+    /// </summary>
+    public List<string> Notes { get; set; } = new();
+
+    /// <summary>
+    /// Unified diff of every file changed, for operations that produce one
+    /// </summary>
+    public string? Diff { get; set; }
+
     /// <summary>
     /// Next steps or suggestions
     /// </summary>
@@ -0,0 +1,99 @@
+using COA.CodeSearch.McpServer.Services.Lucene;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Merged results of a natural-language request together with the query plan that produced them
+/// </summary>
+public class SmartSearchResult
+{
+    public string Request { get; set; } = string.Empty;
+    public SmartSearchPlan Plan { get; set; } = new();
+
+    /// <summary>
+    /// Files ranked by how many parts of the request they match, then by combined score
+    /// </summary>
+    public List<SmartSearchHit> Results { get; set; } = new();
+
+    /// <summary>
+    /// Distinct files matched by any step, before the result limit
+    /// </summary>
+    public int TotalFiles { get; set; }
+}
+
+/// <summary>
+/// How the request was decomposed into index queries
+/// </summary>
+public class SmartSearchPlan
+{
+    public List<PlanClause> Clauses { get; set; } = new();
+    public List<PlanStep> Steps { get; set; } = new();
+}
+
+/// <summary>
+/// One part of the request, e.g. "users are deactivated"
+/// </summary>
+public class PlanClause
+{
+    public string Text { get; set; } = string.Empty;
+    public List<PlanConcept> Concepts { get; set; } = new();
+
+    /// <summary>
+    /// Set when a concept was carried over from the previous clause ("deactivated and cached" → users cached)
+    /// </summary>
+    public string? Inherited { get; set; }
+}
+
+/// <summary>
+/// A word of the request reduced to a prefix, with synonym prefixes that also count
+/// </summary>
+public class PlanConcept
+{
+    public string Word { get; set; } = string.Empty;
+    public string Prefix { get; set; } = string.Empty;
+    public List<string> Alternatives { get; set; } = new();
+}
+
+/// <summary>
+/// One executed index query
+/// </summary>
+public class PlanStep
+{
+    public int Id { get; set; }
+
+    /// <summary>
+    /// Index of the clause in <see cref="SmartSearchPlan.Clauses"/>
+    /// </summary>
+    public int Clause { get; set; }
+
+    /// <summary>
+    /// content_symbols for identifiers, content for full text
+    /// </summary>
+    public string Field { get; set; } = string.Empty;
+
+    public string Purpose { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Lucene form of the query, for transparency and reuse in text_search
+    /// </summary>
+    public string Query { get; set; } = string.Empty;
+
+    public double Weight { get; set; } = 1.0;
+    public int? HitCount { get; set; }
+}
+
+public class SmartSearchHit
+{
+    public string FilePath { get; set; } = string.Empty;
+    public double Score { get; set; }
+
+    /// <summary>
+    /// How many clauses of the request this file matched
+    /// </summary>
+    public int ClausesMatched { get; set; }
+
+    public List<int> MatchedSteps { get; set; } = new();
+    public int? LineNumber { get; set; }
+    public string? Snippet { get; set; }
+    public FileSummary? Summary { get; set; }
+}
@@ -0,0 +1,63 @@
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Symbol-level comparison of two index snapshots or two git refs
+/// </summary>
+public class SnapshotDiffResult
+{
+    public string? From { get; set; }
+    public string? To { get; set; }
+    public string Mode { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Snapshot saved by this call (saveAs)
+    /// </summary>
+    public IndexSnapshotInfo? Saved { get; set; }
+
+    public List<IndexSnapshotInfo> AvailableSnapshots { get; set; } = new();
+
+    public List<string> FilesAdded { get; set; } = new();
+    public List<string> FilesRemoved { get; set; } = new();
+    public int FilesModified { get; set; }
+
+    /// <summary>
+    /// Count of symbol changes by kind of change (added, removed, renamed, moved, modified)
+    /// </summary>
+    public Dictionary<string, int> SymbolSummary { get; set; } = new();
+
+    public List<SymbolChange> SymbolChanges { get; set; } = new();
+    public int TotalSymbolChanges { get; set; }
+
+    /// <summary>
+    /// Per-language file counts and sizes on both sides, largest change first
+    /// </summary>
+    public List<LanguageTrend> LanguageTrends { get; set; } = new();
+}
+
+/// <summary>
+/// How one language's share of the codebase changed
+/// </summary>
+public class LanguageTrend
+{
+    public string Language { get; set; } = string.Empty;
+    public int FromFiles { get; set; }
+    public int ToFiles { get; set; }
+    public long FromBytes { get; set; }
+    public long ToBytes { get; set; }
+
+    /// <summary>
+    /// Line counts; only available for index snapshots
+    /// </summary>
+    public int? FromLines { get; set; }
+    public int? ToLines { get; set; }
+
+    public long BytesChange => ToBytes - FromBytes;
+
+    /// <summary>
+    /// Size change in percent; null for a language that is new on the "to" side
+    /// </summary>
+    public double? PercentChange => FromBytes == 0 ? null : Math.Round(100.0 * (ToBytes - FromBytes) / FromBytes, 1);
+}
@@ -0,0 +1,81 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Reviewer suggestions for the changes between two refs
+/// </summary>
+public class SuggestReviewersResult
+{
+    public string BaseRef { get; set; } = string.Empty;
+    public string HeadRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// CODEOWNERS file used, relative to the repository root; null when the repository has none
+    /// </summary>
+    public string? CodeOwnersFile { get; set; }
+
+    /// <summary>
+    /// Reviewers ranked by ownership and familiarity with the changed code
+    /// </summary>
+    public List<SuggestedReviewer> Suggested { get; set; } = new();
+
+    /// <summary>
+    /// Every CODEOWNERS owner of a changed file - approvals branch protection may require
+    /// </summary>
+    public List<string> RequiredOwners { get; set; } = new();
+
+    public List<FileReviewers> Files { get; set; } = new();
+
+    /// <summary>
+    /// Changed files no CODEOWNERS rule covers
+    /// </summary>
+    public List<string> UnownedFiles { get; set; } = new();
+
+    /// <summary>
+    /// Authors of the change, never suggested
+    /// </summary>
+    public List<string> ExcludedAuthors { get; set; } = new();
+}
+
+/// <summary>
+/// A suggested reviewer with the reasons for the suggestion
+/// </summary>
+public class SuggestedReviewer
+{
+    /// <summary>
+    /// @handle when known (CODEOWNERS, alias configuration or GitHub noreply address), otherwise the email
+    /// </summary>
+    public string Reviewer { get; set; } = string.Empty;
+    public string? Name { get; set; }
+    public double Score { get; set; }
+    public bool IsCodeOwner { get; set; }
+    public int FilesCovered { get; set; }
+    public List<string> Reasons { get; set; } = new();
+}
+
+/// <summary>
+/// Owners and experts for one changed file
+/// </summary>
+public class FileReviewers
+{
+    public string Path { get; set; } = string.Empty;
+    public List<string> Owners { get; set; } = new();
+
+    /// <summary>
+    /// Authors of the changed lines (blame at the base ref) and recent committers, most familiar first
+    /// </summary>
+    public List<string> Experts { get; set; } = new();
+
+    public List<HunkReviewers> Hunks { get; set; } = new();
+}
+
+/// <summary>
+/// Authors of the code a hunk changes
+/// </summary>
+public class HunkReviewers
+{
+    /// <summary>
+    /// Enclosing function/class from git's hunk header, or the line range
+    /// </summary>
+    public string Location { get; set; } = string.Empty;
+    public List<string> Experts { get; set; } = new();
+}
@@ -74,9 +74,30 @@ public class SymbolDefinition
     public int Line { get; set; }
     
     /// <summary>
-    /// Column position where the symbol is defined
+    /// Column where the symbol is defined, in UTF-8 bytes (0-based, tree-sitter convention)
     /// </summary>
     public int Column { get; set; }
+
+    /// <summary>
+    /// Column where the symbol is defined, in UTF-16 code units (0-based, LSP convention)
+    /// </summary>
+    public int? Utf16Column { get; set; }
+
+    /// <summary>
+    /// UTF-8 byte offset of the definition from the start of the file, when known
+    /// </summary>
+    public int? ByteOffset { get; set; }
+
+    /// <summary>
+    /// Durable reference to this symbol ("anchor:path#Type.Member~fingerprint") that can be stored and passed
+    /// back in place of a symbol name after the file has changed
+    /// </summary>
+    public string? Anchor { get; set; }
+
+    /// <summary>
+    /// How the anchor passed in was re-resolved (exact, changed, moved, renamed), when one was passed
+    /// </summary>
+    public string? AnchorStatus { get; set; }
     
     /// <summary>
     /// Language of the source file
@@ -99,9 +120,35 @@ public class SymbolDefinition
     public List<string>? Interfaces { get; set; }
     
     /// <summary>
-    /// For methods: the containing type
+    /// For methods: the containing type - the enclosing class, or the receiver type of a Go method
     /// </summary>
     public string? ContainingType { get; set; }
+
+    /// <summary>
+    /// Name qualified by the containing type (UserService.GetUser), when there is one
+    /// </summary>
+    public string? QualifiedName { get; set; }
+
+    /// <summary>
+    /// Go: the build constraint of the defining file (<c>linux &amp;&amp; amd64</c>), when it has one
+    /// </summary>
+    public string? BuildConstraint { get; set; }
+
+    /// <summary>
+    /// Go: false when the build constraint excludes the workspace's GOOS/GOARCH profile
+    /// </summary>
+    public bool? InBuild { get; set; }
+
+    /// <summary>
+    /// True when the defining file lies in a vendored or third-party directory
+    /// </summary>
+    public bool? DependencyCode { get; set; }
+
+    /// <summary>
+    /// Go: embedded fields a promoted field or method was reached through, outermost first (Admin.Name found
+    /// on User through Admin's embedded User gives [User])
+    /// </summary>
+    public List<string>? PromotedVia { get; set; }
     
     /// <summary>
     /// For methods: return type
@@ -112,6 +159,16 @@ public class SymbolDefinition
     /// For methods: parameter list
     /// </summary>
     public List<string>? Parameters { get; set; }
+
+    /// <summary>
+    /// Type parameters of a generic type or function, with their constraints
+    /// </summary>
+    public List<TypeParameterInfo>? TypeParameters { get; set; }
+
+    /// <summary>
+    /// For constraint interfaces: the union terms of the type set
+    /// </summary>
+    public List<string>? TypeSet { get; set; }
     
     /// <summary>
     /// Number of references to this symbol (if requested)
@@ -41,6 +41,11 @@ public class SymbolsOverviewResult
     /// Methods/Functions found in the file (if not part of a type)
     /// </summary>
     public List<MethodOverview> Methods { get; set; } = new();
+
+    /// <summary>
+    /// Markdown headings in document order, the outline of a design document
+    /// </summary>
+    public List<SectionOverview> Sections { get; set; } = new();
     
     /// <summary>
     /// Total count of all symbols
@@ -84,9 +89,14 @@ public class TypeOverview
     public int Line { get; set; }
     
     /// <summary>
-    /// Column position where defined
+    /// Column position where defined (0-based, UTF-8 bytes)
     /// </summary>
     public int Column { get; set; }
+
+    /// <summary>
+    /// Column position where defined in UTF-16 code units, as LSP clients count it
+    /// </summary>
+    public int? Utf16Column { get; set; }
     
     /// <summary>
     /// Access modifiers (public, private, etc.)
@@ -140,9 +150,14 @@ public class MethodOverview
     public int Line { get; set; }
     
     /// <summary>
-    /// Column position where defined
+    /// Column position where defined (0-based, UTF-8 bytes)
     /// </summary>
     public int Column { get; set; }
+
+    /// <summary>
+    /// Column position where defined in UTF-16 code units, as LSP clients count it
+    /// </summary>
+    public int? Utf16Column { get; set; }
     
     /// <summary>
     /// Access modifiers and other modifiers
@@ -158,4 +173,40 @@ public class MethodOverview
     /// Type that contains this method (if any)
     /// </summary>
     public string? ContainingType { get; set; }
-}
\ No newline at end of file
+}
+
+/// <summary>
+/// Overview information for a Markdown heading and the section it starts
+/// </summary>
+public class SectionOverview
+{
+    /// <summary>
+    /// Heading text without inline markup
+    /// </summary>
+    public string Name { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Heading level, 1 for # through 6 for ######
+    /// </summary>
+    public int Level { get; set; }
+
+    /// <summary>
+    /// The heading with its GitHub anchor, e.g. "## Storage (#storage)"
+    /// </summary>
+    public string Signature { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Line number of the heading
+    /// </summary>
+    public int Line { get; set; }
+
+    /// <summary>
+    /// Last line of the section, before the next heading of the same or a higher level
+    /// </summary>
+    public int EndLine { get; set; }
+
+    /// <summary>
+    /// Languages of the fenced code blocks directly in this section
+    /// </summary>
+    public List<string> CodeBlocks { get; set; } = new();
+}
@@ -0,0 +1,49 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Shortest call paths from source symbols to sinks
+/// </summary>
+public class TaintPathsResult
+{
+    public List<TaintPath> Paths { get; set; } = new();
+
+    /// <summary>
+    /// Symbols the sources query selected (types expanded to their methods)
+    /// </summary>
+    public int SourceCount { get; set; }
+
+    /// <summary>
+    /// Symbols the sinks query selected
+    /// </summary>
+    public int SinkCount { get; set; }
+
+    /// <summary>
+    /// Symbols whose calls were followed
+    /// </summary>
+    public int Visited { get; set; }
+
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One path, source first: each step is a symbol and where it was called from
+/// </summary>
+public class TaintPath
+{
+    public string Source { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Sink symbol, or the matched sink call pattern
+    /// </summary>
+    public string Sink { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Calls from source to sink
+    /// </summary>
+    public int Length { get; set; }
+
+    /// <summary>
+    /// Steps as "Name (path:line)", where the line is the call into that step; the last step of a sink-call path is the call itself
+    /// </summary>
+    public List<string> Steps { get; set; } = new();
+}
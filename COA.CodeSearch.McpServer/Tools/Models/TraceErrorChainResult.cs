@@ -0,0 +1,22 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Propagation of a Go error from where it is made to where it is checked; file paths are workspace-relative
+/// </summary>
+public class TraceErrorChainResult
+{
+    public GoErrorOrigin Origin { get; set; } = new();
+
+    /// <summary>
+    /// One line per path, producer first
+    /// </summary>
+    public List<string> Paths { get; set; } = new();
+
+    public List<GoErrorHop> Hops { get; set; } = new();
+    public List<GoErrorCheck> Checks { get; set; } = new();
+    public int FilesScanned { get; set; }
+    public bool DepthLimited { get; set; }
+    public bool Truncated { get; set; }
+}
@@ -0,0 +1,92 @@
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Vendored modules and copied files with their upstream origins and whether they were modified locally;
+/// paths are workspace-relative
+/// </summary>
+public class VendoredProvenanceResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Go module cache vendor/ was compared against; null when no vendor/modules.txt was found
+    /// </summary>
+    public string? ModuleCache { get; set; }
+
+    public List<VendoredModuleStatus> Modules { get; set; } = new();
+
+    /// <summary>
+    /// Modified, added, unlisted and unverified files, and copies found by header comment; clean and unchecked
+    /// vendor files only with showClean
+    /// </summary>
+    public List<VendoredFileStatus> Files { get; set; } = new();
+
+    public int FilesScanned { get; set; }
+    public int FilesCompared { get; set; }
+    public int Modified { get; set; }
+    public int Unverified { get; set; }
+
+    /// <summary>
+    /// True when maxFiles stopped the comparison early
+    /// </summary>
+    public bool Truncated { get; set; }
+}
+
+/// <summary>
+/// One module of a vendor/modules.txt and how its vendored files compare with upstream
+/// </summary>
+public class VendoredModuleStatus
+{
+    /// <summary>
+    /// The vendor directory holding the module, e.g. vendor or tools/vendor
+    /// </summary>
+    public string VendorDirectory { get; set; } = string.Empty;
+    public string Path { get; set; } = string.Empty;
+    public string Version { get; set; } = string.Empty;
+    public string? Replacement { get; set; }
+
+    /// <summary>
+    /// clean, modified, unverified, or unchecked when checkModifications is off
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+    public int Files { get; set; }
+    public int Modified { get; set; }
+    public int Unverified { get; set; }
+}
+
+/// <summary>
+/// A vendored or copied file, where it came from and how it compares with upstream
+/// </summary>
+public class VendoredFileStatus
+{
+    public string FilePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// go-vendor for files listed by vendor/modules.txt, header for files whose comments name their origin
+    /// </summary>
+    public string Source { get; set; } = string.Empty;
+
+    /// <summary>
+    /// module@version for Go vendor files, the URL for header origins
+    /// </summary>
+    public string Origin { get; set; } = string.Empty;
+    public string? Revision { get; set; }
+
+    /// <summary>
+    /// clean, modified, added (not in upstream), unlisted (in vendor/ but no module lists it), unverified, or
+    /// unchecked when checkModifications is off
+    /// </summary>
+    public string Status { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Why the file could not be compared, or a note on how it was
+    /// </summary>
+    public string? Reason { get; set; }
+    public int AddedLines { get; set; }
+    public int RemovedLines { get; set; }
+
+    /// <summary>
+    /// Unified diff from upstream to the local copy
+    /// </summary>
+    public string? Diff { get; set; }
+}
@@ -0,0 +1,18 @@
+using COA.CodeSearch.McpServer.Services.Watches;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Watches of a workspace and the alerts they raised
+/// </summary>
+public class WatchResult
+{
+    public string Action { get; set; } = string.Empty;
+    public List<WatchDefinition> Watches { get; set; } = new();
+    public List<WatchAlert> Alerts { get; set; } = new();
+
+    /// <summary>
+    /// Highest alert sequence returned; pass it as since to fetch only newer alerts
+    /// </summary>
+    public long LastSequence { get; set; }
+}
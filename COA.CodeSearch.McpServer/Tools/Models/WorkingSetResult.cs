@@ -0,0 +1,31 @@
+using COA.CodeSearch.McpServer.Services.WorkingSet;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// The session's working set in a workspace
+/// </summary>
+public class WorkingSetResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Files viewed, opened or edited, highest weight first
+    /// </summary>
+    public List<WorkingSetEntry> Files { get; set; } = new();
+
+    /// <summary>
+    /// Symbol names looked up, highest weight first
+    /// </summary>
+    public List<WorkingSetSymbol> Symbols { get; set; } = new();
+
+    /// <summary>
+    /// Files boosted only as neighbors of working-set files
+    /// </summary>
+    public int NeighborCount { get; set; }
+
+    /// <summary>
+    /// Files forgotten by clear
+    /// </summary>
+    public int Cleared { get; set; }
+}
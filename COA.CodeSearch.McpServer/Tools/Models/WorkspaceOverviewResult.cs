@@ -0,0 +1,23 @@
+using COA.CodeSearch.McpServer.Services.Analysis;
+
+namespace COA.CodeSearch.McpServer.Tools.Models;
+
+/// <summary>
+/// Onboarding report of a workspace; file paths are workspace-relative
+/// </summary>
+public class WorkspaceOverviewResult
+{
+    public string WorkspacePath { get; set; } = string.Empty;
+
+    public WorkspaceReport Report { get; set; } = new();
+
+    /// <summary>
+    /// Files left out as vendored or third-party code
+    /// </summary>
+    public int DependencyFilesSkipped { get; set; }
+
+    /// <summary>
+    /// Clusters found before MaxClusters applied
+    /// </summary>
+    public int TotalClusters { get; set; }
+}
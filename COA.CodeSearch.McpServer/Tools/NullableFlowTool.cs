@@ -0,0 +1,153 @@
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using COA.CodeSearch.McpServer.Services.Roslyn;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Reports the C# compiler's nullable analysis at a position - declared annotation, flow state and the
+/// nullable warnings on the line - from the Roslyn tier. The index has no type information to answer this.
+/// </summary>
+public class NullableFlowTool : CodeSearchToolBase<NullableFlowParameters, AIOptimizedResponse<NullableFlowResult>>
+{
+    private readonly IRoslynAnalysisService _roslyn;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<NullableFlowTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the NullableFlowTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="roslyn">Roslyn analysis tier</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public NullableFlowTool(
+        IServiceProvider serviceProvider,
+        IRoslynAnalysisService roslyn,
+        IPathResolutionService pathResolutionService,
+        ILogger<NullableFlowTool> logger) : base(serviceProvider, logger)
+    {
+        _roslyn = roslyn;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.NullableFlow;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "C# NULL ANALYSIS - Can this expression be null here? Returns the compiler's flow state (not-null/maybe-null), the declared annotation and nullable warnings on the line. " +
+        "Use before adding null checks or '!' instead of guessing. Requires the Roslyn tier (CodeSearch:Roslyn:Enabled).";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Executes the nullable analysis at the position.
+    /// </summary>
+    /// <param name="parameters">Position and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Annotation, flow state and warnings at the position</returns>
+    protected override async Task<AIOptimizedResponse<NullableFlowResult>> ExecuteInternalAsync(
+        NullableFlowParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var position = ValidateRequired(parameters.Position, nameof(parameters.Position));
+        if (!SourcePositions.TryParseLocation(position, out var filePath, out var line, out var column))
+        {
+            return Failure("INVALID_POSITION", $"'{position}' is not a position 'path:line:column'",
+                "Use a 1-based line and 0-based column, e.g. src/Services/UserService.cs:42:16");
+        }
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return Failure("INVALID_COLUMN_ENCODING", $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                "Use 'utf-16' for LSP positions or 'utf-8' for byte columns");
+        }
+        if (!_roslyn.IsEnabled)
+        {
+            return Failure("ROSLYN_DISABLED", "Nullable analysis needs the Roslyn tier, which is disabled",
+                "Set CodeSearch:Roslyn:Enabled to true in appsettings.json and restart the server");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+
+        var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+        if (!_roslyn.Handles(fullPath))
+        {
+            return Failure("NOT_CSHARP", $"{filePath} is not a C# file", "nullable_flow only analyses .cs files");
+        }
+
+        var character = string.Equals(parameters.ColumnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+            ? await CreateSourcePositions(workspacePath).ToUtf16ColumnAsync(filePath, line, column, cancellationToken)
+            : column;
+
+        var result = await _roslyn.GetNullableFlowAsync(workspacePath, fullPath, new LspPosition(line - 1, character), cancellationToken);
+        if (result == null)
+        {
+            _logger.LogInformation("No nullable analysis for {Position}", position);
+            return Failure("NO_ANALYSIS", $"No expression or declaration at {position} in a loaded project",
+                "Check the file belongs to a project in the workspace's solution (or set CodeSearch:Roslyn:SolutionPath)",
+                "Point the column at an identifier rather than whitespace or punctuation",
+                "See the server log for MSBuild load errors (a .NET SDK must be installed)");
+        }
+
+        var response = new AIOptimizedResponse<NullableFlowResult>
+        {
+            Success = true,
+            Data = new AIResponseData<NullableFlowResult> { Results = result },
+            Message = $"{result.Expression}: {result.FlowState} (declared {result.DeclaredAnnotation})"
+        };
+
+        var insights = new List<string>();
+        if (!result.AnnotationsEnabled)
+        {
+            insights.Add("Nullable annotations are disabled here (#nullable or <Nullable>) - the compiler tracks no null state");
+        }
+        else if (result.FlowState == "maybe-null")
+        {
+            insights.Add($"'{result.Expression}' may be null here - check it before dereferencing");
+        }
+        else if (result.FlowState == "not-null" && result.DeclaredAnnotation == "annotated")
+        {
+            insights.Add("Declared nullable but proven not null at this point - no check needed here");
+        }
+        if (result.AnnotationsEnabled && !result.WarningsEnabled)
+        {
+            insights.Add("Nullable warnings are disabled in this context, so unsafe dereferences are not reported");
+        }
+        if (result.Diagnostics.Count > 0)
+        {
+            insights.Add($"{result.Diagnostics.Count} nullable warning(s) on this line");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<NullableFlowResult> Failure(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,217 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.OpenApi;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Lists the operations and schemas of the workspace's OpenAPI specs with the handlers serving each operation
+/// and the DTO types each schema describes, and reports where spec and code have drifted apart
+/// </summary>
+public class OpenApiLinksTool : CodeSearchToolBase<OpenApiLinksParameters, AIOptimizedResponse<OpenApiLinksResult>>
+{
+    private static readonly string[] DriftKinds = { "unlinked-operation", "unlinked-schema", "missing-property", "extra-property" };
+
+    private readonly IOpenApiIndexService _openApiIndexService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<OpenApiLinksTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the OpenApiLinksTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="openApiIndexService">OpenAPI spec index</param>
+    /// <param name="sqliteService">SQLite symbol service for the index check</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public OpenApiLinksTool(
+        IServiceProvider serviceProvider,
+        IOpenApiIndexService openApiIndexService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<OpenApiLinksTool> logger) : base(serviceProvider, logger)
+    {
+        _openApiIndexService = openApiIndexService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.OpenApiLinks;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "FROM SPEC TO CODE. Indexes OpenAPI/Swagger documents (JSON or YAML) and links each operation to the handler " +
+        "serving it (through the route, or its operationId) and each schema to the class, struct, record or interface it " +
+        "describes. Reports drift: operations and schemas with no code, and schema properties missing from or extra in the DTO.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Loads the spec index and applies the filters.
+    /// </summary>
+    /// <param name="parameters">Operation and schema filters, drift switch and limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Operations, schemas and drift with their code locations</returns>
+    protected override async Task<AIOptimizedResponse<OpenApiLinksResult>> ExecuteInternalAsync(
+        OpenApiLinksParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try openapi_links again");
+        }
+
+        var index = await _openApiIndexService.GetIndexAsync(workspacePath, cancellationToken);
+        if (index.Specs.Count == 0)
+        {
+            return CreateErrorResponse("NO_SPECS", "No OpenAPI or Swagger documents were found in this workspace",
+                "Specs are read from indexed .json, .yaml and .yml files with an openapi or swagger key and a paths section",
+                "Reindex with mcp__codesearch__index_workspace if the spec was added recently");
+        }
+
+        var operationFilter = parameters.Operation?.Trim();
+        var schemaFilter = parameters.Schema?.Trim();
+        var hasOperationFilter = !string.IsNullOrEmpty(operationFilter);
+        var hasSchemaFilter = !string.IsNullOrEmpty(schemaFilter);
+
+        // Either filter alone narrows to its own kind; without filters everything is listed
+        var operations = hasSchemaFilter && !hasOperationFilter
+            ? new List<OpenApiOperation>()
+            : index.Specs.SelectMany(s => s.Operations).Where(o => !hasOperationFilter || OperationMatches(o, operationFilter!)).ToList();
+        var schemas = hasOperationFilter && !hasSchemaFilter
+            ? new List<OpenApiSchema>()
+            : index.Specs.SelectMany(s => s.Schemas).Where(s => !hasSchemaFilter || s.Name.Equals(schemaFilter, StringComparison.OrdinalIgnoreCase)).ToList();
+
+        if (hasOperationFilter && operations.Count == 0)
+        {
+            return CreateErrorResponse("OPERATION_NOT_FOUND", $"No operation matches '{operationFilter}'",
+                "Use 'METHOD /path', a path such as '/users/{id}', or an operationId",
+                "Run openapi_links without operation to list every operation");
+        }
+        if (hasSchemaFilter && schemas.Count == 0)
+        {
+            return CreateErrorResponse("SCHEMA_NOT_FOUND", $"No schema is named '{schemaFilter}'",
+                "Run openapi_links without schema to list every schema");
+        }
+
+        var subjects = operations.Select(o => $"{o.Method} {o.Path}").ToHashSet(StringComparer.Ordinal);
+        var schemaNames = schemas.Select(s => s.Name).ToHashSet(StringComparer.Ordinal);
+        var drift = index.Drift
+            .Where(d => !hasOperationFilter && !hasSchemaFilter || subjects.Contains(d.Subject)
+                || schemaNames.Contains(d.Subject.Split('.')[0]))
+            .OrderBy(d => Array.IndexOf(DriftKinds, d.Kind))
+            .ThenBy(d => d.FilePath, StringComparer.Ordinal)
+            .ThenBy(d => d.Line)
+            .ToList();
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var result = new OpenApiLinksResult
+        {
+            Specs = index.Specs.Select(s => $"{s.FilePath} ({s.Title ?? "untitled"}, {(s.Version.StartsWith('2') ? "swagger" : "openapi")} {s.Version})").ToList(),
+            Operations = parameters.DriftOnly ? new List<OpenApiOperation>() : operations.Take(limit).ToList(),
+            Schemas = parameters.DriftOnly ? new List<OpenApiSchema>() : schemas.Take(limit).ToList(),
+            Drift = drift.Take(limit).ToList(),
+            DriftCounts = drift.GroupBy(d => d.Kind).ToDictionary(g => g.Key, g => g.Count()),
+            TotalOperations = operations.Count,
+            TotalSchemas = schemas.Count,
+            Truncated = drift.Count > limit || !parameters.DriftOnly && (operations.Count > limit || schemas.Count > limit)
+        };
+
+        _logger.LogDebug("openapi_links: {Operations} operations, {Schemas} schemas, {Drift} drift", operations.Count, schemas.Count, drift.Count);
+
+        var linkedOperations = operations.Count(o => o.Handlers.Count > 0);
+        var linkedSchemas = schemas.Count(s => s.Types.Count > 0);
+        var response = new AIOptimizedResponse<OpenApiLinksResult>
+        {
+            Success = true,
+            Data = new AIResponseData<OpenApiLinksResult> { Results = result },
+            Message = $"{index.Specs.Count} spec(s): {linkedOperations}/{operations.Count} operation(s) linked to handlers, " +
+                      $"{linkedSchemas}/{schemas.Count} schema(s) linked to types" +
+                      (drift.Count > 0
+                          ? "; drift: " + string.Join(", ", DriftKinds.Where(result.DriftCounts.ContainsKey).Select(k => $"{result.DriftCounts[k]} {k}"))
+                          : "; no drift")
+        };
+
+        var insights = new List<string>();
+        if (index.Routes == 0 && operations.Count > 0)
+        {
+            insights.Add("No server routes were recognized, so operations were linked through operationId only");
+        }
+        if (operations.Any(o => o.Handlers.Any(h => h.Kind == "route")))
+        {
+            insights.Add("Handlers of kind 'route' are inline or could not be resolved - the location is the route registration");
+        }
+        if (result.DriftCounts.GetValueOrDefault("missing-property") + result.DriftCounts.GetValueOrDefault("extra-property") > 0)
+        {
+            insights.Add("Property names are compared ignoring case, '_' and '-' - a custom naming policy or converter can still make a match look like drift");
+        }
+        if (schemas.Any(s => s.Types.Count > 1))
+        {
+            insights.Add("Some schemas match several types by name - property drift is reported against each");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped at {limit} entries - filter by operation or schema, or use driftOnly");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    /// <summary>
+    /// 'GET /users/{id}', '/users/{id}' or an operationId; path parameters match whatever they are named
+    /// </summary>
+    private static bool OperationMatches(OpenApiOperation operation, string filter)
+    {
+        var parts = filter.Split(' ', 2, StringSplitOptions.RemoveEmptyEntries | StringSplitOptions.TrimEntries);
+        if (parts.Length == 2 && parts[1].StartsWith('/'))
+            return operation.Method.Equals(parts[0], StringComparison.OrdinalIgnoreCase) && PathsMatch(operation.Path, parts[1]);
+        if (filter.StartsWith('/'))
+            return PathsMatch(operation.Path, filter);
+        return filter.Equals(operation.OperationId, StringComparison.OrdinalIgnoreCase);
+    }
+
+    private static bool PathsMatch(string operationPath, string filter)
+    {
+        static string Shape(string path) => Regex.Replace(ApiContracts.NormalizePath(path), @"\{[^}]*\}", "{}");
+        var shape = Shape(filter);
+        var operationShape = Shape(operationPath);
+
+        // The filter may leave out the server base path
+        return operationShape.Equals(shape, StringComparison.OrdinalIgnoreCase)
+            || operationShape.EndsWith(shape, StringComparison.OrdinalIgnoreCase) && shape.Length > 1;
+    }
+
+    private static AIOptimizedResponse<OpenApiLinksResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
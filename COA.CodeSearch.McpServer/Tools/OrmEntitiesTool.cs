@@ -0,0 +1,173 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Orm;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Lists ORM entities with the table and columns each maps to, from Go struct tags, EF Core attributes and
+/// fluent configuration, SQLAlchemy and Django models
+/// </summary>
+public class OrmEntitiesTool : CodeSearchToolBase<OrmEntitiesParameters, AIOptimizedResponse<OrmEntitiesResult>>
+{
+    private readonly IOrmSchemaService _ormSchemaService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<OrmEntitiesTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the OrmEntitiesTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="ormSchemaService">ORM entity catalog</param>
+    /// <param name="sqliteService">SQLite symbol service for the index check</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public OrmEntitiesTool(
+        IServiceProvider serviceProvider,
+        IOrmSchemaService ormSchemaService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<OrmEntitiesTool> logger) : base(serviceProvider, logger)
+    {
+        _ormSchemaService = ormSchemaService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.OrmEntities;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHICH TABLE IS THIS TYPE? Lists ORM entities with the database table and columns each maps to - Go struct tags " +
+        "(gorm, db/sqlx, bun), EF Core attributes and fluent configuration, SQLAlchemy and Django models - including columns " +
+        "named by convention. Filter by entity or table. Pair with column_usages to find the code touching a column.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Loads the entity catalog and applies the filters.
+    /// </summary>
+    /// <param name="parameters">Entity and table filters and the limit</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Entities with their tables and columns</returns>
+    protected override async Task<AIOptimizedResponse<OrmEntitiesResult>> ExecuteInternalAsync(
+        OrmEntitiesParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try orm_entities again");
+        }
+
+        var entities = await _ormSchemaService.GetEntitiesAsync(workspacePath, cancellationToken);
+        var total = entities.Count;
+        var matching = entities
+            .Where(e => string.IsNullOrWhiteSpace(parameters.Entity) || e.Name.Equals(parameters.Entity.Trim(), StringComparison.OrdinalIgnoreCase))
+            .Where(e => string.IsNullOrWhiteSpace(parameters.Table) || e.Table.Equals(parameters.Table.Trim(), StringComparison.OrdinalIgnoreCase))
+            .OrderBy(e => e.Table, StringComparer.OrdinalIgnoreCase)
+            .ThenBy(e => e.Name, StringComparer.Ordinal)
+            .ToList();
+
+        if (matching.Count == 0)
+        {
+            return total == 0
+                ? CreateErrorResponse("NO_ENTITIES", "No ORM entities were recognized in this workspace",
+                    "Entities are read from gorm/db/bun struct tags, EF Core [Table]/[Column] and DbContext configuration, SQLAlchemy and Django models",
+                    "Reindex with mcp__codesearch__index_workspace if models were added recently")
+                : CreateErrorResponse("ENTITY_NOT_FOUND", $"None of the {total} ORM entities match the filter",
+                    "Run orm_entities without entity or table to list them all");
+        }
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+
+        // Entities come from the service's cached catalog, so they are copied rather than edited
+        var result = new OrmEntitiesResult
+        {
+            Entities = matching.Take(limit).Select(e => Copy(workspacePath, e)).ToList(),
+            TotalCount = matching.Count,
+            Truncated = matching.Count > limit
+        };
+
+        _logger.LogDebug("orm_entities: {Count} of {Total} entities", result.Entities.Count, total);
+
+        var response = new AIOptimizedResponse<OrmEntitiesResult>
+        {
+            Success = true,
+            Data = new AIResponseData<OrmEntitiesResult> { Results = result },
+            Message = $"{result.TotalCount} entit{(result.TotalCount == 1 ? "y" : "ies")} mapping " +
+                      $"{result.Entities.Select(e => e.Table).Distinct(StringComparer.OrdinalIgnoreCase).Count()} table(s)"
+        };
+
+        var insights = new List<string>();
+        var shared = matching.GroupBy(e => e.Table, StringComparer.OrdinalIgnoreCase).Where(g => g.Count() > 1).Select(g => g.Key).ToList();
+        if (shared.Count > 0)
+        {
+            insights.Add($"Several entities map {string.Join(", ", shared.Take(5))} - usually DTOs or partial views of one table");
+        }
+        var conventional = result.Entities.Count(e => e.TableSource == "convention");
+        if (conventional > 0)
+        {
+            insights.Add($"{conventional} table name(s) come from naming convention rather than an explicit mapping - check them against the schema");
+        }
+        if (result.Truncated)
+        {
+            insights.Add("Results were cut at maxResults - filter by entity or table");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static OrmEntity Copy(string workspacePath, OrmEntity entity) => new()
+    {
+        Name = entity.Name,
+        Table = entity.Table,
+        Framework = entity.Framework,
+        TableSource = entity.TableSource,
+        FilePath = Relative(workspacePath, entity.FilePath),
+        Line = entity.Line,
+        Columns = entity.Columns.Select(c => new OrmColumn
+        {
+            Member = c.Member,
+            Column = c.Column,
+            Explicit = c.Explicit,
+            PrimaryKey = c.PrimaryKey,
+            FilePath = Relative(workspacePath, c.FilePath),
+            Line = c.Line
+        }).ToList()
+    };
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<OrmEntitiesResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,38 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the api_contracts tool - server routes, client calls and OpenAPI specs checked against each other
+/// </summary>
+public class ApiContractsParameters
+{
+    /// <summary>
+    /// Finding kinds to report (default: all)
+    /// </summary>
+    /// <example>["missing", "method-mismatch"]</example>
+    [Description("Finding kinds to report: missing, method-mismatch, parameter-drift, unimplemented, undocumented, uncalled (default: all)")]
+    public List<string>? Kinds { get; set; }
+
+    /// <summary>
+    /// Glob restricting the files whose findings are reported; every file is still read so routes and calls elsewhere count
+    /// </summary>
+    /// <example>web/src/**</example>
+    [Description("Only report findings in files matching this glob, e.g. 'web/**' or '*.go'; routes and calls in other files are still matched")]
+    public string? FilePattern { get; set; }
+
+    /// <summary>
+    /// Maximum findings to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum findings to return (default: 200)")]
+    public int MaxResults { get; set; } = 200;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
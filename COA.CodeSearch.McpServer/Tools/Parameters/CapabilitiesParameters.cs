@@ -0,0 +1,17 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the capabilities tool - describes what this server supports so clients can adapt
+/// </summary>
+public class CapabilitiesParameters
+{
+    /// <summary>
+    /// Only report this language, matched by name or file extension
+    /// </summary>
+    /// <example>typescript</example>
+    /// <example>.vue</example>
+    [Description("Only report one language, by name or extension. Examples: 'typescript', 'csharp', '.vue'")]
+    public string? Language { get; set; }
+}
@@ -0,0 +1,36 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the check_architecture tool - layering rules checked against imports and references
+/// </summary>
+public class CheckArchitectureParameters
+{
+    /// <summary>
+    /// Only check rules whose name contains this text
+    /// </summary>
+    [Description("Only check rules whose name contains this text (default: all rules)")]
+    public string? Rule { get; set; }
+
+    /// <summary>
+    /// Maximum violations to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum violations to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Include vendored and third-party directories
+    /// </summary>
+    [Description("Check vendored and third-party directories (see dependency_code) too (default: false)")]
+    public bool IncludeDependencyCode { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
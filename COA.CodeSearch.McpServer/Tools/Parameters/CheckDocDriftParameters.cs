@@ -0,0 +1,42 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the check_doc_drift tool - doc comments checked against the code they document
+/// </summary>
+public class CheckDocDriftParameters
+{
+    /// <summary>
+    /// Only report this kind of drift
+    /// </summary>
+    [Description("Only report this kind of drift: parameter, reference, name or value (default: all)")]
+    public string? Kind { get; set; }
+
+    /// <summary>
+    /// Only check symbols of this language
+    /// </summary>
+    [Description("Only check symbols of this language, e.g. csharp or python (default: all)")]
+    public string? Language { get; set; }
+
+    /// <summary>
+    /// Only check files under this workspace-relative directory
+    /// </summary>
+    [Description("Only check files under this workspace-relative directory, e.g. src/Orders (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Maximum findings to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum findings to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,36 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the check_namespaces tool - namespace and package declarations checked against directories
+/// </summary>
+public class CheckNamespacesParameters
+{
+    /// <summary>
+    /// Only check files of this language
+    /// </summary>
+    [Description("Only check files of this language: csharp, go, java, kotlin or scala (default: all)")]
+    public string? Language { get; set; }
+
+    /// <summary>
+    /// Only check files under this workspace-relative directory
+    /// </summary>
+    [Description("Only check files under this workspace-relative directory, e.g. src/Acme.Api (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Maximum violations to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum violations to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
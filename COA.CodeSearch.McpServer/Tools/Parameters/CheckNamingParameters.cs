@@ -0,0 +1,42 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the check_naming tool - symbol names checked against naming rules
+/// </summary>
+public class CheckNamingParameters
+{
+    /// <summary>
+    /// Only check this rule
+    /// </summary>
+    [Description("Only check this rule, e.g. go-exported-names or csharp-interface-prefix (default: all enabled rules)")]
+    public string? Rule { get; set; }
+
+    /// <summary>
+    /// Only check symbols of this language
+    /// </summary>
+    [Description("Only check symbols of this language, e.g. csharp or go (default: all)")]
+    public string? Language { get; set; }
+
+    /// <summary>
+    /// Only check files under this workspace-relative directory
+    /// </summary>
+    [Description("Only check files under this workspace-relative directory, e.g. pkg/api (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Maximum violations to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum violations to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
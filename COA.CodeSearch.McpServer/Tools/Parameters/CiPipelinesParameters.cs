@@ -0,0 +1,43 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the ci_pipelines tool - GitHub Actions and Azure Pipelines definitions cross-referenced against the workspace
+/// </summary>
+public class CiPipelinesParameters
+{
+    /// <summary>
+    /// Only pipelines whose file path or name contains this text
+    /// </summary>
+    /// <example>release</example>
+    [Description("Only pipelines whose file path or name contains this text, e.g. 'release' or 'ci.yml' (default: all)")]
+    public string? Pipeline { get; set; }
+
+    /// <summary>
+    /// Only references to paths that do not exist, and variables that are unread or undefined
+    /// </summary>
+    [Description("Only references to missing paths and variables that are unread or undefined (default: false)")]
+    public bool ProblemsOnly { get; set; }
+
+    /// <summary>
+    /// Cross-reference environment variables against the code that reads them
+    /// </summary>
+    [Description("Cross-reference pipeline variables against the code and scripts that read them (default: true)")]
+    public bool IncludeVariables { get; set; } = true;
+
+    /// <summary>
+    /// Maximum references and variables to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum references and variables to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
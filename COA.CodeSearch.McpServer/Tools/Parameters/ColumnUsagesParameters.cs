@@ -0,0 +1,46 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the column_usages tool - code reading or writing a database column
+/// </summary>
+public class ColumnUsagesParameters
+{
+    /// <summary>
+    /// Column name, optionally qualified by its table
+    /// </summary>
+    /// <example>is_active</example>
+    /// <example>users.is_active</example>
+    [Required]
+    [Description("Column name, optionally table-qualified, e.g. 'is_active' or 'users.is_active'")]
+    public string Column { get; set; } = string.Empty;
+
+    /// <summary>
+    /// write, read or all
+    /// </summary>
+    [Description("Which usages to return: 'write', 'read' or 'all' (default: all)")]
+    public string Access { get; set; } = "all";
+
+    /// <summary>
+    /// Glob restricting the files whose usages are reported
+    /// </summary>
+    /// <example>internal/**</example>
+    [Description("Only report usages in files matching this glob, e.g. '*.go' or 'src/**'")]
+    public string? FilePattern { get; set; }
+
+    /// <summary>
+    /// Maximum usages to return
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum usages to return (default: 200)")]
+    public int MaxResults { get; set; } = 200;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,28 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the commit_scratch tool - writes staged files to the workspace as one unit
+/// </summary>
+public class CommitScratchParameters
+{
+    /// <summary>
+    /// Commit only these staged files (default: every staged file)
+    /// </summary>
+    [Description("Only these staged files (default: all)")]
+    public List<string>? FilePaths { get; set; }
+
+    /// <summary>
+    /// Overwrite files that changed on disk after they were staged
+    /// </summary>
+    [Description("Overwrite files edited on disk since they were staged (default: false - the commit is refused)")]
+    public bool Force { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,46 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the data_flow tool - where a variable's value comes from and where it goes
+/// </summary>
+public class DataFlowParameters
+{
+    /// <summary>
+    /// Position of the variable or parameter as "path:line:column" (1-based line, 0-based column)
+    /// </summary>
+    /// <example>src/Data/UserRepository.cs:58:24</example>
+    [Required]
+    [Description("Position 'path:line:column' (1-based line, 0-based column) of a variable or parameter inside a function (e.g., src/Data/UserRepository.cs:58:24)")]
+    public string Position { get; set; } = string.Empty;
+
+    /// <summary>
+    /// "sources" (where the value comes from), "sinks" (where it goes) or "both"
+    /// </summary>
+    /// <example>sources</example>
+    [Description("'sources' (assignments, parameters, caller arguments), 'sinks' (calls, assignments, returns) or 'both' (default)")]
+    public string Direction { get; set; } = "both";
+
+    /// <summary>
+    /// How many calls to follow away from the starting function
+    /// </summary>
+    [Range(0, 5)]
+    [Description("Calls to follow into callers and callees (default: 2, 0 stays inside the function)")]
+    public int Depth { get; set; } = 2;
+
+    /// <summary>
+    /// How the column is counted: utf-16 code units as in LSP (default) or utf-8 bytes
+    /// </summary>
+    /// <example>utf-16</example>
+    [Description("Column unit: 'utf-16' (LSP, default) or 'utf-8' (bytes)")]
+    public string ColumnEncoding { get; set; } = "utf-16";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
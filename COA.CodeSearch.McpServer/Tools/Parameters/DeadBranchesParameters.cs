@@ -0,0 +1,39 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the dead_branches tool - conditionals that known constant configuration decides
+/// </summary>
+public class DeadBranchesParameters
+{
+    /// <summary>
+    /// Known values as NAME=value; names may be qualified and match references ending with them
+    /// </summary>
+    /// <example>["Features.NewCheckout=false", "DEBUG=0", "config.region=\"eu\""]</example>
+    [Required(ErrorMessage = "Constants is required")]
+    [Description("Known values as NAME=value, e.g. [\"Features.NewCheckout=false\", \"DEBUG=0\", \"Region=\\\"eu\\\"\"]. true/on/yes and false/off/no are booleans; a qualified name also matches longer references ending with it")]
+    public List<string> Constants { get; set; } = new();
+
+    /// <summary>
+    /// Glob restricting the files scanned
+    /// </summary>
+    /// <example>*.cs</example>
+    [Description("Only scan files whose path matches this glob, e.g. '*.cs' or '*/src/*'")]
+    public string? FilePattern { get; set; }
+
+    /// <summary>
+    /// Maximum number of conditionals to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum conditionals to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,37 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the dependency_code tool - shows or sets how a workspace indexes vendored and third-party directories
+/// </summary>
+public class DependencyCodeParameters
+{
+    /// <summary>
+    /// Indexing mode to set
+    /// </summary>
+    /// <example>exclude</example>
+    /// <example>downrank</example>
+    [Description("Set the mode for this workspace: 'exclude' leaves dependency code out of the index, 'include' indexes it flagged as dependency code, 'downrank' also ranks it below first-party code in text_search. Omit to keep the current one")]
+    public string? Mode { get; set; }
+
+    /// <summary>
+    /// Dependency directories to set, replacing the current ones
+    /// </summary>
+    /// <example>["vendor", "third_party", "src/legacy/libs"]</example>
+    [Description("Set the dependency directories, replacing the current ones: a bare name like 'vendor' matches that directory anywhere, a path like 'src/legacy/libs' matches it from the workspace root")]
+    public List<string>? Directories { get; set; }
+
+    /// <summary>
+    /// Go back to the configured default
+    /// </summary>
+    [Description("Remove this workspace's settings and go back to the configured default (default: false)")]
+    public bool Reset { get; set; } = false;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,30 @@
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Marks a parameter property as a legacy name kept for older clients during a deprecation window.
+/// Values sent under the old name are copied to <see cref="ReplacedBy"/> before validation and a
+/// deprecation notice is returned with the response.
+/// </summary>
+[AttributeUsage(AttributeTargets.Property, AllowMultiple = false)]
+public sealed class DeprecatedParameterAttribute : Attribute
+{
+    public DeprecatedParameterAttribute(string replacedBy)
+    {
+        ReplacedBy = replacedBy;
+    }
+
+    /// <summary>
+    /// Name of the property that replaces this one
+    /// </summary>
+    public string ReplacedBy { get; }
+
+    /// <summary>
+    /// Schema version in which the old name was deprecated
+    /// </summary>
+    public string Since { get; init; } = ToolSchemaVersions.Default;
+
+    /// <summary>
+    /// Schema version in which the old name will stop being accepted
+    /// </summary>
+    public string? RemovedIn { get; init; }
+}
@@ -0,0 +1,60 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the detect_renames tool - finds symbols renamed or moved between two refs
+/// </summary>
+public class DetectRenamesParameters
+{
+    /// <summary>
+    /// Start of the range (branch, tag or commit)
+    /// </summary>
+    /// <example>main</example>
+    /// <example>HEAD~5</example>
+    [Required]
+    [Description("Start of the range - Examples: 'main', 'HEAD~5', 'v2.1.0'")]
+    public string BaseRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// End of the range; 'worktree' includes uncommitted changes
+    /// </summary>
+    /// <example>HEAD</example>
+    /// <example>worktree</example>
+    [Description("End of the range (default: HEAD). Use 'worktree' to include uncommitted changes")]
+    public string HeadRef { get; set; } = "HEAD";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Minimum confidence (0-1) for a removed/added pair to count as a rename
+    /// </summary>
+    [Range(0.3, 1.0)]
+    [Description("Minimum confidence (0.3-1.0) to report a rename (default: 0.6)")]
+    public double MinConfidence { get; set; } = 0.6;
+
+    /// <summary>
+    /// Also report symbols that kept their name but moved to another file or type
+    /// </summary>
+    [Description("Also report symbols moved to another file or type under the same name (default: true)")]
+    public bool IncludeMoves { get; set; } = true;
+
+    /// <summary>
+    /// Count references to old names still in the index
+    /// </summary>
+    [Description("Count references to old names still in the index, to catch missed call sites (default: true)")]
+    public bool CheckReferences { get; set; } = true;
+
+    /// <summary>
+    /// Maximum number of changed files to scan
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum changed files to scan (default: 200)")]
+    public int MaxFiles { get; set; } = 200;
+}
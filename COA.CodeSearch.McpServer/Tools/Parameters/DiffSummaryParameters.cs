@@ -0,0 +1,41 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the diff_summary tool - structural summary of the changes between two refs
+/// </summary>
+public class DiffSummaryParameters
+{
+    /// <summary>
+    /// Base ref the changes are compared against (branch, tag or commit)
+    /// </summary>
+    /// <example>main</example>
+    /// <example>HEAD~1</example>
+    [Required]
+    [Description("Base ref to compare against - Examples: 'main', 'HEAD~1', 'v2.1.0'")]
+    public string BaseRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Head ref with the changes; 'worktree' includes uncommitted changes
+    /// </summary>
+    /// <example>HEAD</example>
+    /// <example>worktree</example>
+    [Description("Head ref with the changes (default: HEAD). Use 'worktree' to summarize uncommitted changes")]
+    public string HeadRef { get; set; } = "HEAD";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Maximum number of changed files to scan for symbol changes
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum changed files to scan for symbol changes (default: 100)")]
+    public int MaxFiles { get; set; } = 100;
+}
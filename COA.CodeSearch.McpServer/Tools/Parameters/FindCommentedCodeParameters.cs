@@ -0,0 +1,56 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_commented_code tool - commented-out code blocks with the history of their disabling
+/// </summary>
+public class FindCommentedCodeParameters
+{
+    /// <summary>
+    /// Only scan files under this workspace-relative directory
+    /// </summary>
+    [Description("Only scan files under this workspace-relative directory, e.g. src/Orders (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Only scan files of this language
+    /// </summary>
+    [Description("Only scan files of this language, e.g. csharp or python (default: all)")]
+    public string? Language { get; set; }
+
+    /// <summary>
+    /// Fewest code lines a block needs to be reported
+    /// </summary>
+    [Range(1, 100)]
+    [Description("Fewest code lines a block needs to be reported (default: 5)")]
+    public int MinLines { get; set; } = 5;
+
+    /// <summary>
+    /// Blocks disabled longer ago than this are recommended for deletion
+    /// </summary>
+    [Range(1, 3650)]
+    [Description("Blocks disabled more than this many days ago are recommended for deletion (default: 180)")]
+    public int StaleDays { get; set; } = 180;
+
+    /// <summary>
+    /// Blame each block to find the commit that disabled it
+    /// </summary>
+    [Description("Blame each block to find when and by whom it was commented out (default: true)")]
+    public bool IncludeHistory { get; set; } = true;
+
+    /// <summary>
+    /// Maximum blocks to return
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum blocks to return (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
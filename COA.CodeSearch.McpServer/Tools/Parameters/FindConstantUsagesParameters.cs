@@ -0,0 +1,53 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_constant_usages tool - usages of a constant or enum member, by name and by raw value
+/// </summary>
+public class FindConstantUsagesParameters
+{
+    /// <summary>
+    /// Constant or enum member, optionally qualified by its enum or type
+    /// </summary>
+    /// <example>OrderStatus.Archived</example>
+    /// <example>MAX_RETRIES</example>
+    [Description("Constant or enum member, e.g. 'OrderStatus.Archived' or 'MAX_RETRIES'. Give name, value or both")]
+    public string? Name { get; set; }
+
+    /// <summary>
+    /// A value as written in code; finds constants with that value and raw literals equal to it
+    /// </summary>
+    /// <example>3</example>
+    /// <example>"archived"</example>
+    [Description("Value as written in code, e.g. 3, 0x03 or \"archived\" - finds constants holding it and literal uses of it")]
+    public string? Value { get; set; }
+
+    /// <summary>
+    /// Also report raw literals equal to the constant's value
+    /// </summary>
+    [Description("Also report raw literals equal to the value, e.g. status == 3 instead of OrderStatus.Archived (default: true)")]
+    public bool IncludeLiterals { get; set; } = true;
+
+    /// <summary>
+    /// Glob restricting the files whose usages are reported
+    /// </summary>
+    /// <example>src/**/*.cs</example>
+    [Description("Only report usages in files matching this glob, e.g. '*.cs' or 'src/**'")]
+    public string? FilePattern { get; set; }
+
+    /// <summary>
+    /// Maximum usages of each kind to return
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum references and maximum literals to return (default: 200)")]
+    public int MaxResults { get; set; } = 200;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
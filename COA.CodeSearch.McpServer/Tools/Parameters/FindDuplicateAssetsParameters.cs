@@ -0,0 +1,49 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_duplicate_assets tool - non-code files with identical content
+/// </summary>
+public class FindDuplicateAssetsParameters
+{
+    /// <summary>
+    /// Only report groups with a copy under this workspace-relative directory
+    /// </summary>
+    [Description("Only report duplicates with a copy under this workspace-relative directory (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Only report assets of this kind
+    /// </summary>
+    [Description("Only report assets of this kind: image, font, media, document, archive, binary or fixture (default: all)")]
+    public string? Kind { get; set; }
+
+    /// <summary>
+    /// Smallest asset size worth reporting
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("Ignore assets smaller than this many bytes (default: 1)")]
+    public int MinSizeBytes { get; set; } = 1;
+
+    /// <summary>
+    /// Rescan the workspace's assets before reporting
+    /// </summary>
+    [Description("Rescan and rehash assets first instead of using the inventory from the last index (default: false)")]
+    public bool Refresh { get; set; }
+
+    /// <summary>
+    /// Maximum groups to return
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum duplicate groups to return (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
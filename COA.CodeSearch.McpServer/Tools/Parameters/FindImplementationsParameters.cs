@@ -0,0 +1,45 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_implementations tool - Go types whose method sets satisfy an interface
+/// </summary>
+public class FindImplementationsParameters
+{
+    /// <summary>
+    /// Interface name, optionally qualified by its package
+    /// </summary>
+    /// <example>UserRepository</example>
+    /// <example>store.UserRepository</example>
+    [Required(ErrorMessage = "Interface is required")]
+    [Description("Go interface name, optionally package-qualified, e.g. 'UserRepository' or 'store.UserRepository'")]
+    public string Interface { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Also list types missing exactly one of the interface's methods
+    /// </summary>
+    [Description("Also list types that miss exactly one method - useful when a type was meant to implement the interface (default: true)")]
+    public bool IncludeNearMisses { get; set; } = true;
+
+    /// <summary>
+    /// Maximum number of implementations to return
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum implementations to return (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+}
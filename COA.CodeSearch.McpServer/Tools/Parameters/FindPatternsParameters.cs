@@ -70,6 +70,14 @@ public class FindPatternsParameters
     [Description("Custom regex patterns to detect (default: none)")]
     public List<string> CustomPatterns { get; set; } = new();
 
+    /// <summary>
+    /// Run analyzers contributed by plugins (default: true)
+    /// </summary>
+    /// <example>false</example>
+    [JsonPropertyName("includePluginAnalyzers")]
+    [Description("Run analyzers contributed by installed plugins (default: true)")]
+    public bool IncludePluginAnalyzers { get; set; } = true;
+
     /// <summary>
     /// Severity levels to include in results - filter by impact level for focused analysis (default: [Info, Warning, Error])
     /// </summary>
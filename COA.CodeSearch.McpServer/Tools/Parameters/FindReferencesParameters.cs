@@ -10,14 +10,24 @@ public class FindReferencesParameters
 {
     /// <summary>
     /// The symbol name to find all references for - CRITICAL for understanding impact before refactoring.
+    /// A position "path:line:column" (1-based line, 0-based column) resolves to the identifier there, and an anchor
+    /// ("anchor:path#Type.Member~fingerprint" from an earlier result) to the symbol it was taken from.
     /// </summary>
     /// <example>UpdateUser</example>
     /// <example>IUserService</example>
-    /// <example>UserController</example>
+    /// <example>src/Controllers/UserController.cs:28:12</example>
     [Required]
-    [Description("Symbol to find all references for (e.g., UpdateUser, IUserService, UserController)")]
+    [Description("Symbol to find all references for, a position 'path:line:column' or an anchor from an earlier result (e.g., UpdateUser, IUserService, src/Controllers/UserController.cs:28:12)")]
     public string Symbol { get; set; } = string.Empty;
 
+    /// <summary>
+    /// How the column of a position is counted: utf-16 code units as in LSP (default) or utf-8 bytes
+    /// </summary>
+    /// <example>utf-16</example>
+    /// <example>utf-8</example>
+    [Description("Column unit when Symbol is a position: 'utf-16' (LSP, default) or 'utf-8' (bytes)")]
+    public string ColumnEncoding { get; set; } = "utf-16";
+
     /// <summary>
     /// Path to the workspace directory to search. Can be absolute or relative path (default: current workspace)
     /// </summary>
@@ -73,6 +83,12 @@ public class FindReferencesParameters
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
 
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+
     /// <summary>
     /// Case sensitive search (default: false - case insensitive)
     /// </summary>
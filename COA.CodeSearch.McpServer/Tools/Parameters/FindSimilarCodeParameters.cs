@@ -0,0 +1,63 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_similar_code tool - functions elsewhere in the workspace that resemble a snippet or symbol
+/// </summary>
+public class FindSimilarCodeParameters
+{
+    /// <summary>
+    /// Code to compare against; either this or Symbol is required
+    /// </summary>
+    /// <example>foreach (var item in items) { if (seen.Add(item.Id)) result.Add(item); }</example>
+    [Description("Code snippet to compare against (either this or symbol is required)")]
+    public string? Snippet { get; set; }
+
+    /// <summary>
+    /// Name of an existing function or method to compare against
+    /// </summary>
+    /// <example>FormatCurrency</example>
+    [Description("Existing function or method whose body to compare against - Examples: 'FormatCurrency', 'parse_date'")]
+    public string? Symbol { get; set; }
+
+    /// <summary>
+    /// File containing the symbol, when the name is declared more than once
+    /// </summary>
+    [Description("File containing the symbol, to pick one of several declarations with the same name")]
+    public string? FilePath { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Maximum matches to return
+    /// </summary>
+    [Range(1, 50)]
+    [Description("Maximum matches (default: 10)")]
+    public int MaxResults { get; set; } = 10;
+
+    /// <summary>
+    /// Minimum similarity (0-1) for a match
+    /// </summary>
+    [Range(0.1, 1.0)]
+    [Description("Minimum similarity 0.1-1.0 (default: 0.35)")]
+    public double MinSimilarity { get; set; } = 0.35;
+
+    /// <summary>
+    /// auto (token vectors, plus embeddings when available), tokens, or embedding
+    /// </summary>
+    [Description("Comparison method: 'auto' (token vectors plus embeddings when available), 'tokens', 'embedding' (default: auto)")]
+    public string Method { get; set; } = "auto";
+
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+}
@@ -0,0 +1,39 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_tests tool - the tests, benchmarks and examples that exercise a symbol
+/// </summary>
+public class FindTestsParameters
+{
+    /// <summary>
+    /// Symbol name, optionally qualified by its type or package
+    /// </summary>
+    /// <example>UserService.GetUser</example>
+    /// <example>users.ParseID</example>
+    [Required(ErrorMessage = "Symbol is required")]
+    [Description("Symbol name, optionally qualified by its type or package, e.g. 'UserService.GetUser', 'users.ParseID' or 'GetUser'")]
+    public string Symbol { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Also list tests that reach the symbol through one of its direct callers
+    /// </summary>
+    [Description("Also list tests that call the symbol's direct callers - they break too when its behavior changes (default: true)")]
+    public bool IncludeCallers { get; set; } = true;
+
+    /// <summary>
+    /// Maximum number of tests to return
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum tests to return (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,42 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the find_unreferenced_assets tool - images, fonts, media and fixtures no indexed file names
+/// </summary>
+public class FindUnreferencedAssetsParameters
+{
+    /// <summary>
+    /// Only report assets under this workspace-relative directory
+    /// </summary>
+    [Description("Only report assets under this workspace-relative directory, e.g. public/img (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Only report assets of this kind
+    /// </summary>
+    [Description("Only report assets of this kind: image, font, media, document, archive, binary or fixture (default: all)")]
+    public string? Kind { get; set; }
+
+    /// <summary>
+    /// Rescan the workspace's assets before reporting
+    /// </summary>
+    [Description("Rescan assets and their references first instead of using the inventory from the last index (default: false)")]
+    public bool Refresh { get; set; }
+
+    /// <summary>
+    /// Maximum assets to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum assets to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
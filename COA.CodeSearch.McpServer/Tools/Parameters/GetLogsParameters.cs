@@ -0,0 +1,56 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the get_logs tool - retrieves recent server log entries for debugging
+/// </summary>
+public class GetLogsParameters
+{
+    /// <summary>
+    /// Minimum level to include: Verbose/Trace, Debug, Information, Warning, Error, Fatal/Critical (default: Information)
+    /// </summary>
+    /// <example>Warning</example>
+    /// <example>Debug</example>
+    [Description("Minimum level: 'Trace', 'Debug', 'Information' (default), 'Warning', 'Error', 'Critical'")]
+    public string? Level { get; set; } = "Information";
+
+    /// <summary>
+    /// Only include entries logged while serving this tool
+    /// </summary>
+    /// <example>text_search</example>
+    /// <example>symbol_search</example>
+    [Description("Only entries logged while serving this tool. Examples: 'text_search', 'symbol_search'")]
+    public string? ToolName { get; set; }
+
+    /// <summary>
+    /// Only include entries for a single request
+    /// </summary>
+    /// <example>3f9a1c0b7d2e</example>
+    [Description("Only entries for one request (correlation ID from a previous get_logs result)")]
+    public string? CorrelationId { get; set; }
+
+    /// <summary>
+    /// Case-insensitive text that must appear in the message or exception
+    /// </summary>
+    /// <example>timeout</example>
+    /// <example>write.lock</example>
+    [Description("Text that must appear in the message or exception (case-insensitive). Examples: 'timeout', 'write.lock'")]
+    public string? Contains { get; set; }
+
+    /// <summary>
+    /// Only include entries newer than this age, e.g. '15m', '2h', '1d'
+    /// </summary>
+    /// <example>15m</example>
+    /// <example>2h</example>
+    [Description("Only entries newer than this age. Examples: '15m', '2h', '1d'")]
+    public string? Since { get; set; }
+
+    /// <summary>
+    /// Maximum entries to return, newest first (default: 50)
+    /// </summary>
+    [Description("Maximum entries to return, newest first (default: 50)")]
+    [Range(1, 1000)]
+    public int MaxEntries { get; set; } = 50;
+}
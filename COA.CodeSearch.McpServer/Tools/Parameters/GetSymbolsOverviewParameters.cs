@@ -63,4 +63,10 @@ public class GetSymbolsOverviewParameters
     /// </summary>
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
+
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
 }
\ No newline at end of file
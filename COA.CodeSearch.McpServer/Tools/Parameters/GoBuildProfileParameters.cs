@@ -0,0 +1,53 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the go_build_profile tool - shows or sets the GOOS/GOARCH profile a workspace's Go code is read under
+/// </summary>
+public class GoBuildProfileParameters
+{
+    /// <summary>
+    /// Target operating system to set
+    /// </summary>
+    /// <example>linux</example>
+    /// <example>windows</example>
+    [Description("Set GOOS for this workspace, e.g. 'linux', 'windows', 'darwin'. Omit to keep the current one")]
+    public string? Goos { get; set; }
+
+    /// <summary>
+    /// Target architecture to set
+    /// </summary>
+    /// <example>amd64</example>
+    /// <example>arm64</example>
+    [Description("Set GOARCH for this workspace, e.g. 'amd64', 'arm64'. Omit to keep the current one")]
+    public string? Goarch { get; set; }
+
+    /// <summary>
+    /// Custom build tags to set, replacing the current ones
+    /// </summary>
+    /// <example>["integration"]</example>
+    [Description("Set custom build tags, as passed to go build -tags, e.g. ['integration', 'cgo']; replaces the current tags. [] clears them")]
+    public List<string>? Tags { get; set; }
+
+    /// <summary>
+    /// Go back to the configured or host default
+    /// </summary>
+    [Description("Remove this workspace's profile and go back to the configured or host default (default: false)")]
+    public bool Reset { get; set; } = false;
+
+    /// <summary>
+    /// Maximum constrained files to list
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum build-constrained files to list, excluded ones first (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
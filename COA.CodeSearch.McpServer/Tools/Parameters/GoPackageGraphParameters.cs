@@ -0,0 +1,62 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the go_package_graph tool - the package import graph of a workspace's Go modules
+/// </summary>
+public class GoPackageGraphParameters
+{
+    /// <summary>
+    /// Package to center the graph on: full import path, a path suffix or a workspace directory
+    /// </summary>
+    /// <example>internal/auth</example>
+    [Description("Package to center on: import path (github.com/acme/app/internal/auth), suffix (internal/auth) or directory (default: overview of all packages)")]
+    public string? Package { get; set; }
+
+    /// <summary>
+    /// importers (who imports the package), imports (what it imports) or both
+    /// </summary>
+    [Description("importers = packages importing it, imports = what it imports, both (default: both)")]
+    public string Direction { get; set; } = "both";
+
+    /// <summary>
+    /// How many import hops to follow from the package
+    /// </summary>
+    [Range(1, 20)]
+    [Description("Import hops to follow; 1 = direct only, higher = transitive (default: 1)")]
+    public int Depth { get; set; } = 1;
+
+    /// <summary>
+    /// Count imports from _test.go files
+    /// </summary>
+    [Description("Include imports made by _test.go files (default: false)")]
+    public bool IncludeTests { get; set; }
+
+    /// <summary>
+    /// List standard library imports too
+    /// </summary>
+    [Description("List standard library imports of the package (default: false)")]
+    public bool IncludeStd { get; set; }
+
+    /// <summary>
+    /// Skip files the workspace's GOOS/GOARCH profile excludes
+    /// </summary>
+    [Description("Ignore imports from files whose build constraints exclude them under the go_build_profile profile (default: false)")]
+    public bool BuildProfileOnly { get; set; }
+
+    /// <summary>
+    /// Maximum packages or edges to return
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum packages or import edges to return (default: 200)")]
+    public int MaxResults { get; set; } = 200;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -10,14 +10,25 @@ public class GoToDefinitionParameters
 {
     /// <summary>
     /// The symbol name to find the exact definition for - VERIFY BEFORE CODING to understand types and signatures.
+    /// A position "path:line:column" (1-based line, 0-based column) resolves to the identifier there, and an anchor
+    /// ("anchor:path#Type.Member~fingerprint" from an earlier result) to the symbol it was taken from. In Go, a
+    /// selector - "Admin.Name", or a position on admin.Name - resolves through embedded fields to the promoted member.
     /// </summary>
     /// <example>UserService</example>
     /// <example>FindByEmailAsync</example>
-    /// <example>IUserRepository</example>
+    /// <example>src/Services/UserService.cs:42:17</example>
     [Required]
-    [Description("The symbol name to find the exact definition for. Examples: 'UserService', 'FindByEmailAsync', 'IUserRepository'")]
+    [Description("The symbol name, a position 'path:line:column' of a usage, or an anchor from an earlier result. Examples: 'UserService', 'FindByEmailAsync', 'src/Services/UserService.cs:42:17'")]
     public string Symbol { get; set; } = string.Empty;
 
+    /// <summary>
+    /// How the column of a position is counted: utf-16 code units as in LSP (default) or utf-8 bytes
+    /// </summary>
+    /// <example>utf-16</example>
+    /// <example>utf-8</example>
+    [Description("Column unit when Symbol is a position: 'utf-16' (LSP, default) or 'utf-8' (bytes)")]
+    public string ColumnEncoding { get; set; } = "utf-16";
+
     /// <summary>
     /// Path to the workspace directory to search. Can be absolute or relative path (default: current workspace)
     /// </summary>
@@ -51,6 +62,12 @@ public class GoToDefinitionParameters
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
 
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+
     /// <summary>
     /// Case sensitive search (default: false - case insensitive)
     /// </summary>
@@ -0,0 +1,38 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the graphql_links tool - GraphQL schema types and fields linked to resolvers and models
+/// </summary>
+public class GraphQLLinksParameters
+{
+    /// <summary>
+    /// Type or field to show
+    /// </summary>
+    /// <example>User</example>
+    /// <example>Query.user</example>
+    [Description("Only this type, e.g. 'User', or field, e.g. 'Query.user'")]
+    public string? Type { get; set; }
+
+    /// <summary>
+    /// Return drift only, without the type listing
+    /// </summary>
+    [Description("Return only drift between schema and resolvers - fields nothing resolves and resolvers for fields the schema lacks (default: false)")]
+    public bool DriftOnly { get; set; } = false;
+
+    /// <summary>
+    /// Maximum types and drift entries to return, each
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum types and drift entries to return, each (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
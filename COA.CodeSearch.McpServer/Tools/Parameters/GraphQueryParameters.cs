@@ -0,0 +1,41 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the graph_query tool - chained navigation over symbols, calls, references and inheritance
+/// </summary>
+public class GraphQueryParameters
+{
+    /// <summary>
+    /// Steps separated by '|', evaluated left to right
+    /// </summary>
+    /// <example>symbol:Charge | callers | in:src/Billing | implements:IPaymentHandler</example>
+    [Required(ErrorMessage = "Query is required")]
+    [Description("Steps separated by '|', left to right. Start with a filter: symbol:<name or glob>, kind:<class,method,...>, in:<directory or path glob>, " +
+                 "language:<lang>, within:<containing type or namespace>, implements:<type> (the symbol or its containing type extends/implements it, directly or indirectly). " +
+                 "Traverse with callers, callees, references, members, parent, bases, implementations. Prefix a filter with ! to exclude, end with limit:N. " +
+                 "Example: symbol:Charge | callers | in:src/Billing | implements:IPaymentHandler")]
+    public string Query { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Match symbol names in symbol:, within: and implements: case-sensitively
+    /// </summary>
+    [Description("Case-sensitive name matching in symbol:, within: and implements: (default: false)")]
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Maximum number of symbols to return
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum symbols to return (default: 50)")]
+    public int MaxResults { get; set; } = 50;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,33 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the hover tool - type, signature and doc of the expression at a position
+/// </summary>
+public class HoverParameters
+{
+    /// <summary>
+    /// Position of an identifier as "path:line:column" (1-based line, 0-based column)
+    /// </summary>
+    /// <example>src/Services/UserService.cs:42:16</example>
+    [Required]
+    [Description("Position 'path:line:column' (1-based line, 0-based column) of an identifier (e.g., src/Services/UserService.cs:42:16)")]
+    public string Position { get; set; } = string.Empty;
+
+    /// <summary>
+    /// How the column is counted: utf-16 code units as in LSP (default) or utf-8 bytes
+    /// </summary>
+    /// <example>utf-16</example>
+    /// <example>utf-8</example>
+    [Description("Column unit: 'utf-16' (LSP, default) or 'utf-8' (bytes)")]
+    public string ColumnEncoding { get; set; } = "utf-16";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,43 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the k8s_manifests tool - Kubernetes manifests and Helm charts correlated with the code they deploy
+/// </summary>
+public class KubernetesManifestsParameters
+{
+    /// <summary>
+    /// Only objects whose kind, name, image or file path contains this text
+    /// </summary>
+    /// <example>orders</example>
+    [Description("Only objects whose kind, name, image or file path contains this text, e.g. 'orders' or 'CronJob' (default: all)")]
+    public string? Resource { get; set; }
+
+    /// <summary>
+    /// Only unread variables, ConfigMap and Secret references that do not resolve, and undefined or unused values
+    /// </summary>
+    [Description("Only unread variables, ConfigMap/Secret references that do not resolve, and undefined or unused Helm values (default: false)")]
+    public bool ProblemsOnly { get; set; }
+
+    /// <summary>
+    /// Cross-reference container environment variables against the code that reads them
+    /// </summary>
+    [Description("Cross-reference container environment variables against the code that reads them (default: true)")]
+    public bool IncludeVariables { get; set; } = true;
+
+    /// <summary>
+    /// Maximum containers, variables and references to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum containers, variables and references to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
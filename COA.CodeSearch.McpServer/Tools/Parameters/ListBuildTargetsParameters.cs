@@ -0,0 +1,50 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the list_build_targets tool - targets of Makefiles, MSBuild files, npm scripts and Taskfiles
+/// </summary>
+public class ListBuildTargetsParameters
+{
+    /// <summary>
+    /// Only targets whose name or description contains this text
+    /// </summary>
+    /// <example>test</example>
+    [Description("Only targets whose name or description contains this text, e.g. 'test' or 'lint' (default: all)")]
+    public string? Query { get; set; }
+
+    /// <summary>
+    /// Component directory: its build files, those below it, and targets above it that mention it
+    /// </summary>
+    /// <example>services/api</example>
+    [Description("Component directory: build files in and below it, plus targets in parent directories whose commands mention it (default: whole workspace)")]
+    public string? Directory { get; set; }
+
+    /// <summary>
+    /// Only one build system: make, msbuild, npm or task
+    /// </summary>
+    [Description("Only one build system: make, msbuild, npm or task (default: all)")]
+    public string? Tool { get; set; }
+
+    /// <summary>
+    /// Include the commands each target runs
+    /// </summary>
+    [Description("Include the commands each target runs (default: true)")]
+    public bool IncludeCommands { get; set; } = true;
+
+    /// <summary>
+    /// Maximum targets to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum targets to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,44 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the message_flows tool - message producers and consumers paired by topic, queue or event type
+/// </summary>
+public class MessageFlowsParameters
+{
+    /// <summary>
+    /// Channel filter: a topic, queue, subject or event type name, or part of one
+    /// </summary>
+    /// <example>orders</example>
+    [Description("Only channels whose name contains this text (case-insensitive), e.g. 'orders' or 'OrderPlaced' (default: all)")]
+    public string? Channel { get; set; }
+
+    /// <summary>
+    /// Brokers to include (default: all)
+    /// </summary>
+    /// <example>["kafka", "rabbitmq"]</example>
+    [Description("Brokers to include: kafka, rabbitmq, nats, redis, azure-service-bus, jms, masstransit, mediatr, nservicebus, event-bus, nestjs, events, pubsub (default: all)")]
+    public List<string>? Brokers { get; set; }
+
+    /// <summary>
+    /// Only report channels with producers but no consumers, or consumers but no producers
+    /// </summary>
+    [Description("Only channels nothing consumes or nothing publishes to (default: false)")]
+    public bool OrphansOnly { get; set; }
+
+    /// <summary>
+    /// Maximum channels to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum channels to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,44 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the migration_schema tool - the schema migration files build, their timeline, and the code using a table
+/// </summary>
+public class MigrationSchemaParameters
+{
+    /// <summary>
+    /// Table or view to show with its change history and the code using it (default: every table)
+    /// </summary>
+    /// <example>users</example>
+    [Description("Table or view to focus on: its columns, every migration that changed it, and the entities and SQL using it (default: all tables)")]
+    public string? Table { get; set; }
+
+    /// <summary>
+    /// Only migration sets whose directory contains this text
+    /// </summary>
+    /// <example>services/billing</example>
+    [Description("Only migration sets whose directory contains this text, e.g. 'billing/migrations' (default: all sets)")]
+    public string? Directory { get; set; }
+
+    /// <summary>
+    /// Include the ordered list of migrations with the changes each makes
+    /// </summary>
+    [Description("Include the ordered migration timeline with each migration's changes (default: false)")]
+    public bool IncludeTimeline { get; set; }
+
+    /// <summary>
+    /// Maximum tables, timeline entries and references to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum tables, timeline entries and references to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,45 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the openapi_links tool - OpenAPI operations and schemas linked to handlers and DTO types
+/// </summary>
+public class OpenApiLinksParameters
+{
+    /// <summary>
+    /// Operation to show: method and path, a path, or an operationId
+    /// </summary>
+    /// <example>GET /users/{id}</example>
+    /// <example>getUser</example>
+    [Description("Only this operation: 'GET /users/{id}', '/users/{id}' (any method) or an operationId such as 'getUser'")]
+    public string? Operation { get; set; }
+
+    /// <summary>
+    /// Schema to show
+    /// </summary>
+    /// <example>User</example>
+    [Description("Only this schema (components/schemas or definitions), e.g. 'User'")]
+    public string? Schema { get; set; }
+
+    /// <summary>
+    /// Return drift only, without the operation and schema listings
+    /// </summary>
+    [Description("Return only drift between spec and code - unlinked operations and schemas, properties missing from or extra in DTO types (default: false)")]
+    public bool DriftOnly { get; set; } = false;
+
+    /// <summary>
+    /// Maximum operations, schemas and drift entries to return, each
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum operations, schemas and drift entries to return, each (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
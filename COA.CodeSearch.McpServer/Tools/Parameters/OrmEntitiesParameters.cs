@@ -0,0 +1,38 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the orm_entities tool - code entities mapped to database tables and columns
+/// </summary>
+public class OrmEntitiesParameters
+{
+    /// <summary>
+    /// Entity type name to show
+    /// </summary>
+    /// <example>User</example>
+    [Description("Only the entity with this type name, e.g. 'User'")]
+    public string? Entity { get; set; }
+
+    /// <summary>
+    /// Table name to show
+    /// </summary>
+    /// <example>users</example>
+    [Description("Only entities mapped to this table, e.g. 'users'")]
+    public string? Table { get; set; }
+
+    /// <summary>
+    /// Maximum entities to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum entities to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,36 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the purge_index tool - removes workspace indexes to reclaim disk space
+/// </summary>
+public class PurgeIndexParameters
+{
+    /// <summary>
+    /// Workspace whose index should be removed or pruned when Scope is 'workspace' or 'budget' (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    /// <example>/home/user/projects/web-app</example>
+    [Description("Workspace path for scope 'workspace' or 'budget'. Default: current workspace - Examples: 'C:\\source\\MyProject', '/home/user/projects/web-app'")]
+    public string? WorkspacePath { get; set; } = null;
+
+    /// <summary>
+    /// What to purge: 'workspace' (one index), 'stale' (apply the retention policy), 'all' (every index),
+    /// or 'budget' (prune files from one index until it fits its size budget)
+    /// </summary>
+    /// <example>workspace</example>
+    /// <example>stale</example>
+    /// <example>all</example>
+    /// <example>budget</example>
+    [Description("What to purge: 'workspace' (default), 'stale' (apply retention policy - LRU eviction), 'all', or 'budget' (prune files to fit MaxWorkspaceSizeMB and list pruned files)")]
+    public string Scope { get; set; } = "workspace";
+
+    /// <summary>
+    /// Report what would be removed without deleting anything
+    /// </summary>
+    /// <example>true</example>
+    /// <example>false</example>
+    [Description("Preview what would be removed without deleting anything (default: false)")]
+    public bool DryRun { get; set; } = false;
+}
@@ -0,0 +1,30 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the query_help tool - text_search syntax with examples run against the workspace
+/// </summary>
+public class QueryHelpParameters
+{
+    /// <summary>
+    /// Only describe one topic: auto, exact, fuzzy, regex, operators or semantic
+    /// </summary>
+    /// <example>regex</example>
+    /// <example>operators</example>
+    [Description("Only one topic: 'auto', 'exact', 'fuzzy', 'regex', 'operators', 'semantic' (default: all)")]
+    public string? Topic { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Execute the examples against the index and report hit counts
+    /// </summary>
+    [Description("Run the examples against the index and report hit counts (default: true)")]
+    public bool ValidateExamples { get; set; } = true;
+}
@@ -0,0 +1,55 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the review_context tool - builds a review packet for the changes between two refs
+/// </summary>
+public class ReviewContextParameters
+{
+    /// <summary>
+    /// Base ref the changes are compared against (branch, tag or commit)
+    /// </summary>
+    /// <example>main</example>
+    /// <example>origin/develop</example>
+    [Required]
+    [Description("Base ref to compare against - Examples: 'main', 'origin/develop', 'v2.1.0'")]
+    public string BaseRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Head ref with the changes; 'worktree' includes uncommitted changes
+    /// </summary>
+    /// <example>HEAD</example>
+    /// <example>feature/login</example>
+    /// <example>worktree</example>
+    [Description("Head ref with the changes (default: HEAD). Use 'worktree' to include uncommitted changes")]
+    public string HeadRef { get; set; } = "HEAD";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Maximum callers reported per changed symbol
+    /// </summary>
+    [Range(1, 100)]
+    [Description("Maximum callers outside the diff per changed symbol (default: 10)")]
+    public int MaxCallersPerSymbol { get; set; } = 10;
+
+    /// <summary>
+    /// Maximum number of changed files to analyze
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum changed files to analyze (default: 50)")]
+    public int MaxFiles { get; set; } = 50;
+
+    /// <summary>
+    /// Include TODO/FIXME notes added in the diff and documentation mentioning changed symbols
+    /// </summary>
+    [Description("Include TODO/FIXME notes from the diff and docs mentioning changed symbols (default: true)")]
+    public bool IncludeNotes { get; set; } = true;
+}
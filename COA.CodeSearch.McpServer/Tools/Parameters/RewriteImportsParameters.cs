@@ -0,0 +1,71 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the rewrite_imports tool - rewrites an import path or namespace prefix across the workspace
+/// </summary>
+public class RewriteImportsParameters
+{
+    /// <summary>
+    /// Import path or namespace prefix to rewrite
+    /// </summary>
+    /// <example>github.com/org/old</example>
+    [Required]
+    [Description("Import path or namespace prefix to rewrite, matched on whole segments: github.com/org/old, @org/old, Acme.Old, com.acme.old")]
+    public string From { get; set; } = string.Empty;
+
+    /// <summary>
+    /// What the prefix becomes
+    /// </summary>
+    /// <example>github.com/org/new</example>
+    [Required]
+    [Description("What the prefix becomes: github.com/org/new, @org/new, Acme.Core, com.acme.core")]
+    public string To { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Show the changes without writing them
+    /// </summary>
+    [Description("Show the rewritten lines and diffs without writing anything (default: true)")]
+    public bool Preview { get; set; } = true;
+
+    /// <summary>
+    /// Add the old package name as an alias to Go imports whose last element changes
+    /// </summary>
+    [Description("Go: alias unaliased imports whose package name would change with the old name, so code using it still builds (default: true)")]
+    public bool KeepGoPackageNames { get; set; } = true;
+
+    /// <summary>
+    /// Workspace-relative directory to limit the rewrite to
+    /// </summary>
+    [Description("Only rewrite files under this workspace-relative directory (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Write even when files changed on disk since they were read
+    /// </summary>
+    [Description("Overwrite files edited on disk between reading and writing (default: false)")]
+    public bool Force { get; set; }
+
+    /// <summary>
+    /// Lines of context around each change in the diffs
+    /// </summary>
+    [Range(0, 20)]
+    [Description("Context lines in the diffs (default: 1)")]
+    public int ContextLines { get; set; } = 1;
+
+    /// <summary>
+    /// Maximum number of files to rewrite
+    /// </summary>
+    [Range(1, 5000)]
+    [Description("Maximum files to rewrite; nothing is written when more would change (default: 500)")]
+    public int MaxFiles { get; set; } = 500;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
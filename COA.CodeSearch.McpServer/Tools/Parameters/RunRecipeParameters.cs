@@ -0,0 +1,52 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the run_recipe tool - previews, applies or rolls back a multi-step refactoring recipe
+/// </summary>
+public class RunRecipeParameters
+{
+    /// <summary>
+    /// Recipe JSON: {"name": "...", "steps": [{"op": "search", ...}, ...]}
+    /// </summary>
+    /// <example>{"name":"rename service","steps":[{"op":"search","pattern":"UserService","filePattern":"*.cs"},{"op":"rename","oldName":"UserService","newName":"AccountService"},{"op":"organize_imports"},{"op":"verify_build"}]}</example>
+    [Description("Recipe as JSON: {\"name\", \"steps\": [...]}. Ops: search (pattern, filePattern, isRegex, caseSensitive - later steps use its matches), " +
+                 "replace (pattern, replacement), rename (oldName, newName - whole identifiers), edit (filePath, operation write/insert/replace/delete, startLine, endLine, content), " +
+                 "organize_imports (C# usings of changed files, or filePath), verify_build (last step; runs the build after apply and rolls back on failure). " +
+                 "Required for preview and apply")]
+    public string? Recipe { get; set; }
+
+    /// <summary>
+    /// preview (default), apply or rollback
+    /// </summary>
+    [Description("preview (show every change, write nothing), apply (write all files or none, then verify_build), rollback (restore the files of an applied run) (default: preview)")]
+    public string Mode { get; set; } = "preview";
+
+    /// <summary>
+    /// Run to roll back (default: the latest applied run in the workspace)
+    /// </summary>
+    [Description("Run id returned by apply - for rollback (default: latest run of the workspace)")]
+    public string? RunId { get; set; }
+
+    /// <summary>
+    /// Apply or roll back even when files changed on disk in the meantime
+    /// </summary>
+    [Description("Overwrite files edited on disk since the recipe read them (apply) or wrote them (rollback) (default: false)")]
+    public bool Force { get; set; }
+
+    /// <summary>
+    /// Lines of context around each change in the diffs
+    /// </summary>
+    [Range(0, 20)]
+    [Description("Context lines in the diffs (default: 3)")]
+    public int ContextLines { get; set; } = 3;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,30 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the scratch_diff tool - staged files compared with the real workspace
+/// </summary>
+public class ScratchDiffParameters
+{
+    /// <summary>
+    /// Limit the diff to these files (default: every staged file)
+    /// </summary>
+    [Description("Only these staged files (default: all)")]
+    public List<string>? FilePaths { get; set; }
+
+    /// <summary>
+    /// Unchanged lines around each change
+    /// </summary>
+    [Range(0, 20)]
+    [Description("Unchanged lines around each change (default: 3)")]
+    public int ContextLines { get; set; } = 3;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,45 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the search_scratch tool - line search over staged content
+/// </summary>
+public class SearchScratchParameters
+{
+    /// <summary>
+    /// Text or regular expression to find
+    /// </summary>
+    /// <example>TODO</example>
+    /// <example>FormatCurrency\(</example>
+    [Required]
+    [Description("Text to find in staged files - Examples: 'TODO', 'FormatCurrency('")]
+    public string Pattern { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Treat the pattern as a regular expression
+    /// </summary>
+    [Description("Treat pattern as a regular expression (default: false)")]
+    public bool Regex { get; set; }
+
+    /// <summary>
+    /// Match case
+    /// </summary>
+    [Description("Case sensitive (default: false)")]
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Maximum matching lines
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum matching lines (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
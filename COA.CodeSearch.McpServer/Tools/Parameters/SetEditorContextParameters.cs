@@ -0,0 +1,93 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the set_editor_context tool - reports the user's open file, cursor and selection
+/// </summary>
+public class SetEditorContextParameters
+{
+    /// <summary>
+    /// File open in the editor, absolute or relative to the workspace
+    /// </summary>
+    /// <example>src/Services/UserService.cs</example>
+    [Description("File open in the editor, absolute or workspace-relative - required unless clear is true")]
+    public string? FilePath { get; set; }
+
+    /// <summary>
+    /// Cursor line (1-based)
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("Cursor line, 1-based")]
+    public int? Line { get; set; }
+
+    /// <summary>
+    /// Cursor column (0-based) in ColumnEncoding units
+    /// </summary>
+    [Range(0, int.MaxValue)]
+    [Description("Cursor column, 0-based as in LSP (default: 0)")]
+    public int? Column { get; set; }
+
+    /// <summary>
+    /// First line of the selection (1-based)
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("First selected line, 1-based")]
+    public int? SelectionStartLine { get; set; }
+
+    /// <summary>
+    /// Column the selection starts at (0-based)
+    /// </summary>
+    [Range(0, int.MaxValue)]
+    [Description("Column the selection starts at, 0-based (default: 0)")]
+    public int? SelectionStartColumn { get; set; }
+
+    /// <summary>
+    /// Last line of the selection (default: SelectionStartLine)
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("Last selected line (default: selectionStartLine)")]
+    public int? SelectionEndLine { get; set; }
+
+    /// <summary>
+    /// Column the selection ends before (0-based, exclusive)
+    /// </summary>
+    [Range(0, int.MaxValue)]
+    [Description("Column the selection ends before, 0-based and exclusive")]
+    public int? SelectionEndColumn { get; set; }
+
+    /// <summary>
+    /// Selected text; a selected name becomes the symbol of symbol tools
+    /// </summary>
+    /// <example>GetUserAsync</example>
+    [Description("Selected text - a selected name (GetUserAsync, UserService.GetUserAsync) is used as the symbol")]
+    public string? SelectedText { get; set; }
+
+    /// <summary>
+    /// Name of the symbol under the cursor, when the client knows it
+    /// </summary>
+    /// <example>UserService</example>
+    [Description("Symbol under the cursor if the client knows it (e.g. from its language server), for tools that take names only")]
+    public string? SymbolAtCursor { get; set; }
+
+    /// <summary>
+    /// How columns are counted: utf-16 code units as in LSP (default) or utf-8 bytes
+    /// </summary>
+    /// <example>utf-16</example>
+    [Description("Column unit: 'utf-16' (LSP, default) or 'utf-8' (bytes)")]
+    public string ColumnEncoding { get; set; } = "utf-16";
+
+    /// <summary>
+    /// Forget the editor context instead of setting it
+    /// </summary>
+    [Description("Forget the editor context, e.g. when the editor was closed (default: false)")]
+    public bool Clear { get; set; } = false;
+
+    /// <summary>
+    /// Path to the workspace directory the file belongs to. Defaults to current workspace if not specified.
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,27 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the set_log_level tool - changes logging verbosity without restarting the server
+/// </summary>
+public class SetLogLevelParameters
+{
+    /// <summary>
+    /// New minimum level: Verbose/Trace, Debug, Information, Warning, Error, Fatal/Critical
+    /// </summary>
+    /// <example>Debug</example>
+    /// <example>Information</example>
+    [Required]
+    [Description("New minimum level: 'Trace', 'Debug', 'Information', 'Warning', 'Error', 'Critical'")]
+    public string Level { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Apply only to CodeSearch's own loggers, leaving framework and library logging unchanged (default: true)
+    /// </summary>
+    /// <example>true</example>
+    /// <example>false</example>
+    [Description("Apply only to CodeSearch loggers, not framework/library logging (default: true)")]
+    public bool CodeSearchOnly { get; set; } = true;
+}
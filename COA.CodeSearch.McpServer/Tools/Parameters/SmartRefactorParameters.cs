@@ -10,14 +10,17 @@ public class SmartRefactorParameters
 {
     /// <summary>
     /// The refactoring operation to perform.
-    /// Valid operations: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface
+    /// Valid operations: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface, fix_namespaces, fix_naming, rename_module
     /// </summary>
-    [Description("The refactoring operation to perform: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface")]
+    [Description("The refactoring operation to perform: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface, fix_namespaces, fix_naming, rename_module")]
     public required string Operation { get; set; }
 
     /// <summary>
     /// Operation-specific parameters as JSON (default: {} - empty object)
     /// For rename_symbol: {\"old_name\": \"UserService\", \"new_name\": \"AccountService\"}
+    /// For fix_namespaces: {\"file_path\": \"src/Api/Orders/OrderService.cs\", \"language\": \"csharp\"} - both optional
+    /// For fix_naming: {\"rule\": \"go-exported-names\", \"file_path\": \"pkg/api/server.go\", \"language\": \"go\"} - all optional
+    /// For rename_module: {\"old_name\": \"github.com/acme/old\", \"new_name\": \"github.com/acme/new\"} - a Go module path or C# root namespace
     /// </summary>
     [Description("Operation-specific parameters as JSON string (default: {} - empty object)")]
     public string Params { get; set; } = "{}";
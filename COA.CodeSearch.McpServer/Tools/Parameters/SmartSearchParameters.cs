@@ -0,0 +1,39 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the smart_search tool - plans and runs index queries for a natural-language request
+/// </summary>
+public class SmartSearchParameters
+{
+    /// <summary>
+    /// What to find, in plain language
+    /// </summary>
+    /// <example>find where users are deactivated and cached</example>
+    /// <example>retry logic for failed payments</example>
+    [Required]
+    [Description("What to find, in plain language - Examples: 'find where users are deactivated and cached', 'retry logic for failed payments'")]
+    public string Request { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Maximum merged results
+    /// </summary>
+    [Range(1, 100)]
+    [Description("Maximum merged results (default: 20)")]
+    public int MaxResults { get; set; } = 20;
+
+    /// <summary>
+    /// Also match common programming synonyms (deactivate → disable, suspend)
+    /// </summary>
+    [Description("Also match programming synonyms, e.g. deactivate → disable/suspend (default: true)")]
+    public bool ExpandSynonyms { get; set; } = true;
+}
@@ -0,0 +1,61 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the snapshot_diff tool - compares two index snapshots or two git refs at the symbol level
+/// </summary>
+public class SnapshotDiffParameters
+{
+    /// <summary>
+    /// Older side: a snapshot name ('current' for the live index), or a git ref when mode is 'git'.
+    /// Leave empty to only save and/or list snapshots.
+    /// </summary>
+    /// <example>before-refactor</example>
+    /// <example>v1.0.0</example>
+    [Description("Older side: snapshot name, 'current' for the live index, or a git ref when mode='git'. Empty lists saved snapshots")]
+    public string? From { get; set; }
+
+    /// <summary>
+    /// Newer side: a snapshot name, 'current' (default), or a git ref when mode is 'git'
+    /// </summary>
+    /// <example>current</example>
+    /// <example>HEAD</example>
+    [Description("Newer side: snapshot name or 'current' (default), or a git ref when mode='git'")]
+    public string To { get; set; } = "current";
+
+    /// <summary>
+    /// 'snapshot' compares saved index snapshots; 'git' compares two refs without touching the index
+    /// </summary>
+    [Description("'snapshot' (default) compares saved index snapshots; 'git' compares two refs")]
+    public string Mode { get; set; } = "snapshot";
+
+    /// <summary>
+    /// Save the current index as a snapshot with this name before comparing
+    /// </summary>
+    /// <example>before-refactor</example>
+    [Description("Save the current index as a snapshot with this name first - letters, digits, '.', '_' and '-'")]
+    public string? SaveAs { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Maximum symbol changes returned
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum symbol changes returned (default: 200)")]
+    public int MaxSymbolChanges { get; set; } = 200;
+
+    /// <summary>
+    /// Maximum changed files read in git mode
+    /// </summary>
+    [Range(1, 2000)]
+    [Description("Maximum changed files scanned for symbols in git mode (default: 300)")]
+    public int MaxFiles { get; set; } = 300;
+}
@@ -0,0 +1,58 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the stage_scratch tool - stages generated files and snippets without touching the workspace
+/// </summary>
+public class StageScratchParameters
+{
+    /// <summary>
+    /// What to do with the scratch area
+    /// </summary>
+    /// <example>write</example>
+    /// <example>insert</example>
+    [Description("write (whole file), append, insert (before startLine), replace (startLine-endLine), delete (stage file deletion), unstage (drop one file), clear (drop all), list (default: write)")]
+    public string Operation { get; set; } = "write";
+
+    /// <summary>
+    /// File to stage, absolute or relative to the workspace
+    /// </summary>
+    /// <example>src/Services/InvoiceFormatter.cs</example>
+    [Description("File to stage, absolute or workspace-relative - required except for clear and list")]
+    public string? FilePath { get; set; }
+
+    /// <summary>
+    /// File content (write) or snippet (append, insert, replace)
+    /// </summary>
+    [Description("Whole file content for write, or the snippet for append/insert/replace")]
+    public string? Content { get; set; }
+
+    /// <summary>
+    /// First line for insert and replace (1-based), against the staged version if the file is already staged
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("1-based line for insert/replace, counted in the staged version when the file is already staged")]
+    public int? StartLine { get; set; }
+
+    /// <summary>
+    /// Last line for replace (default: StartLine)
+    /// </summary>
+    [Range(1, int.MaxValue)]
+    [Description("Last line for replace (default: startLine)")]
+    public int? EndLine { get; set; }
+
+    /// <summary>
+    /// Why the file is staged, shown by scratch_diff
+    /// </summary>
+    [Description("Optional note on why the file is staged, shown by scratch_diff and commit_scratch")]
+    public string? Note { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
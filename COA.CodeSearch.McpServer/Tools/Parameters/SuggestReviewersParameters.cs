@@ -0,0 +1,54 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the suggest_reviewers tool - proposes reviewers for the changes between two refs
+/// </summary>
+public class SuggestReviewersParameters
+{
+    /// <summary>
+    /// Base ref the changes are compared against (branch, tag or commit)
+    /// </summary>
+    /// <example>main</example>
+    /// <example>origin/develop</example>
+    [Required]
+    [Description("Base ref to compare against - Examples: 'main', 'origin/develop'")]
+    public string BaseRef { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Head ref with the changes; 'worktree' includes uncommitted changes
+    /// </summary>
+    /// <example>HEAD</example>
+    /// <example>worktree</example>
+    [Description("Head ref with the changes (default: HEAD). Use 'worktree' to include uncommitted changes")]
+    public string HeadRef { get; set; } = "HEAD";
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+
+    /// <summary>
+    /// Number of reviewers to suggest
+    /// </summary>
+    [Range(1, 10)]
+    [Description("Number of reviewers to suggest (default: 3)")]
+    public int MaxReviewers { get; set; } = 3;
+
+    /// <summary>
+    /// People who must not be suggested (handles, emails or names). Authors of the head commits are always excluded.
+    /// </summary>
+    /// <example>["@jane", "bob@example.com"]</example>
+    [Description("Handles, emails or names to exclude, e.g. people who are away. Authors of the change are excluded automatically")]
+    public List<string>? ExcludeReviewers { get; set; }
+
+    /// <summary>
+    /// Maximum number of changed files to analyze
+    /// </summary>
+    [Range(1, 500)]
+    [Description("Maximum changed files to analyze (default: 50)")]
+    public int MaxFiles { get; set; } = 50;
+}
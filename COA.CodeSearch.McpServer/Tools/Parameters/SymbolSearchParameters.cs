@@ -36,6 +36,14 @@ public class SymbolSearchParameters
     [Description("Filter by symbol type (default: all types. Examples: class, interface, method, function, property)")]
     public string? SymbolType { get; set; }
 
+    /// <summary>
+    /// Optional: Only members of this type - the enclosing class, or for Go methods the receiver type, pointer or
+    /// value. A qualified symbol such as UserService.GetUser or (*UserService).GetUser sets it too.
+    /// </summary>
+    /// <example>UserService</example>
+    [Description("Filter methods by containing type - the enclosing class or Go receiver type (e.g., UserService). Also set by qualified symbols like UserService.GetUser")]
+    public string? ContainingType { get; set; }
+
     /// <summary>
     /// Include usage count for each symbol showing how many references exist across the codebase. Useful for understanding symbol popularity and impact (default: false)
     /// </summary>
@@ -64,9 +72,22 @@ public class SymbolSearchParameters
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
 
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+
     /// <summary>
     /// Case sensitive search (default: false - case insensitive)
     /// </summary>
     [Description("Case sensitive search (default: false - case insensitive)")]
     public bool CaseSensitive { get; set; } = false;
+
+    /// <summary>
+    /// Go: leave out symbols from files whose build constraints exclude the workspace's GOOS/GOARCH profile
+    /// (default: false - they are returned with inBuild false)
+    /// </summary>
+    [Description("Go: leave out symbols from files whose build constraints (//go:build, _linux.go) exclude the workspace's GOOS/GOARCH profile set with go_build_profile (default: false - they are annotated instead)")]
+    public bool BuildProfileOnly { get; set; } = false;
 }
\ No newline at end of file
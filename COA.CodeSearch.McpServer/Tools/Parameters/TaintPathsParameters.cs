@@ -0,0 +1,60 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the taint_paths tool - shortest call paths from source symbols to sink symbols or calls
+/// </summary>
+public class TaintPathsParameters
+{
+    /// <summary>
+    /// Graph query selecting where paths start; types stand for their methods
+    /// </summary>
+    /// <example>in:src/Controllers | kind:method</example>
+    /// <example>implements:IHttpHandler</example>
+    [Required(ErrorMessage = "Sources is required")]
+    [Description("graph_query selecting where paths start, e.g. 'in:src/Controllers | kind:method' or 'implements:ControllerBase' (a type stands for its methods)")]
+    public string Sources { get; set; } = string.Empty;
+
+    /// <summary>
+    /// Graph query selecting indexed sink symbols
+    /// </summary>
+    /// <example>symbol:ExecuteRaw*</example>
+    [Description("graph_query selecting sink symbols in the index, e.g. 'symbol:ExecuteRaw* | within:SqlHelper'")]
+    public string? Sinks { get; set; }
+
+    /// <summary>
+    /// Calls to code outside the index that count as sinks, as qualifier.name (* for any qualifier)
+    /// </summary>
+    /// <example>["exec.Command", "*.ExecuteSqlRaw", "os.system"]</example>
+    [Description("Calls outside the index that count as sinks, e.g. [\"exec.Command\", \"*.ExecuteSqlRaw\", \"subprocess.run\"]; * matches any qualifier or name part")]
+    public List<string>? SinkCalls { get; set; }
+
+    /// <summary>
+    /// Longest path to look for, in calls
+    /// </summary>
+    [Range(1, 12)]
+    [Description("Longest path in calls (default: 6)")]
+    public int MaxDepth { get; set; } = 6;
+
+    /// <summary>
+    /// Maximum number of paths to return
+    /// </summary>
+    [Range(1, 200)]
+    [Description("Maximum paths to return, shortest first (default: 20)")]
+    public int MaxPaths { get; set; } = 20;
+
+    /// <summary>
+    /// Match symbol names in the queries and sink calls case-sensitively
+    /// </summary>
+    [Description("Case-sensitive name matching (default: false)")]
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
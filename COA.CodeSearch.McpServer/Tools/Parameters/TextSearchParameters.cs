@@ -52,6 +52,12 @@ public class TextSearchParameters
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
 
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+
     /// <summary>
     /// Search mode controls how queries are matched (default: auto - smart detection).
     /// - 'auto': Automatically detect best approach (symbol/pattern/standard routing)
@@ -59,19 +65,55 @@ public class TextSearchParameters
     /// - 'fuzzy': Typo-tolerant search (handles spelling variations)
     /// - 'semantic': Vector similarity search using embeddings (cross-language concept matching)
     /// - 'regex': Regular expression pattern matching (full regex syntax)
+    /// - 'sample': Search a random subset of files and extrapolate how many files match overall
+    /// - 'tag': Match Go struct field tags with tag:KEY=VALUE queries (also used in auto mode for queries starting with tag:)
     /// </summary>
     /// <example>auto</example>
     /// <example>exact</example>
     /// <example>fuzzy</example>
     /// <example>semantic</example>
     /// <example>regex</example>
-    [Description("Search mode: 'auto' (default - smart detection), 'exact' (literal), 'fuzzy' (typo-tolerant), 'semantic' (embeddings), 'regex' (patterns)")]
+    /// <example>sample</example>
+    /// <example>tag</example>
+    [Description("Search mode: 'auto' (default - smart detection), 'exact' (literal), 'fuzzy' (typo-tolerant), 'semantic' (embeddings), 'regex' (patterns), 'sample' (estimate how widespread a pattern is from a random subset of files), 'tag' (Go struct tags: tag:json=user_name, tag:column=is_active), 'config' (JSON/YAML key paths: path:server.timeout, path:services.*.image=nginx*)")]
     public string SearchMode { get; set; } = "auto";
 
+    /// <summary>
+    /// Fraction of files searched in 'sample' mode (default: 0.1)
+    /// </summary>
+    [Description("Fraction of files searched in 'sample' mode, 0.001-1 (default: 0.1)")]
+    [Range(0.001, 1.0)]
+    public double SampleRate { get; set; } = 0.1;
+
 
     /// <summary>
     /// Case sensitive search (default: false - case insensitive)
     /// </summary>
     [Description("Case sensitive search (default: false - case insensitive)")]
     public bool CaseSensitive { get; set; } = false;
+
+    /// <summary>
+    /// Run a query the server estimates as expensive (leading-wildcard or unanchored regex over a large index)
+    /// in full, skipping the configured warn/confirm/sample guardrail.
+    /// </summary>
+    [Description("Run a query estimated as expensive (e.g. '.*' regex over a large index) in full instead of being refused or sampled (default: false)")]
+    public bool ConfirmExpensive { get; set; } = false;
+
+    /// <summary>
+    /// Ask the client's LLM (MCP sampling) to re-rank and filter the top hits. Useful for ambiguous
+    /// natural-language queries; the raw Lucene order is returned alongside.
+    /// </summary>
+    [Description("Re-rank top hits with the client's LLM via MCP sampling for ambiguous natural-language queries; raw order is returned too (default: false)")]
+    public bool Rerank { get; set; } = false;
+
+    /// <summary>
+    /// How hit context lines are rendered:
+    /// - 'plain': raw source text
+    /// - 'ansi': context lines carry ANSI colour escapes for terminal display
+    /// - 'classified': context lines stay plain and each hit adds classifiedLines, per-line segments tagged
+    ///   keyword, type, function, identifier, string, number, comment, operator, punctuation or text
+    /// </summary>
+    /// <example>classified</example>
+    [Description("Snippet rendering: 'plain' (default), 'ansi' (terminal colour escapes), 'classified' (per-line token segments for client-side highlighting)")]
+    public string SnippetFormat { get; set; } = "plain";
 }
@@ -79,6 +79,12 @@ public class TraceCallPathParameters
     [Description("Disable caching for this request (default: false - caching enabled)")]
     public bool NoCache { get; set; } = false;
 
+    /// <summary>
+    /// Answer from the index as it is, without first indexing files edited moments ago (default: false)
+    /// </summary>
+    [Description("Skip indexing recently edited files before searching, for speed; their edits show up once indexed (default: false - edits made through this server are always reflected)")]
+    public bool SkipPendingEdits { get; set; } = false;
+
     /// <summary>
     /// Path to the workspace directory to search. Can be absolute or relative path (default: current workspace)
     /// </summary>
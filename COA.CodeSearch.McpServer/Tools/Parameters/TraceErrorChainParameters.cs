@@ -0,0 +1,47 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the trace_error_chain tool - how a Go error is wrapped and passed up to the checks that look for it
+/// </summary>
+public class TraceErrorChainParameters
+{
+    /// <summary>
+    /// The error to trace: a sentinel, an error type or the "path:line" of an errors.New/fmt.Errorf/composite literal
+    /// </summary>
+    /// <example>ErrNotFound</example>
+    /// <example>users.ValidationError</example>
+    /// <example>internal/users/store.go:42</example>
+    [Required]
+    [Description("Sentinel (ErrNotFound, users.ErrNotFound), error type (ValidationError) or construction site 'path:line' of an errors.New/fmt.Errorf")]
+    public string Error { get; set; } = string.Empty;
+
+    /// <summary>
+    /// How many caller levels to follow from the functions producing the error
+    /// </summary>
+    [Range(1, 20)]
+    [Description("Caller levels to follow up from the producing functions (default: 5)")]
+    public int MaxDepth { get; set; } = 5;
+
+    /// <summary>
+    /// Follow the error into _test.go files too; their checks are listed either way
+    /// </summary>
+    [Description("Follow the error into _test.go callers as well (default: false; checks in tests are always listed)")]
+    public bool IncludeTests { get; set; } = false;
+
+    /// <summary>
+    /// Maximum number of hops and checks to return
+    /// </summary>
+    [Range(1, 1000)]
+    [Description("Maximum hops and checks to return (default: 100)")]
+    public int MaxResults { get; set; } = 100;
+
+    /// <summary>
+    /// Path to the workspace directory to analyze. Defaults to current workspace if not specified.
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,72 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the vendored_provenance tool - finds where vendored and copied code came from and whether it
+/// was modified locally
+/// </summary>
+public class VendoredProvenanceParameters
+{
+    /// <summary>
+    /// Workspace-relative directory to limit the check to
+    /// </summary>
+    /// <example>vendor</example>
+    /// <example>third_party/yaml</example>
+    [Description("Only check files under this workspace-relative directory (default: whole workspace)")]
+    public string? Path { get; set; }
+
+    /// <summary>
+    /// Compare copies with their upstream files
+    /// </summary>
+    [Description("Compare each copy with its upstream version to flag local modifications; false only lists origins (default: true)")]
+    public bool CheckModifications { get; set; } = true;
+
+    /// <summary>
+    /// Download upstream files named by header comments
+    /// </summary>
+    [Description("Download the upstream files that header comments name (GitHub and GitLab URLs with a commit or tag) to compare against. Go vendor/ is compared with the local module cache without this (default: false)")]
+    public bool Fetch { get; set; } = false;
+
+    /// <summary>
+    /// Go module cache to compare vendor/ against
+    /// </summary>
+    /// <example>/home/me/go/pkg/mod</example>
+    [Description("Go module cache directory (default: GOMODCACHE, else GOPATH/pkg/mod, else ~/go/pkg/mod)")]
+    public string? ModuleCache { get; set; }
+
+    /// <summary>
+    /// List copies that match upstream too
+    /// </summary>
+    [Description("List unmodified copies as well as modified and unverified ones (default: false)")]
+    public bool ShowClean { get; set; } = false;
+
+    /// <summary>
+    /// Lines of context around each change in the diffs
+    /// </summary>
+    [Range(0, 20)]
+    [Description("Context lines in the diffs (default: 2)")]
+    public int ContextLines { get; set; } = 2;
+
+    /// <summary>
+    /// Maximum number of diffs to include
+    /// </summary>
+    [Range(0, 200)]
+    [Description("Diffs to include for modified files (default: 20)")]
+    public int MaxDiffs { get; set; } = 20;
+
+    /// <summary>
+    /// Maximum number of files to compare
+    /// </summary>
+    [Range(1, 50000)]
+    [Description("Maximum files to compare with upstream; the rest are counted as unverified (default: 5000)")]
+    public int MaxFiles { get; set; } = 5000;
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
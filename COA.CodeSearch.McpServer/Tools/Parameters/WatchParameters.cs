@@ -0,0 +1,66 @@
+using System.ComponentModel;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the watch tool - registers standing queries and retrieves their alerts
+/// </summary>
+public class WatchParameters
+{
+    /// <summary>
+    /// add, list (default), remove or alerts
+    /// </summary>
+    [Description("add (register a watch), list (watches of the workspace), remove (by watchId), alerts (matches raised by index updates) (default: list)")]
+    public string Action { get; set; } = "list";
+
+    /// <summary>
+    /// Text or regex a new line must contain - for add
+    /// </summary>
+    /// <example>Thread.Sleep</example>
+    [Description("Text a new line must contain (regex with isRegex) - required for add")]
+    public string? Pattern { get; set; }
+
+    /// <summary>
+    /// Label shown in alerts - for add
+    /// </summary>
+    [Description("Label shown in alerts (default: the pattern)")]
+    public string? Name { get; set; }
+
+    /// <summary>
+    /// Treat the pattern as a regular expression
+    /// </summary>
+    [Description("Treat pattern as a regular expression (default: false)")]
+    public bool IsRegex { get; set; }
+
+    /// <summary>
+    /// Match the pattern case-sensitively
+    /// </summary>
+    [Description("Case-sensitive pattern (default: false)")]
+    public bool CaseSensitive { get; set; }
+
+    /// <summary>
+    /// Glob limiting the watched files - for add
+    /// </summary>
+    /// <example>payments/**</example>
+    [Description("Files to watch: '*.cs' matches names anywhere, 'payments/**' a directory (default: all files)")]
+    public string? FilePattern { get; set; }
+
+    /// <summary>
+    /// Watch to remove, or to filter alerts by
+    /// </summary>
+    [Description("Watch id - required for remove, optional filter for alerts")]
+    public string? WatchId { get; set; }
+
+    /// <summary>
+    /// Return only alerts after this sequence number
+    /// </summary>
+    [Description("Only alerts with a sequence above this - pass lastSequence from the previous call (default: 0, all kept alerts)")]
+    public long Since { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
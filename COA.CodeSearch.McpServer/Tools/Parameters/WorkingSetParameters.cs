@@ -0,0 +1,30 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the working_set tool - shows or clears the session's working set
+/// </summary>
+public class WorkingSetParameters
+{
+    /// <summary>
+    /// Forget the working set instead of showing it
+    /// </summary>
+    [Description("Forget the working set, e.g. when switching to an unrelated task (default: false)")]
+    public bool Clear { get; set; } = false;
+
+    /// <summary>
+    /// Maximum number of files and of symbols to show
+    /// </summary>
+    [Range(1, 200)]
+    [Description("Maximum files and symbols to show (default: 20)")]
+    public int MaxResults { get; set; } = 20;
+
+    /// <summary>
+    /// Path to the workspace directory. Defaults to current workspace if not specified.
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,37 @@
+using System.ComponentModel;
+using System.ComponentModel.DataAnnotations;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Parameters for the workspace_overview tool - an onboarding report built from the index
+/// </summary>
+public class WorkspaceOverviewParameters
+{
+    /// <summary>
+    /// Directory levels that name an architecture cluster
+    /// </summary>
+    [Range(1, 4)]
+    [Description("Directory levels that name a cluster: 1 = src, 2 = src/Services (default: 2)")]
+    public int ClusterDepth { get; set; } = 2;
+
+    /// <summary>
+    /// Maximum clusters to return
+    /// </summary>
+    [Range(1, 200)]
+    [Description("Maximum architecture clusters to return, most connected first (default: 20)")]
+    public int MaxClusters { get; set; } = 20;
+
+    /// <summary>
+    /// Include vendored and third-party directories
+    /// </summary>
+    [Description("Count vendored and third-party directories (see dependency_code) in the report (default: false)")]
+    public bool IncludeDependencyCode { get; set; }
+
+    /// <summary>
+    /// Path to the workspace directory (default: current workspace)
+    /// </summary>
+    /// <example>C:\source\MyProject</example>
+    [Description("Path to the workspace directory (default: current workspace)")]
+    public string? WorkspacePath { get; set; }
+}
@@ -0,0 +1,233 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Removes workspace indexes: a single workspace, stale indexes under the retention policy, or everything
+/// </summary>
+public class PurgeIndexTool : CodeSearchToolBase<PurgeIndexParameters, AIOptimizedResponse<PurgeIndexResult>>
+{
+    private static readonly string[] ValidScopes = { "workspace", "stale", "all", "budget" };
+
+    private readonly IIndexRetentionService _retentionService;
+    private readonly IIndexBudgetService _indexBudget;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<PurgeIndexTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the PurgeIndexTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="retentionService">Index retention service that performs the purge</param>
+    /// <param name="indexBudget">Size budget service that prunes files from an index</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public PurgeIndexTool(
+        IServiceProvider serviceProvider,
+        IIndexRetentionService retentionService,
+        IIndexBudgetService indexBudget,
+        IPathResolutionService pathResolutionService,
+        ILogger<PurgeIndexTool> logger) : base(serviceProvider, logger)
+    {
+        _retentionService = retentionService;
+        _indexBudget = indexBudget;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.PurgeIndex;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "RECLAIM DISK SPACE - Delete workspace indexes. Scope 'workspace' removes one index, 'stale' applies the retention policy " +
+        "(least recently used first), 'all' removes every index, 'budget' prunes files from one index until it fits MaxWorkspaceSizeMB " +
+        "and lists every pruned file. Use dryRun to preview. Re-run index_workspace to rebuild.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Resources;
+
+    /// <summary>
+    /// Executes the purge operation.
+    /// </summary>
+    /// <param name="parameters">Purge parameters including scope and dry-run flag</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The indexes removed and the space reclaimed</returns>
+    protected override async Task<AIOptimizedResponse<PurgeIndexResult>> ExecuteInternalAsync(
+        PurgeIndexParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var scope = (parameters.Scope ?? "workspace").Trim().ToLowerInvariant();
+        if (!ValidScopes.Contains(scope))
+        {
+            return new AIOptimizedResponse<PurgeIndexResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "INVALID_SCOPE",
+                    Message = $"Unknown scope '{parameters.Scope}'. Valid scopes: {string.Join(", ", ValidScopes)}",
+                    Recovery = new RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Use scope 'workspace' to remove a single index",
+                            "Use scope 'stale' to apply the retention policy",
+                            "Use scope 'all' to remove every index",
+                            "Use scope 'budget' to prune files from an index over its size budget"
+                        }
+                    }
+                }
+            };
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        try
+        {
+            if (scope == "budget")
+            {
+                return await PruneToBudgetAsync(workspacePath, parameters.DryRun, cancellationToken);
+            }
+
+            var purge = scope switch
+            {
+                "all" => await _retentionService.PurgeAllAsync(parameters.DryRun, cancellationToken),
+                "stale" => await _retentionService.EnforceRetentionAsync(parameters.DryRun, cancellationToken),
+                _ => await _retentionService.PurgeWorkspaceAsync(workspacePath, parameters.DryRun, cancellationToken)
+            };
+
+            var result = new PurgeIndexResult
+            {
+                Scope = scope,
+                DryRun = purge.DryRun,
+                Removed = purge.Removed.Select(r => new PurgedIndexInfo
+                {
+                    IndexPath = r.Index.IndexPath,
+                    WorkspacePath = r.Index.WorkspacePath,
+                    SizeBytes = r.Index.SizeBytes,
+                    LastAccessed = r.Index.LastAccessed,
+                    Reason = r.Reason
+                }).ToList(),
+                BytesFreed = purge.BytesFreed,
+                RemainingIndexes = _retentionService.GetWorkspaceIndexes().Count,
+                IndexRootPath = _pathResolutionService.GetIndexRootPath(),
+                Errors = purge.Errors
+            };
+
+            var verb = purge.DryRun ? "Would remove" : "Removed";
+            var response = new AIOptimizedResponse<PurgeIndexResult>
+            {
+                Success = purge.Errors.Count == 0,
+                Data = new AIResponseData<PurgeIndexResult> { Results = result },
+                Message = $"{verb} {result.Removed.Count} index(es), {result.BytesFreed / (1024.0 * 1024.0):F1} MB"
+            };
+
+            if (scope == "workspace" && result.Removed.Count == 0)
+            {
+                response.Insights = new List<string> { $"No index found for {workspacePath}" };
+            }
+            else if (!purge.DryRun && result.Removed.Count > 0)
+            {
+                response.Actions = new List<AIAction>
+                {
+                    new AIAction
+                    {
+                        Action = ToolNames.IndexWorkspace,
+                        Description = "Rebuild the index for any workspace you still need",
+                        Priority = 80
+                    }
+                };
+            }
+
+            return response;
+        }
+        catch (Exception ex)
+        {
+            _logger.LogError(ex, "Failed to purge indexes (scope: {Scope})", scope);
+            return new AIOptimizedResponse<PurgeIndexResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "PURGE_FAILED",
+                    Message = $"Failed to purge indexes: {ex.Message}",
+                    Recovery = new RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Close other CodeSearch instances that may hold index files open",
+                            "Verify write permissions for the index location",
+                            "Retry with dryRun to see which indexes are targeted"
+                        }
+                    }
+                }
+            };
+        }
+    }
+
+    /// <summary>
+    /// Applies the size budget to one workspace index and reports the files pruned from it
+    /// </summary>
+    private async Task<AIOptimizedResponse<PurgeIndexResult>> PruneToBudgetAsync(string workspacePath, bool dryRun, CancellationToken cancellationToken)
+    {
+        var prune = await _indexBudget.EnforceBudgetAsync(workspacePath, dryRun, cancellationToken);
+        var pruned = _indexBudget.GetPrunedFiles(workspacePath);
+
+        var result = new PurgeIndexResult
+        {
+            Scope = "budget",
+            DryRun = dryRun,
+            BytesFreed = Math.Max(0, prune.SizeBeforeBytes - prune.SizeAfterBytes),
+            RemainingIndexes = _retentionService.GetWorkspaceIndexes().Count,
+            IndexRootPath = _pathResolutionService.GetIndexRootPath(),
+            Budget = new IndexBudgetInfo
+            {
+                BudgetBytes = prune.BudgetBytes,
+                SizeBytes = prune.SizeAfterBytes,
+                WithinBudget = prune.WithinBudget,
+                PrunedThisPass = prune.Pruned,
+                PrunedFiles = pruned.ToList()
+            }
+        };
+
+        var response = new AIOptimizedResponse<PurgeIndexResult>
+        {
+            Success = true,
+            Data = new AIResponseData<PurgeIndexResult> { Results = result },
+            Message = prune.Pruned.Count > 0
+                ? $"{(dryRun ? "Would prune" : "Pruned")} {prune.Pruned.Count} file(s); {pruned.Count} file(s) pruned in total"
+                : $"Index is within budget; {pruned.Count} file(s) pruned earlier"
+        };
+
+        if (!_indexBudget.IsEnabled)
+        {
+            response.Insights = new List<string>
+            {
+                "Pruning is disabled - set CodeSearch:IndexStorage:Pruning:Enabled and MaxWorkspaceSizeMB to enforce a budget"
+            };
+        }
+        else if (!prune.WithinBudget)
+        {
+            response.Insights = new List<string>
+            {
+                "Still over budget after every pruning strategy - add exclusions to .codesearchignore or raise CodeSearch:IndexStorage:MaxWorkspaceSizeMB"
+            };
+        }
+
+        return response;
+    }
+}
@@ -0,0 +1,278 @@
+using System.Globalization;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Lucene.Net.Index;
+using Lucene.Net.QueryParsers.Classic;
+using Lucene.Net.Search;
+using Lucene.Net.Util;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Explains text_search query syntax with examples built from the workspace's own identifiers and
+/// executed against its index, so the hit counts show which syntax actually works here
+/// </summary>
+public class QueryHelpTool : CodeSearchToolBase<QueryHelpParameters, AIOptimizedResponse<QueryHelpResult>>
+{
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly QueryPreprocessor _queryPreprocessor;
+    private readonly SmartQueryPreprocessor _smartQueryPreprocessor;
+    private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly ILogger<QueryHelpTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the QueryHelpTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="luceneIndexService">Lucene index service used to execute the examples</param>
+    /// <param name="sqliteService">Symbol database used to pick example identifiers</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="queryPreprocessor">Query builder shared with text_search</param>
+    /// <param name="smartQueryPreprocessor">Auto-mode field routing shared with text_search</param>
+    /// <param name="codeAnalyzer">Analyzer used for query parsing</param>
+    /// <param name="logger">Logger instance</param>
+    public QueryHelpTool(
+        IServiceProvider serviceProvider,
+        ILuceneIndexService luceneIndexService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        QueryPreprocessor queryPreprocessor,
+        SmartQueryPreprocessor smartQueryPreprocessor,
+        CodeAnalyzer codeAnalyzer,
+        ILogger<QueryHelpTool> logger) : base(serviceProvider, logger)
+    {
+        _luceneIndexService = luceneIndexService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _queryPreprocessor = queryPreprocessor;
+        _smartQueryPreprocessor = smartQueryPreprocessor;
+        _codeAnalyzer = codeAnalyzer;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.QueryHelp;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "LEARN QUERY SYNTAX - text_search modes, wildcards, regex and boolean operators with examples executed against this workspace (hit counts included). " +
+        "Use when unsure which syntax is supported instead of guessing operators.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Executes the syntax lookup and validates the examples.
+    /// </summary>
+    /// <param name="parameters">Topic filter and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Syntax topics with validated examples</returns>
+    protected override async Task<AIOptimizedResponse<QueryHelpResult>> ExecuteInternalAsync(
+        QueryHelpParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var topic = string.IsNullOrWhiteSpace(parameters.Topic) || parameters.Topic.Equals("all", StringComparison.OrdinalIgnoreCase)
+            ? null
+            : parameters.Topic.Trim();
+        if (topic != null && !QuerySyntaxGuide.TopicNames.Contains(topic, StringComparer.OrdinalIgnoreCase))
+        {
+            return new AIOptimizedResponse<QueryHelpResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "UNKNOWN_TOPIC",
+                    Message = $"Unknown topic '{parameters.Topic}'. Topics: {string.Join(", ", QuerySyntaxGuide.TopicNames)}",
+                    Recovery = new RecoveryInfo
+                    {
+                        Steps = new[] { "Omit topic to get every topic", $"Use one of: {string.Join(", ", QuerySyntaxGuide.TopicNames)}" }
+                    }
+                }
+            };
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        var indexExists = await _luceneIndexService.IndexExistsAsync(workspacePath, cancellationToken);
+        var (typeName, memberName, source) = indexExists
+            ? await PickSampleNamesAsync(workspacePath, cancellationToken)
+            : (QuerySyntaxGuide.GenericTypeName, QuerySyntaxGuide.GenericMemberName, "generic");
+
+        var result = new QueryHelpResult
+        {
+            WorkspacePath = workspacePath,
+            SampleSource = source,
+            Topics = QuerySyntaxGuide.Build(typeName, memberName, topic),
+            Tips = QuerySyntaxGuide.Tips.ToList()
+        };
+
+        if (parameters.ValidateExamples && indexExists)
+        {
+            result.Validated = true;
+            foreach (var syntaxTopic in result.Topics.Where(t => t.SearchMode != "semantic"))
+            {
+                foreach (var example in syntaxTopic.Examples)
+                {
+                    await ValidateExampleAsync(workspacePath, syntaxTopic.SearchMode, example, cancellationToken);
+                }
+            }
+        }
+
+        var executed = result.Topics.SelectMany(t => t.Examples).Where(e => e.HitCount.HasValue || e.Error != null).ToList();
+        var response = new AIOptimizedResponse<QueryHelpResult>
+        {
+            Success = true,
+            Data = new AIResponseData<QueryHelpResult> { Results = result },
+            Message = result.Validated
+                ? $"{result.Topics.Count} topic(s); {executed.Count(e => e.HitCount > 0)} of {executed.Count} examples matched in this workspace"
+                : $"{result.Topics.Count} topic(s); examples not executed"
+        };
+
+        var insights = new List<string>();
+        if (!indexExists)
+        {
+            insights.Add("Workspace is not indexed - examples use generic names and were not executed; run index_workspace first");
+        }
+        if (executed.Any(e => e.HitCount == 0))
+        {
+            insights.Add("Zero-hit examples parsed fine but matched nothing here - the syntax works, the terms don't occur");
+        }
+        if (executed.Any(e => e.Error != null))
+        {
+            insights.Add("Examples with an error show syntax this index rejects - avoid that form");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        return response;
+    }
+
+    /// <summary>
+    /// Builds the query the way text_search does for the mode and counts matching documents
+    /// </summary>
+    private async Task ValidateExampleAsync(string workspacePath, string searchMode, QueryHelpExample example, CancellationToken cancellationToken)
+    {
+        try
+        {
+            Query query;
+            switch (searchMode)
+            {
+                case "exact":
+                    query = _queryPreprocessor.BuildQuery(example.Query, "literal", false, _codeAnalyzer);
+                    example.RoutedTo = "content";
+                    break;
+                case "fuzzy":
+                case "regex":
+                    query = _queryPreprocessor.BuildQuery(example.Query, searchMode, false, _codeAnalyzer);
+                    example.RoutedTo = "content";
+                    break;
+                default:
+                    var processed = _smartQueryPreprocessor.Process(example.Query, SearchMode.Auto);
+                    example.RoutedTo = processed.TargetField;
+                    if (!_queryPreprocessor.IsValidQuery(processed.ProcessedQuery, "standard", out var errorMessage))
+                    {
+                        example.Error = errorMessage;
+                        return;
+                    }
+
+                    if (processed.TargetField == "content")
+                    {
+                        query = _queryPreprocessor.BuildQuery(processed.ProcessedQuery, "standard", false, _codeAnalyzer);
+                    }
+                    else
+                    {
+                        var parser = new QueryParser(LuceneVersion.LUCENE_48, processed.TargetField, _codeAnalyzer) { AllowLeadingWildcard = true, Locale = CultureInfo.InvariantCulture };
+                        try
+                        {
+                            query = parser.Parse(processed.ProcessedQuery);
+                        }
+                        catch (ParseException)
+                        {
+                            query = new TermQuery(new Term(processed.TargetField, processed.ProcessedQuery.ToLowerInvariant()));
+                        }
+                    }
+                    break;
+            }
+
+            var searchResult = await _luceneIndexService.SearchAsync(workspacePath, query, 1, cancellationToken);
+            example.HitCount = searchResult.TotalHits;
+        }
+        catch (Exception ex) when (ex is ParseException or ArgumentException or InvalidOperationException)
+        {
+            example.Error = ex.Message;
+        }
+    }
+
+    /// <summary>
+    /// Picks a type and a member name that exist in the workspace so the examples can match
+    /// </summary>
+    private async Task<(string TypeName, string MemberName, string Source)> PickSampleNamesAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        try
+        {
+            if (_sqliteService.DatabaseExists(workspacePath))
+            {
+                var types = await _sqliteService.GetSymbolsByKindAsync(workspacePath, "class", cancellationToken);
+                var members = await _sqliteService.GetSymbolsByKindAsync(workspacePath, "method", cancellationToken);
+                if (members.Count == 0)
+                {
+                    members = await _sqliteService.GetSymbolsByKindAsync(workspacePath, "function", cancellationToken);
+                }
+
+                var typeName = PickMostCommon(types.Select(s => s.Name), requireUpperFirst: true);
+                var memberName = PickMostCommon(members.Select(s => s.Name), requireUpperFirst: false);
+                if (typeName != null && memberName != null)
+                {
+                    return (typeName, memberName, "symbols");
+                }
+            }
+
+            // No symbol database - fall back to PascalCase file names, which usually name a type
+            var files = await _luceneIndexService.SearchAsync(workspacePath, new MatchAllDocsQuery(), 200, cancellationToken);
+            var fileType = PickMostCommon(files.Hits
+                .Select(h => Path.GetFileNameWithoutExtension(h.FileName ?? h.FilePath)), requireUpperFirst: true);
+            if (fileType != null)
+            {
+                return (fileType, QuerySyntaxGuide.GenericMemberName, "files");
+            }
+        }
+        catch (Exception ex)
+        {
+            _logger.LogDebug(ex, "Could not pick sample names for query_help in {Workspace}", workspacePath);
+        }
+
+        return (QuerySyntaxGuide.GenericTypeName, QuerySyntaxGuide.GenericMemberName, "generic");
+    }
+
+    /// <summary>
+    /// Most frequent good sample name; ties break alphabetically so output is stable
+    /// </summary>
+    private static string? PickMostCommon(IEnumerable<string> names, bool requireUpperFirst) =>
+        names
+            .Where(n => QuerySyntaxGuide.IsGoodSample(n, requireUpperFirst))
+            .GroupBy(n => n, StringComparer.Ordinal)
+            .OrderByDescending(g => g.Count())
+            .ThenBy(g => g.Key, StringComparer.Ordinal)
+            .Select(g => g.Key)
+            .FirstOrDefault();
+}
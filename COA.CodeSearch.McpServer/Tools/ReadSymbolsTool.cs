@@ -1,4 +1,5 @@
 using System.ComponentModel;
+using System.Text;
 using COA.Mcp.Framework;
 using COA.Mcp.Framework.Base;
 using COA.Mcp.Framework.Models;
@@ -8,6 +9,7 @@ using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Julie;
 using COA.CodeSearch.McpServer.Tools.Models;
@@ -370,9 +372,20 @@ public class ReadSymbolsTool : CodeSearchToolBase<ReadSymbolsParameters, AIOptim
             StartLine = symbol.StartLine,
             EndLine = symbol.EndLine,
             StartColumn = symbol.StartColumn,
-            EndColumn = symbol.EndColumn
+            EndColumn = symbol.EndColumn,
+            StartByte = symbol.StartByte,
+            EndByte = symbol.EndByte
         };
 
+        // Tree-sitter columns count bytes; LSP clients want UTF-16 code units
+        var sourceLines = fileContent.Split('\n');
+        symbolCode.StartUtf16Column = symbol.StartLine >= 1 && symbol.StartLine <= sourceLines.Length
+            ? SourcePositions.ByteToUtf16Column(sourceLines[symbol.StartLine - 1].TrimEnd('\r'), symbol.StartColumn)
+            : symbol.StartColumn;
+        symbolCode.EndUtf16Column = symbol.EndLine >= 1 && symbol.EndLine <= sourceLines.Length
+            ? SourcePositions.ByteToUtf16Column(sourceLines[symbol.EndLine - 1].TrimEnd('\r'), symbol.EndColumn)
+            : symbol.EndColumn;
+
         // Extract code based on detail level
         if (parameters.DetailLevel == "signature")
         {
@@ -385,9 +398,11 @@ public class ReadSymbolsTool : CodeSearchToolBase<ReadSymbolsParameters, AIOptim
             // Implementation or full - use byte offsets for surgical precision
             if (symbol.StartByte.HasValue && symbol.EndByte.HasValue)
             {
-                // Byte-offset extraction (surgical precision - no bleeding into neighbors)
-                int startByte = symbol.StartByte.Value;
-                int endByte = symbol.EndByte.Value;
+                // Byte-offset extraction (surgical precision - no bleeding into neighbors).
+                // Offsets are UTF-8 bytes, so convert them to string indexes for non-ASCII files.
+                var utf8 = Encoding.UTF8.GetBytes(fileContent);
+                int startByte = UnicodeIdentifiers.CharIndexOfUtf8Offset(utf8, symbol.StartByte.Value);
+                int endByte = UnicodeIdentifiers.CharIndexOfUtf8Offset(utf8, symbol.EndByte.Value);
 
                 // Extract code using byte offsets
                 int length = endByte - startByte;
@@ -481,6 +496,8 @@ public class ReadSymbolsTool : CodeSearchToolBase<ReadSymbolsParameters, AIOptim
                     })
                     .Take(20) // Limit to prevent token explosion
                     .ToList();
+
+                await AddUtf16ColumnsAsync(workspacePath, symbolCode.Dependencies, cancellationToken);
             }
 
             // Callers: What calls this symbol?
@@ -501,6 +518,8 @@ public class ReadSymbolsTool : CodeSearchToolBase<ReadSymbolsParameters, AIOptim
                     })
                     .Take(20) // Limit to prevent token explosion
                     .ToList();
+
+                await AddUtf16ColumnsAsync(workspacePath, symbolCode.Callers, cancellationToken);
             }
 
             // Inheritance: Base classes and interfaces
@@ -552,6 +571,16 @@ public class ReadSymbolsTool : CodeSearchToolBase<ReadSymbolsParameters, AIOptim
         return symbolCode;
     }
 
+    private async Task AddUtf16ColumnsAsync(string workspacePath, List<SymbolReference> references, CancellationToken cancellationToken)
+    {
+        var positions = CreateSourcePositions(workspacePath);
+        foreach (var reference in references)
+        {
+            reference.Utf16Column = await positions.ToUtf16ColumnAsync(
+                reference.FilePath, reference.Line, reference.Column, cancellationToken);
+        }
+    }
+
     private int EstimateTokens(string text)
     {
         // Rough estimation: ~4 characters per token
@@ -0,0 +1,434 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Builds a review packet for the changes between two refs: enclosing symbols per hunk,
+/// callers outside the diff, related tests and relevant notes
+/// </summary>
+public class ReviewContextTool : CodeSearchToolBase<ReviewContextParameters, AIOptimizedResponse<ReviewContextResult>>
+{
+    private const int MaxChangedSymbols = 25;
+    private const int MaxNotes = 30;
+
+    private static readonly Regex NoteComment = new(@"\b(TODO|FIXME|HACK|XXX|NOTE)\b[:\s](.*)$", RegexOptions.Compiled);
+    private static readonly string[] DocumentationExtensions = { ".md", ".mdx", ".rst", ".adoc", ".txt" };
+    private static readonly string[] ContainerKinds = { "namespace", "module", "package", "import" };
+
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly QueryPreprocessor _queryPreprocessor;
+    private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly ISQLiteSymbolService? _sqliteService;
+    private readonly ILogger<ReviewContextTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the ReviewContextTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="gitService">Git service for reading the diff</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="luceneIndexService">Lucene index service for documentation lookups</param>
+    /// <param name="queryPreprocessor">Query preprocessor for building documentation queries</param>
+    /// <param name="codeAnalyzer">Code analyzer used by the index</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="sqliteService">Optional symbol database for enclosing symbols and callers</param>
+    public ReviewContextTool(
+        IServiceProvider serviceProvider,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        ILuceneIndexService luceneIndexService,
+        QueryPreprocessor queryPreprocessor,
+        CodeAnalyzer codeAnalyzer,
+        ILogger<ReviewContextTool> logger,
+        ISQLiteSymbolService? sqliteService = null) : base(serviceProvider, logger)
+    {
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _luceneIndexService = luceneIndexService;
+        _queryPreprocessor = queryPreprocessor;
+        _codeAnalyzer = codeAnalyzer;
+        _sqliteService = sqliteService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.ReviewContext;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "REVIEW A CHANGE IN ONE CALL - Given baseRef/headRef, returns changed files and hunks with their enclosing symbols, " +
+        "callers of changed symbols outside the diff, related tests, and TODO/doc notes. Use before reviewing a PR or branch.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Executes the review context analysis.
+    /// </summary>
+    /// <param name="parameters">Base and head refs and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The review packet</returns>
+    protected override async Task<AIOptimizedResponse<ReviewContextResult>> ExecuteInternalAsync(
+        ReviewContextParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _gitService.IsRepositoryAsync(workspacePath, cancellationToken))
+        {
+            return CreateGitErrorResponse("NOT_A_GIT_REPOSITORY", $"{workspacePath} is not inside a git repository (or git is not installed)");
+        }
+
+        var headRef = string.IsNullOrWhiteSpace(parameters.HeadRef) || parameters.HeadRef.Equals("worktree", StringComparison.OrdinalIgnoreCase)
+            ? null
+            : parameters.HeadRef;
+
+        List<GitFileDiff> diff;
+        try
+        {
+            diff = await _gitService.GetDiffAsync(workspacePath, parameters.BaseRef, headRef, cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            return CreateGitErrorResponse("GIT_DIFF_FAILED", ex.Message);
+        }
+
+        var result = new ReviewContextResult
+        {
+            BaseRef = parameters.BaseRef,
+            HeadRef = headRef ?? "worktree",
+            TotalFiles = diff.Count
+        };
+
+        var hasSymbols = _sqliteService != null && _sqliteService.DatabaseExists(workspacePath);
+        var symbolCache = new Dictionary<string, List<JulieSymbol>>(StringComparer.OrdinalIgnoreCase);
+        var changedByFullPath = new Dictionary<string, GitFileDiff>(StringComparer.OrdinalIgnoreCase);
+        var changedSymbols = new List<(JulieSymbol Symbol, string RelativePath)>();
+
+        foreach (var file in diff.Where(f => !f.IsBinary).Take(parameters.MaxFiles))
+        {
+            var fullPath = Path.GetFullPath(Path.Combine(workspacePath, file.Path));
+            changedByFullPath[fullPath] = file;
+
+            var symbols = hasSymbols && file.Status != "deleted"
+                ? await GetSymbolsAsync(workspacePath, fullPath, symbolCache, cancellationToken)
+                : new List<JulieSymbol>();
+
+            var reviewFile = new ReviewFile
+            {
+                Path = file.Path,
+                OldPath = file.OldPath,
+                Status = file.Status,
+                Additions = file.Additions,
+                Deletions = file.Deletions,
+                IsTest = TestFileDetector.IsTestFile(file.Path)
+            };
+
+            foreach (var hunk in file.Hunks)
+            {
+                var enclosing = FindInnermostSymbols(symbols, hunk.NewStart, hunk.NewEnd);
+                reviewFile.Hunks.Add(new ReviewHunk
+                {
+                    OldStart = hunk.OldStart,
+                    OldCount = hunk.OldCount,
+                    NewStart = hunk.NewStart,
+                    NewCount = hunk.NewCount,
+                    Added = hunk.AddedLines.Count,
+                    Removed = hunk.RemovedLines.Count,
+                    EnclosingSymbols = enclosing.Select(s => s.Name).ToList()
+                });
+
+                foreach (var symbol in enclosing.Where(s => !changedSymbols.Any(c => c.Symbol.Id == s.Id)))
+                {
+                    changedSymbols.Add((symbol, file.Path));
+                }
+
+                if (parameters.IncludeNotes)
+                {
+                    AddDiffNotes(result, file.Path, hunk);
+                }
+            }
+
+            result.Files.Add(reviewFile);
+        }
+
+        result.ChangedSymbols = changedSymbols.Take(MaxChangedSymbols).Select(c => new ReviewSymbol
+        {
+            Name = c.Symbol.Name,
+            Kind = c.Symbol.Kind,
+            FilePath = c.RelativePath,
+            StartLine = c.Symbol.StartLine,
+            EndLine = c.Symbol.EndLine,
+            Signature = c.Symbol.Signature
+        }).ToList();
+
+        if (hasSymbols)
+        {
+            await AddAffectedCallersAsync(result, workspacePath, changedByFullPath, symbolCache, parameters.MaxCallersPerSymbol, cancellationToken);
+            await AddRelatedTestsAsync(result, workspacePath, cancellationToken);
+        }
+        else
+        {
+            foreach (var testFile in result.Files.Where(f => f.IsTest))
+            {
+                result.RelatedTests.Add(new RelatedTest { FilePath = testFile.Path, Reason = "changed in this diff" });
+            }
+        }
+
+        if (parameters.IncludeNotes)
+        {
+            await AddDocumentationNotesAsync(result, workspacePath, cancellationToken);
+        }
+
+        var response = new AIOptimizedResponse<ReviewContextResult>
+        {
+            Success = true,
+            Data = new AIResponseData<ReviewContextResult> { Results = result },
+            Message = $"{result.TotalFiles} file(s), {result.Files.Sum(f => f.Hunks.Count)} hunk(s), {result.ChangedSymbols.Count} changed symbol(s), " +
+                      $"{result.AffectedCallers.Count} caller(s) outside the diff, {result.RelatedTests.Count} related test(s)"
+        };
+
+        var insights = new List<string>();
+        if (!hasSymbols)
+        {
+            insights.Add("Symbol database not available - enclosing symbols and callers were skipped; run index_workspace first");
+        }
+        if (headRef != null && !headRef.Equals("HEAD", StringComparison.OrdinalIgnoreCase))
+        {
+            insights.Add($"Symbols and callers come from the indexed checkout, not {headRef}; check out {headRef} and re-index for exact results");
+        }
+        if (result.TotalFiles > result.Files.Count)
+        {
+            insights.Add($"Only {result.Files.Count} of {result.TotalFiles} changed files were analyzed (maxFiles or binary files)");
+        }
+        if (result.Files.Any(f => !f.IsTest) && !result.RelatedTests.Any())
+        {
+            insights.Add("No related tests found for the changed code");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        if (result.AffectedCallers.Count > 0)
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction
+                {
+                    Action = ToolNames.ReadSymbols,
+                    Description = "Read the changed symbols and their callers to check the change is compatible",
+                    Priority = 80
+                }
+            };
+        }
+
+        _logger.LogDebug("Built review context for {Base}..{Head}: {Files} files, {Symbols} symbols",
+            result.BaseRef, result.HeadRef, result.TotalFiles, result.ChangedSymbols.Count);
+        return response;
+    }
+
+    /// <summary>
+    /// Symbols overlapping the line range that don't contain another overlapping symbol,
+    /// e.g. the method rather than its class
+    /// </summary>
+    private static List<JulieSymbol> FindInnermostSymbols(List<JulieSymbol> symbols, int startLine, int endLine)
+    {
+        var overlapping = symbols
+            .Where(s => !ContainerKinds.Contains(s.Kind, StringComparer.OrdinalIgnoreCase) &&
+                        s.StartLine <= endLine && s.EndLine >= startLine)
+            .ToList();
+
+        return overlapping
+            .Where(s => !overlapping.Any(o => o.Id != s.Id &&
+                                               o.StartLine >= s.StartLine && o.EndLine <= s.EndLine &&
+                                               (o.StartLine > s.StartLine || o.EndLine < s.EndLine)))
+            .ToList();
+    }
+
+    private async Task<List<JulieSymbol>> GetSymbolsAsync(
+        string workspacePath,
+        string fullPath,
+        Dictionary<string, List<JulieSymbol>> cache,
+        CancellationToken cancellationToken)
+    {
+        if (!cache.TryGetValue(fullPath, out var symbols))
+        {
+            symbols = await _sqliteService!.GetSymbolsForFileAsync(workspacePath, fullPath, cancellationToken) ?? new List<JulieSymbol>();
+            cache[fullPath] = symbols;
+        }
+
+        return symbols;
+    }
+
+    private async Task AddAffectedCallersAsync(
+        ReviewContextResult result,
+        string workspacePath,
+        Dictionary<string, GitFileDiff> changedByFullPath,
+        Dictionary<string, List<JulieSymbol>> symbolCache,
+        int maxCallersPerSymbol,
+        CancellationToken cancellationToken)
+    {
+        foreach (var name in result.ChangedSymbols.Select(s => s.Name).Distinct(StringComparer.Ordinal))
+        {
+            var identifiers = await _sqliteService!.GetIdentifiersByNameAsync(workspacePath, name, caseSensitive: true, cancellationToken);
+            var callers = 0;
+
+            foreach (var identifier in identifiers.OrderBy(i => i.FilePath, StringComparer.Ordinal).ThenBy(i => i.StartLine))
+            {
+                var fullPath = Path.GetFullPath(Path.IsPathRooted(identifier.FilePath)
+                    ? identifier.FilePath
+                    : Path.Combine(workspacePath, identifier.FilePath));
+
+                // Usages inside the diff are already in front of the reviewer
+                if (changedByFullPath.TryGetValue(fullPath, out var changedFile) &&
+                    changedFile.Hunks.Any(h => h.ContainsNewLine(identifier.StartLine)))
+                {
+                    continue;
+                }
+
+                var containing = FindInnermostSymbols(
+                    await GetSymbolsAsync(workspacePath, fullPath, symbolCache, cancellationToken),
+                    identifier.StartLine,
+                    identifier.StartLine).FirstOrDefault();
+
+                var relativePath = ToRelativePath(workspacePath, fullPath);
+                result.AffectedCallers.Add(new ReviewCaller
+                {
+                    Symbol = name,
+                    FilePath = relativePath,
+                    Line = identifier.StartLine,
+                    Kind = identifier.Kind,
+                    CallerSymbol = containing?.Name,
+                    IsTest = TestFileDetector.IsTestFile(relativePath)
+                });
+
+                if (++callers >= maxCallersPerSymbol)
+                    break;
+            }
+        }
+    }
+
+    private async Task AddRelatedTestsAsync(ReviewContextResult result, string workspacePath, CancellationToken cancellationToken)
+    {
+        void Add(string path, string reason)
+        {
+            if (!result.RelatedTests.Any(t => t.FilePath.Equals(path, StringComparison.OrdinalIgnoreCase)))
+                result.RelatedTests.Add(new RelatedTest { FilePath = path, Reason = reason });
+        }
+
+        foreach (var testFile in result.Files.Where(f => f.IsTest))
+        {
+            Add(testFile.Path, "changed in this diff");
+        }
+
+        foreach (var caller in result.AffectedCallers.Where(c => c.IsTest))
+        {
+            Add(caller.FilePath, $"references {caller.Symbol}");
+        }
+
+        foreach (var sourceFile in result.Files.Where(f => !f.IsTest && f.Status != "deleted"))
+        {
+            foreach (var pattern in TestFileDetector.GetConventionalTestPatterns(sourceFile.Path))
+            {
+                var matches = await _sqliteService!.SearchFilesByPatternAsync(workspacePath, pattern, searchFullPath: false, maxResults: 5, cancellationToken: cancellationToken);
+                foreach (var match in matches)
+                {
+                    Add(ToRelativePath(workspacePath, match.Path), $"named after {Path.GetFileName(sourceFile.Path)}");
+                }
+            }
+        }
+    }
+
+    private static void AddDiffNotes(ReviewContextResult result, string filePath, DiffHunk hunk)
+    {
+        // With --unified=0 the added lines of a hunk are contiguous from NewStart
+        for (var i = 0; i < hunk.AddedLines.Count && result.Notes.Count < MaxNotes; i++)
+        {
+            var match = NoteComment.Match(hunk.AddedLines[i]);
+            if (!match.Success)
+                continue;
+
+            result.Notes.Add(new ReviewNote
+            {
+                Source = "diff",
+                FilePath = filePath,
+                Line = hunk.NewStart + i,
+                Text = $"{match.Groups[1].Value}: {match.Groups[2].Value.Trim()}"
+            });
+        }
+    }
+
+    private async Task AddDocumentationNotesAsync(ReviewContextResult result, string workspacePath, CancellationToken cancellationToken)
+    {
+        foreach (var name in result.ChangedSymbols.Select(s => s.Name).Where(n => n.Length > 3).Distinct(StringComparer.Ordinal).Take(10))
+        {
+            if (result.Notes.Count >= MaxNotes)
+                break;
+
+            try
+            {
+                var query = _queryPreprocessor.BuildQuery(name, "literal", false, _codeAnalyzer);
+                var search = await _luceneIndexService.SearchAsync(workspacePath, query, 50, cancellationToken);
+
+                foreach (var hit in search.Hits.Where(h => DocumentationExtensions.Contains(Path.GetExtension(h.FilePath), StringComparer.OrdinalIgnoreCase)).Take(3))
+                {
+                    result.Notes.Add(new ReviewNote
+                    {
+                        Source = "docs",
+                        FilePath = ToRelativePath(workspacePath, hit.FilePath),
+                        Line = hit.LineNumber ?? 1,
+                        Text = $"Mentions {name}"
+                    });
+                }
+            }
+            catch (Exception ex)
+            {
+                _logger.LogDebug(ex, "Documentation lookup failed for {Symbol}", name);
+            }
+        }
+    }
+
+    private static string ToRelativePath(string workspacePath, string path) =>
+        Path.GetRelativePath(workspacePath, path).Replace('\\', '/');
+
+    private static AIOptimizedResponse<ReviewContextResult> CreateGitErrorResponse(string code, string message) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo
+            {
+                Steps = new[]
+                {
+                    "Check that the workspace is a git checkout and git is on PATH",
+                    "Fetch the base branch (e.g. 'git fetch origin main') and use 'origin/main' as baseRef",
+                    "Use headRef 'worktree' to review uncommitted changes"
+                }
+            }
+        }
+    };
+}
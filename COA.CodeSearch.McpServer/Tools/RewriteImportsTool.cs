@@ -0,0 +1,258 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Rewrites every import of a path or namespace prefix across the indexed workspace with <see cref="ImportRewriter"/>:
+/// preview shows the rewritten lines and diffs, apply writes all files or none through
+/// <see cref="UnifiedFileEditService.ApplyFileSetAsync"/>
+/// </summary>
+public class RewriteImportsTool : CodeSearchToolBase<RewriteImportsParameters, AIOptimizedResponse<RewriteImportsResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly UnifiedFileEditService _fileEditService;
+    private readonly IWorkspacePermissionService _permissionService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<RewriteImportsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the RewriteImportsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite service listing the workspace's indexed files</param>
+    /// <param name="fileEditService">Edit service that writes the rewritten files as one unit</param>
+    /// <param name="permissionService">Workspace permission service for edit checks</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public RewriteImportsTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        UnifiedFileEditService fileEditService,
+        IWorkspacePermissionService permissionService,
+        IPathResolutionService pathResolutionService,
+        ILogger<RewriteImportsTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _fileEditService = fileEditService;
+        _permissionService = permissionService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.RewriteImports;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "REWRITE IMPORT PATHS ACROSS THE WORKSPACE - After a module or namespace rename (github.com/org/old → github.com/org/new, Acme.Old → Acme.New), " +
+        "rewrites Go imports and go.mod requires, C# usings, .csproj Using/ProjectReference/PackageReference items, JS/TS, Python and JVM imports. " +
+        "Keeps aliases and grouped imports intact. Preview (default) shows every changed line; apply writes all files or none.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Refactoring;
+
+    /// <summary>
+    /// Previews or applies the import rewrite.
+    /// </summary>
+    /// <param name="parameters">Prefixes, scope and mode</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Rewritten lines and diffs per file</returns>
+    protected override async Task<AIOptimizedResponse<RewriteImportsResult>> ExecuteInternalAsync(
+        RewriteImportsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        var from = parameters.From.Trim();
+        var to = parameters.To.Trim();
+        if (from.Length == 0 || to.Length == 0)
+        {
+            return CreateErrorResponse("MISSING_PREFIX", "Both from and to are required", "Pass the old and new import path or namespace");
+        }
+        if (from == to)
+        {
+            return CreateErrorResponse("NOTHING_TO_REWRITE", $"from and to are both '{from}'");
+        }
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try rewrite_imports again");
+        }
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Select(f => (Relative: Relative(workspacePath, f.Path), Full: Path.IsPathRooted(f.Path) ? f.Path : Path.Combine(workspacePath, f.Path)))
+            .Where(f => ImportRewriter.Handles(f.Relative))
+            .Where(f => string.IsNullOrEmpty(scope) || f.Relative.StartsWith(scope + "/", StringComparison.Ordinal))
+            .OrderBy(f => f.Relative, StringComparer.Ordinal)
+            .ToList();
+
+        var result = new RewriteImportsResult { WorkspacePath = workspacePath, From = from, To = to, Preview = parameters.Preview };
+        var changes = new List<FileSetChange>();
+
+        foreach (var file in files)
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!File.Exists(file.Full))
+                continue;
+
+            // Read from disk rather than the index so the expected hash matches what apply will overwrite
+            var bytes = await File.ReadAllBytesAsync(file.Full, cancellationToken);
+            var content = FileLineUtilities.DetectEncoding(bytes).GetString(bytes).TrimStart('\uFEFF');
+            result.FilesScanned++;
+
+            var (rewritten, rewrites) = ImportRewriter.Rewrite(file.Relative, content, from, to, parameters.KeepGoPackageNames);
+            if (rewrites.Count == 0)
+                continue;
+
+            result.FilesChanged++;
+            result.TotalRewrites += rewrites.Count;
+            changes.Add(new FileSetChange
+            {
+                FilePath = file.Full,
+                NewContent = rewritten,
+                ExpectedHash = UnifiedFileEditService.ComputeHash(bytes),
+                CheckExpectedHash = !parameters.Force
+            });
+
+            if (result.Files.Count < parameters.MaxFiles)
+            {
+                var diff = LineDiff.Unified(content, rewritten, file.Relative, parameters.ContextLines);
+                result.Files.Add(new ImportRewriteFile
+                {
+                    FilePath = file.Relative,
+                    Rewrites = rewrites,
+                    Diff = diff.Diff.Length > 0 ? diff.Diff : null
+                });
+            }
+        }
+        result.Truncated = result.FilesChanged > result.Files.Count;
+
+        if (changes.Count == 0)
+        {
+            return CreateResponse(result, $"No imports of {from} in {result.FilesScanned} file(s)");
+        }
+
+        if (parameters.Preview)
+        {
+            var preview = CreateResponse(result, $"Would rewrite {result.TotalRewrites} import(s) in {result.FilesChanged} file(s)");
+            preview.Actions = new List<AIAction>
+            {
+                new AIAction { Action = ToolNames.RewriteImports, Description = "Run again with preview: false to write these changes", Priority = 80 }
+            };
+            preview.Insights = BuildInsights(result, from);
+            return preview;
+        }
+
+        if (result.Truncated)
+        {
+            return CreateErrorResponse("TOO_MANY_FILES", $"{result.FilesChanged} file(s) would change, more than maxFiles ({parameters.MaxFiles})",
+                result, "Nothing was written", "Narrow the rewrite with path, or raise maxFiles");
+        }
+
+        var permission = await _permissionService.IsEditAllowedAsync(new EditPermissionRequest
+        {
+            FilePath = changes[0].FilePath,
+            OperationType = "rewrite_imports",
+            Context = $"{from} → {to}: {changes.Count} file(s)"
+        }, cancellationToken);
+        if (!permission.Allowed)
+        {
+            return CreateErrorResponse("EDIT_NOT_ALLOWED", $"Edit not allowed: {permission.Reason}");
+        }
+
+        var applied = await _fileEditService.ApplyFileSetAsync(changes, cancellationToken);
+        if (!applied.Success)
+        {
+            result.Conflicts = applied.Conflicts.Select(p => Relative(workspacePath, p)).ToList();
+            return result.Conflicts.Count > 0
+                ? CreateErrorResponse("REWRITE_CONFLICT", $"{result.Conflicts.Count} file(s) changed on disk during the rewrite: {string.Join(", ", result.Conflicts)}",
+                    result, "Nothing was written", "Preview again, or apply with force: true to overwrite")
+                : CreateErrorResponse("APPLY_FAILED", $"{applied.ErrorMessage}{(applied.RolledBack ? " - files already written were restored" : "")}",
+                    result, "Nothing was changed", "Check file permissions and retry");
+        }
+
+        result.Applied = true;
+        _logger.LogInformation("Rewrote {Count} import(s) of {From} to {To} in {Files} file(s) in {Workspace}",
+            result.TotalRewrites, from, to, result.FilesChanged, workspacePath);
+
+        var response = CreateResponse(result, $"Rewrote {result.TotalRewrites} import(s) in {result.FilesChanged} file(s)");
+        response.Insights = BuildInsights(result, from);
+        response.Insights.Add("Build the workspace to confirm; the file watcher reindexes the rewritten files");
+        return response;
+    }
+
+    private static List<string> BuildInsights(RewriteImportsResult result, string from)
+    {
+        var insights = new List<string>();
+        var aliased = result.Files.SelectMany(f => f.Rewrites).Count(r => r.Note != null);
+        if (aliased > 0)
+        {
+            insights.Add($"{aliased} Go import(s) were aliased with the old package name - pass keepGoPackageNames: false to drop the aliases and rename uses yourself");
+        }
+        if (result.Files.Any(f => f.Rewrites.Any(r => r.Kind is "using" or "jvm-import" or "python-import")))
+        {
+            insights.Add($"Only import lines are rewritten - fully qualified uses of {from} in code and namespace or package declarations are left as they are");
+        }
+        if (result.Files.Any(f => f.Rewrites.Any(r => r.Kind == "project" && r.OldText.Contains("ProjectReference", StringComparison.Ordinal))))
+        {
+            insights.Add("ProjectReference paths were rewritten - the referenced project folders and files still need renaming to match");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Showing {result.Files.Count} of {result.FilesChanged} changed files - narrow with path or raise maxFiles");
+        }
+        return insights;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<RewriteImportsResult> CreateResponse(RewriteImportsResult result, string message) => new()
+    {
+        Success = true,
+        Data = new AIResponseData<RewriteImportsResult> { Results = result },
+        Message = message
+    };
+
+    private static AIOptimizedResponse<RewriteImportsResult> CreateErrorResponse(string code, string message, RewriteImportsResult result, params string[] steps) => new()
+    {
+        Success = false,
+        Data = new AIResponseData<RewriteImportsResult> { Results = result },
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+
+    private static AIOptimizedResponse<RewriteImportsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
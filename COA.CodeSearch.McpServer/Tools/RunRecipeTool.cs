@@ -0,0 +1,332 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Recipes;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Runs a declarative refactoring recipe (search, replace, rename, edit, organize imports, verify build)
+/// in one call: preview shows every resulting change, apply writes all files or none through
+/// <see cref="UnifiedFileEditService.ApplyFileSetAsync"/> and rollback restores an applied run as a unit
+/// </summary>
+public class RunRecipeTool : CodeSearchToolBase<RunRecipeParameters, AIOptimizedResponse<RunRecipeResult>>
+{
+    private readonly IRecipeService _recipeService;
+    private readonly UnifiedFileEditService _fileEditService;
+    private readonly IWorkspacePermissionService _permissionService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<RunRecipeTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the RunRecipeTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="recipeService">Recipe planner, build runner and run history</param>
+    /// <param name="fileEditService">Edit service that writes the recipe's files as one unit</param>
+    /// <param name="permissionService">Workspace permission service for edit checks</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public RunRecipeTool(
+        IServiceProvider serviceProvider,
+        IRecipeService recipeService,
+        UnifiedFileEditService fileEditService,
+        IWorkspacePermissionService permissionService,
+        IPathResolutionService pathResolutionService,
+        ILogger<RunRecipeTool> logger) : base(serviceProvider, logger)
+    {
+        _recipeService = recipeService;
+        _fileEditService = fileEditService;
+        _permissionService = permissionService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.RunRecipe;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "MULTI-STEP REFACTOR IN ONE CALL - Define a recipe (search → replace/rename → edit → organize_imports → verify_build) as JSON. " +
+        "preview shows the diff of every file the whole recipe touches without writing; apply writes all files or none, runs the build " +
+        "and rolls everything back if it fails; rollback undoes an applied run as a unit.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Refactoring;
+
+    /// <summary>
+    /// Previews, applies or rolls back a recipe.
+    /// </summary>
+    /// <param name="parameters">Recipe, mode and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Step reports, file diffs and the build outcome</returns>
+    protected override async Task<AIOptimizedResponse<RunRecipeResult>> ExecuteInternalAsync(
+        RunRecipeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        var mode = parameters.Mode.ToLowerInvariant();
+
+        try
+        {
+            return mode switch
+            {
+                "preview" or "apply" => await RunAsync(workspacePath, mode, parameters, cancellationToken),
+                "rollback" => await RollbackAsync(workspacePath, parameters, cancellationToken),
+                _ => CreateErrorResponse("INVALID_MODE", $"Unknown mode '{parameters.Mode}'", "Use preview, apply or rollback")
+            };
+        }
+        catch (RecipeException ex)
+        {
+            return CreateErrorResponse(ex.Code, ex.Message, "Fix the recipe and preview it again");
+        }
+    }
+
+    private async Task<AIOptimizedResponse<RunRecipeResult>> RunAsync(
+        string workspacePath,
+        string mode,
+        RunRecipeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        if (string.IsNullOrWhiteSpace(parameters.Recipe))
+        {
+            return CreateErrorResponse("MISSING_RECIPE", $"{mode} needs a recipe", "Pass the recipe JSON in the recipe parameter");
+        }
+
+        var recipe = _recipeService.Parse(parameters.Recipe);
+        var plan = await _recipeService.PlanAsync(workspacePath, recipe, cancellationToken);
+        var result = new RunRecipeResult { Mode = mode, Name = plan.Name, Steps = plan.Steps };
+        AddDiffs(result, plan.ChangedFiles, parameters.ContextLines);
+
+        if (plan.FailedStep != null)
+        {
+            return CreateErrorResponse("RECIPE_STEP_FAILED", $"Step {plan.FailedStep.Index} ({plan.FailedStep.Op}) failed: {plan.FailedStep.Message}",
+                result, "Nothing was written", "Fix the step and preview the recipe again");
+        }
+
+        var changed = plan.ChangedFiles.ToList();
+        if (mode == "preview")
+        {
+            var preview = CreateResponse(result,
+                changed.Count == 0
+                    ? "Recipe changes no files"
+                    : $"Recipe would change {changed.Count} file(s): +{result.TotalAdded} -{result.TotalRemoved}" +
+                      (plan.BuildStep != null ? ", then verify the build" : ""));
+            if (changed.Count > 0)
+            {
+                preview.Actions = new List<AIAction>
+                {
+                    new AIAction { Action = ToolNames.RunRecipe, Description = "Run again with mode: apply to write these changes", Priority = 80 }
+                };
+            }
+            return preview;
+        }
+
+        if (changed.Count == 0)
+        {
+            return CreateResponse(result, "Recipe changes no files; nothing applied");
+        }
+
+        var permission = await _permissionService.IsEditAllowedAsync(new EditPermissionRequest
+        {
+            FilePath = Path.Combine(workspacePath, changed[0].FilePath),
+            OperationType = "run_recipe",
+            Context = $"{plan.Name ?? "recipe"}: {changed.Count} file(s)"
+        }, cancellationToken);
+        if (!permission.Allowed)
+        {
+            return CreateErrorResponse("EDIT_NOT_ALLOWED", $"Edit not allowed: {permission.Reason}");
+        }
+
+        var applied = await _fileEditService.ApplyFileSetAsync(changed
+            .Select(f => new FileSetChange
+            {
+                FilePath = Path.Combine(workspacePath, f.FilePath),
+                NewContent = f.Current,
+                ExpectedHash = f.OriginalHash,
+                CheckExpectedHash = !parameters.Force
+            })
+            .ToList(), cancellationToken);
+
+        if (!applied.Success)
+        {
+            result.Conflicts = applied.Conflicts.Select(p => ToRelative(workspacePath, p)).ToList();
+            return result.Conflicts.Count > 0
+                ? CreateErrorResponse("RECIPE_CONFLICT", $"{result.Conflicts.Count} file(s) changed on disk while the recipe ran: {string.Join(", ", result.Conflicts)}",
+                    result, "Nothing was written", "Preview the recipe again, or apply with force: true to overwrite")
+                : CreateErrorResponse("APPLY_FAILED", $"{applied.ErrorMessage}{(applied.RolledBack ? " - files already written were restored" : "")}",
+                    result, "Nothing was changed", "Check file permissions and retry");
+        }
+
+        var run = _recipeService.RecordRun(workspacePath, plan);
+        result.RunId = run.RunId;
+        _logger.LogInformation("Applied recipe {Name} ({RunId}) to {Workspace}: {Count} file(s)",
+            plan.Name, run.RunId, workspacePath, changed.Count);
+
+        if (plan.BuildStep != null)
+        {
+            result.Build = await _recipeService.VerifyBuildAsync(workspacePath, plan.BuildStep, cancellationToken);
+            UpdateBuildStep(result);
+
+            if (!result.Build.Succeeded)
+            {
+                var restored = await RestoreAsync(workspacePath, run, force: true, cancellationToken);
+                result.RolledBack = restored.Success;
+                return CreateErrorResponse("BUILD_FAILED",
+                    $"Build failed (exit code {result.Build.ExitCode}) after applying the recipe; " +
+                    (restored.Success ? "all files were restored" : $"restoring the files failed: {restored.ErrorMessage}"),
+                    result, "Read build.output for the errors", "Fix the recipe and preview it again");
+            }
+        }
+
+        var response = CreateResponse(result,
+            $"Applied recipe to {changed.Count} file(s): +{result.TotalAdded} -{result.TotalRemoved}" +
+            (result.Build != null ? $", build passed in {result.Build.Duration.TotalSeconds:F0}s" : ""));
+        response.Actions = new List<AIAction>
+        {
+            new AIAction { Action = ToolNames.RunRecipe, Description = $"Roll back with mode: rollback, runId: {run.RunId}", Priority = 40 }
+        };
+        return response;
+    }
+
+    private async Task<AIOptimizedResponse<RunRecipeResult>> RollbackAsync(
+        string workspacePath,
+        RunRecipeParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var run = parameters.RunId != null
+            ? _recipeService.GetRun(parameters.RunId)
+            : _recipeService.ListRuns(workspacePath).FirstOrDefault(r => !r.RolledBack);
+        if (run == null)
+        {
+            return CreateErrorResponse("RUN_NOT_FOUND",
+                parameters.RunId != null ? $"No applied recipe run '{parameters.RunId}'" : "No applied recipe run to roll back in this workspace",
+                "Runs are kept in memory until the server restarts", "Use git to restore older changes");
+        }
+        if (run.RolledBack)
+        {
+            return CreateErrorResponse("ALREADY_ROLLED_BACK", $"Run {run.RunId} was already rolled back");
+        }
+
+        var permission = await _permissionService.IsEditAllowedAsync(new EditPermissionRequest
+        {
+            FilePath = Path.Combine(run.WorkspacePath, run.Files[0].FilePath),
+            OperationType = "run_recipe",
+            Context = $"rollback of {run.Name ?? run.RunId}: {run.Files.Count} file(s)"
+        }, cancellationToken);
+        if (!permission.Allowed)
+        {
+            return CreateErrorResponse("EDIT_NOT_ALLOWED", $"Edit not allowed: {permission.Reason}");
+        }
+
+        var result = new RunRecipeResult { Mode = "rollback", Name = run.Name, RunId = run.RunId };
+        var restored = await RestoreAsync(run.WorkspacePath, run, parameters.Force, cancellationToken);
+        if (!restored.Success)
+        {
+            result.Conflicts = restored.Conflicts.Select(p => ToRelative(run.WorkspacePath, p)).ToList();
+            return result.Conflicts.Count > 0
+                ? CreateErrorResponse("RECIPE_CONFLICT", $"{result.Conflicts.Count} file(s) were edited after the recipe ran: {string.Join(", ", result.Conflicts)}",
+                    result, "Nothing was restored", "Roll back with force: true to discard those edits")
+                : CreateErrorResponse("ROLLBACK_FAILED", restored.ErrorMessage ?? "Rollback failed", result, "Nothing was restored");
+        }
+
+        result.RolledBack = true;
+        result.Files = run.Files
+            .Select(f => new RecipeFileDiff { FilePath = f.FilePath, Status = f.Before == null ? "deleted" : "modified" })
+            .ToList();
+        _logger.LogInformation("Rolled back recipe run {RunId} in {Workspace}", run.RunId, run.WorkspacePath);
+
+        return CreateResponse(result, $"Rolled back run {run.RunId}: restored {run.Files.Count} file(s)");
+    }
+
+    private async Task<FileSetResult> RestoreAsync(string workspacePath, RecipeRun run, bool force, CancellationToken cancellationToken)
+    {
+        var restored = await _fileEditService.ApplyFileSetAsync(run.Files
+            .Select(f => new FileSetChange
+            {
+                FilePath = Path.Combine(workspacePath, f.FilePath),
+                NewContent = f.Before,
+                ExpectedHash = f.AfterHash,
+                CheckExpectedHash = !force
+            })
+            .ToList(), cancellationToken);
+
+        run.RolledBack = restored.Success;
+        return restored;
+    }
+
+    private static void AddDiffs(RunRecipeResult result, IEnumerable<RecipeFileChange> files, int contextLines)
+    {
+        foreach (var file in files)
+        {
+            var diff = LineDiff.Unified(file.Original, file.Current, file.FilePath, contextLines);
+            result.Files.Add(new RecipeFileDiff
+            {
+                FilePath = file.FilePath,
+                Status = file.Original == null ? "added" : file.Current == null ? "deleted" : "modified",
+                Added = diff.Added,
+                Removed = diff.Removed,
+                Diff = diff.Diff.Length > 0 ? diff.Diff : null
+            });
+        }
+
+        result.TotalAdded = result.Files.Sum(f => f.Added);
+        result.TotalRemoved = result.Files.Sum(f => f.Removed);
+    }
+
+    private static void UpdateBuildStep(RunRecipeResult result)
+    {
+        var step = result.Steps.LastOrDefault(s => s.Op == "verify_build");
+        if (step == null || result.Build == null)
+            return;
+
+        step.Status = result.Build.Succeeded ? "ok" : "failed";
+        step.Message = $"'{result.Build.Command}' exited with {result.Build.ExitCode}";
+    }
+
+    private static string ToRelative(string workspacePath, string path) =>
+        Path.GetRelativePath(workspacePath, path).Replace('\\', '/');
+
+    private static AIOptimizedResponse<RunRecipeResult> CreateResponse(RunRecipeResult result, string message) => new()
+    {
+        Success = true,
+        Data = new AIResponseData<RunRecipeResult> { Results = result },
+        Message = message
+    };
+
+    private static AIOptimizedResponse<RunRecipeResult> CreateErrorResponse(string code, string message, RunRecipeResult result, params string[] steps) => new()
+    {
+        Success = false,
+        Data = new AIResponseData<RunRecipeResult> { Results = result },
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+
+    private static AIOptimizedResponse<RunRecipeResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
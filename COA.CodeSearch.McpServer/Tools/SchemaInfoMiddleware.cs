@@ -0,0 +1,71 @@
+using System.Reflection;
+using COA.Mcp.Framework.Pipeline;
+using COA.Mcp.Framework.TokenOptimization.Models;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Stamps every tool response with the tool's schema version (meta.schemaVersion) and surfaces the invocation's
+/// <see cref="ToolCallNotes"/>: deprecated parameters as warning insights plus meta.deprecations, so clients using
+/// renamed parameters see a warning instead of silently breaking later, and arguments taken from the editor context.
+/// </summary>
+public class SchemaInfoMiddleware : SimpleMiddlewareBase
+{
+    public const string SchemaVersionKey = "schemaVersion";
+    public const string DeprecationsKey = "deprecations";
+
+    public SchemaInfoMiddleware()
+    {
+        // After the tool's own middleware, before QueryUsageMiddleware which runs last
+        Order = int.MaxValue - 1;
+    }
+
+    public override Task OnAfterExecutionAsync(string toolName, object? parameters, object? result, long elapsedMs)
+    {
+        // Tool results are AIOptimizedResponse<T> for many T - find the properties rather than the type
+        var properties = result?.GetType().GetProperties(BindingFlags.Public | BindingFlags.Instance);
+        var metaProperty = properties?.FirstOrDefault(p => p.Name == "Meta" && p.PropertyType == typeof(AIResponseMeta) && p.CanWrite);
+        if (metaProperty == null)
+            return Task.CompletedTask;
+
+        var meta = metaProperty.GetValue(result) as AIResponseMeta ?? new AIResponseMeta();
+        metaProperty.SetValue(result, meta);
+        meta.ExtensionData ??= new Dictionary<string, object>();
+        meta.ExtensionData[SchemaVersionKey] = ToolSchemaVersions.GetVersion(toolName);
+
+        var deprecations = ToolCallNotes.Deprecations;
+        var editorContextUses = ToolCallNotes.EditorContextUses;
+        if (deprecations.Count == 0 && editorContextUses.Count == 0)
+            return Task.CompletedTask;
+
+        var insightsProperty = properties!.FirstOrDefault(p => p.Name == "Insights" && p.PropertyType.IsAssignableFrom(typeof(List<string>)) && p.CanWrite);
+        var insights = insightsProperty?.GetValue(result) as IList<string> ?? new List<string>();
+        insightsProperty?.SetValue(result, insights);
+
+        if (deprecations.Count > 0)
+        {
+            meta.ExtensionData[DeprecationsKey] = deprecations;
+            foreach (var notice in deprecations)
+            {
+                // Responses may be served again from the cache - don't repeat the warning
+                var warning = $"⚠️ {notice}";
+                if (!insights.Contains(warning))
+                {
+                    insights.Insert(0, warning);
+                }
+            }
+        }
+
+        // Say where an argument nobody typed came from, so a stale editor context is noticed
+        foreach (var use in editorContextUses)
+        {
+            var note = $"📍 {use}";
+            if (!insights.Contains(note))
+            {
+                insights.Add(note);
+            }
+        }
+
+        return Task.CompletedTask;
+    }
+}
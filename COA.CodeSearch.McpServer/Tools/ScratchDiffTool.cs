@@ -0,0 +1,123 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Unified diffs of staged scratch files against the real workspace, with conflict detection
+/// </summary>
+public class ScratchDiffTool : CodeSearchToolBase<ScratchDiffParameters, AIOptimizedResponse<ScratchDiffResult>>
+{
+    private readonly IScratchWorkspaceService _scratch;
+    private readonly IPathResolutionService _pathResolutionService;
+
+    /// <summary>
+    /// Initializes a new instance of the ScratchDiffTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="scratch">Scratch area holding staged files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public ScratchDiffTool(
+        IServiceProvider serviceProvider,
+        IScratchWorkspaceService scratch,
+        IPathResolutionService pathResolutionService,
+        ILogger<ScratchDiffTool> logger) : base(serviceProvider, logger)
+    {
+        _scratch = scratch;
+        _pathResolutionService = pathResolutionService;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.ScratchDiff;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "REVIEW STAGED CODE - Unified diff of every file in the scratch area against the real workspace, flagging files edited on disk " +
+        "since they were staged. Use before commit_scratch.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Diffs staged files.
+    /// </summary>
+    /// <param name="parameters">Files and context size</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Per-file diffs</returns>
+    protected override Task<AIOptimizedResponse<ScratchDiffResult>> ExecuteInternalAsync(
+        ScratchDiffParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        IReadOnlyList<ScratchFileDiff> files;
+        try
+        {
+            files = _scratch.Diff(workspacePath, parameters.FilePaths, parameters.ContextLines);
+        }
+        catch (ScratchException ex)
+        {
+            return Task.FromResult(new AIOptimizedResponse<ScratchDiffResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = ex.Code,
+                    Message = ex.Message,
+                    Recovery = new RecoveryInfo { Steps = new[] { "Use paths inside the workspace" } }
+                }
+            });
+        }
+
+        var result = new ScratchDiffResult
+        {
+            Files = files.ToList(),
+            TotalAdded = files.Sum(f => f.Added),
+            TotalRemoved = files.Sum(f => f.Removed),
+            Conflicts = files.Count(f => f.Conflict)
+        };
+
+        var response = new AIOptimizedResponse<ScratchDiffResult>
+        {
+            Success = true,
+            Data = new AIResponseData<ScratchDiffResult> { Results = result },
+            Message = files.Count == 0
+                ? "Scratch area is empty"
+                : $"{files.Count} staged file(s): +{result.TotalAdded} -{result.TotalRemoved}" +
+                  (result.Conflicts > 0 ? $", {result.Conflicts} changed on disk since staging" : "")
+        };
+
+        if (result.Conflicts > 0)
+        {
+            response.Insights = new List<string>
+            {
+                $"Edited on disk after staging: {string.Join(", ", files.Where(f => f.Conflict).Select(f => f.FilePath))} - " +
+                "re-stage from the current file, or commit with force to overwrite"
+            };
+        }
+
+        if (files.Any(f => f.Status != "unchanged"))
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction { Action = ToolNames.CommitScratch, Description = "Apply the staged files to the workspace", Priority = 70 }
+            };
+        }
+
+        return Task.FromResult(response);
+    }
+}
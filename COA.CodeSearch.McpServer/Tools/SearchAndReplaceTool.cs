@@ -1,3 +1,4 @@
+using System.Globalization;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.Services;
 using COA.CodeSearch.McpServer.Services.Analysis;
@@ -134,6 +135,7 @@ public class SearchAndReplaceTool : CodeSearchToolBase<SearchAndReplaceParams, A
             // Build Lucene query for finding candidate files
             var queryBuilder = new QueryParser(LuceneVersion.LUCENE_48, "content", new StandardAnalyzer(LuceneVersion.LUCENE_48));
             queryBuilder.AllowLeadingWildcard = true;
+            queryBuilder.Locale = CultureInfo.InvariantCulture;
             
             Lucene.Net.Search.Query query;
             try
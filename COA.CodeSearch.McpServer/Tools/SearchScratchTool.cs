@@ -0,0 +1,118 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Line search over staged scratch content, which the Lucene index doesn't see until it is committed
+/// </summary>
+public class SearchScratchTool : CodeSearchToolBase<SearchScratchParameters, AIOptimizedResponse<SearchScratchResult>>
+{
+    private readonly IScratchWorkspaceService _scratch;
+    private readonly IPathResolutionService _pathResolutionService;
+
+    /// <summary>
+    /// Initializes a new instance of the SearchScratchTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="scratch">Scratch area holding staged files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public SearchScratchTool(
+        IServiceProvider serviceProvider,
+        IScratchWorkspaceService scratch,
+        IPathResolutionService pathResolutionService,
+        ILogger<SearchScratchTool> logger) : base(serviceProvider, logger)
+    {
+        _scratch = scratch;
+        _pathResolutionService = pathResolutionService;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SearchScratch;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "SEARCH STAGED CODE - Find lines in files staged with stage_scratch (text or regex). The index only covers the real workspace, " +
+        "so use this to check generated code before committing it.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Searches staged files.
+    /// </summary>
+    /// <param name="parameters">Pattern and options</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Matching lines</returns>
+    protected override Task<AIOptimizedResponse<SearchScratchResult>> ExecuteInternalAsync(
+        SearchScratchParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var pattern = ValidateRequired(parameters.Pattern, nameof(parameters.Pattern));
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        IReadOnlyList<ScratchSearchMatch> matches;
+        try
+        {
+            matches = _scratch.Search(workspacePath, pattern, parameters.Regex, parameters.CaseSensitive, parameters.MaxResults);
+        }
+        catch (ArgumentException ex)
+        {
+            return Task.FromResult(new AIOptimizedResponse<SearchScratchResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "INVALID_REGEX",
+                    Message = $"Invalid regular expression: {ex.Message}",
+                    Recovery = new RecoveryInfo { Steps = new[] { "Escape special characters or set regex to false" } }
+                }
+            });
+        }
+        catch (RegexMatchTimeoutException)
+        {
+            return Task.FromResult(new AIOptimizedResponse<SearchScratchResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "REGEX_TIMEOUT",
+                    Message = "The regular expression took too long",
+                    Recovery = new RecoveryInfo { Steps = new[] { "Simplify the pattern" } }
+                }
+            });
+        }
+
+        var staged = _scratch.List(workspacePath);
+        var result = new SearchScratchResult
+        {
+            Pattern = pattern,
+            FilesSearched = staged.Count(e => e.Content != null),
+            Matches = matches.ToList()
+        };
+
+        return Task.FromResult(new AIOptimizedResponse<SearchScratchResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SearchScratchResult> { Results = result },
+            Message = staged.Count == 0
+                ? "Scratch area is empty"
+                : $"{matches.Count} match(es) in {matches.Select(m => m.FilePath).Distinct().Count()} of {result.FilesSearched} staged file(s)"
+        });
+    }
+}
@@ -0,0 +1,198 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Editor;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Records the user's open file, cursor and selection, so later calls can say "this" instead of a location
+/// </summary>
+public class SetEditorContextTool : CodeSearchToolBase<SetEditorContextParameters, AIOptimizedResponse<SetEditorContextResult>>
+{
+    private readonly IEditorContextService _editorContext;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly IWorkingSetService? _workingSet;
+    private readonly ILogger<SetEditorContextTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the SetEditorContextTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="editorContext">Session editor context</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="workingSet">Optional session working set, which the open file joins</param>
+    public SetEditorContextTool(
+        IServiceProvider serviceProvider,
+        IEditorContextService editorContext,
+        IPathResolutionService pathResolutionService,
+        ILogger<SetEditorContextTool> logger,
+        IWorkingSetService? workingSet = null) : base(serviceProvider, logger)
+    {
+        _editorContext = editorContext;
+        _pathResolutionService = pathResolutionService;
+        _workingSet = workingSet;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SetEditorContext;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT IS THE USER LOOKING AT? Record the editor's open file, cursor and selection. Afterwards find_references, goto_definition, " +
+        "hover, type_of, find_tests, trace_call_path, get_symbols_overview and similar tools called without a symbol, position or file use " +
+        "the selection or cursor, so \"find references to this\" needs no location, and text_search ranks files near the open one higher. " +
+        "Call again whenever the editor moves; clear when it closes.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Sets or clears the editor context.
+    /// </summary>
+    /// <param name="parameters">Open file, cursor and selection</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The context in effect and the arguments tools will take from it</returns>
+    protected override Task<AIOptimizedResponse<SetEditorContextResult>> ExecuteInternalAsync(
+        SetEditorContextParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        if (parameters.Clear)
+        {
+            var cleared = _editorContext.Clear();
+            return Task.FromResult(new AIOptimizedResponse<SetEditorContextResult>
+            {
+                Success = true,
+                Data = new AIResponseData<SetEditorContextResult> { Results = new SetEditorContextResult() },
+                Message = cleared ? "Editor context cleared" : "No editor context was set"
+            });
+        }
+
+        if (string.IsNullOrWhiteSpace(parameters.FilePath))
+        {
+            return Task.FromResult(CreateErrorResponse("MISSING_FILE_PATH", "filePath is required unless clear is true",
+                "Pass the file open in the editor", "Or pass clear: true to forget the context"));
+        }
+
+        var encoding = parameters.ColumnEncoding.ToLowerInvariant();
+        if (encoding is not ("utf-16" or "utf-8"))
+        {
+            return Task.FromResult(CreateErrorResponse("INVALID_COLUMN_ENCODING", $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                "Use utf-16 (LSP) or utf-8"));
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        var fullPath = Path.GetFullPath(Path.Combine(workspacePath, parameters.FilePath));
+        var relativePath = Path.GetRelativePath(workspacePath, fullPath).Replace('\\', '/');
+        if (relativePath.StartsWith("../") || Path.IsPathRooted(relativePath))
+        {
+            return Task.FromResult(CreateErrorResponse("FILE_OUTSIDE_WORKSPACE", $"{parameters.FilePath} is not in {workspacePath}",
+                "Pass workspacePath for the workspace the file belongs to"));
+        }
+
+        var context = new EditorContext
+        {
+            WorkspacePath = workspacePath,
+            FilePath = relativePath,
+            Line = parameters.Line,
+            Column = parameters.Line == null ? null : parameters.Column ?? 0,
+            SymbolAtCursor = string.IsNullOrWhiteSpace(parameters.SymbolAtCursor) ? null : parameters.SymbolAtCursor.Trim(),
+            ColumnEncoding = encoding,
+            UpdatedAt = DateTime.UtcNow
+        };
+
+        if (parameters.SelectionStartLine != null)
+        {
+            context.Selection = new EditorSelection
+            {
+                StartLine = parameters.SelectionStartLine.Value,
+                StartColumn = parameters.SelectionStartColumn ?? 0,
+                EndLine = parameters.SelectionEndLine ?? parameters.SelectionStartLine.Value,
+                EndColumn = parameters.SelectionEndColumn ?? parameters.SelectionStartColumn ?? 0,
+                Text = parameters.SelectedText
+            };
+            if (context.Selection.EndLine < context.Selection.StartLine)
+            {
+                return Task.FromResult(CreateErrorResponse("INVALID_SELECTION",
+                    $"Selection ends on line {context.Selection.EndLine}, before it starts on line {context.Selection.StartLine}",
+                    "Pass the selection with selectionStartLine <= selectionEndLine"));
+            }
+        }
+        else if (!string.IsNullOrEmpty(parameters.SelectedText))
+        {
+            context.Selection = new EditorSelection
+            {
+                StartLine = parameters.Line ?? 1,
+                StartColumn = parameters.Column ?? 0,
+                EndLine = parameters.Line ?? 1,
+                EndColumn = parameters.Column ?? 0,
+                Text = parameters.SelectedText
+            };
+        }
+
+        _editorContext.Set(context);
+        _workingSet?.RecordFile(relativePath, WorkingSetActivity.Opened, workspacePath);
+        if (context.SymbolAtCursor != null)
+        {
+            _workingSet?.RecordSymbol(workspacePath, context.SymbolAtCursor);
+        }
+
+        var result = new SetEditorContextResult { Context = context };
+        foreach (var tool in EditorContextService.ContextAwareTools)
+        {
+            if (EditorContextService.ArgumentFor(context, tool) is { } use)
+            {
+                result.Defaults[tool] = use;
+            }
+        }
+
+        var insights = new List<string>();
+        if (!File.Exists(fullPath))
+        {
+            insights.Add($"⚠️ {relativePath} does not exist on disk - positions in it won't resolve");
+        }
+        if (result.Defaults.TryGetValue(ToolNames.FindReferences, out var references))
+        {
+            insights.Add($"find_references without a symbol now uses {references.Value}");
+        }
+        if (!result.Defaults.ContainsKey(ToolNames.FindTests))
+        {
+            insights.Add("No name is selected and symbolAtCursor is unset - tools taking names only (find_tests, trace_call_path, find_implementations) still need one");
+        }
+
+        _logger.LogDebug("Editor context set to {FilePath}:{Line}:{Column} in {WorkspacePath}", relativePath, context.Line, context.Column, workspacePath);
+
+        return Task.FromResult(new AIOptimizedResponse<SetEditorContextResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SetEditorContextResult> { Results = result },
+            Message = context.CursorPosition != null ? $"Editor at {context.CursorPosition}" : $"Editor on {relativePath}",
+            Insights = insights
+        });
+    }
+
+    private static AIOptimizedResponse<SetEditorContextResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
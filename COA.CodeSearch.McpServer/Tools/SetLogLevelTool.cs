@@ -0,0 +1,96 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services.Logging;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Changes the server's minimum log level at runtime
+/// </summary>
+public class SetLogLevelTool : CodeSearchToolBase<SetLogLevelParameters, AIOptimizedResponse<SetLogLevelResult>>
+{
+    private readonly ILogQueryService _logQueryService;
+
+    /// <summary>
+    /// Initializes a new instance of the SetLogLevelTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="logQueryService">Log query service that owns the level switches</param>
+    /// <param name="logger">Logger instance</param>
+    public SetLogLevelTool(
+        IServiceProvider serviceProvider,
+        ILogQueryService logQueryService,
+        ILogger<SetLogLevelTool> logger) : base(serviceProvider, logger)
+    {
+        _logQueryService = logQueryService;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SetLogLevel;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "Change log verbosity without restarting - raise to 'Debug' before reproducing a problem, then inspect with get_logs. " +
+        "Resets to appsettings.json on restart.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Executes the log level change.
+    /// </summary>
+    /// <param name="parameters">The new level and scope</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Previous and current levels</returns>
+    protected override Task<AIOptimizedResponse<SetLogLevelResult>> ExecuteInternalAsync(
+        SetLogLevelParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        if (!RuntimeLogLevel.TryParse(parameters.Level, out var level))
+        {
+            return Task.FromResult(new AIOptimizedResponse<SetLogLevelResult>
+            {
+                Success = false,
+                Error = new ErrorInfo
+                {
+                    Code = "INVALID_LOG_LEVEL",
+                    Message = $"Unknown log level '{parameters.Level}'",
+                    Recovery = new RecoveryInfo
+                    {
+                        Steps = new[] { "Use one of: Trace, Debug, Information, Warning, Error, Critical" }
+                    }
+                }
+            });
+        }
+
+        var (previousDefault, previousCodeSearch) = _logQueryService.GetLevels();
+        _logQueryService.SetLevel(level, parameters.CodeSearchOnly);
+        var (currentDefault, currentCodeSearch) = _logQueryService.GetLevels();
+
+        var result = new SetLogLevelResult
+        {
+            PreviousDefaultLevel = previousDefault.ToString(),
+            PreviousCodeSearchLevel = previousCodeSearch.ToString(),
+            DefaultLevel = currentDefault.ToString(),
+            CodeSearchLevel = currentCodeSearch.ToString()
+        };
+
+        return Task.FromResult(new AIOptimizedResponse<SetLogLevelResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SetLogLevelResult> { Results = result },
+            Message = parameters.CodeSearchOnly
+                ? $"CodeSearch log level set to {currentCodeSearch}"
+                : $"Log level set to {currentDefault}"
+        });
+    }
+}
@@ -5,7 +5,12 @@ using System.Text.Json;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.ResponseBuilders;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.GoTypes;
 using COA.CodeSearch.McpServer.Services.Julie;
+using COA.CodeSearch.McpServer.Services.LanguageServers;
+using COA.CodeSearch.McpServer.Services.Roslyn;
+using COA.CodeSearch.McpServer.Services.Scratch;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Tools.Models;
 using COA.CodeSearch.McpServer.Tools.Parameters;
@@ -17,6 +22,7 @@ using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.ResponseBuilders;
 using COA.Mcp.Framework.TokenOptimization.Storage;
+using Microsoft.Extensions.Configuration;
 using Microsoft.Extensions.Logging;
 
 namespace COA.CodeSearch.McpServer.Tools;
@@ -34,6 +40,11 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
     private readonly IResourceStorageService _storageService;
     private readonly ICacheKeyGenerator _keyGenerator;
     private readonly SmartRefactorResponseBuilder _responseBuilder;
+    private readonly ILanguageServerService? _languageServers;
+    private readonly IRoslynAnalysisService? _roslyn;
+    private readonly IGoTypesService? _goTypes;
+    private readonly NamespaceConventionSettings _namespaceConventions;
+    private readonly NamingConventionSettings _namingConventions;
     private readonly ILogger<SmartRefactorTool> _logger;
 
     public SmartRefactorTool(
@@ -43,7 +54,11 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         IResponseCacheService cacheService,
         IResourceStorageService storageService,
         ICacheKeyGenerator keyGenerator,
-        ILogger<SmartRefactorTool> logger) : base(serviceProvider, logger)
+        ILogger<SmartRefactorTool> logger,
+        ILanguageServerService? languageServers = null,
+        IRoslynAnalysisService? roslyn = null,
+        IGoTypesService? goTypes = null,
+        IConfiguration? configuration = null) : base(serviceProvider, logger)
     {
         _referenceResolver = referenceResolver ?? throw new ArgumentNullException(nameof(referenceResolver));
         _sqliteService = sqliteService ?? throw new ArgumentNullException(nameof(sqliteService));
@@ -52,6 +67,13 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         _keyGenerator = keyGenerator ?? throw new ArgumentNullException(nameof(keyGenerator));
         _responseBuilder = new SmartRefactorResponseBuilder(logger as ILogger<SmartRefactorResponseBuilder>, storageService);
         _logger = logger ?? throw new ArgumentNullException(nameof(logger));
+        _languageServers = languageServers;
+        _roslyn = roslyn;
+        _goTypes = goTypes;
+        _namespaceConventions = configuration?.GetSection("CodeSearch:NamespaceConventions").Get<NamespaceConventionSettings>()
+            ?? new NamespaceConventionSettings();
+        _namingConventions = configuration?.GetSection("CodeSearch:NamingConventions").Get<NamingConventionSettings>()
+            ?? new NamingConventionSettings();
     }
 
     public override string Name => ToolNames.SmartRefactor;
@@ -59,7 +81,7 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
     public override string Description =>
         "SAFE SEMANTIC REFACTORING - Symbol-aware code transformations using AST-validated positions. " +
         "You are skilled at safe refactoring - this tool handles the mechanics perfectly. " +
-        "Performs rename_symbol, extract_to_file, move_symbol_to_file, extract_interface operations across entire workspace. " +
+        "Performs rename_symbol, extract_to_file, move_symbol_to_file, extract_interface, fix_namespaces, fix_naming, rename_module operations across entire workspace. " +
         "ALWAYS use find_references BEFORE refactoring to understand impact. " +
         "When dry_run preview looks correct, the actual operation will succeed perfectly - no need to verify afterward. " +
         "Unlike simple text editing, this tool preserves code structure and updates all references atomically.";
@@ -89,6 +111,9 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
                 "extract_to_file" => await HandleExtractToFileAsync(parameters, workspacePath, cancellationToken),
                 "move_symbol_to_file" => await HandleMoveSymbolToFileAsync(parameters, workspacePath, cancellationToken),
                 "extract_interface" => await HandleExtractInterfaceAsync(parameters, workspacePath, cancellationToken),
+                "fix_namespaces" => await HandleFixNamespacesAsync(parameters, workspacePath, cancellationToken),
+                "fix_naming" => await HandleFixNamingAsync(parameters, workspacePath, cancellationToken),
+                "rename_module" => await HandleRenameModuleAsync(parameters, workspacePath, cancellationToken),
                 _ => new SmartRefactorResult
                 {
                     Success = false,
@@ -96,14 +121,17 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
                     DryRun = parameters.DryRun,
                     Errors = new List<string>
                     {
-                        $"Unknown operation: '{operation}'. Supported: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface"
+                        $"Unknown operation: '{operation}'. Supported: rename_symbol, extract_to_file, move_symbol_to_file, extract_interface, fix_namespaces, fix_naming, rename_module"
                     },
                     NextActions = new List<string>
                     {
                         "Use operation='rename_symbol' for renaming symbols across workspace",
                         "Use operation='extract_to_file' to extract a symbol to a new file",
                         "Use operation='move_symbol_to_file' to move a symbol to a new file (extract + remove from source)",
-                        "Use operation='extract_interface' to create an interface from a class"
+                        "Use operation='extract_interface' to create an interface from a class",
+                        "Use operation='fix_namespaces' to make namespace and package declarations match their directories",
+                        "Use operation='fix_naming' to rename the symbols check_naming reports to their suggested names",
+                        "Use operation='rename_module' to rename a Go module path or C# root namespace everywhere it is used"
                     }
                 }
             };
@@ -178,6 +206,16 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
             caseSensitive: false,
             cancellationToken);
 
+        // Roslyn (C#) or a language server for the definition's language renames exactly that symbol; the index is the fallback
+        var precise = await TryPreciseRenameAsync(parameters, workspacePath, oldName, newName, references, cancellationToken);
+        if (precise != null)
+        {
+            return precise;
+        }
+
+        // go/types keeps only the Go identifiers that denote the definition's symbol and adds ones the index missed
+        var goTypesNotes = await ValidateGoReferencesAsync(workspacePath, oldName, references, cancellationToken);
+
         if (!references.Any())
         {
             return new SmartRefactorResult
@@ -250,6 +288,11 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
             Changes = changes,
             Errors = errors
         };
+        if (goTypesNotes != null)
+        {
+            result.Source = "go/types";
+            result.Notes.AddRange(goTypesNotes);
+        }
 
         // Add next actions
         if (parameters.DryRun)
@@ -266,6 +309,280 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         return result;
     }
 
+    /// <summary>
+    /// Renames through the Roslyn tier for C# definitions, or the language server configured for the definition's
+    /// file, when one is enabled and answers. Returns null to fall back to the index's references.
+    /// </summary>
+    private async Task<SmartRefactorResult?> TryPreciseRenameAsync(
+        SmartRefactorParameters parameters,
+        string workspacePath,
+        string oldName,
+        string newName,
+        List<ResolvedReference> references,
+        CancellationToken cancellationToken)
+    {
+        var roslynEnabled = _roslyn is { IsEnabled: true };
+        var languageServersEnabled = _languageServers is { IsEnabled: true };
+        if (!roslynEnabled && !languageServersEnabled)
+            return null;
+
+        var definitions = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, oldName, caseSensitive: true, cancellationToken))
+            .Where(s => s.Name == oldName
+                        && ((roslynEnabled && _roslyn!.Handles(s.FilePath))
+                            || (languageServersEnabled && _languageServers!.GetServerFor(s.FilePath) != null)))
+            .ToList();
+        var definition = definitions.FirstOrDefault();
+        if (definition == null)
+            return null;
+
+        var position = await FindNamePositionAsync(workspacePath, definition, oldName, cancellationToken);
+        if (position == null)
+            return null;
+
+        string? server = null;
+        Dictionary<string, List<LspTextEdit>>? edits = null;
+        if (roslynEnabled && _roslyn!.Handles(definition.FilePath))
+        {
+            try
+            {
+                edits = await _roslyn.GetRenameEditsAsync(workspacePath, definition.FilePath, position, newName, cancellationToken);
+                server = "roslyn";
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogWarning(ex, "Roslyn rename of '{OldName}' failed", oldName);
+            }
+        }
+        if ((edits == null || edits.Count == 0) && languageServersEnabled && _languageServers!.GetServerFor(definition.FilePath) is { } languageServer)
+        {
+            edits = await _languageServers.GetRenameEditsAsync(workspacePath, definition.FilePath, position, newName, cancellationToken);
+            server = languageServer;
+        }
+        if (edits == null || edits.Count == 0 || server == null)
+            return null;
+
+        _logger.LogInformation("🎯 {Server} renamed '{OldName}' with {Count} edits across {FileCount} files",
+            server, oldName, edits.Values.Sum(e => e.Count), edits.Count);
+
+        var workspaceRoot = Path.GetFullPath(workspacePath).TrimEnd(Path.DirectorySeparatorChar) + Path.DirectorySeparatorChar;
+        var changes = new List<FileRefactorChange>();
+        var errors = new List<string>();
+        foreach (var (filePath, fileEdits) in edits.OrderBy(e => e.Key))
+        {
+            if (changes.Count >= parameters.MaxFiles)
+            {
+                errors.Add($"Reached max files limit ({parameters.MaxFiles}). Stopping.");
+                break;
+            }
+
+            // Servers may also want to edit dependencies or generated code they see; only the workspace is touched
+            if (!Path.GetFullPath(filePath).StartsWith(workspaceRoot, StringComparison.OrdinalIgnoreCase))
+            {
+                errors.Add($"⚠️ {filePath}: outside the workspace, not renamed");
+                continue;
+            }
+
+            try
+            {
+                var content = await File.ReadAllTextAsync(filePath, cancellationToken);
+                var newContent = LspProtocol.ApplyEdits(content, fileEdits);
+                if (!parameters.DryRun && newContent != content)
+                {
+                    await File.WriteAllTextAsync(filePath, newContent, cancellationToken);
+                    RecordWrite(filePath);
+                }
+
+                var lines = fileEdits.Select(e => e.Range.Start.Line + 1).Distinct().OrderBy(l => l).ToList();
+                changes.Add(new FileRefactorChange
+                {
+                    FilePath = filePath,
+                    ReplacementCount = fileEdits.Count,
+                    ChangePreview = parameters.DryRun
+                        ? $"{fileEdits.Count} occurrences of '{oldName}' → '{newName}' at lines: {string.Join(", ", lines)}"
+                        : null,
+                    Lines = lines
+                });
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                _logger.LogError(ex, "Failed to apply {Server} edits to {FilePath}", server, filePath);
+                errors.Add($"❌ {Path.GetFileName(filePath)}: {ex.Message}");
+            }
+        }
+
+        var result = new SmartRefactorResult
+        {
+            Success = errors.Count == 0 || changes.Count > 0,
+            Operation = "rename_symbol",
+            DryRun = parameters.DryRun,
+            Source = server,
+            FilesModified = changes.Select(c => c.FilePath).ToList(),
+            ChangesCount = changes.Sum(c => c.ReplacementCount),
+            Changes = changes,
+            Errors = errors
+        };
+
+        // Where the index's name-based matches and the server's semantic answer part ways
+        var indexed = references
+            .Select(r => (Path.GetFullPath(r.Identifier.FilePath), r.Identifier.StartLine))
+            .ToHashSet();
+        var renamed = edits
+            .SelectMany(f => f.Value.Select(e => (Path.GetFullPath(f.Key), e.Range.Start.Line + 1)))
+            .ToHashSet();
+        var missedByIndex = renamed.Count(l => !indexed.Contains(l));
+        var otherSymbols = indexed.Count(l => !renamed.Contains(l));
+        result.Notes.Add($"🎯 {server} computed the rename: {missedByIndex} location(s) the index missed, " +
+                         $"{otherSymbols} name match(es) left alone as other symbols or text");
+        if (definitions.Count > 1)
+        {
+            result.Notes.Add($"'{oldName}' is defined {definitions.Count} times; only the definition at " +
+                             $"{definition.FilePath}:{definition.StartLine} and its references were renamed");
+        }
+
+        if (parameters.DryRun)
+        {
+            result.NextActions.Add("Set dry_run=false to apply changes");
+        }
+        else
+        {
+            result.NextActions.Add("Run tests to verify changes");
+            result.NextActions.Add("Review git diff to inspect changes");
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Narrows the index's Go references to the identifiers go/types resolves to the first Go definition of
+    /// <paramref name="oldName"/>, adding any the index missed. References in other languages are kept.
+    /// Returns notes for the result, or null (references untouched) when there is no Go definition or no answer.
+    /// </summary>
+    private async Task<List<string>?> ValidateGoReferencesAsync(
+        string workspacePath,
+        string oldName,
+        List<ResolvedReference> references,
+        CancellationToken cancellationToken)
+    {
+        if (_goTypes is not { IsEnabled: true })
+            return null;
+
+        var definitions = (await _sqliteService.GetSymbolsByNameAsync(workspacePath, oldName, caseSensitive: true, cancellationToken))
+            .Where(s => s.Name == oldName && _goTypes.Handles(s.FilePath))
+            .ToList();
+        var definition = definitions.FirstOrDefault();
+        if (definition == null)
+            return null;
+
+        var position = await FindNamePositionAsync(workspacePath, definition, oldName, cancellationToken);
+        if (position == null)
+            return null;
+
+        GoReferenceResult? goReferences;
+        try
+        {
+            var line = position.Line + 1;
+            var byteColumn = await CreateSourcePositions(workspacePath).ToByteColumnAsync(definition.FilePath, line, position.Character, cancellationToken);
+            goReferences = await _goTypes.FindReferencesAsync(workspacePath, definition.FilePath, line, byteColumn, cancellationToken);
+        }
+        catch (Exception ex) when (ex is not OperationCanceledException)
+        {
+            _logger.LogWarning(ex, "go/types references for '{OldName}' failed, renaming from the index", oldName);
+            return null;
+        }
+        if (goReferences == null || goReferences.Locations.Count == 0)
+            return null;
+
+        var confirmed = goReferences.Locations
+            .GroupBy(l => (Path.GetFullPath(l.File), l.Offset))
+            .ToDictionary(g => g.Key, g => g.First());
+        var kept = new HashSet<(string, int)>();
+        var removed = references.RemoveAll(r =>
+        {
+            if (!_goTypes.Handles(r.Identifier.FilePath))
+                return false;
+            var key = (Path.GetFullPath(r.Identifier.FilePath), r.Identifier.StartByte ?? -1);
+            return !confirmed.ContainsKey(key) || !kept.Add(key);
+        });
+
+        var added = 0;
+        foreach (var (key, location) in confirmed)
+        {
+            if (kept.Contains(key))
+                continue;
+            references.Add(new ResolvedReference
+            {
+                Identifier = new JulieIdentifier
+                {
+                    Name = oldName,
+                    Kind = location.IsDefinition ? "definition" : "identifier",
+                    Language = "go",
+                    FilePath = location.File,
+                    StartLine = location.Line,
+                    StartColumn = location.Column,
+                    EndLine = location.Line,
+                    EndColumn = location.Column + location.Length,
+                    StartByte = location.Offset,
+                    EndByte = location.Offset + location.Length,
+                    CodeContext = location.LineText
+                }
+            });
+            added++;
+        }
+
+        _logger.LogInformation("🎯 go/types kept {Kept} Go references to '{OldName}', dropped {Removed}, added {Added}",
+            kept.Count, oldName, removed, added);
+
+        var notes = new List<string>
+        {
+            $"🎯 go/types resolved {goReferences.Kind} {goReferences.Symbol}: {added} location(s) the index missed, " +
+            $"{removed} Go name match(es) left alone as other symbols"
+        };
+        if (definitions.Count > 1)
+        {
+            notes.Add($"'{oldName}' is defined {definitions.Count} times in Go; only the definition at " +
+                      $"{definition.FilePath}:{definition.StartLine} and its references were renamed");
+        }
+        if (goReferences.TypeErrors > 0)
+        {
+            notes.Add($"{goReferences.TypeErrors} Go package(s) have type errors; references in them may be missing");
+        }
+        return notes;
+    }
+
+    /// <summary>
+    /// LSP position of the symbol's name on its declaration line; declarations start at modifiers or keywords
+    /// </summary>
+    private async Task<LspPosition?> FindNamePositionAsync(
+        string workspacePath,
+        JulieSymbol definition,
+        string name,
+        CancellationToken cancellationToken)
+    {
+        var text = await CreateSourcePositions(workspacePath).GetLineAsync(definition.FilePath, definition.StartLine, cancellationToken);
+        if (text == null)
+            return null;
+
+        var start = Math.Min(SourcePositions.ByteToUtf16Column(text, definition.StartColumn), text.Length);
+        var index = FindIdentifier(text, name, start);
+        if (index < 0)
+            index = FindIdentifier(text, name, 0);
+        return index < 0 ? null : new LspPosition(definition.StartLine - 1, index);
+    }
+
+    private static int FindIdentifier(string text, string name, int from)
+    {
+        for (var index = text.IndexOf(name, from, StringComparison.Ordinal); index >= 0; index = text.IndexOf(name, index + 1, StringComparison.Ordinal))
+        {
+            var end = index + name.Length;
+            if ((index == 0 || !UnicodeIdentifiers.IsIdentifierPart(text[index - 1]))
+                && (end >= text.Length || !UnicodeIdentifiers.IsIdentifierPart(text[end])))
+            {
+                return index;
+            }
+        }
+        return -1;
+    }
+
     /// <summary>
     /// Process renames for a single file using byte-offset replacement
     /// </summary>
@@ -293,6 +610,7 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         // Apply replacements from end to start
         var builder = new StringBuilder(content);
         var lines = new List<int>();
+        var utf8 = Encoding.UTF8.GetBytes(content);
 
         foreach (var reference in sortedRefs)
         {
@@ -307,14 +625,23 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
                 continue;
             }
 
-            var start = startByte.Value;
-            var end = endByte.Value;
-
             // Validate byte positions
-            if (start < 0 || end > content.Length || start >= end)
+            if (startByte.Value < 0 || endByte.Value > utf8.Length || startByte.Value >= endByte.Value)
             {
                 _logger.LogWarning("Invalid byte positions: {Start}-{End} in {File}",
-                    start, end, Path.GetFileName(filePath));
+                    startByte.Value, endByte.Value, Path.GetFileName(filePath));
+                continue;
+            }
+
+            // Offsets are UTF-8 bytes; the builder is indexed in UTF-16 chars
+            var start = UnicodeIdentifiers.CharIndexOfUtf8Offset(utf8, startByte.Value);
+            var end = UnicodeIdentifiers.CharIndexOfUtf8Offset(utf8, endByte.Value);
+
+            // Stale offsets (file edited since indexing) must not clobber unrelated text
+            if (!UnicodeIdentifiers.EqualsIgnoreCase(content[start..end], oldName))
+            {
+                _logger.LogWarning("Text at {Start}-{End} in {File} is no longer '{OldName}', skipping",
+                    startByte.Value, endByte.Value, Path.GetFileName(filePath), oldName);
                 continue;
             }
 
@@ -331,6 +658,7 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         if (!dryRun && newContent != originalContent)
         {
             await File.WriteAllTextAsync(filePath, newContent, cancellationToken);
+            RecordWrite(filePath);
             _logger.LogInformation("✅ Updated {FilePath}: {Count} changes",
                 Path.GetFileName(filePath), sortedRefs.Count);
         }
@@ -501,6 +829,7 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
             }
 
             await File.WriteAllTextAsync(targetFile, targetContent.ToString(), cancellationToken);
+            RecordWrite(targetFile);
             _logger.LogInformation("✅ Created {File} with {Kind} {Name}",
                 Path.GetFileName(targetFile), symbolDef.Kind, symbolName);
         }
@@ -712,11 +1041,13 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
 
             // Write target file
             await File.WriteAllTextAsync(targetFile, targetContent.ToString(), cancellationToken);
+            RecordWrite(targetFile);
             _logger.LogInformation("✅ Created {File} with {Kind} {Name}",
                 Path.GetFileName(targetFile), symbolDef.Kind, symbolName);
 
             // Update source file (remove symbol)
             await File.WriteAllTextAsync(sourceFile, modifiedSourceContent, cancellationToken);
+            RecordWrite(sourceFile);
             _logger.LogInformation("✅ Removed {Kind} {Name} from {File}",
                 symbolDef.Kind, symbolName, Path.GetFileName(sourceFile));
 
@@ -955,6 +1286,7 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
             }
 
             await File.WriteAllTextAsync(targetFile, interfaceContent.ToString(), cancellationToken);
+            RecordWrite(targetFile);
             _logger.LogInformation("✅ Created interface {Interface} at {File}",
                 interfaceName, Path.GetFileName(targetFile));
         }
@@ -996,6 +1328,413 @@ public class SmartRefactorTool : CodeSearchToolBase<SmartRefactorParameters, AIO
         return result;
     }
 
+    /// <summary>
+    /// Handle fix namespaces operation - renames namespace and package declarations that do not match their
+    /// directories (see check_namespaces) and moves the using directives, imports and qualified names referring to them
+    /// </summary>
+    private async Task<SmartRefactorResult> HandleFixNamespacesAsync(
+        SmartRefactorParameters parameters,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        // Parse operation parameters - both optional
+        var paramsDoc = JsonDocument.Parse(parameters.Params);
+        var filePath = paramsDoc.RootElement.TryGetProperty("file_path", out var fileProp) ? fileProp.GetString() : null;
+        var language = paramsDoc.RootElement.TryGetProperty("language", out var languageProp) ? languageProp.GetString() : null;
+        if (!string.IsNullOrWhiteSpace(filePath))
+        {
+            filePath = (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+        }
+
+        var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Select(f => (Path: (Path.IsPathRooted(f.Path) ? Path.GetRelativePath(workspacePath, f.Path) : f.Path).Replace('\\', '/'), f.Content))
+            .ToList();
+        var violations = NamespaceConventions.Check(files, _namespaceConventions)
+            .Where(v => string.IsNullOrWhiteSpace(filePath) || v.FilePath == filePath)
+            .Where(v => string.IsNullOrWhiteSpace(language) || v.Language.Equals(language, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+
+        if (violations.Count == 0)
+        {
+            return new SmartRefactorResult
+            {
+                Success = true,
+                Operation = "fix_namespaces",
+                DryRun = parameters.DryRun,
+                NextActions = new List<string>
+                {
+                    string.IsNullOrWhiteSpace(filePath) ? "Every declaration already matches its directory" : $"{filePath} already matches its directory"
+                }
+            };
+        }
+
+        _logger.LogInformation("🗂️ Fixing {Count} namespace/package declarations", violations.Count);
+
+        // Edits come from the indexed content; a file changed on disk since indexing is left alone
+        var changes = new List<FileRefactorChange>();
+        var errors = new List<string>();
+        var totalChanges = 0;
+        foreach (var fileEdits in NamespaceConventions.Fix(files, violations).GroupBy(e => e.FilePath))
+        {
+            if (changes.Count >= parameters.MaxFiles)
+            {
+                errors.Add($"Reached max files limit ({parameters.MaxFiles}). Stopping.");
+                break;
+            }
+
+            var absolutePath = Path.Combine(workspacePath, fileEdits.Key);
+            try
+            {
+                var content = await File.ReadAllTextAsync(absolutePath, cancellationToken);
+                var newline = content.Contains("\r\n") ? "\r\n" : "\n";
+                var lines = content.Replace("\r\n", "\n").Split('\n');
+                var stale = fileEdits.FirstOrDefault(e => e.Line > lines.Length || lines[e.Line - 1] != e.OldText);
+                if (stale != null)
+                {
+                    errors.Add($"❌ {fileEdits.Key}: line {stale.Line} changed since indexing - reindex and try again");
+                    continue;
+                }
+
+                foreach (var edit in fileEdits)
+                {
+                    lines[edit.Line - 1] = edit.NewText;
+                }
+                if (!parameters.DryRun)
+                {
+                    await File.WriteAllTextAsync(absolutePath, string.Join("\n", lines).Replace("\n", newline), cancellationToken);
+                    RecordWrite(absolutePath);
+                }
+
+                changes.Add(new FileRefactorChange
+                {
+                    FilePath = absolutePath,
+                    ReplacementCount = fileEdits.Count(),
+                    ChangePreview = parameters.DryRun
+                        ? string.Join("; ", fileEdits.Select(e => $"line {e.Line}: {e.OldText.Trim()} → {e.NewText.Trim().Replace("\n", " + ")}"))
+                        : null,
+                    Lines = fileEdits.Select(e => e.Line).ToList()
+                });
+                totalChanges += fileEdits.Count();
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogError(ex, "Failed to process file: {FilePath}", absolutePath);
+                errors.Add($"❌ {fileEdits.Key}: {ex.Message}");
+            }
+        }
+
+        var result = new SmartRefactorResult
+        {
+            Success = errors.Count == 0 || totalChanges > 0,
+            Operation = "fix_namespaces",
+            DryRun = parameters.DryRun,
+            FilesModified = changes.Select(c => c.FilePath).ToList(),
+            ChangesCount = totalChanges,
+            Changes = changes,
+            Errors = errors,
+            Notes = violations.Select(v => $"{v.FilePath}:{v.Line} {v.Declared} → {v.Expected} ({v.Convention})").ToList()
+        };
+
+        if (parameters.DryRun)
+        {
+            result.NextActions.Add("Set dry_run=false to apply changes");
+        }
+        else
+        {
+            result.NextActions.Add("Build to verify - references through aliases and reflection are not updated");
+            result.NextActions.Add("Run check_namespaces to confirm nothing is left");
+            result.NextActions.Add("Review git diff to inspect changes");
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Handle fix naming operation - renames the symbols breaking naming rules (see check_naming) to their suggested
+    /// names, each through rename_symbol so references follow
+    /// </summary>
+    private async Task<SmartRefactorResult> HandleFixNamingAsync(
+        SmartRefactorParameters parameters,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        // Parse operation parameters - all optional
+        var paramsDoc = JsonDocument.Parse(parameters.Params);
+        var ruleName = paramsDoc.RootElement.TryGetProperty("rule", out var ruleProp) ? ruleProp.GetString() : null;
+        var filePath = paramsDoc.RootElement.TryGetProperty("file_path", out var fileProp) ? fileProp.GetString() : null;
+        var language = paramsDoc.RootElement.TryGetProperty("language", out var languageProp) ? languageProp.GetString() : null;
+        if (!string.IsNullOrWhiteSpace(filePath))
+        {
+            filePath = (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+        }
+
+        var problems = NamingConventions.Validate(_namingConventions);
+        if (problems.Count > 0)
+        {
+            return new SmartRefactorResult
+            {
+                Success = false,
+                Operation = "fix_naming",
+                DryRun = parameters.DryRun,
+                Errors = problems,
+                NextActions = new List<string> { "Fix the rules under CodeSearch:NamingConventions:Rules" }
+            };
+        }
+
+        var rules = NamingConventions.RulesFor(_namingConventions)
+            .Where(r => string.IsNullOrWhiteSpace(ruleName) || r.Name.Equals(ruleName, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+        var tests = new HashSet<(string FilePath, string Name)>();
+        if (rules.Any(r => r.Tests != null))
+        {
+            var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+                .Select(f => (Path: (Path.IsPathRooted(f.Path) ? Path.GetRelativePath(workspacePath, f.Path) : f.Path).Replace('\\', '/'), f.Content));
+            tests = NamingConventions.TestsIn(files);
+        }
+        var symbols = await _sqliteService.GetAllSymbolsAsync(workspacePath, cancellationToken);
+        var violations = NamingConventions.Check(workspacePath, symbols, rules, tests, _namingConventions.Ignore)
+            .Where(v => string.IsNullOrWhiteSpace(filePath) || v.FilePath == filePath)
+            .Where(v => string.IsNullOrWhiteSpace(language) || v.Language.Equals(language, StringComparison.OrdinalIgnoreCase))
+            .ToList();
+
+        var result = new SmartRefactorResult
+        {
+            Success = true,
+            Operation = "fix_naming",
+            DryRun = parameters.DryRun
+        };
+        foreach (var violation in violations.Where(v => v.Suggested == null))
+        {
+            result.Notes.Add($"{violation.FilePath}:{violation.Line} {violation.Name}: no mechanical fix for {violation.Rule} - rename it with rename_symbol");
+        }
+
+        var renames = violations.Where(v => v.Suggested != null).GroupBy(v => v.Name).ToList();
+        if (renames.Count == 0)
+        {
+            result.NextActions.Add(violations.Count == 0 ? "Every name already follows the naming rules" : "Rename the names listed in notes with rename_symbol");
+            return result;
+        }
+
+        _logger.LogInformation("🔤 Fixing {Count} names breaking naming rules", renames.Count);
+
+        // Renames go one at a time through the index; a file already rewritten has stale offsets until it is reindexed
+        var touched = new HashSet<string>(StringComparer.OrdinalIgnoreCase);
+        var deferred = 0;
+        foreach (var rename in renames)
+        {
+            var oldName = rename.Key;
+            var suggestions = rename.Select(v => v.Suggested!).Distinct().ToList();
+            if (suggestions.Count > 1)
+            {
+                result.Notes.Add($"{oldName}: rules disagree ({string.Join(", ", suggestions)}) - rename it with rename_symbol");
+                continue;
+            }
+
+            var newName = suggestions[0];
+            if ((await _sqliteService.GetSymbolsByNameAsync(workspacePath, newName, caseSensitive: true, cancellationToken)).Any(s => s.Name == newName))
+            {
+                result.Notes.Add($"{oldName} → {newName}: {newName} is already declared - rename it by hand");
+                continue;
+            }
+            if (touched.Count >= parameters.MaxFiles)
+            {
+                result.Errors.Add($"Reached max files limit ({parameters.MaxFiles}). Stopping.");
+                break;
+            }
+            if (!parameters.DryRun)
+            {
+                var referencedIn = (await _referenceResolver.FindReferencesAsync(workspacePath, oldName, caseSensitive: false, cancellationToken))
+                    .Select(r => r.Identifier.FilePath);
+                if (referencedIn.Any(touched.Contains))
+                {
+                    deferred++;
+                    continue;
+                }
+            }
+
+            var renameParameters = new SmartRefactorParameters
+            {
+                Operation = "rename_symbol",
+                WorkspacePath = workspacePath,
+                Params = JsonSerializer.Serialize(new Dictionary<string, string> { ["old_name"] = oldName, ["new_name"] = newName }),
+                DryRun = parameters.DryRun,
+                MaxFiles = parameters.MaxFiles - touched.Count,
+                MaxTokens = parameters.MaxTokens
+            };
+            var renamed = await HandleRenameSymbolAsync(renameParameters, workspacePath, cancellationToken);
+            result.Changes.AddRange(renamed.Changes);
+            result.ChangesCount += renamed.ChangesCount;
+            result.Errors.AddRange(renamed.Errors.Select(e => $"{oldName}: {e}"));
+            touched.UnionWith(renamed.FilesModified);
+            result.Notes.Add($"{oldName} → {newName} ({rename.First().Rule}, {renamed.ChangesCount} occurrences)");
+        }
+
+        result.FilesModified = touched.OrderBy(f => f, StringComparer.Ordinal).ToList();
+        result.Success = result.Errors.Count == 0 || result.ChangesCount > 0;
+        if (deferred > 0)
+        {
+            result.Notes.Add($"{deferred} rename(s) share files with renames just applied - run fix_naming again once the index has caught up");
+        }
+
+        if (parameters.DryRun)
+        {
+            result.NextActions.Add("Set dry_run=false to apply changes");
+        }
+        else
+        {
+            result.NextActions.Add("Build and run tests to verify - names used through reflection, serialization or strings are not updated");
+            result.NextActions.Add("Run check_naming to confirm nothing is left");
+            result.NextActions.Add("Review git diff to inspect changes");
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Handle rename module operation - renames a Go module path or C# root namespace across the workspace (see
+    /// <see cref="ModuleRename"/>): go.mod, imports, using directives, namespace declarations, project files and
+    /// configuration, previewed as one unified diff. Every file is read before any is written, so a failure leaves
+    /// the workspace untouched.
+    /// </summary>
+    private async Task<SmartRefactorResult> HandleRenameModuleAsync(
+        SmartRefactorParameters parameters,
+        string workspacePath,
+        CancellationToken cancellationToken)
+    {
+        // Parse operation parameters
+        var paramsDoc = JsonDocument.Parse(parameters.Params);
+        var oldName = paramsDoc.RootElement.TryGetProperty("old_name", out var oldProp)
+            ? oldProp.GetString()?.Trim()
+            : throw new ArgumentException("Missing required parameter: old_name");
+        var newName = paramsDoc.RootElement.TryGetProperty("new_name", out var newProp)
+            ? newProp.GetString()?.Trim()
+            : throw new ArgumentException("Missing required parameter: new_name");
+
+        if (string.IsNullOrWhiteSpace(oldName) || string.IsNullOrWhiteSpace(newName))
+        {
+            throw new ArgumentException("old_name and new_name cannot be empty");
+        }
+        if (oldName == newName)
+        {
+            throw new ArgumentException("old_name and new_name are the same");
+        }
+
+        _logger.LogInformation("📦 Rename module '{OldName}' → '{NewName}'", oldName, newName);
+
+        var files = (await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+            .Select(f => (Path: (Path.IsPathRooted(f.Path) ? Path.GetRelativePath(workspacePath, f.Path) : f.Path).Replace('\\', '/'), f.Content))
+            .Where(f => ModuleRename.Handles(f.Path))
+            .OrderBy(f => f.Path, StringComparer.Ordinal)
+            .ToList();
+
+        var renamed = new List<(string AbsolutePath, string RelativePath, string Content, List<ImportRewrite> Rewrites)>();
+        var diff = new StringBuilder();
+        var errors = new List<string>();
+        foreach (var file in files)
+        {
+            var absolutePath = Path.Combine(workspacePath, file.Path);
+            if (!File.Exists(absolutePath))
+                continue;
+
+            try
+            {
+                var content = await File.ReadAllTextAsync(absolutePath, cancellationToken);
+                var (newContent, rewrites) = ModuleRename.Rename(file.Path, content, oldName, newName);
+                if (rewrites.Count == 0)
+                    continue;
+
+                renamed.Add((absolutePath, file.Path, newContent, rewrites));
+                diff.Append(LineDiff.Unified(content, newContent, file.Path, 1).Diff);
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                _logger.LogError(ex, "Failed to process file: {FilePath}", absolutePath);
+                errors.Add($"❌ {file.Path}: {ex.Message}");
+            }
+        }
+
+        var result = new SmartRefactorResult
+        {
+            Success = errors.Count == 0,
+            Operation = "rename_module",
+            DryRun = parameters.DryRun,
+            Errors = errors
+        };
+
+        if (renamed.Count == 0)
+        {
+            result.NextActions.Add($"Nothing in the indexed files names {oldName} - check the spelling, or reindex if files were added since");
+            return result;
+        }
+        if (renamed.Count > parameters.MaxFiles)
+        {
+            result.Success = false;
+            result.Errors.Add($"{renamed.Count} files would change, more than the max files limit ({parameters.MaxFiles}). Nothing was written.");
+            result.NextActions.Add($"Run again with max_files of at least {renamed.Count}");
+            return result;
+        }
+        if (errors.Count > 0 && !parameters.DryRun)
+        {
+            result.Errors.Add("Nothing was written - fix the files above and run again");
+            return result;
+        }
+
+        foreach (var (absolutePath, relativePath, content, rewrites) in renamed)
+        {
+            if (!parameters.DryRun)
+            {
+                await File.WriteAllTextAsync(absolutePath, content, cancellationToken);
+                RecordWrite(absolutePath);
+            }
+
+            result.Changes.Add(new FileRefactorChange
+            {
+                FilePath = absolutePath,
+                ReplacementCount = rewrites.Count,
+                ChangePreview = parameters.DryRun
+                    ? string.Join("; ", rewrites.Select(r => $"line {r.Line} ({r.Kind}): {r.OldText.Trim()} → {r.NewText.Trim()}"))
+                    : null,
+                Lines = rewrites.Select(r => r.Line).ToList()
+            });
+        }
+
+        var all = renamed.SelectMany(r => r.Rewrites).ToList();
+        result.FilesModified = renamed.Select(r => r.AbsolutePath).ToList();
+        result.ChangesCount = all.Count;
+        result.Diff = diff.ToString();
+        result.Notes.Add(string.Join(", ", all.GroupBy(r => r.Kind).OrderByDescending(g => g.Count()).Select(g => $"{g.Count()} {g.Key}")));
+
+        if (!all.Any(r => r.Kind is "go-module" or "namespace" or "root-namespace"))
+        {
+            result.Notes.Add($"Nothing declares {oldName} - only references to it were renamed");
+        }
+        var aliased = all.Count(r => r.Note != null);
+        if (aliased > 0)
+        {
+            result.Notes.Add($"{aliased} Go import(s) were aliased with the old package name because the last path element changed");
+        }
+        var misnamed = renamed
+            .Select(r => r.RelativePath)
+            .Where(p => Path.GetExtension(p) is ".csproj" or ".fsproj" or ".vbproj" && p.Split('/').Any(s => s == oldName || s.StartsWith(oldName + ".", StringComparison.Ordinal)))
+            .ToList();
+        if (misnamed.Count > 0)
+        {
+            result.Notes.Add($"Project folders and files still carry the old name ({string.Join(", ", misnamed)}) - rename them to match the rewritten ProjectReference paths");
+        }
+
+        if (parameters.DryRun)
+        {
+            result.NextActions.Add("Set dry_run=false to apply changes");
+        }
+        else
+        {
+            result.NextActions.Add("Build to verify - names built at runtime from strings are not updated");
+            result.NextActions.Add("Review git diff to inspect changes");
+        }
+
+        return result;
+    }
+
     /// <summary>
     /// Extract public member signatures from class code
     /// </summary>
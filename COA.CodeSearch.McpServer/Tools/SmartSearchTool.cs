@@ -0,0 +1,221 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Lucene;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Natural-language search: decomposes a request into a plan of identifier and full-text queries,
+/// runs them, and merges the hits per file so files covering more of the request rank first
+/// </summary>
+public class SmartSearchTool : CodeSearchToolBase<SmartSearchParameters, AIOptimizedResponse<SmartSearchResult>>
+{
+    private const int HitsPerStep = 50;
+
+    private readonly ILuceneIndexService _luceneIndexService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<SmartSearchTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the SmartSearchTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="luceneIndexService">Lucene index service for executing the plan</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public SmartSearchTool(
+        IServiceProvider serviceProvider,
+        ILuceneIndexService luceneIndexService,
+        IPathResolutionService pathResolutionService,
+        ILogger<SmartSearchTool> logger) : base(serviceProvider, logger)
+    {
+        _luceneIndexService = luceneIndexService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SmartSearch;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "SEARCH IN PLAIN LANGUAGE - Describe what you're looking for ('where users are deactivated and cached'); the request is split into " +
+        "identifier and full-text queries with programming synonyms, executed, and merged per file. The plan is returned so you can see and reuse the queries.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Plans and executes the search.
+    /// </summary>
+    /// <param name="parameters">Natural-language request and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Merged results with the plan</returns>
+    protected override async Task<AIOptimizedResponse<SmartSearchResult>> ExecuteInternalAsync(
+        SmartSearchParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var request = ValidateRequired(parameters.Request, nameof(parameters.Request));
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _luceneIndexService.IndexExistsAsync(workspacePath, cancellationToken))
+        {
+            return CreateErrorResponse("INDEX_NOT_FOUND", $"No index found for workspace: {workspacePath}",
+                "Run index_workspace first", "Then retry smart_search");
+        }
+
+        var plan = SmartSearchPlanner.Plan(request, parameters.ExpandSynonyms);
+        if (plan.Steps.Count == 0)
+        {
+            return CreateErrorResponse("NO_SEARCHABLE_TERMS", $"'{request}' has no searchable words after removing filler words",
+                "Name the domain concepts or actions, e.g. 'user deactivation' or 'payment retry'",
+                "Use text_search for exact identifiers");
+        }
+
+        var files = new Dictionary<string, MergedFile>(StringComparer.OrdinalIgnoreCase);
+        foreach (var step in plan.Steps)
+        {
+            var query = SmartSearchPlanner.ToLuceneQuery(step, plan.Clauses[step.Clause]);
+            SearchResult stepResult;
+            try
+            {
+                stepResult = await _luceneIndexService.SearchAsync(workspacePath, query, HitsPerStep, true, cancellationToken);
+            }
+            catch (Exception ex) when (ex is not OperationCanceledException)
+            {
+                // Prefix queries can exceed the boolean clause limit on huge indexes - skip the step, keep the rest
+                _logger.LogDebug(ex, "smart_search step {Step} failed: {Query}", step.Id, step.Query);
+                step.HitCount = 0;
+                continue;
+            }
+
+            step.HitCount = stepResult.TotalHits;
+            var topScore = stepResult.Hits.Count > 0 ? stepResult.Hits.Max(h => h.Score) : 0f;
+            foreach (var hit in stepResult.Hits)
+            {
+                if (!files.TryGetValue(hit.FilePath, out var file))
+                {
+                    file = new MergedFile(hit);
+                    files[hit.FilePath] = file;
+                }
+
+                var contribution = step.Weight * (topScore > 0 ? hit.Score / topScore : 1.0);
+                file.Score += contribution;
+                file.Steps.Add(step.Id);
+                file.Clauses.Add(step.Clause);
+                if (contribution > file.BestContribution && (hit.Snippet != null || hit.LineNumber != null))
+                {
+                    file.BestContribution = contribution;
+                    file.Best = hit;
+                }
+            }
+        }
+
+        var result = new SmartSearchResult
+        {
+            Request = request,
+            Plan = plan,
+            TotalFiles = files.Count,
+            Results = files.Values
+                .OrderByDescending(f => f.Clauses.Count)
+                .ThenByDescending(f => f.Score)
+                .ThenBy(f => f.Best.FilePath, StringComparer.Ordinal)
+                .Take(parameters.MaxResults)
+                .Select(f => new SmartSearchHit
+                {
+                    FilePath = ToRelative(workspacePath, f.Best.FilePath),
+                    Score = Math.Round(f.Score, 3),
+                    ClausesMatched = f.Clauses.Count,
+                    MatchedSteps = f.Steps.OrderBy(s => s).ToList(),
+                    LineNumber = f.Best.LineNumber,
+                    Snippet = f.Best.Snippet,
+                    Summary = f.Best.Summary
+                })
+                .ToList()
+        };
+
+        var fullMatches = result.Results.Count(r => r.ClausesMatched == plan.Clauses.Count);
+        var response = new AIOptimizedResponse<SmartSearchResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SmartSearchResult> { Results = result },
+            Message = files.Count == 0
+                ? $"No matches for '{request}' ({plan.Steps.Count} queries)"
+                : plan.Clauses.Count > 1
+                    ? $"{files.Count} file(s) from {plan.Steps.Count} queries; {fullMatches} of the top {result.Results.Count} match all {plan.Clauses.Count} parts"
+                    : $"{files.Count} file(s) from {plan.Steps.Count} queries"
+        };
+
+        var insights = new List<string>();
+        foreach (var (clause, index) in plan.Clauses.Select((c, i) => (c, i)))
+        {
+            if (plan.Steps.Where(s => s.Clause == index).All(s => s.HitCount == 0))
+            {
+                insights.Add($"Nothing matched '{clause.Text}' - try other words for it");
+            }
+            if (clause.Inherited != null)
+            {
+                insights.Add($"'{clause.Text}' was read as '{clause.Inherited} {clause.Text}'");
+            }
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        if (files.Count == 0)
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction
+                {
+                    Action = ToolNames.TextSearch,
+                    Description = "Search for a specific identifier or phrase with searchMode 'semantic' or 'fuzzy'",
+                    Priority = 70
+                }
+            };
+        }
+
+        return response;
+    }
+
+    private static string ToRelative(string workspacePath, string path) =>
+        Path.IsPathRooted(path) ? Path.GetRelativePath(workspacePath, path) : path;
+
+    private static AIOptimizedResponse<SmartSearchResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+
+    private sealed class MergedFile
+    {
+        public MergedFile(SearchHit first)
+        {
+            Best = first;
+        }
+
+        public SearchHit Best { get; set; }
+        public double BestContribution { get; set; }
+        public double Score { get; set; }
+        public HashSet<int> Steps { get; } = new();
+        public HashSet<int> Clauses { get; } = new();
+    }
+}
@@ -0,0 +1,327 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Compares two index snapshots (or two git refs) at the symbol level: files added and removed,
+/// symbols added, removed and renamed, and how each language's size changed
+/// </summary>
+public class SnapshotDiffTool : CodeSearchToolBase<SnapshotDiffParameters, AIOptimizedResponse<SnapshotDiffResult>>
+{
+    private const string Current = "current";
+    private const int MaxListedFiles = 100;
+
+    private readonly IIndexSnapshotService _snapshotService;
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<SnapshotDiffTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the SnapshotDiffTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="snapshotService">Index snapshot storage</param>
+    /// <param name="gitService">Git service for comparing refs</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public SnapshotDiffTool(
+        IServiceProvider serviceProvider,
+        IIndexSnapshotService snapshotService,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        ILogger<SnapshotDiffTool> logger) : base(serviceProvider, logger)
+    {
+        _snapshotService = snapshotService;
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SnapshotDiff;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT CHANGED OVER TIME - Compare two index snapshots (saveAs to take one) or two git refs (mode='git') at the symbol level: " +
+        "files added/removed, symbols added/removed/renamed with confidence, and per-language size trends.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Executes the snapshot comparison.
+    /// </summary>
+    /// <param name="parameters">Snapshots or refs to compare</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Files, symbols and language trends that changed</returns>
+    protected override async Task<AIOptimizedResponse<SnapshotDiffResult>> ExecuteInternalAsync(
+        SnapshotDiffParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        var gitMode = parameters.Mode.Equals("git", StringComparison.OrdinalIgnoreCase);
+
+        var result = new SnapshotDiffResult
+        {
+            From = parameters.From,
+            To = parameters.To,
+            Mode = gitMode ? "git" : "snapshot"
+        };
+
+        try
+        {
+            if (!string.IsNullOrWhiteSpace(parameters.SaveAs))
+            {
+                result.Saved = await _snapshotService.SaveAsync(workspacePath, parameters.SaveAs, cancellationToken);
+            }
+
+            if (string.IsNullOrWhiteSpace(parameters.From))
+            {
+                result.AvailableSnapshots = _snapshotService.List(workspacePath).ToList();
+                return new AIOptimizedResponse<SnapshotDiffResult>
+                {
+                    Success = true,
+                    Data = new AIResponseData<SnapshotDiffResult> { Results = result },
+                    Message = (result.Saved != null ? $"Saved snapshot '{result.Saved.Name}'. " : string.Empty) +
+                              $"{result.AvailableSnapshots.Count} snapshot(s) available - pass 'from' to compare"
+                };
+            }
+
+            if (gitMode)
+            {
+                await CompareRefsAsync(result, workspacePath, parameters.From, parameters.To, parameters.MaxFiles, cancellationToken);
+            }
+            else
+            {
+                var from = await LoadAsync(workspacePath, parameters.From, cancellationToken);
+                var to = await LoadAsync(workspacePath, parameters.To, cancellationToken);
+                if (from == null || to == null)
+                {
+                    var missing = from == null ? parameters.From : parameters.To;
+                    return CreateErrorResponse("SNAPSHOT_NOT_FOUND", $"No snapshot named '{missing}'",
+                        $"Available snapshots: {string.Join(", ", _snapshotService.List(workspacePath).Select(s => s.Name).DefaultIfEmpty("none"))}",
+                        "Save one with saveAs, or use 'current' for the live index");
+                }
+
+                CompareSnapshots(result, from, to);
+            }
+        }
+        catch (GitException ex)
+        {
+            return CreateErrorResponse("GIT_FAILED", ex.Message,
+                "Check that both refs exist (git fetch for remote branches)",
+                "Make sure the workspace is inside a git repository and git is on PATH");
+        }
+        catch (ArgumentException ex)
+        {
+            return CreateErrorResponse("INVALID_SNAPSHOT_NAME", ex.Message,
+                "Use letters, digits, '.', '_' and '-' in snapshot names (e.g. 'before-refactor', '2024-06-01')");
+        }
+        catch (InvalidOperationException ex)
+        {
+            return CreateErrorResponse("INDEX_NOT_FOUND", ex.Message,
+                "Run index_workspace to build the symbol database",
+                "Or use mode='git' to compare two refs without an index");
+        }
+
+        result.TotalSymbolChanges = result.SymbolChanges.Count;
+        result.SymbolSummary = result.SymbolChanges
+            .GroupBy(c => c.Change)
+            .ToDictionary(g => g.Key, g => g.Count());
+        result.SymbolChanges = result.SymbolChanges
+            .OrderBy(c => ChangeOrder(c.Change))
+            .ThenBy(c => c.FilePath, StringComparer.Ordinal)
+            .ThenBy(c => c.Line)
+            .Take(parameters.MaxSymbolChanges)
+            .ToList();
+
+        var response = new AIOptimizedResponse<SnapshotDiffResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SnapshotDiffResult> { Results = result },
+            Message = $"{result.From} → {result.To}: +{result.FilesAdded.Count}/-{result.FilesRemoved.Count} file(s), {result.FilesModified} modified; " +
+                      string.Join(", ", result.SymbolSummary.Select(s => $"{s.Value} {s.Key}").DefaultIfEmpty("no symbol changes"))
+        };
+
+        var insights = new List<string>();
+        var growth = result.LanguageTrends.Where(t => t.BytesChange != 0).Take(3).ToList();
+        if (growth.Count > 0)
+        {
+            insights.Add("Largest size changes: " + string.Join(", ", growth.Select(t =>
+                $"{t.Language} {(t.BytesChange > 0 ? "+" : "")}{t.BytesChange / 1024.0:F1} KB" +
+                (t.PercentChange.HasValue ? $" ({(t.PercentChange > 0 ? "+" : "")}{t.PercentChange}%)" : " (new)"))));
+        }
+        if (result.TotalSymbolChanges > result.SymbolChanges.Count)
+        {
+            insights.Add($"Showing {result.SymbolChanges.Count} of {result.TotalSymbolChanges} symbol changes - renames and removals first");
+        }
+        if (result.FilesAdded.Count >= MaxListedFiles || result.FilesRemoved.Count >= MaxListedFiles)
+        {
+            insights.Add($"File lists are capped at {MaxListedFiles} entries");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        _logger.LogDebug("Compared {From} and {To} ({Mode}): {Changes} symbol changes",
+            result.From, result.To, result.Mode, result.TotalSymbolChanges);
+        return response;
+    }
+
+    /// <summary>
+    /// Per-language totals on both sides, ordered by absolute size change
+    /// </summary>
+    public static List<LanguageTrend> BuildLanguageTrends(
+        IEnumerable<(string Language, long Bytes, int? Lines)> from,
+        IEnumerable<(string Language, long Bytes, int? Lines)> to)
+    {
+        var trends = new Dictionary<string, LanguageTrend>(StringComparer.OrdinalIgnoreCase);
+        LanguageTrend Get(string language) =>
+            trends.TryGetValue(language, out var trend) ? trend : trends[language] = new LanguageTrend { Language = language };
+
+        foreach (var (language, bytes, lines) in from)
+        {
+            var trend = Get(language);
+            trend.FromFiles++;
+            trend.FromBytes += bytes;
+            if (lines.HasValue)
+                trend.FromLines = (trend.FromLines ?? 0) + lines.Value;
+        }
+        foreach (var (language, bytes, lines) in to)
+        {
+            var trend = Get(language);
+            trend.ToFiles++;
+            trend.ToBytes += bytes;
+            if (lines.HasValue)
+                trend.ToLines = (trend.ToLines ?? 0) + lines.Value;
+        }
+
+        return trends.Values
+            .OrderByDescending(t => Math.Abs(t.BytesChange))
+            .ThenByDescending(t => t.ToBytes)
+            .ToList();
+    }
+
+    private static void CompareSnapshots(SnapshotDiffResult result, IndexSnapshot from, IndexSnapshot to)
+    {
+        var files = new List<(string? OldPath, IReadOnlyList<Declaration> Old, string NewPath, IReadOnlyList<Declaration> New)>();
+        var none = new List<Declaration>();
+
+        foreach (var (path, file) in from.Files)
+        {
+            if (!to.Files.TryGetValue(path, out var current))
+            {
+                result.FilesRemoved.Add(path);
+                files.Add((path, file.Declarations, path, none));
+            }
+            else if (current.ContentHash != file.ContentHash)
+            {
+                result.FilesModified++;
+                files.Add((path, file.Declarations, path, current.Declarations));
+            }
+        }
+        foreach (var (path, file) in to.Files.Where(f => !from.Files.ContainsKey(f.Key)))
+        {
+            result.FilesAdded.Add(path);
+            files.Add((null, none, path, file.Declarations));
+        }
+
+        result.SymbolChanges = SymbolDiffer.CompareFiles(files);
+        result.LanguageTrends = BuildLanguageTrends(
+            from.Files.Values.Select(f => (f.Language, f.SizeBytes, (int?)f.Lines)),
+            to.Files.Values.Select(f => (f.Language, f.SizeBytes, (int?)f.Lines)));
+        CapFileLists(result);
+    }
+
+    private async Task CompareRefsAsync(
+        SnapshotDiffResult result,
+        string workspacePath,
+        string fromRef,
+        string toRef,
+        int maxFiles,
+        CancellationToken cancellationToken)
+    {
+        if (toRef.Equals(Current, StringComparison.OrdinalIgnoreCase))
+            toRef = "HEAD";
+        result.To = toRef;
+
+        var diff = await _gitService.GetTreeDiffAsync(workspacePath, fromRef, toRef, cancellationToken);
+        foreach (var file in diff)
+        {
+            if (file.Status == "added")
+                result.FilesAdded.Add(file.Path);
+            else if (file.Status == "deleted")
+                result.FilesRemoved.Add(file.Path);
+            else
+                result.FilesModified++;
+        }
+
+        var (files, _) = await _gitService.ScanChangedFilesAsync(workspacePath, diff, fromRef, toRef, maxFiles, _logger, cancellationToken);
+        result.SymbolChanges = GitDeclarationReader.Compare(files);
+
+        // Sizes come from the trees; line counts would need every blob
+        var fromTree = await _gitService.ListFilesAsync(workspacePath, fromRef, cancellationToken);
+        var toTree = await _gitService.ListFilesAsync(workspacePath, toRef, cancellationToken);
+        result.LanguageTrends = BuildLanguageTrends(
+            fromTree.Select(f => (LanguageOf(f.Path), f.Size, (int?)null)),
+            toTree.Select(f => (LanguageOf(f.Path), f.Size, (int?)null)));
+        CapFileLists(result);
+    }
+
+    private async Task<IndexSnapshot?> LoadAsync(string workspacePath, string name, CancellationToken cancellationToken)
+    {
+        if (name.Equals(Current, StringComparison.OrdinalIgnoreCase))
+        {
+            var snapshot = await _snapshotService.CaptureAsync(workspacePath, cancellationToken);
+            snapshot.Name = Current;
+            return snapshot;
+        }
+
+        return await _snapshotService.LoadAsync(workspacePath, name, cancellationToken);
+    }
+
+    private static string LanguageOf(string path) =>
+        LanguageCapabilities.Find(Path.GetExtension(path))?.Name ?? "other";
+
+    private static void CapFileLists(SnapshotDiffResult result)
+    {
+        result.FilesAdded = result.FilesAdded.OrderBy(p => p, StringComparer.Ordinal).Take(MaxListedFiles).ToList();
+        result.FilesRemoved = result.FilesRemoved.OrderBy(p => p, StringComparer.Ordinal).Take(MaxListedFiles).ToList();
+    }
+
+    private static int ChangeOrder(string change) => change switch
+    {
+        "renamed" => 0,
+        "moved" => 1,
+        "removed" => 2,
+        "modified" => 3,
+        _ => 4
+    };
+
+    private static AIOptimizedResponse<SnapshotDiffResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
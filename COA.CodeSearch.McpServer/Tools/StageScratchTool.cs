@@ -0,0 +1,182 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Scratch;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Stages generated files and snippets in the scratch area, where they can be searched and diffed
+/// before commit_scratch writes them to the workspace
+/// </summary>
+public class StageScratchTool : CodeSearchToolBase<StageScratchParameters, AIOptimizedResponse<StageScratchResult>>
+{
+    private readonly IScratchWorkspaceService _scratch;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<StageScratchTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the StageScratchTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="scratch">Scratch area holding staged files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public StageScratchTool(
+        IServiceProvider serviceProvider,
+        IScratchWorkspaceService scratch,
+        IPathResolutionService pathResolutionService,
+        ILogger<StageScratchTool> logger) : base(serviceProvider, logger)
+    {
+        _scratch = scratch;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.StageScratch;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "STAGE GENERATED CODE - Put new files or snippets in a scratch area instead of the workspace (write, append, insert, replace, delete). " +
+        "Review with scratch_diff and search_scratch, then apply everything at once with commit_scratch. Nothing on disk changes until then.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Applies the staging operation.
+    /// </summary>
+    /// <param name="parameters">Operation, file and content</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The scratch area after the operation</returns>
+    protected override Task<AIOptimizedResponse<StageScratchResult>> ExecuteInternalAsync(
+        StageScratchParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        var operation = parameters.Operation.ToLowerInvariant();
+        var result = new StageScratchResult { Operation = operation };
+        string message;
+
+        try
+        {
+            switch (operation)
+            {
+                case "list":
+                    message = $"{_scratch.List(workspacePath).Count} file(s) staged";
+                    break;
+
+                case "clear":
+                    message = $"Dropped {_scratch.Clear(workspacePath)} staged file(s)";
+                    break;
+
+                case "unstage":
+                    result.FilePath = _scratch.ToRelativePath(workspacePath, RequireFilePath(parameters));
+                    message = _scratch.Unstage(workspacePath, result.FilePath)
+                        ? $"Unstaged {result.FilePath}"
+                        : $"{result.FilePath} was not staged";
+                    break;
+
+                case "delete":
+                    result.FilePath = _scratch.Stage(workspacePath, RequireFilePath(parameters), null, parameters.Note).FilePath;
+                    message = $"Staged deletion of {result.FilePath}";
+                    break;
+
+                case "write":
+                case "append":
+                case "insert":
+                case "replace":
+                    var filePath = RequireFilePath(parameters);
+                    var content = BuildContent(workspacePath, filePath, operation, parameters);
+                    var entry = _scratch.Stage(workspacePath, filePath, content, parameters.Note);
+                    result.FilePath = entry.FilePath;
+                    result.Lines = content.Length == 0 ? 0 : content.TrimEnd('\n', '\r').Split('\n').Length;
+                    message = $"Staged {entry.FilePath} ({operation}, {result.Lines} lines)";
+                    break;
+
+                default:
+                    return Task.FromResult(CreateErrorResponse("INVALID_OPERATION", $"Unknown operation '{parameters.Operation}'",
+                        "Use write, append, insert, replace, delete, unstage, clear or list"));
+            }
+        }
+        catch (ScratchException ex)
+        {
+            return Task.FromResult(CreateErrorResponse(ex.Code, ex.Message, "Fix the file path, line range or size and retry"));
+        }
+
+        result.StagedFiles = _scratch.List(workspacePath)
+            .Select(e => new StagedFileInfo
+            {
+                FilePath = e.FilePath,
+                Action = e.Content == null ? "delete" : "write",
+                Note = e.Note,
+                UpdatedAt = e.UpdatedAt
+            })
+            .ToList();
+
+        var response = new AIOptimizedResponse<StageScratchResult>
+        {
+            Success = true,
+            Data = new AIResponseData<StageScratchResult> { Results = result },
+            Message = message
+        };
+
+        if (result.StagedFiles.Count > 0)
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction { Action = ToolNames.ScratchDiff, Description = "Review staged changes against the workspace", Priority = 80 },
+                new AIAction { Action = ToolNames.CommitScratch, Description = $"Apply the {result.StagedFiles.Count} staged file(s)", Priority = 60 }
+            };
+        }
+
+        return Task.FromResult(response);
+    }
+
+    private string BuildContent(string workspacePath, string filePath, string operation, StageScratchParameters parameters)
+    {
+        var snippet = parameters.Content ?? string.Empty;
+        if (operation == "write")
+            return snippet;
+
+        var current = _scratch.GetEffectiveContent(workspacePath, filePath) ?? string.Empty;
+        if (operation == "append")
+        {
+            var separator = current.Length == 0 || current.EndsWith('\n') ? "" : current.Contains("\r\n") ? "\r\n" : "\n";
+            return current + separator + snippet;
+        }
+
+        if (parameters.StartLine == null)
+            throw new ScratchException("MISSING_START_LINE", $"{operation} needs startLine");
+
+        return ScratchWorkspaceService.ApplyLineEdit(current, operation, parameters.StartLine.Value, parameters.EndLine, snippet);
+    }
+
+    private static string RequireFilePath(StageScratchParameters parameters) =>
+        string.IsNullOrWhiteSpace(parameters.FilePath)
+            ? throw new ScratchException("MISSING_FILE_PATH", $"{parameters.Operation} needs filePath")
+            : parameters.FilePath;
+
+    private static AIOptimizedResponse<StageScratchResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
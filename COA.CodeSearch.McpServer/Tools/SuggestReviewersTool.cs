@@ -0,0 +1,383 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Git;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Suggests reviewers for a change from CODEOWNERS, blame of the changed lines and recent history
+/// </summary>
+public class SuggestReviewersTool : CodeSearchToolBase<SuggestReviewersParameters, AIOptimizedResponse<SuggestReviewersResult>>
+{
+    // Owners outrank familiarity: an owner's approval is usually required anyway
+    private const double OwnerWeight = 3.0;
+    private const double BlameWeight = 2.0;
+    private const double HistoryWeight = 1.0;
+    private const int HistoryCommitsPerFile = 20;
+
+    private static readonly Regex GitHubNoReply = new(@"^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$", RegexOptions.IgnoreCase | RegexOptions.Compiled);
+
+    private readonly IGitService _gitService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly Dictionary<string, string> _aliases;
+    private readonly ILogger<SuggestReviewersTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the SuggestReviewersTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="gitService">Git service for diff, blame and history</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="configuration">Configuration for email-to-handle aliases</param>
+    /// <param name="logger">Logger instance</param>
+    public SuggestReviewersTool(
+        IServiceProvider serviceProvider,
+        IGitService gitService,
+        IPathResolutionService pathResolutionService,
+        IConfiguration configuration,
+        ILogger<SuggestReviewersTool> logger) : base(serviceProvider, logger)
+    {
+        _gitService = gitService;
+        _pathResolutionService = pathResolutionService;
+        _aliases = new Dictionary<string, string>(
+            configuration.GetSection("CodeSearch:Git:ReviewerAliases").Get<Dictionary<string, string>>() ?? new Dictionary<string, string>(),
+            StringComparer.OrdinalIgnoreCase);
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.SuggestReviewers;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "PROPOSE REVIEWERS - Ranks reviewers for baseRef..headRef from CODEOWNERS, blame of the changed lines and recent history, " +
+        "with per-file and per-hunk experts and the owners whose approval may be required. Authors of the change are excluded.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Executes the reviewer suggestion.
+    /// </summary>
+    /// <param name="parameters">Base and head refs, exclusions and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Ranked reviewers with per-file ownership</returns>
+    protected override async Task<AIOptimizedResponse<SuggestReviewersResult>> ExecuteInternalAsync(
+        SuggestReviewersParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!await _gitService.IsRepositoryAsync(workspacePath, cancellationToken))
+        {
+            return CreateGitErrorResponse("NOT_A_GIT_REPOSITORY", $"{workspacePath} is not inside a git repository (or git is not installed)");
+        }
+
+        var headRef = string.IsNullOrWhiteSpace(parameters.HeadRef) || parameters.HeadRef.Equals("worktree", StringComparison.OrdinalIgnoreCase)
+            ? null
+            : parameters.HeadRef;
+
+        List<GitFileDiff> diff;
+        string repositoryRoot;
+        List<CommitAuthor> changeAuthors;
+        try
+        {
+            diff = await _gitService.GetDiffAsync(workspacePath, parameters.BaseRef, headRef, cancellationToken);
+            repositoryRoot = await _gitService.GetRepositoryRootAsync(workspacePath, cancellationToken);
+            changeAuthors = headRef == null
+                ? new List<CommitAuthor>()
+                : await _gitService.GetCommitAuthorsAsync(workspacePath, headRef, parameters.BaseRef, maxCommits: 200, cancellationToken: cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            return CreateGitErrorResponse("GIT_FAILED", ex.Message);
+        }
+
+        var codeOwners = CodeOwners.Load(repositoryRoot);
+        var result = new SuggestReviewersResult
+        {
+            BaseRef = parameters.BaseRef,
+            HeadRef = headRef ?? "worktree",
+            CodeOwnersFile = codeOwners?.SourcePath,
+            ExcludedAuthors = changeAuthors.Select(a => Resolve(a.Email)).Distinct(StringComparer.OrdinalIgnoreCase).ToList()
+        };
+
+        var excluded = new HashSet<string>(StringComparer.OrdinalIgnoreCase);
+        foreach (var author in changeAuthors)
+        {
+            excluded.Add(author.Email);
+            excluded.Add(author.Name);
+            excluded.Add(Resolve(author.Email));
+        }
+        foreach (var reviewer in parameters.ExcludeReviewers ?? new List<string>())
+        {
+            excluded.Add(reviewer.Trim());
+        }
+
+        var candidates = new Dictionary<string, Candidate>(StringComparer.OrdinalIgnoreCase);
+        Candidate GetCandidate(string key, string? name)
+        {
+            if (!candidates.TryGetValue(key, out var candidate))
+            {
+                candidate = new Candidate { Key = key };
+                candidates[key] = candidate;
+            }
+            candidate.Name ??= name;
+            return candidate;
+        }
+
+        foreach (var file in diff.Take(parameters.MaxFiles))
+        {
+            var fullPath = Path.GetFullPath(Path.Combine(workspacePath, file.Path));
+            var repositoryPath = Path.GetRelativePath(repositoryRoot, fullPath).Replace('\\', '/');
+            var fileReviewers = new FileReviewers { Path = file.Path };
+
+            // Ownership
+            var owners = codeOwners?.GetOwners(repositoryPath) ?? Array.Empty<string>();
+            fileReviewers.Owners = owners.ToList();
+            if (owners.Count == 0)
+            {
+                result.UnownedFiles.Add(file.Path);
+            }
+            foreach (var owner in owners)
+            {
+                var candidate = GetCandidate(owner, null);
+                candidate.IsCodeOwner = true;
+                candidate.OwnedFiles++;
+                candidate.Score += OwnerWeight;
+                candidate.Files.Add(file.Path);
+            }
+
+            // Authors of the lines being changed, blamed at the base
+            var expertScores = new Dictionary<string, double>(StringComparer.OrdinalIgnoreCase);
+            if (file.Status != "added" && !file.IsBinary)
+            {
+                var blame = await TryBlameAsync(workspacePath, parameters.BaseRef, file, cancellationToken);
+                var weightedTotal = blame.Sum(b => RecencyWeight(b.AuthorTime));
+
+                foreach (var group in blame.GroupBy(b => Resolve(b.AuthorEmail), StringComparer.OrdinalIgnoreCase))
+                {
+                    var share = group.Sum(b => RecencyWeight(b.AuthorTime)) / Math.Max(1, weightedTotal);
+                    var candidate = GetCandidate(group.Key, group.First().AuthorName);
+                    candidate.Lines += group.Count();
+                    candidate.Score += BlameWeight * share;
+                    candidate.Files.Add(file.Path);
+                    expertScores[group.Key] = expertScores.GetValueOrDefault(group.Key) + BlameWeight * share;
+                }
+
+                foreach (var hunk in file.Hunks)
+                {
+                    var (start, end) = GetBlameRange(hunk);
+                    var hunkExperts = blame
+                        .Where(b => b.Line >= start && b.Line <= end)
+                        .GroupBy(b => Resolve(b.AuthorEmail), StringComparer.OrdinalIgnoreCase)
+                        .Where(g => !excluded.Contains(g.Key))
+                        .OrderByDescending(g => g.Count())
+                        .Take(2)
+                        .Select(g => g.Key)
+                        .ToList();
+
+                    if (hunkExperts.Count > 0)
+                    {
+                        fileReviewers.Hunks.Add(new HunkReviewers
+                        {
+                            Location = hunk.Context ?? $"lines {hunk.NewStart}-{hunk.NewEnd}",
+                            Experts = hunkExperts
+                        });
+                    }
+                }
+            }
+
+            // Recent committers to the file (or its directory for new files)
+            var historyPath = file.Status == "added" ? Path.GetDirectoryName(file.Path)?.Replace('\\', '/') : file.OldPath ?? file.Path;
+            if (!string.IsNullOrEmpty(historyPath))
+            {
+                var history = await TryGetHistoryAsync(workspacePath, parameters.BaseRef, historyPath, cancellationToken);
+                foreach (var group in history.GroupBy(a => Resolve(a.Email), StringComparer.OrdinalIgnoreCase))
+                {
+                    var share = (double)group.Count() / history.Count;
+                    var candidate = GetCandidate(group.Key, group.First().Name);
+                    candidate.Commits += group.Count();
+                    candidate.Score += HistoryWeight * share;
+                    candidate.Files.Add(file.Path);
+                    expertScores[group.Key] = expertScores.GetValueOrDefault(group.Key) + HistoryWeight * share;
+                }
+            }
+
+            fileReviewers.Experts = expertScores
+                .Where(e => !excluded.Contains(e.Key))
+                .OrderByDescending(e => e.Value)
+                .Take(3)
+                .Select(e => e.Key)
+                .ToList();
+            result.Files.Add(fileReviewers);
+        }
+
+        result.RequiredOwners = result.Files.SelectMany(f => f.Owners).Distinct(StringComparer.OrdinalIgnoreCase).ToList();
+        result.Suggested = candidates.Values
+            .Where(c => !excluded.Contains(c.Key) && (c.Name == null || !excluded.Contains(c.Name)))
+            .OrderByDescending(c => c.Score)
+            .ThenByDescending(c => c.Files.Count)
+            .Take(parameters.MaxReviewers)
+            .Select(c => new SuggestedReviewer
+            {
+                Reviewer = c.Key,
+                Name = c.Name,
+                Score = Math.Round(c.Score, 2),
+                IsCodeOwner = c.IsCodeOwner,
+                FilesCovered = c.Files.Count,
+                Reasons = c.GetReasons()
+            })
+            .ToList();
+
+        var response = new AIOptimizedResponse<SuggestReviewersResult>
+        {
+            Success = true,
+            Data = new AIResponseData<SuggestReviewersResult> { Results = result },
+            Message = result.Suggested.Count == 0
+                ? $"No reviewer candidates found for {diff.Count} changed file(s)"
+                : $"Suggested {string.Join(", ", result.Suggested.Select(s => s.Reviewer))} for {diff.Count} changed file(s)"
+        };
+
+        var insights = new List<string>();
+        if (codeOwners == null)
+        {
+            insights.Add("No CODEOWNERS file - suggestions are based on blame and history only");
+        }
+        else if (result.UnownedFiles.Count > 0)
+        {
+            insights.Add($"{result.UnownedFiles.Count} changed file(s) have no CODEOWNERS owner");
+        }
+        var uncovered = result.RequiredOwners.Where(o => !result.Suggested.Any(s => s.Reviewer.Equals(o, StringComparison.OrdinalIgnoreCase))).ToList();
+        if (uncovered.Count > 0)
+        {
+            insights.Add($"CODEOWNERS also requires: {string.Join(", ", uncovered.Take(5))}");
+        }
+        if (diff.Count > parameters.MaxFiles)
+        {
+            insights.Add($"Only {parameters.MaxFiles} of {diff.Count} changed files were analyzed");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        _logger.LogDebug("Suggested {Count} reviewer(s) for {Base}..{Head}", result.Suggested.Count, result.BaseRef, result.HeadRef);
+        return response;
+    }
+
+    /// <summary>
+    /// @handle for configured aliases and GitHub noreply addresses, otherwise the lower-cased email
+    /// </summary>
+    private string Resolve(string email)
+    {
+        if (_aliases.TryGetValue(email, out var alias))
+            return alias;
+
+        var noReply = GitHubNoReply.Match(email);
+        return noReply.Success ? "@" + noReply.Groups[1].Value : email.ToLowerInvariant();
+    }
+
+    /// <summary>
+    /// Lines removed or replaced by the hunk; for pure insertions, the line they follow
+    /// </summary>
+    private static (int Start, int End) GetBlameRange(DiffHunk hunk) =>
+        hunk.OldCount > 0
+            ? (hunk.OldStart, hunk.OldStart + hunk.OldCount - 1)
+            : (Math.Max(1, hunk.OldStart), Math.Max(1, hunk.OldStart));
+
+    /// <summary>
+    /// Recent authorship counts for more; code untouched for years may have a new owner
+    /// </summary>
+    private static double RecencyWeight(DateTime authorTime) =>
+        (DateTime.UtcNow - authorTime).TotalDays switch
+        {
+            < 365 => 1.0,
+            < 730 => 0.6,
+            _ => 0.3
+        };
+
+    private async Task<List<BlameLine>> TryBlameAsync(string workspacePath, string baseRef, GitFileDiff file, CancellationToken cancellationToken)
+    {
+        try
+        {
+            return await _gitService.BlameAsync(workspacePath, baseRef, file.OldPath ?? file.Path,
+                file.Hunks.Select(GetBlameRange), cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            _logger.LogDebug(ex, "Blame failed for {FilePath}", file.Path);
+            return new List<BlameLine>();
+        }
+    }
+
+    private async Task<List<CommitAuthor>> TryGetHistoryAsync(string workspacePath, string baseRef, string path, CancellationToken cancellationToken)
+    {
+        try
+        {
+            return await _gitService.GetCommitAuthorsAsync(workspacePath, baseRef, path: path, maxCommits: HistoryCommitsPerFile, cancellationToken: cancellationToken);
+        }
+        catch (GitException ex)
+        {
+            _logger.LogDebug(ex, "History lookup failed for {Path}", path);
+            return new List<CommitAuthor>();
+        }
+    }
+
+    private static AIOptimizedResponse<SuggestReviewersResult> CreateGitErrorResponse(string code, string message) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo
+            {
+                Steps = new[]
+                {
+                    "Check that the workspace is a git checkout and git is on PATH",
+                    "Fetch the base branch (e.g. 'git fetch origin main') and use 'origin/main' as baseRef",
+                    "Shallow clones lack the history blame needs - fetch with more depth"
+                }
+            }
+        }
+    };
+
+    private class Candidate
+    {
+        public string Key { get; init; } = string.Empty;
+        public string? Name { get; set; }
+        public double Score { get; set; }
+        public bool IsCodeOwner { get; set; }
+        public int OwnedFiles { get; set; }
+        public int Lines { get; set; }
+        public int Commits { get; set; }
+        public HashSet<string> Files { get; } = new(StringComparer.OrdinalIgnoreCase);
+
+        public List<string> GetReasons()
+        {
+            var reasons = new List<string>();
+            if (OwnedFiles > 0)
+                reasons.Add($"CODEOWNERS owner of {OwnedFiles} changed file(s)");
+            if (Lines > 0)
+                reasons.Add($"wrote {Lines} of the changed line(s)");
+            if (Commits > 0)
+                reasons.Add($"{Commits} recent commit(s) to the changed files");
+            return reasons;
+        }
+    }
+}
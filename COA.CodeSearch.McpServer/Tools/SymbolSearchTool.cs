@@ -1,5 +1,6 @@
 using System.ComponentModel;
 using System.Text.Json;
+using System.Text.RegularExpressions;
 using COA.Mcp.Framework;
 using COA.Mcp.Framework.Base;
 using COA.Mcp.Framework.Models;
@@ -9,10 +10,13 @@ using COA.Mcp.Framework.TokenOptimization.Models;
 using COA.Mcp.Framework.TokenOptimization.Caching;
 using COA.Mcp.Framework.TokenOptimization.Storage;
 using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.GoBuild;
 using COA.CodeSearch.McpServer.Services.Lucene;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Julie;
 using COA.CodeSearch.McpServer.Services.TypeExtraction;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
 using COA.CodeSearch.McpServer.Services.Analysis;
 using COA.CodeSearch.McpServer.Tools.Models;
 using COA.CodeSearch.McpServer.ResponseBuilders;
@@ -40,6 +44,9 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
     private readonly SymbolSearchResponseBuilder _responseBuilder;
     private readonly SmartQueryPreprocessor _queryProcessor;
     private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly IGoBuildProfileService? _goBuildProfiles;
+    private readonly IDependencyCodeService? _dependencyCode;
+    private readonly IWorkingSetService? _workingSet;
     private readonly ILogger<SymbolSearchTool> _logger;
     private const LuceneVersion LUCENE_VERSION = LuceneVersion.LUCENE_48;
 
@@ -56,6 +63,9 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
     /// <param name="queryProcessor">Smart query preprocessing service</param>
     /// <param name="codeAnalyzer">Code analysis service</param>
     /// <param name="logger">Logger instance</param>
+    /// <param name="goBuildProfiles">Optional Go build profiles for annotating build-constrained symbols</param>
+    /// <param name="dependencyCode">Optional dependency code settings for flagging vendored symbols</param>
+    /// <param name="workingSet">Optional session working set, to rank symbols in recently touched files higher</param>
     public SymbolSearchTool(
         IServiceProvider serviceProvider,
         ILuceneIndexService luceneIndexService,
@@ -66,7 +76,10 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
         ICacheKeyGenerator keyGenerator,
         SmartQueryPreprocessor queryProcessor,
         CodeAnalyzer codeAnalyzer,
-        ILogger<SymbolSearchTool> logger) : base(serviceProvider, logger)
+        ILogger<SymbolSearchTool> logger,
+        IGoBuildProfileService? goBuildProfiles = null,
+        IDependencyCodeService? dependencyCode = null,
+        IWorkingSetService? workingSet = null) : base(serviceProvider, logger)
     {
         _luceneIndexService = luceneIndexService;
         _sqliteService = sqliteService;
@@ -76,6 +89,9 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
         _keyGenerator = keyGenerator;
         _queryProcessor = queryProcessor;
         _codeAnalyzer = codeAnalyzer;
+        _goBuildProfiles = goBuildProfiles;
+        _dependencyCode = dependencyCode;
+        _workingSet = workingSet;
         _responseBuilder = new SymbolSearchResponseBuilder(logger as ILogger<SymbolSearchResponseBuilder>, storageService);
         _logger = logger;
     }
@@ -117,15 +133,32 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
         CancellationToken cancellationToken)
     {
         // Validate required parameters
-        var symbolName = ValidateRequired(parameters.Symbol, nameof(parameters.Symbol));
+        // A generic written with type arguments (Sum[T Number], Stack[int]) is looked up by its name
+        var symbolName = GoGenerics.StripTypeArguments(ValidateRequired(parameters.Symbol, nameof(parameters.Symbol)));
+
+        // A qualified name (UserService.GetUser, (*UserService).GetUser) looks the member up within its type
+        var containingType = string.IsNullOrWhiteSpace(parameters.ContainingType) ? null : TypeNameOf(parameters.ContainingType);
+        if (SplitQualifiedName(symbolName) is { } qualified)
+        {
+            symbolName = qualified.Member;
+            containingType ??= qualified.Type;
+        }
 
         // Use provided workspace path or default to current workspace
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
-            : Path.GetFullPath(parameters.WorkspacePath);
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
         
-        // Generate cache key
+        // Generate cache key; the session's working set changes the ranking
         var cacheKey = _keyGenerator.GenerateKey(Name, parameters);
+        var workingSetWeights = _workingSet is { Enabled: true }
+            ? _workingSet.GetFileWeights(workspacePath)
+            : new Dictionary<string, float>();
+        if (workingSetWeights.Count > 0)
+        {
+            cacheKey += $":ws:{_workingSet!.Version}";
+        }
         
         // Check cache first (unless explicitly disabled)
         if (!parameters.NoCache)
@@ -143,7 +176,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
             var stopwatch = System.Diagnostics.Stopwatch.StartNew();
 
             // TIER 1: Try exact match via SQLite (0-1ms) - fastest path for exact symbol names
-            var exactMatch = await TryExactMatchAsync(workspacePath, symbolName, parameters, cancellationToken);
+            var exactMatch = await TryExactMatchAsync(workspacePath, symbolName, containingType, parameters, cancellationToken);
             if (exactMatch != null)
             {
                 stopwatch.Stop();
@@ -165,6 +198,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                 {
                     await _cacheService.SetAsync(cacheKey, tierOneResult);
                 }
+                PrefetchTopMatch(exactMatch, parameters.WorkspacePath, workspacePath);
                 return tierOneResult;
             }
 
@@ -245,6 +279,8 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                                                 Modifiers = type.Modifiers,
                                                 BaseType = type.BaseType,
                                                 Interfaces = type.Interfaces,
+                                                TypeParameters = type.TypeParameters,
+                                                TypeSet = type.TypeSet,
                                                 Score = hit.Score,
                                                 Snippet = GetSnippet(hit.ContextLines)
                                             });
@@ -275,6 +311,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                                                 ContainingType = method.ContainingType,
                                                 ReturnType = method.ReturnType,
                                                 Parameters = method.Parameters,
+                                                TypeParameters = method.TypeParameters,
                                                 Score = hit.Score,
                                                 Snippet = GetSnippet(hit.ContextLines)
                                             });
@@ -332,6 +369,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                             FilePath = sr.Symbol.FilePath,
                             Line = sr.Symbol.StartLine,
                             Column = sr.Symbol.StartColumn,
+                            ByteOffset = sr.Symbol.StartByte,
                             Language = sr.Symbol.Language,
                             Score = sr.SimilarityScore, // Semantic similarity score (0-1)
                             Snippet = sr.Symbol.DocComment
@@ -362,6 +400,27 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
             var luceneSymbols = await luceneTask;
             var semanticSymbols = await semanticTask;
 
+            // Go methods come back under their bare name; their receiver is read from the declaration
+            var positions = CreateSourcePositions(workspacePath);
+            foreach (var symbol in luceneSymbols.Concat(semanticSymbols))
+            {
+                symbol.ContainingType ??= await GoReceiverAsync(positions, symbol.FilePath, symbol.Line, symbol.Signature, cancellationToken);
+            }
+            if (containingType != null)
+            {
+                luceneSymbols = luceneSymbols.Where(s => ContainingTypeMatches(s, containingType, parameters.CaseSensitive)).ToList();
+                semanticSymbols = semanticSymbols.Where(s => ContainingTypeMatches(s, containingType, parameters.CaseSensitive)).ToList();
+            }
+
+            // Files guarded for other platforms define the same names; mark them, or drop them when asked
+            await AddBuildConstraintsAsync(_goBuildProfiles, workspacePath, luceneSymbols.Concat(semanticSymbols), cancellationToken);
+            AddDependencyCode(_dependencyCode, workspacePath, luceneSymbols.Concat(semanticSymbols));
+            if (parameters.BuildProfileOnly)
+            {
+                luceneSymbols = luceneSymbols.Where(s => s.InBuild != false).ToList();
+                semanticSymbols = semanticSymbols.Where(s => s.InBuild != false).ToList();
+            }
+
             // Merge results intelligently (deduplicate by file:name, keep highest score)
             var mergedSymbols = new Dictionary<string, SymbolDefinition>();
             var tier2Count = 0;
@@ -370,7 +429,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
             // Add Lucene results first (Tier 2)
             foreach (var symbol in luceneSymbols)
             {
-                var key = $"{symbol.FilePath}:{symbol.Name}";
+                var key = MergeKey(symbol);
                 mergedSymbols[key] = symbol;
                 tier2Count++;
             }
@@ -378,7 +437,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
             // Add semantic results, deduplicating with Lucene (Tier 4)
             foreach (var symbol in semanticSymbols)
             {
-                var key = $"{symbol.FilePath}:{symbol.Name}";
+                var key = MergeKey(symbol);
                 if (!mergedSymbols.ContainsKey(key))
                 {
                     mergedSymbols[key] = symbol;
@@ -394,10 +453,10 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                 }
             }
 
-            // Sort by relevance (exact matches first, then by score)
+            // Sort by relevance (exact matches first, then by score, boosted for the session's working set)
             var symbols = mergedSymbols.Values
                 .OrderByDescending(s => s.Name.Equals(symbolName, StringComparison.OrdinalIgnoreCase))
-                .ThenByDescending(s => s.Score)
+                .ThenByDescending(s => s.Score * (1 + 0.5f * WorkingSetWeight(workspacePath, workingSetWeights, s)))
                 .Take(parameters.MaxResults)
                 .ToList();
 
@@ -410,6 +469,9 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
             {
                 await AddReferenceCounts(workspacePath, symbols, cancellationToken);
             }
+
+            AddQualifiedNames(symbols);
+            await AddUtf16ColumnsAsync(positions, symbols, cancellationToken);
             
             // Create the result with tier breakdown
             var result = new SymbolSearchResult
@@ -445,6 +507,7 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                 });
             }
             
+            PrefetchTopMatch(result, parameters.WorkspacePath, workspacePath);
             return response;
         }
         catch (Exception ex)
@@ -472,6 +535,103 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
         }
     }
     
+    /// <summary>
+    /// Splits <c>UserService.GetUser</c> or <c>(*UserService).GetUser</c> into the type and member searched for
+    /// </summary>
+    private static (string Type, string Member)? SplitQualifiedName(string symbol)
+    {
+        var dot = symbol.LastIndexOf('.');
+        if (dot <= 0 || dot == symbol.Length - 1)
+            return null;
+
+        var member = symbol[(dot + 1)..].Trim();
+        var type = TypeNameOf(symbol[..dot]);
+        return type.Length > 0 && member.All(c => char.IsLetterOrDigit(c) || c == '_') ? (type, member) : null;
+    }
+
+    /// <summary>
+    /// A type's bare name: without pointer, parentheses, type arguments or package/namespace qualifier
+    /// </summary>
+    private static string TypeNameOf(string type)
+    {
+        var name = Regex.Replace(type, @"\[[^\]]*\]|<[^>]*>", string.Empty).Trim().Trim('(', ')').TrimStart('*', '&').Trim();
+        return name[(name.LastIndexOf('.') + 1)..];
+    }
+
+    private static bool ContainingTypeMatches(SymbolDefinition symbol, string containingType, bool caseSensitive) =>
+        symbol.ContainingType != null && TypeNameOf(symbol.ContainingType).Equals(containingType,
+            caseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Same-named Go methods on different receivers are different symbols, even in one file
+    /// </summary>
+    private static string MergeKey(SymbolDefinition symbol) =>
+        IsGoFile(symbol.FilePath) && symbol.ContainingType != null
+            ? $"{symbol.FilePath}:{symbol.ContainingType}.{symbol.Name}"
+            : $"{symbol.FilePath}:{symbol.Name}";
+
+    /// <summary>
+    /// The larger of the working-set weights of the symbol's file and of its name, 0 to 1
+    /// </summary>
+    private float WorkingSetWeight(string workspacePath, IReadOnlyDictionary<string, float> fileWeights, SymbolDefinition symbol)
+    {
+        if (_workingSet is not { Enabled: true })
+            return 0f;
+
+        var fileWeight = string.IsNullOrEmpty(symbol.FilePath)
+            ? 0f
+            : fileWeights.GetValueOrDefault(Path.GetFullPath(Path.Combine(workspacePath, symbol.FilePath)));
+        return Math.Max(fileWeight, _workingSet.GetSymbolWeight(workspacePath, symbol.Name));
+    }
+
+    private static void AddQualifiedNames(IEnumerable<SymbolDefinition> symbols)
+    {
+        foreach (var symbol in symbols.Where(s => s.ContainingType != null))
+        {
+            symbol.QualifiedName = $"{TypeNameOf(symbol.ContainingType!)}.{symbol.Name}";
+        }
+    }
+
+    private static bool IsGoFile(string filePath) => filePath.EndsWith(".go", StringComparison.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// The receiver type of a Go method, from its signature or else its declaration line
+    /// </summary>
+    private static async Task<string?> GoReceiverAsync(SourcePositions positions, string filePath, int line, string? signature,
+        CancellationToken cancellationToken)
+    {
+        if (!IsGoFile(filePath))
+            return null;
+
+        return GoMethodSets.ReceiverOf(signature)
+            ?? GoMethodSets.ReceiverOf(await positions.GetLineAsync(filePath, line, cancellationToken));
+    }
+
+    /// <summary>
+    /// The enclosing type of a member from its parent symbol, or the receiver of a Go method or Kotlin extension,
+    /// which are stored at file level. Kotlin companion object members count as members of the enclosing class.
+    /// </summary>
+    private async Task<string?> ContainingTypeOfAsync(string workspacePath, JulieSymbol symbol, SourcePositions positions,
+        Dictionary<string, List<JulieSymbol>> fileSymbols, CancellationToken cancellationToken)
+    {
+        if (symbol.ParentId == null)
+            return symbol.Language == "kotlin"
+                ? KotlinSymbols.ReceiverOf(symbol.Signature)
+                : await GoReceiverAsync(positions, symbol.FilePath, symbol.StartLine, symbol.Signature, cancellationToken);
+
+        if (!fileSymbols.TryGetValue(symbol.FilePath, out var siblings))
+        {
+            siblings = await _sqliteService.GetSymbolsForFileAsync(workspacePath, symbol.FilePath, cancellationToken);
+            fileSymbols[symbol.FilePath] = siblings;
+        }
+
+        // Locals and nested functions have a function for a parent, not a type
+        var parent = siblings.FirstOrDefault(s => s.Id == symbol.ParentId);
+        if (parent != null && parent.ParentId != null && KotlinSymbols.IsCompanion(parent))
+            parent = siblings.FirstOrDefault(s => s.Id == parent.ParentId) ?? parent;
+        return parent is { Kind: not ("function" or "method" or "constructor") } ? parent.Name : null;
+    }
+
     private bool MatchesSymbol(string symbolName, string searchTerm, bool caseSensitive)
     {
         if (caseSensitive)
@@ -515,9 +675,22 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
     /// <summary>
     /// Tier 1: Try exact match via SQLite symbols table (0-1ms)
     /// </summary>
+    /// <summary>
+    /// Prefetches references and the file outline for the best match, which is what gets explored next
+    /// </summary>
+    private void PrefetchTopMatch(SymbolSearchResult result, string? workspacePathArgument, string workspacePath)
+    {
+        var top = result.Symbols.FirstOrDefault();
+        if (top != null)
+        {
+            PrefetchSymbol(workspacePathArgument, workspacePath, top.Name, top.FilePath);
+        }
+    }
+
     private async Task<SymbolSearchResult?> TryExactMatchAsync(
         string workspacePath,
         string symbolName,
+        string? containingType,
         SymbolSearchParameters parameters,
         CancellationToken cancellationToken)
     {
@@ -537,6 +710,8 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
 
             // Convert JulieSymbol to SymbolDefinition
             var symbols = new List<SymbolDefinition>();
+            var positions = CreateSourcePositions(workspacePath);
+            var fileSymbols = new Dictionary<string, List<JulieSymbol>>(StringComparer.OrdinalIgnoreCase);
             foreach (var julieSymbol in sqliteSymbols)
             {
                 // Get reference count from identifiers table (optimized COUNT query, not full fetch)
@@ -554,9 +729,12 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                     FilePath = julieSymbol.FilePath,
                     Line = julieSymbol.StartLine,
                     Column = julieSymbol.StartColumn,
+                    ByteOffset = julieSymbol.StartByte,
                     Language = julieSymbol.Language,
                     Modifiers = julieSymbol.Visibility != null ? new List<string> { julieSymbol.Visibility } : new List<string>(),
+                    ContainingType = await ContainingTypeOfAsync(workspacePath, julieSymbol, positions, fileSymbols, cancellationToken),
                     ReferenceCount = referenceCount,
+                    Anchor = await CreateAnchorAsync(workspacePath, julieSymbol, cancellationToken),
                     Score = 1.0f // Exact match gets perfect score
                 });
             }
@@ -574,6 +752,29 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                 }
             }
 
+            if (containingType != null)
+            {
+                symbols = symbols
+                    .Where(s => ContainingTypeMatches(s, containingType, parameters.CaseSensitive))
+                    .ToList();
+
+                if (!symbols.Any())
+                {
+                    return null; // No member of that type
+                }
+            }
+
+            await AddBuildConstraintsAsync(_goBuildProfiles, workspacePath, symbols, cancellationToken);
+            AddDependencyCode(_dependencyCode, workspacePath, symbols);
+            if (parameters.BuildProfileOnly)
+            {
+                symbols = symbols.Where(s => s.InBuild != false).ToList();
+                if (!symbols.Any())
+                {
+                    return null; // Only defined for other platforms
+                }
+            }
+
             // Limit results
             if (symbols.Count > parameters.MaxResults)
             {
@@ -583,6 +784,10 @@ public class SymbolSearchTool : CodeSearchToolBase<SymbolSearchParameters, AIOpt
                     .ToList();
             }
 
+            AddQualifiedNames(symbols);
+            await AddUtf16ColumnsAsync(positions, symbols, cancellationToken);
+            await AddGoGenericsAsync(positions, symbols, cancellationToken);
+
             return new SymbolSearchResult
             {
                 Symbols = symbols,
@@ -0,0 +1,183 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Graph;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds how entry points reach dangerous operations: the shortest call paths from a set of source symbols
+/// (handlers, CLI commands) to sink symbols or to calls into code outside the index (exec.Command, raw SQL)
+/// </summary>
+public class TaintPathsTool : CodeSearchToolBase<TaintPathsParameters, AIOptimizedResponse<TaintPathsResult>>
+{
+    private readonly IGraphQueryService _graphQueryService;
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<TaintPathsTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the TaintPathsTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="graphQueryService">Query evaluator and path search over the call graph</param>
+    /// <param name="sqliteService">SQLite symbol service the graph is read from</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public TaintPathsTool(
+        IServiceProvider serviceProvider,
+        IGraphQueryService graphQueryService,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<TaintPathsTool> logger) : base(serviceProvider, logger)
+    {
+        _graphQueryService = graphQueryService;
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.TaintPaths;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "CAN ENTRY POINTS REACH THIS? Shortest call paths from source symbols (e.g. HTTP handlers) to sinks - symbols selected by a graph query, " +
+        "or calls outside the index such as exec.Command or *.ExecuteSqlRaw. Sources and sinks use graph_query syntax. " +
+        "Use for security review: which handlers reach shell execution or raw SQL, and through which functions.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Selects sources and sinks and searches the call graph between them.
+    /// </summary>
+    /// <param name="parameters">Source and sink queries, sink calls and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Shortest paths, one per sink reached</returns>
+    protected override async Task<AIOptimizedResponse<TaintPathsResult>> ExecuteInternalAsync(
+        TaintPathsParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        ValidateRequired(parameters.Sources, nameof(parameters.Sources));
+        var sinkCalls = parameters.SinkCalls?.Where(c => !string.IsNullOrWhiteSpace(c)).Select(c => c.Trim()).ToList() ?? new List<string>();
+        if (string.IsNullOrWhiteSpace(parameters.Sinks) && sinkCalls.Count == 0)
+        {
+            return CreateErrorResponse("MISSING_SINKS", "Give sinks (a graph query) or sinkCalls (calls outside the index), or both",
+                "Example sinkCalls: [\"exec.Command\", \"*.ExecuteSqlRaw\", \"subprocess.run\"]",
+                "Example sinks: symbol:RunRawQuery");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try taint_paths again");
+        }
+
+        GraphPathOutcome outcome;
+        try
+        {
+            var sources = _graphQueryService.Parse(parameters.Sources);
+            var sinks = string.IsNullOrWhiteSpace(parameters.Sinks) ? null : _graphQueryService.Parse(parameters.Sinks);
+            outcome = await _graphQueryService.FindPathsAsync(workspacePath, sources, sinks, sinkCalls,
+                Math.Clamp(parameters.MaxDepth, 1, 12), Math.Clamp(parameters.MaxPaths, 1, 200), parameters.CaseSensitive, cancellationToken);
+        }
+        catch (GraphQueryException ex)
+        {
+            return CreateErrorResponse(ex.Code, ex.Message,
+                "Sources and sinks are graph_query pipelines: separate steps with '|' and start with a filter",
+                "Example: in:src/Controllers | kind:method");
+        }
+
+        var result = new TaintPathsResult
+        {
+            SourceCount = outcome.SourceCount,
+            SinkCount = outcome.SinkCount,
+            Visited = outcome.Visited,
+            Truncated = outcome.Truncated,
+            Paths = outcome.Paths.Select(p => ToTaintPath(workspacePath, p)).ToList()
+        };
+
+        _logger.LogDebug("taint_paths found {Count} paths from {Sources} sources", result.Paths.Count, result.SourceCount);
+
+        var response = new AIOptimizedResponse<TaintPathsResult>
+        {
+            Success = true,
+            Data = new AIResponseData<TaintPathsResult> { Results = result },
+            Message = result.Paths.Count > 0
+                ? $"{result.Paths.Count} path(s), shortest {result.Paths[0].Length} call(s): {result.Paths[0].Source} -> {result.Paths[0].Sink}"
+                : $"No path within {parameters.MaxDepth} calls from {result.SourceCount} source(s)"
+        };
+
+        var insights = new List<string>();
+        if (result.SourceCount == 0)
+        {
+            insights.Add("The sources query matched nothing - try it in graph_query first");
+        }
+        else if (result.SinkCount == 0 && sinkCalls.Count == 0)
+        {
+            insights.Add("The sinks query matched nothing - try it in graph_query first");
+        }
+        if (result.Truncated)
+        {
+            insights.Add("Stopped at the CodeSearch:GraphQuery:MaxNodes limit - narrow the sources or lower maxDepth");
+        }
+        insights.Add("Paths follow the index's call graph: calls through interfaces, delegates and reflection may be missing, and unresolved calls link every function with that name");
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static TaintPath ToTaintPath(string workspacePath, GraphPath path)
+    {
+        var steps = new List<string>();
+        for (var i = 0; i < path.Hops.Count; i++)
+        {
+            var symbol = path.Hops[i].Symbol;
+            var step = $"{symbol.Name} ({Relative(workspacePath, symbol.FilePath)}:{symbol.StartLine})";
+            if (i > 0)
+                step += $" <- called at {Relative(workspacePath, path.Hops[i - 1].Symbol.FilePath)}:{path.Hops[i].CallLine}";
+            steps.Add(step);
+        }
+
+        var last = path.Hops[^1].Symbol;
+        if (path.SinkCall != null)
+            steps.Add($"{path.SinkCall} <- called at {Relative(workspacePath, last.FilePath)}:{path.SinkCallLine}");
+
+        return new TaintPath
+        {
+            Source = path.Hops[0].Symbol.Name,
+            Sink = path.SinkCall ?? last.Name,
+            Length = path.Length,
+            Steps = steps
+        };
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<TaintPathsResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -1,5 +1,6 @@
 using System.ComponentModel;
 using System.ComponentModel.DataAnnotations;
+using System.Globalization;
 using COA.Mcp.Framework;
 using COA.Mcp.Framework.Base;
 using COA.Mcp.Framework.Models;
@@ -12,6 +13,10 @@ using COA.CodeSearch.McpServer.Services;
 using COA.CodeSearch.McpServer.Services.Lucene;
 using COA.CodeSearch.McpServer.Services.Sqlite;
 using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Editor;
+using COA.CodeSearch.McpServer.Services.Sampling;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
 using COA.CodeSearch.McpServer.Models;
 using COA.CodeSearch.McpServer.ResponseBuilders;
 using COA.CodeSearch.McpServer.Scoring;
@@ -43,6 +48,11 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
     private readonly QueryPreprocessor _queryPreprocessor;
     private readonly SmartQueryPreprocessor _smartQueryPreprocessor;
     private readonly CodeAnalyzer _codeAnalyzer;
+    private readonly SearchReranker? _reranker;
+    private readonly QueryCostEstimator? _costEstimator;
+    private readonly IDependencyCodeService? _dependencyCode;
+    private readonly IEditorContextService? _editorContext;
+    private readonly IWorkingSetService? _workingSet;
     private readonly ILogger<TextSearchTool> _logger;
 
     /// <summary>
@@ -59,6 +69,11 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
     /// <param name="smartQueryPreprocessor">Smart query preprocessing service</param>
     /// <param name="codeAnalyzer">Code analysis service</param>
     /// <param name="logger">Logger instance</param>
+    /// <param name="reranker">Optional re-ranking of top hits via MCP sampling</param>
+    /// <param name="costEstimator">Optional guardrail for expensive queries</param>
+    /// <param name="dependencyCode">Optional dependency code settings, to downrank vendored code</param>
+    /// <param name="editorContext">Optional editor context, to rank files near the open one higher</param>
+    /// <param name="workingSet">Optional session working set, to rank recently viewed or edited files higher</param>
     public TextSearchTool(
         IServiceProvider serviceProvider,
         ILuceneIndexService luceneIndexService,
@@ -70,7 +85,12 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         QueryPreprocessor queryPreprocessor,
         SmartQueryPreprocessor smartQueryPreprocessor,
         CodeAnalyzer codeAnalyzer,
-        ILogger<TextSearchTool> logger) : base(serviceProvider, logger)
+        ILogger<TextSearchTool> logger,
+        SearchReranker? reranker = null,
+        QueryCostEstimator? costEstimator = null,
+        IDependencyCodeService? dependencyCode = null,
+        IEditorContextService? editorContext = null,
+        IWorkingSetService? workingSet = null) : base(serviceProvider, logger)
     {
         _luceneIndexService = luceneIndexService;
         _sqliteService = sqliteService;
@@ -82,6 +102,11 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         _logger = logger;
         _smartQueryPreprocessor = smartQueryPreprocessor;
         _codeAnalyzer = codeAnalyzer;
+        _reranker = reranker;
+        _costEstimator = costEstimator;
+        _dependencyCode = dependencyCode;
+        _editorContext = editorContext;
+        _workingSet = workingSet;
         
         // Create response builder with dependencies
         _responseBuilder = new SearchResponseBuilder(logger as ILogger<SearchResponseBuilder>, storageService);
@@ -138,13 +163,42 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         // Validate required parameters
         var query = ValidateRequired(parameters.Query, nameof(parameters.Query));
 
+        if (!SnippetHighlighter.IsValidFormat(parameters.SnippetFormat))
+        {
+            return CreateInvalidSnippetFormatError(parameters.SnippetFormat);
+        }
+
         // Use current workspace if not specified
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
             : Path.GetFullPath(parameters.WorkspacePath);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
         
+        // Files near the one open in the editor rank higher, so the ranking depends on it too
+        var openFile = _editorContext?.Current is { } editor &&
+                       string.Equals(Path.GetFullPath(editor.WorkspacePath), workspacePath, StringComparison.OrdinalIgnoreCase)
+            ? editor.FilePath
+            : null;
+
         // Generate cache key
         var cacheKey = _keyGenerator.GenerateKey(Name, parameters);
+        if (openFile != null)
+        {
+            cacheKey += $":near:{openFile}";
+        }
+
+        // So does the session's working set
+        var workingSetWeights = _workingSet is { Enabled: true }
+            ? _workingSet.GetFileWeights(workspacePath)
+            : null;
+        if (workingSetWeights?.Count > 0)
+        {
+            cacheKey += $":ws:{_workingSet!.Version}";
+        }
+        else
+        {
+            workingSetWeights = null;
+        }
         
         // Check cache first (unless explicitly disabled)
         if (!parameters.NoCache)
@@ -184,6 +238,18 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 return await HandleSemanticOnlySearchAsync(workspacePath, query, parameters, cacheKey, cancellationToken);
             }
 
+            // Struct tag queries read field tags from Go sources instead of the Lucene index
+            if (searchMode == SearchMode.Tag || searchMode == SearchMode.Auto && GoStructTags.IsTagQuery(query))
+            {
+                return await HandleStructTagSearchAsync(workspacePath, query, parameters, cacheKey, cancellationToken);
+            }
+
+            // Key path queries read the nesting of JSON and YAML files instead of matching their text
+            if (searchMode == SearchMode.Config || searchMode == SearchMode.Auto && ConfigKeyPaths.IsKeyPathQuery(query))
+            {
+                return await HandleConfigKeySearchAsync(workspacePath, query, parameters, cacheKey, cancellationToken);
+            }
+
             // Build Lucene query based on search mode
             Query luceneQuery;
             string searchType; // For backward compatibility with scoring
@@ -213,7 +279,8 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
             else
             {
                 // Auto mode (default): Use SmartQueryPreprocessor for intelligent routing
-                var queryResult = _smartQueryPreprocessor.Process(query, searchMode);
+                // Sample mode routes like auto; only the documents it runs over differ
+                var queryResult = _smartQueryPreprocessor.Process(query, searchMode == SearchMode.Sample ? SearchMode.Auto : searchMode);
                         
                 // Log the smart query processing result for debugging
                 _logger.LogDebug("Smart query processing: '{OriginalQuery}' -> '{ProcessedQuery}', Field: {TargetField}, Mode: {Mode}, Reason: {Reason}",
@@ -240,6 +307,8 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                     // Create field-specific query for multi-field indexing
                     var queryParser = new QueryParser(LuceneVersion.LUCENE_48, queryResult.TargetField, _codeAnalyzer);
                     queryParser.AllowLeadingWildcard = true;
+                    // Wildcard terms skip the analyzer and are lowercased with this culture
+                    queryParser.Locale = CultureInfo.InvariantCulture;
 
                     try
                     {
@@ -253,6 +322,32 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 }
             }
 
+            // Estimate the cost before running anything so an accidental full-index scan is caught
+            QueryCostEstimate? costEstimate = null;
+            SampledDocFilter? sampleFilter = null;
+            if (searchMode == SearchMode.Sample)
+            {
+                sampleFilter = new SampledDocFilter(parameters.SampleRate, Random.Shared.Next());
+                luceneQuery = new FilteredQuery(luceneQuery, sampleFilter);
+            }
+            else if (_costEstimator is { Enabled: true } && !parameters.ConfirmExpensive)
+            {
+                var documentCount = await _luceneIndexService.GetDocumentCountAsync(workspacePath, cancellationToken);
+                costEstimate = _costEstimator.Estimate(query, searchModeString, documentCount);
+                if (costEstimate.IsExpensive)
+                {
+                    if (_costEstimator.Action == QueryGuardrailAction.Confirm)
+                    {
+                        return CreateExpensiveQueryError(query, costEstimate);
+                    }
+                    if (_costEstimator.Action == QueryGuardrailAction.Sample)
+                    {
+                        sampleFilter = new SampledDocFilter(_costEstimator.SampleRate);
+                        luceneQuery = new FilteredQuery(luceneQuery, sampleFilter);
+                    }
+                }
+            }
+
             // Apply scoring factors for better relevance
             var scoringContext = new ScoringContext
             {
@@ -270,6 +365,19 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
             multiFactorQuery.AddScoringFactor(new RecencyBoostFactor());         // Boost recently modified
             multiFactorQuery.AddScoringFactor(new ExactMatchBoostFactor(parameters.CaseSensitive)); // Exact phrase matches
             multiFactorQuery.AddScoringFactor(new InterfaceImplementationFactor(_logger)); // Reduce mock/test noise for interface searches
+            var downrankDependencies = _dependencyCode?.GetSettings(workspacePath).Mode == DependencyCodeSettings.Downrank;
+            if (downrankDependencies)
+            {
+                multiFactorQuery.AddScoringFactor(new DependencyCodeFactor()); // Vendored and third-party code below first-party
+            }
+            if (openFile != null)
+            {
+                multiFactorQuery.AddScoringFactor(new EditorProximityFactor(openFile)); // Near the file open in the editor
+            }
+            if (workingSetWeights != null)
+            {
+                multiFactorQuery.AddScoringFactor(new WorkingSetFactor(workingSetWeights)); // Recently viewed or edited, and their neighbors
+            }
 
             // Implement aggressive token-aware limiting like the old system
             // The old system targeted ~1500 tokens with ~5 results for maximum relevance
@@ -293,6 +401,12 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 "summary" => Math.Min(budgetBasedMax, 2),   // Summary: ultra-lean - just 2 results
                 _ => Math.Min(budgetBasedMax, 3)            // Default: lean - just top 3 results
             };
+
+            // Re-ranking needs a wider candidate window; the response builder still trims to budget afterwards
+            if (parameters.Rerank && _reranker != null)
+            {
+                maxResults = Math.Max(maxResults, _reranker.MaxCandidates);
+            }
             
             _logger.LogDebug("Token-aware search limits: budget={Budget}, tokensPerResult={TokensPerResult}, maxResults={MaxResults}, mode={Mode}, Query={Query}", 
                 safetyBudget, tokensPerResult, maxResults, responseMode, query);
@@ -317,6 +431,10 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 
                 // Retry with content field
                 var fallbackQuery = _queryPreprocessor.BuildQuery(query, searchType, parameters.CaseSensitive, _codeAnalyzer);
+                if (sampleFilter != null)
+                {
+                    fallbackQuery = new FilteredQuery(fallbackQuery, sampleFilter);
+                }
                 var fallbackMultiFactorQuery = new MultiFactorScoreQuery(fallbackQuery, scoringContext, _logger);
                 
                 // Add same scoring factors
@@ -326,6 +444,18 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 fallbackMultiFactorQuery.AddScoringFactor(new RecencyBoostFactor());
                 fallbackMultiFactorQuery.AddScoringFactor(new ExactMatchBoostFactor(parameters.CaseSensitive));
                 fallbackMultiFactorQuery.AddScoringFactor(new InterfaceImplementationFactor(_logger));
+                if (downrankDependencies)
+                {
+                    fallbackMultiFactorQuery.AddScoringFactor(new DependencyCodeFactor());
+                }
+                if (openFile != null)
+                {
+                    fallbackMultiFactorQuery.AddScoringFactor(new EditorProximityFactor(openFile));
+                }
+                if (workingSetWeights != null)
+                {
+                    fallbackMultiFactorQuery.AddScoringFactor(new WorkingSetFactor(workingSetWeights));
+                }
                 
                 searchResult = await _luceneIndexService.SearchAsync(
                     workspacePath, 
@@ -342,8 +472,14 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 }
             }
 
+            if (searchMode == SearchMode.Sample && sampleFilter != null)
+            {
+                searchResult.Sample = await EstimateFromSampleAsync(workspacePath, searchResult.TotalHits, sampleFilter, cancellationToken);
+            }
+
             // TIER 3: Semantic search fallback (if few results and semantic search available)
-            if (searchResult.TotalHits < 5 && _sqliteService.IsSemanticSearchAvailable())
+            // Skipped for sampled searches - semantic hits are not drawn from the sample and would skew the counts
+            if (searchResult.TotalHits < 5 && sampleFilter == null && _sqliteService.IsSemanticSearchAvailable())
             {
                 _logger.LogDebug("Lucene returned {Count} results, trying Tier 3 semantic search", searchResult.TotalHits);
 
@@ -414,6 +550,25 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
                 }
             }
 
+            if (parameters.Rerank)
+            {
+                if (_reranker != null)
+                {
+                    await _reranker.RerankAsync(searchResult, query, cancellationToken);
+                }
+                else
+                {
+                    searchResult.Reranking = new SearchReranking { FallbackReason = "Re-ranking is not available in this host" };
+                }
+            }
+
+            if (searchResult.Hits != null)
+            {
+                foreach (var hit in searchResult.Hits)
+                {
+                    SnippetHighlighter.Apply(hit, parameters.SnippetFormat);
+                }
+            }
 
             // Build response context
             var context = new ResponseContext
@@ -428,6 +583,11 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
             // Use response builder to create optimized response
             var result = await _responseBuilder.BuildResponseAsync(searchResult, context);
 
+            if (costEstimate is { IsExpensive: true })
+            {
+                AddCostInsights(result, costEstimate, sampleFilter);
+            }
+
             // Cache the successful response
             if (!parameters.NoCache && result.Success)
             {
@@ -503,6 +663,26 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         return result;
     }
 
+    private AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult> CreateInvalidSnippetFormatError(string? format)
+    {
+        return new AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>
+        {
+            Success = false,
+            Error = new COA.Mcp.Framework.Models.ErrorInfo
+            {
+                Code = "INVALID_SNIPPET_FORMAT",
+                Message = $"Unknown snippetFormat '{format}'",
+                Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                {
+                    Steps = new[]
+                    {
+                        $"Use one of: {string.Join(", ", SnippetHighlighter.Formats)}"
+                    }
+                }
+            }
+        };
+    }
+
     private AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult> CreateQueryParseError(string query, string? customMessage = null)
     {
         var result = new AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>
@@ -547,6 +727,83 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         return result;
     }
 
+    /// <summary>
+    /// Counts the sampled documents and extrapolates the sample's matches to the whole index
+    /// </summary>
+    private async Task<SampleEstimate> EstimateFromSampleAsync(
+        string workspacePath,
+        int matchesInSample,
+        SampledDocFilter sampleFilter,
+        CancellationToken cancellationToken)
+    {
+        var totalDocuments = await _luceneIndexService.GetDocumentCountAsync(workspacePath, cancellationToken);
+        var sampled = await _luceneIndexService.SearchAsync(
+            workspacePath,
+            new FilteredQuery(new MatchAllDocsQuery(), sampleFilter),
+            1,
+            false,
+            cancellationToken);
+
+        return SampleStatistics.Extrapolate(matchesInSample, sampled.TotalHits, totalDocuments, sampleFilter.Rate);
+    }
+
+    private AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult> CreateExpensiveQueryError(string query, QueryCostEstimate estimate)
+    {
+        return new AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>
+        {
+            Success = false,
+            Error = new COA.Mcp.Framework.Models.ErrorInfo
+            {
+                Code = "EXPENSIVE_QUERY",
+                Message = $"Query '{query}' would scan {estimate.CandidateDocuments} documents with an unanchored pattern: {string.Join("; ", estimate.Reasons)}",
+                Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                {
+                    Steps = new[]
+                    {
+                        "Anchor the pattern on a literal of 3+ characters (e.g. 'Service.*Async' instead of '.*Async')",
+                        "Search a narrower workspacePath",
+                        "Pass confirmExpensive: true to run the query anyway"
+                    }
+                }
+            },
+            Insights = new List<string>
+            {
+                $"Estimated cost {estimate.Cost:N0} exceeds the limit of {_costEstimator!.MaxCost:N0} (candidate documents x pattern complexity {estimate.Complexity})"
+            },
+            Actions = new List<AIAction>
+            {
+                new AIAction
+                {
+                    Action = "confirm_expensive",
+                    Description = "Re-run with confirmExpensive: true",
+                    Priority = 70
+                }
+            }
+        };
+    }
+
+    private static void AddCostInsights(
+        AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult> result,
+        QueryCostEstimate estimate,
+        SampledDocFilter? sampleFilter)
+    {
+        result.Insights ??= new List<string>();
+        var reasons = string.Join("; ", estimate.Reasons);
+        result.Insights.Insert(0, sampleFilter != null
+            ? $"🎲 Expensive query ({reasons}) - searched a {sampleFilter.Rate:P0} sample of {estimate.CandidateDocuments} documents; pass confirmExpensive: true for complete results"
+            : $"⚠️ Expensive query ({reasons}) over {estimate.CandidateDocuments} documents - anchor it on a literal to make it cheaper");
+
+        result.Meta ??= new AIResponseMeta();
+        result.Meta.ExtensionData ??= new Dictionary<string, object>();
+        result.Meta.ExtensionData["queryCost"] = new
+        {
+            candidateDocuments = estimate.CandidateDocuments,
+            complexity = estimate.Complexity,
+            cost = estimate.Cost,
+            sampleRate = sampleFilter?.Rate
+        };
+    }
+
     /// <summary>
     /// Handle semantic-only search mode (Tier 3 vector search, no Lucene)
     /// </summary>
@@ -680,5 +937,278 @@ public class TextSearchTool : CodeSearchToolBase<TextSearchParameters, AIOptimiz
         return result;
     }
 
+    /// <summary>
+    /// Handle struct tag search mode (Go field tags matched against tag:KEY=VALUE, no Lucene)
+    /// </summary>
+    private async Task<AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>> HandleStructTagSearchAsync(
+        string workspacePath,
+        string query,
+        TextSearchParameters parameters,
+        string cacheKey,
+        CancellationToken cancellationToken)
+    {
+        if (!GoStructTags.TryParseQuery(query, out var tagQuery))
+        {
+            return new AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "INVALID_TAG_QUERY",
+                    Message = $"'{query}' is not a struct tag query",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Use tag:KEY=VALUE, e.g. tag:json=user_name or tag:db=created_at",
+                            "Use tag:KEY to list every field with that tag, or tag:=VALUE to match any key",
+                            "Use tag:column=NAME to find fields mapped to a database column (db, gorm column:, bun, pg, sql tags)"
+                        }
+                    }
+                }
+            };
+        }
+
+        var stopwatch = System.Diagnostics.Stopwatch.StartNew();
+        var hits = new List<SearchHit>();
+        var filesScanned = 0;
+        var comparison = parameters.CaseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase;
+        var literals = tagQuery.Value.Split('*', StringSplitOptions.RemoveEmptyEntries);
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!file.Path.EndsWith(".go", StringComparison.OrdinalIgnoreCase))
+                continue;
+
+            var fullPath = Path.IsPathRooted(file.Path) ? file.Path : Path.Combine(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null || !content.Contains('`'))
+                continue;
+
+            filesScanned++;
+            // Every literal piece of the value pattern appears somewhere in a file with a matching tag
+            if (literals.Any(literal => content.IndexOf(literal, comparison) < 0))
+                continue;
+
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            foreach (var field in GoStructTags.Scan(lines))
+            {
+                var matches = GoStructTags.Match(field, tagQuery, parameters.CaseSensitive);
+                if (matches.Count == 0)
+                    continue;
+
+                hits.Add(new SearchHit
+                {
+                    FilePath = fullPath,
+                    LineNumber = field.Line,
+                    StartLine = field.Line,
+                    EndLine = field.Line,
+                    Column = Encoding.UTF8.GetByteCount(lines[field.Line - 1].AsSpan(0, field.Column)),
+                    Utf16Column = field.Column,
+                    Score = 1.0f,
+                    Snippet = lines[field.Line - 1].Trim(),
+                    Fields = new Dictionary<string, string>
+                    {
+                        ["struct"] = field.Struct,
+                        ["field"] = field.Field,
+                        ["type"] = field.Type,
+                        ["tag"] = field.Tag,
+                        ["tag_key"] = string.Join(",", matches.Select(m => m.Key)),
+                        ["tag_value"] = matches[0].Value,
+                        ["search_tier"] = "struct_tag"
+                    }
+                });
+            }
+        }
+        stopwatch.Stop();
+
+        _logger.LogInformation("🏷️ Struct tag search: {Count} field(s) in {Files} Go file(s) in {Ms}ms",
+            hits.Count, filesScanned, stopwatch.ElapsedMilliseconds);
+
+        var searchResult = new COA.CodeSearch.McpServer.Services.Lucene.SearchResult
+        {
+            TotalHits = hits.Count,
+            SearchTime = stopwatch.Elapsed,
+            Query = query,
+            Hits = hits
+        };
+
+        var context = new ResponseContext
+        {
+            ResponseMode = parameters.ResponseMode?.ToLowerInvariant() ?? "adaptive",
+            TokenLimit = parameters.MaxTokens,
+            StoreFullResults = true,
+            ToolName = Name,
+            CacheKey = cacheKey
+        };
+
+        var result = await _responseBuilder.BuildResponseAsync(searchResult, context);
+
+        var insights = new List<string>();
+        if (filesScanned == 0)
+        {
+            insights.Add("No Go files with struct tags are indexed in this workspace");
+        }
+        else if (hits.Count == 0)
+        {
+            insights.Add($"No struct field tags match '{query}' in {filesScanned} Go file(s)");
+        }
+        if (tagQuery.Key.Equals(GoStructTags.ColumnKey, StringComparison.OrdinalIgnoreCase) || tagQuery.Key == "gorm")
+        {
+            insights.Add("gorm fields without a column: setting get their column by naming convention and are not matched - column_usages resolves those");
+        }
+        if (tagQuery.Value.Length > 0)
+        {
+            insights.Add("Values match the tag's name (before the first comma) - include the comma or semicolon to match options, e.g. tag:json=*,omitempty");
+        }
+        result.Insights = insights.Concat(result.Insights ?? new List<string>()).ToList();
+
+        if (!parameters.NoCache && result.Success)
+        {
+            await _cacheService.SetAsync(cacheKey, result, new CacheEntryOptions
+            {
+                AbsoluteExpiration = TimeSpan.FromMinutes(15),
+                Priority = CachePriority.Normal
+            });
+        }
+
+        return result;
+    }
+
+    /// <summary>
+    /// Handle config key path search mode (JSON and YAML keys matched against path:KEY.PATH, no Lucene)
+    /// </summary>
+    private async Task<AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>> HandleConfigKeySearchAsync(
+        string workspacePath,
+        string query,
+        TextSearchParameters parameters,
+        string cacheKey,
+        CancellationToken cancellationToken)
+    {
+        if (!ConfigKeyPaths.TryParseQuery(query, out var keyQuery))
+        {
+            return new AIOptimizedResponse<COA.CodeSearch.McpServer.Services.Lucene.SearchResult>
+            {
+                Success = false,
+                Error = new COA.Mcp.Framework.Models.ErrorInfo
+                {
+                    Code = "INVALID_KEY_PATH_QUERY",
+                    Message = $"'{query}' is not a key path query",
+                    Recovery = new COA.Mcp.Framework.Models.RecoveryInfo
+                    {
+                        Steps = new[]
+                        {
+                            "Use path:KEY.PATH, e.g. path:server.timeout or path:Logging.LogLevel.Default",
+                            "Use * for any one key or index (path:services.*.image) and ** for any depth (path:spec.**.image)",
+                            "Add =VALUE to match values (path:image.tag=1.2.*); start with $. to anchor at the document root"
+                        }
+                    }
+                }
+            };
+        }
+
+        var stopwatch = System.Diagnostics.Stopwatch.StartNew();
+        var hits = new List<SearchHit>();
+        var filesScanned = 0;
+        var lastKey = keyQuery.Segments.LastOrDefault(s => s is not ("*" or "**" or "[*]") && !s.StartsWith('['));
+
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!ConfigKeyPaths.Handles(file.Path))
+                continue;
+
+            var fullPath = Path.IsPathRooted(file.Path) ? file.Path : Path.Combine(workspacePath, file.Path);
+            var content = file.Content ?? (File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null);
+            if (content == null || ConfigKeyPaths.IsTooLarge(content.Length))
+                continue;
+
+            filesScanned++;
+            if (lastKey != null && content.IndexOf(lastKey, parameters.CaseSensitive ? StringComparison.Ordinal : StringComparison.OrdinalIgnoreCase) < 0)
+                continue;
+
+            var lines = content.Replace("\r\n", "\n").Split('\n');
+            foreach (var entry in ConfigKeyPaths.Scan(file.Path, content))
+            {
+                if (!ConfigKeyPaths.Match(entry, keyQuery, parameters.CaseSensitive))
+                    continue;
+
+                var fields = new Dictionary<string, string>
+                {
+                    ["key_path"] = entry.Path,
+                    ["search_tier"] = "config_key"
+                };
+                if (entry.Value != null)
+                {
+                    fields["value"] = entry.Value;
+                }
+
+                hits.Add(new SearchHit
+                {
+                    FilePath = fullPath,
+                    LineNumber = entry.Line,
+                    StartLine = entry.Line,
+                    EndLine = entry.Line,
+                    Column = Encoding.UTF8.GetByteCount(lines[entry.Line - 1].AsSpan(0, entry.Column)),
+                    Utf16Column = entry.Column,
+                    Score = 1.0f,
+                    Snippet = lines[entry.Line - 1].Trim(),
+                    Fields = fields
+                });
+            }
+        }
+        stopwatch.Stop();
+
+        _logger.LogInformation("🗝️ Config key search: {Count} key(s) in {Files} JSON/YAML file(s) in {Ms}ms",
+            hits.Count, filesScanned, stopwatch.ElapsedMilliseconds);
 
-}
\ No newline at end of file
+        var searchResult = new COA.CodeSearch.McpServer.Services.Lucene.SearchResult
+        {
+            TotalHits = hits.Count,
+            SearchTime = stopwatch.Elapsed,
+            Query = query,
+            Hits = hits
+        };
+
+        var context = new ResponseContext
+        {
+            ResponseMode = parameters.ResponseMode?.ToLowerInvariant() ?? "adaptive",
+            TokenLimit = parameters.MaxTokens,
+            StoreFullResults = true,
+            ToolName = Name,
+            CacheKey = cacheKey
+        };
+
+        var result = await _responseBuilder.BuildResponseAsync(searchResult, context);
+
+        var insights = new List<string>();
+        if (filesScanned == 0)
+        {
+            insights.Add("No JSON or YAML files are indexed in this workspace");
+        }
+        else if (hits.Count == 0)
+        {
+            insights.Add(keyQuery.Anchored
+                ? $"No key path matches '{query}' from the document root in {filesScanned} file(s) - drop the $. to match at any depth"
+                : $"No key path matches '{query}' in {filesScanned} JSON/YAML file(s) - try a shorter path or * for a key in between");
+        }
+        if (hits.Any(h => Path.GetFileName(h.FilePath).StartsWith("appsettings", StringComparison.OrdinalIgnoreCase)))
+        {
+            var key = string.Join("__", keyQuery.Segments.Where(s => s is not ("*" or "**")).Select(s => s.Trim('[', ']')));
+            insights.Add($"appsettings keys can be overridden by environment variables written with __ between keys (e.g. {key})");
+        }
+        result.Insights = insights.Concat(result.Insights ?? new List<string>()).ToList();
+
+        if (!parameters.NoCache && result.Success)
+        {
+            await _cacheService.SetAsync(cacheKey, result, new CacheEntryOptions
+            {
+                AbsoluteExpiration = TimeSpan.FromMinutes(15),
+                Priority = CachePriority.Normal
+            });
+        }
+
+        return result;
+    }
+}
@@ -0,0 +1,34 @@
+using COA.CodeSearch.McpServer.Models;
+using COA.CodeSearch.McpServer.Services.Editor;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// What the current tool invocation should be told about how its arguments were read: deprecated parameter
+/// names it used and arguments taken from the editor context. Set while the parameters are validated and
+/// added to the response by <see cref="SchemaInfoMiddleware"/>; flows with the async call chain like
+/// <see cref="Services.Logging.QueryUsage"/>.
+/// </summary>
+public static class ToolCallNotes
+{
+    private static readonly AsyncLocal<IReadOnlyList<DeprecationNotice>?> _deprecations = new();
+    private static readonly AsyncLocal<IReadOnlyList<EditorContextUse>?> _editorContextUses = new();
+
+    /// <summary>
+    /// Deprecated parameters the client used in the current invocation
+    /// </summary>
+    public static IReadOnlyList<DeprecationNotice> Deprecations
+    {
+        get => _deprecations.Value ?? Array.Empty<DeprecationNotice>();
+        set => _deprecations.Value = value;
+    }
+
+    /// <summary>
+    /// Arguments the current invocation took from the editor context because the call left them empty
+    /// </summary>
+    public static IReadOnlyList<EditorContextUse> EditorContextUses
+    {
+        get => _editorContextUses.Value ?? Array.Empty<EditorContextUse>();
+        set => _editorContextUses.Value = value;
+    }
+}
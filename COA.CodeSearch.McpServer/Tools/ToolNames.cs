@@ -9,10 +9,13 @@ public static class ToolNames
     public const string IndexWorkspace = "index_workspace";
     public const string TextSearch = "text_search";
     public const string FileSearch = "file_search";
+    public const string DependencyCode = "dependency_code";
+    public const string VendoredProvenance = "vendored_provenance";
     
     // Advanced search operations
     public const string LineSearch = "line_search";
     public const string SearchAndReplace = "search_and_replace";
+    public const string SmartSearch = "smart_search";
     
     // File and directory operations
     public const string SearchFiles = "search_files";
@@ -24,6 +27,26 @@ public static class ToolNames
     public const string FindReferences = "find_references";
     public const string GoToDefinition = "goto_definition";
     public const string TraceCallPath = "trace_call_path";
+    public const string GraphQuery = "graph_query";
+    public const string TaintPaths = "taint_paths";
+    public const string NullableFlow = "nullable_flow";
+    public const string TypeOf = "type_of";
+    public const string Hover = "hover";
+    public const string DataFlow = "data_flow";
+    public const string FindImplementations = "find_implementations";
+    public const string FindTests = "find_tests";
+    public const string FindConstantUsages = "find_constant_usages";
+    public const string OrmEntities = "orm_entities";
+    public const string ColumnUsages = "column_usages";
+    public const string MigrationSchema = "migration_schema";
+    public const string OpenApiLinks = "openapi_links";
+    public const string GraphQLLinks = "graphql_links";
+    public const string GoBuildProfile = "go_build_profile";
+    public const string GoPackageGraph = "go_package_graph";
+    public const string TraceErrorChain = "trace_error_chain";
+    public const string ListBuildTargets = "list_build_targets";
+    public const string CiPipelines = "ci_pipelines";
+    public const string KubernetesManifests = "k8s_manifests";
     
     // Editing tools
     public const string EditLines = "edit_lines";
@@ -35,7 +58,48 @@ public static class ToolNames
     public const string GetSymbolsOverview = "get_symbols_overview";
     public const string ReadSymbols = "read_symbols";
     public const string FindPatterns = "find_patterns";
+    public const string FindSimilarCode = "find_similar_code";
+    public const string DeadBranches = "dead_branches";
+    public const string ApiContracts = "api_contracts";
+    public const string MessageFlows = "message_flows";
+    public const string WorkspaceOverview = "workspace_overview";
+    public const string CheckArchitecture = "check_architecture";
+    public const string CheckNamespaces = "check_namespaces";
+    public const string CheckNaming = "check_naming";
+    public const string CheckDocDrift = "check_doc_drift";
+    public const string FindCommentedCode = "find_commented_code";
+    public const string FindUnreferencedAssets = "find_unreferenced_assets";
+    public const string FindDuplicateAssets = "find_duplicate_assets";
+
+    // Code review tools
+    public const string ReviewContext = "review_context";
+    public const string SuggestReviewers = "suggest_reviewers";
+    public const string DiffSummary = "diff_summary";
+    public const string SnapshotDiff = "snapshot_diff";
+    public const string DetectRenames = "detect_renames";
+
+    // Scratch workspace tools
+    public const string StageScratch = "stage_scratch";
+    public const string ScratchDiff = "scratch_diff";
+    public const string SearchScratch = "search_scratch";
+    public const string CommitScratch = "commit_scratch";
+
+    // Editor integration
+    public const string SetEditorContext = "set_editor_context";
+    public const string WorkingSet = "working_set";
 
     // Refactoring tools
     public const string SmartRefactor = "smart_refactor";
+    public const string RunRecipe = "run_recipe";
+    public const string RewriteImports = "rewrite_imports";
+
+    // Index maintenance tools
+    public const string PurgeIndex = "purge_index";
+    public const string Watch = "watch";
+
+    // Diagnostics tools
+    public const string GetLogs = "get_logs";
+    public const string SetLogLevel = "set_log_level";
+    public const string Capabilities = "capabilities";
+    public const string QueryHelp = "query_help";
 }
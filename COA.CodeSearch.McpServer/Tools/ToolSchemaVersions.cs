@@ -0,0 +1,30 @@
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Input/output schema version for each tool. Bump a tool's version whenever its parameters or result shape
+/// change incompatibly, and keep renamed parameters as <see cref="DeprecatedParameterAttribute"/> aliases
+/// until the version named in RemovedIn.
+/// </summary>
+public static class ToolSchemaVersions
+{
+    /// <summary>
+    /// Version of every tool that has never changed shape
+    /// </summary>
+    public const string Default = "1";
+
+    // Add a tool here, with a comment naming the change, when its schema first moves past Default
+    private static readonly Dictionary<string, string> _versions = new(StringComparer.OrdinalIgnoreCase);
+
+    /// <summary>
+    /// Gets the current schema version for a tool
+    /// </summary>
+    public static string GetVersion(string toolName)
+    {
+        return _versions.TryGetValue(toolName, out var version) ? version : Default;
+    }
+
+    /// <summary>
+    /// All tools whose schema has moved past <see cref="Default"/>
+    /// </summary>
+    public static IReadOnlyDictionary<string, string> Versioned => _versions;
+}
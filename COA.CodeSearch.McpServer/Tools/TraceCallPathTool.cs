@@ -115,6 +115,7 @@ public class TraceCallPathTool : CodeSearchToolBase<TraceCallPathParameters, AIO
         var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
             ? _pathResolutionService.GetPrimaryWorkspacePath()
             : Path.GetFullPath(parameters.WorkspacePath);
+        await EnsurePendingChangesIndexedAsync(workspacePath, parameters.SkipPendingEdits, cancellationToken);
         
         // Generate cache key
         var cacheKey = _keyGenerator.GenerateKey(Name, parameters);
@@ -325,6 +326,13 @@ public class TraceCallPathTool : CodeSearchToolBase<TraceCallPathParameters, AIO
         // Convert CallPathNodes to SearchHits for compatibility with response builder
         var hits = ConvertCallPathNodesToSearchHits(callPathNodes, symbolName, parameters.Direction);
 
+        var positions = CreateSourcePositions(workspacePath);
+        foreach (var hit in hits)
+        {
+            hit.Utf16Column = await positions.ToUtf16ColumnAsync(
+                hit.FilePath, hit.StartLine!.Value, hit.Column!.Value, cancellationToken);
+        }
+
         return new COA.CodeSearch.McpServer.Services.Lucene.SearchResult
         {
             TotalHits = hits.Count,
@@ -354,6 +362,8 @@ public class TraceCallPathTool : CodeSearchToolBase<TraceCallPathParameters, AIO
             {
                 FilePath = node.Identifier.FilePath,
                 StartLine = node.Identifier.StartLine,
+                Column = node.Identifier.StartColumn,
+                ByteOffset = node.Identifier.StartByte,
                 Snippet = node.Identifier.CodeContext ?? string.Empty,
                 ContextLines = contextLines,
                 Score = 1.0f - (node.Depth * 0.1f), // Higher score for shallower depth
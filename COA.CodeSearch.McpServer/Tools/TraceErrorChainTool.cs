@@ -0,0 +1,215 @@
+using System.Text.RegularExpressions;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Traces how a Go error travels: from the sentinel, error type or errors.New/fmt.Errorf site that makes it,
+/// through the callers wrapping it with %w or returning it, to the errors.Is/errors.As checks that look for it
+/// </summary>
+public class TraceErrorChainTool : CodeSearchToolBase<TraceErrorChainParameters, AIOptimizedResponse<TraceErrorChainResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<TraceErrorChainTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the TraceErrorChainTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public TraceErrorChainTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<TraceErrorChainTool> logger) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.TraceErrorChain;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHERE DOES THIS GO ERROR END UP? Give a sentinel (ErrNotFound), an error type (ValidationError) or the path:line of an " +
+        "errors.New/fmt.Errorf and get the propagation paths: which callers wrap it with fmt.Errorf(\"...: %w\", err) - with the " +
+        "message it reads as after each wrap - which return it unchanged, which swallow it, and the errors.Is/errors.As checks " +
+        "that look for it, on the path or elsewhere. Sentinels wrapping the sentinel count as it, as errors.Is does.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Scans the workspace's Go files and traces the error through them.
+    /// </summary>
+    /// <param name="parameters">Error, depth and filters</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The propagation paths, hops and checks</returns>
+    protected override async Task<AIOptimizedResponse<TraceErrorChainResult>> ExecuteInternalAsync(
+        TraceErrorChainParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var error = ValidateRequired(parameters.Error, nameof(parameters.Error)).Trim();
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try trace_error_chain again");
+        }
+
+        var files = await ScanAsync(workspacePath, cancellationToken);
+        if (files.Count == 0)
+        {
+            return CreateErrorResponse("NO_GO_FILES", "The workspace has no indexed Go files",
+                "trace_error_chain follows Go error wrapping - check the workspace path");
+        }
+
+        // Positions may be absolute; the scanned files are workspace-relative
+        var position = Regex.Match(error, @"^(?<path>.+?):(?<line>\d+(?::\d+)?)$");
+        if (position.Success && Path.IsPathRooted(position.Groups["path"].Value))
+        {
+            error = $"{Relative(workspacePath, position.Groups["path"].Value)}:{position.Groups["line"].Value}";
+        }
+
+        var trace = GoErrorChains.Trace(files, error, parameters.MaxDepth, parameters.IncludeTests);
+        if (trace == null)
+        {
+            var name = error.Split('.').Last();
+            var similar = files.SelectMany(f => f.Sentinels.Select(s => s.Name).Concat(f.Types.Select(t => t.Name)))
+                .Where(n => n.Contains(name.Replace("Err", ""), StringComparison.OrdinalIgnoreCase))
+                .Distinct()
+                .Take(5)
+                .ToList();
+            return CreateErrorResponse("ERROR_NOT_FOUND", $"No sentinel, error type or error construction matches '{parameters.Error}'",
+                similar.Count > 0 ? $"Similar errors: {string.Join(", ", similar)}" : "Sentinels are package-level vars set by errors.New or fmt.Errorf",
+                "A position must be the line of an errors.New, fmt.Errorf or error composite literal inside a function");
+        }
+
+        var limit = Math.Clamp(parameters.MaxResults, 1, 1000);
+        var result = new TraceErrorChainResult
+        {
+            Origin = trace.Origin,
+            Paths = trace.Paths.Take(limit).ToList(),
+            Hops = trace.Hops.Take(limit).ToList(),
+            Checks = trace.Checks.OrderByDescending(c => c.OnPath).Take(limit).ToList(),
+            FilesScanned = files.Count,
+            DepthLimited = trace.DepthLimited,
+            Truncated = trace.Hops.Count > limit || trace.Checks.Count > limit || trace.Paths.Count > limit
+        };
+
+        _logger.LogDebug("trace_error_chain: {Hops} hops and {Checks} checks for {Error} in {Files} Go files",
+            trace.Hops.Count, trace.Checks.Count, error, files.Count);
+
+        var producers = trace.Hops.Count(h => h.How == "produces");
+        var response = new AIOptimizedResponse<TraceErrorChainResult>
+        {
+            Success = true,
+            Data = new AIResponseData<TraceErrorChainResult> { Results = result },
+            Message = $"{trace.Origin.Name}: produced in {producers} function(s), {trace.Hops.Count(h => h.How == "wraps")} wrap(s), " +
+                      $"{trace.Hops.Count(h => h.How == "returns")} pass-through(s), {trace.Checks.Count} check(s)"
+        };
+
+        var insights = new List<string>();
+        var messages = trace.Hops.Where(h => h.How is "checks" or "handles").Select(h => h.Message).OfType<string>().Distinct().ToList();
+        if (messages.Count > 0)
+        {
+            insights.Add($"Reads as: {string.Join(" | ", messages.Take(3).Select(m => $"\"{m}\""))}");
+        }
+        if (producers == 0)
+        {
+            insights.Add("Nothing returns or wraps it directly - it may only be returned through a variable or another package's alias");
+        }
+        var swallowed = trace.Hops.Count(h => h.How == "handles");
+        if (swallowed > 0)
+        {
+            insights.Add($"{swallowed} caller(s) neither wrap nor return it - the error is logged, replaced or dropped there");
+        }
+        if (trace.Origin.Matches.Count > 0 && trace.Checks.Count == 0)
+        {
+            insights.Add("No errors.Is/errors.As/== check looks for it - callers only ever see the message");
+        }
+        var offPath = trace.Checks.Count(c => !c.OnPath);
+        if (offPath > 0)
+        {
+            insights.Add($"{offPath} check(s) are in functions the trace didn't reach" +
+                         (parameters.IncludeTests ? "" : " (tests are not followed - pass includeTests)"));
+        }
+        if (trace.DepthLimited)
+        {
+            insights.Add($"Callers beyond depth {parameters.MaxDepth} were not followed - raise maxDepth to see further");
+        }
+        insights.Add("Callers are matched by function name and package qualifier, so same-named functions elsewhere can add paths");
+        response.Insights = insights;
+
+        if (trace.Origin.Kind != "site")
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction { Action = ToolNames.FindReferences, Description = $"All references to {trace.Origin.Name}", Priority = 60 }
+            };
+        }
+
+        return response;
+    }
+
+    /// <summary>
+    /// Error statements of every indexed Go file outside vendor and testdata directories
+    /// </summary>
+    private async Task<List<GoErrorFile>> ScanAsync(string workspacePath, CancellationToken cancellationToken)
+    {
+        var files = new List<GoErrorFile>();
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            var relative = Relative(workspacePath, file.Path);
+            if (!relative.EndsWith(".go", StringComparison.OrdinalIgnoreCase)
+                || relative.Split('/').Any(s => s is "vendor" or "testdata" || s.StartsWith('_') || s.StartsWith('.')))
+                continue;
+            cancellationToken.ThrowIfCancellationRequested();
+
+            var content = file.Content ?? await ReadAsync(Path.Combine(workspacePath, relative), cancellationToken);
+            if (content != null)
+            {
+                files.Add(GoErrorChains.Scan(relative, content));
+            }
+        }
+        return files;
+    }
+
+    private static async Task<string?> ReadAsync(string fullPath, CancellationToken cancellationToken) =>
+        File.Exists(fullPath) ? await File.ReadAllTextAsync(fullPath, cancellationToken) : null;
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<TraceErrorChainResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
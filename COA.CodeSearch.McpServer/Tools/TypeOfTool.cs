@@ -0,0 +1,146 @@
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.GoTypes;
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Reports what go/types knows about the Go expression at a position - its type, underlying type, constant
+/// value and the object it denotes. Tree-sitter only sees names, so the index cannot answer this.
+/// </summary>
+public class TypeOfTool : CodeSearchToolBase<TypeOfParameters, AIOptimizedResponse<GoTypeInfo>>
+{
+    private readonly IGoTypesService _goTypes;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<TypeOfTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the TypeOfTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="goTypes">go/types tier</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public TypeOfTool(
+        IServiceProvider serviceProvider,
+        IGoTypesService goTypes,
+        IPathResolutionService pathResolutionService,
+        ILogger<TypeOfTool> logger) : base(serviceProvider, logger)
+    {
+        _goTypes = goTypes;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.TypeOf;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "GO TYPE AT A POSITION - What type does this Go expression have? Returns the type-checked type, its underlying type, constant value and the declaration it refers to. " +
+        "Use instead of reading through declarations and inferring := types by hand. Requires the go/types tier (CodeSearch:GoTypes:Enabled).";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Executes the type query at the position.
+    /// </summary>
+    /// <param name="parameters">Position and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Type, mode and denoted object at the position</returns>
+    protected override async Task<AIOptimizedResponse<GoTypeInfo>> ExecuteInternalAsync(
+        TypeOfParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var position = ValidateRequired(parameters.Position, nameof(parameters.Position));
+        if (!SourcePositions.TryParseLocation(position, out var filePath, out var line, out var column))
+        {
+            return Failure("INVALID_POSITION", $"'{position}' is not a position 'path:line:column'",
+                "Use a 1-based line and 0-based column, e.g. internal/store/users.go:42:9");
+        }
+        if (!SourcePositions.IsValidEncoding(parameters.ColumnEncoding))
+        {
+            return Failure("INVALID_COLUMN_ENCODING", $"Unknown column encoding '{parameters.ColumnEncoding}'",
+                "Use 'utf-16' for LSP positions or 'utf-8' for byte columns");
+        }
+        if (!_goTypes.IsEnabled)
+        {
+            return Failure("GO_TYPES_DISABLED", "Type queries need the go/types tier, which is disabled",
+                "Set CodeSearch:GoTypes:Enabled to true in appsettings.json and restart the server");
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : await ResolveWorkspacePathAsync(parameters.WorkspacePath, cancellationToken);
+
+        var fullPath = Path.IsPathRooted(filePath) ? filePath : Path.Combine(workspacePath, filePath);
+        if (!_goTypes.Handles(fullPath))
+        {
+            return Failure("NOT_GO", $"{filePath} is not a Go file", "type_of only answers for .go files");
+        }
+
+        var byteColumn = string.Equals(parameters.ColumnEncoding, SourcePositions.Utf8, StringComparison.OrdinalIgnoreCase)
+            ? column
+            : await CreateSourcePositions(workspacePath).ToByteColumnAsync(filePath, line, column, cancellationToken);
+
+        var result = await _goTypes.TypeOfAsync(workspacePath, fullPath, line, byteColumn, cancellationToken);
+        if (result == null)
+        {
+            _logger.LogInformation("No go/types answer for {Position}", position);
+            return Failure("NO_TYPE_INFO", $"No typed expression at {position} in a loaded Go module",
+                "Point the column at an identifier rather than whitespace, a keyword or punctuation",
+                "Check the file belongs to a module (go.mod or go.work) that 'go list ./...' can load",
+                "See the server log for codesearch-gotypes errors (the go toolchain must be on PATH)");
+        }
+
+        var response = new AIOptimizedResponse<GoTypeInfo>
+        {
+            Success = true,
+            Data = new AIResponseData<GoTypeInfo> { Results = result },
+            Message = result.Value != null
+                ? $"{result.Expression}: {result.Type} = {result.Value}"
+                : $"{result.Expression}: {result.Type}"
+        };
+
+        var insights = new List<string>();
+        if (result.Object?.Declared != null)
+        {
+            insights.Add($"Declared at {result.Object.Declared.File}:{result.Object.Declared.Line}: {result.Object.Signature}");
+        }
+        if (result.Underlying != null)
+        {
+            insights.Add($"Underlying type: {result.Underlying}");
+        }
+        if (result.TypeErrors is { Count: > 0 })
+        {
+            insights.Add($"The package has {result.TypeErrors.Count} type error(s); types involving them may show as invalid");
+        }
+        if (insights.Count > 0)
+        {
+            response.Insights = insights;
+        }
+
+        return response;
+    }
+
+    private static AIOptimizedResponse<GoTypeInfo> Failure(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,462 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Configuration;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Finds vendored and copied third-party code with <see cref="VendoredProvenance"/> - Go vendor/modules.txt and
+/// origin header comments - and compares each copy with its upstream version: Go vendor files with the module
+/// cache, header origins with the file downloaded at the recorded revision when fetch is on. Reads the disk
+/// rather than the index, which usually leaves vendor/ out.
+/// </summary>
+public class VendoredProvenanceTool : CodeSearchToolBase<VendoredProvenanceParameters, AIOptimizedResponse<VendoredProvenanceResult>>
+{
+    private const long MaxFileBytes = 2 * 1024 * 1024;
+    private const int MaxFetches = 50;
+
+    private static readonly HttpClient Http = new() { Timeout = TimeSpan.FromSeconds(20) };
+
+    private readonly IDependencyCodeService _dependencyCode;
+    private readonly IConfiguration _configuration;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<VendoredProvenanceTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the VendoredProvenanceTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="dependencyCode">Dependency code settings naming the third-party directories</param>
+    /// <param name="configuration">Configuration with the directories and extensions indexing skips</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public VendoredProvenanceTool(
+        IServiceProvider serviceProvider,
+        IDependencyCodeService dependencyCode,
+        IConfiguration configuration,
+        IPathResolutionService pathResolutionService,
+        ILogger<VendoredProvenanceTool> logger) : base(serviceProvider, logger)
+    {
+        _dependencyCode = dependencyCode;
+        _configuration = configuration;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.VendoredProvenance;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WAS THIS VENDORED CODE PATCHED LOCALLY? Finds where vendored and copied third-party code came from - Go vendor/modules.txt " +
+        "module versions and 'Copied from <url>' style header comments - and diffs each copy against the recorded upstream version: " +
+        "Go vendor/ against the module cache, header origins against GitHub/GitLab at the recorded commit (fetch: true). " +
+        "Flags modified, added and unlisted files that the next go mod vendor or upstream update would silently overwrite.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Query;
+
+    /// <summary>
+    /// Finds the vendored and copied files and compares them with upstream.
+    /// </summary>
+    /// <param name="parameters">Scope and comparison options</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>Modules and files with their origins and modification status</returns>
+    protected override async Task<AIOptimizedResponse<VendoredProvenanceResult>> ExecuteInternalAsync(
+        VendoredProvenanceParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        var scope = parameters.Path?.Replace('\\', '/').Trim('/');
+        var root = string.IsNullOrEmpty(scope) ? workspacePath : Path.Combine(workspacePath, scope);
+        if (!Directory.Exists(root))
+        {
+            return CreateErrorResponse("PATH_NOT_FOUND", $"'{scope ?? workspacePath}' is not a directory",
+                "Pass a workspace-relative directory as path, or omit it to check the whole workspace");
+        }
+
+        var files = await Task.Run(() => EnumerateFiles(workspacePath, root)
+            .Select(f => Path.GetRelativePath(workspacePath, f).Replace('\\', '/'))
+            .OrderBy(f => f, StringComparer.Ordinal)
+            .ToList(), cancellationToken);
+
+        var result = new VendoredProvenanceResult { WorkspacePath = workspacePath, FilesScanned = files.Count };
+        var diffs = 0;
+        void Record(VendoredFileStatus file, VendoredComparison? comparison)
+        {
+            if (comparison != null)
+            {
+                file.AddedLines = comparison.Added;
+                file.RemovedLines = comparison.Removed;
+                if (comparison.Diff != null && diffs < parameters.MaxDiffs)
+                {
+                    file.Diff = comparison.Diff;
+                    diffs++;
+                }
+            }
+            if (file.Status == "modified" || file.Status == "added" || file.Status == "unlisted")
+                result.Modified++;
+            if (file.Status == "unverified")
+                result.Unverified++;
+            if (file.Status is not ("clean" or "unchecked") || file.Source == "header" || parameters.ShowClean)
+                result.Files.Add(file);
+        }
+
+        // Go vendor directories: the files go mod vendor copied from the modules modules.txt lists
+        var vendorDirectories = files
+            .Where(f => f == "vendor/modules.txt" || f.EndsWith("/vendor/modules.txt", StringComparison.Ordinal))
+            .Select(f => f[..^"/modules.txt".Length])
+            .ToList();
+        if (vendorDirectories.Count > 0)
+        {
+            result.ModuleCache = parameters.ModuleCache ?? DefaultModuleCache();
+        }
+
+        foreach (var vendorDirectory in vendorDirectories)
+        {
+            var modules = VendoredProvenance.ParseModulesTxt(
+                await File.ReadAllTextAsync(Path.Combine(workspacePath, vendorDirectory, "modules.txt"), cancellationToken));
+            var statuses = modules.Where(m => m.Version.Length > 0).ToDictionary(m => m, m => new VendoredModuleStatus
+            {
+                VendorDirectory = vendorDirectory,
+                Path = m.Path,
+                Version = m.Version,
+                Replacement = m.Replacement == null ? null : $"{m.Replacement} {m.ReplacementVersion}".TrimEnd()
+            });
+            var goModDirectory = Path.GetDirectoryName(Path.Combine(workspacePath, vendorDirectory))!;
+
+            foreach (var file in files.Where(f => f.StartsWith(vendorDirectory + "/", StringComparison.Ordinal) && f != vendorDirectory + "/modules.txt"))
+            {
+                cancellationToken.ThrowIfCancellationRequested();
+                var owner = VendoredProvenance.FindModule(statuses.Keys, file[(vendorDirectory.Length + 1)..]);
+                if (owner == null)
+                {
+                    Record(new VendoredFileStatus
+                    {
+                        FilePath = file,
+                        Source = "go-vendor",
+                        Status = "unlisted",
+                        Reason = $"No module in {vendorDirectory}/modules.txt provides this file - go mod vendor will delete it"
+                    }, null);
+                    continue;
+                }
+
+                var (module, pathInModule) = owner.Value;
+                var status = statuses[module];
+                status.Files++;
+                var upstreamDirectory = module.IsLocalReplacement
+                    ? Path.GetFullPath(module.Replacement!, goModDirectory)
+                    : VendoredProvenance.ModuleCacheDirectory(result.ModuleCache!, module.Replacement ?? module.Path, module.ReplacementVersion ?? module.Version);
+                var entry = new VendoredFileStatus
+                {
+                    FilePath = file,
+                    Source = "go-vendor",
+                    Origin = $"{module.Path}@{module.Version}",
+                    Revision = module.ReplacementVersion ?? module.Version
+                };
+
+                if (!parameters.CheckModifications)
+                {
+                    entry.Status = "unchecked";
+                    Record(entry, null);
+                    continue;
+                }
+                if (result.FilesCompared >= parameters.MaxFiles)
+                {
+                    result.Truncated = true;
+                    entry.Status = "unverified";
+                    entry.Reason = "Not compared - maxFiles reached";
+                    status.Unverified++;
+                    Record(entry, null);
+                    continue;
+                }
+                if (!Directory.Exists(upstreamDirectory))
+                {
+                    entry.Status = "unverified";
+                    entry.Reason = module.IsLocalReplacement
+                        ? $"Replacement directory {module.Replacement} does not exist"
+                        : $"Not in the module cache - run go mod download {module.Replacement ?? module.Path}@{module.ReplacementVersion ?? module.Version}";
+                    status.Unverified++;
+                    Record(entry, null);
+                    continue;
+                }
+
+                var upstreamFile = Path.Combine(upstreamDirectory, pathInModule.Replace('/', Path.DirectorySeparatorChar));
+                result.FilesCompared++;
+                if (!File.Exists(upstreamFile))
+                {
+                    entry.Status = "added";
+                    entry.Reason = $"Not in {entry.Origin} - added locally";
+                    status.Modified++;
+                    Record(entry, null);
+                    continue;
+                }
+
+                var comparison = VendoredProvenance.Compare(
+                    await File.ReadAllTextAsync(upstreamFile, cancellationToken),
+                    await File.ReadAllTextAsync(Path.Combine(workspacePath, file), cancellationToken),
+                    file, parameters.ContextLines);
+                entry.Status = comparison.Identical ? "clean" : "modified";
+                if (!comparison.Identical)
+                    status.Modified++;
+                Record(entry, comparison);
+            }
+
+            foreach (var status in statuses.Values.Where(s => s.Files > 0))
+            {
+                status.Status = !parameters.CheckModifications ? "unchecked"
+                    : status.Modified > 0 ? "modified" : status.Unverified > 0 ? "unverified" : "clean";
+                result.Modules.Add(status);
+            }
+        }
+
+        // Files anywhere else whose header comments say where they were copied from
+        var fetched = new Dictionary<string, string?>(StringComparer.Ordinal);
+        foreach (var file in files.Where(f => !vendorDirectories.Any(v => f.StartsWith(v + "/", StringComparison.Ordinal))))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            var fullPath = Path.Combine(workspacePath, file);
+            var origin = VendoredProvenance.DetectOrigin(await ReadHeadAsync(fullPath, cancellationToken));
+            if (origin == null)
+                continue;
+
+            var entry = new VendoredFileStatus
+            {
+                FilePath = file,
+                Source = "header",
+                Origin = origin.Url,
+                Revision = origin.Revision,
+                Status = "unverified"
+            };
+            var rawUrl = VendoredProvenance.RawUrl(origin);
+
+            if (!parameters.CheckModifications)
+            {
+                entry.Status = "unchecked";
+            }
+            else if (rawUrl == null)
+            {
+                entry.Reason = origin.Repository == null
+                    ? "Origin is not a GitHub or GitLab URL - compare by hand"
+                    : origin.UpstreamPath == null
+                        ? "Origin names a repository but no file - link the file (…/blob/<commit>/<path>) to compare"
+                        : "Origin records no commit, tag or version - add one so the copy can be compared";
+            }
+            else if (!parameters.Fetch)
+            {
+                entry.Reason = $"Upstream at {origin.Revision} not downloaded - pass fetch: true to compare";
+            }
+            else if (result.FilesCompared >= parameters.MaxFiles || !fetched.ContainsKey(rawUrl) && fetched.Count >= MaxFetches)
+            {
+                result.Truncated = true;
+                entry.Reason = "Not compared - download limit reached";
+            }
+            else
+            {
+                if (!fetched.TryGetValue(rawUrl, out var upstream))
+                {
+                    upstream = await FetchAsync(rawUrl, cancellationToken);
+                    fetched[rawUrl] = upstream;
+                }
+
+                if (upstream == null)
+                {
+                    entry.Reason = $"Could not download {rawUrl}";
+                }
+                else
+                {
+                    result.FilesCompared++;
+                    var comparison = VendoredProvenance.Compare(upstream, await File.ReadAllTextAsync(fullPath, cancellationToken), file, parameters.ContextLines);
+                    entry.Status = comparison.Identical ? "clean" : "modified";
+                    entry.Reason = comparison.HeaderOnly ? "Matches upstream apart from the header comment" : null;
+                    Record(entry, comparison);
+                    continue;
+                }
+            }
+            Record(entry, null);
+        }
+
+        _logger.LogDebug("vendored_provenance {Workspace}: {Modules} module(s), {Files} file(s), {Modified} modified, {Unverified} unverified",
+            workspacePath, result.Modules.Count, result.Files.Count, result.Modified, result.Unverified);
+
+        var copies = result.Modules.Count + result.Files.Count(f => f.Source == "header");
+        var response = CreateResponse(result, copies == 0
+            ? $"No vendor/modules.txt or origin header comments in {result.FilesScanned} file(s)"
+            : $"{result.Modules.Count} vendored module(s) and {result.Files.Count(f => f.Source == "header")} copied file(s): " +
+              $"{result.Modified} modified, {result.Unverified} unverified");
+        response.Insights = BuildInsights(result, parameters);
+        if (!parameters.Fetch && result.Files.Any(f => f.Source == "header" && f.Reason?.Contains("fetch: true", StringComparison.Ordinal) == true))
+        {
+            response.Actions = new List<AIAction>
+            {
+                new AIAction { Action = ToolNames.VendoredProvenance, Description = "Run again with fetch: true to download the upstream files and compare", Priority = 70 }
+            };
+        }
+        return response;
+    }
+
+    private static List<string> BuildInsights(VendoredProvenanceResult result, VendoredProvenanceParameters parameters)
+    {
+        var insights = new List<string>();
+        var patched = result.Modules.Where(m => m.Modified > 0).ToList();
+        if (patched.Count > 0)
+        {
+            insights.Add($"{patched.Count} vendored module(s) were changed locally ({string.Join(", ", patched.Take(3).Select(m => $"{m.Path}@{m.Version}"))}) - " +
+                         "go mod vendor overwrites vendor/, so move the changes to a fork with a replace directive");
+        }
+        if (result.Files.Any(f => f.Status == "unlisted"))
+        {
+            insights.Add($"{result.Files.Count(f => f.Status == "unlisted")} file(s) in vendor/ belong to no module in modules.txt - go mod vendor deletes them");
+        }
+        var missing = result.Modules.Count(m => m.Status == "unverified");
+        if (missing > 0)
+        {
+            insights.Add($"{missing} module(s) are not in the module cache at {result.ModuleCache} - run go mod download, or pass moduleCache");
+        }
+        if (result.Files.Any(f => f.Source == "header" && f.Status == "modified"))
+        {
+            insights.Add("Copied files that differ from upstream need their changes re-applied by hand when the copy is refreshed - note them beside the origin comment");
+        }
+        if (result.Truncated)
+        {
+            insights.Add($"Stopped comparing after {result.FilesCompared} file(s) - narrow with path or raise maxFiles");
+        }
+        if (!parameters.ShowClean && result.Modules.Count > 0)
+        {
+            insights.Add("Unmodified vendor files are counted per module only - pass showClean: true to list them");
+        }
+        return insights;
+    }
+
+    /// <summary>
+    /// GOMODCACHE, else the first GOPATH entry's pkg/mod, else ~/go/pkg/mod - as the go command decides
+    /// </summary>
+    private static string DefaultModuleCache()
+    {
+        var moduleCache = Environment.GetEnvironmentVariable("GOMODCACHE");
+        if (!string.IsNullOrWhiteSpace(moduleCache))
+            return moduleCache;
+
+        var goPath = Environment.GetEnvironmentVariable("GOPATH")?.Split(Path.PathSeparator).FirstOrDefault(p => p.Length > 0)
+            ?? Path.Combine(Environment.GetFolderPath(Environment.SpecialFolder.UserProfile), "go");
+        return Path.Combine(goPath, "pkg", "mod");
+    }
+
+    /// <summary>
+    /// Files under the scope, skipping the directories indexing skips except vendor and the dependency
+    /// directories, which are what this tool is about
+    /// </summary>
+    private IEnumerable<string> EnumerateFiles(string workspacePath, string root)
+    {
+        var dependencyDirectories = _dependencyCode.GetSettings(workspacePath).Directories
+            .Where(d => !d.Contains('/'))
+            .Append("vendor");
+        var excluded = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Lucene:ExcludedDirectories").Get<string[]>() ?? PathConstants.DefaultExcludedDirectories,
+            StringComparer.OrdinalIgnoreCase);
+        excluded.ExceptWith(dependencyDirectories);
+        var blacklisted = new HashSet<string>(
+            _configuration.GetSection("CodeSearch:Indexing:BlacklistedExtensions").Get<string[]>() ?? PathConstants.DefaultBlacklistedExtensions,
+            StringComparer.OrdinalIgnoreCase);
+
+        var pending = new Stack<string>();
+        pending.Push(root);
+        while (pending.Count > 0)
+        {
+            var directory = pending.Pop();
+            string[] entries;
+            string[] subdirectories;
+            try
+            {
+                entries = Directory.GetFiles(directory);
+                subdirectories = Directory.GetDirectories(directory);
+            }
+            catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+            {
+                continue;
+            }
+
+            foreach (var file in entries.Where(f => !blacklisted.Contains(Path.GetExtension(f))))
+                yield return file;
+
+            foreach (var subdirectory in subdirectories.Where(d => !excluded.Contains(Path.GetFileName(d))))
+                pending.Push(subdirectory);
+        }
+    }
+
+    /// <summary>
+    /// The first lines of a file - enough for <see cref="VendoredProvenance.DetectOrigin"/> - or nothing for
+    /// large and unreadable files
+    /// </summary>
+    private static async Task<string> ReadHeadAsync(string filePath, CancellationToken cancellationToken)
+    {
+        try
+        {
+            var info = new FileInfo(filePath);
+            if (info.Length > MaxFileBytes)
+                return string.Empty;
+
+            using var reader = new StreamReader(filePath, detectEncodingFromByteOrderMarks: true);
+            var buffer = new char[8192];
+            var read = await reader.ReadBlockAsync(buffer.AsMemory(), cancellationToken);
+            var head = new string(buffer, 0, read);
+            return head.Contains('\0') ? string.Empty : head;
+        }
+        catch (Exception ex) when (ex is IOException or UnauthorizedAccessException)
+        {
+            return string.Empty;
+        }
+    }
+
+    private async Task<string?> FetchAsync(string url, CancellationToken cancellationToken)
+    {
+        try
+        {
+            using var response = await Http.GetAsync(url, cancellationToken);
+            if (!response.IsSuccessStatusCode)
+            {
+                _logger.LogDebug("Upstream download {Url} returned {Status}", url, response.StatusCode);
+                return null;
+            }
+            return await response.Content.ReadAsStringAsync(cancellationToken);
+        }
+        catch (Exception ex) when (ex is HttpRequestException or TaskCanceledException && !cancellationToken.IsCancellationRequested)
+        {
+            _logger.LogDebug(ex, "Upstream download {Url} failed", url);
+            return null;
+        }
+    }
+
+    private static AIOptimizedResponse<VendoredProvenanceResult> CreateResponse(VendoredProvenanceResult result, string message) => new()
+    {
+        Success = true,
+        Data = new AIResponseData<VendoredProvenanceResult> { Results = result },
+        Message = message
+    };
+
+    private static AIOptimizedResponse<VendoredProvenanceResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,165 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Watches;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Manages standing queries ("any new usage of Thread.Sleep", "any new TODO in payments/") that the file watcher
+/// evaluates on every incremental index update, and returns the alerts they raised
+/// </summary>
+public class WatchTool : CodeSearchToolBase<WatchParameters, AIOptimizedResponse<WatchResult>>
+{
+    private readonly IWatchService _watchService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<WatchTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the WatchTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="watchService">Watch registry and alert store</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public WatchTool(
+        IServiceProvider serviceProvider,
+        IWatchService watchService,
+        IPathResolutionService pathResolutionService,
+        ILogger<WatchTool> logger) : base(serviceProvider, logger)
+    {
+        _watchService = watchService;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.Watch;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "STANDING QUERIES - Register a watch (pattern plus optional file glob) and every file change the index picks up is checked for " +
+        "lines that newly match, e.g. new Thread.Sleep calls or new TODOs under payments/. Matches are pushed as notifications and " +
+        "kept as alerts: fetch them with action: alerts and since: lastSequence.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Adds, lists or removes watches, or returns their alerts.
+    /// </summary>
+    /// <param name="parameters">Action, watch definition and workspace</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The workspace's watches and requested alerts</returns>
+    protected override Task<AIOptimizedResponse<WatchResult>> ExecuteInternalAsync(
+        WatchParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+        var action = parameters.Action.ToLowerInvariant();
+
+        try
+        {
+            return Task.FromResult(action switch
+            {
+                "add" => Add(workspacePath, parameters),
+                "list" => List(workspacePath),
+                "remove" => Remove(workspacePath, parameters),
+                "alerts" => Alerts(workspacePath, parameters),
+                _ => CreateErrorResponse("INVALID_ACTION", $"Unknown action '{parameters.Action}'", "Use add, list, remove or alerts")
+            });
+        }
+        catch (WatchException ex)
+        {
+            return Task.FromResult(CreateErrorResponse(ex.Code, ex.Message, "Fix the watch and add it again"));
+        }
+    }
+
+    private AIOptimizedResponse<WatchResult> Add(string workspacePath, WatchParameters parameters)
+    {
+        var watch = _watchService.Add(workspacePath, new WatchDefinition
+        {
+            Name = parameters.Name ?? string.Empty,
+            Pattern = parameters.Pattern ?? string.Empty,
+            IsRegex = parameters.IsRegex,
+            CaseSensitive = parameters.CaseSensitive,
+            FilePattern = parameters.FilePattern
+        });
+
+        var result = new WatchResult { Action = "add", Watches = new List<WatchDefinition> { watch } };
+        var response = CreateResponse(result,
+            $"Watching {(watch.FilePattern != null ? $"'{watch.FilePattern}'" : "all files")} for new lines matching '{watch.Pattern}' (id {watch.Id})");
+        response.Actions = new List<AIAction>
+        {
+            new AIAction { Action = ToolNames.Watch, Description = "Fetch matches later with action: alerts", Priority = 60 }
+        };
+        return response;
+    }
+
+    private AIOptimizedResponse<WatchResult> List(string workspacePath)
+    {
+        var watches = _watchService.List(workspacePath).ToList();
+        return CreateResponse(new WatchResult { Action = "list", Watches = watches },
+            watches.Count == 0 ? "No watches in this workspace" : $"{watches.Count} watch(es)");
+    }
+
+    private AIOptimizedResponse<WatchResult> Remove(string workspacePath, WatchParameters parameters)
+    {
+        if (string.IsNullOrWhiteSpace(parameters.WatchId))
+        {
+            return CreateErrorResponse("MISSING_WATCH_ID", "remove needs a watchId", "Use action: list to see the watch ids");
+        }
+        if (!_watchService.Remove(workspacePath, parameters.WatchId))
+        {
+            return CreateErrorResponse("WATCH_NOT_FOUND", $"No watch '{parameters.WatchId}' in this workspace", "Use action: list to see the watch ids");
+        }
+
+        _logger.LogInformation("Removed watch {Id} from {Workspace}", parameters.WatchId, workspacePath);
+        return CreateResponse(new WatchResult { Action = "remove", Watches = _watchService.List(workspacePath).ToList() },
+            $"Removed watch {parameters.WatchId}");
+    }
+
+    private AIOptimizedResponse<WatchResult> Alerts(string workspacePath, WatchParameters parameters)
+    {
+        var alerts = _watchService.GetAlerts(workspacePath, parameters.WatchId, parameters.Since).ToList();
+        var result = new WatchResult
+        {
+            Action = "alerts",
+            Alerts = alerts,
+            LastSequence = alerts.Count > 0 ? alerts[^1].Sequence : parameters.Since
+        };
+
+        return CreateResponse(result, alerts.Count == 0
+            ? "No new watch alerts"
+            : $"{alerts.Count} alert(s) in {alerts.Select(a => a.FilePath).Distinct().Count()} file(s)");
+    }
+
+    private static AIOptimizedResponse<WatchResult> CreateResponse(WatchResult result, string message) => new()
+    {
+        Success = true,
+        Data = new AIResponseData<WatchResult> { Results = result },
+        Message = message
+    };
+
+    private static AIOptimizedResponse<WatchResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,143 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.WorkingSet;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Shows or clears the files and symbols the session has been working with, which searches rank higher
+/// </summary>
+public class WorkingSetTool : CodeSearchToolBase<WorkingSetParameters, AIOptimizedResponse<WorkingSetResult>>
+{
+    private readonly IWorkingSetService _workingSet;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly ILogger<WorkingSetTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the WorkingSetTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="workingSet">Session working set</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    public WorkingSetTool(
+        IServiceProvider serviceProvider,
+        IWorkingSetService workingSet,
+        IPathResolutionService pathResolutionService,
+        ILogger<WorkingSetTool> logger) : base(serviceProvider, logger)
+    {
+        _workingSet = workingSet;
+        _pathResolutionService = pathResolutionService;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.WorkingSet;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT AM I WORKING ON? Show the session's working set: files recently viewed, opened or edited and symbols looked up, each with a " +
+        "weight that fades over time. text_search and symbol_search rank these files, and files referencing or referenced by them, higher. " +
+        "Clear it when switching to an unrelated task so old files stop steering results.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Utility;
+
+    /// <summary>
+    /// Shows or clears the working set.
+    /// </summary>
+    /// <param name="parameters">Workspace and whether to clear</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The working set's files and symbols</returns>
+    protected override Task<AIOptimizedResponse<WorkingSetResult>> ExecuteInternalAsync(
+        WorkingSetParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        if (!_workingSet.Enabled)
+        {
+            return Task.FromResult(CreateErrorResponse("WORKING_SET_DISABLED", "The session working set is turned off",
+                "Set CodeSearch:WorkingSet:Enabled to true to track and boost the files a session works with"));
+        }
+
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (parameters.Clear)
+        {
+            var cleared = _workingSet.Clear(workspacePath);
+            _logger.LogDebug("Cleared working set of {WorkspacePath}: {Count} file(s)", workspacePath, cleared);
+            return Task.FromResult(new AIOptimizedResponse<WorkingSetResult>
+            {
+                Success = true,
+                Data = new AIResponseData<WorkingSetResult>
+                {
+                    Results = new WorkingSetResult { WorkspacePath = workspacePath, Cleared = cleared }
+                },
+                Message = cleared > 0 ? $"Forgot {cleared} working-set file(s)" : "The working set was already empty"
+            });
+        }
+
+        var entries = _workingSet.GetEntries(workspacePath);
+        var symbols = _workingSet.GetSymbols(workspacePath);
+        var direct = entries.Select(e => e.FilePath).ToHashSet(StringComparer.OrdinalIgnoreCase);
+        var result = new WorkingSetResult
+        {
+            WorkspacePath = workspacePath,
+            Files = entries.Take(parameters.MaxResults).ToList(),
+            Symbols = symbols.Take(parameters.MaxResults).ToList(),
+            NeighborCount = _workingSet.GetFileWeights(workspacePath).Keys
+                .Count(path => !direct.Contains(Path.GetRelativePath(workspacePath, path).Replace('\\', '/')))
+        };
+
+        var insights = new List<string>();
+        if (entries.Count == 0 && symbols.Count == 0)
+        {
+            insights.Add("Nothing recorded yet - files and symbols join as searches, navigation and edits name them");
+        }
+        else
+        {
+            if (entries.FirstOrDefault() is { } top)
+            {
+                insights.Add($"Strongest boost: {top.FilePath} ({top.Weight:0.00}, {top.Edits} edit(s), {top.Views} view(s))");
+            }
+            if (result.NeighborCount > 0)
+            {
+                insights.Add($"{result.NeighborCount} neighboring file(s) get a smaller boost through their references");
+            }
+            if (entries.Count > result.Files.Count || symbols.Count > result.Symbols.Count)
+            {
+                insights.Add($"Showing {result.Files.Count} of {entries.Count} files and {result.Symbols.Count} of {symbols.Count} symbols");
+            }
+        }
+
+        return Task.FromResult(new AIOptimizedResponse<WorkingSetResult>
+        {
+            Success = true,
+            Data = new AIResponseData<WorkingSetResult> { Results = result },
+            Message = $"Working set: {entries.Count} file(s), {symbols.Count} symbol(s)",
+            Insights = insights
+        });
+    }
+
+    private static AIOptimizedResponse<WorkingSetResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
@@ -0,0 +1,175 @@
+using COA.Mcp.Framework;
+using COA.Mcp.Framework.Models;
+using COA.Mcp.Framework.TokenOptimization.Models;
+using COA.CodeSearch.McpServer.Services;
+using COA.CodeSearch.McpServer.Services.Analysis;
+using COA.CodeSearch.McpServer.Services.DependencyCode;
+using COA.CodeSearch.McpServer.Services.Sqlite;
+using COA.CodeSearch.McpServer.Tools.Models;
+using Microsoft.Extensions.Logging;
+
+namespace COA.CodeSearch.McpServer.Tools;
+
+/// <summary>
+/// Builds an onboarding report of a workspace from its indexed files: languages, entry points, directory
+/// clusters linked by their imports, key configuration and test layout
+/// </summary>
+public class WorkspaceOverviewTool : CodeSearchToolBase<WorkspaceOverviewParameters, AIOptimizedResponse<WorkspaceOverviewResult>>
+{
+    private readonly ISQLiteSymbolService _sqliteService;
+    private readonly IPathResolutionService _pathResolutionService;
+    private readonly IDependencyCodeService? _dependencyCode;
+    private readonly ILogger<WorkspaceOverviewTool> _logger;
+
+    /// <summary>
+    /// Initializes a new instance of the WorkspaceOverviewTool with required dependencies.
+    /// </summary>
+    /// <param name="serviceProvider">Service provider for dependency resolution</param>
+    /// <param name="sqliteService">SQLite symbol service listing the indexed files</param>
+    /// <param name="pathResolutionService">Path resolution service for workspace defaults</param>
+    /// <param name="logger">Logger instance</param>
+    /// <param name="dependencyCode">Vendored and third-party directories left out of the report</param>
+    public WorkspaceOverviewTool(
+        IServiceProvider serviceProvider,
+        ISQLiteSymbolService sqliteService,
+        IPathResolutionService pathResolutionService,
+        ILogger<WorkspaceOverviewTool> logger,
+        IDependencyCodeService? dependencyCode = null) : base(serviceProvider, logger)
+    {
+        _sqliteService = sqliteService;
+        _pathResolutionService = pathResolutionService;
+        _dependencyCode = dependencyCode;
+        _logger = logger;
+    }
+
+    /// <summary>
+    /// Gets the tool name identifier.
+    /// </summary>
+    public override string Name => ToolNames.WorkspaceOverview;
+
+    /// <summary>
+    /// Gets the tool description explaining its purpose and usage scenarios.
+    /// </summary>
+    public override string Description =>
+        "WHAT IS THIS CODEBASE? Onboarding report built from the index: languages by share of code, entry points (main functions, " +
+        "host builders, package.json bins, container entrypoints), top-level architecture as directory clusters linked by the imports " +
+        "between them, key build/settings/container/CI/deploy configuration, and how tests are laid out. Use it first in an unfamiliar workspace.";
+
+    /// <summary>
+    /// Gets the tool category for classification purposes.
+    /// </summary>
+    public override ToolCategory Category => ToolCategory.Analysis;
+
+    /// <summary>
+    /// Reads the indexed files and builds the report.
+    /// </summary>
+    /// <param name="parameters">Cluster depth and limits</param>
+    /// <param name="cancellationToken">Cancellation token for the operation</param>
+    /// <returns>The workspace's onboarding report</returns>
+    protected override async Task<AIOptimizedResponse<WorkspaceOverviewResult>> ExecuteInternalAsync(
+        WorkspaceOverviewParameters parameters,
+        CancellationToken cancellationToken)
+    {
+        var workspacePath = string.IsNullOrWhiteSpace(parameters.WorkspacePath)
+            ? _pathResolutionService.GetPrimaryWorkspacePath()
+            : Path.GetFullPath(parameters.WorkspacePath);
+
+        if (!_sqliteService.DatabaseExists(workspacePath))
+        {
+            return CreateErrorResponse("WORKSPACE_NOT_INDEXED", "Workspace has not been indexed. Run index_workspace first.",
+                "Run mcp__codesearch__index_workspace with this workspace path", "Try workspace_overview again");
+        }
+
+        var files = new List<(string Path, string? Content)>();
+        var skipped = 0;
+        foreach (var file in await _sqliteService.GetAllFilesAsync(workspacePath, cancellationToken))
+        {
+            cancellationToken.ThrowIfCancellationRequested();
+            if (!parameters.IncludeDependencyCode && _dependencyCode?.GetDependencyDirectory(workspacePath, file.Path) != null)
+            {
+                skipped++;
+                continue;
+            }
+            files.Add((Relative(workspacePath, file.Path), file.Content));
+        }
+
+        if (files.Count == 0)
+        {
+            return CreateErrorResponse("NO_FILES", "The workspace index holds no files",
+                "Run mcp__codesearch__index_workspace with this workspace path",
+                skipped > 0 ? "Every indexed file is dependency code - pass includeDependencyCode to report on it" : "Check the workspace path");
+        }
+
+        var report = WorkspaceOverview.Build(files, Math.Clamp(parameters.ClusterDepth, 1, 4));
+        var result = new WorkspaceOverviewResult
+        {
+            WorkspacePath = workspacePath,
+            Report = report,
+            DependencyFilesSkipped = skipped,
+            TotalClusters = report.Clusters.Count
+        };
+        report.Clusters = report.Clusters.Take(Math.Clamp(parameters.MaxClusters, 1, 200)).ToList();
+
+        _logger.LogDebug("workspace_overview: {Files} files, {Languages} languages, {Clusters} clusters, {Entries} entry points",
+            report.Files, report.Languages.Count, result.TotalClusters, report.EntryPoints.Count);
+
+        var primary = report.Languages.FirstOrDefault();
+        var response = new AIOptimizedResponse<WorkspaceOverviewResult>
+        {
+            Success = true,
+            Data = new AIResponseData<WorkspaceOverviewResult> { Results = result },
+            Message = $"{report.Files} file(s)" +
+                      (primary != null ? $", mostly {primary.Language} ({primary.Percent:0.#}%)" : string.Empty) +
+                      $", {report.EntryPoints.Count} entry point(s), {result.TotalClusters} cluster(s), {report.Tests.TestFiles} test file(s)"
+        };
+
+        var insights = new List<string>();
+        var applications = report.Clusters.Where(c => c.Role == "application").Select(c => c.Name).ToList();
+        if (applications.Count > 0)
+        {
+            insights.Add($"Start reading at {string.Join(", ", applications.Take(3))} - the cluster(s) holding entry points");
+        }
+        var core = report.Clusters.Where(c => c.Role == "core").OrderByDescending(c => c.UsedBy.Count).FirstOrDefault();
+        if (core != null)
+        {
+            insights.Add($"{core.Name} is imported by {core.UsedBy.Count} cluster(s) and imports none - the shared core");
+        }
+        if (report.Clusters.Count > 1 && report.Clusters.All(c => c.Role == "isolated"))
+        {
+            insights.Add("No imports link the clusters - try a different clusterDepth, or the code links through something other than imports");
+        }
+        if (report.EntryPoints.Count == 0)
+        {
+            insights.Add("No entry point found - this may be a library; its public API is the place to start");
+        }
+        insights.Add(report.Tests.TestFiles == 0
+            ? "No test files found"
+            : $"Tests are {report.Tests.Layout} ({report.Tests.TestFiles} file(s), {report.Tests.Tests} test(s)" +
+              (report.Tests.Frameworks.Count > 0 ? $", {string.Join(", ", report.Tests.Frameworks)}" : string.Empty) + ") - find_tests maps symbols to them");
+        if (files.Any(f => f.Content == null))
+        {
+            insights.Add($"{files.Count(f => f.Content == null)} file(s) have no indexed content and count by name only");
+        }
+        if (result.TotalClusters > report.Clusters.Count)
+        {
+            insights.Add($"Showing {report.Clusters.Count} of {result.TotalClusters} clusters - raise maxClusters or lower clusterDepth");
+        }
+        response.Insights = insights;
+
+        return response;
+    }
+
+    private static string Relative(string workspacePath, string filePath) =>
+        (Path.IsPathRooted(filePath) ? Path.GetRelativePath(workspacePath, filePath) : filePath).Replace('\\', '/');
+
+    private static AIOptimizedResponse<WorkspaceOverviewResult> CreateErrorResponse(string code, string message, params string[] steps) => new()
+    {
+        Success = false,
+        Error = new ErrorInfo
+        {
+            Code = code,
+            Message = message,
+            Recovery = new RecoveryInfo { Steps = steps }
+        }
+    };
+}
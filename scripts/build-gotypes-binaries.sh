@@ -0,0 +1,45 @@
+#!/bin/bash
+# Cross-platform build script for the codesearch-gotypes helper (tools/gotypes)
+# Builds for macOS (ARM64/x64), Linux (x64/ARM64), and Windows (x64)
+# Pure Go, so no C toolchains are needed; the machine running the helper still needs the go toolchain
+
+set -e
+
+SCRIPT_DIR="$(cd "$(dirname "$0")" && pwd)"
+SOURCE_DIR="$SCRIPT_DIR/../tools/gotypes"
+OUTPUT_DIR="$SCRIPT_DIR/../bin/gotypes-binaries"
+
+echo "🔨 Building codesearch-gotypes binaries for distribution"
+echo "📁 Source: $SOURCE_DIR"
+echo "📦 Output: $OUTPUT_DIR"
+echo ""
+
+if ! command -v go &> /dev/null; then
+    echo "❌ Error: go not found on PATH"
+    exit 1
+fi
+
+mkdir -p "$OUTPUT_DIR"
+cd "$SOURCE_DIR"
+
+build_target() {
+    local goos=$1
+    local goarch=$2
+    local output_name=$3
+
+    echo "🔨 Building for $goos/$goarch..."
+    CGO_ENABLED=0 GOOS="$goos" GOARCH="$goarch" go build -trimpath -ldflags "-s -w" -o "$OUTPUT_DIR/$output_name" .
+    ls -lh "$OUTPUT_DIR/$output_name" | awk '{print "   Size:", $5}'
+    echo ""
+}
+
+build_target darwin arm64 "codesearch-gotypes-macos-arm64"
+build_target darwin amd64 "codesearch-gotypes-macos-x64"
+build_target linux amd64 "codesearch-gotypes-linux-x64"
+build_target linux arm64 "codesearch-gotypes-linux-arm64"
+build_target windows amd64 "codesearch-gotypes-windows-x64.exe"
+
+echo "✅ Build complete!"
+echo ""
+echo "📦 Binaries created in: $OUTPUT_DIR"
+ls -lh "$OUTPUT_DIR"
@@ -0,0 +1,3 @@
+module github.com/anortham/coa-codesearch-mcp/tools/gotypes
+
+go 1.23
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// listedPackage is the subset of `go list -json` output the loader uses.
+type listedPackage struct {
+	ImportPath  string
+	Name        string
+	Dir         string
+	Export      string
+	ForTest     string
+	GoFiles     []string
+	CgoFiles    []string
+	TestGoFiles []string
+	ImportMap   map[string]string
+	Module      *struct {
+		Path string
+		Main bool
+	}
+	Error *struct {
+		Err string
+	}
+}
+
+// sourcePackage is a package of the main module (or go.work modules), type-checked from source.
+// In-package test files are checked together with the package, so objects are shared with them.
+type sourcePackage struct {
+	path      string
+	dir       string
+	filenames []string
+	importMap map[string]string
+	xtest     bool
+
+	files  []*ast.File
+	types  *types.Package
+	info   *types.Info
+	errors []string
+}
+
+type sourceFile struct {
+	ast     *ast.File
+	content []byte
+	pkg     *sourcePackage
+}
+
+// module is one loaded go.mod or go.work root.
+type module struct {
+	root     string
+	fset     *token.FileSet
+	packages map[string]*sourcePackage // by import path; xtests keyed "path_test"
+	files    map[string]*sourceFile    // by absolute filename
+	exports  map[string]string         // import path -> export data file
+	gc       types.Importer
+	checking map[string]bool
+	stamps   map[string]time.Time
+}
+
+// findModuleRoot walks up from dir to the nearest directory with a go.work or go.mod.
+func findModuleRoot(dir string) (string, error) {
+	for current := dir; ; {
+		for _, name := range []string{"go.work", "go.mod"} {
+			if _, err := os.Stat(filepath.Join(current, name)); err == nil {
+				return current, nil
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", fmt.Errorf("no go.mod or go.work at or above %s", dir)
+		}
+		current = parent
+	}
+}
+
+func loadModule(root string) (*module, error) {
+	cmd := exec.Command("go", "list", "-e", "-deps", "-export", "-test",
+		"-json=ImportPath,Name,Dir,Export,ForTest,GoFiles,CgoFiles,TestGoFiles,ImportMap,Module,Error", "./...")
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed in %s: %v: %s", root, err, strings.TrimSpace(stderr.String()))
+	}
+
+	m := &module{
+		root:     root,
+		fset:     token.NewFileSet(),
+		packages: map[string]*sourcePackage{},
+		files:    map[string]*sourceFile{},
+		exports:  map[string]string{},
+		checking: map[string]bool{},
+		stamps:   map[string]time.Time{},
+	}
+	m.gc = importer.ForCompiler(m.fset, "gc", m.lookupExport)
+
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var p listedPackage
+		if err := decoder.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading go list output: %v", err)
+		}
+
+		switch {
+		case p.Name == "main" && strings.HasSuffix(p.ImportPath, ".test"):
+			// Generated test main
+		case p.ForTest != "":
+			// Test variants recompile packages against the package under test; only the external
+			// test package ("p_test [p.test]") has files of its own
+			if strings.HasSuffix(p.Name, "_test") {
+				m.addSource(p, stripVariant(p.ImportPath), true)
+			}
+		case p.Module != nil && p.Module.Main:
+			m.addSource(p, p.ImportPath, false)
+		case p.Export != "":
+			m.exports[p.ImportPath] = p.Export
+		}
+	}
+
+	for _, name := range []string{"go.mod", "go.sum", "go.work", "go.work.sum"} {
+		m.stamp(filepath.Join(root, name))
+	}
+	return m, nil
+}
+
+func (m *module) addSource(p listedPackage, path string, xtest bool) {
+	names := append(append(append([]string{}, p.GoFiles...), p.CgoFiles...), p.TestGoFiles...)
+	if xtest {
+		names = p.GoFiles
+	}
+
+	pkg := &sourcePackage{path: path, dir: p.Dir, importMap: p.ImportMap, xtest: xtest}
+	for _, name := range names {
+		pkg.filenames = append(pkg.filenames, filepath.Join(p.Dir, name))
+	}
+
+	key := path
+	if xtest {
+		key = path + "_test"
+	}
+	m.packages[key] = pkg
+	m.stamp(p.Dir)
+	for _, filename := range pkg.filenames {
+		m.stamp(filename)
+	}
+}
+
+func (m *module) stamp(path string) {
+	if info, err := os.Stat(path); err == nil {
+		m.stamps[path] = info.ModTime()
+	} else {
+		m.stamps[path] = time.Time{}
+	}
+}
+
+// stale reports whether any file, package directory or module file changed since loading.
+func (m *module) stale() bool {
+	for path, modTime := range m.stamps {
+		info, err := os.Stat(path)
+		if err != nil {
+			if !modTime.IsZero() {
+				return true
+			}
+			continue
+		}
+		if !info.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *module) lookupExport(path string) (io.ReadCloser, error) {
+	export, ok := m.exports[path]
+	if !ok {
+		return nil, fmt.Errorf("no export data for %s", path)
+	}
+	return os.Open(export)
+}
+
+// check type-checks a source package (and, through imports, the source packages it depends on).
+func (m *module) check(key string) *sourcePackage {
+	pkg := m.packages[key]
+	if pkg == nil || pkg.info != nil || m.checking[key] {
+		return pkg
+	}
+	m.checking[key] = true
+	defer delete(m.checking, key)
+
+	for _, filename := range pkg.filenames {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			pkg.errors = append(pkg.errors, err.Error())
+			continue
+		}
+		file, err := parser.ParseFile(m.fset, filename, content, parser.ParseComments)
+		if file == nil {
+			pkg.errors = append(pkg.errors, err.Error())
+			continue
+		}
+		pkg.files = append(pkg.files, file)
+		m.files[filename] = &sourceFile{ast: file, content: content, pkg: pkg}
+	}
+
+	pkg.info = &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	config := types.Config{
+		Importer:    importerFunc(func(path string) (*types.Package, error) { return m.importFrom(pkg, path) }),
+		FakeImportC: true,
+		Error: func(err error) {
+			if len(pkg.errors) < 20 {
+				pkg.errors = append(pkg.errors, err.Error())
+			}
+		},
+	}
+	// Errors are collected above; a partially checked package is still useful
+	pkg.types, _ = config.Check(pkg.path, m.fset, pkg.files, pkg.info)
+	return pkg
+}
+
+func (m *module) importFrom(from *sourcePackage, path string) (*types.Package, error) {
+	if mapped, ok := from.importMap[path]; ok {
+		path = mapped
+	}
+	path = stripVariant(path)
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if _, ok := m.packages[path]; ok {
+		if m.checking[path] {
+			return nil, fmt.Errorf("import cycle through %s", path)
+		}
+		if pkg := m.check(path); pkg.types != nil {
+			return pkg.types, nil
+		}
+		return nil, fmt.Errorf("could not type-check %s", path)
+	}
+	return m.gc.Import(path)
+}
+
+// checkAll type-checks every source package, including external test packages.
+func (m *module) checkAll() {
+	for key := range m.packages {
+		m.check(key)
+	}
+}
+
+// fileFor type-checks the package containing filename and returns the parsed file.
+func (m *module) fileFor(filename string) (*sourceFile, error) {
+	if file, ok := m.files[filename]; ok {
+		return file, nil
+	}
+	for key, pkg := range m.packages {
+		for _, candidate := range pkg.filenames {
+			if candidate == filename {
+				m.check(key)
+				if file, ok := m.files[filename]; ok {
+					return file, nil
+				}
+				return nil, fmt.Errorf("%s could not be parsed", filename)
+			}
+		}
+	}
+	return nil, errors.New("file is not part of any package in the module (excluded by build constraints, or in testdata/vendor)")
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// stripVariant turns "p [q.test]" into "p".
+func stripVariant(path string) string {
+	if i := strings.Index(path, " ["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
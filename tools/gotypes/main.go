@@ -0,0 +1,65 @@
+// Command codesearch-gotypes type-checks Go modules for the CodeSearch MCP server.
+//
+// It reads one JSON request per line on stdin and writes one JSON response per line on stdout.
+// Packages are loaded the way go/packages loads them - `go list -deps -export -test` for the package
+// graph and compiler export data, then go/types over the module's own sources - so the helper needs
+// nothing beyond the standard library and a Go toolchain on PATH. Loaded modules stay cached until one
+// of their files, directories or go.mod/go.sum/go.work changes.
+//
+// Requests:
+//
+//	{"id":1,"op":"typeof","dir":"/ws","file":"/ws/a/b.go","line":12,"column":8}
+//	{"id":2,"op":"references","dir":"/ws","file":"/ws/a/b.go","line":12,"column":8}
+//	{"id":3,"op":"references","dir":"/ws","name":"UpdateUser"}
+//
+// Lines are 1-based, columns 0-based UTF-8 bytes.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type request struct {
+	ID     int64  `json:"id"`
+	Op     string `json:"op"`
+	Dir    string `json:"dir"`
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type response struct {
+	ID     int64  `json:"id"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func main() {
+	in := bufio.NewScanner(os.Stdin)
+	in.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	out := json.NewEncoder(os.Stdout)
+	s := newServer()
+
+	for in.Scan() {
+		var req request
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			_ = out.Encode(response{Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		result, err := s.handle(req)
+		resp := response{ID: req.ID, Result: result}
+		if err != nil {
+			resp.Result = nil
+			resp.Error = err.Error()
+		}
+		if err := out.Encode(resp); err != nil {
+			fmt.Fprintf(os.Stderr, "write failed: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type location struct {
+	File         string `json:"file"`
+	Line         int    `json:"line"`
+	Column       int    `json:"column"`
+	Offset       int    `json:"offset"`
+	Length       int    `json:"length"`
+	IsDefinition bool   `json:"isDefinition"`
+	ContainedIn  string `json:"containedIn,omitempty"`
+	LineText     string `json:"lineText,omitempty"`
+}
+
+type objectInfo struct {
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Package   string    `json:"package,omitempty"`
+	Signature string    `json:"signature"`
+	Doc       string    `json:"doc,omitempty"`
+	Declared  *location `json:"declared,omitempty"`
+}
+
+type typeOfResult struct {
+	Expression string      `json:"expression"`
+	Type       string      `json:"type"`
+	Underlying string      `json:"underlying,omitempty"`
+	Mode       string      `json:"mode"`
+	Value      string      `json:"value,omitempty"`
+	Object     *objectInfo `json:"object,omitempty"`
+	TypeErrors []string    `json:"typeErrors,omitempty"`
+}
+
+type referencesResult struct {
+	Symbol      string     `json:"symbol"`
+	Kind        string     `json:"kind"`
+	SymbolCount int        `json:"symbolCount"`
+	Locations   []location `json:"locations"`
+	TypeErrors  int        `json:"typeErrors"`
+}
+
+// typeOf describes the expression at a position: the innermost expression, widened from a selector's
+// name to the whole selector so "user.Name" rather than "Name" is described.
+func (m *module) typeOf(filename string, line, column int) (*typeOfResult, error) {
+	file, pos, err := m.position(filename, line, column)
+	if err != nil {
+		return nil, err
+	}
+
+	path := enclosing(file.ast, pos)
+	if _, ok := last(path).(*ast.Ident); !ok && column > 0 {
+		if before := enclosing(file.ast, pos-1); isIdent(last(before)) {
+			path = before
+		}
+	}
+
+	index := len(path) - 1
+	for ; index >= 0; index-- {
+		if _, ok := path[index].(ast.Expr); ok {
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.New("no expression at this position")
+	}
+
+	expr := path[index].(ast.Expr)
+	var ident *ast.Ident
+	if id, ok := expr.(*ast.Ident); ok {
+		ident = id
+		if index > 0 {
+			if selector, ok := path[index-1].(*ast.SelectorExpr); ok && selector.Sel == id {
+				expr = selector
+			}
+		}
+	}
+
+	info := file.pkg.info
+	qualifier := packageNameQualifier(file.pkg.types)
+	result := &typeOfResult{Expression: m.source(file, expr), TypeErrors: file.pkg.errors}
+
+	var object types.Object
+	if ident != nil {
+		if object = info.Defs[ident]; object == nil {
+			object = info.Uses[ident]
+		}
+	}
+
+	if imported, ok := object.(*types.PkgName); ok && expr == ident {
+		result.Type = imported.Imported().Path()
+		result.Mode = "package"
+	} else if tv, ok := info.Types[expr]; ok && tv.Type != nil {
+		result.Type = types.TypeString(tv.Type, qualifier)
+		if underlying := types.TypeString(tv.Type.Underlying(), qualifier); underlying != result.Type {
+			result.Underlying = underlying
+		}
+		result.Mode = mode(tv)
+		if tv.Value != nil {
+			result.Value = tv.Value.ExactString()
+		}
+	} else if object != nil && object.Type() != nil {
+		result.Type = types.TypeString(object.Type(), qualifier)
+		if underlying := types.TypeString(object.Type().Underlying(), qualifier); underlying != result.Type {
+			result.Underlying = underlying
+		}
+		result.Mode = "definition"
+		if constant, ok := object.(*types.Const); ok {
+			result.Value = constant.Val().ExactString()
+		}
+	} else {
+		return nil, fmt.Errorf("no type information for %q (the package may have type errors)", result.Expression)
+	}
+
+	if object != nil {
+		result.Object = m.describe(object, qualifier)
+	}
+	return result, nil
+}
+
+// referencesAt finds every use and definition of the object named at a position.
+func (m *module) referencesAt(filename string, line, column int) (*referencesResult, error) {
+	file, pos, err := m.position(filename, line, column)
+	if err != nil {
+		return nil, err
+	}
+
+	ident, _ := last(enclosing(file.ast, pos)).(*ast.Ident)
+	if ident == nil && column > 0 {
+		ident, _ = last(enclosing(file.ast, pos-1)).(*ast.Ident)
+	}
+	if ident == nil {
+		return nil, errors.New("no identifier at this position")
+	}
+
+	object := file.pkg.info.Defs[ident]
+	if object == nil {
+		object = file.pkg.info.Uses[ident]
+	}
+	if object == nil {
+		return nil, fmt.Errorf("%s does not resolve to an object (package clause, or a type error)", ident.Name)
+	}
+	return m.references([]types.Object{object}), nil
+}
+
+// referencesByName finds references to every package-level object, method and field named name.
+// Locals and parameters are left out: a name lookup means a symbol visible beyond one function.
+func (m *module) referencesByName(name string) *referencesResult {
+	m.checkAll()
+
+	seen := map[types.Object]bool{}
+	var objects []types.Object
+	for _, key := range m.sortedKeys() {
+		for ident, object := range m.packages[key].info.Defs {
+			if object == nil || ident.Name != name || seen[origin(object)] || !visibleBeyondFunction(object) {
+				continue
+			}
+			seen[origin(object)] = true
+			objects = append(objects, origin(object))
+		}
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Pos() < objects[j].Pos() })
+	return m.references(objects)
+}
+
+func (m *module) references(objects []types.Object) *referencesResult {
+	m.checkAll()
+
+	targets := map[types.Object]bool{}
+	for _, object := range objects {
+		targets[origin(object)] = true
+	}
+
+	result := &referencesResult{
+		Symbol:      types.ObjectString(objects[0], nil),
+		Kind:        kind(objects[0]),
+		SymbolCount: len(objects),
+		Locations:   []location{},
+	}
+
+	seen := map[token.Pos]bool{}
+	for _, key := range m.sortedKeys() {
+		pkg := m.packages[key]
+		if pkg.info == nil {
+			continue
+		}
+		if len(pkg.errors) > 0 {
+			result.TypeErrors++
+		}
+		collect := func(uses map[*ast.Ident]types.Object, definition bool) {
+			for ident, object := range uses {
+				if object == nil || !targets[origin(object)] || seen[ident.Pos()] {
+					continue
+				}
+				seen[ident.Pos()] = true
+				loc := m.locate(ident.Pos(), len(ident.Name))
+				loc.IsDefinition = definition
+				result.Locations = append(result.Locations, loc)
+			}
+		}
+		collect(pkg.info.Defs, true)
+		collect(pkg.info.Uses, false)
+	}
+
+	sort.Slice(result.Locations, func(i, j int) bool {
+		a, b := result.Locations[i], result.Locations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Offset < b.Offset
+	})
+	return result
+}
+
+// position converts a 1-based line and 0-based byte column to a token.Pos in a checked file.
+func (m *module) position(filename string, line, column int) (*sourceFile, token.Pos, error) {
+	file, err := m.fileFor(filename)
+	if err != nil {
+		return nil, token.NoPos, err
+	}
+	tokenFile := m.fset.File(file.ast.Pos())
+	if line < 1 || line > tokenFile.LineCount() {
+		return nil, token.NoPos, fmt.Errorf("line %d is outside %s (%d lines)", line, filepath.Base(filename), tokenFile.LineCount())
+	}
+	start := tokenFile.LineStart(line)
+	offset := tokenFile.Offset(start) + column
+	if offset > tokenFile.Size() {
+		offset = tokenFile.Size()
+	}
+	return file, tokenFile.Pos(offset), nil
+}
+
+func (m *module) locate(pos token.Pos, length int) location {
+	position := m.fset.Position(pos)
+	loc := location{File: position.Filename, Line: position.Line, Column: position.Column - 1, Offset: position.Offset, Length: length}
+	if file, ok := m.files[position.Filename]; ok {
+		loc.LineText = lineText(file.content, position.Offset)
+		loc.ContainedIn = containingDecl(file.ast, pos)
+	}
+	return loc
+}
+
+func (m *module) describe(object types.Object, qualifier types.Qualifier) *objectInfo {
+	info := &objectInfo{Name: object.Name(), Kind: kind(object), Signature: types.ObjectString(object, qualifier)}
+	if object.Pkg() != nil {
+		info.Package = object.Pkg().Path()
+	}
+	if object.Pos().IsValid() {
+		declared := m.locate(object.Pos(), len(object.Name()))
+		info.Declared = &declared
+		info.Doc = m.docFor(object.Pos())
+	}
+	return info
+}
+
+// docFor returns the doc comment of the declaration whose name is at pos, when it is in the module's
+// sources. A spec without its own comment takes the comment of a single-spec declaration ("// Limit ...
+// const Limit = 10"); a field or spec falls back to its trailing line comment.
+func (m *module) docFor(pos token.Pos) string {
+	file, ok := m.files[m.fset.Position(pos).Filename]
+	if !ok {
+		return ""
+	}
+
+	path := enclosing(file.ast, pos)
+	for index := len(path) - 1; index >= 0; index-- {
+		var doc, comment *ast.CommentGroup
+		switch node := path[index].(type) {
+		case *ast.FuncDecl:
+			return strings.TrimSpace(node.Doc.Text())
+		case *ast.Field:
+			doc, comment = node.Doc, node.Comment
+		case *ast.ValueSpec:
+			doc, comment = node.Doc, node.Comment
+		case *ast.TypeSpec:
+			doc, comment = node.Doc, node.Comment
+		case *ast.AssignStmt, *ast.BlockStmt:
+			return ""
+		default:
+			continue
+		}
+		if doc == nil && index > 0 {
+			if decl, ok := path[index-1].(*ast.GenDecl); ok && len(decl.Specs) == 1 {
+				doc = decl.Doc
+			}
+		}
+		if doc == nil {
+			doc = comment
+		}
+		return strings.TrimSpace(doc.Text())
+	}
+	return ""
+}
+
+func (m *module) source(file *sourceFile, node ast.Node) string {
+	start, end := m.fset.Position(node.Pos()).Offset, m.fset.Position(node.End()).Offset
+	if start < 0 || end > len(file.content) || start > end {
+		return ""
+	}
+	return string(file.content[start:end])
+}
+
+func (m *module) sortedKeys() []string {
+	keys := make([]string, 0, len(m.packages))
+	for key := range m.packages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// enclosing returns the nodes containing pos, outermost first. Sibling ranges can overlap (a FuncDecl's
+// FuncType starts at "func" and so spans the name), so the nodes are ordered by size rather than by visit.
+func enclosing(file *ast.File, pos token.Pos) []ast.Node {
+	var path []ast.Node
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil || pos < node.Pos() || pos >= node.End() {
+			return false
+		}
+		path = append(path, node)
+		return true
+	})
+	sort.SliceStable(path, func(i, j int) bool { return path[i].End()-path[i].Pos() > path[j].End()-path[j].Pos() })
+	return path
+}
+
+// packageNameQualifier writes other packages by name ("store.User"), as they appear in source.
+func packageNameQualifier(current *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == current {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+func last(path []ast.Node) ast.Node {
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+func isIdent(node ast.Node) bool {
+	_, ok := node.(*ast.Ident)
+	return ok
+}
+
+// origin maps members of instantiated generic types back to their declarations.
+func origin(object types.Object) types.Object {
+	switch o := object.(type) {
+	case *types.Func:
+		return o.Origin()
+	case *types.Var:
+		return o.Origin()
+	}
+	return object
+}
+
+func visibleBeyondFunction(object types.Object) bool {
+	switch o := object.(type) {
+	case *types.Func:
+		return true
+	case *types.Var:
+		return o.IsField() || (o.Pkg() != nil && o.Parent() == o.Pkg().Scope())
+	default:
+		return object.Pkg() != nil && object.Parent() == object.Pkg().Scope()
+	}
+}
+
+func kind(object types.Object) string {
+	switch o := object.(type) {
+	case *types.Func:
+		if signature, ok := o.Type().(*types.Signature); ok && signature.Recv() != nil {
+			return "method"
+		}
+		return "func"
+	case *types.Var:
+		if o.IsField() {
+			return "field"
+		}
+		return "var"
+	case *types.Const:
+		return "const"
+	case *types.TypeName:
+		return "type"
+	case *types.PkgName:
+		return "package"
+	case *types.Label:
+		return "label"
+	case *types.Builtin:
+		return "builtin"
+	case *types.Nil:
+		return "nil"
+	}
+	return "object"
+}
+
+func mode(tv types.TypeAndValue) string {
+	switch {
+	case tv.IsVoid():
+		return "void"
+	case tv.IsType():
+		return "type"
+	case tv.IsBuiltin():
+		return "builtin"
+	case tv.Value != nil:
+		return "constant"
+	case tv.Addressable():
+		return "variable"
+	case tv.Assignable():
+		return "mapindex"
+	default:
+		return "value"
+	}
+}
+
+func lineText(content []byte, offset int) string {
+	if offset > len(content) {
+		return ""
+	}
+	start := strings.LastIndexByte(string(content[:offset]), '\n') + 1
+	end := offset
+	for end < len(content) && content[end] != '\n' {
+		end++
+	}
+	return strings.TrimRight(string(content[start:end]), "\r")
+}
+
+// containingDecl names the function, method or type declaration around pos.
+func containingDecl(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos >= decl.End() {
+			continue
+		}
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				return receiverName(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+			return d.Name.Name
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if pos < spec.Pos() || pos >= spec.End() {
+					continue
+				}
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					return s.Name.Name
+				case *ast.ValueSpec:
+					if len(s.Names) > 0 {
+						return s.Names[0].Name
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type server struct {
+	modules map[string]*module
+}
+
+func newServer() *server {
+	return &server{modules: map[string]*module{}}
+}
+
+func (s *server) handle(req request) (any, error) {
+	start := req.Dir
+	if req.File != "" {
+		start = filepath.Dir(req.File)
+	}
+	if start == "" {
+		return nil, fmt.Errorf("request needs dir or file")
+	}
+
+	root, err := findModuleRoot(start)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.module(root)
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Clean(req.File)
+	switch req.Op {
+	case "typeof":
+		return m.typeOf(file, req.Line, req.Column)
+	case "references":
+		if req.Name != "" {
+			if result := m.referencesByName(req.Name); result != nil {
+				return result, nil
+			}
+			return nil, fmt.Errorf("no package-level object, method or field named %s in %s", req.Name, root)
+		}
+		return m.referencesAt(file, req.Line, req.Column)
+	case "ping":
+		return map[string]string{"root": root}, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", req.Op)
+	}
+}
+
+// module returns the cached module for root, reloading it when anything it was loaded from changed.
+func (s *server) module(root string) (*module, error) {
+	if m, ok := s.modules[root]; ok && !m.stale() {
+		return m, nil
+	}
+
+	m, err := loadModule(root)
+	if err != nil {
+		delete(s.modules, root)
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "loaded %s: %d packages, %d dependencies with export data\n", root, len(m.packages), len(m.exports))
+	s.modules[root] = m
+	return m, nil
+}
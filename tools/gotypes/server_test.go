@@ -0,0 +1,224 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModule lays out a two-package module with a test file and returns its root.
+func writeModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+const Limit = 10 // Maximum users per page
+
+type User struct {
+	Name string
+}
+
+type Repo struct{}
+
+func (r *Repo) Find(name string) *User { return &User{Name: name} }
+
+type Cache struct{}
+
+func (c *Cache) Find(name string) *User { return nil }
+`,
+		"api/api.go": `package api
+
+import "example.com/shop/store"
+
+func Lookup(r *store.Repo) string {
+	user := r.Find("ada")
+	return user.Name
+}
+`,
+		"api/api_test.go": `package api
+
+import (
+	"testing"
+
+	"example.com/shop/store"
+)
+
+func TestLookup(t *testing.T) {
+	if Lookup(&store.Repo{}) == "" {
+		t.Fatal("empty")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestTypeOfSelectorReportsFieldType(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+
+	// "return user.Name" - column of Name
+	result, err := s.handle(request{Op: "typeof", Dir: root, File: filepath.Join(root, "api", "api.go"), Line: 7, Column: 13})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := result.(*typeOfResult)
+	if info.Expression != "user.Name" || info.Type != "string" {
+		t.Fatalf("got %q: %q, want user.Name: string", info.Expression, info.Type)
+	}
+	if info.Object == nil || info.Object.Kind != "field" {
+		t.Fatalf("want the Name field as object, got %+v", info.Object)
+	}
+}
+
+func TestTypeOfShortVarDeclarationUsesPackageName(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+
+	// "user := r.Find" - column of user
+	result, err := s.handle(request{Op: "typeof", Dir: root, File: filepath.Join(root, "api", "api.go"), Line: 6, Column: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.(*typeOfResult).Type; got != "*store.User" {
+		t.Fatalf("got %q, want *store.User", got)
+	}
+}
+
+func TestTypeOfDeclarationIncludesDoc(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+
+	// "const Limit = 10" - column of Limit
+	result, err := s.handle(request{Op: "typeof", Dir: root, File: filepath.Join(root, "store", "store.go"), Line: 3, Column: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := result.(*typeOfResult)
+	if info.Value != "10" {
+		t.Fatalf("got value %q, want 10", info.Value)
+	}
+	if info.Object == nil || info.Object.Doc != "Maximum users per page" {
+		t.Fatalf("want the trailing comment as doc, got %+v", info.Object)
+	}
+}
+
+func TestReferencesAtMethodExcludeSameNamedMethod(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+
+	// "func (r *Repo) Find" - column of Find
+	result, err := s.handle(request{Op: "references", Dir: root, File: filepath.Join(root, "store", "store.go"), Line: 11, Column: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := result.(*referencesResult)
+	if len(refs.Locations) != 2 {
+		t.Fatalf("want the definition and the call in api.go, got %+v", refs.Locations)
+	}
+	for _, location := range refs.Locations {
+		if location.Line == 15 {
+			t.Fatalf("Cache.Find must not be a reference to Repo.Find: %+v", location)
+		}
+	}
+}
+
+func TestReferencesByNameCoversTestFiles(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+
+	result, err := s.handle(request{Op: "references", Dir: root, Name: "Repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inTest bool
+	for _, location := range result.(*referencesResult).Locations {
+		if strings.HasSuffix(location.File, "api_test.go") {
+			inTest = true
+		}
+	}
+	if !inTest {
+		t.Fatal("want the reference in api_test.go")
+	}
+}
+
+func TestModuleReloadsWhenFileAdded(t *testing.T) {
+	root := writeModule(t)
+	s := newServer()
+	if _, err := s.handle(request{Op: "references", Dir: root, Name: "Limit"}); err != nil {
+		t.Fatal(err)
+	}
+
+	extra := "package api\n\nimport \"example.com/shop/store\"\n\nvar max = store.Limit\n"
+	if err := os.WriteFile(filepath.Join(root, "api", "limits.go"), []byte(extra), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.handle(request{Op: "references", Dir: root, Name: "Limit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(result.(*referencesResult).Locations); got != 2 {
+		t.Fatalf("want the declaration and the new use, got %d locations", got)
+	}
+}
+
+func TestTypeOfRangeOverFuncVariable(t *testing.T) {
+	root := writeModule(t)
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/shop\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	iterators := `package store
+
+import "iter"
+
+func Users(names []string) iter.Seq2[int, *User] {
+	return func(yield func(int, *User) bool) {
+		for i := range len(names) {
+			if !yield(i, &User{Name: names[i]}) {
+				return
+			}
+		}
+	}
+}
+
+func First(names []string) string {
+	for _, user := range Users(names) {
+		return user.Name
+	}
+	return ""
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "store", "iter.go"), []byte(iterators), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := newServer()
+
+	// "for _, user := range Users(names)" - column of user
+	result, err := s.handle(request{Op: "typeof", Dir: root, File: filepath.Join(root, "store", "iter.go"), Line: 16, Column: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := result.(*typeOfResult)
+	if info.Type != "*User" || len(info.TypeErrors) > 0 {
+		t.Fatalf("got %q with errors %v, want *User", info.Type, info.TypeErrors)
+	}
+}